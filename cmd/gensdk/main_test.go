@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestSDKUpToDate fails when the committed clients no longer match the
+// spec, so handler and model changes come with a regenerated SDK.
+func TestSDKUpToDate(t *testing.T) {
+	files, err := generate()
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	for name, expected := range files {
+		actual, err := os.ReadFile(filepath.Join("..", "..", "sdk", name))
+		if err != nil {
+			t.Fatalf("%v; run go run ./cmd/gensdk", err)
+		}
+		if !bytes.Equal(actual, expected) {
+			t.Errorf("sdk/%s is out of date; run go run ./cmd/gensdk", filepath.ToSlash(name))
+		}
+	}
+}
+
+func TestIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		exported bool
+		expected string
+	}{
+		{"public_id", true, "PublicID"},
+		{"keyId", false, "keyID"},
+		{"User.GetByID", true, "UserGetByID"},
+		{"WebAuthn.LoginBegin", false, "webAuthnLoginBegin"},
+		{"avatar_url", true, "AvatarURL"},
+	}
+	for _, tt := range tests {
+		if actual := identifier(tt.name, tt.exported); actual != tt.expected {
+			t.Errorf("identifier(%q, %v) = %q, expected %q", tt.name, tt.exported, actual, tt.expected)
+		}
+	}
+}
+
+func TestPaths(t *testing.T) {
+	tests := []struct {
+		path, golang, typescript string
+	}{
+		{"/users/me", `"/users/me"`, `"/users/me"`},
+		{"/users/{id}", `"/users/"+url.PathEscape(id)`, "`/users/${encodeURIComponent(id)}`"},
+		{"/admin/service-accounts/{id}/keys/{keyId}", `"/admin/service-accounts/"+url.PathEscape(id)+"/keys/"+url.PathEscape(keyID)`, "`/admin/service-accounts/${encodeURIComponent(id)}/keys/${encodeURIComponent(keyID)}`"},
+	}
+	for _, tt := range tests {
+		if actual := goPath(tt.path); actual != tt.golang {
+			t.Errorf("goPath(%q) = %s, expected %s", tt.path, actual, tt.golang)
+		}
+		if actual := tsPath(tt.path); actual != tt.typescript {
+			t.Errorf("tsPath(%q) = %s, expected %s", tt.path, actual, tt.typescript)
+		}
+	}
+}
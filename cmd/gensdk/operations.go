@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"BACKEND/internal/routes"
+)
+
+// operation is one client method.
+type operation struct {
+	name   string // e.g. "UserGetByID"
+	method string // e.g. "GET"
+	path   string // e.g. "/users/{id}"
+	params []string
+	body   *routes.Schema
+	result *routes.Schema
+}
+
+// skipped are the operations a JSON client can't call: they serve files,
+// redirect a browser, take a multipart form or are called by the billing
+// provider.
+var skipped = map[string]bool{
+	"BillingHandler.Webhook":   true,
+	"ExportHandler.Download":   true,
+	"FileHandler.Serve":        true,
+	"IdentityHandler.Callback": true,
+	"IdentityHandler.Login":    true,
+	"UserHandler.Avatar":       true,
+	"UserHandler.UploadAvatar": true,
+}
+
+// operations lists the spec's operations sorted by name. Methods are named
+// after the handler, "UserHandler.GetByID" becoming "UserGetByID".
+// Responses the spec has no model for come back as untyped JSON, except
+// for 204s, which have no body.
+func operations(doc routes.OpenAPI) ([]operation, error) {
+	var ops []operation
+	seen := make(map[string]string)
+	for path, methods := range doc.Paths {
+		for method, op := range methods {
+			if skipped[op.OperationID] {
+				continue
+			}
+			o := operation{
+				name:   identifier(strings.Replace(op.OperationID, "Handler.", ".", 1), true),
+				method: strings.ToUpper(method),
+				path:   path,
+			}
+			if prev, ok := seen[o.name]; ok {
+				return nil, fmt.Errorf("%s %s and %s are both named %s", o.method, path, prev, o.name)
+			}
+			seen[o.name] = o.method + " " + path
+
+			for _, p := range op.Parameters {
+				if p.In == "path" {
+					o.params = append(o.params, p.Name)
+				}
+			}
+			if op.RequestBody != nil {
+				for _, media := range op.RequestBody.Content {
+					o.body = media.Schema
+				}
+			}
+			for status, resp := range op.Responses {
+				if status == "default" || status == "204" {
+					continue
+				}
+				o.result = &routes.Schema{}
+				for _, media := range resp.Content {
+					o.result = media.Schema
+				}
+			}
+			ops = append(ops, o)
+		}
+	}
+	sort.Slice(ops, func(i, j int) bool { return ops[i].name < ops[j].name })
+	return ops, nil
+}
+
+// schemaNames returns the component schemas' names in order.
+func schemaNames(doc routes.OpenAPI) []string {
+	names := make([]string, 0, len(doc.Components.Schemas))
+	for name := range doc.Components.Schemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// propertyNames returns s's properties in order.
+func propertyNames(s *routes.Schema) []string {
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// refName returns the component a $ref points at.
+func refName(ref string) string {
+	return strings.TrimPrefix(ref, "#/components/schemas/")
+}
+
+// requestSchemas returns the components reachable from request bodies.
+// Their optional scalars become pointers in Go, so a client can send false
+// or zero without it being dropped.
+func requestSchemas(doc routes.OpenAPI, ops []operation) map[string]bool {
+	reached := make(map[string]bool)
+	var walk func(*routes.Schema)
+	walk = func(s *routes.Schema) {
+		if s == nil {
+			return
+		}
+		if s.Ref != "" {
+			name := refName(s.Ref)
+			if !reached[name] {
+				reached[name] = true
+				walk(doc.Components.Schemas[name])
+			}
+			return
+		}
+		walk(s.Items)
+		walk(s.AdditionalProperties)
+		for _, prop := range s.Properties {
+			walk(prop)
+		}
+	}
+	for _, op := range ops {
+		walk(op.body)
+	}
+	return reached
+}
+
+// initialisms are the words Go spells in capitals.
+var initialisms = map[string]bool{
+	"API": true, "DOB": true, "HTTP": true, "ID": true, "IP": true, "JWT": true, "OIDC": true,
+	"SCIM": true, "SSO": true, "TOTP": true, "URL": true, "URI": true, "UUID": true,
+}
+
+// identifier turns a JSON or path name such as "public_id" or "keyId"
+// into "PublicID", or "publicID" when exported is false.
+func identifier(name string, exported bool) string {
+	var words []string
+	start := -1
+	runes := []rune(name)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			if start >= 0 {
+				words = append(words, string(runes[start:i]))
+			}
+			start = -1
+		case start < 0:
+			start = i
+		case unicode.IsUpper(r) && unicode.IsLower(runes[i-1]):
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	if start >= 0 {
+		words = append(words, string(runes[start:]))
+	}
+
+	var b strings.Builder
+	for i, word := range words {
+		switch upper := strings.ToUpper(word); {
+		case i == 0 && !exported:
+			b.WriteString(strings.ToLower(word))
+		case initialisms[upper]:
+			b.WriteString(upper)
+		default:
+			b.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return b.String()
+}
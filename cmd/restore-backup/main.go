@@ -0,0 +1,88 @@
+// Command restore-backup loads an archive taken with POST /admin/backups
+// into the database at DATABASE_URL, replacing what the backed-up tables
+// hold. It is meant for staging and refuses to run with APP_ENV=prod.
+//
+//	restore-backup users-20260101T000000Z.tar.gz   # from the configured storage
+//	restore-backup -file ./users.tar.gz            # from a local file
+//	restore-backup -list
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"BACKEND/config"
+	"BACKEND/internal/backup"
+)
+
+func main() {
+	file := flag.String("file", "", "restore from this local file instead of the backup storage")
+	list := flag.Bool("list", false, "list the backups in storage and exit")
+	flag.Parse()
+
+	cfg := config.Load()
+	ctx := context.Background()
+	storage := backup.NewStorage(cfg.Backups)
+
+	if *list {
+		objects, err := storage.List(ctx)
+		if err != nil {
+			log.Fatal("Failed to list backups: ", err)
+		}
+		for _, obj := range objects {
+			fmt.Printf("%s\t%d\t%s\n", obj.Name, obj.Size, obj.CreatedAt.Format("2006-01-02 15:04:05"))
+		}
+		return
+	}
+
+	if cfg.IsProduction() {
+		log.Fatal("Refusing to restore with APP_ENV=prod")
+	}
+	if cfg.DBDriver != config.DriverPostgres {
+		log.Fatalf("Backups can only be restored into Postgres, not %q", cfg.DBDriver)
+	}
+	if (*file == "") == (flag.NArg() != 1) {
+		fmt.Fprintln(os.Stderr, "usage: restore-backup [-file path | name]")
+		os.Exit(2)
+	}
+
+	var archive io.ReadCloser
+	var err error
+	if *file != "" {
+		archive, err = os.Open(*file)
+	} else {
+		archive, err = storage.Get(ctx, flag.Arg(0))
+	}
+	if err != nil {
+		log.Fatal("Failed to open backup: ", err)
+	}
+	defer archive.Close()
+
+	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		log.Fatal("Failed to connect to database: ", err)
+	}
+	defer pool.Close()
+
+	manifest, err := backup.Restore(ctx, pool, archive)
+	if err != nil {
+		log.Fatal("Restore failed: ", err)
+	}
+
+	fmt.Printf("Restored backup taken at %s\n", manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+	tables := make([]string, 0, len(manifest.Tables))
+	for table := range manifest.Tables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		fmt.Printf("  %-22s %d rows\n", table, manifest.Tables[table])
+	}
+}
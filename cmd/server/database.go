@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"BACKEND/config"
+	"BACKEND/internal/tracing"
+)
+
+// database holds whichever connection DB_DRIVER selected; the other is nil.
+// pgReadPool is only set when DATABASE_READ_URL is. With STORAGE=memory
+// there is no connection at all.
+type database struct {
+	pgPool     *pgxpool.Pool
+	pgReadPool *pgxpool.Pool
+	sqlDB      *sql.DB
+	ping       func(context.Context) error
+	maxConns   int
+	close      func()
+}
+
+func openDatabase(cfg *config.Config) (*database, error) {
+	if cfg.Storage == config.StorageMemory {
+		return &database{
+			ping:  func(context.Context) error { return nil },
+			close: func() {},
+		}, nil
+	}
+
+	switch cfg.DBDriver {
+	case config.DriverPostgres:
+		pool, err := newPgPool(cfg.DatabaseURL)
+		if err != nil {
+			return nil, err
+		}
+		db := &database{
+			pgPool:   pool,
+			ping:     pool.Ping,
+			maxConns: int(pool.Config().MaxConns),
+			close:    pool.Close,
+		}
+		if cfg.Replication.ReadURL != "" {
+			readPool, err := newPgPool(cfg.Replication.ReadURL)
+			if err != nil {
+				pool.Close()
+				return nil, fmt.Errorf("read replica: %w", err)
+			}
+			db.pgReadPool = readPool
+			db.close = func() {
+				readPool.Close()
+				pool.Close()
+			}
+		}
+		return db, nil
+
+	case config.DriverMySQL:
+		if cfg.Replication.ReadURL != "" {
+			return nil, fmt.Errorf("DATABASE_READ_URL is only supported with %s", config.DriverPostgres)
+		}
+		// The driver is registered by driver_mysql.go, which is only
+		// compiled with -tags mysql.
+		db, err := sql.Open("mysql", cfg.DatabaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("%w (build with -tags mysql)", err)
+		}
+		return &database{
+			sqlDB:    db,
+			ping:     db.PingContext,
+			maxConns: db.Stats().MaxOpenConnections,
+			close:    func() { db.Close() },
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported DB_DRIVER %q", cfg.DBDriver)
+}
+
+// newPgPool connects to url with a span recorded for each query.
+func newPgPool(url string) (*pgxpool.Pool, error) {
+	poolConfig, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		return nil, err
+	}
+	poolConfig.ConnConfig.Tracer = tracing.QueryTracer{}
+	return pgxpool.NewWithConfig(context.Background(), poolConfig)
+}
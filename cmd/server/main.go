@@ -8,44 +8,48 @@ import (
 	"syscall"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 
 	"BACKEND/config"
-	"BACKEND/db/sqlc/generated"
-	"BACKEND/internal/handler"
 	"BACKEND/internal/logger"
 	"BACKEND/internal/middleware"
-	"BACKEND/internal/repository"
-	"BACKEND/internal/routes"
-	"BACKEND/internal/service"
+	"BACKEND/internal/tracing"
+	"BACKEND/internal/version"
+	"BACKEND/useapi"
 )
 
 func main() {
 	cfg := config.Load()
-	appLogger := logger.New()
+	appLogger := logger.New(cfg.LogLevel, cfg.LogFormat, cfg.LogStackTraces)
 	defer appLogger.Sync()
 	middleware.InitLogger(appLogger)
-	dbPool, err := pgxpool.New(context.Background(), cfg.DatabaseURL)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing, version.Get().Version)
 	if err != nil {
-		log.Fatal("Failed to connect to database:", err)
+		log.Fatal("Failed to set up tracing:", err)
 	}
-	defer dbPool.Close()
-
-	appLogger.Info("Connected to database successfully")
-
-	queries := generated.New(dbPool)
-	userRepo := repository.NewUserRepository(queries)
-	userSvc := service.NewUserService(userRepo)
-	userHandler := handler.NewUserHandler(userRepo, userSvc, appLogger)
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			appLogger.Error("Tracing shutdown error", zap.Error(err))
+		}
+	}()
 
-	authSvc := service.NewAuthService(userRepo)
-	authSvc.SetJWTConfig(cfg.JWTSecret, cfg.JWTExpiry)
-	authHandler := handler.NewAuthHandler(authSvc, appLogger, cfg.CookieSecure)
+	db, err := openDatabase(cfg)
+	if err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	defer db.close()
 
-	adminHandler := handler.NewAdminHandler(userRepo, appLogger)
+	logStartupReport(appLogger, cfg, db)
 
 	app := fiber.New(fiber.Config{
+		Prefork:          cfg.Server.Prefork,
+		Concurrency:      cfg.Server.Concurrency,
+		ReadTimeout:      cfg.Server.ReadTimeout,
+		WriteTimeout:     cfg.Server.WriteTimeout,
+		BodyLimit:        cfg.Server.BodyLimit,
+		IdleTimeout:      cfg.Server.IdleTimeout,
+		DisableKeepalive: !cfg.Server.Keepalive,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			code := fiber.StatusInternalServerError
 			if e, ok := err.(*fiber.Error); ok {
@@ -55,7 +59,24 @@ func main() {
 		},
 	})
 
-	routes.Register(app, userHandler, authHandler, adminHandler, cfg.JWTSecret)
+	// Fiber doesn't expose these, so they are set on its fasthttp server.
+	server := app.Server()
+	server.TCPKeepalive = cfg.Server.TCPKeepalivePeriod > 0
+	server.TCPKeepalivePeriod = cfg.Server.TCPKeepalivePeriod
+	server.MaxConnsPerIP = cfg.Server.MaxConnsPerIP
+	server.MaxRequestsPerConn = cfg.Server.MaxRequestsPerConn
+
+	api, err := useapi.Mount(app, useapi.Options{
+		Config: cfg,
+		DB:     db.pgPool,
+		ReadDB: db.pgReadPool,
+		MySQL:  db.sqlDB,
+		Logger: appLogger,
+	})
+	if err != nil {
+		log.Fatal("Failed to mount API:", err)
+	}
+	defer api.Close()
 
 	go func() {
 		sigint := make(chan os.Signal, 1)
@@ -63,6 +84,7 @@ func main() {
 		<-sigint
 
 		appLogger.Info("Shutting down server...")
+		api.Close()
 		if err := app.Shutdown(); err != nil {
 			appLogger.Error("Server shutdown error", zap.Error(err))
 		}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/config"
+	"BACKEND/internal/version"
+)
+
+const startupPingTimeout = 3 * time.Second
+
+func logStartupReport(log *zap.Logger, cfg *config.Config, db *database) {
+	buildInfo := version.Get()
+
+	ctx, cancel := context.WithTimeout(context.Background(), startupPingTimeout)
+	defer cancel()
+
+	start := time.Now()
+	dbErr := db.ping(ctx)
+	dbLatency := time.Since(start)
+
+	dbFields := []zap.Field{
+		zap.String("status", "up"),
+		zap.Duration("latency", dbLatency),
+		zap.Int("max_conns", db.maxConns),
+	}
+	if dbErr != nil {
+		dbFields[0] = zap.String("status", "down")
+		dbFields = append(dbFields, zap.Error(dbErr))
+	}
+
+	dbName := cfg.DBDriver
+	if cfg.Storage == config.StorageMemory {
+		dbName = config.StorageMemory
+	}
+
+	fields := []zap.Field{
+		zap.Dict("build",
+			zap.String("version", buildInfo.Version),
+			zap.String("git_commit", buildInfo.GitCommit),
+			zap.String("build_time", buildInfo.BuildTime),
+			zap.String("go_version", buildInfo.GoVersion),
+		),
+		zap.Dict("config",
+			zap.String("app_env", cfg.AppEnv),
+			zap.String("storage", cfg.Storage),
+			zap.String("db_driver", cfg.DBDriver),
+			zap.String("server_port", cfg.ServerPort),
+			zap.Bool("prefork", cfg.Server.Prefork),
+			zap.String("log_level", cfg.LogLevel),
+			zap.String("log_format", cfg.LogFormat),
+		),
+		zap.Dict("features",
+			zap.String("auth_mode", "jwt_bearer"),
+			zap.Duration("jwt_expiry", cfg.JWTExpiry),
+			zap.Bool("cookie_secure", cfg.CookieSecure),
+			zap.String("cache_backend", "none"),
+			zap.String("mailer_driver", mailerDriver(cfg)),
+			zap.String("rate_limits", rateLimitStore(cfg.RateLimit.Requests, cfg.RateLimit.Store)),
+			zap.String("sensitive_rate_limits", rateLimitStore(cfg.SensitiveRateLimit.Requests, cfg.SensitiveRateLimit.Store)),
+			zap.Bool("load_shedding", cfg.LoadShedding.Enabled),
+			zap.Strings("allowed_origins", cfg.AllowedOrigins),
+			zap.Bool("docs_enabled", cfg.DocsEnabled),
+			zap.Bool("scim_enabled", cfg.SCIMToken != ""),
+			zap.Bool("sso_enabled", cfg.OIDC.Enabled()),
+			zap.Int("identity_providers", len(cfg.IdentityProviders)),
+			zap.String("billing_provider", cfg.Billing.Provider),
+			zap.Bool("read_replica", db.pgReadPool != nil),
+		),
+		zap.Dict("route_limits",
+			routeLimitsField("auth", cfg.AuthRoutes),
+			routeLimitsField("users", cfg.UserRoutes),
+			routeLimitsField("admin", cfg.AdminRoutes),
+		),
+		zap.Dict("dependencies",
+			zap.Dict(dbName, dbFields...),
+		),
+	}
+
+	if dbErr != nil {
+		log.Warn("startup report: one or more dependencies are unhealthy", fields...)
+		return
+	}
+	log.Info("startup report", fields...)
+}
+
+func routeLimitsField(group string, limits config.RouteLimits) zap.Field {
+	return zap.Dict(group,
+		zap.Duration("timeout", limits.Timeout),
+		zap.Int("max_concurrent", limits.MaxConcurrent),
+	)
+}
+
+func mailerDriver(cfg *config.Config) string {
+	if cfg.Mailer.SMTPAddr != "" {
+		return "smtp"
+	}
+	return "log"
+}
+
+func rateLimitStore(requests int, store string) string {
+	if requests <= 0 {
+		return "disabled"
+	}
+	return store
+}
@@ -0,0 +1,257 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DocumentVersion is the version of the configuration document format.
+const DocumentVersion = 1
+
+// Document is the admin-managed part of the configuration as one JSON
+// document, so it can be exported from one environment and diffed against
+// another. Nothing applies a document: the differences are reported as
+// environment variables to set. Secrets, connection strings and URLs
+// specific to an environment are left out. Durations are Go duration
+// strings.
+type Document struct {
+	Version     int          `json:"version"`
+	Roles       []string     `json:"roles"`
+	Permissions []Permission `json:"permissions"`
+	Webhooks    Webhooks     `json:"webhooks"`
+	Flags       Flags        `json:"flags"`
+	Quotas      Quotas       `json:"quotas"`
+}
+
+// Permission is an authorization rule. Rules are defined in code, so they
+// are exported for review and differences in them have no setting.
+type Permission struct {
+	Name    string   `json:"name"`
+	Actions []string `json:"actions"`
+}
+
+type Webhooks struct {
+	BeforeUserCreate string `json:"before_user_create"`
+	AfterLogin       string `json:"after_login"`
+	BeforeDelete     string `json:"before_delete"`
+	SecurityAlert    string `json:"security_alert"`
+	Timeout          string `json:"timeout"`
+}
+
+type Flags struct {
+	DocsEnabled         bool `json:"docs_enabled"`
+	StrictJSON          bool `json:"strict_json"`
+	LoadShedding        bool `json:"load_shedding"`
+	OIDCJITProvisioning bool `json:"oidc_jit_provisioning"`
+}
+
+type Quotas struct {
+	RateLimit   RateLimitQuota `json:"rate_limit"`
+	AuthRoutes  RouteQuota     `json:"auth_routes"`
+	UserRoutes  RouteQuota     `json:"user_routes"`
+	AdminRoutes RouteQuota     `json:"admin_routes"`
+}
+
+type RateLimitQuota struct {
+	Requests     int    `json:"requests"`
+	Window       string `json:"window"`
+	WarnPercent  int    `json:"warn_percent"`
+	GracePercent int    `json:"grace_percent"`
+}
+
+type RouteQuota struct {
+	Timeout       string `json:"timeout"`
+	MaxConcurrent int    `json:"max_concurrent"`
+}
+
+// Setting is one value of a Document with the environment variable that
+// sets it. Env is empty for values that can't be set from the environment.
+type Setting struct {
+	Path  string `json:"path"`
+	Env   string `json:"env,omitempty"`
+	Value string `json:"value"`
+}
+
+// Change is a setting whose value differs between two documents.
+type Change struct {
+	Path string `json:"path"`
+	Env  string `json:"env,omitempty"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Document exports c. permissions are the authorization rules in force,
+// which live outside the configuration.
+func (c *Config) Document(permissions []Permission) Document {
+	routeQuota := func(r RouteLimits) RouteQuota {
+		return RouteQuota{Timeout: r.Timeout.String(), MaxConcurrent: r.MaxConcurrent}
+	}
+	return Document{
+		Version:     DocumentVersion,
+		Roles:       c.RoleHierarchy,
+		Permissions: permissions,
+		Webhooks: Webhooks{
+			BeforeUserCreate: c.Hooks.BeforeUserCreateURL,
+			AfterLogin:       c.Hooks.AfterLoginURL,
+			BeforeDelete:     c.Hooks.BeforeDeleteURL,
+			SecurityAlert:    c.BruteForce.AlertWebhookURL,
+			Timeout:          c.Hooks.Timeout.String(),
+		},
+		Flags: Flags{
+			DocsEnabled:         c.DocsEnabled,
+			StrictJSON:          c.StrictJSON,
+			LoadShedding:        c.LoadShedding.Enabled,
+			OIDCJITProvisioning: c.OIDC.JITProvisioning,
+		},
+		Quotas: Quotas{
+			RateLimit: RateLimitQuota{
+				Requests:     c.RateLimit.Requests,
+				Window:       c.RateLimit.Window.String(),
+				WarnPercent:  c.RateLimit.WarnPercent,
+				GracePercent: c.RateLimit.GracePercent,
+			},
+			AuthRoutes:  routeQuota(c.AuthRoutes),
+			UserRoutes:  routeQuota(c.UserRoutes),
+			AdminRoutes: routeQuota(c.AdminRoutes),
+		},
+	}
+}
+
+// Settings flattens d in a fixed order.
+func (d Document) Settings() []Setting {
+	settings := []Setting{
+		{"roles", "ROLE_HIERARCHY", strings.Join(d.Roles, ",")},
+	}
+	for _, p := range d.Permissions {
+		settings = append(settings, Setting{"permissions." + p.Name, "", strings.Join(p.Actions, ",")})
+	}
+	settings = append(settings,
+		Setting{"webhooks.before_user_create", "HOOK_BEFORE_USER_CREATE_URL", d.Webhooks.BeforeUserCreate},
+		Setting{"webhooks.after_login", "HOOK_AFTER_LOGIN_URL", d.Webhooks.AfterLogin},
+		Setting{"webhooks.before_delete", "HOOK_BEFORE_DELETE_URL", d.Webhooks.BeforeDelete},
+		Setting{"webhooks.security_alert", "SECURITY_ALERT_WEBHOOK_URL", d.Webhooks.SecurityAlert},
+		Setting{"webhooks.timeout", "HOOK_TIMEOUT", d.Webhooks.Timeout},
+		Setting{"flags.docs_enabled", "DOCS_ENABLED", strconv.FormatBool(d.Flags.DocsEnabled)},
+		Setting{"flags.strict_json", "STRICT_JSON", strconv.FormatBool(d.Flags.StrictJSON)},
+		Setting{"flags.load_shedding", "LOAD_SHEDDING_ENABLED", strconv.FormatBool(d.Flags.LoadShedding)},
+		Setting{"flags.oidc_jit_provisioning", "OIDC_JIT_PROVISIONING", strconv.FormatBool(d.Flags.OIDCJITProvisioning)},
+		Setting{"quotas.rate_limit.requests", "RATE_LIMIT_REQUESTS", strconv.Itoa(d.Quotas.RateLimit.Requests)},
+		Setting{"quotas.rate_limit.window", "RATE_LIMIT_WINDOW", d.Quotas.RateLimit.Window},
+		Setting{"quotas.rate_limit.warn_percent", "RATE_LIMIT_WARN_PERCENT", strconv.Itoa(d.Quotas.RateLimit.WarnPercent)},
+		Setting{"quotas.rate_limit.grace_percent", "RATE_LIMIT_GRACE_PERCENT", strconv.Itoa(d.Quotas.RateLimit.GracePercent)},
+		Setting{"quotas.auth_routes.timeout", "AUTH_ROUTE_TIMEOUT", d.Quotas.AuthRoutes.Timeout},
+		Setting{"quotas.auth_routes.max_concurrent", "AUTH_MAX_CONCURRENT", strconv.Itoa(d.Quotas.AuthRoutes.MaxConcurrent)},
+		Setting{"quotas.user_routes.timeout", "USER_ROUTE_TIMEOUT", d.Quotas.UserRoutes.Timeout},
+		Setting{"quotas.user_routes.max_concurrent", "USER_MAX_CONCURRENT", strconv.Itoa(d.Quotas.UserRoutes.MaxConcurrent)},
+		Setting{"quotas.admin_routes.timeout", "ADMIN_ROUTE_TIMEOUT", d.Quotas.AdminRoutes.Timeout},
+		Setting{"quotas.admin_routes.max_concurrent", "ADMIN_MAX_CONCURRENT", strconv.Itoa(d.Quotas.AdminRoutes.MaxConcurrent)},
+	)
+	return settings
+}
+
+// Validate checks that d could be loaded as configuration and returns one
+// message per problem.
+func (d Document) Validate() []string {
+	var problems []string
+	fail := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if d.Version != DocumentVersion {
+		fail("version must be %d", DocumentVersion)
+	}
+
+	seen := make(map[string]bool)
+	for _, role := range d.Roles {
+		if role == "" || strings.ContainsAny(role, ", ") {
+			fail("roles: %q is not a valid role name", role)
+		}
+		if seen[role] {
+			fail("roles: %q is listed twice", role)
+		}
+		seen[role] = true
+	}
+	// The API checks for these roles by name.
+	for _, role := range []string{"admin", "user"} {
+		if !seen[role] {
+			fail("roles: must include %q", role)
+		}
+	}
+
+	for path, raw := range map[string]string{
+		"webhooks.before_user_create": d.Webhooks.BeforeUserCreate,
+		"webhooks.after_login":        d.Webhooks.AfterLogin,
+		"webhooks.before_delete":      d.Webhooks.BeforeDelete,
+		"webhooks.security_alert":     d.Webhooks.SecurityAlert,
+	} {
+		if raw == "" {
+			continue
+		}
+		if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			fail("%s: must be an http or https URL", path)
+		}
+	}
+
+	for path, raw := range map[string]string{
+		"webhooks.timeout":            d.Webhooks.Timeout,
+		"quotas.rate_limit.window":    d.Quotas.RateLimit.Window,
+		"quotas.auth_routes.timeout":  d.Quotas.AuthRoutes.Timeout,
+		"quotas.user_routes.timeout":  d.Quotas.UserRoutes.Timeout,
+		"quotas.admin_routes.timeout": d.Quotas.AdminRoutes.Timeout,
+	} {
+		if v, err := time.ParseDuration(raw); err != nil || v <= 0 {
+			fail("%s: must be a positive duration such as 30s", path)
+		}
+	}
+
+	for path, v := range map[string]int{
+		"quotas.rate_limit.requests":         d.Quotas.RateLimit.Requests,
+		"quotas.auth_routes.max_concurrent":  d.Quotas.AuthRoutes.MaxConcurrent,
+		"quotas.user_routes.max_concurrent":  d.Quotas.UserRoutes.MaxConcurrent,
+		"quotas.admin_routes.max_concurrent": d.Quotas.AdminRoutes.MaxConcurrent,
+	} {
+		if v < 0 {
+			fail("%s: must not be negative", path)
+		}
+	}
+	for path, v := range map[string]int{
+		"quotas.rate_limit.warn_percent":  d.Quotas.RateLimit.WarnPercent,
+		"quotas.rate_limit.grace_percent": d.Quotas.RateLimit.GracePercent,
+	} {
+		if v < 0 || v > 100 {
+			fail("%s: must be between 0 and 100", path)
+		}
+	}
+
+	// Map iteration order is random; keep the report stable.
+	sort.Strings(problems)
+	return problems
+}
+
+// Diff lists the settings that differ between from and to, in the order of
+// Settings.
+func Diff(from, to Document) []Change {
+	current := make(map[string]Setting)
+	for _, s := range from.Settings() {
+		current[s.Path] = s
+	}
+
+	var changes []Change
+	seen := make(map[string]bool)
+	for _, s := range to.Settings() {
+		seen[s.Path] = true
+		if old, ok := current[s.Path]; !ok || old.Value != s.Value {
+			changes = append(changes, Change{Path: s.Path, Env: s.Env, From: old.Value, To: s.Value})
+		}
+	}
+	for _, s := range from.Settings() {
+		if !seen[s.Path] {
+			changes = append(changes, Change{Path: s.Path, Env: s.Env, From: s.Value})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func testConfig() *Config {
+	return &Config{
+		RoleHierarchy: []string{"admin", "moderator", "user"},
+		Hooks:         Hooks{Timeout: 5 * time.Second},
+		RateLimit:     RateLimit{Requests: 600, Window: time.Minute, WarnPercent: 80, GracePercent: 10},
+		AuthRoutes:    RouteLimits{Timeout: 10 * time.Second, MaxConcurrent: 50},
+		UserRoutes:    RouteLimits{Timeout: 10 * time.Second, MaxConcurrent: 100},
+		AdminRoutes:   RouteLimits{Timeout: 30 * time.Second, MaxConcurrent: 5},
+	}
+}
+
+func TestDocument_DiffAndValidate(t *testing.T) {
+	current := testConfig().Document([]Permission{{Name: "admins manage anyone", Actions: []string{"users:manage"}}})
+	if problems := current.Validate(); len(problems) != 0 {
+		t.Fatalf("exported document is invalid: %v", problems)
+	}
+	if changes := Diff(current, current); len(changes) != 0 {
+		t.Errorf("Diff of a document with itself = %v; want none", changes)
+	}
+
+	imported := current
+	imported.Quotas.RateLimit.Requests = 100
+	imported.Flags.StrictJSON = true
+	imported.Permissions = nil
+
+	changes := Diff(current, imported)
+	want := map[string]Change{
+		"flags.strict_json":                {Env: "STRICT_JSON", From: "false", To: "true"},
+		"quotas.rate_limit.requests":       {Env: "RATE_LIMIT_REQUESTS", From: "600", To: "100"},
+		"permissions.admins manage anyone": {From: "users:manage"},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("Diff = %v; want %d changes", changes, len(want))
+	}
+	for _, change := range changes {
+		w, ok := want[change.Path]
+		if !ok || change.Env != w.Env || change.From != w.From || change.To != w.To {
+			t.Errorf("unexpected change %+v", change)
+		}
+	}
+}
+
+func TestDocument_ValidateRejects(t *testing.T) {
+	doc := testConfig().Document(nil)
+	doc.Roles = []string{"admin", "admin"}
+	doc.Webhooks.AfterLogin = "ftp://hooks.example.com"
+	doc.Quotas.RateLimit.Window = "soon"
+	doc.Quotas.RateLimit.WarnPercent = 120
+
+	if problems := doc.Validate(); len(problems) != 5 {
+		t.Errorf("Validate() = %v; want 5 problems", problems)
+	}
+}
@@ -0,0 +1,12 @@
+CREATE MATERIALIZED VIEW user_stats AS
+SELECT
+    1 AS id,
+    COUNT(*) AS total_users,
+    COUNT(*) FILTER (WHERE role = 'admin') AS admin_users,
+    COUNT(*) FILTER (WHERE created_at >= CURRENT_TIMESTAMP - INTERVAL '7 days') AS signups_last_7_days,
+    COUNT(*) FILTER (WHERE created_at >= CURRENT_TIMESTAMP - INTERVAL '30 days') AS signups_last_30_days,
+    COALESCE(AVG(date_part('year', age(CURRENT_DATE, dob))), 0)::float8 AS average_age,
+    CURRENT_TIMESTAMP::timestamp AS refreshed_at
+FROM users;
+
+CREATE UNIQUE INDEX user_stats_id_idx ON user_stats (id);
@@ -0,0 +1,13 @@
+CREATE TABLE login_history (
+    id SERIAL PRIMARY KEY,
+    user_id INT REFERENCES users(id) ON DELETE CASCADE,
+    email TEXT NOT NULL,
+    succeeded BOOLEAN NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    ip_address TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX login_history_user_id_idx ON login_history (user_id);
+CREATE INDEX login_history_created_at_idx ON login_history (created_at);
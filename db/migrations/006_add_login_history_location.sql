@@ -0,0 +1,2 @@
+ALTER TABLE login_history ADD COLUMN country TEXT NOT NULL DEFAULT '';
+ALTER TABLE login_history ADD COLUMN city TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+CREATE TABLE token_revocations (
+    user_id INT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    revoked_at TIMESTAMP NOT NULL
+);
@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN signup_source TEXT NOT NULL DEFAULT 'unknown';
+
+CREATE INDEX users_signup_source_idx ON users (signup_source, created_at);
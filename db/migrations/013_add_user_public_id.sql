@@ -0,0 +1,3 @@
+ALTER TABLE users ADD COLUMN public_id UUID NOT NULL DEFAULT gen_random_uuid();
+
+CREATE UNIQUE INDEX users_public_id_idx ON users (public_id);
@@ -0,0 +1,33 @@
+ALTER TABLE users ALTER COLUMN id TYPE BIGINT;
+ALTER SEQUENCE users_id_seq AS BIGINT;
+
+ALTER TABLE api_keys
+    ALTER COLUMN id TYPE BIGINT,
+    ALTER COLUMN user_id TYPE BIGINT;
+ALTER SEQUENCE api_keys_id_seq AS BIGINT;
+
+ALTER TABLE login_history
+    ALTER COLUMN id TYPE BIGINT,
+    ALTER COLUMN user_id TYPE BIGINT;
+ALTER SEQUENCE login_history_id_seq AS BIGINT;
+
+ALTER TABLE webauthn_credentials
+    ALTER COLUMN id TYPE BIGINT,
+    ALTER COLUMN user_id TYPE BIGINT;
+ALTER SEQUENCE webauthn_credentials_id_seq AS BIGINT;
+
+ALTER TABLE token_revocations ALTER COLUMN user_id TYPE BIGINT;
+
+ALTER TABLE name_reviews
+    ALTER COLUMN id TYPE BIGINT,
+    ALTER COLUMN user_id TYPE BIGINT,
+    ALTER COLUMN reviewed_by TYPE BIGINT;
+ALTER SEQUENCE name_reviews_id_seq AS BIGINT;
+
+ALTER TABLE referral_codes ALTER COLUMN user_id TYPE BIGINT;
+
+ALTER TABLE referrals
+    ALTER COLUMN id TYPE BIGINT,
+    ALTER COLUMN referrer_id TYPE BIGINT,
+    ALTER COLUMN referred_id TYPE BIGINT;
+ALTER SEQUENCE referrals_id_seq AS BIGINT;
@@ -0,0 +1,13 @@
+CREATE TABLE organization_billing (
+    org_id BIGINT PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL,
+    customer_id TEXT NOT NULL,
+    subscription_id TEXT NOT NULL,
+    plan TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL,
+    seats BIGINT NOT NULL DEFAULT 0,
+    current_period_end TIMESTAMP,
+    event_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (provider, customer_id)
+);
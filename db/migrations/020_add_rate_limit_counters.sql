@@ -0,0 +1,5 @@
+CREATE TABLE rate_limit_counters (
+    client TEXT PRIMARY KEY,
+    window_start TIMESTAMP NOT NULL,
+    hits INTEGER NOT NULL
+);
@@ -0,0 +1,25 @@
+CREATE TABLE security_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_type TEXT NOT NULL,
+    actor_id BIGINT,
+    target_id BIGINT,
+    details TEXT NOT NULL DEFAULT '',
+    ip_address TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    prev_hash TEXT NOT NULL UNIQUE,
+    hash TEXT NOT NULL
+);
+
+CREATE FUNCTION security_events_append_only() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'security_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER security_events_no_change
+BEFORE UPDATE OR DELETE ON security_events
+FOR EACH ROW EXECUTE FUNCTION security_events_append_only();
+
+CREATE TRIGGER security_events_no_truncate
+BEFORE TRUNCATE ON security_events
+FOR EACH STATEMENT EXECUTE FUNCTION security_events_append_only();
@@ -0,0 +1,6 @@
+CREATE TABLE login_lockouts (
+    lock_key TEXT PRIMARY KEY,
+    failures INTEGER NOT NULL,
+    window_start TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP
+);
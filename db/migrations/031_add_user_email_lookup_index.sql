@@ -0,0 +1,4 @@
+-- Serves the admin lookup's case-insensitive prefix match on email;
+-- text_pattern_ops lets LIKE 'prefix%' use the index whatever the
+-- database's collation.
+CREATE INDEX users_email_lookup_idx ON users (LOWER(email) text_pattern_ops) WHERE deleted_at IS NULL;
@@ -0,0 +1,2 @@
+-- Where the user's uploaded avatar is kept in the object storage, or ''.
+ALTER TABLE user_profiles ADD COLUMN avatar_key TEXT NOT NULL DEFAULT '';
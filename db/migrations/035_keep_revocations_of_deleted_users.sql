@@ -0,0 +1,3 @@
+-- Revocations outlive hard-deleted users, whose tokens must stay rejected
+-- until they expire.
+ALTER TABLE token_revocations DROP CONSTRAINT token_revocations_user_id_fkey;
@@ -0,0 +1,8 @@
+-- SSO logins waiting for the IdP's callback, keyed by a hash of their
+-- state, so the callback can reach any instance.
+CREATE TABLE sso_pending_logins (
+    state_hash TEXT PRIMARY KEY,
+    nonce TEXT NOT NULL,
+    code_verifier TEXT NOT NULL,
+    expires_at TIMESTAMP NOT NULL
+);
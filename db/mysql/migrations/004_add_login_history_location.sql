@@ -0,0 +1,2 @@
+ALTER TABLE login_history ADD COLUMN country CHAR(2) NOT NULL DEFAULT '';
+ALTER TABLE login_history ADD COLUMN city VARCHAR(255) NOT NULL DEFAULT '';
@@ -0,0 +1,5 @@
+CREATE TABLE token_revocations (
+    user_id INT PRIMARY KEY,
+    revoked_at TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE users DROP CHECK users_chk_1;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'moderator', 'admin'));
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN signup_source VARCHAR(255) NOT NULL DEFAULT 'unknown',
+    ADD INDEX users_signup_source_idx (signup_source, created_at);
@@ -0,0 +1,7 @@
+ALTER TABLE users ADD COLUMN public_id CHAR(36) NULL;
+
+UPDATE users SET public_id = UUID() WHERE public_id IS NULL;
+
+ALTER TABLE users
+    MODIFY COLUMN public_id CHAR(36) NOT NULL DEFAULT (UUID()),
+    ADD UNIQUE INDEX users_public_id_idx (public_id);
@@ -0,0 +1,33 @@
+-- The referencing columns are widened with the key they point at, so the
+-- foreign keys are briefly between mismatched types.
+SET FOREIGN_KEY_CHECKS = 0;
+
+ALTER TABLE users MODIFY COLUMN id BIGINT AUTO_INCREMENT;
+
+ALTER TABLE api_keys
+    MODIFY COLUMN id BIGINT AUTO_INCREMENT,
+    MODIFY COLUMN user_id BIGINT NOT NULL;
+
+ALTER TABLE login_history
+    MODIFY COLUMN id BIGINT AUTO_INCREMENT,
+    MODIFY COLUMN user_id BIGINT NULL;
+
+ALTER TABLE webauthn_credentials
+    MODIFY COLUMN id BIGINT AUTO_INCREMENT,
+    MODIFY COLUMN user_id BIGINT NOT NULL;
+
+ALTER TABLE token_revocations MODIFY COLUMN user_id BIGINT;
+
+ALTER TABLE name_reviews
+    MODIFY COLUMN id BIGINT AUTO_INCREMENT,
+    MODIFY COLUMN user_id BIGINT NOT NULL,
+    MODIFY COLUMN reviewed_by BIGINT NULL;
+
+ALTER TABLE referral_codes MODIFY COLUMN user_id BIGINT;
+
+ALTER TABLE referrals
+    MODIFY COLUMN id BIGINT AUTO_INCREMENT,
+    MODIFY COLUMN referrer_id BIGINT NOT NULL,
+    MODIFY COLUMN referred_id BIGINT NOT NULL;
+
+SET FOREIGN_KEY_CHECKS = 1;
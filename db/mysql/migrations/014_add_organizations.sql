@@ -0,0 +1,24 @@
+CREATE TABLE organizations (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE organization_members (
+    user_id BIGINT PRIMARY KEY,
+    org_id BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX organization_members_org_id_idx (org_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (org_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
+
+CREATE TABLE organization_usage (
+    org_id BIGINT NOT NULL,
+    day DATE NOT NULL,
+    seats BIGINT NOT NULL DEFAULT 0,
+    api_calls BIGINT NOT NULL DEFAULT 0,
+    storage_bytes BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (org_id, day),
+    FOREIGN KEY (org_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
@@ -0,0 +1,14 @@
+CREATE TABLE organization_billing (
+    org_id BIGINT PRIMARY KEY,
+    provider VARCHAR(64) NOT NULL,
+    customer_id VARCHAR(255) NOT NULL,
+    subscription_id VARCHAR(255) NOT NULL,
+    plan VARCHAR(255) NOT NULL DEFAULT '',
+    status VARCHAR(32) NOT NULL,
+    seats BIGINT NOT NULL DEFAULT 0,
+    current_period_end TIMESTAMP NULL,
+    event_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY organization_billing_provider_customer_id_key (provider, customer_id),
+    FOREIGN KEY (org_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+CREATE TABLE api_usage (
+    user_id BIGINT NOT NULL,
+    api_key_id BIGINT NOT NULL DEFAULT 0,
+    day DATE NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, api_key_id, day),
+    INDEX api_usage_day_idx (day),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+CREATE TABLE job_leases (
+    name VARCHAR(100) PRIMARY KEY,
+    holder VARCHAR(255) NOT NULL,
+    expires_at TIMESTAMP(6) NOT NULL
+);
@@ -0,0 +1,5 @@
+CREATE TABLE rate_limit_counters (
+    client VARCHAR(255) PRIMARY KEY,
+    window_start TIMESTAMP(6) NOT NULL,
+    hits INT NOT NULL
+);
@@ -0,0 +1,17 @@
+CREATE TABLE security_events (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    event_type VARCHAR(64) NOT NULL,
+    actor_id BIGINT NULL,
+    target_id BIGINT NULL,
+    details TEXT NOT NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP(6) NOT NULL,
+    prev_hash CHAR(64) NOT NULL UNIQUE,
+    hash CHAR(64) NOT NULL
+);
+
+CREATE TRIGGER security_events_no_update BEFORE UPDATE ON security_events
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'security_events is append-only';
+
+CREATE TRIGGER security_events_no_delete BEFORE DELETE ON security_events
+FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'security_events is append-only';
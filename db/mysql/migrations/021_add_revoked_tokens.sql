@@ -0,0 +1,8 @@
+CREATE TABLE revoked_tokens (
+    jti CHAR(32) PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    revoked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    INDEX revoked_tokens_expires_at_idx (expires_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
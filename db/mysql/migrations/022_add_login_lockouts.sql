@@ -0,0 +1,6 @@
+CREATE TABLE login_lockouts (
+    lock_key VARCHAR(320) PRIMARY KEY,
+    failures INT NOT NULL,
+    window_start TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP NULL
+);
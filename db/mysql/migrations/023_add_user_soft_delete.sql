@@ -0,0 +1,8 @@
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP NULL;
+
+-- Deleted users keep their email, but it can be signed up with again: the
+-- unique index is on a column that is NULL once a user is deleted.
+ALTER TABLE users
+    ADD COLUMN live_email VARCHAR(255) AS (IF(deleted_at IS NULL, email, NULL)) STORED,
+    DROP INDEX email,
+    ADD UNIQUE INDEX email (live_email);
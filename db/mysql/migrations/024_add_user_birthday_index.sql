@@ -0,0 +1,3 @@
+-- Birthdays are looked up as month * 100 + day, e.g. 1231 for 31 December,
+-- so a window of days is one range, or two across the new year.
+CREATE INDEX users_birthday_idx ON users ((MONTH(dob) * 100 + DAY(dob)));
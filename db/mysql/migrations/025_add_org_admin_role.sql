@@ -0,0 +1,2 @@
+ALTER TABLE users DROP CHECK users_role_check;
+ALTER TABLE users ADD CONSTRAINT users_role_check CHECK (role IN ('user', 'org_admin', 'moderator', 'admin'));
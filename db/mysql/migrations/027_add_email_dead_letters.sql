@@ -0,0 +1,10 @@
+CREATE TABLE email_dead_letters (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    recipients TEXT NOT NULL,
+    subject VARCHAR(998) NOT NULL,
+    html MEDIUMTEXT NOT NULL,
+    error TEXT NOT NULL,
+    attempts INT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_attempt_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,34 @@
+CREATE TABLE notification_rules (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    event_type VARCHAR(64) NOT NULL,
+    channel VARCHAR(16) NOT NULL,
+    audience VARCHAR(16) NOT NULL DEFAULT '',
+    template VARCHAR(64) NOT NULL DEFAULT '',
+    webhook_url VARCHAR(2048) NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX notification_rules_event_type_idx (event_type),
+    CONSTRAINT notification_rules_channel_check CHECK (channel IN ('email', 'webhook', 'in_app'))
+);
+
+CREATE TABLE notification_preferences (
+    user_id BIGINT NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    channel VARCHAR(16) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    PRIMARY KEY (user_id, event_type, channel),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE notifications (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    title VARCHAR(998) NOT NULL,
+    details TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    read_at TIMESTAMP NULL,
+    INDEX notifications_user_id_idx (user_id, created_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,12 @@
+-- Profile fields users set themselves. They live apart from users so the
+-- public user queries can't return them by accident.
+CREATE TABLE user_profiles (
+    user_id BIGINT PRIMARY KEY,
+    bio VARCHAR(500) NOT NULL DEFAULT '',
+    phone VARCHAR(16) NOT NULL DEFAULT '',
+    avatar_url VARCHAR(2048) NOT NULL DEFAULT '',
+    locale VARCHAR(35) NOT NULL DEFAULT '',
+    timezone VARCHAR(64) NOT NULL DEFAULT '',
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
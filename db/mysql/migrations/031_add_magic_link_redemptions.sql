@@ -0,0 +1,6 @@
+-- Magic links that have been used, kept until they expire so each works
+-- once across all instances.
+CREATE TABLE magic_link_redemptions (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP(6) NOT NULL
+);
@@ -0,0 +1,3 @@
+-- Revocations keep milliseconds, like token issue times, so tokens issued
+-- later in the same second as a forced logout stay valid.
+ALTER TABLE token_revocations MODIFY COLUMN revoked_at TIMESTAMP(3) NOT NULL;
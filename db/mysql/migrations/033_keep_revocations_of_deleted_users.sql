@@ -0,0 +1,3 @@
+-- Revocations outlive hard-deleted users, whose tokens must stay rejected
+-- until they expire.
+ALTER TABLE token_revocations DROP FOREIGN KEY token_revocations_ibfk_1;
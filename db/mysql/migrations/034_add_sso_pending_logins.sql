@@ -0,0 +1,8 @@
+-- SSO logins waiting for the IdP's callback, keyed by a hash of their
+-- state, so the callback can reach any instance.
+CREATE TABLE sso_pending_logins (
+    state_hash VARCHAR(64) PRIMARY KEY,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP(6) NOT NULL
+);
@@ -0,0 +1,793 @@
+-- name: CreateUser :execlastid
+INSERT INTO users (name, dob, email, password_hash, role, signup_source)
+VALUES (?, ?, ?, ?, COALESCE(NULLIF(sqlc.arg(role), ''), 'user'), sqlc.arg(signup_source));
+
+-- name: GetUserByID :one
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: GetDeletedUserByID :one
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- name: GetUserIDByPublicID :one
+SELECT id
+FROM users
+WHERE public_id = ?;
+
+-- name: GetUserByEmail :one
+SELECT id, name, dob, email, password_hash, role, created_at, updated_at, active, account_type, signup_source, public_id, deleted_at, live_email
+FROM users
+WHERE email = ? AND deleted_at IS NULL;
+
+-- name: ListUsers :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE deleted_at IS NULL
+ORDER BY id;
+
+-- name: ListUsersPaginated :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE deleted_at IS NULL
+ORDER BY id
+LIMIT ? OFFSET ?;
+
+-- name: ListUsersBySignupSource :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE signup_source = ? AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: CountUsers :one
+SELECT COUNT(*)
+FROM users
+WHERE deleted_at IS NULL;
+
+-- name: SearchUsers :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE deleted_at IS NULL
+    AND (sqlc.narg(name_pattern) IS NULL OR LOWER(name) LIKE LOWER(sqlc.narg(name_pattern)))
+    AND (sqlc.narg(email) IS NULL OR LOWER(email) = LOWER(sqlc.narg(email)))
+    AND (sqlc.narg(role) IS NULL OR role = sqlc.narg(role))
+    AND (sqlc.narg(signup_source) IS NULL OR signup_source = sqlc.narg(signup_source))
+    AND (sqlc.narg(dob_after) IS NULL OR dob > sqlc.narg(dob_after))
+    AND (sqlc.narg(dob_until) IS NULL OR dob <= sqlc.narg(dob_until))
+    AND (sqlc.narg(created_from) IS NULL OR created_at >= sqlc.narg(created_from))
+    AND (sqlc.narg(created_before) IS NULL OR created_at < sqlc.narg(created_before))
+    AND (sqlc.narg(org_id) IS NULL OR id IN (SELECT user_id FROM organization_members WHERE org_id = sqlc.narg(org_id)))
+ORDER BY
+    CASE sqlc.arg(sort1) WHEN 'name' THEN name WHEN 'email' THEN email WHEN 'role' THEN role END,
+    CASE sqlc.arg(sort1) WHEN 'dob' THEN dob END,
+    CASE sqlc.arg(sort1) WHEN 'created_at' THEN created_at WHEN 'updated_at' THEN updated_at END,
+    CASE sqlc.arg(sort1) WHEN '-name' THEN name WHEN '-email' THEN email WHEN '-role' THEN role END DESC,
+    CASE sqlc.arg(sort1) WHEN '-dob' THEN dob END DESC,
+    CASE sqlc.arg(sort1) WHEN '-created_at' THEN created_at WHEN '-updated_at' THEN updated_at END DESC,
+    CASE sqlc.arg(sort2) WHEN 'name' THEN name WHEN 'email' THEN email WHEN 'role' THEN role END,
+    CASE sqlc.arg(sort2) WHEN 'dob' THEN dob END,
+    CASE sqlc.arg(sort2) WHEN 'created_at' THEN created_at WHEN 'updated_at' THEN updated_at END,
+    CASE sqlc.arg(sort2) WHEN '-name' THEN name WHEN '-email' THEN email WHEN '-role' THEN role END DESC,
+    CASE sqlc.arg(sort2) WHEN '-dob' THEN dob END DESC,
+    CASE sqlc.arg(sort2) WHEN '-created_at' THEN created_at WHEN '-updated_at' THEN updated_at END DESC,
+    CASE sqlc.arg(sort3) WHEN 'name' THEN name WHEN 'email' THEN email WHEN 'role' THEN role END,
+    CASE sqlc.arg(sort3) WHEN 'dob' THEN dob END,
+    CASE sqlc.arg(sort3) WHEN 'created_at' THEN created_at WHEN 'updated_at' THEN updated_at END,
+    CASE sqlc.arg(sort3) WHEN '-name' THEN name WHEN '-email' THEN email WHEN '-role' THEN role END DESC,
+    CASE sqlc.arg(sort3) WHEN '-dob' THEN dob END DESC,
+    CASE sqlc.arg(sort3) WHEN '-created_at' THEN created_at WHEN '-updated_at' THEN updated_at END DESC,
+    id
+LIMIT ? OFFSET ?;
+
+-- name: CountSearchUsers :one
+SELECT COUNT(*)
+FROM users
+WHERE deleted_at IS NULL
+    AND (sqlc.narg(name_pattern) IS NULL OR LOWER(name) LIKE LOWER(sqlc.narg(name_pattern)))
+    AND (sqlc.narg(email) IS NULL OR LOWER(email) = LOWER(sqlc.narg(email)))
+    AND (sqlc.narg(role) IS NULL OR role = sqlc.narg(role))
+    AND (sqlc.narg(signup_source) IS NULL OR signup_source = sqlc.narg(signup_source))
+    AND (sqlc.narg(dob_after) IS NULL OR dob > sqlc.narg(dob_after))
+    AND (sqlc.narg(dob_until) IS NULL OR dob <= sqlc.narg(dob_until))
+    AND (sqlc.narg(created_from) IS NULL OR created_at >= sqlc.narg(created_from))
+    AND (sqlc.narg(created_before) IS NULL OR created_at < sqlc.narg(created_before))
+    AND (sqlc.narg(org_id) IS NULL OR id IN (SELECT user_id FROM organization_members WHERE org_id = sqlc.narg(org_id)));
+
+-- name: UpdateUser :execrows
+UPDATE users
+SET name = ?, dob = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: UpdateUserPassword :execrows
+UPDATE users
+SET password_hash = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: RestoreUser :execrows
+UPDATE users
+SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NOT NULL;
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = ?;
+
+-- name: UsersByAgeBracket :many
+SELECT bracket, COUNT(*) AS user_count
+FROM (
+    SELECT CASE
+        WHEN age < 18 THEN 'under_18'
+        WHEN age < 25 THEN '18_24'
+        WHEN age < 35 THEN '25_34'
+        WHEN age < 45 THEN '35_44'
+        WHEN age < 55 THEN '45_54'
+        WHEN age < 65 THEN '55_64'
+        ELSE '65_plus'
+    END AS bracket,
+    age
+    FROM (
+        SELECT TIMESTAMPDIFF(YEAR, dob, CURDATE()) AS age
+        FROM users
+        WHERE deleted_at IS NULL
+    ) ages
+) brackets
+GROUP BY bracket
+ORDER BY MIN(age);
+
+-- name: AgeDistribution :many
+SELECT grp, CAST(FLOOR(age / size) * size AS SIGNED) AS bucket_start, COUNT(*) AS user_count
+FROM (
+    SELECT CASE CAST(sqlc.arg(group_by) AS CHAR)
+        WHEN 'role' THEN role
+        WHEN 'signup_cohort' THEN DATE_FORMAT(created_at, '%Y-%m')
+        ELSE ''
+    END AS grp,
+    TIMESTAMPDIFF(YEAR, dob, CURDATE()) AS age,
+    CAST(sqlc.arg(bucket_size) AS SIGNED) AS size
+    FROM users
+    WHERE deleted_at IS NULL
+) ages
+GROUP BY grp, bucket_start
+ORDER BY grp, bucket_start;
+
+-- name: SignupsByDay :many
+SELECT DATE_FORMAT(created_at, '%Y-%m-%d') AS day, COUNT(*) AS signups
+FROM users
+WHERE created_at >= sqlc.arg(since) AND deleted_at IS NULL
+GROUP BY day
+ORDER BY day;
+
+-- name: SignupsByMonth :many
+SELECT DATE_FORMAT(created_at, '%Y-%m') AS month, COUNT(*) AS signups
+FROM users
+WHERE created_at >= sqlc.arg(since) AND deleted_at IS NULL
+GROUP BY month
+ORDER BY month;
+
+-- name: SignupsBySource :many
+SELECT signup_source, COUNT(*) AS signups
+FROM users
+WHERE created_at >= sqlc.arg(since) AND deleted_at IS NULL
+GROUP BY signup_source
+ORDER BY signups DESC, signup_source;
+
+-- name: ListUpcomingBirthdays :many
+SELECT id, name, dob, public_id
+FROM users
+WHERE deleted_at IS NULL
+    AND ((MONTH(dob) * 100 + DAY(dob)) BETWEEN sqlc.arg(from_day) AND sqlc.arg(to_day)
+    OR (MONTH(dob) * 100 + DAY(dob)) BETWEEN sqlc.arg(wrap_from) AND sqlc.arg(wrap_to))
+ORDER BY (MONTH(dob) * 100 + DAY(dob)) < sqlc.arg(from_day),
+    MONTH(dob) * 100 + DAY(dob), id
+LIMIT ?;
+
+-- name: ListDeactivatedUsers :many
+SELECT id, name, email, updated_at
+FROM users
+WHERE active = FALSE AND updated_at >= sqlc.arg(since) AND deleted_at IS NULL
+ORDER BY updated_at DESC, id;
+
+-- name: ListActiveAdmins :many
+SELECT id, name, email
+FROM users
+WHERE role = 'admin' AND active = TRUE AND account_type = 'human' AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: GetUserStats :one
+SELECT
+    COUNT(*) AS total_users,
+    COALESCE(SUM(role = 'admin'), 0) AS admin_users,
+    COALESCE(SUM(created_at >= CURRENT_TIMESTAMP - INTERVAL 7 DAY), 0) AS signups_last_7_days,
+    COALESCE(SUM(created_at >= CURRENT_TIMESTAMP - INTERVAL 30 DAY), 0) AS signups_last_30_days,
+    CAST(COALESCE(AVG(TIMESTAMPDIFF(YEAR, dob, CURDATE())), 0) AS DOUBLE) AS average_age,
+    CURRENT_TIMESTAMP AS refreshed_at
+FROM users
+WHERE deleted_at IS NULL;
+
+-- name: SetUserActive :execrows
+UPDATE users
+SET active = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: UpdateUserRole :execrows
+UPDATE users
+SET role = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL;
+
+-- name: CreateServiceAccount :execlastid
+INSERT INTO users (name, dob, email, password_hash, role, account_type, signup_source)
+VALUES (?, CURDATE(), ?, ?, ?, 'service', 'admin');
+
+-- name: ListServiceAccounts :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE account_type = 'service' AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: CreateAPIKey :execlastid
+INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: GetAPIKeyByID :one
+SELECT id, user_id, name, prefix, scopes, created_at, expires_at, last_used_at, revoked_at
+FROM api_keys
+WHERE id = ?;
+
+-- name: ListAPIKeysByUser :many
+SELECT id, user_id, name, prefix, scopes, created_at, expires_at, last_used_at, revoked_at
+FROM api_keys
+WHERE user_id = ?
+ORDER BY id;
+
+-- name: GetAPIKeyByHash :one
+SELECT k.id, k.user_id, k.scopes, k.expires_at, k.revoked_at, u.role, u.active, u.account_type
+FROM api_keys k
+JOIN users u ON u.id = k.user_id
+WHERE k.key_hash = ? AND u.deleted_at IS NULL;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = ? AND user_id = ? AND revoked_at IS NULL;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: RecordLogin :exec
+INSERT INTO login_history (user_id, email, succeeded, reason, ip_address, user_agent, country, city)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListLoginHistoryByUser :many
+SELECT id, email, succeeded, reason, ip_address, user_agent, country, city, created_at
+FROM login_history
+WHERE user_id = ?
+ORDER BY created_at DESC, id DESC
+LIMIT ?;
+
+-- name: LoginHistoryStats :one
+SELECT COUNT(*) AS total, MIN(created_at) AS oldest
+FROM login_history;
+
+-- name: FailedLoginsByDay :many
+SELECT DATE_FORMAT(created_at, '%Y-%m-%d') AS day, COUNT(*) AS failures
+FROM login_history
+WHERE succeeded = FALSE AND created_at >= sqlc.arg(since)
+GROUP BY day
+ORDER BY day;
+
+-- name: PruneLoginHistory :execrows
+DELETE FROM login_history
+WHERE created_at < ?;
+
+-- name: CreateWebAuthnCredential :exec
+INSERT INTO webauthn_credentials (user_id, credential_id, public_key, sign_count, name)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: GetWebAuthnCredential :one
+SELECT id, user_id, credential_id, public_key, sign_count, name, created_at, last_used_at
+FROM webauthn_credentials
+WHERE credential_id = ?;
+
+-- name: ListWebAuthnCredentialsByUser :many
+SELECT id, user_id, credential_id, public_key, sign_count, name, created_at, last_used_at
+FROM webauthn_credentials
+WHERE user_id = ?
+ORDER BY id;
+
+-- name: UpdateWebAuthnCredentialUse :exec
+UPDATE webauthn_credentials
+SET sign_count = ?, last_used_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: DeleteWebAuthnCredential :execrows
+DELETE FROM webauthn_credentials
+WHERE id = ? AND user_id = ?;
+
+-- name: RevokeUserTokens :exec
+INSERT INTO token_revocations (user_id, revoked_at)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE revoked_at = VALUES(revoked_at);
+
+-- name: ListTokenRevocations :many
+SELECT user_id, revoked_at
+FROM token_revocations;
+
+-- name: CreateNameReview :execlastid
+INSERT INTO name_reviews (user_id, name, matched)
+VALUES (?, ?, ?);
+
+-- name: GetNameReview :one
+SELECT id, user_id, name, matched, status, reviewed_by, created_at, reviewed_at
+FROM name_reviews
+WHERE id = ?;
+
+-- name: ListNameReviews :many
+SELECT id, user_id, name, matched, status, reviewed_by, created_at, reviewed_at
+FROM name_reviews
+WHERE status = ?
+ORDER BY created_at, id
+LIMIT ?;
+
+-- name: ResolveNameReview :execrows
+UPDATE name_reviews
+SET status = ?, reviewed_by = ?, reviewed_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending';
+
+-- name: GetReferralCode :one
+SELECT code
+FROM referral_codes
+WHERE user_id = ?;
+
+-- name: CreateReferralCode :execrows
+INSERT IGNORE INTO referral_codes (user_id, code)
+VALUES (?, ?);
+
+-- name: GetReferralCodeOwner :one
+SELECT user_id
+FROM referral_codes
+WHERE code = ?;
+
+-- name: CreateReferral :execrows
+INSERT IGNORE INTO referrals (referrer_id, referred_id)
+VALUES (?, ?);
+
+-- name: ReferralStats :one
+SELECT COUNT(*) AS total, COUNT(CASE WHEN created_at >= sqlc.arg(since) THEN 1 END) AS recent
+FROM referrals
+WHERE referrer_id = sqlc.arg(referrer_id);
+
+-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (user_id, provider, subject, email)
+VALUES (?, ?, ?, ?);
+
+-- name: GetUserIdentityBySubject :one
+SELECT id, user_id, provider, subject, email, created_at
+FROM user_identities
+WHERE provider = ? AND subject = ?;
+
+-- name: ListUserIdentitiesByUser :many
+SELECT id, user_id, provider, subject, email, created_at
+FROM user_identities
+WHERE user_id = ?
+ORDER BY provider;
+
+-- name: DeleteUserIdentity :execrows
+DELETE FROM user_identities
+WHERE user_id = ? AND provider = ?;
+
+-- name: CreateOrganization :execlastid
+INSERT INTO organizations (name)
+VALUES (?);
+
+-- name: GetOrganization :one
+SELECT id, name, created_at
+FROM organizations
+WHERE id = ?;
+
+-- name: ListOrganizations :many
+SELECT id, name, created_at
+FROM organizations
+ORDER BY id;
+
+-- name: SetOrganizationMember :exec
+INSERT INTO organization_members (user_id, org_id)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE org_id = VALUES(org_id), created_at = CURRENT_TIMESTAMP;
+
+-- name: GetOrganizationMembership :one
+SELECT org_id
+FROM organization_members
+WHERE user_id = ?;
+
+-- name: DeleteOrganizationMember :execrows
+DELETE FROM organization_members
+WHERE user_id = ?;
+
+-- name: AggregateOrganizationUsage :exec
+INSERT INTO organization_usage (org_id, day, seats, storage_bytes)
+SELECT o.id, sqlc.arg(day),
+    COALESCE(SUM(u.active AND u.account_type <> 'service'), 0),
+    COUNT(u.id) * (
+        SELECT COALESCE(MAX(AVG_ROW_LENGTH), 0)
+        FROM information_schema.TABLES
+        WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'users'
+    ) + (
+        SELECT COUNT(*)
+        FROM login_history l
+        JOIN organization_members lm ON lm.user_id = l.user_id
+        WHERE lm.org_id = o.id
+    ) * (
+        SELECT COALESCE(MAX(AVG_ROW_LENGTH), 0)
+        FROM information_schema.TABLES
+        WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'login_history'
+    )
+FROM organizations o
+LEFT JOIN organization_members m ON m.org_id = o.id
+LEFT JOIN users u ON u.id = m.user_id AND u.deleted_at IS NULL
+GROUP BY o.id
+ON DUPLICATE KEY UPDATE seats = VALUES(seats), storage_bytes = VALUES(storage_bytes);
+
+-- name: AddOrganizationAPICalls :exec
+INSERT INTO organization_usage (org_id, day, api_calls)
+SELECT org_id, sqlc.arg(day), sqlc.arg(calls)
+FROM organization_members
+WHERE user_id = sqlc.arg(user_id)
+ON DUPLICATE KEY UPDATE api_calls = organization_usage.api_calls + VALUES(api_calls);
+
+-- name: ListOrganizationUsage :many
+SELECT org_id, day, seats, api_calls, storage_bytes
+FROM organization_usage
+WHERE org_id = sqlc.arg(org_id) AND day >= sqlc.arg(from_day) AND day <= sqlc.arg(to_day)
+ORDER BY day;
+
+-- name: CountOrganizationSeats :one
+SELECT COUNT(*)
+FROM organization_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.org_id = sqlc.arg(org_id) AND m.user_id <> sqlc.arg(except_user_id) AND u.active AND u.account_type <> 'service' AND u.deleted_at IS NULL;
+
+-- name: GetOrganizationBilling :one
+SELECT org_id, provider, customer_id, subscription_id, plan, status, seats, current_period_end, event_at, updated_at
+FROM organization_billing
+WHERE org_id = ?;
+
+-- name: GetOrganizationBillingByCustomer :one
+SELECT org_id, provider, customer_id, subscription_id, plan, status, seats, current_period_end, event_at, updated_at
+FROM organization_billing
+WHERE provider = ? AND customer_id = ?;
+
+-- name: UpsertOrganizationBilling :exec
+-- MySQL applies the assignments in order, so event_at has to come last
+-- for the others to compare against the stored value.
+INSERT INTO organization_billing (org_id, provider, customer_id, subscription_id, plan, status, seats, current_period_end, event_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+    provider = IF(event_at <= VALUES(event_at), VALUES(provider), provider),
+    customer_id = IF(event_at <= VALUES(event_at), VALUES(customer_id), customer_id),
+    subscription_id = IF(event_at <= VALUES(event_at), VALUES(subscription_id), subscription_id),
+    plan = IF(event_at <= VALUES(event_at), VALUES(plan), plan),
+    status = IF(event_at <= VALUES(event_at), VALUES(status), status),
+    seats = IF(event_at <= VALUES(event_at), VALUES(seats), seats),
+    current_period_end = IF(event_at <= VALUES(event_at), VALUES(current_period_end), current_period_end),
+    updated_at = IF(event_at <= VALUES(event_at), CURRENT_TIMESTAMP, updated_at),
+    event_at = IF(event_at <= VALUES(event_at), VALUES(event_at), event_at);
+
+-- name: AddAPIUsage :exec
+INSERT INTO api_usage (user_id, api_key_id, day, requests)
+VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE requests = api_usage.requests + VALUES(requests);
+
+-- name: ListAPIUsageByUser :many
+SELECT a.api_key_id, CAST(COALESCE(k.name, '') AS CHAR) AS key_name, a.day, a.requests
+FROM api_usage a
+LEFT JOIN api_keys k ON k.id = a.api_key_id
+WHERE a.user_id = sqlc.arg(user_id) AND a.day >= sqlc.arg(from_day) AND a.day <= sqlc.arg(to_day)
+ORDER BY a.day, a.api_key_id;
+
+-- name: TopAPIUsage :many
+SELECT u.public_id, u.name, a.api_key_id, CAST(COALESCE(k.name, '') AS CHAR) AS key_name, CAST(SUM(a.requests) AS SIGNED) AS requests
+FROM api_usage a
+JOIN users u ON u.id = a.user_id
+LEFT JOIN api_keys k ON k.id = a.api_key_id
+WHERE a.day >= sqlc.arg(from_day) AND a.day <= sqlc.arg(to_day)
+GROUP BY u.public_id, u.name, a.api_key_id, k.name
+ORDER BY requests DESC, u.public_id, a.api_key_id
+LIMIT sqlc.arg(row_limit);
+
+-- name: AcquireJobLease :exec
+INSERT INTO job_leases (name, holder, expires_at)
+VALUES (sqlc.arg(name), sqlc.arg(holder), TIMESTAMPADD(MICROSECOND, sqlc.arg(ttl_micros), CURRENT_TIMESTAMP(6)))
+ON DUPLICATE KEY UPDATE
+    holder = IF(holder = VALUES(holder) OR expires_at < CURRENT_TIMESTAMP(6), VALUES(holder), holder),
+    expires_at = IF(holder = VALUES(holder), VALUES(expires_at), expires_at);
+
+-- name: GetJobLeaseHolder :one
+SELECT holder FROM job_leases WHERE name = ?;
+
+-- name: ReleaseJobLease :exec
+DELETE FROM job_leases WHERE name = ? AND holder = ?;
+
+-- name: TakeRateLimit :exec
+INSERT INTO rate_limit_counters (client, window_start, hits)
+VALUES (sqlc.arg(client), sqlc.arg(now), 1)
+ON DUPLICATE KEY UPDATE
+    hits = IF(window_start <= sqlc.arg(expired_before), 1, LEAST(hits + 1, sqlc.arg(max_hits) + 1)),
+    window_start = IF(window_start <= sqlc.arg(expired_before), VALUES(window_start), window_start);
+
+-- name: GetRateLimit :one
+SELECT hits, window_start FROM rate_limit_counters WHERE client = ?;
+
+-- name: RateLimitStats :one
+SELECT COUNT(*) AS total, MIN(window_start) AS oldest
+FROM rate_limit_counters;
+
+-- name: DeleteExpiredRateLimits :execrows
+DELETE FROM rate_limit_counters WHERE window_start <= ?;
+
+-- name: AppendSecurityEvent :execlastid
+INSERT INTO security_events (event_type, actor_id, target_id, details, ip_address, created_at, prev_hash, hash)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: GetLastSecurityEventHash :one
+SELECT hash FROM security_events ORDER BY id DESC LIMIT 1;
+
+-- name: ListSecurityEventsAfter :many
+SELECT id, event_type, actor_id, target_id, details, ip_address, created_at, prev_hash, hash
+FROM security_events
+WHERE id > ?
+ORDER BY id
+LIMIT ?;
+
+-- name: ListSecurityEvents :many
+SELECT e.id, e.event_type, actor.public_id AS actor_public_id, target.public_id AS target_public_id, e.details, e.ip_address, e.created_at, e.hash
+FROM security_events e
+LEFT JOIN users actor ON actor.id = e.actor_id
+LEFT JOIN users target ON target.id = e.target_id
+WHERE e.id < ?
+ORDER BY e.id DESC
+LIMIT ?;
+
+-- name: CreateRefreshToken :exec
+INSERT INTO refresh_tokens (user_id, token_hash, family, created_at, expires_at)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: GetRefreshTokenByHash :one
+SELECT id, user_id, family, created_at, expires_at, revoked_at
+FROM refresh_tokens
+WHERE token_hash = ?;
+
+-- name: RevokeRefreshToken :execrows
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = ? AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE family = ? AND revoked_at IS NULL;
+
+-- name: RevokeToken :exec
+INSERT IGNORE INTO revoked_tokens (jti, user_id, expires_at)
+VALUES (?, ?, ?);
+
+-- name: ListRevokedTokens :many
+SELECT jti, expires_at
+FROM revoked_tokens
+WHERE expires_at > ?;
+
+-- name: RevokedTokenStats :one
+SELECT COUNT(*) AS total, MIN(expires_at) AS oldest
+FROM revoked_tokens;
+
+-- name: PruneRevokedTokens :execrows
+DELETE FROM revoked_tokens
+WHERE expires_at < ?;
+
+-- name: RecordLoginFailure :exec
+INSERT INTO login_lockouts (lock_key, failures, window_start)
+VALUES (sqlc.arg(lock_key), 1, sqlc.arg(now))
+ON DUPLICATE KEY UPDATE
+    failures = IF(window_start <= sqlc.arg(expired_before), 1, failures + 1),
+    window_start = IF(window_start <= sqlc.arg(expired_before), VALUES(window_start), window_start);
+
+-- name: GetLoginLockout :one
+SELECT failures, window_start, locked_until FROM login_lockouts WHERE lock_key = ?;
+
+-- name: LockLogin :exec
+UPDATE login_lockouts SET locked_until = ? WHERE lock_key = ?;
+
+-- name: ClearLoginLockout :execrows
+DELETE FROM login_lockouts WHERE lock_key = ?;
+
+-- name: ListLoginLockouts :many
+SELECT lock_key, failures, locked_until
+FROM login_lockouts
+WHERE locked_until > ?
+ORDER BY locked_until DESC;
+
+-- name: LoginLockoutStats :one
+SELECT COUNT(*) AS total, MIN(window_start) AS oldest
+FROM login_lockouts;
+
+-- name: DeleteExpiredLoginLockouts :execrows
+DELETE FROM login_lockouts
+WHERE window_start <= sqlc.arg(expired_before) AND (locked_until IS NULL OR locked_until <= sqlc.arg(now));
+
+-- name: CreateSession :exec
+INSERT INTO sessions (id, user_id, ip_address, user_agent, created_at, expires_at)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: ListActiveSessions :many
+SELECT id, ip_address, user_agent, created_at, expires_at
+FROM sessions
+WHERE user_id = ? AND revoked_at IS NULL AND expires_at > ?
+ORDER BY created_at, id;
+
+-- name: ExtendSession :exec
+UPDATE sessions
+SET expires_at = ?
+WHERE id = ? AND revoked_at IS NULL;
+
+-- name: RevokeSession :execrows
+UPDATE sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = ? AND user_id = ? AND revoked_at IS NULL;
+
+-- name: SessionStats :one
+SELECT COUNT(*) AS total, MIN(expires_at) AS oldest
+FROM sessions;
+
+-- name: PruneSessions :execrows
+DELETE FROM sessions
+WHERE expires_at < ?;
+
+-- name: CreateEmailDeadLetter :execlastid
+INSERT INTO email_dead_letters (recipients, subject, html, error, attempts)
+VALUES (?, ?, ?, ?, ?);
+
+-- name: GetEmailDeadLetter :one
+SELECT id, recipients, subject, html, error, attempts, created_at, last_attempt_at
+FROM email_dead_letters
+WHERE id = ?;
+
+-- name: ListEmailDeadLetters :many
+SELECT id, recipients, subject, html, error, attempts, created_at, last_attempt_at
+FROM email_dead_letters
+ORDER BY created_at DESC, id DESC
+LIMIT ?;
+
+-- name: RecordEmailDeadLetterAttempt :exec
+UPDATE email_dead_letters
+SET error = ?, attempts = attempts + 1, last_attempt_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: DeleteEmailDeadLetter :execrows
+DELETE FROM email_dead_letters
+WHERE id = ?;
+
+-- name: CreateNotificationRule :execlastid
+INSERT INTO notification_rules (event_type, channel, audience, template, webhook_url, enabled)
+VALUES (?, ?, ?, ?, ?, ?);
+
+-- name: GetNotificationRule :one
+SELECT id, event_type, channel, audience, template, webhook_url, enabled, created_at, updated_at
+FROM notification_rules
+WHERE id = ?;
+
+-- name: ListNotificationRules :many
+SELECT id, event_type, channel, audience, template, webhook_url, enabled, created_at, updated_at
+FROM notification_rules
+ORDER BY event_type, id;
+
+-- name: ListNotificationRulesForEvent :many
+SELECT id, event_type, channel, audience, template, webhook_url, enabled, created_at, updated_at
+FROM notification_rules
+WHERE event_type = ? AND enabled = TRUE
+ORDER BY id;
+
+-- name: UpdateNotificationRule :execrows
+UPDATE notification_rules
+SET event_type = ?, channel = ?, audience = ?, template = ?, webhook_url = ?, enabled = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?;
+
+-- name: DeleteNotificationRule :execrows
+DELETE FROM notification_rules
+WHERE id = ?;
+
+-- name: ListNotificationPreferences :many
+SELECT user_id, event_type, channel, enabled
+FROM notification_preferences
+WHERE user_id = ?
+ORDER BY event_type, channel;
+
+-- name: SetNotificationPreference :exec
+INSERT INTO notification_preferences (user_id, event_type, channel, enabled)
+VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE enabled = VALUES(enabled);
+
+-- name: CreateNotification :exec
+INSERT INTO notifications (user_id, event_type, title, details)
+VALUES (?, ?, ?, ?);
+
+-- name: ListNotifications :many
+SELECT id, user_id, event_type, title, details, created_at, read_at
+FROM notifications
+WHERE user_id = ?
+ORDER BY created_at DESC, id DESC
+LIMIT ?;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = CURRENT_TIMESTAMP
+WHERE id = ? AND user_id = ? AND read_at IS NULL;
+
+-- name: NotificationStats :one
+SELECT COUNT(*) AS total, MIN(created_at) AS oldest
+FROM notifications;
+
+-- name: PruneNotifications :execrows
+DELETE FROM notifications
+WHERE created_at < ?;
+
+-- name: LookupUsersByEmail :many
+-- The unique index on live_email serves the prefix match; live_email is
+-- NULL for deleted users and compares case-insensitively.
+SELECT id, name, email, role, active, account_type, public_id
+FROM users
+WHERE live_email LIKE sqlc.arg(pattern)
+ORDER BY live_email, id
+LIMIT ?;
+
+-- name: GetUserProfile :one
+SELECT user_id, bio, phone, avatar_url, locale, timezone, updated_at, avatar_key
+FROM user_profiles
+WHERE user_id = ?;
+
+-- name: UpsertUserProfile :exec
+INSERT INTO user_profiles (user_id, bio, phone, avatar_url, locale, timezone)
+VALUES (?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+    bio = VALUES(bio), phone = VALUES(phone), avatar_url = VALUES(avatar_url),
+    locale = VALUES(locale), timezone = VALUES(timezone), updated_at = CURRENT_TIMESTAMP;
+
+-- name: SetUserProfileAvatarKey :exec
+INSERT INTO user_profiles (user_id, avatar_key)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE avatar_key = VALUES(avatar_key), updated_at = CURRENT_TIMESTAMP;
+
+-- name: RedeemMagicLink :execrows
+INSERT IGNORE INTO magic_link_redemptions (token_hash, expires_at)
+VALUES (?, ?);
+
+-- name: MagicLinkRedemptionStats :one
+SELECT COUNT(*) AS total, MIN(expires_at) AS oldest
+FROM magic_link_redemptions;
+
+-- name: DeleteExpiredMagicLinkRedemptions :execrows
+DELETE FROM magic_link_redemptions WHERE expires_at <= ?;
+
+-- name: CreateSSOPendingLogin :exec
+INSERT INTO sso_pending_logins (state_hash, nonce, code_verifier, expires_at)
+VALUES (?, ?, ?, ?);
+
+-- name: GetSSOPendingLogin :one
+SELECT state_hash, nonce, code_verifier, expires_at FROM sso_pending_logins WHERE state_hash = ?;
+
+-- name: DeleteSSOPendingLogin :execrows
+DELETE FROM sso_pending_logins WHERE state_hash = ?;
+
+-- name: SSOPendingLoginStats :one
+SELECT COUNT(*) AS total, MIN(expires_at) AS oldest
+FROM sso_pending_logins;
+
+-- name: DeleteExpiredSSOPendingLogins :execrows
+DELETE FROM sso_pending_logins WHERE expires_at <= ?;
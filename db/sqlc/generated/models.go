@@ -4,8 +4,264 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type ApiKey struct {
+	ID         int64            `json:"id"`
+	UserID     int64            `json:"user_id"`
+	Name       string           `json:"name"`
+	Prefix     string           `json:"prefix"`
+	KeyHash    string           `json:"key_hash"`
+	Scopes     string           `json:"scopes"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	ExpiresAt  pgtype.Timestamp `json:"expires_at"`
+	LastUsedAt pgtype.Timestamp `json:"last_used_at"`
+	RevokedAt  pgtype.Timestamp `json:"revoked_at"`
+}
+
+type ApiUsage struct {
+	UserID   int64       `json:"user_id"`
+	ApiKeyID int64       `json:"api_key_id"`
+	Day      pgtype.Date `json:"day"`
+	Requests int64       `json:"requests"`
+}
+
+type EmailDeadLetter struct {
+	ID            int64            `json:"id"`
+	Recipients    string           `json:"recipients"`
+	Subject       string           `json:"subject"`
+	Html          string           `json:"html"`
+	Error         string           `json:"error"`
+	Attempts      int32            `json:"attempts"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	LastAttemptAt pgtype.Timestamp `json:"last_attempt_at"`
+}
+
+type JobLease struct {
+	Name      string           `json:"name"`
+	Holder    string           `json:"holder"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+type LoginHistory struct {
+	ID        int64            `json:"id"`
+	UserID    pgtype.Int8      `json:"user_id"`
+	Email     string           `json:"email"`
+	Succeeded bool             `json:"succeeded"`
+	Reason    string           `json:"reason"`
+	IpAddress string           `json:"ip_address"`
+	UserAgent string           `json:"user_agent"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	Country   string           `json:"country"`
+	City      string           `json:"city"`
+}
+
+type LoginLockout struct {
+	LockKey     string           `json:"lock_key"`
+	Failures    int32            `json:"failures"`
+	WindowStart pgtype.Timestamp `json:"window_start"`
+	LockedUntil pgtype.Timestamp `json:"locked_until"`
+}
+
+type MagicLinkRedemption struct {
+	TokenHash string           `json:"token_hash"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+type NameReview struct {
+	ID         int64            `json:"id"`
+	UserID     int64            `json:"user_id"`
+	Name       string           `json:"name"`
+	Matched    string           `json:"matched"`
+	Status     string           `json:"status"`
+	ReviewedBy pgtype.Int8      `json:"reviewed_by"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	ReviewedAt pgtype.Timestamp `json:"reviewed_at"`
+}
+
+type Notification struct {
+	ID        int64            `json:"id"`
+	UserID    int64            `json:"user_id"`
+	EventType string           `json:"event_type"`
+	Title     string           `json:"title"`
+	Details   string           `json:"details"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ReadAt    pgtype.Timestamp `json:"read_at"`
+}
+
+type NotificationPreference struct {
+	UserID    int64  `json:"user_id"`
+	EventType string `json:"event_type"`
+	Channel   string `json:"channel"`
+	Enabled   bool   `json:"enabled"`
+}
+
+type NotificationRule struct {
+	ID         int64            `json:"id"`
+	EventType  string           `json:"event_type"`
+	Channel    string           `json:"channel"`
+	Audience   string           `json:"audience"`
+	Template   string           `json:"template"`
+	WebhookUrl string           `json:"webhook_url"`
+	Enabled    bool             `json:"enabled"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	UpdatedAt  pgtype.Timestamp `json:"updated_at"`
+}
+
+type Organization struct {
+	ID        int64            `json:"id"`
+	Name      string           `json:"name"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type OrganizationBilling struct {
+	OrgID            int64            `json:"org_id"`
+	Provider         string           `json:"provider"`
+	CustomerID       string           `json:"customer_id"`
+	SubscriptionID   string           `json:"subscription_id"`
+	Plan             string           `json:"plan"`
+	Status           string           `json:"status"`
+	Seats            int64            `json:"seats"`
+	CurrentPeriodEnd pgtype.Timestamp `json:"current_period_end"`
+	EventAt          pgtype.Timestamp `json:"event_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+}
+
+type OrganizationMember struct {
+	UserID    int64            `json:"user_id"`
+	OrgID     int64            `json:"org_id"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type OrganizationUsage struct {
+	OrgID        int64       `json:"org_id"`
+	Day          pgtype.Date `json:"day"`
+	Seats        int64       `json:"seats"`
+	ApiCalls     int64       `json:"api_calls"`
+	StorageBytes int64       `json:"storage_bytes"`
+}
+
+type RateLimitCounter struct {
+	Client      string           `json:"client"`
+	WindowStart pgtype.Timestamp `json:"window_start"`
+	Hits        int32            `json:"hits"`
+}
+
+type Referral struct {
+	ID         int64            `json:"id"`
+	ReferrerID int64            `json:"referrer_id"`
+	ReferredID int64            `json:"referred_id"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+type ReferralCode struct {
+	UserID    int64            `json:"user_id"`
+	Code      string           `json:"code"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type RefreshToken struct {
+	ID        int64            `json:"id"`
+	UserID    int64            `json:"user_id"`
+	TokenHash string           `json:"token_hash"`
+	Family    string           `json:"family"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	RevokedAt pgtype.Timestamp `json:"revoked_at"`
+}
+
+type RevokedToken struct {
+	Jti       string           `json:"jti"`
+	UserID    int64            `json:"user_id"`
+	RevokedAt pgtype.Timestamp `json:"revoked_at"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+type SecurityEvent struct {
+	ID        int64            `json:"id"`
+	EventType string           `json:"event_type"`
+	ActorID   pgtype.Int8      `json:"actor_id"`
+	TargetID  pgtype.Int8      `json:"target_id"`
+	Details   string           `json:"details"`
+	IpAddress string           `json:"ip_address"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	PrevHash  string           `json:"prev_hash"`
+	Hash      string           `json:"hash"`
+}
+
+type Session struct {
+	ID        string           `json:"id"`
+	UserID    int64            `json:"user_id"`
+	IpAddress string           `json:"ip_address"`
+	UserAgent string           `json:"user_agent"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	RevokedAt pgtype.Timestamp `json:"revoked_at"`
+}
+
+type SsoPendingLogin struct {
+	StateHash    string           `json:"state_hash"`
+	Nonce        string           `json:"nonce"`
+	CodeVerifier string           `json:"code_verifier"`
+	ExpiresAt    pgtype.Timestamp `json:"expires_at"`
+}
+
+type TokenRevocation struct {
+	UserID    int64            `json:"user_id"`
+	RevokedAt pgtype.Timestamp `json:"revoked_at"`
+}
+
 type User struct {
-	ID   int32       `json:"id"`
-	Name string      `json:"name"`
-	Dob  pgtype.Date `json:"dob"`
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Dob          pgtype.Date      `json:"dob"`
+	Email        string           `json:"email"`
+	PasswordHash string           `json:"password_hash"`
+	Role         string           `json:"role"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	Active       bool             `json:"active"`
+	AccountType  string           `json:"account_type"`
+	SignupSource string           `json:"signup_source"`
+	PublicID     pgtype.UUID      `json:"public_id"`
+	DeletedAt    pgtype.Timestamp `json:"deleted_at"`
+}
+
+type UserIdentity struct {
+	ID        int64            `json:"id"`
+	UserID    int64            `json:"user_id"`
+	Provider  string           `json:"provider"`
+	Subject   string           `json:"subject"`
+	Email     string           `json:"email"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type UserProfile struct {
+	UserID    int64            `json:"user_id"`
+	Bio       string           `json:"bio"`
+	Phone     string           `json:"phone"`
+	AvatarUrl string           `json:"avatar_url"`
+	Locale    string           `json:"locale"`
+	Timezone  string           `json:"timezone"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	AvatarKey string           `json:"avatar_key"`
+}
+
+type UserStat struct {
+	ID                int32            `json:"id"`
+	TotalUsers        int64            `json:"total_users"`
+	AdminUsers        int64            `json:"admin_users"`
+	SignupsLast7Days  int64            `json:"signups_last_7_days"`
+	SignupsLast30Days int64            `json:"signups_last_30_days"`
+	AverageAge        float64          `json:"average_age"`
+	RefreshedAt       pgtype.Timestamp `json:"refreshed_at"`
+}
+
+type WebauthnCredential struct {
+	ID           int64            `json:"id"`
+	UserID       int64            `json:"user_id"`
+	CredentialID []byte           `json:"credential_id"`
+	PublicKey    []byte           `json:"public_key"`
+	SignCount    int64            `json:"sign_count"`
+	Name         string           `json:"name"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	LastUsedAt   pgtype.Timestamp `json:"last_used_at"`
 }
@@ -6,75 +6,3298 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const acquireJobLease = `-- name: AcquireJobLease :execrows
+INSERT INTO job_leases (name, holder, expires_at)
+VALUES ($1, $2, CURRENT_TIMESTAMP + $3::bigint * INTERVAL '1 millisecond')
+ON CONFLICT (name) DO UPDATE SET holder = EXCLUDED.holder, expires_at = EXCLUDED.expires_at
+WHERE job_leases.holder = EXCLUDED.holder OR job_leases.expires_at < CURRENT_TIMESTAMP
+`
+
+type AcquireJobLeaseParams struct {
+	Name   string `json:"name"`
+	Holder string `json:"holder"`
+	TtlMs  int64  `json:"ttl_ms"`
+}
+
+func (q *Queries) AcquireJobLease(ctx context.Context, arg AcquireJobLeaseParams) (int64, error) {
+	result, err := q.db.Exec(ctx, acquireJobLease, arg.Name, arg.Holder, arg.TtlMs)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const addAPIUsage = `-- name: AddAPIUsage :exec
+INSERT INTO api_usage (user_id, api_key_id, day, requests)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, api_key_id, day) DO UPDATE SET requests = api_usage.requests + EXCLUDED.requests
+`
+
+type AddAPIUsageParams struct {
+	UserID   int64       `json:"user_id"`
+	ApiKeyID int64       `json:"api_key_id"`
+	Day      pgtype.Date `json:"day"`
+	Requests int64       `json:"requests"`
+}
+
+func (q *Queries) AddAPIUsage(ctx context.Context, arg AddAPIUsageParams) error {
+	_, err := q.db.Exec(ctx, addAPIUsage,
+		arg.UserID,
+		arg.ApiKeyID,
+		arg.Day,
+		arg.Requests,
+	)
+	return err
+}
+
+const addOrganizationAPICalls = `-- name: AddOrganizationAPICalls :exec
+INSERT INTO organization_usage (org_id, day, api_calls)
+SELECT org_id, $1::date, $2::bigint
+FROM organization_members
+WHERE user_id = $3
+ON CONFLICT (org_id, day) DO UPDATE SET api_calls = organization_usage.api_calls + EXCLUDED.api_calls
+`
+
+type AddOrganizationAPICallsParams struct {
+	Day    pgtype.Date `json:"day"`
+	Calls  int64       `json:"calls"`
+	UserID int64       `json:"user_id"`
+}
+
+func (q *Queries) AddOrganizationAPICalls(ctx context.Context, arg AddOrganizationAPICallsParams) error {
+	_, err := q.db.Exec(ctx, addOrganizationAPICalls, arg.Day, arg.Calls, arg.UserID)
+	return err
+}
+
+const ageDistribution = `-- name: AgeDistribution :many
+SELECT grp::text AS grp, (age / $1::int * $1::int)::int AS bucket_start, COUNT(*) AS user_count
+FROM (
+    SELECT CASE $2::text
+        WHEN 'role' THEN role
+        WHEN 'signup_cohort' THEN to_char(date_trunc('month', created_at), 'YYYY-MM')
+        ELSE ''
+    END AS grp,
+    date_part('year', age(CURRENT_DATE, dob))::int AS age
+    FROM users
+    WHERE deleted_at IS NULL
+) ages
+GROUP BY grp, bucket_start
+ORDER BY grp, bucket_start
+`
+
+type AgeDistributionParams struct {
+	BucketSize int32  `json:"bucket_size"`
+	GroupBy    string `json:"group_by"`
+}
+
+type AgeDistributionRow struct {
+	Grp         string `json:"grp"`
+	BucketStart int32  `json:"bucket_start"`
+	UserCount   int64  `json:"user_count"`
+}
+
+func (q *Queries) AgeDistribution(ctx context.Context, arg AgeDistributionParams) ([]AgeDistributionRow, error) {
+	rows, err := q.db.Query(ctx, ageDistribution, arg.BucketSize, arg.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AgeDistributionRow
+	for rows.Next() {
+		var i AgeDistributionRow
+		if err := rows.Scan(&i.Grp, &i.BucketStart, &i.UserCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const aggregateOrganizationUsage = `-- name: AggregateOrganizationUsage :exec
+INSERT INTO organization_usage (org_id, day, seats, storage_bytes)
+SELECT o.id, $1::date,
+    COUNT(u.id) FILTER (WHERE u.active AND u.account_type <> 'service'),
+    COALESCE(SUM(pg_column_size(u.*)), 0) + COALESCE((
+        SELECT SUM(pg_column_size(l.*))
+        FROM login_history l
+        JOIN organization_members lm ON lm.user_id = l.user_id
+        WHERE lm.org_id = o.id
+    ), 0)
+FROM organizations o
+LEFT JOIN organization_members m ON m.org_id = o.id
+LEFT JOIN users u ON u.id = m.user_id AND u.deleted_at IS NULL
+GROUP BY o.id
+ON CONFLICT (org_id, day) DO UPDATE SET seats = EXCLUDED.seats, storage_bytes = EXCLUDED.storage_bytes
+`
+
+func (q *Queries) AggregateOrganizationUsage(ctx context.Context, day pgtype.Date) error {
+	_, err := q.db.Exec(ctx, aggregateOrganizationUsage, day)
+	return err
+}
+
+const appendSecurityEvent = `-- name: AppendSecurityEvent :one
+INSERT INTO security_events (event_type, actor_id, target_id, details, ip_address, created_at, prev_hash, hash)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id
+`
+
+type AppendSecurityEventParams struct {
+	EventType string           `json:"event_type"`
+	ActorID   pgtype.Int8      `json:"actor_id"`
+	TargetID  pgtype.Int8      `json:"target_id"`
+	Details   string           `json:"details"`
+	IpAddress string           `json:"ip_address"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	PrevHash  string           `json:"prev_hash"`
+	Hash      string           `json:"hash"`
+}
+
+func (q *Queries) AppendSecurityEvent(ctx context.Context, arg AppendSecurityEventParams) (int64, error) {
+	row := q.db.QueryRow(ctx, appendSecurityEvent,
+		arg.EventType,
+		arg.ActorID,
+		arg.TargetID,
+		arg.Details,
+		arg.IpAddress,
+		arg.CreatedAt,
+		arg.PrevHash,
+		arg.Hash,
+	)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const clearLoginLockout = `-- name: ClearLoginLockout :execrows
+DELETE FROM login_lockouts WHERE lock_key = $1
+`
+
+func (q *Queries) ClearLoginLockout(ctx context.Context, lockKey string) (int64, error) {
+	result, err := q.db.Exec(ctx, clearLoginLockout, lockKey)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const countOrganizationSeats = `-- name: CountOrganizationSeats :one
+SELECT COUNT(*)
+FROM organization_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.org_id = $1 AND m.user_id <> $2 AND u.active AND u.account_type <> 'service' AND u.deleted_at IS NULL
+`
+
+type CountOrganizationSeatsParams struct {
+	OrgID        int64 `json:"org_id"`
+	ExceptUserID int64 `json:"except_user_id"`
+}
+
+func (q *Queries) CountOrganizationSeats(ctx context.Context, arg CountOrganizationSeatsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countOrganizationSeats, arg.OrgID, arg.ExceptUserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchUsers = `-- name: CountSearchUsers :one
+SELECT COUNT(*)
+FROM users
+WHERE deleted_at IS NULL
+    AND ($1::text IS NULL OR name ILIKE $1)
+    AND ($2::text IS NULL OR LOWER(email) = LOWER($2))
+    AND ($3::text IS NULL OR role = $3)
+    AND ($4::text IS NULL OR signup_source = $4)
+    AND ($5::date IS NULL OR dob > $5)
+    AND ($6::date IS NULL OR dob <= $6)
+    AND ($7::timestamp IS NULL OR created_at >= $7)
+    AND ($8::timestamp IS NULL OR created_at < $8)
+    AND ($9::bigint IS NULL OR id IN (SELECT user_id FROM organization_members WHERE org_id = $9))
+`
+
+type CountSearchUsersParams struct {
+	NamePattern   pgtype.Text      `json:"name_pattern"`
+	Email         pgtype.Text      `json:"email"`
+	Role          pgtype.Text      `json:"role"`
+	SignupSource  pgtype.Text      `json:"signup_source"`
+	DobAfter      pgtype.Date      `json:"dob_after"`
+	DobUntil      pgtype.Date      `json:"dob_until"`
+	CreatedFrom   pgtype.Timestamp `json:"created_from"`
+	CreatedBefore pgtype.Timestamp `json:"created_before"`
+	OrgID         pgtype.Int8      `json:"org_id"`
+}
+
+func (q *Queries) CountSearchUsers(ctx context.Context, arg CountSearchUsersParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSearchUsers,
+		arg.NamePattern,
+		arg.Email,
+		arg.Role,
+		arg.SignupSource,
+		arg.DobAfter,
+		arg.DobUntil,
+		arg.CreatedFrom,
+		arg.CreatedBefore,
+		arg.OrgID,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const countUsers = `-- name: CountUsers :one
-SELECT COUNT(*) 
+SELECT COUNT(*)
+FROM users
+WHERE deleted_at IS NULL
+`
+
+func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRow(ctx, countUsers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAPIKey = `-- name: CreateAPIKey :one
+INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, name, prefix, scopes, created_at, expires_at, last_used_at, revoked_at
+`
+
+type CreateAPIKeyParams struct {
+	UserID    int64            `json:"user_id"`
+	Name      string           `json:"name"`
+	Prefix    string           `json:"prefix"`
+	KeyHash   string           `json:"key_hash"`
+	Scopes    string           `json:"scopes"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+type CreateAPIKeyRow struct {
+	ID         int64            `json:"id"`
+	UserID     int64            `json:"user_id"`
+	Name       string           `json:"name"`
+	Prefix     string           `json:"prefix"`
+	Scopes     string           `json:"scopes"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	ExpiresAt  pgtype.Timestamp `json:"expires_at"`
+	LastUsedAt pgtype.Timestamp `json:"last_used_at"`
+	RevokedAt  pgtype.Timestamp `json:"revoked_at"`
+}
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (CreateAPIKeyRow, error) {
+	row := q.db.QueryRow(ctx, createAPIKey,
+		arg.UserID,
+		arg.Name,
+		arg.Prefix,
+		arg.KeyHash,
+		arg.Scopes,
+		arg.ExpiresAt,
+	)
+	var i CreateAPIKeyRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Prefix,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const createEmailDeadLetter = `-- name: CreateEmailDeadLetter :one
+INSERT INTO email_dead_letters (recipients, subject, html, error, attempts)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, recipients, subject, html, error, attempts, created_at, last_attempt_at
+`
+
+type CreateEmailDeadLetterParams struct {
+	Recipients string `json:"recipients"`
+	Subject    string `json:"subject"`
+	Html       string `json:"html"`
+	Error      string `json:"error"`
+	Attempts   int32  `json:"attempts"`
+}
+
+func (q *Queries) CreateEmailDeadLetter(ctx context.Context, arg CreateEmailDeadLetterParams) (EmailDeadLetter, error) {
+	row := q.db.QueryRow(ctx, createEmailDeadLetter,
+		arg.Recipients,
+		arg.Subject,
+		arg.Html,
+		arg.Error,
+		arg.Attempts,
+	)
+	var i EmailDeadLetter
+	err := row.Scan(
+		&i.ID,
+		&i.Recipients,
+		&i.Subject,
+		&i.Html,
+		&i.Error,
+		&i.Attempts,
+		&i.CreatedAt,
+		&i.LastAttemptAt,
+	)
+	return i, err
+}
+
+const createNameReview = `-- name: CreateNameReview :one
+INSERT INTO name_reviews (user_id, name, matched)
+VALUES ($1, $2, $3)
+RETURNING id, user_id, name, matched, status, reviewed_by, created_at, reviewed_at
+`
+
+type CreateNameReviewParams struct {
+	UserID  int64  `json:"user_id"`
+	Name    string `json:"name"`
+	Matched string `json:"matched"`
+}
+
+func (q *Queries) CreateNameReview(ctx context.Context, arg CreateNameReviewParams) (NameReview, error) {
+	row := q.db.QueryRow(ctx, createNameReview, arg.UserID, arg.Name, arg.Matched)
+	var i NameReview
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Matched,
+		&i.Status,
+		&i.ReviewedBy,
+		&i.CreatedAt,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
+const createNotification = `-- name: CreateNotification :exec
+INSERT INTO notifications (user_id, event_type, title, details)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateNotificationParams struct {
+	UserID    int64  `json:"user_id"`
+	EventType string `json:"event_type"`
+	Title     string `json:"title"`
+	Details   string `json:"details"`
+}
+
+func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotificationParams) error {
+	_, err := q.db.Exec(ctx, createNotification,
+		arg.UserID,
+		arg.EventType,
+		arg.Title,
+		arg.Details,
+	)
+	return err
+}
+
+const createNotificationRule = `-- name: CreateNotificationRule :one
+INSERT INTO notification_rules (event_type, channel, audience, template, webhook_url, enabled)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, event_type, channel, audience, template, webhook_url, enabled, created_at, updated_at
+`
+
+type CreateNotificationRuleParams struct {
+	EventType  string `json:"event_type"`
+	Channel    string `json:"channel"`
+	Audience   string `json:"audience"`
+	Template   string `json:"template"`
+	WebhookUrl string `json:"webhook_url"`
+	Enabled    bool   `json:"enabled"`
+}
+
+func (q *Queries) CreateNotificationRule(ctx context.Context, arg CreateNotificationRuleParams) (NotificationRule, error) {
+	row := q.db.QueryRow(ctx, createNotificationRule,
+		arg.EventType,
+		arg.Channel,
+		arg.Audience,
+		arg.Template,
+		arg.WebhookUrl,
+		arg.Enabled,
+	)
+	var i NotificationRule
+	err := row.Scan(
+		&i.ID,
+		&i.EventType,
+		&i.Channel,
+		&i.Audience,
+		&i.Template,
+		&i.WebhookUrl,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createOrganization = `-- name: CreateOrganization :one
+INSERT INTO organizations (name)
+VALUES ($1)
+RETURNING id, name, created_at
+`
+
+func (q *Queries) CreateOrganization(ctx context.Context, name string) (Organization, error) {
+	row := q.db.QueryRow(ctx, createOrganization, name)
+	var i Organization
+	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	return i, err
+}
+
+const createReferral = `-- name: CreateReferral :execrows
+INSERT INTO referrals (referrer_id, referred_id)
+VALUES ($1, $2)
+ON CONFLICT (referred_id) DO NOTHING
+`
+
+type CreateReferralParams struct {
+	ReferrerID int64 `json:"referrer_id"`
+	ReferredID int64 `json:"referred_id"`
+}
+
+func (q *Queries) CreateReferral(ctx context.Context, arg CreateReferralParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createReferral, arg.ReferrerID, arg.ReferredID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createReferralCode = `-- name: CreateReferralCode :execrows
+INSERT INTO referral_codes (user_id, code)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING
+`
+
+type CreateReferralCodeParams struct {
+	UserID int64  `json:"user_id"`
+	Code   string `json:"code"`
+}
+
+func (q *Queries) CreateReferralCode(ctx context.Context, arg CreateReferralCodeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, createReferralCode, arg.UserID, arg.Code)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const createRefreshToken = `-- name: CreateRefreshToken :exec
+INSERT INTO refresh_tokens (user_id, token_hash, family, created_at, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateRefreshTokenParams struct {
+	UserID    int64            `json:"user_id"`
+	TokenHash string           `json:"token_hash"`
+	Family    string           `json:"family"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) error {
+	_, err := q.db.Exec(ctx, createRefreshToken,
+		arg.UserID,
+		arg.TokenHash,
+		arg.Family,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const createSSOPendingLogin = `-- name: CreateSSOPendingLogin :exec
+INSERT INTO sso_pending_logins (state_hash, nonce, code_verifier, expires_at)
+VALUES ($1, $2, $3, $4)
+`
+
+type CreateSSOPendingLoginParams struct {
+	StateHash    string           `json:"state_hash"`
+	Nonce        string           `json:"nonce"`
+	CodeVerifier string           `json:"code_verifier"`
+	ExpiresAt    pgtype.Timestamp `json:"expires_at"`
+}
+
+func (q *Queries) CreateSSOPendingLogin(ctx context.Context, arg CreateSSOPendingLoginParams) error {
+	_, err := q.db.Exec(ctx, createSSOPendingLogin,
+		arg.StateHash,
+		arg.Nonce,
+		arg.CodeVerifier,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const createServiceAccount = `-- name: CreateServiceAccount :one
+INSERT INTO users (name, dob, email, password_hash, role, account_type, signup_source)
+VALUES ($1, CURRENT_DATE, $2, $3, $4, 'service', 'admin')
+RETURNING id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+`
+
+type CreateServiceAccountParams struct {
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	PasswordHash string `json:"password_hash"`
+	Role         string `json:"role"`
+}
+
+type CreateServiceAccountRow struct {
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Dob          pgtype.Date      `json:"dob"`
+	Email        string           `json:"email"`
+	Role         string           `json:"role"`
+	Active       bool             `json:"active"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	AccountType  string           `json:"account_type"`
+	SignupSource string           `json:"signup_source"`
+	PublicID     pgtype.UUID      `json:"public_id"`
+}
+
+func (q *Queries) CreateServiceAccount(ctx context.Context, arg CreateServiceAccountParams) (CreateServiceAccountRow, error) {
+	row := q.db.QueryRow(ctx, createServiceAccount,
+		arg.Name,
+		arg.Email,
+		arg.PasswordHash,
+		arg.Role,
+	)
+	var i CreateServiceAccountRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.Email,
+		&i.Role,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AccountType,
+		&i.SignupSource,
+		&i.PublicID,
+	)
+	return i, err
+}
+
+const createSession = `-- name: CreateSession :exec
+INSERT INTO sessions (id, user_id, ip_address, user_agent, created_at, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type CreateSessionParams struct {
+	ID        string           `json:"id"`
+	UserID    int64            `json:"user_id"`
+	IpAddress string           `json:"ip_address"`
+	UserAgent string           `json:"user_agent"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) error {
+	_, err := q.db.Exec(ctx, createSession,
+		arg.ID,
+		arg.UserID,
+		arg.IpAddress,
+		arg.UserAgent,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const createUser = `-- name: CreateUser :one
+INSERT INTO users (name, dob, email, password_hash, role, signup_source)
+VALUES ($1, $2, $3, $4, COALESCE($5, 'user'), $6)
+RETURNING id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+`
+
+type CreateUserParams struct {
+	Name         string      `json:"name"`
+	Dob          pgtype.Date `json:"dob"`
+	Email        string      `json:"email"`
+	PasswordHash string      `json:"password_hash"`
+	Column5      interface{} `json:"column_5"`
+	SignupSource string      `json:"signup_source"`
+}
+
+type CreateUserRow struct {
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Dob          pgtype.Date      `json:"dob"`
+	Email        string           `json:"email"`
+	Role         string           `json:"role"`
+	Active       bool             `json:"active"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	AccountType  string           `json:"account_type"`
+	SignupSource string           `json:"signup_source"`
+	PublicID     pgtype.UUID      `json:"public_id"`
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (CreateUserRow, error) {
+	row := q.db.QueryRow(ctx, createUser,
+		arg.Name,
+		arg.Dob,
+		arg.Email,
+		arg.PasswordHash,
+		arg.Column5,
+		arg.SignupSource,
+	)
+	var i CreateUserRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.Email,
+		&i.Role,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AccountType,
+		&i.SignupSource,
+		&i.PublicID,
+	)
+	return i, err
+}
+
+const createUserIdentity = `-- name: CreateUserIdentity :one
+INSERT INTO user_identities (user_id, provider, subject, email)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, provider, subject, email, created_at
+`
+
+type CreateUserIdentityParams struct {
+	UserID   int64  `json:"user_id"`
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+	Email    string `json:"email"`
+}
+
+func (q *Queries) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error) {
+	row := q.db.QueryRow(ctx, createUserIdentity,
+		arg.UserID,
+		arg.Provider,
+		arg.Subject,
+		arg.Email,
+	)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Provider,
+		&i.Subject,
+		&i.Email,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createWebAuthnCredential = `-- name: CreateWebAuthnCredential :one
+INSERT INTO webauthn_credentials (user_id, credential_id, public_key, sign_count, name)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, credential_id, public_key, sign_count, name, created_at, last_used_at
+`
+
+type CreateWebAuthnCredentialParams struct {
+	UserID       int64  `json:"user_id"`
+	CredentialID []byte `json:"credential_id"`
+	PublicKey    []byte `json:"public_key"`
+	SignCount    int64  `json:"sign_count"`
+	Name         string `json:"name"`
+}
+
+func (q *Queries) CreateWebAuthnCredential(ctx context.Context, arg CreateWebAuthnCredentialParams) (WebauthnCredential, error) {
+	row := q.db.QueryRow(ctx, createWebAuthnCredential,
+		arg.UserID,
+		arg.CredentialID,
+		arg.PublicKey,
+		arg.SignCount,
+		arg.Name,
+	)
+	var i WebauthnCredential
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CredentialID,
+		&i.PublicKey,
+		&i.SignCount,
+		&i.Name,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const deleteEmailDeadLetter = `-- name: DeleteEmailDeadLetter :execrows
+DELETE FROM email_dead_letters
+WHERE id = $1
+`
+
+func (q *Queries) DeleteEmailDeadLetter(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteEmailDeadLetter, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteExpiredLoginLockouts = `-- name: DeleteExpiredLoginLockouts :execrows
+DELETE FROM login_lockouts
+WHERE window_start <= $1::timestamp AND (locked_until IS NULL OR locked_until <= $2::timestamp)
+`
+
+type DeleteExpiredLoginLockoutsParams struct {
+	ExpiredBefore pgtype.Timestamp `json:"expired_before"`
+	Now           pgtype.Timestamp `json:"now"`
+}
+
+func (q *Queries) DeleteExpiredLoginLockouts(ctx context.Context, arg DeleteExpiredLoginLockoutsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredLoginLockouts, arg.ExpiredBefore, arg.Now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteExpiredMagicLinkRedemptions = `-- name: DeleteExpiredMagicLinkRedemptions :execrows
+DELETE FROM magic_link_redemptions WHERE expires_at <= $1
+`
+
+func (q *Queries) DeleteExpiredMagicLinkRedemptions(ctx context.Context, expiresAt pgtype.Timestamp) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredMagicLinkRedemptions, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteExpiredRateLimits = `-- name: DeleteExpiredRateLimits :execrows
+DELETE FROM rate_limit_counters WHERE window_start <= $1
+`
+
+func (q *Queries) DeleteExpiredRateLimits(ctx context.Context, windowStart pgtype.Timestamp) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredRateLimits, windowStart)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteExpiredSSOPendingLogins = `-- name: DeleteExpiredSSOPendingLogins :execrows
+DELETE FROM sso_pending_logins WHERE expires_at <= $1
+`
+
+func (q *Queries) DeleteExpiredSSOPendingLogins(ctx context.Context, expiresAt pgtype.Timestamp) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteExpiredSSOPendingLogins, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteNotificationRule = `-- name: DeleteNotificationRule :execrows
+DELETE FROM notification_rules
+WHERE id = $1
+`
+
+func (q *Queries) DeleteNotificationRule(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteNotificationRule, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteOrganizationMember = `-- name: DeleteOrganizationMember :execrows
+DELETE FROM organization_members
+WHERE user_id = $1
+`
+
+func (q *Queries) DeleteOrganizationMember(ctx context.Context, userID int64) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteOrganizationMember, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = $1
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, deleteUser, id)
+	return err
+}
+
+const deleteUserIdentity = `-- name: DeleteUserIdentity :execrows
+DELETE FROM user_identities
+WHERE user_id = $1 AND provider = $2
+`
+
+type DeleteUserIdentityParams struct {
+	UserID   int64  `json:"user_id"`
+	Provider string `json:"provider"`
+}
+
+func (q *Queries) DeleteUserIdentity(ctx context.Context, arg DeleteUserIdentityParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUserIdentity, arg.UserID, arg.Provider)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteWebAuthnCredential = `-- name: DeleteWebAuthnCredential :execrows
+DELETE FROM webauthn_credentials
+WHERE id = $1 AND user_id = $2
+`
+
+type DeleteWebAuthnCredentialParams struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) DeleteWebAuthnCredential(ctx context.Context, arg DeleteWebAuthnCredentialParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteWebAuthnCredential, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const extendSession = `-- name: ExtendSession :exec
+UPDATE sessions
+SET expires_at = $2
+WHERE id = $1 AND revoked_at IS NULL
+`
+
+type ExtendSessionParams struct {
+	ID        string           `json:"id"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+func (q *Queries) ExtendSession(ctx context.Context, arg ExtendSessionParams) error {
+	_, err := q.db.Exec(ctx, extendSession, arg.ID, arg.ExpiresAt)
+	return err
+}
+
+const failedLoginsByDay = `-- name: FailedLoginsByDay :many
+SELECT to_char(date_trunc('day', created_at), 'YYYY-MM-DD')::text AS day, COUNT(*) AS failures
+FROM login_history
+WHERE succeeded = FALSE AND created_at >= $1::timestamp
+GROUP BY day
+ORDER BY day
+`
+
+type FailedLoginsByDayRow struct {
+	Day      string `json:"day"`
+	Failures int64  `json:"failures"`
+}
+
+func (q *Queries) FailedLoginsByDay(ctx context.Context, since pgtype.Timestamp) ([]FailedLoginsByDayRow, error) {
+	rows, err := q.db.Query(ctx, failedLoginsByDay, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FailedLoginsByDayRow
+	for rows.Next() {
+		var i FailedLoginsByDayRow
+		if err := rows.Scan(&i.Day, &i.Failures); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAPIKeyByHash = `-- name: GetAPIKeyByHash :one
+SELECT k.id, k.user_id, k.scopes, k.expires_at, k.revoked_at, u.role, u.active, u.account_type
+FROM api_keys k
+JOIN users u ON u.id = k.user_id
+WHERE k.key_hash = $1 AND u.deleted_at IS NULL
+`
+
+type GetAPIKeyByHashRow struct {
+	ID          int64            `json:"id"`
+	UserID      int64            `json:"user_id"`
+	Scopes      string           `json:"scopes"`
+	ExpiresAt   pgtype.Timestamp `json:"expires_at"`
+	RevokedAt   pgtype.Timestamp `json:"revoked_at"`
+	Role        string           `json:"role"`
+	Active      bool             `json:"active"`
+	AccountType string           `json:"account_type"`
+}
+
+func (q *Queries) GetAPIKeyByHash(ctx context.Context, keyHash string) (GetAPIKeyByHashRow, error) {
+	row := q.db.QueryRow(ctx, getAPIKeyByHash, keyHash)
+	var i GetAPIKeyByHashRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Scopes,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.Role,
+		&i.Active,
+		&i.AccountType,
+	)
+	return i, err
+}
+
+const getDeletedUserByID = `-- name: GetDeletedUserByID :one
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE id = $1 AND deleted_at IS NOT NULL
+`
+
+type GetDeletedUserByIDRow struct {
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Dob          pgtype.Date      `json:"dob"`
+	Email        string           `json:"email"`
+	Role         string           `json:"role"`
+	Active       bool             `json:"active"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	AccountType  string           `json:"account_type"`
+	SignupSource string           `json:"signup_source"`
+	PublicID     pgtype.UUID      `json:"public_id"`
+}
+
+func (q *Queries) GetDeletedUserByID(ctx context.Context, id int64) (GetDeletedUserByIDRow, error) {
+	row := q.db.QueryRow(ctx, getDeletedUserByID, id)
+	var i GetDeletedUserByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.Email,
+		&i.Role,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AccountType,
+		&i.SignupSource,
+		&i.PublicID,
+	)
+	return i, err
+}
+
+const getEmailDeadLetter = `-- name: GetEmailDeadLetter :one
+SELECT id, recipients, subject, html, error, attempts, created_at, last_attempt_at
+FROM email_dead_letters
+WHERE id = $1
+`
+
+func (q *Queries) GetEmailDeadLetter(ctx context.Context, id int64) (EmailDeadLetter, error) {
+	row := q.db.QueryRow(ctx, getEmailDeadLetter, id)
+	var i EmailDeadLetter
+	err := row.Scan(
+		&i.ID,
+		&i.Recipients,
+		&i.Subject,
+		&i.Html,
+		&i.Error,
+		&i.Attempts,
+		&i.CreatedAt,
+		&i.LastAttemptAt,
+	)
+	return i, err
+}
+
+const getLastSecurityEventHash = `-- name: GetLastSecurityEventHash :one
+SELECT hash FROM security_events ORDER BY id DESC LIMIT 1
+`
+
+func (q *Queries) GetLastSecurityEventHash(ctx context.Context) (string, error) {
+	row := q.db.QueryRow(ctx, getLastSecurityEventHash)
+	var hash string
+	err := row.Scan(&hash)
+	return hash, err
+}
+
+const getLoginLockout = `-- name: GetLoginLockout :one
+SELECT failures, window_start, locked_until FROM login_lockouts WHERE lock_key = $1
+`
+
+type GetLoginLockoutRow struct {
+	Failures    int32            `json:"failures"`
+	WindowStart pgtype.Timestamp `json:"window_start"`
+	LockedUntil pgtype.Timestamp `json:"locked_until"`
+}
+
+func (q *Queries) GetLoginLockout(ctx context.Context, lockKey string) (GetLoginLockoutRow, error) {
+	row := q.db.QueryRow(ctx, getLoginLockout, lockKey)
+	var i GetLoginLockoutRow
+	err := row.Scan(&i.Failures, &i.WindowStart, &i.LockedUntil)
+	return i, err
+}
+
+const getNameReview = `-- name: GetNameReview :one
+SELECT id, user_id, name, matched, status, reviewed_by, created_at, reviewed_at
+FROM name_reviews
+WHERE id = $1
+`
+
+func (q *Queries) GetNameReview(ctx context.Context, id int64) (NameReview, error) {
+	row := q.db.QueryRow(ctx, getNameReview, id)
+	var i NameReview
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Matched,
+		&i.Status,
+		&i.ReviewedBy,
+		&i.CreatedAt,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
+const getNotificationRule = `-- name: GetNotificationRule :one
+SELECT id, event_type, channel, audience, template, webhook_url, enabled, created_at, updated_at
+FROM notification_rules
+WHERE id = $1
+`
+
+func (q *Queries) GetNotificationRule(ctx context.Context, id int64) (NotificationRule, error) {
+	row := q.db.QueryRow(ctx, getNotificationRule, id)
+	var i NotificationRule
+	err := row.Scan(
+		&i.ID,
+		&i.EventType,
+		&i.Channel,
+		&i.Audience,
+		&i.Template,
+		&i.WebhookUrl,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getOrganization = `-- name: GetOrganization :one
+SELECT id, name, created_at
+FROM organizations
+WHERE id = $1
+`
+
+func (q *Queries) GetOrganization(ctx context.Context, id int64) (Organization, error) {
+	row := q.db.QueryRow(ctx, getOrganization, id)
+	var i Organization
+	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	return i, err
+}
+
+const getOrganizationBilling = `-- name: GetOrganizationBilling :one
+SELECT org_id, provider, customer_id, subscription_id, plan, status, seats, current_period_end, event_at, updated_at
+FROM organization_billing
+WHERE org_id = $1
+`
+
+func (q *Queries) GetOrganizationBilling(ctx context.Context, orgID int64) (OrganizationBilling, error) {
+	row := q.db.QueryRow(ctx, getOrganizationBilling, orgID)
+	var i OrganizationBilling
+	err := row.Scan(
+		&i.OrgID,
+		&i.Provider,
+		&i.CustomerID,
+		&i.SubscriptionID,
+		&i.Plan,
+		&i.Status,
+		&i.Seats,
+		&i.CurrentPeriodEnd,
+		&i.EventAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getOrganizationBillingByCustomer = `-- name: GetOrganizationBillingByCustomer :one
+SELECT org_id, provider, customer_id, subscription_id, plan, status, seats, current_period_end, event_at, updated_at
+FROM organization_billing
+WHERE provider = $1 AND customer_id = $2
+`
+
+type GetOrganizationBillingByCustomerParams struct {
+	Provider   string `json:"provider"`
+	CustomerID string `json:"customer_id"`
+}
+
+func (q *Queries) GetOrganizationBillingByCustomer(ctx context.Context, arg GetOrganizationBillingByCustomerParams) (OrganizationBilling, error) {
+	row := q.db.QueryRow(ctx, getOrganizationBillingByCustomer, arg.Provider, arg.CustomerID)
+	var i OrganizationBilling
+	err := row.Scan(
+		&i.OrgID,
+		&i.Provider,
+		&i.CustomerID,
+		&i.SubscriptionID,
+		&i.Plan,
+		&i.Status,
+		&i.Seats,
+		&i.CurrentPeriodEnd,
+		&i.EventAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getOrganizationMembership = `-- name: GetOrganizationMembership :one
+SELECT org_id
+FROM organization_members
+WHERE user_id = $1
+`
+
+func (q *Queries) GetOrganizationMembership(ctx context.Context, userID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, getOrganizationMembership, userID)
+	var org_id int64
+	err := row.Scan(&org_id)
+	return org_id, err
+}
+
+const getRateLimit = `-- name: GetRateLimit :one
+SELECT hits, window_start FROM rate_limit_counters WHERE client = $1
+`
+
+type GetRateLimitRow struct {
+	Hits        int32            `json:"hits"`
+	WindowStart pgtype.Timestamp `json:"window_start"`
+}
+
+func (q *Queries) GetRateLimit(ctx context.Context, client string) (GetRateLimitRow, error) {
+	row := q.db.QueryRow(ctx, getRateLimit, client)
+	var i GetRateLimitRow
+	err := row.Scan(&i.Hits, &i.WindowStart)
+	return i, err
+}
+
+const getReferralCode = `-- name: GetReferralCode :one
+SELECT code
+FROM referral_codes
+WHERE user_id = $1
+`
+
+func (q *Queries) GetReferralCode(ctx context.Context, userID int64) (string, error) {
+	row := q.db.QueryRow(ctx, getReferralCode, userID)
+	var code string
+	err := row.Scan(&code)
+	return code, err
+}
+
+const getReferralCodeOwner = `-- name: GetReferralCodeOwner :one
+SELECT user_id
+FROM referral_codes
+WHERE code = $1
+`
+
+func (q *Queries) GetReferralCodeOwner(ctx context.Context, code string) (int64, error) {
+	row := q.db.QueryRow(ctx, getReferralCodeOwner, code)
+	var user_id int64
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const getRefreshTokenByHash = `-- name: GetRefreshTokenByHash :one
+SELECT id, user_id, family, created_at, expires_at, revoked_at
+FROM refresh_tokens
+WHERE token_hash = $1
+`
+
+type GetRefreshTokenByHashRow struct {
+	ID        int64            `json:"id"`
+	UserID    int64            `json:"user_id"`
+	Family    string           `json:"family"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+	RevokedAt pgtype.Timestamp `json:"revoked_at"`
+}
+
+func (q *Queries) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (GetRefreshTokenByHashRow, error) {
+	row := q.db.QueryRow(ctx, getRefreshTokenByHash, tokenHash)
+	var i GetRefreshTokenByHashRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Family,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, name, dob, email, password_hash, role, created_at, updated_at, active, account_type, signup_source, public_id, deleted_at
+FROM users
+WHERE email = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Active,
+		&i.AccountType,
+		&i.SignupSource,
+		&i.PublicID,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+type GetUserByIDRow struct {
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Dob          pgtype.Date      `json:"dob"`
+	Email        string           `json:"email"`
+	Role         string           `json:"role"`
+	Active       bool             `json:"active"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	AccountType  string           `json:"account_type"`
+	SignupSource string           `json:"signup_source"`
+	PublicID     pgtype.UUID      `json:"public_id"`
+}
+
+func (q *Queries) GetUserByID(ctx context.Context, id int64) (GetUserByIDRow, error) {
+	row := q.db.QueryRow(ctx, getUserByID, id)
+	var i GetUserByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.Email,
+		&i.Role,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AccountType,
+		&i.SignupSource,
+		&i.PublicID,
+	)
+	return i, err
+}
+
+const getUserIDByPublicID = `-- name: GetUserIDByPublicID :one
+SELECT id
+FROM users
+WHERE public_id = $1
+`
+
+func (q *Queries) GetUserIDByPublicID(ctx context.Context, publicID pgtype.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, getUserIDByPublicID, publicID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getUserIdentityBySubject = `-- name: GetUserIdentityBySubject :one
+SELECT id, user_id, provider, subject, email, created_at
+FROM user_identities
+WHERE provider = $1 AND subject = $2
+`
+
+type GetUserIdentityBySubjectParams struct {
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+}
+
+func (q *Queries) GetUserIdentityBySubject(ctx context.Context, arg GetUserIdentityBySubjectParams) (UserIdentity, error) {
+	row := q.db.QueryRow(ctx, getUserIdentityBySubject, arg.Provider, arg.Subject)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Provider,
+		&i.Subject,
+		&i.Email,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserProfile = `-- name: GetUserProfile :one
+SELECT user_id, bio, phone, avatar_url, locale, timezone, updated_at, avatar_key
+FROM user_profiles
+WHERE user_id = $1
+`
+
+func (q *Queries) GetUserProfile(ctx context.Context, userID int64) (UserProfile, error) {
+	row := q.db.QueryRow(ctx, getUserProfile, userID)
+	var i UserProfile
+	err := row.Scan(
+		&i.UserID,
+		&i.Bio,
+		&i.Phone,
+		&i.AvatarUrl,
+		&i.Locale,
+		&i.Timezone,
+		&i.UpdatedAt,
+		&i.AvatarKey,
+	)
+	return i, err
+}
+
+const getUserStats = `-- name: GetUserStats :one
+SELECT total_users, admin_users, signups_last_7_days, signups_last_30_days, average_age, refreshed_at
+FROM user_stats
+WHERE id = 1
+`
+
+type GetUserStatsRow struct {
+	TotalUsers        int64            `json:"total_users"`
+	AdminUsers        int64            `json:"admin_users"`
+	SignupsLast7Days  int64            `json:"signups_last_7_days"`
+	SignupsLast30Days int64            `json:"signups_last_30_days"`
+	AverageAge        float64          `json:"average_age"`
+	RefreshedAt       pgtype.Timestamp `json:"refreshed_at"`
+}
+
+func (q *Queries) GetUserStats(ctx context.Context) (GetUserStatsRow, error) {
+	row := q.db.QueryRow(ctx, getUserStats)
+	var i GetUserStatsRow
+	err := row.Scan(
+		&i.TotalUsers,
+		&i.AdminUsers,
+		&i.SignupsLast7Days,
+		&i.SignupsLast30Days,
+		&i.AverageAge,
+		&i.RefreshedAt,
+	)
+	return i, err
+}
+
+const getWebAuthnCredential = `-- name: GetWebAuthnCredential :one
+SELECT id, user_id, credential_id, public_key, sign_count, name, created_at, last_used_at
+FROM webauthn_credentials
+WHERE credential_id = $1
+`
+
+func (q *Queries) GetWebAuthnCredential(ctx context.Context, credentialID []byte) (WebauthnCredential, error) {
+	row := q.db.QueryRow(ctx, getWebAuthnCredential, credentialID)
+	var i WebauthnCredential
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CredentialID,
+		&i.PublicKey,
+		&i.SignCount,
+		&i.Name,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const listAPIKeysByUser = `-- name: ListAPIKeysByUser :many
+SELECT id, user_id, name, prefix, scopes, created_at, expires_at, last_used_at, revoked_at
+FROM api_keys
+WHERE user_id = $1
+ORDER BY id
+`
+
+type ListAPIKeysByUserRow struct {
+	ID         int64            `json:"id"`
+	UserID     int64            `json:"user_id"`
+	Name       string           `json:"name"`
+	Prefix     string           `json:"prefix"`
+	Scopes     string           `json:"scopes"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	ExpiresAt  pgtype.Timestamp `json:"expires_at"`
+	LastUsedAt pgtype.Timestamp `json:"last_used_at"`
+	RevokedAt  pgtype.Timestamp `json:"revoked_at"`
+}
+
+func (q *Queries) ListAPIKeysByUser(ctx context.Context, userID int64) ([]ListAPIKeysByUserRow, error) {
+	rows, err := q.db.Query(ctx, listAPIKeysByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAPIKeysByUserRow
+	for rows.Next() {
+		var i ListAPIKeysByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Prefix,
+			&i.Scopes,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.LastUsedAt,
+			&i.RevokedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAPIUsageByUser = `-- name: ListAPIUsageByUser :many
+SELECT a.api_key_id, COALESCE(k.name, '')::text AS key_name, a.day, a.requests
+FROM api_usage a
+LEFT JOIN api_keys k ON k.id = a.api_key_id
+WHERE a.user_id = $1 AND a.day >= $2::date AND a.day <= $3::date
+ORDER BY a.day, a.api_key_id
+`
+
+type ListAPIUsageByUserParams struct {
+	UserID  int64       `json:"user_id"`
+	FromDay pgtype.Date `json:"from_day"`
+	ToDay   pgtype.Date `json:"to_day"`
+}
+
+type ListAPIUsageByUserRow struct {
+	ApiKeyID int64       `json:"api_key_id"`
+	KeyName  string      `json:"key_name"`
+	Day      pgtype.Date `json:"day"`
+	Requests int64       `json:"requests"`
+}
+
+func (q *Queries) ListAPIUsageByUser(ctx context.Context, arg ListAPIUsageByUserParams) ([]ListAPIUsageByUserRow, error) {
+	rows, err := q.db.Query(ctx, listAPIUsageByUser, arg.UserID, arg.FromDay, arg.ToDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAPIUsageByUserRow
+	for rows.Next() {
+		var i ListAPIUsageByUserRow
+		if err := rows.Scan(
+			&i.ApiKeyID,
+			&i.KeyName,
+			&i.Day,
+			&i.Requests,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActiveAdmins = `-- name: ListActiveAdmins :many
+SELECT id, name, email
+FROM users
+WHERE role = 'admin' AND active = TRUE AND account_type = 'human' AND deleted_at IS NULL
+ORDER BY id
+`
+
+type ListActiveAdminsRow struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func (q *Queries) ListActiveAdmins(ctx context.Context) ([]ListActiveAdminsRow, error) {
+	rows, err := q.db.Query(ctx, listActiveAdmins)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActiveAdminsRow
+	for rows.Next() {
+		var i ListActiveAdminsRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Email); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActiveSessions = `-- name: ListActiveSessions :many
+SELECT id, ip_address, user_agent, created_at, expires_at
+FROM sessions
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+ORDER BY created_at, id
+`
+
+type ListActiveSessionsParams struct {
+	UserID    int64            `json:"user_id"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+type ListActiveSessionsRow struct {
+	ID        string           `json:"id"`
+	IpAddress string           `json:"ip_address"`
+	UserAgent string           `json:"user_agent"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+func (q *Queries) ListActiveSessions(ctx context.Context, arg ListActiveSessionsParams) ([]ListActiveSessionsRow, error) {
+	rows, err := q.db.Query(ctx, listActiveSessions, arg.UserID, arg.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActiveSessionsRow
+	for rows.Next() {
+		var i ListActiveSessionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeactivatedUsers = `-- name: ListDeactivatedUsers :many
+SELECT id, name, email, updated_at
+FROM users
+WHERE active = FALSE AND updated_at >= $1::timestamp AND deleted_at IS NULL
+ORDER BY updated_at DESC, id
+`
+
+type ListDeactivatedUsersRow struct {
+	ID        int64            `json:"id"`
+	Name      string           `json:"name"`
+	Email     string           `json:"email"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) ListDeactivatedUsers(ctx context.Context, since pgtype.Timestamp) ([]ListDeactivatedUsersRow, error) {
+	rows, err := q.db.Query(ctx, listDeactivatedUsers, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDeactivatedUsersRow
+	for rows.Next() {
+		var i ListDeactivatedUsersRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Email, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEmailDeadLetters = `-- name: ListEmailDeadLetters :many
+SELECT id, recipients, subject, html, error, attempts, created_at, last_attempt_at
+FROM email_dead_letters
+ORDER BY created_at DESC, id DESC
+LIMIT $1
+`
+
+func (q *Queries) ListEmailDeadLetters(ctx context.Context, limit int32) ([]EmailDeadLetter, error) {
+	rows, err := q.db.Query(ctx, listEmailDeadLetters, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EmailDeadLetter
+	for rows.Next() {
+		var i EmailDeadLetter
+		if err := rows.Scan(
+			&i.ID,
+			&i.Recipients,
+			&i.Subject,
+			&i.Html,
+			&i.Error,
+			&i.Attempts,
+			&i.CreatedAt,
+			&i.LastAttemptAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLoginHistoryByUser = `-- name: ListLoginHistoryByUser :many
+SELECT id, email, succeeded, reason, ip_address, user_agent, country, city, created_at
+FROM login_history
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2
+`
+
+type ListLoginHistoryByUserParams struct {
+	UserID pgtype.Int8 `json:"user_id"`
+	Limit  int32       `json:"limit"`
+}
+
+type ListLoginHistoryByUserRow struct {
+	ID        int64            `json:"id"`
+	Email     string           `json:"email"`
+	Succeeded bool             `json:"succeeded"`
+	Reason    string           `json:"reason"`
+	IpAddress string           `json:"ip_address"`
+	UserAgent string           `json:"user_agent"`
+	Country   string           `json:"country"`
+	City      string           `json:"city"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+func (q *Queries) ListLoginHistoryByUser(ctx context.Context, arg ListLoginHistoryByUserParams) ([]ListLoginHistoryByUserRow, error) {
+	rows, err := q.db.Query(ctx, listLoginHistoryByUser, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLoginHistoryByUserRow
+	for rows.Next() {
+		var i ListLoginHistoryByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Succeeded,
+			&i.Reason,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.Country,
+			&i.City,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLoginLockouts = `-- name: ListLoginLockouts :many
+SELECT lock_key, failures, locked_until
+FROM login_lockouts
+WHERE locked_until > $1
+ORDER BY locked_until DESC
+`
+
+type ListLoginLockoutsRow struct {
+	LockKey     string           `json:"lock_key"`
+	Failures    int32            `json:"failures"`
+	LockedUntil pgtype.Timestamp `json:"locked_until"`
+}
+
+func (q *Queries) ListLoginLockouts(ctx context.Context, lockedUntil pgtype.Timestamp) ([]ListLoginLockoutsRow, error) {
+	rows, err := q.db.Query(ctx, listLoginLockouts, lockedUntil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLoginLockoutsRow
+	for rows.Next() {
+		var i ListLoginLockoutsRow
+		if err := rows.Scan(&i.LockKey, &i.Failures, &i.LockedUntil); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNameReviews = `-- name: ListNameReviews :many
+SELECT id, user_id, name, matched, status, reviewed_by, created_at, reviewed_at
+FROM name_reviews
+WHERE status = $1
+ORDER BY created_at, id
+LIMIT $2
+`
+
+type ListNameReviewsParams struct {
+	Status string `json:"status"`
+	Limit  int32  `json:"limit"`
+}
+
+func (q *Queries) ListNameReviews(ctx context.Context, arg ListNameReviewsParams) ([]NameReview, error) {
+	rows, err := q.db.Query(ctx, listNameReviews, arg.Status, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NameReview
+	for rows.Next() {
+		var i NameReview
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Matched,
+			&i.Status,
+			&i.ReviewedBy,
+			&i.CreatedAt,
+			&i.ReviewedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationPreferences = `-- name: ListNotificationPreferences :many
+SELECT user_id, event_type, channel, enabled
+FROM notification_preferences
+WHERE user_id = $1
+ORDER BY event_type, channel
+`
+
+func (q *Queries) ListNotificationPreferences(ctx context.Context, userID int64) ([]NotificationPreference, error) {
+	rows, err := q.db.Query(ctx, listNotificationPreferences, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationPreference
+	for rows.Next() {
+		var i NotificationPreference
+		if err := rows.Scan(
+			&i.UserID,
+			&i.EventType,
+			&i.Channel,
+			&i.Enabled,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationRules = `-- name: ListNotificationRules :many
+SELECT id, event_type, channel, audience, template, webhook_url, enabled, created_at, updated_at
+FROM notification_rules
+ORDER BY event_type, id
+`
+
+func (q *Queries) ListNotificationRules(ctx context.Context) ([]NotificationRule, error) {
+	rows, err := q.db.Query(ctx, listNotificationRules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationRule
+	for rows.Next() {
+		var i NotificationRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.Channel,
+			&i.Audience,
+			&i.Template,
+			&i.WebhookUrl,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationRulesForEvent = `-- name: ListNotificationRulesForEvent :many
+SELECT id, event_type, channel, audience, template, webhook_url, enabled, created_at, updated_at
+FROM notification_rules
+WHERE event_type = $1 AND enabled
+ORDER BY id
+`
+
+func (q *Queries) ListNotificationRulesForEvent(ctx context.Context, eventType string) ([]NotificationRule, error) {
+	rows, err := q.db.Query(ctx, listNotificationRulesForEvent, eventType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationRule
+	for rows.Next() {
+		var i NotificationRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.Channel,
+			&i.Audience,
+			&i.Template,
+			&i.WebhookUrl,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotifications = `-- name: ListNotifications :many
+SELECT id, user_id, event_type, title, details, created_at, read_at
+FROM notifications
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2
+`
+
+type ListNotificationsParams struct {
+	UserID int64 `json:"user_id"`
+	Limit  int32 `json:"limit"`
+}
+
+func (q *Queries) ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error) {
+	rows, err := q.db.Query(ctx, listNotifications, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.EventType,
+			&i.Title,
+			&i.Details,
+			&i.CreatedAt,
+			&i.ReadAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrganizationUsage = `-- name: ListOrganizationUsage :many
+SELECT org_id, day, seats, api_calls, storage_bytes
+FROM organization_usage
+WHERE org_id = $1 AND day >= $2::date AND day <= $3::date
+ORDER BY day
+`
+
+type ListOrganizationUsageParams struct {
+	OrgID   int64       `json:"org_id"`
+	FromDay pgtype.Date `json:"from_day"`
+	ToDay   pgtype.Date `json:"to_day"`
+}
+
+func (q *Queries) ListOrganizationUsage(ctx context.Context, arg ListOrganizationUsageParams) ([]OrganizationUsage, error) {
+	rows, err := q.db.Query(ctx, listOrganizationUsage, arg.OrgID, arg.FromDay, arg.ToDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrganizationUsage
+	for rows.Next() {
+		var i OrganizationUsage
+		if err := rows.Scan(
+			&i.OrgID,
+			&i.Day,
+			&i.Seats,
+			&i.ApiCalls,
+			&i.StorageBytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrganizations = `-- name: ListOrganizations :many
+SELECT id, name, created_at
+FROM organizations
+ORDER BY id
+`
+
+func (q *Queries) ListOrganizations(ctx context.Context) ([]Organization, error) {
+	rows, err := q.db.Query(ctx, listOrganizations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Organization
+	for rows.Next() {
+		var i Organization
+		if err := rows.Scan(&i.ID, &i.Name, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRevokedTokens = `-- name: ListRevokedTokens :many
+SELECT jti, expires_at
+FROM revoked_tokens
+WHERE expires_at > $1
+`
+
+type ListRevokedTokensRow struct {
+	Jti       string           `json:"jti"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+func (q *Queries) ListRevokedTokens(ctx context.Context, expiresAt pgtype.Timestamp) ([]ListRevokedTokensRow, error) {
+	rows, err := q.db.Query(ctx, listRevokedTokens, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRevokedTokensRow
+	for rows.Next() {
+		var i ListRevokedTokensRow
+		if err := rows.Scan(&i.Jti, &i.ExpiresAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSecurityEvents = `-- name: ListSecurityEvents :many
+SELECT e.id, e.event_type, actor.public_id AS actor_public_id, target.public_id AS target_public_id, e.details, e.ip_address, e.created_at, e.hash
+FROM security_events e
+LEFT JOIN users actor ON actor.id = e.actor_id
+LEFT JOIN users target ON target.id = e.target_id
+WHERE e.id < $1
+ORDER BY e.id DESC
+LIMIT $2
+`
+
+type ListSecurityEventsParams struct {
+	ID    int64 `json:"id"`
+	Limit int32 `json:"limit"`
+}
+
+type ListSecurityEventsRow struct {
+	ID             int64            `json:"id"`
+	EventType      string           `json:"event_type"`
+	ActorPublicID  pgtype.UUID      `json:"actor_public_id"`
+	TargetPublicID pgtype.UUID      `json:"target_public_id"`
+	Details        string           `json:"details"`
+	IpAddress      string           `json:"ip_address"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	Hash           string           `json:"hash"`
+}
+
+func (q *Queries) ListSecurityEvents(ctx context.Context, arg ListSecurityEventsParams) ([]ListSecurityEventsRow, error) {
+	rows, err := q.db.Query(ctx, listSecurityEvents, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSecurityEventsRow
+	for rows.Next() {
+		var i ListSecurityEventsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.ActorPublicID,
+			&i.TargetPublicID,
+			&i.Details,
+			&i.IpAddress,
+			&i.CreatedAt,
+			&i.Hash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSecurityEventsAfter = `-- name: ListSecurityEventsAfter :many
+SELECT id, event_type, actor_id, target_id, details, ip_address, created_at, prev_hash, hash
+FROM security_events
+WHERE id > $1
+ORDER BY id
+LIMIT $2
+`
+
+type ListSecurityEventsAfterParams struct {
+	ID    int64 `json:"id"`
+	Limit int32 `json:"limit"`
+}
+
+func (q *Queries) ListSecurityEventsAfter(ctx context.Context, arg ListSecurityEventsAfterParams) ([]SecurityEvent, error) {
+	rows, err := q.db.Query(ctx, listSecurityEventsAfter, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SecurityEvent
+	for rows.Next() {
+		var i SecurityEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.ActorID,
+			&i.TargetID,
+			&i.Details,
+			&i.IpAddress,
+			&i.CreatedAt,
+			&i.PrevHash,
+			&i.Hash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listServiceAccounts = `-- name: ListServiceAccounts :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE account_type = 'service' AND deleted_at IS NULL
+ORDER BY id
+`
+
+type ListServiceAccountsRow struct {
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Dob          pgtype.Date      `json:"dob"`
+	Email        string           `json:"email"`
+	Role         string           `json:"role"`
+	Active       bool             `json:"active"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	AccountType  string           `json:"account_type"`
+	SignupSource string           `json:"signup_source"`
+	PublicID     pgtype.UUID      `json:"public_id"`
+}
+
+func (q *Queries) ListServiceAccounts(ctx context.Context) ([]ListServiceAccountsRow, error) {
+	rows, err := q.db.Query(ctx, listServiceAccounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListServiceAccountsRow
+	for rows.Next() {
+		var i ListServiceAccountsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Dob,
+			&i.Email,
+			&i.Role,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AccountType,
+			&i.SignupSource,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTokenRevocations = `-- name: ListTokenRevocations :many
+SELECT user_id, revoked_at
+FROM token_revocations
+`
+
+func (q *Queries) ListTokenRevocations(ctx context.Context) ([]TokenRevocation, error) {
+	rows, err := q.db.Query(ctx, listTokenRevocations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TokenRevocation
+	for rows.Next() {
+		var i TokenRevocation
+		if err := rows.Scan(&i.UserID, &i.RevokedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUpcomingBirthdays = `-- name: ListUpcomingBirthdays :many
+SELECT id, name, dob, public_id
+FROM users
+WHERE deleted_at IS NULL
+    AND ((EXTRACT(MONTH FROM dob)::int * 100 + EXTRACT(DAY FROM dob)::int) BETWEEN $1::int AND $2::int
+    OR (EXTRACT(MONTH FROM dob)::int * 100 + EXTRACT(DAY FROM dob)::int) BETWEEN $3::int AND $4::int)
+ORDER BY (EXTRACT(MONTH FROM dob)::int * 100 + EXTRACT(DAY FROM dob)::int) < $1::int,
+    EXTRACT(MONTH FROM dob)::int * 100 + EXTRACT(DAY FROM dob)::int, id
+LIMIT $5
+`
+
+type ListUpcomingBirthdaysParams struct {
+	FromDay  int32 `json:"from_day"`
+	ToDay    int32 `json:"to_day"`
+	WrapFrom int32 `json:"wrap_from"`
+	WrapTo   int32 `json:"wrap_to"`
+	RowLimit int32 `json:"row_limit"`
+}
+
+type ListUpcomingBirthdaysRow struct {
+	ID       int64       `json:"id"`
+	Name     string      `json:"name"`
+	Dob      pgtype.Date `json:"dob"`
+	PublicID pgtype.UUID `json:"public_id"`
+}
+
+func (q *Queries) ListUpcomingBirthdays(ctx context.Context, arg ListUpcomingBirthdaysParams) ([]ListUpcomingBirthdaysRow, error) {
+	rows, err := q.db.Query(ctx, listUpcomingBirthdays,
+		arg.FromDay,
+		arg.ToDay,
+		arg.WrapFrom,
+		arg.WrapTo,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUpcomingBirthdaysRow
+	for rows.Next() {
+		var i ListUpcomingBirthdaysRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Dob,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserIdentitiesByUser = `-- name: ListUserIdentitiesByUser :many
+SELECT id, user_id, provider, subject, email, created_at
+FROM user_identities
+WHERE user_id = $1
+ORDER BY provider
+`
+
+func (q *Queries) ListUserIdentitiesByUser(ctx context.Context, userID int64) ([]UserIdentity, error) {
+	rows, err := q.db.Query(ctx, listUserIdentitiesByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserIdentity
+	for rows.Next() {
+		var i UserIdentity
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Provider,
+			&i.Subject,
+			&i.Email,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE deleted_at IS NULL
+ORDER BY id
+`
+
+type ListUsersRow struct {
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Dob          pgtype.Date      `json:"dob"`
+	Email        string           `json:"email"`
+	Role         string           `json:"role"`
+	Active       bool             `json:"active"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	AccountType  string           `json:"account_type"`
+	SignupSource string           `json:"signup_source"`
+	PublicID     pgtype.UUID      `json:"public_id"`
+}
+
+func (q *Queries) ListUsers(ctx context.Context) ([]ListUsersRow, error) {
+	rows, err := q.db.Query(ctx, listUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersRow
+	for rows.Next() {
+		var i ListUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Dob,
+			&i.Email,
+			&i.Role,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AccountType,
+			&i.SignupSource,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersBySignupSource = `-- name: ListUsersBySignupSource :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE signup_source = $1 AND deleted_at IS NULL
+ORDER BY id
+`
+
+type ListUsersBySignupSourceRow struct {
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Dob          pgtype.Date      `json:"dob"`
+	Email        string           `json:"email"`
+	Role         string           `json:"role"`
+	Active       bool             `json:"active"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	AccountType  string           `json:"account_type"`
+	SignupSource string           `json:"signup_source"`
+	PublicID     pgtype.UUID      `json:"public_id"`
+}
+
+func (q *Queries) ListUsersBySignupSource(ctx context.Context, signupSource string) ([]ListUsersBySignupSourceRow, error) {
+	rows, err := q.db.Query(ctx, listUsersBySignupSource, signupSource)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersBySignupSourceRow
+	for rows.Next() {
+		var i ListUsersBySignupSourceRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Dob,
+			&i.Email,
+			&i.Role,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AccountType,
+			&i.SignupSource,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersPaginated = `-- name: ListUsersPaginated :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE deleted_at IS NULL
+ORDER BY id
+LIMIT $1 OFFSET $2
+`
+
+type ListUsersPaginatedParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+type ListUsersPaginatedRow struct {
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Dob          pgtype.Date      `json:"dob"`
+	Email        string           `json:"email"`
+	Role         string           `json:"role"`
+	Active       bool             `json:"active"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	AccountType  string           `json:"account_type"`
+	SignupSource string           `json:"signup_source"`
+	PublicID     pgtype.UUID      `json:"public_id"`
+}
+
+func (q *Queries) ListUsersPaginated(ctx context.Context, arg ListUsersPaginatedParams) ([]ListUsersPaginatedRow, error) {
+	rows, err := q.db.Query(ctx, listUsersPaginated, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersPaginatedRow
+	for rows.Next() {
+		var i ListUsersPaginatedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Dob,
+			&i.Email,
+			&i.Role,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AccountType,
+			&i.SignupSource,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebAuthnCredentialsByUser = `-- name: ListWebAuthnCredentialsByUser :many
+SELECT id, user_id, credential_id, public_key, sign_count, name, created_at, last_used_at
+FROM webauthn_credentials
+WHERE user_id = $1
+ORDER BY id
+`
+
+func (q *Queries) ListWebAuthnCredentialsByUser(ctx context.Context, userID int64) ([]WebauthnCredential, error) {
+	rows, err := q.db.Query(ctx, listWebAuthnCredentialsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebauthnCredential
+	for rows.Next() {
+		var i WebauthnCredential
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CredentialID,
+			&i.PublicKey,
+			&i.SignCount,
+			&i.Name,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockLogin = `-- name: LockLogin :exec
+UPDATE login_lockouts SET locked_until = $2 WHERE lock_key = $1
+`
+
+type LockLoginParams struct {
+	LockKey     string           `json:"lock_key"`
+	LockedUntil pgtype.Timestamp `json:"locked_until"`
+}
+
+func (q *Queries) LockLogin(ctx context.Context, arg LockLoginParams) error {
+	_, err := q.db.Exec(ctx, lockLogin, arg.LockKey, arg.LockedUntil)
+	return err
+}
+
+const loginHistoryStats = `-- name: LoginHistoryStats :one
+SELECT COUNT(*) AS total, MIN(created_at)::timestamp AS oldest
+FROM login_history
+`
+
+type LoginHistoryStatsRow struct {
+	Total  int64            `json:"total"`
+	Oldest pgtype.Timestamp `json:"oldest"`
+}
+
+func (q *Queries) LoginHistoryStats(ctx context.Context) (LoginHistoryStatsRow, error) {
+	row := q.db.QueryRow(ctx, loginHistoryStats)
+	var i LoginHistoryStatsRow
+	err := row.Scan(&i.Total, &i.Oldest)
+	return i, err
+}
+
+const loginLockoutStats = `-- name: LoginLockoutStats :one
+SELECT COUNT(*) AS total, MIN(window_start)::timestamp AS oldest
+FROM login_lockouts
+`
+
+type LoginLockoutStatsRow struct {
+	Total  int64            `json:"total"`
+	Oldest pgtype.Timestamp `json:"oldest"`
+}
+
+func (q *Queries) LoginLockoutStats(ctx context.Context) (LoginLockoutStatsRow, error) {
+	row := q.db.QueryRow(ctx, loginLockoutStats)
+	var i LoginLockoutStatsRow
+	err := row.Scan(&i.Total, &i.Oldest)
+	return i, err
+}
+
+const lookupUsersByEmail = `-- name: LookupUsersByEmail :many
+SELECT id, name, email, role, active, account_type, public_id
+FROM users
+WHERE deleted_at IS NULL AND LOWER(email) LIKE $1::text
+ORDER BY LOWER(email), id
+LIMIT $2
+`
+
+type LookupUsersByEmailParams struct {
+	Pattern  string `json:"pattern"`
+	RowLimit int32  `json:"row_limit"`
+}
+
+type LookupUsersByEmailRow struct {
+	ID          int64       `json:"id"`
+	Name        string      `json:"name"`
+	Email       string      `json:"email"`
+	Role        string      `json:"role"`
+	Active      bool        `json:"active"`
+	AccountType string      `json:"account_type"`
+	PublicID    pgtype.UUID `json:"public_id"`
+}
+
+func (q *Queries) LookupUsersByEmail(ctx context.Context, arg LookupUsersByEmailParams) ([]LookupUsersByEmailRow, error) {
+	rows, err := q.db.Query(ctx, lookupUsersByEmail, arg.Pattern, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LookupUsersByEmailRow
+	for rows.Next() {
+		var i LookupUsersByEmailRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Role,
+			&i.Active,
+			&i.AccountType,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const magicLinkRedemptionStats = `-- name: MagicLinkRedemptionStats :one
+SELECT COUNT(*) AS total, MIN(expires_at)::timestamp AS oldest
+FROM magic_link_redemptions
+`
+
+type MagicLinkRedemptionStatsRow struct {
+	Total  int64            `json:"total"`
+	Oldest pgtype.Timestamp `json:"oldest"`
+}
+
+func (q *Queries) MagicLinkRedemptionStats(ctx context.Context) (MagicLinkRedemptionStatsRow, error) {
+	row := q.db.QueryRow(ctx, magicLinkRedemptionStats)
+	var i MagicLinkRedemptionStatsRow
+	err := row.Scan(&i.Total, &i.Oldest)
+	return i, err
+}
+
+const markNotificationRead = `-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND read_at IS NULL
+`
+
+type MarkNotificationReadParams struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markNotificationRead, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const notificationStats = `-- name: NotificationStats :one
+SELECT COUNT(*) AS total, MIN(created_at)::timestamp AS oldest
+FROM notifications
+`
+
+type NotificationStatsRow struct {
+	Total  int64            `json:"total"`
+	Oldest pgtype.Timestamp `json:"oldest"`
+}
+
+func (q *Queries) NotificationStats(ctx context.Context) (NotificationStatsRow, error) {
+	row := q.db.QueryRow(ctx, notificationStats)
+	var i NotificationStatsRow
+	err := row.Scan(&i.Total, &i.Oldest)
+	return i, err
+}
+
+const pruneLoginHistory = `-- name: PruneLoginHistory :execrows
+DELETE FROM login_history
+WHERE created_at < $1
+`
+
+func (q *Queries) PruneLoginHistory(ctx context.Context, createdAt pgtype.Timestamp) (int64, error) {
+	result, err := q.db.Exec(ctx, pruneLoginHistory, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const pruneNotifications = `-- name: PruneNotifications :execrows
+DELETE FROM notifications
+WHERE created_at < $1
+`
+
+func (q *Queries) PruneNotifications(ctx context.Context, createdAt pgtype.Timestamp) (int64, error) {
+	result, err := q.db.Exec(ctx, pruneNotifications, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const pruneRevokedTokens = `-- name: PruneRevokedTokens :execrows
+DELETE FROM revoked_tokens
+WHERE expires_at < $1
+`
+
+func (q *Queries) PruneRevokedTokens(ctx context.Context, expiresAt pgtype.Timestamp) (int64, error) {
+	result, err := q.db.Exec(ctx, pruneRevokedTokens, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const pruneSessions = `-- name: PruneSessions :execrows
+DELETE FROM sessions
+WHERE expires_at < $1
+`
+
+func (q *Queries) PruneSessions(ctx context.Context, expiresAt pgtype.Timestamp) (int64, error) {
+	result, err := q.db.Exec(ctx, pruneSessions, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const rateLimitStats = `-- name: RateLimitStats :one
+SELECT COUNT(*) AS total, MIN(window_start)::timestamp AS oldest
+FROM rate_limit_counters
+`
+
+type RateLimitStatsRow struct {
+	Total  int64            `json:"total"`
+	Oldest pgtype.Timestamp `json:"oldest"`
+}
+
+func (q *Queries) RateLimitStats(ctx context.Context) (RateLimitStatsRow, error) {
+	row := q.db.QueryRow(ctx, rateLimitStats)
+	var i RateLimitStatsRow
+	err := row.Scan(&i.Total, &i.Oldest)
+	return i, err
+}
+
+const recordEmailDeadLetterAttempt = `-- name: RecordEmailDeadLetterAttempt :exec
+UPDATE email_dead_letters
+SET error = $2, attempts = attempts + 1, last_attempt_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+type RecordEmailDeadLetterAttemptParams struct {
+	ID    int64  `json:"id"`
+	Error string `json:"error"`
+}
+
+func (q *Queries) RecordEmailDeadLetterAttempt(ctx context.Context, arg RecordEmailDeadLetterAttemptParams) error {
+	_, err := q.db.Exec(ctx, recordEmailDeadLetterAttempt, arg.ID, arg.Error)
+	return err
+}
+
+const recordLogin = `-- name: RecordLogin :exec
+INSERT INTO login_history (user_id, email, succeeded, reason, ip_address, user_agent, country, city)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type RecordLoginParams struct {
+	UserID    pgtype.Int8 `json:"user_id"`
+	Email     string      `json:"email"`
+	Succeeded bool        `json:"succeeded"`
+	Reason    string      `json:"reason"`
+	IpAddress string      `json:"ip_address"`
+	UserAgent string      `json:"user_agent"`
+	Country   string      `json:"country"`
+	City      string      `json:"city"`
+}
+
+func (q *Queries) RecordLogin(ctx context.Context, arg RecordLoginParams) error {
+	_, err := q.db.Exec(ctx, recordLogin,
+		arg.UserID,
+		arg.Email,
+		arg.Succeeded,
+		arg.Reason,
+		arg.IpAddress,
+		arg.UserAgent,
+		arg.Country,
+		arg.City,
+	)
+	return err
+}
+
+const recordLoginFailure = `-- name: RecordLoginFailure :one
+INSERT INTO login_lockouts (lock_key, failures, window_start)
+VALUES ($1, 1, $2::timestamp)
+ON CONFLICT (lock_key) DO UPDATE SET
+    failures = CASE WHEN login_lockouts.window_start <= $3::timestamp THEN 1 ELSE login_lockouts.failures + 1 END,
+    window_start = CASE WHEN login_lockouts.window_start <= $3::timestamp THEN EXCLUDED.window_start ELSE login_lockouts.window_start END
+RETURNING failures
+`
+
+type RecordLoginFailureParams struct {
+	LockKey       string           `json:"lock_key"`
+	Now           pgtype.Timestamp `json:"now"`
+	ExpiredBefore pgtype.Timestamp `json:"expired_before"`
+}
+
+func (q *Queries) RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) (int32, error) {
+	row := q.db.QueryRow(ctx, recordLoginFailure, arg.LockKey, arg.Now, arg.ExpiredBefore)
+	var failures int32
+	err := row.Scan(&failures)
+	return failures, err
+}
+
+const redeemMagicLink = `-- name: RedeemMagicLink :execrows
+INSERT INTO magic_link_redemptions (token_hash, expires_at)
+VALUES ($1, $2)
+ON CONFLICT (token_hash) DO NOTHING
+`
+
+type RedeemMagicLinkParams struct {
+	TokenHash string           `json:"token_hash"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+func (q *Queries) RedeemMagicLink(ctx context.Context, arg RedeemMagicLinkParams) (int64, error) {
+	result, err := q.db.Exec(ctx, redeemMagicLink, arg.TokenHash, arg.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const referralStats = `-- name: ReferralStats :one
+SELECT COUNT(*) AS total, COUNT(CASE WHEN created_at >= $1::timestamp THEN 1 END) AS recent
+FROM referrals
+WHERE referrer_id = $2
+`
+
+type ReferralStatsParams struct {
+	Since      pgtype.Timestamp `json:"since"`
+	ReferrerID int64            `json:"referrer_id"`
+}
+
+type ReferralStatsRow struct {
+	Total  int64 `json:"total"`
+	Recent int64 `json:"recent"`
+}
+
+func (q *Queries) ReferralStats(ctx context.Context, arg ReferralStatsParams) (ReferralStatsRow, error) {
+	row := q.db.QueryRow(ctx, referralStats, arg.Since, arg.ReferrerID)
+	var i ReferralStatsRow
+	err := row.Scan(&i.Total, &i.Recent)
+	return i, err
+}
+
+const refreshUserStats = `-- name: RefreshUserStats :exec
+REFRESH MATERIALIZED VIEW CONCURRENTLY user_stats
+`
+
+func (q *Queries) RefreshUserStats(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, refreshUserStats)
+	return err
+}
+
+const releaseJobLease = `-- name: ReleaseJobLease :exec
+DELETE FROM job_leases WHERE name = $1 AND holder = $2
+`
+
+type ReleaseJobLeaseParams struct {
+	Name   string `json:"name"`
+	Holder string `json:"holder"`
+}
+
+func (q *Queries) ReleaseJobLease(ctx context.Context, arg ReleaseJobLeaseParams) error {
+	_, err := q.db.Exec(ctx, releaseJobLease, arg.Name, arg.Holder)
+	return err
+}
+
+const resolveNameReview = `-- name: ResolveNameReview :execrows
+UPDATE name_reviews
+SET status = $2, reviewed_by = $3, reviewed_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'pending'
+`
+
+type ResolveNameReviewParams struct {
+	ID         int64       `json:"id"`
+	Status     string      `json:"status"`
+	ReviewedBy pgtype.Int8 `json:"reviewed_by"`
+}
+
+func (q *Queries) ResolveNameReview(ctx context.Context, arg ResolveNameReviewParams) (int64, error) {
+	result, err := q.db.Exec(ctx, resolveNameReview, arg.ID, arg.Status, arg.ReviewedBy)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreUser = `-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+`
+
+type RestoreUserRow struct {
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Dob          pgtype.Date      `json:"dob"`
+	Email        string           `json:"email"`
+	Role         string           `json:"role"`
+	Active       bool             `json:"active"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	AccountType  string           `json:"account_type"`
+	SignupSource string           `json:"signup_source"`
+	PublicID     pgtype.UUID      `json:"public_id"`
+}
+
+func (q *Queries) RestoreUser(ctx context.Context, id int64) (RestoreUserRow, error) {
+	row := q.db.QueryRow(ctx, restoreUser, id)
+	var i RestoreUserRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.Email,
+		&i.Role,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AccountType,
+		&i.SignupSource,
+		&i.PublicID,
+	)
+	return i, err
+}
+
+const revokeAPIKey = `-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+`
+
+type RevokeAPIKeyParams struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeAPIKey, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeRefreshToken = `-- name: RevokeRefreshToken :execrows
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeRefreshToken(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeRefreshToken, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeRefreshTokenFamily = `-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE family = $1 AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeRefreshTokenFamily(ctx context.Context, family string) error {
+	_, err := q.db.Exec(ctx, revokeRefreshTokenFamily, family)
+	return err
+}
+
+const revokeSession = `-- name: RevokeSession :execrows
+UPDATE sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL
+`
+
+type RevokeSessionParams struct {
+	ID     string `json:"id"`
+	UserID int64  `json:"user_id"`
+}
+
+func (q *Queries) RevokeSession(ctx context.Context, arg RevokeSessionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, revokeSession, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const revokeToken = `-- name: RevokeToken :exec
+INSERT INTO revoked_tokens (jti, user_id, expires_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (jti) DO NOTHING
+`
+
+type RevokeTokenParams struct {
+	Jti       string           `json:"jti"`
+	UserID    int64            `json:"user_id"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+func (q *Queries) RevokeToken(ctx context.Context, arg RevokeTokenParams) error {
+	_, err := q.db.Exec(ctx, revokeToken, arg.Jti, arg.UserID, arg.ExpiresAt)
+	return err
+}
+
+const revokeUserTokens = `-- name: RevokeUserTokens :exec
+INSERT INTO token_revocations (user_id, revoked_at)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET revoked_at = EXCLUDED.revoked_at
+`
+
+type RevokeUserTokensParams struct {
+	UserID    int64            `json:"user_id"`
+	RevokedAt pgtype.Timestamp `json:"revoked_at"`
+}
+
+func (q *Queries) RevokeUserTokens(ctx context.Context, arg RevokeUserTokensParams) error {
+	_, err := q.db.Exec(ctx, revokeUserTokens, arg.UserID, arg.RevokedAt)
+	return err
+}
+
+const revokedTokenStats = `-- name: RevokedTokenStats :one
+SELECT COUNT(*) AS total, MIN(expires_at)::timestamp AS oldest
+FROM revoked_tokens
+`
+
+type RevokedTokenStatsRow struct {
+	Total  int64            `json:"total"`
+	Oldest pgtype.Timestamp `json:"oldest"`
+}
+
+func (q *Queries) RevokedTokenStats(ctx context.Context) (RevokedTokenStatsRow, error) {
+	row := q.db.QueryRow(ctx, revokedTokenStats)
+	var i RevokedTokenStatsRow
+	err := row.Scan(&i.Total, &i.Oldest)
+	return i, err
+}
+
+const sSOPendingLoginStats = `-- name: SSOPendingLoginStats :one
+SELECT COUNT(*) AS total, MIN(expires_at)::timestamp AS oldest
+FROM sso_pending_logins
+`
+
+type SSOPendingLoginStatsRow struct {
+	Total  int64            `json:"total"`
+	Oldest pgtype.Timestamp `json:"oldest"`
+}
+
+func (q *Queries) SSOPendingLoginStats(ctx context.Context) (SSOPendingLoginStatsRow, error) {
+	row := q.db.QueryRow(ctx, sSOPendingLoginStats)
+	var i SSOPendingLoginStatsRow
+	err := row.Scan(&i.Total, &i.Oldest)
+	return i, err
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
 FROM users
+WHERE deleted_at IS NULL
+    AND ($1::text IS NULL OR name ILIKE $1)
+    AND ($2::text IS NULL OR LOWER(email) = LOWER($2))
+    AND ($3::text IS NULL OR role = $3)
+    AND ($4::text IS NULL OR signup_source = $4)
+    AND ($5::date IS NULL OR dob > $5)
+    AND ($6::date IS NULL OR dob <= $6)
+    AND ($7::timestamp IS NULL OR created_at >= $7)
+    AND ($8::timestamp IS NULL OR created_at < $8)
+    AND ($9::bigint IS NULL OR id IN (SELECT user_id FROM organization_members WHERE org_id = $9))
+ORDER BY
+    CASE $10::text WHEN 'name' THEN name WHEN 'email' THEN email WHEN 'role' THEN role END,
+    CASE $10::text WHEN 'dob' THEN dob END,
+    CASE $10::text WHEN 'created_at' THEN created_at WHEN 'updated_at' THEN updated_at END,
+    CASE $10::text WHEN '-name' THEN name WHEN '-email' THEN email WHEN '-role' THEN role END DESC,
+    CASE $10::text WHEN '-dob' THEN dob END DESC,
+    CASE $10::text WHEN '-created_at' THEN created_at WHEN '-updated_at' THEN updated_at END DESC,
+    CASE $11::text WHEN 'name' THEN name WHEN 'email' THEN email WHEN 'role' THEN role END,
+    CASE $11::text WHEN 'dob' THEN dob END,
+    CASE $11::text WHEN 'created_at' THEN created_at WHEN 'updated_at' THEN updated_at END,
+    CASE $11::text WHEN '-name' THEN name WHEN '-email' THEN email WHEN '-role' THEN role END DESC,
+    CASE $11::text WHEN '-dob' THEN dob END DESC,
+    CASE $11::text WHEN '-created_at' THEN created_at WHEN '-updated_at' THEN updated_at END DESC,
+    CASE $12::text WHEN 'name' THEN name WHEN 'email' THEN email WHEN 'role' THEN role END,
+    CASE $12::text WHEN 'dob' THEN dob END,
+    CASE $12::text WHEN 'created_at' THEN created_at WHEN 'updated_at' THEN updated_at END,
+    CASE $12::text WHEN '-name' THEN name WHEN '-email' THEN email WHEN '-role' THEN role END DESC,
+    CASE $12::text WHEN '-dob' THEN dob END DESC,
+    CASE $12::text WHEN '-created_at' THEN created_at WHEN '-updated_at' THEN updated_at END DESC,
+    id
+LIMIT $13 OFFSET $14
 `
 
-func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
-	row := q.db.QueryRow(ctx, countUsers)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
+type SearchUsersParams struct {
+	NamePattern   pgtype.Text      `json:"name_pattern"`
+	Email         pgtype.Text      `json:"email"`
+	Role          pgtype.Text      `json:"role"`
+	SignupSource  pgtype.Text      `json:"signup_source"`
+	DobAfter      pgtype.Date      `json:"dob_after"`
+	DobUntil      pgtype.Date      `json:"dob_until"`
+	CreatedFrom   pgtype.Timestamp `json:"created_from"`
+	CreatedBefore pgtype.Timestamp `json:"created_before"`
+	OrgID         pgtype.Int8      `json:"org_id"`
+	Sort1         string           `json:"sort1"`
+	Sort2         string           `json:"sort2"`
+	Sort3         string           `json:"sort3"`
+	RowLimit      int32            `json:"row_limit"`
+	RowOffset     int32            `json:"row_offset"`
 }
 
-const createUser = `-- name: CreateUser :one
-INSERT INTO users (name, dob) 
-VALUES ($1, $2) 
-RETURNING id, name, dob
+type SearchUsersRow struct {
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Dob          pgtype.Date      `json:"dob"`
+	Email        string           `json:"email"`
+	Role         string           `json:"role"`
+	Active       bool             `json:"active"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	AccountType  string           `json:"account_type"`
+	SignupSource string           `json:"signup_source"`
+	PublicID     pgtype.UUID      `json:"public_id"`
+}
+
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error) {
+	rows, err := q.db.Query(ctx, searchUsers,
+		arg.NamePattern,
+		arg.Email,
+		arg.Role,
+		arg.SignupSource,
+		arg.DobAfter,
+		arg.DobUntil,
+		arg.CreatedFrom,
+		arg.CreatedBefore,
+		arg.OrgID,
+		arg.Sort1,
+		arg.Sort2,
+		arg.Sort3,
+		arg.RowLimit,
+		arg.RowOffset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchUsersRow
+	for rows.Next() {
+		var i SearchUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Dob,
+			&i.Email,
+			&i.Role,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AccountType,
+			&i.SignupSource,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sessionStats = `-- name: SessionStats :one
+SELECT COUNT(*) AS total, MIN(expires_at)::timestamp AS oldest
+FROM sessions
 `
 
-type CreateUserParams struct {
-	Name string      `json:"name"`
-	Dob  pgtype.Date `json:"dob"`
+type SessionStatsRow struct {
+	Total  int64            `json:"total"`
+	Oldest pgtype.Timestamp `json:"oldest"`
 }
 
-func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRow(ctx, createUser, arg.Name, arg.Dob)
-	var i User
-	err := row.Scan(&i.ID, &i.Name, &i.Dob)
+func (q *Queries) SessionStats(ctx context.Context) (SessionStatsRow, error) {
+	row := q.db.QueryRow(ctx, sessionStats)
+	var i SessionStatsRow
+	err := row.Scan(&i.Total, &i.Oldest)
 	return i, err
 }
 
-const deleteUser = `-- name: DeleteUser :exec
-DELETE FROM users 
-WHERE id = $1
+const setNotificationPreference = `-- name: SetNotificationPreference :exec
+INSERT INTO notification_preferences (user_id, event_type, channel, enabled)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, event_type, channel) DO UPDATE SET enabled = EXCLUDED.enabled
 `
 
-func (q *Queries) DeleteUser(ctx context.Context, id int32) error {
-	_, err := q.db.Exec(ctx, deleteUser, id)
+type SetNotificationPreferenceParams struct {
+	UserID    int64  `json:"user_id"`
+	EventType string `json:"event_type"`
+	Channel   string `json:"channel"`
+	Enabled   bool   `json:"enabled"`
+}
+
+func (q *Queries) SetNotificationPreference(ctx context.Context, arg SetNotificationPreferenceParams) error {
+	_, err := q.db.Exec(ctx, setNotificationPreference,
+		arg.UserID,
+		arg.EventType,
+		arg.Channel,
+		arg.Enabled,
+	)
 	return err
 }
 
-const getUserByID = `-- name: GetUserByID :one
-SELECT id, name, dob 
-FROM users 
-WHERE id = $1
+const setOrganizationMember = `-- name: SetOrganizationMember :exec
+INSERT INTO organization_members (user_id, org_id)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET org_id = EXCLUDED.org_id, created_at = CURRENT_TIMESTAMP
 `
 
-func (q *Queries) GetUserByID(ctx context.Context, id int32) (User, error) {
-	row := q.db.QueryRow(ctx, getUserByID, id)
-	var i User
-	err := row.Scan(&i.ID, &i.Name, &i.Dob)
+type SetOrganizationMemberParams struct {
+	UserID int64 `json:"user_id"`
+	OrgID  int64 `json:"org_id"`
+}
+
+func (q *Queries) SetOrganizationMember(ctx context.Context, arg SetOrganizationMemberParams) error {
+	_, err := q.db.Exec(ctx, setOrganizationMember, arg.UserID, arg.OrgID)
+	return err
+}
+
+const setUserActive = `-- name: SetUserActive :one
+UPDATE users
+SET active = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+`
+
+type SetUserActiveParams struct {
+	ID     int64 `json:"id"`
+	Active bool  `json:"active"`
+}
+
+type SetUserActiveRow struct {
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Dob          pgtype.Date      `json:"dob"`
+	Email        string           `json:"email"`
+	Role         string           `json:"role"`
+	Active       bool             `json:"active"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	AccountType  string           `json:"account_type"`
+	SignupSource string           `json:"signup_source"`
+	PublicID     pgtype.UUID      `json:"public_id"`
+}
+
+func (q *Queries) SetUserActive(ctx context.Context, arg SetUserActiveParams) (SetUserActiveRow, error) {
+	row := q.db.QueryRow(ctx, setUserActive, arg.ID, arg.Active)
+	var i SetUserActiveRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.Email,
+		&i.Role,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AccountType,
+		&i.SignupSource,
+		&i.PublicID,
+	)
 	return i, err
 }
 
-const listUsers = `-- name: ListUsers :many
-SELECT id, name, dob 
-FROM users 
-ORDER BY id
+const setUserProfileAvatarKey = `-- name: SetUserProfileAvatarKey :exec
+INSERT INTO user_profiles (user_id, avatar_key)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET avatar_key = EXCLUDED.avatar_key, updated_at = CURRENT_TIMESTAMP
 `
 
-func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
-	rows, err := q.db.Query(ctx, listUsers)
+type SetUserProfileAvatarKeyParams struct {
+	UserID    int64  `json:"user_id"`
+	AvatarKey string `json:"avatar_key"`
+}
+
+func (q *Queries) SetUserProfileAvatarKey(ctx context.Context, arg SetUserProfileAvatarKeyParams) error {
+	_, err := q.db.Exec(ctx, setUserProfileAvatarKey, arg.UserID, arg.AvatarKey)
+	return err
+}
+
+const signupsByDay = `-- name: SignupsByDay :many
+SELECT to_char(date_trunc('day', created_at), 'YYYY-MM-DD')::text AS day, COUNT(*) AS signups
+FROM users
+WHERE created_at >= $1::timestamp AND deleted_at IS NULL
+GROUP BY day
+ORDER BY day
+`
+
+type SignupsByDayRow struct {
+	Day     string `json:"day"`
+	Signups int64  `json:"signups"`
+}
+
+func (q *Queries) SignupsByDay(ctx context.Context, since pgtype.Timestamp) ([]SignupsByDayRow, error) {
+	rows, err := q.db.Query(ctx, signupsByDay, since)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []User
+	var items []SignupsByDayRow
 	for rows.Next() {
-		var i User
-		if err := rows.Scan(&i.ID, &i.Name, &i.Dob); err != nil {
+		var i SignupsByDayRow
+		if err := rows.Scan(&i.Day, &i.Signups); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -85,28 +3308,177 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
-const listUsersPaginated = `-- name: ListUsersPaginated :many
-SELECT id, name, dob 
-FROM users 
-ORDER BY id
-LIMIT $1 OFFSET $2
+const signupsByMonth = `-- name: SignupsByMonth :many
+SELECT to_char(date_trunc('month', created_at), 'YYYY-MM')::text AS month, COUNT(*) AS signups
+FROM users
+WHERE created_at >= $1::timestamp AND deleted_at IS NULL
+GROUP BY month
+ORDER BY month
 `
 
-type ListUsersPaginatedParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+type SignupsByMonthRow struct {
+	Month   string `json:"month"`
+	Signups int64  `json:"signups"`
 }
 
-func (q *Queries) ListUsersPaginated(ctx context.Context, arg ListUsersPaginatedParams) ([]User, error) {
-	rows, err := q.db.Query(ctx, listUsersPaginated, arg.Limit, arg.Offset)
+func (q *Queries) SignupsByMonth(ctx context.Context, since pgtype.Timestamp) ([]SignupsByMonthRow, error) {
+	rows, err := q.db.Query(ctx, signupsByMonth, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SignupsByMonthRow
+	for rows.Next() {
+		var i SignupsByMonthRow
+		if err := rows.Scan(&i.Month, &i.Signups); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const signupsBySource = `-- name: SignupsBySource :many
+SELECT signup_source, COUNT(*) AS signups
+FROM users
+WHERE created_at >= $1::timestamp AND deleted_at IS NULL
+GROUP BY signup_source
+ORDER BY signups DESC, signup_source
+`
+
+type SignupsBySourceRow struct {
+	SignupSource string `json:"signup_source"`
+	Signups      int64  `json:"signups"`
+}
+
+func (q *Queries) SignupsBySource(ctx context.Context, since pgtype.Timestamp) ([]SignupsBySourceRow, error) {
+	rows, err := q.db.Query(ctx, signupsBySource, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SignupsBySourceRow
+	for rows.Next() {
+		var i SignupsBySourceRow
+		if err := rows.Scan(&i.SignupSource, &i.Signups); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteUser(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.Exec(ctx, softDeleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const takeRateLimit = `-- name: TakeRateLimit :one
+INSERT INTO rate_limit_counters (client, window_start, hits)
+VALUES ($1, $2::timestamp, 1)
+ON CONFLICT (client) DO UPDATE SET
+    window_start = CASE WHEN rate_limit_counters.window_start <= $3::timestamp THEN EXCLUDED.window_start ELSE rate_limit_counters.window_start END,
+    hits = CASE WHEN rate_limit_counters.window_start <= $3::timestamp THEN 1 ELSE LEAST(rate_limit_counters.hits + 1, $4::int + 1) END
+RETURNING hits, window_start
+`
+
+type TakeRateLimitParams struct {
+	Client        string           `json:"client"`
+	Now           pgtype.Timestamp `json:"now"`
+	ExpiredBefore pgtype.Timestamp `json:"expired_before"`
+	MaxHits       int32            `json:"max_hits"`
+}
+
+type TakeRateLimitRow struct {
+	Hits        int32            `json:"hits"`
+	WindowStart pgtype.Timestamp `json:"window_start"`
+}
+
+func (q *Queries) TakeRateLimit(ctx context.Context, arg TakeRateLimitParams) (TakeRateLimitRow, error) {
+	row := q.db.QueryRow(ctx, takeRateLimit,
+		arg.Client,
+		arg.Now,
+		arg.ExpiredBefore,
+		arg.MaxHits,
+	)
+	var i TakeRateLimitRow
+	err := row.Scan(&i.Hits, &i.WindowStart)
+	return i, err
+}
+
+const takeSSOPendingLogin = `-- name: TakeSSOPendingLogin :one
+DELETE FROM sso_pending_logins WHERE state_hash = $1
+RETURNING state_hash, nonce, code_verifier, expires_at
+`
+
+func (q *Queries) TakeSSOPendingLogin(ctx context.Context, stateHash string) (SsoPendingLogin, error) {
+	row := q.db.QueryRow(ctx, takeSSOPendingLogin, stateHash)
+	var i SsoPendingLogin
+	err := row.Scan(
+		&i.StateHash,
+		&i.Nonce,
+		&i.CodeVerifier,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const topAPIUsage = `-- name: TopAPIUsage :many
+SELECT u.public_id, u.name, a.api_key_id, COALESCE(k.name, '')::text AS key_name, SUM(a.requests)::bigint AS requests
+FROM api_usage a
+JOIN users u ON u.id = a.user_id
+LEFT JOIN api_keys k ON k.id = a.api_key_id
+WHERE a.day >= $1::date AND a.day <= $2::date
+GROUP BY u.public_id, u.name, a.api_key_id, k.name
+ORDER BY requests DESC, u.public_id, a.api_key_id
+LIMIT $3
+`
+
+type TopAPIUsageParams struct {
+	FromDay  pgtype.Date `json:"from_day"`
+	ToDay    pgtype.Date `json:"to_day"`
+	RowLimit int32       `json:"row_limit"`
+}
+
+type TopAPIUsageRow struct {
+	PublicID pgtype.UUID `json:"public_id"`
+	Name     string      `json:"name"`
+	ApiKeyID int64       `json:"api_key_id"`
+	KeyName  string      `json:"key_name"`
+	Requests int64       `json:"requests"`
+}
+
+func (q *Queries) TopAPIUsage(ctx context.Context, arg TopAPIUsageParams) ([]TopAPIUsageRow, error) {
+	rows, err := q.db.Query(ctx, topAPIUsage, arg.FromDay, arg.ToDay, arg.RowLimit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []User
+	var items []TopAPIUsageRow
 	for rows.Next() {
-		var i User
-		if err := rows.Scan(&i.ID, &i.Name, &i.Dob); err != nil {
+		var i TopAPIUsageRow
+		if err := rows.Scan(
+			&i.PublicID,
+			&i.Name,
+			&i.ApiKeyID,
+			&i.KeyName,
+			&i.Requests,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -117,22 +3489,309 @@ func (q *Queries) ListUsersPaginated(ctx context.Context, arg ListUsersPaginated
 	return items, nil
 }
 
+const touchAPIKey = `-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+func (q *Queries) TouchAPIKey(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, touchAPIKey, id)
+	return err
+}
+
+const updateNotificationRule = `-- name: UpdateNotificationRule :execrows
+UPDATE notification_rules
+SET event_type = $2, channel = $3, audience = $4, template = $5, webhook_url = $6, enabled = $7, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+type UpdateNotificationRuleParams struct {
+	ID         int64  `json:"id"`
+	EventType  string `json:"event_type"`
+	Channel    string `json:"channel"`
+	Audience   string `json:"audience"`
+	Template   string `json:"template"`
+	WebhookUrl string `json:"webhook_url"`
+	Enabled    bool   `json:"enabled"`
+}
+
+func (q *Queries) UpdateNotificationRule(ctx context.Context, arg UpdateNotificationRuleParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateNotificationRule,
+		arg.ID,
+		arg.EventType,
+		arg.Channel,
+		arg.Audience,
+		arg.Template,
+		arg.WebhookUrl,
+		arg.Enabled,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updateUser = `-- name: UpdateUser :one
-UPDATE users 
-SET name = $2, dob = $3 
-WHERE id = $1 
-RETURNING id, name, dob
+UPDATE users
+SET name = $2, dob = $3, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
 `
 
 type UpdateUserParams struct {
-	ID   int32       `json:"id"`
+	ID   int64       `json:"id"`
 	Name string      `json:"name"`
 	Dob  pgtype.Date `json:"dob"`
 }
 
-func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error) {
+type UpdateUserRow struct {
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Dob          pgtype.Date      `json:"dob"`
+	Email        string           `json:"email"`
+	Role         string           `json:"role"`
+	Active       bool             `json:"active"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	AccountType  string           `json:"account_type"`
+	SignupSource string           `json:"signup_source"`
+	PublicID     pgtype.UUID      `json:"public_id"`
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateUserRow, error) {
 	row := q.db.QueryRow(ctx, updateUser, arg.ID, arg.Name, arg.Dob)
-	var i User
-	err := row.Scan(&i.ID, &i.Name, &i.Dob)
+	var i UpdateUserRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.Email,
+		&i.Role,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AccountType,
+		&i.SignupSource,
+		&i.PublicID,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :one
+UPDATE users
+SET password_hash = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, email, updated_at
+`
+
+type UpdateUserPasswordParams struct {
+	ID           int64  `json:"id"`
+	PasswordHash string `json:"password_hash"`
+}
+
+type UpdateUserPasswordRow struct {
+	ID        int64            `json:"id"`
+	Email     string           `json:"email"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (UpdateUserPasswordRow, error) {
+	row := q.db.QueryRow(ctx, updateUserPassword, arg.ID, arg.PasswordHash)
+	var i UpdateUserPasswordRow
+	err := row.Scan(&i.ID, &i.Email, &i.UpdatedAt)
+	return i, err
+}
+
+const updateUserRole = `-- name: UpdateUserRole :one
+UPDATE users
+SET role = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+`
+
+type UpdateUserRoleParams struct {
+	ID   int64  `json:"id"`
+	Role string `json:"role"`
+}
+
+type UpdateUserRoleRow struct {
+	ID           int64            `json:"id"`
+	Name         string           `json:"name"`
+	Dob          pgtype.Date      `json:"dob"`
+	Email        string           `json:"email"`
+	Role         string           `json:"role"`
+	Active       bool             `json:"active"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	AccountType  string           `json:"account_type"`
+	SignupSource string           `json:"signup_source"`
+	PublicID     pgtype.UUID      `json:"public_id"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (UpdateUserRoleRow, error) {
+	row := q.db.QueryRow(ctx, updateUserRole, arg.ID, arg.Role)
+	var i UpdateUserRoleRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.Email,
+		&i.Role,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AccountType,
+		&i.SignupSource,
+		&i.PublicID,
+	)
+	return i, err
+}
+
+const updateWebAuthnCredentialUse = `-- name: UpdateWebAuthnCredentialUse :exec
+UPDATE webauthn_credentials
+SET sign_count = $2, last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1
+`
+
+type UpdateWebAuthnCredentialUseParams struct {
+	ID        int64 `json:"id"`
+	SignCount int64 `json:"sign_count"`
+}
+
+func (q *Queries) UpdateWebAuthnCredentialUse(ctx context.Context, arg UpdateWebAuthnCredentialUseParams) error {
+	_, err := q.db.Exec(ctx, updateWebAuthnCredentialUse, arg.ID, arg.SignCount)
+	return err
+}
+
+const upsertOrganizationBilling = `-- name: UpsertOrganizationBilling :exec
+INSERT INTO organization_billing (org_id, provider, customer_id, subscription_id, plan, status, seats, current_period_end, event_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (org_id) DO UPDATE SET
+    provider = EXCLUDED.provider,
+    customer_id = EXCLUDED.customer_id,
+    subscription_id = EXCLUDED.subscription_id,
+    plan = EXCLUDED.plan,
+    status = EXCLUDED.status,
+    seats = EXCLUDED.seats,
+    current_period_end = EXCLUDED.current_period_end,
+    event_at = EXCLUDED.event_at,
+    updated_at = CURRENT_TIMESTAMP
+WHERE organization_billing.event_at <= EXCLUDED.event_at
+`
+
+type UpsertOrganizationBillingParams struct {
+	OrgID            int64            `json:"org_id"`
+	Provider         string           `json:"provider"`
+	CustomerID       string           `json:"customer_id"`
+	SubscriptionID   string           `json:"subscription_id"`
+	Plan             string           `json:"plan"`
+	Status           string           `json:"status"`
+	Seats            int64            `json:"seats"`
+	CurrentPeriodEnd pgtype.Timestamp `json:"current_period_end"`
+	EventAt          pgtype.Timestamp `json:"event_at"`
+}
+
+func (q *Queries) UpsertOrganizationBilling(ctx context.Context, arg UpsertOrganizationBillingParams) error {
+	_, err := q.db.Exec(ctx, upsertOrganizationBilling,
+		arg.OrgID,
+		arg.Provider,
+		arg.CustomerID,
+		arg.SubscriptionID,
+		arg.Plan,
+		arg.Status,
+		arg.Seats,
+		arg.CurrentPeriodEnd,
+		arg.EventAt,
+	)
+	return err
+}
+
+const upsertUserProfile = `-- name: UpsertUserProfile :one
+INSERT INTO user_profiles (user_id, bio, phone, avatar_url, locale, timezone)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (user_id) DO UPDATE
+SET bio = EXCLUDED.bio, phone = EXCLUDED.phone, avatar_url = EXCLUDED.avatar_url,
+    locale = EXCLUDED.locale, timezone = EXCLUDED.timezone, updated_at = CURRENT_TIMESTAMP
+RETURNING user_id, bio, phone, avatar_url, locale, timezone, updated_at, avatar_key
+`
+
+type UpsertUserProfileParams struct {
+	UserID    int64  `json:"user_id"`
+	Bio       string `json:"bio"`
+	Phone     string `json:"phone"`
+	AvatarUrl string `json:"avatar_url"`
+	Locale    string `json:"locale"`
+	Timezone  string `json:"timezone"`
+}
+
+func (q *Queries) UpsertUserProfile(ctx context.Context, arg UpsertUserProfileParams) (UserProfile, error) {
+	row := q.db.QueryRow(ctx, upsertUserProfile,
+		arg.UserID,
+		arg.Bio,
+		arg.Phone,
+		arg.AvatarUrl,
+		arg.Locale,
+		arg.Timezone,
+	)
+	var i UserProfile
+	err := row.Scan(
+		&i.UserID,
+		&i.Bio,
+		&i.Phone,
+		&i.AvatarUrl,
+		&i.Locale,
+		&i.Timezone,
+		&i.UpdatedAt,
+		&i.AvatarKey,
+	)
 	return i, err
 }
+
+const usersByAgeBracket = `-- name: UsersByAgeBracket :many
+SELECT bracket::text AS bracket, COUNT(*) AS user_count
+FROM (
+    SELECT CASE
+        WHEN age < 18 THEN 'under_18'
+        WHEN age < 25 THEN '18_24'
+        WHEN age < 35 THEN '25_34'
+        WHEN age < 45 THEN '35_44'
+        WHEN age < 55 THEN '45_54'
+        WHEN age < 65 THEN '55_64'
+        ELSE '65_plus'
+    END AS bracket,
+    age
+    FROM (
+        SELECT date_part('year', age(CURRENT_DATE, dob))::int AS age
+        FROM users
+        WHERE deleted_at IS NULL
+    ) ages
+) brackets
+GROUP BY bracket
+ORDER BY MIN(age)
+`
+
+type UsersByAgeBracketRow struct {
+	Bracket   string `json:"bracket"`
+	UserCount int64  `json:"user_count"`
+}
+
+func (q *Queries) UsersByAgeBracket(ctx context.Context) ([]UsersByAgeBracketRow, error) {
+	rows, err := q.db.Query(ctx, usersByAgeBracket)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UsersByAgeBracketRow
+	for rows.Next() {
+		var i UsersByAgeBracketRow
+		if err := rows.Scan(&i.Bracket, &i.UserCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,259 @@
+package mysqlgen
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ApiKey struct {
+	ID         int64        `json:"id"`
+	UserID     int64        `json:"user_id"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix"`
+	KeyHash    string       `json:"key_hash"`
+	Scopes     string       `json:"scopes"`
+	CreatedAt  time.Time    `json:"created_at"`
+	ExpiresAt  sql.NullTime `json:"expires_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+}
+
+type ApiUsage struct {
+	UserID   int64     `json:"user_id"`
+	ApiKeyID int64     `json:"api_key_id"`
+	Day      time.Time `json:"day"`
+	Requests int64     `json:"requests"`
+}
+
+type EmailDeadLetter struct {
+	ID            int64     `json:"id"`
+	Recipients    string    `json:"recipients"`
+	Subject       string    `json:"subject"`
+	Html          string    `json:"html"`
+	Error         string    `json:"error"`
+	Attempts      int32     `json:"attempts"`
+	CreatedAt     time.Time `json:"created_at"`
+	LastAttemptAt time.Time `json:"last_attempt_at"`
+}
+
+type JobLease struct {
+	Name      string    `json:"name"`
+	Holder    string    `json:"holder"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type LoginHistory struct {
+	ID        int64         `json:"id"`
+	UserID    sql.NullInt64 `json:"user_id"`
+	Email     string        `json:"email"`
+	Succeeded bool          `json:"succeeded"`
+	Reason    string        `json:"reason"`
+	IpAddress string        `json:"ip_address"`
+	UserAgent string        `json:"user_agent"`
+	CreatedAt time.Time     `json:"created_at"`
+	Country   string        `json:"country"`
+	City      string        `json:"city"`
+}
+
+type LoginLockout struct {
+	LockKey     string       `json:"lock_key"`
+	Failures    int32        `json:"failures"`
+	WindowStart time.Time    `json:"window_start"`
+	LockedUntil sql.NullTime `json:"locked_until"`
+}
+
+type MagicLinkRedemption struct {
+	TokenHash string    `json:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type NameReview struct {
+	ID         int64         `json:"id"`
+	UserID     int64         `json:"user_id"`
+	Name       string        `json:"name"`
+	Matched    string        `json:"matched"`
+	Status     string        `json:"status"`
+	ReviewedBy sql.NullInt64 `json:"reviewed_by"`
+	CreatedAt  time.Time     `json:"created_at"`
+	ReviewedAt sql.NullTime  `json:"reviewed_at"`
+}
+
+type Notification struct {
+	ID        int64        `json:"id"`
+	UserID    int64        `json:"user_id"`
+	EventType string       `json:"event_type"`
+	Title     string       `json:"title"`
+	Details   string       `json:"details"`
+	CreatedAt time.Time    `json:"created_at"`
+	ReadAt    sql.NullTime `json:"read_at"`
+}
+
+type NotificationPreference struct {
+	UserID    int64  `json:"user_id"`
+	EventType string `json:"event_type"`
+	Channel   string `json:"channel"`
+	Enabled   bool   `json:"enabled"`
+}
+
+type NotificationRule struct {
+	ID         int64     `json:"id"`
+	EventType  string    `json:"event_type"`
+	Channel    string    `json:"channel"`
+	Audience   string    `json:"audience"`
+	Template   string    `json:"template"`
+	WebhookUrl string    `json:"webhook_url"`
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type Organization struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OrganizationBilling struct {
+	OrgID            int64        `json:"org_id"`
+	Provider         string       `json:"provider"`
+	CustomerID       string       `json:"customer_id"`
+	SubscriptionID   string       `json:"subscription_id"`
+	Plan             string       `json:"plan"`
+	Status           string       `json:"status"`
+	Seats            int64        `json:"seats"`
+	CurrentPeriodEnd sql.NullTime `json:"current_period_end"`
+	EventAt          time.Time    `json:"event_at"`
+	UpdatedAt        time.Time    `json:"updated_at"`
+}
+
+type OrganizationMember struct {
+	UserID    int64     `json:"user_id"`
+	OrgID     int64     `json:"org_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OrganizationUsage struct {
+	OrgID        int64     `json:"org_id"`
+	Day          time.Time `json:"day"`
+	Seats        int64     `json:"seats"`
+	ApiCalls     int64     `json:"api_calls"`
+	StorageBytes int64     `json:"storage_bytes"`
+}
+
+type RateLimitCounter struct {
+	Client      string    `json:"client"`
+	WindowStart time.Time `json:"window_start"`
+	Hits        int32     `json:"hits"`
+}
+
+type Referral struct {
+	ID         int64     `json:"id"`
+	ReferrerID int64     `json:"referrer_id"`
+	ReferredID int64     `json:"referred_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ReferralCode struct {
+	UserID    int64     `json:"user_id"`
+	Code      string    `json:"code"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RefreshToken struct {
+	ID        int64        `json:"id"`
+	UserID    int64        `json:"user_id"`
+	TokenHash string       `json:"token_hash"`
+	Family    string       `json:"family"`
+	CreatedAt time.Time    `json:"created_at"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	RevokedAt sql.NullTime `json:"revoked_at"`
+}
+
+type RevokedToken struct {
+	Jti       string    `json:"jti"`
+	UserID    int64     `json:"user_id"`
+	RevokedAt time.Time `json:"revoked_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type SecurityEvent struct {
+	ID        int64         `json:"id"`
+	EventType string        `json:"event_type"`
+	ActorID   sql.NullInt64 `json:"actor_id"`
+	TargetID  sql.NullInt64 `json:"target_id"`
+	Details   string        `json:"details"`
+	IpAddress string        `json:"ip_address"`
+	CreatedAt time.Time     `json:"created_at"`
+	PrevHash  string        `json:"prev_hash"`
+	Hash      string        `json:"hash"`
+}
+
+type Session struct {
+	ID        string       `json:"id"`
+	UserID    int64        `json:"user_id"`
+	IpAddress string       `json:"ip_address"`
+	UserAgent string       `json:"user_agent"`
+	CreatedAt time.Time    `json:"created_at"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	RevokedAt sql.NullTime `json:"revoked_at"`
+}
+
+type SsoPendingLogin struct {
+	StateHash    string    `json:"state_hash"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"code_verifier"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+type TokenRevocation struct {
+	UserID    int64     `json:"user_id"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+type User struct {
+	ID           int64          `json:"id"`
+	Name         string         `json:"name"`
+	Dob          time.Time      `json:"dob"`
+	Email        string         `json:"email"`
+	PasswordHash string         `json:"password_hash"`
+	Role         string         `json:"role"`
+	Active       bool           `json:"active"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	AccountType  string         `json:"account_type"`
+	SignupSource string         `json:"signup_source"`
+	PublicID     string         `json:"public_id"`
+	DeletedAt    sql.NullTime   `json:"deleted_at"`
+	LiveEmail    sql.NullString `json:"live_email"`
+}
+
+type UserIdentity struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type UserProfile struct {
+	UserID    int64     `json:"user_id"`
+	Bio       string    `json:"bio"`
+	Phone     string    `json:"phone"`
+	AvatarUrl string    `json:"avatar_url"`
+	Locale    string    `json:"locale"`
+	Timezone  string    `json:"timezone"`
+	UpdatedAt time.Time `json:"updated_at"`
+	AvatarKey string    `json:"avatar_key"`
+}
+
+type WebauthnCredential struct {
+	ID           int64        `json:"id"`
+	UserID       int64        `json:"user_id"`
+	CredentialID []byte       `json:"credential_id"`
+	PublicKey    []byte       `json:"public_key"`
+	SignCount    int64        `json:"sign_count"`
+	Name         string       `json:"name"`
+	CreatedAt    time.Time    `json:"created_at"`
+	LastUsedAt   sql.NullTime `json:"last_used_at"`
+}
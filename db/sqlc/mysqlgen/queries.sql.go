@@ -0,0 +1,3743 @@
+package mysqlgen
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const acquireJobLease = `-- name: AcquireJobLease :exec
+INSERT INTO job_leases (name, holder, expires_at)
+VALUES (?, ?, TIMESTAMPADD(MICROSECOND, ?, CURRENT_TIMESTAMP(6)))
+ON DUPLICATE KEY UPDATE
+    holder = IF(holder = VALUES(holder) OR expires_at < CURRENT_TIMESTAMP(6), VALUES(holder), holder),
+    expires_at = IF(holder = VALUES(holder), VALUES(expires_at), expires_at)
+`
+
+type AcquireJobLeaseParams struct {
+	Name      string `json:"name"`
+	Holder    string `json:"holder"`
+	TtlMicros int64  `json:"ttl_micros"`
+}
+
+func (q *Queries) AcquireJobLease(ctx context.Context, arg AcquireJobLeaseParams) error {
+	_, err := q.db.ExecContext(ctx, acquireJobLease, arg.Name, arg.Holder, arg.TtlMicros)
+	return err
+}
+
+const addAPIUsage = `-- name: AddAPIUsage :exec
+INSERT INTO api_usage (user_id, api_key_id, day, requests)
+VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE requests = api_usage.requests + VALUES(requests)
+`
+
+type AddAPIUsageParams struct {
+	UserID   int64     `json:"user_id"`
+	ApiKeyID int64     `json:"api_key_id"`
+	Day      time.Time `json:"day"`
+	Requests int64     `json:"requests"`
+}
+
+func (q *Queries) AddAPIUsage(ctx context.Context, arg AddAPIUsageParams) error {
+	_, err := q.db.ExecContext(ctx, addAPIUsage,
+		arg.UserID,
+		arg.ApiKeyID,
+		arg.Day,
+		arg.Requests,
+	)
+	return err
+}
+
+const addOrganizationAPICalls = `-- name: AddOrganizationAPICalls :exec
+INSERT INTO organization_usage (org_id, day, api_calls)
+SELECT org_id, ?, ?
+FROM organization_members
+WHERE user_id = ?
+ON DUPLICATE KEY UPDATE api_calls = organization_usage.api_calls + VALUES(api_calls)
+`
+
+type AddOrganizationAPICallsParams struct {
+	Day    interface{} `json:"day"`
+	Calls  interface{} `json:"calls"`
+	UserID int64       `json:"user_id"`
+}
+
+func (q *Queries) AddOrganizationAPICalls(ctx context.Context, arg AddOrganizationAPICallsParams) error {
+	_, err := q.db.ExecContext(ctx, addOrganizationAPICalls, arg.Day, arg.Calls, arg.UserID)
+	return err
+}
+
+const ageDistribution = `-- name: AgeDistribution :many
+SELECT grp, CAST(FLOOR(age / size) * size AS SIGNED) AS bucket_start, COUNT(*) AS user_count
+FROM (
+    SELECT CASE CAST(? AS CHAR)
+        WHEN 'role' THEN role
+        WHEN 'signup_cohort' THEN DATE_FORMAT(created_at, '%Y-%m')
+        ELSE ''
+    END AS grp,
+    TIMESTAMPDIFF(YEAR, dob, CURDATE()) AS age,
+    CAST(? AS SIGNED) AS size
+    FROM users
+    WHERE deleted_at IS NULL
+) ages
+GROUP BY grp, bucket_start
+ORDER BY grp, bucket_start
+`
+
+type AgeDistributionParams struct {
+	GroupBy    string `json:"group_by"`
+	BucketSize int32  `json:"bucket_size"`
+}
+
+type AgeDistributionRow struct {
+	Grp         string `json:"grp"`
+	BucketStart int64  `json:"bucket_start"`
+	UserCount   int64  `json:"user_count"`
+}
+
+func (q *Queries) AgeDistribution(ctx context.Context, arg AgeDistributionParams) ([]AgeDistributionRow, error) {
+	rows, err := q.db.QueryContext(ctx, ageDistribution, arg.GroupBy, arg.BucketSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AgeDistributionRow
+	for rows.Next() {
+		var i AgeDistributionRow
+		if err := rows.Scan(&i.Grp, &i.BucketStart, &i.UserCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const aggregateOrganizationUsage = `-- name: AggregateOrganizationUsage :exec
+INSERT INTO organization_usage (org_id, day, seats, storage_bytes)
+SELECT o.id, ?,
+    COALESCE(SUM(u.active AND u.account_type <> 'service'), 0),
+    COUNT(u.id) * (
+        SELECT COALESCE(MAX(AVG_ROW_LENGTH), 0)
+        FROM information_schema.TABLES
+        WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'users'
+    ) + (
+        SELECT COUNT(*)
+        FROM login_history l
+        JOIN organization_members lm ON lm.user_id = l.user_id
+        WHERE lm.org_id = o.id
+    ) * (
+        SELECT COALESCE(MAX(AVG_ROW_LENGTH), 0)
+        FROM information_schema.TABLES
+        WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'login_history'
+    )
+FROM organizations o
+LEFT JOIN organization_members m ON m.org_id = o.id
+LEFT JOIN users u ON u.id = m.user_id AND u.deleted_at IS NULL
+GROUP BY o.id
+ON DUPLICATE KEY UPDATE seats = VALUES(seats), storage_bytes = VALUES(storage_bytes)
+`
+
+func (q *Queries) AggregateOrganizationUsage(ctx context.Context, day interface{}) error {
+	_, err := q.db.ExecContext(ctx, aggregateOrganizationUsage, day)
+	return err
+}
+
+const appendSecurityEvent = `-- name: AppendSecurityEvent :execlastid
+INSERT INTO security_events (event_type, actor_id, target_id, details, ip_address, created_at, prev_hash, hash)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type AppendSecurityEventParams struct {
+	EventType string        `json:"event_type"`
+	ActorID   sql.NullInt64 `json:"actor_id"`
+	TargetID  sql.NullInt64 `json:"target_id"`
+	Details   string        `json:"details"`
+	IpAddress string        `json:"ip_address"`
+	CreatedAt time.Time     `json:"created_at"`
+	PrevHash  string        `json:"prev_hash"`
+	Hash      string        `json:"hash"`
+}
+
+func (q *Queries) AppendSecurityEvent(ctx context.Context, arg AppendSecurityEventParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, appendSecurityEvent,
+		arg.EventType,
+		arg.ActorID,
+		arg.TargetID,
+		arg.Details,
+		arg.IpAddress,
+		arg.CreatedAt,
+		arg.PrevHash,
+		arg.Hash,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+const clearLoginLockout = `-- name: ClearLoginLockout :execrows
+DELETE FROM login_lockouts WHERE lock_key = ?
+`
+
+func (q *Queries) ClearLoginLockout(ctx context.Context, lockKey string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, clearLoginLockout, lockKey)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countOrganizationSeats = `-- name: CountOrganizationSeats :one
+SELECT COUNT(*)
+FROM organization_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.org_id = ? AND m.user_id <> ? AND u.active AND u.account_type <> 'service' AND u.deleted_at IS NULL
+`
+
+type CountOrganizationSeatsParams struct {
+	OrgID        int64 `json:"org_id"`
+	ExceptUserID int64 `json:"except_user_id"`
+}
+
+func (q *Queries) CountOrganizationSeats(ctx context.Context, arg CountOrganizationSeatsParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countOrganizationSeats, arg.OrgID, arg.ExceptUserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchUsers = `-- name: CountSearchUsers :one
+SELECT COUNT(*)
+FROM users
+WHERE deleted_at IS NULL
+    AND (? IS NULL OR LOWER(name) LIKE LOWER(?))
+    AND (? IS NULL OR LOWER(email) = LOWER(?))
+    AND (? IS NULL OR role = ?)
+    AND (? IS NULL OR signup_source = ?)
+    AND (? IS NULL OR dob > ?)
+    AND (? IS NULL OR dob <= ?)
+    AND (? IS NULL OR created_at >= ?)
+    AND (? IS NULL OR created_at < ?)
+    AND (? IS NULL OR id IN (SELECT user_id FROM organization_members WHERE org_id = ?))
+`
+
+type CountSearchUsersParams struct {
+	NamePattern   sql.NullString `json:"name_pattern"`
+	Email         sql.NullString `json:"email"`
+	Role          sql.NullString `json:"role"`
+	SignupSource  sql.NullString `json:"signup_source"`
+	DobAfter      sql.NullTime   `json:"dob_after"`
+	DobUntil      sql.NullTime   `json:"dob_until"`
+	CreatedFrom   sql.NullTime   `json:"created_from"`
+	CreatedBefore sql.NullTime   `json:"created_before"`
+	OrgID         sql.NullInt64  `json:"org_id"`
+}
+
+func (q *Queries) CountSearchUsers(ctx context.Context, arg CountSearchUsersParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countSearchUsers,
+		arg.NamePattern,
+		arg.NamePattern,
+		arg.Email,
+		arg.Email,
+		arg.Role,
+		arg.Role,
+		arg.SignupSource,
+		arg.SignupSource,
+		arg.DobAfter,
+		arg.DobAfter,
+		arg.DobUntil,
+		arg.DobUntil,
+		arg.CreatedFrom,
+		arg.CreatedFrom,
+		arg.CreatedBefore,
+		arg.CreatedBefore,
+		arg.OrgID,
+		arg.OrgID,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countUsers = `-- name: CountUsers :one
+SELECT COUNT(*)
+FROM users
+WHERE deleted_at IS NULL
+`
+
+func (q *Queries) CountUsers(ctx context.Context) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUsers)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAPIKey = `-- name: CreateAPIKey :execlastid
+INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+type CreateAPIKeyParams struct {
+	UserID    int64        `json:"user_id"`
+	Name      string       `json:"name"`
+	Prefix    string       `json:"prefix"`
+	KeyHash   string       `json:"key_hash"`
+	Scopes    string       `json:"scopes"`
+	ExpiresAt sql.NullTime `json:"expires_at"`
+}
+
+func (q *Queries) CreateAPIKey(ctx context.Context, arg CreateAPIKeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createAPIKey,
+		arg.UserID,
+		arg.Name,
+		arg.Prefix,
+		arg.KeyHash,
+		arg.Scopes,
+		arg.ExpiresAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+const createEmailDeadLetter = `-- name: CreateEmailDeadLetter :execlastid
+INSERT INTO email_dead_letters (recipients, subject, html, error, attempts)
+VALUES (?, ?, ?, ?, ?)
+`
+
+type CreateEmailDeadLetterParams struct {
+	Recipients string `json:"recipients"`
+	Subject    string `json:"subject"`
+	Html       string `json:"html"`
+	Error      string `json:"error"`
+	Attempts   int32  `json:"attempts"`
+}
+
+func (q *Queries) CreateEmailDeadLetter(ctx context.Context, arg CreateEmailDeadLetterParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createEmailDeadLetter,
+		arg.Recipients,
+		arg.Subject,
+		arg.Html,
+		arg.Error,
+		arg.Attempts,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+const createNameReview = `-- name: CreateNameReview :execlastid
+INSERT INTO name_reviews (user_id, name, matched)
+VALUES (?, ?, ?)
+`
+
+type CreateNameReviewParams struct {
+	UserID  int64  `json:"user_id"`
+	Name    string `json:"name"`
+	Matched string `json:"matched"`
+}
+
+func (q *Queries) CreateNameReview(ctx context.Context, arg CreateNameReviewParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createNameReview, arg.UserID, arg.Name, arg.Matched)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+const createNotification = `-- name: CreateNotification :exec
+INSERT INTO notifications (user_id, event_type, title, details)
+VALUES (?, ?, ?, ?)
+`
+
+type CreateNotificationParams struct {
+	UserID    int64  `json:"user_id"`
+	EventType string `json:"event_type"`
+	Title     string `json:"title"`
+	Details   string `json:"details"`
+}
+
+func (q *Queries) CreateNotification(ctx context.Context, arg CreateNotificationParams) error {
+	_, err := q.db.ExecContext(ctx, createNotification,
+		arg.UserID,
+		arg.EventType,
+		arg.Title,
+		arg.Details,
+	)
+	return err
+}
+
+const createNotificationRule = `-- name: CreateNotificationRule :execlastid
+INSERT INTO notification_rules (event_type, channel, audience, template, webhook_url, enabled)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+type CreateNotificationRuleParams struct {
+	EventType  string `json:"event_type"`
+	Channel    string `json:"channel"`
+	Audience   string `json:"audience"`
+	Template   string `json:"template"`
+	WebhookUrl string `json:"webhook_url"`
+	Enabled    bool   `json:"enabled"`
+}
+
+func (q *Queries) CreateNotificationRule(ctx context.Context, arg CreateNotificationRuleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createNotificationRule,
+		arg.EventType,
+		arg.Channel,
+		arg.Audience,
+		arg.Template,
+		arg.WebhookUrl,
+		arg.Enabled,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+const createOrganization = `-- name: CreateOrganization :execlastid
+INSERT INTO organizations (name)
+VALUES (?)
+`
+
+func (q *Queries) CreateOrganization(ctx context.Context, name string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createOrganization, name)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+const createReferral = `-- name: CreateReferral :execrows
+INSERT IGNORE INTO referrals (referrer_id, referred_id)
+VALUES (?, ?)
+`
+
+type CreateReferralParams struct {
+	ReferrerID int64 `json:"referrer_id"`
+	ReferredID int64 `json:"referred_id"`
+}
+
+func (q *Queries) CreateReferral(ctx context.Context, arg CreateReferralParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createReferral, arg.ReferrerID, arg.ReferredID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createReferralCode = `-- name: CreateReferralCode :execrows
+INSERT IGNORE INTO referral_codes (user_id, code)
+VALUES (?, ?)
+`
+
+type CreateReferralCodeParams struct {
+	UserID int64  `json:"user_id"`
+	Code   string `json:"code"`
+}
+
+func (q *Queries) CreateReferralCode(ctx context.Context, arg CreateReferralCodeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createReferralCode, arg.UserID, arg.Code)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const createRefreshToken = `-- name: CreateRefreshToken :exec
+INSERT INTO refresh_tokens (user_id, token_hash, family, created_at, expires_at)
+VALUES (?, ?, ?, ?, ?)
+`
+
+type CreateRefreshTokenParams struct {
+	UserID    int64     `json:"user_id"`
+	TokenHash string    `json:"token_hash"`
+	Family    string    `json:"family"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateRefreshToken(ctx context.Context, arg CreateRefreshTokenParams) error {
+	_, err := q.db.ExecContext(ctx, createRefreshToken,
+		arg.UserID,
+		arg.TokenHash,
+		arg.Family,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const createSSOPendingLogin = `-- name: CreateSSOPendingLogin :exec
+INSERT INTO sso_pending_logins (state_hash, nonce, code_verifier, expires_at)
+VALUES (?, ?, ?, ?)
+`
+
+type CreateSSOPendingLoginParams struct {
+	StateHash    string    `json:"state_hash"`
+	Nonce        string    `json:"nonce"`
+	CodeVerifier string    `json:"code_verifier"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateSSOPendingLogin(ctx context.Context, arg CreateSSOPendingLoginParams) error {
+	_, err := q.db.ExecContext(ctx, createSSOPendingLogin,
+		arg.StateHash,
+		arg.Nonce,
+		arg.CodeVerifier,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const createServiceAccount = `-- name: CreateServiceAccount :execlastid
+INSERT INTO users (name, dob, email, password_hash, role, account_type, signup_source)
+VALUES (?, CURDATE(), ?, ?, ?, 'service', 'admin')
+`
+
+type CreateServiceAccountParams struct {
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	PasswordHash string `json:"password_hash"`
+	Role         string `json:"role"`
+}
+
+func (q *Queries) CreateServiceAccount(ctx context.Context, arg CreateServiceAccountParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createServiceAccount,
+		arg.Name,
+		arg.Email,
+		arg.PasswordHash,
+		arg.Role,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+const createSession = `-- name: CreateSession :exec
+INSERT INTO sessions (id, user_id, ip_address, user_agent, created_at, expires_at)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+type CreateSessionParams struct {
+	ID        string    `json:"id"`
+	UserID    int64     `json:"user_id"`
+	IpAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) error {
+	_, err := q.db.ExecContext(ctx, createSession,
+		arg.ID,
+		arg.UserID,
+		arg.IpAddress,
+		arg.UserAgent,
+		arg.CreatedAt,
+		arg.ExpiresAt,
+	)
+	return err
+}
+
+const createUser = `-- name: CreateUser :execlastid
+INSERT INTO users (name, dob, email, password_hash, role, signup_source)
+VALUES (?, ?, ?, ?, COALESCE(NULLIF(?, ''), 'user'), ?)
+`
+
+type CreateUserParams struct {
+	Name         string    `json:"name"`
+	Dob          time.Time `json:"dob"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"password_hash"`
+	Role         string    `json:"role"`
+	SignupSource string    `json:"signup_source"`
+}
+
+func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, createUser,
+		arg.Name,
+		arg.Dob,
+		arg.Email,
+		arg.PasswordHash,
+		arg.Role,
+		arg.SignupSource,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.LastInsertId()
+}
+
+const createUserIdentity = `-- name: CreateUserIdentity :exec
+INSERT INTO user_identities (user_id, provider, subject, email)
+VALUES (?, ?, ?, ?)
+`
+
+type CreateUserIdentityParams struct {
+	UserID   int64  `json:"user_id"`
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+	Email    string `json:"email"`
+}
+
+func (q *Queries) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) error {
+	_, err := q.db.ExecContext(ctx, createUserIdentity,
+		arg.UserID,
+		arg.Provider,
+		arg.Subject,
+		arg.Email,
+	)
+	return err
+}
+
+const createWebAuthnCredential = `-- name: CreateWebAuthnCredential :exec
+INSERT INTO webauthn_credentials (user_id, credential_id, public_key, sign_count, name)
+VALUES (?, ?, ?, ?, ?)
+`
+
+type CreateWebAuthnCredentialParams struct {
+	UserID       int64  `json:"user_id"`
+	CredentialID []byte `json:"credential_id"`
+	PublicKey    []byte `json:"public_key"`
+	SignCount    int64  `json:"sign_count"`
+	Name         string `json:"name"`
+}
+
+func (q *Queries) CreateWebAuthnCredential(ctx context.Context, arg CreateWebAuthnCredentialParams) error {
+	_, err := q.db.ExecContext(ctx, createWebAuthnCredential,
+		arg.UserID,
+		arg.CredentialID,
+		arg.PublicKey,
+		arg.SignCount,
+		arg.Name,
+	)
+	return err
+}
+
+const deleteEmailDeadLetter = `-- name: DeleteEmailDeadLetter :execrows
+DELETE FROM email_dead_letters
+WHERE id = ?
+`
+
+func (q *Queries) DeleteEmailDeadLetter(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteEmailDeadLetter, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExpiredLoginLockouts = `-- name: DeleteExpiredLoginLockouts :execrows
+DELETE FROM login_lockouts
+WHERE window_start <= ? AND (locked_until IS NULL OR locked_until <= ?)
+`
+
+type DeleteExpiredLoginLockoutsParams struct {
+	ExpiredBefore time.Time    `json:"expired_before"`
+	Now           sql.NullTime `json:"now"`
+}
+
+func (q *Queries) DeleteExpiredLoginLockouts(ctx context.Context, arg DeleteExpiredLoginLockoutsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredLoginLockouts, arg.ExpiredBefore, arg.Now)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExpiredMagicLinkRedemptions = `-- name: DeleteExpiredMagicLinkRedemptions :execrows
+DELETE FROM magic_link_redemptions WHERE expires_at <= ?
+`
+
+func (q *Queries) DeleteExpiredMagicLinkRedemptions(ctx context.Context, expiresAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredMagicLinkRedemptions, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExpiredRateLimits = `-- name: DeleteExpiredRateLimits :execrows
+DELETE FROM rate_limit_counters WHERE window_start <= ?
+`
+
+func (q *Queries) DeleteExpiredRateLimits(ctx context.Context, windowStart time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredRateLimits, windowStart)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteExpiredSSOPendingLogins = `-- name: DeleteExpiredSSOPendingLogins :execrows
+DELETE FROM sso_pending_logins WHERE expires_at <= ?
+`
+
+func (q *Queries) DeleteExpiredSSOPendingLogins(ctx context.Context, expiresAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteExpiredSSOPendingLogins, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteNotificationRule = `-- name: DeleteNotificationRule :execrows
+DELETE FROM notification_rules
+WHERE id = ?
+`
+
+func (q *Queries) DeleteNotificationRule(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteNotificationRule, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteOrganizationMember = `-- name: DeleteOrganizationMember :execrows
+DELETE FROM organization_members
+WHERE user_id = ?
+`
+
+func (q *Queries) DeleteOrganizationMember(ctx context.Context, userID int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOrganizationMember, userID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteSSOPendingLogin = `-- name: DeleteSSOPendingLogin :execrows
+DELETE FROM sso_pending_logins WHERE state_hash = ?
+`
+
+func (q *Queries) DeleteSSOPendingLogin(ctx context.Context, stateHash string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteSSOPendingLogin, stateHash)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteUser = `-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = ?
+`
+
+func (q *Queries) DeleteUser(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteUser, id)
+	return err
+}
+
+const deleteUserIdentity = `-- name: DeleteUserIdentity :execrows
+DELETE FROM user_identities
+WHERE user_id = ? AND provider = ?
+`
+
+type DeleteUserIdentityParams struct {
+	UserID   int64  `json:"user_id"`
+	Provider string `json:"provider"`
+}
+
+func (q *Queries) DeleteUserIdentity(ctx context.Context, arg DeleteUserIdentityParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUserIdentity, arg.UserID, arg.Provider)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteWebAuthnCredential = `-- name: DeleteWebAuthnCredential :execrows
+DELETE FROM webauthn_credentials
+WHERE id = ? AND user_id = ?
+`
+
+type DeleteWebAuthnCredentialParams struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) DeleteWebAuthnCredential(ctx context.Context, arg DeleteWebAuthnCredentialParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteWebAuthnCredential, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const extendSession = `-- name: ExtendSession :exec
+UPDATE sessions
+SET expires_at = ?
+WHERE id = ? AND revoked_at IS NULL
+`
+
+type ExtendSessionParams struct {
+	ExpiresAt time.Time `json:"expires_at"`
+	ID        string    `json:"id"`
+}
+
+func (q *Queries) ExtendSession(ctx context.Context, arg ExtendSessionParams) error {
+	_, err := q.db.ExecContext(ctx, extendSession, arg.ExpiresAt, arg.ID)
+	return err
+}
+
+const failedLoginsByDay = `-- name: FailedLoginsByDay :many
+SELECT DATE_FORMAT(created_at, '%Y-%m-%d') AS day, COUNT(*) AS failures
+FROM login_history
+WHERE succeeded = FALSE AND created_at >= ?
+GROUP BY day
+ORDER BY day
+`
+
+type FailedLoginsByDayRow struct {
+	Day      string `json:"day"`
+	Failures int64  `json:"failures"`
+}
+
+func (q *Queries) FailedLoginsByDay(ctx context.Context, since time.Time) ([]FailedLoginsByDayRow, error) {
+	rows, err := q.db.QueryContext(ctx, failedLoginsByDay, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FailedLoginsByDayRow
+	for rows.Next() {
+		var i FailedLoginsByDayRow
+		if err := rows.Scan(&i.Day, &i.Failures); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAPIKeyByHash = `-- name: GetAPIKeyByHash :one
+SELECT k.id, k.user_id, k.scopes, k.expires_at, k.revoked_at, u.role, u.active, u.account_type
+FROM api_keys k
+JOIN users u ON u.id = k.user_id
+WHERE k.key_hash = ? AND u.deleted_at IS NULL
+`
+
+type GetAPIKeyByHashRow struct {
+	ID          int64        `json:"id"`
+	UserID      int64        `json:"user_id"`
+	Scopes      string       `json:"scopes"`
+	ExpiresAt   sql.NullTime `json:"expires_at"`
+	RevokedAt   sql.NullTime `json:"revoked_at"`
+	Role        string       `json:"role"`
+	Active      bool         `json:"active"`
+	AccountType string       `json:"account_type"`
+}
+
+func (q *Queries) GetAPIKeyByHash(ctx context.Context, keyHash string) (GetAPIKeyByHashRow, error) {
+	row := q.db.QueryRowContext(ctx, getAPIKeyByHash, keyHash)
+	var i GetAPIKeyByHashRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Scopes,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.Role,
+		&i.Active,
+		&i.AccountType,
+	)
+	return i, err
+}
+
+const getAPIKeyByID = `-- name: GetAPIKeyByID :one
+SELECT id, user_id, name, prefix, scopes, created_at, expires_at, last_used_at, revoked_at
+FROM api_keys
+WHERE id = ?
+`
+
+type GetAPIKeyByIDRow struct {
+	ID         int64        `json:"id"`
+	UserID     int64        `json:"user_id"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix"`
+	Scopes     string       `json:"scopes"`
+	CreatedAt  time.Time    `json:"created_at"`
+	ExpiresAt  sql.NullTime `json:"expires_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+}
+
+func (q *Queries) GetAPIKeyByID(ctx context.Context, id int64) (GetAPIKeyByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getAPIKeyByID, id)
+	var i GetAPIKeyByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Prefix,
+		&i.Scopes,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const getDeletedUserByID = `-- name: GetDeletedUserByID :one
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE id = ? AND deleted_at IS NOT NULL
+`
+
+type GetDeletedUserByIDRow struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	Dob          time.Time `json:"dob"`
+	Email        string    `json:"email"`
+	Role         string    `json:"role"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	AccountType  string    `json:"account_type"`
+	SignupSource string    `json:"signup_source"`
+	PublicID     string    `json:"public_id"`
+}
+
+func (q *Queries) GetDeletedUserByID(ctx context.Context, id int64) (GetDeletedUserByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getDeletedUserByID, id)
+	var i GetDeletedUserByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.Email,
+		&i.Role,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AccountType,
+		&i.SignupSource,
+		&i.PublicID,
+	)
+	return i, err
+}
+
+const getEmailDeadLetter = `-- name: GetEmailDeadLetter :one
+SELECT id, recipients, subject, html, error, attempts, created_at, last_attempt_at
+FROM email_dead_letters
+WHERE id = ?
+`
+
+func (q *Queries) GetEmailDeadLetter(ctx context.Context, id int64) (EmailDeadLetter, error) {
+	row := q.db.QueryRowContext(ctx, getEmailDeadLetter, id)
+	var i EmailDeadLetter
+	err := row.Scan(
+		&i.ID,
+		&i.Recipients,
+		&i.Subject,
+		&i.Html,
+		&i.Error,
+		&i.Attempts,
+		&i.CreatedAt,
+		&i.LastAttemptAt,
+	)
+	return i, err
+}
+
+const getJobLeaseHolder = `-- name: GetJobLeaseHolder :one
+SELECT holder FROM job_leases WHERE name = ?
+`
+
+func (q *Queries) GetJobLeaseHolder(ctx context.Context, name string) (string, error) {
+	row := q.db.QueryRowContext(ctx, getJobLeaseHolder, name)
+	var holder string
+	err := row.Scan(&holder)
+	return holder, err
+}
+
+const getLastSecurityEventHash = `-- name: GetLastSecurityEventHash :one
+SELECT hash FROM security_events ORDER BY id DESC LIMIT 1
+`
+
+func (q *Queries) GetLastSecurityEventHash(ctx context.Context) (string, error) {
+	row := q.db.QueryRowContext(ctx, getLastSecurityEventHash)
+	var hash string
+	err := row.Scan(&hash)
+	return hash, err
+}
+
+const getLoginLockout = `-- name: GetLoginLockout :one
+SELECT failures, window_start, locked_until FROM login_lockouts WHERE lock_key = ?
+`
+
+type GetLoginLockoutRow struct {
+	Failures    int32        `json:"failures"`
+	WindowStart time.Time    `json:"window_start"`
+	LockedUntil sql.NullTime `json:"locked_until"`
+}
+
+func (q *Queries) GetLoginLockout(ctx context.Context, lockKey string) (GetLoginLockoutRow, error) {
+	row := q.db.QueryRowContext(ctx, getLoginLockout, lockKey)
+	var i GetLoginLockoutRow
+	err := row.Scan(&i.Failures, &i.WindowStart, &i.LockedUntil)
+	return i, err
+}
+
+const getNameReview = `-- name: GetNameReview :one
+SELECT id, user_id, name, matched, status, reviewed_by, created_at, reviewed_at
+FROM name_reviews
+WHERE id = ?
+`
+
+func (q *Queries) GetNameReview(ctx context.Context, id int64) (NameReview, error) {
+	row := q.db.QueryRowContext(ctx, getNameReview, id)
+	var i NameReview
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Matched,
+		&i.Status,
+		&i.ReviewedBy,
+		&i.CreatedAt,
+		&i.ReviewedAt,
+	)
+	return i, err
+}
+
+const getNotificationRule = `-- name: GetNotificationRule :one
+SELECT id, event_type, channel, audience, template, webhook_url, enabled, created_at, updated_at
+FROM notification_rules
+WHERE id = ?
+`
+
+func (q *Queries) GetNotificationRule(ctx context.Context, id int64) (NotificationRule, error) {
+	row := q.db.QueryRowContext(ctx, getNotificationRule, id)
+	var i NotificationRule
+	err := row.Scan(
+		&i.ID,
+		&i.EventType,
+		&i.Channel,
+		&i.Audience,
+		&i.Template,
+		&i.WebhookUrl,
+		&i.Enabled,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getOrganization = `-- name: GetOrganization :one
+SELECT id, name, created_at
+FROM organizations
+WHERE id = ?
+`
+
+func (q *Queries) GetOrganization(ctx context.Context, id int64) (Organization, error) {
+	row := q.db.QueryRowContext(ctx, getOrganization, id)
+	var i Organization
+	err := row.Scan(&i.ID, &i.Name, &i.CreatedAt)
+	return i, err
+}
+
+const getOrganizationBilling = `-- name: GetOrganizationBilling :one
+SELECT org_id, provider, customer_id, subscription_id, plan, status, seats, current_period_end, event_at, updated_at
+FROM organization_billing
+WHERE org_id = ?
+`
+
+func (q *Queries) GetOrganizationBilling(ctx context.Context, orgID int64) (OrganizationBilling, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationBilling, orgID)
+	var i OrganizationBilling
+	err := row.Scan(
+		&i.OrgID,
+		&i.Provider,
+		&i.CustomerID,
+		&i.SubscriptionID,
+		&i.Plan,
+		&i.Status,
+		&i.Seats,
+		&i.CurrentPeriodEnd,
+		&i.EventAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getOrganizationBillingByCustomer = `-- name: GetOrganizationBillingByCustomer :one
+SELECT org_id, provider, customer_id, subscription_id, plan, status, seats, current_period_end, event_at, updated_at
+FROM organization_billing
+WHERE provider = ? AND customer_id = ?
+`
+
+type GetOrganizationBillingByCustomerParams struct {
+	Provider   string `json:"provider"`
+	CustomerID string `json:"customer_id"`
+}
+
+func (q *Queries) GetOrganizationBillingByCustomer(ctx context.Context, arg GetOrganizationBillingByCustomerParams) (OrganizationBilling, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationBillingByCustomer, arg.Provider, arg.CustomerID)
+	var i OrganizationBilling
+	err := row.Scan(
+		&i.OrgID,
+		&i.Provider,
+		&i.CustomerID,
+		&i.SubscriptionID,
+		&i.Plan,
+		&i.Status,
+		&i.Seats,
+		&i.CurrentPeriodEnd,
+		&i.EventAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getOrganizationMembership = `-- name: GetOrganizationMembership :one
+SELECT org_id
+FROM organization_members
+WHERE user_id = ?
+`
+
+func (q *Queries) GetOrganizationMembership(ctx context.Context, userID int64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getOrganizationMembership, userID)
+	var org_id int64
+	err := row.Scan(&org_id)
+	return org_id, err
+}
+
+const getRateLimit = `-- name: GetRateLimit :one
+SELECT hits, window_start FROM rate_limit_counters WHERE client = ?
+`
+
+type GetRateLimitRow struct {
+	Hits        int32     `json:"hits"`
+	WindowStart time.Time `json:"window_start"`
+}
+
+func (q *Queries) GetRateLimit(ctx context.Context, client string) (GetRateLimitRow, error) {
+	row := q.db.QueryRowContext(ctx, getRateLimit, client)
+	var i GetRateLimitRow
+	err := row.Scan(&i.Hits, &i.WindowStart)
+	return i, err
+}
+
+const getReferralCode = `-- name: GetReferralCode :one
+SELECT code
+FROM referral_codes
+WHERE user_id = ?
+`
+
+func (q *Queries) GetReferralCode(ctx context.Context, userID int64) (string, error) {
+	row := q.db.QueryRowContext(ctx, getReferralCode, userID)
+	var code string
+	err := row.Scan(&code)
+	return code, err
+}
+
+const getReferralCodeOwner = `-- name: GetReferralCodeOwner :one
+SELECT user_id
+FROM referral_codes
+WHERE code = ?
+`
+
+func (q *Queries) GetReferralCodeOwner(ctx context.Context, code string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getReferralCodeOwner, code)
+	var user_id int64
+	err := row.Scan(&user_id)
+	return user_id, err
+}
+
+const getRefreshTokenByHash = `-- name: GetRefreshTokenByHash :one
+SELECT id, user_id, family, created_at, expires_at, revoked_at
+FROM refresh_tokens
+WHERE token_hash = ?
+`
+
+type GetRefreshTokenByHashRow struct {
+	ID        int64        `json:"id"`
+	UserID    int64        `json:"user_id"`
+	Family    string       `json:"family"`
+	CreatedAt time.Time    `json:"created_at"`
+	ExpiresAt time.Time    `json:"expires_at"`
+	RevokedAt sql.NullTime `json:"revoked_at"`
+}
+
+func (q *Queries) GetRefreshTokenByHash(ctx context.Context, tokenHash string) (GetRefreshTokenByHashRow, error) {
+	row := q.db.QueryRowContext(ctx, getRefreshTokenByHash, tokenHash)
+	var i GetRefreshTokenByHashRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Family,
+		&i.CreatedAt,
+		&i.ExpiresAt,
+		&i.RevokedAt,
+	)
+	return i, err
+}
+
+const getSSOPendingLogin = `-- name: GetSSOPendingLogin :one
+SELECT state_hash, nonce, code_verifier, expires_at FROM sso_pending_logins WHERE state_hash = ?
+`
+
+func (q *Queries) GetSSOPendingLogin(ctx context.Context, stateHash string) (SsoPendingLogin, error) {
+	row := q.db.QueryRowContext(ctx, getSSOPendingLogin, stateHash)
+	var i SsoPendingLogin
+	err := row.Scan(
+		&i.StateHash,
+		&i.Nonce,
+		&i.CodeVerifier,
+		&i.ExpiresAt,
+	)
+	return i, err
+}
+
+const getUserByEmail = `-- name: GetUserByEmail :one
+SELECT id, name, dob, email, password_hash, role, created_at, updated_at, active, account_type, signup_source, public_id, deleted_at, live_email
+FROM users
+WHERE email = ? AND deleted_at IS NULL
+`
+
+func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Role,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Active,
+		&i.AccountType,
+		&i.SignupSource,
+		&i.PublicID,
+		&i.DeletedAt,
+		&i.LiveEmail,
+	)
+	return i, err
+}
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE id = ? AND deleted_at IS NULL
+`
+
+type GetUserByIDRow struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	Dob          time.Time `json:"dob"`
+	Email        string    `json:"email"`
+	Role         string    `json:"role"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	AccountType  string    `json:"account_type"`
+	SignupSource string    `json:"signup_source"`
+	PublicID     string    `json:"public_id"`
+}
+
+func (q *Queries) GetUserByID(ctx context.Context, id int64) (GetUserByIDRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserByID, id)
+	var i GetUserByIDRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Dob,
+		&i.Email,
+		&i.Role,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.AccountType,
+		&i.SignupSource,
+		&i.PublicID,
+	)
+	return i, err
+}
+
+const getUserIDByPublicID = `-- name: GetUserIDByPublicID :one
+SELECT id
+FROM users
+WHERE public_id = ?
+`
+
+func (q *Queries) GetUserIDByPublicID(ctx context.Context, publicID string) (int64, error) {
+	row := q.db.QueryRowContext(ctx, getUserIDByPublicID, publicID)
+	var id int64
+	err := row.Scan(&id)
+	return id, err
+}
+
+const getUserIdentityBySubject = `-- name: GetUserIdentityBySubject :one
+SELECT id, user_id, provider, subject, email, created_at
+FROM user_identities
+WHERE provider = ? AND subject = ?
+`
+
+type GetUserIdentityBySubjectParams struct {
+	Provider string `json:"provider"`
+	Subject  string `json:"subject"`
+}
+
+func (q *Queries) GetUserIdentityBySubject(ctx context.Context, arg GetUserIdentityBySubjectParams) (UserIdentity, error) {
+	row := q.db.QueryRowContext(ctx, getUserIdentityBySubject, arg.Provider, arg.Subject)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Provider,
+		&i.Subject,
+		&i.Email,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getUserProfile = `-- name: GetUserProfile :one
+SELECT user_id, bio, phone, avatar_url, locale, timezone, updated_at, avatar_key
+FROM user_profiles
+WHERE user_id = ?
+`
+
+func (q *Queries) GetUserProfile(ctx context.Context, userID int64) (UserProfile, error) {
+	row := q.db.QueryRowContext(ctx, getUserProfile, userID)
+	var i UserProfile
+	err := row.Scan(
+		&i.UserID,
+		&i.Bio,
+		&i.Phone,
+		&i.AvatarUrl,
+		&i.Locale,
+		&i.Timezone,
+		&i.UpdatedAt,
+		&i.AvatarKey,
+	)
+	return i, err
+}
+
+const getUserStats = `-- name: GetUserStats :one
+SELECT
+    COUNT(*) AS total_users,
+    COALESCE(SUM(role = 'admin'), 0) AS admin_users,
+    COALESCE(SUM(created_at >= CURRENT_TIMESTAMP - INTERVAL 7 DAY), 0) AS signups_last_7_days,
+    COALESCE(SUM(created_at >= CURRENT_TIMESTAMP - INTERVAL 30 DAY), 0) AS signups_last_30_days,
+    CAST(COALESCE(AVG(TIMESTAMPDIFF(YEAR, dob, CURDATE())), 0) AS DOUBLE) AS average_age,
+    CURRENT_TIMESTAMP AS refreshed_at
+FROM users
+WHERE deleted_at IS NULL
+`
+
+type GetUserStatsRow struct {
+	TotalUsers        int64     `json:"total_users"`
+	AdminUsers        int64     `json:"admin_users"`
+	SignupsLast7Days  int64     `json:"signups_last_7_days"`
+	SignupsLast30Days int64     `json:"signups_last_30_days"`
+	AverageAge        float64   `json:"average_age"`
+	RefreshedAt       time.Time `json:"refreshed_at"`
+}
+
+func (q *Queries) GetUserStats(ctx context.Context) (GetUserStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getUserStats)
+	var i GetUserStatsRow
+	err := row.Scan(
+		&i.TotalUsers,
+		&i.AdminUsers,
+		&i.SignupsLast7Days,
+		&i.SignupsLast30Days,
+		&i.AverageAge,
+		&i.RefreshedAt,
+	)
+	return i, err
+}
+
+const getWebAuthnCredential = `-- name: GetWebAuthnCredential :one
+SELECT id, user_id, credential_id, public_key, sign_count, name, created_at, last_used_at
+FROM webauthn_credentials
+WHERE credential_id = ?
+`
+
+func (q *Queries) GetWebAuthnCredential(ctx context.Context, credentialID []byte) (WebauthnCredential, error) {
+	row := q.db.QueryRowContext(ctx, getWebAuthnCredential, credentialID)
+	var i WebauthnCredential
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.CredentialID,
+		&i.PublicKey,
+		&i.SignCount,
+		&i.Name,
+		&i.CreatedAt,
+		&i.LastUsedAt,
+	)
+	return i, err
+}
+
+const listAPIKeysByUser = `-- name: ListAPIKeysByUser :many
+SELECT id, user_id, name, prefix, scopes, created_at, expires_at, last_used_at, revoked_at
+FROM api_keys
+WHERE user_id = ?
+ORDER BY id
+`
+
+type ListAPIKeysByUserRow struct {
+	ID         int64        `json:"id"`
+	UserID     int64        `json:"user_id"`
+	Name       string       `json:"name"`
+	Prefix     string       `json:"prefix"`
+	Scopes     string       `json:"scopes"`
+	CreatedAt  time.Time    `json:"created_at"`
+	ExpiresAt  sql.NullTime `json:"expires_at"`
+	LastUsedAt sql.NullTime `json:"last_used_at"`
+	RevokedAt  sql.NullTime `json:"revoked_at"`
+}
+
+func (q *Queries) ListAPIKeysByUser(ctx context.Context, userID int64) ([]ListAPIKeysByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAPIKeysByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAPIKeysByUserRow
+	for rows.Next() {
+		var i ListAPIKeysByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Prefix,
+			&i.Scopes,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+			&i.LastUsedAt,
+			&i.RevokedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAPIUsageByUser = `-- name: ListAPIUsageByUser :many
+SELECT a.api_key_id, CAST(COALESCE(k.name, '') AS CHAR) AS key_name, a.day, a.requests
+FROM api_usage a
+LEFT JOIN api_keys k ON k.id = a.api_key_id
+WHERE a.user_id = ? AND a.day >= ? AND a.day <= ?
+ORDER BY a.day, a.api_key_id
+`
+
+type ListAPIUsageByUserParams struct {
+	UserID  int64     `json:"user_id"`
+	FromDay time.Time `json:"from_day"`
+	ToDay   time.Time `json:"to_day"`
+}
+
+type ListAPIUsageByUserRow struct {
+	ApiKeyID int64     `json:"api_key_id"`
+	KeyName  string    `json:"key_name"`
+	Day      time.Time `json:"day"`
+	Requests int64     `json:"requests"`
+}
+
+func (q *Queries) ListAPIUsageByUser(ctx context.Context, arg ListAPIUsageByUserParams) ([]ListAPIUsageByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listAPIUsageByUser, arg.UserID, arg.FromDay, arg.ToDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAPIUsageByUserRow
+	for rows.Next() {
+		var i ListAPIUsageByUserRow
+		if err := rows.Scan(
+			&i.ApiKeyID,
+			&i.KeyName,
+			&i.Day,
+			&i.Requests,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActiveAdmins = `-- name: ListActiveAdmins :many
+SELECT id, name, email
+FROM users
+WHERE role = 'admin' AND active = TRUE AND account_type = 'human' AND deleted_at IS NULL
+ORDER BY id
+`
+
+type ListActiveAdminsRow struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func (q *Queries) ListActiveAdmins(ctx context.Context) ([]ListActiveAdminsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveAdmins)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActiveAdminsRow
+	for rows.Next() {
+		var i ListActiveAdminsRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Email); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listActiveSessions = `-- name: ListActiveSessions :many
+SELECT id, ip_address, user_agent, created_at, expires_at
+FROM sessions
+WHERE user_id = ? AND revoked_at IS NULL AND expires_at > ?
+ORDER BY created_at, id
+`
+
+type ListActiveSessionsParams struct {
+	UserID    int64     `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type ListActiveSessionsRow struct {
+	ID        string    `json:"id"`
+	IpAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) ListActiveSessions(ctx context.Context, arg ListActiveSessionsParams) ([]ListActiveSessionsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listActiveSessions, arg.UserID, arg.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActiveSessionsRow
+	for rows.Next() {
+		var i ListActiveSessionsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.CreatedAt,
+			&i.ExpiresAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeactivatedUsers = `-- name: ListDeactivatedUsers :many
+SELECT id, name, email, updated_at
+FROM users
+WHERE active = FALSE AND updated_at >= ? AND deleted_at IS NULL
+ORDER BY updated_at DESC, id
+`
+
+type ListDeactivatedUsersRow struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (q *Queries) ListDeactivatedUsers(ctx context.Context, since time.Time) ([]ListDeactivatedUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listDeactivatedUsers, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDeactivatedUsersRow
+	for rows.Next() {
+		var i ListDeactivatedUsersRow
+		if err := rows.Scan(&i.ID, &i.Name, &i.Email, &i.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listEmailDeadLetters = `-- name: ListEmailDeadLetters :many
+SELECT id, recipients, subject, html, error, attempts, created_at, last_attempt_at
+FROM email_dead_letters
+ORDER BY created_at DESC, id DESC
+LIMIT ?
+`
+
+func (q *Queries) ListEmailDeadLetters(ctx context.Context, limit int32) ([]EmailDeadLetter, error) {
+	rows, err := q.db.QueryContext(ctx, listEmailDeadLetters, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EmailDeadLetter
+	for rows.Next() {
+		var i EmailDeadLetter
+		if err := rows.Scan(
+			&i.ID,
+			&i.Recipients,
+			&i.Subject,
+			&i.Html,
+			&i.Error,
+			&i.Attempts,
+			&i.CreatedAt,
+			&i.LastAttemptAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLoginHistoryByUser = `-- name: ListLoginHistoryByUser :many
+SELECT id, email, succeeded, reason, ip_address, user_agent, country, city, created_at
+FROM login_history
+WHERE user_id = ?
+ORDER BY created_at DESC, id DESC
+LIMIT ?
+`
+
+type ListLoginHistoryByUserParams struct {
+	UserID sql.NullInt64 `json:"user_id"`
+	Limit  int32         `json:"limit"`
+}
+
+type ListLoginHistoryByUserRow struct {
+	ID        int64     `json:"id"`
+	Email     string    `json:"email"`
+	Succeeded bool      `json:"succeeded"`
+	Reason    string    `json:"reason"`
+	IpAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	Country   string    `json:"country"`
+	City      string    `json:"city"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (q *Queries) ListLoginHistoryByUser(ctx context.Context, arg ListLoginHistoryByUserParams) ([]ListLoginHistoryByUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLoginHistoryByUser, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLoginHistoryByUserRow
+	for rows.Next() {
+		var i ListLoginHistoryByUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Email,
+			&i.Succeeded,
+			&i.Reason,
+			&i.IpAddress,
+			&i.UserAgent,
+			&i.Country,
+			&i.City,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLoginLockouts = `-- name: ListLoginLockouts :many
+SELECT lock_key, failures, locked_until
+FROM login_lockouts
+WHERE locked_until > ?
+ORDER BY locked_until DESC
+`
+
+type ListLoginLockoutsRow struct {
+	LockKey     string       `json:"lock_key"`
+	Failures    int32        `json:"failures"`
+	LockedUntil sql.NullTime `json:"locked_until"`
+}
+
+func (q *Queries) ListLoginLockouts(ctx context.Context, lockedUntil sql.NullTime) ([]ListLoginLockoutsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listLoginLockouts, lockedUntil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLoginLockoutsRow
+	for rows.Next() {
+		var i ListLoginLockoutsRow
+		if err := rows.Scan(&i.LockKey, &i.Failures, &i.LockedUntil); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNameReviews = `-- name: ListNameReviews :many
+SELECT id, user_id, name, matched, status, reviewed_by, created_at, reviewed_at
+FROM name_reviews
+WHERE status = ?
+ORDER BY created_at, id
+LIMIT ?
+`
+
+type ListNameReviewsParams struct {
+	Status string `json:"status"`
+	Limit  int32  `json:"limit"`
+}
+
+func (q *Queries) ListNameReviews(ctx context.Context, arg ListNameReviewsParams) ([]NameReview, error) {
+	rows, err := q.db.QueryContext(ctx, listNameReviews, arg.Status, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NameReview
+	for rows.Next() {
+		var i NameReview
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Matched,
+			&i.Status,
+			&i.ReviewedBy,
+			&i.CreatedAt,
+			&i.ReviewedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationPreferences = `-- name: ListNotificationPreferences :many
+SELECT user_id, event_type, channel, enabled
+FROM notification_preferences
+WHERE user_id = ?
+ORDER BY event_type, channel
+`
+
+func (q *Queries) ListNotificationPreferences(ctx context.Context, userID int64) ([]NotificationPreference, error) {
+	rows, err := q.db.QueryContext(ctx, listNotificationPreferences, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationPreference
+	for rows.Next() {
+		var i NotificationPreference
+		if err := rows.Scan(
+			&i.UserID,
+			&i.EventType,
+			&i.Channel,
+			&i.Enabled,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationRules = `-- name: ListNotificationRules :many
+SELECT id, event_type, channel, audience, template, webhook_url, enabled, created_at, updated_at
+FROM notification_rules
+ORDER BY event_type, id
+`
+
+func (q *Queries) ListNotificationRules(ctx context.Context) ([]NotificationRule, error) {
+	rows, err := q.db.QueryContext(ctx, listNotificationRules)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationRule
+	for rows.Next() {
+		var i NotificationRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.Channel,
+			&i.Audience,
+			&i.Template,
+			&i.WebhookUrl,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotificationRulesForEvent = `-- name: ListNotificationRulesForEvent :many
+SELECT id, event_type, channel, audience, template, webhook_url, enabled, created_at, updated_at
+FROM notification_rules
+WHERE event_type = ? AND enabled = TRUE
+ORDER BY id
+`
+
+func (q *Queries) ListNotificationRulesForEvent(ctx context.Context, eventType string) ([]NotificationRule, error) {
+	rows, err := q.db.QueryContext(ctx, listNotificationRulesForEvent, eventType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []NotificationRule
+	for rows.Next() {
+		var i NotificationRule
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.Channel,
+			&i.Audience,
+			&i.Template,
+			&i.WebhookUrl,
+			&i.Enabled,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listNotifications = `-- name: ListNotifications :many
+SELECT id, user_id, event_type, title, details, created_at, read_at
+FROM notifications
+WHERE user_id = ?
+ORDER BY created_at DESC, id DESC
+LIMIT ?
+`
+
+type ListNotificationsParams struct {
+	UserID int64 `json:"user_id"`
+	Limit  int32 `json:"limit"`
+}
+
+func (q *Queries) ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error) {
+	rows, err := q.db.QueryContext(ctx, listNotifications, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Notification
+	for rows.Next() {
+		var i Notification
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.EventType,
+			&i.Title,
+			&i.Details,
+			&i.CreatedAt,
+			&i.ReadAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrganizationUsage = `-- name: ListOrganizationUsage :many
+SELECT org_id, day, seats, api_calls, storage_bytes
+FROM organization_usage
+WHERE org_id = ? AND day >= ? AND day <= ?
+ORDER BY day
+`
+
+type ListOrganizationUsageParams struct {
+	OrgID   int64     `json:"org_id"`
+	FromDay time.Time `json:"from_day"`
+	ToDay   time.Time `json:"to_day"`
+}
+
+func (q *Queries) ListOrganizationUsage(ctx context.Context, arg ListOrganizationUsageParams) ([]OrganizationUsage, error) {
+	rows, err := q.db.QueryContext(ctx, listOrganizationUsage, arg.OrgID, arg.FromDay, arg.ToDay)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OrganizationUsage
+	for rows.Next() {
+		var i OrganizationUsage
+		if err := rows.Scan(
+			&i.OrgID,
+			&i.Day,
+			&i.Seats,
+			&i.ApiCalls,
+			&i.StorageBytes,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrganizations = `-- name: ListOrganizations :many
+SELECT id, name, created_at
+FROM organizations
+ORDER BY id
+`
+
+func (q *Queries) ListOrganizations(ctx context.Context) ([]Organization, error) {
+	rows, err := q.db.QueryContext(ctx, listOrganizations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Organization
+	for rows.Next() {
+		var i Organization
+		if err := rows.Scan(&i.ID, &i.Name, &i.CreatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRevokedTokens = `-- name: ListRevokedTokens :many
+SELECT jti, expires_at
+FROM revoked_tokens
+WHERE expires_at > ?
+`
+
+type ListRevokedTokensRow struct {
+	Jti       string    `json:"jti"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) ListRevokedTokens(ctx context.Context, expiresAt time.Time) ([]ListRevokedTokensRow, error) {
+	rows, err := q.db.QueryContext(ctx, listRevokedTokens, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRevokedTokensRow
+	for rows.Next() {
+		var i ListRevokedTokensRow
+		if err := rows.Scan(&i.Jti, &i.ExpiresAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSecurityEvents = `-- name: ListSecurityEvents :many
+SELECT e.id, e.event_type, actor.public_id AS actor_public_id, target.public_id AS target_public_id, e.details, e.ip_address, e.created_at, e.hash
+FROM security_events e
+LEFT JOIN users actor ON actor.id = e.actor_id
+LEFT JOIN users target ON target.id = e.target_id
+WHERE e.id < ?
+ORDER BY e.id DESC
+LIMIT ?
+`
+
+type ListSecurityEventsParams struct {
+	ID    int64 `json:"id"`
+	Limit int32 `json:"limit"`
+}
+
+type ListSecurityEventsRow struct {
+	ID             int64          `json:"id"`
+	EventType      string         `json:"event_type"`
+	ActorPublicID  sql.NullString `json:"actor_public_id"`
+	TargetPublicID sql.NullString `json:"target_public_id"`
+	Details        string         `json:"details"`
+	IpAddress      string         `json:"ip_address"`
+	CreatedAt      time.Time      `json:"created_at"`
+	Hash           string         `json:"hash"`
+}
+
+func (q *Queries) ListSecurityEvents(ctx context.Context, arg ListSecurityEventsParams) ([]ListSecurityEventsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listSecurityEvents, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListSecurityEventsRow
+	for rows.Next() {
+		var i ListSecurityEventsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.ActorPublicID,
+			&i.TargetPublicID,
+			&i.Details,
+			&i.IpAddress,
+			&i.CreatedAt,
+			&i.Hash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSecurityEventsAfter = `-- name: ListSecurityEventsAfter :many
+SELECT id, event_type, actor_id, target_id, details, ip_address, created_at, prev_hash, hash
+FROM security_events
+WHERE id > ?
+ORDER BY id
+LIMIT ?
+`
+
+type ListSecurityEventsAfterParams struct {
+	ID    int64 `json:"id"`
+	Limit int32 `json:"limit"`
+}
+
+func (q *Queries) ListSecurityEventsAfter(ctx context.Context, arg ListSecurityEventsAfterParams) ([]SecurityEvent, error) {
+	rows, err := q.db.QueryContext(ctx, listSecurityEventsAfter, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SecurityEvent
+	for rows.Next() {
+		var i SecurityEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.EventType,
+			&i.ActorID,
+			&i.TargetID,
+			&i.Details,
+			&i.IpAddress,
+			&i.CreatedAt,
+			&i.PrevHash,
+			&i.Hash,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listServiceAccounts = `-- name: ListServiceAccounts :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE account_type = 'service' AND deleted_at IS NULL
+ORDER BY id
+`
+
+type ListServiceAccountsRow struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	Dob          time.Time `json:"dob"`
+	Email        string    `json:"email"`
+	Role         string    `json:"role"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	AccountType  string    `json:"account_type"`
+	SignupSource string    `json:"signup_source"`
+	PublicID     string    `json:"public_id"`
+}
+
+func (q *Queries) ListServiceAccounts(ctx context.Context) ([]ListServiceAccountsRow, error) {
+	rows, err := q.db.QueryContext(ctx, listServiceAccounts)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListServiceAccountsRow
+	for rows.Next() {
+		var i ListServiceAccountsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Dob,
+			&i.Email,
+			&i.Role,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AccountType,
+			&i.SignupSource,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTokenRevocations = `-- name: ListTokenRevocations :many
+SELECT user_id, revoked_at
+FROM token_revocations
+`
+
+func (q *Queries) ListTokenRevocations(ctx context.Context) ([]TokenRevocation, error) {
+	rows, err := q.db.QueryContext(ctx, listTokenRevocations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TokenRevocation
+	for rows.Next() {
+		var i TokenRevocation
+		if err := rows.Scan(&i.UserID, &i.RevokedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUpcomingBirthdays = `-- name: ListUpcomingBirthdays :many
+SELECT id, name, dob, public_id
+FROM users
+WHERE deleted_at IS NULL
+    AND ((MONTH(dob) * 100 + DAY(dob)) BETWEEN ? AND ?
+    OR (MONTH(dob) * 100 + DAY(dob)) BETWEEN ? AND ?)
+ORDER BY (MONTH(dob) * 100 + DAY(dob)) < ?,
+    MONTH(dob) * 100 + DAY(dob), id
+LIMIT ?
+`
+
+type ListUpcomingBirthdaysParams struct {
+	FromDay  int32 `json:"from_day"`
+	ToDay    int32 `json:"to_day"`
+	WrapFrom int32 `json:"wrap_from"`
+	WrapTo   int32 `json:"wrap_to"`
+	Limit    int32 `json:"limit"`
+}
+
+type ListUpcomingBirthdaysRow struct {
+	ID       int64     `json:"id"`
+	Name     string    `json:"name"`
+	Dob      time.Time `json:"dob"`
+	PublicID string    `json:"public_id"`
+}
+
+func (q *Queries) ListUpcomingBirthdays(ctx context.Context, arg ListUpcomingBirthdaysParams) ([]ListUpcomingBirthdaysRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUpcomingBirthdays,
+		arg.FromDay,
+		arg.ToDay,
+		arg.WrapFrom,
+		arg.WrapTo,
+		arg.FromDay,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUpcomingBirthdaysRow
+	for rows.Next() {
+		var i ListUpcomingBirthdaysRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Dob,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserIdentitiesByUser = `-- name: ListUserIdentitiesByUser :many
+SELECT id, user_id, provider, subject, email, created_at
+FROM user_identities
+WHERE user_id = ?
+ORDER BY provider
+`
+
+func (q *Queries) ListUserIdentitiesByUser(ctx context.Context, userID int64) ([]UserIdentity, error) {
+	rows, err := q.db.QueryContext(ctx, listUserIdentitiesByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserIdentity
+	for rows.Next() {
+		var i UserIdentity
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Provider,
+			&i.Subject,
+			&i.Email,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsers = `-- name: ListUsers :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE deleted_at IS NULL
+ORDER BY id
+`
+
+type ListUsersRow struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	Dob          time.Time `json:"dob"`
+	Email        string    `json:"email"`
+	Role         string    `json:"role"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	AccountType  string    `json:"account_type"`
+	SignupSource string    `json:"signup_source"`
+	PublicID     string    `json:"public_id"`
+}
+
+func (q *Queries) ListUsers(ctx context.Context) ([]ListUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersRow
+	for rows.Next() {
+		var i ListUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Dob,
+			&i.Email,
+			&i.Role,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AccountType,
+			&i.SignupSource,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersBySignupSource = `-- name: ListUsersBySignupSource :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE signup_source = ? AND deleted_at IS NULL
+ORDER BY id
+`
+
+type ListUsersBySignupSourceRow struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	Dob          time.Time `json:"dob"`
+	Email        string    `json:"email"`
+	Role         string    `json:"role"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	AccountType  string    `json:"account_type"`
+	SignupSource string    `json:"signup_source"`
+	PublicID     string    `json:"public_id"`
+}
+
+func (q *Queries) ListUsersBySignupSource(ctx context.Context, signupSource string) ([]ListUsersBySignupSourceRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersBySignupSource, signupSource)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersBySignupSourceRow
+	for rows.Next() {
+		var i ListUsersBySignupSourceRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Dob,
+			&i.Email,
+			&i.Role,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AccountType,
+			&i.SignupSource,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUsersPaginated = `-- name: ListUsersPaginated :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE deleted_at IS NULL
+ORDER BY id
+LIMIT ? OFFSET ?
+`
+
+type ListUsersPaginatedParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
+}
+
+type ListUsersPaginatedRow struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	Dob          time.Time `json:"dob"`
+	Email        string    `json:"email"`
+	Role         string    `json:"role"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	AccountType  string    `json:"account_type"`
+	SignupSource string    `json:"signup_source"`
+	PublicID     string    `json:"public_id"`
+}
+
+func (q *Queries) ListUsersPaginated(ctx context.Context, arg ListUsersPaginatedParams) ([]ListUsersPaginatedRow, error) {
+	rows, err := q.db.QueryContext(ctx, listUsersPaginated, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListUsersPaginatedRow
+	for rows.Next() {
+		var i ListUsersPaginatedRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Dob,
+			&i.Email,
+			&i.Role,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AccountType,
+			&i.SignupSource,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listWebAuthnCredentialsByUser = `-- name: ListWebAuthnCredentialsByUser :many
+SELECT id, user_id, credential_id, public_key, sign_count, name, created_at, last_used_at
+FROM webauthn_credentials
+WHERE user_id = ?
+ORDER BY id
+`
+
+func (q *Queries) ListWebAuthnCredentialsByUser(ctx context.Context, userID int64) ([]WebauthnCredential, error) {
+	rows, err := q.db.QueryContext(ctx, listWebAuthnCredentialsByUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []WebauthnCredential
+	for rows.Next() {
+		var i WebauthnCredential
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.CredentialID,
+			&i.PublicKey,
+			&i.SignCount,
+			&i.Name,
+			&i.CreatedAt,
+			&i.LastUsedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockLogin = `-- name: LockLogin :exec
+UPDATE login_lockouts SET locked_until = ? WHERE lock_key = ?
+`
+
+type LockLoginParams struct {
+	LockedUntil sql.NullTime `json:"locked_until"`
+	LockKey     string       `json:"lock_key"`
+}
+
+func (q *Queries) LockLogin(ctx context.Context, arg LockLoginParams) error {
+	_, err := q.db.ExecContext(ctx, lockLogin, arg.LockedUntil, arg.LockKey)
+	return err
+}
+
+const loginHistoryStats = `-- name: LoginHistoryStats :one
+SELECT COUNT(*) AS total, MIN(created_at) AS oldest
+FROM login_history
+`
+
+type LoginHistoryStatsRow struct {
+	Total  int64        `json:"total"`
+	Oldest sql.NullTime `json:"oldest"`
+}
+
+func (q *Queries) LoginHistoryStats(ctx context.Context) (LoginHistoryStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, loginHistoryStats)
+	var i LoginHistoryStatsRow
+	err := row.Scan(&i.Total, &i.Oldest)
+	return i, err
+}
+
+const loginLockoutStats = `-- name: LoginLockoutStats :one
+SELECT COUNT(*) AS total, MIN(window_start) AS oldest
+FROM login_lockouts
+`
+
+type LoginLockoutStatsRow struct {
+	Total  int64        `json:"total"`
+	Oldest sql.NullTime `json:"oldest"`
+}
+
+func (q *Queries) LoginLockoutStats(ctx context.Context) (LoginLockoutStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, loginLockoutStats)
+	var i LoginLockoutStatsRow
+	err := row.Scan(&i.Total, &i.Oldest)
+	return i, err
+}
+
+const lookupUsersByEmail = `-- name: LookupUsersByEmail :many
+SELECT id, name, email, role, active, account_type, public_id
+FROM users
+WHERE live_email LIKE ?
+ORDER BY live_email, id
+LIMIT ?
+`
+
+type LookupUsersByEmailParams struct {
+	Pattern  string `json:"pattern"`
+	RowLimit int32  `json:"row_limit"`
+}
+
+type LookupUsersByEmailRow struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	Role        string `json:"role"`
+	Active      bool   `json:"active"`
+	AccountType string `json:"account_type"`
+	PublicID    string `json:"public_id"`
+}
+
+func (q *Queries) LookupUsersByEmail(ctx context.Context, arg LookupUsersByEmailParams) ([]LookupUsersByEmailRow, error) {
+	rows, err := q.db.QueryContext(ctx, lookupUsersByEmail, arg.Pattern, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LookupUsersByEmailRow
+	for rows.Next() {
+		var i LookupUsersByEmailRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Email,
+			&i.Role,
+			&i.Active,
+			&i.AccountType,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const magicLinkRedemptionStats = `-- name: MagicLinkRedemptionStats :one
+SELECT COUNT(*) AS total, MIN(expires_at) AS oldest
+FROM magic_link_redemptions
+`
+
+type MagicLinkRedemptionStatsRow struct {
+	Total  int64        `json:"total"`
+	Oldest sql.NullTime `json:"oldest"`
+}
+
+func (q *Queries) MagicLinkRedemptionStats(ctx context.Context) (MagicLinkRedemptionStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, magicLinkRedemptionStats)
+	var i MagicLinkRedemptionStatsRow
+	err := row.Scan(&i.Total, &i.Oldest)
+	return i, err
+}
+
+const markNotificationRead = `-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = CURRENT_TIMESTAMP
+WHERE id = ? AND user_id = ? AND read_at IS NULL
+`
+
+type MarkNotificationReadParams struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markNotificationRead, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const notificationStats = `-- name: NotificationStats :one
+SELECT COUNT(*) AS total, MIN(created_at) AS oldest
+FROM notifications
+`
+
+type NotificationStatsRow struct {
+	Total  int64        `json:"total"`
+	Oldest sql.NullTime `json:"oldest"`
+}
+
+func (q *Queries) NotificationStats(ctx context.Context) (NotificationStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, notificationStats)
+	var i NotificationStatsRow
+	err := row.Scan(&i.Total, &i.Oldest)
+	return i, err
+}
+
+const pruneLoginHistory = `-- name: PruneLoginHistory :execrows
+DELETE FROM login_history
+WHERE created_at < ?
+`
+
+func (q *Queries) PruneLoginHistory(ctx context.Context, createdAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneLoginHistory, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const pruneNotifications = `-- name: PruneNotifications :execrows
+DELETE FROM notifications
+WHERE created_at < ?
+`
+
+func (q *Queries) PruneNotifications(ctx context.Context, createdAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneNotifications, createdAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const pruneRevokedTokens = `-- name: PruneRevokedTokens :execrows
+DELETE FROM revoked_tokens
+WHERE expires_at < ?
+`
+
+func (q *Queries) PruneRevokedTokens(ctx context.Context, expiresAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneRevokedTokens, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const pruneSessions = `-- name: PruneSessions :execrows
+DELETE FROM sessions
+WHERE expires_at < ?
+`
+
+func (q *Queries) PruneSessions(ctx context.Context, expiresAt time.Time) (int64, error) {
+	result, err := q.db.ExecContext(ctx, pruneSessions, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const rateLimitStats = `-- name: RateLimitStats :one
+SELECT COUNT(*) AS total, MIN(window_start) AS oldest
+FROM rate_limit_counters
+`
+
+type RateLimitStatsRow struct {
+	Total  int64        `json:"total"`
+	Oldest sql.NullTime `json:"oldest"`
+}
+
+func (q *Queries) RateLimitStats(ctx context.Context) (RateLimitStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, rateLimitStats)
+	var i RateLimitStatsRow
+	err := row.Scan(&i.Total, &i.Oldest)
+	return i, err
+}
+
+const recordEmailDeadLetterAttempt = `-- name: RecordEmailDeadLetterAttempt :exec
+UPDATE email_dead_letters
+SET error = ?, attempts = attempts + 1, last_attempt_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type RecordEmailDeadLetterAttemptParams struct {
+	Error string `json:"error"`
+	ID    int64  `json:"id"`
+}
+
+func (q *Queries) RecordEmailDeadLetterAttempt(ctx context.Context, arg RecordEmailDeadLetterAttemptParams) error {
+	_, err := q.db.ExecContext(ctx, recordEmailDeadLetterAttempt, arg.Error, arg.ID)
+	return err
+}
+
+const recordLogin = `-- name: RecordLogin :exec
+INSERT INTO login_history (user_id, email, succeeded, reason, ip_address, user_agent, country, city)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+type RecordLoginParams struct {
+	UserID    sql.NullInt64 `json:"user_id"`
+	Email     string        `json:"email"`
+	Succeeded bool          `json:"succeeded"`
+	Reason    string        `json:"reason"`
+	IpAddress string        `json:"ip_address"`
+	UserAgent string        `json:"user_agent"`
+	Country   string        `json:"country"`
+	City      string        `json:"city"`
+}
+
+func (q *Queries) RecordLogin(ctx context.Context, arg RecordLoginParams) error {
+	_, err := q.db.ExecContext(ctx, recordLogin,
+		arg.UserID,
+		arg.Email,
+		arg.Succeeded,
+		arg.Reason,
+		arg.IpAddress,
+		arg.UserAgent,
+		arg.Country,
+		arg.City,
+	)
+	return err
+}
+
+const recordLoginFailure = `-- name: RecordLoginFailure :exec
+INSERT INTO login_lockouts (lock_key, failures, window_start)
+VALUES (?, 1, ?)
+ON DUPLICATE KEY UPDATE
+    failures = IF(window_start <= ?, 1, failures + 1),
+    window_start = IF(window_start <= ?, VALUES(window_start), window_start)
+`
+
+type RecordLoginFailureParams struct {
+	LockKey       string    `json:"lock_key"`
+	Now           time.Time `json:"now"`
+	ExpiredBefore time.Time `json:"expired_before"`
+}
+
+func (q *Queries) RecordLoginFailure(ctx context.Context, arg RecordLoginFailureParams) error {
+	_, err := q.db.ExecContext(ctx, recordLoginFailure,
+		arg.LockKey,
+		arg.Now,
+		arg.ExpiredBefore,
+		arg.ExpiredBefore,
+	)
+	return err
+}
+
+const redeemMagicLink = `-- name: RedeemMagicLink :execrows
+INSERT IGNORE INTO magic_link_redemptions (token_hash, expires_at)
+VALUES (?, ?)
+`
+
+type RedeemMagicLinkParams struct {
+	TokenHash string    `json:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) RedeemMagicLink(ctx context.Context, arg RedeemMagicLinkParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, redeemMagicLink, arg.TokenHash, arg.ExpiresAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const referralStats = `-- name: ReferralStats :one
+SELECT COUNT(*) AS total, COUNT(CASE WHEN created_at >= ? THEN 1 END) AS recent
+FROM referrals
+WHERE referrer_id = ?
+`
+
+type ReferralStatsParams struct {
+	Since      time.Time `json:"since"`
+	ReferrerID int64     `json:"referrer_id"`
+}
+
+type ReferralStatsRow struct {
+	Total  int64 `json:"total"`
+	Recent int64 `json:"recent"`
+}
+
+func (q *Queries) ReferralStats(ctx context.Context, arg ReferralStatsParams) (ReferralStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, referralStats, arg.Since, arg.ReferrerID)
+	var i ReferralStatsRow
+	err := row.Scan(&i.Total, &i.Recent)
+	return i, err
+}
+
+const releaseJobLease = `-- name: ReleaseJobLease :exec
+DELETE FROM job_leases WHERE name = ? AND holder = ?
+`
+
+type ReleaseJobLeaseParams struct {
+	Name   string `json:"name"`
+	Holder string `json:"holder"`
+}
+
+func (q *Queries) ReleaseJobLease(ctx context.Context, arg ReleaseJobLeaseParams) error {
+	_, err := q.db.ExecContext(ctx, releaseJobLease, arg.Name, arg.Holder)
+	return err
+}
+
+const resolveNameReview = `-- name: ResolveNameReview :execrows
+UPDATE name_reviews
+SET status = ?, reviewed_by = ?, reviewed_at = CURRENT_TIMESTAMP
+WHERE id = ? AND status = 'pending'
+`
+
+type ResolveNameReviewParams struct {
+	Status     string        `json:"status"`
+	ReviewedBy sql.NullInt64 `json:"reviewed_by"`
+	ID         int64         `json:"id"`
+}
+
+func (q *Queries) ResolveNameReview(ctx context.Context, arg ResolveNameReviewParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, resolveNameReview, arg.Status, arg.ReviewedBy, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const restoreUser = `-- name: RestoreUser :execrows
+UPDATE users
+SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NOT NULL
+`
+
+func (q *Queries) RestoreUser(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, restoreUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const revokeAPIKey = `-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = ? AND user_id = ? AND revoked_at IS NULL
+`
+
+type RevokeAPIKeyParams struct {
+	ID     int64 `json:"id"`
+	UserID int64 `json:"user_id"`
+}
+
+func (q *Queries) RevokeAPIKey(ctx context.Context, arg RevokeAPIKeyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeAPIKey, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const revokeRefreshToken = `-- name: RevokeRefreshToken :execrows
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = ? AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeRefreshToken(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeRefreshToken, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const revokeRefreshTokenFamily = `-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE family = ? AND revoked_at IS NULL
+`
+
+func (q *Queries) RevokeRefreshTokenFamily(ctx context.Context, family string) error {
+	_, err := q.db.ExecContext(ctx, revokeRefreshTokenFamily, family)
+	return err
+}
+
+const revokeSession = `-- name: RevokeSession :execrows
+UPDATE sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = ? AND user_id = ? AND revoked_at IS NULL
+`
+
+type RevokeSessionParams struct {
+	ID     string `json:"id"`
+	UserID int64  `json:"user_id"`
+}
+
+func (q *Queries) RevokeSession(ctx context.Context, arg RevokeSessionParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeSession, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const revokeToken = `-- name: RevokeToken :exec
+INSERT IGNORE INTO revoked_tokens (jti, user_id, expires_at)
+VALUES (?, ?, ?)
+`
+
+type RevokeTokenParams struct {
+	Jti       string    `json:"jti"`
+	UserID    int64     `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (q *Queries) RevokeToken(ctx context.Context, arg RevokeTokenParams) error {
+	_, err := q.db.ExecContext(ctx, revokeToken, arg.Jti, arg.UserID, arg.ExpiresAt)
+	return err
+}
+
+const revokeUserTokens = `-- name: RevokeUserTokens :exec
+INSERT INTO token_revocations (user_id, revoked_at)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE revoked_at = VALUES(revoked_at)
+`
+
+type RevokeUserTokensParams struct {
+	UserID    int64     `json:"user_id"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+func (q *Queries) RevokeUserTokens(ctx context.Context, arg RevokeUserTokensParams) error {
+	_, err := q.db.ExecContext(ctx, revokeUserTokens, arg.UserID, arg.RevokedAt)
+	return err
+}
+
+const revokedTokenStats = `-- name: RevokedTokenStats :one
+SELECT COUNT(*) AS total, MIN(expires_at) AS oldest
+FROM revoked_tokens
+`
+
+type RevokedTokenStatsRow struct {
+	Total  int64        `json:"total"`
+	Oldest sql.NullTime `json:"oldest"`
+}
+
+func (q *Queries) RevokedTokenStats(ctx context.Context) (RevokedTokenStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, revokedTokenStats)
+	var i RevokedTokenStatsRow
+	err := row.Scan(&i.Total, &i.Oldest)
+	return i, err
+}
+
+const sSOPendingLoginStats = `-- name: SSOPendingLoginStats :one
+SELECT COUNT(*) AS total, MIN(expires_at) AS oldest
+FROM sso_pending_logins
+`
+
+type SSOPendingLoginStatsRow struct {
+	Total  int64        `json:"total"`
+	Oldest sql.NullTime `json:"oldest"`
+}
+
+func (q *Queries) SSOPendingLoginStats(ctx context.Context) (SSOPendingLoginStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, sSOPendingLoginStats)
+	var i SSOPendingLoginStatsRow
+	err := row.Scan(&i.Total, &i.Oldest)
+	return i, err
+}
+
+const searchUsers = `-- name: SearchUsers :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE deleted_at IS NULL
+    AND (? IS NULL OR LOWER(name) LIKE LOWER(?))
+    AND (? IS NULL OR LOWER(email) = LOWER(?))
+    AND (? IS NULL OR role = ?)
+    AND (? IS NULL OR signup_source = ?)
+    AND (? IS NULL OR dob > ?)
+    AND (? IS NULL OR dob <= ?)
+    AND (? IS NULL OR created_at >= ?)
+    AND (? IS NULL OR created_at < ?)
+    AND (? IS NULL OR id IN (SELECT user_id FROM organization_members WHERE org_id = ?))
+ORDER BY
+    CASE ? WHEN 'name' THEN name WHEN 'email' THEN email WHEN 'role' THEN role END,
+    CASE ? WHEN 'dob' THEN dob END,
+    CASE ? WHEN 'created_at' THEN created_at WHEN 'updated_at' THEN updated_at END,
+    CASE ? WHEN '-name' THEN name WHEN '-email' THEN email WHEN '-role' THEN role END DESC,
+    CASE ? WHEN '-dob' THEN dob END DESC,
+    CASE ? WHEN '-created_at' THEN created_at WHEN '-updated_at' THEN updated_at END DESC,
+    CASE ? WHEN 'name' THEN name WHEN 'email' THEN email WHEN 'role' THEN role END,
+    CASE ? WHEN 'dob' THEN dob END,
+    CASE ? WHEN 'created_at' THEN created_at WHEN 'updated_at' THEN updated_at END,
+    CASE ? WHEN '-name' THEN name WHEN '-email' THEN email WHEN '-role' THEN role END DESC,
+    CASE ? WHEN '-dob' THEN dob END DESC,
+    CASE ? WHEN '-created_at' THEN created_at WHEN '-updated_at' THEN updated_at END DESC,
+    CASE ? WHEN 'name' THEN name WHEN 'email' THEN email WHEN 'role' THEN role END,
+    CASE ? WHEN 'dob' THEN dob END,
+    CASE ? WHEN 'created_at' THEN created_at WHEN 'updated_at' THEN updated_at END,
+    CASE ? WHEN '-name' THEN name WHEN '-email' THEN email WHEN '-role' THEN role END DESC,
+    CASE ? WHEN '-dob' THEN dob END DESC,
+    CASE ? WHEN '-created_at' THEN created_at WHEN '-updated_at' THEN updated_at END DESC,
+    id
+LIMIT ? OFFSET ?
+`
+
+type SearchUsersParams struct {
+	NamePattern   sql.NullString `json:"name_pattern"`
+	Email         sql.NullString `json:"email"`
+	Role          sql.NullString `json:"role"`
+	SignupSource  sql.NullString `json:"signup_source"`
+	DobAfter      sql.NullTime   `json:"dob_after"`
+	DobUntil      sql.NullTime   `json:"dob_until"`
+	CreatedFrom   sql.NullTime   `json:"created_from"`
+	CreatedBefore sql.NullTime   `json:"created_before"`
+	OrgID         sql.NullInt64  `json:"org_id"`
+	Sort1         string         `json:"sort1"`
+	Sort2         string         `json:"sort2"`
+	Sort3         string         `json:"sort3"`
+	Limit         int32          `json:"limit"`
+	Offset        int32          `json:"offset"`
+}
+
+type SearchUsersRow struct {
+	ID           int64     `json:"id"`
+	Name         string    `json:"name"`
+	Dob          time.Time `json:"dob"`
+	Email        string    `json:"email"`
+	Role         string    `json:"role"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	AccountType  string    `json:"account_type"`
+	SignupSource string    `json:"signup_source"`
+	PublicID     string    `json:"public_id"`
+}
+
+func (q *Queries) SearchUsers(ctx context.Context, arg SearchUsersParams) ([]SearchUsersRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchUsers,
+		arg.NamePattern,
+		arg.NamePattern,
+		arg.Email,
+		arg.Email,
+		arg.Role,
+		arg.Role,
+		arg.SignupSource,
+		arg.SignupSource,
+		arg.DobAfter,
+		arg.DobAfter,
+		arg.DobUntil,
+		arg.DobUntil,
+		arg.CreatedFrom,
+		arg.CreatedFrom,
+		arg.CreatedBefore,
+		arg.CreatedBefore,
+		arg.OrgID,
+		arg.OrgID,
+		arg.Sort1,
+		arg.Sort1,
+		arg.Sort1,
+		arg.Sort1,
+		arg.Sort1,
+		arg.Sort1,
+		arg.Sort2,
+		arg.Sort2,
+		arg.Sort2,
+		arg.Sort2,
+		arg.Sort2,
+		arg.Sort2,
+		arg.Sort3,
+		arg.Sort3,
+		arg.Sort3,
+		arg.Sort3,
+		arg.Sort3,
+		arg.Sort3,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchUsersRow
+	for rows.Next() {
+		var i SearchUsersRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Name,
+			&i.Dob,
+			&i.Email,
+			&i.Role,
+			&i.Active,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.AccountType,
+			&i.SignupSource,
+			&i.PublicID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const sessionStats = `-- name: SessionStats :one
+SELECT COUNT(*) AS total, MIN(expires_at) AS oldest
+FROM sessions
+`
+
+type SessionStatsRow struct {
+	Total  int64        `json:"total"`
+	Oldest sql.NullTime `json:"oldest"`
+}
+
+func (q *Queries) SessionStats(ctx context.Context) (SessionStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, sessionStats)
+	var i SessionStatsRow
+	err := row.Scan(&i.Total, &i.Oldest)
+	return i, err
+}
+
+const setNotificationPreference = `-- name: SetNotificationPreference :exec
+INSERT INTO notification_preferences (user_id, event_type, channel, enabled)
+VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE enabled = VALUES(enabled)
+`
+
+type SetNotificationPreferenceParams struct {
+	UserID    int64  `json:"user_id"`
+	EventType string `json:"event_type"`
+	Channel   string `json:"channel"`
+	Enabled   bool   `json:"enabled"`
+}
+
+func (q *Queries) SetNotificationPreference(ctx context.Context, arg SetNotificationPreferenceParams) error {
+	_, err := q.db.ExecContext(ctx, setNotificationPreference,
+		arg.UserID,
+		arg.EventType,
+		arg.Channel,
+		arg.Enabled,
+	)
+	return err
+}
+
+const setOrganizationMember = `-- name: SetOrganizationMember :exec
+INSERT INTO organization_members (user_id, org_id)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE org_id = VALUES(org_id), created_at = CURRENT_TIMESTAMP
+`
+
+type SetOrganizationMemberParams struct {
+	UserID int64 `json:"user_id"`
+	OrgID  int64 `json:"org_id"`
+}
+
+func (q *Queries) SetOrganizationMember(ctx context.Context, arg SetOrganizationMemberParams) error {
+	_, err := q.db.ExecContext(ctx, setOrganizationMember, arg.UserID, arg.OrgID)
+	return err
+}
+
+const setUserActive = `-- name: SetUserActive :execrows
+UPDATE users
+SET active = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL
+`
+
+type SetUserActiveParams struct {
+	Active bool  `json:"active"`
+	ID     int64 `json:"id"`
+}
+
+func (q *Queries) SetUserActive(ctx context.Context, arg SetUserActiveParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, setUserActive, arg.Active, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const setUserProfileAvatarKey = `-- name: SetUserProfileAvatarKey :exec
+INSERT INTO user_profiles (user_id, avatar_key)
+VALUES (?, ?)
+ON DUPLICATE KEY UPDATE avatar_key = VALUES(avatar_key), updated_at = CURRENT_TIMESTAMP
+`
+
+type SetUserProfileAvatarKeyParams struct {
+	UserID    int64  `json:"user_id"`
+	AvatarKey string `json:"avatar_key"`
+}
+
+func (q *Queries) SetUserProfileAvatarKey(ctx context.Context, arg SetUserProfileAvatarKeyParams) error {
+	_, err := q.db.ExecContext(ctx, setUserProfileAvatarKey, arg.UserID, arg.AvatarKey)
+	return err
+}
+
+const signupsByDay = `-- name: SignupsByDay :many
+SELECT DATE_FORMAT(created_at, '%Y-%m-%d') AS day, COUNT(*) AS signups
+FROM users
+WHERE created_at >= ? AND deleted_at IS NULL
+GROUP BY day
+ORDER BY day
+`
+
+type SignupsByDayRow struct {
+	Day     string `json:"day"`
+	Signups int64  `json:"signups"`
+}
+
+func (q *Queries) SignupsByDay(ctx context.Context, since time.Time) ([]SignupsByDayRow, error) {
+	rows, err := q.db.QueryContext(ctx, signupsByDay, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SignupsByDayRow
+	for rows.Next() {
+		var i SignupsByDayRow
+		if err := rows.Scan(&i.Day, &i.Signups); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const signupsByMonth = `-- name: SignupsByMonth :many
+SELECT DATE_FORMAT(created_at, '%Y-%m') AS month, COUNT(*) AS signups
+FROM users
+WHERE created_at >= ? AND deleted_at IS NULL
+GROUP BY month
+ORDER BY month
+`
+
+type SignupsByMonthRow struct {
+	Month   string `json:"month"`
+	Signups int64  `json:"signups"`
+}
+
+func (q *Queries) SignupsByMonth(ctx context.Context, since time.Time) ([]SignupsByMonthRow, error) {
+	rows, err := q.db.QueryContext(ctx, signupsByMonth, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SignupsByMonthRow
+	for rows.Next() {
+		var i SignupsByMonthRow
+		if err := rows.Scan(&i.Month, &i.Signups); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const signupsBySource = `-- name: SignupsBySource :many
+SELECT signup_source, COUNT(*) AS signups
+FROM users
+WHERE created_at >= ? AND deleted_at IS NULL
+GROUP BY signup_source
+ORDER BY signups DESC, signup_source
+`
+
+type SignupsBySourceRow struct {
+	SignupSource string `json:"signup_source"`
+	Signups      int64  `json:"signups"`
+}
+
+func (q *Queries) SignupsBySource(ctx context.Context, since time.Time) ([]SignupsBySourceRow, error) {
+	rows, err := q.db.QueryContext(ctx, signupsBySource, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SignupsBySourceRow
+	for rows.Next() {
+		var i SignupsBySourceRow
+		if err := rows.Scan(&i.SignupSource, &i.Signups); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const softDeleteUser = `-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL
+`
+
+func (q *Queries) SoftDeleteUser(ctx context.Context, id int64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, softDeleteUser, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const takeRateLimit = `-- name: TakeRateLimit :exec
+INSERT INTO rate_limit_counters (client, window_start, hits)
+VALUES (?, ?, 1)
+ON DUPLICATE KEY UPDATE
+    hits = IF(window_start <= ?, 1, LEAST(hits + 1, ? + 1)),
+    window_start = IF(window_start <= ?, VALUES(window_start), window_start)
+`
+
+type TakeRateLimitParams struct {
+	Client        string    `json:"client"`
+	Now           time.Time `json:"now"`
+	ExpiredBefore time.Time `json:"expired_before"`
+	MaxHits       int32     `json:"max_hits"`
+}
+
+func (q *Queries) TakeRateLimit(ctx context.Context, arg TakeRateLimitParams) error {
+	_, err := q.db.ExecContext(ctx, takeRateLimit,
+		arg.Client,
+		arg.Now,
+		arg.ExpiredBefore,
+		arg.MaxHits,
+		arg.ExpiredBefore,
+	)
+	return err
+}
+
+const topAPIUsage = `-- name: TopAPIUsage :many
+SELECT u.public_id, u.name, a.api_key_id, CAST(COALESCE(k.name, '') AS CHAR) AS key_name, CAST(SUM(a.requests) AS SIGNED) AS requests
+FROM api_usage a
+JOIN users u ON u.id = a.user_id
+LEFT JOIN api_keys k ON k.id = a.api_key_id
+WHERE a.day >= ? AND a.day <= ?
+GROUP BY u.public_id, u.name, a.api_key_id, k.name
+ORDER BY requests DESC, u.public_id, a.api_key_id
+LIMIT ?
+`
+
+type TopAPIUsageParams struct {
+	FromDay  time.Time `json:"from_day"`
+	ToDay    time.Time `json:"to_day"`
+	RowLimit int32     `json:"row_limit"`
+}
+
+type TopAPIUsageRow struct {
+	PublicID string `json:"public_id"`
+	Name     string `json:"name"`
+	ApiKeyID int64  `json:"api_key_id"`
+	KeyName  string `json:"key_name"`
+	Requests int64  `json:"requests"`
+}
+
+func (q *Queries) TopAPIUsage(ctx context.Context, arg TopAPIUsageParams) ([]TopAPIUsageRow, error) {
+	rows, err := q.db.QueryContext(ctx, topAPIUsage, arg.FromDay, arg.ToDay, arg.RowLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []TopAPIUsageRow
+	for rows.Next() {
+		var i TopAPIUsageRow
+		if err := rows.Scan(
+			&i.PublicID,
+			&i.Name,
+			&i.ApiKeyID,
+			&i.KeyName,
+			&i.Requests,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const touchAPIKey = `-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+func (q *Queries) TouchAPIKey(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, touchAPIKey, id)
+	return err
+}
+
+const updateNotificationRule = `-- name: UpdateNotificationRule :execrows
+UPDATE notification_rules
+SET event_type = ?, channel = ?, audience = ?, template = ?, webhook_url = ?, enabled = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdateNotificationRuleParams struct {
+	EventType  string `json:"event_type"`
+	Channel    string `json:"channel"`
+	Audience   string `json:"audience"`
+	Template   string `json:"template"`
+	WebhookUrl string `json:"webhook_url"`
+	Enabled    bool   `json:"enabled"`
+	ID         int64  `json:"id"`
+}
+
+func (q *Queries) UpdateNotificationRule(ctx context.Context, arg UpdateNotificationRuleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateNotificationRule,
+		arg.EventType,
+		arg.Channel,
+		arg.Audience,
+		arg.Template,
+		arg.WebhookUrl,
+		arg.Enabled,
+		arg.ID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateUser = `-- name: UpdateUser :execrows
+UPDATE users
+SET name = ?, dob = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL
+`
+
+type UpdateUserParams struct {
+	Name string    `json:"name"`
+	Dob  time.Time `json:"dob"`
+	ID   int64     `json:"id"`
+}
+
+func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUser, arg.Name, arg.Dob, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :execrows
+UPDATE users
+SET password_hash = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL
+`
+
+type UpdateUserPasswordParams struct {
+	PasswordHash string `json:"password_hash"`
+	ID           int64  `json:"id"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserPassword, arg.PasswordHash, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateUserRole = `-- name: UpdateUserRole :execrows
+UPDATE users
+SET role = ?, updated_at = CURRENT_TIMESTAMP
+WHERE id = ? AND deleted_at IS NULL
+`
+
+type UpdateUserRoleParams struct {
+	Role string `json:"role"`
+	ID   int64  `json:"id"`
+}
+
+func (q *Queries) UpdateUserRole(ctx context.Context, arg UpdateUserRoleParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateUserRole, arg.Role, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateWebAuthnCredentialUse = `-- name: UpdateWebAuthnCredentialUse :exec
+UPDATE webauthn_credentials
+SET sign_count = ?, last_used_at = CURRENT_TIMESTAMP
+WHERE id = ?
+`
+
+type UpdateWebAuthnCredentialUseParams struct {
+	SignCount int64 `json:"sign_count"`
+	ID        int64 `json:"id"`
+}
+
+func (q *Queries) UpdateWebAuthnCredentialUse(ctx context.Context, arg UpdateWebAuthnCredentialUseParams) error {
+	_, err := q.db.ExecContext(ctx, updateWebAuthnCredentialUse, arg.SignCount, arg.ID)
+	return err
+}
+
+const upsertOrganizationBilling = `-- name: UpsertOrganizationBilling :exec
+INSERT INTO organization_billing (org_id, provider, customer_id, subscription_id, plan, status, seats, current_period_end, event_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+    provider = IF(event_at <= VALUES(event_at), VALUES(provider), provider),
+    customer_id = IF(event_at <= VALUES(event_at), VALUES(customer_id), customer_id),
+    subscription_id = IF(event_at <= VALUES(event_at), VALUES(subscription_id), subscription_id),
+    plan = IF(event_at <= VALUES(event_at), VALUES(plan), plan),
+    status = IF(event_at <= VALUES(event_at), VALUES(status), status),
+    seats = IF(event_at <= VALUES(event_at), VALUES(seats), seats),
+    current_period_end = IF(event_at <= VALUES(event_at), VALUES(current_period_end), current_period_end),
+    updated_at = IF(event_at <= VALUES(event_at), CURRENT_TIMESTAMP, updated_at),
+    event_at = IF(event_at <= VALUES(event_at), VALUES(event_at), event_at)
+`
+
+type UpsertOrganizationBillingParams struct {
+	OrgID            int64        `json:"org_id"`
+	Provider         string       `json:"provider"`
+	CustomerID       string       `json:"customer_id"`
+	SubscriptionID   string       `json:"subscription_id"`
+	Plan             string       `json:"plan"`
+	Status           string       `json:"status"`
+	Seats            int64        `json:"seats"`
+	CurrentPeriodEnd sql.NullTime `json:"current_period_end"`
+	EventAt          time.Time    `json:"event_at"`
+}
+
+// MySQL applies the assignments in order, so event_at has to come last
+// for the others to compare against the stored value.
+func (q *Queries) UpsertOrganizationBilling(ctx context.Context, arg UpsertOrganizationBillingParams) error {
+	_, err := q.db.ExecContext(ctx, upsertOrganizationBilling,
+		arg.OrgID,
+		arg.Provider,
+		arg.CustomerID,
+		arg.SubscriptionID,
+		arg.Plan,
+		arg.Status,
+		arg.Seats,
+		arg.CurrentPeriodEnd,
+		arg.EventAt,
+	)
+	return err
+}
+
+const upsertUserProfile = `-- name: UpsertUserProfile :exec
+INSERT INTO user_profiles (user_id, bio, phone, avatar_url, locale, timezone)
+VALUES (?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE
+    bio = VALUES(bio), phone = VALUES(phone), avatar_url = VALUES(avatar_url),
+    locale = VALUES(locale), timezone = VALUES(timezone), updated_at = CURRENT_TIMESTAMP
+`
+
+type UpsertUserProfileParams struct {
+	UserID    int64  `json:"user_id"`
+	Bio       string `json:"bio"`
+	Phone     string `json:"phone"`
+	AvatarUrl string `json:"avatar_url"`
+	Locale    string `json:"locale"`
+	Timezone  string `json:"timezone"`
+}
+
+func (q *Queries) UpsertUserProfile(ctx context.Context, arg UpsertUserProfileParams) error {
+	_, err := q.db.ExecContext(ctx, upsertUserProfile,
+		arg.UserID,
+		arg.Bio,
+		arg.Phone,
+		arg.AvatarUrl,
+		arg.Locale,
+		arg.Timezone,
+	)
+	return err
+}
+
+const usersByAgeBracket = `-- name: UsersByAgeBracket :many
+SELECT bracket, COUNT(*) AS user_count
+FROM (
+    SELECT CASE
+        WHEN age < 18 THEN 'under_18'
+        WHEN age < 25 THEN '18_24'
+        WHEN age < 35 THEN '25_34'
+        WHEN age < 45 THEN '35_44'
+        WHEN age < 55 THEN '45_54'
+        WHEN age < 65 THEN '55_64'
+        ELSE '65_plus'
+    END AS bracket,
+    age
+    FROM (
+        SELECT TIMESTAMPDIFF(YEAR, dob, CURDATE()) AS age
+        FROM users
+        WHERE deleted_at IS NULL
+    ) ages
+) brackets
+GROUP BY bracket
+ORDER BY MIN(age)
+`
+
+type UsersByAgeBracketRow struct {
+	Bracket   string `json:"bracket"`
+	UserCount int64  `json:"user_count"`
+}
+
+func (q *Queries) UsersByAgeBracket(ctx context.Context) ([]UsersByAgeBracketRow, error) {
+	rows, err := q.db.QueryContext(ctx, usersByAgeBracket)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UsersByAgeBracketRow
+	for rows.Next() {
+		var i UsersByAgeBracketRow
+		if err := rows.Scan(&i.Bracket, &i.UserCount); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
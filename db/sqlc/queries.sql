@@ -1,39 +1,791 @@
+-- name: CreateUser :one
+INSERT INTO users (name, dob, email, password_hash, role, signup_source) 
+VALUES ($1, $2, $3, $4, COALESCE($5, 'user'), $6) 
+RETURNING id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id;
 
-INSERT INTO users (name, dob, email, password_hash, role) 
-VALUES ($1, $2, $3, $4, COALESCE($5, 'user')) 
-RETURNING id, name, dob, email, role, created_at, updated_at;
+-- name: GetUserByID :one
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id 
+FROM users 
+WHERE id = $1 AND deleted_at IS NULL;
 
+-- name: GetDeletedUserByID :one
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE id = $1 AND deleted_at IS NOT NULL;
 
-SELECT id, name, dob, email, role, created_at, updated_at 
-FROM users 
-WHERE id = $1;
+-- name: GetUserIDByPublicID :one
+SELECT id
+FROM users
+WHERE public_id = $1;
 
-SELECT id, name, dob, email, password_hash, role, created_at, updated_at 
+-- name: GetUserByEmail :one
+SELECT id, name, dob, email, password_hash, role, created_at, updated_at, active, account_type, signup_source, public_id, deleted_at 
 FROM users 
-WHERE email = $1;
+WHERE email = $1 AND deleted_at IS NULL;
 
-SELECT id, name, dob, email, role, created_at, updated_at 
+-- name: ListUsers :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id 
 FROM users 
+WHERE deleted_at IS NULL
 ORDER BY id;
 
-SELECT id, name, dob, email, role, created_at, updated_at 
+-- name: ListUsersPaginated :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id 
 FROM users 
+WHERE deleted_at IS NULL
 ORDER BY id
 LIMIT $1 OFFSET $2;
 
+-- name: ListUsersBySignupSource :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE signup_source = $1 AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: CountUsers :one
 SELECT COUNT(*) 
-FROM users;
+FROM users
+WHERE deleted_at IS NULL;
 
+-- name: SearchUsers :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE deleted_at IS NULL
+    AND (sqlc.narg(name_pattern)::text IS NULL OR name ILIKE sqlc.narg(name_pattern))
+    AND (sqlc.narg(email)::text IS NULL OR LOWER(email) = LOWER(sqlc.narg(email)))
+    AND (sqlc.narg(role)::text IS NULL OR role = sqlc.narg(role))
+    AND (sqlc.narg(signup_source)::text IS NULL OR signup_source = sqlc.narg(signup_source))
+    AND (sqlc.narg(dob_after)::date IS NULL OR dob > sqlc.narg(dob_after))
+    AND (sqlc.narg(dob_until)::date IS NULL OR dob <= sqlc.narg(dob_until))
+    AND (sqlc.narg(created_from)::timestamp IS NULL OR created_at >= sqlc.narg(created_from))
+    AND (sqlc.narg(created_before)::timestamp IS NULL OR created_at < sqlc.narg(created_before))
+    AND (sqlc.narg(org_id)::bigint IS NULL OR id IN (SELECT user_id FROM organization_members WHERE org_id = sqlc.narg(org_id)))
+ORDER BY
+    CASE sqlc.arg(sort1)::text WHEN 'name' THEN name WHEN 'email' THEN email WHEN 'role' THEN role END,
+    CASE sqlc.arg(sort1)::text WHEN 'dob' THEN dob END,
+    CASE sqlc.arg(sort1)::text WHEN 'created_at' THEN created_at WHEN 'updated_at' THEN updated_at END,
+    CASE sqlc.arg(sort1)::text WHEN '-name' THEN name WHEN '-email' THEN email WHEN '-role' THEN role END DESC,
+    CASE sqlc.arg(sort1)::text WHEN '-dob' THEN dob END DESC,
+    CASE sqlc.arg(sort1)::text WHEN '-created_at' THEN created_at WHEN '-updated_at' THEN updated_at END DESC,
+    CASE sqlc.arg(sort2)::text WHEN 'name' THEN name WHEN 'email' THEN email WHEN 'role' THEN role END,
+    CASE sqlc.arg(sort2)::text WHEN 'dob' THEN dob END,
+    CASE sqlc.arg(sort2)::text WHEN 'created_at' THEN created_at WHEN 'updated_at' THEN updated_at END,
+    CASE sqlc.arg(sort2)::text WHEN '-name' THEN name WHEN '-email' THEN email WHEN '-role' THEN role END DESC,
+    CASE sqlc.arg(sort2)::text WHEN '-dob' THEN dob END DESC,
+    CASE sqlc.arg(sort2)::text WHEN '-created_at' THEN created_at WHEN '-updated_at' THEN updated_at END DESC,
+    CASE sqlc.arg(sort3)::text WHEN 'name' THEN name WHEN 'email' THEN email WHEN 'role' THEN role END,
+    CASE sqlc.arg(sort3)::text WHEN 'dob' THEN dob END,
+    CASE sqlc.arg(sort3)::text WHEN 'created_at' THEN created_at WHEN 'updated_at' THEN updated_at END,
+    CASE sqlc.arg(sort3)::text WHEN '-name' THEN name WHEN '-email' THEN email WHEN '-role' THEN role END DESC,
+    CASE sqlc.arg(sort3)::text WHEN '-dob' THEN dob END DESC,
+    CASE sqlc.arg(sort3)::text WHEN '-created_at' THEN created_at WHEN '-updated_at' THEN updated_at END DESC,
+    id
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountSearchUsers :one
+SELECT COUNT(*)
+FROM users
+WHERE deleted_at IS NULL
+    AND (sqlc.narg(name_pattern)::text IS NULL OR name ILIKE sqlc.narg(name_pattern))
+    AND (sqlc.narg(email)::text IS NULL OR LOWER(email) = LOWER(sqlc.narg(email)))
+    AND (sqlc.narg(role)::text IS NULL OR role = sqlc.narg(role))
+    AND (sqlc.narg(signup_source)::text IS NULL OR signup_source = sqlc.narg(signup_source))
+    AND (sqlc.narg(dob_after)::date IS NULL OR dob > sqlc.narg(dob_after))
+    AND (sqlc.narg(dob_until)::date IS NULL OR dob <= sqlc.narg(dob_until))
+    AND (sqlc.narg(created_from)::timestamp IS NULL OR created_at >= sqlc.narg(created_from))
+    AND (sqlc.narg(created_before)::timestamp IS NULL OR created_at < sqlc.narg(created_before))
+    AND (sqlc.narg(org_id)::bigint IS NULL OR id IN (SELECT user_id FROM organization_members WHERE org_id = sqlc.narg(org_id)));
+
+-- name: UpdateUser :one
 UPDATE users 
 SET name = $2, dob = $3, updated_at = CURRENT_TIMESTAMP 
-WHERE id = $1 
-RETURNING id, name, dob, email, role, created_at, updated_at;
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id;
 
+-- name: UpdateUserPassword :one
 UPDATE users 
 SET password_hash = $2, updated_at = CURRENT_TIMESTAMP 
-WHERE id = $1 
+WHERE id = $1 AND deleted_at IS NULL
 RETURNING id, email, updated_at;
 
+-- name: SoftDeleteUser :execrows
+UPDATE users
+SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL;
+
+-- name: RestoreUser :one
+UPDATE users
+SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NOT NULL
+RETURNING id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id;
+
+-- name: DeleteUser :exec
 DELETE FROM users 
 WHERE id = $1;
 
+-- name: UsersByAgeBracket :many
+SELECT bracket::text AS bracket, COUNT(*) AS user_count
+FROM (
+    SELECT CASE
+        WHEN age < 18 THEN 'under_18'
+        WHEN age < 25 THEN '18_24'
+        WHEN age < 35 THEN '25_34'
+        WHEN age < 45 THEN '35_44'
+        WHEN age < 55 THEN '45_54'
+        WHEN age < 65 THEN '55_64'
+        ELSE '65_plus'
+    END AS bracket,
+    age
+    FROM (
+        SELECT date_part('year', age(CURRENT_DATE, dob))::int AS age
+        FROM users
+        WHERE deleted_at IS NULL
+    ) ages
+) brackets
+GROUP BY bracket
+ORDER BY MIN(age);
+
+-- name: AgeDistribution :many
+SELECT grp::text AS grp, (age / sqlc.arg(bucket_size)::int * sqlc.arg(bucket_size)::int)::int AS bucket_start, COUNT(*) AS user_count
+FROM (
+    SELECT CASE sqlc.arg(group_by)::text
+        WHEN 'role' THEN role
+        WHEN 'signup_cohort' THEN to_char(date_trunc('month', created_at), 'YYYY-MM')
+        ELSE ''
+    END AS grp,
+    date_part('year', age(CURRENT_DATE, dob))::int AS age
+    FROM users
+    WHERE deleted_at IS NULL
+) ages
+GROUP BY grp, bucket_start
+ORDER BY grp, bucket_start;
+
+-- name: SignupsByDay :many
+SELECT to_char(date_trunc('day', created_at), 'YYYY-MM-DD')::text AS day, COUNT(*) AS signups
+FROM users
+WHERE created_at >= sqlc.arg(since)::timestamp AND deleted_at IS NULL
+GROUP BY day
+ORDER BY day;
+
+-- name: SignupsByMonth :many
+SELECT to_char(date_trunc('month', created_at), 'YYYY-MM')::text AS month, COUNT(*) AS signups
+FROM users
+WHERE created_at >= sqlc.arg(since)::timestamp AND deleted_at IS NULL
+GROUP BY month
+ORDER BY month;
+
+-- name: SignupsBySource :many
+SELECT signup_source, COUNT(*) AS signups
+FROM users
+WHERE created_at >= sqlc.arg(since)::timestamp AND deleted_at IS NULL
+GROUP BY signup_source
+ORDER BY signups DESC, signup_source;
+
+-- name: ListUpcomingBirthdays :many
+SELECT id, name, dob, public_id
+FROM users
+WHERE deleted_at IS NULL
+    AND ((EXTRACT(MONTH FROM dob)::int * 100 + EXTRACT(DAY FROM dob)::int) BETWEEN sqlc.arg(from_day)::int AND sqlc.arg(to_day)::int
+    OR (EXTRACT(MONTH FROM dob)::int * 100 + EXTRACT(DAY FROM dob)::int) BETWEEN sqlc.arg(wrap_from)::int AND sqlc.arg(wrap_to)::int)
+ORDER BY (EXTRACT(MONTH FROM dob)::int * 100 + EXTRACT(DAY FROM dob)::int) < sqlc.arg(from_day)::int,
+    EXTRACT(MONTH FROM dob)::int * 100 + EXTRACT(DAY FROM dob)::int, id
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListDeactivatedUsers :many
+SELECT id, name, email, updated_at
+FROM users
+WHERE active = FALSE AND updated_at >= sqlc.arg(since)::timestamp AND deleted_at IS NULL
+ORDER BY updated_at DESC, id;
+
+-- name: ListActiveAdmins :many
+SELECT id, name, email
+FROM users
+WHERE role = 'admin' AND active = TRUE AND account_type = 'human' AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: GetUserStats :one
+SELECT total_users, admin_users, signups_last_7_days, signups_last_30_days, average_age, refreshed_at
+FROM user_stats
+WHERE id = 1;
+
+-- name: RefreshUserStats :exec
+REFRESH MATERIALIZED VIEW CONCURRENTLY user_stats;
+
+-- name: SetUserActive :one
+UPDATE users
+SET active = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id;
+
+-- name: UpdateUserRole :one
+UPDATE users
+SET role = $2, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND deleted_at IS NULL
+RETURNING id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id;
+
+
+-- name: CreateServiceAccount :one
+INSERT INTO users (name, dob, email, password_hash, role, account_type, signup_source)
+VALUES ($1, CURRENT_DATE, $2, $3, $4, 'service', 'admin')
+RETURNING id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id;
+
+-- name: ListServiceAccounts :many
+SELECT id, name, dob, email, role, active, created_at, updated_at, account_type, signup_source, public_id
+FROM users
+WHERE account_type = 'service' AND deleted_at IS NULL
+ORDER BY id;
+
+-- name: CreateAPIKey :one
+INSERT INTO api_keys (user_id, name, prefix, key_hash, scopes, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, user_id, name, prefix, scopes, created_at, expires_at, last_used_at, revoked_at;
+
+-- name: ListAPIKeysByUser :many
+SELECT id, user_id, name, prefix, scopes, created_at, expires_at, last_used_at, revoked_at
+FROM api_keys
+WHERE user_id = $1
+ORDER BY id;
+
+-- name: GetAPIKeyByHash :one
+SELECT k.id, k.user_id, k.scopes, k.expires_at, k.revoked_at, u.role, u.active, u.account_type
+FROM api_keys k
+JOIN users u ON u.id = k.user_id
+WHERE k.key_hash = $1 AND u.deleted_at IS NULL;
+
+-- name: RevokeAPIKey :execrows
+UPDATE api_keys
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: TouchAPIKey :exec
+UPDATE api_keys
+SET last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: RecordLogin :exec
+INSERT INTO login_history (user_id, email, succeeded, reason, ip_address, user_agent, country, city)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: ListLoginHistoryByUser :many
+SELECT id, email, succeeded, reason, ip_address, user_agent, country, city, created_at
+FROM login_history
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2;
+
+-- name: LoginHistoryStats :one
+SELECT COUNT(*) AS total, MIN(created_at)::timestamp AS oldest
+FROM login_history;
+
+-- name: FailedLoginsByDay :many
+SELECT to_char(date_trunc('day', created_at), 'YYYY-MM-DD')::text AS day, COUNT(*) AS failures
+FROM login_history
+WHERE succeeded = FALSE AND created_at >= sqlc.arg(since)::timestamp
+GROUP BY day
+ORDER BY day;
+
+-- name: PruneLoginHistory :execrows
+DELETE FROM login_history
+WHERE created_at < $1;
+
+-- name: CreateWebAuthnCredential :one
+INSERT INTO webauthn_credentials (user_id, credential_id, public_key, sign_count, name)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_id, credential_id, public_key, sign_count, name, created_at, last_used_at;
+
+-- name: GetWebAuthnCredential :one
+SELECT id, user_id, credential_id, public_key, sign_count, name, created_at, last_used_at
+FROM webauthn_credentials
+WHERE credential_id = $1;
+
+-- name: ListWebAuthnCredentialsByUser :many
+SELECT id, user_id, credential_id, public_key, sign_count, name, created_at, last_used_at
+FROM webauthn_credentials
+WHERE user_id = $1
+ORDER BY id;
+
+-- name: UpdateWebAuthnCredentialUse :exec
+UPDATE webauthn_credentials
+SET sign_count = $2, last_used_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: DeleteWebAuthnCredential :execrows
+DELETE FROM webauthn_credentials
+WHERE id = $1 AND user_id = $2;
+
+-- name: RevokeUserTokens :exec
+INSERT INTO token_revocations (user_id, revoked_at)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET revoked_at = EXCLUDED.revoked_at;
+
+-- name: ListTokenRevocations :many
+SELECT user_id, revoked_at
+FROM token_revocations;
+
+-- name: CreateNameReview :one
+INSERT INTO name_reviews (user_id, name, matched)
+VALUES ($1, $2, $3)
+RETURNING id, user_id, name, matched, status, reviewed_by, created_at, reviewed_at;
+
+-- name: GetNameReview :one
+SELECT id, user_id, name, matched, status, reviewed_by, created_at, reviewed_at
+FROM name_reviews
+WHERE id = $1;
+
+-- name: ListNameReviews :many
+SELECT id, user_id, name, matched, status, reviewed_by, created_at, reviewed_at
+FROM name_reviews
+WHERE status = $1
+ORDER BY created_at, id
+LIMIT $2;
+
+-- name: ResolveNameReview :execrows
+UPDATE name_reviews
+SET status = $2, reviewed_by = $3, reviewed_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND status = 'pending';
+
+-- name: GetReferralCode :one
+SELECT code
+FROM referral_codes
+WHERE user_id = $1;
+
+-- name: CreateReferralCode :execrows
+INSERT INTO referral_codes (user_id, code)
+VALUES ($1, $2)
+ON CONFLICT DO NOTHING;
+
+-- name: GetReferralCodeOwner :one
+SELECT user_id
+FROM referral_codes
+WHERE code = $1;
+
+-- name: CreateReferral :execrows
+INSERT INTO referrals (referrer_id, referred_id)
+VALUES ($1, $2)
+ON CONFLICT (referred_id) DO NOTHING;
+
+-- name: ReferralStats :one
+SELECT COUNT(*) AS total, COUNT(CASE WHEN created_at >= sqlc.arg(since)::timestamp THEN 1 END) AS recent
+FROM referrals
+WHERE referrer_id = sqlc.arg(referrer_id);
+
+-- name: CreateUserIdentity :one
+INSERT INTO user_identities (user_id, provider, subject, email)
+VALUES ($1, $2, $3, $4)
+RETURNING id, user_id, provider, subject, email, created_at;
+
+-- name: GetUserIdentityBySubject :one
+SELECT id, user_id, provider, subject, email, created_at
+FROM user_identities
+WHERE provider = $1 AND subject = $2;
+
+-- name: ListUserIdentitiesByUser :many
+SELECT id, user_id, provider, subject, email, created_at
+FROM user_identities
+WHERE user_id = $1
+ORDER BY provider;
+
+-- name: DeleteUserIdentity :execrows
+DELETE FROM user_identities
+WHERE user_id = $1 AND provider = $2;
+
+-- name: CreateOrganization :one
+INSERT INTO organizations (name)
+VALUES ($1)
+RETURNING id, name, created_at;
+
+-- name: GetOrganization :one
+SELECT id, name, created_at
+FROM organizations
+WHERE id = $1;
+
+-- name: ListOrganizations :many
+SELECT id, name, created_at
+FROM organizations
+ORDER BY id;
+
+-- name: SetOrganizationMember :exec
+INSERT INTO organization_members (user_id, org_id)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET org_id = EXCLUDED.org_id, created_at = CURRENT_TIMESTAMP;
+
+-- name: GetOrganizationMembership :one
+SELECT org_id
+FROM organization_members
+WHERE user_id = $1;
+
+-- name: DeleteOrganizationMember :execrows
+DELETE FROM organization_members
+WHERE user_id = $1;
+
+-- name: AggregateOrganizationUsage :exec
+INSERT INTO organization_usage (org_id, day, seats, storage_bytes)
+SELECT o.id, sqlc.arg(day)::date,
+    COUNT(u.id) FILTER (WHERE u.active AND u.account_type <> 'service'),
+    COALESCE(SUM(pg_column_size(u.*)), 0) + COALESCE((
+        SELECT SUM(pg_column_size(l.*))
+        FROM login_history l
+        JOIN organization_members lm ON lm.user_id = l.user_id
+        WHERE lm.org_id = o.id
+    ), 0)
+FROM organizations o
+LEFT JOIN organization_members m ON m.org_id = o.id
+LEFT JOIN users u ON u.id = m.user_id AND u.deleted_at IS NULL
+GROUP BY o.id
+ON CONFLICT (org_id, day) DO UPDATE SET seats = EXCLUDED.seats, storage_bytes = EXCLUDED.storage_bytes;
+
+-- name: AddOrganizationAPICalls :exec
+INSERT INTO organization_usage (org_id, day, api_calls)
+SELECT org_id, sqlc.arg(day)::date, sqlc.arg(calls)::bigint
+FROM organization_members
+WHERE user_id = sqlc.arg(user_id)
+ON CONFLICT (org_id, day) DO UPDATE SET api_calls = organization_usage.api_calls + EXCLUDED.api_calls;
+
+-- name: ListOrganizationUsage :many
+SELECT org_id, day, seats, api_calls, storage_bytes
+FROM organization_usage
+WHERE org_id = sqlc.arg(org_id) AND day >= sqlc.arg(from_day)::date AND day <= sqlc.arg(to_day)::date
+ORDER BY day;
+
+-- name: CountOrganizationSeats :one
+SELECT COUNT(*)
+FROM organization_members m
+JOIN users u ON u.id = m.user_id
+WHERE m.org_id = sqlc.arg(org_id) AND m.user_id <> sqlc.arg(except_user_id) AND u.active AND u.account_type <> 'service' AND u.deleted_at IS NULL;
+
+-- name: GetOrganizationBilling :one
+SELECT org_id, provider, customer_id, subscription_id, plan, status, seats, current_period_end, event_at, updated_at
+FROM organization_billing
+WHERE org_id = $1;
+
+-- name: GetOrganizationBillingByCustomer :one
+SELECT org_id, provider, customer_id, subscription_id, plan, status, seats, current_period_end, event_at, updated_at
+FROM organization_billing
+WHERE provider = $1 AND customer_id = $2;
+
+-- name: UpsertOrganizationBilling :exec
+INSERT INTO organization_billing (org_id, provider, customer_id, subscription_id, plan, status, seats, current_period_end, event_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (org_id) DO UPDATE SET
+    provider = EXCLUDED.provider,
+    customer_id = EXCLUDED.customer_id,
+    subscription_id = EXCLUDED.subscription_id,
+    plan = EXCLUDED.plan,
+    status = EXCLUDED.status,
+    seats = EXCLUDED.seats,
+    current_period_end = EXCLUDED.current_period_end,
+    event_at = EXCLUDED.event_at,
+    updated_at = CURRENT_TIMESTAMP
+WHERE organization_billing.event_at <= EXCLUDED.event_at;
+
+-- name: AddAPIUsage :exec
+INSERT INTO api_usage (user_id, api_key_id, day, requests)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, api_key_id, day) DO UPDATE SET requests = api_usage.requests + EXCLUDED.requests;
+
+-- name: ListAPIUsageByUser :many
+SELECT a.api_key_id, COALESCE(k.name, '')::text AS key_name, a.day, a.requests
+FROM api_usage a
+LEFT JOIN api_keys k ON k.id = a.api_key_id
+WHERE a.user_id = sqlc.arg(user_id) AND a.day >= sqlc.arg(from_day)::date AND a.day <= sqlc.arg(to_day)::date
+ORDER BY a.day, a.api_key_id;
+
+-- name: TopAPIUsage :many
+SELECT u.public_id, u.name, a.api_key_id, COALESCE(k.name, '')::text AS key_name, SUM(a.requests)::bigint AS requests
+FROM api_usage a
+JOIN users u ON u.id = a.user_id
+LEFT JOIN api_keys k ON k.id = a.api_key_id
+WHERE a.day >= sqlc.arg(from_day)::date AND a.day <= sqlc.arg(to_day)::date
+GROUP BY u.public_id, u.name, a.api_key_id, k.name
+ORDER BY requests DESC, u.public_id, a.api_key_id
+LIMIT sqlc.arg(row_limit);
+
+-- name: AcquireJobLease :execrows
+INSERT INTO job_leases (name, holder, expires_at)
+VALUES (sqlc.arg(name), sqlc.arg(holder), CURRENT_TIMESTAMP + sqlc.arg(ttl_ms)::bigint * INTERVAL '1 millisecond')
+ON CONFLICT (name) DO UPDATE SET holder = EXCLUDED.holder, expires_at = EXCLUDED.expires_at
+WHERE job_leases.holder = EXCLUDED.holder OR job_leases.expires_at < CURRENT_TIMESTAMP;
+
+-- name: ReleaseJobLease :exec
+DELETE FROM job_leases WHERE name = $1 AND holder = $2;
+
+-- name: TakeRateLimit :one
+INSERT INTO rate_limit_counters (client, window_start, hits)
+VALUES (sqlc.arg(client), sqlc.arg(now)::timestamp, 1)
+ON CONFLICT (client) DO UPDATE SET
+    window_start = CASE WHEN rate_limit_counters.window_start <= sqlc.arg(expired_before)::timestamp THEN EXCLUDED.window_start ELSE rate_limit_counters.window_start END,
+    hits = CASE WHEN rate_limit_counters.window_start <= sqlc.arg(expired_before)::timestamp THEN 1 ELSE LEAST(rate_limit_counters.hits + 1, sqlc.arg(max_hits)::int + 1) END
+RETURNING hits, window_start;
+
+-- name: GetRateLimit :one
+SELECT hits, window_start FROM rate_limit_counters WHERE client = $1;
+
+-- name: RateLimitStats :one
+SELECT COUNT(*) AS total, MIN(window_start)::timestamp AS oldest
+FROM rate_limit_counters;
+
+-- name: DeleteExpiredRateLimits :execrows
+DELETE FROM rate_limit_counters WHERE window_start <= $1;
+
+-- name: AppendSecurityEvent :one
+INSERT INTO security_events (event_type, actor_id, target_id, details, ip_address, created_at, prev_hash, hash)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id;
+
+-- name: GetLastSecurityEventHash :one
+SELECT hash FROM security_events ORDER BY id DESC LIMIT 1;
+
+-- name: ListSecurityEventsAfter :many
+SELECT id, event_type, actor_id, target_id, details, ip_address, created_at, prev_hash, hash
+FROM security_events
+WHERE id > $1
+ORDER BY id
+LIMIT $2;
+
+-- name: ListSecurityEvents :many
+SELECT e.id, e.event_type, actor.public_id AS actor_public_id, target.public_id AS target_public_id, e.details, e.ip_address, e.created_at, e.hash
+FROM security_events e
+LEFT JOIN users actor ON actor.id = e.actor_id
+LEFT JOIN users target ON target.id = e.target_id
+WHERE e.id < $1
+ORDER BY e.id DESC
+LIMIT $2;
+
+-- name: CreateRefreshToken :exec
+INSERT INTO refresh_tokens (user_id, token_hash, family, created_at, expires_at)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: GetRefreshTokenByHash :one
+SELECT id, user_id, family, created_at, expires_at, revoked_at
+FROM refresh_tokens
+WHERE token_hash = $1;
+
+-- name: RevokeRefreshToken :execrows
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeRefreshTokenFamily :exec
+UPDATE refresh_tokens
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE family = $1 AND revoked_at IS NULL;
+
+-- name: RevokeToken :exec
+INSERT INTO revoked_tokens (jti, user_id, expires_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (jti) DO NOTHING;
+
+-- name: ListRevokedTokens :many
+SELECT jti, expires_at
+FROM revoked_tokens
+WHERE expires_at > $1;
+
+-- name: RevokedTokenStats :one
+SELECT COUNT(*) AS total, MIN(expires_at)::timestamp AS oldest
+FROM revoked_tokens;
+
+-- name: PruneRevokedTokens :execrows
+DELETE FROM revoked_tokens
+WHERE expires_at < $1;
+
+-- name: RecordLoginFailure :one
+INSERT INTO login_lockouts (lock_key, failures, window_start)
+VALUES (sqlc.arg(lock_key), 1, sqlc.arg(now)::timestamp)
+ON CONFLICT (lock_key) DO UPDATE SET
+    failures = CASE WHEN login_lockouts.window_start <= sqlc.arg(expired_before)::timestamp THEN 1 ELSE login_lockouts.failures + 1 END,
+    window_start = CASE WHEN login_lockouts.window_start <= sqlc.arg(expired_before)::timestamp THEN EXCLUDED.window_start ELSE login_lockouts.window_start END
+RETURNING failures;
+
+-- name: GetLoginLockout :one
+SELECT failures, window_start, locked_until FROM login_lockouts WHERE lock_key = $1;
+
+-- name: LockLogin :exec
+UPDATE login_lockouts SET locked_until = $2 WHERE lock_key = $1;
+
+-- name: ClearLoginLockout :execrows
+DELETE FROM login_lockouts WHERE lock_key = $1;
+
+-- name: ListLoginLockouts :many
+SELECT lock_key, failures, locked_until
+FROM login_lockouts
+WHERE locked_until > $1
+ORDER BY locked_until DESC;
+
+-- name: LoginLockoutStats :one
+SELECT COUNT(*) AS total, MIN(window_start)::timestamp AS oldest
+FROM login_lockouts;
+
+-- name: DeleteExpiredLoginLockouts :execrows
+DELETE FROM login_lockouts
+WHERE window_start <= sqlc.arg(expired_before)::timestamp AND (locked_until IS NULL OR locked_until <= sqlc.arg(now)::timestamp);
+
+-- name: CreateSession :exec
+INSERT INTO sessions (id, user_id, ip_address, user_agent, created_at, expires_at)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: ListActiveSessions :many
+SELECT id, ip_address, user_agent, created_at, expires_at
+FROM sessions
+WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+ORDER BY created_at, id;
+
+-- name: ExtendSession :exec
+UPDATE sessions
+SET expires_at = $2
+WHERE id = $1 AND revoked_at IS NULL;
+
+-- name: RevokeSession :execrows
+UPDATE sessions
+SET revoked_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL;
+
+-- name: SessionStats :one
+SELECT COUNT(*) AS total, MIN(expires_at)::timestamp AS oldest
+FROM sessions;
+
+-- name: PruneSessions :execrows
+DELETE FROM sessions
+WHERE expires_at < $1;
+
+-- name: CreateEmailDeadLetter :one
+INSERT INTO email_dead_letters (recipients, subject, html, error, attempts)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, recipients, subject, html, error, attempts, created_at, last_attempt_at;
+
+-- name: GetEmailDeadLetter :one
+SELECT id, recipients, subject, html, error, attempts, created_at, last_attempt_at
+FROM email_dead_letters
+WHERE id = $1;
+
+-- name: ListEmailDeadLetters :many
+SELECT id, recipients, subject, html, error, attempts, created_at, last_attempt_at
+FROM email_dead_letters
+ORDER BY created_at DESC, id DESC
+LIMIT $1;
+
+-- name: RecordEmailDeadLetterAttempt :exec
+UPDATE email_dead_letters
+SET error = $2, attempts = attempts + 1, last_attempt_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: DeleteEmailDeadLetter :execrows
+DELETE FROM email_dead_letters
+WHERE id = $1;
+
+-- name: CreateNotificationRule :one
+INSERT INTO notification_rules (event_type, channel, audience, template, webhook_url, enabled)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, event_type, channel, audience, template, webhook_url, enabled, created_at, updated_at;
+
+-- name: GetNotificationRule :one
+SELECT id, event_type, channel, audience, template, webhook_url, enabled, created_at, updated_at
+FROM notification_rules
+WHERE id = $1;
+
+-- name: ListNotificationRules :many
+SELECT id, event_type, channel, audience, template, webhook_url, enabled, created_at, updated_at
+FROM notification_rules
+ORDER BY event_type, id;
+
+-- name: ListNotificationRulesForEvent :many
+SELECT id, event_type, channel, audience, template, webhook_url, enabled, created_at, updated_at
+FROM notification_rules
+WHERE event_type = $1 AND enabled
+ORDER BY id;
+
+-- name: UpdateNotificationRule :execrows
+UPDATE notification_rules
+SET event_type = $2, channel = $3, audience = $4, template = $5, webhook_url = $6, enabled = $7, updated_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: DeleteNotificationRule :execrows
+DELETE FROM notification_rules
+WHERE id = $1;
+
+-- name: ListNotificationPreferences :many
+SELECT user_id, event_type, channel, enabled
+FROM notification_preferences
+WHERE user_id = $1
+ORDER BY event_type, channel;
+
+-- name: SetNotificationPreference :exec
+INSERT INTO notification_preferences (user_id, event_type, channel, enabled)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id, event_type, channel) DO UPDATE SET enabled = EXCLUDED.enabled;
+
+-- name: CreateNotification :exec
+INSERT INTO notifications (user_id, event_type, title, details)
+VALUES ($1, $2, $3, $4);
+
+-- name: ListNotifications :many
+SELECT id, user_id, event_type, title, details, created_at, read_at
+FROM notifications
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC
+LIMIT $2;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = CURRENT_TIMESTAMP
+WHERE id = $1 AND user_id = $2 AND read_at IS NULL;
+
+-- name: NotificationStats :one
+SELECT COUNT(*) AS total, MIN(created_at)::timestamp AS oldest
+FROM notifications;
+
+-- name: PruneNotifications :execrows
+DELETE FROM notifications
+WHERE created_at < $1;
+
+-- name: LookupUsersByEmail :many
+SELECT id, name, email, role, active, account_type, public_id
+FROM users
+WHERE deleted_at IS NULL AND LOWER(email) LIKE sqlc.arg(pattern)::text
+ORDER BY LOWER(email), id
+LIMIT sqlc.arg(row_limit);
+
+-- name: GetUserProfile :one
+SELECT user_id, bio, phone, avatar_url, locale, timezone, updated_at, avatar_key
+FROM user_profiles
+WHERE user_id = $1;
+
+-- name: UpsertUserProfile :one
+INSERT INTO user_profiles (user_id, bio, phone, avatar_url, locale, timezone)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (user_id) DO UPDATE
+SET bio = EXCLUDED.bio, phone = EXCLUDED.phone, avatar_url = EXCLUDED.avatar_url,
+    locale = EXCLUDED.locale, timezone = EXCLUDED.timezone, updated_at = CURRENT_TIMESTAMP
+RETURNING user_id, bio, phone, avatar_url, locale, timezone, updated_at, avatar_key;
+
+-- name: SetUserProfileAvatarKey :exec
+INSERT INTO user_profiles (user_id, avatar_key)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET avatar_key = EXCLUDED.avatar_key, updated_at = CURRENT_TIMESTAMP;
+
+-- name: RedeemMagicLink :execrows
+INSERT INTO magic_link_redemptions (token_hash, expires_at)
+VALUES ($1, $2)
+ON CONFLICT (token_hash) DO NOTHING;
+
+-- name: MagicLinkRedemptionStats :one
+SELECT COUNT(*) AS total, MIN(expires_at)::timestamp AS oldest
+FROM magic_link_redemptions;
+
+-- name: DeleteExpiredMagicLinkRedemptions :execrows
+DELETE FROM magic_link_redemptions WHERE expires_at <= $1;
+
+-- name: CreateSSOPendingLogin :exec
+INSERT INTO sso_pending_logins (state_hash, nonce, code_verifier, expires_at)
+VALUES ($1, $2, $3, $4);
+
+-- name: TakeSSOPendingLogin :one
+DELETE FROM sso_pending_logins WHERE state_hash = $1
+RETURNING state_hash, nonce, code_verifier, expires_at;
+
+-- name: SSOPendingLoginStats :one
+SELECT COUNT(*) AS total, MIN(expires_at)::timestamp AS oldest
+FROM sso_pending_logins;
+
+-- name: DeleteExpiredSSOPendingLogins :execrows
+DELETE FROM sso_pending_logins WHERE expires_at <= $1;
@@ -0,0 +1,121 @@
+// Package hooks lets deployers run custom logic at fixed points in the user
+// lifecycle, either as Go functions (when embedding via useapi) or as
+// external HTTP endpoints.
+package hooks
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type Event string
+
+const (
+	// BeforeUserCreate runs before any user is inserted (signup, admin
+	// create, SCIM, SSO provisioning). Hooks may edit the user or veto.
+	BeforeUserCreate Event = "before_user_create"
+	// AfterLogin runs after a successful password or SSO login. It runs in
+	// the background; errors are logged and never fail the login.
+	AfterLogin Event = "after_login"
+	// BeforeDelete runs before a user is deleted. Hooks may veto.
+	BeforeDelete Event = "before_delete"
+)
+
+const afterHookTimeout = 10 * time.Second
+
+type User struct {
+	ID           int64     `json:"id,omitempty"`
+	Name         string    `json:"name"`
+	Email        string    `json:"email,omitempty"`
+	Role         string    `json:"role,omitempty"`
+	AccountType  string    `json:"account_type,omitempty"`
+	SignupSource string    `json:"signup_source,omitempty"`
+	Dob          time.Time `json:"dob"`
+}
+
+type Hook func(ctx context.Context, u *User) error
+
+// RejectedError is returned by a hook to veto an operation. Reason is shown
+// to the client.
+type RejectedError struct {
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return "rejected by hook: " + e.Reason
+}
+
+func Reject(reason string) error {
+	return &RejectedError{Reason: reason}
+}
+
+type Registry struct {
+	mu     sync.RWMutex
+	hooks  map[Event][]Hook
+	logger *zap.Logger
+}
+
+func NewRegistry(logger *zap.Logger) *Registry {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Registry{
+		hooks:  make(map[Event][]Hook),
+		logger: logger,
+	}
+}
+
+// Register adds a hook for an event. Hooks run in registration order.
+func (r *Registry) Register(event Event, h Hook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hooks[event] = append(r.hooks[event], h)
+}
+
+// Run executes the hooks for an event, stopping at the first error. A nil
+// registry runs nothing.
+func (r *Registry) Run(ctx context.Context, event Event, u *User) error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	registered := append([]Hook(nil), r.hooks[event]...)
+	r.mu.RUnlock()
+
+	if len(registered) == 0 {
+		return nil
+	}
+
+	if event == AfterLogin {
+		r.runDetached(ctx, event, *u, registered)
+		return nil
+	}
+
+	for _, h := range registered {
+		if err := h(ctx, u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Registry) runDetached(ctx context.Context, event Event, u User, registered []Hook) {
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, afterHookTimeout)
+		defer cancel()
+		for _, h := range registered {
+			if err := h(ctx, &u); err != nil {
+				r.logger.Warn("hook failed",
+					zap.String("event", string(event)),
+					zap.Int64("user_id", u.ID),
+					zap.Error(err),
+				)
+			}
+		}
+	}()
+}
@@ -0,0 +1,132 @@
+package hooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRegistryRunsInOrderAndStopsOnError(t *testing.T) {
+	r := NewRegistry(nil)
+	var calls []string
+
+	r.Register(BeforeUserCreate, func(ctx context.Context, u *User) error {
+		calls = append(calls, "first")
+		u.Name = "Edited"
+		return nil
+	})
+	r.Register(BeforeUserCreate, func(ctx context.Context, u *User) error {
+		calls = append(calls, "second")
+		return Reject("no thanks")
+	})
+	r.Register(BeforeUserCreate, func(ctx context.Context, u *User) error {
+		calls = append(calls, "third")
+		return nil
+	})
+
+	u := &User{Name: "Jane"}
+	err := r.Run(context.Background(), BeforeUserCreate, u)
+
+	var rejected *RejectedError
+	if !errors.As(err, &rejected) || rejected.Reason != "no thanks" {
+		t.Fatalf("expected rejection, got %v", err)
+	}
+	if len(calls) != 2 {
+		t.Errorf("expected hooks to stop after the rejection, ran %v", calls)
+	}
+	if u.Name != "Edited" {
+		t.Errorf("expected hook edits to be visible, got name %q", u.Name)
+	}
+}
+
+func TestNilRegistry(t *testing.T) {
+	var r *Registry
+	if err := r.Run(context.Background(), BeforeDelete, &User{ID: 1}); err != nil {
+		t.Errorf("expected nil registry to run nothing, got %v", err)
+	}
+}
+
+func TestAfterLoginRunsInBackground(t *testing.T) {
+	r := NewRegistry(nil)
+	done := make(chan int64, 1)
+	r.Register(AfterLogin, func(ctx context.Context, u *User) error {
+		done <- u.ID
+		return errors.New("ignored")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := r.Run(ctx, AfterLogin, &User{ID: 7}); err != nil {
+		t.Fatalf("expected after-login errors to be swallowed, got %v", err)
+	}
+	cancel()
+
+	select {
+	case id := <-done:
+		if id != 7 {
+			t.Errorf("expected user 7, got %d", id)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("after-login hook did not run")
+	}
+}
+
+func TestHTTPHook(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     int
+		body       string
+		wantReject string
+		wantErr    bool
+	}{
+		{name: "allowed", status: http.StatusNoContent},
+		{name: "rejected", status: http.StatusUnprocessableEntity, body: `{"message":"domain not allowed"}`, wantReject: "domain not allowed"},
+		{name: "rejected without message", status: http.StatusForbidden, wantReject: "request was rejected"},
+		{name: "server error fails closed", status: http.StatusBadGateway, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotSig string
+			var gotPayload httpPayload
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				gotSig = r.Header.Get(SignatureHeader)
+				_ = json.Unmarshal(body, &gotPayload)
+				if gotSig != Sign("s3cret", body) {
+					t.Errorf("signature mismatch")
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			hook := HTTPHook(BeforeUserCreate, srv.URL, "s3cret", time.Second)
+			err := hook(context.Background(), &User{Email: "jane@example.com"})
+
+			if gotPayload.Event != BeforeUserCreate || gotPayload.User == nil || gotPayload.User.Email != "jane@example.com" {
+				t.Errorf("unexpected payload %+v", gotPayload)
+			}
+
+			var rejected *RejectedError
+			switch {
+			case tt.wantReject != "":
+				if !errors.As(err, &rejected) || rejected.Reason != tt.wantReject {
+					t.Errorf("expected rejection %q, got %v", tt.wantReject, err)
+				}
+			case tt.wantErr:
+				if err == nil || errors.As(err, &rejected) {
+					t.Errorf("expected a non-rejection error, got %v", err)
+				}
+			default:
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+			}
+		})
+	}
+}
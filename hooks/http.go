@@ -0,0 +1,72 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const SignatureHeader = "X-Hook-Signature"
+
+type httpPayload struct {
+	Event Event `json:"event"`
+	User  *User `json:"user"`
+}
+
+// HTTPHook POSTs {"event", "user"} as JSON to url. A 2xx response allows the
+// operation; a 4xx rejects it with the response's "message" field as the
+// reason. Anything else is an error, so before-hooks fail closed. When secret
+// is set the body is signed with HMAC-SHA256 in the X-Hook-Signature header.
+func HTTPHook(event Event, url, secret string, timeout time.Duration) Hook {
+	client := &http.Client{Timeout: timeout}
+
+	return func(ctx context.Context, u *User) error {
+		body, err := json.Marshal(httpPayload{Event: event, User: u})
+		if err != nil {
+			return err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		if secret != "" {
+			req.Header.Set(SignatureHeader, Sign(secret, body))
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("%s hook: %w", event, err)
+		}
+		defer resp.Body.Close()
+
+		switch {
+		case resp.StatusCode >= 200 && resp.StatusCode < 300:
+			return nil
+		case resp.StatusCode >= 400 && resp.StatusCode < 500:
+			var out struct {
+				Message string `json:"message"`
+			}
+			_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&out)
+			if out.Message == "" {
+				out.Message = "request was rejected"
+			}
+			return Reject(out.Message)
+		}
+		return fmt.Errorf("%s hook: endpoint returned %d", event, resp.StatusCode)
+	}
+}
+
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
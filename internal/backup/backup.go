@@ -0,0 +1,206 @@
+// Package backup snapshots the user tables into a single archive and
+// restores them: one CSV file per table, written with COPY, plus a manifest,
+// in a gzipped tar. Archives are kept in a Storage, on local disk or in an
+// S3 bucket.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"BACKEND/config"
+)
+
+// Tables are the tables a backup holds, in an order that restores them
+// without breaking foreign keys.
+var Tables = []string{
+	"users",
+	"organizations",
+	"organization_members",
+	"organization_usage",
+	"organization_billing",
+	"api_keys",
+	"api_usage",
+	"login_history",
+	"webauthn_credentials",
+	"user_identities",
+	"token_revocations",
+	"name_reviews",
+	"referral_codes",
+	"referrals",
+}
+
+const manifestName = "manifest.json"
+
+// Manifest describes an archive. It is the last entry, so its row counts
+// are those of the data before it.
+type Manifest struct {
+	CreatedAt time.Time        `json:"created_at"`
+	Tables    map[string]int64 `json:"tables"`
+}
+
+// Copier copies whole tables as CSV with a header row.
+type Copier interface {
+	CopyOut(ctx context.Context, table string, w io.Writer) (rows int64, err error)
+}
+
+// Object is an archive in a Storage.
+type Object struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ErrNotFound is returned by Storage.Get for an archive that doesn't
+// exist.
+var ErrNotFound = errors.New("backup not found")
+
+type Storage interface {
+	Put(ctx context.Context, name string, r io.Reader) error
+	Get(ctx context.Context, name string) (io.ReadCloser, error)
+	// List returns the archives, newest first.
+	List(ctx context.Context) ([]Object, error)
+}
+
+// NewStorage returns the S3 bucket in cfg if one is set, and the local
+// directory otherwise.
+func NewStorage(cfg config.Backups) Storage {
+	if cfg.S3Bucket != "" {
+		return &S3Storage{
+			Endpoint:     cfg.S3Endpoint,
+			Region:       cfg.S3Region,
+			Bucket:       cfg.S3Bucket,
+			Prefix:       cfg.S3Prefix,
+			AccessKey:    cfg.AccessKey,
+			SecretKey:    cfg.SecretKey,
+			SessionToken: cfg.SessionToken,
+		}
+	}
+	return LocalStorage{Dir: cfg.Dir}
+}
+
+// Write copies each table into an archive written to w.
+func Write(ctx context.Context, w io.Writer, copier Copier, tables []string, now time.Time) (Manifest, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	manifest := Manifest{CreatedAt: now.UTC(), Tables: make(map[string]int64, len(tables))}
+
+	for _, table := range tables {
+		// tar needs each entry's size up front, so the table is spooled to
+		// a temporary file rather than held in memory.
+		rows, size, tmp, err := spool(ctx, copier, table)
+		if err != nil {
+			return Manifest{}, err
+		}
+		err = writeEntry(tw, table+".csv", size, now, tmp)
+		tmp.Close()
+		os.Remove(tmp.Name())
+		if err != nil {
+			return Manifest{}, fmt.Errorf("failed to archive %s: %w", table, err)
+		}
+		manifest.Tables[table] = rows
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return Manifest{}, err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: manifestName, Mode: 0o600, Size: int64(len(data)), ModTime: now}); err != nil {
+		return Manifest{}, err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return Manifest{}, err
+	}
+	if err := tw.Close(); err != nil {
+		return Manifest{}, err
+	}
+	return manifest, gz.Close()
+}
+
+func spool(ctx context.Context, copier Copier, table string) (int64, int64, *os.File, error) {
+	tmp, err := os.CreateTemp("", "backup-"+table+"-*.csv")
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	rows, err := copier.CopyOut(ctx, table, tmp)
+	if err == nil {
+		_, err = tmp.Seek(0, io.SeekStart)
+	}
+	var size int64
+	if err == nil {
+		var info os.FileInfo
+		if info, err = tmp.Stat(); err == nil {
+			size = info.Size()
+		}
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return 0, 0, nil, fmt.Errorf("failed to copy %s: %w", table, err)
+	}
+	return rows, size, tmp, nil
+}
+
+func writeEntry(tw *tar.Writer, name string, size int64, modTime time.Time, r io.Reader) error {
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o600, Size: size, ModTime: modTime}); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, r)
+	return err
+}
+
+// Read calls fn with each table's CSV in archive order, then returns the
+// manifest.
+func Read(r io.Reader, fn func(table string, csv io.Reader) error) (Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("not a backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	var manifest Manifest
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return Manifest{}, fmt.Errorf("corrupt backup archive: %w", err)
+		}
+		if hdr.Name == manifestName {
+			if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+				return Manifest{}, fmt.Errorf("corrupt backup manifest: %w", err)
+			}
+			continue
+		}
+		table, ok := tableOf(hdr.Name)
+		if !ok {
+			return Manifest{}, fmt.Errorf("unexpected file %q in backup archive", hdr.Name)
+		}
+		if err := fn(table, tr); err != nil {
+			return Manifest{}, fmt.Errorf("failed to restore %s: %w", table, err)
+		}
+	}
+	if manifest.Tables == nil {
+		return Manifest{}, errors.New("backup archive has no manifest")
+	}
+	return manifest, nil
+}
+
+// tableOf returns the table an archive entry holds, if it is one of Tables.
+func tableOf(name string) (string, bool) {
+	for _, table := range Tables {
+		if name == table+".csv" {
+			return table, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,151 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeCopier map[string]string
+
+func (f fakeCopier) CopyOut(ctx context.Context, table string, w io.Writer) (int64, error) {
+	data, ok := f[table]
+	if !ok {
+		return 0, fmt.Errorf("no such table %q", table)
+	}
+	if _, err := io.WriteString(w, data); err != nil {
+		return 0, err
+	}
+	return int64(strings.Count(data, "\n") - 1), nil
+}
+
+func TestWriteReadRoundTrip(t *testing.T) {
+	copier := fakeCopier{
+		"users":    "id,email\n1,a@example.com\n2,b@example.com\n",
+		"api_keys": "id,user_id\n",
+	}
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	var buf bytes.Buffer
+	written, err := Write(context.Background(), &buf, copier, []string{"users", "api_keys"}, now)
+	if err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if written.Tables["users"] != 2 || written.Tables["api_keys"] != 0 {
+		t.Errorf("unexpected row counts %v", written.Tables)
+	}
+
+	got := map[string]string{}
+	read, err := Read(&buf, func(table string, csv io.Reader) error {
+		data, err := io.ReadAll(csv)
+		got[table] = string(data)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !read.CreatedAt.Equal(now) || read.Tables["users"] != 2 {
+		t.Errorf("unexpected manifest %+v", read)
+	}
+	for table, want := range copier {
+		if got[table] != want {
+			t.Errorf("%s: got %q, want %q", table, got[table], want)
+		}
+	}
+}
+
+func TestWriteCopyError(t *testing.T) {
+	_, err := Write(context.Background(), io.Discard, fakeCopier{}, []string{"users"}, time.Now())
+	if err == nil || !strings.Contains(err.Error(), "users") {
+		t.Errorf("expected an error naming the table, got %v", err)
+	}
+}
+
+func TestReadRejectsNonArchive(t *testing.T) {
+	if _, err := Read(strings.NewReader("not gzip"), nil); err == nil {
+		t.Error("expected an error")
+	}
+}
+
+func TestLocalStorage(t *testing.T) {
+	ctx := context.Background()
+	s := LocalStorage{Dir: t.TempDir()}
+
+	objects, err := s.List(ctx)
+	if err != nil || len(objects) != 0 {
+		t.Fatalf("List on empty dir = %v, %v", objects, err)
+	}
+	if err := s.Put(ctx, "a.tar.gz", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	objects, err = s.List(ctx)
+	if err != nil || len(objects) != 1 || objects[0].Name != "a.tar.gz" || objects[0].Size != 5 {
+		t.Fatalf("List = %+v, %v", objects, err)
+	}
+
+	r, err := s.Get(ctx, "a.tar.gz")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "hello" {
+		t.Errorf("Get returned %q", data)
+	}
+
+	for _, name := range []string{"missing.tar.gz", "../a.tar.gz", ".tmp-a"} {
+		if _, err := s.Get(ctx, name); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Get(%q): expected ErrNotFound, got %v", name, err)
+		}
+	}
+}
+
+func TestS3StorageSignsRequests(t *testing.T) {
+	var auth, path, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		path = r.URL.Path
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer srv.Close()
+
+	s := &S3Storage{
+		Endpoint:  srv.URL,
+		Region:    "eu-west-1",
+		Bucket:    "bucket",
+		Prefix:    "backups/",
+		AccessKey: "AKID",
+		SecretKey: "secret",
+		now:       func() time.Time { return time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC) },
+	}
+	if err := s.Put(context.Background(), "a.tar.gz", strings.NewReader("hello")); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if path != "/bucket/backups/a.tar.gz" || body != "hello" {
+		t.Errorf("unexpected upload %q: %q", path, body)
+	}
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/20260102/eu-west-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=") {
+		t.Errorf("unexpected Authorization header %q", auth)
+	}
+}
+
+func TestS3StorageGetNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	s := &S3Storage{Endpoint: srv.URL, Region: "us-east-1", Bucket: "bucket"}
+	if _, err := s.Get(context.Background(), "missing.tar.gz"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LocalStorage keeps archives as files in Dir.
+type LocalStorage struct {
+	Dir string
+}
+
+func (s LocalStorage) Put(ctx context.Context, name string, r io.Reader) error {
+	if err := os.MkdirAll(s.Dir, 0o700); err != nil {
+		return err
+	}
+	// Write under a temporary name so a failed backup never shows up in
+	// List.
+	tmp, err := os.CreateTemp(s.Dir, ".tmp-"+name+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.Dir, name))
+}
+
+func (s LocalStorage) Get(ctx context.Context, name string) (io.ReadCloser, error) {
+	if name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, ErrNotFound
+	}
+	f, err := os.Open(filepath.Join(s.Dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (s LocalStorage) List(ctx context.Context) ([]Object, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return []Object{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []Object{}
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		objects = append(objects, Object{Name: entry.Name(), Size: info.Size(), CreatedAt: info.ModTime().UTC()})
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].CreatedAt.After(objects[j].CreatedAt) })
+	return objects, nil
+}
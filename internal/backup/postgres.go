@@ -0,0 +1,121 @@
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PostgresCopier copies tables with COPY.
+type PostgresCopier struct {
+	Pool *pgxpool.Pool
+}
+
+func (p PostgresCopier) CopyOut(ctx context.Context, table string, w io.Writer) (int64, error) {
+	conn, err := p.Pool.Acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Release()
+
+	sql := fmt.Sprintf("COPY %s TO STDOUT (FORMAT csv, HEADER true)", pgx.Identifier{table}.Sanitize())
+	tag, err := conn.Conn().PgConn().CopyTo(ctx, w, sql)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// Restore replaces the contents of the backed-up tables with the archive
+// read from r, in one transaction, then moves each ID sequence past the
+// restored rows and refreshes the stats view. Columns are matched by the
+// names in each CSV header, so the archive may come from a schema whose
+// columns are in a different order.
+func Restore(ctx context.Context, pool *pgxpool.Pool, r io.Reader) (Manifest, error) {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	quoted := make([]string, len(Tables))
+	for i, table := range Tables {
+		quoted[i] = pgx.Identifier{table}.Sanitize()
+	}
+	if _, err := tx.Exec(ctx, "TRUNCATE "+strings.Join(quoted, ", ")+" RESTART IDENTITY CASCADE"); err != nil {
+		return Manifest{}, fmt.Errorf("failed to clear tables: %w", err)
+	}
+
+	restored := make(map[string]int64)
+	manifest, err := Read(r, func(table string, data io.Reader) error {
+		rows, err := copyIn(ctx, tx, table, data)
+		restored[table] = rows
+		return err
+	})
+	if err != nil {
+		return Manifest{}, err
+	}
+	for table, want := range manifest.Tables {
+		if got := restored[table]; got != want {
+			return Manifest{}, fmt.Errorf("restored %d rows into %s; the manifest lists %d", got, table, want)
+		}
+	}
+
+	for i, table := range Tables {
+		if err := resetSequence(ctx, tx, table, quoted[i]); err != nil {
+			return Manifest{}, fmt.Errorf("failed to reset %s sequence: %w", table, err)
+		}
+	}
+	if _, err := tx.Exec(ctx, "REFRESH MATERIALIZED VIEW user_stats"); err != nil {
+		return Manifest{}, fmt.Errorf("failed to refresh stats: %w", err)
+	}
+
+	return manifest, tx.Commit(ctx)
+}
+
+// resetSequence makes the table's ID sequence continue after the restored
+// rows. Tables keyed by user_id have none.
+func resetSequence(ctx context.Context, tx pgx.Tx, table, quoted string) error {
+	var seq *string
+	err := tx.QueryRow(ctx, `SELECT pg_get_serial_sequence(table_name, column_name)
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = $1 AND column_name = 'id'`, table).Scan(&seq)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && seq == nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(ctx, "SELECT setval($1, COALESCE((SELECT MAX(id) FROM "+quoted+"), 0) + 1, false)", *seq)
+	return err
+}
+
+func copyIn(ctx context.Context, tx pgx.Tx, table string, data io.Reader) (int64, error) {
+	br := bufio.NewReader(data)
+	header, err := br.ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return 0, err
+	}
+	columns, err := csv.NewReader(bytes.NewReader(header)).Read()
+	if err != nil {
+		return 0, fmt.Errorf("bad CSV header: %w", err)
+	}
+	for i, column := range columns {
+		columns[i] = pgx.Identifier{column}.Sanitize()
+	}
+
+	sql := fmt.Sprintf("COPY %s (%s) FROM STDIN (FORMAT csv, HEADER true)", pgx.Identifier{table}.Sanitize(), strings.Join(columns, ", "))
+	tag, err := tx.Conn().PgConn().CopyFrom(ctx, io.MultiReader(bytes.NewReader(header), br), sql)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,66 @@
+// Package billing reads subscription changes from a billing provider's
+// webhooks. Each provider implements Provider and reports changes in the
+// same terms, so the rest of the API doesn't depend on which one is used.
+package billing
+
+import (
+	"errors"
+	"time"
+)
+
+// Status is a subscription's state, in Stripe's vocabulary, which other
+// providers map onto.
+type Status string
+
+const (
+	StatusActive     Status = "active"
+	StatusTrialing   Status = "trialing"
+	StatusPastDue    Status = "past_due"
+	StatusIncomplete Status = "incomplete"
+	StatusUnpaid     Status = "unpaid"
+	StatusPaused     Status = "paused"
+	StatusCanceled   Status = "canceled"
+)
+
+// AllowsSeats reports whether members can be added under a subscription in
+// this state. Past due subscriptions keep working while the provider
+// retries the payment.
+func (s Status) AllowsSeats() bool {
+	return s == StatusActive || s == StatusTrialing || s == StatusPastDue
+}
+
+var (
+	ErrInvalidSignature = errors.New("webhook signature is invalid")
+	ErrMalformedEvent   = errors.New("webhook event is malformed")
+	// ErrIgnoredEvent is returned for events that don't describe a
+	// subscription. They should still be acknowledged.
+	ErrIgnoredEvent = errors.New("webhook event is not about a subscription")
+)
+
+// Subscription is the state of a subscription after an event.
+type Subscription struct {
+	// OrgID is the organization named in the subscription's metadata, or
+	// 0 when it names none.
+	OrgID            int64
+	CustomerID       string
+	SubscriptionID   string
+	Plan             string
+	Status           Status
+	Seats            int64
+	CurrentPeriodEnd time.Time
+}
+
+type Event struct {
+	ID           string
+	Type         string
+	CreatedAt    time.Time
+	Subscription Subscription
+}
+
+type Provider interface {
+	// Name is stored with each subscription, e.g. "stripe".
+	Name() string
+	// ParseWebhook verifies a webhook delivery from its body and headers
+	// and returns the subscription event it carries.
+	ParseWebhook(payload []byte, header func(key string) string) (Event, error)
+}
@@ -0,0 +1,177 @@
+package billing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"BACKEND/internal/clock"
+)
+
+const StripeSignatureHeader = "Stripe-Signature"
+
+// Stripe verifies and reads Stripe webhooks. Subscriptions are matched to
+// organizations by an org_id key in their metadata, set when the
+// subscription is created; later events may also be matched by customer.
+type Stripe struct {
+	webhookSecret string
+	tolerance     time.Duration
+	clock         clock.Clock
+}
+
+// NewStripe returns a Stripe provider that accepts webhooks signed with
+// webhookSecret (the endpoint's whsec_ secret) at most tolerance ago.
+func NewStripe(webhookSecret string, tolerance time.Duration) *Stripe {
+	return &Stripe{webhookSecret: webhookSecret, tolerance: tolerance, clock: clock.System}
+}
+
+func (s *Stripe) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+func (s *Stripe) Name() string {
+	return "stripe"
+}
+
+type stripeEvent struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+type stripeSubscription struct {
+	Object           string            `json:"object"`
+	ID               string            `json:"id"`
+	Customer         json.RawMessage   `json:"customer"`
+	Status           string            `json:"status"`
+	CurrentPeriodEnd int64             `json:"current_period_end"`
+	Metadata         map[string]string `json:"metadata"`
+	Items            struct {
+		Data []struct {
+			Quantity         int64 `json:"quantity"`
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+			Price            struct {
+				ID        string `json:"id"`
+				LookupKey string `json:"lookup_key"`
+			} `json:"price"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+func (s *Stripe) ParseWebhook(payload []byte, header func(key string) string) (Event, error) {
+	if err := s.verify(payload, header(StripeSignatureHeader)); err != nil {
+		return Event{}, err
+	}
+
+	var evt stripeEvent
+	if err := json.Unmarshal(payload, &evt); err != nil {
+		return Event{}, fmt.Errorf("%w: %v", ErrMalformedEvent, err)
+	}
+	if !strings.HasPrefix(evt.Type, "customer.subscription.") {
+		return Event{ID: evt.ID, Type: evt.Type}, ErrIgnoredEvent
+	}
+
+	var sub stripeSubscription
+	if err := json.Unmarshal(evt.Data.Object, &sub); err != nil || sub.Object != "subscription" {
+		return Event{}, fmt.Errorf("%w: %s has no subscription", ErrMalformedEvent, evt.Type)
+	}
+	customer := stripeID(sub.Customer)
+	if sub.ID == "" || customer == "" {
+		return Event{}, fmt.Errorf("%w: subscription without an id or customer", ErrMalformedEvent)
+	}
+
+	out := Subscription{
+		CustomerID:     customer,
+		SubscriptionID: sub.ID,
+		Status:         stripeStatus(sub.Status),
+	}
+	out.OrgID, _ = strconv.ParseInt(sub.Metadata["org_id"], 10, 64)
+	periodEnd := sub.CurrentPeriodEnd
+	for i, item := range sub.Items.Data {
+		out.Seats += item.Quantity
+		if i == 0 {
+			out.Plan = item.Price.LookupKey
+			if out.Plan == "" {
+				out.Plan = item.Price.ID
+			}
+			// Newer API versions only set the period on items.
+			if periodEnd == 0 {
+				periodEnd = item.CurrentPeriodEnd
+			}
+		}
+	}
+	if periodEnd > 0 {
+		out.CurrentPeriodEnd = time.Unix(periodEnd, 0).UTC()
+	}
+
+	return Event{
+		ID:           evt.ID,
+		Type:         evt.Type,
+		CreatedAt:    time.Unix(evt.Created, 0).UTC(),
+		Subscription: out,
+	}, nil
+}
+
+// verify checks a Stripe-Signature header, "t=<unix time>,v1=<hex
+// HMAC-SHA256 of t.payload>". There can be several v1 signatures while a
+// secret is being rolled; one matching is enough.
+func (s *Stripe) verify(payload []byte, sigHeader string) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(sigHeader, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return ErrInvalidSignature
+	}
+	if age := s.clock.Now().Sub(time.Unix(ts, 0)); s.tolerance > 0 && (age > s.tolerance || age < -s.tolerance) {
+		return fmt.Errorf("%w: timestamp outside the tolerance", ErrInvalidSignature)
+	}
+
+	mac := hmac.New(sha256.New, []byte(s.webhookSecret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(payload)
+	expected := mac.Sum(nil)
+	for _, sig := range signatures {
+		got, err := hex.DecodeString(sig)
+		if err == nil && hmac.Equal(got, expected) {
+			return nil
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// stripeID reads a field that holds either an ID or the expanded object.
+func stripeID(raw json.RawMessage) string {
+	var id string
+	if json.Unmarshal(raw, &id) == nil {
+		return id
+	}
+	var obj struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(raw, &obj)
+	return obj.ID
+}
+
+func stripeStatus(status string) Status {
+	if status == "incomplete_expired" {
+		return StatusCanceled
+	}
+	return Status(status)
+}
@@ -0,0 +1,102 @@
+package billing
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"BACKEND/internal/clock"
+)
+
+const testSecret = "whsec_test"
+
+func signStripe(secret string, ts time.Time, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.%s", ts.Unix(), payload)
+	return fmt.Sprintf("t=%d,v1=%s", ts.Unix(), hex.EncodeToString(mac.Sum(nil)))
+}
+
+func headers(sig string) func(string) string {
+	return func(key string) string {
+		if key == StripeSignatureHeader {
+			return sig
+		}
+		return ""
+	}
+}
+
+func TestStripeParseWebhook(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	s := NewStripe(testSecret, 5*time.Minute)
+	s.SetClock(clock.NewFake(now))
+
+	payload := `{"id":"evt_1","type":"customer.subscription.updated","created":1777636800,"data":{"object":{
+		"object":"subscription","id":"sub_1","customer":{"id":"cus_1","object":"customer"},"status":"past_due",
+		"metadata":{"org_id":"7"},
+		"items":{"data":[{"quantity":5,"current_period_end":1780000000,"price":{"id":"price_1","lookup_key":"team"}},{"quantity":2,"price":{"id":"price_2"}}]}}}}`
+
+	evt, err := s.ParseWebhook([]byte(payload), headers(signStripe(testSecret, now, payload)))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	sub := evt.Subscription
+	if evt.ID != "evt_1" || !evt.CreatedAt.Equal(time.Unix(1777636800, 0)) {
+		t.Errorf("unexpected event %+v", evt)
+	}
+	if sub.OrgID != 7 || sub.CustomerID != "cus_1" || sub.SubscriptionID != "sub_1" || sub.Plan != "team" || sub.Seats != 7 || sub.Status != StatusPastDue {
+		t.Errorf("unexpected subscription %+v", sub)
+	}
+	if !sub.CurrentPeriodEnd.Equal(time.Unix(1780000000, 0)) {
+		t.Errorf("period end %v", sub.CurrentPeriodEnd)
+	}
+	if !sub.Status.AllowsSeats() {
+		t.Errorf("past due subscriptions should keep their seats")
+	}
+}
+
+func TestStripeRejectsBadSignatures(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	s := NewStripe(testSecret, 5*time.Minute)
+	s.SetClock(clock.NewFake(now))
+	payload := `{"id":"evt_1","type":"customer.subscription.deleted","created":1,"data":{"object":{"object":"subscription","id":"sub_1","customer":"cus_1","status":"canceled"}}}`
+
+	tests := map[string]string{
+		"missing":      "",
+		"wrong secret": signStripe("whsec_other", now, payload),
+		"too old":      signStripe(testSecret, now.Add(-6*time.Minute), payload),
+		"tampered":     signStripe(testSecret, now, payload+" "),
+	}
+	for name, sig := range tests {
+		if _, err := s.ParseWebhook([]byte(payload), headers(sig)); !errors.Is(err, ErrInvalidSignature) {
+			t.Errorf("%s: expected ErrInvalidSignature, got %v", name, err)
+		}
+	}
+
+	// A rolled secret adds a second v1 signature.
+	sig := signStripe(testSecret, now, payload) + ",v1=" + hex.EncodeToString([]byte("stale"))
+	evt, err := s.ParseWebhook([]byte(payload), headers(sig))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if evt.Subscription.Status != StatusCanceled || evt.Subscription.CustomerID != "cus_1" || evt.Subscription.OrgID != 0 {
+		t.Errorf("unexpected subscription %+v", evt.Subscription)
+	}
+}
+
+func TestStripeIgnoresOtherEvents(t *testing.T) {
+	now := time.Now()
+	s := NewStripe(testSecret, 5*time.Minute)
+	payload := `{"id":"evt_2","type":"invoice.paid","created":1,"data":{"object":{"object":"invoice"}}}`
+
+	evt, err := s.ParseWebhook([]byte(payload), headers(signStripe(testSecret, now, payload)))
+	if !errors.Is(err, ErrIgnoredEvent) {
+		t.Fatalf("expected ErrIgnoredEvent, got %v", err)
+	}
+	if evt.Type != "invoice.paid" {
+		t.Errorf("ignored events should still report their type, got %q", evt.Type)
+	}
+}
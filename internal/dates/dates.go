@@ -0,0 +1,47 @@
+// Package dates does the calendar arithmetic on dates of birth, so ages and
+// birthdays agree everywhere. Only calendar dates count: times of day and
+// time zones are ignored. People born on 29 February have their birthday
+// on the 28th in common years.
+package dates
+
+import "time"
+
+// Day is the calendar date of t, as midnight UTC.
+func Day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func IsLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// BirthdayIn is the birthday in year of someone born on dob.
+func BirthdayIn(dob time.Time, year int) time.Time {
+	day := time.Date(year, dob.Month(), dob.Day(), 0, 0, 0, 0, time.UTC)
+	if day.Month() != dob.Month() {
+		// 29 February in a common year rolled over to 1 March.
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// Age is how many whole years old someone born on dob is on day: the number
+// of birthdays they have had. It is negative for days before dob.
+func Age(dob, day time.Time) int {
+	age := day.Year() - dob.Year()
+	if Day(day).Before(BirthdayIn(dob, day.Year())) {
+		age--
+	}
+	return age
+}
+
+// BornBy is the last date of birth of someone who is at least age on day,
+// so Age(dob, day) >= age exactly when dob is not after it.
+func BornBy(day time.Time, age int) time.Time {
+	last := BirthdayIn(day, day.Year()-age)
+	if day.Month() == time.February && day.Day() == 28 && !IsLeap(day.Year()) && IsLeap(last.Year()) {
+		// Whoever was born on the 29th has their birthday today as well.
+		last = last.AddDate(0, 0, 1)
+	}
+	return last
+}
@@ -0,0 +1,76 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAge(t *testing.T) {
+	tests := []struct {
+		name string
+		dob  time.Time
+		day  time.Time
+		want int
+	}{
+		{"birthday already passed this year", date(1990, 5, 10), date(2025, 2, 15), 34},
+		{"birthday not yet this year", date(1991, 12, 31), date(2025, 2, 15), 33},
+		{"born this year", date(2025, 1, 1), date(2025, 2, 15), 0},
+		{"very old person", date(1924, 6, 15), date(2025, 2, 15), 100},
+		{"on the birthday", date(1996, 10, 16), date(2026, 10, 16), 30},
+		{"day before the birthday", date(1996, 10, 16), date(2026, 10, 15), 29},
+		{"late on the day before, in another zone", date(1996, 10, 16), time.Date(2026, 10, 15, 23, 30, 0, 0, time.FixedZone("UTC-5", -5*3600)), 29},
+		{"leap day birth before the 28th", date(2000, 2, 29), date(2025, 2, 15), 24},
+		{"leap day birth on the 28th of a common year", date(2000, 2, 29), date(2025, 2, 28), 25},
+		{"leap day birth on the 28th of a leap year", date(2000, 2, 29), date(2028, 2, 28), 27},
+		{"leap day birth on the 29th", date(2000, 2, 29), date(2028, 2, 29), 28},
+		{"before birth", date(2000, 1, 2), date(2000, 1, 1), -1},
+		{"on the day of birth", date(2000, 1, 1), date(2000, 1, 1), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Age(tt.dob, tt.day); got != tt.want {
+				t.Errorf("Age(%s, %s) = %d; want %d", tt.dob.Format(time.DateOnly), tt.day.Format(time.DateTime), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBirthdayIn(t *testing.T) {
+	if got := BirthdayIn(date(2000, 2, 29), 2027); !got.Equal(date(2027, 2, 28)) {
+		t.Errorf("leap day birthday in 2027 = %s; want 2027-02-28", got.Format(time.DateOnly))
+	}
+	if got := BirthdayIn(date(2000, 2, 29), 2028); !got.Equal(date(2028, 2, 29)) {
+		t.Errorf("leap day birthday in 2028 = %s; want 2028-02-29", got.Format(time.DateOnly))
+	}
+	if got := BirthdayIn(date(1990, 12, 31), 2030); !got.Equal(date(2030, 12, 31)) {
+		t.Errorf("birthday in 2030 = %s; want 2030-12-31", got.Format(time.DateOnly))
+	}
+}
+
+func TestIsLeap(t *testing.T) {
+	for year, want := range map[int]bool{1900: false, 2000: true, 2024: true, 2025: false, 2100: false} {
+		if got := IsLeap(year); got != want {
+			t.Errorf("IsLeap(%d) = %v; want %v", year, got, want)
+		}
+	}
+}
+
+// TestBornBy checks BornBy against Age for every day of a few years around
+// a leap year.
+func TestBornBy(t *testing.T) {
+	for day := date(2026, 1, 1); day.Before(date(2030, 1, 1)); day = day.AddDate(0, 0, 1) {
+		for age := 0; age <= 30; age++ {
+			last := BornBy(day, age)
+			if got := Age(last, day); got < age {
+				t.Fatalf("BornBy(%s, %d) = %s, who is only %d", day.Format(time.DateOnly), age, last.Format(time.DateOnly), got)
+			}
+			if got := Age(last.AddDate(0, 0, 1), day); got >= age {
+				t.Fatalf("BornBy(%s, %d) = %s, but the day after is %d too", day.Format(time.DateOnly), age, last.Format(time.DateOnly), got)
+			}
+		}
+	}
+}
@@ -0,0 +1,257 @@
+// Package fixtures loads declarative test data into a set of stores, either
+// the real repositories against a test database or in-memory fakes.
+// Fixtures are JSON documents in which entities refer to users by a ref
+// name, so tests never depend on generated IDs:
+//
+//	{
+//	  "users": [
+//	    {"ref": "ada", "name": "Ada Admin", "email": "ada@example.com", "role": "admin"},
+//	    {"ref": "jane", "name": "Jane Doe", "email": "jane@example.com", "active": false}
+//	  ],
+//	  "referrals": [{"referrer": "ada", "referred": "jane"}],
+//	  "logins": [{"user": "jane", "succeeded": false, "reason": "invalid_password"}]
+//	}
+package fixtures
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/repository"
+)
+
+const (
+	defaultDob    = "1990-01-01"
+	defaultRole   = "user"
+	defaultSource = "api"
+)
+
+// Stores are where fixtures are written. Only the stores a fixture needs
+// have to be set.
+type Stores struct {
+	Users        repository.UserStore
+	Referrals    repository.ReferralStore
+	LoginHistory repository.LoginHistoryStore
+}
+
+type Fixture struct {
+	Users     []User     `json:"users"`
+	Referrals []Referral `json:"referrals"`
+	Logins    []Login    `json:"logins"`
+}
+
+// User is created with CreateWithAuth, or CreateServiceAccount when
+// AccountType is "service". Password is hashed at the lowest bcrypt cost;
+// users without one can't log in with a password. Dob defaults to
+// 1990-01-01 and Role to "user".
+type User struct {
+	Ref          string `json:"ref"`
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Password     string `json:"password"`
+	Role         string `json:"role"`
+	AccountType  string `json:"account_type"`
+	SignupSource string `json:"signup_source"`
+	Dob          string `json:"dob"`
+	Active       *bool  `json:"active"`
+	ReferralCode string `json:"referral_code"`
+}
+
+type Referral struct {
+	Referrer string `json:"referrer"`
+	Referred string `json:"referred"`
+}
+
+// Login is a login history entry. User is a ref; leave it empty for
+// attempts on an unknown email.
+type Login struct {
+	User      string `json:"user"`
+	Email     string `json:"email"`
+	Succeeded bool   `json:"succeeded"`
+	Reason    string `json:"reason"`
+	IPAddress string `json:"ip_address"`
+	Country   string `json:"country"`
+}
+
+// Set holds the users a fixture created, by ref.
+type Set struct {
+	users map[string]generated.CreateUserRow
+}
+
+func (s *Set) User(ref string) (generated.CreateUserRow, bool) {
+	u, ok := s.users[ref]
+	return u, ok
+}
+
+// ID returns the ID of the user created for ref. It panics on an unknown
+// ref, which is always a mistake in the test.
+func (s *Set) ID(ref string) int64 {
+	u, ok := s.users[ref]
+	if !ok {
+		panic(fmt.Sprintf("fixtures: no user with ref %q", ref))
+	}
+	return u.ID
+}
+
+// LoadFile loads the fixture at path, typically under the test's testdata
+// directory.
+func LoadFile(ctx context.Context, stores Stores, path string) (*Set, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("fixtures: %w", err)
+	}
+	set, err := Load(ctx, stores, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w (in %s)", err, path)
+	}
+	return set, nil
+}
+
+// Load decodes a fixture and writes it to stores: users first, in order,
+// then referrals and logins. Unknown fields and refs are errors.
+func Load(ctx context.Context, stores Stores, data []byte) (*Set, error) {
+	var f Fixture
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&f); err != nil {
+		return nil, fmt.Errorf("fixtures: invalid fixture: %w", err)
+	}
+	return Apply(ctx, stores, f)
+}
+
+// Apply writes an already decoded fixture to stores.
+func Apply(ctx context.Context, stores Stores, f Fixture) (*Set, error) {
+	set := &Set{users: make(map[string]generated.CreateUserRow)}
+
+	if len(f.Users) > 0 && stores.Users == nil {
+		return nil, fmt.Errorf("fixtures: users need a user store")
+	}
+	for i, u := range f.Users {
+		if u.Ref == "" {
+			u.Ref = fmt.Sprintf("users[%d]", i)
+		}
+		if _, ok := set.users[u.Ref]; ok {
+			return nil, fmt.Errorf("fixtures: duplicate user ref %q", u.Ref)
+		}
+		created, err := createUser(ctx, stores, u)
+		if err != nil {
+			return nil, fmt.Errorf("fixtures: user %q: %w", u.Ref, err)
+		}
+		set.users[u.Ref] = created
+	}
+
+	if len(f.Referrals) > 0 && stores.Referrals == nil {
+		return nil, fmt.Errorf("fixtures: referrals need a referral store")
+	}
+	for _, r := range f.Referrals {
+		referrer, ok := set.users[r.Referrer]
+		if !ok {
+			return nil, fmt.Errorf("fixtures: referral from unknown user %q", r.Referrer)
+		}
+		referred, ok := set.users[r.Referred]
+		if !ok {
+			return nil, fmt.Errorf("fixtures: referral of unknown user %q", r.Referred)
+		}
+		if _, err := stores.Referrals.Record(ctx, referrer.ID, referred.ID); err != nil {
+			return nil, fmt.Errorf("fixtures: referral %s -> %s: %w", r.Referrer, r.Referred, err)
+		}
+	}
+
+	if len(f.Logins) > 0 && stores.LoginHistory == nil {
+		return nil, fmt.Errorf("fixtures: logins need a login history store")
+	}
+	for _, l := range f.Logins {
+		attempt := repository.LoginAttempt{
+			Email:     l.Email,
+			Succeeded: l.Succeeded,
+			Reason:    l.Reason,
+			IPAddress: l.IPAddress,
+			Country:   l.Country,
+		}
+		if l.User != "" {
+			u, ok := set.users[l.User]
+			if !ok {
+				return nil, fmt.Errorf("fixtures: login of unknown user %q", l.User)
+			}
+			attempt.UserID = &u.ID
+			if attempt.Email == "" {
+				attempt.Email = u.Email
+			}
+		}
+		if err := stores.LoginHistory.Record(ctx, attempt); err != nil {
+			return nil, fmt.Errorf("fixtures: login of %q: %w", attempt.Email, err)
+		}
+	}
+
+	return set, nil
+}
+
+func createUser(ctx context.Context, stores Stores, u User) (generated.CreateUserRow, error) {
+	var hash string
+	if u.Password != "" {
+		b, err := bcrypt.GenerateFromPassword([]byte(u.Password), bcrypt.MinCost)
+		if err != nil {
+			return generated.CreateUserRow{}, err
+		}
+		hash = string(b)
+	}
+	role := u.Role
+	if role == "" {
+		role = defaultRole
+	}
+
+	var created generated.CreateUserRow
+	switch u.AccountType {
+	case "", "human":
+		dobStr := u.Dob
+		if dobStr == "" {
+			dobStr = defaultDob
+		}
+		dob, err := time.Parse("2006-01-02", dobStr)
+		if err != nil {
+			return created, fmt.Errorf("invalid dob %q", u.Dob)
+		}
+		source := u.SignupSource
+		if source == "" {
+			source = defaultSource
+		}
+		if created, err = stores.Users.CreateWithAuth(ctx, u.Name, u.Email, hash, role, source, dob); err != nil {
+			return created, err
+		}
+	case "service":
+		row, err := stores.Users.CreateServiceAccount(ctx, u.Name, u.Email, hash, role)
+		if err != nil {
+			return created, err
+		}
+		created = generated.CreateUserRow(row)
+	default:
+		return created, fmt.Errorf("unknown account_type %q", u.AccountType)
+	}
+
+	if u.Active != nil && !*u.Active {
+		if _, err := stores.Users.SetActive(ctx, created.ID, false); err != nil {
+			return created, err
+		}
+		created.Active = false
+	}
+	if u.ReferralCode != "" {
+		if stores.Referrals == nil {
+			return created, fmt.Errorf("referral_code needs a referral store")
+		}
+		ok, err := stores.Referrals.CreateCode(ctx, created.ID, u.ReferralCode)
+		if err != nil {
+			return created, err
+		}
+		if !ok {
+			return created, fmt.Errorf("referral code %q is taken", u.ReferralCode)
+		}
+	}
+	return created, nil
+}
@@ -0,0 +1,122 @@
+package fixtures
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/repository"
+)
+
+type memUserStore struct {
+	repository.UserStore
+	users  []generated.CreateUserRow
+	hashes map[int64]string
+}
+
+func (m *memUserStore) CreateWithAuth(ctx context.Context, name, email, passwordHash, role, source string, dob time.Time) (generated.CreateUserRow, error) {
+	u := generated.CreateUserRow{ID: int64(len(m.users) + 1), Name: name, Email: email, Role: role, Active: true, AccountType: "human", SignupSource: source}
+	m.users = append(m.users, u)
+	if m.hashes == nil {
+		m.hashes = make(map[int64]string)
+	}
+	m.hashes[u.ID] = passwordHash
+	return u, nil
+}
+
+func (m *memUserStore) CreateServiceAccount(ctx context.Context, name, email, passwordHash, role string) (generated.CreateServiceAccountRow, error) {
+	u := generated.CreateUserRow{ID: int64(len(m.users) + 1), Name: name, Email: email, Role: role, Active: true, AccountType: "service", SignupSource: "admin"}
+	m.users = append(m.users, u)
+	return generated.CreateServiceAccountRow(u), nil
+}
+
+func (m *memUserStore) SetActive(ctx context.Context, id int64, active bool) (generated.SetUserActiveRow, error) {
+	m.users[id-1].Active = active
+	return generated.SetUserActiveRow{ID: id, Active: active}, nil
+}
+
+type memReferralStore struct {
+	repository.ReferralStore
+	codes     map[int64]string
+	referrals map[int64]int64
+}
+
+func (m *memReferralStore) CreateCode(ctx context.Context, userID int64, code string) (bool, error) {
+	m.codes[userID] = code
+	return true, nil
+}
+
+func (m *memReferralStore) Record(ctx context.Context, referrerID, referredID int64) (bool, error) {
+	m.referrals[referredID] = referrerID
+	return true, nil
+}
+
+type memLoginHistory struct {
+	repository.LoginHistoryStore
+	attempts []repository.LoginAttempt
+}
+
+func (m *memLoginHistory) Record(ctx context.Context, attempt repository.LoginAttempt) error {
+	m.attempts = append(m.attempts, attempt)
+	return nil
+}
+
+func TestLoadFile(t *testing.T) {
+	users := &memUserStore{}
+	referrals := &memReferralStore{codes: map[int64]string{}, referrals: map[int64]int64{}}
+	logins := &memLoginHistory{}
+
+	set, err := LoadFile(context.Background(), Stores{Users: users, Referrals: referrals, LoginHistory: logins}, "testdata/users.json")
+	if err != nil {
+		t.Fatalf("LoadFile: %v", err)
+	}
+
+	ada, jane := set.ID("ada"), set.ID("jane")
+	if u, _ := set.User("ada"); u.Role != "admin" {
+		t.Errorf("ada role = %q; want admin", u.Role)
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(users.hashes[ada]), []byte("Password123!")); err != nil {
+		t.Errorf("ada's password hash doesn't match: %v", err)
+	}
+	if u, _ := set.User("jane"); u.Active || users.users[jane-1].Active {
+		t.Errorf("jane is active; want deactivated")
+	}
+	if u, _ := set.User("ci"); u.AccountType != "service" {
+		t.Errorf("ci account type = %q; want service", u.AccountType)
+	}
+	if referrals.codes[ada] != "ADA12345" || referrals.referrals[jane] != ada {
+		t.Errorf("referrals = %v, codes = %v; want jane referred by ada", referrals.referrals, referrals.codes)
+	}
+	if len(logins.attempts) != 2 || logins.attempts[0].UserID == nil || *logins.attempts[0].UserID != jane || logins.attempts[0].Email != "jane@example.com" {
+		t.Errorf("logins = %+v; want jane's failed login first", logins.attempts)
+	}
+	if logins.attempts[1].UserID != nil {
+		t.Errorf("login for an unknown email has user %d", *logins.attempts[1].UserID)
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		fixture string
+		want    string
+	}{
+		{"unknown field", `{"users": [{"ref": "a", "nmae": "A"}]}`, "unknown field"},
+		{"duplicate ref", `{"users": [{"ref": "a"}, {"ref": "a"}]}`, "duplicate user ref"},
+		{"unknown ref", `{"users": [{"ref": "a"}], "referrals": [{"referrer": "a", "referred": "b"}]}`, `unknown user "b"`},
+		{"missing store", `{"logins": [{"email": "x@example.com"}]}`, "login history store"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stores := Stores{Users: &memUserStore{}, Referrals: &memReferralStore{codes: map[int64]string{}, referrals: map[int64]int64{}}}
+			_, err := Load(context.Background(), stores, []byte(tt.fixture))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Load() error = %v; want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
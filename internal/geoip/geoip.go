@@ -0,0 +1,124 @@
+// Package geoip maps IP addresses to countries and cities.
+package geoip
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"os"
+	"sort"
+	"strings"
+)
+
+type Location struct {
+	Country string `json:"country"`
+	City    string `json:"city,omitempty"`
+}
+
+// Locator looks up where an IP address is. ok is false when the address is
+// not in the database, e.g. for private ranges.
+type Locator interface {
+	Lookup(ip string) (loc Location, ok bool)
+}
+
+type ipRange struct {
+	start, end netip.Addr
+	loc        Location
+}
+
+// DB is an in-memory range database loaded from a DB-IP Lite CSV file.
+type DB struct {
+	ranges []ipRange
+}
+
+// Open loads a DB-IP "IP to Country Lite" or "IP to City Lite" CSV file,
+// gzipped if the name ends in .gz. Rows are start IP, end IP and country
+// code; the city layout adds continent before the country and region and
+// city after it.
+func Open(path string) (*DB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	db, err := Parse(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	return db, nil
+}
+
+func Parse(r io.Reader) (*DB, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	db := &DB{}
+	for line := 1; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var loc Location
+		switch {
+		case len(rec) >= 6:
+			loc = Location{Country: rec[3], City: rec[5]}
+		case len(rec) >= 3:
+			loc = Location{Country: rec[2]}
+		default:
+			return nil, fmt.Errorf("line %d: expected at least 3 columns, got %d", line, len(rec))
+		}
+
+		start, err := netip.ParseAddr(rec[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		end, err := netip.ParseAddr(rec[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		loc.Country = strings.ToUpper(loc.Country)
+		loc.City = strings.Clone(loc.City)
+		db.ranges = append(db.ranges, ipRange{start: start, end: end, loc: loc})
+	}
+
+	sort.Slice(db.ranges, func(i, j int) bool {
+		return db.ranges[i].start.Less(db.ranges[j].start)
+	})
+	return db, nil
+}
+
+func (db *DB) Lookup(ip string) (Location, bool) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return Location{}, false
+	}
+	addr = addr.Unmap()
+
+	// The last range starting at or before addr is the only candidate.
+	i := sort.Search(len(db.ranges), func(i int) bool {
+		return addr.Less(db.ranges[i].start)
+	}) - 1
+	if i < 0 || db.ranges[i].end.Less(addr) || db.ranges[i].start.Is4() != addr.Is4() {
+		return Location{}, false
+	}
+	return db.ranges[i].loc, true
+}
@@ -0,0 +1,76 @@
+package geoip
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const countryCSV = `1.0.0.0,1.0.0.255,au
+8.8.8.0,8.8.8.255,US
+2001:db8::,2001:db8::ffff,NL
+`
+
+const cityCSV = `81.2.69.0,81.2.69.255,EU,GB,England,London,51.5,-0.1
+`
+
+func TestLookup(t *testing.T) {
+	db, err := Parse(strings.NewReader(countryCSV + cityCSV))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	tests := []struct {
+		ip   string
+		want Location
+		ok   bool
+	}{
+		{"1.0.0.1", Location{Country: "AU"}, true},
+		{"8.8.8.8", Location{Country: "US"}, true},
+		{"::ffff:8.8.8.8", Location{Country: "US"}, true},
+		{"81.2.69.142", Location{Country: "GB", City: "London"}, true},
+		{"2001:db8::1", Location{Country: "NL"}, true},
+		{"8.8.9.1", Location{}, false},
+		{"10.0.0.1", Location{}, false},
+		{"0.0.0.1", Location{}, false},
+		{"not-an-ip", Location{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := db.Lookup(tt.ip)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("Lookup(%q) = %+v, %v; want %+v, %v", tt.ip, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestOpenGzip(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write([]byte(countryCSV))
+	gz.Close()
+
+	path := filepath.Join(t.TempDir(), "dbip-country-lite.csv.gz")
+	if err := os.WriteFile(path, buf.Bytes(), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if loc, ok := db.Lookup("8.8.8.8"); !ok || loc.Country != "US" {
+		t.Errorf("Lookup = %+v, %v; want US", loc, ok)
+	}
+}
+
+func TestParseRejectsBadRows(t *testing.T) {
+	if _, err := Parse(strings.NewReader("1.0.0.0,AU\n")); err == nil {
+		t.Error("expected an error for a row without an end address")
+	}
+	if _, err := Parse(strings.NewReader("1.0.0.x,1.0.0.255,AU\n")); err == nil {
+		t.Error("expected an error for an invalid address")
+	}
+}
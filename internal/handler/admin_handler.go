@@ -1,60 +1,648 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/hooks"
 	"BACKEND/internal/middleware"
 	"BACKEND/internal/models"
+	"BACKEND/internal/policy"
+	"BACKEND/internal/queryparams"
 	"BACKEND/internal/repository"
+	"BACKEND/internal/service"
 )
 
 type AdminHandler struct {
-	repo   *repository.UserRepository
-	logger *zap.Logger
+	repo        repository.UserStore
+	policies    *policy.Engine
+	validate    *validator.Validate
+	logger      *zap.Logger
+	revocations *service.TokenRevocationService
+	resets      *service.PasswordResetService
+	users       *service.UserService
+	securityLog *service.SecurityLogService
+	lockout     *service.LoginLockout
 }
 
-func NewAdminHandler(repo *repository.UserRepository, logger *zap.Logger) *AdminHandler {
+func NewAdminHandler(repo repository.UserStore, policies *policy.Engine, logger *zap.Logger) *AdminHandler {
 	return &AdminHandler{
-		repo:   repo,
-		logger: logger,
+		repo:     repo,
+		policies: policies,
+		validate: validator.New(),
+		logger:   logger,
 	}
 }
 
+// SetPagination takes the page sizes of GET /admin/users, and whether it
+// always pages, from users. Without it the list isn't paged.
+func (h *AdminHandler) SetPagination(users *service.UserService) {
+	h.users = users
+}
+
+// SetSecurityLog records role changes and the other account actions in
+// log.
+func (h *AdminHandler) SetSecurityLog(log *service.SecurityLogService) {
+	h.securityLog = log
+}
+
+// SetCredentialControls enables forcing users to log out or reset their
+// password.
+func (h *AdminHandler) SetCredentialControls(revocations *service.TokenRevocationService, resets *service.PasswordResetService) {
+	h.revocations = revocations
+	h.resets = resets
+}
+
+// SetLockout lets admins list and lift login lockouts.
+func (h *AdminHandler) SetLockout(lockout *service.LoginLockout) {
+	h.lockout = lockout
+}
+
 func (h *AdminHandler) GetAllUsers(c *fiber.Ctx) error {
 	authUser := middleware.GetAuthUser(c)
 
 	middleware.GetRequestLogger(c).Info("admin accessing all users",
-		zap.Int32("admin_id", authUser.ID),
+		zap.Int64("admin_id", authUser.ID),
 	)
 
-	users, err := h.repo.List(c.Context())
+	sort, err := service.ParseUserSort(c.Query("sort"))
 	if err != nil {
-		middleware.GetRequestLogger(c).Error("failed to list all users", zap.Error(err))
+		return sendSortError(c, err)
+	}
+
+	paged := wantsPage(c) || len(sort) > 0
+	if !paged {
+		middleware.MarkDeprecated(c, DeprecatedFullList)
+	}
+	// Org admins only ever get pages, as only the search queries filter by
+	// organization.
+	_, scoped := middleware.GetOrgScope(c)
+	if h.users != nil && (paged || scoped || h.users.AlwaysPaginate()) {
+		return h.getUsersPage(c, sort)
+	}
+	if scoped {
+		middleware.GetRequestLogger(c).Error("org-scoped user list needs pagination set up")
 		return models.SendInternalError(c, "Failed to retrieve users", middleware.GetRequestID(c))
 	}
 
+	// One more than the cap tells whether any were left out.
+	listCap, limit := service.DefaultListCap, int32(math.MaxInt32)
+	if h.users != nil {
+		listCap = h.users.ListCap()
+	}
+	if listCap > 0 {
+		limit = int32(listCap + 1)
+	}
+
+	var users interface{}
+	var count int
+	if source := c.Query("signup_source"); source != "" {
+		rows, err := h.repo.Search(c.UserContext(), repository.UserFilter{SignupSource: source}, nil, limit, 0)
+		if err != nil {
+			middleware.GetRequestLogger(c).Error("failed to list users by signup source", zap.Error(err))
+			return models.SendInternalError(c, "Failed to retrieve users", middleware.GetRequestID(c))
+		}
+		if listCap > 0 && len(rows) > listCap {
+			rows = rows[:listCap]
+			c.Set(ResultTruncatedHeader, "true")
+		}
+		users, count = rows, len(rows)
+	} else {
+		rows, err := h.repo.ListPaginated(c.UserContext(), limit, 0)
+		if err != nil {
+			middleware.GetRequestLogger(c).Error("failed to list all users", zap.Error(err))
+			return models.SendInternalError(c, "Failed to retrieve users", middleware.GetRequestID(c))
+		}
+		if listCap > 0 && len(rows) > listCap {
+			rows = rows[:listCap]
+			c.Set(ResultTruncatedHeader, "true")
+		}
+		users, count = rows, len(rows)
+	}
+
+	return c.JSON(fiber.Map{
+		"total": count,
+		"users": users,
+	})
+}
+
+// getUsersPage is GetAllUsers for one page. Pages filtered by signup
+// source or organization, or sorted, come from the search queries.
+func (h *AdminHandler) getUsersPage(c *fiber.Ctx, sort []repository.UserSortKey) error {
+	page, limit, err := parsePagination(func(key string) string { return c.Query(key) }, h.users.DefaultPageSize(), h.users.MaxPageSize())
+	if err != nil {
+		return sendQueryError(c, err)
+	}
+	offset := (page - 1) * limit
+
+	var meta models.PaginationMeta
+	var users interface{}
+	filter := repository.UserFilter{SignupSource: c.Query("signup_source")}
+	filter.OrgID, _ = middleware.GetOrgScope(c)
+	if filter.SignupSource != "" || filter.OrgID != 0 || len(sort) > 0 {
+		total, err := h.repo.CountSearch(c.UserContext(), filter)
+		if err != nil {
+			middleware.GetRequestLogger(c).Error("failed to count users", zap.Error(err))
+			return models.SendInternalError(c, "Failed to retrieve users", middleware.GetRequestID(c))
+		}
+		rows, err := h.repo.Search(c.UserContext(), filter, sort, int32(limit), int32(offset))
+		if err != nil {
+			middleware.GetRequestLogger(c).Error("failed to search users", zap.Error(err))
+			return models.SendInternalError(c, "Failed to retrieve users", middleware.GetRequestID(c))
+		}
+		meta = models.NewPaginationMeta(total, page, limit)
+		users = rows
+	} else {
+		total, err := h.repo.Count(c.UserContext())
+		if err != nil {
+			middleware.GetRequestLogger(c).Error("failed to count users", zap.Error(err))
+			return models.SendInternalError(c, "Failed to retrieve users", middleware.GetRequestID(c))
+		}
+		rows, err := h.repo.ListPaginated(c.UserContext(), int32(limit), int32(offset))
+		if err != nil {
+			middleware.GetRequestLogger(c).Error("failed to list all users", zap.Error(err))
+			return models.SendInternalError(c, "Failed to retrieve users", middleware.GetRequestID(c))
+		}
+		meta = models.NewPaginationMeta(total, page, limit)
+		users = rows
+	}
+
+	setLinkHeader(c, meta)
+	return c.JSON(fiber.Map{
+		"total":      meta.Total,
+		"users":      users,
+		"pagination": meta,
+	})
+}
+
+// Birthdays lists the users whose birthday falls within ?window= days
+// (default 7d, at most a year), today included, soonest first.
+func (h *AdminHandler) Birthdays(c *fiber.Ctx) error {
+	window := c.Query("window", "7d")
+	days, fe := queryparams.Int("window", strings.TrimSuffix(window, "d"), 1, 366)
+	if fe == nil && !strings.HasSuffix(window, "d") {
+		fe = &queryparams.FieldError{Param: "window", Value: window, Message: "must be a number of days, such as 7d"}
+	}
+	if fe != nil {
+		fe.Value = window
+		return sendQueryError(c, queryparams.Errors{*fe})
+	}
+
+	users, truncated, err := h.users.UpcomingBirthdays(c.UserContext(), int(days))
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to list upcoming birthdays", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve birthdays", middleware.GetRequestID(c))
+	}
+	if truncated {
+		c.Set(ResultTruncatedHeader, "true")
+	}
+
 	return c.JSON(fiber.Map{
 		"total": len(users),
 		"users": users,
 	})
 }
 
+// userLookupQuery holds the parameters of an email lookup.
+type userLookupQuery struct {
+	Email string `query:"email"`
+	Limit int    `query:"limit" default:"10" min:"1" max:"25"`
+}
+
+// LookupUsers finds users by their email or its beginning, for support
+// agents resolving tickets. Lookups are logged with who made them.
+func (h *AdminHandler) LookupUsers(c *fiber.Ctx) error {
+	var q userLookupQuery
+	if err := parseQuery(c, &q); err != nil {
+		return sendQueryError(c, err)
+	}
+
+	users, err := h.users.LookupByEmail(c.UserContext(), q.Email, q.Limit)
+	if errors.Is(err, service.ErrLookupTooShort) {
+		return sendQueryError(c, queryparams.Errors{{Param: "email", Value: q.Email, Message: err.Error()}})
+	}
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to look up users", zap.Error(err))
+		return models.SendInternalError(c, "Failed to look up users", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("admin looked up users by email",
+		zap.Int64("admin_id", middleware.GetAuthUser(c).ID),
+		zap.Int("matches", len(users)),
+	)
+	return c.JSON(models.UserLookupListResponse{Total: len(users), Users: users})
+}
+
 func (h *AdminHandler) GetStats(c *fiber.Ctx) error {
 	authUser := middleware.GetAuthUser(c)
 
 	middleware.GetRequestLogger(c).Info("admin accessing stats",
-		zap.Int32("admin_id", authUser.ID),
+		zap.Int64("admin_id", authUser.ID),
 	)
 
-	count, err := h.repo.Count(c.Context())
+	stats, err := h.repo.GetStats(c.UserContext())
 	if err != nil {
-		middleware.GetRequestLogger(c).Error("failed to get user count", zap.Error(err))
+		middleware.GetRequestLogger(c).Error("failed to get user stats", zap.Error(err))
 		return models.SendInternalError(c, "Failed to retrieve statistics", middleware.GetRequestID(c))
 	}
 
 	return c.JSON(fiber.Map{
-		"total_users": count,
-		"message":     "Admin statistics",
+		"total_users":          stats.TotalUsers,
+		"admin_users":          stats.AdminUsers,
+		"signups_last_7_days":  stats.SignupsLast7Days,
+		"signups_last_30_days": stats.SignupsLast30Days,
+		"average_age":          stats.AverageAge,
+		"refreshed_at":         stats.RefreshedAt.Time,
+		"message":              "Admin statistics",
 	})
 }
+
+func (h *AdminHandler) RefreshStats(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+
+	if err := h.repo.RefreshStats(c.UserContext()); err != nil {
+		middleware.GetRequestLogger(c).Error("failed to refresh user stats", zap.Error(err))
+		return models.SendInternalError(c, "Failed to refresh statistics", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("admin refreshed stats",
+		zap.Int64("admin_id", authUser.ID),
+	)
+
+	return h.GetStats(c)
+}
+
+// ForceLogout revokes every token issued to the user so far.
+func (h *AdminHandler) ForceLogout(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	target, err := h.manageableUser(c)
+	if target == nil {
+		return err
+	}
+
+	if err := h.revocations.RevokeUser(c.UserContext(), target.ID); err != nil {
+		middleware.GetRequestLogger(c).Error("failed to revoke user tokens", zap.Error(err))
+		return models.SendInternalError(c, "Failed to log out user", middleware.GetRequestID(c))
+	}
+
+	h.recordSecurityEvent(c, service.SecurityEventForceLogout, target.ID, nil)
+	middleware.GetRequestLogger(c).Warn("admin forced user logout",
+		zap.Int64("admin_id", authUser.ID),
+		zap.Int64("user_id", target.ID),
+	)
+	return c.JSON(fiber.Map{
+		"message": "All sessions for the user have been revoked",
+	})
+}
+
+// ForcePasswordReset invalidates the user's password, logs them out and
+// emails them a link to choose a new one. The email's language comes from
+// the locale query parameter.
+func (h *AdminHandler) ForcePasswordReset(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	target, err := h.manageableUser(c)
+	if target == nil {
+		return err
+	}
+
+	if err := h.resets.ForceReset(c.UserContext(), target.ID, c.Query("locale")); err != nil {
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return models.SendNotFound(c, "User not found", middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrInteractiveLoginDenied):
+			return models.SendError(c, fiber.StatusBadRequest, "Service accounts have no password to reset", models.ErrCodeServiceAccount, middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to force password reset", zap.Error(err))
+		return models.SendInternalError(c, "Failed to reset password", middleware.GetRequestID(c))
+	}
+
+	h.recordSecurityEvent(c, service.SecurityEventForcePasswordReset, target.ID, nil)
+	middleware.GetRequestLogger(c).Warn("admin forced password reset",
+		zap.Int64("admin_id", authUser.ID),
+		zap.Int64("user_id", target.ID),
+	)
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Password invalidated and reset link sent",
+	})
+}
+
+// Deactivate disables the user's account and logs them out. Moderators can
+// use it, but only on users ranked below them.
+func (h *AdminHandler) Deactivate(c *fiber.Ctx) error {
+	return h.setActive(c, false)
+}
+
+func (h *AdminHandler) Activate(c *fiber.Ctx) error {
+	return h.setActive(c, true)
+}
+
+func (h *AdminHandler) setActive(c *fiber.Ctx, active bool) error {
+	authUser := middleware.GetAuthUser(c)
+	target, err := h.manageableUser(c)
+	if target == nil {
+		return err
+	}
+
+	user, err := h.repo.SetActive(c.UserContext(), target.ID, active)
+	if err != nil {
+		if isConstraintError(err) {
+			return sendConstraintError(c, err)
+		}
+		middleware.GetRequestLogger(c).Error("failed to set user active", zap.Error(err))
+		return models.SendInternalError(c, "Failed to update user", middleware.GetRequestID(c))
+	}
+	if !active && h.revocations != nil {
+		if err := h.revocations.RevokeUser(c.UserContext(), user.ID); err != nil {
+			middleware.GetRequestLogger(c).Error("failed to revoke user tokens", zap.Error(err))
+			return models.SendInternalError(c, "Failed to log out user", middleware.GetRequestID(c))
+		}
+	}
+
+	event := service.SecurityEventUserDeactivated
+	if active {
+		event = service.SecurityEventUserActivated
+	}
+	h.recordSecurityEvent(c, event, user.ID, nil)
+	middleware.GetRequestLogger(c).Info("admin changed user active state",
+		zap.Int64("admin_id", authUser.ID),
+		zap.Int64("user_id", user.ID),
+		zap.Bool("active", active),
+	)
+	return c.JSON(user)
+}
+
+// Unlock lifts the lockout on the user's email after repeated failed
+// logins. Lockouts of the IPs the failures came from stay until they
+// expire.
+func (h *AdminHandler) Unlock(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	target, err := h.manageableUser(c)
+	if target == nil {
+		return err
+	}
+
+	cleared, err := h.lockout.Unlock(c.UserContext(), target.Email)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to unlock user", zap.Error(err))
+		return models.SendInternalError(c, "Failed to unlock user", middleware.GetRequestID(c))
+	}
+
+	h.recordSecurityEvent(c, service.SecurityEventAccountUnlocked, target.ID, nil)
+	middleware.GetRequestLogger(c).Info("admin unlocked user",
+		zap.Int64("admin_id", authUser.ID),
+		zap.Int64("user_id", target.ID),
+		zap.Bool("was_locked", cleared),
+	)
+	return c.JSON(fiber.Map{
+		"message":    "Login lockout cleared",
+		"was_locked": cleared,
+	})
+}
+
+// Lockouts lists the emails and IPs locked out after failed logins.
+func (h *AdminHandler) Lockouts(c *fiber.Ctx) error {
+	locked, err := h.lockout.Locked(c.UserContext())
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to list login lockouts", zap.Error(err))
+		return models.SendInternalError(c, "Failed to list lockouts", middleware.GetRequestID(c))
+	}
+	return c.JSON(fiber.Map{"lockouts": locked})
+}
+
+// UpdateRole changes the user's role and logs them out, since their tokens
+// carry the old role. With ?dry_run=true it only reports the change.
+func (h *AdminHandler) UpdateRole(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+
+	var req models.RoleUpdateRequest
+	if err := parseBody(c, &req); err != nil {
+		return sendBodyError(c, err)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	target, err := h.manageableUser(c)
+	if target == nil {
+		return err
+	}
+	if target.ID == authUser.ID {
+		return models.SendBadRequest(c, "You cannot change your own role", middleware.GetRequestID(c))
+	}
+
+	if isDryRun(c) {
+		result := models.DryRunResponse{DryRun: true, Action: "update_role", UserID: target.PublicID.String(), Changes: []models.DryRunChange{}, Effects: []string{}}
+		if target.Role != req.Role {
+			result.Changes = append(result.Changes, models.DryRunChange{Field: "role", From: target.Role, To: req.Role})
+		}
+		if h.revocations != nil {
+			result.Effects = append(result.Effects, "all of the user's sessions are revoked")
+		}
+		return c.JSON(result)
+	}
+
+	roles := service.NewRoleChanger(h.repo, h.revocations, h.securityLog, middleware.GetRequestLogger(c))
+	user, err := roles.Change(c.UserContext(), service.RoleChange{
+		UserID:    target.ID,
+		From:      target.Role,
+		To:        req.Role,
+		ActorID:   authUser.ID,
+		IPAddress: c.IP(),
+	})
+	if err != nil {
+		if isConstraintError(err) {
+			return sendConstraintError(c, err)
+		}
+		middleware.GetRequestLogger(c).Error("failed to update user role", zap.Error(err))
+		return models.SendInternalError(c, "Failed to update user", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Warn("admin changed user role",
+		zap.Int64("admin_id", authUser.ID),
+		zap.Int64("user_id", user.ID),
+		zap.String("from", target.Role),
+		zap.String("to", user.Role),
+	)
+	return c.JSON(user)
+}
+
+// DeleteUser deletes the user, who can be brought back with RestoreUser.
+// With ?hard=true it removes them for good instead, deleted already or not.
+// With ?dry_run=true it only reports what would be deleted; delete hooks
+// aren't run.
+func (h *AdminHandler) DeleteUser(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	hard := c.QueryBool("hard")
+	lookup := h.repo.GetByID
+	if hard {
+		lookup = h.anyUser
+	}
+	target, err := h.manageable(c, lookup)
+	if target == nil {
+		return err
+	}
+
+	if isDryRun(c) {
+		resp := models.DryRunResponse{
+			DryRun:  true,
+			Action:  "delete",
+			UserID:  target.PublicID.String(),
+			Changes: []models.DryRunChange{{Field: "user", From: target.Name, To: ""}},
+			Effects: []string{"the user can no longer sign in and is left out of lists until restored"},
+		}
+		if hard {
+			resp.Action = "hard_delete"
+			resp.Effects = []string{"the user's API keys, passkeys, login history and referrals are deleted with them"}
+		}
+		return c.JSON(resp)
+	}
+
+	remove := h.repo.SoftDelete
+	if hard {
+		remove = h.repo.HardDelete
+	}
+	if err := remove(c.UserContext(), target.ID); err != nil {
+		var rejected *hooks.RejectedError
+		if errors.As(err, &rejected) {
+			return models.SendError(c, fiber.StatusUnprocessableEntity, rejected.Reason, models.ErrCodeHookRejected, middleware.GetRequestID(c))
+		}
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.SendNotFound(c, "User not found", middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to delete user", zap.Error(err))
+		return models.SendInternalError(c, "Failed to delete user", middleware.GetRequestID(c))
+	}
+	// The auth middleware doesn't look users up, so their tokens would
+	// outlive them.
+	if h.revocations != nil {
+		if err := h.revocations.RevokeUser(c.UserContext(), target.ID); err != nil {
+			middleware.GetRequestLogger(c).Error("failed to revoke user tokens", zap.Error(err))
+			return models.SendInternalError(c, "Failed to log out user", middleware.GetRequestID(c))
+		}
+	}
+
+	// The user's public ID goes in the details, as after a hard delete the
+	// log can no longer look it up.
+	details := map[string]string{"user_id": target.PublicID.String()}
+	if hard {
+		details["hard"] = "true"
+	}
+	h.recordSecurityEvent(c, service.SecurityEventUserDeleted, target.ID, details)
+	middleware.GetRequestLogger(c).Warn("admin deleted user",
+		zap.Int64("admin_id", authUser.ID),
+		zap.Int64("user_id", target.ID),
+		zap.Bool("hard", hard),
+	)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// RestoreUser brings back a user deleted without ?hard=true. It fails with
+// 409 if their email has since been signed up with again.
+func (h *AdminHandler) RestoreUser(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	target, err := h.manageable(c, h.deletedUser)
+	if target == nil {
+		return err
+	}
+
+	user, err := h.repo.Restore(c.UserContext(), target.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.SendNotFound(c, "User not found", middleware.GetRequestID(c))
+		}
+		if errors.Is(err, repository.ErrEmailAlreadyExists) {
+			return models.SendConflict(c, "Another user has signed up with this email", middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to restore user", zap.Error(err))
+		return models.SendInternalError(c, "Failed to restore user", middleware.GetRequestID(c))
+	}
+
+	h.recordSecurityEvent(c, service.SecurityEventUserRestored, user.ID, nil)
+	middleware.GetRequestLogger(c).Warn("admin restored user",
+		zap.Int64("admin_id", authUser.ID),
+		zap.Int64("user_id", user.ID),
+	)
+	return c.JSON(user)
+}
+
+// recordSecurityEvent adds an event by the caller to the security log. The
+// action has already been taken, so failing to record it is only logged.
+func (h *AdminHandler) recordSecurityEvent(c *fiber.Ctx, eventType string, targetID int64, details map[string]string) {
+	if h.securityLog == nil {
+		return
+	}
+	err := h.securityLog.Record(c.UserContext(), service.SecurityEvent{
+		Type:      eventType,
+		ActorID:   middleware.GetAuthUser(c).ID,
+		TargetID:  targetID,
+		Details:   details,
+		IPAddress: c.IP(),
+	})
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to record security event", zap.String("event", eventType), zap.Error(err))
+	}
+}
+
+// manageableUser loads the user named by the id parameter and checks the
+// caller may manage them. When it returns nil the error response has
+// already been sent.
+func (h *AdminHandler) manageableUser(c *fiber.Ctx) (*generated.GetUserByIDRow, error) {
+	return h.manageable(c, h.repo.GetByID)
+}
+
+// manageable is manageableUser with the user loaded by lookup.
+func (h *AdminHandler) manageable(c *fiber.Ctx, lookup func(context.Context, int64) (generated.GetUserByIDRow, error)) (*generated.GetUserByIDRow, error) {
+	authUser := middleware.GetAuthUser(c)
+	id, ok := middleware.GetUserID(c)
+	if !ok {
+		return nil, models.SendBadRequest(c, "Invalid user ID", middleware.GetRequestID(c))
+	}
+
+	target, err := lookup(c.UserContext(), id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, models.SendNotFound(c, "User not found", middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to get user", zap.Error(err))
+		return nil, models.SendInternalError(c, "Failed to retrieve user", middleware.GetRequestID(c))
+	}
+
+	resource := policy.Resource{Type: "user", ID: c.Params("id"), OwnerID: target.ID, Role: target.Role}
+	if !h.policies.Allowed(middleware.PolicySubject(c), policy.ActionUsersManage, resource) {
+		middleware.GetRequestLogger(c).Warn("admin action on equal or higher role denied",
+			zap.Int64("admin_id", authUser.ID),
+			zap.Int64("user_id", target.ID),
+		)
+		return nil, models.SendError(c, fiber.StatusForbidden, "Forbidden: user has an equal or higher role", models.ErrCodeInsufficientPerms, middleware.GetRequestID(c))
+	}
+	return &target, nil
+}
+
+// deletedUser looks up a soft-deleted user.
+func (h *AdminHandler) deletedUser(ctx context.Context, id int64) (generated.GetUserByIDRow, error) {
+	user, err := h.repo.GetDeletedByID(ctx, id)
+	return generated.GetUserByIDRow(user), err
+}
+
+// anyUser looks up a user, deleted or not.
+func (h *AdminHandler) anyUser(ctx context.Context, id int64) (generated.GetUserByIDRow, error) {
+	user, err := h.repo.GetByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return h.deletedUser(ctx, id)
+	}
+	return user, err
+}
+
+// isDryRun reports whether the request asked to preview its changes with
+// ?dry_run=true instead of making them.
+func isDryRun(c *fiber.Ctx) bool {
+	return c.QueryBool("dry_run")
+}
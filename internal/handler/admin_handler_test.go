@@ -0,0 +1,466 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/mailer"
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/policy"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/service"
+	"BACKEND/internal/templates"
+)
+
+type fakeAdminUserStore struct {
+	repository.UserStore
+	writes int
+}
+
+// testPublicID is the public ID the fake store gives the user with id.
+func testPublicID(id int64) string {
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", id)
+}
+
+func (f *fakeAdminUserStore) GetIDByPublicID(ctx context.Context, publicID string) (int64, error) {
+	for id := int64(1); id < 10; id++ {
+		if publicID == testPublicID(id) {
+			return id, nil
+		}
+	}
+	return 0, pgx.ErrNoRows
+}
+
+func (f *fakeAdminUserStore) GetByID(ctx context.Context, id int64) (generated.GetUserByIDRow, error) {
+	var publicID pgtype.UUID
+	_ = publicID.Scan(testPublicID(id))
+	return generated.GetUserByIDRow{ID: id, Name: "Jane Doe", Role: "user", PublicID: publicID}, nil
+}
+
+func (f *fakeAdminUserStore) UpdateRole(ctx context.Context, id int64, role string) (generated.UpdateUserRoleRow, error) {
+	f.writes++
+	return generated.UpdateUserRoleRow{ID: id, Role: role}, nil
+}
+
+func (f *fakeAdminUserStore) SoftDelete(ctx context.Context, id int64) error {
+	f.writes++
+	return nil
+}
+
+func newTestAdminApp(store repository.UserStore) *fiber.App {
+	h := NewAdminHandler(store, policy.NewEngine(policy.DefaultRules()...), zap.NewNop())
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(middleware.AuthUserKey, models.AuthUser{ID: 1, Role: "admin", AccountType: "human"})
+		return c.Next()
+	})
+	app.Put("/admin/users/:id/role", middleware.UserParam(store), h.UpdateRole)
+	app.Delete("/admin/users/:id", middleware.UserParam(store), h.DeleteUser)
+	app.Post("/admin/users/:id/restore", middleware.UserParam(store), h.RestoreUser)
+	return app
+}
+
+func TestAdminHandler_DryRun(t *testing.T) {
+	store := &fakeAdminUserStore{}
+	app := newTestAdminApp(store)
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/users/"+testPublicID(7)+"/role?dry_run=true", strings.NewReader(`{"role":"moderator"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	var result models.DryRunResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK || !result.DryRun || result.UserID != testPublicID(7) || len(result.Changes) != 1 || result.Changes[0].To != "moderator" {
+		t.Errorf("role dry run = %d %+v; want the role change reported", resp.StatusCode, result)
+	}
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodDelete, "/admin/users/"+testPublicID(7)+"?dry_run=true", nil))
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		t.Errorf("delete dry run status = %d; want 200", resp.StatusCode)
+	}
+	if store.writes != 0 {
+		t.Errorf("dry runs wrote %d times; want none", store.writes)
+	}
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodDelete, "/admin/users/"+testPublicID(7), nil))
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusNoContent || store.writes != 1 {
+		t.Errorf("delete status = %d with %d writes; want 204 and one write", resp.StatusCode, store.writes)
+	}
+}
+
+func TestAdminHandler_DryRunStillValidates(t *testing.T) {
+	app := newTestAdminApp(&fakeAdminUserStore{})
+
+	req := httptest.NewRequest(http.MethodPut, "/admin/users/"+testPublicID(1)+"/role?dry_run=true", strings.NewReader(`{"role":"user"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Errorf("dry run of own role change status = %d; want 400", resp.StatusCode)
+	}
+}
+
+func TestAdminHandler_PublicIDs(t *testing.T) {
+	store := &fakeAdminUserStore{}
+	app := newTestAdminApp(store)
+
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"/admin/users/7", fiber.StatusBadRequest},
+		{"/admin/users/not-a-uuid", fiber.StatusBadRequest},
+		{"/admin/users/" + testPublicID(42), fiber.StatusNotFound},
+		{"/admin/users/" + strings.ToUpper(testPublicID(7)), fiber.StatusNoContent},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, tt.path, nil))
+		if err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+		if resp.StatusCode != tt.want {
+			t.Errorf("DELETE %s status = %d; want %d", tt.path, resp.StatusCode, tt.want)
+		}
+	}
+	if store.writes != 1 {
+		t.Errorf("got %d writes; want only the known user deleted", store.writes)
+	}
+}
+
+func (f *fakeAdminUserStore) Count(ctx context.Context) (int64, error) {
+	return 25, nil
+}
+
+func (f *fakeAdminUserStore) ListPaginated(ctx context.Context, limit, offset int32) ([]generated.ListUsersPaginatedRow, error) {
+	rows := make([]generated.ListUsersPaginatedRow, limit)
+	for i := range rows {
+		rows[i].ID = int64(offset) + int64(i) + 1
+	}
+	return rows, nil
+}
+
+func (f *fakeAdminUserStore) List(ctx context.Context) ([]generated.ListUsersRow, error) {
+	return make([]generated.ListUsersRow, 25), nil
+}
+
+func TestAdminHandler_AlwaysPaginate(t *testing.T) {
+	store := &fakeAdminUserStore{}
+	users := service.NewUserService(store)
+	h := NewAdminHandler(store, policy.NewEngine(policy.DefaultRules()...), zap.NewNop())
+	h.SetPagination(users)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(middleware.AuthUserKey, models.AuthUser{ID: 1, Role: "admin", AccountType: "human"})
+		return c.Next()
+	})
+	app.Get("/admin/users", h.GetAllUsers)
+
+	list := func(path string) (*http.Response, map[string]json.RawMessage) {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		if err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+		var body map[string]json.RawMessage
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		return resp, body
+	}
+
+	resp, body := list("/admin/users")
+	if resp.Header.Get(middleware.DeprecationHeader) != "true" || body["pagination"] != nil {
+		t.Errorf("full list: Deprecation %q, pagination %s; want deprecated and unpaged", resp.Header.Get(middleware.DeprecationHeader), body["pagination"])
+	}
+
+	resp, body = list("/admin/users?page=2&limit=5")
+	if resp.Header.Get(middleware.DeprecationHeader) != "" || !strings.Contains(string(body["pagination"]), `"page":2`) {
+		t.Errorf("paged list: Deprecation %q, pagination %s; want page 2", resp.Header.Get(middleware.DeprecationHeader), body["pagination"])
+	}
+
+	users.SetAlwaysPaginate(true)
+	resp, body = list("/admin/users")
+	var rows []json.RawMessage
+	_ = json.Unmarshal(body["users"], &rows)
+	if resp.Header.Get(middleware.DeprecationHeader) != "true" || len(rows) != service.DefaultPageSize {
+		t.Errorf("forced list: Deprecation %q, %d users; want deprecated first page of %d", resp.Header.Get(middleware.DeprecationHeader), len(rows), service.DefaultPageSize)
+	}
+}
+
+func TestAdminHandler_SoftDelete(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryUserStore()
+	if _, err := store.CreateWithAuth(ctx, "Admin", "admin@example.com", "hash", "admin", "admin", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	jane, err := store.CreateWithAuth(ctx, "Jane", "jane@example.com", "hash", "", "web", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := newTestAdminApp(store)
+	path := "/admin/users/" + jane.PublicID.String()
+
+	steps := []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodPost, path + "/restore", fiber.StatusNotFound},
+		{http.MethodDelete, path, fiber.StatusNoContent},
+		{http.MethodDelete, path, fiber.StatusNotFound},
+		{http.MethodPost, path + "/restore", fiber.StatusOK},
+		{http.MethodDelete, path, fiber.StatusNoContent},
+		{http.MethodDelete, path + "?hard=true", fiber.StatusNoContent},
+		{http.MethodPost, path + "/restore", fiber.StatusNotFound},
+	}
+	for _, step := range steps {
+		resp, err := app.Test(httptest.NewRequest(step.method, step.path, nil))
+		if err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+		if resp.StatusCode != step.want {
+			t.Fatalf("%s %s status = %d; want %d", step.method, step.path, resp.StatusCode, step.want)
+		}
+	}
+	if _, err := store.GetIDByPublicID(ctx, jane.PublicID.String()); err == nil {
+		t.Error("user still exists after a hard delete")
+	}
+}
+
+func TestAdminHandler_ForceLogoutHigherRole(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryUserStore()
+	moderator, err := store.CreateWithAuth(ctx, "Moderator", "moderator@example.com", "hash", "moderator", "admin", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := store.CreateWithAuth(ctx, "Admin", "admin@example.com", "hash", "admin", "admin", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	jane, err := store.CreateWithAuth(ctx, "Jane", "jane@example.com", "hash", "", "web", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	revocations := service.NewTokenRevocationService(repository.NewMemoryTokenRevocationStore())
+	h := NewAdminHandler(store, policy.NewEngine(policy.DefaultRules()...), zap.NewNop())
+	h.SetCredentialControls(revocations, nil)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(middleware.AuthUserKey, models.AuthUser{ID: moderator.ID, Role: "moderator", AccountType: "human"})
+		return c.Next()
+	})
+	app.Post("/admin/users/:id/force-logout", middleware.UserParam(store), h.ForceLogout)
+
+	tests := []struct {
+		name     string
+		user     generated.CreateUserRow
+		expected int
+	}{
+		{"higher role", admin, fiber.StatusForbidden},
+		{"lower role", jane, fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/admin/users/"+tt.user.PublicID.String()+"/force-logout", nil))
+		if err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+		if resp.StatusCode != tt.expected {
+			t.Errorf("%s: status = %d; want %d", tt.name, resp.StatusCode, tt.expected)
+		}
+		if revoked := revocations.IsRevoked(tt.user.ID, time.Now().Add(-time.Minute)); revoked != (tt.expected == fiber.StatusOK) {
+			t.Errorf("%s: tokens revoked = %v", tt.name, revoked)
+		}
+	}
+}
+
+func TestAdminHandler_ForcePasswordResetHigherRole(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryUserStore()
+	moderator, err := store.CreateWithAuth(ctx, "Moderator", "moderator@example.com", "hash", "moderator", "admin", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := store.CreateWithAuth(ctx, "Admin", "admin@example.com", "hash", "admin", "admin", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	jane, err := store.CreateWithAuth(ctx, "Jane", "jane@example.com", "hash", "", "web", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	renderer, err := templates.NewRenderer(templates.Branding{ProductName: "Test", SupportEmail: "support@example.com"}, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	revocations := service.NewTokenRevocationService(repository.NewMemoryTokenRevocationStore())
+	resets := service.NewPasswordResetService(store, service.NewAuthService(store), revocations, mailer.NewLog(zap.NewNop()), renderer, service.PasswordResetConfig{
+		Secret: "reset-secret",
+		TTL:    time.Hour,
+		URL:    "https://example.com/reset-password",
+	}, zap.NewNop())
+	h := NewAdminHandler(store, policy.NewEngine(policy.DefaultRules()...), zap.NewNop())
+	h.SetCredentialControls(revocations, resets)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(middleware.AuthUserKey, models.AuthUser{ID: moderator.ID, Role: "moderator", AccountType: "human"})
+		return c.Next()
+	})
+	app.Post("/admin/users/:id/force-password-reset", middleware.UserParam(store), h.ForcePasswordReset)
+
+	tests := []struct {
+		name     string
+		user     generated.CreateUserRow
+		expected int
+	}{
+		{"higher role", admin, fiber.StatusForbidden},
+		{"lower role", jane, fiber.StatusAccepted},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/admin/users/"+tt.user.PublicID.String()+"/force-password-reset", nil))
+		if err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+		if resp.StatusCode != tt.expected {
+			t.Errorf("%s: status = %d; want %d", tt.name, resp.StatusCode, tt.expected)
+		}
+		if revoked := revocations.IsRevoked(tt.user.ID, time.Now().Add(-time.Minute)); revoked != (tt.expected == fiber.StatusAccepted) {
+			t.Errorf("%s: tokens revoked = %v", tt.name, revoked)
+		}
+	}
+}
+
+func TestAdminHandler_BirthdaysWindow(t *testing.T) {
+	store := repository.NewMemoryUserStore()
+	h := NewAdminHandler(store, policy.NewEngine(policy.DefaultRules()...), zap.NewNop())
+	h.SetPagination(service.NewUserService(store))
+	app := fiber.New()
+	app.Get("/admin/users/birthdays", h.Birthdays)
+
+	for path, want := range map[string]int{
+		"/admin/users/birthdays":             fiber.StatusOK,
+		"/admin/users/birthdays?window=366d": fiber.StatusOK,
+		"/admin/users/birthdays?window=7":    fiber.StatusBadRequest,
+		"/admin/users/birthdays?window=0d":   fiber.StatusBadRequest,
+		"/admin/users/birthdays?window=367d": fiber.StatusBadRequest,
+		"/admin/users/birthdays?window=1w":   fiber.StatusBadRequest,
+	} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+		if err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+		if resp.StatusCode != want {
+			t.Errorf("GET %s = %d; want %d", path, resp.StatusCode, want)
+		}
+	}
+}
+
+func TestAdminHandler_SortedUsers(t *testing.T) {
+	store := repository.NewMemoryUserStore()
+	for _, name := range []string{"Cy", "Al", "Bo"} {
+		if _, err := store.CreateWithAuth(context.Background(), name, name+"@example.com", "hash", "", "web", time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := NewAdminHandler(store, policy.NewEngine(policy.DefaultRules()...), zap.NewNop())
+	h.SetPagination(service.NewUserService(store))
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(middleware.AuthUserKey, models.AuthUser{ID: 1, Role: "admin", AccountType: "human"})
+		return c.Next()
+	})
+	app.Get("/admin/users", h.GetAllUsers)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/admin/users?sort=-name&signup_source=web", nil))
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	var body struct {
+		Users []struct{ Name string } `json:"users"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(body.Users) != 3 || body.Users[0].Name != "Cy" || body.Users[2].Name != "Al" {
+		t.Errorf("sort=-name: %+v; want Cy, Bo, Al", body.Users)
+	}
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/admin/users?sort=password_hash", nil))
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	var errResp models.ErrorResponse
+	_ = json.NewDecoder(resp.Body).Decode(&errResp)
+	if resp.StatusCode != fiber.StatusBadRequest || errResp.Error.Code != models.ErrCodeValidationFailed {
+		t.Errorf("sort=password_hash: %d %+v; want 400 VALIDATION_FAILED", resp.StatusCode, errResp)
+	}
+}
+
+func TestAdminHandler_ListCap(t *testing.T) {
+	store := repository.NewMemoryUserStore()
+	for _, name := range []string{"Al", "Bo", "Cy", "Di"} {
+		if _, err := store.CreateWithAuth(context.Background(), name, name+"@example.com", "hash", "", "web", time.Date(1990, 1, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	users := service.NewUserService(store)
+	users.SetListCap(3)
+	h := NewAdminHandler(store, policy.NewEngine(policy.DefaultRules()...), zap.NewNop())
+	h.SetPagination(users)
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(middleware.AuthUserKey, models.AuthUser{ID: 1, Role: "admin", AccountType: "human"})
+		return c.Next()
+	})
+	app.Get("/admin/users", h.GetAllUsers)
+
+	tests := []struct {
+		path      string
+		users     int
+		truncated string
+	}{
+		{"/admin/users", 3, "true"},
+		{"/admin/users?signup_source=web", 3, "true"},
+		{"/admin/users?signup_source=api", 0, ""},
+	}
+	for _, tt := range tests {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+		var body struct {
+			Total int               `json:"total"`
+			Users []json.RawMessage `json:"users"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(body.Users) != tt.users || body.Total != tt.users || resp.Header.Get(ResultTruncatedHeader) != tt.truncated {
+			t.Errorf("GET %s = %d users (total %d), %s %q; want %d users, %q", tt.path, len(body.Users), body.Total, ResultTruncatedHeader, resp.Header.Get(ResultTruncatedHeader), tt.users, tt.truncated)
+		}
+	}
+}
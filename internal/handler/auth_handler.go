@@ -1,23 +1,44 @@
 package handler
 
 import (
-	"strings"
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 
+	"BACKEND/hooks"
+	"BACKEND/internal/geoip"
 	"BACKEND/internal/middleware"
 	"BACKEND/internal/models"
+	"BACKEND/internal/repository"
 	"BACKEND/internal/service"
 )
+
 type AuthHandler struct {
 	authService  service.AuthServiceInterface
 	validate     *validator.Validate
 	logger       *zap.Logger
 	cookieSecure bool
+	loginHistory repository.LoginHistoryStore
+	detector     *service.BruteForceDetector
+	locator      geoip.Locator
+	magicLinks   *service.MagicLinkService
+	resets       *service.PasswordResetService
+	referrals    *service.ReferralService
+	refresh      *service.RefreshTokenService
+	revocations  *service.TokenRevocationService
+	sessions     *service.SessionService
+	risk         *service.LoginRisk
 }
 
+// refreshTokenCookie holds the refresh token issued at login.
+const refreshTokenCookie = "refresh_token"
+
 func NewAuthHandler(authService service.AuthServiceInterface, logger *zap.Logger, cookieSecure bool) *AuthHandler {
 	return &AuthHandler{
 		authService:  authService,
@@ -27,26 +48,78 @@ func NewAuthHandler(authService service.AuthServiceInterface, logger *zap.Logger
 	}
 }
 
+// SetLoginHistory records every password login attempt in store.
+func (h *AuthHandler) SetLoginHistory(store repository.LoginHistoryStore) {
+	h.loginHistory = store
+}
+
+// SetBruteForceDetector reports every password login attempt to detector.
+func (h *AuthHandler) SetBruteForceDetector(detector *service.BruteForceDetector) {
+	h.detector = detector
+}
+
+// SetGeoIP adds the country and city to recorded login attempts.
+func (h *AuthHandler) SetGeoIP(locator geoip.Locator) {
+	h.locator = locator
+}
+
+// SetMagicLinks enables password-less login through emailed links.
+func (h *AuthHandler) SetMagicLinks(svc *service.MagicLinkService) {
+	h.magicLinks = svc
+}
+
+// SetPasswordResets enables setting a new password from an emailed reset
+// link.
+func (h *AuthHandler) SetPasswordResets(svc *service.PasswordResetService) {
+	h.resets = svc
+}
+
+// SetReferrals credits signups made with ?ref=<code> to the code's owner.
+func (h *AuthHandler) SetReferrals(svc *service.ReferralService) {
+	h.referrals = svc
+}
+
+// SetRefreshTokens issues a refresh token with each password or magic link
+// login, to be traded for a new JWT at /auth/refresh.
+func (h *AuthHandler) SetRefreshTokens(svc *service.RefreshTokenService) {
+	h.refresh = svc
+}
+
+// SetSessions lets users list their sessions and sign out of them one at a
+// time, and makes Logout end the caller's.
+func (h *AuthHandler) SetSessions(svc *service.SessionService) {
+	h.sessions = svc
+}
+
+// SetLoginRisk enables confirming challenged logins at
+// /auth/login/challenge.
+func (h *AuthHandler) SetLoginRisk(risk *service.LoginRisk) {
+	h.risk = risk
+}
+
+// SetTokenRevocation lets Logout revoke the JWT it was called with, so the
+// token stops working before it expires.
+func (h *AuthHandler) SetTokenRevocation(svc *service.TokenRevocationService) {
+	h.revocations = svc
+}
+
 func (h *AuthHandler) Signup(c *fiber.Ctx) error {
 	var req models.SignupRequest
 
-	if err := c.BodyParser(&req); err != nil {
-		middleware.GetRequestLogger(c).Error("failed to parse signup request", zap.Error(err))
-		return models.SendBadRequest(c, "Invalid request body", middleware.GetRequestID(c))
+	if err := parseBody(c, &req); err != nil {
+		middleware.LogRejected(c, fiber.StatusBadRequest, "failed to parse signup request", zap.Error(err))
+		return sendBodyError(c, err)
 	}
 
 	if err := h.validate.Struct(req); err != nil {
-		middleware.GetRequestLogger(c).Error("signup validation failed", zap.Error(err))
-		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
-	}
-
-	if err := h.authService.ValidatePasswordStrength(req.Password); err != nil {
-		middleware.GetRequestLogger(c).Warn("weak password attempt", zap.String("email", req.Email), zap.Error(err))
+		middleware.LogRejected(c, fiber.StatusBadRequest, "signup validation failed", zap.Error(err))
 		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
 	}
 
+	// CreateUser checks the password strength itself, so a weak password
+	// is counted as a signup outcome.
 	user, err := h.authService.CreateUser(
-		c.Context(),
+		c.UserContext(),
 		req.Name,
 		req.Email,
 		req.Password,
@@ -54,14 +127,24 @@ func (h *AuthHandler) Signup(c *fiber.Ctx) error {
 		"user",
 	)
 	if err != nil {
-		if err == service.ErrEmailAlreadyExists {
+		if service.IsWeakPassword(err) {
+			middleware.GetRequestLogger(c).Warn("weak password attempt", zap.String("email", req.Email), zap.Error(err))
+			return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+		}
+
+		var rejected *hooks.RejectedError
+		if errors.As(err, &rejected) {
+			middleware.GetRequestLogger(c).Warn("signup rejected by hook", zap.String("email", req.Email), zap.String("reason", rejected.Reason))
+			return models.SendError(c, fiber.StatusUnprocessableEntity, rejected.Reason, models.ErrCodeHookRejected, middleware.GetRequestID(c))
+		}
+
+		if errors.Is(err, service.ErrEmailAlreadyExists) {
 			middleware.GetRequestLogger(c).Warn("signup attempt with existing email", zap.String("email", req.Email))
 			return models.SendConflict(c, "Email already exists", middleware.GetRequestID(c))
 		}
 
-		if strings.Contains(err.Error(), "duplicate key") || strings.Contains(err.Error(), "unique constraint") {
-			middleware.GetRequestLogger(c).Warn("signup attempt with existing email (db error)", zap.String("email", req.Email))
-			return models.SendConflict(c, "Email already exists", middleware.GetRequestID(c))
+		if isConstraintError(err) {
+			return sendConstraintError(c, err)
 		}
 
 		middleware.GetRequestLogger(c).Error("failed to create user", zap.Error(err))
@@ -69,12 +152,19 @@ func (h *AuthHandler) Signup(c *fiber.Ctx) error {
 	}
 
 	middleware.GetRequestLogger(c).Info("user signed up successfully",
-		zap.Int32("user_id", user.ID),
+		zap.Int64("user_id", user.ID),
 		zap.String("email", user.Email),
 	)
 
+	// A bad referral code never fails the signup.
+	if ref := c.Query("ref"); ref != "" && h.referrals != nil {
+		if err := h.referrals.RecordSignup(c.UserContext(), ref, user.ID); err != nil {
+			middleware.GetRequestLogger(c).Warn("failed to record referral", zap.Int64("user_id", user.ID), zap.String("ref", ref), zap.Error(err))
+		}
+	}
+
 	return c.Status(fiber.StatusCreated).JSON(models.SignupResponse{
-		ID:        user.ID,
+		ID:        user.PublicID.String(),
 		Name:      user.Name,
 		Email:     user.Email,
 		Role:      user.Role,
@@ -85,26 +175,63 @@ func (h *AuthHandler) Signup(c *fiber.Ctx) error {
 func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	var req models.LoginRequest
 
-	if err := c.BodyParser(&req); err != nil {
-		middleware.GetRequestLogger(c).Error("failed to parse login request", zap.Error(err))
-		return models.SendBadRequest(c, "Invalid request body", middleware.GetRequestID(c))
+	if err := parseBody(c, &req); err != nil {
+		middleware.LogRejected(c, fiber.StatusBadRequest, "failed to parse login request", zap.Error(err))
+		return sendBodyError(c, err)
 	}
 
 	if err := h.validate.Struct(req); err != nil {
-		middleware.GetRequestLogger(c).Error("login validation failed", zap.Error(err))
+		middleware.LogRejected(c, fiber.StatusBadRequest, "login validation failed", zap.Error(err))
 		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
 	}
 
-	user, token, err := h.authService.Login(c.Context(), req.Email, req.Password)
+	user, token, err := h.authService.Login(clientContext(c), req.Email, req.Password)
+	h.recordLogin(c, user.ID, req.Email, err)
 	if err != nil {
+		var challenge *service.LoginChallengeError
+		if errors.As(err, &challenge) {
+			middleware.GetRequestLogger(c).Info("login challenged",
+				zap.Int64("user_id", user.ID),
+				zap.Strings("signals", challenge.Signals),
+			)
+			return c.Status(fiber.StatusAccepted).JSON(models.LoginChallengeResponse{
+				Message:     "Enter the code sent to your email to finish logging in",
+				ChallengeID: challenge.ChallengeID,
+				ExpiresAt:   challenge.ExpiresAt,
+			})
+		}
+		var locked *service.AccountLockedError
+		if errors.As(err, &locked) {
+			middleware.GetRequestLogger(c).Warn("login attempt while locked out", zap.String("email", req.Email), zap.Time("locked_until", locked.Until))
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(time.Until(locked.Until).Seconds()))))
+			return models.SendError(c, fiber.StatusLocked, "Too many failed logins, try again later", models.ErrCodeAccountLocked, middleware.GetRequestID(c))
+		}
 		if err == service.ErrInvalidCredentials {
 			middleware.GetRequestLogger(c).Warn("invalid login attempt", zap.String("email", req.Email))
 			return models.SendError(c, fiber.StatusUnauthorized, "Invalid email or password", models.ErrCodeInvalidCredentials, middleware.GetRequestID(c))
 		}
+		if err == service.ErrAccountDisabled {
+			middleware.GetRequestLogger(c).Warn("login attempt on disabled account", zap.String("email", req.Email))
+			return models.SendError(c, fiber.StatusForbidden, "Account is disabled", models.ErrCodeAccountDisabled, middleware.GetRequestID(c))
+		}
+		if err == service.ErrInteractiveLoginDenied {
+			middleware.GetRequestLogger(c).Warn("login attempt on service account", zap.String("email", req.Email))
+			return models.SendError(c, fiber.StatusForbidden, "Service accounts must authenticate with an API key", models.ErrCodeServiceAccount, middleware.GetRequestID(c))
+		}
+		if errors.Is(err, service.ErrSessionLimitReached) {
+			middleware.GetRequestLogger(c).Warn("login over the session limit", zap.Int64("user_id", user.ID))
+			return models.SendError(c, fiber.StatusConflict, "Too many active sessions; sign out of another device first", models.ErrCodeSessionLimit, middleware.GetRequestID(c))
+		}
 		middleware.GetRequestLogger(c).Error("failed to login", zap.Error(err))
 		return models.SendInternalError(c, "Failed to authenticate user", middleware.GetRequestID(c))
 	}
 
+	refreshToken, err := h.issueRefreshToken(c, user.ID, token)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to issue refresh token", zap.Error(err))
+		return models.SendInternalError(c, "Failed to authenticate user", middleware.GetRequestID(c))
+	}
+
 	cookie := &fiber.Cookie{
 		Name:     "token",
 		Value:    token,
@@ -117,21 +244,511 @@ func (h *AuthHandler) Login(c *fiber.Ctx) error {
 	c.Cookie(cookie)
 
 	middleware.GetRequestLogger(c).Info("user logged in successfully",
-		zap.Int32("user_id", user.ID),
+		zap.Int64("user_id", user.ID),
 		zap.String("email", user.Email),
 	)
 	return c.Status(fiber.StatusOK).JSON(models.LoginResponse{
 		Message: "Login successful",
 		User: struct {
-			ID    int32  `json:"id"`
+			ID    string `json:"id" format:"uuid"`
 			Name  string `json:"name"`
 			Email string `json:"email"`
 			Role  string `json:"role"`
 		}{
-			ID:    user.ID,
+			ID:    user.PublicID.String(),
 			Name:  user.Name,
 			Email: user.Email,
 			Role:  user.Role,
 		},
+		RefreshToken: refreshToken,
+	})
+}
+
+// ConfirmLoginChallenge completes a login that was challenged, given the
+// code emailed to the user.
+func (h *AuthHandler) ConfirmLoginChallenge(c *fiber.Ctx) error {
+	var req models.LoginChallengeRequest
+
+	if err := parseBody(c, &req); err != nil {
+		return sendBodyError(c, err)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	user, jwtToken, err := h.risk.Confirm(clientContext(c), req.ChallengeID, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrLoginChallengeInvalid):
+			return models.SendError(c, fiber.StatusUnauthorized, "Login challenge is invalid or has expired, log in again", models.ErrCodeInvalidToken, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrLoginChallengeCode):
+			middleware.GetRequestLogger(c).Warn("wrong login challenge code")
+			return models.SendError(c, fiber.StatusUnauthorized, "Code is incorrect", models.ErrCodeInvalidCredentials, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrAccountDisabled):
+			return models.SendError(c, fiber.StatusForbidden, "Account is disabled", models.ErrCodeAccountDisabled, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrSessionLimitReached):
+			return models.SendError(c, fiber.StatusConflict, "Too many active sessions; sign out of another device first", models.ErrCodeSessionLimit, middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to confirm login challenge", zap.Error(err))
+		return models.SendInternalError(c, "Failed to authenticate user", middleware.GetRequestID(c))
+	}
+	h.recordLogin(c, user.ID, user.Email, nil)
+
+	refreshToken, err := h.issueRefreshToken(c, user.ID, jwtToken)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to issue refresh token", zap.Error(err))
+		return models.SendInternalError(c, "Failed to authenticate user", middleware.GetRequestID(c))
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "token",
+		Value:    jwtToken,
+		Path:     "/",
+		MaxAge:   int(h.authService.GetJWTExpiry().Seconds()),
+		HTTPOnly: true,
+		Secure:   h.cookieSecure,
+		SameSite: "Strict",
+	})
+
+	middleware.GetRequestLogger(c).Info("user logged in after challenge",
+		zap.Int64("user_id", user.ID),
+		zap.String("email", user.Email),
+	)
+
+	var resp models.LoginResponse
+	resp.Message = "Login successful"
+	resp.User.ID = user.PublicID.String()
+	resp.User.Name = user.Name
+	resp.User.Email = user.Email
+	resp.User.Role = user.Role
+	resp.RefreshToken = refreshToken
+	return c.JSON(resp)
+}
+
+// RequestMagicLink emails a login link. It answers 202 whether or not the
+// address belongs to an account.
+func (h *AuthHandler) RequestMagicLink(c *fiber.Ctx) error {
+	var req models.MagicLinkRequest
+
+	if err := parseBody(c, &req); err != nil {
+		return sendBodyError(c, err)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	if err := h.magicLinks.Send(c.UserContext(), req.Email, req.Locale); err != nil {
+		middleware.GetRequestLogger(c).Error("failed to send magic link", zap.Error(err))
+		return models.SendInternalError(c, "Failed to send login link", middleware.GetRequestID(c))
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "If the address belongs to an account, a login link has been sent",
+	})
+}
+
+// VerifyMagicLink logs in the user a magic link was sent to. Each link works
+// once.
+func (h *AuthHandler) VerifyMagicLink(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if token == "" {
+		return models.SendBadRequest(c, "Missing token", middleware.GetRequestID(c))
+	}
+
+	user, jwtToken, err := h.magicLinks.Verify(clientContext(c), token)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrMagicLinkInvalid):
+			return models.SendError(c, fiber.StatusUnauthorized, "Login link is invalid", models.ErrCodeInvalidToken, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrMagicLinkExpired):
+			return models.SendError(c, fiber.StatusGone, "Login link has expired", models.ErrCodeURLExpired, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrMagicLinkUsed):
+			return models.SendError(c, fiber.StatusGone, "Login link has already been used", models.ErrCodeURLUsed, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrAccountDisabled):
+			return models.SendError(c, fiber.StatusForbidden, "Account is disabled", models.ErrCodeAccountDisabled, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrInteractiveLoginDenied):
+			return models.SendError(c, fiber.StatusForbidden, "Service accounts must authenticate with an API key", models.ErrCodeServiceAccount, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrSessionLimitReached):
+			return models.SendError(c, fiber.StatusConflict, "Too many active sessions; sign out of another device first", models.ErrCodeSessionLimit, middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to verify magic link", zap.Error(err))
+		return models.SendInternalError(c, "Failed to authenticate user", middleware.GetRequestID(c))
+	}
+	h.recordLogin(c, user.ID, user.Email, nil)
+
+	refreshToken, err := h.issueRefreshToken(c, user.ID, jwtToken)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to issue refresh token", zap.Error(err))
+		return models.SendInternalError(c, "Failed to authenticate user", middleware.GetRequestID(c))
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "token",
+		Value:    jwtToken,
+		Path:     "/",
+		MaxAge:   int(h.authService.GetJWTExpiry().Seconds()),
+		HTTPOnly: true,
+		Secure:   h.cookieSecure,
+		SameSite: "Strict",
+	})
+
+	middleware.GetRequestLogger(c).Info("user logged in via magic link",
+		zap.Int64("user_id", user.ID),
+		zap.String("email", user.Email),
+	)
+
+	var resp models.LoginResponse
+	resp.Message = "Login successful"
+	resp.User.ID = user.PublicID.String()
+	resp.User.Name = user.Name
+	resp.User.Email = user.Email
+	resp.User.Role = user.Role
+	resp.RefreshToken = refreshToken
+	return c.JSON(resp)
+}
+
+// Refresh trades a refresh token, from the body or the refresh_token cookie,
+// for a new JWT and a new refresh token. The old one stops working; using it
+// again logs out every session that descends from the same login.
+func (h *AuthHandler) Refresh(c *fiber.Ctx) error {
+	var req models.RefreshRequest
+	if len(c.Body()) > 0 {
+		if err := parseBody(c, &req); err != nil {
+			return sendBodyError(c, err)
+		}
+	}
+	token := req.RefreshToken
+	if token == "" {
+		token = c.Cookies(refreshTokenCookie)
+	}
+	if token == "" {
+		return models.SendBadRequest(c, "Missing refresh token", middleware.GetRequestID(c))
+	}
+
+	user, jwtToken, refreshToken, err := h.refresh.Refresh(c.UserContext(), token)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrRefreshTokenInvalid):
+			return models.SendError(c, fiber.StatusUnauthorized, "Refresh token is invalid", models.ErrCodeInvalidToken, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrRefreshTokenExpired):
+			return models.SendError(c, fiber.StatusUnauthorized, "Refresh token has expired", models.ErrCodeExpiredToken, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrRefreshTokenReused):
+			middleware.GetRequestLogger(c).Warn("refresh token reused, session revoked")
+			h.clearAuthCookies(c)
+			return models.SendError(c, fiber.StatusUnauthorized, "Refresh token has already been used", models.ErrCodeInvalidToken, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrAccountDisabled):
+			return models.SendError(c, fiber.StatusForbidden, "Account is disabled", models.ErrCodeAccountDisabled, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrInteractiveLoginDenied):
+			return models.SendError(c, fiber.StatusForbidden, "Service accounts must authenticate with an API key", models.ErrCodeServiceAccount, middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to refresh token", zap.Error(err))
+		return models.SendInternalError(c, "Failed to refresh token", middleware.GetRequestID(c))
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "token",
+		Value:    jwtToken,
+		Path:     "/",
+		MaxAge:   int(h.authService.GetJWTExpiry().Seconds()),
+		HTTPOnly: true,
+		Secure:   h.cookieSecure,
+		SameSite: "Strict",
 	})
+	h.setRefreshCookie(c, refreshToken)
+
+	var resp models.LoginResponse
+	resp.Message = "Token refreshed"
+	resp.User.ID = user.PublicID.String()
+	resp.User.Name = user.Name
+	resp.User.Email = user.Email
+	resp.User.Role = user.Role
+	resp.RefreshToken = refreshToken
+	return c.JSON(resp)
+}
+
+// Logout revokes the refresh token, from the body or the refresh_token
+// cookie, along with the rest of its login's tokens, and the JWT the
+// request was made with, then clears the auth cookies. It succeeds without
+// any token, which only clears the cookies.
+func (h *AuthHandler) Logout(c *fiber.Ctx) error {
+	var req models.RefreshRequest
+	if len(c.Body()) > 0 {
+		if err := parseBody(c, &req); err != nil {
+			return sendBodyError(c, err)
+		}
+	}
+	token := req.RefreshToken
+	if token == "" {
+		token = c.Cookies(refreshTokenCookie)
+	}
+
+	if token != "" && h.refresh != nil {
+		if err := h.refresh.Revoke(c.UserContext(), token); err != nil {
+			middleware.GetRequestLogger(c).Error("failed to revoke refresh token", zap.Error(err))
+			return models.SendInternalError(c, "Failed to log out", middleware.GetRequestID(c))
+		}
+	}
+	if claims := h.accessTokenClaims(c); claims != nil && h.revocations != nil {
+		if err := h.revocations.RevokeToken(c.UserContext(), claims); err != nil {
+			middleware.GetRequestLogger(c).Error("failed to revoke access token", zap.Error(err))
+			return models.SendInternalError(c, "Failed to log out", middleware.GetRequestID(c))
+		}
+		if claims.SessionID != "" && h.sessions != nil {
+			err := h.sessions.Revoke(c.UserContext(), claims.UserID, claims.SessionID)
+			if err != nil && !errors.Is(err, service.ErrSessionNotFound) {
+				middleware.GetRequestLogger(c).Error("failed to end session", zap.Error(err))
+				return models.SendInternalError(c, "Failed to log out", middleware.GetRequestID(c))
+			}
+		}
+	}
+	h.clearAuthCookies(c)
+
+	return c.JSON(fiber.Map{
+		"message": "Logged out",
+	})
+}
+
+// ListSessions returns the caller's live sessions, oldest first.
+func (h *AuthHandler) ListSessions(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	if authUser == nil {
+		return models.SendUnauthorized(c, "Unauthorized", middleware.GetRequestID(c))
+	}
+
+	rows, err := h.sessions.List(c.UserContext(), authUser.ID)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to list sessions", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve sessions", middleware.GetRequestID(c))
+	}
+
+	var current string
+	if claims := middleware.GetJWTClaims(c); claims != nil {
+		current = claims.SessionID
+	}
+	sessions := make([]models.SessionResponse, 0, len(rows))
+	for _, row := range rows {
+		sessions = append(sessions, models.SessionResponse{
+			ID:        row.ID,
+			IPAddress: row.IpAddress,
+			UserAgent: row.UserAgent,
+			CreatedAt: row.CreatedAt.Time,
+			ExpiresAt: row.ExpiresAt.Time,
+			Current:   row.ID == current,
+		})
+	}
+	return c.JSON(models.SessionListResponse{Total: len(sessions), Sessions: sessions})
+}
+
+// RevokeSession signs the caller out of one of their sessions, which may be
+// the current one.
+func (h *AuthHandler) RevokeSession(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	if authUser == nil {
+		return models.SendUnauthorized(c, "Unauthorized", middleware.GetRequestID(c))
+	}
+
+	id := c.Params("id")
+	if err := h.sessions.Revoke(c.UserContext(), authUser.ID, id); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			return models.SendNotFound(c, "Session not found", middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to revoke session", zap.Error(err))
+		return models.SendInternalError(c, "Failed to revoke session", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("session revoked",
+		zap.Int64("user_id", authUser.ID),
+		zap.String("session_id", id),
+	)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// accessTokenClaims returns the claims of the JWT the request carries in
+// its Authorization header or token cookie. Tokens that are invalid or
+// already expired give nil: there is nothing left to revoke.
+func (h *AuthHandler) accessTokenClaims(c *fiber.Ctx) *service.JWTClaims {
+	token, err := middleware.BearerToken(c.Get(fiber.HeaderAuthorization))
+	if err != nil {
+		token = c.Cookies("token")
+	}
+	if token == "" {
+		return nil
+	}
+	claims, err := h.authService.ParseJWT(token)
+	if err != nil {
+		return nil
+	}
+	return claims
+}
+
+// clientContext describes the client logging in to the services, for the
+// lockout and the session it starts.
+func clientContext(c *fiber.Ctx) context.Context {
+	ctx := service.WithClientIP(c.UserContext(), c.IP())
+	return service.WithUserAgent(ctx, c.Get(fiber.HeaderUserAgent))
+}
+
+// issueRefreshToken starts a refresh token family for a user who has just
+// logged in with jwt and sets its cookie. The family is jwt's session. It
+// returns "" when refresh tokens are disabled.
+func (h *AuthHandler) issueRefreshToken(c *fiber.Ctx, userID int64, jwt string) (string, error) {
+	if h.refresh == nil {
+		return "", nil
+	}
+	var sessionID string
+	if claims, err := h.authService.ParseJWT(jwt); err == nil {
+		sessionID = claims.SessionID
+	}
+	token, err := h.refresh.Issue(c.UserContext(), userID, sessionID)
+	if err != nil {
+		return "", err
+	}
+	h.setRefreshCookie(c, token)
+	return token, nil
+}
+
+func (h *AuthHandler) setRefreshCookie(c *fiber.Ctx, token string) {
+	c.Cookie(&fiber.Cookie{
+		Name:     refreshTokenCookie,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   int(h.refresh.TTL().Seconds()),
+		HTTPOnly: true,
+		Secure:   h.cookieSecure,
+		SameSite: "Strict",
+	})
+}
+
+func (h *AuthHandler) clearAuthCookies(c *fiber.Ctx) {
+	for _, name := range []string{"token", refreshTokenCookie} {
+		c.Cookie(&fiber.Cookie{
+			Name:     name,
+			Path:     "/",
+			MaxAge:   -1,
+			HTTPOnly: true,
+			Secure:   h.cookieSecure,
+			SameSite: "Strict",
+		})
+	}
+}
+
+// ResetPassword sets a new password using the token from a reset link.
+func (h *AuthHandler) ResetPassword(c *fiber.Ctx) error {
+	var req models.PasswordResetRequest
+
+	if err := parseBody(c, &req); err != nil {
+		return sendBodyError(c, err)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+	if err := h.authService.ValidatePasswordStrength(req.Password); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	userID, err := h.resets.Reset(c.UserContext(), req.Token, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrPasswordResetInvalid):
+			return models.SendError(c, fiber.StatusUnauthorized, "Reset link is invalid or has already been used", models.ErrCodeInvalidToken, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrPasswordResetExpired):
+			return models.SendError(c, fiber.StatusGone, "Reset link has expired", models.ErrCodeURLExpired, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrAccountDisabled):
+			return models.SendError(c, fiber.StatusForbidden, "Account is disabled", models.ErrCodeAccountDisabled, middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to reset password", zap.Error(err))
+		return models.SendInternalError(c, "Failed to reset password", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("password reset", zap.Int64("user_id", userID))
+	return c.JSON(fiber.Map{
+		"message": "Password has been reset",
+	})
+}
+
+// ChangePassword sets a new password for the caller after checking their
+// current one, then logs out all of their sessions, this one included.
+func (h *AuthHandler) ChangePassword(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	var req models.ChangePasswordRequest
+
+	if err := parseBody(c, &req); err != nil {
+		return sendBodyError(c, err)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	err := h.authService.ChangePassword(c.UserContext(), authUser.ID, req.CurrentPassword, req.NewPassword)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrWrongPassword):
+			middleware.GetRequestLogger(c).Warn("password change with wrong current password", zap.Int64("user_id", authUser.ID))
+			return models.SendError(c, fiber.StatusUnauthorized, "Current password is incorrect", models.ErrCodeInvalidCredentials, middleware.GetRequestID(c))
+		case service.IsWeakPassword(err):
+			return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrInteractiveLoginDenied):
+			return models.SendError(c, fiber.StatusForbidden, "Service accounts have no password to change", models.ErrCodeServiceAccount, middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to change password", zap.Error(err))
+		return models.SendInternalError(c, "Failed to change password", middleware.GetRequestID(c))
+	}
+
+	if h.revocations != nil {
+		if err := h.revocations.RevokeUser(c.UserContext(), authUser.ID); err != nil {
+			middleware.GetRequestLogger(c).Error("failed to revoke user tokens", zap.Error(err))
+			return models.SendInternalError(c, "Password changed, but failed to log out other sessions", middleware.GetRequestID(c))
+		}
+	}
+	h.clearAuthCookies(c)
+
+	middleware.GetRequestLogger(c).Info("password changed", zap.Int64("user_id", authUser.ID))
+	return c.JSON(fiber.Map{
+		"message": "Password has been changed, please log in again",
+	})
+}
+
+func (h *AuthHandler) recordLogin(c *fiber.Ctx, userID int64, email string, loginErr error) {
+	if h.detector != nil && (loginErr == nil || loginErr == service.ErrInvalidCredentials) {
+		h.detector.Observe(c.IP(), email, loginErr == nil)
+	}
+	if h.loginHistory == nil {
+		return
+	}
+
+	attempt := repository.LoginAttempt{
+		Email:     email,
+		Succeeded: loginErr == nil,
+		IPAddress: c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	}
+	if userID != 0 {
+		attempt.UserID = &userID
+	}
+	switch loginErr {
+	case nil:
+	case service.ErrInvalidCredentials:
+		attempt.Reason = "invalid_credentials"
+	case service.ErrAccountDisabled:
+		attempt.Reason = "account_disabled"
+	case service.ErrInteractiveLoginDenied:
+		attempt.Reason = "service_account"
+	default:
+		attempt.Reason = "error"
+		switch {
+		case errors.Is(loginErr, service.ErrAccountLocked):
+			attempt.Reason = "account_locked"
+		case errors.Is(loginErr, service.ErrLoginChallenged):
+			attempt.Reason = "challenged"
+		}
+	}
+	if h.locator != nil {
+		if loc, ok := h.locator.Lookup(attempt.IPAddress); ok {
+			attempt.Country, attempt.City = loc.Country, loc.City
+		}
+	}
+
+	if err := h.loginHistory.Record(c.UserContext(), attempt); err != nil {
+		middleware.GetRequestLogger(c).Warn("failed to record login attempt", zap.Error(err))
+	}
 }
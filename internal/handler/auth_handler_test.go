@@ -6,15 +6,20 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
 	"go.uber.org/zap"
 
 	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/middleware"
 	"BACKEND/internal/models"
+	"BACKEND/internal/repository"
 	"BACKEND/internal/service"
 )
 
@@ -24,6 +29,7 @@ type mockAuthService struct {
 	loginFunc                    func(ctx context.Context, email, password string) (generated.User, string, error)
 	getJWTExpiryFunc             func() time.Duration
 	setJWTConfigFunc             func(secret string, expiry time.Duration)
+	changePasswordFunc           func(ctx context.Context, userID int64, currentPassword, newPassword string) error
 }
 
 func (m *mockAuthService) ValidatePasswordStrength(password string) error {
@@ -37,6 +43,9 @@ func (m *mockAuthService) CreateUser(ctx context.Context, name, email, password,
 	if m.createUserFunc != nil {
 		return m.createUserFunc(ctx, name, email, password, dobStr, role)
 	}
+	if err := m.ValidatePasswordStrength(password); err != nil {
+		return generated.CreateUserRow{}, err
+	}
 	dob, _ := time.Parse("2006-01-02", dobStr)
 	return generated.CreateUserRow{
 		ID:   1,
@@ -78,13 +87,24 @@ func (m *mockAuthService) SetJWTConfig(secret string, expiry time.Duration) {
 	}
 }
 
+func (m *mockAuthService) ParseJWT(tokenString string) (*service.JWTClaims, error) {
+	return nil, jwt.ErrTokenMalformed
+}
+
+func (m *mockAuthService) ChangePassword(ctx context.Context, userID int64, currentPassword, newPassword string) error {
+	if m.changePasswordFunc != nil {
+		return m.changePasswordFunc(ctx, userID, currentPassword, newPassword)
+	}
+	return service.ErrWrongPassword
+}
+
 func TestSignup_Success(t *testing.T) {
 	app := fiber.New()
 	logger, _ := zap.NewDevelopment()
 
 	mockSvc := &mockAuthService{
 		validatePasswordStrengthFunc: func(password string) error {
-			return nil 
+			return nil
 		},
 		createUserFunc: func(ctx context.Context, name, email, password, dobStr, role string) (generated.CreateUserRow, error) {
 			dob, _ := time.Parse("2006-01-02", dobStr)
@@ -167,7 +187,7 @@ func TestSignup_WeakPassword(t *testing.T) {
 	logger, _ := zap.NewDevelopment()
 	mockSvc := &mockAuthService{
 		validatePasswordStrengthFunc: func(password string) error {
-			return service.ErrPasswordTooShort 
+			return service.ErrPasswordTooShort
 		},
 	}
 	handler := NewAuthHandler(mockSvc, logger, false)
@@ -293,7 +313,7 @@ func TestSignup_InvalidDateFormat(t *testing.T) {
 		Name:     "John Doe",
 		Email:    "john@example.com",
 		Password: "SecurePass123!",
-		Dob:      "01/01/1990", 
+		Dob:      "01/01/1990",
 	}
 
 	body, _ := json.Marshal(reqBody)
@@ -331,6 +351,52 @@ func TestSignup_InvalidJSON(t *testing.T) {
 	}
 }
 
+func TestSignup_MalformedJSONDiagnostics(t *testing.T) {
+	app := fiber.New()
+	logger, _ := zap.NewDevelopment()
+	mockSvc := &mockAuthService{}
+	handler := NewAuthHandler(mockSvc, logger, false)
+
+	app.Post("/auth/signup", handler.Signup)
+
+	body := "{\n  \"name\": \"John\",\n  \"email\": \"john@example.com\",\n}"
+	req := httptest.NewRequest(http.MethodPost, "/auth/signup", bytes.NewReader([]byte(body)))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+
+	if resp.StatusCode != fiber.StatusBadRequest {
+		t.Fatalf("Expected status 400 for malformed JSON, got %d", resp.StatusCode)
+	}
+
+	var errResp struct {
+		Error struct {
+			Code    string `json:"code"`
+			Details struct {
+				Line   int    `json:"line"`
+				Column int    `json:"column"`
+				Hint   string `json:"hint"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&errResp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	if errResp.Error.Code != models.ErrCodeInvalidFormat {
+		t.Errorf("Expected code %s, got %s", models.ErrCodeInvalidFormat, errResp.Error.Code)
+	}
+	if errResp.Error.Details.Line != 4 || errResp.Error.Details.Column != 1 {
+		t.Errorf("Expected error at line 4, column 1, got line %d, column %d", errResp.Error.Details.Line, errResp.Error.Details.Column)
+	}
+	if errResp.Error.Details.Hint == "" {
+		t.Error("Expected a hint about the trailing comma")
+	}
+}
+
 func TestPasswordStrengthValidation(t *testing.T) {
 	authService := &service.AuthService{}
 
@@ -359,3 +425,113 @@ func TestPasswordStrengthValidation(t *testing.T) {
 		})
 	}
 }
+
+// uniqueEmailStore enforces a unique email the way the users_email_key
+// index does, and holds every insert until all expected signups have
+// arrived so they really race.
+type uniqueEmailStore struct {
+	repository.UserStore
+	mu      sync.Mutex
+	emails  map[string]bool
+	arrived sync.WaitGroup
+}
+
+func (s *uniqueEmailStore) CreateWithAuth(ctx context.Context, name, email, passwordHash, role, source string, dob time.Time) (generated.CreateUserRow, error) {
+	s.arrived.Done()
+	s.arrived.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.emails[email] {
+		return generated.CreateUserRow{}, &repository.ConstraintError{
+			Kind:       repository.ErrUniqueViolation,
+			Constraint: "users_email_key",
+			Err:        &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key", Message: "duplicate key value violates unique constraint \"users_email_key\""},
+		}
+	}
+	s.emails[email] = true
+	return generated.CreateUserRow{ID: int64(len(s.emails)), Name: name, Email: email, Role: role}, nil
+}
+
+func TestSignup_ConcurrentDuplicateEmail(t *testing.T) {
+	const signups = 2
+	store := &uniqueEmailStore{emails: map[string]bool{}}
+	store.arrived.Add(signups)
+
+	app := fiber.New()
+	app.Post("/auth/signup", NewAuthHandler(service.NewAuthService(store), zap.NewNop(), false).Signup)
+
+	body, _ := json.Marshal(models.SignupRequest{
+		Name:     "Jane Doe",
+		Email:    "jane@example.com",
+		Password: "SecurePass123!",
+		Dob:      "1990-01-01",
+	})
+
+	statuses := make(chan int, signups)
+	var wg sync.WaitGroup
+	for i := 0; i < signups; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/auth/signup", bytes.NewReader(body))
+			req.Header.Set("Content-Type", "application/json")
+			resp, err := app.Test(req, 10000)
+			if err != nil {
+				t.Errorf("Failed to send request: %v", err)
+				return
+			}
+			statuses <- resp.StatusCode
+		}()
+	}
+	wg.Wait()
+	close(statuses)
+
+	counts := map[int]int{}
+	for status := range statuses {
+		counts[status]++
+	}
+	if counts[fiber.StatusCreated] != 1 || counts[fiber.StatusConflict] != 1 {
+		t.Errorf("statuses = %v; want exactly one 201 and one 409", counts)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	mockSvc := &mockAuthService{
+		changePasswordFunc: func(ctx context.Context, userID int64, currentPassword, newPassword string) error {
+			if currentPassword != "OldPass123!" {
+				return service.ErrWrongPassword
+			}
+			if newPassword == "short" {
+				return service.ErrPasswordTooShort
+			}
+			return nil
+		},
+	}
+	app := fiber.New()
+	app.Put("/users/me/password", func(c *fiber.Ctx) error {
+		c.Locals(middleware.AuthUserKey, models.AuthUser{ID: 1, Role: "user", AccountType: "human"})
+		return c.Next()
+	}, NewAuthHandler(mockSvc, zap.NewNop(), false).ChangePassword)
+
+	tests := []struct {
+		current, next string
+		want          int
+	}{
+		{"WrongPass123!", "NewPass123!", fiber.StatusUnauthorized},
+		{"OldPass123!", "short", fiber.StatusBadRequest},
+		{"OldPass123!", "NewPass123!", fiber.StatusOK},
+	}
+	for _, tt := range tests {
+		body, _ := json.Marshal(models.ChangePasswordRequest{CurrentPassword: tt.current, NewPassword: tt.next})
+		req := httptest.NewRequest(http.MethodPut, "/users/me/password", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != tt.want {
+			t.Errorf("current %q, new %q: status %d, want %d", tt.current, tt.next, resp.StatusCode, tt.want)
+		}
+	}
+}
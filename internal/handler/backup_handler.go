@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+)
+
+type BackupHandler struct {
+	backupService *service.BackupService
+	logger        *zap.Logger
+}
+
+func NewBackupHandler(backupService *service.BackupService, logger *zap.Logger) *BackupHandler {
+	return &BackupHandler{
+		backupService: backupService,
+		logger:        logger,
+	}
+}
+
+// Create starts a backup of the user tables. It runs in the background;
+// List shows when it is done.
+func (h *BackupHandler) Create(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+
+	b, err := h.backupService.Start(c.UserContext(), authUser.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrBackupRunning) {
+			return models.SendConflict(c, "A backup is already running", middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to start backup", zap.Error(err))
+		return models.SendInternalError(c, "Failed to start backup", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("admin started backup",
+		zap.Int64("admin_id", authUser.ID),
+		zap.String("backup", b.Name),
+	)
+
+	return c.Status(fiber.StatusAccepted).JSON(b)
+}
+
+func (h *BackupHandler) List(c *fiber.Ctx) error {
+	backups, err := h.backupService.List(c.UserContext())
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to list backups", zap.Error(err))
+		return models.SendInternalError(c, "Failed to list backups", middleware.GetRequestID(c))
+	}
+
+	return c.JSON(fiber.Map{
+		"total":   len(backups),
+		"backups": backups,
+	})
+}
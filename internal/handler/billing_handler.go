@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/billing"
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+)
+
+type BillingHandler struct {
+	billingService *service.BillingService
+	logger         *zap.Logger
+}
+
+func NewBillingHandler(billingService *service.BillingService, logger *zap.Logger) *BillingHandler {
+	return &BillingHandler{
+		billingService: billingService,
+		logger:         logger,
+	}
+}
+
+// Webhook receives subscription events from the billing provider. Events
+// that can never be applied are acknowledged anyway, so the provider
+// doesn't keep retrying them; only failures worth a retry get a 5xx.
+func (h *BillingHandler) Webhook(c *fiber.Ctx) error {
+	header := func(key string) string { return c.Get(key) }
+	evt, err := h.billingService.HandleWebhook(c.UserContext(), c.Body(), header)
+	logger := middleware.GetRequestLogger(c).With(
+		zap.String("event_id", evt.ID),
+		zap.String("event_type", evt.Type),
+	)
+
+	switch {
+	case err == nil:
+		logger.Info("subscription updated",
+			zap.String("customer_id", evt.Subscription.CustomerID),
+			zap.String("status", string(evt.Subscription.Status)),
+			zap.Int64("seats", evt.Subscription.Seats),
+		)
+	case errors.Is(err, service.ErrBillingDisabled):
+		return models.SendNotFound(c, "Billing is not configured", middleware.GetRequestID(c))
+	case errors.Is(err, billing.ErrInvalidSignature):
+		logger.Warn("billing webhook rejected", zap.Error(err))
+		return models.SendBadRequest(c, "Invalid signature", middleware.GetRequestID(c))
+	case errors.Is(err, billing.ErrMalformedEvent):
+		logger.Warn("billing webhook rejected", zap.Error(err))
+		return models.SendBadRequest(c, "Malformed event", middleware.GetRequestID(c))
+	case errors.Is(err, billing.ErrIgnoredEvent):
+		logger.Debug("billing event ignored")
+	case errors.Is(err, service.ErrBillingOrgUnknown), errors.Is(err, service.ErrBillingCustomerInUse):
+		logger.Warn("billing event not applied",
+			zap.String("customer_id", evt.Subscription.CustomerID),
+			zap.Error(err),
+		)
+	default:
+		logger.Error("failed to handle billing webhook", zap.Error(err))
+		return models.SendInternalError(c, "Failed to handle event", middleware.GetRequestID(c))
+	}
+
+	return c.JSON(fiber.Map{"received": true})
+}
+
+// Status returns the organization's subscription and the seats it uses.
+func (h *BillingHandler) Status(c *fiber.Ctx) error {
+	orgID, ok := idParam(c, "id")
+	if !ok {
+		return models.SendBadRequest(c, "Invalid organization ID", middleware.GetRequestID(c))
+	}
+
+	status, err := h.billingService.Status(c.UserContext(), orgID)
+	if err != nil {
+		if errors.Is(err, service.ErrOrganizationNotFound) {
+			return models.SendNotFound(c, "Organization not found", middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to get billing status", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve billing status", middleware.GetRequestID(c))
+	}
+
+	resp := models.BillingResponse{
+		Organization: organizationResponse(status.Organization),
+		Status:       "none",
+		SeatsUsed:    status.SeatsUsed,
+	}
+	if sub := status.Subscription; sub != nil {
+		resp.Provider = sub.Provider
+		resp.Status = sub.Status
+		resp.Plan = sub.Plan
+		resp.Seats = sub.Seats
+		resp.CustomerID = sub.CustomerID
+		resp.SubscriptionID = sub.SubscriptionID
+		resp.UpdatedAt = &sub.UpdatedAt.Time
+		if sub.CurrentPeriodEnd.Valid {
+			resp.CurrentPeriodEnd = &sub.CurrentPeriodEnd.Time
+		}
+	}
+	return c.JSON(resp)
+}
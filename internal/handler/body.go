@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/jsonbody"
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+)
+
+// parseBody is c.BodyParser, except that JSON bodies go through jsonbody so
+// a malformed body can be reported precisely by sendBodyError, and unknown
+// fields are rejected in strict mode.
+func parseBody(c *fiber.Ctx, out interface{}) error {
+	ctype := strings.ToLower(c.Get(fiber.HeaderContentType))
+	ctype, _, _ = strings.Cut(ctype, ";")
+	if !strings.HasSuffix(strings.TrimSpace(ctype), "json") {
+		return c.BodyParser(out)
+	}
+	if middleware.IsStrictJSON(c) {
+		return jsonbody.DecodeStrict(c.Body(), out)
+	}
+	return jsonbody.Decode(c.Body(), out)
+}
+
+func sendBodyError(c *fiber.Ctx, err error) error {
+	var bodyErr *jsonbody.Error
+	if errors.As(err, &bodyErr) {
+		return models.SendErrorWithDetails(c, fiber.StatusBadRequest, "Malformed JSON: "+bodyErr.Message, models.ErrCodeInvalidFormat, middleware.GetRequestID(c), bodyErr)
+	}
+	var unknownErr *jsonbody.UnknownFieldsError
+	if errors.As(err, &unknownErr) {
+		return models.SendErrorWithDetails(c, fiber.StatusBadRequest, "Unknown fields: "+strings.Join(unknownErr.Fields, ", "), models.ErrCodeUnknownFields, middleware.GetRequestID(c), unknownErr)
+	}
+	return models.SendBadRequest(c, "Invalid request body", middleware.GetRequestID(c))
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/config"
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/policy"
+)
+
+// ConfigHandler exports the admin-managed configuration and previews
+// importing a document exported elsewhere. Configuration is read from the
+// environment at startup, so an import reports the environment changes to
+// make rather than applying them.
+type ConfigHandler struct {
+	cfg      *config.Config
+	policies *policy.Engine
+	logger   *zap.Logger
+}
+
+func NewConfigHandler(cfg *config.Config, policies *policy.Engine, logger *zap.Logger) *ConfigHandler {
+	return &ConfigHandler{
+		cfg:      cfg,
+		policies: policies,
+		logger:   logger,
+	}
+}
+
+func (h *ConfigHandler) document() config.Document {
+	var permissions []config.Permission
+	for _, rule := range h.policies.Rules() {
+		permissions = append(permissions, config.Permission{Name: rule.Name, Actions: rule.Actions})
+	}
+	return h.cfg.Document(permissions)
+}
+
+func (h *ConfigHandler) Export(c *fiber.Ctx) error {
+	return c.JSON(h.document())
+}
+
+// Import previews an import: it validates the posted document and lists
+// how it differs from the running configuration, with the environment
+// variable behind each change. It changes nothing.
+func (h *ConfigHandler) Import(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+
+	var doc config.Document
+	if err := parseBody(c, &doc); err != nil {
+		return sendBodyError(c, err)
+	}
+	if problems := doc.Validate(); len(problems) > 0 {
+		return models.SendErrorWithDetails(c, fiber.StatusBadRequest, "Invalid configuration document", models.ErrCodeValidationFailed, middleware.GetRequestID(c), problems)
+	}
+
+	changes := config.Diff(h.document(), doc)
+	env := make(map[string]string)
+	var unsupported []config.Change
+	for _, change := range changes {
+		if change.Env == "" {
+			unsupported = append(unsupported, change)
+			continue
+		}
+		env[change.Env] = change.To
+	}
+
+	middleware.GetRequestLogger(c).Info("admin previewed configuration import",
+		zap.Int64("admin_id", authUser.ID),
+		zap.Int("changes", len(changes)),
+	)
+	return c.JSON(fiber.Map{
+		"valid":       true,
+		"changes":     changes,
+		"env":         env,
+		"unsupported": unsupported,
+		"message":     "Set the listed environment variables and restart to apply the changes",
+	})
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/repository"
+)
+
+// isConstraintError reports whether err is a write the database rejected
+// for breaking a constraint, which sendConstraintError answers.
+func isConstraintError(err error) bool {
+	var constraintErr *repository.ConstraintError
+	return errors.As(err, &constraintErr)
+}
+
+// sendConstraintError answers a constraint violation: 409 for a duplicate,
+// or for a reference to a row that doesn't exist (usually deleted while
+// the request ran), and 400 for a value a check constraint refused. The
+// constraint is logged but not named to the client.
+func sendConstraintError(c *fiber.Ctx, err error) error {
+	var constraintErr *repository.ConstraintError
+	errors.As(err, &constraintErr)
+	middleware.GetRequestLogger(c).Warn("write rejected by constraint",
+		zap.String("constraint", constraintErr.Constraint),
+		zap.Error(err),
+	)
+
+	switch {
+	case errors.Is(err, repository.ErrUniqueViolation):
+		return models.SendConflict(c, "Resource already exists", middleware.GetRequestID(c))
+	case errors.Is(err, repository.ErrForeignKeyViolation):
+		return models.SendError(c, fiber.StatusConflict, "A referenced resource no longer exists", models.ErrCodeStaleReference, middleware.GetRequestID(c))
+	default:
+		return models.SendError(c, fiber.StatusBadRequest, "Invalid value", models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/repository"
+)
+
+func TestSendConstraintError(t *testing.T) {
+	tests := []struct {
+		name       string
+		kind       error
+		wantStatus int
+	}{
+		{"duplicate", repository.ErrUniqueViolation, fiber.StatusConflict},
+		{"missing reference", repository.ErrForeignKeyViolation, fiber.StatusConflict},
+		{"check", repository.ErrCheckViolation, fiber.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := &repository.ConstraintError{Kind: tt.kind, Constraint: "c", Err: errors.New("driver error")}
+			if !isConstraintError(err) {
+				t.Fatal("isConstraintError() = false")
+			}
+
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error { return sendConstraintError(c, err) })
+			resp, reqErr := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			if reqErr != nil {
+				t.Fatal(reqErr)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d; want %d", resp.StatusCode, tt.wantStatus)
+			}
+		})
+	}
+
+	if isConstraintError(errors.New("connection reset")) {
+		t.Error("isConstraintError() = true for a plain error")
+	}
+}
@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+)
+
+type DeviceHandler struct {
+	deviceService *service.DeviceService
+	validate      *validator.Validate
+	logger        *zap.Logger
+	jwtExpiry     int
+}
+
+func NewDeviceHandler(deviceService *service.DeviceService, logger *zap.Logger, jwtExpirySeconds int) *DeviceHandler {
+	return &DeviceHandler{
+		deviceService: deviceService,
+		validate:      validator.New(),
+		logger:        logger,
+		jwtExpiry:     jwtExpirySeconds,
+	}
+}
+
+// Code starts the device flow. The CLI shows the user code and
+// verification URI, then polls Token.
+func (h *DeviceHandler) Code(c *fiber.Ctx) error {
+	code, err := h.deviceService.Start()
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to start device authorization", zap.Error(err))
+		return models.SendInternalError(c, "Failed to start device authorization", middleware.GetRequestID(c))
+	}
+
+	return c.JSON(code)
+}
+
+func (h *DeviceHandler) Token(c *fiber.Ctx) error {
+	var req models.DeviceTokenRequest
+
+	if err := parseBody(c, &req); err != nil {
+		return sendOAuthError(c, "invalid_request", err.Error())
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return sendOAuthError(c, "invalid_request", "grant_type and device_code are required")
+	}
+	if req.GrantType != models.DeviceGrantType {
+		return sendOAuthError(c, "unsupported_grant_type", "")
+	}
+
+	token, err := h.deviceService.Token(clientContext(c), req.DeviceCode)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDeviceAuthorizationPending),
+			errors.Is(err, service.ErrDeviceSlowDown),
+			errors.Is(err, service.ErrDeviceAccessDenied),
+			errors.Is(err, service.ErrDeviceExpiredToken),
+			errors.Is(err, service.ErrDeviceInvalidCode):
+			return sendOAuthError(c, err.Error(), "")
+		case errors.Is(err, service.ErrAccountDisabled):
+			return sendOAuthError(c, "access_denied", "Account is disabled")
+		case errors.Is(err, service.ErrSessionLimitReached):
+			return sendOAuthError(c, "access_denied", "Too many active sessions")
+		}
+		middleware.GetRequestLogger(c).Error("failed to issue device token", zap.Error(err))
+		return models.SendInternalError(c, "Failed to authenticate user", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("device authorized")
+
+	return c.JSON(models.DeviceTokenResponse{
+		AccessToken: token,
+		TokenType:   "Bearer",
+		ExpiresIn:   h.jwtExpiry,
+	})
+}
+
+func (h *DeviceHandler) Approve(c *fiber.Ctx) error {
+	return h.decide(c, true)
+}
+
+func (h *DeviceHandler) Deny(c *fiber.Ctx) error {
+	return h.decide(c, false)
+}
+
+func (h *DeviceHandler) decide(c *fiber.Ctx, approve bool) error {
+	authUser := middleware.GetAuthUser(c)
+
+	var req models.DeviceApproveRequest
+	if err := parseBody(c, &req); err != nil {
+		return sendBodyError(c, err)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	if err := h.deviceService.Approve(req.UserCode, authUser.ID, approve); err != nil {
+		if errors.Is(err, service.ErrDeviceInvalidCode) || errors.Is(err, service.ErrDeviceExpiredToken) {
+			return models.SendError(c, fiber.StatusBadRequest, "Invalid or expired code", models.ErrCodeInvalidInput, middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to record device decision", zap.Error(err))
+		return models.SendInternalError(c, "Failed to record decision", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("device authorization decided",
+		zap.Int64("user_id", authUser.ID),
+		zap.Bool("approved", approve),
+	)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func sendOAuthError(c *fiber.Ctx, code, description string) error {
+	return c.Status(fiber.StatusBadRequest).JSON(models.OAuthErrorResponse{
+		Error:            code,
+		ErrorDescription: description,
+	})
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+)
+
+// deadLetterQuery holds the parameters of a dead letter listing.
+type deadLetterQuery struct {
+	Limit int32 `query:"limit" default:"50" min:"1" max:"200"`
+}
+
+type EmailDeliveryHandler struct {
+	delivery *service.MailDelivery
+	logger   *zap.Logger
+}
+
+func NewEmailDeliveryHandler(delivery *service.MailDelivery, logger *zap.Logger) *EmailDeliveryHandler {
+	return &EmailDeliveryHandler{
+		delivery: delivery,
+		logger:   logger,
+	}
+}
+
+// ListDeadLetters lists emails that could not be delivered, newest first.
+func (h *EmailDeliveryHandler) ListDeadLetters(c *fiber.Ctx) error {
+	var q deadLetterQuery
+	if err := parseQuery(c, &q); err != nil {
+		return sendQueryError(c, err)
+	}
+
+	rows, err := h.delivery.ListDeadLetters(c.UserContext(), q.Limit)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to list dead letters", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve dead letters", middleware.GetRequestID(c))
+	}
+
+	letters := make([]models.EmailDeadLetterResponse, 0, len(rows))
+	for _, row := range rows {
+		letters = append(letters, models.EmailDeadLetterResponse{
+			ID:            row.ID,
+			Recipients:    strings.Split(row.Recipients, ","),
+			Subject:       row.Subject,
+			Error:         row.Error,
+			Attempts:      row.Attempts,
+			CreatedAt:     row.CreatedAt.Time,
+			LastAttemptAt: row.LastAttemptAt.Time,
+		})
+	}
+	return c.JSON(models.EmailDeadLetterListResponse{Total: len(letters), DeadLetters: letters})
+}
+
+// RetryDeadLetter sends a dead letter again and removes it once it goes
+// through. When the relay still refuses it, the response is a 502 carrying
+// the relay's answer.
+func (h *EmailDeliveryHandler) RetryDeadLetter(c *fiber.Ctx) error {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return models.SendBadRequest(c, "Invalid dead letter ID", middleware.GetRequestID(c))
+	}
+
+	err := h.delivery.RetryDeadLetter(c.UserContext(), id)
+	switch {
+	case err == nil:
+		middleware.GetRequestLogger(c).Info("dead letter resent",
+			zap.Int64("dead_letter_id", id),
+			zap.Int64("admin_id", middleware.GetAuthUser(c).ID),
+		)
+		return c.SendStatus(fiber.StatusNoContent)
+	case errors.Is(err, service.ErrDeadLetterNotFound):
+		return models.SendNotFound(c, "Dead letter not found", middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrDeadLetterRetryFailed):
+		return models.SendError(c, fiber.StatusBadGateway, err.Error(), models.ErrCodeDeliveryFailed, middleware.GetRequestID(c))
+	}
+	middleware.GetRequestLogger(c).Error("failed to retry dead letter", zap.Int64("dead_letter_id", id), zap.Error(err))
+	return models.SendInternalError(c, "Failed to retry dead letter", middleware.GetRequestID(c))
+}
@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/templates"
+)
+
+type EmailTemplateHandler struct {
+	renderer *templates.Renderer
+	logger   *zap.Logger
+}
+
+func NewEmailTemplateHandler(renderer *templates.Renderer, logger *zap.Logger) *EmailTemplateHandler {
+	return &EmailTemplateHandler{
+		renderer: renderer,
+		logger:   logger,
+	}
+}
+
+func (h *EmailTemplateHandler) List(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"branding":  h.renderer.Branding(),
+		"templates": h.renderer.List(),
+	})
+}
+
+func (h *EmailTemplateHandler) Preview(c *fiber.Ctx) error {
+	name := c.Params("name")
+	locale := c.Query("locale")
+
+	email, err := h.renderer.Render(name, locale, templates.SampleData(name))
+	if err != nil {
+		if errors.Is(err, templates.ErrTemplateNotFound) {
+			return models.SendNotFound(c, "Email template not found", middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to render email preview", zap.String("template", name), zap.Error(err))
+		return models.SendInternalError(c, "Failed to render email template", middleware.GetRequestID(c))
+	}
+
+	if c.Query("format") == "json" {
+		return c.JSON(email)
+	}
+
+	c.Type("html")
+	return c.SendString(email.HTML)
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"errors"
+	"os"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+)
+
+type ExportHandler struct {
+	exportService *service.ExportService
+	baseURL       string
+	logger        *zap.Logger
+}
+
+// NewExportHandler takes the public base URL of the API, including any
+// mount prefix, to build download links from.
+func NewExportHandler(exportService *service.ExportService, baseURL string, logger *zap.Logger) *ExportHandler {
+	return &ExportHandler{
+		exportService: exportService,
+		baseURL:       baseURL,
+		logger:        logger,
+	}
+}
+
+func (h *ExportHandler) Create(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	name := c.Params("name")
+
+	export, err := h.exportService.Start(c.UserContext(), name, c.Queries(), authUser.ID)
+	if err != nil {
+		if errors.Is(err, service.ErrReportNotFound) {
+			return models.SendNotFound(c, "Report not found", middleware.GetRequestID(c))
+		}
+		if errors.Is(err, service.ErrInvalidReportParam) {
+			return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to start export", zap.String("report", name), zap.Error(err))
+		return models.SendInternalError(c, "Failed to start export", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("admin started export",
+		zap.Int64("admin_id", authUser.ID),
+		zap.String("export_id", export.ID),
+		zap.String("report", name),
+	)
+
+	return c.Status(fiber.StatusAccepted).JSON(export)
+}
+
+// Get returns the export's status and, once it has completed, a fresh
+// signed download URL.
+func (h *ExportHandler) Get(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	export, err := h.exportService.Get(id)
+	if err != nil {
+		return models.SendNotFound(c, "Export not found", middleware.GetRequestID(c))
+	}
+
+	if export.Status != service.ExportCompleted {
+		return c.JSON(fiber.Map{"export": export})
+	}
+
+	downloadURL, expiresAt, err := h.exportService.SignedURL(id, h.baseURL)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to sign download url", zap.String("export_id", id), zap.Error(err))
+		return models.SendInternalError(c, "Failed to create download URL", middleware.GetRequestID(c))
+	}
+
+	return c.JSON(fiber.Map{
+		"export":                  export,
+		"download_url":            downloadURL,
+		"download_url_expires_at": expiresAt,
+	})
+}
+
+// Download serves an export through a signed URL; no JWT is needed.
+func (h *ExportHandler) Download(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	export, path, err := h.exportService.Open(id, c.Query("expires"), c.Query("nonce"), c.Query("signature"))
+	if err != nil {
+		middleware.GetRequestLogger(c).Warn("export download rejected",
+			zap.String("export_id", id),
+			zap.String("ip", c.IP()),
+			zap.Error(err),
+		)
+		switch {
+		case errors.Is(err, service.ErrExportURLExpired):
+			return models.SendError(c, fiber.StatusGone, "Download URL has expired", models.ErrCodeURLExpired, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrExportURLUsed):
+			return models.SendError(c, fiber.StatusGone, "Download URL has already been used", models.ErrCodeURLUsed, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrExportFileMissing):
+			return models.SendNotFound(c, "Export not found", middleware.GetRequestID(c))
+		}
+		return models.SendError(c, fiber.StatusForbidden, "Invalid download URL", models.ErrCodeForbidden, middleware.GetRequestID(c))
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		middleware.GetRequestLogger(c).Error("export file missing", zap.String("export_id", id), zap.Error(err))
+		return models.SendNotFound(c, "Export not found", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("export downloaded",
+		zap.String("export_id", export.ID),
+		zap.String("report", export.Report),
+		zap.Int64("created_by", export.CreatedBy),
+		zap.Int("downloads", export.Downloads),
+		zap.String("ip", c.IP()),
+		zap.String("user_agent", c.Get(fiber.HeaderUserAgent)),
+	)
+
+	return c.Download(path, export.Report+".csv")
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"net/url"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/storage"
+)
+
+// FileHandler serves the uploads kept on local disk, to whoever holds a
+// signed URL for them. Uploads kept in S3 are downloaded from S3 itself.
+type FileHandler struct {
+	files  *storage.Local
+	logger *zap.Logger
+}
+
+// NewFileHandler takes nil files when uploads aren't kept on local disk.
+func NewFileHandler(files *storage.Local, logger *zap.Logger) *FileHandler {
+	return &FileHandler{files: files, logger: logger}
+}
+
+func (h *FileHandler) Serve(c *fiber.Ctx) error {
+	if h.files == nil {
+		return models.SendNotFound(c, "File not found", middleware.GetRequestID(c))
+	}
+	key, err := url.PathUnescape(c.Params("*"))
+	if err != nil {
+		return models.SendNotFound(c, "File not found", middleware.GetRequestID(c))
+	}
+
+	expires := c.Query("expires")
+	if err := h.files.Verify(key, expires, c.Query("signature")); err != nil {
+		if errors.Is(err, storage.ErrURLExpired) {
+			return models.SendError(c, fiber.StatusGone, "File URL has expired", models.ErrCodeURLExpired, middleware.GetRequestID(c))
+		}
+		return models.SendError(c, fiber.StatusForbidden, "Invalid file URL", models.ErrCodeForbidden, middleware.GetRequestID(c))
+	}
+
+	f, err := h.files.Get(c.UserContext(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return models.SendNotFound(c, "File not found", middleware.GetRequestID(c))
+	}
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to open file", zap.String("key", key), zap.Error(err))
+		return models.SendInternalError(c, "Failed to read file", middleware.GetRequestID(c))
+	}
+
+	// Browsers may cache the file for as long as its URL works.
+	unix, _ := strconv.ParseInt(expires, 10, 64)
+	if maxAge := int(time.Until(time.Unix(unix, 0)).Seconds()); maxAge > 0 {
+		c.Set(fiber.HeaderCacheControl, "private, max-age="+strconv.Itoa(maxAge))
+	}
+	if contentType := mime.TypeByExtension(path.Ext(key)); contentType != "" {
+		c.Set(fiber.HeaderContentType, contentType)
+	}
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	return c.SendStream(f)
+}
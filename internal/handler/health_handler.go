@@ -0,0 +1,91 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/models"
+	"BACKEND/internal/version"
+)
+
+type databaseCheck struct {
+	name   string
+	driver string
+	ping   func(context.Context) error
+	stats  func() models.PoolStats
+}
+
+// HealthHandler answers Kubernetes-style liveness and readiness probes.
+type HealthHandler struct {
+	timeout   time.Duration
+	databases []databaseCheck
+	logger    *zap.Logger
+}
+
+// defaultReadinessTimeout bounds the readiness pings when no timeout is
+// configured.
+const defaultReadinessTimeout = 2 * time.Second
+
+func NewHealthHandler(timeout time.Duration, logger *zap.Logger) *HealthHandler {
+	if timeout <= 0 {
+		timeout = defaultReadinessTimeout
+	}
+	return &HealthHandler{timeout: timeout, logger: logger}
+}
+
+// AddDatabase adds a database that must answer ping for the API to be
+// ready. stats may be nil when the driver has no pool to report on.
+func (h *HealthHandler) AddDatabase(name, driver string, ping func(context.Context) error, stats func() models.PoolStats) {
+	h.databases = append(h.databases, databaseCheck{name: name, driver: driver, ping: ping, stats: stats})
+}
+
+// Live reports that the process is up. It checks nothing else, so a
+// database outage doesn't get every instance restarted.
+func (h *HealthHandler) Live(c *fiber.Ctx) error {
+	return c.JSON(models.HealthResponse{Status: "ok"})
+}
+
+// Ready pings each database with the readiness timeout and answers 503 if
+// any of them fails, so the instance is taken out of rotation until it
+// can serve requests again.
+func (h *HealthHandler) Ready(c *fiber.Ctx) error {
+	ctx, cancel := context.WithTimeout(c.UserContext(), h.timeout)
+	defer cancel()
+
+	resp := models.ReadinessResponse{
+		Status:    "ok",
+		Version:   version.Get(),
+		Databases: make(map[string]models.DatabaseHealth, len(h.databases)),
+	}
+	for _, db := range h.databases {
+		health := models.DatabaseHealth{Driver: db.driver, Status: "up"}
+		start := time.Now()
+		err := db.ping(ctx)
+		health.LatencyMs = float64(time.Since(start).Microseconds()) / 1000
+		if err != nil {
+			// The response is public, so the error, which may name hosts,
+			// only goes to the log.
+			health.Status = "down"
+			health.Error = "unreachable"
+			if errors.Is(err, context.DeadlineExceeded) {
+				health.Error = "timeout"
+			}
+			resp.Status = "unavailable"
+			h.logger.Warn("readiness check failed", zap.String("database", db.name), zap.Error(err))
+		}
+		if db.stats != nil {
+			stats := db.stats()
+			health.Pool = &stats
+		}
+		resp.Databases[db.name] = health
+	}
+
+	if resp.Status != "ok" {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(resp)
+	}
+	return c.JSON(resp)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/models"
+)
+
+func TestHealthHandlerReady(t *testing.T) {
+	replicaDown := false
+	h := NewHealthHandler(50*time.Millisecond, zap.NewNop())
+	h.AddDatabase("primary", "postgres", func(context.Context) error { return nil }, func() models.PoolStats {
+		return models.PoolStats{MaxConns: 10, TotalConns: 3, IdleConns: 2, InUseConns: 1}
+	})
+	h.AddDatabase("replica", "postgres", func(ctx context.Context) error {
+		if replicaDown {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return nil
+	}, nil)
+
+	app := fiber.New()
+	app.Get("/healthz", h.Live)
+	app.Get("/readyz", h.Ready)
+
+	ready := func() (int, models.ReadinessResponse) {
+		t.Helper()
+		resp, err := app.Test(httptest.NewRequest("GET", "/readyz", nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body models.ReadinessResponse
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, body
+	}
+
+	status, body := ready()
+	if status != fiber.StatusOK || body.Status != "ok" || body.Version.Version == "" {
+		t.Errorf("ready = %d %+v", status, body)
+	}
+	if primary := body.Databases["primary"]; primary.Status != "up" || primary.Pool == nil || primary.Pool.InUseConns != 1 {
+		t.Errorf("primary = %+v", primary)
+	}
+	if replica := body.Databases["replica"]; replica.Status != "up" || replica.Pool != nil {
+		t.Errorf("replica = %+v", replica)
+	}
+
+	replicaDown = true
+	status, body = ready()
+	if status != fiber.StatusServiceUnavailable || body.Status != "unavailable" {
+		t.Errorf("ready with the replica down = %d %+v", status, body)
+	}
+	if replica := body.Databases["replica"]; replica.Status != "down" || replica.Error != "timeout" || replica.LatencyMs < 50 {
+		t.Errorf("replica = %+v; want down after the 50ms timeout", replica)
+	}
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/healthz", nil))
+	if err != nil || resp.StatusCode != fiber.StatusOK {
+		t.Errorf("live = %v, %v; want 200 regardless of the databases", resp, err)
+	}
+}
@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/hooks"
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+	"BACKEND/internal/sso"
+)
+
+type IdentityHandler struct {
+	identityService *service.IdentityService
+	logger          *zap.Logger
+	cookieSecure    bool
+	jwtExpiry       int
+}
+
+func NewIdentityHandler(identityService *service.IdentityService, logger *zap.Logger, cookieSecure bool, jwtExpirySeconds int) *IdentityHandler {
+	return &IdentityHandler{
+		identityService: identityService,
+		logger:          logger,
+		cookieSecure:    cookieSecure,
+		jwtExpiry:       jwtExpirySeconds,
+	}
+}
+
+// List returns the caller's linked identities and the providers they can
+// link.
+func (h *IdentityHandler) List(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	if authUser == nil {
+		return models.SendUnauthorized(c, "Unauthorized", middleware.GetRequestID(c))
+	}
+
+	linked, err := h.identityService.List(c.UserContext(), authUser.ID)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to list identities", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve identities", middleware.GetRequestID(c))
+	}
+
+	identities := make([]models.IdentityResponse, 0, len(linked))
+	for _, identity := range linked {
+		identities = append(identities, identityResponse(identity))
+	}
+	return c.JSON(models.IdentityListResponse{
+		Total:      len(identities),
+		Identities: identities,
+		Providers:  h.identityService.Providers(),
+	})
+}
+
+// Link returns the URL that sends the caller to the provider to approve
+// linking. The provider redirects back to Callback.
+func (h *IdentityHandler) Link(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	if authUser == nil {
+		return models.SendUnauthorized(c, "Unauthorized", middleware.GetRequestID(c))
+	}
+
+	authURL, err := h.identityService.BeginLink(c.UserContext(), authUser.ID, c.Params("provider"))
+	if err != nil {
+		return h.sendError(c, err, "Failed to start linking")
+	}
+	return c.JSON(fiber.Map{"authorization_url": authURL})
+}
+
+func (h *IdentityHandler) Unlink(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	if authUser == nil {
+		return models.SendUnauthorized(c, "Unauthorized", middleware.GetRequestID(c))
+	}
+	provider := c.Params("provider")
+
+	if err := h.identityService.Unlink(c.UserContext(), authUser.ID, provider); err != nil {
+		return h.sendError(c, err, "Failed to unlink identity")
+	}
+
+	middleware.GetRequestLogger(c).Info("identity unlinked",
+		zap.Int64("user_id", authUser.ID),
+		zap.String("provider", provider),
+	)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Login redirects to the provider to sign in with a linked identity.
+func (h *IdentityHandler) Login(c *fiber.Ctx) error {
+	authURL, err := h.identityService.BeginLogin(c.UserContext(), c.Params("provider"))
+	if err != nil {
+		return h.sendError(c, err, "Failed to start login")
+	}
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+// Callback finishes a link or a login, whichever the state was issued for.
+func (h *IdentityHandler) Callback(c *fiber.Ctx) error {
+	if idpErr := c.Query("error"); idpErr != "" {
+		middleware.GetRequestLogger(c).Warn("identity provider returned an error",
+			zap.String("error", idpErr),
+			zap.String("description", c.Query("error_description")),
+		)
+		return models.SendError(c, fiber.StatusUnauthorized, "Identity provider did not approve the request", models.ErrCodeUnauthorized, middleware.GetRequestID(c))
+	}
+
+	state, code := c.Query("state"), c.Query("code")
+	if state == "" || code == "" {
+		return models.SendBadRequest(c, "Missing state or code", middleware.GetRequestID(c))
+	}
+	provider := c.Params("provider")
+
+	outcome, err := h.identityService.Complete(clientContext(c), provider, state, code)
+	if err != nil {
+		return h.sendError(c, err, "Failed to complete identity request")
+	}
+
+	if outcome.Token == "" {
+		middleware.GetRequestLogger(c).Info("identity linked",
+			zap.Int64("user_id", outcome.Identity.UserID),
+			zap.String("provider", provider),
+		)
+		return c.Status(fiber.StatusCreated).JSON(identityResponse(outcome.Identity))
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "token",
+		Value:    outcome.Token,
+		Path:     "/",
+		MaxAge:   h.jwtExpiry,
+		HTTPOnly: true,
+		Secure:   h.cookieSecure,
+		SameSite: "Strict",
+	})
+
+	middleware.GetRequestLogger(c).Info("user logged in with linked identity",
+		zap.Int64("user_id", outcome.User.ID),
+		zap.String("provider", provider),
+	)
+
+	var resp models.LoginResponse
+	resp.Message = "Login successful"
+	resp.User.ID = outcome.User.PublicID.String()
+	resp.User.Name = outcome.User.Name
+	resp.User.Email = outcome.User.Email
+	resp.User.Role = outcome.User.Role
+	return c.JSON(resp)
+}
+
+func (h *IdentityHandler) sendError(c *fiber.Ctx, err error, message string) error {
+	var rejected *hooks.RejectedError
+	switch {
+	case errors.As(err, &rejected):
+		return models.SendError(c, fiber.StatusForbidden, rejected.Reason, models.ErrCodeHookRejected, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrIdentityProviderUnknown):
+		return models.SendNotFound(c, "Identity provider not found", middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrIdentityNotLinked):
+		return models.SendNotFound(c, "No identity from this provider is linked", middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrIdentityInvalidState):
+		return models.SendError(c, fiber.StatusBadRequest, "Request expired, please try again", models.ErrCodeInvalidInput, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrIdentityLinkedElsewhere):
+		return models.SendConflict(c, "This identity is linked to another account", middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrIdentityProviderLinked):
+		return models.SendConflict(c, "An identity from this provider is already linked; unlink it first", middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrLastCredential):
+		return models.SendError(c, fiber.StatusConflict, "This identity is the only way to sign in; add a passkey or link another identity first", models.ErrCodeLastCredential, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrIdentityUnknown):
+		return models.SendError(c, fiber.StatusUnauthorized, "No account is linked to this identity", models.ErrCodeInvalidCredentials, middleware.GetRequestID(c))
+	case errors.Is(err, sso.ErrTokenExchange), errors.Is(err, sso.ErrInvalidIDToken), errors.Is(err, service.ErrIdentityMissingSubject):
+		middleware.GetRequestLogger(c).Warn("identity rejected", zap.Error(err))
+		return models.SendError(c, fiber.StatusUnauthorized, "Identity could not be verified", models.ErrCodeUnauthorized, middleware.GetRequestID(c))
+	case errors.Is(err, sso.ErrDiscoveryFailed):
+		middleware.GetRequestLogger(c).Error("identity provider is unavailable", zap.Error(err))
+		return models.SendError(c, fiber.StatusBadGateway, "Identity provider is unavailable", models.ErrCodeServiceUnavailable, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrIdentitySSORequired):
+		return models.SendError(c, fiber.StatusForbidden, "This account must sign in with SSO", models.ErrCodeSSORequired, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrAccountDisabled):
+		return models.SendError(c, fiber.StatusForbidden, "Account is disabled", models.ErrCodeAccountDisabled, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrInteractiveLoginDenied):
+		return models.SendError(c, fiber.StatusForbidden, "Service accounts must authenticate with an API key", models.ErrCodeServiceAccount, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrSessionLimitReached):
+		return models.SendError(c, fiber.StatusConflict, "Too many active sessions; sign out of another device first", models.ErrCodeSessionLimit, middleware.GetRequestID(c))
+	}
+	middleware.GetRequestLogger(c).Error("identity request failed", zap.Error(err))
+	return models.SendInternalError(c, message, middleware.GetRequestID(c))
+}
+
+func identityResponse(identity generated.UserIdentity) models.IdentityResponse {
+	return models.IdentityResponse{
+		Provider: identity.Provider,
+		Email:    identity.Email,
+		LinkedAt: identity.CreatedAt.Time,
+	}
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/repository"
+)
+
+// loginHistoryQuery holds the parameters of a login history request.
+type loginHistoryQuery struct {
+	Limit int32 `query:"limit" default:"50" min:"1" max:"200"`
+}
+
+type LoginHistoryHandler struct {
+	repo   repository.LoginHistoryStore
+	logger *zap.Logger
+}
+
+func NewLoginHistoryHandler(repo repository.LoginHistoryStore, logger *zap.Logger) *LoginHistoryHandler {
+	return &LoginHistoryHandler{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Mine lists the caller's recent logins, newest first, with where they
+// came from.
+func (h *LoginHistoryHandler) Mine(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	if authUser == nil {
+		return models.SendUnauthorized(c, "Unauthorized", middleware.GetRequestID(c))
+	}
+	return h.list(c, authUser.ID)
+}
+
+func (h *LoginHistoryHandler) ForUser(c *fiber.Ctx) error {
+	id, ok := middleware.GetUserID(c)
+	if !ok {
+		return models.SendBadRequest(c, "Invalid user ID", middleware.GetRequestID(c))
+	}
+	return h.list(c, id)
+}
+
+func (h *LoginHistoryHandler) list(c *fiber.Ctx, userID int64) error {
+	var q loginHistoryQuery
+	if err := parseQuery(c, &q); err != nil {
+		return sendQueryError(c, err)
+	}
+
+	logins, err := h.repo.ListByUser(c.UserContext(), userID, q.Limit)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to list login history", zap.Int64("user_id", userID), zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve login history", middleware.GetRequestID(c))
+	}
+
+	return c.JSON(fiber.Map{
+		"total":  len(logins),
+		"logins": logins,
+	})
+}
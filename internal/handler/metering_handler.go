@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+)
+
+type topUsageQuery struct {
+	From  string `query:"from"`
+	To    string `query:"to"`
+	Limit int32  `query:"limit" default:"20" min:"1" max:"100"`
+}
+
+type MeteringHandler struct {
+	meteringService *service.MeteringService
+	logger          *zap.Logger
+}
+
+func NewMeteringHandler(meteringService *service.MeteringService, logger *zap.Logger) *MeteringHandler {
+	return &MeteringHandler{
+		meteringService: meteringService,
+		logger:          logger,
+	}
+}
+
+// Mine returns the caller's requests per API key and day between the from
+// and to days (YYYY-MM-DD, UTC, inclusive), by default the last 30 days.
+func (h *MeteringHandler) Mine(c *fiber.Ctx) error {
+	return h.usage(c, middleware.GetAuthUser(c).ID)
+}
+
+// ForUser is Mine for the user named by the route.
+func (h *MeteringHandler) ForUser(c *fiber.Ctx) error {
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return models.SendBadRequest(c, "Invalid user ID", middleware.GetRequestID(c))
+	}
+	return h.usage(c, userID)
+}
+
+func (h *MeteringHandler) usage(c *fiber.Ctx, userID int64) error {
+	var q usageQuery
+	if err := parseQuery(c, &q); err != nil {
+		return sendQueryError(c, err)
+	}
+	from, to, err := q.days()
+	if err != nil {
+		return models.SendBadRequest(c, err.Error(), middleware.GetRequestID(c))
+	}
+
+	days, err := h.meteringService.ForUser(c.UserContext(), userID, from, to)
+	if err != nil {
+		if errors.Is(err, service.ErrUsageRange) {
+			return sendUsageRangeError(c)
+		}
+		middleware.GetRequestLogger(c).Error("failed to get api usage", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve usage", middleware.GetRequestID(c))
+	}
+
+	resp := models.APIUsageResponse{
+		From: from.Format("2006-01-02"),
+		To:   to.Format("2006-01-02"),
+		Days: make([]models.APIUsageDay, 0, len(days)),
+	}
+	for _, day := range days {
+		resp.Requests += day.Requests
+		resp.Days = append(resp.Days, models.APIUsageDay{
+			Day:      day.Day.Time.Format("2006-01-02"),
+			APIKeyID: day.ApiKeyID,
+			KeyName:  day.KeyName,
+			Requests: day.Requests,
+		})
+	}
+	return c.JSON(resp)
+}
+
+// Top lists the users and API keys that made the most requests between the
+// from and to days, for chargeback and to spot abuse.
+func (h *MeteringHandler) Top(c *fiber.Ctx) error {
+	var q topUsageQuery
+	if err := parseQuery(c, &q); err != nil {
+		return sendQueryError(c, err)
+	}
+	from, to, err := usageQuery{From: q.From, To: q.To}.days()
+	if err != nil {
+		return models.SendBadRequest(c, err.Error(), middleware.GetRequestID(c))
+	}
+
+	rows, err := h.meteringService.Top(c.UserContext(), from, to, q.Limit)
+	if err != nil {
+		if errors.Is(err, service.ErrUsageRange) {
+			return sendUsageRangeError(c)
+		}
+		middleware.GetRequestLogger(c).Error("failed to get top api usage", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve usage", middleware.GetRequestID(c))
+	}
+
+	resp := models.TopAPIUsageResponse{
+		From:      from.Format("2006-01-02"),
+		To:        to.Format("2006-01-02"),
+		Consumers: make([]models.APIUsageConsumer, 0, len(rows)),
+	}
+	for _, row := range rows {
+		resp.Consumers = append(resp.Consumers, models.APIUsageConsumer{
+			UserID:   row.PublicID.String(),
+			Name:     row.Name,
+			APIKeyID: row.ApiKeyID,
+			KeyName:  row.KeyName,
+			Requests: row.Requests,
+		})
+	}
+	return c.JSON(resp)
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/policy"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/service"
+)
+
+// nameReviewQuery holds the parameters of a name review listing.
+type nameReviewQuery struct {
+	Status string `query:"status" default:"pending" enum:"pending,approved,rejected"`
+	Limit  int32  `query:"limit" default:"50" min:"1" max:"200"`
+}
+
+type ModerationHandler struct {
+	moderation *service.ModerationService
+	repo       repository.UserStore
+	policies   *policy.Engine
+	logger     *zap.Logger
+}
+
+func NewModerationHandler(moderation *service.ModerationService, repo repository.UserStore, policies *policy.Engine, logger *zap.Logger) *ModerationHandler {
+	return &ModerationHandler{
+		moderation: moderation,
+		repo:       repo,
+		policies:   policies,
+		logger:     logger,
+	}
+}
+
+// ListReviews lists queued names, oldest first. status defaults to pending.
+func (h *ModerationHandler) ListReviews(c *fiber.Ctx) error {
+	var q nameReviewQuery
+	if err := parseQuery(c, &q); err != nil {
+		return sendQueryError(c, err)
+	}
+
+	reviews, err := h.moderation.ListReviews(c.UserContext(), q.Status, q.Limit)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to list name reviews", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve name reviews", middleware.GetRequestID(c))
+	}
+
+	return c.JSON(fiber.Map{
+		"total":   len(reviews),
+		"reviews": reviews,
+	})
+}
+
+func (h *ModerationHandler) Approve(c *fiber.Ctx) error {
+	return h.resolve(c, false)
+}
+
+// Reject deactivates the user whose name was queued. Moderators can only
+// reject names of users with a lower role.
+func (h *ModerationHandler) Reject(c *fiber.Ctx) error {
+	return h.resolve(c, true)
+}
+
+func (h *ModerationHandler) resolve(c *fiber.Ctx, reject bool) error {
+	authUser := middleware.GetAuthUser(c)
+	id, ok := idParam(c, "id")
+	if !ok {
+		return models.SendBadRequest(c, "Invalid review ID", middleware.GetRequestID(c))
+	}
+
+	review, err := h.moderation.GetReview(c.UserContext(), id)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	if reject {
+		target, err := h.repo.GetByID(c.UserContext(), review.UserID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return models.SendNotFound(c, "User not found", middleware.GetRequestID(c))
+			}
+			middleware.GetRequestLogger(c).Error("failed to get user", zap.Error(err))
+			return models.SendInternalError(c, "Failed to retrieve user", middleware.GetRequestID(c))
+		}
+		resource := policy.Resource{Type: "user", ID: target.PublicID.String(), OwnerID: target.ID, Role: target.Role}
+		if !h.policies.Allowed(middleware.PolicySubject(c), policy.ActionUsersManage, resource) {
+			return models.SendError(c, fiber.StatusForbidden, "Forbidden: user has an equal or higher role", models.ErrCodeInsufficientPerms, middleware.GetRequestID(c))
+		}
+		review, err = h.moderation.Reject(c.UserContext(), review.ID, authUser.ID)
+	} else {
+		review, err = h.moderation.Approve(c.UserContext(), review.ID, authUser.ID)
+	}
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	middleware.GetRequestLogger(c).Info("name review decided",
+		zap.Int64("reviewer_id", authUser.ID),
+		zap.Int64("review_id", review.ID),
+		zap.Int64("user_id", review.UserID),
+		zap.String("status", review.Status),
+	)
+	return c.JSON(review)
+}
+
+func (h *ModerationHandler) sendError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, service.ErrNameReviewNotFound):
+		return models.SendNotFound(c, "Name review not found", middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrNameReviewResolved):
+		return models.SendError(c, fiber.StatusConflict, "Name review was already decided", models.ErrCodeAlreadyDecided, middleware.GetRequestID(c))
+	}
+	middleware.GetRequestLogger(c).Error("failed to decide name review", zap.Error(err))
+	return models.SendInternalError(c, "Failed to decide name review", middleware.GetRequestID(c))
+}
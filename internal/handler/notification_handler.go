@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+)
+
+// notificationQuery holds the parameters of an in-app notification listing.
+type notificationQuery struct {
+	Limit int `query:"limit" default:"50" min:"1" max:"200"`
+}
+
+type NotificationHandler struct {
+	notifications *service.NotificationService
+	logger        *zap.Logger
+	validate      *validator.Validate
+}
+
+func NewNotificationHandler(notifications *service.NotificationService, logger *zap.Logger) *NotificationHandler {
+	return &NotificationHandler{
+		notifications: notifications,
+		logger:        logger,
+		validate:      validator.New(),
+	}
+}
+
+// ListRules lists the notification rules along with the event types rules
+// can be written for.
+func (h *NotificationHandler) ListRules(c *fiber.Ctx) error {
+	rules, err := h.notifications.ListRules(c.UserContext())
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to list notification rules", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve notification rules", middleware.GetRequestID(c))
+	}
+	return c.JSON(models.NotificationRuleListResponse{
+		Total:  len(rules),
+		Rules:  rules,
+		Events: service.NotificationEvents,
+	})
+}
+
+func (h *NotificationHandler) CreateRule(c *fiber.Ctx) error {
+	var req models.NotificationRuleRequest
+	if err := parseBody(c, &req); err != nil {
+		return sendBodyError(c, err)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	rule, err := h.notifications.CreateRule(c.UserContext(), req)
+	if errors.Is(err, service.ErrInvalidNotificationRule) {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to create notification rule", zap.Error(err))
+		return models.SendInternalError(c, "Failed to create notification rule", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("notification rule created",
+		zap.Int64("rule_id", rule.ID),
+		zap.String("event_type", rule.EventType),
+		zap.String("channel", rule.Channel),
+		zap.Int64("admin_id", middleware.GetAuthUser(c).ID),
+	)
+	return c.Status(fiber.StatusCreated).JSON(rule)
+}
+
+func (h *NotificationHandler) UpdateRule(c *fiber.Ctx) error {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return models.SendBadRequest(c, "Invalid notification rule ID", middleware.GetRequestID(c))
+	}
+	var req models.NotificationRuleRequest
+	if err := parseBody(c, &req); err != nil {
+		return sendBodyError(c, err)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	rule, err := h.notifications.UpdateRule(c.UserContext(), id, req)
+	switch {
+	case err == nil:
+		middleware.GetRequestLogger(c).Info("notification rule updated",
+			zap.Int64("rule_id", id),
+			zap.Int64("admin_id", middleware.GetAuthUser(c).ID),
+		)
+		return c.JSON(rule)
+	case errors.Is(err, service.ErrInvalidNotificationRule):
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrNotificationRuleNotFound):
+		return models.SendNotFound(c, "Notification rule not found", middleware.GetRequestID(c))
+	}
+	middleware.GetRequestLogger(c).Error("failed to update notification rule", zap.Int64("rule_id", id), zap.Error(err))
+	return models.SendInternalError(c, "Failed to update notification rule", middleware.GetRequestID(c))
+}
+
+func (h *NotificationHandler) DeleteRule(c *fiber.Ctx) error {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return models.SendBadRequest(c, "Invalid notification rule ID", middleware.GetRequestID(c))
+	}
+
+	err := h.notifications.DeleteRule(c.UserContext(), id)
+	if errors.Is(err, service.ErrNotificationRuleNotFound) {
+		return models.SendNotFound(c, "Notification rule not found", middleware.GetRequestID(c))
+	}
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to delete notification rule", zap.Int64("rule_id", id), zap.Error(err))
+		return models.SendInternalError(c, "Failed to delete notification rule", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("notification rule deleted",
+		zap.Int64("rule_id", id),
+		zap.Int64("admin_id", middleware.GetAuthUser(c).ID),
+	)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Mine lists the caller's in-app notifications, newest first.
+func (h *NotificationHandler) Mine(c *fiber.Ctx) error {
+	var q notificationQuery
+	if err := parseQuery(c, &q); err != nil {
+		return sendQueryError(c, err)
+	}
+
+	notifications, err := h.notifications.Inbox(c.UserContext(), middleware.GetAuthUser(c).ID, q.Limit)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to list notifications", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve notifications", middleware.GetRequestID(c))
+	}
+	return c.JSON(models.NotificationListResponse{Total: len(notifications), Notifications: notifications})
+}
+
+func (h *NotificationHandler) MarkRead(c *fiber.Ctx) error {
+	id, ok := idParam(c, "id")
+	if !ok {
+		return models.SendBadRequest(c, "Invalid notification ID", middleware.GetRequestID(c))
+	}
+
+	err := h.notifications.MarkRead(c.UserContext(), middleware.GetAuthUser(c).ID, id)
+	if errors.Is(err, service.ErrNotificationNotFound) {
+		return models.SendNotFound(c, "Unread notification not found", middleware.GetRequestID(c))
+	}
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to mark notification read", zap.Int64("notification_id", id), zap.Error(err))
+		return models.SendInternalError(c, "Failed to mark notification read", middleware.GetRequestID(c))
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Preferences lists, for every event type, whether the caller receives it
+// by email and in-app.
+func (h *NotificationHandler) Preferences(c *fiber.Ctx) error {
+	prefs, err := h.notifications.Preferences(c.UserContext(), middleware.GetAuthUser(c).ID)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to list notification preferences", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve notification preferences", middleware.GetRequestID(c))
+	}
+	return c.JSON(models.NotificationPreferenceListResponse{Total: len(prefs), Preferences: prefs})
+}
+
+// SetPreference turns an event type on or off on a channel for the caller.
+func (h *NotificationHandler) SetPreference(c *fiber.Ctx) error {
+	var req models.NotificationPreference
+	if err := parseBody(c, &req); err != nil {
+		return sendBodyError(c, err)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	err := h.notifications.SetPreference(c.UserContext(), middleware.GetAuthUser(c).ID, req)
+	if errors.Is(err, service.ErrUnknownNotificationEvent) {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to set notification preference", zap.Error(err))
+		return models.SendInternalError(c, "Failed to update notification preference", middleware.GetRequestID(c))
+	}
+	return c.JSON(req)
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+)
+
+// defaultUsageDays is how far back usage goes when no from day is given.
+const defaultUsageDays = 30
+
+// usageQuery is the range of days usage is asked for, YYYY-MM-DD in UTC.
+type usageQuery struct {
+	From string `query:"from"`
+	To   string `query:"to"`
+}
+
+// days parses the range, defaulting to the defaultUsageDays up to today.
+// The error is fit to show the client.
+func (q usageQuery) days() (time.Time, time.Time, error) {
+	to := time.Now().UTC()
+	if q.To != "" {
+		t, err := time.Parse("2006-01-02", q.To)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("Invalid to day, expected YYYY-MM-DD")
+		}
+		to = t
+	}
+	from := to.AddDate(0, 0, -(defaultUsageDays - 1))
+	if q.From != "" {
+		t, err := time.Parse("2006-01-02", q.From)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("Invalid from day, expected YYYY-MM-DD")
+		}
+		from = t
+	}
+	return from, to, nil
+}
+
+func sendUsageRangeError(c *fiber.Ctx) error {
+	return models.SendBadRequest(c, "from must not be after to, and the range can be at most a year", middleware.GetRequestID(c))
+}
+
+type OrganizationHandler struct {
+	orgService *service.OrganizationService
+	logger     *zap.Logger
+	validate   *validator.Validate
+}
+
+func NewOrganizationHandler(orgService *service.OrganizationService, logger *zap.Logger) *OrganizationHandler {
+	return &OrganizationHandler{
+		orgService: orgService,
+		logger:     logger,
+		validate:   validator.New(),
+	}
+}
+
+func (h *OrganizationHandler) Create(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+
+	var req models.OrganizationRequest
+	if err := parseBody(c, &req); err != nil {
+		return sendBodyError(c, err)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	org, err := h.orgService.Create(c.UserContext(), req.Name)
+	if err != nil {
+		if isConstraintError(err) {
+			return sendConstraintError(c, err)
+		}
+		middleware.GetRequestLogger(c).Error("failed to create organization", zap.Error(err))
+		return models.SendInternalError(c, "Failed to create organization", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("admin created organization",
+		zap.Int64("admin_id", authUser.ID),
+		zap.Int64("org_id", org.ID),
+	)
+
+	return c.Status(fiber.StatusCreated).JSON(organizationResponse(org))
+}
+
+func (h *OrganizationHandler) List(c *fiber.Ctx) error {
+	orgs, err := h.orgService.List(c.UserContext())
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to list organizations", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve organizations", middleware.GetRequestID(c))
+	}
+
+	resp := make([]models.OrganizationResponse, 0, len(orgs))
+	for _, org := range orgs {
+		resp = append(resp, organizationResponse(org))
+	}
+	return c.JSON(models.OrganizationListResponse{Total: len(resp), Organizations: resp})
+}
+
+// SetMember puts the user named by the route into the organization in the
+// body, moving them out of any other.
+func (h *OrganizationHandler) SetMember(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return models.SendBadRequest(c, "Invalid user ID", middleware.GetRequestID(c))
+	}
+
+	var req models.OrganizationMemberRequest
+	if err := parseBody(c, &req); err != nil {
+		return sendBodyError(c, err)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	if err := h.orgService.SetMember(c.UserContext(), userID, req.OrgID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrganizationNotFound):
+			return models.SendNotFound(c, "Organization not found", middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrSeatLimitReached):
+			return models.SendError(c, fiber.StatusPaymentRequired, "All of the organization's seats are taken", models.ErrCodeSeatLimitReached, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrSubscriptionInactive):
+			return models.SendError(c, fiber.StatusPaymentRequired, "The organization has no active subscription", models.ErrCodeSubscriptionInactive, middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to set organization member", zap.Error(err))
+		return models.SendInternalError(c, "Failed to update organization", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("admin moved user into organization",
+		zap.Int64("admin_id", authUser.ID),
+		zap.Int64("user_id", userID),
+		zap.Int64("org_id", req.OrgID),
+	)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *OrganizationHandler) RemoveMember(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	userID, ok := middleware.GetUserID(c)
+	if !ok {
+		return models.SendBadRequest(c, "Invalid user ID", middleware.GetRequestID(c))
+	}
+
+	removed, err := h.orgService.RemoveMember(c.UserContext(), userID)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to remove organization member", zap.Error(err))
+		return models.SendInternalError(c, "Failed to update organization", middleware.GetRequestID(c))
+	}
+	if !removed {
+		return models.SendNotFound(c, "User is not in an organization", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("admin removed user from organization",
+		zap.Int64("admin_id", authUser.ID),
+		zap.Int64("user_id", userID),
+	)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// Usage returns the organization's daily usage between the from and to
+// days (YYYY-MM-DD, UTC, inclusive), by default the last 30 days.
+func (h *OrganizationHandler) Usage(c *fiber.Ctx) error {
+	orgID, ok := idParam(c, "id")
+	if !ok {
+		return models.SendBadRequest(c, "Invalid organization ID", middleware.GetRequestID(c))
+	}
+
+	var q usageQuery
+	if err := parseQuery(c, &q); err != nil {
+		return sendQueryError(c, err)
+	}
+	from, to, err := q.days()
+	if err != nil {
+		return models.SendBadRequest(c, err.Error(), middleware.GetRequestID(c))
+	}
+
+	usage, err := h.orgService.Usage(c.UserContext(), orgID, from, to)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrOrganizationNotFound):
+			return models.SendNotFound(c, "Organization not found", middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrUsageRange):
+			return sendUsageRangeError(c)
+		}
+		middleware.GetRequestLogger(c).Error("failed to get organization usage", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve usage", middleware.GetRequestID(c))
+	}
+
+	resp := models.OrganizationUsageResponse{
+		Organization: organizationResponse(usage.Organization),
+		From:         from.Format("2006-01-02"),
+		To:           to.Format("2006-01-02"),
+		Seats:        usage.Seats,
+		APICalls:     usage.APICalls,
+		StorageBytes: usage.StorageBytes,
+		Days:         make([]models.OrganizationUsageDay, 0, len(usage.Days)),
+	}
+	for _, day := range usage.Days {
+		resp.Days = append(resp.Days, models.OrganizationUsageDay{
+			Day:          day.Day.Time.Format("2006-01-02"),
+			Seats:        day.Seats,
+			APICalls:     day.ApiCalls,
+			StorageBytes: day.StorageBytes,
+		})
+	}
+	return c.JSON(resp)
+}
+
+func organizationResponse(org generated.Organization) models.OrganizationResponse {
+	return models.OrganizationResponse{
+		ID:        org.ID,
+		Name:      org.Name,
+		CreatedAt: org.CreatedAt.Time,
+	}
+}
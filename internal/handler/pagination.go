@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/queryparams"
+)
+
+// ResultTruncatedHeader is set on unpaginated lists cut short at the list
+// cap; the rest can be read with ?page= and ?limit=.
+const ResultTruncatedHeader = "X-Result-Truncated"
+
+// DeprecatedFullList marks lists requested without page or limit, which
+// are deprecated in favour of paging.
+const DeprecatedFullList = "full-user-list"
+
+// DeprecatedFeatures describes the features handlers mark with
+// middleware.MarkDeprecated, for the Deprecation headers and usage counts.
+var DeprecatedFeatures = []middleware.Deprecation{
+	{
+		Feature: DeprecatedFullList,
+		Message: "Lists without page or limit are deprecated; ask for pages with ?page= and ?limit=.",
+	},
+}
+
+// wantsPage reports whether a list request asks for a page rather than the
+// whole list.
+func wantsPage(c *fiber.Ctx) bool {
+	return c.Query("page") != "" || c.Query("limit") != ""
+}
+
+// paginationQuery holds the page and limit parameters of a list request.
+type paginationQuery struct {
+	Page  int `query:"page" default:"1" min:"1"`
+	Limit int `query:"limit" min:"1"`
+}
+
+// parsePagination binds the page and limit parameters from lookup. Limit
+// defaults to defaultLimit and can be at most maxLimit, and page can only
+// be so high that its offset fits the int32 the list queries take. The
+// error is nil or queryparams.Errors.
+func parsePagination(lookup func(key string) string, defaultLimit, maxLimit int) (page, limit int, err error) {
+	q := paginationQuery{Limit: defaultLimit}
+	var errs queryparams.Errors
+	if err := queryparams.Bind(lookup, &q); err != nil && !errors.As(err, &errs) {
+		return 0, 0, err
+	}
+
+	if !errs.Has("limit") && q.Limit > maxLimit {
+		errs = append(errs, queryparams.FieldError{Param: "limit", Value: lookup("limit"), Message: fmt.Sprintf("must be at most %d", maxLimit)})
+	}
+	if !errs.Has("page") && !errs.Has("limit") {
+		if maxPage := math.MaxInt32/q.Limit + 1; q.Page > maxPage {
+			errs = append(errs, queryparams.FieldError{Param: "page", Value: lookup("page"), Message: fmt.Sprintf("must be at most %d", maxPage)})
+		}
+	}
+	if len(errs) > 0 {
+		return 0, 0, errs
+	}
+	return q.Page, q.Limit, nil
+}
+
+// sendSortError rejects a sort parameter service.ParseUserSort didn't
+// accept.
+func sendSortError(c *fiber.Ctx, err error) error {
+	return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+}
+
+// setLinkHeader adds RFC 5988 first, prev, next and last links for a page
+// of results. Links are relative to the request and keep its other query
+// parameters.
+func setLinkHeader(c *fiber.Ctx, meta models.PaginationMeta) {
+	u, err := url.Parse(c.OriginalURL())
+	if err != nil {
+		return
+	}
+
+	lastPage := meta.TotalPages
+	if lastPage < 1 {
+		lastPage = 1
+	}
+	link := func(page int, rel string) string {
+		q := u.Query()
+		q.Set("page", strconv.Itoa(page))
+		q.Set("limit", strconv.Itoa(meta.Limit))
+		return fmt.Sprintf(`<%s?%s>; rel="%s"`, u.Path, q.Encode(), rel)
+	}
+
+	links := []string{link(1, "first")}
+	if meta.HasPrevious {
+		prev := meta.Page - 1
+		if prev > lastPage {
+			prev = lastPage
+		}
+		links = append(links, link(prev, "prev"))
+	}
+	if meta.HasNext {
+		links = append(links, link(meta.Page+1, "next"))
+	}
+	links = append(links, link(lastPage, "last"))
+
+	c.Append(fiber.HeaderLink, strings.Join(links, ", "))
+}
@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func FuzzParsePagination(f *testing.F) {
+	for _, seed := range [][2]string{
+		{"2", "10"},
+		{"", "25"},
+		{"-1", "0"},
+		{"abc", "1e3"},
+		{"9223372036854775807", "100"},
+		{"+3", " 5"},
+		{"0x10", "010"},
+	} {
+		f.Add(seed[0], seed[1])
+	}
+
+	f.Fuzz(func(t *testing.T, pageStr, limitStr string) {
+		page, limit, err := parsePagination(lookupPagination(pageStr, limitStr), 10, 100)
+		if err != nil {
+			if n, convErr := strconv.Atoi(pageStr); pageStr == "" || convErr == nil && n >= 1 && n <= 1000 {
+				if m, convErr := strconv.Atoi(limitStr); limitStr == "" || convErr == nil && m >= 1 && m <= 100 {
+					t.Fatalf("parsePagination(%q, %q) rejected valid parameters: %v", pageStr, limitStr, err)
+				}
+			}
+			return
+		}
+		if page < 1 || limit < 1 || limit > 100 {
+			t.Fatalf("parsePagination(%q, %q) = %d, %d; out of range", pageStr, limitStr, page, limit)
+		}
+		if offset := int64(page-1) * int64(limit); offset > math.MaxInt32 {
+			t.Fatalf("parsePagination(%q, %q) = page %d, limit %d; offset %d overflows int32", pageStr, limitStr, page, limit, offset)
+		}
+		if n, err := strconv.Atoi(pageStr); pageStr != "" && (err != nil || n != page) {
+			t.Fatalf("parsePagination(%q, _) = page %d; want %q", pageStr, page, pageStr)
+		}
+	})
+}
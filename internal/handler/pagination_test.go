@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/models"
+)
+
+func TestSetLinkHeader(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		meta models.PaginationMeta
+		want string
+	}{
+		{
+			name: "middle page keeps other params",
+			url:  "/api/users?limit=10&page=2&sort=name",
+			meta: models.PaginationMeta{Page: 2, Limit: 10, TotalPages: 3, HasNext: true, HasPrevious: true},
+			want: `</api/users?limit=10&page=1&sort=name>; rel="first", </api/users?limit=10&page=1&sort=name>; rel="prev", </api/users?limit=10&page=3&sort=name>; rel="next", </api/users?limit=10&page=3&sort=name>; rel="last"`,
+		},
+		{
+			name: "first page",
+			url:  "/users?page=1",
+			meta: models.PaginationMeta{Page: 1, Limit: 10, TotalPages: 2, HasNext: true},
+			want: `</users?limit=10&page=1>; rel="first", </users?limit=10&page=2>; rel="next", </users?limit=10&page=2>; rel="last"`,
+		},
+		{
+			name: "past the end",
+			url:  "/users?page=9&limit=5",
+			meta: models.PaginationMeta{Page: 9, Limit: 5, TotalPages: 2, HasPrevious: true},
+			want: `</users?limit=5&page=1>; rel="first", </users?limit=5&page=2>; rel="prev", </users?limit=5&page=2>; rel="last"`,
+		},
+		{
+			name: "no results",
+			url:  "/users?page=1",
+			meta: models.PaginationMeta{Page: 1, Limit: 10},
+			want: `</users?limit=10&page=1>; rel="first", </users?limit=10&page=1>; rel="last"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/*", func(c *fiber.Ctx) error {
+				setLinkHeader(c, tt.meta)
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			resp, err := app.Test(httptest.NewRequest("GET", tt.url, nil))
+			if err != nil {
+				t.Fatalf("app.Test failed: %v", err)
+			}
+			if got := resp.Header.Get(fiber.HeaderLink); got != tt.want {
+				t.Errorf("Link = %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// lookupPagination stands in for c.Query with fixed page and limit values.
+func lookupPagination(page, limit string) func(string) string {
+	return func(key string) string {
+		switch key {
+		case "page":
+			return page
+		case "limit":
+			return limit
+		}
+		return ""
+	}
+}
+
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		name        string
+		page, limit string
+		wantPage    int
+		wantLimit   int
+		wantErr     string
+	}{
+		{name: "defaults", wantPage: 1, wantLimit: 10},
+		{name: "valid", page: "3", limit: "25", wantPage: 3, wantLimit: 25},
+		{name: "max limit", limit: "50", wantPage: 1, wantLimit: 50},
+		{name: "non-numeric page", page: "abc", wantErr: "page: must be a whole number"},
+		{name: "negative limit", limit: "-5", wantErr: "limit: must be at least 1"},
+		{name: "zero page", page: "0", wantErr: "page: must be at least 1"},
+		{name: "limit over max", limit: "51", wantErr: "limit: must be at most 50"},
+		{name: "page past int32 offset", page: "214748366", limit: "10", wantErr: "page: must be at most 214748365"},
+		{name: "page out of int range", page: "99999999999999999999", wantErr: "page: is out of range"},
+		{name: "both bad", page: "1.5", limit: "x", wantErr: "page: must be a whole number; limit: must be a whole number"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, limit, err := parsePagination(lookupPagination(tt.page, tt.limit), 10, 50)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v; want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if page != tt.wantPage || limit != tt.wantLimit {
+				t.Errorf("page, limit = %d, %d; want %d, %d", page, limit, tt.wantPage, tt.wantLimit)
+			}
+		})
+	}
+}
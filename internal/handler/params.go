@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// idParam parses a numeric ID route parameter. It fails for anything but a
+// positive integer that fits the BIGINT ID columns, so out of range IDs get
+// a 400 instead of wrapping around.
+func idParam(c *fiber.Ctx, name string) (int64, bool) {
+	id, err := strconv.ParseInt(c.Params(name), 10, 64)
+	if err != nil || id < 1 {
+		return 0, false
+	}
+	return id, true
+}
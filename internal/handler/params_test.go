@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestIDParam(t *testing.T) {
+	tests := []struct {
+		param  string
+		want   int64
+		wantOK bool
+	}{
+		{"1", 1, true},
+		{"2147483648", 2147483648, true},
+		{"9223372036854775807", 9223372036854775807, true},
+		{"9223372036854775808", 0, false},
+		{"0", 0, false},
+		{"-5", 0, false},
+		{"abc", 0, false},
+		{"1.5", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.param, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/:id", func(c *fiber.Ctx) error {
+				id, ok := idParam(c, "id")
+				if !ok {
+					return c.SendStatus(fiber.StatusBadRequest)
+				}
+				return c.SendString(strconv.FormatInt(id, 10))
+			})
+
+			resp, err := app.Test(httptest.NewRequest("GET", "/"+tt.param, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ok := resp.StatusCode == fiber.StatusOK; ok != tt.wantOK {
+				t.Fatalf("status = %d, want ok = %v", resp.StatusCode, tt.wantOK)
+			}
+			if tt.wantOK {
+				body, _ := io.ReadAll(resp.Body)
+				if got := string(body); got != strconv.FormatInt(tt.want, 10) {
+					t.Errorf("id = %s, want %d", got, tt.want)
+				}
+			}
+		})
+	}
+}
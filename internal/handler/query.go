@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/queryparams"
+)
+
+// parseQuery binds the request's query parameters to out, as described by
+// its query, default, min, max and enum tags.
+func parseQuery(c *fiber.Ctx, out interface{}) error {
+	return queryparams.Bind(func(key string) string { return c.Query(key) }, out)
+}
+
+// sendQueryError lists every parameter parseQuery rejected in the details
+// of a 400.
+func sendQueryError(c *fiber.Ctx, err error) error {
+	var errs queryparams.Errors
+	if errors.As(err, &errs) {
+		return models.SendErrorWithDetails(c, fiber.StatusBadRequest, "Invalid query parameters: "+errs.Error(), models.ErrCodeInvalidInput, middleware.GetRequestID(c), errs)
+	}
+	return models.SendBadRequest(c, "Invalid query parameters", middleware.GetRequestID(c))
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+)
+
+type ReferralHandler struct {
+	referrals *service.ReferralService
+	logger    *zap.Logger
+}
+
+func NewReferralHandler(referrals *service.ReferralService, logger *zap.Logger) *ReferralHandler {
+	return &ReferralHandler{
+		referrals: referrals,
+		logger:    logger,
+	}
+}
+
+// Mine returns the caller's referral code and link, creating the code on
+// first use, and how many signups it has brought in.
+func (h *ReferralHandler) Mine(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	if authUser == nil {
+		return models.SendUnauthorized(c, "Unauthorized", middleware.GetRequestID(c))
+	}
+
+	stats, err := h.referrals.Stats(c.UserContext(), authUser.ID)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to get referral stats", zap.Int64("user_id", authUser.ID), zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve referrals", middleware.GetRequestID(c))
+	}
+	return c.JSON(stats)
+}
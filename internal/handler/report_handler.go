@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+)
+
+type ReportHandler struct {
+	reportService *service.ReportService
+	logger        *zap.Logger
+}
+
+func NewReportHandler(reportService *service.ReportService, logger *zap.Logger) *ReportHandler {
+	return &ReportHandler{
+		reportService: reportService,
+		logger:        logger,
+	}
+}
+
+func (h *ReportHandler) List(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"reports": h.reportService.List(),
+	})
+}
+
+// reportQuery holds the parameters Run reads itself; the rest are the
+// report's own.
+type reportQuery struct {
+	Format string `query:"format" default:"json" enum:"json,csv"`
+}
+
+func (h *ReportHandler) Run(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	name := c.Params("name")
+
+	var q reportQuery
+	if err := parseQuery(c, &q); err != nil {
+		return sendQueryError(c, err)
+	}
+
+	result, err := h.reportService.Run(c.UserContext(), name, c.Queries())
+	if err != nil {
+		if errors.Is(err, service.ErrReportNotFound) {
+			return models.SendNotFound(c, "Report not found", middleware.GetRequestID(c))
+		}
+		if errors.Is(err, service.ErrInvalidReportParam) {
+			return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to run report", zap.String("report", name), zap.Error(err))
+		return models.SendInternalError(c, "Failed to run report", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("admin ran report",
+		zap.Int64("admin_id", authUser.ID),
+		zap.String("report", name),
+		zap.String("format", q.Format),
+	)
+
+	if q.Format == "csv" {
+		return sendCSV(c, name+".csv", result)
+	}
+	return c.JSON(result)
+}
+
+func sendCSV(c *fiber.Ctx, filename string, result *service.ReportResult) error {
+	var buf bytes.Buffer
+	if err := result.WriteCSV(&buf); err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	return c.Send(buf.Bytes())
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+)
+
+type RetentionHandler struct {
+	retentionService *service.RetentionService
+	logger           *zap.Logger
+}
+
+func NewRetentionHandler(retentionService *service.RetentionService, logger *zap.Logger) *RetentionHandler {
+	return &RetentionHandler{
+		retentionService: retentionService,
+		logger:           logger,
+	}
+}
+
+func (h *RetentionHandler) List(c *fiber.Ctx) error {
+	statuses, err := h.retentionService.Status(c.UserContext())
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to get retention status", zap.Error(err))
+		return models.SendInternalError(c, "Failed to get retention status", middleware.GetRequestID(c))
+	}
+
+	return c.JSON(fiber.Map{
+		"categories": statuses,
+	})
+}
+
+func (h *RetentionHandler) Get(c *fiber.Ctx) error {
+	category := c.Params("category")
+
+	status, err := h.retentionService.CategoryStatus(c.UserContext(), category)
+	if err != nil {
+		if errors.Is(err, service.ErrRetentionCategoryNotFound) {
+			return models.SendNotFound(c, "Retention category not found", middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to get retention status", zap.String("category", category), zap.Error(err))
+		return models.SendInternalError(c, "Failed to get retention status", middleware.GetRequestID(c))
+	}
+
+	return c.JSON(status)
+}
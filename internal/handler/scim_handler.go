@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/hooks"
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+)
+
+type SCIMHandler struct {
+	scimService *service.SCIMService
+	logger      *zap.Logger
+}
+
+func NewSCIMHandler(scimService *service.SCIMService, logger *zap.Logger) *SCIMHandler {
+	return &SCIMHandler{
+		scimService: scimService,
+		logger:      logger,
+	}
+}
+
+func (h *SCIMHandler) ServiceProviderConfig(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"schemas":        []string{"urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"},
+		"patch":          fiber.Map{"supported": true},
+		"bulk":           fiber.Map{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         fiber.Map{"supported": true, "maxResults": 200},
+		"changePassword": fiber.Map{"supported": false},
+		"sort":           fiber.Map{"supported": false},
+		"etag":           fiber.Map{"supported": false},
+		"authenticationSchemes": []fiber.Map{{
+			"type":        "oauthbearertoken",
+			"name":        "OAuth Bearer Token",
+			"description": "Authentication using a static bearer token configured via SCIM_TOKEN",
+		}},
+	}, models.SCIMContentType)
+}
+
+func (h *SCIMHandler) ListUsers(c *fiber.Ctx) error {
+	startIndex := c.QueryInt("startIndex", 1)
+	count := c.QueryInt("count", 0)
+
+	resp, err := h.scimService.List(c.UserContext(), c.Query("filter"), startIndex, count)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+	return c.JSON(resp, models.SCIMContentType)
+}
+
+func (h *SCIMHandler) GetUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+	user, err := h.scimService.Get(c.UserContext(), id)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+	return c.JSON(user, models.SCIMContentType)
+}
+
+func (h *SCIMHandler) CreateUser(c *fiber.Ctx) error {
+	var req models.SCIMUser
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return models.SendSCIMError(c, fiber.StatusBadRequest, models.SCIMErrInvalidSyntax, "Invalid request body")
+	}
+
+	user, err := h.scimService.Create(c.UserContext(), req)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	middleware.GetRequestLogger(c).Info("scim user provisioned",
+		zap.String("user_id", user.ID),
+		zap.String("email", user.UserName),
+	)
+
+	c.Location(user.Meta.Location)
+	return c.Status(fiber.StatusCreated).JSON(user, models.SCIMContentType)
+}
+
+func (h *SCIMHandler) ReplaceUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var req models.SCIMUser
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return models.SendSCIMError(c, fiber.StatusBadRequest, models.SCIMErrInvalidSyntax, "Invalid request body")
+	}
+
+	user, err := h.scimService.Replace(c.UserContext(), id, req)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	middleware.GetRequestLogger(c).Info("scim user replaced", zap.String("user_id", user.ID))
+	return c.JSON(user, models.SCIMContentType)
+}
+
+func (h *SCIMHandler) PatchUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+	var req models.SCIMPatchRequest
+	if err := json.Unmarshal(c.Body(), &req); err != nil {
+		return models.SendSCIMError(c, fiber.StatusBadRequest, models.SCIMErrInvalidSyntax, "Invalid request body")
+	}
+
+	user, err := h.scimService.Patch(c.UserContext(), id, req)
+	if err != nil {
+		return h.sendError(c, err)
+	}
+
+	middleware.GetRequestLogger(c).Info("scim user patched",
+		zap.String("user_id", user.ID),
+		zap.Bool("active", *user.Active),
+	)
+	return c.JSON(user, models.SCIMContentType)
+}
+
+func (h *SCIMHandler) DeleteUser(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if err := h.scimService.Delete(c.UserContext(), id); err != nil {
+		return h.sendError(c, err)
+	}
+
+	middleware.GetRequestLogger(c).Info("scim user deprovisioned", zap.String("user_id", id))
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *SCIMHandler) sendError(c *fiber.Ctx, err error) error {
+	var rejected *hooks.RejectedError
+	if errors.As(err, &rejected) {
+		return models.SendSCIMError(c, fiber.StatusBadRequest, models.SCIMErrInvalidValue, rejected.Reason)
+	}
+
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		return models.SendSCIMError(c, fiber.StatusNotFound, "", "User not found")
+	case errors.Is(err, service.ErrEmailAlreadyExists):
+		return models.SendSCIMError(c, fiber.StatusConflict, models.SCIMErrUniqueness, "A user with this userName already exists")
+	case errors.Is(err, service.ErrSCIMInvalidFilter):
+		return models.SendSCIMError(c, fiber.StatusBadRequest, models.SCIMErrInvalidFilter, err.Error())
+	case errors.Is(err, service.ErrSCIMInvalidValue):
+		return models.SendSCIMError(c, fiber.StatusBadRequest, models.SCIMErrInvalidValue, err.Error())
+	case errors.Is(err, service.ErrSCIMMutability):
+		return models.SendSCIMError(c, fiber.StatusBadRequest, models.SCIMErrMutability, err.Error())
+	}
+
+	middleware.GetRequestLogger(c).Error("scim request failed", zap.Error(err))
+	return models.SendSCIMError(c, fiber.StatusInternalServerError, "", "Internal server error")
+}
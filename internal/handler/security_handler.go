@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+)
+
+type SecurityHandler struct {
+	detector    *service.BruteForceDetector
+	securityLog *service.SecurityLogService
+	logger      *zap.Logger
+}
+
+func NewSecurityHandler(detector *service.BruteForceDetector, logger *zap.Logger) *SecurityHandler {
+	return &SecurityHandler{
+		detector: detector,
+		logger:   logger,
+	}
+}
+
+// SetSecurityLog serves the security log from log.
+func (h *SecurityHandler) SetSecurityLog(log *service.SecurityLogService) {
+	h.securityLog = log
+}
+
+// Alerts lists recent security alerts, newest first. Alerts are kept in
+// memory and lost on restart.
+func (h *SecurityHandler) Alerts(c *fiber.Ctx) error {
+	alerts := h.detector.Alerts()
+	return c.JSON(fiber.Map{
+		"total":  len(alerts),
+		"alerts": alerts,
+	})
+}
+
+type securityEventsQuery struct {
+	Before int64 `query:"before" min:"1"`
+	Limit  int   `query:"limit" default:"50" min:"1" max:"500"`
+}
+
+// Events lists the security log, newest first. ?before= takes the id of
+// the last event of the previous page.
+func (h *SecurityHandler) Events(c *fiber.Ctx) error {
+	var q securityEventsQuery
+	if err := parseQuery(c, &q); err != nil {
+		return sendQueryError(c, err)
+	}
+
+	events, err := h.securityLog.List(c.UserContext(), q.Before, q.Limit)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to list security events", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve security events", middleware.GetRequestID(c))
+	}
+	return c.JSON(fiber.Map{
+		"total":  len(events),
+		"events": events,
+	})
+}
+
+// Verify checks the security log's hash chain from the first event to the
+// last.
+func (h *SecurityHandler) Verify(c *fiber.Ctx) error {
+	result, err := h.securityLog.Verify(c.UserContext())
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to verify security log", zap.Error(err))
+		return models.SendInternalError(c, "Failed to verify security log", middleware.GetRequestID(c))
+	}
+	if !result.Valid {
+		middleware.GetRequestLogger(c).Error("security log integrity check failed",
+			zap.Int64("event_id", result.BrokenAt),
+			zap.String("problem", result.Problem),
+		)
+	}
+	return c.JSON(result)
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/hooks"
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+)
+
+type ServiceAccountHandler struct {
+	service  *service.ServiceAccountService
+	validate *validator.Validate
+	logger   *zap.Logger
+}
+
+func NewServiceAccountHandler(s *service.ServiceAccountService, logger *zap.Logger) *ServiceAccountHandler {
+	return &ServiceAccountHandler{
+		service:  s,
+		validate: validator.New(),
+		logger:   logger,
+	}
+}
+
+func (h *ServiceAccountHandler) List(c *fiber.Ctx) error {
+	accounts, err := h.service.List(c.UserContext())
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to list service accounts", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve service accounts", middleware.GetRequestID(c))
+	}
+
+	return c.JSON(fiber.Map{
+		"total":            len(accounts),
+		"service_accounts": accounts,
+	})
+}
+
+func (h *ServiceAccountHandler) Create(c *fiber.Ctx) error {
+	var req models.ServiceAccountRequest
+
+	if err := parseBody(c, &req); err != nil {
+		return sendBodyError(c, err)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	account, err := h.service.Create(c.UserContext(), req.Name, req.Role)
+	if err != nil {
+		var rejected *hooks.RejectedError
+		if errors.As(err, &rejected) {
+			return models.SendError(c, fiber.StatusUnprocessableEntity, rejected.Reason, models.ErrCodeHookRejected, middleware.GetRequestID(c))
+		}
+		if isConstraintError(err) {
+			return sendConstraintError(c, err)
+		}
+		middleware.GetRequestLogger(c).Error("failed to create service account", zap.Error(err))
+		return models.SendInternalError(c, "Failed to create service account", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("service account created",
+		zap.Int64("service_account_id", account.ID),
+		zap.String("role", account.Role),
+	)
+
+	return c.Status(fiber.StatusCreated).JSON(account)
+}
+
+func (h *ServiceAccountHandler) ListKeys(c *fiber.Ctx) error {
+	id, ok := middleware.GetUserID(c)
+	if !ok {
+		return models.SendBadRequest(c, "Invalid service account ID", middleware.GetRequestID(c))
+	}
+
+	keys, err := h.service.ListKeys(c.UserContext(), id)
+	if err != nil {
+		return h.sendAccountError(c, err, "Failed to retrieve API keys")
+	}
+
+	return c.JSON(fiber.Map{
+		"total": len(keys),
+		"keys":  keys,
+	})
+}
+
+// CreateKey returns the plaintext key. It is not stored and cannot be
+// retrieved again.
+func (h *ServiceAccountHandler) CreateKey(c *fiber.Ctx) error {
+	id, ok := middleware.GetUserID(c)
+	if !ok {
+		return models.SendBadRequest(c, "Invalid service account ID", middleware.GetRequestID(c))
+	}
+
+	var req models.APIKeyRequest
+	if err := parseBody(c, &req); err != nil {
+		return sendBodyError(c, err)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	expiresIn := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	key, rawKey, err := h.service.CreateKey(c.UserContext(), id, req.Name, req.Scopes, expiresIn)
+	if err != nil {
+		return h.sendAccountError(c, err, "Failed to create API key")
+	}
+
+	middleware.GetRequestLogger(c).Info("api key created",
+		zap.Int64("service_account_id", key.UserID),
+		zap.Int64("key_id", key.ID),
+		zap.String("prefix", key.Prefix),
+	)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"key":     rawKey,
+		"api_key": key,
+	})
+}
+
+func (h *ServiceAccountHandler) RevokeKey(c *fiber.Ctx) error {
+	id, ok := middleware.GetUserID(c)
+	if !ok {
+		return models.SendBadRequest(c, "Invalid service account ID", middleware.GetRequestID(c))
+	}
+	keyID, ok := idParam(c, "keyId")
+	if !ok {
+		return models.SendBadRequest(c, "Invalid API key ID", middleware.GetRequestID(c))
+	}
+
+	if err := h.service.RevokeKey(c.UserContext(), id, keyID); err != nil {
+		if errors.Is(err, service.ErrAPIKeyNotFound) {
+			return models.SendNotFound(c, "API key not found", middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to revoke api key", zap.Error(err))
+		return models.SendInternalError(c, "Failed to revoke API key", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("api key revoked",
+		zap.Int64("service_account_id", id),
+		zap.Int64("key_id", keyID),
+	)
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *ServiceAccountHandler) sendAccountError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		return models.SendNotFound(c, "Service account not found", middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrNotServiceAccount):
+		return models.SendError(c, fiber.StatusBadRequest, "User is not a service account", models.ErrCodeInvalidInput, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrInvalidScope):
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+	middleware.GetRequestLogger(c).Error("service account request failed", zap.Error(err))
+	return models.SendInternalError(c, message, middleware.GetRequestID(c))
+}
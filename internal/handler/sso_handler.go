@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/hooks"
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+	"BACKEND/internal/sso"
+)
+
+type SSOHandler struct {
+	ssoService   *service.SSOService
+	validate     *validator.Validate
+	logger       *zap.Logger
+	cookieSecure bool
+	jwtExpiry    int
+}
+
+func NewSSOHandler(ssoService *service.SSOService, logger *zap.Logger, cookieSecure bool, jwtExpirySeconds int) *SSOHandler {
+	return &SSOHandler{
+		ssoService:   ssoService,
+		validate:     validator.New(),
+		logger:       logger,
+		cookieSecure: cookieSecure,
+		jwtExpiry:    jwtExpirySeconds,
+	}
+}
+
+// Discover tells the client whether an email must sign in through the IdP.
+func (h *SSOHandler) Discover(c *fiber.Ctx) error {
+	var req models.SSODiscoverRequest
+
+	if err := parseBody(c, &req); err != nil {
+		return sendBodyError(c, err)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	if !h.ssoService.RequiresSSO(req.Email) {
+		return c.JSON(models.SSODiscoverResponse{SSO: false})
+	}
+	return c.JSON(models.SSODiscoverResponse{
+		SSO:      true,
+		LoginURL: strings.TrimSuffix(c.Path(), "/discover") + "/login?email=" + url.QueryEscape(req.Email),
+	})
+}
+
+func (h *SSOHandler) Login(c *fiber.Ctx) error {
+	authURL, err := h.ssoService.Begin(c.UserContext(), c.Query("email"))
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to start sso login", zap.Error(err))
+		if errors.Is(err, sso.ErrDiscoveryFailed) {
+			return models.SendError(c, fiber.StatusBadGateway, "Identity provider is unavailable", models.ErrCodeServiceUnavailable, middleware.GetRequestID(c))
+		}
+		return models.SendInternalError(c, "Failed to start SSO login", middleware.GetRequestID(c))
+	}
+
+	return c.Redirect(authURL, fiber.StatusFound)
+}
+
+func (h *SSOHandler) Callback(c *fiber.Ctx) error {
+	if idpErr := c.Query("error"); idpErr != "" {
+		middleware.GetRequestLogger(c).Warn("identity provider returned an error",
+			zap.String("error", idpErr),
+			zap.String("description", c.Query("error_description")),
+		)
+		return models.SendError(c, fiber.StatusUnauthorized, "SSO login was not completed", models.ErrCodeUnauthorized, middleware.GetRequestID(c))
+	}
+
+	state, code := c.Query("state"), c.Query("code")
+	if state == "" || code == "" {
+		return models.SendBadRequest(c, "Missing state or code", middleware.GetRequestID(c))
+	}
+
+	user, token, err := h.ssoService.Complete(clientContext(c), state, code)
+	if err != nil {
+		var rejected *hooks.RejectedError
+		switch {
+		case errors.As(err, &rejected):
+			return models.SendError(c, fiber.StatusForbidden, rejected.Reason, models.ErrCodeHookRejected, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrSSOInvalidState):
+			return models.SendError(c, fiber.StatusBadRequest, "SSO login expired, please try again", models.ErrCodeInvalidInput, middleware.GetRequestID(c))
+		case errors.Is(err, sso.ErrTokenExchange), errors.Is(err, sso.ErrInvalidIDToken), errors.Is(err, service.ErrSSOEmailNotVerified):
+			middleware.GetRequestLogger(c).Warn("sso login rejected", zap.Error(err))
+			return models.SendError(c, fiber.StatusUnauthorized, "SSO login failed", models.ErrCodeUnauthorized, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrSSOUserNotFound), errors.Is(err, service.ErrSSOMissingBirthdate):
+			middleware.GetRequestLogger(c).Warn("sso user could not be provisioned", zap.Error(err))
+			return models.SendError(c, fiber.StatusForbidden, "No account exists for this identity; ask an administrator to provision it", models.ErrCodeForbidden, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrSSODomainNotLinked):
+			middleware.GetRequestLogger(c).Warn("sso login refused for an account outside the sso domains", zap.Error(err))
+			return models.SendError(c, fiber.StatusForbidden, "This account does not sign in with SSO; use your password instead", models.ErrCodeForbidden, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrAccountDisabled):
+			return models.SendError(c, fiber.StatusForbidden, "Account is disabled", models.ErrCodeAccountDisabled, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrInteractiveLoginDenied):
+			return models.SendError(c, fiber.StatusForbidden, "Service accounts must authenticate with an API key", models.ErrCodeServiceAccount, middleware.GetRequestID(c))
+		case errors.Is(err, service.ErrSessionLimitReached):
+			return models.SendError(c, fiber.StatusConflict, "Too many active sessions; sign out of another device first", models.ErrCodeSessionLimit, middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to complete sso login", zap.Error(err))
+		return models.SendInternalError(c, "Failed to authenticate user", middleware.GetRequestID(c))
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "token",
+		Value:    token,
+		Path:     "/",
+		MaxAge:   h.jwtExpiry,
+		HTTPOnly: true,
+		Secure:   h.cookieSecure,
+		SameSite: "Strict",
+	})
+
+	middleware.GetRequestLogger(c).Info("user logged in via sso",
+		zap.Int64("user_id", user.ID),
+		zap.String("email", user.Email),
+	)
+
+	var resp models.LoginResponse
+	resp.Message = "Login successful"
+	resp.User.ID = user.PublicID.String()
+	resp.User.Name = user.Name
+	resp.User.Email = user.Email
+	resp.User.Role = user.Role
+	return c.JSON(resp)
+}
+
+// RequirePasswordLogin rejects password logins for domains routed to the IdP.
+func (h *SSOHandler) RequirePasswordLogin(c *fiber.Ctx) error {
+	var req models.LoginRequest
+	if err := json.Unmarshal(c.Body(), &req); err == nil && h.ssoService.RequiresSSO(req.Email) {
+		middleware.GetRequestLogger(c).Warn("password login attempted for sso domain", zap.String("email", req.Email))
+		return models.SendError(c, fiber.StatusForbidden, "This account must sign in with SSO", models.ErrCodeSSORequired, middleware.GetRequestID(c))
+	}
+	return c.Next()
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/service"
+	"BACKEND/internal/version"
+)
+
+type SystemHandler struct {
+	limiter      *middleware.AdaptiveLimiter
+	rateLimiter  *middleware.RateLimiter
+	repoMetrics  *repository.Metrics
+	outcomes     *service.Outcomes
+	connections  *middleware.ConnectionStats
+	deprecations *middleware.Deprecations
+	analytics    *middleware.EndpointAnalytics
+	logger       *zap.Logger
+}
+
+func NewSystemHandler(limiter *middleware.AdaptiveLimiter, logger *zap.Logger) *SystemHandler {
+	return &SystemHandler{
+		limiter: limiter,
+		logger:  logger,
+	}
+}
+
+func (h *SystemHandler) SetRateLimiter(l *middleware.RateLimiter) {
+	h.rateLimiter = l
+}
+
+func (h *SystemHandler) SetRepositoryMetrics(m *repository.Metrics) {
+	h.repoMetrics = m
+}
+
+func (h *SystemHandler) SetOutcomes(o *service.Outcomes) {
+	h.outcomes = o
+}
+
+func (h *SystemHandler) SetConnectionStats(s *middleware.ConnectionStats) {
+	h.connections = s
+}
+
+func (h *SystemHandler) SetDeprecations(d *middleware.Deprecations) {
+	h.deprecations = d
+}
+
+func (h *SystemHandler) SetEndpointAnalytics(a *middleware.EndpointAnalytics) {
+	h.analytics = a
+}
+
+func (h *SystemHandler) Version(c *fiber.Ctx) error {
+	return c.JSON(version.Get())
+}
+
+func (h *SystemHandler) LoadShedding(c *fiber.Ctx) error {
+	return c.JSON(h.limiter.Stats())
+}
+
+// Connections shows this process's open connections, how many requests
+// each connection carried on average, and the listener's timeouts.
+func (h *SystemHandler) Connections(c *fiber.Ctx) error {
+	return c.JSON(h.connections.Stats(c.App()))
+}
+
+// Deprecations lists the deprecated features and how many requests still
+// used each since this process started.
+func (h *SystemHandler) Deprecations(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"deprecations": h.deprecations.Usage()})
+}
+
+// EndpointAnalytics shows the calls to each route over the analytics
+// window, with their error counts and latency percentiles, broken down by
+// client. ?route= and ?client= narrow it to one route or client, e.g. to
+// see who still calls an endpoint before changing it.
+func (h *SystemHandler) EndpointAnalytics(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"window_seconds": int64(h.analytics.Window().Seconds()),
+		"endpoints":      h.analytics.Stats(c.Query("route"), c.Query("client")),
+	})
+}
+
+// RepositoryStats shows the latency, error rate and row counts of each
+// repository method since startup, to find which calls are slow.
+func (h *SystemHandler) RepositoryStats(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{
+		"slow_threshold_ms": h.repoMetrics.SlowThreshold().Milliseconds(),
+		"methods":           h.repoMetrics.Stats(),
+	})
+}
+
+// Outcomes counts the business outcomes of signups and logins since
+// startup, e.g. signup_duplicate_email, for product dashboards.
+func (h *SystemHandler) Outcomes(c *fiber.Ctx) error {
+	return c.JSON(fiber.Map{"outcomes": h.outcomes.Counts()})
+}
+
+// MyRateLimit shows the caller's usage of the per-client rate limit,
+// including this request.
+func (h *SystemHandler) MyRateLimit(c *fiber.Ctx) error {
+	usage, err := h.rateLimiter.Usage(c.UserContext(), middleware.RateLimitClient(c))
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to get rate limit usage", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve rate limit usage", middleware.GetRequestID(c))
+	}
+	return c.JSON(usage)
+}
@@ -1,31 +1,39 @@
 package handler
 
 import (
-	"strconv"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 
+	"BACKEND/hooks"
 	"BACKEND/internal/middleware"
 	"BACKEND/internal/models"
+	"BACKEND/internal/queryparams"
 	"BACKEND/internal/repository"
 	"BACKEND/internal/service"
 )
 
 type UserHandler struct {
-	repo     *repository.UserRepository
+	repo     repository.UserStore
 	service  *service.UserService
 	validate *validator.Validate
 	logger   *zap.Logger
 }
 
-func NewUserHandler(r *repository.UserRepository, s *service.UserService, l *zap.Logger) *UserHandler {
+func NewUserHandler(r repository.UserStore, s *service.UserService, l *zap.Logger) *UserHandler {
 	return &UserHandler{
 		repo:     r,
 		service:  s,
-		validate: validator.New(), 
+		validate: validator.New(),
 		logger:   l,
 	}
 }
@@ -33,155 +41,453 @@ func NewUserHandler(r *repository.UserRepository, s *service.UserService, l *zap
 func (h *UserHandler) Create(c *fiber.Ctx) error {
 	var req models.UserRequest
 
-	if err := c.BodyParser(&req); err != nil {
-		middleware.GetRequestLogger(c).Error("failed to parse request body", zap.Error(err))
-		return models.SendBadRequest(c, "Invalid request body", middleware.GetRequestID(c))
+	if err := parseBody(c, &req); err != nil {
+		middleware.LogRejected(c, fiber.StatusBadRequest, "failed to parse request body", zap.Error(err))
+		return sendBodyError(c, err)
 	}
 
 	if err := h.validate.Struct(req); err != nil {
-		middleware.GetRequestLogger(c).Error("validation failed", zap.Error(err))
+		middleware.LogRejected(c, fiber.StatusBadRequest, "validation failed", zap.Error(err))
 		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
 	}
 
-	dob, err := time.Parse("2006-01-02", req.Dob)
+	dob, err := service.ParseDob(req.Dob)
 	if err != nil {
-		middleware.GetRequestLogger(c).Error("invalid date format", zap.Error(err))
+		middleware.LogRejected(c, fiber.StatusBadRequest, "invalid date format", zap.Error(err))
 		return models.SendBadRequest(c, "Invalid date format, use YYYY-MM-DD", middleware.GetRequestID(c))
 	}
 
-	user, err := h.repo.Create(c.Context(), req.Name, dob)
+	user, err := h.repo.Create(c.UserContext(), req.Name, service.SignupSourceAPI, dob)
 	if err != nil {
+		var rejected *hooks.RejectedError
+		if errors.As(err, &rejected) {
+			return models.SendError(c, fiber.StatusUnprocessableEntity, rejected.Reason, models.ErrCodeHookRejected, middleware.GetRequestID(c))
+		}
+		if isConstraintError(err) {
+			return sendConstraintError(c, err)
+		}
 		middleware.GetRequestLogger(c).Error("create user failed", zap.Error(err))
 		return models.SendInternalError(c, "Failed to create user", middleware.GetRequestID(c))
 	}
 
-	middleware.GetRequestLogger(c).Info("user created", zap.Int32("id", user.ID))
+	middleware.GetRequestLogger(c).Info("user created", zap.Int64("id", user.ID))
 
 	return c.Status(201).JSON(models.UserResponse{
-		ID:   user.ID,
-		Name: user.Name,
-		Dob:  user.Dob.Time.Format("2006-01-02"),
+		ID:        user.PublicID.String(),
+		Name:      user.Name,
+		Dob:       user.Dob.Time.Format("2006-01-02"),
+		AvatarURL: h.service.Avatars().URL(user.Email, user.PublicID.String()),
 	})
 }
 
 func (h *UserHandler) GetByID(c *fiber.Ctx) error {
-	id, err := strconv.Atoi(c.Params("id"))
-	if err != nil {
+	id, ok := middleware.GetUserID(c)
+	if !ok {
 		return models.SendBadRequest(c, "Invalid user ID", middleware.GetRequestID(c))
 	}
 
-	resp, err := h.service.GetUserWithAge(c.Context(), int32(id))
+	resp, err := h.service.GetUserWithAge(c.UserContext(), id)
 	if err != nil {
-		middleware.GetRequestLogger(c).Error("get user failed", zap.Error(err))
+		middleware.LogRejected(c, fiber.StatusNotFound, "get user failed", zap.Error(err))
 		return models.SendNotFound(c, "User not found", middleware.GetRequestID(c))
 	}
 
 	return c.JSON(resp)
 }
 
+// GetAge returns the user's age on the ?at= day (YYYY-MM-DD), by default
+// today.
+func (h *UserHandler) GetAge(c *fiber.Ctx) error {
+	id, ok := middleware.GetUserID(c)
+	if !ok {
+		return models.SendBadRequest(c, "Invalid user ID", middleware.GetRequestID(c))
+	}
+
+	var at time.Time
+	if raw := c.Query("at"); raw != "" {
+		var err error
+		if at, err = service.ParseDob(raw); err != nil {
+			return sendQueryError(c, queryparams.Errors{{Param: "at", Value: raw, Message: "must be a YYYY-MM-DD day"}})
+		}
+	}
+
+	resp, err := h.service.AgeAt(c.UserContext(), id, at)
+	if errors.Is(err, service.ErrDateBeforeBirth) {
+		return models.SendBadRequest(c, "at is before the user was born", middleware.GetRequestID(c))
+	}
+	if err != nil {
+		middleware.LogRejected(c, fiber.StatusNotFound, "get user age failed", zap.Error(err))
+		return models.SendNotFound(c, "User not found", middleware.GetRequestID(c))
+	}
+
+	return c.JSON(resp)
+}
 
 func (h *UserHandler) GetCurrentUser(c *fiber.Ctx) error {
-	
+
 	authUser := middleware.GetAuthUser(c)
 	if authUser == nil {
 		middleware.GetRequestLogger(c).Error("auth user not found in context")
 		return models.SendUnauthorized(c, "Unauthorized", middleware.GetRequestID(c))
 	}
 
-	resp, err := h.service.GetUserWithAge(c.Context(), authUser.ID)
+	resp, err := h.service.CurrentUser(c.UserContext(), authUser.ID)
 	if err != nil {
-		middleware.GetRequestLogger(c).Error("get current user failed", zap.Int32("user_id", authUser.ID), zap.Error(err))
+		middleware.LogRejected(c, fiber.StatusNotFound, "get current user failed", zap.Int64("user_id", authUser.ID), zap.Error(err))
 		return models.SendNotFound(c, "User not found", middleware.GetRequestID(c))
 	}
 
-	middleware.GetRequestLogger(c).Info("current user retrieved", zap.Int32("user_id", authUser.ID))
+	middleware.GetRequestLogger(c).Info("current user retrieved", zap.Int64("user_id", authUser.ID))
 
 	return c.JSON(resp)
 }
 
-func (h *UserHandler) List(c *fiber.Ctx) error {
-	pageStr := c.Query("page")
-	limitStr := c.Query("limit")
+// UpdateProfile changes the fields of the caller's profile that the request
+// sets and returns the caller as GET /users/me does.
+func (h *UserHandler) UpdateProfile(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	if authUser == nil {
+		return models.SendUnauthorized(c, "Unauthorized", middleware.GetRequestID(c))
+	}
+
+	var req models.ProfileUpdateRequest
+	if err := parseBody(c, &req); err != nil {
+		middleware.LogRejected(c, fiber.StatusBadRequest, "failed to parse request body", zap.Error(err))
+		return sendBodyError(c, err)
+	}
+
+	profile, err := h.service.Profile(c.UserContext(), authUser.ID)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to load profile", zap.Int64("user_id", authUser.ID), zap.Error(err))
+		return models.SendInternalError(c, "Failed to update profile", middleware.GetRequestID(c))
+	}
+	req.Apply(&profile)
+	if err := h.validate.Struct(profile); err != nil {
+		middleware.LogRejected(c, fiber.StatusBadRequest, "validation failed", zap.Error(err))
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	if err := h.service.SaveProfile(c.UserContext(), authUser.ID, profile); err != nil {
+		middleware.GetRequestLogger(c).Error("failed to save profile", zap.Int64("user_id", authUser.ID), zap.Error(err))
+		return models.SendInternalError(c, "Failed to update profile", middleware.GetRequestID(c))
+	}
+	resp, err := h.service.CurrentUser(c.UserContext(), authUser.ID)
+	if err != nil {
+		middleware.LogRejected(c, fiber.StatusNotFound, "get current user failed", zap.Int64("user_id", authUser.ID), zap.Error(err))
+		return models.SendNotFound(c, "User not found", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("profile updated", zap.Int64("user_id", authUser.ID))
+	return c.JSON(resp)
+}
+
+// UploadAvatar makes the picture in the avatar field of a multipart form the
+// caller's avatar and returns the caller as GET /users/me does.
+func (h *UserHandler) UploadAvatar(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	if authUser == nil {
+		return models.SendUnauthorized(c, "Unauthorized", middleware.GetRequestID(c))
+	}
+	maxBytes := h.service.Avatars().MaxUploadBytes()
+	if maxBytes == 0 {
+		return models.SendNotFound(c, "Avatar uploads are not enabled", middleware.GetRequestID(c))
+	}
+
+	file, err := c.FormFile("avatar")
+	if err != nil {
+		middleware.LogRejected(c, fiber.StatusBadRequest, "avatar missing", zap.Error(err))
+		return models.SendBadRequest(c, "Send the picture as the avatar field of a multipart/form-data body", middleware.GetRequestID(c))
+	}
+	if file.Size > maxBytes {
+		return models.SendError(c, fiber.StatusRequestEntityTooLarge, fmt.Sprintf("Avatar must be at most %d bytes", maxBytes), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+	f, err := file.Open()
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to open uploaded avatar", zap.Error(err))
+		return models.SendInternalError(c, "Failed to upload avatar", middleware.GetRequestID(c))
+	}
+	data, err := io.ReadAll(io.LimitReader(f, maxBytes+1))
+	f.Close()
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to read uploaded avatar", zap.Error(err))
+		return models.SendInternalError(c, "Failed to upload avatar", middleware.GetRequestID(c))
+	}
+
+	err = h.service.UploadAvatar(c.UserContext(), authUser.ID, data)
+	switch {
+	case errors.Is(err, service.ErrAvatarTooLarge):
+		middleware.LogRejected(c, fiber.StatusRequestEntityTooLarge, "avatar rejected", zap.Error(err))
+		return models.SendError(c, fiber.StatusRequestEntityTooLarge, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrAvatarType):
+		middleware.LogRejected(c, fiber.StatusUnsupportedMediaType, "avatar rejected", zap.Error(err))
+		return models.SendError(c, fiber.StatusUnsupportedMediaType, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrAvatarUploadsOff), errors.Is(err, service.ErrNoProfiles):
+		return models.SendNotFound(c, "Avatar uploads are not enabled", middleware.GetRequestID(c))
+	case err != nil:
+		middleware.GetRequestLogger(c).Error("failed to upload avatar", zap.Int64("user_id", authUser.ID), zap.Error(err))
+		return models.SendInternalError(c, "Failed to upload avatar", middleware.GetRequestID(c))
+	}
+
+	resp, err := h.service.CurrentUser(c.UserContext(), authUser.ID)
+	if err != nil {
+		middleware.LogRejected(c, fiber.StatusNotFound, "get current user failed", zap.Int64("user_id", authUser.ID), zap.Error(err))
+		return models.SendNotFound(c, "User not found", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("avatar uploaded", zap.Int64("user_id", authUser.ID), zap.Int("bytes", len(data)))
+	return c.JSON(resp)
+}
 
-	if pageStr != "" || limitStr != "" {
-		page, _ := strconv.Atoi(pageStr)
-		limit, _ := strconv.Atoi(limitStr)
+// DeleteAvatar removes the caller's uploaded avatar, so they are shown the
+// profile's avatar_url or the derived picture again.
+func (h *UserHandler) DeleteAvatar(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	if authUser == nil {
+		return models.SendUnauthorized(c, "Unauthorized", middleware.GetRequestID(c))
+	}
+	if err := h.service.RemoveAvatar(c.UserContext(), authUser.ID); err != nil {
+		middleware.GetRequestLogger(c).Error("failed to remove avatar", zap.Int64("user_id", authUser.ID), zap.Error(err))
+		return models.SendInternalError(c, "Failed to remove avatar", middleware.GetRequestID(c))
+	}
+	middleware.GetRequestLogger(c).Info("avatar removed", zap.Int64("user_id", authUser.ID))
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// HeadCurrentUser answers whether the caller's credentials are still valid.
+// It does not touch the database.
+func (h *UserHandler) HeadCurrentUser(c *fiber.Ctx) error {
+	if middleware.GetAuthUser(c) == nil {
+		return c.SendStatus(fiber.StatusUnauthorized)
+	}
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// Avatar serves the identicon avatar_url points at when Gravatar is off.
+// The picture only depends on the hash, so it can be cached for good.
+func (h *UserHandler) Avatar(c *fiber.Ctx) error {
+	svg, err := h.service.Avatars().Identicon(c.Params("hash"))
+	if err != nil {
+		return models.SendNotFound(c, "Avatar not found", middleware.GetRequestID(c))
+	}
+	c.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+	c.Set(fiber.HeaderContentType, "image/svg+xml")
+	return c.Send(svg)
+}
+
+// GetCurrentClaims returns what the caller's token says about them, without
+// a database lookup, so frontends can poll it cheaply. The ETag changes when
+// the token does.
+func (h *UserHandler) GetCurrentClaims(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	if authUser == nil {
+		return models.SendUnauthorized(c, "Unauthorized", middleware.GetRequestID(c))
+	}
 
-		if page < 1 {
-			page = 1
+	resp := models.ClaimsResponse{
+		UserID:      authUser.ID,
+		Role:        authUser.Role,
+		AccountType: authUser.AccountType,
+		Scopes:      authUser.Scopes,
+	}
+	if claims := middleware.GetJWTClaims(c); claims != nil {
+		if claims.IssuedAt != nil {
+			resp.IssuedAt = &claims.IssuedAt.Time
 		}
-		if limit < 1 {
-			limit = 10
+		if claims.ExpiresAt != nil {
+			resp.ExpiresAt = &claims.ExpiresAt.Time
+		}
+		resp.Claims = claims.Extra
+	}
+
+	body, err := json.Marshal(resp)
+	if err != nil {
+		return models.SendInternalError(c, "Failed to encode claims", middleware.GetRequestID(c))
+	}
+	sum := sha256.Sum256(body)
+	c.Set(fiber.HeaderETag, `"`+hex.EncodeToString(sum[:16])+`"`)
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	if c.Fresh() {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(body)
+}
+
+// userSearchQuery holds the filters of the user list.
+type userSearchQuery struct {
+	Name          string `query:"name"`
+	Email         string `query:"email"`
+	Role          string `query:"role" enum:"user,moderator,admin"`
+	MinAge        int    `query:"min_age" min:"0" max:"200"`
+	MaxAge        int    `query:"max_age" min:"0" max:"200"`
+	CreatedAfter  string `query:"created_after"`
+	CreatedBefore string `query:"created_before"`
+}
+
+// parseUserSearch binds the user list filters. The error is nil or
+// queryparams.Errors.
+func parseUserSearch(c *fiber.Ctx) (service.UserSearch, error) {
+	var q userSearchQuery
+	var errs queryparams.Errors
+	if err := parseQuery(c, &q); err != nil && !errors.As(err, &errs) {
+		return service.UserSearch{}, err
+	}
+
+	search := service.UserSearch{
+		Name:  strings.TrimSpace(q.Name),
+		Email: strings.TrimSpace(q.Email),
+		Role:  q.Role,
+	}
+	if c.Query("min_age") != "" && !errs.Has("min_age") {
+		search.MinAge = &q.MinAge
+	}
+	if c.Query("max_age") != "" && !errs.Has("max_age") {
+		search.MaxAge = &q.MaxAge
+	}
+	if search.MinAge != nil && search.MaxAge != nil && *search.MaxAge < *search.MinAge {
+		errs = append(errs, queryparams.FieldError{Param: "max_age", Value: c.Query("max_age"), Message: "must not be less than min_age"})
+	}
+	var fe *queryparams.FieldError
+	if search.CreatedAfter, fe = parseInstant("created_after", q.CreatedAfter); fe != nil {
+		errs = append(errs, *fe)
+	}
+	if search.CreatedBefore, fe = parseInstant("created_before", q.CreatedBefore); fe != nil {
+		errs = append(errs, *fe)
+	}
+	if len(errs) > 0 {
+		return service.UserSearch{}, errs
+	}
+	return search, nil
+}
+
+// parseInstant reads an RFC 3339 time, or a YYYY-MM-DD day meaning its
+// start in UTC. An empty raw is the zero time.
+func parseInstant(param, raw string) (time.Time, *queryparams.FieldError) {
+	if raw == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t.UTC(), nil
+	}
+	if t, err := time.Parse(service.DobLayout, raw); err == nil {
+		return t, nil
+	}
+	return time.Time{}, &queryparams.FieldError{Param: param, Value: raw, Message: "must be a YYYY-MM-DD day or an RFC 3339 time"}
+}
+
+// List returns a page of users, or every user up to the list cap when no
+// page is asked for and pagination isn't forced. Filtered and sorted lists
+// are always paged.
+func (h *UserHandler) List(c *fiber.Ctx) error {
+	search, err := parseUserSearch(c)
+	if err != nil {
+		return sendQueryError(c, err)
+	}
+	if search.Sort, err = service.ParseUserSort(c.Query("sort")); err != nil {
+		return sendSortError(c, err)
+	}
+
+	paged := wantsPage(c) || !search.IsZero()
+	if !paged {
+		middleware.MarkDeprecated(c, DeprecatedFullList)
+	}
+	if paged || h.service.AlwaysPaginate() {
+		page, limit, err := parsePagination(func(key string) string { return c.Query(key) }, h.service.DefaultPageSize(), h.service.MaxPageSize())
+		if err != nil {
+			return sendQueryError(c, err)
 		}
 
-		paginatedResp, err := h.service.ListUsersWithAgePaginated(c.Context(), page, limit)
+		var paginatedResp *models.PaginatedUsersResponse
+		if search.IsZero() {
+			paginatedResp, err = h.service.ListUsersWithAgePaginated(c.UserContext(), page, limit)
+		} else {
+			paginatedResp, err = h.service.SearchUsersWithAgePaginated(c.UserContext(), search, page, limit)
+		}
 		if err != nil {
 			middleware.GetRequestLogger(c).Error("list users paginated failed", zap.Error(err))
 			return models.SendInternalError(c, "Failed to list users", middleware.GetRequestID(c))
 		}
 
+		setLinkHeader(c, paginatedResp.Pagination)
 		return c.JSON(paginatedResp)
 	}
 
-	users, err := h.service.ListUsersWithAge(c.Context())
+	users, truncated, err := h.service.ListUsersWithAge(c.UserContext())
 	if err != nil {
 		middleware.GetRequestLogger(c).Error("list users failed", zap.Error(err))
 		return models.SendInternalError(c, "Failed to list users", middleware.GetRequestID(c))
 	}
+	if truncated {
+		c.Set(ResultTruncatedHeader, "true")
+	}
 
 	return c.JSON(users)
 }
 
 func (h *UserHandler) Update(c *fiber.Ctx) error {
-	id, err := strconv.Atoi(c.Params("id"))
-	if err != nil {
+	id, ok := middleware.GetUserID(c)
+	if !ok {
 		return models.SendBadRequest(c, "Invalid user ID", middleware.GetRequestID(c))
 	}
 
 	var req models.UserRequest
-	if err := c.BodyParser(&req); err != nil {
-		middleware.GetRequestLogger(c).Error("failed to parse request body", zap.Error(err))
-		return models.SendBadRequest(c, "Invalid request body", middleware.GetRequestID(c))
+	if err := parseBody(c, &req); err != nil {
+		middleware.LogRejected(c, fiber.StatusBadRequest, "failed to parse request body", zap.Error(err))
+		return sendBodyError(c, err)
 	}
 
 	if err := h.validate.Struct(req); err != nil {
-		middleware.GetRequestLogger(c).Error("validation failed", zap.Error(err))
+		middleware.LogRejected(c, fiber.StatusBadRequest, "validation failed", zap.Error(err))
 		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
 	}
 
-	dob, err := time.Parse("2006-01-02", req.Dob)
+	dob, err := service.ParseDob(req.Dob)
 	if err != nil {
-		middleware.GetRequestLogger(c).Error("invalid date format", zap.Error(err))
+		middleware.LogRejected(c, fiber.StatusBadRequest, "invalid date format", zap.Error(err))
 		return models.SendBadRequest(c, "Invalid date format, use YYYY-MM-DD", middleware.GetRequestID(c))
 	}
 
-	user, err := h.repo.Update(c.Context(), int32(id), req.Name, dob)
+	user, err := h.repo.Update(c.UserContext(), id, req.Name, dob)
 	if err != nil {
-		middleware.GetRequestLogger(c).Error("update user failed", zap.Error(err))
+		var rejected *hooks.RejectedError
+		if errors.As(err, &rejected) {
+			return models.SendError(c, fiber.StatusUnprocessableEntity, rejected.Reason, models.ErrCodeHookRejected, middleware.GetRequestID(c))
+		}
+		if isConstraintError(err) {
+			return sendConstraintError(c, err)
+		}
+		middleware.LogRejected(c, fiber.StatusNotFound, "update user failed", zap.Error(err))
 		return models.SendNotFound(c, "User not found", middleware.GetRequestID(c))
 	}
 
-	middleware.GetRequestLogger(c).Info("user updated", zap.Int32("id", user.ID))
+	middleware.GetRequestLogger(c).Info("user updated", zap.Int64("id", user.ID))
 
 	return c.JSON(models.UserResponse{
-		ID:   user.ID,
-		Name: user.Name,
-		Dob:  user.Dob.Time.Format("2006-01-02"),
+		ID:        user.PublicID.String(),
+		Name:      user.Name,
+		Dob:       user.Dob.Time.Format("2006-01-02"),
+		AvatarURL: h.service.Avatars().URL(user.Email, user.PublicID.String()),
 	})
 }
 
 func (h *UserHandler) Delete(c *fiber.Ctx) error {
-	id, err := strconv.Atoi(c.Params("id"))
-	if err != nil {
+	id, ok := middleware.GetUserID(c)
+	if !ok {
 		return models.SendBadRequest(c, "Invalid user ID", middleware.GetRequestID(c))
 	}
 
-	if err := h.repo.Delete(c.Context(), int32(id)); err != nil {
-		middleware.GetRequestLogger(c).Error("delete user failed", zap.Error(err))
+	if err := h.repo.SoftDelete(c.UserContext(), id); err != nil {
+		var rejected *hooks.RejectedError
+		if errors.As(err, &rejected) {
+			return models.SendError(c, fiber.StatusUnprocessableEntity, rejected.Reason, models.ErrCodeHookRejected, middleware.GetRequestID(c))
+		}
+		middleware.LogRejected(c, fiber.StatusNotFound, "delete user failed", zap.Error(err))
 		return models.SendNotFound(c, "User not found", middleware.GetRequestID(c))
 	}
 
-	middleware.GetRequestLogger(c).Info("user deleted", zap.Int("id", id))
+	middleware.GetRequestLogger(c).Info("user deleted", zap.Int64("id", id))
 
 	return c.SendStatus(204)
 }
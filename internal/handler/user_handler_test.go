@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/queryparams"
+	"BACKEND/internal/service"
+)
+
+func TestParseUserSearch(t *testing.T) {
+	var search service.UserSearch
+	var err error
+	app := fiber.New()
+	app.Get("/users", func(c *fiber.Ctx) error {
+		search, err = parseUserSearch(c)
+		return nil
+	})
+	get := func(url string) {
+		t.Helper()
+		if _, testErr := app.Test(httptest.NewRequest("GET", url, nil)); testErr != nil {
+			t.Fatalf("app.Test failed: %v", testErr)
+		}
+	}
+
+	get("/users?page=2")
+	if err != nil || !search.IsZero() {
+		t.Errorf("no filters: %+v, %v; want none", search, err)
+	}
+
+	get("/users?name=+ann+&max_age=0&created_after=2026-01-02&created_before=2026-03-01T10:00:00%2B02:00")
+	if err != nil || search.Name != "ann" || search.MinAge != nil || search.MaxAge == nil || *search.MaxAge != 0 ||
+		!search.CreatedAfter.Equal(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)) ||
+		!search.CreatedBefore.Equal(time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("filters = %+v, %v", search, err)
+	}
+
+	get("/users?role=owner&min_age=40&max_age=30&created_after=yesterday")
+	var errs queryparams.Errors
+	if !errors.As(err, &errs) || !errs.Has("role") || !errs.Has("max_age") || !errs.Has("created_after") {
+		t.Errorf("bad filters: %v; want role, max_age and created_after rejected", err)
+	}
+}
@@ -0,0 +1,213 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+	"BACKEND/internal/webauthn"
+)
+
+type WebAuthnHandler struct {
+	webauthnService *service.WebAuthnService
+	validate        *validator.Validate
+	logger          *zap.Logger
+	cookieSecure    bool
+	jwtExpiry       int
+}
+
+func NewWebAuthnHandler(webauthnService *service.WebAuthnService, logger *zap.Logger, cookieSecure bool, jwtExpirySeconds int) *WebAuthnHandler {
+	return &WebAuthnHandler{
+		webauthnService: webauthnService,
+		validate:        validator.New(),
+		logger:          logger,
+		cookieSecure:    cookieSecure,
+		jwtExpiry:       jwtExpirySeconds,
+	}
+}
+
+// RegisterBegin returns options for navigator.credentials.create() for the
+// logged-in user.
+func (h *WebAuthnHandler) RegisterBegin(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	if authUser == nil {
+		return models.SendUnauthorized(c, "Unauthorized", middleware.GetRequestID(c))
+	}
+
+	options, err := h.webauthnService.BeginRegistration(c.UserContext(), authUser.ID)
+	if err != nil {
+		return h.sendError(c, err, "Failed to start passkey registration")
+	}
+	return c.JSON(fiber.Map{"publicKey": options})
+}
+
+func (h *WebAuthnHandler) RegisterFinish(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	if authUser == nil {
+		return models.SendUnauthorized(c, "Unauthorized", middleware.GetRequestID(c))
+	}
+
+	var req models.PasskeyRegisterRequest
+	if err := parseBody(c, &req); err != nil {
+		return sendBodyError(c, err)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	cred, err := h.webauthnService.FinishRegistration(c.UserContext(), authUser.ID, req.Name, req.Credential)
+	if err != nil {
+		return h.sendError(c, err, "Failed to register passkey")
+	}
+
+	middleware.GetRequestLogger(c).Info("passkey registered",
+		zap.Int64("user_id", authUser.ID),
+		zap.Int64("passkey_id", cred.ID),
+	)
+	return c.Status(fiber.StatusCreated).JSON(passkeyResponse(cred))
+}
+
+// LoginBegin returns options for navigator.credentials.get(). No passkeys
+// are listed, so the browser offers every discoverable passkey it holds for
+// this site and the response says nothing about which accounts exist.
+func (h *WebAuthnHandler) LoginBegin(c *fiber.Ctx) error {
+	options, err := h.webauthnService.BeginLogin(c.UserContext())
+	if err != nil {
+		return h.sendError(c, err, "Failed to start passkey login")
+	}
+	return c.JSON(fiber.Map{"publicKey": options})
+}
+
+func (h *WebAuthnHandler) LoginFinish(c *fiber.Ctx) error {
+	var req webauthn.AssertionResponse
+	if err := parseBody(c, &req); err != nil {
+		return sendBodyError(c, err)
+	}
+	if err := h.validate.Struct(req); err != nil {
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeValidationFailed, middleware.GetRequestID(c))
+	}
+
+	user, token, err := h.webauthnService.FinishLogin(clientContext(c), req)
+	if err != nil {
+		return h.sendError(c, err, "Failed to authenticate user")
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "token",
+		Value:    token,
+		Path:     "/",
+		MaxAge:   h.jwtExpiry,
+		HTTPOnly: true,
+		Secure:   h.cookieSecure,
+		SameSite: "Strict",
+	})
+
+	middleware.GetRequestLogger(c).Info("user logged in with passkey",
+		zap.Int64("user_id", user.ID),
+		zap.String("email", user.Email),
+	)
+
+	var resp models.LoginResponse
+	resp.Message = "Login successful"
+	resp.User.ID = user.PublicID.String()
+	resp.User.Name = user.Name
+	resp.User.Email = user.Email
+	resp.User.Role = user.Role
+	return c.JSON(resp)
+}
+
+// List returns the caller's passkeys.
+func (h *WebAuthnHandler) List(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	if authUser == nil {
+		return models.SendUnauthorized(c, "Unauthorized", middleware.GetRequestID(c))
+	}
+
+	creds, err := h.webauthnService.ListCredentials(c.UserContext(), authUser.ID)
+	if err != nil {
+		middleware.GetRequestLogger(c).Error("failed to list passkeys", zap.Error(err))
+		return models.SendInternalError(c, "Failed to retrieve passkeys", middleware.GetRequestID(c))
+	}
+
+	passkeys := make([]models.PasskeyResponse, 0, len(creds))
+	for _, cred := range creds {
+		passkeys = append(passkeys, passkeyResponse(cred))
+	}
+	return c.JSON(models.PasskeyListResponse{Total: len(passkeys), Passkeys: passkeys})
+}
+
+func (h *WebAuthnHandler) Delete(c *fiber.Ctx) error {
+	authUser := middleware.GetAuthUser(c)
+	if authUser == nil {
+		return models.SendUnauthorized(c, "Unauthorized", middleware.GetRequestID(c))
+	}
+	id, ok := idParam(c, "id")
+	if !ok {
+		return models.SendBadRequest(c, "Invalid passkey ID", middleware.GetRequestID(c))
+	}
+
+	if err := h.webauthnService.DeleteCredential(c.UserContext(), id, authUser.ID); err != nil {
+		if errors.Is(err, service.ErrWebAuthnNotFound) {
+			return models.SendNotFound(c, "Passkey not found", middleware.GetRequestID(c))
+		}
+		middleware.GetRequestLogger(c).Error("failed to delete passkey", zap.Error(err))
+		return models.SendInternalError(c, "Failed to delete passkey", middleware.GetRequestID(c))
+	}
+
+	middleware.GetRequestLogger(c).Info("passkey deleted",
+		zap.Int64("user_id", authUser.ID),
+		zap.Int64("passkey_id", id),
+	)
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func (h *WebAuthnHandler) sendError(c *fiber.Ctx, err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrWebAuthnInvalidChallenge):
+		return models.SendError(c, fiber.StatusBadRequest, "Passkey request expired, please try again", models.ErrCodeInvalidInput, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrWebAuthnCredentialExists):
+		return models.SendError(c, fiber.StatusConflict, "Passkey is already registered", models.ErrCodeAlreadyExists, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrWebAuthnUnknownCredential),
+		errors.Is(err, service.ErrWebAuthnCloned),
+		errors.Is(err, webauthn.ErrInvalidSignature),
+		errors.Is(err, webauthn.ErrUserNotPresent),
+		errors.Is(err, webauthn.ErrUserNotVerified):
+		middleware.GetRequestLogger(c).Warn("passkey rejected", zap.Error(err))
+		return models.SendError(c, fiber.StatusUnauthorized, "Passkey could not be verified", models.ErrCodeInvalidCredentials, middleware.GetRequestID(c))
+	case errors.Is(err, webauthn.ErrInvalidResponse),
+		errors.Is(err, webauthn.ErrChallengeMismatch),
+		errors.Is(err, webauthn.ErrOriginMismatch),
+		errors.Is(err, webauthn.ErrRPIDMismatch),
+		errors.Is(err, webauthn.ErrUnsupportedKey):
+		middleware.GetRequestLogger(c).Warn("invalid passkey response", zap.Error(err))
+		return models.SendError(c, fiber.StatusBadRequest, err.Error(), models.ErrCodeInvalidInput, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrWebAuthnSSORequired):
+		return models.SendError(c, fiber.StatusForbidden, "This account must sign in with SSO", models.ErrCodeSSORequired, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrAccountDisabled):
+		return models.SendError(c, fiber.StatusForbidden, "Account is disabled", models.ErrCodeAccountDisabled, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrInteractiveLoginDenied):
+		return models.SendError(c, fiber.StatusForbidden, "Service accounts must authenticate with an API key", models.ErrCodeServiceAccount, middleware.GetRequestID(c))
+	case errors.Is(err, service.ErrSessionLimitReached):
+		return models.SendError(c, fiber.StatusConflict, "Too many active sessions; sign out of another device first", models.ErrCodeSessionLimit, middleware.GetRequestID(c))
+	}
+	middleware.GetRequestLogger(c).Error("passkey request failed", zap.Error(err))
+	return models.SendInternalError(c, message, middleware.GetRequestID(c))
+}
+
+func passkeyResponse(cred generated.WebauthnCredential) models.PasskeyResponse {
+	resp := models.PasskeyResponse{
+		ID:        cred.ID,
+		Name:      cred.Name,
+		CreatedAt: cred.CreatedAt.Time,
+	}
+	if cred.LastUsedAt.Valid {
+		resp.LastUsedAt = &cred.LastUsedAt.Time
+	}
+	return resp
+}
@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/internal/service"
+)
+
+type DigestSender struct {
+	digests  *service.DigestService
+	interval time.Duration
+	logger   *zap.Logger
+	leader   Leader
+}
+
+func NewDigestSender(digests *service.DigestService, interval time.Duration, logger *zap.Logger) *DigestSender {
+	return &DigestSender{
+		digests:  digests,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// SetLeader sends digests only from the instance that leads, so admins get
+// one per interval however many instances run.
+func (j *DigestSender) SetLeader(leader Leader) {
+	j.leader = leader
+}
+
+// Run sends a digest every interval. Unlike the other jobs it doesn't run
+// at startup, so restarts and deploys don't send extra digests.
+func (j *DigestSender) Run(ctx context.Context) {
+	if j.interval <= 0 {
+		j.logger.Info("admin digest job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !leads(j.leader) {
+				continue
+			}
+			if err := j.digests.Send(ctx); err != nil && ctx.Err() == nil {
+				j.logger.Error("failed to send admin digest", zap.Error(err))
+			}
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package jobs
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/internal/repository"
+)
+
+// leaseName is the lease the instance that runs the singleton jobs holds.
+const leaseName = "jobs"
+
+// Leader reports whether this instance should run the jobs that must run
+// only once across all instances.
+type Leader interface {
+	IsLeader() bool
+}
+
+// LeaseElector makes one instance at a time leader by holding a lease in
+// the database. The leader renews the lease every third of its TTL; if it
+// stops, another instance takes over once the lease expires.
+type LeaseElector struct {
+	store  repository.JobLeaseStore
+	holder string
+	ttl    time.Duration
+	logger *zap.Logger
+	leader atomic.Bool
+}
+
+func NewLeaseElector(store repository.JobLeaseStore, holder string, ttl time.Duration, logger *zap.Logger) *LeaseElector {
+	return &LeaseElector{
+		store:  store,
+		holder: holder,
+		ttl:    ttl,
+		logger: logger,
+	}
+}
+
+func (e *LeaseElector) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// Start tries for the lease once, so jobs started next know whether they
+// lead, then keeps trying in the background until ctx is done, when the
+// lease is given up.
+func (e *LeaseElector) Start(ctx context.Context) {
+	e.campaign(ctx)
+	go e.run(ctx)
+}
+
+func (e *LeaseElector) run(ctx context.Context) {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.resign()
+			return
+		case <-ticker.C:
+			e.campaign(ctx)
+		}
+	}
+}
+
+func (e *LeaseElector) campaign(ctx context.Context) {
+	acquired, err := e.store.Acquire(ctx, leaseName, e.holder, e.ttl)
+	if err != nil {
+		if ctx.Err() == nil {
+			e.logger.Error("failed to renew job lease", zap.Error(err))
+		}
+		// The lease may lapse before the next try succeeds, and another
+		// instance take over.
+		acquired = false
+	}
+	if was := e.leader.Swap(acquired); was != acquired {
+		e.logger.Info("job leadership changed", zap.Bool("leader", acquired), zap.String("holder", e.holder))
+	}
+}
+
+func (e *LeaseElector) resign() {
+	if !e.leader.Swap(false) {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := e.store.Release(ctx, leaseName, e.holder); err != nil {
+		e.logger.Warn("failed to release job lease", zap.Error(err))
+	}
+}
+
+// leads reports whether a job given leader should run now. Without a
+// leader every instance runs it.
+func leads(leader Leader) bool {
+	return leader == nil || leader.IsLeader()
+}
@@ -0,0 +1,83 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// fakeLeaseStore hands the lease to the first holder to ask until it is
+// released or expire is set.
+type fakeLeaseStore struct {
+	holder string
+	expire bool
+	err    error
+}
+
+func (f *fakeLeaseStore) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	if f.err != nil {
+		return false, f.err
+	}
+	if f.holder == "" || f.holder == holder || f.expire {
+		f.holder, f.expire = holder, false
+	}
+	return f.holder == holder, nil
+}
+
+func (f *fakeLeaseStore) Release(ctx context.Context, name, holder string) error {
+	if f.holder == holder {
+		f.holder = ""
+	}
+	return nil
+}
+
+func TestLeaseElector(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeLeaseStore{}
+	a := NewLeaseElector(store, "a", time.Minute, zap.NewNop())
+	b := NewLeaseElector(store, "b", time.Minute, zap.NewNop())
+
+	a.campaign(ctx)
+	b.campaign(ctx)
+	if !a.IsLeader() || b.IsLeader() {
+		t.Fatalf("expected only a to lead, got a=%v b=%v", a.IsLeader(), b.IsLeader())
+	}
+
+	store.err = errors.New("db down")
+	a.campaign(ctx)
+	if a.IsLeader() {
+		t.Error("a still leads after failing to renew")
+	}
+	store.err = nil
+	a.campaign(ctx)
+
+	// a stops renewing and its lease expires.
+	store.expire = true
+	b.campaign(ctx)
+	if !b.IsLeader() {
+		t.Error("b didn't take over the expired lease")
+	}
+	a.campaign(ctx)
+	if a.IsLeader() {
+		t.Error("a leads alongside b")
+	}
+
+	b.resign()
+	if b.IsLeader() || store.holder != "" {
+		t.Errorf("b kept the lease after resigning: %q", store.holder)
+	}
+}
+
+func TestLeads(t *testing.T) {
+	if !leads(nil) {
+		t.Error("jobs without a leader should run")
+	}
+	e := NewLeaseElector(&fakeLeaseStore{holder: "other"}, "me", time.Minute, zap.NewNop())
+	e.campaign(context.Background())
+	if leads(e) {
+		t.Error("a job ran on an instance that doesn't lead")
+	}
+}
@@ -0,0 +1,56 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/internal/service"
+)
+
+type RetentionPruner struct {
+	retention *service.RetentionService
+	interval  time.Duration
+	logger    *zap.Logger
+	leader    Leader
+}
+
+func NewRetentionPruner(retention *service.RetentionService, interval time.Duration, logger *zap.Logger) *RetentionPruner {
+	return &RetentionPruner{
+		retention: retention,
+		interval:  interval,
+		logger:    logger,
+	}
+}
+
+// SetLeader leaves pruning to the instance that leads.
+func (j *RetentionPruner) SetLeader(leader Leader) {
+	j.leader = leader
+}
+
+func (j *RetentionPruner) Run(ctx context.Context) {
+	if j.interval <= 0 {
+		j.logger.Info("retention pruning job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.prune(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.prune(ctx)
+		}
+	}
+}
+
+func (j *RetentionPruner) prune(ctx context.Context) {
+	if leads(j.leader) {
+		j.retention.Prune(ctx)
+	}
+}
@@ -0,0 +1,64 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/internal/repository"
+)
+
+type StatsRefresher struct {
+	repo     repository.UserStore
+	interval time.Duration
+	logger   *zap.Logger
+	leader   Leader
+}
+
+func NewStatsRefresher(repo repository.UserStore, interval time.Duration, logger *zap.Logger) *StatsRefresher {
+	return &StatsRefresher{
+		repo:     repo,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// SetLeader skips refreshes on instances that don't lead.
+func (j *StatsRefresher) SetLeader(leader Leader) {
+	j.leader = leader
+}
+
+func (j *StatsRefresher) Run(ctx context.Context) {
+	if j.interval <= 0 {
+		j.logger.Info("stats refresh job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.refresh(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.refresh(ctx)
+		}
+	}
+}
+
+func (j *StatsRefresher) refresh(ctx context.Context) {
+	if !leads(j.leader) {
+		return
+	}
+	start := time.Now()
+	if err := j.repo.RefreshStats(ctx); err != nil {
+		if ctx.Err() == nil {
+			j.logger.Error("failed to refresh user stats", zap.Error(err))
+		}
+		return
+	}
+	j.logger.Debug("user stats refreshed", zap.Duration("duration", time.Since(start)))
+}
@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/internal/service"
+)
+
+type TokenRevocationRefresher struct {
+	revocations *service.TokenRevocationService
+	interval    time.Duration
+	logger      *zap.Logger
+}
+
+func NewTokenRevocationRefresher(revocations *service.TokenRevocationService, interval time.Duration, logger *zap.Logger) *TokenRevocationRefresher {
+	return &TokenRevocationRefresher{
+		revocations: revocations,
+		interval:    interval,
+		logger:      logger,
+	}
+}
+
+// Run loads revocations once, then reloads them every interval. With a zero
+// interval only the initial load happens, which is enough for a single
+// instance since revocations made locally apply immediately.
+func (j *TokenRevocationRefresher) Run(ctx context.Context) {
+	j.refresh(ctx)
+	if j.interval <= 0 {
+		j.logger.Info("token revocation refresh job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.refresh(ctx)
+		}
+	}
+}
+
+func (j *TokenRevocationRefresher) refresh(ctx context.Context) {
+	if err := j.revocations.Refresh(ctx); err != nil {
+		if ctx.Err() == nil {
+			j.logger.Error("failed to refresh token revocations", zap.Error(err))
+		}
+	}
+}
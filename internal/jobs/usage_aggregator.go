@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/internal/service"
+)
+
+type UsageAggregator struct {
+	orgs     *service.OrganizationService
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+func NewUsageAggregator(orgs *service.OrganizationService, interval time.Duration, logger *zap.Logger) *UsageAggregator {
+	return &UsageAggregator{
+		orgs:     orgs,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+func (j *UsageAggregator) Run(ctx context.Context) {
+	if j.interval <= 0 {
+		j.logger.Info("organization usage job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.aggregate(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.aggregate(ctx)
+		}
+	}
+}
+
+func (j *UsageAggregator) aggregate(ctx context.Context) {
+	start := time.Now()
+	if err := j.orgs.Aggregate(ctx); err != nil {
+		if ctx.Err() == nil {
+			j.logger.Error("failed to aggregate organization usage", zap.Error(err))
+		}
+		return
+	}
+	j.logger.Debug("organization usage aggregated", zap.Duration("duration", time.Since(start)))
+}
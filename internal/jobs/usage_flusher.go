@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/internal/service"
+)
+
+type UsageFlusher struct {
+	metering *service.MeteringService
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+func NewUsageFlusher(metering *service.MeteringService, interval time.Duration, logger *zap.Logger) *UsageFlusher {
+	return &UsageFlusher{
+		metering: metering,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+func (j *UsageFlusher) Run(ctx context.Context) {
+	if j.interval <= 0 {
+		j.logger.Info("api usage flush job disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	j.flush(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.flush(ctx)
+		}
+	}
+}
+
+func (j *UsageFlusher) flush(ctx context.Context) {
+	start := time.Now()
+	if err := j.metering.Flush(ctx); err != nil {
+		if ctx.Err() == nil {
+			j.logger.Error("failed to flush api usage", zap.Error(err))
+		}
+		return
+	}
+	j.logger.Debug("api usage flushed", zap.Duration("duration", time.Since(start)))
+}
@@ -0,0 +1,314 @@
+// Package jsonbody decodes JSON request bodies and, when that fails, says
+// where and why in terms a client developer can act on.
+package jsonbody
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Error describes a body that could not be decoded. Offset is the byte
+// offset of the problem; Line and Column are 1-based.
+type Error struct {
+	Message string `json:"-"`
+	Offset  int64  `json:"offset"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Field   string `json:"field,omitempty"`
+	Hint    string `json:"hint,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at line %d, column %d", e.Message, e.Line, e.Column)
+}
+
+func Decode(data []byte, v interface{}) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return &Error{Message: "request body is empty", Line: 1, Column: 1, Hint: "send a JSON object"}
+	}
+
+	err := json.Unmarshal(data, v)
+	if err == nil {
+		return nil
+	}
+
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return locate(data, syntaxErr.Offset, &Error{
+			Message: syntaxErr.Error(),
+			Hint:    syntaxHint(data, syntaxErr),
+		})
+	case errors.As(err, &typeErr):
+		want := jsonType(typeErr.Type)
+		e := &Error{
+			Message: fmt.Sprintf("field %q must be %s, got %s", typeErr.Field, withArticle(want), typeErr.Value),
+			Field:   typeErr.Field,
+		}
+		if want == "string" && typeErr.Value == "number" {
+			e.Hint = "wrap the value in double quotes"
+		}
+		return locate(data, typeErr.Offset, e)
+	}
+	return err
+}
+
+// locate fills in the line and column for an offset. encoding/json reports
+// the offset just past the offending byte, which is where the column
+// points.
+func locate(data []byte, offset int64, e *Error) *Error {
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	e.Offset = offset
+
+	before := data[:offset]
+	e.Line = bytes.Count(before, []byte("\n")) + 1
+	e.Column = int(offset) - bytes.LastIndexByte(before, '\n') - 1
+	if e.Column < 1 {
+		e.Column = 1
+	}
+	return e
+}
+
+func syntaxHint(data []byte, err *json.SyntaxError) string {
+	msg := err.Error()
+	if msg == "unexpected end of JSON input" {
+		return "the body ends early; check for a missing closing brace, bracket or quote"
+	}
+
+	var bad byte
+	if err.Offset > 0 && err.Offset <= int64(len(data)) {
+		bad = data[err.Offset-1]
+	}
+	prev := lastNonSpace(data, err.Offset-1)
+
+	switch {
+	case (bad == '}' || bad == ']') && prev == ',':
+		return fmt.Sprintf("remove the trailing comma before '%c'", bad)
+	case bad == '\'':
+		return "strings and keys must use double quotes"
+	case strings.Contains(msg, "looking for beginning of object key string"):
+		return "object keys must be double-quoted strings"
+	case strings.Contains(msg, "after object key:value pair"), strings.Contains(msg, "after array element"):
+		return "add a comma between elements"
+	case strings.Contains(msg, "after object key"):
+		return "add a colon between the key and its value"
+	case strings.Contains(msg, "after top-level value"):
+		return "send a single JSON value; remove the content after it"
+	case strings.Contains(msg, "in string literal"):
+		return "escape control characters such as newlines and tabs inside strings"
+	}
+	return ""
+}
+
+// lastNonSpace returns the last non-whitespace byte before end.
+func lastNonSpace(data []byte, end int64) byte {
+	for i := end - 1; i >= 0 && i < int64(len(data)); i-- {
+		switch data[i] {
+		case ' ', '\t', '\n', '\r':
+			continue
+		}
+		return data[i]
+	}
+	return 0
+}
+
+func jsonType(t reflect.Type) string {
+	if t == nil {
+		return "a value"
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	case reflect.Ptr:
+		return jsonType(t.Elem())
+	}
+	return t.String()
+}
+
+func withArticle(s string) string {
+	switch s {
+	case "array", "object":
+		return "an " + s
+	case "a value":
+		return s
+	}
+	return "a " + s
+}
+
+// UnknownFieldsError lists keys in a body that match no field of the target,
+// with a likely intended field for each where one is close enough.
+type UnknownFieldsError struct {
+	Fields      []string          `json:"unknown_fields"`
+	Suggestions map[string]string `json:"suggestions,omitempty"`
+}
+
+func (e *UnknownFieldsError) Error() string {
+	return "unknown fields: " + strings.Join(e.Fields, ", ")
+}
+
+// DecodeStrict is Decode, but also rejects keys that do not map to a field.
+// Unlike json.Decoder.DisallowUnknownFields it reports every unknown key,
+// including ones in nested objects, not just the first.
+func DecodeStrict(data []byte, v interface{}) error {
+	if err := Decode(data, v); err != nil {
+		return err
+	}
+
+	e := &UnknownFieldsError{Suggestions: make(map[string]string)}
+	collectUnknown(data, reflect.TypeOf(v), "", e)
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	if len(e.Suggestions) == 0 {
+		e.Suggestions = nil
+	}
+	return e
+}
+
+func collectUnknown(data []byte, t reflect.Type, prefix string, e *UnknownFieldsError) {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	// Types with their own decoding, like time.Time, define their own shape.
+	if t == nil || reflect.PointerTo(t).Implements(unmarshalerType) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(data, &obj) != nil {
+			return
+		}
+		fields := structFields(t)
+
+		keys := make([]string, 0, len(obj))
+		for k := range obj {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			field, ok := matchField(fields, key)
+			if !ok {
+				e.Fields = append(e.Fields, prefix+key)
+				if s := suggest(fields, key); s != "" {
+					e.Suggestions[prefix+key] = prefix + s
+				}
+				continue
+			}
+			collectUnknown(obj[key], field.Type, prefix+key+".", e)
+		}
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(data, &items) != nil {
+			return
+		}
+		for i, item := range items {
+			collectUnknown(item, t.Elem(), fmt.Sprintf("%s[%d].", strings.TrimSuffix(prefix, "."), i), e)
+		}
+	}
+}
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+type structField struct {
+	Name string
+	Type reflect.Type
+}
+
+// structFields returns the JSON names of t's fields the way encoding/json
+// sees them, including promoted fields of embedded structs.
+func structFields(t reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		ft := f.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			fields = append(fields, structFields(ft)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, structField{Name: name, Type: f.Type})
+	}
+	return fields
+}
+
+// matchField matches case-insensitively, as encoding/json does.
+func matchField(fields []structField, key string) (structField, bool) {
+	for _, f := range fields {
+		if strings.EqualFold(f.Name, key) {
+			return f, true
+		}
+	}
+	return structField{}, false
+}
+
+func suggest(fields []structField, key string) string {
+	best, bestDist := "", 3
+	for _, f := range fields {
+		if d := editDistance(strings.ToLower(key), strings.ToLower(f.Name)); d < bestDist {
+			best, bestDist = f.Name, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Damerau-Levenshtein distance restricted to adjacent
+// transpositions, so "emial" is one edit from "email".
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	d := make([][]int, len(ra)+1)
+	for i := range d {
+		d[i] = make([]int, len(rb)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+	return d[len(ra)][len(rb)]
+}
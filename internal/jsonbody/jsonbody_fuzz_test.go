@@ -0,0 +1,66 @@
+package jsonbody
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"BACKEND/internal/models"
+)
+
+var requestSeeds = []string{
+	`{"name": "Jane Doe", "email": "jane@example.com", "password": "Str0ng!Pass", "dob": "1990-05-10"}`,
+	`{"email": "jane@example.com", "password": "x"}`,
+	`{"name": "Jane", "email": "jane@example.com",}`,
+	`{"dob": 19900510}`,
+	`{"emial": "jane@example.com", "extra": {"nested": [1, 2, {"deep": null}]}}`,
+	"{\n\t\"name\": \"J\xff\xfe\"\n}",
+	`[]`,
+	`null`,
+	``,
+	`{`,
+	`"`,
+}
+
+// fuzzDecode checks that malformed bodies produce a located *Error instead
+// of panicking, and that bodies which decode survive a round trip.
+func fuzzDecode[T comparable](t *testing.T, data []byte) {
+	var v T
+	err := Decode(data, &v)
+	var bodyErr *Error
+	switch {
+	case err == nil:
+		out, merr := json.Marshal(v)
+		if merr != nil {
+			t.Fatalf("Marshal of decoded value: %v", merr)
+		}
+		var again T
+		if err := Decode(out, &again); err != nil || again != v {
+			t.Fatalf("round trip of %q = %+v, %v; want %+v", out, again, err, v)
+		}
+	case errors.As(err, &bodyErr):
+		if bodyErr.Line < 1 || bodyErr.Column < 1 || bodyErr.Offset < 0 || bodyErr.Offset > int64(len(data)) {
+			t.Fatalf("error %+v is outside the %d byte body", bodyErr, len(data))
+		}
+		_ = bodyErr.Error()
+	}
+
+	var strict T
+	if err := DecodeStrict(data, &strict); err != nil {
+		_ = err.Error()
+	}
+}
+
+func FuzzDecodeSignupRequest(f *testing.F) {
+	for _, seed := range requestSeeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(fuzzDecode[models.SignupRequest])
+}
+
+func FuzzDecodeLoginRequest(f *testing.F) {
+	for _, seed := range requestSeeds {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(fuzzDecode[models.LoginRequest])
+}
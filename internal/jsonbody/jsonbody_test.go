@@ -0,0 +1,123 @@
+package jsonbody
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type payload struct {
+	Name string   `json:"name"`
+	Age  int      `json:"age"`
+	Tags []string `json:"tags"`
+}
+
+func TestDecodeValid(t *testing.T) {
+	var p payload
+	if err := Decode([]byte(`{"name": "Ann", "age": 30}`), &p); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if p.Name != "Ann" || p.Age != 30 {
+		t.Errorf("unexpected result: %+v", p)
+	}
+}
+
+func TestDecodeDiagnostics(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		line   int
+		column int
+		field  string
+		hint   string
+	}{
+		{"empty", "  ", 1, 1, "", "send a JSON object"},
+		{"trailing comma in object", "{\n  \"name\": \"Ann\",\n}", 3, 1, "", "trailing comma"},
+		{"trailing comma in array", `{"tags": ["a", "b",]}`, 1, 20, "", "trailing comma"},
+		{"single quotes", `{'name': "Ann"}`, 1, 2, "", "double quotes"},
+		{"unquoted key", `{name: "Ann"}`, 1, 2, "", "keys must be double-quoted"},
+		{"missing comma", "{\"name\": \"Ann\"\n \"age\": 3}", 2, 2, "", "comma"},
+		{"truncated", `{"name": "Ann"`, 1, 14, "", "ends early"},
+		{"trailing content", `{"name": "Ann"} x`, 1, 17, "", "single JSON value"},
+		{"number for string", `{"name": 42}`, 1, 11, "name", "double quotes"},
+		{"string for number", `{"age": "thirty"}`, 1, 16, "age", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p payload
+			err := Decode([]byte(tt.body), &p)
+
+			var bodyErr *Error
+			if !errors.As(err, &bodyErr) {
+				t.Fatalf("expected *Error, got %v", err)
+			}
+			if bodyErr.Line != tt.line || bodyErr.Column != tt.column {
+				t.Errorf("position = %d:%d, expected %d:%d (%s)", bodyErr.Line, bodyErr.Column, tt.line, tt.column, bodyErr.Message)
+			}
+			if bodyErr.Field != tt.field {
+				t.Errorf("field = %q, expected %q", bodyErr.Field, tt.field)
+			}
+			if tt.hint != "" && !strings.Contains(bodyErr.Hint, tt.hint) {
+				t.Errorf("hint = %q, expected it to mention %q", bodyErr.Hint, tt.hint)
+			}
+		})
+	}
+}
+
+func TestDecodeTypeErrorMessage(t *testing.T) {
+	var p payload
+	err := Decode([]byte(`{"tags": "a"}`), &p)
+	if err == nil || !strings.Contains(err.Error(), `field "tags" must be an array, got string`) {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+type nested struct {
+	Email   string    `json:"email"`
+	Profile payload   `json:"profile"`
+	Items   []payload `json:"items"`
+}
+
+func TestDecodeStrict(t *testing.T) {
+	var n nested
+	if err := DecodeStrict([]byte(`{"EMAIL": "a@example.com", "profile": {"name": "Ann"}}`), &n); err != nil {
+		t.Fatalf("expected keys to match case-insensitively, got %v", err)
+	}
+
+	err := DecodeStrict([]byte(`{"emial": "a@example.com", "profile": {"nmae": "Ann", "colour": "red"}, "items": [{"age": 1}, {"tag": "x"}]}`), &n)
+
+	var unknownErr *UnknownFieldsError
+	if !errors.As(err, &unknownErr) {
+		t.Fatalf("expected *UnknownFieldsError, got %v", err)
+	}
+
+	expected := []string{"emial", "items[1].tag", "profile.colour", "profile.nmae"}
+	if strings.Join(unknownErr.Fields, ",") != strings.Join(expected, ",") {
+		t.Errorf("fields = %v, expected %v", unknownErr.Fields, expected)
+	}
+
+	suggestions := map[string]string{
+		"emial":        "email",
+		"profile.nmae": "profile.name",
+		"items[1].tag": "items[1].tags",
+	}
+	for key, want := range suggestions {
+		if got := unknownErr.Suggestions[key]; got != want {
+			t.Errorf("suggestion for %q = %q, expected %q", key, got, want)
+		}
+	}
+	if _, ok := unknownErr.Suggestions["profile.colour"]; ok {
+		t.Error("expected no suggestion for a key unlike any field")
+	}
+}
+
+func TestDecodeStrictSkipsCustomUnmarshalers(t *testing.T) {
+	var v struct {
+		At time.Time `json:"at"`
+	}
+	if err := DecodeStrict([]byte(`{"at": "2024-01-02T03:04:05Z"}`), &v); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -1,8 +1,25 @@
-package logger
-
-import "go.uber.org/zap"
-
-func New() *zap.Logger {
-	log, _ := zap.NewProduction()
-	return log
-}
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+func New(level, format string, stackTraces bool) *zap.Logger {
+	cfg := zap.NewProductionConfig()
+	if format == "console" {
+		cfg.Encoding = "console"
+		cfg.EncoderConfig = zap.NewDevelopmentEncoderConfig()
+	}
+
+	if lvl, err := zapcore.ParseLevel(level); err == nil {
+		cfg.Level = zap.NewAtomicLevelAt(lvl)
+	}
+	cfg.DisableStacktrace = !stackTraces
+
+	log, err := cfg.Build()
+	if err != nil {
+		log, _ = zap.NewProduction()
+	}
+	return log
+}
@@ -0,0 +1,115 @@
+// Package mailer delivers rendered emails.
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/internal/templates"
+)
+
+type Mailer interface {
+	Send(ctx context.Context, to []string, email *templates.Email) error
+	Driver() string
+}
+
+type SMTPConfig struct {
+	Addr     string
+	Username string
+	Password string
+	From     string
+}
+
+// SMTP sends mail through a relay. It authenticates with PLAIN auth when a
+// username is set; net/smtp refuses that on a connection without TLS unless
+// the relay is on localhost.
+type SMTP struct {
+	cfg SMTPConfig
+}
+
+func NewSMTP(cfg SMTPConfig) *SMTP {
+	return &SMTP{cfg: cfg}
+}
+
+func (m *SMTP) Driver() string {
+	return "smtp"
+}
+
+func (m *SMTP) Send(ctx context.Context, to []string, email *templates.Email) error {
+	var auth smtp.Auth
+	if m.cfg.Username != "" {
+		host, _, err := net.SplitHostPort(m.cfg.Addr)
+		if err != nil {
+			return fmt.Errorf("invalid smtp address %q: %w", m.cfg.Addr, err)
+		}
+		auth = smtp.PlainAuth("", m.cfg.Username, m.cfg.Password, host)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(m.cfg.Addr, auth, m.cfg.From, to, message(m.cfg.From, to, email))
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send email: %w", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Permanent reports whether err is a failure sending the same email again
+// won't fix: the relay answered with a 5xx reply, such as an unknown
+// recipient or a rejected message. Anything else, like a dropped
+// connection or a 4xx reply, may go through on a later try.
+func Permanent(err error) bool {
+	var reply *textproto.Error
+	return errors.As(err, &reply) && reply.Code >= 500 && reply.Code < 600
+}
+
+func message(from string, to []string, email *templates.Email) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", email.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(email.HTML)
+	return []byte(b.String())
+}
+
+// Log is used when no SMTP relay is configured. It logs who would have
+// received which email instead of sending it.
+type Log struct {
+	logger *zap.Logger
+}
+
+func NewLog(logger *zap.Logger) *Log {
+	return &Log{logger: logger}
+}
+
+func (m *Log) Driver() string {
+	return "log"
+}
+
+func (m *Log) Send(ctx context.Context, to []string, email *templates.Email) error {
+	m.logger.Info("email not sent: no smtp relay configured",
+		zap.Strings("to", to),
+		zap.String("subject", email.Subject),
+	)
+	return nil
+}
@@ -0,0 +1,32 @@
+package mailer
+
+import (
+	"strings"
+	"testing"
+
+	"BACKEND/internal/templates"
+)
+
+func TestMessage(t *testing.T) {
+	msg := string(message("alerts@acme.test", []string{"a@acme.test", "b@acme.test"}, &templates.Email{
+		Subject: "Alerta de seguridad",
+		HTML:    "<p>body</p>",
+	}))
+
+	for _, want := range []string{
+		"From: alerts@acme.test\r\n",
+		"To: a@acme.test, b@acme.test\r\n",
+		"Subject: Alerta de seguridad\r\n",
+		"Content-Type: text/html; charset=UTF-8\r\n",
+		"\r\n\r\n<p>body</p>",
+	} {
+		if !strings.Contains(msg, want) {
+			t.Errorf("message is missing %q:\n%s", want, msg)
+		}
+	}
+
+	msg = string(message("a@acme.test", []string{"b@acme.test"}, &templates.Email{Subject: "Café ☕"}))
+	if !strings.Contains(msg, "Subject: =?utf-8?q?") {
+		t.Errorf("expected a non-ASCII subject to be encoded:\n%s", msg)
+	}
+}
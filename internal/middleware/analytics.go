@@ -0,0 +1,277 @@
+package middleware
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// analyticsSlots is how many slots the rolling window is split into; the
+// oldest slot is dropped as a new one starts.
+const analyticsSlots = 60
+
+// latencyBoundsMs are the upper bounds of the latency histogram buckets.
+// Percentiles are reported as the bound of the bucket they fall in.
+var latencyBoundsMs = [...]float64{1, 2, 5, 10, 20, 50, 100, 200, 500, 1000, 2000, 5000, 10000}
+
+// OtherClient collects the calls of clients seen after a slot reached its
+// series cap.
+const OtherClient = "other"
+
+// EndpointAnalytics counts calls and latencies per route and client over a
+// rolling window, in memory on each instance. Clients are API keys,
+// service accounts and users, or "anonymous".
+type EndpointAnalytics struct {
+	window    time.Duration
+	slot      time.Duration
+	maxSeries int
+	now       func() time.Time
+
+	mu    sync.Mutex
+	slots [analyticsSlots]analyticsSlot
+}
+
+type analyticsSlot struct {
+	start  time.Time
+	series map[analyticsKey]*latencyHistogram
+}
+
+type analyticsKey struct {
+	method, route, client string
+}
+
+type latencyHistogram struct {
+	calls        uint64
+	clientErrors uint64
+	serverErrors uint64
+	totalMs      float64
+	maxMs        float64
+	buckets      [len(latencyBoundsMs) + 1]uint64
+}
+
+// EndpointStats summarises the calls to one route over the window, overall
+// and per client.
+type EndpointStats struct {
+	Method string `json:"method"`
+	Route  string `json:"route"`
+	LatencyStats
+	Clients []ClientStats `json:"clients"`
+}
+
+type ClientStats struct {
+	Client string `json:"client"`
+	LatencyStats
+}
+
+type LatencyStats struct {
+	Calls        uint64  `json:"calls"`
+	ClientErrors uint64  `json:"client_errors"`
+	ServerErrors uint64  `json:"server_errors"`
+	AvgMs        float64 `json:"avg_ms"`
+	P50Ms        float64 `json:"p50_ms"`
+	P95Ms        float64 `json:"p95_ms"`
+	P99Ms        float64 `json:"p99_ms"`
+	MaxMs        float64 `json:"max_ms"`
+}
+
+// NewEndpointAnalytics keeps the last window of calls. Each slot of the
+// window tracks at most maxSeries route and client pairs, to bound memory;
+// zero means no cap.
+func NewEndpointAnalytics(window time.Duration, maxSeries int) *EndpointAnalytics {
+	slot := window / analyticsSlots
+	if slot <= 0 {
+		slot = time.Second
+	}
+	return &EndpointAnalytics{
+		window:    window,
+		slot:      slot,
+		maxSeries: maxSeries,
+		now:       time.Now,
+	}
+}
+
+func (a *EndpointAnalytics) Window() time.Duration {
+	return a.window
+}
+
+// TrackEndpoints records the route, client, status and latency of every
+// request. Nil analytics track nothing.
+func TrackEndpoints(a *EndpointAnalytics) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if a == nil {
+			return c.Next()
+		}
+		start := time.Now()
+		err := c.Next()
+		a.record(c.Method(), c.Route().Path, analyticsClient(c), responseStatus(c, err), time.Since(start))
+		return err
+	}
+}
+
+func analyticsClient(c *fiber.Ctx) string {
+	user := GetAuthUser(c)
+	switch {
+	case user == nil:
+		return "anonymous"
+	case user.APIKeyID != 0:
+		return fmt.Sprintf("api_key:%d", user.APIKeyID)
+	case user.AccountType == "service":
+		return fmt.Sprintf("service:%d", user.ID)
+	}
+	return fmt.Sprintf("user:%d", user.ID)
+}
+
+func (a *EndpointAnalytics) record(method, route, client string, status int, latency time.Duration) {
+	ms := float64(latency) / float64(time.Millisecond)
+	bucket := sort.SearchFloat64s(latencyBoundsMs[:], ms)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	slot := a.currentSlot()
+	key := analyticsKey{method: method, route: route, client: client}
+	h := slot.series[key]
+	if h == nil {
+		if a.maxSeries > 0 && len(slot.series) >= a.maxSeries {
+			key.client = OtherClient
+			h = slot.series[key]
+		}
+		if h == nil {
+			h = &latencyHistogram{}
+			slot.series[key] = h
+		}
+	}
+	h.calls++
+	h.totalMs += ms
+	h.buckets[bucket]++
+	if ms > h.maxMs {
+		h.maxMs = ms
+	}
+	switch {
+	case status >= fiber.StatusInternalServerError:
+		h.serverErrors++
+	case status >= fiber.StatusBadRequest:
+		h.clientErrors++
+	}
+}
+
+// currentSlot returns the slot for now, clearing it if it last held an
+// older part of the window. a.mu must be held.
+func (a *EndpointAnalytics) currentSlot() *analyticsSlot {
+	start := a.now().Truncate(a.slot)
+	slot := &a.slots[(start.UnixNano()/int64(a.slot))%analyticsSlots]
+	if !slot.start.Equal(start) {
+		slot.start = start
+		slot.series = make(map[analyticsKey]*latencyHistogram)
+	}
+	return slot
+}
+
+// Stats returns the routes called within the window, busiest first, each
+// with its clients, busiest first. An empty route or client matches all.
+func (a *EndpointAnalytics) Stats(route, client string) []EndpointStats {
+	since := a.now().Add(-a.window)
+	type endpointKey struct{ method, route string }
+	endpoints := make(map[endpointKey]*latencyHistogram)
+	clients := make(map[endpointKey]map[string]*latencyHistogram)
+
+	a.mu.Lock()
+	for i := range a.slots {
+		slot := &a.slots[i]
+		if slot.series == nil || !slot.start.After(since) {
+			continue
+		}
+		for key, h := range slot.series {
+			if (route != "" && key.route != route) || (client != "" && key.client != client) {
+				continue
+			}
+			ek := endpointKey{method: key.method, route: key.route}
+			if endpoints[ek] == nil {
+				endpoints[ek] = &latencyHistogram{}
+				clients[ek] = make(map[string]*latencyHistogram)
+			}
+			endpoints[ek].add(h)
+			if clients[ek][key.client] == nil {
+				clients[ek][key.client] = &latencyHistogram{}
+			}
+			clients[ek][key.client].add(h)
+		}
+	}
+	a.mu.Unlock()
+
+	stats := make([]EndpointStats, 0, len(endpoints))
+	for ek, h := range endpoints {
+		es := EndpointStats{Method: ek.method, Route: ek.route, LatencyStats: h.stats()}
+		for name, ch := range clients[ek] {
+			es.Clients = append(es.Clients, ClientStats{Client: name, LatencyStats: ch.stats()})
+		}
+		sort.Slice(es.Clients, func(i, j int) bool {
+			if es.Clients[i].Calls != es.Clients[j].Calls {
+				return es.Clients[i].Calls > es.Clients[j].Calls
+			}
+			return es.Clients[i].Client < es.Clients[j].Client
+		})
+		stats = append(stats, es)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Calls != stats[j].Calls {
+			return stats[i].Calls > stats[j].Calls
+		}
+		if stats[i].Route != stats[j].Route {
+			return stats[i].Route < stats[j].Route
+		}
+		return stats[i].Method < stats[j].Method
+	})
+	return stats
+}
+
+func (h *latencyHistogram) add(o *latencyHistogram) {
+	h.calls += o.calls
+	h.clientErrors += o.clientErrors
+	h.serverErrors += o.serverErrors
+	h.totalMs += o.totalMs
+	if o.maxMs > h.maxMs {
+		h.maxMs = o.maxMs
+	}
+	for i, n := range o.buckets {
+		h.buckets[i] += n
+	}
+}
+
+func (h *latencyHistogram) stats() LatencyStats {
+	s := LatencyStats{
+		Calls:        h.calls,
+		ClientErrors: h.clientErrors,
+		ServerErrors: h.serverErrors,
+		MaxMs:        h.maxMs,
+	}
+	if h.calls > 0 {
+		s.AvgMs = h.totalMs / float64(h.calls)
+		s.P50Ms = h.percentile(0.50)
+		s.P95Ms = h.percentile(0.95)
+		s.P99Ms = h.percentile(0.99)
+	}
+	return s
+}
+
+// percentile returns the upper bound of the bucket holding the p-th
+// fraction of calls, capped at the slowest call.
+func (h *latencyHistogram) percentile(p float64) float64 {
+	rank := uint64(p*float64(h.calls) + 0.5)
+	if rank < 1 {
+		rank = 1
+	}
+	var seen uint64
+	for i, n := range h.buckets {
+		seen += n
+		if seen >= rank {
+			if i < len(latencyBoundsMs) && latencyBoundsMs[i] < h.maxMs {
+				return latencyBoundsMs[i]
+			}
+			return h.maxMs
+		}
+	}
+	return h.maxMs
+}
@@ -0,0 +1,99 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/models"
+)
+
+func TestEndpointAnalytics(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	a := NewEndpointAnalytics(time.Hour, 0)
+	a.now = func() time.Time { return now }
+
+	app := fiber.New()
+	app.Use(TrackEndpoints(a))
+	app.Use(func(c *fiber.Ctx) error {
+		if key := c.Get("X-Key"); key != "" {
+			c.Locals(AuthUserKey, models.AuthUser{ID: 3, AccountType: "service", APIKeyID: 9})
+		}
+		return c.Next()
+	})
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		if c.Params("id") == "missing" {
+			return fiber.ErrNotFound
+		}
+		return c.SendString("ok")
+	})
+
+	get := func(path string, key bool) {
+		req := httptest.NewRequest("GET", path, nil)
+		if key {
+			req.Header.Set("X-Key", "1")
+		}
+		if _, err := app.Test(req); err != nil {
+			t.Fatal(err)
+		}
+	}
+	get("/users/1", true)
+	get("/users/2", true)
+	get("/users/missing", false)
+
+	stats := a.Stats("", "")
+	if len(stats) != 1 {
+		t.Fatalf("got %d endpoints, want the one route: %+v", len(stats), stats)
+	}
+	users := stats[0]
+	if users.Route != "/users/:id" || users.Calls != 3 || users.ClientErrors != 1 {
+		t.Errorf("endpoint = %+v", users)
+	}
+	if len(users.Clients) != 2 || users.Clients[0].Client != "api_key:9" || users.Clients[0].Calls != 2 || users.Clients[1].Client != "anonymous" {
+		t.Errorf("clients = %+v", users.Clients)
+	}
+	if users.P99Ms <= 0 || users.P99Ms > users.MaxMs {
+		t.Errorf("p99 %v outside (0, max %v]", users.P99Ms, users.MaxMs)
+	}
+	if got := a.Stats("", "anonymous"); len(got) != 1 || got[0].Calls != 1 {
+		t.Errorf("filtered by client = %+v", got)
+	}
+
+	// Calls older than the window drop out.
+	now = now.Add(time.Hour)
+	if got := a.Stats("", ""); len(got) != 0 {
+		t.Errorf("after the window: %+v", got)
+	}
+}
+
+func TestEndpointAnalyticsSeriesCap(t *testing.T) {
+	a := NewEndpointAnalytics(time.Hour, 1)
+	a.record("GET", "/users", "user:1", fiber.StatusOK, time.Millisecond)
+	a.record("GET", "/users", "user:2", fiber.StatusOK, time.Millisecond)
+	a.record("GET", "/users", "user:3", fiber.StatusInternalServerError, time.Millisecond)
+
+	clients := a.Stats("", "")[0].Clients
+	if len(clients) != 2 || clients[0].Client != OtherClient || clients[0].Calls != 2 || clients[0].ServerErrors != 1 {
+		t.Errorf("clients = %+v", clients)
+	}
+}
+
+func TestLatencyPercentile(t *testing.T) {
+	var h latencyHistogram
+	for i := 0; i < 99; i++ {
+		h.add(&latencyHistogram{calls: 1, buckets: [len(latencyBoundsMs) + 1]uint64{2: 1}, maxMs: 4})
+	}
+	h.add(&latencyHistogram{calls: 1, buckets: [len(latencyBoundsMs) + 1]uint64{len(latencyBoundsMs): 1}, maxMs: 30000})
+
+	if got := h.percentile(0.5); got != 5 {
+		t.Errorf("p50 = %v, want the 5ms bucket", got)
+	}
+	if got := h.percentile(0.99); got != 5 {
+		t.Errorf("p99 = %v, want the 5ms bucket", got)
+	}
+	if got := h.percentile(1); got != 30000 {
+		t.Errorf("p100 = %v, want the slowest call", got)
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/models"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, rawKey string) (*models.AuthUser, error)
+}
+
+// APIKey authenticates service accounts by their X-API-Key header. Requests
+// without the header fall through to Auth.
+func APIKey(authenticator APIKeyAuthenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rawKey := c.Get(APIKeyHeader)
+		if rawKey == "" {
+			return c.Next()
+		}
+
+		authUser, err := authenticator.Authenticate(c.UserContext(), rawKey)
+		if err != nil {
+			if logger != nil {
+				logger.Warn("api key authentication failed", zap.Error(err), zap.String("path", c.Path()))
+			}
+			return models.SendError(c, fiber.StatusUnauthorized, "Invalid or expired API key", models.ErrCodeInvalidToken, GetRequestID(c))
+		}
+		c.Locals(AuthUserKey, *authUser)
+
+		return c.Next()
+	}
+}
+
+// RequireScope limits API-key callers to keys carrying the scope. JWT users
+// have no scopes and are governed by their role alone.
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser := GetAuthUser(c)
+		if authUser == nil || authUser.Scopes == nil {
+			return c.Next()
+		}
+
+		for _, s := range authUser.Scopes {
+			if s == scope {
+				return c.Next()
+			}
+		}
+
+		if logger != nil {
+			logger.Warn("scope check failed",
+				zap.Int64("user_id", authUser.ID),
+				zap.Strings("scopes", authUser.Scopes),
+				zap.String("required_scope", scope),
+				zap.String("path", c.Path()),
+			)
+		}
+		return models.SendError(c, fiber.StatusForbidden, "Forbidden: API key lacks scope "+scope, models.ErrCodeInsufficientPerms, GetRequestID(c))
+	}
+}
+
+// RequireMethodScope requires readScope for safe methods and writeScope for
+// everything else.
+func RequireMethodScope(readScope, writeScope string) fiber.Handler {
+	read, write := RequireScope(readScope), RequireScope(writeScope)
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return read(c)
+		}
+		return write(c)
+	}
+}
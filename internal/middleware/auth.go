@@ -1,18 +1,22 @@
 package middleware
 
 import (
+	"errors"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 	"go.uber.org/zap"
 
 	"BACKEND/internal/models"
+	"BACKEND/internal/policy"
 	"BACKEND/internal/service"
 )
 
 const (
-	AuthUserKey = "authUser"
+	AuthUserKey  = "authUser"
+	JWTClaimsKey = "jwtClaims"
 )
 
 func GetAuthUser(c *fiber.Ctx) *models.AuthUser {
@@ -23,52 +27,94 @@ func GetAuthUser(c *fiber.Ctx) *models.AuthUser {
 	return &user
 }
 
-func Auth(jwtSecret string) fiber.Handler {
-	return func(c *fiber.Ctx) error {
-		authHeader := c.Get("Authorization")
-		if authHeader == "" {
-			if logger != nil {
-				logger.Warn("missing authorization header", zap.String("path", c.Path()))
-			}
-			return models.SendUnauthorized(c, "Missing authorization header", GetRequestID(c))
-		}
+// GetJWTClaims returns the verified claims of the request's bearer token, or
+// nil when the request was authenticated some other way.
+func GetJWTClaims(c *fiber.Ctx) *service.JWTClaims {
+	claims, _ := c.Locals(JWTClaimsKey).(*service.JWTClaims)
+	return claims
+}
 
-		authHeaderLower := strings.ToLower(authHeader)
-		if !strings.HasPrefix(authHeaderLower, "bearer ") {
-			if logger != nil {
-				logger.Warn("invalid authorization header format", zap.String("path", c.Path()))
-			}
-			return models.SendUnauthorized(c, "Invalid authorization header format. Expected: Bearer <token>", GetRequestID(c))
-		}
+var (
+	errMissingAuthHeader = errors.New("missing authorization header")
+	errInvalidAuthHeader = errors.New("invalid authorization header format")
+	errEmptyToken        = errors.New("empty token")
+)
 
-		parts := strings.Fields(authHeader)
-		if len(parts) < 2 {
-			if logger != nil {
-				logger.Warn("empty token", zap.String("path", c.Path()))
-			}
-			return models.SendUnauthorized(c, "Token is required", GetRequestID(c))
-		}
+// BearerToken extracts the token from an Authorization header of the form
+// "Bearer <token>". The scheme is case-insensitive.
+func BearerToken(header string) (string, error) {
+	if header == "" {
+		return "", errMissingAuthHeader
+	}
+	if !strings.HasPrefix(strings.ToLower(header), "bearer ") {
+		return "", errInvalidAuthHeader
+	}
 
-		if !strings.EqualFold(parts[0], "bearer") {
-			if logger != nil {
-				logger.Warn("invalid authorization header format", zap.String("path", c.Path()))
-			}
-			return models.SendUnauthorized(c, "Invalid authorization header format. Expected: Bearer <token>", GetRequestID(c))
+	parts := strings.Fields(header)
+	if len(parts) < 2 {
+		return "", errEmptyToken
+	}
+	if !strings.EqualFold(parts[0], "bearer") {
+		return "", errInvalidAuthHeader
+	}
+	return strings.Join(parts[1:], " "), nil
+}
+
+// Where Auth looks for the JWT first when a request carries one in both
+// the Authorization header and the token cookie.
+const (
+	TokenPrecedenceHeader = "header"
+	TokenPrecedenceCookie = "cookie"
+)
+
+// TokenCookie is the cookie login sets the JWT in for browser clients.
+const TokenCookie = "token"
+
+// AuthConfig configures Auth. Precedence is TokenPrecedenceHeader, the
+// default, or TokenPrecedenceCookie.
+type AuthConfig struct {
+	Secret     string
+	Precedence string
+}
+
+// requestToken returns the JWT from whichever of the Authorization header
+// and the token cookie comes first by precedence, or from the other when
+// the request doesn't carry that one. A malformed header it picks is an
+// error even when there is a cookie to fall back on.
+func requestToken(c *fiber.Ctx, precedence string) (string, error) {
+	header := c.Get(fiber.HeaderAuthorization)
+	cookie := c.Cookies(TokenCookie)
+	if cookie != "" && (header == "" || precedence == TokenPrecedenceCookie) {
+		return cookie, nil
+	}
+	return BearerToken(header)
+}
+
+func Auth(cfg AuthConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if GetAuthUser(c) != nil {
+			return c.Next()
 		}
 
-		tokenString := strings.Join(parts[1:], " ")
-		if tokenString == "" {
+		tokenString, err := requestToken(c, cfg.Precedence)
+		if err != nil {
 			if logger != nil {
-				logger.Warn("empty token", zap.String("path", c.Path()))
+				logger.Warn(err.Error(), zap.String("path", c.Path()))
 			}
-			return models.SendUnauthorized(c, "Token is required", GetRequestID(c))
+			switch err {
+			case errMissingAuthHeader:
+				return models.SendUnauthorized(c, "Missing authorization header or token cookie", GetRequestID(c))
+			case errEmptyToken:
+				return models.SendUnauthorized(c, "Token is required", GetRequestID(c))
+			}
+			return models.SendUnauthorized(c, "Invalid authorization header format. Expected: Bearer <token>", GetRequestID(c))
 		}
 
 		token, err := jwt.ParseWithClaims(tokenString, &service.JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 				return nil, jwt.ErrSignatureInvalid
 			}
-			return []byte(jwtSecret), nil
+			return []byte(cfg.Secret), nil
 		})
 
 		if err != nil {
@@ -86,15 +132,41 @@ func Auth(jwtSecret string) fiber.Handler {
 			return models.SendError(c, fiber.StatusUnauthorized, "Invalid token claims", models.ErrCodeInvalidToken, GetRequestID(c))
 		}
 
+		// Tokens without an issue time predate any revocation.
+		var issuedAt time.Time
+		if claims.IssuedAt != nil {
+			issuedAt = claims.IssuedAt.Time
+		}
+		if isTokenRevoked(c, claims.UserID, issuedAt) {
+			if logger != nil {
+				logger.Warn("revoked token used", zap.Int64("user_id", claims.UserID), zap.String("path", c.Path()))
+			}
+			return models.SendError(c, fiber.StatusUnauthorized, "Token has been revoked", models.ErrCodeTokenRevoked, GetRequestID(c))
+		}
+		if isTokenIDRevoked(c, claims.ID) || isTokenIDRevoked(c, claims.SessionID) {
+			if logger != nil {
+				logger.Warn("logged out token used", zap.Int64("user_id", claims.UserID), zap.String("path", c.Path()))
+			}
+			return models.SendError(c, fiber.StatusUnauthorized, "Token has been revoked", models.ErrCodeTokenRevoked, GetRequestID(c))
+		}
+		if isSessionIdle(c, claims.ID, issuedAt) {
+			if logger != nil {
+				logger.Info("idle session ended", zap.Int64("user_id", claims.UserID), zap.String("path", c.Path()))
+			}
+			return models.SendError(c, fiber.StatusUnauthorized, "Session has expired due to inactivity", models.ErrCodeSessionIdle, GetRequestID(c))
+		}
+
 		authUser := models.AuthUser{
-			ID:   claims.UserID,
-			Role: claims.Role,
+			ID:          claims.UserID,
+			Role:        claims.Role,
+			AccountType: "human",
 		}
 		c.Locals(AuthUserKey, authUser)
+		c.Locals(JWTClaimsKey, claims)
 
 		if logger != nil {
 			logger.Info("user authenticated",
-				zap.Int32("user_id", authUser.ID),
+				zap.Int64("user_id", authUser.ID),
 				zap.String("role", authUser.Role),
 				zap.String("path", c.Path()),
 			)
@@ -104,9 +176,11 @@ func Auth(jwtSecret string) fiber.Handler {
 	}
 }
 
-func RequireRole(allowedRoles ...string) fiber.Handler {
+// RequireRole admits users whose role is one of allowedRoles or ranks above
+// one of them in the role hierarchy of policies.
+func RequireRole(policies *policy.Engine, allowedRoles ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-	
+
 		authUser := GetAuthUser(c)
 		if authUser == nil {
 			if logger != nil {
@@ -119,7 +193,7 @@ func RequireRole(allowedRoles ...string) fiber.Handler {
 
 		hasRole := false
 		for _, role := range allowedRoles {
-			if authUser.Role == role {
+			if policies.RoleSatisfies(authUser.Role, role) {
 				hasRole = true
 				break
 			}
@@ -128,7 +202,7 @@ func RequireRole(allowedRoles ...string) fiber.Handler {
 		if !hasRole {
 			if logger != nil {
 				logger.Warn("role check failed: insufficient permissions",
-					zap.Int32("user_id", authUser.ID),
+					zap.Int64("user_id", authUser.ID),
 					zap.String("user_role", authUser.Role),
 					zap.Strings("required_roles", allowedRoles),
 					zap.String("path", c.Path()),
@@ -139,7 +213,7 @@ func RequireRole(allowedRoles ...string) fiber.Handler {
 
 		if logger != nil {
 			logger.Info("role check passed",
-				zap.Int32("user_id", authUser.ID),
+				zap.Int64("user_id", authUser.ID),
 				zap.String("role", authUser.Role),
 				zap.String("path", c.Path()),
 			)
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"strings"
+	"testing"
+)
+
+func FuzzBearerToken(f *testing.F) {
+	for _, seed := range []string{
+		"Bearer eyJhbGciOiJIUzI1NiJ9.e30.sig",
+		"bearer abc",
+		"BEARER  abc  def ",
+		"Bearer ",
+		"Bearer",
+		"Basic dXNlcjpwYXNz",
+		"Bearer\tabc",
+		"Bearer abc",
+		"",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, header string) {
+		token, err := BearerToken(header)
+		if err != nil {
+			if token != "" {
+				t.Fatalf("BearerToken(%q) = %q with error %v", header, token, err)
+			}
+			return
+		}
+		if token == "" || token != strings.TrimSpace(token) {
+			t.Fatalf("BearerToken(%q) = %q; want a non-empty, trimmed token", header, token)
+		}
+		if !strings.HasPrefix(strings.ToLower(header), "bearer ") {
+			t.Fatalf("BearerToken(%q) accepted a header without the Bearer scheme", header)
+		}
+	})
+}
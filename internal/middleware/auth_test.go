@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/service"
+)
+
+func TestAuthTokenSources(t *testing.T) {
+	auth := service.NewAuthService(nil)
+	auth.SetJWTConfig("secret", time.Hour)
+	alice, err := auth.GenerateJWT(context.Background(), 7, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bob, err := auth.GenerateJWT(context.Background(), 8, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newApp := func(precedence string) *fiber.App {
+		app := fiber.New()
+		app.Get("/", Auth(AuthConfig{Secret: "secret", Precedence: precedence}), func(c *fiber.Ctx) error {
+			return c.JSON(GetAuthUser(c).ID)
+		})
+		return app
+	}
+	// get returns the status and, when authenticated, the user's ID.
+	get := func(app *fiber.App, header, cookie string) (int, string) {
+		req := httptest.NewRequest("GET", "/", nil)
+		if header != "" {
+			req.Header.Set(fiber.HeaderAuthorization, header)
+		}
+		if cookie != "" {
+			req.Header.Set(fiber.HeaderCookie, TokenCookie+"="+cookie)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body)
+	}
+
+	for _, tc := range []struct {
+		name       string
+		precedence string
+		header     string
+		cookie     string
+		wantStatus int
+		wantUser   string
+	}{
+		{"header", "", "Bearer " + alice, "", fiber.StatusOK, "7"},
+		{"cookie", "", "", alice, fiber.StatusOK, "7"},
+		{"both, header first", TokenPrecedenceHeader, "Bearer " + alice, bob, fiber.StatusOK, "7"},
+		{"both, cookie first", TokenPrecedenceCookie, "Bearer " + alice, bob, fiber.StatusOK, "8"},
+		{"header when preferring the cookie", TokenPrecedenceCookie, "Bearer " + bob, "", fiber.StatusOK, "8"},
+		{"neither", "", "", "", fiber.StatusUnauthorized, ""},
+		{"invalid cookie", "", "", "not-a-jwt", fiber.StatusUnauthorized, ""},
+		{"malformed header with a cookie", TokenPrecedenceHeader, "Basic dXNlcjpwYXNz", alice, fiber.StatusUnauthorized, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			status, user := get(newApp(tc.precedence), tc.header, tc.cookie)
+			if status != tc.wantStatus {
+				t.Fatalf("status = %d; want %d", status, tc.wantStatus)
+			}
+			if tc.wantUser != "" && user != tc.wantUser {
+				t.Errorf("authenticated as %s; want %s", user, tc.wantUser)
+			}
+		})
+	}
+}
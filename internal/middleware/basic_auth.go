@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/models"
+)
+
+// BasicAuth admits requests with HTTP basic auth credentials matching
+// username and password, and asks for them otherwise.
+func BasicAuth(realm, username, password string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, pass, _ := basicAuthCredentials(c.Get(fiber.HeaderAuthorization))
+		// Both are compared, so the time taken doesn't tell which was wrong.
+		userOK := subtle.ConstantTimeCompare([]byte(user), []byte(username)) == 1
+		passOK := subtle.ConstantTimeCompare([]byte(pass), []byte(password)) == 1
+		if !userOK || !passOK {
+			if logger != nil {
+				logger.Warn("basic authentication failed", zap.String("path", c.Path()))
+			}
+			c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="`+realm+`"`)
+			return models.SendUnauthorized(c, "Unauthorized", GetRequestID(c))
+		}
+		return c.Next()
+	}
+}
+
+func basicAuthCredentials(header string) (user, pass string, ok bool) {
+	scheme, encoded, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "basic") {
+		return "", "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", "", false
+	}
+	return strings.Cut(string(decoded), ":")
+}
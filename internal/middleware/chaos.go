@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/models"
+)
+
+// ChaosConfig sets how often Chaos slows or fails requests. Percentages are
+// of all requests, decided independently, so a request can be both delayed
+// and failed.
+type ChaosConfig struct {
+	Latency        time.Duration
+	LatencyPercent int
+	ErrorPercent   int
+}
+
+func (c ChaosConfig) Enabled() bool {
+	return (c.Latency > 0 && c.LatencyPercent > 0) || c.ErrorPercent > 0
+}
+
+// Chaos injects artificial latency and 500s so clients and their retry logic
+// can be tested against failures. It is for development only; Mount refuses
+// to enable it elsewhere. Affected responses carry an X-Chaos header naming
+// the faults injected.
+func Chaos(cfg ChaosConfig) fiber.Handler {
+	return chaos(cfg, func(percent int) bool { return rand.IntN(100) < percent })
+}
+
+func chaos(cfg ChaosConfig, chance func(percent int) bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !cfg.Enabled() {
+			return c.Next()
+		}
+
+		if cfg.Latency > 0 && chance(cfg.LatencyPercent) {
+			c.Append("X-Chaos", "latency")
+			GetRequestLogger(c).Debug("chaos: injecting latency", zap.Duration("latency", cfg.Latency))
+			select {
+			case <-time.After(cfg.Latency):
+			case <-c.UserContext().Done():
+			}
+		}
+
+		if chance(cfg.ErrorPercent) {
+			c.Append("X-Chaos", "error")
+			GetRequestLogger(c).Debug("chaos: injecting error")
+			return models.SendInternalError(c, "Injected fault", GetRequestID(c))
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestChaos(t *testing.T) {
+	always := func(percent int) bool { return percent > 0 }
+	tests := []struct {
+		name       string
+		cfg        ChaosConfig
+		wantStatus int
+		wantHeader string
+		wantDelay  bool
+	}{
+		{"disabled", ChaosConfig{}, fiber.StatusOK, "", false},
+		{"latency", ChaosConfig{Latency: 20 * time.Millisecond, LatencyPercent: 100}, fiber.StatusOK, "latency", true},
+		{"error", ChaosConfig{ErrorPercent: 100}, fiber.StatusInternalServerError, "error", false},
+		{"both", ChaosConfig{Latency: 20 * time.Millisecond, LatencyPercent: 50, ErrorPercent: 50}, fiber.StatusInternalServerError, "latency, error", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(chaos(tt.cfg, always))
+			app.Get("/", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+			start := time.Now()
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d; want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := resp.Header.Get("X-Chaos"); got != tt.wantHeader {
+				t.Errorf("X-Chaos = %q; want %q", got, tt.wantHeader)
+			}
+			if delayed := time.Since(start) >= 20*time.Millisecond; delayed != tt.wantDelay {
+				t.Errorf("delayed = %v; want %v", delayed, tt.wantDelay)
+			}
+		})
+	}
+}
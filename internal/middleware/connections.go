@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"sync/atomic"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ConnectionStats counts requests and the connections they arrive on, to
+// show how well clients reuse keep-alive connections.
+type ConnectionStats struct {
+	connections atomic.Uint64
+	requests    atomic.Uint64
+}
+
+type ConnectionStatsSnapshot struct {
+	Open                  int32   `json:"open"`
+	Concurrency           uint32  `json:"concurrency"`
+	MaxConcurrency        int     `json:"max_concurrency"`
+	Connections           uint64  `json:"connections"`
+	Requests              uint64  `json:"requests"`
+	RequestsPerConnection float64 `json:"requests_per_connection"`
+	Keepalive             bool    `json:"keepalive"`
+	IdleTimeoutMs         int64   `json:"idle_timeout_ms"`
+	ReadTimeoutMs         int64   `json:"read_timeout_ms"`
+	WriteTimeoutMs        int64   `json:"write_timeout_ms"`
+}
+
+func NewConnectionStats() *ConnectionStats {
+	return &ConnectionStats{}
+}
+
+// CountConnections counts every request, and each connection on its first
+// request.
+func CountConnections(stats *ConnectionStats) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		stats.requests.Add(1)
+		if c.Context().ConnRequestNum() == 1 {
+			stats.connections.Add(1)
+		}
+		return c.Next()
+	}
+}
+
+// Stats combines the counts with app's open connections and listener
+// settings. Open connections include idle keep-alive ones; Concurrency is
+// the connections being served right now.
+func (s *ConnectionStats) Stats(app *fiber.App) ConnectionStatsSnapshot {
+	server, cfg := app.Server(), app.Config()
+	// Without an idle timeout the server falls back to the read timeout.
+	idle := cfg.IdleTimeout
+	if idle == 0 {
+		idle = cfg.ReadTimeout
+	}
+	stats := ConnectionStatsSnapshot{
+		Open:           server.GetOpenConnectionsCount(),
+		Concurrency:    server.GetCurrentConcurrency(),
+		MaxConcurrency: server.Concurrency,
+		Connections:    s.connections.Load(),
+		Requests:       s.requests.Load(),
+		Keepalive:      !cfg.DisableKeepalive,
+		IdleTimeoutMs:  idle.Milliseconds(),
+		ReadTimeoutMs:  cfg.ReadTimeout.Milliseconds(),
+		WriteTimeoutMs: cfg.WriteTimeout.Milliseconds(),
+	}
+	if stats.Connections > 0 {
+		stats.RequestsPerConnection = float64(stats.Requests) / float64(stats.Connections)
+	}
+	return stats
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCountConnections(t *testing.T) {
+	stats := NewConnectionStats()
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Use(CountConnections(stats))
+	app.Get("/", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go app.Listener(ln)
+	defer app.Shutdown()
+
+	// One client reuses its connection; the other closes it each time.
+	client := &http.Client{Transport: &http.Transport{}}
+	closing := &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+	for _, c := range []*http.Client{client, client, client, closing, closing} {
+		resp, err := c.Get("http://" + ln.Addr().String() + "/")
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+
+	got := stats.Stats(app)
+	if got.Requests != 5 || got.Connections != 3 {
+		t.Errorf("got %d requests on %d connections, want 5 on 3", got.Requests, got.Connections)
+	}
+	if got.RequestsPerConnection < 1.66 || got.RequestsPerConnection > 1.67 {
+		t.Errorf("requests per connection = %v", got.RequestsPerConnection)
+	}
+	if !got.Keepalive {
+		t.Error("keep-alive reported off")
+	}
+}
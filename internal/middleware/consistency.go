@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/repository"
+)
+
+// primaryCookie marks a client that wrote recently, so its reads go to the
+// primary until replicas have caught up.
+const primaryCookie = "db_primary"
+
+// ConsistencyConfig configures Consistency. StickyWindow is how long reads
+// after a write stay on the primary.
+type ConsistencyConfig struct {
+	StickyWindow time.Duration
+	CookieSecure bool
+}
+
+// Consistency routes a request's queries to the primary database instead
+// of a read replica when
+//   - it sends "Prefer: consistency=strong", which is then echoed in
+//     Preference-Applied;
+//   - it is a write (any method but GET, HEAD and OPTIONS), so reads made
+//     while handling it see its own writes; or
+//   - the client wrote within StickyWindow, as recorded by a short-lived
+//     cookie set on the write's response.
+//
+// Mount only installs it when there is a replica.
+func Consistency(cfg ConsistencyConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		write := c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead && c.Method() != fiber.MethodOptions
+		strong := prefersStrong(c.Get("Prefer"))
+		if strong {
+			c.Set("Preference-Applied", "consistency=strong")
+		}
+		if strong || write || c.Cookies(primaryCookie) != "" {
+			c.SetUserContext(repository.WithPrimary(c.UserContext()))
+		}
+
+		err := c.Next()
+
+		if write && cfg.StickyWindow > 0 && err == nil && c.Response().StatusCode() < fiber.StatusBadRequest {
+			c.Cookie(&fiber.Cookie{
+				Name:     primaryCookie,
+				Value:    "1",
+				Path:     "/",
+				MaxAge:   int(math.Ceil(cfg.StickyWindow.Seconds())),
+				HTTPOnly: true,
+				Secure:   cfg.CookieSecure,
+				SameSite: "Lax",
+			})
+		}
+		return err
+	}
+}
+
+// prefersStrong reports whether a Prefer header (RFC 7240) asks for
+// consistency=strong.
+func prefersStrong(header string) bool {
+	for _, pref := range strings.Split(header, ",") {
+		// Parameters after ";" don't change the preference itself.
+		token, _, _ := strings.Cut(pref, ";")
+		name, value, _ := strings.Cut(strings.TrimSpace(token), "=")
+		if strings.EqualFold(strings.TrimSpace(name), "consistency") &&
+			strings.EqualFold(strings.Trim(strings.TrimSpace(value), `"`), "strong") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/repository"
+)
+
+func TestConsistency(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		prefer      string
+		cookie      string
+		status      int
+		wantPrimary bool
+		wantApplied bool
+		wantCookie  bool
+	}{
+		{"read", fiber.MethodGet, "", "", fiber.StatusOK, false, false, false},
+		{"strong read", fiber.MethodGet, "consistency=strong", "", fiber.StatusOK, true, true, false},
+		{"strong among others", fiber.MethodGet, `return=minimal, Consistency="strong"; x=1`, "", fiber.StatusOK, true, true, false},
+		{"eventual read", fiber.MethodGet, "consistency=eventual", "", fiber.StatusOK, false, false, false},
+		{"read after write", fiber.MethodGet, "", "1", fiber.StatusOK, true, false, false},
+		{"write", fiber.MethodPost, "", "", fiber.StatusCreated, true, false, true},
+		{"failed write", fiber.MethodPatch, "", "", fiber.StatusBadRequest, true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var primary bool
+			app := fiber.New()
+			app.Use(Consistency(ConsistencyConfig{StickyWindow: 5 * time.Second}))
+			app.All("/", func(c *fiber.Ctx) error {
+				primary = repository.UsesPrimary(c.UserContext())
+				return c.SendStatus(tt.status)
+			})
+
+			req := httptest.NewRequest(tt.method, "/", nil)
+			if tt.prefer != "" {
+				req.Header.Set("Prefer", tt.prefer)
+			}
+			if tt.cookie != "" {
+				req.Header.Set("Cookie", primaryCookie+"="+tt.cookie)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if primary != tt.wantPrimary {
+				t.Errorf("primary = %v; want %v", primary, tt.wantPrimary)
+			}
+			if applied := resp.Header.Get("Preference-Applied") != ""; applied != tt.wantApplied {
+				t.Errorf("Preference-Applied set = %v; want %v", applied, tt.wantApplied)
+			}
+			setCookie := false
+			for _, ck := range resp.Cookies() {
+				if ck.Name == primaryCookie && ck.MaxAge == 5 {
+					setCookie = true
+				}
+			}
+			if setCookie != tt.wantCookie {
+				t.Errorf("sticky cookie set = %v; want %v", setCookie, tt.wantCookie)
+			}
+		})
+	}
+}
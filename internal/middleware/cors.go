@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CORSConfig says which browser origins other than the API's own may call
+// it.
+type CORSConfig struct {
+	// AllowedOrigins are origins such as https://app.example.com, or "*"
+	// for any origin. None turns cross-origin access off.
+	AllowedOrigins []string
+	AllowedMethods []string
+	// AllowCredentials lets the listed origins send cookies. It never
+	// applies to origins let in by "*".
+	AllowCredentials bool
+}
+
+// corsMaxAge is how long, in seconds, browsers may cache a preflight.
+const corsMaxAge = "600"
+
+// corsExposedHeaders are the response headers scripts on other origins get
+// to read besides the CORS-safelisted ones.
+var corsExposedHeaders = strings.Join([]string{
+	"X-Request-ID",
+	"X-API-Version",
+	"Preference-Applied",
+	fiber.HeaderRetryAfter,
+	fiber.HeaderLink,
+	fiber.HeaderETag,
+}, ", ")
+
+// CORS answers preflight requests and adds the CORS headers browsers need
+// to let pages on the allowed origins call the API. Requests from other
+// origins are served without them, so the browser withholds the response;
+// clients that aren't browsers send no Origin and aren't affected.
+func CORS(cfg CORSConfig) fiber.Handler {
+	listed := make(map[string]bool, len(cfg.AllowedOrigins))
+	var anyOrigin bool
+	for _, origin := range cfg.AllowedOrigins {
+		if origin == "*" {
+			anyOrigin = true
+			continue
+		}
+		listed[strings.ToLower(strings.TrimSuffix(origin, "/"))] = true
+	}
+	methods := strings.ToUpper(strings.Join(cfg.AllowedMethods, ", "))
+
+	return func(c *fiber.Ctx) error {
+		origin := c.Get(fiber.HeaderOrigin)
+		if origin == "" {
+			return c.Next()
+		}
+		c.Vary(fiber.HeaderOrigin)
+		preflight := c.Method() == fiber.MethodOptions && c.Get(fiber.HeaderAccessControlRequestMethod) != ""
+
+		isListed := listed[strings.ToLower(origin)]
+		if !isListed && !anyOrigin {
+			if preflight {
+				return c.SendStatus(fiber.StatusNoContent)
+			}
+			return c.Next()
+		}
+		if isListed {
+			c.Set(fiber.HeaderAccessControlAllowOrigin, origin)
+			if cfg.AllowCredentials {
+				c.Set(fiber.HeaderAccessControlAllowCredentials, "true")
+			}
+		} else {
+			c.Set(fiber.HeaderAccessControlAllowOrigin, "*")
+		}
+
+		if !preflight {
+			c.Set(fiber.HeaderAccessControlExposeHeaders, corsExposedHeaders)
+			return c.Next()
+		}
+		c.Vary(fiber.HeaderAccessControlRequestMethod, fiber.HeaderAccessControlRequestHeaders)
+		c.Set(fiber.HeaderAccessControlAllowMethods, methods)
+		if headers := c.Get(fiber.HeaderAccessControlRequestHeaders); headers != "" {
+			c.Set(fiber.HeaderAccessControlAllowHeaders, headers)
+		}
+		c.Set(fiber.HeaderAccessControlMaxAge, corsMaxAge)
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+}
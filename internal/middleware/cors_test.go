@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestCORS(t *testing.T) {
+	newApp := func(cfg CORSConfig) *fiber.App {
+		app := fiber.New()
+		app.Use(CORS(cfg))
+		app.Post("/auth/login", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+		return app
+	}
+	do := func(app *fiber.App, method, origin string) *http.Response {
+		req := httptest.NewRequest(method, "/auth/login", nil)
+		if origin != "" {
+			req.Header.Set(fiber.HeaderOrigin, origin)
+		}
+		if method == fiber.MethodOptions {
+			req.Header.Set(fiber.HeaderAccessControlRequestMethod, fiber.MethodPost)
+			req.Header.Set(fiber.HeaderAccessControlRequestHeaders, "content-type, authorization")
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	app := newApp(CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{"get", "post"},
+		AllowCredentials: true,
+	})
+
+	resp := do(app, fiber.MethodOptions, "https://app.example.com")
+	if resp.StatusCode != fiber.StatusNoContent {
+		t.Errorf("preflight = %d; want 204", resp.StatusCode)
+	}
+	for header, want := range map[string]string{
+		fiber.HeaderAccessControlAllowOrigin:      "https://app.example.com",
+		fiber.HeaderAccessControlAllowCredentials: "true",
+		fiber.HeaderAccessControlAllowMethods:     "GET, POST",
+		fiber.HeaderAccessControlAllowHeaders:     "content-type, authorization",
+	} {
+		if got := resp.Header.Get(header); got != want {
+			t.Errorf("preflight %s = %q; want %q", header, got, want)
+		}
+	}
+
+	resp = do(app, fiber.MethodPost, "https://app.example.com")
+	if resp.StatusCode != fiber.StatusOK || resp.Header.Get(fiber.HeaderAccessControlAllowOrigin) != "https://app.example.com" {
+		t.Errorf("request from an allowed origin = %d, Allow-Origin %q", resp.StatusCode, resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	}
+	if resp.Header.Get(fiber.HeaderAccessControlExposeHeaders) == "" {
+		t.Error("no headers exposed to the allowed origin")
+	}
+
+	resp = do(app, fiber.MethodOptions, "https://evil.example.com")
+	if got := resp.Header.Get(fiber.HeaderAccessControlAllowOrigin); got != "" {
+		t.Errorf("preflight from another origin was allowed: %q", got)
+	}
+	resp = do(app, fiber.MethodPost, "")
+	if resp.StatusCode != fiber.StatusOK || resp.Header.Get(fiber.HeaderAccessControlAllowOrigin) != "" {
+		t.Errorf("request without an Origin = %d, Allow-Origin %q", resp.StatusCode, resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	}
+
+	// Any origin may call, but never with credentials.
+	app = newApp(CORSConfig{AllowedOrigins: []string{"*"}, AllowedMethods: []string{"POST"}, AllowCredentials: true})
+	resp = do(app, fiber.MethodOptions, "https://anywhere.example.com")
+	if got := resp.Header.Get(fiber.HeaderAccessControlAllowOrigin); got != "*" {
+		t.Errorf("wildcard Allow-Origin = %q; want *", got)
+	}
+	if got := resp.Header.Get(fiber.HeaderAccessControlAllowCredentials); got != "" {
+		t.Errorf("wildcard origin allowed credentials: %q", got)
+	}
+}
@@ -0,0 +1,169 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DeprecationHeader carries the time a feature the request used was
+// deprecated, or "true" when that isn't recorded.
+const DeprecationHeader = "Deprecation"
+
+// SunsetHeader carries the time a deprecated feature stops working.
+const SunsetHeader = "Sunset"
+
+const (
+	deprecationsKey = "deprecations"
+	deprecatedKey   = "deprecated"
+)
+
+// Deprecation describes an endpoint, parameter or field that clients
+// should move off.
+type Deprecation struct {
+	Feature string `json:"feature"`
+	Message string `json:"message,omitempty"`
+	// Since is when the feature was deprecated; zero when it isn't recorded.
+	Since time.Time `json:"since,omitzero"`
+	// Sunset is when the feature goes away; zero until that is decided.
+	Sunset time.Time `json:"sunset,omitzero"`
+	// Link points to the migration notes.
+	Link string `json:"link,omitempty"`
+}
+
+type DeprecationUsage struct {
+	Deprecation
+	Requests   uint64     `json:"requests"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+// Deprecations is the set of deprecated features, with how often each is
+// still used since the process started.
+type Deprecations struct {
+	mu    sync.Mutex
+	usage map[string]*DeprecationUsage
+	now   func() time.Time
+}
+
+func NewDeprecations(features ...Deprecation) *Deprecations {
+	d := &Deprecations{
+		usage: make(map[string]*DeprecationUsage, len(features)),
+		now:   time.Now,
+	}
+	for _, f := range features {
+		d.usage[f.Feature] = &DeprecationUsage{Deprecation: f}
+	}
+	return d
+}
+
+// Usage lists every registered feature by name with its request count.
+func (d *Deprecations) Usage() []DeprecationUsage {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	out := make([]DeprecationUsage, 0, len(d.usage))
+	for _, u := range d.usage {
+		out = append(out, *u)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Feature < out[j].Feature })
+	return out
+}
+
+// use counts a request to feature and returns its description. Features
+// that weren't registered are still counted, with only a name.
+func (d *Deprecations) use(feature string) Deprecation {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	u, ok := d.usage[feature]
+	if !ok {
+		u = &DeprecationUsage{Deprecation: Deprecation{Feature: feature}}
+		d.usage[feature] = u
+	}
+	now := d.now()
+	u.Requests++
+	u.LastUsedAt = &now
+	return u.Deprecation
+}
+
+// TrackDeprecations lets MarkDeprecated count usage against d and adds the
+// features a request used to its JSON object response under
+// meta.deprecations.
+func TrackDeprecations(d *Deprecations) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(deprecationsKey, d)
+		err := c.Next()
+		if used, ok := c.Locals(deprecatedKey).([]Deprecation); ok {
+			addDeprecationMeta(c, used)
+		}
+		return err
+	}
+}
+
+// Deprecated marks every request to a route as using feature.
+func Deprecated(feature string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		MarkDeprecated(c, feature)
+		return c.Next()
+	}
+}
+
+// MarkDeprecated records that the request uses feature and sets the
+// Deprecation header, with Sunset and a deprecation Link when they are
+// known. Without TrackDeprecations only "Deprecation: true" is set.
+func MarkDeprecated(c *fiber.Ctx, feature string) {
+	dep := Deprecation{Feature: feature}
+	if d, ok := c.Locals(deprecationsKey).(*Deprecations); ok {
+		dep = d.use(feature)
+	}
+	used, _ := c.Locals(deprecatedKey).([]Deprecation)
+	c.Locals(deprecatedKey, append(used, dep))
+
+	if dep.Since.IsZero() {
+		c.Set(DeprecationHeader, "true")
+	} else {
+		c.Set(DeprecationHeader, fmt.Sprintf("@%d", dep.Since.Unix()))
+	}
+	if !dep.Sunset.IsZero() {
+		c.Set(SunsetHeader, dep.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if dep.Link != "" {
+		c.Append(fiber.HeaderLink, fmt.Sprintf(`<%s>; rel="deprecation"`, dep.Link))
+	}
+}
+
+// addDeprecationMeta splices meta.deprecations into a JSON object body
+// that doesn't have a meta field of its own. Arrays, other content types
+// and error responses are left alone; the headers still apply.
+func addDeprecationMeta(c *fiber.Ctx, used []Deprecation) {
+	resp := c.Response()
+	if resp.StatusCode() >= fiber.StatusBadRequest || !bytes.HasPrefix(resp.Header.ContentType(), []byte(fiber.MIMEApplicationJSON)) {
+		return
+	}
+	body := bytes.TrimSpace(resp.Body())
+	var fields map[string]json.RawMessage
+	if len(body) == 0 || body[0] != '{' || json.Unmarshal(body, &fields) != nil {
+		return
+	}
+	if _, ok := fields["meta"]; ok {
+		return
+	}
+	meta, err := json.Marshal(map[string][]Deprecation{"deprecations": used})
+	if err != nil {
+		return
+	}
+
+	out := make([]byte, 0, len(body)+len(meta)+9)
+	out = append(out, body[:len(body)-1]...)
+	if len(fields) > 0 {
+		out = append(out, ',')
+	}
+	out = append(out, `"meta":`...)
+	out = append(out, meta...)
+	out = append(out, '}')
+	resp.SetBodyRaw(out)
+}
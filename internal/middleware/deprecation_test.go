@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestDeprecations(t *testing.T) {
+	since := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	d := NewDeprecations(Deprecation{
+		Feature: "old-list",
+		Message: "use pages",
+		Since:   since,
+		Sunset:  since.AddDate(1, 0, 0),
+		Link:    "https://example.com/migrate",
+	})
+	app := fiber.New()
+	app.Use(TrackDeprecations(d))
+	app.Get("/object", Deprecated("old-list"), func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"users": []string{"a"}})
+	})
+	app.Get("/array", Deprecated("old-list"), func(c *fiber.Ctx) error {
+		return c.JSON([]string{"a"})
+	})
+	app.Get("/current", func(c *fiber.Ctx) error {
+		return c.JSON(fiber.Map{"users": []string{"a"}})
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/object", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Header.Get(DeprecationHeader); got != "@1767225600" {
+		t.Errorf("Deprecation = %q", got)
+	}
+	if got := resp.Header.Get(SunsetHeader); got != "Fri, 01 Jan 2027 00:00:00 GMT" {
+		t.Errorf("Sunset = %q", got)
+	}
+	if got := resp.Header.Get(fiber.HeaderLink); got != `<https://example.com/migrate>; rel="deprecation"` {
+		t.Errorf("Link = %q", got)
+	}
+	var body struct {
+		Users []string `json:"users"`
+		Meta  struct {
+			Deprecations []Deprecation `json:"deprecations"`
+		} `json:"meta"`
+	}
+	raw, _ := io.ReadAll(resp.Body)
+	if err := json.Unmarshal(raw, &body); err != nil {
+		t.Fatalf("body %s: %v", raw, err)
+	}
+	if len(body.Users) != 1 || len(body.Meta.Deprecations) != 1 || body.Meta.Deprecations[0].Message != "use pages" {
+		t.Errorf("body = %s", raw)
+	}
+
+	// Arrays can't carry meta, but still get the headers and count.
+	resp, _ = app.Test(httptest.NewRequest("GET", "/array", nil))
+	raw, _ = io.ReadAll(resp.Body)
+	if string(raw) != `["a"]` || resp.Header.Get(DeprecationHeader) == "" {
+		t.Errorf("array response: %s, Deprecation %q", raw, resp.Header.Get(DeprecationHeader))
+	}
+
+	resp, _ = app.Test(httptest.NewRequest("GET", "/current", nil))
+	raw, _ = io.ReadAll(resp.Body)
+	if resp.Header.Get(DeprecationHeader) != "" || string(raw) != `{"users":["a"]}` {
+		t.Errorf("current response: %s, Deprecation %q", raw, resp.Header.Get(DeprecationHeader))
+	}
+
+	usage := d.Usage()
+	if len(usage) != 1 || usage[0].Requests != 2 || usage[0].LastUsedAt == nil {
+		t.Errorf("usage = %+v", usage)
+	}
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/geoip"
+	"BACKEND/internal/models"
+)
+
+// GeoBlock rejects requests from IPs located in one of the denied
+// countries (ISO codes). Addresses the locator cannot place are allowed.
+func GeoBlock(locator geoip.Locator, deniedCountries []string) fiber.Handler {
+	denied := make(map[string]bool, len(deniedCountries))
+	for _, country := range deniedCountries {
+		denied[strings.ToUpper(strings.TrimSpace(country))] = true
+	}
+
+	return func(c *fiber.Ctx) error {
+		if locator == nil || len(denied) == 0 {
+			return c.Next()
+		}
+
+		loc, ok := locator.Lookup(c.IP())
+		if !ok || !denied[loc.Country] {
+			return c.Next()
+		}
+
+		GetRequestLogger(c).Warn("login blocked by country denylist",
+			zap.String("ip", c.IP()),
+			zap.String("country", loc.Country),
+			zap.String("path", c.Path()),
+		)
+		return models.SendError(c, fiber.StatusForbidden, "Logins from your location are not allowed", models.ErrCodeLocationBlocked, GetRequestID(c))
+	}
+}
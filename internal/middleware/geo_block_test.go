@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/geoip"
+)
+
+type fixedLocator struct {
+	loc geoip.Location
+	ok  bool
+}
+
+func (l fixedLocator) Lookup(ip string) (geoip.Location, bool) {
+	return l.loc, l.ok
+}
+
+func TestGeoBlock(t *testing.T) {
+	tests := []struct {
+		name    string
+		locator geoip.Locator
+		deny    []string
+		want    int
+	}{
+		{"denied country", fixedLocator{geoip.Location{Country: "KP"}, true}, []string{"kp", "IR"}, fiber.StatusForbidden},
+		{"allowed country", fixedLocator{geoip.Location{Country: "NL"}, true}, []string{"KP"}, fiber.StatusOK},
+		{"unknown location", fixedLocator{}, []string{"KP"}, fiber.StatusOK},
+		{"no denylist", fixedLocator{geoip.Location{Country: "KP"}, true}, nil, fiber.StatusOK},
+		{"no locator", nil, []string{"KP"}, fiber.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Post("/login", GeoBlock(tt.locator, tt.deny), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/login", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d; want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
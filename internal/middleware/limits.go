@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/models"
+)
+
+const RequestTimeoutHeader = "X-Request-Timeout"
+
+// Timeout bounds how long a request may run. Callers can ask for a shorter
+// deadline with X-Request-Timeout, either a Go duration ("1500ms") or a
+// number of seconds; anything above max is capped to it.
+func Timeout(max time.Duration) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		timeout := max
+		if v := c.Get(RequestTimeoutHeader); v != "" {
+			requested, ok := parseRequestTimeout(v)
+			if !ok {
+				return models.SendError(c, fiber.StatusBadRequest, RequestTimeoutHeader+" must be a positive duration such as 500ms or 2s", models.ErrCodeInvalidInput, GetRequestID(c))
+			}
+			if timeout <= 0 || requested < timeout {
+				timeout = requested
+			}
+		}
+		if timeout <= 0 {
+			return c.Next()
+		}
+
+		ctx, cancel := context.WithTimeout(c.UserContext(), timeout)
+		defer cancel()
+		c.SetUserContext(ctx)
+
+		err := c.Next()
+
+		// A handler that answered after the deadline, or failed for some
+		// other reason, keeps its own response.
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && (errors.Is(err, context.DeadlineExceeded) || !responseWritten(c)) {
+			GetRequestLogger(c).Warn("request timed out",
+				zap.String("path", c.Path()),
+				zap.Duration("timeout", timeout),
+			)
+			return models.SendError(c, fiber.StatusGatewayTimeout, "Request timed out", models.ErrCodeRequestTimeout, GetRequestID(c))
+		}
+
+		return err
+	}
+}
+
+// responseWritten reports whether a handler has set a status or body.
+func responseWritten(c *fiber.Ctx) bool {
+	return c.Response().StatusCode() != fiber.StatusOK || len(c.Response().Body()) > 0
+}
+
+func parseRequestTimeout(v string) (time.Duration, bool) {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		secs, serr := strconv.ParseFloat(v, 64)
+		if serr != nil {
+			return 0, false
+		}
+		d = time.Duration(secs * float64(time.Second))
+	}
+	return d, d > 0
+}
+
+func ConcurrencyLimit(name string, maxConcurrent int) fiber.Handler {
+	if maxConcurrent <= 0 {
+		return func(c *fiber.Ctx) error {
+			return c.Next()
+		}
+	}
+
+	slots := make(chan struct{}, maxConcurrent)
+
+	return func(c *fiber.Ctx) error {
+		select {
+		case slots <- struct{}{}:
+		default:
+			GetRequestLogger(c).Warn("concurrency limit reached",
+				zap.String("group", name),
+				zap.Int("max_concurrent", maxConcurrent),
+				zap.String("path", c.Path()),
+			)
+			c.Set(fiber.HeaderRetryAfter, "1")
+			return models.SendServiceUnavailable(c, "Too many concurrent requests, please retry shortly", GetRequestID(c))
+		}
+		defer func() { <-slots }()
+
+		return c.Next()
+	}
+}
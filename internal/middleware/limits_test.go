@@ -0,0 +1,122 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestTimeout_RequestHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		max    time.Duration
+		header string
+		want   time.Duration
+		status int
+	}{
+		{"no header uses max", 10 * time.Second, "", 10 * time.Second, fiber.StatusOK},
+		{"shorter duration", 10 * time.Second, "1500ms", 1500 * time.Millisecond, fiber.StatusOK},
+		{"seconds", 10 * time.Second, "2", 2 * time.Second, fiber.StatusOK},
+		{"capped at max", 10 * time.Second, "1m", 10 * time.Second, fiber.StatusOK},
+		{"no max", 0, "3s", 3 * time.Second, fiber.StatusOK},
+		{"invalid", 10 * time.Second, "soon", 0, fiber.StatusBadRequest},
+		{"not positive", 10 * time.Second, "0", 0, fiber.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got time.Duration
+			app := fiber.New()
+			app.Use(Timeout(tt.max))
+			app.Get("/", func(c *fiber.Ctx) error {
+				if deadline, ok := c.UserContext().Deadline(); ok {
+					got = time.Until(deadline)
+				}
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestTimeoutHeader, tt.header)
+			}
+			resp, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Fatalf("status = %d; want %d", resp.StatusCode, tt.status)
+			}
+			if tt.status == fiber.StatusOK && (got > tt.want || got < tt.want-time.Second) {
+				t.Errorf("deadline in %v; want about %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeout_ExpiredRequestDeadline(t *testing.T) {
+	app := fiber.New()
+	app.Use(Timeout(10 * time.Second))
+	app.Get("/", func(c *fiber.Ctx) error {
+		select {
+		case <-c.UserContext().Done():
+			return c.UserContext().Err()
+		case <-time.After(time.Second):
+			return c.SendStatus(fiber.StatusOK)
+		}
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set(RequestTimeoutHeader, "20ms")
+	resp, err := app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusGatewayTimeout {
+		t.Errorf("status = %d; want %d", resp.StatusCode, fiber.StatusGatewayTimeout)
+	}
+}
+
+func TestTimeout_ResponseWrittenAfterDeadline(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler fiber.Handler
+		status  int
+	}{
+		{"nothing written", func(c *fiber.Ctx) error {
+			<-c.UserContext().Done()
+			return nil
+		}, fiber.StatusGatewayTimeout},
+		{"written response", func(c *fiber.Ctx) error {
+			<-c.UserContext().Done()
+			return c.Status(fiber.StatusCreated).JSON(fiber.Map{"id": 1})
+		}, fiber.StatusCreated},
+		{"written error", func(c *fiber.Ctx) error {
+			<-c.UserContext().Done()
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed"})
+		}, fiber.StatusInternalServerError},
+		{"deadline error", func(c *fiber.Ctx) error {
+			<-c.UserContext().Done()
+			_ = c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "failed"})
+			return fmt.Errorf("query: %w", c.UserContext().Err())
+		}, fiber.StatusGatewayTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Use(Timeout(20 * time.Millisecond))
+			app.Get("/", tt.handler)
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d; want %d", resp.StatusCode, tt.status)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/models"
+)
+
+const (
+	latencySmoothing = 0.1
+	limitBackoff     = 0.95
+)
+
+type AdaptiveLimiter struct {
+	mu            sync.Mutex
+	limit         float64
+	minLimit      int
+	maxLimit      int
+	targetLatency time.Duration
+	inflight      int
+	latencyEWMA   time.Duration
+	accepted      uint64
+	shed          uint64
+}
+
+type AdaptiveLimiterStats struct {
+	Enabled         bool    `json:"enabled"`
+	Limit           int     `json:"limit"`
+	MinLimit        int     `json:"min_limit"`
+	MaxLimit        int     `json:"max_limit"`
+	InFlight        int     `json:"in_flight"`
+	TargetLatencyMs float64 `json:"target_latency_ms"`
+	LatencyEWMAMs   float64 `json:"latency_ewma_ms"`
+	Accepted        uint64  `json:"accepted"`
+	Shed            uint64  `json:"shed"`
+}
+
+func NewAdaptiveLimiter(initialLimit, minLimit, maxLimit int, targetLatency time.Duration) *AdaptiveLimiter {
+	if minLimit < 1 {
+		minLimit = 1
+	}
+	if maxLimit < minLimit {
+		maxLimit = minLimit
+	}
+	if initialLimit < minLimit {
+		initialLimit = minLimit
+	}
+	if initialLimit > maxLimit {
+		initialLimit = maxLimit
+	}
+
+	return &AdaptiveLimiter{
+		limit:         float64(initialLimit),
+		minLimit:      minLimit,
+		maxLimit:      maxLimit,
+		targetLatency: targetLatency,
+	}
+}
+
+func (l *AdaptiveLimiter) acquire() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.inflight >= int(l.limit) {
+		l.shed++
+		return false
+	}
+	l.inflight++
+	l.accepted++
+	return true
+}
+
+// release records the latency of a finished request and adjusts the limit:
+// additive increase while latency stays under target, multiplicative
+// decrease once the smoothed latency degrades past it.
+func (l *AdaptiveLimiter) release(latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inflight--
+
+	if l.latencyEWMA == 0 {
+		l.latencyEWMA = latency
+	} else {
+		l.latencyEWMA = time.Duration(float64(l.latencyEWMA)*(1-latencySmoothing) + float64(latency)*latencySmoothing)
+	}
+
+	if l.latencyEWMA > l.targetLatency {
+		l.limit = math.Max(float64(l.minLimit), l.limit*limitBackoff)
+	} else {
+		l.limit = math.Min(float64(l.maxLimit), l.limit+1/l.limit)
+	}
+}
+
+func (l *AdaptiveLimiter) retryAfter() string {
+	l.mu.Lock()
+	seconds := math.Ceil(l.latencyEWMA.Seconds())
+	l.mu.Unlock()
+
+	if seconds < 1 {
+		seconds = 1
+	}
+	return strconv.Itoa(int(seconds))
+}
+
+func (l *AdaptiveLimiter) Stats() AdaptiveLimiterStats {
+	if l == nil {
+		return AdaptiveLimiterStats{Enabled: false}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return AdaptiveLimiterStats{
+		Enabled:         true,
+		Limit:           int(l.limit),
+		MinLimit:        l.minLimit,
+		MaxLimit:        l.maxLimit,
+		InFlight:        l.inflight,
+		TargetLatencyMs: float64(l.targetLatency) / float64(time.Millisecond),
+		LatencyEWMAMs:   float64(l.latencyEWMA) / float64(time.Millisecond),
+		Accepted:        l.accepted,
+		Shed:            l.shed,
+	}
+}
+
+func LoadShedding(l *AdaptiveLimiter) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if l == nil {
+			return c.Next()
+		}
+
+		if !l.acquire() {
+			GetRequestLogger(c).Warn("request shed under load",
+				zap.String("path", c.Path()),
+				zap.Int("limit", l.Stats().Limit),
+			)
+			c.Set(fiber.HeaderRetryAfter, l.retryAfter())
+			return models.SendServiceUnavailable(c, "Server is overloaded, please retry later", GetRequestID(c))
+		}
+
+		start := time.Now()
+		defer func() { l.release(time.Since(start)) }()
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestAdaptiveLimiter_ShedsWhenFull(t *testing.T) {
+	l := NewAdaptiveLimiter(1, 1, 1, time.Second)
+
+	if !l.acquire() {
+		t.Fatal("expected first request to be admitted")
+	}
+	if l.acquire() {
+		t.Fatal("expected second request to be shed while limit is reached")
+	}
+
+	l.release(10 * time.Millisecond)
+	if !l.acquire() {
+		t.Fatal("expected request to be admitted after release")
+	}
+
+	stats := l.Stats()
+	if stats.Accepted != 2 || stats.Shed != 1 {
+		t.Errorf("Stats() accepted=%d shed=%d; want 2 and 1", stats.Accepted, stats.Shed)
+	}
+}
+
+func TestAdaptiveLimiter_AdjustsToLatency(t *testing.T) {
+	l := NewAdaptiveLimiter(50, 10, 100, 100*time.Millisecond)
+
+	for i := 0; i < 50; i++ {
+		l.acquire()
+		l.release(500 * time.Millisecond)
+	}
+	if got := l.Stats().Limit; got >= 50 {
+		t.Errorf("limit after slow requests = %d; want it below the initial 50", got)
+	}
+	if got := l.Stats().Limit; got < 10 {
+		t.Errorf("limit after slow requests = %d; want it to respect the minimum of 10", got)
+	}
+
+	l = NewAdaptiveLimiter(50, 10, 100, 100*time.Millisecond)
+	for i := 0; i < 500; i++ {
+		l.acquire()
+		l.release(time.Millisecond)
+	}
+	if got := l.Stats().Limit; got <= 50 {
+		t.Errorf("limit after fast requests = %d; want it above the initial 50", got)
+	}
+}
+
+func TestLoadShedding_Returns503WithRetryAfter(t *testing.T) {
+	l := NewAdaptiveLimiter(1, 1, 1, time.Second)
+	l.acquire()
+
+	app := fiber.New()
+	app.Use(LoadShedding(l))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+
+	if resp.StatusCode != fiber.StatusServiceUnavailable {
+		t.Errorf("status = %d; want %d", resp.StatusCode, fiber.StatusServiceUnavailable)
+	}
+	if resp.Header.Get(fiber.HeaderRetryAfter) == "" {
+		t.Error("expected Retry-After header on shed response")
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// LogPolicy sets the level a request's outcome is logged at, so that bad
+// input from clients doesn't drown out failures of the server. Server
+// errors are always logged at Error.
+type LogPolicy struct {
+	// Success is for 1xx to 3xx responses.
+	Success zapcore.Level
+	// ClientError is for 4xx responses other than 401 and 404.
+	ClientError zapcore.Level
+	// Routine is for 401 and 404, which expired sessions and crawlers
+	// cause all day.
+	Routine zapcore.Level
+}
+
+var DefaultLogPolicy = LogPolicy{
+	Success:     zapcore.DebugLevel,
+	ClientError: zapcore.WarnLevel,
+	Routine:     zapcore.InfoLevel,
+}
+
+var logPolicy = DefaultLogPolicy
+
+func SetLogPolicy(p LogPolicy) {
+	logPolicy = p
+}
+
+// Level returns the level for a response with status.
+func (p LogPolicy) Level(status int) zapcore.Level {
+	switch {
+	case status >= fiber.StatusInternalServerError:
+		return zapcore.ErrorLevel
+	case status == fiber.StatusUnauthorized || status == fiber.StatusNotFound:
+		return p.Routine
+	case status >= fiber.StatusBadRequest:
+		return p.ClientError
+	default:
+		return p.Success
+	}
+}
+
+// LogRejected logs why a request is being answered with a client error
+// status, at the level the policy gives that status. Handlers use it
+// instead of Error for bad input, so Error is left for server failures.
+func LogRejected(c *fiber.Ctx, status int, msg string, fields ...zap.Field) {
+	if ce := GetRequestLogger(c).Check(logPolicy.Level(status), msg); ce != nil {
+		ce.Write(append(fields, zap.Int("status", status))...)
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogPolicyLevel(t *testing.T) {
+	tests := []struct {
+		status int
+		want   zapcore.Level
+	}{
+		{fiber.StatusOK, zapcore.DebugLevel},
+		{fiber.StatusFound, zapcore.DebugLevel},
+		{fiber.StatusBadRequest, zapcore.WarnLevel},
+		{fiber.StatusConflict, zapcore.WarnLevel},
+		{fiber.StatusUnauthorized, zapcore.InfoLevel},
+		{fiber.StatusNotFound, zapcore.InfoLevel},
+		{fiber.StatusInternalServerError, zapcore.ErrorLevel},
+		{fiber.StatusServiceUnavailable, zapcore.ErrorLevel},
+	}
+	for _, tt := range tests {
+		if got := DefaultLogPolicy.Level(tt.status); got != tt.want {
+			t.Errorf("Level(%d) = %v; want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestLoggerUsesLogPolicy(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	InitLogger(zap.New(core))
+	defer InitLogger(nil)
+
+	app := fiber.New()
+	app.Use(Logger())
+	app.Get("/ok", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	app.Get("/bad", func(c *fiber.Ctx) error {
+		LogRejected(c, fiber.StatusBadRequest, "validation failed")
+		return c.SendStatus(fiber.StatusBadRequest)
+	})
+	app.Get("/missing", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusNotFound) })
+	app.Get("/fail", func(c *fiber.Ctx) error { return errors.New("database is down") })
+
+	tests := []struct {
+		path      string
+		want      []zapcore.Level
+		wantError bool
+	}{
+		{"/ok", []zapcore.Level{zapcore.DebugLevel}, false},
+		{"/bad", []zapcore.Level{zapcore.WarnLevel, zapcore.WarnLevel}, false},
+		{"/missing", []zapcore.Level{zapcore.InfoLevel}, false},
+		{"/fail", []zapcore.Level{zapcore.ErrorLevel}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			logs.TakeAll()
+			if _, err := app.Test(httptest.NewRequest(fiber.MethodGet, tt.path, nil)); err != nil {
+				t.Fatal(err)
+			}
+			entries := logs.TakeAll()
+			if len(entries) != len(tt.want) {
+				t.Fatalf("got %d log entries; want %d", len(entries), len(tt.want))
+			}
+			for i, e := range entries {
+				if e.Level != tt.want[i] {
+					t.Errorf("entry %q at %v; want %v", e.Message, e.Level, tt.want[i])
+				}
+			}
+			last := entries[len(entries)-1].ContextMap()
+			if _, ok := last["error"]; ok != tt.wantError {
+				t.Errorf("request line has error field = %v; want %v", ok, tt.wantError)
+			}
+		})
+	}
+}
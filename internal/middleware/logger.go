@@ -1,10 +1,13 @@
 package middleware
 
 import (
+	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
+
+	"BACKEND/internal/tracing"
 )
 
 var logger *zap.Logger
@@ -23,12 +26,51 @@ func GetRequestLogger(c *fiber.Ctx) *zap.Logger {
 		return zap.NewNop()
 	}
 
-	requestID := GetRequestID(c)
-	if requestID != "" {
-		return logger.With(zap.String("request_id", requestID))
+	l := logger
+	if requestID := GetRequestID(c); requestID != "" {
+		l = l.With(zap.String("request_id", requestID))
+	}
+	return l.With(append(traceFields(c), actorFields(c)...)...)
+}
+
+// traceFields gives the IDs of the request's trace, so a log line can be
+// looked up alongside its spans.
+func traceFields(c *fiber.Ctx) []zap.Field {
+	traceID, spanID := tracing.IDs(c.UserContext())
+	if traceID == "" {
+		return nil
+	}
+	return []zap.Field{
+		zap.String("trace_id", traceID),
+		zap.String("span_id", spanID),
+	}
+}
+
+// actorFields labels log lines with who made the request, so actions taken
+// by service accounts can be told apart from those of people.
+func actorFields(c *fiber.Ctx) []zap.Field {
+	authUser := GetAuthUser(c)
+	if authUser == nil {
+		return nil
+	}
+	return []zap.Field{
+		zap.Int64("actor_id", authUser.ID),
+		zap.String("actor_type", authUser.AccountType),
 	}
+}
 
-	return logger
+// responseStatus returns the status a request will be answered with. An
+// error returned up the chain is only turned into a response by the app's
+// error handler, after the middleware has returned.
+func responseStatus(c *fiber.Ctx, err error) int {
+	if err == nil {
+		return c.Response().StatusCode()
+	}
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) {
+		return fiberErr.Code
+	}
+	return fiber.StatusInternalServerError
 }
 
 func Logger() fiber.Handler {
@@ -42,13 +84,25 @@ func Logger() fiber.Handler {
 		duration := time.Since(start)
 
 		if logger != nil {
-			logger.Info("request completed",
-				zap.String("method", c.Method()),
-				zap.String("path", c.Path()),
-				zap.Int("status", c.Response().StatusCode()),
-				zap.Duration("duration", duration),
-				zap.String("request_id", requestID),
-			)
+			status := responseStatus(c, err)
+			if ce := logger.Check(logPolicy.Level(status), "request completed"); ce != nil {
+				fields := []zap.Field{
+					zap.String("method", c.Method()),
+					zap.String("path", c.Path()),
+					zap.Int("status", status),
+					zap.Duration("duration", duration),
+					zap.String("request_id", requestID),
+				}
+				fields = append(fields, traceFields(c)...)
+				// Client errors are logged with the fields above only.
+				if status < fiber.StatusBadRequest || status >= fiber.StatusInternalServerError {
+					fields = append(fields, actorFields(c)...)
+				}
+				if err != nil && status >= fiber.StatusInternalServerError {
+					fields = append(fields, zap.Error(err))
+				}
+				ce.Write(fields...)
+			}
 		}
 
 		return err
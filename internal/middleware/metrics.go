@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"BACKEND/internal/models"
+)
+
+// Metrics holds the Prometheus metrics served on /metrics: request counts
+// and latencies by route and status, requests in flight, authentication
+// failures by error code and database pool stats, plus the Go runtime and
+// process metrics.
+type Metrics struct {
+	registry     *prometheus.Registry
+	requests     *prometheus.CounterVec
+	duration     *prometheus.HistogramVec
+	inFlight     prometheus.Gauge
+	authFailures *prometheus.CounterVec
+}
+
+func NewMetrics() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by method, route and status.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latencies by method, route and status.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "http_requests_in_flight",
+			Help: "HTTP requests being served.",
+		}),
+		authFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_failures_total",
+			Help: "Requests turned away with a 401, by error code.",
+		}, []string{"reason"}),
+	}
+	m.registry.MustRegister(
+		m.requests,
+		m.duration,
+		m.inFlight,
+		m.authFailures,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	return m
+}
+
+// AddDatabase reports the connection pool of the database called name,
+// reading stats on each scrape.
+func (m *Metrics) AddDatabase(name string, stats func() models.PoolStats) {
+	m.registry.MustRegister(&poolCollector{database: name, stats: stats})
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func (m *Metrics) Handler() fiber.Handler {
+	return adaptor.HTTPHandler(promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{}))
+}
+
+// CollectMetrics records each request in m. Routes are the registered
+// patterns, so a metric series covers all users rather than one.
+func CollectMetrics(m *Metrics) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if m == nil {
+			return c.Next()
+		}
+		m.inFlight.Inc()
+		defer m.inFlight.Dec()
+
+		start := time.Now()
+		err := c.Next()
+		status := responseStatus(c, err)
+		labels := prometheus.Labels{
+			"method": c.Method(),
+			"route":  c.Route().Path,
+			"status": strconv.Itoa(status),
+		}
+		m.requests.With(labels).Inc()
+		m.duration.With(labels).Observe(time.Since(start).Seconds())
+		if status == fiber.StatusUnauthorized {
+			m.authFailures.WithLabelValues(authFailureReason(c)).Inc()
+		}
+		return err
+	}
+}
+
+// authFailureReason is the error code of a 401 response, e.g.
+// INVALID_CREDENTIALS or EXPIRED_TOKEN.
+func authFailureReason(c *fiber.Ctx) string {
+	var body models.ErrorResponse
+	if err := json.Unmarshal(c.Response().Body(), &body); err != nil || body.Error.Code == "" {
+		return "unknown"
+	}
+	return body.Error.Code
+}
+
+var (
+	poolMaxConnsDesc = prometheus.NewDesc("db_pool_max_connections",
+		"Most connections the pool opens.", []string{"database"}, nil)
+	poolConnsDesc = prometheus.NewDesc("db_pool_connections",
+		"Open connections by state.", []string{"database", "state"}, nil)
+	poolWaitsDesc = prometheus.NewDesc("db_pool_waits_total",
+		"Times a query waited for a free connection.", []string{"database"}, nil)
+)
+
+// poolCollector reads a database pool's stats when scraped.
+type poolCollector struct {
+	database string
+	stats    func() models.PoolStats
+}
+
+func (p *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- poolMaxConnsDesc
+	ch <- poolConnsDesc
+	ch <- poolWaitsDesc
+}
+
+func (p *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	s := p.stats()
+	ch <- prometheus.MustNewConstMetric(poolMaxConnsDesc, prometheus.GaugeValue, float64(s.MaxConns), p.database)
+	ch <- prometheus.MustNewConstMetric(poolConnsDesc, prometheus.GaugeValue, float64(s.IdleConns), p.database, "idle")
+	ch <- prometheus.MustNewConstMetric(poolConnsDesc, prometheus.GaugeValue, float64(s.InUseConns), p.database, "in_use")
+	ch <- prometheus.MustNewConstMetric(poolWaitsDesc, prometheus.CounterValue, float64(s.WaitCount), p.database)
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/models"
+)
+
+func TestCollectMetrics(t *testing.T) {
+	m := NewMetrics()
+	m.AddDatabase("primary", func() models.PoolStats {
+		return models.PoolStats{MaxConns: 10, IdleConns: 3, InUseConns: 2, WaitCount: 7}
+	})
+
+	app := fiber.New()
+	app.Get("/metrics", BasicAuth("metrics", "prom", "secret"), m.Handler())
+	app.Use(CollectMetrics(m))
+	app.Get("/users/:id", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+	app.Get("/me", func(c *fiber.Ctx) error {
+		return models.SendError(c, fiber.StatusUnauthorized, "Token has expired", models.ErrCodeExpiredToken, "")
+	})
+
+	for _, path := range []string{"/users/1", "/users/2", "/me"} {
+		if _, err := app.Test(httptest.NewRequest(fiber.MethodGet, path, nil)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/metrics", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusUnauthorized || resp.Header.Get("WWW-Authenticate") != `Basic realm="metrics"` {
+		t.Fatalf("GET /metrics without credentials = %d, %q; want 401 with a challenge", resp.StatusCode, resp.Header.Get("WWW-Authenticate"))
+	}
+
+	req := httptest.NewRequest(fiber.MethodGet, "/metrics", nil)
+	req.SetBasicAuth("prom", "secret")
+	resp, err = app.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != fiber.StatusOK {
+		t.Fatalf("GET /metrics = %d: %s", resp.StatusCode, body)
+	}
+	for _, want := range []string{
+		`http_requests_total{method="GET",route="/users/:id",status="200"} 2`,
+		`http_request_duration_seconds_count{method="GET",route="/users/:id",status="200"} 2`,
+		`http_requests_in_flight 0`,
+		`auth_failures_total{reason="EXPIRED_TOKEN"} 1`,
+		`db_pool_connections{database="primary",state="in_use"} 2`,
+		`db_pool_waits_total{database="primary"} 7`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("metrics are missing %s", want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+)
+
+const orgScopeKey = "orgScope"
+
+// OrgMembershipResolver finds the organization a user belongs to, returning
+// pgx.ErrNoRows when they are in none.
+type OrgMembershipResolver interface {
+	MemberOf(ctx context.Context, userID int64) (int64, error)
+}
+
+// OrgScope confines org admins to their own organization; other callers
+// pass through unscoped. An org admin in no organization gets a 403. When
+// the route names a user, UserParam must run first, and a user outside the
+// org admin's organization gets a 404, as if they didn't exist.
+func OrgScope(orgs OrgMembershipResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authUser := GetAuthUser(c)
+		if authUser == nil || authUser.Role != service.RoleOrgAdmin {
+			return c.Next()
+		}
+
+		orgID, err := orgs.MemberOf(c.UserContext(), authUser.ID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				GetRequestLogger(c).Warn("org admin without an organization",
+					zap.Int64("user_id", authUser.ID),
+				)
+				return models.SendError(c, fiber.StatusForbidden, "Forbidden: you are not in an organization", models.ErrCodeInsufficientPerms, GetRequestID(c))
+			}
+			GetRequestLogger(c).Error("failed to get organization", zap.Error(err))
+			return models.SendInternalError(c, "Failed to check organization", GetRequestID(c))
+		}
+
+		if targetID, ok := GetUserID(c); ok {
+			targetOrg, err := orgs.MemberOf(c.UserContext(), targetID)
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				GetRequestLogger(c).Error("failed to get organization", zap.Error(err))
+				return models.SendInternalError(c, "Failed to check organization", GetRequestID(c))
+			}
+			if err != nil || targetOrg != orgID {
+				return models.SendNotFound(c, "User not found", GetRequestID(c))
+			}
+		}
+
+		c.Locals(orgScopeKey, orgID)
+		return c.Next()
+	}
+}
+
+// GetOrgScope returns the organization OrgScope confined the request to,
+// if any.
+func GetOrgScope(c *fiber.Ctx) (int64, bool) {
+	orgID, ok := c.Locals(orgScopeKey).(int64)
+	return orgID, ok
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/internal/models"
+)
+
+type fakeOrgMembers map[int64]int64
+
+func (f fakeOrgMembers) MemberOf(ctx context.Context, userID int64) (int64, error) {
+	if userID == 500 {
+		return 0, errors.New("database down")
+	}
+	orgID, ok := f[userID]
+	if !ok {
+		return 0, pgx.ErrNoRows
+	}
+	return orgID, nil
+}
+
+func TestOrgScope(t *testing.T) {
+	orgs := fakeOrgMembers{1: 10, 2: 10, 3: 20}
+	tests := []struct {
+		name   string
+		caller models.AuthUser
+		target int64
+		want   int
+		scope  int64
+	}{
+		{"admin is unscoped", models.AuthUser{ID: 1, Role: "admin"}, 3, fiber.StatusOK, 0},
+		{"moderator is unscoped", models.AuthUser{ID: 4, Role: "moderator"}, 0, fiber.StatusOK, 0},
+		{"org admin listing", models.AuthUser{ID: 1, Role: "org_admin"}, 0, fiber.StatusOK, 10},
+		{"org admin on a member", models.AuthUser{ID: 1, Role: "org_admin"}, 2, fiber.StatusOK, 10},
+		{"org admin on another org's user", models.AuthUser{ID: 1, Role: "org_admin"}, 3, fiber.StatusNotFound, 0},
+		{"org admin on a user in no org", models.AuthUser{ID: 1, Role: "org_admin"}, 4, fiber.StatusNotFound, 0},
+		{"org admin in no org", models.AuthUser{ID: 4, Role: "org_admin"}, 0, fiber.StatusForbidden, 0},
+		{"lookup fails", models.AuthUser{ID: 500, Role: "org_admin"}, 0, fiber.StatusInternalServerError, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			var scope int64
+			app.Get("/", func(c *fiber.Ctx) error {
+				c.Locals(AuthUserKey, tt.caller)
+				if tt.target != 0 {
+					c.Locals(userIDKey, tt.target)
+				}
+				return c.Next()
+			}, OrgScope(orgs), func(c *fiber.Ctx) error {
+				scope, _ = GetOrgScope(c)
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.want || scope != tt.scope {
+				t.Errorf("status = %d, scope = %d; want %d, %d", resp.StatusCode, scope, tt.want, tt.scope)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/models"
+	"BACKEND/internal/policy"
+)
+
+// Authorize lets a request through when engine allows the caller to perform
+// action on the resource built by resource, which may be nil for actions
+// without one. It must run after Auth or APIKey.
+func Authorize(engine *policy.Engine, action string, resource func(c *fiber.Ctx) policy.Resource) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if GetAuthUser(c) == nil {
+			return models.SendUnauthorized(c, "Unauthorized", GetRequestID(c))
+		}
+
+		var res policy.Resource
+		if resource != nil {
+			res = resource(c)
+		}
+		decision := engine.Evaluate(policy.Request{Subject: PolicySubject(c), Action: action, Resource: res})
+		if !decision.Allowed {
+			GetRequestLogger(c).Warn("policy denied request",
+				zap.String("action", action),
+				zap.String("resource_type", res.Type),
+				zap.String("resource_id", res.ID),
+			)
+			return models.SendError(c, fiber.StatusForbidden, "Forbidden: insufficient permissions", models.ErrCodeInsufficientPerms, GetRequestID(c))
+		}
+
+		GetRequestLogger(c).Debug("policy allowed request",
+			zap.String("action", action),
+			zap.String("rule", decision.Rule),
+		)
+		return c.Next()
+	}
+}
+
+// PolicySubject describes the authenticated caller for policy evaluation.
+func PolicySubject(c *fiber.Ctx) policy.Subject {
+	authUser := GetAuthUser(c)
+	if authUser == nil {
+		return policy.Subject{}
+	}
+	subject := policy.Subject{
+		ID:          authUser.ID,
+		Role:        authUser.Role,
+		AccountType: authUser.AccountType,
+	}
+	if claims := GetJWTClaims(c); claims != nil {
+		subject.Claims = claims.Extra
+	}
+	return subject
+}
+
+// UserResource is the user named by the route's :id parameter. It must run
+// after UserParam.
+func UserResource(c *fiber.Ctx) policy.Resource {
+	res := policy.Resource{Type: "user", ID: c.Params("id")}
+	if id, ok := GetUserID(c); ok {
+		res.OwnerID = id
+	}
+	return res
+}
@@ -0,0 +1,300 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/models"
+)
+
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset"
+	RateLimitWarningHeader   = "X-RateLimit-Warning"
+)
+
+// Rate limit states, from least to most severe.
+const (
+	RateLimitOK      = "ok"
+	RateLimitWarning = "warning"
+	RateLimitGrace   = "grace"
+	RateLimitBlocked = "blocked"
+)
+
+// RateLimitStore keeps rate limit counts outside the process so every
+// instance enforces the same limit. Counts are over the window ending now.
+type RateLimitStore interface {
+	// Take counts a request by client unless max requests are already in
+	// the window. It returns the requests in the window, including this one
+	// if it was counted, and when the oldest of them was made.
+	Take(ctx context.Context, client string, now time.Time, window time.Duration, max int) (count int, oldest time.Time, counted bool, err error)
+	Count(ctx context.Context, client string, now time.Time, window time.Duration) (count int, oldest time.Time, err error)
+}
+
+// RateLimiter counts requests per client, in fixed windows in memory or in
+// a sliding window in a RateLimitStore. Past warnAt of the limit responses
+// carry X-RateLimit-Warning; past the limit, requests in the grace band are
+// still served with a stronger warning, and only those beyond it are
+// refused with 429.
+type RateLimiter struct {
+	store RateLimitStore
+
+	mu        sync.Mutex
+	limit     int
+	grace     int
+	warnAt    int
+	window    time.Duration
+	clients   map[string]*rateWindow
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+type RateLimitUsage struct {
+	Enabled       bool      `json:"enabled"`
+	Client        string    `json:"client"`
+	State         string    `json:"state"`
+	Limit         int       `json:"limit"`
+	Used          int       `json:"used"`
+	Remaining     int       `json:"remaining"`
+	Grace         int       `json:"grace"`
+	WarnAt        int       `json:"warn_at"`
+	WindowSeconds float64   `json:"window_seconds"`
+	ResetAt       time.Time `json:"reset_at"`
+}
+
+// NewRateLimiter allows limit requests per window. warnPercent and
+// gracePercent are percentages of limit; the grace band is at least one
+// request so every client sees a warning before a 429.
+func NewRateLimiter(limit int, window time.Duration, warnPercent, gracePercent int) *RateLimiter {
+	if limit < 1 {
+		limit = 1
+	}
+	if window <= 0 {
+		window = time.Minute
+	}
+	warnAt := limit * warnPercent / 100
+	if warnPercent <= 0 || warnAt > limit {
+		warnAt = limit
+	}
+	grace := limit * gracePercent / 100
+	if grace < 1 {
+		grace = 1
+	}
+
+	return &RateLimiter{
+		limit:   limit,
+		grace:   grace,
+		warnAt:  warnAt,
+		window:  window,
+		clients: make(map[string]*rateWindow),
+		now:     time.Now,
+	}
+}
+
+// SetStore moves the counts to store, to share them between instances.
+func (l *RateLimiter) SetStore(store RateLimitStore) {
+	l.store = store
+}
+
+// SetGrace overrides the grace band. With 0, requests are refused as soon
+// as the limit is reached.
+func (l *RateLimiter) SetGrace(requests int) {
+	l.grace = max(requests, 0)
+}
+
+// take counts a request from client and returns the usage including it.
+func (l *RateLimiter) take(ctx context.Context, client string) (RateLimitUsage, error) {
+	if l.store != nil {
+		count, oldest, counted, err := l.store.Take(ctx, client, l.now(), l.window, l.limit+l.grace)
+		if err != nil {
+			return RateLimitUsage{}, err
+		}
+		state := ""
+		if !counted {
+			state = RateLimitBlocked
+		}
+		return l.usage(client, count, oldest.Add(l.window), state), nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweepLocked(now)
+
+	w := l.windowLocked(client, now)
+	if w.count < l.limit+l.grace {
+		w.count++
+	} else {
+		// Blocked requests don't extend the block.
+		return l.usage(client, w.count, w.start.Add(l.window), RateLimitBlocked), nil
+	}
+	return l.usage(client, w.count, w.start.Add(l.window), ""), nil
+}
+
+// Usage reports client's usage without counting a request.
+func (l *RateLimiter) Usage(ctx context.Context, client string) (RateLimitUsage, error) {
+	if l == nil {
+		return RateLimitUsage{Enabled: false}, nil
+	}
+	if l.store != nil {
+		now := l.now()
+		count, oldest, err := l.store.Count(ctx, client, now, l.window)
+		if err != nil {
+			return RateLimitUsage{}, err
+		}
+		return l.usage(client, count, oldest.Add(l.window), ""), nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	w, ok := l.clients[client]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &rateWindow{start: now}
+	}
+	return l.usage(client, w.count, w.start.Add(l.window), ""), nil
+}
+
+func (l *RateLimiter) windowLocked(client string, now time.Time) *rateWindow {
+	w, ok := l.clients[client]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &rateWindow{start: now}
+		l.clients[client] = w
+	}
+	return w
+}
+
+func (l *RateLimiter) usage(client string, used int, resetAt time.Time, state string) RateLimitUsage {
+	if state == "" {
+		switch {
+		case used > l.limit:
+			state = RateLimitGrace
+		case used >= l.warnAt:
+			state = RateLimitWarning
+		default:
+			state = RateLimitOK
+		}
+	}
+	remaining := l.limit - used
+	if remaining < 0 {
+		remaining = 0
+	}
+	return RateLimitUsage{
+		Enabled:       true,
+		Client:        client,
+		State:         state,
+		Limit:         l.limit,
+		Used:          used,
+		Remaining:     remaining,
+		Grace:         l.grace,
+		WarnAt:        l.warnAt,
+		WindowSeconds: l.window.Seconds(),
+		ResetAt:       resetAt,
+	}
+}
+
+// sweepLocked drops expired windows at most once per window so idle
+// clients don't accumulate.
+func (l *RateLimiter) sweepLocked(now time.Time) {
+	if now.Sub(l.lastSweep) < l.window {
+		return
+	}
+	for client, w := range l.clients {
+		if now.Sub(w.start) >= l.window {
+			delete(l.clients, client)
+		}
+	}
+	l.lastSweep = now
+}
+
+// RateLimitClient identifies the caller: the authenticated user when there
+// is one, otherwise the client IP.
+func RateLimitClient(c *fiber.Ctx) string {
+	if user := GetAuthUser(c); user != nil {
+		return "user:" + strconv.Itoa(int(user.ID))
+	}
+	return "ip:" + c.IP()
+}
+
+// RateLimitEmail identifies the account a request names by its JSON
+// "email" field, so requests about one account share a limit whichever IP
+// they come from. Requests without one are counted by RateLimitClient.
+func RateLimitEmail(c *fiber.Ctx) string {
+	var body struct {
+		Email string `json:"email"`
+	}
+	if err := json.Unmarshal(c.Body(), &body); err == nil && body.Email != "" {
+		return "email:" + strings.ToLower(strings.TrimSpace(body.Email))
+	}
+	return RateLimitClient(c)
+}
+
+// RateLimit applies l to each request. Install it after authentication so
+// signed-in users are counted per account rather than per IP. Requests are
+// let through uncounted when l's store can't be reached.
+func RateLimit(l *RateLimiter) fiber.Handler {
+	return rateLimit(l, RateLimitClient)
+}
+
+// RateLimitBy applies l to each request, counted by scope and key. Routes
+// sharing l with different scopes get separate budgets.
+func RateLimitBy(l *RateLimiter, scope string, key func(c *fiber.Ctx) string) fiber.Handler {
+	return rateLimit(l, func(c *fiber.Ctx) string {
+		return scope + ":" + key(c)
+	})
+}
+
+func rateLimit(l *RateLimiter, key func(c *fiber.Ctx) string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if l == nil {
+			return c.Next()
+		}
+
+		usage, err := l.take(c.UserContext(), key(c))
+		if err != nil {
+			GetRequestLogger(c).Error("rate limit store unavailable", zap.Error(err))
+			return c.Next()
+		}
+		resetIn := int(math.Ceil(usage.ResetAt.Sub(l.now()).Seconds()))
+		if resetIn < 1 {
+			resetIn = 1
+		}
+		c.Set(RateLimitLimitHeader, strconv.Itoa(usage.Limit))
+		c.Set(RateLimitRemainingHeader, strconv.Itoa(usage.Remaining))
+		c.Set(RateLimitResetHeader, strconv.Itoa(resetIn))
+
+		switch usage.State {
+		case RateLimitWarning:
+			c.Set(RateLimitWarningHeader, fmt.Sprintf("approaching rate limit: %d of %d requests used, resets in %ds", usage.Used, usage.Limit, resetIn))
+		case RateLimitGrace:
+			c.Set(RateLimitWarningHeader, fmt.Sprintf("rate limit exceeded: %d of %d requests used, %d more will be served before requests are refused, resets in %ds", usage.Used, usage.Limit, usage.Limit+usage.Grace-usage.Used, resetIn))
+		case RateLimitBlocked:
+			GetRequestLogger(c).Warn("rate limit exceeded",
+				zap.String("client", usage.Client),
+				zap.String("path", c.Path()),
+			)
+			c.Set(RateLimitWarningHeader, fmt.Sprintf("rate limit exceeded: requests are refused until the limit resets in %ds", resetIn))
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(resetIn))
+			return models.SendError(c, fiber.StatusTooManyRequests, "Rate limit exceeded, please retry later", models.ErrCodeRateLimited, GetRequestID(c))
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"time"
+
+	"BACKEND/internal/redis"
+)
+
+// slidingWindowScript keeps each client's requests in a sorted set scored
+// by time in microseconds. It drops requests older than the window, adds
+// this one unless the window is full, and returns the count, whether it
+// was added, and the oldest request's time.
+const slidingWindowScript = `
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', ARGV[1] - ARGV[2])
+local count = redis.call('ZCARD', KEYS[1])
+local added = 0
+if tonumber(ARGV[3]) > 0 and count < tonumber(ARGV[3]) then
+	redis.call('ZADD', KEYS[1], ARGV[1], ARGV[4])
+	count = count + 1
+	added = 1
+end
+redis.call('PEXPIRE', KEYS[1], math.ceil(ARGV[2] / 1000))
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+return {count, added, oldest[2] or ARGV[1]}
+`
+
+// RedisRateLimitStore shares rate limit counts between instances through
+// Redis, counting requests in a sliding window.
+type RedisRateLimitStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisRateLimitStore keeps each client's requests under prefix plus
+// the client.
+func NewRedisRateLimitStore(client *redis.Client, prefix string) *RedisRateLimitStore {
+	return &RedisRateLimitStore{client: client, prefix: prefix}
+}
+
+func (s *RedisRateLimitStore) Take(ctx context.Context, client string, now time.Time, window time.Duration, max int) (int, time.Time, bool, error) {
+	return s.eval(ctx, client, now, window, max)
+}
+
+func (s *RedisRateLimitStore) Count(ctx context.Context, client string, now time.Time, window time.Duration) (int, time.Time, error) {
+	count, oldest, _, err := s.eval(ctx, client, now, window, 0)
+	return count, oldest, err
+}
+
+func (s *RedisRateLimitStore) eval(ctx context.Context, client string, now time.Time, window time.Duration, max int) (int, time.Time, bool, error) {
+	micros := now.UnixMicro()
+	// Requests in the same microsecond need distinct members.
+	member := strconv.FormatInt(micros, 10) + "-" + strconv.FormatUint(rand.Uint64(), 36)
+	reply, err := s.client.Do(ctx, "EVAL", slidingWindowScript, "1", s.prefix+client,
+		strconv.FormatInt(micros, 10),
+		strconv.FormatInt(window.Microseconds(), 10),
+		strconv.Itoa(max),
+		member,
+	)
+	if err != nil {
+		return 0, time.Time{}, false, err
+	}
+
+	items, ok := reply.([]interface{})
+	if !ok || len(items) != 3 {
+		return 0, time.Time{}, false, fmt.Errorf("unexpected rate limit reply %v", reply)
+	}
+	count, ok1 := items[0].(int64)
+	added, ok2 := items[1].(int64)
+	oldest, ok3 := items[2].(string)
+	if !ok1 || !ok2 || !ok3 {
+		return 0, time.Time{}, false, fmt.Errorf("unexpected rate limit reply %v", reply)
+	}
+	oldestMicros, err := strconv.ParseFloat(oldest, 64)
+	if err != nil {
+		return 0, time.Time{}, false, fmt.Errorf("unexpected rate limit reply %v", reply)
+	}
+	return int(count), time.UnixMicro(int64(oldestMicros)), added == 1, nil
+}
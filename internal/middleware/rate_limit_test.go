@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestRateLimit_WarnsThenGraceThenBlocks(t *testing.T) {
+	l := NewRateLimiter(10, time.Minute, 80, 20)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	app := fiber.New()
+	app.Use(RateLimit(l))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	// Requests 1-7 are quiet, 8-10 warn, 11-12 are the grace band and 13 is
+	// refused.
+	for i := 1; i <= 13; i++ {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		if err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+		warning := resp.Header.Get(RateLimitWarningHeader)
+
+		switch {
+		case i < 8:
+			if resp.StatusCode != fiber.StatusOK || warning != "" {
+				t.Errorf("request %d: status %d, warning %q; want 200 without a warning", i, resp.StatusCode, warning)
+			}
+		case i <= 12:
+			if resp.StatusCode != fiber.StatusOK || warning == "" {
+				t.Errorf("request %d: status %d, warning %q; want 200 with a warning", i, resp.StatusCode, warning)
+			}
+		default:
+			if resp.StatusCode != fiber.StatusTooManyRequests || resp.Header.Get(fiber.HeaderRetryAfter) != "60" {
+				t.Errorf("request %d: status %d, Retry-After %q; want 429 with Retry-After 60", i, resp.StatusCode, resp.Header.Get(fiber.HeaderRetryAfter))
+			}
+		}
+		if i == 5 && resp.Header.Get(RateLimitRemainingHeader) != "5" {
+			t.Errorf("request 5: %s = %q; want 5", RateLimitRemainingHeader, resp.Header.Get(RateLimitRemainingHeader))
+		}
+	}
+
+	usage, _ := l.Usage(context.Background(), "ip:0.0.0.0")
+	if usage.Used != 12 || usage.State != RateLimitGrace {
+		t.Errorf("Usage() = %+v; want 12 used in the grace band", usage)
+	}
+
+	now = now.Add(time.Minute)
+	resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+	if err != nil {
+		t.Fatalf("app.Test failed: %v", err)
+	}
+	if resp.StatusCode != fiber.StatusOK || resp.Header.Get(RateLimitWarningHeader) != "" {
+		t.Errorf("after the window: status %d; want 200 without a warning", resp.StatusCode)
+	}
+}
+
+func TestRateLimiter_UsageDisabled(t *testing.T) {
+	var l *RateLimiter
+	if usage, _ := l.Usage(context.Background(), "ip:203.0.113.7"); usage.Enabled {
+		t.Errorf("Usage() on a nil limiter = %+v; want disabled", usage)
+	}
+}
+
+// slidingStore is an in-memory RateLimitStore with a sliding window.
+type slidingStore struct {
+	requests map[string][]time.Time
+	err      error
+}
+
+func (s *slidingStore) Take(ctx context.Context, client string, now time.Time, window time.Duration, max int) (int, time.Time, bool, error) {
+	count, oldest, err := s.Count(ctx, client, now, window)
+	if err != nil || count >= max {
+		return count, oldest, false, err
+	}
+	s.requests[client] = append(s.requests[client], now)
+	return count + 1, s.requests[client][0], true, nil
+}
+
+func (s *slidingStore) Count(ctx context.Context, client string, now time.Time, window time.Duration) (int, time.Time, error) {
+	if s.err != nil {
+		return 0, time.Time{}, s.err
+	}
+	var kept []time.Time
+	for _, t := range s.requests[client] {
+		if now.Sub(t) < window {
+			kept = append(kept, t)
+		}
+	}
+	s.requests[client] = kept
+	if len(kept) == 0 {
+		return 0, now, nil
+	}
+	return len(kept), kept[0], nil
+}
+
+func TestRateLimit_Store(t *testing.T) {
+	l := NewRateLimiter(2, time.Minute, 100, 50)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+	store := &slidingStore{requests: make(map[string][]time.Time)}
+	l.SetStore(store)
+
+	app := fiber.New()
+	app.Use(RateLimit(l))
+	app.Get("/", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	get := func() int {
+		resp, err := app.Test(httptest.NewRequest("GET", "/", nil))
+		if err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	// The limit of 2 plus one request of grace, 10 seconds apart.
+	start := now
+	for i := 0; i < 3; i++ {
+		if status := get(); status != fiber.StatusOK {
+			t.Fatalf("request %d: status %d; want 200", i+1, status)
+		}
+		now = now.Add(10 * time.Second)
+	}
+	if status := get(); status != fiber.StatusTooManyRequests {
+		t.Errorf("request 4: status %d; want 429", status)
+	}
+
+	// The window slides: once the first request is a minute old there is
+	// room for one more.
+	now = start.Add(time.Minute)
+	if status := get(); status != fiber.StatusOK {
+		t.Errorf("after the first request left the window: status %d; want 200", status)
+	}
+	if usage, _ := l.Usage(context.Background(), "ip:0.0.0.0"); usage.Used != 3 {
+		t.Errorf("Usage() = %+v; want 3 used", usage)
+	}
+
+	store.err = errors.New("connection refused")
+	if status := get(); status != fiber.StatusOK {
+		t.Errorf("store down: status %d; want the request let through", status)
+	}
+}
+
+func TestRateLimitBy_SeparateScopesWithoutGrace(t *testing.T) {
+	l := NewRateLimiter(2, time.Minute, 0, 0)
+	l.SetGrace(0)
+
+	app := fiber.New()
+	app.Post("/reset", RateLimitBy(l, "reset", RateLimitClient), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Post("/link", RateLimitBy(l, "link", RateLimitEmail), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	post := func(path, body string) int {
+		t.Helper()
+		req := httptest.NewRequest("POST", path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatalf("app.Test failed: %v", err)
+		}
+		return resp.StatusCode
+	}
+
+	for i, want := range []int{200, 200, 429} {
+		if got := post("/reset", ""); got != want {
+			t.Errorf("reset %d: status %d; want %d", i+1, got, want)
+		}
+	}
+	// The exhausted reset budget doesn't touch the link one, which is kept
+	// per email.
+	for i, want := range []int{200, 200, 429} {
+		if got := post("/link", `{"email":"Jane@example.com"}`); got != want {
+			t.Errorf("link %d: status %d; want %d", i+1, got, want)
+		}
+	}
+	if got := post("/link", `{"email":"john@example.com"}`); got != 200 {
+		t.Errorf("link for another email: status %d; want 200", got)
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/models"
+	"BACKEND/internal/policy"
+)
+
+func TestRequireRoleHierarchy(t *testing.T) {
+	tests := []struct {
+		name      string
+		hierarchy []string
+		role      string
+		required  string
+		want      int
+	}{
+		{"same role", policy.DefaultRoleHierarchy, "moderator", "moderator", fiber.StatusOK},
+		{"higher role", policy.DefaultRoleHierarchy, "admin", "moderator", fiber.StatusOK},
+		{"lower role", policy.DefaultRoleHierarchy, "user", "moderator", fiber.StatusForbidden},
+		{"role outside hierarchy", policy.DefaultRoleHierarchy, "auditor", "user", fiber.StatusForbidden},
+		{"required role outside hierarchy", policy.DefaultRoleHierarchy, "admin", "auditor", fiber.StatusForbidden},
+		{"custom hierarchy", []string{"owner", "admin", "user"}, "owner", "admin", fiber.StatusOK},
+		{"flat hierarchy", nil, "admin", "user", fiber.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies := policy.NewEngine()
+			policies.SetRoleHierarchy(tt.hierarchy)
+			app := fiber.New()
+			app.Get("/", func(c *fiber.Ctx) error {
+				c.Locals(AuthUserKey, models.AuthUser{ID: 1, Role: tt.role})
+				return c.Next()
+			}, RequireRole(policies, tt.required), func(c *fiber.Ctx) error {
+				return c.SendStatus(fiber.StatusOK)
+			})
+
+			resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.want {
+				t.Errorf("status = %d; want %d", resp.StatusCode, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/models"
+)
+
+func SCIMAuth(token string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		authHeader := c.Get("Authorization")
+		parts := strings.Fields(authHeader)
+		if len(parts) != 2 || !strings.EqualFold(parts[0], "bearer") ||
+			subtle.ConstantTimeCompare([]byte(parts[1]), []byte(token)) != 1 {
+			if logger != nil {
+				logger.Warn("scim authentication failed", zap.String("path", c.Path()))
+			}
+			return models.SendSCIMError(c, fiber.StatusUnauthorized, "", "Invalid or missing SCIM bearer token")
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,153 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/redis"
+)
+
+const idleSessionsKey = "idleSessions"
+
+// SessionActivityStore keeps when each token was last used outside the
+// process, so a token used on one instance stays live on the others.
+type SessionActivityStore interface {
+	// Touch records a use of the token jti at now unless it has been idle
+	// for longer than timeout, and reports whether it was recorded. A token
+	// not seen before counts as used when it was issued.
+	Touch(ctx context.Context, jti string, issuedAt, now time.Time, timeout time.Duration) (bool, error)
+}
+
+// IdleSessions ends a token's session once it goes unused for longer than
+// the timeout, however long the token itself is valid for. Each request
+// made with the token starts the timeout over. Activity is kept in memory
+// unless a SessionActivityStore is set.
+type IdleSessions struct {
+	timeout time.Duration
+	store   SessionActivityStore
+
+	mu        sync.Mutex
+	lastUsed  map[string]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+func NewIdleSessions(timeout time.Duration) *IdleSessions {
+	return &IdleSessions{
+		timeout:  timeout,
+		lastUsed: make(map[string]time.Time),
+		now:      time.Now,
+	}
+}
+
+// SetStore moves the activity to store, to share it between instances.
+func (s *IdleSessions) SetStore(store SessionActivityStore) {
+	s.store = store
+}
+
+// touch records a use of the token jti issued at issuedAt and reports
+// whether its session was still live.
+func (s *IdleSessions) touch(ctx context.Context, jti string, issuedAt time.Time) (bool, error) {
+	now := s.now()
+	if s.store != nil {
+		return s.store.Touch(ctx, jti, issuedAt, now, s.timeout)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now.Sub(s.lastSweep) >= s.timeout {
+		for id, used := range s.lastUsed {
+			if now.Sub(used) > s.timeout {
+				delete(s.lastUsed, id)
+			}
+		}
+		s.lastSweep = now
+	}
+	used, ok := s.lastUsed[jti]
+	if !ok {
+		used = issuedAt
+	}
+	if now.Sub(used) > s.timeout {
+		delete(s.lastUsed, jti)
+		return false, nil
+	}
+	s.lastUsed[jti] = now
+	return true, nil
+}
+
+// SessionIdleTimeout makes every Auth further down the chain reject tokens
+// whose session has been idle for longer than sessions' timeout. A nil
+// sessions turns the timeout off.
+func SessionIdleTimeout(sessions *IdleSessions) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if sessions != nil {
+			c.Locals(idleSessionsKey, sessions)
+		}
+		return c.Next()
+	}
+}
+
+// isSessionIdle touches the session of a token and reports whether it had
+// already timed out. Tokens without an ID or issue time predate the
+// timeout and aren't checked; neither are any when the store can't be
+// reached.
+func isSessionIdle(c *fiber.Ctx, jti string, issuedAt time.Time) bool {
+	sessions, ok := c.Locals(idleSessionsKey).(*IdleSessions)
+	if !ok || jti == "" || issuedAt.IsZero() {
+		return false
+	}
+	live, err := sessions.touch(c.UserContext(), jti, issuedAt)
+	if err != nil {
+		GetRequestLogger(c).Error("session activity store unavailable", zap.Error(err))
+		return false
+	}
+	return !live
+}
+
+// touchSessionScript refreshes the key of a live session, or creates it
+// for a token issued within the timeout, and returns whether it did.
+const touchSessionScript = `
+if redis.call('EXISTS', KEYS[1]) == 1 or ARGV[2] == '1' then
+	redis.call('SET', KEYS[1], '1', 'PX', ARGV[1])
+	return 1
+end
+return 0
+`
+
+// RedisSessionActivityStore keeps a key per live session in Redis that
+// expires after the timeout, so the key vanishing is the session ending.
+type RedisSessionActivityStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisSessionActivityStore keeps each session under prefix plus the
+// token's ID.
+func NewRedisSessionActivityStore(client *redis.Client, prefix string) *RedisSessionActivityStore {
+	return &RedisSessionActivityStore{client: client, prefix: prefix}
+}
+
+func (s *RedisSessionActivityStore) Touch(ctx context.Context, jti string, issuedAt, now time.Time, timeout time.Duration) (bool, error) {
+	fresh := "0"
+	if now.Sub(issuedAt) <= timeout {
+		fresh = "1"
+	}
+	reply, err := s.client.Do(ctx, "EVAL", touchSessionScript, "1", s.prefix+jti,
+		strconv.FormatInt(timeout.Milliseconds(), 10),
+		fresh,
+	)
+	if err != nil {
+		return false, err
+	}
+	touched, ok := reply.(int64)
+	if !ok {
+		return false, fmt.Errorf("unexpected session activity reply %v", reply)
+	}
+	return touched == 1, nil
+}
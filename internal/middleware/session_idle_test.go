@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/models"
+	"BACKEND/internal/service"
+)
+
+func TestAuthEndsIdleSessions(t *testing.T) {
+	auth := service.NewAuthService(nil)
+	auth.SetJWTConfig("secret", 24*time.Hour)
+	newToken := func() string {
+		token, err := auth.GenerateJWT(context.Background(), 7, "user")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	token, other := newToken(), newToken()
+
+	issued := time.Now()
+	now := issued
+	sessions := NewIdleSessions(15 * time.Minute)
+	sessions.now = func() time.Time { return now }
+
+	app := fiber.New()
+	app.Use(SessionIdleTimeout(sessions))
+	app.Get("/", Auth(AuthConfig{Secret: "secret"}), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	get := func(token string) (int, string) {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var body models.ErrorResponse
+		json.NewDecoder(resp.Body).Decode(&body)
+		return resp.StatusCode, body.Error.Code
+	}
+
+	// Used every 10 minutes, the session outlives several timeouts.
+	for i := 0; i < 6; i++ {
+		if status, _ := get(token); status != fiber.StatusOK {
+			t.Fatalf("after %v: status %d while in use", now.Sub(issued), status)
+		}
+		now = now.Add(10 * time.Minute)
+	}
+
+	now = now.Add(6 * time.Minute)
+	if status, code := get(token); status != fiber.StatusUnauthorized || code != models.ErrCodeSessionIdle {
+		t.Errorf("after 16 idle minutes: %d %s; want 401 %s", status, code, models.ErrCodeSessionIdle)
+	}
+	if status, _ := get(token); status != fiber.StatusUnauthorized {
+		t.Errorf("an ended session came back: status %d", status)
+	}
+	// A token first used after the timeout was idle since it was issued.
+	if status, code := get(other); status != fiber.StatusUnauthorized || code != models.ErrCodeSessionIdle {
+		t.Errorf("token unused since issue: %d %s; want 401 %s", status, code, models.ErrCodeSessionIdle)
+	}
+	// Logging in again starts a new session.
+	fresh := newToken()
+	now = time.Now()
+	if status, _ := get(fresh); status != fiber.StatusOK {
+		t.Errorf("new token: status %d", status)
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const StrictJSONHeader = "X-Strict-JSON"
+
+// StrictJSON decides per request whether JSON bodies with unknown fields are
+// rejected. The X-Strict-JSON header, when it parses as a boolean, overrides
+// the configured default.
+func StrictJSON(enabled bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		strict := enabled
+		if v, err := strconv.ParseBool(c.Get(StrictJSONHeader)); err == nil {
+			strict = v
+		}
+		c.Locals("strictJSON", strict)
+
+		return c.Next()
+	}
+}
+
+func IsStrictJSON(c *fiber.Ctx) bool {
+	strict, _ := c.Locals("strictJSON").(bool)
+	return strict
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const tokenRevocationsKey = "tokenRevocations"
+
+// TokenRevocationChecker reports whether a user's token issued at issuedAt
+// has been revoked, e.g. by an admin forcing a logout.
+type TokenRevocationChecker interface {
+	IsRevoked(userID int64, issuedAt time.Time) bool
+}
+
+// RevokedTokenChecker reports whether a single token, identified by its
+// jti claim, was revoked by logging out. A TokenRevocationChecker that
+// also implements it is consulted for both.
+type RevokedTokenChecker interface {
+	IsTokenRevoked(jti string) bool
+}
+
+// TokenRevocation makes every Auth further down the chain reject tokens the
+// checker reports as revoked.
+func TokenRevocation(checker TokenRevocationChecker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if checker != nil {
+			c.Locals(tokenRevocationsKey, checker)
+		}
+		return c.Next()
+	}
+}
+
+func isTokenRevoked(c *fiber.Ctx, userID int64, issuedAt time.Time) bool {
+	checker, ok := c.Locals(tokenRevocationsKey).(TokenRevocationChecker)
+	return ok && checker.IsRevoked(userID, issuedAt)
+}
+
+func isTokenIDRevoked(c *fiber.Ctx, jti string) bool {
+	checker, ok := c.Locals(tokenRevocationsKey).(RevokedTokenChecker)
+	return ok && jti != "" && checker.IsTokenRevoked(jti)
+}
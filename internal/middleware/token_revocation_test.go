@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/service"
+)
+
+// fakeRevocations revokes single tokens by ID and none by user.
+type fakeRevocations struct {
+	tokens map[string]bool
+}
+
+func (f *fakeRevocations) IsRevoked(userID int64, issuedAt time.Time) bool {
+	return false
+}
+
+func (f *fakeRevocations) IsTokenRevoked(jti string) bool {
+	return f.tokens[jti]
+}
+
+func TestAuthRejectsLoggedOutToken(t *testing.T) {
+	auth := service.NewAuthService(nil)
+	auth.SetJWTConfig("secret", time.Hour)
+	token, err := auth.GenerateJWT(context.Background(), 7, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := auth.ParseJWT(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	revocations := &fakeRevocations{tokens: map[string]bool{}}
+	app := fiber.New()
+	app.Use(TokenRevocation(revocations))
+	app.Get("/", Auth(AuthConfig{Secret: "secret"}), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+	get := func() int {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode
+	}
+
+	if got := get(); got != fiber.StatusOK {
+		t.Fatalf("before logout: status %d", got)
+	}
+	revocations.tokens[claims.ID] = true
+	if got := get(); got != fiber.StatusUnauthorized {
+		t.Errorf("after logout: status %d, want %d", got, fiber.StatusUnauthorized)
+	}
+}
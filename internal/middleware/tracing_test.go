@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"BACKEND/internal/tracing"
+)
+
+func TestTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	InitLogger(zap.New(core))
+	defer InitLogger(nil)
+
+	app := fiber.New()
+	app.Use(RequestID())
+	app.Use(Tracing())
+	app.Use(Logger())
+	app.Get("/users/:id", func(c *fiber.Ctx) error {
+		_, span := tracing.Start(c.UserContext(), "UserService.GetUserWithAge")
+		span.End()
+		GetRequestLogger(c).Info("looked up user")
+		return c.SendStatus(fiber.StatusOK)
+	})
+	app.Get("/fail", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusInternalServerError) })
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(fiber.MethodGet, "/users/42", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	req.Header.Set("X-Request-ID", "req-1")
+	if _, err := app.Test(req); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans; want the service span and the request span", len(spans))
+	}
+	child, server := spans[0], spans[1]
+	if server.Name != "GET /users/:id" {
+		t.Errorf("request span is named %q; want %q", server.Name, "GET /users/:id")
+	}
+	if got := server.SpanContext.TraceID().String(); got != traceID {
+		t.Errorf("request span has trace %s; want the caller's %s", got, traceID)
+	}
+	if server.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("request span's parent is %s; want the caller's span", server.Parent.SpanID())
+	}
+	if child.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Errorf("service span isn't a child of the request span")
+	}
+
+	entries := logs.TakeAll()
+	if len(entries) != 2 {
+		t.Fatalf("got %d log entries; want 2", len(entries))
+	}
+	for _, e := range entries {
+		fields := e.ContextMap()
+		if fields["request_id"] != "req-1" || fields["trace_id"] != traceID {
+			t.Errorf("%q has request_id %v and trace_id %v; want req-1 and %s", e.Message, fields["request_id"], fields["trace_id"], traceID)
+		}
+	}
+
+	exporter.Reset()
+	if _, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/fail", nil)); err != nil {
+		t.Fatal(err)
+	}
+	spans = exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Status.Code != codes.Error {
+		t.Errorf("a 500 should leave one failed span; got %+v", spans)
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/models"
+)
+
+// UsageRecorder counts API calls by authenticated users, for metering.
+type UsageRecorder interface {
+	RecordCall(user models.AuthUser)
+}
+
+// CountCalls records a call for the authenticated user of every request
+// that reaches it with each recorder, so it goes after Auth and RateLimit.
+// Nil recorders count nothing.
+func CountCalls(recorders ...UsageRecorder) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if user := GetAuthUser(c); user != nil {
+			for _, recorder := range recorders {
+				if recorder != nil {
+					recorder.RecordCall(*user)
+				}
+			}
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/models"
+)
+
+const userIDKey = "userID"
+
+// UserIDResolver maps the public ID the API shows for a user to their
+// internal one.
+type UserIDResolver interface {
+	GetIDByPublicID(ctx context.Context, publicID string) (int64, error)
+}
+
+// UserParam resolves the public ID in the route's :id parameter, for
+// GetUserID and UserResource further down the chain. Malformed IDs get a 400
+// and unknown ones a 404.
+func UserParam(resolver UserIDResolver) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		publicID, err := uuid.Parse(c.Params("id"))
+		if err != nil {
+			return models.SendBadRequest(c, "Invalid user ID", GetRequestID(c))
+		}
+
+		id, err := resolver.GetIDByPublicID(c.UserContext(), publicID.String())
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return models.SendNotFound(c, "User not found", GetRequestID(c))
+			}
+			GetRequestLogger(c).Error("failed to resolve user id", zap.Error(err))
+			return models.SendInternalError(c, "Failed to retrieve user", GetRequestID(c))
+		}
+
+		c.Locals(userIDKey, id)
+		return c.Next()
+	}
+}
+
+// GetUserID returns the internal ID of the user named by the route, as
+// resolved by UserParam.
+func GetUserID(c *fiber.Ctx) (int64, bool) {
+	id, ok := c.Locals(userIDKey).(int64)
+	return id, ok
+}
@@ -0,0 +1,14 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/version"
+)
+
+func APIVersion() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set("X-API-Version", version.Version)
+		return c.Next()
+	}
+}
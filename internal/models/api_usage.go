@@ -0,0 +1,34 @@
+package models
+
+// APIUsageDay is a day's requests with one API key. APIKeyID is 0 for
+// requests made with a token.
+type APIUsageDay struct {
+	Day      string `json:"day" format:"date"`
+	APIKeyID int64  `json:"api_key_id"`
+	KeyName  string `json:"key_name,omitempty"`
+	Requests int64  `json:"requests"`
+}
+
+// APIUsageResponse reports a user's requests from From to To, inclusive.
+type APIUsageResponse struct {
+	From     string        `json:"from" format:"date"`
+	To       string        `json:"to" format:"date"`
+	Requests int64         `json:"requests"`
+	Days     []APIUsageDay `json:"days"`
+}
+
+type APIUsageConsumer struct {
+	UserID   string `json:"user_id" format:"uuid"`
+	Name     string `json:"name"`
+	APIKeyID int64  `json:"api_key_id"`
+	KeyName  string `json:"key_name,omitempty"`
+	Requests int64  `json:"requests"`
+}
+
+// TopAPIUsageResponse lists the users and keys with the most requests from
+// From to To, inclusive, most first.
+type TopAPIUsageResponse struct {
+	From      string             `json:"from" format:"date"`
+	To        string             `json:"to" format:"date"`
+	Consumers []APIUsageConsumer `json:"consumers"`
+}
@@ -1,5 +1,10 @@
 package models
 
+import (
+	"time"
+
+	"BACKEND/internal/webauthn"
+)
 
 type SignupRequest struct {
 	Name     string `json:"name" validate:"required,min=2"`
@@ -9,11 +14,11 @@ type SignupRequest struct {
 }
 
 type SignupResponse struct {
-	ID        int32  `json:"id"`
+	ID        string `json:"id" format:"uuid"`
 	Name      string `json:"name"`
 	Email     string `json:"email"`
 	Role      string `json:"role"`
-	CreatedAt string `json:"created_at"`
+	CreatedAt string `json:"created_at" format:"date-time"`
 }
 
 type LoginRequest struct {
@@ -21,17 +26,150 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+type MagicLinkRequest struct {
+	Email  string `json:"email" validate:"required,email"`
+	Locale string `json:"locale"`
+}
+
+type PasswordResetRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required"`
+}
+
+type PasskeyRegisterRequest struct {
+	Name       string                        `json:"name" validate:"max=64"`
+	Credential webauthn.RegistrationResponse `json:"credential"`
+}
+
+type PasskeyResponse struct {
+	ID         int64      `json:"id"`
+	Name       string     `json:"name"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+type PasskeyListResponse struct {
+	Total    int               `json:"total"`
+	Passkeys []PasskeyResponse `json:"passkeys"`
+}
+
+// SessionResponse is one of the caller's live sessions. Current marks the
+// session of the token the request was made with.
+type SessionResponse struct {
+	ID        string    `json:"id"`
+	IPAddress string    `json:"ip_address,omitempty"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Current   bool      `json:"current"`
+}
+
+type SessionListResponse struct {
+	Total    int               `json:"total"`
+	Sessions []SessionResponse `json:"sessions"`
+}
+
+type IdentityResponse struct {
+	Provider string    `json:"provider"`
+	Email    string    `json:"email,omitempty"`
+	LinkedAt time.Time `json:"linked_at"`
+}
+
+// IdentityListResponse lists the caller's linked identities and the
+// providers that can be linked.
+type IdentityListResponse struct {
+	Total      int                `json:"total"`
+	Identities []IdentityResponse `json:"identities"`
+	Providers  []string           `json:"providers"`
+}
+
+type SSODiscoverRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+type SSODiscoverResponse struct {
+	SSO      bool   `json:"sso"`
+	LoginURL string `json:"login_url,omitempty"`
+}
+
+// DeviceGrantType is the grant_type a device sends when polling for its token.
+const DeviceGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+type DeviceTokenRequest struct {
+	GrantType  string `json:"grant_type" form:"grant_type" validate:"required"`
+	DeviceCode string `json:"device_code" form:"device_code" validate:"required"`
+}
+
+type DeviceApproveRequest struct {
+	UserCode string `json:"user_code" validate:"required"`
+}
+
+type DeviceTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// OAuthErrorResponse is the RFC 6749 error body, which device clients expect
+// instead of the usual error envelope.
+type OAuthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
+
 type LoginResponse struct {
 	Message string `json:"message"`
 	User    struct {
-		ID    int32  `json:"id"`
+		ID    string `json:"id" format:"uuid"`
 		Name  string `json:"name"`
 		Email string `json:"email"`
 		Role  string `json:"role"`
 	} `json:"user"`
+	// RefreshToken is also set as the refresh_token cookie. It is left out
+	// when refresh tokens are disabled.
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// LoginChallengeResponse answers a login that has to be confirmed with the
+// code emailed to the user before it completes.
+type LoginChallengeResponse struct {
+	Message     string    `json:"message"`
+	ChallengeID string    `json:"challenge_id"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+type LoginChallengeRequest struct {
+	ChallengeID string `json:"challenge_id" validate:"required"`
+	Code        string `json:"code" validate:"required"`
+}
+
+// RefreshRequest carries the refresh token for clients that don't send the
+// refresh_token cookie.
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// ClaimsResponse is what the caller's credentials say about them, without
+// looking the user up.
+type ClaimsResponse struct {
+	UserID      int64                  `json:"user_id"`
+	Role        string                 `json:"role"`
+	AccountType string                 `json:"account_type"`
+	Scopes      []string               `json:"scopes,omitempty"`
+	IssuedAt    *time.Time             `json:"issued_at,omitempty"`
+	ExpiresAt   *time.Time             `json:"expires_at,omitempty"`
+	Claims      map[string]interface{} `json:"claims,omitempty"`
 }
 
 type AuthUser struct {
-	ID   int32  `json:"id"`
-	Role string `json:"role"`
+	ID          int64    `json:"id"`
+	Role        string   `json:"role"`
+	AccountType string   `json:"account_type"`
+	Scopes      []string `json:"scopes,omitempty"`
+	APIKeyID    int64    `json:"api_key_id,omitempty"`
 }
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// EmailDeadLetterResponse is an email that could not be delivered. Error
+// is the relay's answer to the last attempt. The body isn't included: it
+// may hold sign-in links meant only for the recipients.
+type EmailDeadLetterResponse struct {
+	ID            int64     `json:"id"`
+	Recipients    []string  `json:"recipients"`
+	Subject       string    `json:"subject"`
+	Error         string    `json:"error"`
+	Attempts      int32     `json:"attempts"`
+	CreatedAt     time.Time `json:"created_at"`
+	LastAttemptAt time.Time `json:"last_attempt_at"`
+}
+
+type EmailDeadLetterListResponse struct {
+	Total       int                       `json:"total"`
+	DeadLetters []EmailDeadLetterResponse `json:"dead_letters"`
+}
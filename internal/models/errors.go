@@ -8,21 +8,41 @@ const (
 	ErrCodeMissingAuth        = "MISSING_AUTH_HEADER"
 	ErrCodeInvalidToken       = "INVALID_TOKEN"
 	ErrCodeExpiredToken       = "EXPIRED_TOKEN"
+	ErrCodeTokenRevoked       = "TOKEN_REVOKED"
+	ErrCodeSessionIdle        = "SESSION_IDLE"
+	ErrCodeSessionLimit       = "SESSION_LIMIT_REACHED"
 
 	ErrCodeForbidden         = "FORBIDDEN"
 	ErrCodeInsufficientPerms = "INSUFFICIENT_PERMISSIONS"
+	ErrCodeAccountDisabled   = "ACCOUNT_DISABLED"
+	ErrCodeAccountLocked     = "ACCOUNT_LOCKED"
+	ErrCodeSSORequired       = "SSO_REQUIRED"
+	ErrCodeHookRejected      = "HOOK_REJECTED"
+	ErrCodeServiceAccount    = "SERVICE_ACCOUNT_LOGIN"
+	ErrCodeLocationBlocked   = "LOCATION_BLOCKED"
 
 	ErrCodeValidationFailed = "VALIDATION_FAILED"
 	ErrCodeInvalidInput     = "INVALID_INPUT"
 	ErrCodeInvalidFormat    = "INVALID_FORMAT"
-
-
-	ErrCodeNotFound      = "NOT_FOUND"
-	ErrCodeAlreadyExists = "ALREADY_EXISTS"
-
-
-	ErrCodeInternalError = "INTERNAL_ERROR"
-	ErrCodeDatabaseError = "DATABASE_ERROR"
+	ErrCodeUnknownFields    = "UNKNOWN_FIELDS"
+
+	ErrCodeNotFound       = "NOT_FOUND"
+	ErrCodeAlreadyExists  = "ALREADY_EXISTS"
+	ErrCodeURLExpired     = "URL_EXPIRED"
+	ErrCodeURLUsed        = "URL_ALREADY_USED"
+	ErrCodeAlreadyDecided = "ALREADY_DECIDED"
+	ErrCodeStaleReference = "STALE_REFERENCE"
+	ErrCodeLastCredential = "LAST_CREDENTIAL"
+
+	ErrCodeSeatLimitReached     = "SEAT_LIMIT_REACHED"
+	ErrCodeSubscriptionInactive = "SUBSCRIPTION_INACTIVE"
+
+	ErrCodeInternalError      = "INTERNAL_ERROR"
+	ErrCodeDatabaseError      = "DATABASE_ERROR"
+	ErrCodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+	ErrCodeRequestTimeout     = "REQUEST_TIMEOUT"
+	ErrCodeRateLimited        = "RATE_LIMITED"
+	ErrCodeDeliveryFailed     = "DELIVERY_FAILED"
 )
 
 func NewErrorResponse(message, code, requestID string) ErrorResponse {
@@ -39,6 +59,14 @@ func SendError(c *fiber.Ctx, status int, message, code, requestID string) error
 	return c.Status(status).JSON(NewErrorResponse(message, code, requestID))
 }
 
+// SendErrorWithDetails adds machine-readable details, such as where a
+// malformed body went wrong, to the error envelope.
+func SendErrorWithDetails(c *fiber.Ctx, status int, message, code, requestID string, details interface{}) error {
+	resp := NewErrorResponse(message, code, requestID)
+	resp.Error.Details = details
+	return c.Status(status).JSON(resp)
+}
+
 func SendBadRequest(c *fiber.Ctx, message, requestID string) error {
 	return SendError(c, fiber.StatusBadRequest, message, ErrCodeInvalidInput, requestID)
 }
@@ -62,3 +90,7 @@ func SendConflict(c *fiber.Ctx, message, requestID string) error {
 func SendInternalError(c *fiber.Ctx, message, requestID string) error {
 	return SendError(c, fiber.StatusInternalServerError, message, ErrCodeInternalError, requestID)
 }
+
+func SendServiceUnavailable(c *fiber.Ctx, message, requestID string) error {
+	return SendError(c, fiber.StatusServiceUnavailable, message, ErrCodeServiceUnavailable, requestID)
+}
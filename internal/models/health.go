@@ -0,0 +1,35 @@
+package models
+
+import "BACKEND/internal/version"
+
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// PoolStats is a database connection pool's state. WaitCount is how many
+// times a request had to wait for a connection since startup.
+type PoolStats struct {
+	MaxConns   int64 `json:"max_conns"`
+	TotalConns int64 `json:"total_conns"`
+	IdleConns  int64 `json:"idle_conns"`
+	InUseConns int64 `json:"in_use_conns"`
+	WaitCount  int64 `json:"wait_count"`
+}
+
+// DatabaseHealth is the result of pinging one database. LatencyMs is how
+// long the ping took, including a failed one.
+type DatabaseHealth struct {
+	Driver    string     `json:"driver"`
+	Status    string     `json:"status"`
+	LatencyMs float64    `json:"latency_ms"`
+	Error     string     `json:"error,omitempty"`
+	Pool      *PoolStats `json:"pool,omitempty"`
+}
+
+// ReadinessResponse is "ok" when every database answered, else
+// "unavailable".
+type ReadinessResponse struct {
+	Status    string                    `json:"status"`
+	Version   version.Info              `json:"version"`
+	Databases map[string]DatabaseHealth `json:"databases"`
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// NotificationRuleRequest routes an event type to a channel. Email and
+// in-app rules go to an audience: "user", the user the event is about, or
+// "admins", every active admin. Webhook rules POST to WebhookURL instead.
+// Template is the email template rendered for the message and defaults to
+// "notification".
+type NotificationRuleRequest struct {
+	EventType  string `json:"event_type" validate:"required,max=64"`
+	Channel    string `json:"channel" validate:"required,oneof=email webhook in_app"`
+	Audience   string `json:"audience" validate:"omitempty,oneof=user admins"`
+	Template   string `json:"template" validate:"max=64"`
+	WebhookURL string `json:"webhook_url" validate:"omitempty,url,max=2048"`
+	Enabled    *bool  `json:"enabled"`
+}
+
+type NotificationRuleResponse struct {
+	ID         int64     `json:"id"`
+	EventType  string    `json:"event_type"`
+	Channel    string    `json:"channel"`
+	Audience   string    `json:"audience,omitempty"`
+	Template   string    `json:"template"`
+	WebhookURL string    `json:"webhook_url,omitempty"`
+	Enabled    bool      `json:"enabled"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// NotificationRuleListResponse lists the rules along with the event types
+// rules can be written for.
+type NotificationRuleListResponse struct {
+	Total  int                        `json:"total"`
+	Rules  []NotificationRuleResponse `json:"rules"`
+	Events []string                   `json:"events"`
+}
+
+// NotificationPreference is whether a user receives an event type on a
+// channel. Users receive everything the rules send them until they opt out.
+type NotificationPreference struct {
+	EventType string `json:"event_type" validate:"required,max=64"`
+	Channel   string `json:"channel" validate:"required,oneof=email in_app"`
+	Enabled   *bool  `json:"enabled" validate:"required"`
+}
+
+type NotificationPreferenceListResponse struct {
+	Total       int                      `json:"total"`
+	Preferences []NotificationPreference `json:"preferences"`
+}
+
+type NotificationResponse struct {
+	ID        int64             `json:"id"`
+	EventType string            `json:"event_type"`
+	Title     string            `json:"title"`
+	Details   map[string]string `json:"details,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	ReadAt    *time.Time        `json:"read_at,omitempty"`
+}
+
+type NotificationListResponse struct {
+	Total         int                    `json:"total"`
+	Notifications []NotificationResponse `json:"notifications"`
+}
@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+type OrganizationRequest struct {
+	Name string `json:"name" validate:"required,min=2,max=200"`
+}
+
+type OrganizationMemberRequest struct {
+	OrgID int64 `json:"org_id" validate:"required,gt=0"`
+}
+
+type OrganizationResponse struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OrganizationListResponse struct {
+	Total         int                    `json:"total"`
+	Organizations []OrganizationResponse `json:"organizations"`
+}
+
+type OrganizationUsageDay struct {
+	Day          string `json:"day" format:"date"`
+	Seats        int64  `json:"seats"`
+	APICalls     int64  `json:"api_calls"`
+	StorageBytes int64  `json:"storage_bytes"`
+}
+
+// OrganizationUsageResponse reports usage from From to To, inclusive.
+// Seats and StorageBytes are the latest day's; APICalls is the total.
+type OrganizationUsageResponse struct {
+	Organization OrganizationResponse   `json:"organization"`
+	From         string                 `json:"from" format:"date"`
+	To           string                 `json:"to" format:"date"`
+	Seats        int64                  `json:"seats"`
+	APICalls     int64                  `json:"api_calls"`
+	StorageBytes int64                  `json:"storage_bytes"`
+	Days         []OrganizationUsageDay `json:"days"`
+}
+
+// BillingResponse is an organization's subscription. Status is "none" and
+// the subscription fields are empty when it has none.
+type BillingResponse struct {
+	Organization     OrganizationResponse `json:"organization"`
+	Provider         string               `json:"provider,omitempty"`
+	Status           string               `json:"status"`
+	Plan             string               `json:"plan,omitempty"`
+	Seats            int64                `json:"seats"`
+	SeatsUsed        int64                `json:"seats_used"`
+	CustomerID       string               `json:"customer_id,omitempty"`
+	SubscriptionID   string               `json:"subscription_id,omitempty"`
+	CurrentPeriodEnd *time.Time           `json:"current_period_end,omitempty"`
+	UpdatedAt        *time.Time           `json:"updated_at,omitempty"`
+}
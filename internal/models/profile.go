@@ -0,0 +1,54 @@
+package models
+
+// Profile holds the fields users fill in about themselves. It is only
+// returned to the user it belongs to, never in public listings. Empty
+// fields are unset.
+type Profile struct {
+	Bio       string `json:"bio" validate:"max=500"`
+	Phone     string `json:"phone" validate:"omitempty,e164"`
+	AvatarURL string `json:"avatar_url" validate:"omitempty,http_url,max=2048"`
+	Locale    string `json:"locale" validate:"omitempty,bcp47_language_tag"`
+	Timezone  string `json:"timezone" validate:"omitempty,timezone"`
+}
+
+// ProfileUpdateRequest is a partial update to a Profile: fields left out
+// keep their value and fields set to "" are cleared. The merged profile is
+// validated, not the request.
+type ProfileUpdateRequest struct {
+	Bio       *string `json:"bio"`
+	Phone     *string `json:"phone"`
+	AvatarURL *string `json:"avatar_url"`
+	Locale    *string `json:"locale"`
+	Timezone  *string `json:"timezone"`
+}
+
+// Apply copies the fields set in r onto p.
+func (r ProfileUpdateRequest) Apply(p *Profile) {
+	for _, f := range []struct {
+		from *string
+		to   *string
+	}{
+		{r.Bio, &p.Bio},
+		{r.Phone, &p.Phone},
+		{r.AvatarURL, &p.AvatarURL},
+		{r.Locale, &p.Locale},
+		{r.Timezone, &p.Timezone},
+	} {
+		if f.from != nil {
+			*f.to = *f.from
+		}
+	}
+}
+
+// CurrentUserResponse is what GET /users/me returns: the user as everyone
+// sees them, plus their profile. AvatarURL is the profile's picture when
+// they set one.
+type CurrentUserResponse struct {
+	ID          string  `json:"id" format:"uuid"`
+	Name        string  `json:"name"`
+	Dob         string  `json:"dob" format:"date"`
+	Age         int     `json:"age"`
+	AccountType string  `json:"account_type"`
+	AvatarURL   string  `json:"avatar_url,omitempty"`
+	Profile     Profile `json:"profile"`
+}
@@ -1,22 +1,62 @@
 package models
 
+// User IDs in responses are public UUIDs; the serial IDs stay internal.
+// AvatarURL is the Gravatar or identicon picture for the user.
 type UserResponse struct {
-	ID   int32  `json:"id"`
-	Name string `json:"name"`
-	Dob  string `json:"dob"`
+	ID        string `json:"id" format:"uuid"`
+	Name      string `json:"name"`
+	Dob       string `json:"dob" format:"date"`
+	AvatarURL string `json:"avatar_url,omitempty"`
 }
 
 type UserWithAgeResponse struct {
-	ID   int32  `json:"id"`
-	Name string `json:"name"`
-	Dob  string `json:"dob"`
-	Age  int    `json:"age"`
+	ID          string `json:"id" format:"uuid"`
+	Name        string `json:"name"`
+	Dob         string `json:"dob" format:"date"`
+	Age         int    `json:"age"`
+	AccountType string `json:"account_type"`
+	AvatarURL   string `json:"avatar_url,omitempty"`
+}
+
+// AgeResponse is a user's age on the day At.
+type AgeResponse struct {
+	ID  string `json:"id" format:"uuid"`
+	Dob string `json:"dob" format:"date"`
+	At  string `json:"at" format:"date"`
+	Age int    `json:"age"`
+}
+
+// BirthdayResponse is a user with a birthday coming up: the date it falls on
+// and the age they turn.
+type BirthdayResponse struct {
+	ID       string `json:"id" format:"uuid"`
+	Name     string `json:"name"`
+	Dob      string `json:"dob" format:"date"`
+	Birthday string `json:"birthday" format:"date"`
+	Turns    int    `json:"turns"`
+}
+
+// UserLookupResponse is a user matched by an email lookup, with just what a
+// support agent needs to tell them apart.
+type UserLookupResponse struct {
+	ID          string `json:"id" format:"uuid"`
+	Name        string `json:"name"`
+	Email       string `json:"email"`
+	Role        string `json:"role"`
+	Active      bool   `json:"active"`
+	AccountType string `json:"account_type"`
+}
+
+type UserLookupListResponse struct {
+	Total int                  `json:"total"`
+	Users []UserLookupResponse `json:"users"`
 }
 
 type ErrorDetail struct {
-	Message   string `json:"message"`
-	Code      string `json:"code"`
-	RequestID string `json:"request_id,omitempty"`
+	Message   string      `json:"message"`
+	Code      string      `json:"code"`
+	RequestID string      `json:"request_id,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
 }
 
 type ErrorResponse struct {
@@ -36,7 +76,38 @@ type PaginationMeta struct {
 	HasPrevious bool  `json:"has_previous"`
 }
 
+// NewPaginationMeta describes page of limit results out of total.
+func NewPaginationMeta(total int64, page, limit int) PaginationMeta {
+	totalPages := int(total) / limit
+	if int(total)%limit != 0 {
+		totalPages++
+	}
+	return PaginationMeta{
+		Total:       total,
+		Page:        page,
+		Limit:       limit,
+		TotalPages:  totalPages,
+		HasNext:     page < totalPages,
+		HasPrevious: page > 1,
+	}
+}
+
 type PaginatedUsersResponse struct {
 	Data       []UserWithAgeResponse `json:"data"`
 	Pagination PaginationMeta        `json:"pagination"`
 }
+
+type DryRunChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+// DryRunResponse describes what an admin action would do without doing it.
+type DryRunResponse struct {
+	DryRun  bool           `json:"dry_run"`
+	Action  string         `json:"action"`
+	UserID  string         `json:"user_id" format:"uuid"`
+	Changes []DryRunChange `json:"changes"`
+	Effects []string       `json:"effects"`
+}
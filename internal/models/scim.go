@@ -0,0 +1,96 @@
+package models
+
+import (
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	SCIMSchemaUser         = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIMSchemaUserExt      = "urn:ietf:params:scim:schemas:extension:useapi:2.0:User"
+	SCIMSchemaListResponse = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIMSchemaPatchOp      = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIMSchemaError        = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+	SCIMContentType = "application/scim+json"
+
+	SCIMErrInvalidFilter = "invalidFilter"
+	SCIMErrInvalidValue  = "invalidValue"
+	SCIMErrInvalidSyntax = "invalidSyntax"
+	SCIMErrMutability    = "mutability"
+	SCIMErrUniqueness    = "uniqueness"
+)
+
+type SCIMName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type SCIMEmail struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type SCIMUserExtension struct {
+	Dob  string `json:"dob,omitempty" format:"date"`
+	Role string `json:"role,omitempty"`
+}
+
+type SCIMMeta struct {
+	ResourceType string `json:"resourceType"`
+	Created      string `json:"created,omitempty" format:"date-time"`
+	LastModified string `json:"lastModified,omitempty" format:"date-time"`
+	Location     string `json:"location,omitempty"`
+}
+
+type SCIMUser struct {
+	Schemas     []string           `json:"schemas"`
+	ID          string             `json:"id,omitempty" format:"uuid"`
+	ExternalID  string             `json:"externalId,omitempty"`
+	UserName    string             `json:"userName"`
+	Name        *SCIMName          `json:"name,omitempty"`
+	DisplayName string             `json:"displayName,omitempty"`
+	Emails      []SCIMEmail        `json:"emails,omitempty"`
+	Active      *bool              `json:"active,omitempty"`
+	Password    string             `json:"password,omitempty"`
+	Extension   *SCIMUserExtension `json:"urn:ietf:params:scim:schemas:extension:useapi:2.0:User,omitempty"`
+	Meta        *SCIMMeta          `json:"meta,omitempty"`
+}
+
+type SCIMListResponse struct {
+	Schemas      []string   `json:"schemas"`
+	TotalResults int64      `json:"totalResults"`
+	StartIndex   int        `json:"startIndex"`
+	ItemsPerPage int        `json:"itemsPerPage"`
+	Resources    []SCIMUser `json:"Resources"`
+}
+
+type SCIMPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+type SCIMPatchRequest struct {
+	Schemas    []string             `json:"schemas"`
+	Operations []SCIMPatchOperation `json:"Operations"`
+}
+
+type SCIMError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+func SendSCIMError(c *fiber.Ctx, status int, scimType, detail string) error {
+	return c.Status(status).JSON(SCIMError{
+		Schemas:  []string{SCIMSchemaError},
+		Status:   strconv.Itoa(status),
+		ScimType: scimType,
+		Detail:   detail,
+	}, SCIMContentType)
+}
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// SecurityEventResponse is one entry in the security log. ActorID and
+// TargetID are public user IDs, left out when there was no such user or it
+// has since been deleted.
+type SecurityEventResponse struct {
+	ID        int64             `json:"id"`
+	Type      string            `json:"type"`
+	ActorID   string            `json:"actor_id,omitempty" format:"uuid"`
+	TargetID  string            `json:"target_id,omitempty" format:"uuid"`
+	Details   map[string]string `json:"details,omitempty"`
+	IPAddress string            `json:"ip_address,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+	Hash      string            `json:"hash"`
+}
+
+// SecurityLogVerification is the result of checking the security log's
+// hash chain. When it is broken, BrokenAt is the first event that doesn't
+// follow from the ones before it. HeadHash is the newest event's hash;
+// kept elsewhere, it shows whether events were later cut off the end.
+type SecurityLogVerification struct {
+	Valid    bool   `json:"valid"`
+	Events   int64  `json:"events"`
+	HeadHash string `json:"head_hash"`
+	BrokenAt int64  `json:"broken_at,omitempty"`
+	Problem  string `json:"problem,omitempty"`
+}
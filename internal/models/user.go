@@ -1,6 +1,21 @@
-package models
-
-type UserRequest struct {
-	Name string `json:"name" validate:"required,min=2"`
-	Dob  string `json:"dob" validate:"required,datetime=2006-01-02"`
-}
+package models
+
+type UserRequest struct {
+	Name string `json:"name" validate:"required,min=2"`
+	Dob  string `json:"dob" validate:"required,datetime=2006-01-02"`
+}
+
+type ServiceAccountRequest struct {
+	Name string `json:"name" validate:"required,min=2"`
+	Role string `json:"role" validate:"omitempty,oneof=user moderator admin"`
+}
+
+type RoleUpdateRequest struct {
+	Role string `json:"role" validate:"required,oneof=user org_admin moderator admin"`
+}
+
+type APIKeyRequest struct {
+	Name          string   `json:"name" validate:"required"`
+	Scopes        []string `json:"scopes" validate:"required,min=1"`
+	ExpiresInDays int      `json:"expires_in_days" validate:"gte=0"`
+}
@@ -0,0 +1,57 @@
+package policy
+
+// Authenticated holds for every request; the auth middleware has already
+// rejected anonymous callers by the time policies run.
+func Authenticated(Request) bool { return true }
+
+// RoleAtLeast holds when the subject's role is role or ranks above it.
+func RoleAtLeast(role string) Condition {
+	return func(r Request) bool { return r.Roles.Satisfies(r.Subject.Role, role) }
+}
+
+// Owner holds when the resource belongs to the subject.
+func Owner(r Request) bool {
+	return r.Resource.OwnerID != 0 && r.Resource.OwnerID == r.Subject.ID
+}
+
+// Outranks holds when the subject's role ranks strictly above the role of
+// the resource's owner.
+func Outranks(r Request) bool {
+	return r.Resource.Role != "" && r.Roles.Satisfies(r.Subject.Role, r.Resource.Role) && !r.Roles.Satisfies(r.Resource.Role, r.Subject.Role)
+}
+
+// SameClaim holds when the subject's token claim key equals the resource
+// attribute of the same name, e.g. SameClaim("org_id") for org-scoped
+// access. It never holds when either side is missing.
+func SameClaim(key string) Condition {
+	return func(r Request) bool {
+		want, ok := r.Subject.Claims[key]
+		if !ok || want == nil {
+			return false
+		}
+		have, ok := r.Resource.Attributes[key]
+		return ok && have == want
+	}
+}
+
+func AnyOf(conditions ...Condition) Condition {
+	return func(r Request) bool {
+		for _, c := range conditions {
+			if c(r) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func AllOf(conditions ...Condition) Condition {
+	return func(r Request) bool {
+		for _, c := range conditions {
+			if !c(r) {
+				return false
+			}
+		}
+		return true
+	}
+}
@@ -0,0 +1,105 @@
+// Package policy decides whether a subject may perform an action on a
+// resource. Rules are plain Go values, so every authorization rule beyond
+// "is logged in" can be read in one place (see DefaultRules).
+package policy
+
+import "strings"
+
+// Subject is the caller. Claims holds the extra claims of the caller's
+// token, e.g. an org ID added by a claims enricher.
+type Subject struct {
+	ID          int64
+	Role        string
+	AccountType string
+	Claims      map[string]interface{}
+}
+
+// Resource is what the action applies to. OwnerID is the user the resource
+// belongs to (the user itself for user resources) and Role is that user's
+// role, when known.
+type Resource struct {
+	Type       string
+	ID         string
+	OwnerID    int64
+	Role       string
+	Attributes map[string]interface{}
+}
+
+// Request is what a rule's condition is checked against. Roles is the
+// engine's role hierarchy, filled in by Evaluate.
+type Request struct {
+	Subject  Subject
+	Action   string
+	Resource Resource
+	Roles    RoleHierarchy
+}
+
+// Condition reports whether a rule applies to a request.
+type Condition func(Request) bool
+
+// Rule allows the actions it lists when its condition holds. An action is
+// matched exactly, by a "users:*" style prefix, or by "*".
+type Rule struct {
+	Name    string
+	Actions []string
+	When    Condition
+}
+
+func (r Rule) matches(action string) bool {
+	for _, a := range r.Actions {
+		if a == "*" || a == action || (strings.HasSuffix(a, ":*") && strings.HasPrefix(action, a[:len(a)-1])) {
+			return true
+		}
+	}
+	return false
+}
+
+// Decision is the outcome of an evaluation. Rule names the rule that
+// allowed the request and is empty when it was denied.
+type Decision struct {
+	Allowed bool
+	Rule    string
+}
+
+// Engine evaluates requests against a rule set. Anything no rule allows is
+// denied. Roles rank as in DefaultRoleHierarchy unless SetRoleHierarchy
+// says otherwise.
+type Engine struct {
+	rules []Rule
+	roles RoleHierarchy
+}
+
+func NewEngine(rules ...Rule) *Engine {
+	return &Engine{rules: rules, roles: NewRoleHierarchy(DefaultRoleHierarchy)}
+}
+
+// SetRoleHierarchy sets the role ranking, highest first.
+func (e *Engine) SetRoleHierarchy(roles []string) {
+	e.roles = NewRoleHierarchy(roles)
+}
+
+// RoleSatisfies reports whether a user with role may access something that
+// requires the required role, by the engine's hierarchy.
+func (e *Engine) RoleSatisfies(role, required string) bool {
+	return e.roles.Satisfies(role, required)
+}
+
+func (e *Engine) Evaluate(req Request) Decision {
+	req.Roles = e.roles
+	for _, rule := range e.rules {
+		if rule.matches(req.Action) && (rule.When == nil || rule.When(req)) {
+			return Decision{Allowed: true, Rule: rule.Name}
+		}
+	}
+	return Decision{}
+}
+
+// Allowed is shorthand for Evaluate(...).Allowed.
+func (e *Engine) Allowed(subject Subject, action string, resource Resource) bool {
+	return e.Evaluate(Request{Subject: subject, Action: action, Resource: resource}).Allowed
+}
+
+// Rules returns the engine's rules in evaluation order.
+func (e *Engine) Rules() []Rule {
+	return append([]Rule(nil), e.rules...)
+}
@@ -0,0 +1,114 @@
+package policy
+
+import "testing"
+
+func TestDefaultRules(t *testing.T) {
+	engine := NewEngine(DefaultRules()...)
+
+	user := Subject{ID: 1, Role: "user"}
+	moderator := Subject{ID: 2, Role: "moderator"}
+	admin := Subject{ID: 3, Role: "admin"}
+
+	self := Resource{Type: "user", ID: "1", OwnerID: 1, Role: "user"}
+	otherUser := Resource{Type: "user", ID: "4", OwnerID: 4, Role: "user"}
+	otherModerator := Resource{Type: "user", ID: "5", OwnerID: 5, Role: "moderator"}
+	otherAdmin := Resource{Type: "user", ID: "6", OwnerID: 6, Role: "admin"}
+
+	tests := []struct {
+		name     string
+		subject  Subject
+		action   string
+		resource Resource
+		want     bool
+	}{
+		{"user lists users", user, ActionUsersList, Resource{}, true},
+		{"user reads another user", user, ActionUsersRead, otherUser, true},
+		{"user updates self", user, ActionUsersUpdate, self, true},
+		{"user updates another user", user, ActionUsersUpdate, otherUser, false},
+		{"user deletes another user", user, ActionUsersDelete, otherUser, false},
+		{"moderator deletes a user", moderator, ActionUsersDelete, otherUser, false},
+		{"admin updates another user", admin, ActionUsersUpdate, otherUser, true},
+		{"moderator manages a user", moderator, ActionUsersManage, otherUser, true},
+		{"moderator manages a moderator", moderator, ActionUsersManage, otherModerator, false},
+		{"moderator manages an admin", moderator, ActionUsersManage, otherAdmin, false},
+		{"admin manages an admin", admin, ActionUsersManage, otherAdmin, true},
+		{"user manages a user", user, ActionUsersManage, otherUser, false},
+		{"unknown action", admin, "reports:export", Resource{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := engine.Allowed(tt.subject, tt.action, tt.resource); got != tt.want {
+				t.Errorf("Allowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvaluateReportsRule(t *testing.T) {
+	engine := NewEngine(
+		Rule{Name: "never", Actions: []string{"*"}, When: func(Request) bool { return false }},
+		Rule{Name: "docs", Actions: []string{"docs:*"}},
+	)
+
+	got := engine.Evaluate(Request{Action: "docs:read"})
+	if !got.Allowed || got.Rule != "docs" {
+		t.Errorf("Evaluate(docs:read) = %+v, want allowed by docs", got)
+	}
+
+	got = engine.Evaluate(Request{Action: "documents:read"})
+	if got.Allowed || got.Rule != "" {
+		t.Errorf("Evaluate(documents:read) = %+v, want denied", got)
+	}
+}
+
+func TestSameClaim(t *testing.T) {
+	engine := NewEngine(Rule{
+		Name:    "org members read their org",
+		Actions: []string{"orgs:read"},
+		When:    SameClaim("org_id"),
+	})
+	org := func(id interface{}) Resource {
+		return Resource{Type: "org", Attributes: map[string]interface{}{"org_id": id}}
+	}
+
+	tests := []struct {
+		name     string
+		claims   map[string]interface{}
+		resource Resource
+		want     bool
+	}{
+		{"same org", map[string]interface{}{"org_id": float64(7)}, org(float64(7)), true},
+		{"other org", map[string]interface{}{"org_id": float64(7)}, org(float64(8)), false},
+		{"no claim", nil, org(float64(7)), false},
+		{"no attribute", map[string]interface{}{"org_id": float64(7)}, Resource{Type: "org"}, false},
+		{"both missing", nil, Resource{Type: "org"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			subject := Subject{ID: 1, Role: "user", Claims: tt.claims}
+			if got := engine.Allowed(subject, "orgs:read", tt.resource); got != tt.want {
+				t.Errorf("Allowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEngineRoleHierarchy(t *testing.T) {
+	flat := NewEngine(DefaultRules()...)
+	flat.SetRoleHierarchy([]string{"admin", "user"})
+	standard := NewEngine(DefaultRules()...)
+
+	moderator := Subject{ID: 2, Role: "moderator"}
+	user := Resource{Type: "user", ID: "4", OwnerID: 4, Role: "user"}
+	if flat.Allowed(moderator, ActionUsersManage, user) {
+		t.Error("moderator outranks a user in a hierarchy without moderators")
+	}
+	if !standard.Allowed(moderator, ActionUsersManage, user) {
+		t.Error("another engine's hierarchy changed the default one")
+	}
+	if !flat.RoleSatisfies("admin", "user") || flat.RoleSatisfies("moderator", "user") {
+		t.Error("RoleSatisfies does not follow the engine's hierarchy")
+	}
+}
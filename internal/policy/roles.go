@@ -0,0 +1,36 @@
+package policy
+
+// DefaultRoleHierarchy ranks the built-in roles, highest first.
+var DefaultRoleHierarchy = []string{"admin", "moderator", "org_admin", "user"}
+
+// RoleHierarchy ranks roles. A role satisfies a requirement for itself and
+// every role ranked below it; roles outside the hierarchy only satisfy
+// themselves. The zero value ranks no roles.
+type RoleHierarchy struct {
+	ranks map[string]int
+}
+
+// NewRoleHierarchy ranks roles, highest first.
+func NewRoleHierarchy(roles []string) RoleHierarchy {
+	ranks := make(map[string]int, len(roles))
+	for i, role := range roles {
+		if _, dup := ranks[role]; !dup {
+			ranks[role] = i
+		}
+	}
+	return RoleHierarchy{ranks: ranks}
+}
+
+// Satisfies reports whether a user with role may access something that
+// requires the required role.
+func (h RoleHierarchy) Satisfies(role, required string) bool {
+	if role == required {
+		return true
+	}
+	have, ok := h.ranks[role]
+	if !ok {
+		return false
+	}
+	want, ok := h.ranks[required]
+	return ok && have < want
+}
@@ -0,0 +1,36 @@
+package policy
+
+// Actions checked by the API.
+const (
+	ActionUsersList   = "users:list"
+	ActionUsersRead   = "users:read"
+	ActionUsersCreate = "users:create"
+	ActionUsersUpdate = "users:update"
+	ActionUsersDelete = "users:delete"
+
+	// ActionUsersManage covers the /admin user operations: activation,
+	// role changes and deletion.
+	ActionUsersManage = "users:manage"
+)
+
+// DefaultRules is the API's authorization policy. Route groups still check
+// the minimum role (e.g. moderator for /admin) before these run.
+func DefaultRules() []Rule {
+	return []Rule{
+		{
+			Name:    "any user can browse and add users",
+			Actions: []string{ActionUsersList, ActionUsersRead, ActionUsersCreate},
+			When:    Authenticated,
+		},
+		{
+			Name:    "owner or admin can change a user",
+			Actions: []string{ActionUsersUpdate, ActionUsersDelete},
+			When:    AnyOf(Owner, RoleAtLeast("admin")),
+		},
+		{
+			Name:    "admins manage anyone, others only lower roles",
+			Actions: []string{ActionUsersManage},
+			When:    AnyOf(RoleAtLeast("admin"), Outranks),
+		},
+	}
+}
@@ -0,0 +1,173 @@
+// Package queryparams binds URL query parameters to a struct, so handlers
+// get typed values with defaults, ranges and allowed values checked, and
+// clients get every bad parameter back at once.
+//
+// Fields take their parameter name from the query tag and are bound only if
+// they have one. The optional tags are:
+//
+//	default:"10"        used when the parameter is missing or empty
+//	min:"1" max:"200"   inclusive bounds for integer fields
+//	enum:"json,csv"     allowed values for string fields
+//
+// Supported field kinds are string, bool and the signed integers.
+package queryparams
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldError is a parameter that could not be bound.
+type FieldError struct {
+	Param   string `json:"param"`
+	Value   string `json:"value"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Param + ": " + e.Message
+}
+
+// Errors lists every parameter that could not be bound, in field order.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Has reports whether param is among the errors.
+func (e Errors) Has(param string) bool {
+	for _, fe := range e {
+		if fe.Param == param {
+			return true
+		}
+	}
+	return false
+}
+
+// Bind fills the tagged fields of the struct v points to from lookup, which
+// returns a parameter's raw value or "" if it is missing; fiber's c.Query
+// fits. The error is nil or Errors. Bind panics if v is not a pointer to a
+// struct or a tag doesn't fit its field, as those are programming errors.
+func Bind(lookup func(key string) string, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("queryparams: Bind needs a pointer to a struct, got %T", v))
+	}
+	rv = rv.Elem()
+	rt := rv.Type()
+
+	var errs Errors
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		name := f.Tag.Get("query")
+		if name == "" {
+			continue
+		}
+
+		raw := lookup(name)
+		if raw == "" {
+			raw = f.Tag.Get("default")
+			if raw == "" {
+				continue
+			}
+		}
+		if fe := set(rv.Field(i), f, name, raw); fe != nil {
+			errs = append(errs, *fe)
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func set(field reflect.Value, f reflect.StructField, name, raw string) *FieldError {
+	switch field.Kind() {
+	case reflect.String:
+		if enum := f.Tag.Get("enum"); enum != "" {
+			if fe := OneOf(name, raw, strings.Split(enum, ",")); fe != nil {
+				return fe
+			}
+		}
+		field.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return &FieldError{Param: name, Value: raw, Message: "must be true or false"}
+		}
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// Bounds default to, and never go past, what the field can hold.
+		hi := int64(math.MaxInt64 >> (64 - field.Type().Bits()))
+		min, max := bound(f, "min", -hi-1), bound(f, "max", hi)
+		if max > hi {
+			max = hi
+		}
+		if min < -hi-1 {
+			min = -hi - 1
+		}
+		n, fe := Int(name, raw, min, max)
+		if fe != nil {
+			return fe
+		}
+		field.SetInt(n)
+	default:
+		panic(fmt.Sprintf("queryparams: unsupported type %s for field %s", field.Type(), f.Name))
+	}
+	return nil
+}
+
+func bound(f reflect.StructField, tag string, fallback int64) int64 {
+	s := f.Tag.Get(tag)
+	if s == "" {
+		return fallback
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		panic(fmt.Sprintf("queryparams: bad %s tag %q on field %s", tag, s, f.Name))
+	}
+	return n
+}
+
+// Int parses an integer parameter within inclusive bounds. Numbers too big
+// or small for an int64 are reported against the bound they pass rather than
+// as malformed.
+func Int(param, raw string, min, max int64) (int64, *FieldError) {
+	n, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return 0, &FieldError{Param: param, Value: raw, Message: "must be a whole number"}
+	}
+	if n < min {
+		return 0, &FieldError{Param: param, Value: raw, Message: fmt.Sprintf("must be at least %d", min)}
+	}
+	if n > max {
+		return 0, &FieldError{Param: param, Value: raw, Message: fmt.Sprintf("must be at most %d", max)}
+	}
+	if err != nil {
+		return 0, &FieldError{Param: param, Value: raw, Message: "is out of range"}
+	}
+	return n, nil
+}
+
+// OneOf checks that a parameter is one of the allowed values.
+func OneOf(param, raw string, allowed []string) *FieldError {
+	for _, a := range allowed {
+		if raw == a {
+			return nil
+		}
+	}
+	msg := "must be " + allowed[len(allowed)-1]
+	if len(allowed) > 1 {
+		msg = "must be " + strings.Join(allowed[:len(allowed)-1], ", ") + " or " + allowed[len(allowed)-1]
+	}
+	return &FieldError{Param: param, Value: raw, Message: msg}
+}
@@ -0,0 +1,97 @@
+package queryparams
+
+import (
+	"errors"
+	"testing"
+)
+
+type listQuery struct {
+	Status string `query:"status" default:"pending" enum:"pending,approved,rejected"`
+	Limit  int32  `query:"limit" default:"50" min:"1" max:"200"`
+	Offset int8   `query:"offset"`
+	Draft  bool   `query:"draft"`
+	Note   string
+}
+
+func lookup(values map[string]string) func(string) string {
+	return func(key string) string { return values[key] }
+}
+
+func TestBindDefaults(t *testing.T) {
+	q := listQuery{Note: "kept"}
+	if err := Bind(lookup(nil), &q); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if q.Status != "pending" || q.Limit != 50 || q.Offset != 0 || q.Draft || q.Note != "kept" {
+		t.Errorf("unexpected result: %+v", q)
+	}
+}
+
+func TestBindValues(t *testing.T) {
+	var q listQuery
+	err := Bind(lookup(map[string]string{"status": "approved", "limit": "200", "offset": "-128", "draft": "true", "Note": "x"}), &q)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if q.Status != "approved" || q.Limit != 200 || q.Offset != -128 || !q.Draft || q.Note != "" {
+		t.Errorf("unexpected result: %+v", q)
+	}
+}
+
+func TestBindErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		values map[string]string
+		want   string
+	}{
+		{"enum", map[string]string{"status": "open"}, "status: must be pending, approved or rejected"},
+		{"not a number", map[string]string{"limit": "ten"}, "limit: must be a whole number"},
+		{"below min", map[string]string{"limit": "0"}, "limit: must be at least 1"},
+		{"above max", map[string]string{"limit": "201"}, "limit: must be at most 200"},
+		{"past int64", map[string]string{"limit": "99999999999999999999"}, "limit: must be at most 200"},
+		{"past field size", map[string]string{"offset": "128"}, "offset: must be at most 127"},
+		{"bool", map[string]string{"draft": "maybe"}, "draft: must be true or false"},
+		{"several", map[string]string{"status": "x", "limit": "-1"}, "status: must be pending, approved or rejected; limit: must be at least 1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var q listQuery
+			err := Bind(lookup(tt.values), &q)
+			var errs Errors
+			if !errors.As(err, &errs) {
+				t.Fatalf("err = %v; want Errors", err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("err = %q; want %q", err, tt.want)
+			}
+			for _, fe := range errs {
+				if fe.Value != tt.values[fe.Param] {
+					t.Errorf("%s: value = %q; want %q", fe.Param, fe.Value, tt.values[fe.Param])
+				}
+			}
+		})
+	}
+}
+
+func TestInt(t *testing.T) {
+	if n, fe := Int("days", "30", 1, 365); fe != nil || n != 30 {
+		t.Errorf("Int = %d, %v; want 30", n, fe)
+	}
+	if _, fe := Int("days", "-99999999999999999999", 1, 365); fe == nil || fe.Message != "must be at least 1" {
+		t.Errorf("fe = %v; want must be at least 1", fe)
+	}
+	if _, fe := Int("n", "99999999999999999999", 0, 1<<63-1); fe == nil || fe.Message != "is out of range" {
+		t.Errorf("fe = %v; want is out of range", fe)
+	}
+}
+
+func TestBindPanicsOnNonStruct(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Bind did not panic")
+		}
+	}()
+	var n int
+	Bind(lookup(nil), &n)
+}
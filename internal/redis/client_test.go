@@ -0,0 +1,125 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// serve answers each command read from ln with the reply replies holds for
+// its name, and records the commands.
+func serve(t *testing.T, replies map[string]string) (string, func() [][]string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	var mu sync.Mutex
+	var commands [][]string
+	go func() {
+		for {
+			nc, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer nc.Close()
+				r := bufio.NewReader(nc)
+				for {
+					cmd, err := readCommand(r)
+					if err != nil {
+						return
+					}
+					mu.Lock()
+					commands = append(commands, cmd)
+					mu.Unlock()
+					nc.Write([]byte(replies[cmd[0]]))
+				}
+			}()
+		}
+	}()
+	return ln.Addr().String(), func() [][]string {
+		mu.Lock()
+		defer mu.Unlock()
+		return commands
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(line[1:]))
+	cmd := make([]string, n)
+	for i := range cmd {
+		if _, err := r.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		cmd[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return cmd, nil
+}
+
+func TestClientDo(t *testing.T) {
+	addr, commands := serve(t, map[string]string{
+		"AUTH":   "+OK\r\n",
+		"SELECT": "+OK\r\n",
+		"GET":    "$-1\r\n",
+		"EVAL":   "*3\r\n:2\r\n$5\r\nhello\r\n-ERR nope\r\n",
+		"INCR":   "-WRONGTYPE not an integer\r\n",
+	})
+	c := New(Options{Addr: addr, Password: "secret", DB: 2})
+	defer c.Close()
+	ctx := context.Background()
+
+	if _, err := c.Do(ctx, "GET", "missing"); !errors.Is(err, ErrNil) {
+		t.Errorf("GET: expected ErrNil, got %v", err)
+	}
+	reply, err := c.Do(ctx, "EVAL", "return 1", "0")
+	if err != nil {
+		t.Fatalf("EVAL: %v", err)
+	}
+	want := []interface{}{int64(2), "hello", Error("ERR nope")}
+	if !reflect.DeepEqual(reply, want) {
+		t.Errorf("EVAL = %#v; want %#v", reply, want)
+	}
+	var replyErr Error
+	if _, err := c.Do(ctx, "INCR", "k"); !errors.As(err, &replyErr) {
+		t.Errorf("INCR: expected an error reply, got %v", err)
+	}
+
+	// One connection, authenticated and switched to the database once.
+	got := commands()
+	if len(got) != 5 || got[0][0] != "AUTH" || got[0][1] != "secret" || got[1][0] != "SELECT" || got[1][1] != "2" {
+		t.Errorf("unexpected commands %q", got)
+	}
+}
+
+func TestParseURL(t *testing.T) {
+	opts, err := ParseURL("redis://:pw@cache:6380/3")
+	if err != nil {
+		t.Fatalf("ParseURL: %v", err)
+	}
+	if opts.Addr != "cache:6380" || opts.Password != "pw" || opts.DB != 3 {
+		t.Errorf("unexpected options %+v", opts)
+	}
+	if opts, _ := ParseURL("redis://cache"); opts.Addr != "cache:6379" {
+		t.Errorf("default port: got %q", opts.Addr)
+	}
+	if _, err := ParseURL("rediss://cache"); err == nil {
+		t.Error("expected an error for rediss")
+	}
+}
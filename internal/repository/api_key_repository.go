@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+type APIKeyStore interface {
+	Create(ctx context.Context, userID int64, name, prefix, keyHash, scopes string, expiresAt *time.Time) (generated.CreateAPIKeyRow, error)
+	ListByUser(ctx context.Context, userID int64) ([]generated.ListAPIKeysByUserRow, error)
+	GetByHash(ctx context.Context, keyHash string) (generated.GetAPIKeyByHashRow, error)
+	Revoke(ctx context.Context, id, userID int64) (bool, error)
+	Touch(ctx context.Context, id int64) error
+}
+
+var (
+	_ APIKeyStore = (*APIKeyRepository)(nil)
+	_ APIKeyStore = (*MySQLAPIKeyRepository)(nil)
+	_ APIKeyStore = (*MemoryAPIKeyStore)(nil)
+)
+
+type APIKeyRepository struct {
+	queries *generated.Queries
+}
+
+func NewAPIKeyRepository(q *generated.Queries) *APIKeyRepository {
+	return &APIKeyRepository{queries: q}
+}
+
+func (r *APIKeyRepository) Create(ctx context.Context, userID int64, name, prefix, keyHash, scopes string, expiresAt *time.Time) (generated.CreateAPIKeyRow, error) {
+	params := generated.CreateAPIKeyParams{
+		UserID:  userID,
+		Name:    name,
+		Prefix:  prefix,
+		KeyHash: keyHash,
+		Scopes:  scopes,
+	}
+	if expiresAt != nil {
+		params.ExpiresAt = pgtype.Timestamp{Time: *expiresAt, Valid: true}
+	}
+	row, err := r.queries.CreateAPIKey(ctx, params)
+	return row, pgError(err)
+}
+
+func (r *APIKeyRepository) ListByUser(ctx context.Context, userID int64) ([]generated.ListAPIKeysByUserRow, error) {
+	return r.queries.ListAPIKeysByUser(ctx, userID)
+}
+
+func (r *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (generated.GetAPIKeyByHashRow, error) {
+	return r.queries.GetAPIKeyByHash(ctx, keyHash)
+}
+
+func (r *APIKeyRepository) Revoke(ctx context.Context, id, userID int64) (bool, error) {
+	n, err := r.queries.RevokeAPIKey(ctx, generated.RevokeAPIKeyParams{
+		ID:     id,
+		UserID: userID,
+	})
+	return n > 0, err
+}
+
+func (r *APIKeyRepository) Touch(ctx context.Context, id int64) error {
+	return r.queries.TouchAPIKey(ctx, id)
+}
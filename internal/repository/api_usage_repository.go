@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// APIUsageStore holds daily request counts per user and API key. Requests
+// made with a token rather than a key are counted under key 0.
+type APIUsageStore interface {
+	// Add adds requests to the day's count for the user and key.
+	Add(ctx context.Context, userID, apiKeyID int64, day time.Time, requests int64) error
+	ListByUser(ctx context.Context, userID int64, from, to time.Time) ([]generated.ListAPIUsageByUserRow, error)
+	// Top returns the users and keys with the most requests over the days,
+	// most first.
+	Top(ctx context.Context, from, to time.Time, limit int32) ([]generated.TopAPIUsageRow, error)
+}
+
+var (
+	_ APIUsageStore = (*APIUsageRepository)(nil)
+	_ APIUsageStore = (*MySQLAPIUsageRepository)(nil)
+	_ APIUsageStore = (*MemoryAPIUsageStore)(nil)
+)
+
+type APIUsageRepository struct {
+	queries *generated.Queries
+}
+
+func NewAPIUsageRepository(q *generated.Queries) *APIUsageRepository {
+	return &APIUsageRepository{queries: q}
+}
+
+func (r *APIUsageRepository) Add(ctx context.Context, userID, apiKeyID int64, day time.Time, requests int64) error {
+	return pgError(r.queries.AddAPIUsage(ctx, generated.AddAPIUsageParams{
+		UserID:   userID,
+		ApiKeyID: apiKeyID,
+		Day:      pgtype.Date{Time: day, Valid: true},
+		Requests: requests,
+	}))
+}
+
+func (r *APIUsageRepository) ListByUser(ctx context.Context, userID int64, from, to time.Time) ([]generated.ListAPIUsageByUserRow, error) {
+	return r.queries.ListAPIUsageByUser(ctx, generated.ListAPIUsageByUserParams{
+		UserID:  userID,
+		FromDay: pgtype.Date{Time: from, Valid: true},
+		ToDay:   pgtype.Date{Time: to, Valid: true},
+	})
+}
+
+func (r *APIUsageRepository) Top(ctx context.Context, from, to time.Time, limit int32) ([]generated.TopAPIUsageRow, error) {
+	return r.queries.TopAPIUsage(ctx, generated.TopAPIUsageParams{
+		FromDay:  pgtype.Date{Time: from, Valid: true},
+		ToDay:    pgtype.Date{Time: to, Valid: true},
+		RowLimit: limit,
+	})
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// BillingUpdate is an organization's subscription as of EventAt.
+type BillingUpdate struct {
+	OrgID            int64
+	Provider         string
+	CustomerID       string
+	SubscriptionID   string
+	Plan             string
+	Status           string
+	Seats            int64
+	CurrentPeriodEnd *time.Time
+	EventAt          time.Time
+}
+
+// BillingStore holds each organization's subscription with its billing
+// provider.
+type BillingStore interface {
+	Get(ctx context.Context, orgID int64) (generated.OrganizationBilling, error)
+	GetByCustomer(ctx context.Context, provider, customerID string) (generated.OrganizationBilling, error)
+	// Apply stores the update, unless the organization's subscription was
+	// already updated from a later event.
+	Apply(ctx context.Context, update BillingUpdate) error
+}
+
+var (
+	_ BillingStore = (*BillingRepository)(nil)
+	_ BillingStore = (*MySQLBillingRepository)(nil)
+	_ BillingStore = (*MemoryBillingStore)(nil)
+)
+
+type BillingRepository struct {
+	queries *generated.Queries
+}
+
+func NewBillingRepository(q *generated.Queries) *BillingRepository {
+	return &BillingRepository{queries: q}
+}
+
+func (r *BillingRepository) Get(ctx context.Context, orgID int64) (generated.OrganizationBilling, error) {
+	return r.queries.GetOrganizationBilling(ctx, orgID)
+}
+
+func (r *BillingRepository) GetByCustomer(ctx context.Context, provider, customerID string) (generated.OrganizationBilling, error) {
+	return r.queries.GetOrganizationBillingByCustomer(ctx, generated.GetOrganizationBillingByCustomerParams{
+		Provider:   provider,
+		CustomerID: customerID,
+	})
+}
+
+func (r *BillingRepository) Apply(ctx context.Context, update BillingUpdate) error {
+	params := generated.UpsertOrganizationBillingParams{
+		OrgID:          update.OrgID,
+		Provider:       update.Provider,
+		CustomerID:     update.CustomerID,
+		SubscriptionID: update.SubscriptionID,
+		Plan:           update.Plan,
+		Status:         update.Status,
+		Seats:          update.Seats,
+		EventAt:        pgtype.Timestamp{Time: update.EventAt, Valid: true},
+	}
+	if update.CurrentPeriodEnd != nil {
+		params.CurrentPeriodEnd = pgtype.Timestamp{Time: *update.CurrentPeriodEnd, Valid: true}
+	}
+	return pgError(r.queries.UpsertOrganizationBilling(ctx, params))
+}
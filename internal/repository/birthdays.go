@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"time"
+
+	"BACKEND/internal/dates"
+)
+
+// birthdayWindow is the birthdays between two dates as days of the year
+// written month * 100 + day, which the users_birthday_idx index is on: one
+// range, or two when the dates cross the new year. An unused second range
+// is empty (1 to 0).
+type birthdayWindow struct {
+	from, to         int32
+	wrapFrom, wrapTo int32
+}
+
+func birthdayDay(t time.Time) int32 {
+	return int32(t.Month())*100 + int32(t.Day())
+}
+
+// newBirthdayWindow covers from to to inclusive. People born on 29 February
+// celebrate on the 28th in other years, so a window ending on 28 February
+// of a common year takes in the 29th too.
+func newBirthdayWindow(from, to time.Time) birthdayWindow {
+	w := birthdayWindow{from: birthdayDay(from), to: birthdayDay(to), wrapFrom: 1, wrapTo: 0}
+	if w.to == 228 && !dates.IsLeap(to.Year()) {
+		w.to = 229
+	}
+	if to.Year() == from.Year() {
+		return w
+	}
+	if w.to >= w.from || to.Year() > from.Year()+1 {
+		return birthdayWindow{from: 101, to: 1231, wrapFrom: 1, wrapTo: 0}
+	}
+	w.wrapFrom, w.wrapTo = 101, w.to
+	w.to = 1231
+	return w
+}
+
+func (w birthdayWindow) contains(day int32) bool {
+	return (day >= w.from && day <= w.to) || (day >= w.wrapFrom && day <= w.wrapTo)
+}
+
+// less orders birthdays as they come up in the window: the rest of this
+// year, then next year's.
+func (w birthdayWindow) less(a, b int32) bool {
+	if aNext, bNext := a < w.from, b < w.from; aNext != bNext {
+		return bNext
+	}
+	return a < b
+}
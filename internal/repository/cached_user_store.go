@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"math/rand/v2"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// CacheConfig configures the user list and stats cache. Each entry lives for
+// TTL plus up to JitterPercent of TTL more, so entries filled together don't
+// all expire together. Zero TTL disables the cache.
+type CacheConfig struct {
+	TTL           time.Duration
+	JitterPercent int
+}
+
+// cachedUserStore caches the list, count and stats reads of the wrapped
+// store. Concurrent misses for the same key share one query, and any write
+// through the store drops every entry.
+type cachedUserStore struct {
+	UserStore
+	cfg     CacheConfig
+	group   singleflight.Group
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	// gen is bumped by every write so a query that started before it can't
+	// store its now stale result.
+	gen uint64
+	now func() time.Time
+}
+
+type cacheEntry struct {
+	value     interface{}
+	expiresAt time.Time
+}
+
+func WithCache(store UserStore, cfg CacheConfig) UserStore {
+	if cfg.TTL <= 0 {
+		return store
+	}
+	return &cachedUserStore{
+		UserStore: store,
+		cfg:       cfg,
+		entries:   make(map[string]cacheEntry),
+		now:       time.Now,
+	}
+}
+
+func (s *cachedUserStore) ttl() time.Duration {
+	ttl := s.cfg.TTL
+	if jitter := int64(ttl) * int64(s.cfg.JitterPercent) / 100; jitter > 0 {
+		ttl += time.Duration(rand.Int64N(jitter + 1))
+	}
+	return ttl
+}
+
+// load returns the cached value for key or runs fn once for all concurrent
+// callers. fn runs detached from the caller's cancellation so one caller
+// giving up doesn't fail the others waiting on the same query.
+func (s *cachedUserStore) load(ctx context.Context, key string, fn func(context.Context) (interface{}, error)) (interface{}, error) {
+	s.mu.Lock()
+	if e, ok := s.entries[key]; ok && s.now().Before(e.expiresAt) {
+		s.mu.Unlock()
+		return e.value, nil
+	}
+	gen := s.gen
+	s.mu.Unlock()
+
+	// Keying the flight by generation keeps callers arriving after a write
+	// from joining a query that started before it.
+	v, err, _ := s.group.Do(key+"@"+strconv.FormatUint(gen, 10), func() (interface{}, error) {
+		v, err := fn(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		if s.gen == gen {
+			s.entries[key] = cacheEntry{value: v, expiresAt: s.now().Add(s.ttl())}
+		}
+		s.mu.Unlock()
+		return v, nil
+	})
+	return v, err
+}
+
+func (s *cachedUserStore) invalidate() {
+	s.mu.Lock()
+	s.gen++
+	s.entries = make(map[string]cacheEntry)
+	s.mu.Unlock()
+}
+
+func (s *cachedUserStore) List(ctx context.Context) ([]generated.ListUsersRow, error) {
+	v, err := s.load(ctx, "list", func(ctx context.Context) (interface{}, error) {
+		return s.UserStore.List(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]generated.ListUsersRow), nil
+}
+
+func (s *cachedUserStore) ListPaginated(ctx context.Context, limit, offset int32) ([]generated.ListUsersPaginatedRow, error) {
+	key := "list:" + strconv.Itoa(int(limit)) + ":" + strconv.Itoa(int(offset))
+	v, err := s.load(ctx, key, func(ctx context.Context) (interface{}, error) {
+		return s.UserStore.ListPaginated(ctx, limit, offset)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]generated.ListUsersPaginatedRow), nil
+}
+
+func (s *cachedUserStore) Count(ctx context.Context) (int64, error) {
+	v, err := s.load(ctx, "count", func(ctx context.Context) (interface{}, error) {
+		return s.UserStore.Count(ctx)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return v.(int64), nil
+}
+
+func (s *cachedUserStore) GetStats(ctx context.Context) (generated.GetUserStatsRow, error) {
+	v, err := s.load(ctx, "stats", func(ctx context.Context) (interface{}, error) {
+		return s.UserStore.GetStats(ctx)
+	})
+	if err != nil {
+		return generated.GetUserStatsRow{}, err
+	}
+	return v.(generated.GetUserStatsRow), nil
+}
+
+func (s *cachedUserStore) Create(ctx context.Context, name, source string, dob time.Time) (generated.CreateUserRow, error) {
+	defer s.invalidate()
+	return s.UserStore.Create(ctx, name, source, dob)
+}
+
+func (s *cachedUserStore) CreateWithAuth(ctx context.Context, name, email, passwordHash, role, source string, dob time.Time) (generated.CreateUserRow, error) {
+	defer s.invalidate()
+	return s.UserStore.CreateWithAuth(ctx, name, email, passwordHash, role, source, dob)
+}
+
+func (s *cachedUserStore) CreateServiceAccount(ctx context.Context, name, email, passwordHash, role string) (generated.CreateServiceAccountRow, error) {
+	defer s.invalidate()
+	return s.UserStore.CreateServiceAccount(ctx, name, email, passwordHash, role)
+}
+
+func (s *cachedUserStore) Update(ctx context.Context, id int64, name string, dob time.Time) (generated.UpdateUserRow, error) {
+	defer s.invalidate()
+	return s.UserStore.Update(ctx, id, name, dob)
+}
+
+func (s *cachedUserStore) SoftDelete(ctx context.Context, id int64) error {
+	defer s.invalidate()
+	return s.UserStore.SoftDelete(ctx, id)
+}
+
+func (s *cachedUserStore) Restore(ctx context.Context, id int64) (generated.RestoreUserRow, error) {
+	defer s.invalidate()
+	return s.UserStore.Restore(ctx, id)
+}
+
+func (s *cachedUserStore) HardDelete(ctx context.Context, id int64) error {
+	defer s.invalidate()
+	return s.UserStore.HardDelete(ctx, id)
+}
+
+func (s *cachedUserStore) SetActive(ctx context.Context, id int64, active bool) (generated.SetUserActiveRow, error) {
+	defer s.invalidate()
+	return s.UserStore.SetActive(ctx, id, active)
+}
+
+func (s *cachedUserStore) UpdateRole(ctx context.Context, id int64, role string) (generated.UpdateUserRoleRow, error) {
+	defer s.invalidate()
+	return s.UserStore.UpdateRole(ctx, id, role)
+}
+
+func (s *cachedUserStore) RefreshStats(ctx context.Context) error {
+	defer s.invalidate()
+	return s.UserStore.RefreshStats(ctx)
+}
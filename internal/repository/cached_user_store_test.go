@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+type countingUserStore struct {
+	UserStore
+	lists   atomic.Int32
+	release chan struct{}
+}
+
+func (s *countingUserStore) List(ctx context.Context) ([]generated.ListUsersRow, error) {
+	s.lists.Add(1)
+	if s.release != nil {
+		<-s.release
+	}
+	return []generated.ListUsersRow{{ID: 1, Name: "Jane Doe"}}, nil
+}
+
+func (s *countingUserStore) SetActive(ctx context.Context, id int64, active bool) (generated.SetUserActiveRow, error) {
+	return generated.SetUserActiveRow{ID: id}, nil
+}
+
+func TestCachedUserStore_SharesConcurrentMisses(t *testing.T) {
+	inner := &countingUserStore{release: make(chan struct{})}
+	store := WithCache(inner, CacheConfig{TTL: time.Minute})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if users, err := store.List(context.Background()); err != nil || len(users) != 1 {
+				t.Errorf("List() = %v, %v", users, err)
+			}
+		}()
+	}
+	// Let the callers pile up on the first query before it returns.
+	for inner.lists.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(inner.release)
+	wg.Wait()
+
+	if n := inner.lists.Load(); n != 1 {
+		t.Errorf("store queried %d times; want 1", n)
+	}
+}
+
+func TestCachedUserStore_ExpiresWithJitterAndInvalidates(t *testing.T) {
+	inner := &countingUserStore{}
+	store := WithCache(inner, CacheConfig{TTL: time.Minute, JitterPercent: 50}).(*cachedUserStore)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	store.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	store.List(ctx)
+	expiresIn := store.entries["list"].expiresAt.Sub(now)
+	if expiresIn < time.Minute || expiresIn > 90*time.Second {
+		t.Errorf("entry expires in %v; want between 1m and 1m30s", expiresIn)
+	}
+
+	now = now.Add(30 * time.Second)
+	store.List(ctx)
+	if n := inner.lists.Load(); n != 1 {
+		t.Errorf("store queried %d times before expiry; want 1", n)
+	}
+
+	now = now.Add(time.Minute)
+	store.List(ctx)
+	if n := inner.lists.Load(); n != 2 {
+		t.Errorf("store queried %d times after expiry; want 2", n)
+	}
+
+	store.SetActive(ctx, 1, false)
+	store.List(ctx)
+	if n := inner.lists.Load(); n != 3 {
+		t.Errorf("store queried %d times after a write; want 3", n)
+	}
+}
+
+func TestWithCache_Disabled(t *testing.T) {
+	inner := &countingUserStore{}
+	if store := WithCache(inner, CacheConfig{}); store != UserStore(inner) {
+		t.Errorf("WithCache with zero TTL wrapped the store")
+	}
+}
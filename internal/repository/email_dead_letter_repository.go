@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// EmailDeadLetterStore keeps emails that could not be delivered so an
+// admin can look at why and send them again. Recipients are stored
+// comma-separated.
+type EmailDeadLetterStore interface {
+	Create(ctx context.Context, recipients, subject, html, lastError string, attempts int32) (generated.EmailDeadLetter, error)
+	Get(ctx context.Context, id int64) (generated.EmailDeadLetter, error)
+	// List returns the newest dead letters first.
+	List(ctx context.Context, limit int32) ([]generated.EmailDeadLetter, error)
+	// RecordAttempt counts another failed try and keeps its error.
+	RecordAttempt(ctx context.Context, id int64, lastError string) error
+	Delete(ctx context.Context, id int64) (bool, error)
+}
+
+var (
+	_ EmailDeadLetterStore = (*EmailDeadLetterRepository)(nil)
+	_ EmailDeadLetterStore = (*MySQLEmailDeadLetterRepository)(nil)
+	_ EmailDeadLetterStore = (*MemoryEmailDeadLetterStore)(nil)
+)
+
+type EmailDeadLetterRepository struct {
+	queries *generated.Queries
+}
+
+func NewEmailDeadLetterRepository(q *generated.Queries) *EmailDeadLetterRepository {
+	return &EmailDeadLetterRepository{queries: q}
+}
+
+func (r *EmailDeadLetterRepository) Create(ctx context.Context, recipients, subject, html, lastError string, attempts int32) (generated.EmailDeadLetter, error) {
+	row, err := r.queries.CreateEmailDeadLetter(ctx, generated.CreateEmailDeadLetterParams{
+		Recipients: recipients,
+		Subject:    subject,
+		Html:       html,
+		Error:      lastError,
+		Attempts:   attempts,
+	})
+	return row, pgError(err)
+}
+
+func (r *EmailDeadLetterRepository) Get(ctx context.Context, id int64) (generated.EmailDeadLetter, error) {
+	return r.queries.GetEmailDeadLetter(ctx, id)
+}
+
+func (r *EmailDeadLetterRepository) List(ctx context.Context, limit int32) ([]generated.EmailDeadLetter, error) {
+	return r.queries.ListEmailDeadLetters(ctx, limit)
+}
+
+func (r *EmailDeadLetterRepository) RecordAttempt(ctx context.Context, id int64, lastError string) error {
+	return pgError(r.queries.RecordEmailDeadLetterAttempt(ctx, generated.RecordEmailDeadLetterAttemptParams{
+		ID:    id,
+		Error: lastError,
+	}))
+}
+
+func (r *EmailDeadLetterRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	n, err := r.queries.DeleteEmailDeadLetter(ctx, id)
+	return n > 0, pgError(err)
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Constraint violations the repositories report, wrapped in a
+// *ConstraintError. Test for them with errors.Is.
+var (
+	ErrUniqueViolation     = errors.New("unique constraint violation")
+	ErrForeignKeyViolation = errors.New("foreign key violation")
+	ErrCheckViolation      = errors.New("check constraint violation")
+
+	// ErrEmailAlreadyExists is the unique violation on users.email.
+	ErrEmailAlreadyExists = errors.New("email already exists")
+)
+
+// emailConstraints are the names the unique index on users.email has:
+// Postgres's default, and MySQL's key name with and without its table.
+var emailConstraints = map[string]bool{
+	"users_email_key": true,
+	"users.email":     true,
+	"email":           true,
+}
+
+// ConstraintError is a write the database rejected for breaking a
+// constraint. Kind is ErrUniqueViolation, ErrForeignKeyViolation or
+// ErrCheckViolation, and Constraint the name of the constraint or index.
+type ConstraintError struct {
+	Kind       error
+	Constraint string
+	Err        error
+}
+
+func (e *ConstraintError) Error() string {
+	return fmt.Sprintf("%v on %s: %v", e.Kind, e.Constraint, e.Err)
+}
+
+func (e *ConstraintError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+func (e *ConstraintError) Is(target error) bool {
+	return target == ErrEmailAlreadyExists && e.Kind == ErrUniqueViolation && emailConstraints[e.Constraint]
+}
+
+// SQLSTATEs of the constraint violations Postgres reports.
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgCheckViolation      = "23514"
+)
+
+// pgError turns a Postgres constraint violation into a *ConstraintError and
+// returns any other error, or nil, unchanged.
+func pgError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+	var kind error
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		kind = ErrUniqueViolation
+	case pgForeignKeyViolation:
+		kind = ErrForeignKeyViolation
+	case pgCheckViolation:
+		kind = ErrCheckViolation
+	default:
+		return err
+	}
+	return &ConstraintError{Kind: kind, Constraint: pgErr.ConstraintName, Err: err}
+}
+
+// MySQL error numbers of the constraint violations, and where each message
+// names the constraint. The driver is only built with -tags mysql, so its
+// error type can't be used here; its messages start with "Error <number>".
+var (
+	mysqlErrorNumber  = regexp.MustCompile(`^Error (\d+)`)
+	mysqlDuplicateKey = regexp.MustCompile(`for key '([^']+)'`)
+	mysqlConstraint   = regexp.MustCompile("CONSTRAINT `([^`]+)`|[Cc]heck constraint '([^']+)'")
+)
+
+const (
+	mysqlDuplicateEntry  = 1062
+	mysqlRowIsReferenced = 1451
+	mysqlNoReferencedRow = 1452
+	mysqlCheckViolated   = 3819
+)
+
+func mysqlConstraintError(err error) error {
+	m := mysqlErrorNumber.FindStringSubmatch(err.Error())
+	if m == nil {
+		return err
+	}
+	number, _ := strconv.Atoi(m[1])
+
+	var kind error
+	var name []string
+	switch number {
+	case mysqlDuplicateEntry:
+		kind = ErrUniqueViolation
+		name = mysqlDuplicateKey.FindStringSubmatch(err.Error())
+	case mysqlRowIsReferenced, mysqlNoReferencedRow:
+		kind = ErrForeignKeyViolation
+		name = mysqlConstraint.FindStringSubmatch(err.Error())
+	case mysqlCheckViolated:
+		kind = ErrCheckViolation
+		name = mysqlConstraint.FindStringSubmatch(err.Error())
+	default:
+		return err
+	}
+
+	ce := &ConstraintError{Kind: kind, Err: err}
+	if len(name) > 0 {
+		// The patterns have one group per place a name can appear.
+		for _, n := range name[1:] {
+			if n != "" {
+				ce.Constraint = n
+				break
+			}
+		}
+	}
+	return ce
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestPgError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantKind       error
+		wantConstraint string
+		wantEmail      bool
+	}{
+		{"email", &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key"}, ErrUniqueViolation, "users_email_key", true},
+		{"other unique index", &pgconn.PgError{Code: "23505", ConstraintName: "api_keys_key_hash_key"}, ErrUniqueViolation, "api_keys_key_hash_key", false},
+		{"foreign key", &pgconn.PgError{Code: "23503", ConstraintName: "api_keys_user_id_fkey"}, ErrForeignKeyViolation, "api_keys_user_id_fkey", false},
+		{"check", &pgconn.PgError{Code: "23514", ConstraintName: "users_role_check"}, ErrCheckViolation, "users_role_check", false},
+		{"wrapped", fmt.Errorf("hook: %w", &pgconn.PgError{Code: "23505", ConstraintName: "users_email_key"}), ErrUniqueViolation, "users_email_key", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := pgError(tt.err)
+			var ce *ConstraintError
+			if !errors.As(err, &ce) {
+				t.Fatalf("pgError() = %v; want a *ConstraintError", err)
+			}
+			if !errors.Is(err, tt.wantKind) || ce.Constraint != tt.wantConstraint {
+				t.Errorf("pgError() = %v; want %v on %s", err, tt.wantKind, tt.wantConstraint)
+			}
+			if got := errors.Is(err, ErrEmailAlreadyExists); got != tt.wantEmail {
+				t.Errorf("errors.Is(ErrEmailAlreadyExists) = %v; want %v", got, tt.wantEmail)
+			}
+			var pgErr *pgconn.PgError
+			if !errors.As(err, &pgErr) {
+				t.Error("the driver error should still be reachable")
+			}
+		})
+	}
+}
+
+func TestPgErrorPassesOtherErrors(t *testing.T) {
+	notNull := &pgconn.PgError{Code: "23502"}
+	plain := errors.New("connection reset")
+	for _, err := range []error{nil, notNull, plain} {
+		if got := pgError(err); got != err {
+			t.Errorf("pgError(%v) = %v; want it unchanged", err, got)
+		}
+	}
+}
+
+func TestMySQLConstraintError(t *testing.T) {
+	tests := []struct {
+		name           string
+		msg            string
+		wantKind       error
+		wantConstraint string
+		wantEmail      bool
+	}{
+		{"email", "Error 1062 (23000): Duplicate entry 'jane@example.com' for key 'users.email'", ErrUniqueViolation, "users.email", true},
+		{"other key", "Error 1062 (23000): Duplicate entry 'abc' for key 'referral_codes.code'", ErrUniqueViolation, "referral_codes.code", false},
+		{"missing parent", "Error 1452 (23000): Cannot add or update a child row: a foreign key constraint fails (`userdb`.`api_keys`, CONSTRAINT `api_keys_ibfk_1` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`))", ErrForeignKeyViolation, "api_keys_ibfk_1", false},
+		{"check", "Error 3819 (HY000): Check constraint 'users_chk_1' is violated.", ErrCheckViolation, "users_chk_1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mysqlError(errors.New(tt.msg))
+			var ce *ConstraintError
+			if !errors.As(err, &ce) {
+				t.Fatalf("mysqlError() = %v; want a *ConstraintError", err)
+			}
+			if !errors.Is(err, tt.wantKind) || ce.Constraint != tt.wantConstraint {
+				t.Errorf("mysqlError() = %v; want %v on %s", err, tt.wantKind, tt.wantConstraint)
+			}
+			if got := errors.Is(err, ErrEmailAlreadyExists); got != tt.wantEmail {
+				t.Errorf("errors.Is(ErrEmailAlreadyExists) = %v; want %v", got, tt.wantEmail)
+			}
+		})
+	}
+
+	if err := mysqlError(errors.New("Error 1213: Deadlock found")); errors.As(err, new(*ConstraintError)) {
+		t.Errorf("a deadlock should not be a constraint error: %v", err)
+	}
+}
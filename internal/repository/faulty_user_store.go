@@ -0,0 +1,264 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// ErrInjectedFault is the error WithFaults fails calls with.
+var ErrInjectedFault = errors.New("injected database fault")
+
+// faultyUserStore fails a share of calls with ErrInjectedFault before they
+// reach the wrapped store, to test how the API and its clients cope with
+// database errors. Like instrumentedUserStore it doesn't embed the store,
+// so no method is left out.
+type faultyUserStore struct {
+	store   UserStore
+	percent int
+	chance  func(percent int) bool
+}
+
+// WithFaults fails percent of the calls to store. It is for development
+// only.
+func WithFaults(store UserStore, percent int) UserStore {
+	if percent <= 0 {
+		return store
+	}
+	return &faultyUserStore{
+		store:   store,
+		percent: percent,
+		chance:  func(percent int) bool { return rand.IntN(100) < percent },
+	}
+}
+
+func (s *faultyUserStore) fail() bool {
+	return s.chance(s.percent)
+}
+
+func (s *faultyUserStore) Create(ctx context.Context, name, source string, dob time.Time) (generated.CreateUserRow, error) {
+	if s.fail() {
+		return generated.CreateUserRow{}, ErrInjectedFault
+	}
+	return s.store.Create(ctx, name, source, dob)
+}
+
+func (s *faultyUserStore) CreateWithAuth(ctx context.Context, name, email, passwordHash, role, source string, dob time.Time) (generated.CreateUserRow, error) {
+	if s.fail() {
+		return generated.CreateUserRow{}, ErrInjectedFault
+	}
+	return s.store.CreateWithAuth(ctx, name, email, passwordHash, role, source, dob)
+}
+
+func (s *faultyUserStore) GetByID(ctx context.Context, id int64) (generated.GetUserByIDRow, error) {
+	if s.fail() {
+		return generated.GetUserByIDRow{}, ErrInjectedFault
+	}
+	return s.store.GetByID(ctx, id)
+}
+
+func (s *faultyUserStore) GetIDByPublicID(ctx context.Context, publicID string) (int64, error) {
+	if s.fail() {
+		return 0, ErrInjectedFault
+	}
+	return s.store.GetIDByPublicID(ctx, publicID)
+}
+
+func (s *faultyUserStore) GetByEmail(ctx context.Context, email string) (generated.User, error) {
+	if s.fail() {
+		return generated.User{}, ErrInjectedFault
+	}
+	return s.store.GetByEmail(ctx, email)
+}
+
+func (s *faultyUserStore) LookupByEmail(ctx context.Context, prefix string, limit int32) ([]generated.LookupUsersByEmailRow, error) {
+	if s.fail() {
+		return nil, ErrInjectedFault
+	}
+	return s.store.LookupByEmail(ctx, prefix, limit)
+}
+
+func (s *faultyUserStore) List(ctx context.Context) ([]generated.ListUsersRow, error) {
+	if s.fail() {
+		return nil, ErrInjectedFault
+	}
+	return s.store.List(ctx)
+}
+
+func (s *faultyUserStore) ListPaginated(ctx context.Context, limit, offset int32) ([]generated.ListUsersPaginatedRow, error) {
+	if s.fail() {
+		return nil, ErrInjectedFault
+	}
+	return s.store.ListPaginated(ctx, limit, offset)
+}
+
+func (s *faultyUserStore) ListBySignupSource(ctx context.Context, source string) ([]generated.ListUsersBySignupSourceRow, error) {
+	if s.fail() {
+		return nil, ErrInjectedFault
+	}
+	return s.store.ListBySignupSource(ctx, source)
+}
+
+func (s *faultyUserStore) Count(ctx context.Context) (int64, error) {
+	if s.fail() {
+		return 0, ErrInjectedFault
+	}
+	return s.store.Count(ctx)
+}
+
+func (s *faultyUserStore) Search(ctx context.Context, filter UserFilter, sort []UserSortKey, limit, offset int32) ([]generated.SearchUsersRow, error) {
+	if s.fail() {
+		return nil, ErrInjectedFault
+	}
+	return s.store.Search(ctx, filter, sort, limit, offset)
+}
+
+func (s *faultyUserStore) CountSearch(ctx context.Context, filter UserFilter) (int64, error) {
+	if s.fail() {
+		return 0, ErrInjectedFault
+	}
+	return s.store.CountSearch(ctx, filter)
+}
+
+func (s *faultyUserStore) Update(ctx context.Context, id int64, name string, dob time.Time) (generated.UpdateUserRow, error) {
+	if s.fail() {
+		return generated.UpdateUserRow{}, ErrInjectedFault
+	}
+	return s.store.Update(ctx, id, name, dob)
+}
+
+func (s *faultyUserStore) SoftDelete(ctx context.Context, id int64) error {
+	if s.fail() {
+		return ErrInjectedFault
+	}
+	return s.store.SoftDelete(ctx, id)
+}
+
+func (s *faultyUserStore) Restore(ctx context.Context, id int64) (generated.RestoreUserRow, error) {
+	if s.fail() {
+		return generated.RestoreUserRow{}, ErrInjectedFault
+	}
+	return s.store.Restore(ctx, id)
+}
+
+func (s *faultyUserStore) HardDelete(ctx context.Context, id int64) error {
+	if s.fail() {
+		return ErrInjectedFault
+	}
+	return s.store.HardDelete(ctx, id)
+}
+
+func (s *faultyUserStore) GetDeletedByID(ctx context.Context, id int64) (generated.GetDeletedUserByIDRow, error) {
+	if s.fail() {
+		return generated.GetDeletedUserByIDRow{}, ErrInjectedFault
+	}
+	return s.store.GetDeletedByID(ctx, id)
+}
+
+func (s *faultyUserStore) SetActive(ctx context.Context, id int64, active bool) (generated.SetUserActiveRow, error) {
+	if s.fail() {
+		return generated.SetUserActiveRow{}, ErrInjectedFault
+	}
+	return s.store.SetActive(ctx, id, active)
+}
+
+func (s *faultyUserStore) UpdateRole(ctx context.Context, id int64, role string) (generated.UpdateUserRoleRow, error) {
+	if s.fail() {
+		return generated.UpdateUserRoleRow{}, ErrInjectedFault
+	}
+	return s.store.UpdateRole(ctx, id, role)
+}
+
+func (s *faultyUserStore) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	if s.fail() {
+		return ErrInjectedFault
+	}
+	return s.store.UpdatePassword(ctx, id, passwordHash)
+}
+
+func (s *faultyUserStore) CreateServiceAccount(ctx context.Context, name, email, passwordHash, role string) (generated.CreateServiceAccountRow, error) {
+	if s.fail() {
+		return generated.CreateServiceAccountRow{}, ErrInjectedFault
+	}
+	return s.store.CreateServiceAccount(ctx, name, email, passwordHash, role)
+}
+
+func (s *faultyUserStore) ListServiceAccounts(ctx context.Context) ([]generated.ListServiceAccountsRow, error) {
+	if s.fail() {
+		return nil, ErrInjectedFault
+	}
+	return s.store.ListServiceAccounts(ctx)
+}
+
+func (s *faultyUserStore) UsersByAgeBracket(ctx context.Context) ([]generated.UsersByAgeBracketRow, error) {
+	if s.fail() {
+		return nil, ErrInjectedFault
+	}
+	return s.store.UsersByAgeBracket(ctx)
+}
+
+func (s *faultyUserStore) AgeDistribution(ctx context.Context, groupBy string, bucketSize int32) ([]generated.AgeDistributionRow, error) {
+	if s.fail() {
+		return nil, ErrInjectedFault
+	}
+	return s.store.AgeDistribution(ctx, groupBy, bucketSize)
+}
+
+func (s *faultyUserStore) SignupsByDay(ctx context.Context, since time.Time) ([]generated.SignupsByDayRow, error) {
+	if s.fail() {
+		return nil, ErrInjectedFault
+	}
+	return s.store.SignupsByDay(ctx, since)
+}
+
+func (s *faultyUserStore) SignupsByMonth(ctx context.Context, since time.Time) ([]generated.SignupsByMonthRow, error) {
+	if s.fail() {
+		return nil, ErrInjectedFault
+	}
+	return s.store.SignupsByMonth(ctx, since)
+}
+
+func (s *faultyUserStore) SignupsBySource(ctx context.Context, since time.Time) ([]generated.SignupsBySourceRow, error) {
+	if s.fail() {
+		return nil, ErrInjectedFault
+	}
+	return s.store.SignupsBySource(ctx, since)
+}
+
+func (s *faultyUserStore) ListDeactivatedSince(ctx context.Context, since time.Time) ([]generated.ListDeactivatedUsersRow, error) {
+	if s.fail() {
+		return nil, ErrInjectedFault
+	}
+	return s.store.ListDeactivatedSince(ctx, since)
+}
+
+func (s *faultyUserStore) UpcomingBirthdays(ctx context.Context, from, to time.Time, limit int32) ([]generated.ListUpcomingBirthdaysRow, error) {
+	if s.fail() {
+		return nil, ErrInjectedFault
+	}
+	return s.store.UpcomingBirthdays(ctx, from, to, limit)
+}
+
+func (s *faultyUserStore) ListActiveAdmins(ctx context.Context) ([]generated.ListActiveAdminsRow, error) {
+	if s.fail() {
+		return nil, ErrInjectedFault
+	}
+	return s.store.ListActiveAdmins(ctx)
+}
+
+func (s *faultyUserStore) GetStats(ctx context.Context) (generated.GetUserStatsRow, error) {
+	if s.fail() {
+		return generated.GetUserStatsRow{}, ErrInjectedFault
+	}
+	return s.store.GetStats(ctx)
+}
+
+func (s *faultyUserStore) RefreshStats(ctx context.Context) error {
+	if s.fail() {
+		return ErrInjectedFault
+	}
+	return s.store.RefreshStats(ctx)
+}
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFaultyUserStore(t *testing.T) {
+	inner := &countingUserStore{}
+	store := WithFaults(inner, 50).(*faultyUserStore)
+	fail := true
+	store.chance = func(percent int) bool {
+		if percent != 50 {
+			t.Errorf("chance(%d); want 50", percent)
+		}
+		return fail
+	}
+
+	if _, err := store.List(context.Background()); !errors.Is(err, ErrInjectedFault) {
+		t.Errorf("List() error = %v; want ErrInjectedFault", err)
+	}
+	if n := inner.lists.Load(); n != 0 {
+		t.Errorf("failed call reached the store %d times", n)
+	}
+
+	fail = false
+	if users, err := store.List(context.Background()); err != nil || len(users) != 1 {
+		t.Errorf("List() = %v, %v; want the store's result", users, err)
+	}
+
+	if WithFaults(inner, 0) != UserStore(inner) {
+		t.Error("WithFaults(store, 0) should return the store itself")
+	}
+}
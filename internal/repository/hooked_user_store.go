@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/hooks"
+)
+
+// hookedUserStore runs the create and delete hooks around the wrapped store,
+// so every path that writes users (signup, admin, SCIM, SSO) is covered.
+type hookedUserStore struct {
+	UserStore
+	hooks *hooks.Registry
+}
+
+func WithHooks(store UserStore, registry *hooks.Registry) UserStore {
+	if registry == nil {
+		return store
+	}
+	return &hookedUserStore{UserStore: store, hooks: registry}
+}
+
+func (s *hookedUserStore) Create(ctx context.Context, name, source string, dob time.Time) (generated.CreateUserRow, error) {
+	u := &hooks.User{Name: name, SignupSource: source, Dob: dob}
+	if err := s.hooks.Run(ctx, hooks.BeforeUserCreate, u); err != nil {
+		return generated.CreateUserRow{}, err
+	}
+	return s.UserStore.Create(ctx, u.Name, source, u.Dob)
+}
+
+func (s *hookedUserStore) CreateWithAuth(ctx context.Context, name, email, passwordHash, role, source string, dob time.Time) (generated.CreateUserRow, error) {
+	u := &hooks.User{Name: name, Email: email, Role: role, SignupSource: source, Dob: dob}
+	if err := s.hooks.Run(ctx, hooks.BeforeUserCreate, u); err != nil {
+		return generated.CreateUserRow{}, err
+	}
+	return s.UserStore.CreateWithAuth(ctx, u.Name, u.Email, passwordHash, u.Role, source, u.Dob)
+}
+
+func (s *hookedUserStore) CreateServiceAccount(ctx context.Context, name, email, passwordHash, role string) (generated.CreateServiceAccountRow, error) {
+	u := &hooks.User{Name: name, Email: email, Role: role, AccountType: "service", SignupSource: "admin"}
+	if err := s.hooks.Run(ctx, hooks.BeforeUserCreate, u); err != nil {
+		return generated.CreateServiceAccountRow{}, err
+	}
+	return s.UserStore.CreateServiceAccount(ctx, u.Name, u.Email, passwordHash, u.Role)
+}
+
+// beforeDelete runs the delete hooks for the user.
+func (s *hookedUserStore) beforeDelete(ctx context.Context, id int64) error {
+	u := &hooks.User{ID: id}
+	if existing, err := s.UserStore.GetByID(ctx, id); err == nil {
+		u = &hooks.User{
+			ID:           existing.ID,
+			Name:         existing.Name,
+			Email:        existing.Email,
+			Role:         existing.Role,
+			AccountType:  existing.AccountType,
+			SignupSource: existing.SignupSource,
+			Dob:          existing.Dob.Time,
+		}
+	}
+	return s.hooks.Run(ctx, hooks.BeforeDelete, u)
+}
+
+func (s *hookedUserStore) SoftDelete(ctx context.Context, id int64) error {
+	if err := s.beforeDelete(ctx, id); err != nil {
+		return err
+	}
+	return s.UserStore.SoftDelete(ctx, id)
+}
+
+// HardDelete runs the delete hooks unless the user is already soft deleted,
+// as they ran then.
+func (s *hookedUserStore) HardDelete(ctx context.Context, id int64) error {
+	if _, err := s.UserStore.GetDeletedByID(ctx, id); err != nil {
+		if err := s.beforeDelete(ctx, id); err != nil {
+			return err
+		}
+	}
+	return s.UserStore.HardDelete(ctx, id)
+}
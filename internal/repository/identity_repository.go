@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// IdentityStore holds the external identities (provider and subject) users
+// have linked to their accounts.
+type IdentityStore interface {
+	Create(ctx context.Context, userID int64, provider, subject, email string) (generated.UserIdentity, error)
+	GetBySubject(ctx context.Context, provider, subject string) (generated.UserIdentity, error)
+	ListByUser(ctx context.Context, userID int64) ([]generated.UserIdentity, error)
+	// Delete reports false when the user had no identity from the provider.
+	Delete(ctx context.Context, userID int64, provider string) (bool, error)
+}
+
+var (
+	_ IdentityStore = (*IdentityRepository)(nil)
+	_ IdentityStore = (*MySQLIdentityRepository)(nil)
+	_ IdentityStore = (*MemoryIdentityStore)(nil)
+)
+
+type IdentityRepository struct {
+	queries *generated.Queries
+}
+
+func NewIdentityRepository(q *generated.Queries) *IdentityRepository {
+	return &IdentityRepository{queries: q}
+}
+
+func (r *IdentityRepository) Create(ctx context.Context, userID int64, provider, subject, email string) (generated.UserIdentity, error) {
+	row, err := r.queries.CreateUserIdentity(ctx, generated.CreateUserIdentityParams{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  subject,
+		Email:    email,
+	})
+	return row, pgError(err)
+}
+
+func (r *IdentityRepository) GetBySubject(ctx context.Context, provider, subject string) (generated.UserIdentity, error) {
+	return r.queries.GetUserIdentityBySubject(ctx, generated.GetUserIdentityBySubjectParams{
+		Provider: provider,
+		Subject:  subject,
+	})
+}
+
+func (r *IdentityRepository) ListByUser(ctx context.Context, userID int64) ([]generated.UserIdentity, error) {
+	return r.queries.ListUserIdentitiesByUser(ctx, userID)
+}
+
+func (r *IdentityRepository) Delete(ctx context.Context, userID int64, provider string) (bool, error) {
+	n, err := r.queries.DeleteUserIdentity(ctx, generated.DeleteUserIdentityParams{
+		UserID:   userID,
+		Provider: provider,
+	})
+	return n > 0, err
+}
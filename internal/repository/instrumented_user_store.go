@@ -0,0 +1,323 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/tracing"
+)
+
+// instrumentedUserStore records every call to the wrapped store in Metrics
+// and as a span. It doesn't embed the store, so a method added to UserStore
+// fails to compile here instead of going unrecorded.
+type instrumentedUserStore struct {
+	store   UserStore
+	metrics *Metrics
+}
+
+// WithInstrumentation records the latency, errors and rows of every call to
+// store. Wrap the database store directly, so cache hits aren't counted as
+// queries.
+func WithInstrumentation(store UserStore, metrics *Metrics) UserStore {
+	if metrics == nil {
+		return store
+	}
+	return &instrumentedUserStore{store: store, metrics: metrics}
+}
+
+func (s *instrumentedUserStore) Create(ctx context.Context, name, source string, dob time.Time) (generated.CreateUserRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.Create")
+	start := time.Now()
+	result, err := s.store.Create(ctx, name, source, dob)
+	s.metrics.observe("UserStore.Create", start, rowCount(err), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) CreateWithAuth(ctx context.Context, name, email, passwordHash, role, source string, dob time.Time) (generated.CreateUserRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.CreateWithAuth")
+	start := time.Now()
+	result, err := s.store.CreateWithAuth(ctx, name, email, passwordHash, role, source, dob)
+	s.metrics.observe("UserStore.CreateWithAuth", start, rowCount(err), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) GetByID(ctx context.Context, id int64) (generated.GetUserByIDRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.GetByID")
+	start := time.Now()
+	result, err := s.store.GetByID(ctx, id)
+	s.metrics.observe("UserStore.GetByID", start, rowCount(err), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) GetIDByPublicID(ctx context.Context, publicID string) (int64, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.GetIDByPublicID")
+	start := time.Now()
+	result, err := s.store.GetIDByPublicID(ctx, publicID)
+	s.metrics.observe("UserStore.GetIDByPublicID", start, rowCount(err), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) GetByEmail(ctx context.Context, email string) (generated.User, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.GetByEmail")
+	start := time.Now()
+	result, err := s.store.GetByEmail(ctx, email)
+	s.metrics.observe("UserStore.GetByEmail", start, rowCount(err), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) LookupByEmail(ctx context.Context, prefix string, limit int32) ([]generated.LookupUsersByEmailRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.LookupByEmail")
+	start := time.Now()
+	result, err := s.store.LookupByEmail(ctx, prefix, limit)
+	s.metrics.observe("UserStore.LookupByEmail", start, len(result), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) List(ctx context.Context) ([]generated.ListUsersRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.List")
+	start := time.Now()
+	result, err := s.store.List(ctx)
+	s.metrics.observe("UserStore.List", start, len(result), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) ListPaginated(ctx context.Context, limit, offset int32) ([]generated.ListUsersPaginatedRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.ListPaginated")
+	start := time.Now()
+	result, err := s.store.ListPaginated(ctx, limit, offset)
+	s.metrics.observe("UserStore.ListPaginated", start, len(result), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) ListBySignupSource(ctx context.Context, source string) ([]generated.ListUsersBySignupSourceRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.ListBySignupSource")
+	start := time.Now()
+	result, err := s.store.ListBySignupSource(ctx, source)
+	s.metrics.observe("UserStore.ListBySignupSource", start, len(result), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) Count(ctx context.Context) (int64, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.Count")
+	start := time.Now()
+	result, err := s.store.Count(ctx)
+	s.metrics.observe("UserStore.Count", start, rowCount(err), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) Search(ctx context.Context, filter UserFilter, sort []UserSortKey, limit, offset int32) ([]generated.SearchUsersRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.Search")
+	start := time.Now()
+	result, err := s.store.Search(ctx, filter, sort, limit, offset)
+	s.metrics.observe("UserStore.Search", start, len(result), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) CountSearch(ctx context.Context, filter UserFilter) (int64, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.CountSearch")
+	start := time.Now()
+	result, err := s.store.CountSearch(ctx, filter)
+	s.metrics.observe("UserStore.CountSearch", start, rowCount(err), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) Update(ctx context.Context, id int64, name string, dob time.Time) (generated.UpdateUserRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.Update")
+	start := time.Now()
+	result, err := s.store.Update(ctx, id, name, dob)
+	s.metrics.observe("UserStore.Update", start, rowCount(err), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) SoftDelete(ctx context.Context, id int64) error {
+	ctx, span := tracing.Start(ctx, "UserStore.SoftDelete")
+	start := time.Now()
+	err := s.store.SoftDelete(ctx, id)
+	s.metrics.observe("UserStore.SoftDelete", start, 0, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *instrumentedUserStore) Restore(ctx context.Context, id int64) (generated.RestoreUserRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.Restore")
+	start := time.Now()
+	result, err := s.store.Restore(ctx, id)
+	s.metrics.observe("UserStore.Restore", start, rowCount(err), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) HardDelete(ctx context.Context, id int64) error {
+	ctx, span := tracing.Start(ctx, "UserStore.HardDelete")
+	start := time.Now()
+	err := s.store.HardDelete(ctx, id)
+	s.metrics.observe("UserStore.HardDelete", start, 0, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *instrumentedUserStore) GetDeletedByID(ctx context.Context, id int64) (generated.GetDeletedUserByIDRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.GetDeletedByID")
+	start := time.Now()
+	result, err := s.store.GetDeletedByID(ctx, id)
+	s.metrics.observe("UserStore.GetDeletedByID", start, rowCount(err), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) SetActive(ctx context.Context, id int64, active bool) (generated.SetUserActiveRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.SetActive")
+	start := time.Now()
+	result, err := s.store.SetActive(ctx, id, active)
+	s.metrics.observe("UserStore.SetActive", start, rowCount(err), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) UpdateRole(ctx context.Context, id int64, role string) (generated.UpdateUserRoleRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.UpdateRole")
+	start := time.Now()
+	result, err := s.store.UpdateRole(ctx, id, role)
+	s.metrics.observe("UserStore.UpdateRole", start, rowCount(err), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	ctx, span := tracing.Start(ctx, "UserStore.UpdatePassword")
+	start := time.Now()
+	err := s.store.UpdatePassword(ctx, id, passwordHash)
+	s.metrics.observe("UserStore.UpdatePassword", start, 0, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *instrumentedUserStore) CreateServiceAccount(ctx context.Context, name, email, passwordHash, role string) (generated.CreateServiceAccountRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.CreateServiceAccount")
+	start := time.Now()
+	result, err := s.store.CreateServiceAccount(ctx, name, email, passwordHash, role)
+	s.metrics.observe("UserStore.CreateServiceAccount", start, rowCount(err), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) ListServiceAccounts(ctx context.Context) ([]generated.ListServiceAccountsRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.ListServiceAccounts")
+	start := time.Now()
+	result, err := s.store.ListServiceAccounts(ctx)
+	s.metrics.observe("UserStore.ListServiceAccounts", start, len(result), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) UsersByAgeBracket(ctx context.Context) ([]generated.UsersByAgeBracketRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.UsersByAgeBracket")
+	start := time.Now()
+	result, err := s.store.UsersByAgeBracket(ctx)
+	s.metrics.observe("UserStore.UsersByAgeBracket", start, len(result), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) AgeDistribution(ctx context.Context, groupBy string, bucketSize int32) ([]generated.AgeDistributionRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.AgeDistribution")
+	start := time.Now()
+	result, err := s.store.AgeDistribution(ctx, groupBy, bucketSize)
+	s.metrics.observe("UserStore.AgeDistribution", start, len(result), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) SignupsByDay(ctx context.Context, since time.Time) ([]generated.SignupsByDayRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.SignupsByDay")
+	start := time.Now()
+	result, err := s.store.SignupsByDay(ctx, since)
+	s.metrics.observe("UserStore.SignupsByDay", start, len(result), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) SignupsByMonth(ctx context.Context, since time.Time) ([]generated.SignupsByMonthRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.SignupsByMonth")
+	start := time.Now()
+	result, err := s.store.SignupsByMonth(ctx, since)
+	s.metrics.observe("UserStore.SignupsByMonth", start, len(result), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) SignupsBySource(ctx context.Context, since time.Time) ([]generated.SignupsBySourceRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.SignupsBySource")
+	start := time.Now()
+	result, err := s.store.SignupsBySource(ctx, since)
+	s.metrics.observe("UserStore.SignupsBySource", start, len(result), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) ListDeactivatedSince(ctx context.Context, since time.Time) ([]generated.ListDeactivatedUsersRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.ListDeactivatedSince")
+	start := time.Now()
+	result, err := s.store.ListDeactivatedSince(ctx, since)
+	s.metrics.observe("UserStore.ListDeactivatedSince", start, len(result), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) UpcomingBirthdays(ctx context.Context, from, to time.Time, limit int32) ([]generated.ListUpcomingBirthdaysRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.UpcomingBirthdays")
+	start := time.Now()
+	result, err := s.store.UpcomingBirthdays(ctx, from, to, limit)
+	s.metrics.observe("UserStore.UpcomingBirthdays", start, len(result), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) ListActiveAdmins(ctx context.Context) ([]generated.ListActiveAdminsRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.ListActiveAdmins")
+	start := time.Now()
+	result, err := s.store.ListActiveAdmins(ctx)
+	s.metrics.observe("UserStore.ListActiveAdmins", start, len(result), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) GetStats(ctx context.Context) (generated.GetUserStatsRow, error) {
+	ctx, span := tracing.Start(ctx, "UserStore.GetStats")
+	start := time.Now()
+	result, err := s.store.GetStats(ctx)
+	s.metrics.observe("UserStore.GetStats", start, rowCount(err), err)
+	tracing.End(span, err)
+	return result, err
+}
+
+func (s *instrumentedUserStore) RefreshStats(ctx context.Context) error {
+	ctx, span := tracing.Start(ctx, "UserStore.RefreshStats")
+	start := time.Now()
+	err := s.store.RefreshStats(ctx)
+	s.metrics.observe("UserStore.RefreshStats", start, 0, err)
+	tracing.End(span, err)
+	return err
+}
+
+// rowCount is the number of rows a single-row call returned.
+func rowCount(err error) int {
+	if err != nil {
+		return 0
+	}
+	return 1
+}
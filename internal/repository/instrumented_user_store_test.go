@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+type slowUserStore struct {
+	UserStore
+	delay time.Duration
+}
+
+func (s *slowUserStore) List(ctx context.Context) ([]generated.ListUsersRow, error) {
+	time.Sleep(s.delay)
+	return []generated.ListUsersRow{{ID: 1}, {ID: 2}, {ID: 3}}, nil
+}
+
+func (s *slowUserStore) GetByID(ctx context.Context, id int64) (generated.GetUserByIDRow, error) {
+	switch id {
+	case 1:
+		return generated.GetUserByIDRow{ID: id}, nil
+	case 2:
+		return generated.GetUserByIDRow{}, pgx.ErrNoRows
+	}
+	return generated.GetUserByIDRow{}, errors.New("connection reset")
+}
+
+func TestInstrumentedUserStore_RecordsCalls(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	metrics := NewMetrics(zap.New(core), 5*time.Millisecond)
+	store := WithInstrumentation(&slowUserStore{delay: 10 * time.Millisecond}, metrics)
+	ctx := context.Background()
+
+	store.List(ctx)
+	for _, id := range []int64{1, 2, 3, 3} {
+		store.GetByID(ctx, id)
+	}
+
+	stats := metrics.Stats()
+	if len(stats) != 2 {
+		t.Fatalf("got %d methods; want 2: %+v", len(stats), stats)
+	}
+
+	get, list := stats[0], stats[1]
+	if get.Method != "UserStore.GetByID" || get.Calls != 4 || get.Errors != 2 || get.ErrorRate != 0.5 || get.Rows != 1 || get.Slow != 0 {
+		t.Errorf("GetByID stats = %+v; want 4 calls, 2 errors (not found isn't one), 1 row", get)
+	}
+	if list.Method != "UserStore.List" || list.Calls != 1 || list.Rows != 3 || list.Slow != 1 || list.MaxLatencyMs < 10 {
+		t.Errorf("List stats = %+v; want 1 slow call returning 3 rows", list)
+	}
+
+	slow := logs.FilterMessage("slow repository call").All()
+	if len(slow) != 1 || slow[0].ContextMap()["method"] != "UserStore.List" {
+		t.Errorf("slow call logs = %+v; want one for UserStore.List", slow)
+	}
+	if n := logs.FilterMessage("repository call").Len(); n != 4 {
+		t.Errorf("got %d debug logs; want 4", n)
+	}
+}
+
+func TestWithInstrumentation_NilMetrics(t *testing.T) {
+	inner := &slowUserStore{}
+	if store := WithInstrumentation(inner, nil); store != inner {
+		t.Error("WithInstrumentation(store, nil) should return the store itself")
+	}
+}
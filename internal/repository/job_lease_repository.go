@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// JobLeaseStore holds leases that let one instance at a time run a job.
+// Expiry is judged by the database clock, so instances' clocks needn't
+// agree.
+type JobLeaseStore interface {
+	// Acquire takes the lease for holder, or extends it if holder already
+	// has it, unless another holder's lease hasn't expired. It reports
+	// whether holder has the lease.
+	Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, name, holder string) error
+}
+
+var (
+	_ JobLeaseStore = (*JobLeaseRepository)(nil)
+	_ JobLeaseStore = (*MySQLJobLeaseRepository)(nil)
+	_ JobLeaseStore = (*MemoryJobLeaseStore)(nil)
+)
+
+type JobLeaseRepository struct {
+	queries *generated.Queries
+}
+
+func NewJobLeaseRepository(q *generated.Queries) *JobLeaseRepository {
+	return &JobLeaseRepository{queries: q}
+}
+
+func (r *JobLeaseRepository) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	n, err := r.queries.AcquireJobLease(ctx, generated.AcquireJobLeaseParams{
+		Name:   name,
+		Holder: holder,
+		TtlMs:  ttl.Milliseconds(),
+	})
+	return n > 0, err
+}
+
+func (r *JobLeaseRepository) Release(ctx context.Context, name, holder string) error {
+	return r.queries.ReleaseJobLease(ctx, generated.ReleaseJobLeaseParams{
+		Name:   name,
+		Holder: holder,
+	})
+}
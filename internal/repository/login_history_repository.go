@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// LoginAttempt is one row of login history. UserID is nil when the email
+// did not match an account.
+type LoginAttempt struct {
+	UserID    *int64
+	Email     string
+	Succeeded bool
+	Reason    string
+	IPAddress string
+	UserAgent string
+	Country   string
+	City      string
+}
+
+type LoginHistoryStore interface {
+	Record(ctx context.Context, attempt LoginAttempt) error
+	ListByUser(ctx context.Context, userID int64, limit int32) ([]generated.ListLoginHistoryByUserRow, error)
+	RetentionStats(ctx context.Context) (int64, *time.Time, error)
+	PruneBefore(ctx context.Context, before time.Time) (int64, error)
+	FailedByDay(ctx context.Context, since time.Time) ([]generated.FailedLoginsByDayRow, error)
+}
+
+var (
+	_ LoginHistoryStore = (*LoginHistoryRepository)(nil)
+	_ LoginHistoryStore = (*MySQLLoginHistoryRepository)(nil)
+	_ LoginHistoryStore = (*MemoryLoginHistoryStore)(nil)
+)
+
+type LoginHistoryRepository struct {
+	queries *generated.Queries
+}
+
+func NewLoginHistoryRepository(q *generated.Queries) *LoginHistoryRepository {
+	return &LoginHistoryRepository{queries: q}
+}
+
+func (r *LoginHistoryRepository) Record(ctx context.Context, attempt LoginAttempt) error {
+	params := generated.RecordLoginParams{
+		Email:     attempt.Email,
+		Succeeded: attempt.Succeeded,
+		Reason:    attempt.Reason,
+		IpAddress: attempt.IPAddress,
+		UserAgent: attempt.UserAgent,
+		Country:   attempt.Country,
+		City:      attempt.City,
+	}
+	if attempt.UserID != nil {
+		params.UserID = pgtype.Int8{Int64: *attempt.UserID, Valid: true}
+	}
+	return pgError(r.queries.RecordLogin(ctx, params))
+}
+
+func (r *LoginHistoryRepository) ListByUser(ctx context.Context, userID int64, limit int32) ([]generated.ListLoginHistoryByUserRow, error) {
+	return r.queries.ListLoginHistoryByUser(ctx, generated.ListLoginHistoryByUserParams{
+		UserID: pgtype.Int8{Int64: userID, Valid: true},
+		Limit:  limit,
+	})
+}
+
+func (r *LoginHistoryRepository) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	row, err := r.queries.LoginHistoryStats(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !row.Oldest.Valid {
+		return row.Total, nil, nil
+	}
+	return row.Total, &row.Oldest.Time, nil
+}
+
+func (r *LoginHistoryRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.PruneLoginHistory(ctx, pgtype.Timestamp{Time: before, Valid: true})
+}
+
+func (r *LoginHistoryRepository) FailedByDay(ctx context.Context, since time.Time) ([]generated.FailedLoginsByDayRow, error) {
+	return r.queries.FailedLoginsByDay(ctx, pgtype.Timestamp{Time: since, Valid: true})
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// LoginLockoutStore counts failed logins per key (an email or IP) in fixed
+// windows and records until when a key is locked out.
+type LoginLockoutStore interface {
+	// RecordFailure counts a failure and returns the failures in the
+	// current window, which starts again once window has passed.
+	RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (int, error)
+	Lock(ctx context.Context, key string, until time.Time) error
+	// LockedUntil returns the end of key's lockout, or false when it isn't
+	// locked out at now.
+	LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, bool, error)
+	// Clear forgets key's failures and lockout, reporting whether there
+	// were any.
+	Clear(ctx context.Context, key string) (bool, error)
+	ListLocked(ctx context.Context, now time.Time) ([]generated.ListLoginLockoutsRow, error)
+	RetentionStats(ctx context.Context) (int64, *time.Time, error)
+	// PruneBefore deletes keys whose window started and whose lockout, if
+	// any, ended by before.
+	PruneBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+var (
+	_ LoginLockoutStore = (*LoginLockoutRepository)(nil)
+	_ LoginLockoutStore = (*MySQLLoginLockoutRepository)(nil)
+	_ LoginLockoutStore = (*MemoryLoginLockoutStore)(nil)
+)
+
+type LoginLockoutRepository struct {
+	queries *generated.Queries
+}
+
+func NewLoginLockoutRepository(q *generated.Queries) *LoginLockoutRepository {
+	return &LoginLockoutRepository{queries: q}
+}
+
+func (r *LoginLockoutRepository) RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (int, error) {
+	now = now.UTC()
+	failures, err := r.queries.RecordLoginFailure(ctx, generated.RecordLoginFailureParams{
+		LockKey:       key,
+		Now:           pgtype.Timestamp{Time: now, Valid: true},
+		ExpiredBefore: pgtype.Timestamp{Time: now.Add(-window), Valid: true},
+	})
+	return int(failures), pgError(err)
+}
+
+func (r *LoginLockoutRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	return pgError(r.queries.LockLogin(ctx, generated.LockLoginParams{
+		LockKey:     key,
+		LockedUntil: pgtype.Timestamp{Time: until.UTC(), Valid: true},
+	}))
+}
+
+// LockedUntil reads from the primary, so a lockout applies at once on
+// every instance.
+func (r *LoginLockoutRepository) LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, bool, error) {
+	row, err := r.queries.GetLoginLockout(WithPrimary(ctx), key)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if !row.LockedUntil.Valid || !row.LockedUntil.Time.After(now.UTC()) {
+		return time.Time{}, false, nil
+	}
+	return row.LockedUntil.Time, true, nil
+}
+
+func (r *LoginLockoutRepository) Clear(ctx context.Context, key string) (bool, error) {
+	n, err := r.queries.ClearLoginLockout(ctx, key)
+	return n > 0, pgError(err)
+}
+
+func (r *LoginLockoutRepository) ListLocked(ctx context.Context, now time.Time) ([]generated.ListLoginLockoutsRow, error) {
+	return r.queries.ListLoginLockouts(ctx, pgtype.Timestamp{Time: now.UTC(), Valid: true})
+}
+
+// RetentionStats reports the keys held and the earliest window start among
+// them.
+func (r *LoginLockoutRepository) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	row, err := r.queries.LoginLockoutStats(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !row.Oldest.Valid {
+		return row.Total, nil, nil
+	}
+	return row.Total, &row.Oldest.Time, nil
+}
+
+func (r *LoginLockoutRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	before = before.UTC()
+	return r.queries.DeleteExpiredLoginLockouts(ctx, generated.DeleteExpiredLoginLockoutsParams{
+		ExpiredBefore: pgtype.Timestamp{Time: before, Valid: true},
+		Now:           pgtype.Timestamp{Time: before, Valid: true},
+	})
+}
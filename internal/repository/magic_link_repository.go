@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// MagicLinkRedemptionStore records used magic links in the database, so a
+// link works once across instances and restarts. Entries only matter until
+// the link expires and are pruned after that.
+type MagicLinkRedemptionStore interface {
+	// Redeem records the link with tokenHash and reports whether it had not
+	// been used before.
+	Redeem(ctx context.Context, tokenHash string, expiresAt time.Time) (bool, error)
+	RetentionStats(ctx context.Context) (int64, *time.Time, error)
+	PruneBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+var (
+	_ MagicLinkRedemptionStore = (*MagicLinkRepository)(nil)
+	_ MagicLinkRedemptionStore = (*MySQLMagicLinkRepository)(nil)
+	_ MagicLinkRedemptionStore = (*MemoryMagicLinkStore)(nil)
+)
+
+type MagicLinkRepository struct {
+	queries *generated.Queries
+}
+
+func NewMagicLinkRepository(q *generated.Queries) *MagicLinkRepository {
+	return &MagicLinkRepository{queries: q}
+}
+
+func (r *MagicLinkRepository) Redeem(ctx context.Context, tokenHash string, expiresAt time.Time) (bool, error) {
+	n, err := r.queries.RedeemMagicLink(ctx, generated.RedeemMagicLinkParams{
+		TokenHash: tokenHash,
+		ExpiresAt: pgtype.Timestamp{Time: expiresAt.UTC(), Valid: true},
+	})
+	if err != nil {
+		return false, pgError(err)
+	}
+	return n == 1, nil
+}
+
+// RetentionStats reports the redemptions held and the earliest expiry among them.
+func (r *MagicLinkRepository) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	row, err := r.queries.MagicLinkRedemptionStats(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !row.Oldest.Valid {
+		return row.Total, nil, nil
+	}
+	return row.Total, &row.Oldest.Time, nil
+}
+
+// PruneBefore deletes redemptions that expired by before.
+func (r *MagicLinkRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.DeleteExpiredMagicLinkRedemptions(ctx, pgtype.Timestamp{Time: before.UTC(), Valid: true})
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// MemoryAPIKeyStore implements APIKeyStore in this process. Keys are
+// looked up together with their owner in users.
+type MemoryAPIKeyStore struct {
+	users *MemoryUserStore
+
+	mu     sync.RWMutex
+	keys   map[int64]generated.ApiKey
+	nextID int64
+}
+
+func NewMemoryAPIKeyStore(users *MemoryUserStore) *MemoryAPIKeyStore {
+	return &MemoryAPIKeyStore{users: users, keys: make(map[int64]generated.ApiKey)}
+}
+
+func (s *MemoryAPIKeyStore) Create(ctx context.Context, userID int64, name, prefix, keyHash, scopes string, expiresAt *time.Time) (generated.CreateAPIKeyRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, k := range s.keys {
+		if k.KeyHash == keyHash {
+			return generated.CreateAPIKeyRow{}, memoryUniqueViolation("api_keys_key_hash_key")
+		}
+	}
+	s.nextID++
+	key := generated.ApiKey{
+		ID:        s.nextID,
+		UserID:    userID,
+		Name:      name,
+		Prefix:    prefix,
+		KeyHash:   keyHash,
+		Scopes:    scopes,
+		CreatedAt: memoryTimestamp(time.Now()),
+	}
+	if expiresAt != nil {
+		key.ExpiresAt = memoryTimestamp(*expiresAt)
+	}
+	s.keys[key.ID] = key
+	return generated.CreateAPIKeyRow(memoryAPIKeyRow(key)), nil
+}
+
+func memoryAPIKeyRow(k generated.ApiKey) generated.ListAPIKeysByUserRow {
+	return generated.ListAPIKeysByUserRow{
+		ID:         k.ID,
+		UserID:     k.UserID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		CreatedAt:  k.CreatedAt,
+		ExpiresAt:  k.ExpiresAt,
+		LastUsedAt: k.LastUsedAt,
+		RevokedAt:  k.RevokedAt,
+	}
+}
+
+// name returns the key's name, or "" if there is no such key.
+func (s *MemoryAPIKeyStore) name(id int64) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keys[id].Name
+}
+
+func (s *MemoryAPIKeyStore) ListByUser(ctx context.Context, userID int64) ([]generated.ListAPIKeysByUserRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rows := []generated.ListAPIKeysByUserRow{}
+	for _, key := range s.keys {
+		if key.UserID == userID {
+			rows = append(rows, memoryAPIKeyRow(key))
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+	return rows, nil
+}
+
+func (s *MemoryAPIKeyStore) GetByHash(ctx context.Context, keyHash string) (generated.GetAPIKeyByHashRow, error) {
+	s.mu.RLock()
+	var key generated.ApiKey
+	found := false
+	for _, k := range s.keys {
+		if k.KeyHash == keyHash {
+			key, found = k, true
+			break
+		}
+	}
+	s.mu.RUnlock()
+	if !found {
+		return generated.GetAPIKeyByHashRow{}, pgx.ErrNoRows
+	}
+	owner, ok := s.users.user(key.UserID)
+	if !ok {
+		return generated.GetAPIKeyByHashRow{}, pgx.ErrNoRows
+	}
+	return generated.GetAPIKeyByHashRow{
+		ID:          key.ID,
+		UserID:      key.UserID,
+		Scopes:      key.Scopes,
+		ExpiresAt:   key.ExpiresAt,
+		RevokedAt:   key.RevokedAt,
+		Role:        owner.Role,
+		Active:      owner.Active,
+		AccountType: owner.AccountType,
+	}, nil
+}
+
+func (s *MemoryAPIKeyStore) Revoke(ctx context.Context, id, userID int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key, ok := s.keys[id]
+	if !ok || key.UserID != userID || key.RevokedAt.Valid {
+		return false, nil
+	}
+	key.RevokedAt = memoryTimestamp(time.Now())
+	s.keys[id] = key
+	return true, nil
+}
+
+func (s *MemoryAPIKeyStore) Touch(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if key, ok := s.keys[id]; ok {
+		key.LastUsedAt = memoryTimestamp(time.Now())
+		s.keys[id] = key
+	}
+	return nil
+}
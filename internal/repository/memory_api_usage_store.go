@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// MemoryAPIUsageStore implements APIUsageStore in this process, naming
+// users and keys from the given stores.
+type MemoryAPIUsageStore struct {
+	users *MemoryUserStore
+	keys  *MemoryAPIKeyStore
+
+	mu    sync.Mutex
+	usage map[apiUsageKey]int64
+}
+
+type apiUsageKey struct {
+	userID, apiKeyID int64
+	day              time.Time
+}
+
+func NewMemoryAPIUsageStore(users *MemoryUserStore, keys *MemoryAPIKeyStore) *MemoryAPIUsageStore {
+	return &MemoryAPIUsageStore{users: users, keys: keys, usage: make(map[apiUsageKey]int64)}
+}
+
+func (s *MemoryAPIUsageStore) Add(ctx context.Context, userID, apiKeyID int64, day time.Time, requests int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage[apiUsageKey{userID: userID, apiKeyID: apiKeyID, day: memoryDate(day).Time}] += requests
+	return nil
+}
+
+// between returns the counts for the days from from to to, inclusive.
+func (s *MemoryAPIUsageStore) between(from, to time.Time) map[apiUsageKey]int64 {
+	from, to = memoryDate(from).Time, memoryDate(to).Time
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	usage := make(map[apiUsageKey]int64)
+	for key, requests := range s.usage {
+		if !key.day.Before(from) && !key.day.After(to) {
+			usage[key] = requests
+		}
+	}
+	return usage
+}
+
+func (s *MemoryAPIUsageStore) ListByUser(ctx context.Context, userID int64, from, to time.Time) ([]generated.ListAPIUsageByUserRow, error) {
+	rows := []generated.ListAPIUsageByUserRow{}
+	for key, requests := range s.between(from, to) {
+		if key.userID == userID {
+			rows = append(rows, generated.ListAPIUsageByUserRow{
+				ApiKeyID: key.apiKeyID,
+				KeyName:  s.keys.name(key.apiKeyID),
+				Day:      memoryDate(key.day),
+				Requests: requests,
+			})
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if !rows[i].Day.Time.Equal(rows[j].Day.Time) {
+			return rows[i].Day.Time.Before(rows[j].Day.Time)
+		}
+		return rows[i].ApiKeyID < rows[j].ApiKeyID
+	})
+	return rows, nil
+}
+
+func (s *MemoryAPIUsageStore) Top(ctx context.Context, from, to time.Time, limit int32) ([]generated.TopAPIUsageRow, error) {
+	type client struct{ userID, apiKeyID int64 }
+	totals := make(map[client]int64)
+	for key, requests := range s.between(from, to) {
+		totals[client{userID: key.userID, apiKeyID: key.apiKeyID}] += requests
+	}
+	rows := []generated.TopAPIUsageRow{}
+	for c, requests := range totals {
+		user, ok := s.users.user(c.userID)
+		if !ok {
+			continue
+		}
+		rows = append(rows, generated.TopAPIUsageRow{
+			PublicID: user.PublicID,
+			Name:     user.Name,
+			ApiKeyID: c.apiKeyID,
+			KeyName:  s.keys.name(c.apiKeyID),
+			Requests: requests,
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Requests != rows[j].Requests {
+			return rows[i].Requests > rows[j].Requests
+		}
+		if rows[i].PublicID != rows[j].PublicID {
+			return string(rows[i].PublicID.Bytes[:]) < string(rows[j].PublicID.Bytes[:])
+		}
+		return rows[i].ApiKeyID < rows[j].ApiKeyID
+	})
+	if int(limit) < len(rows) {
+		rows = rows[:max(limit, 0)]
+	}
+	return rows, nil
+}
@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// MemoryBillingStore implements BillingStore in this process.
+type MemoryBillingStore struct {
+	mu      sync.RWMutex
+	billing map[int64]generated.OrganizationBilling
+}
+
+func NewMemoryBillingStore() *MemoryBillingStore {
+	return &MemoryBillingStore{billing: make(map[int64]generated.OrganizationBilling)}
+}
+
+func (s *MemoryBillingStore) Get(ctx context.Context, orgID int64) (generated.OrganizationBilling, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	row, ok := s.billing[orgID]
+	if !ok {
+		return generated.OrganizationBilling{}, pgx.ErrNoRows
+	}
+	return row, nil
+}
+
+func (s *MemoryBillingStore) GetByCustomer(ctx context.Context, provider, customerID string) (generated.OrganizationBilling, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, row := range s.billing {
+		if row.Provider == provider && row.CustomerID == customerID {
+			return row, nil
+		}
+	}
+	return generated.OrganizationBilling{}, pgx.ErrNoRows
+}
+
+func (s *MemoryBillingStore) Apply(ctx context.Context, update BillingUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	eventAt := memoryTimestamp(update.EventAt)
+	if row, ok := s.billing[update.OrgID]; ok && row.EventAt.Time.After(eventAt.Time) {
+		return nil
+	}
+	row := generated.OrganizationBilling{
+		OrgID:          update.OrgID,
+		Provider:       update.Provider,
+		CustomerID:     update.CustomerID,
+		SubscriptionID: update.SubscriptionID,
+		Plan:           update.Plan,
+		Status:         update.Status,
+		Seats:          update.Seats,
+		EventAt:        eventAt,
+		UpdatedAt:      memoryTimestamp(time.Now()),
+	}
+	if update.CurrentPeriodEnd != nil {
+		row.CurrentPeriodEnd = memoryTimestamp(*update.CurrentPeriodEnd)
+	}
+	s.billing[update.OrgID] = row
+	return nil
+}
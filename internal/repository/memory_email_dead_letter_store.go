@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// MemoryEmailDeadLetterStore implements EmailDeadLetterStore in this
+// process.
+type MemoryEmailDeadLetterStore struct {
+	mu      sync.RWMutex
+	letters map[int64]generated.EmailDeadLetter
+	nextID  int64
+}
+
+func NewMemoryEmailDeadLetterStore() *MemoryEmailDeadLetterStore {
+	return &MemoryEmailDeadLetterStore{letters: make(map[int64]generated.EmailDeadLetter)}
+}
+
+func (s *MemoryEmailDeadLetterStore) Create(ctx context.Context, recipients, subject, html, lastError string, attempts int32) (generated.EmailDeadLetter, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	now := memoryTimestamp(time.Now())
+	letter := generated.EmailDeadLetter{
+		ID:            s.nextID,
+		Recipients:    recipients,
+		Subject:       subject,
+		Html:          html,
+		Error:         lastError,
+		Attempts:      attempts,
+		CreatedAt:     now,
+		LastAttemptAt: now,
+	}
+	s.letters[letter.ID] = letter
+	return letter, nil
+}
+
+func (s *MemoryEmailDeadLetterStore) Get(ctx context.Context, id int64) (generated.EmailDeadLetter, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	letter, ok := s.letters[id]
+	if !ok {
+		return generated.EmailDeadLetter{}, pgx.ErrNoRows
+	}
+	return letter, nil
+}
+
+func (s *MemoryEmailDeadLetterStore) List(ctx context.Context, limit int32) ([]generated.EmailDeadLetter, error) {
+	s.mu.RLock()
+	letters := make([]generated.EmailDeadLetter, 0, len(s.letters))
+	for _, letter := range s.letters {
+		letters = append(letters, letter)
+	}
+	s.mu.RUnlock()
+	// IDs are handed out in creation order.
+	sort.Slice(letters, func(i, j int) bool { return letters[i].ID > letters[j].ID })
+	if int(limit) < len(letters) {
+		letters = letters[:max(limit, 0)]
+	}
+	return letters, nil
+}
+
+func (s *MemoryEmailDeadLetterStore) RecordAttempt(ctx context.Context, id int64, lastError string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	letter, ok := s.letters[id]
+	if !ok {
+		return nil
+	}
+	letter.Error = lastError
+	letter.Attempts++
+	letter.LastAttemptAt = memoryTimestamp(time.Now())
+	s.letters[id] = letter
+	return nil
+}
+
+func (s *MemoryEmailDeadLetterStore) Delete(ctx context.Context, id int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.letters[id]; !ok {
+		return false, nil
+	}
+	delete(s.letters, id)
+	return true, nil
+}
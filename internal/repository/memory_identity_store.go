@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// MemoryIdentityStore implements IdentityStore in this process. Like the
+// table, it allows one identity per subject and one per user and provider.
+type MemoryIdentityStore struct {
+	mu         sync.RWMutex
+	identities map[int64]generated.UserIdentity
+	nextID     int64
+}
+
+func NewMemoryIdentityStore() *MemoryIdentityStore {
+	return &MemoryIdentityStore{identities: make(map[int64]generated.UserIdentity)}
+}
+
+func (s *MemoryIdentityStore) Create(ctx context.Context, userID int64, provider, subject, email string) (generated.UserIdentity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, identity := range s.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return generated.UserIdentity{}, memoryUniqueViolation("user_identities_provider_subject_key")
+		}
+		if identity.UserID == userID && identity.Provider == provider {
+			return generated.UserIdentity{}, memoryUniqueViolation("user_identities_user_id_provider_key")
+		}
+	}
+	s.nextID++
+	identity := generated.UserIdentity{
+		ID:        s.nextID,
+		UserID:    userID,
+		Provider:  provider,
+		Subject:   subject,
+		Email:     email,
+		CreatedAt: memoryTimestamp(time.Now()),
+	}
+	s.identities[identity.ID] = identity
+	return identity, nil
+}
+
+func (s *MemoryIdentityStore) GetBySubject(ctx context.Context, provider, subject string) (generated.UserIdentity, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, identity := range s.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return generated.UserIdentity{}, pgx.ErrNoRows
+}
+
+func (s *MemoryIdentityStore) ListByUser(ctx context.Context, userID int64) ([]generated.UserIdentity, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	identities := []generated.UserIdentity{}
+	for _, identity := range s.identities {
+		if identity.UserID == userID {
+			identities = append(identities, identity)
+		}
+	}
+	sort.Slice(identities, func(i, j int) bool { return identities[i].Provider < identities[j].Provider })
+	return identities, nil
+}
+
+func (s *MemoryIdentityStore) Delete(ctx context.Context, userID int64, provider string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, identity := range s.identities {
+		if identity.UserID == userID && identity.Provider == provider {
+			delete(s.identities, id)
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryJobLeaseStore implements JobLeaseStore in this process. With no
+// other instance to share them, its leases only matter to elections within
+// the process.
+type MemoryJobLeaseStore struct {
+	mu     sync.Mutex
+	leases map[string]memoryLease
+}
+
+type memoryLease struct {
+	holder    string
+	expiresAt time.Time
+}
+
+func NewMemoryJobLeaseStore() *MemoryJobLeaseStore {
+	return &MemoryJobLeaseStore{leases: make(map[string]memoryLease)}
+}
+
+func (s *MemoryJobLeaseStore) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if lease, ok := s.leases[name]; ok && lease.holder != holder && !lease.expiresAt.Before(now) {
+		return false, nil
+	}
+	s.leases[name] = memoryLease{holder: holder, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+func (s *MemoryJobLeaseStore) Release(ctx context.Context, name, holder string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.leases[name].holder == holder {
+		delete(s.leases, name)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// MemoryLoginHistoryStore implements LoginHistoryStore in this process.
+type MemoryLoginHistoryStore struct {
+	mu      sync.RWMutex
+	history []generated.LoginHistory
+	nextID  int64
+}
+
+func NewMemoryLoginHistoryStore() *MemoryLoginHistoryStore {
+	return &MemoryLoginHistoryStore{}
+}
+
+func (s *MemoryLoginHistoryStore) Record(ctx context.Context, attempt LoginAttempt) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	row := generated.LoginHistory{
+		ID:        s.nextID,
+		Email:     attempt.Email,
+		Succeeded: attempt.Succeeded,
+		Reason:    attempt.Reason,
+		IpAddress: attempt.IPAddress,
+		UserAgent: attempt.UserAgent,
+		CreatedAt: memoryTimestamp(time.Now()),
+		Country:   attempt.Country,
+		City:      attempt.City,
+	}
+	if attempt.UserID != nil {
+		row.UserID = pgtype.Int8{Int64: *attempt.UserID, Valid: true}
+	}
+	s.history = append(s.history, row)
+	return nil
+}
+
+// countByUser returns how many attempts each user has, for organization
+// storage estimates.
+func (s *MemoryLoginHistoryStore) countByUser() map[int64]int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	counts := make(map[int64]int64)
+	for _, row := range s.history {
+		if row.UserID.Valid {
+			counts[row.UserID.Int64]++
+		}
+	}
+	return counts
+}
+
+// ListByUser lists newest first. Rows are appended in order, so that is
+// the reverse of the slice.
+func (s *MemoryLoginHistoryStore) ListByUser(ctx context.Context, userID int64, limit int32) ([]generated.ListLoginHistoryByUserRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rows := []generated.ListLoginHistoryByUserRow{}
+	for i := len(s.history) - 1; i >= 0 && len(rows) < int(limit); i-- {
+		row := s.history[i]
+		if !row.UserID.Valid || row.UserID.Int64 != userID {
+			continue
+		}
+		rows = append(rows, generated.ListLoginHistoryByUserRow{
+			ID:        row.ID,
+			Email:     row.Email,
+			Succeeded: row.Succeeded,
+			Reason:    row.Reason,
+			IpAddress: row.IpAddress,
+			UserAgent: row.UserAgent,
+			Country:   row.Country,
+			City:      row.City,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return rows, nil
+}
+
+func (s *MemoryLoginHistoryStore) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.history) == 0 {
+		return 0, nil, nil
+	}
+	oldest := s.history[0].CreatedAt.Time
+	return int64(len(s.history)), &oldest, nil
+}
+
+func (s *MemoryLoginHistoryStore) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	kept := s.history[:0]
+	for _, row := range s.history {
+		if !row.CreatedAt.Time.Before(before) {
+			kept = append(kept, row)
+		}
+	}
+	pruned := int64(len(s.history) - len(kept))
+	s.history = kept
+	return pruned, nil
+}
+
+func (s *MemoryLoginHistoryStore) FailedByDay(ctx context.Context, since time.Time) ([]generated.FailedLoginsByDayRow, error) {
+	s.mu.RLock()
+	counts := make(map[string]int64)
+	for _, row := range s.history {
+		if !row.Succeeded && !row.CreatedAt.Time.Before(since) {
+			counts[row.CreatedAt.Time.Format("2006-01-02")]++
+		}
+	}
+	s.mu.RUnlock()
+	rows := make([]generated.FailedLoginsByDayRow, 0, len(counts))
+	for day, failures := range counts {
+		rows = append(rows, generated.FailedLoginsByDayRow{Day: day, Failures: failures})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Day < rows[j].Day })
+	return rows, nil
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// MemoryLoginLockoutStore implements LoginLockoutStore in this process.
+type MemoryLoginLockoutStore struct {
+	mu       sync.Mutex
+	lockouts map[string]generated.LoginLockout
+}
+
+func NewMemoryLoginLockoutStore() *MemoryLoginLockoutStore {
+	return &MemoryLoginLockoutStore{lockouts: make(map[string]generated.LoginLockout)}
+}
+
+func (s *MemoryLoginLockoutStore) RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (int, error) {
+	expiredBefore := now.Add(-window)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	row, ok := s.lockouts[key]
+	if !ok {
+		row = generated.LoginLockout{LockKey: key}
+	}
+	if !ok || !row.WindowStart.Time.After(expiredBefore) {
+		row.Failures = 0
+		row.WindowStart = pgtype.Timestamp{Time: now, Valid: true}
+	}
+	row.Failures++
+	s.lockouts[key] = row
+	return int(row.Failures), nil
+}
+
+func (s *MemoryLoginLockoutStore) Lock(ctx context.Context, key string, until time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if row, ok := s.lockouts[key]; ok {
+		row.LockedUntil = pgtype.Timestamp{Time: until, Valid: true}
+		s.lockouts[key] = row
+	}
+	return nil
+}
+
+func (s *MemoryLoginLockoutStore) LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	row, ok := s.lockouts[key]
+	if !ok || !row.LockedUntil.Valid || !row.LockedUntil.Time.After(now) {
+		return time.Time{}, false, nil
+	}
+	return row.LockedUntil.Time, true, nil
+}
+
+func (s *MemoryLoginLockoutStore) Clear(ctx context.Context, key string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.lockouts[key]
+	delete(s.lockouts, key)
+	return ok, nil
+}
+
+func (s *MemoryLoginLockoutStore) ListLocked(ctx context.Context, now time.Time) ([]generated.ListLoginLockoutsRow, error) {
+	s.mu.Lock()
+	rows := []generated.ListLoginLockoutsRow{}
+	for _, row := range s.lockouts {
+		if row.LockedUntil.Valid && row.LockedUntil.Time.After(now) {
+			rows = append(rows, generated.ListLoginLockoutsRow{LockKey: row.LockKey, Failures: row.Failures, LockedUntil: row.LockedUntil})
+		}
+	}
+	s.mu.Unlock()
+	sort.Slice(rows, func(i, j int) bool { return rows[i].LockedUntil.Time.After(rows[j].LockedUntil.Time) })
+	return rows, nil
+}
+
+func (s *MemoryLoginLockoutStore) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var oldest *time.Time
+	for _, row := range s.lockouts {
+		if oldest == nil || row.WindowStart.Time.Before(*oldest) {
+			t := row.WindowStart.Time
+			oldest = &t
+		}
+	}
+	return int64(len(s.lockouts)), oldest, nil
+}
+
+func (s *MemoryLoginLockoutStore) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var pruned int64
+	for key, row := range s.lockouts {
+		if !row.WindowStart.Time.After(before) && (!row.LockedUntil.Valid || !row.LockedUntil.Time.After(before)) {
+			delete(s.lockouts, key)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
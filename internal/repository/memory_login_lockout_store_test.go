@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryLoginLockoutStorePruneKeepsLockouts(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryLoginLockoutStore()
+
+	for _, key := range []string{"stale", "locked", "recent"} {
+		at := now.Add(-time.Hour)
+		if key == "recent" {
+			at = now
+		}
+		if _, err := s.RecordFailure(ctx, key, at, 15*time.Minute); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.Lock(ctx, "locked", now.Add(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	pruned, err := s.PruneBefore(ctx, now.Add(-15*time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 1 {
+		t.Errorf("pruned %d keys, want 1", pruned)
+	}
+	if total, _, _ := s.RetentionStats(ctx); total != 2 {
+		t.Errorf("%d keys left, want 2", total)
+	}
+	if _, locked, _ := s.LockedUntil(ctx, "locked", now); !locked {
+		t.Error("an active lockout was pruned")
+	}
+}
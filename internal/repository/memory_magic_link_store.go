@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryMagicLinkStore implements MagicLinkRedemptionStore in this process.
+type MemoryMagicLinkStore struct {
+	mu       sync.Mutex
+	redeemed map[string]time.Time
+}
+
+func NewMemoryMagicLinkStore() *MemoryMagicLinkStore {
+	return &MemoryMagicLinkStore{redeemed: make(map[string]time.Time)}
+}
+
+func (s *MemoryMagicLinkStore) Redeem(ctx context.Context, tokenHash string, expiresAt time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.redeemed[tokenHash]; ok {
+		return false, nil
+	}
+	s.redeemed[tokenHash] = expiresAt
+	return true, nil
+}
+
+func (s *MemoryMagicLinkStore) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var oldest *time.Time
+	for _, at := range s.redeemed {
+		if oldest == nil || at.Before(*oldest) {
+			t := at
+			oldest = &t
+		}
+	}
+	return int64(len(s.redeemed)), oldest, nil
+}
+
+func (s *MemoryMagicLinkStore) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var pruned int64
+	for hash, at := range s.redeemed {
+		if !at.After(before) {
+			delete(s.redeemed, hash)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
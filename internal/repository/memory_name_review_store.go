@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// MemoryNameReviewStore implements NameReviewStore in this process.
+type MemoryNameReviewStore struct {
+	mu      sync.RWMutex
+	reviews map[int64]generated.NameReview
+	nextID  int64
+}
+
+func NewMemoryNameReviewStore() *MemoryNameReviewStore {
+	return &MemoryNameReviewStore{reviews: make(map[int64]generated.NameReview)}
+}
+
+func (s *MemoryNameReviewStore) Create(ctx context.Context, userID int64, name, matched string) (generated.NameReview, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	review := generated.NameReview{
+		ID:        s.nextID,
+		UserID:    userID,
+		Name:      name,
+		Matched:   matched,
+		Status:    "pending",
+		CreatedAt: memoryTimestamp(time.Now()),
+	}
+	s.reviews[review.ID] = review
+	return review, nil
+}
+
+func (s *MemoryNameReviewStore) Get(ctx context.Context, id int64) (generated.NameReview, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	review, ok := s.reviews[id]
+	if !ok {
+		return generated.NameReview{}, pgx.ErrNoRows
+	}
+	return review, nil
+}
+
+func (s *MemoryNameReviewStore) List(ctx context.Context, status string, limit int32) ([]generated.NameReview, error) {
+	s.mu.RLock()
+	reviews := []generated.NameReview{}
+	for _, review := range s.reviews {
+		if review.Status == status {
+			reviews = append(reviews, review)
+		}
+	}
+	s.mu.RUnlock()
+	// IDs are handed out in creation order.
+	sort.Slice(reviews, func(i, j int) bool { return reviews[i].ID < reviews[j].ID })
+	if int(limit) < len(reviews) {
+		reviews = reviews[:max(limit, 0)]
+	}
+	return reviews, nil
+}
+
+func (s *MemoryNameReviewStore) Resolve(ctx context.Context, id int64, status string, reviewerID int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	review, ok := s.reviews[id]
+	if !ok || review.Status != "pending" {
+		return false, nil
+	}
+	review.Status = status
+	review.ReviewedBy = pgtype.Int8{Int64: reviewerID, Valid: true}
+	review.ReviewedAt = memoryTimestamp(time.Now())
+	s.reviews[id] = review
+	return true, nil
+}
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+type notificationPreferenceKey struct {
+	userID    int64
+	eventType string
+	channel   string
+}
+
+// MemoryNotificationStore implements NotificationStore in this process.
+type MemoryNotificationStore struct {
+	mu            sync.RWMutex
+	rules         map[int64]generated.NotificationRule
+	preferences   map[notificationPreferenceKey]bool
+	notifications map[int64]generated.Notification
+	nextRuleID    int64
+	nextID        int64
+}
+
+func NewMemoryNotificationStore() *MemoryNotificationStore {
+	return &MemoryNotificationStore{
+		rules:         make(map[int64]generated.NotificationRule),
+		preferences:   make(map[notificationPreferenceKey]bool),
+		notifications: make(map[int64]generated.Notification),
+	}
+}
+
+func (s *MemoryNotificationStore) CreateRule(ctx context.Context, rule generated.NotificationRule) (generated.NotificationRule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextRuleID++
+	rule.ID = s.nextRuleID
+	rule.CreatedAt = memoryTimestamp(time.Now())
+	rule.UpdatedAt = rule.CreatedAt
+	s.rules[rule.ID] = rule
+	return rule, nil
+}
+
+func (s *MemoryNotificationStore) GetRule(ctx context.Context, id int64) (generated.NotificationRule, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rule, ok := s.rules[id]
+	if !ok {
+		return generated.NotificationRule{}, pgx.ErrNoRows
+	}
+	return rule, nil
+}
+
+func (s *MemoryNotificationStore) ListRules(ctx context.Context) ([]generated.NotificationRule, error) {
+	return s.listRules(func(generated.NotificationRule) bool { return true }), nil
+}
+
+func (s *MemoryNotificationStore) ListRulesForEvent(ctx context.Context, eventType string) ([]generated.NotificationRule, error) {
+	return s.listRules(func(rule generated.NotificationRule) bool {
+		return rule.EventType == eventType && rule.Enabled
+	}), nil
+}
+
+func (s *MemoryNotificationStore) listRules(keep func(generated.NotificationRule) bool) []generated.NotificationRule {
+	s.mu.RLock()
+	rules := []generated.NotificationRule{}
+	for _, rule := range s.rules {
+		if keep(rule) {
+			rules = append(rules, rule)
+		}
+	}
+	s.mu.RUnlock()
+	sort.Slice(rules, func(i, j int) bool {
+		if rules[i].EventType != rules[j].EventType {
+			return rules[i].EventType < rules[j].EventType
+		}
+		return rules[i].ID < rules[j].ID
+	})
+	return rules
+}
+
+func (s *MemoryNotificationStore) UpdateRule(ctx context.Context, rule generated.NotificationRule) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	old, ok := s.rules[rule.ID]
+	if !ok {
+		return false, nil
+	}
+	rule.CreatedAt = old.CreatedAt
+	rule.UpdatedAt = memoryTimestamp(time.Now())
+	s.rules[rule.ID] = rule
+	return true, nil
+}
+
+func (s *MemoryNotificationStore) DeleteRule(ctx context.Context, id int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.rules[id]; !ok {
+		return false, nil
+	}
+	delete(s.rules, id)
+	return true, nil
+}
+
+func (s *MemoryNotificationStore) ListPreferences(ctx context.Context, userID int64) ([]generated.NotificationPreference, error) {
+	s.mu.RLock()
+	prefs := []generated.NotificationPreference{}
+	for key, enabled := range s.preferences {
+		if key.userID == userID {
+			prefs = append(prefs, generated.NotificationPreference{
+				UserID:    key.userID,
+				EventType: key.eventType,
+				Channel:   key.channel,
+				Enabled:   enabled,
+			})
+		}
+	}
+	s.mu.RUnlock()
+	sort.Slice(prefs, func(i, j int) bool {
+		if prefs[i].EventType != prefs[j].EventType {
+			return prefs[i].EventType < prefs[j].EventType
+		}
+		return prefs[i].Channel < prefs[j].Channel
+	})
+	return prefs, nil
+}
+
+func (s *MemoryNotificationStore) SetPreference(ctx context.Context, pref generated.NotificationPreference) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.preferences[notificationPreferenceKey{pref.UserID, pref.EventType, pref.Channel}] = pref.Enabled
+	return nil
+}
+
+func (s *MemoryNotificationStore) Create(ctx context.Context, userID int64, eventType, title, details string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	s.notifications[s.nextID] = generated.Notification{
+		ID:        s.nextID,
+		UserID:    userID,
+		EventType: eventType,
+		Title:     title,
+		Details:   details,
+		CreatedAt: memoryTimestamp(time.Now()),
+	}
+	return nil
+}
+
+func (s *MemoryNotificationStore) List(ctx context.Context, userID int64, limit int32) ([]generated.Notification, error) {
+	s.mu.RLock()
+	notifications := []generated.Notification{}
+	for _, n := range s.notifications {
+		if n.UserID == userID {
+			notifications = append(notifications, n)
+		}
+	}
+	s.mu.RUnlock()
+	// IDs are handed out in creation order.
+	sort.Slice(notifications, func(i, j int) bool { return notifications[i].ID > notifications[j].ID })
+	if int(limit) < len(notifications) {
+		notifications = notifications[:max(limit, 0)]
+	}
+	return notifications, nil
+}
+
+func (s *MemoryNotificationStore) MarkRead(ctx context.Context, id, userID int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n, ok := s.notifications[id]
+	if !ok || n.UserID != userID || n.ReadAt.Valid {
+		return false, nil
+	}
+	n.ReadAt = memoryTimestamp(time.Now())
+	s.notifications[id] = n
+	return true, nil
+}
+
+func (s *MemoryNotificationStore) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var oldest *time.Time
+	for _, n := range s.notifications {
+		if oldest == nil || n.CreatedAt.Time.Before(*oldest) {
+			t := n.CreatedAt.Time
+			oldest = &t
+		}
+	}
+	return int64(len(s.notifications)), oldest, nil
+}
+
+func (s *MemoryNotificationStore) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var pruned int64
+	for id, n := range s.notifications {
+		if n.CreatedAt.Time.Before(before) {
+			delete(s.notifications, id)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
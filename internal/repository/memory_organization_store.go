@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// Bytes a user and a login history row count for in an organization's
+// storage, standing in for the row sizes Postgres and MySQL measure.
+const (
+	memoryUserBytes         = 256
+	memoryLoginHistoryBytes = 128
+)
+
+// MemoryOrganizationStore implements OrganizationStore in this process.
+// Seats and storage are counted from users and logins.
+type MemoryOrganizationStore struct {
+	users  *MemoryUserStore
+	logins *MemoryLoginHistoryStore
+
+	mu      sync.RWMutex
+	orgs    map[int64]generated.Organization
+	members map[int64]int64
+	usage   map[int64]map[time.Time]generated.OrganizationUsage
+	nextID  int64
+}
+
+// NewMemoryOrganizationStore also hooks the store up to users, so their
+// searches can filter by organization.
+func NewMemoryOrganizationStore(users *MemoryUserStore, logins *MemoryLoginHistoryStore) *MemoryOrganizationStore {
+	s := &MemoryOrganizationStore{
+		users:   users,
+		logins:  logins,
+		orgs:    make(map[int64]generated.Organization),
+		members: make(map[int64]int64),
+		usage:   make(map[int64]map[time.Time]generated.OrganizationUsage),
+	}
+	users.setOrgMembers(s.orgMembers)
+	return s
+}
+
+func (s *MemoryOrganizationStore) Create(ctx context.Context, name string) (generated.Organization, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	org := generated.Organization{ID: s.nextID, Name: name, CreatedAt: memoryTimestamp(time.Now())}
+	s.orgs[org.ID] = org
+	return org, nil
+}
+
+func (s *MemoryOrganizationStore) Get(ctx context.Context, id int64) (generated.Organization, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	org, ok := s.orgs[id]
+	if !ok {
+		return generated.Organization{}, pgx.ErrNoRows
+	}
+	return org, nil
+}
+
+func (s *MemoryOrganizationStore) List(ctx context.Context) ([]generated.Organization, error) {
+	s.mu.RLock()
+	orgs := make([]generated.Organization, 0, len(s.orgs))
+	for _, org := range s.orgs {
+		orgs = append(orgs, org)
+	}
+	s.mu.RUnlock()
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i].ID < orgs[j].ID })
+	return orgs, nil
+}
+
+// SetMember fails with ErrForeignKeyViolation for an organization that
+// doesn't exist, as the table's reference does.
+func (s *MemoryOrganizationStore) SetMember(ctx context.Context, userID, orgID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.orgs[orgID]; !ok {
+		return &ConstraintError{Kind: ErrForeignKeyViolation, Constraint: "organization_members_org_id_fkey", Err: pgx.ErrNoRows}
+	}
+	s.members[userID] = orgID
+	return nil
+}
+
+func (s *MemoryOrganizationStore) RemoveMember(ctx context.Context, userID int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.members[userID]
+	delete(s.members, userID)
+	return ok, nil
+}
+
+func (s *MemoryOrganizationStore) MemberOf(ctx context.Context, userID int64) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	orgID, ok := s.members[userID]
+	if !ok {
+		return 0, pgx.ErrNoRows
+	}
+	return orgID, nil
+}
+
+// orgMembers returns the set of users in the organization.
+func (s *MemoryOrganizationStore) orgMembers(orgID int64) map[int64]bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	members := make(map[int64]bool)
+	for userID, memberOf := range s.members {
+		if memberOf == orgID {
+			members[userID] = true
+		}
+	}
+	return members
+}
+
+func (s *MemoryOrganizationStore) CountSeats(ctx context.Context, orgID, exceptUserID int64) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var seats int64
+	for userID, memberOf := range s.members {
+		if memberOf != orgID || userID == exceptUserID {
+			continue
+		}
+		if user, ok := s.users.user(userID); ok && user.Active && user.AccountType != "service" {
+			seats++
+		}
+	}
+	return seats, nil
+}
+
+// dayUsage returns the organization's usage row for day, creating it.
+// s.mu must be held.
+func (s *MemoryOrganizationStore) dayUsage(orgID int64, day time.Time) generated.OrganizationUsage {
+	date := memoryDate(day)
+	if s.usage[orgID] == nil {
+		s.usage[orgID] = make(map[time.Time]generated.OrganizationUsage)
+	}
+	row, ok := s.usage[orgID][date.Time]
+	if !ok {
+		row = generated.OrganizationUsage{OrgID: orgID, Day: date}
+	}
+	return row
+}
+
+func (s *MemoryOrganizationStore) AggregateUsage(ctx context.Context, day time.Time) error {
+	logins := s.logins.countByUser()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	seats := make(map[int64]int64)
+	storage := make(map[int64]int64)
+	for userID, orgID := range s.members {
+		storage[orgID] += logins[userID] * memoryLoginHistoryBytes
+		user, ok := s.users.user(userID)
+		if !ok {
+			continue
+		}
+		if user.Active && user.AccountType != "service" {
+			seats[orgID]++
+		}
+		storage[orgID] += memoryUserBytes
+	}
+	for orgID := range s.orgs {
+		row := s.dayUsage(orgID, day)
+		row.Seats = seats[orgID]
+		row.StorageBytes = storage[orgID]
+		s.usage[orgID][row.Day.Time] = row
+	}
+	return nil
+}
+
+func (s *MemoryOrganizationStore) AddAPICalls(ctx context.Context, userID int64, day time.Time, calls int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	orgID, ok := s.members[userID]
+	if !ok {
+		return nil
+	}
+	row := s.dayUsage(orgID, day)
+	row.ApiCalls += calls
+	s.usage[orgID][row.Day.Time] = row
+	return nil
+}
+
+func (s *MemoryOrganizationStore) Usage(ctx context.Context, orgID int64, from, to time.Time) ([]generated.OrganizationUsage, error) {
+	from, to = memoryDate(from).Time, memoryDate(to).Time
+	s.mu.RLock()
+	rows := []generated.OrganizationUsage{}
+	for day, row := range s.usage[orgID] {
+		if !day.Before(from) && !day.After(to) {
+			rows = append(rows, row)
+		}
+	}
+	s.mu.RUnlock()
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Day.Time.Before(rows[j].Day.Time) })
+	return rows, nil
+}
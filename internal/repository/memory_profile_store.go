@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// MemoryProfileStore implements ProfileStore in this process.
+type MemoryProfileStore struct {
+	mu       sync.RWMutex
+	profiles map[int64]generated.UserProfile
+}
+
+func NewMemoryProfileStore() *MemoryProfileStore {
+	return &MemoryProfileStore{profiles: make(map[int64]generated.UserProfile)}
+}
+
+func (s *MemoryProfileStore) Get(ctx context.Context, userID int64) (generated.UserProfile, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	profile, ok := s.profiles[userID]
+	if !ok {
+		return generated.UserProfile{}, pgx.ErrNoRows
+	}
+	return profile, nil
+}
+
+func (s *MemoryProfileStore) Save(ctx context.Context, profile generated.UserProfile) (generated.UserProfile, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	profile.UpdatedAt = memoryTimestamp(time.Now())
+	profile.AvatarKey = s.profiles[profile.UserID].AvatarKey
+	s.profiles[profile.UserID] = profile
+	return profile, nil
+}
+
+func (s *MemoryProfileStore) SetAvatarKey(ctx context.Context, userID int64, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	profile, ok := s.profiles[userID]
+	if !ok {
+		profile.UserID = userID
+	}
+	profile.AvatarKey = key
+	profile.UpdatedAt = memoryTimestamp(time.Now())
+	s.profiles[userID] = profile
+	return nil
+}
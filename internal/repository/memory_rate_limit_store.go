@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryRateLimitStore implements RateLimitCounterStore in this process,
+// with the same fixed windows as the database counters.
+type MemoryRateLimitStore struct {
+	mu       sync.Mutex
+	counters map[string]memoryCounter
+}
+
+type memoryCounter struct {
+	hits        int
+	windowStart time.Time
+}
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{counters: make(map[string]memoryCounter)}
+}
+
+func (s *MemoryRateLimitStore) Take(ctx context.Context, client string, now time.Time, window time.Duration, max int) (int, time.Time, bool, error) {
+	expiredBefore := now.Add(-window)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counter, ok := s.counters[client]
+	if !ok || !counter.windowStart.After(expiredBefore) {
+		counter = memoryCounter{windowStart: now}
+	}
+	if counter.hits <= max {
+		counter.hits++
+	}
+	s.counters[client] = counter
+	return takenHits(counter.hits, max), counter.windowStart, counter.hits <= max, nil
+}
+
+func (s *MemoryRateLimitStore) Count(ctx context.Context, client string, now time.Time, window time.Duration) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	counter, ok := s.counters[client]
+	if !ok || !counter.windowStart.After(now.Add(-window)) {
+		return 0, now, nil
+	}
+	return counter.hits, counter.windowStart, nil
+}
+
+func (s *MemoryRateLimitStore) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var oldest *time.Time
+	for _, counter := range s.counters {
+		if oldest == nil || counter.windowStart.Before(*oldest) {
+			t := counter.windowStart
+			oldest = &t
+		}
+	}
+	return int64(len(s.counters)), oldest, nil
+}
+
+func (s *MemoryRateLimitStore) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var pruned int64
+	for client, counter := range s.counters {
+		if !counter.windowStart.After(before) {
+			delete(s.counters, client)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
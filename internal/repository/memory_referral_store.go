@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// MemoryReferralStore implements ReferralStore in this process.
+type MemoryReferralStore struct {
+	mu     sync.RWMutex
+	codes  map[int64]string
+	owners map[string]int64
+	// referrals maps each referred user to their referral.
+	referrals map[int64]generated.Referral
+	nextID    int64
+}
+
+func NewMemoryReferralStore() *MemoryReferralStore {
+	return &MemoryReferralStore{
+		codes:     make(map[int64]string),
+		owners:    make(map[string]int64),
+		referrals: make(map[int64]generated.Referral),
+	}
+}
+
+func (s *MemoryReferralStore) Code(ctx context.Context, userID int64) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	code, ok := s.codes[userID]
+	if !ok {
+		return "", pgx.ErrNoRows
+	}
+	return code, nil
+}
+
+func (s *MemoryReferralStore) CreateCode(ctx context.Context, userID int64, code string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, hasCode := s.codes[userID]
+	_, taken := s.owners[code]
+	if hasCode || taken {
+		return false, nil
+	}
+	s.codes[userID] = code
+	s.owners[code] = userID
+	return true, nil
+}
+
+func (s *MemoryReferralStore) Owner(ctx context.Context, code string) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	userID, ok := s.owners[code]
+	if !ok {
+		return 0, pgx.ErrNoRows
+	}
+	return userID, nil
+}
+
+func (s *MemoryReferralStore) Record(ctx context.Context, referrerID, referredID int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.referrals[referredID]; ok {
+		return false, nil
+	}
+	s.nextID++
+	s.referrals[referredID] = generated.Referral{
+		ID:         s.nextID,
+		ReferrerID: referrerID,
+		ReferredID: referredID,
+		CreatedAt:  memoryTimestamp(time.Now()),
+	}
+	return true, nil
+}
+
+func (s *MemoryReferralStore) Stats(ctx context.Context, referrerID int64, since time.Time) (generated.ReferralStatsRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var stats generated.ReferralStatsRow
+	for _, referral := range s.referrals {
+		if referral.ReferrerID != referrerID {
+			continue
+		}
+		stats.Total++
+		if !referral.CreatedAt.Time.Before(since) {
+			stats.Recent++
+		}
+	}
+	return stats, nil
+}
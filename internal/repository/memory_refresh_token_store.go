@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// MemoryRefreshTokenStore implements RefreshTokenStore in this process.
+type MemoryRefreshTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]generated.RefreshToken
+	nextID int64
+}
+
+func NewMemoryRefreshTokenStore() *MemoryRefreshTokenStore {
+	return &MemoryRefreshTokenStore{tokens: make(map[string]generated.RefreshToken)}
+}
+
+func (s *MemoryRefreshTokenStore) Create(ctx context.Context, userID int64, tokenHash, family string, createdAt, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tokens[tokenHash]; ok {
+		return memoryUniqueViolation("refresh_tokens_token_hash_key")
+	}
+	s.nextID++
+	s.tokens[tokenHash] = generated.RefreshToken{
+		ID:        s.nextID,
+		UserID:    userID,
+		TokenHash: tokenHash,
+		Family:    family,
+		CreatedAt: memoryTimestamp(createdAt),
+		ExpiresAt: memoryTimestamp(expiresAt),
+	}
+	return nil
+}
+
+func (s *MemoryRefreshTokenStore) GetByHash(ctx context.Context, tokenHash string) (generated.GetRefreshTokenByHashRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token, ok := s.tokens[tokenHash]
+	if !ok {
+		return generated.GetRefreshTokenByHashRow{}, pgx.ErrNoRows
+	}
+	return generated.GetRefreshTokenByHashRow{
+		ID:        token.ID,
+		UserID:    token.UserID,
+		Family:    token.Family,
+		CreatedAt: token.CreatedAt,
+		ExpiresAt: token.ExpiresAt,
+		RevokedAt: token.RevokedAt,
+	}, nil
+}
+
+func (s *MemoryRefreshTokenStore) Revoke(ctx context.Context, id int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for hash, token := range s.tokens {
+		if token.ID == id {
+			if token.RevokedAt.Valid {
+				return false, nil
+			}
+			token.RevokedAt = memoryTimestamp(time.Now())
+			s.tokens[hash] = token
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *MemoryRefreshTokenStore) RevokeFamily(ctx context.Context, family string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := memoryTimestamp(time.Now())
+	for hash, token := range s.tokens {
+		if token.Family == family && !token.RevokedAt.Valid {
+			token.RevokedAt = now
+			s.tokens[hash] = token
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryRevokedTokenStore keeps revoked token IDs in this process only. It
+// suits a single instance: other instances don't see its logouts, and a
+// restart forgets them.
+type MemoryRevokedTokenStore struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+}
+
+func NewMemoryRevokedTokenStore() *MemoryRevokedTokenStore {
+	return &MemoryRevokedTokenStore{revoked: make(map[string]time.Time)}
+}
+
+func (s *MemoryRevokedTokenStore) Revoke(ctx context.Context, jti string, userID int64, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.revoked[jti]; !ok {
+		s.revoked[jti] = expiresAt
+	}
+	return nil
+}
+
+func (s *MemoryRevokedTokenStore) List(ctx context.Context, now time.Time) (map[string]time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	revoked := make(map[string]time.Time, len(s.revoked))
+	for jti, expiresAt := range s.revoked {
+		if expiresAt.After(now) {
+			revoked[jti] = expiresAt
+		}
+	}
+	return revoked, nil
+}
+
+func (s *MemoryRevokedTokenStore) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var oldest *time.Time
+	for _, expiresAt := range s.revoked {
+		if oldest == nil || expiresAt.Before(*oldest) {
+			t := expiresAt
+			oldest = &t
+		}
+	}
+	return int64(len(s.revoked)), oldest, nil
+}
+
+func (s *MemoryRevokedTokenStore) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var pruned int64
+	for jti, expiresAt := range s.revoked {
+		if expiresAt.Before(before) {
+			delete(s.revoked, jti)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
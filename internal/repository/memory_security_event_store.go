@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// MemorySecurityEventStore implements SecurityEventStore in this process,
+// with the same chain rule: no two events may follow the same one. It
+// looks up the public IDs of actors and targets in users.
+type MemorySecurityEventStore struct {
+	users *MemoryUserStore
+
+	mu     sync.RWMutex
+	events []generated.SecurityEvent
+	// followed holds the prev_hash of every event.
+	followed map[string]bool
+}
+
+func NewMemorySecurityEventStore(users *MemoryUserStore) *MemorySecurityEventStore {
+	return &MemorySecurityEventStore{users: users, followed: make(map[string]bool)}
+}
+
+func (s *MemorySecurityEventStore) Append(ctx context.Context, e generated.SecurityEvent) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.followed[e.PrevHash] {
+		return 0, memoryUniqueViolation("security_events_prev_hash_key")
+	}
+	e.ID = int64(len(s.events)) + 1
+	s.events = append(s.events, e)
+	s.followed[e.PrevHash] = true
+	return e.ID, nil
+}
+
+func (s *MemorySecurityEventStore) LastHash(ctx context.Context) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if len(s.events) == 0 {
+		return "", nil
+	}
+	return s.events[len(s.events)-1].Hash, nil
+}
+
+// ListAfter and List rely on events never being removed: an event's ID is
+// its index plus one.
+func (s *MemorySecurityEventStore) ListAfter(ctx context.Context, afterID int64, limit int32) ([]generated.SecurityEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	events := []generated.SecurityEvent{}
+	for i := max(afterID, 0); i < int64(len(s.events)) && len(events) < int(limit); i++ {
+		events = append(events, s.events[i])
+	}
+	return events, nil
+}
+
+func (s *MemorySecurityEventStore) List(ctx context.Context, beforeID int64, limit int32) ([]generated.ListSecurityEventsRow, error) {
+	s.mu.RLock()
+	var events []generated.SecurityEvent
+	for i := min(beforeID-1, int64(len(s.events))) - 1; i >= 0 && len(events) < int(limit); i-- {
+		events = append(events, s.events[i])
+	}
+	s.mu.RUnlock()
+
+	rows := make([]generated.ListSecurityEventsRow, 0, len(events))
+	for _, e := range events {
+		rows = append(rows, generated.ListSecurityEventsRow{
+			ID:             e.ID,
+			EventType:      e.EventType,
+			ActorPublicID:  s.publicID(e.ActorID),
+			TargetPublicID: s.publicID(e.TargetID),
+			Details:        e.Details,
+			IpAddress:      e.IpAddress,
+			CreatedAt:      e.CreatedAt,
+			Hash:           e.Hash,
+		})
+	}
+	return rows, nil
+}
+
+func (s *MemorySecurityEventStore) publicID(id pgtype.Int8) pgtype.UUID {
+	if !id.Valid {
+		return pgtype.UUID{}
+	}
+	return s.users.publicID(id.Int64)
+}
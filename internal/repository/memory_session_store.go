@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// MemorySessionStore implements SessionStore in this process.
+type MemorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]generated.Session
+}
+
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{sessions: make(map[string]generated.Session)}
+}
+
+func (s *MemorySessionStore) Create(ctx context.Context, id string, userID int64, ipAddress, userAgent string, createdAt, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.sessions[id]; ok {
+		return memoryUniqueViolation("sessions_pkey")
+	}
+	s.sessions[id] = generated.Session{
+		ID:        id,
+		UserID:    userID,
+		IpAddress: ipAddress,
+		UserAgent: userAgent,
+		CreatedAt: memoryTimestamp(createdAt),
+		ExpiresAt: memoryTimestamp(expiresAt),
+	}
+	return nil
+}
+
+func (s *MemorySessionStore) ListActive(ctx context.Context, userID int64, now time.Time) ([]generated.ListActiveSessionsRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var sessions []generated.ListActiveSessionsRow
+	for _, session := range s.sessions {
+		if session.UserID != userID || session.RevokedAt.Valid || !session.ExpiresAt.Time.After(now) {
+			continue
+		}
+		sessions = append(sessions, generated.ListActiveSessionsRow{
+			ID:        session.ID,
+			IpAddress: session.IpAddress,
+			UserAgent: session.UserAgent,
+			CreatedAt: session.CreatedAt,
+			ExpiresAt: session.ExpiresAt,
+		})
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].CreatedAt.Time.Equal(sessions[j].CreatedAt.Time) {
+			return sessions[i].CreatedAt.Time.Before(sessions[j].CreatedAt.Time)
+		}
+		return sessions[i].ID < sessions[j].ID
+	})
+	return sessions, nil
+}
+
+func (s *MemorySessionStore) Extend(ctx context.Context, id string, expiresAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if session, ok := s.sessions[id]; ok && !session.RevokedAt.Valid {
+		session.ExpiresAt = memoryTimestamp(expiresAt)
+		s.sessions[id] = session
+	}
+	return nil
+}
+
+func (s *MemorySessionStore) Revoke(ctx context.Context, id string, userID int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if !ok || session.UserID != userID || session.RevokedAt.Valid {
+		return false, nil
+	}
+	session.RevokedAt = memoryTimestamp(time.Now())
+	s.sessions[id] = session
+	return true, nil
+}
+
+func (s *MemorySessionStore) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var oldest *time.Time
+	for _, session := range s.sessions {
+		if oldest == nil || session.ExpiresAt.Time.Before(*oldest) {
+			t := session.ExpiresAt.Time
+			oldest = &t
+		}
+	}
+	return int64(len(s.sessions)), oldest, nil
+}
+
+func (s *MemorySessionStore) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var pruned int64
+	for id, session := range s.sessions {
+		if session.ExpiresAt.Time.Before(before) {
+			delete(s.sessions, id)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
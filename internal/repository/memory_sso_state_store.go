@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// MemorySSOStateStore implements SSOStateStore in this process.
+type MemorySSOStateStore struct {
+	mu      sync.Mutex
+	pending map[string]SSOPendingLogin
+}
+
+func NewMemorySSOStateStore() *MemorySSOStateStore {
+	return &MemorySSOStateStore{pending: make(map[string]SSOPendingLogin)}
+}
+
+func (s *MemorySSOStateStore) Save(ctx context.Context, stateHash string, login SSOPendingLogin) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pending[stateHash] = login
+	return nil
+}
+
+func (s *MemorySSOStateStore) Take(ctx context.Context, stateHash string) (SSOPendingLogin, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	login, ok := s.pending[stateHash]
+	if !ok {
+		return SSOPendingLogin{}, pgx.ErrNoRows
+	}
+	delete(s.pending, stateHash)
+	return login, nil
+}
+
+func (s *MemorySSOStateStore) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var oldest *time.Time
+	for _, login := range s.pending {
+		if oldest == nil || login.ExpiresAt.Before(*oldest) {
+			t := login.ExpiresAt
+			oldest = &t
+		}
+	}
+	return int64(len(s.pending)), oldest, nil
+}
+
+func (s *MemorySSOStateStore) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var pruned int64
+	for hash, login := range s.pending {
+		if !login.ExpiresAt.After(before) {
+			delete(s.pending, hash)
+			pruned++
+		}
+	}
+	return pruned, nil
+}
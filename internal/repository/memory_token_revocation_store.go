@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryTokenRevocationStore implements TokenRevocationStore in this
+// process.
+type MemoryTokenRevocationStore struct {
+	mu      sync.RWMutex
+	revoked map[int64]time.Time
+}
+
+func NewMemoryTokenRevocationStore() *MemoryTokenRevocationStore {
+	return &MemoryTokenRevocationStore{revoked: make(map[int64]time.Time)}
+}
+
+func (s *MemoryTokenRevocationStore) Revoke(ctx context.Context, userID int64, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.revoked[userID] = at.UTC()
+	return nil
+}
+
+func (s *MemoryTokenRevocationStore) List(ctx context.Context) (map[int64]time.Time, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	revoked := make(map[int64]time.Time, len(s.revoked))
+	for userID, at := range s.revoked {
+		revoked[userID] = at
+	}
+	return revoked, nil
+}
@@ -0,0 +1,611 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/dates"
+)
+
+// MemoryUserStore implements UserStore with maps in this process, for
+// demos, local development and tests without a database. It keeps the
+// rules callers rely on: IDs count up from 1, emails are unique, and
+// lookups that match nothing return pgx.ErrNoRows. Everything is lost on
+// restart.
+type MemoryUserStore struct {
+	mu    sync.RWMutex
+	users map[int64]generated.User
+	// emails holds the users that aren't deleted.
+	emails map[string]int64
+	nextID int64
+	now    func() time.Time
+	// orgMembers returns an organization's users, for searches by
+	// organization. It's set by NewMemoryOrganizationStore.
+	orgMembers func(orgID int64) map[int64]bool
+}
+
+func NewMemoryUserStore() *MemoryUserStore {
+	return &MemoryUserStore{
+		users:  make(map[int64]generated.User),
+		emails: make(map[string]int64),
+		now:    time.Now,
+	}
+}
+
+// memoryTimestamp is a timestamp as the database stores it: in UTC, to the
+// microsecond.
+func memoryTimestamp(t time.Time) pgtype.Timestamp {
+	return pgtype.Timestamp{Time: t.UTC().Truncate(time.Microsecond), Valid: true}
+}
+
+// memoryUniqueViolation is the error Postgres's unique index named
+// constraint would give.
+func memoryUniqueViolation(constraint string) error {
+	return &ConstraintError{
+		Kind:       ErrUniqueViolation,
+		Constraint: constraint,
+		Err:        fmt.Errorf("duplicate key value violates unique constraint %q", constraint),
+	}
+}
+
+func memoryDate(t time.Time) pgtype.Date {
+	return pgtype.Date{Time: time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), Valid: true}
+}
+
+func (s *MemoryUserStore) Create(ctx context.Context, name, source string, dob time.Time) (generated.CreateUserRow, error) {
+	return s.CreateWithAuth(ctx, name, "", "", "", source, dob)
+}
+
+func (s *MemoryUserStore) CreateWithAuth(ctx context.Context, name, email, passwordHash, role, source string, dob time.Time) (generated.CreateUserRow, error) {
+	if role == "" {
+		role = "user"
+	}
+	user, err := s.insert(generated.User{
+		Name:         name,
+		Dob:          memoryDate(dob),
+		Email:        email,
+		PasswordHash: passwordHash,
+		Role:         role,
+		AccountType:  "human",
+		SignupSource: source,
+	})
+	return generated.CreateUserRow(withoutPassword(user)), err
+}
+
+func (s *MemoryUserStore) CreateServiceAccount(ctx context.Context, name, email, passwordHash, role string) (generated.CreateServiceAccountRow, error) {
+	user, err := s.insert(generated.User{
+		Name:         name,
+		Dob:          memoryDate(s.now()),
+		Email:        email,
+		PasswordHash: passwordHash,
+		Role:         role,
+		AccountType:  "service",
+		SignupSource: "admin",
+	})
+	return generated.CreateServiceAccountRow(withoutPassword(user)), err
+}
+
+func (s *MemoryUserStore) insert(user generated.User) (generated.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, taken := s.emails[user.Email]; taken {
+		return generated.User{}, memoryUniqueViolation("users_email_key")
+	}
+	s.nextID++
+	now := memoryTimestamp(s.now())
+	user.ID = s.nextID
+	user.CreatedAt = now
+	user.UpdatedAt = now
+	user.Active = true
+	user.PublicID = pgtype.UUID{Bytes: uuid.New(), Valid: true}
+	s.users[user.ID] = user
+	s.emails[user.Email] = user.ID
+	return user, nil
+}
+
+// update applies change to the user and bumps updated_at.
+func (s *MemoryUserStore) update(id int64, change func(*generated.User)) (generated.GetUserByIDRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return generated.GetUserByIDRow{}, pgx.ErrNoRows
+	}
+	change(&user)
+	user.UpdatedAt = memoryTimestamp(s.now())
+	s.users[id] = user
+	return withoutPassword(user), nil
+}
+
+// user returns a copy of the user unless they are deleted, for the other
+// memory stores' joins.
+func (s *MemoryUserStore) user(id int64) (generated.User, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	user, ok := s.users[id]
+	return user, ok && !user.DeletedAt.Valid
+}
+
+// publicID returns the user's public ID, deleted or not.
+func (s *MemoryUserStore) publicID(id int64) pgtype.UUID {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.users[id].PublicID
+}
+
+// sorted returns the users that aren't deleted and match keep, by ID.
+func (s *MemoryUserStore) sorted(keep func(generated.User) bool) []generated.User {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	users := make([]generated.User, 0, len(s.users))
+	for _, user := range s.users {
+		if !user.DeletedAt.Valid && (keep == nil || keep(user)) {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users
+}
+
+func withoutPassword(u generated.User) generated.GetUserByIDRow {
+	return generated.GetUserByIDRow{
+		ID:           u.ID,
+		Name:         u.Name,
+		Dob:          u.Dob,
+		Email:        u.Email,
+		Role:         u.Role,
+		Active:       u.Active,
+		CreatedAt:    u.CreatedAt,
+		UpdatedAt:    u.UpdatedAt,
+		AccountType:  u.AccountType,
+		SignupSource: u.SignupSource,
+		PublicID:     u.PublicID,
+	}
+}
+
+func (s *MemoryUserStore) GetByID(ctx context.Context, id int64) (generated.GetUserByIDRow, error) {
+	user, ok := s.user(id)
+	if !ok {
+		return generated.GetUserByIDRow{}, pgx.ErrNoRows
+	}
+	return withoutPassword(user), nil
+}
+
+func (s *MemoryUserStore) GetIDByPublicID(ctx context.Context, publicID string) (int64, error) {
+	var id pgtype.UUID
+	if err := id.Scan(publicID); err != nil {
+		return 0, pgx.ErrNoRows
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, user := range s.users {
+		if user.PublicID == id {
+			return user.ID, nil
+		}
+	}
+	return 0, pgx.ErrNoRows
+}
+
+func (s *MemoryUserStore) GetByEmail(ctx context.Context, email string) (generated.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	id, ok := s.emails[email]
+	if !ok {
+		return generated.User{}, pgx.ErrNoRows
+	}
+	return s.users[id], nil
+}
+
+func (s *MemoryUserStore) LookupByEmail(ctx context.Context, prefix string, limit int32) ([]generated.LookupUsersByEmailRow, error) {
+	prefix = strings.ToLower(prefix)
+	users := s.sorted(func(u generated.User) bool { return strings.HasPrefix(strings.ToLower(u.Email), prefix) })
+	sort.SliceStable(users, func(i, j int) bool { return strings.ToLower(users[i].Email) < strings.ToLower(users[j].Email) })
+	rows := make([]generated.LookupUsersByEmailRow, 0, min(len(users), int(limit)))
+	for _, user := range users[:min(len(users), int(limit))] {
+		rows = append(rows, generated.LookupUsersByEmailRow{
+			ID:          user.ID,
+			Name:        user.Name,
+			Email:       user.Email,
+			Role:        user.Role,
+			Active:      user.Active,
+			AccountType: user.AccountType,
+			PublicID:    user.PublicID,
+		})
+	}
+	return rows, nil
+}
+
+func (s *MemoryUserStore) List(ctx context.Context) ([]generated.ListUsersRow, error) {
+	users := s.sorted(nil)
+	rows := make([]generated.ListUsersRow, 0, len(users))
+	for _, user := range users {
+		rows = append(rows, generated.ListUsersRow(withoutPassword(user)))
+	}
+	return rows, nil
+}
+
+func (s *MemoryUserStore) ListPaginated(ctx context.Context, limit, offset int32) ([]generated.ListUsersPaginatedRow, error) {
+	users := s.sorted(nil)
+	var rows []generated.ListUsersPaginatedRow
+	for i := int(offset); i < len(users) && len(rows) < int(limit); i++ {
+		rows = append(rows, generated.ListUsersPaginatedRow(withoutPassword(users[i])))
+	}
+	return rows, nil
+}
+
+func (s *MemoryUserStore) Search(ctx context.Context, filter UserFilter, sort []UserSortKey, limit, offset int32) ([]generated.SearchUsersRow, error) {
+	users := s.sorted(s.searchFilter(filter))
+	args := userSortArgs(sort)
+	slices.SortStableFunc(users, func(a, b generated.User) int {
+		for _, arg := range args {
+			if c := compareUsers(a, b, strings.TrimPrefix(arg, "-")); c != 0 {
+				if strings.HasPrefix(arg, "-") {
+					return -c
+				}
+				return c
+			}
+		}
+		return 0
+	})
+	var rows []generated.SearchUsersRow
+	for i := int(offset); i < len(users) && len(rows) < int(limit); i++ {
+		rows = append(rows, generated.SearchUsersRow(withoutPassword(users[i])))
+	}
+	return rows, nil
+}
+
+func (s *MemoryUserStore) CountSearch(ctx context.Context, filter UserFilter) (int64, error) {
+	return int64(len(s.sorted(s.searchFilter(filter)))), nil
+}
+
+func (s *MemoryUserStore) setOrgMembers(members func(orgID int64) map[int64]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.orgMembers = members
+}
+
+// searchFilter is filter.matches plus the filter on organization, whose
+// members are looked up first: the organization store takes its lock
+// before ours, so it can't be asked from within sorted.
+func (s *MemoryUserStore) searchFilter(filter UserFilter) func(generated.User) bool {
+	if filter.OrgID == 0 {
+		return filter.matches
+	}
+	s.mu.RLock()
+	orgMembers := s.orgMembers
+	s.mu.RUnlock()
+	var members map[int64]bool
+	if orgMembers != nil {
+		members = orgMembers(filter.OrgID)
+	}
+	return func(u generated.User) bool {
+		return members[u.ID] && filter.matches(u)
+	}
+}
+
+// compareUsers compares a and b on one of the Sort columns, and finds them
+// equal on anything else.
+func compareUsers(a, b generated.User, column string) int {
+	switch column {
+	case SortName:
+		return strings.Compare(a.Name, b.Name)
+	case SortEmail:
+		return strings.Compare(a.Email, b.Email)
+	case SortRole:
+		return strings.Compare(a.Role, b.Role)
+	case SortDob:
+		return a.Dob.Time.Compare(b.Dob.Time)
+	case SortCreatedAt:
+		return a.CreatedAt.Time.Compare(b.CreatedAt.Time)
+	case SortUpdatedAt:
+		return a.UpdatedAt.Time.Compare(b.UpdatedAt.Time)
+	}
+	return 0
+}
+
+// matches is the search queries' WHERE clause.
+func (f UserFilter) matches(u generated.User) bool {
+	switch {
+	case f.Name != "" && !strings.Contains(strings.ToLower(u.Name), strings.ToLower(f.Name)):
+		return false
+	case f.Email != "" && !strings.EqualFold(u.Email, f.Email):
+		return false
+	case f.Role != "" && u.Role != f.Role:
+		return false
+	case f.SignupSource != "" && u.SignupSource != f.SignupSource:
+		return false
+	case !f.DobAfter.IsZero() && !u.Dob.Time.After(f.DobAfter):
+		return false
+	case !f.DobUntil.IsZero() && u.Dob.Time.After(f.DobUntil):
+		return false
+	case !f.CreatedFrom.IsZero() && u.CreatedAt.Time.Before(f.CreatedFrom):
+		return false
+	case !f.CreatedBefore.IsZero() && !u.CreatedAt.Time.Before(f.CreatedBefore):
+		return false
+	}
+	return true
+}
+
+func (s *MemoryUserStore) ListBySignupSource(ctx context.Context, source string) ([]generated.ListUsersBySignupSourceRow, error) {
+	users := s.sorted(func(u generated.User) bool { return u.SignupSource == source })
+	rows := make([]generated.ListUsersBySignupSourceRow, 0, len(users))
+	for _, user := range users {
+		rows = append(rows, generated.ListUsersBySignupSourceRow(withoutPassword(user)))
+	}
+	return rows, nil
+}
+
+func (s *MemoryUserStore) Count(ctx context.Context) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return int64(len(s.emails)), nil
+}
+
+func (s *MemoryUserStore) Update(ctx context.Context, id int64, name string, dob time.Time) (generated.UpdateUserRow, error) {
+	row, err := s.update(id, func(u *generated.User) {
+		u.Name = name
+		u.Dob = memoryDate(dob)
+	})
+	return generated.UpdateUserRow(row), err
+}
+
+func (s *MemoryUserStore) SoftDelete(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return pgx.ErrNoRows
+	}
+	now := memoryTimestamp(s.now())
+	user.DeletedAt = now
+	user.UpdatedAt = now
+	s.users[id] = user
+	delete(s.emails, user.Email)
+	return nil
+}
+
+func (s *MemoryUserStore) Restore(ctx context.Context, id int64) (generated.RestoreUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	user, ok := s.users[id]
+	if !ok || !user.DeletedAt.Valid {
+		return generated.RestoreUserRow{}, pgx.ErrNoRows
+	}
+	if _, taken := s.emails[user.Email]; taken {
+		return generated.RestoreUserRow{}, memoryUniqueViolation("users_email_key")
+	}
+	user.DeletedAt = pgtype.Timestamp{}
+	user.UpdatedAt = memoryTimestamp(s.now())
+	s.users[id] = user
+	s.emails[user.Email] = id
+	return generated.RestoreUserRow(withoutPassword(user)), nil
+}
+
+func (s *MemoryUserStore) HardDelete(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if user, ok := s.users[id]; ok {
+		if !user.DeletedAt.Valid {
+			delete(s.emails, user.Email)
+		}
+		delete(s.users, id)
+	}
+	return nil
+}
+
+func (s *MemoryUserStore) GetDeletedByID(ctx context.Context, id int64) (generated.GetDeletedUserByIDRow, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	user, ok := s.users[id]
+	if !ok || !user.DeletedAt.Valid {
+		return generated.GetDeletedUserByIDRow{}, pgx.ErrNoRows
+	}
+	return generated.GetDeletedUserByIDRow(withoutPassword(user)), nil
+}
+
+func (s *MemoryUserStore) SetActive(ctx context.Context, id int64, active bool) (generated.SetUserActiveRow, error) {
+	row, err := s.update(id, func(u *generated.User) { u.Active = active })
+	return generated.SetUserActiveRow(row), err
+}
+
+func (s *MemoryUserStore) UpdateRole(ctx context.Context, id int64, role string) (generated.UpdateUserRoleRow, error) {
+	row, err := s.update(id, func(u *generated.User) { u.Role = role })
+	return generated.UpdateUserRoleRow(row), err
+}
+
+func (s *MemoryUserStore) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	_, err := s.update(id, func(u *generated.User) { u.PasswordHash = passwordHash })
+	return err
+}
+
+func (s *MemoryUserStore) ListServiceAccounts(ctx context.Context) ([]generated.ListServiceAccountsRow, error) {
+	users := s.sorted(func(u generated.User) bool { return u.AccountType == "service" })
+	rows := make([]generated.ListServiceAccountsRow, 0, len(users))
+	for _, user := range users {
+		rows = append(rows, generated.ListServiceAccountsRow(withoutPassword(user)))
+	}
+	return rows, nil
+}
+
+// ageBrackets are the brackets of UsersByAgeBracket, youngest first, with
+// the age each ends before.
+var ageBrackets = []struct {
+	name  string
+	below int
+}{
+	{"under_18", 18},
+	{"18_24", 25},
+	{"25_34", 35},
+	{"35_44", 45},
+	{"45_54", 55},
+	{"55_64", 65},
+	{"65_plus", int(^uint(0) >> 1)},
+}
+
+func (s *MemoryUserStore) UsersByAgeBracket(ctx context.Context) ([]generated.UsersByAgeBracketRow, error) {
+	today := s.now()
+	counts := make([]int64, len(ageBrackets))
+	for _, user := range s.sorted(nil) {
+		age := dates.Age(user.Dob.Time, today)
+		for i, bracket := range ageBrackets {
+			if age < bracket.below {
+				counts[i]++
+				break
+			}
+		}
+	}
+	rows := []generated.UsersByAgeBracketRow{}
+	for i, bracket := range ageBrackets {
+		if counts[i] > 0 {
+			rows = append(rows, generated.UsersByAgeBracketRow{Bracket: bracket.name, UserCount: counts[i]})
+		}
+	}
+	return rows, nil
+}
+
+func (s *MemoryUserStore) AgeDistribution(ctx context.Context, groupBy string, bucketSize int32) ([]generated.AgeDistributionRow, error) {
+	if bucketSize <= 0 {
+		return nil, fmt.Errorf("bucket size must be positive, got %d", bucketSize)
+	}
+	type bucket struct {
+		grp   string
+		start int32
+	}
+	today := s.now()
+	counts := make(map[bucket]int64)
+	for _, user := range s.sorted(nil) {
+		var grp string
+		switch groupBy {
+		case "role":
+			grp = user.Role
+		case "signup_cohort":
+			grp = user.CreatedAt.Time.Format("2006-01")
+		}
+		counts[bucket{grp: grp, start: int32(dates.Age(user.Dob.Time, today)) / bucketSize * bucketSize}]++
+	}
+	rows := make([]generated.AgeDistributionRow, 0, len(counts))
+	for b, n := range counts {
+		rows = append(rows, generated.AgeDistributionRow{Grp: b.grp, BucketStart: b.start, UserCount: n})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].Grp != rows[j].Grp {
+			return rows[i].Grp < rows[j].Grp
+		}
+		return rows[i].BucketStart < rows[j].BucketStart
+	})
+	return rows, nil
+}
+
+// signupsSince counts the users created at or after since by key, sorted by
+// key.
+func (s *MemoryUserStore) signupsSince(since time.Time, key func(generated.User) string) ([]string, map[string]int64) {
+	counts := make(map[string]int64)
+	for _, user := range s.sorted(func(u generated.User) bool { return !u.CreatedAt.Time.Before(since) }) {
+		counts[key(user)]++
+	}
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, counts
+}
+
+func (s *MemoryUserStore) SignupsByDay(ctx context.Context, since time.Time) ([]generated.SignupsByDayRow, error) {
+	days, counts := s.signupsSince(since, func(u generated.User) string { return u.CreatedAt.Time.Format("2006-01-02") })
+	rows := make([]generated.SignupsByDayRow, 0, len(days))
+	for _, day := range days {
+		rows = append(rows, generated.SignupsByDayRow{Day: day, Signups: counts[day]})
+	}
+	return rows, nil
+}
+
+func (s *MemoryUserStore) SignupsByMonth(ctx context.Context, since time.Time) ([]generated.SignupsByMonthRow, error) {
+	months, counts := s.signupsSince(since, func(u generated.User) string { return u.CreatedAt.Time.Format("2006-01") })
+	rows := make([]generated.SignupsByMonthRow, 0, len(months))
+	for _, month := range months {
+		rows = append(rows, generated.SignupsByMonthRow{Month: month, Signups: counts[month]})
+	}
+	return rows, nil
+}
+
+func (s *MemoryUserStore) SignupsBySource(ctx context.Context, since time.Time) ([]generated.SignupsBySourceRow, error) {
+	sources, counts := s.signupsSince(since, func(u generated.User) string { return u.SignupSource })
+	rows := make([]generated.SignupsBySourceRow, 0, len(sources))
+	for _, source := range sources {
+		rows = append(rows, generated.SignupsBySourceRow{SignupSource: source, Signups: counts[source]})
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].Signups > rows[j].Signups })
+	return rows, nil
+}
+
+func (s *MemoryUserStore) ListDeactivatedSince(ctx context.Context, since time.Time) ([]generated.ListDeactivatedUsersRow, error) {
+	users := s.sorted(func(u generated.User) bool { return !u.Active && !u.UpdatedAt.Time.Before(since) })
+	sort.SliceStable(users, func(i, j int) bool { return users[i].UpdatedAt.Time.After(users[j].UpdatedAt.Time) })
+	rows := make([]generated.ListDeactivatedUsersRow, 0, len(users))
+	for _, user := range users {
+		rows = append(rows, generated.ListDeactivatedUsersRow{ID: user.ID, Name: user.Name, Email: user.Email, UpdatedAt: user.UpdatedAt})
+	}
+	return rows, nil
+}
+
+func (s *MemoryUserStore) UpcomingBirthdays(ctx context.Context, from, to time.Time, limit int32) ([]generated.ListUpcomingBirthdaysRow, error) {
+	w := newBirthdayWindow(from, to)
+	users := s.sorted(func(u generated.User) bool { return w.contains(birthdayDay(u.Dob.Time)) })
+	sort.SliceStable(users, func(i, j int) bool { return w.less(birthdayDay(users[i].Dob.Time), birthdayDay(users[j].Dob.Time)) })
+	if len(users) > int(limit) {
+		users = users[:limit]
+	}
+	rows := make([]generated.ListUpcomingBirthdaysRow, 0, len(users))
+	for _, user := range users {
+		rows = append(rows, generated.ListUpcomingBirthdaysRow{ID: user.ID, Name: user.Name, Dob: user.Dob, PublicID: user.PublicID})
+	}
+	return rows, nil
+}
+
+func (s *MemoryUserStore) ListActiveAdmins(ctx context.Context) ([]generated.ListActiveAdminsRow, error) {
+	users := s.sorted(func(u generated.User) bool { return u.Role == "admin" && u.Active && u.AccountType == "human" })
+	rows := make([]generated.ListActiveAdminsRow, 0, len(users))
+	for _, user := range users {
+		rows = append(rows, generated.ListActiveAdminsRow{ID: user.ID, Name: user.Name, Email: user.Email})
+	}
+	return rows, nil
+}
+
+// GetStats computes the aggregates on every call, as on MySQL.
+func (s *MemoryUserStore) GetStats(ctx context.Context) (generated.GetUserStatsRow, error) {
+	now := s.now()
+	stats := generated.GetUserStatsRow{RefreshedAt: memoryTimestamp(now)}
+	var totalAge int
+	for _, user := range s.sorted(nil) {
+		stats.TotalUsers++
+		if user.Role == "admin" {
+			stats.AdminUsers++
+		}
+		if !user.CreatedAt.Time.Before(now.Add(-7 * 24 * time.Hour)) {
+			stats.SignupsLast7Days++
+		}
+		if !user.CreatedAt.Time.Before(now.Add(-30 * 24 * time.Hour)) {
+			stats.SignupsLast30Days++
+		}
+		totalAge += dates.Age(user.Dob.Time, now)
+	}
+	if stats.TotalUsers > 0 {
+		stats.AverageAge = float64(totalAge) / float64(stats.TotalUsers)
+	}
+	return stats, nil
+}
+
+func (s *MemoryUserStore) RefreshStats(ctx context.Context) error {
+	return nil
+}
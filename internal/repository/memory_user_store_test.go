@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestMemoryUserStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.UTC)
+	s := NewMemoryUserStore()
+	s.now = func() time.Time { return now }
+
+	jane, err := s.CreateWithAuth(ctx, "Jane", "jane@example.com", "hash", "", "web", time.Date(1990, 6, 16, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if jane.ID != 1 || jane.Role != "user" || !jane.Active || jane.AccountType != "human" || !jane.PublicID.Valid {
+		t.Errorf("created %+v", jane)
+	}
+	if _, err := s.CreateWithAuth(ctx, "Other", "jane@example.com", "hash", "", "web", now); !errors.Is(err, ErrEmailAlreadyExists) {
+		t.Errorf("duplicate email: got %v, want ErrEmailAlreadyExists", err)
+	}
+	if _, err := s.CreateWithAuth(ctx, "Admin", "admin@example.com", "hash", "admin", "admin", time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	if user, err := s.GetByEmail(ctx, "jane@example.com"); err != nil || user.PasswordHash != "hash" {
+		t.Errorf("GetByEmail() = %+v, %v", user, err)
+	}
+	if id, err := s.GetIDByPublicID(ctx, jane.PublicID.String()); err != nil || id != jane.ID {
+		t.Errorf("GetIDByPublicID() = %d, %v", id, err)
+	}
+	if _, err := s.GetByID(ctx, 99); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("GetByID(missing) = %v, want pgx.ErrNoRows", err)
+	}
+	if _, err := s.Update(ctx, 99, "Nobody", now); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("Update(missing) = %v, want pgx.ErrNoRows", err)
+	}
+
+	page, err := s.ListPaginated(ctx, 1, 1)
+	if err != nil || len(page) != 1 || page[0].Name != "Admin" {
+		t.Errorf("ListPaginated(1, 1) = %+v, %v", page, err)
+	}
+
+	// Jane turns 36 tomorrow.
+	brackets, err := s.UsersByAgeBracket(ctx)
+	if err != nil || len(brackets) != 2 || brackets[0].Bracket != "35_44" || brackets[0].UserCount != 1 || brackets[1].Bracket != "45_54" {
+		t.Errorf("UsersByAgeBracket() = %+v, %v", brackets, err)
+	}
+	stats, err := s.GetStats(ctx)
+	if err != nil || stats.TotalUsers != 2 || stats.AdminUsers != 1 || stats.SignupsLast7Days != 2 || stats.AverageAge != 40.5 {
+		t.Errorf("GetStats() = %+v, %v", stats, err)
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := s.SetActive(ctx, jane.ID, false); err != nil {
+		t.Fatal(err)
+	}
+	deactivated, err := s.ListDeactivatedSince(ctx, now)
+	if err != nil || len(deactivated) != 1 || deactivated[0].ID != jane.ID {
+		t.Errorf("ListDeactivatedSince() = %+v, %v", deactivated, err)
+	}
+
+	if err := s.SoftDelete(ctx, jane.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetByID(ctx, jane.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("GetByID(deleted) = %v, want pgx.ErrNoRows", err)
+	}
+	if n, _ := s.Count(ctx); n != 1 {
+		t.Errorf("Count() = %d after delete, want 1", n)
+	}
+	if id, err := s.GetIDByPublicID(ctx, jane.PublicID.String()); err != nil || id != jane.ID {
+		t.Errorf("GetIDByPublicID(deleted) = %d, %v", id, err)
+	}
+	if err := s.SoftDelete(ctx, jane.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("SoftDelete(deleted) = %v, want pgx.ErrNoRows", err)
+	}
+	if restored, err := s.Restore(ctx, jane.ID); err != nil || restored.Email != "jane@example.com" {
+		t.Errorf("Restore() = %+v, %v", restored, err)
+	}
+	if _, err := s.Restore(ctx, jane.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("Restore(live) = %v, want pgx.ErrNoRows", err)
+	}
+
+	if err := s.SoftDelete(ctx, jane.ID); err != nil {
+		t.Fatal(err)
+	}
+	again, err := s.CreateWithAuth(ctx, "Jane", "jane@example.com", "hash", "", "web", now)
+	if err != nil {
+		t.Fatalf("email should be free again after delete: %v", err)
+	}
+	if _, err := s.Restore(ctx, jane.ID); !errors.Is(err, ErrEmailAlreadyExists) {
+		t.Errorf("Restore() with the email taken = %v, want ErrEmailAlreadyExists", err)
+	}
+	if err := s.HardDelete(ctx, jane.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.GetDeletedByID(ctx, jane.ID); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("GetDeletedByID(hard deleted) = %v, want pgx.ErrNoRows", err)
+	}
+	if user, err := s.GetByEmail(ctx, "jane@example.com"); err != nil || user.ID != again.ID {
+		t.Errorf("GetByEmail() = %+v, %v; hard deleting the old account shouldn't touch the new one", user, err)
+	}
+}
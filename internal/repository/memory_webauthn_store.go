@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// MemoryWebAuthnCredentialStore implements WebAuthnCredentialStore in this
+// process.
+type MemoryWebAuthnCredentialStore struct {
+	mu          sync.RWMutex
+	credentials map[int64]generated.WebauthnCredential
+	nextID      int64
+}
+
+func NewMemoryWebAuthnCredentialStore() *MemoryWebAuthnCredentialStore {
+	return &MemoryWebAuthnCredentialStore{credentials: make(map[int64]generated.WebauthnCredential)}
+}
+
+func (s *MemoryWebAuthnCredentialStore) Create(ctx context.Context, userID int64, credentialID, publicKey []byte, signCount int64, name string) (generated.WebauthnCredential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, cred := range s.credentials {
+		if bytes.Equal(cred.CredentialID, credentialID) {
+			return generated.WebauthnCredential{}, memoryUniqueViolation("webauthn_credentials_credential_id_key")
+		}
+	}
+	s.nextID++
+	cred := generated.WebauthnCredential{
+		ID:           s.nextID,
+		UserID:       userID,
+		CredentialID: bytes.Clone(credentialID),
+		PublicKey:    bytes.Clone(publicKey),
+		SignCount:    signCount,
+		Name:         name,
+		CreatedAt:    memoryTimestamp(time.Now()),
+	}
+	s.credentials[cred.ID] = cred
+	return cred, nil
+}
+
+func (s *MemoryWebAuthnCredentialStore) Get(ctx context.Context, credentialID []byte) (generated.WebauthnCredential, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, cred := range s.credentials {
+		if bytes.Equal(cred.CredentialID, credentialID) {
+			return cred, nil
+		}
+	}
+	return generated.WebauthnCredential{}, pgx.ErrNoRows
+}
+
+func (s *MemoryWebAuthnCredentialStore) ListByUser(ctx context.Context, userID int64) ([]generated.WebauthnCredential, error) {
+	s.mu.RLock()
+	creds := []generated.WebauthnCredential{}
+	for _, cred := range s.credentials {
+		if cred.UserID == userID {
+			creds = append(creds, cred)
+		}
+	}
+	s.mu.RUnlock()
+	sort.Slice(creds, func(i, j int) bool { return creds[i].ID < creds[j].ID })
+	return creds, nil
+}
+
+func (s *MemoryWebAuthnCredentialStore) UpdateSignCount(ctx context.Context, id int64, signCount int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cred, ok := s.credentials[id]; ok {
+		cred.SignCount = signCount
+		cred.LastUsedAt = memoryTimestamp(time.Now())
+		s.credentials[id] = cred
+	}
+	return nil
+}
+
+func (s *MemoryWebAuthnCredentialStore) Delete(ctx context.Context, id, userID int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cred, ok := s.credentials[id]; !ok || cred.UserID != userID {
+		return false, nil
+	}
+	delete(s.credentials, id)
+	return true, nil
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// Metrics records the latency, errors and row counts of repository calls,
+// keyed by store and method, and logs each call: at debug level normally, as
+// a warning once it takes longer than the slow threshold. Lookups that find
+// nothing are not counted as errors.
+type Metrics struct {
+	logger *zap.Logger
+	slow   time.Duration
+
+	mu      sync.Mutex
+	methods map[string]*methodMetrics
+}
+
+type methodMetrics struct {
+	calls, errors, slow, rows int64
+	total, max                time.Duration
+}
+
+// MethodStats summarises the calls to one repository method since startup.
+type MethodStats struct {
+	Method       string  `json:"method"`
+	Calls        int64   `json:"calls"`
+	Errors       int64   `json:"errors"`
+	ErrorRate    float64 `json:"error_rate"`
+	Slow         int64   `json:"slow"`
+	Rows         int64   `json:"rows"`
+	AvgLatencyMs float64 `json:"avg_latency_ms"`
+	MaxLatencyMs float64 `json:"max_latency_ms"`
+}
+
+// NewMetrics logs calls slower than slow as warnings; zero never does.
+func NewMetrics(logger *zap.Logger, slow time.Duration) *Metrics {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Metrics{
+		logger:  logger,
+		slow:    slow,
+		methods: make(map[string]*methodMetrics),
+	}
+}
+
+func (m *Metrics) observe(method string, start time.Time, rows int, err error) {
+	elapsed := time.Since(start)
+	failed := err != nil && !errors.Is(err, pgx.ErrNoRows)
+	slow := m.slow > 0 && elapsed > m.slow
+
+	m.mu.Lock()
+	mm := m.methods[method]
+	if mm == nil {
+		mm = &methodMetrics{}
+		m.methods[method] = mm
+	}
+	mm.calls++
+	mm.rows += int64(rows)
+	mm.total += elapsed
+	if elapsed > mm.max {
+		mm.max = elapsed
+	}
+	if failed {
+		mm.errors++
+	}
+	if slow {
+		mm.slow++
+	}
+	m.mu.Unlock()
+
+	fields := []zap.Field{
+		zap.String("method", method),
+		zap.Duration("duration", elapsed),
+		zap.Int("rows", rows),
+	}
+	if failed {
+		fields = append(fields, zap.Error(err))
+	}
+	if slow {
+		m.logger.Warn("slow repository call", fields...)
+		return
+	}
+	m.logger.Debug("repository call", fields...)
+}
+
+// SlowThreshold is the latency above which calls are logged as warnings.
+func (m *Metrics) SlowThreshold() time.Duration {
+	if m == nil {
+		return 0
+	}
+	return m.slow
+}
+
+// Stats returns one entry per method called so far, sorted by method.
+func (m *Metrics) Stats() []MethodStats {
+	if m == nil {
+		return []MethodStats{}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make([]MethodStats, 0, len(m.methods))
+	for method, mm := range m.methods {
+		stats = append(stats, MethodStats{
+			Method:       method,
+			Calls:        mm.calls,
+			Errors:       mm.errors,
+			ErrorRate:    float64(mm.errors) / float64(mm.calls),
+			Slow:         mm.slow,
+			Rows:         mm.rows,
+			AvgLatencyMs: float64(mm.total) / float64(mm.calls) / float64(time.Millisecond),
+			MaxLatencyMs: float64(mm.max) / float64(time.Millisecond),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Method < stats[j].Method })
+	return stats
+}
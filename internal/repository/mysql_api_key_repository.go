@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLAPIKeyRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLAPIKeyRepository(q *mysqlgen.Queries) *MySQLAPIKeyRepository {
+	return &MySQLAPIKeyRepository{queries: q}
+}
+
+func (r *MySQLAPIKeyRepository) Create(ctx context.Context, userID int64, name, prefix, keyHash, scopes string, expiresAt *time.Time) (generated.CreateAPIKeyRow, error) {
+	params := mysqlgen.CreateAPIKeyParams{
+		UserID:  userID,
+		Name:    name,
+		Prefix:  prefix,
+		KeyHash: keyHash,
+		Scopes:  scopes,
+	}
+	if expiresAt != nil {
+		params.ExpiresAt = sql.NullTime{Time: *expiresAt, Valid: true}
+	}
+
+	id, err := r.queries.CreateAPIKey(ctx, params)
+	if err != nil {
+		return generated.CreateAPIKeyRow{}, mysqlError(err)
+	}
+	row, err := r.queries.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return generated.CreateAPIKeyRow{}, mysqlError(err)
+	}
+	return generated.CreateAPIKeyRow(apiKeyRow(mysqlgen.ListAPIKeysByUserRow(row))), nil
+}
+
+func (r *MySQLAPIKeyRepository) ListByUser(ctx context.Context, userID int64) ([]generated.ListAPIKeysByUserRow, error) {
+	rows, err := r.queries.ListAPIKeysByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]generated.ListAPIKeysByUserRow, 0, len(rows))
+	for _, row := range rows {
+		keys = append(keys, apiKeyRow(row))
+	}
+	return keys, nil
+}
+
+func (r *MySQLAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (generated.GetAPIKeyByHashRow, error) {
+	row, err := r.queries.GetAPIKeyByHash(ctx, keyHash)
+	if err != nil {
+		return generated.GetAPIKeyByHashRow{}, mysqlError(err)
+	}
+	return generated.GetAPIKeyByHashRow{
+		ID:          row.ID,
+		UserID:      row.UserID,
+		Scopes:      row.Scopes,
+		ExpiresAt:   pgNullTimestamp(row.ExpiresAt),
+		RevokedAt:   pgNullTimestamp(row.RevokedAt),
+		Role:        row.Role,
+		Active:      row.Active,
+		AccountType: row.AccountType,
+	}, nil
+}
+
+func (r *MySQLAPIKeyRepository) Revoke(ctx context.Context, id, userID int64) (bool, error) {
+	n, err := r.queries.RevokeAPIKey(ctx, mysqlgen.RevokeAPIKeyParams{
+		ID:     id,
+		UserID: userID,
+	})
+	return n > 0, err
+}
+
+func (r *MySQLAPIKeyRepository) Touch(ctx context.Context, id int64) error {
+	return r.queries.TouchAPIKey(ctx, id)
+}
+
+func apiKeyRow(row mysqlgen.ListAPIKeysByUserRow) generated.ListAPIKeysByUserRow {
+	return generated.ListAPIKeysByUserRow{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		Name:       row.Name,
+		Prefix:     row.Prefix,
+		Scopes:     row.Scopes,
+		CreatedAt:  pgTimestamp(row.CreatedAt),
+		ExpiresAt:  pgNullTimestamp(row.ExpiresAt),
+		LastUsedAt: pgNullTimestamp(row.LastUsedAt),
+		RevokedAt:  pgNullTimestamp(row.RevokedAt),
+	}
+}
+
+func pgNullTimestamp(t sql.NullTime) pgtype.Timestamp {
+	return pgtype.Timestamp{Time: t.Time, Valid: t.Valid}
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLAPIUsageRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLAPIUsageRepository(q *mysqlgen.Queries) *MySQLAPIUsageRepository {
+	return &MySQLAPIUsageRepository{queries: q}
+}
+
+func (r *MySQLAPIUsageRepository) Add(ctx context.Context, userID, apiKeyID int64, day time.Time, requests int64) error {
+	return mysqlError(r.queries.AddAPIUsage(ctx, mysqlgen.AddAPIUsageParams{
+		UserID:   userID,
+		ApiKeyID: apiKeyID,
+		Day:      day,
+		Requests: requests,
+	}))
+}
+
+func (r *MySQLAPIUsageRepository) ListByUser(ctx context.Context, userID int64, from, to time.Time) ([]generated.ListAPIUsageByUserRow, error) {
+	rows, err := r.queries.ListAPIUsageByUser(ctx, mysqlgen.ListAPIUsageByUserParams{
+		UserID:  userID,
+		FromDay: from,
+		ToDay:   to,
+	})
+	if err != nil {
+		return nil, err
+	}
+	usage := make([]generated.ListAPIUsageByUserRow, 0, len(rows))
+	for _, row := range rows {
+		usage = append(usage, generated.ListAPIUsageByUserRow{
+			ApiKeyID: row.ApiKeyID,
+			KeyName:  row.KeyName,
+			Day:      pgDate(row.Day),
+			Requests: row.Requests,
+		})
+	}
+	return usage, nil
+}
+
+func (r *MySQLAPIUsageRepository) Top(ctx context.Context, from, to time.Time, limit int32) ([]generated.TopAPIUsageRow, error) {
+	rows, err := r.queries.TopAPIUsage(ctx, mysqlgen.TopAPIUsageParams{
+		FromDay:  from,
+		ToDay:    to,
+		RowLimit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	usage := make([]generated.TopAPIUsageRow, 0, len(rows))
+	for _, row := range rows {
+		usage = append(usage, generated.TopAPIUsageRow{
+			PublicID: pgUUID(row.PublicID),
+			Name:     row.Name,
+			ApiKeyID: row.ApiKeyID,
+			KeyName:  row.KeyName,
+			Requests: row.Requests,
+		})
+	}
+	return usage, nil
+}
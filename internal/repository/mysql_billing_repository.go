@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLBillingRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLBillingRepository(q *mysqlgen.Queries) *MySQLBillingRepository {
+	return &MySQLBillingRepository{queries: q}
+}
+
+func (r *MySQLBillingRepository) Get(ctx context.Context, orgID int64) (generated.OrganizationBilling, error) {
+	row, err := r.queries.GetOrganizationBilling(ctx, orgID)
+	if err != nil {
+		return generated.OrganizationBilling{}, mysqlError(err)
+	}
+	return organizationBilling(row), nil
+}
+
+func (r *MySQLBillingRepository) GetByCustomer(ctx context.Context, provider, customerID string) (generated.OrganizationBilling, error) {
+	row, err := r.queries.GetOrganizationBillingByCustomer(ctx, mysqlgen.GetOrganizationBillingByCustomerParams{
+		Provider:   provider,
+		CustomerID: customerID,
+	})
+	if err != nil {
+		return generated.OrganizationBilling{}, mysqlError(err)
+	}
+	return organizationBilling(row), nil
+}
+
+func (r *MySQLBillingRepository) Apply(ctx context.Context, update BillingUpdate) error {
+	params := mysqlgen.UpsertOrganizationBillingParams{
+		OrgID:          update.OrgID,
+		Provider:       update.Provider,
+		CustomerID:     update.CustomerID,
+		SubscriptionID: update.SubscriptionID,
+		Plan:           update.Plan,
+		Status:         update.Status,
+		Seats:          update.Seats,
+		EventAt:        update.EventAt,
+	}
+	if update.CurrentPeriodEnd != nil {
+		params.CurrentPeriodEnd = sql.NullTime{Time: *update.CurrentPeriodEnd, Valid: true}
+	}
+	return mysqlError(r.queries.UpsertOrganizationBilling(ctx, params))
+}
+
+func organizationBilling(row mysqlgen.OrganizationBilling) generated.OrganizationBilling {
+	return generated.OrganizationBilling{
+		OrgID:            row.OrgID,
+		Provider:         row.Provider,
+		CustomerID:       row.CustomerID,
+		SubscriptionID:   row.SubscriptionID,
+		Plan:             row.Plan,
+		Status:           row.Status,
+		Seats:            row.Seats,
+		CurrentPeriodEnd: pgNullTimestamp(row.CurrentPeriodEnd),
+		EventAt:          pgTimestamp(row.EventAt),
+		UpdatedAt:        pgTimestamp(row.UpdatedAt),
+	}
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLEmailDeadLetterRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLEmailDeadLetterRepository(q *mysqlgen.Queries) *MySQLEmailDeadLetterRepository {
+	return &MySQLEmailDeadLetterRepository{queries: q}
+}
+
+func (r *MySQLEmailDeadLetterRepository) Create(ctx context.Context, recipients, subject, html, lastError string, attempts int32) (generated.EmailDeadLetter, error) {
+	id, err := r.queries.CreateEmailDeadLetter(ctx, mysqlgen.CreateEmailDeadLetterParams{
+		Recipients: recipients,
+		Subject:    subject,
+		Html:       html,
+		Error:      lastError,
+		Attempts:   attempts,
+	})
+	if err != nil {
+		return generated.EmailDeadLetter{}, mysqlError(err)
+	}
+	return r.Get(ctx, id)
+}
+
+func (r *MySQLEmailDeadLetterRepository) Get(ctx context.Context, id int64) (generated.EmailDeadLetter, error) {
+	row, err := r.queries.GetEmailDeadLetter(ctx, id)
+	if err != nil {
+		return generated.EmailDeadLetter{}, mysqlError(err)
+	}
+	return emailDeadLetter(row), nil
+}
+
+func (r *MySQLEmailDeadLetterRepository) List(ctx context.Context, limit int32) ([]generated.EmailDeadLetter, error) {
+	rows, err := r.queries.ListEmailDeadLetters(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+	letters := make([]generated.EmailDeadLetter, 0, len(rows))
+	for _, row := range rows {
+		letters = append(letters, emailDeadLetter(row))
+	}
+	return letters, nil
+}
+
+func (r *MySQLEmailDeadLetterRepository) RecordAttempt(ctx context.Context, id int64, lastError string) error {
+	return mysqlError(r.queries.RecordEmailDeadLetterAttempt(ctx, mysqlgen.RecordEmailDeadLetterAttemptParams{
+		Error: lastError,
+		ID:    id,
+	}))
+}
+
+func (r *MySQLEmailDeadLetterRepository) Delete(ctx context.Context, id int64) (bool, error) {
+	n, err := r.queries.DeleteEmailDeadLetter(ctx, id)
+	return n > 0, mysqlError(err)
+}
+
+func emailDeadLetter(row mysqlgen.EmailDeadLetter) generated.EmailDeadLetter {
+	return generated.EmailDeadLetter{
+		ID:            row.ID,
+		Recipients:    row.Recipients,
+		Subject:       row.Subject,
+		Html:          row.Html,
+		Error:         row.Error,
+		Attempts:      row.Attempts,
+		CreatedAt:     pgTimestamp(row.CreatedAt),
+		LastAttemptAt: pgTimestamp(row.LastAttemptAt),
+	}
+}
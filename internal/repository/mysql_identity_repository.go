@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLIdentityRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLIdentityRepository(q *mysqlgen.Queries) *MySQLIdentityRepository {
+	return &MySQLIdentityRepository{queries: q}
+}
+
+func (r *MySQLIdentityRepository) Create(ctx context.Context, userID int64, provider, subject, email string) (generated.UserIdentity, error) {
+	err := r.queries.CreateUserIdentity(ctx, mysqlgen.CreateUserIdentityParams{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  subject,
+		Email:    email,
+	})
+	if err != nil {
+		return generated.UserIdentity{}, mysqlError(err)
+	}
+	return r.GetBySubject(ctx, provider, subject)
+}
+
+func (r *MySQLIdentityRepository) GetBySubject(ctx context.Context, provider, subject string) (generated.UserIdentity, error) {
+	row, err := r.queries.GetUserIdentityBySubject(ctx, mysqlgen.GetUserIdentityBySubjectParams{
+		Provider: provider,
+		Subject:  subject,
+	})
+	if err != nil {
+		return generated.UserIdentity{}, mysqlError(err)
+	}
+	return userIdentity(row), nil
+}
+
+func (r *MySQLIdentityRepository) ListByUser(ctx context.Context, userID int64) ([]generated.UserIdentity, error) {
+	rows, err := r.queries.ListUserIdentitiesByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	identities := make([]generated.UserIdentity, 0, len(rows))
+	for _, row := range rows {
+		identities = append(identities, userIdentity(row))
+	}
+	return identities, nil
+}
+
+func (r *MySQLIdentityRepository) Delete(ctx context.Context, userID int64, provider string) (bool, error) {
+	n, err := r.queries.DeleteUserIdentity(ctx, mysqlgen.DeleteUserIdentityParams{
+		UserID:   userID,
+		Provider: provider,
+	})
+	return n > 0, err
+}
+
+func userIdentity(row mysqlgen.UserIdentity) generated.UserIdentity {
+	return generated.UserIdentity{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		Provider:  row.Provider,
+		Subject:   row.Subject,
+		Email:     row.Email,
+		CreatedAt: pgTimestamp(row.CreatedAt),
+	}
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLJobLeaseRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLJobLeaseRepository(q *mysqlgen.Queries) *MySQLJobLeaseRepository {
+	return &MySQLJobLeaseRepository{queries: q}
+}
+
+// Acquire reads the holder back after the upsert, because whether MySQL
+// counts an unchanged row as affected depends on the connection's flags.
+func (r *MySQLJobLeaseRepository) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	if err := r.queries.AcquireJobLease(ctx, mysqlgen.AcquireJobLeaseParams{
+		Name:      name,
+		Holder:    holder,
+		TtlMicros: ttl.Microseconds(),
+	}); err != nil {
+		return false, err
+	}
+	current, err := r.queries.GetJobLeaseHolder(ctx, name)
+	if err != nil {
+		return false, err
+	}
+	return current == holder, nil
+}
+
+func (r *MySQLJobLeaseRepository) Release(ctx context.Context, name, holder string) error {
+	return r.queries.ReleaseJobLease(ctx, mysqlgen.ReleaseJobLeaseParams{
+		Name:   name,
+		Holder: holder,
+	})
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLLoginHistoryRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLLoginHistoryRepository(q *mysqlgen.Queries) *MySQLLoginHistoryRepository {
+	return &MySQLLoginHistoryRepository{queries: q}
+}
+
+func (r *MySQLLoginHistoryRepository) Record(ctx context.Context, attempt LoginAttempt) error {
+	params := mysqlgen.RecordLoginParams{
+		Email:     attempt.Email,
+		Succeeded: attempt.Succeeded,
+		Reason:    attempt.Reason,
+		IpAddress: attempt.IPAddress,
+		UserAgent: attempt.UserAgent,
+		Country:   attempt.Country,
+		City:      attempt.City,
+	}
+	if attempt.UserID != nil {
+		params.UserID = sql.NullInt64{Int64: *attempt.UserID, Valid: true}
+	}
+	return r.queries.RecordLogin(ctx, params)
+}
+
+func (r *MySQLLoginHistoryRepository) ListByUser(ctx context.Context, userID int64, limit int32) ([]generated.ListLoginHistoryByUserRow, error) {
+	rows, err := r.queries.ListLoginHistoryByUser(ctx, mysqlgen.ListLoginHistoryByUserParams{
+		UserID: sql.NullInt64{Int64: userID, Valid: true},
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	history := make([]generated.ListLoginHistoryByUserRow, 0, len(rows))
+	for _, row := range rows {
+		history = append(history, generated.ListLoginHistoryByUserRow{
+			ID:        row.ID,
+			Email:     row.Email,
+			Succeeded: row.Succeeded,
+			Reason:    row.Reason,
+			IpAddress: row.IpAddress,
+			UserAgent: row.UserAgent,
+			Country:   row.Country,
+			City:      row.City,
+			CreatedAt: pgTimestamp(row.CreatedAt),
+		})
+	}
+	return history, nil
+}
+
+func (r *MySQLLoginHistoryRepository) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	row, err := r.queries.LoginHistoryStats(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !row.Oldest.Valid {
+		return row.Total, nil, nil
+	}
+	return row.Total, &row.Oldest.Time, nil
+}
+
+func (r *MySQLLoginHistoryRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.PruneLoginHistory(ctx, before)
+}
+
+func (r *MySQLLoginHistoryRepository) FailedByDay(ctx context.Context, since time.Time) ([]generated.FailedLoginsByDayRow, error) {
+	rows, err := r.queries.FailedLoginsByDay(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	days := make([]generated.FailedLoginsByDayRow, 0, len(rows))
+	for _, row := range rows {
+		days = append(days, generated.FailedLoginsByDayRow(row))
+	}
+	return days, nil
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLLoginLockoutRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLLoginLockoutRepository(q *mysqlgen.Queries) *MySQLLoginLockoutRepository {
+	return &MySQLLoginLockoutRepository{queries: q}
+}
+
+// RecordFailure reads the count back after the upsert, as MySQL can't
+// return it.
+func (r *MySQLLoginLockoutRepository) RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (int, error) {
+	now = now.UTC()
+	if err := r.queries.RecordLoginFailure(ctx, mysqlgen.RecordLoginFailureParams{
+		LockKey:       key,
+		Now:           now,
+		ExpiredBefore: now.Add(-window),
+	}); err != nil {
+		return 0, mysqlError(err)
+	}
+	row, err := r.queries.GetLoginLockout(ctx, key)
+	if err != nil {
+		return 0, mysqlError(err)
+	}
+	return int(row.Failures), nil
+}
+
+func (r *MySQLLoginLockoutRepository) Lock(ctx context.Context, key string, until time.Time) error {
+	return mysqlError(r.queries.LockLogin(ctx, mysqlgen.LockLoginParams{
+		LockedUntil: sql.NullTime{Time: until.UTC(), Valid: true},
+		LockKey:     key,
+	}))
+}
+
+func (r *MySQLLoginLockoutRepository) LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, bool, error) {
+	row, err := r.queries.GetLoginLockout(WithPrimary(ctx), key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return time.Time{}, false, nil
+	}
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if !row.LockedUntil.Valid || !row.LockedUntil.Time.After(now.UTC()) {
+		return time.Time{}, false, nil
+	}
+	return row.LockedUntil.Time, true, nil
+}
+
+func (r *MySQLLoginLockoutRepository) Clear(ctx context.Context, key string) (bool, error) {
+	n, err := r.queries.ClearLoginLockout(ctx, key)
+	return n > 0, mysqlError(err)
+}
+
+func (r *MySQLLoginLockoutRepository) ListLocked(ctx context.Context, now time.Time) ([]generated.ListLoginLockoutsRow, error) {
+	rows, err := r.queries.ListLoginLockouts(ctx, sql.NullTime{Time: now.UTC(), Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	locks := make([]generated.ListLoginLockoutsRow, 0, len(rows))
+	for _, row := range rows {
+		locks = append(locks, generated.ListLoginLockoutsRow{
+			LockKey:     row.LockKey,
+			Failures:    row.Failures,
+			LockedUntil: pgtype.Timestamp{Time: row.LockedUntil.Time, Valid: row.LockedUntil.Valid},
+		})
+	}
+	return locks, nil
+}
+
+func (r *MySQLLoginLockoutRepository) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	row, err := r.queries.LoginLockoutStats(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !row.Oldest.Valid {
+		return row.Total, nil, nil
+	}
+	return row.Total, &row.Oldest.Time, nil
+}
+
+func (r *MySQLLoginLockoutRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	before = before.UTC()
+	return r.queries.DeleteExpiredLoginLockouts(ctx, mysqlgen.DeleteExpiredLoginLockoutsParams{
+		ExpiredBefore: before,
+		Now:           sql.NullTime{Time: before, Valid: true},
+	})
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLMagicLinkRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLMagicLinkRepository(q *mysqlgen.Queries) *MySQLMagicLinkRepository {
+	return &MySQLMagicLinkRepository{queries: q}
+}
+
+func (r *MySQLMagicLinkRepository) Redeem(ctx context.Context, tokenHash string, expiresAt time.Time) (bool, error) {
+	n, err := r.queries.RedeemMagicLink(ctx, mysqlgen.RedeemMagicLinkParams{
+		TokenHash: tokenHash,
+		ExpiresAt: expiresAt.UTC(),
+	})
+	if err != nil {
+		return false, mysqlError(err)
+	}
+	return n == 1, nil
+}
+
+func (r *MySQLMagicLinkRepository) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	row, err := r.queries.MagicLinkRedemptionStats(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !row.Oldest.Valid {
+		return row.Total, nil, nil
+	}
+	return row.Total, &row.Oldest.Time, nil
+}
+
+func (r *MySQLMagicLinkRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.DeleteExpiredMagicLinkRedemptions(ctx, before.UTC())
+}
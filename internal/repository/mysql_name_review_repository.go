@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLNameReviewRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLNameReviewRepository(q *mysqlgen.Queries) *MySQLNameReviewRepository {
+	return &MySQLNameReviewRepository{queries: q}
+}
+
+func (r *MySQLNameReviewRepository) Create(ctx context.Context, userID int64, name, matched string) (generated.NameReview, error) {
+	id, err := r.queries.CreateNameReview(ctx, mysqlgen.CreateNameReviewParams{
+		UserID:  userID,
+		Name:    name,
+		Matched: matched,
+	})
+	if err != nil {
+		return generated.NameReview{}, mysqlError(err)
+	}
+	return r.Get(ctx, id)
+}
+
+func (r *MySQLNameReviewRepository) Get(ctx context.Context, id int64) (generated.NameReview, error) {
+	row, err := r.queries.GetNameReview(ctx, id)
+	if err != nil {
+		return generated.NameReview{}, mysqlError(err)
+	}
+	return nameReview(row), nil
+}
+
+func (r *MySQLNameReviewRepository) List(ctx context.Context, status string, limit int32) ([]generated.NameReview, error) {
+	rows, err := r.queries.ListNameReviews(ctx, mysqlgen.ListNameReviewsParams{
+		Status: status,
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	reviews := make([]generated.NameReview, 0, len(rows))
+	for _, row := range rows {
+		reviews = append(reviews, nameReview(row))
+	}
+	return reviews, nil
+}
+
+func (r *MySQLNameReviewRepository) Resolve(ctx context.Context, id int64, status string, reviewerID int64) (bool, error) {
+	n, err := r.queries.ResolveNameReview(ctx, mysqlgen.ResolveNameReviewParams{
+		Status:     status,
+		ReviewedBy: sql.NullInt64{Int64: reviewerID, Valid: true},
+		ID:         id,
+	})
+	return n > 0, err
+}
+
+func nameReview(row mysqlgen.NameReview) generated.NameReview {
+	review := generated.NameReview{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		Name:       row.Name,
+		Matched:    row.Matched,
+		Status:     row.Status,
+		CreatedAt:  pgTimestamp(row.CreatedAt),
+		ReviewedAt: pgNullTimestamp(row.ReviewedAt),
+	}
+	review.ReviewedBy.Int64, review.ReviewedBy.Valid = row.ReviewedBy.Int64, row.ReviewedBy.Valid
+	return review
+}
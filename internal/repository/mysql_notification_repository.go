@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLNotificationRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLNotificationRepository(q *mysqlgen.Queries) *MySQLNotificationRepository {
+	return &MySQLNotificationRepository{queries: q}
+}
+
+func (r *MySQLNotificationRepository) CreateRule(ctx context.Context, rule generated.NotificationRule) (generated.NotificationRule, error) {
+	id, err := r.queries.CreateNotificationRule(ctx, mysqlgen.CreateNotificationRuleParams{
+		EventType:  rule.EventType,
+		Channel:    rule.Channel,
+		Audience:   rule.Audience,
+		Template:   rule.Template,
+		WebhookUrl: rule.WebhookUrl,
+		Enabled:    rule.Enabled,
+	})
+	if err != nil {
+		return generated.NotificationRule{}, mysqlError(err)
+	}
+	return r.GetRule(ctx, id)
+}
+
+func (r *MySQLNotificationRepository) GetRule(ctx context.Context, id int64) (generated.NotificationRule, error) {
+	row, err := r.queries.GetNotificationRule(ctx, id)
+	if err != nil {
+		return generated.NotificationRule{}, mysqlError(err)
+	}
+	return notificationRule(row), nil
+}
+
+func (r *MySQLNotificationRepository) ListRules(ctx context.Context) ([]generated.NotificationRule, error) {
+	rows, err := r.queries.ListNotificationRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return notificationRules(rows), nil
+}
+
+func (r *MySQLNotificationRepository) ListRulesForEvent(ctx context.Context, eventType string) ([]generated.NotificationRule, error) {
+	rows, err := r.queries.ListNotificationRulesForEvent(ctx, eventType)
+	if err != nil {
+		return nil, err
+	}
+	return notificationRules(rows), nil
+}
+
+func (r *MySQLNotificationRepository) UpdateRule(ctx context.Context, rule generated.NotificationRule) (bool, error) {
+	n, err := r.queries.UpdateNotificationRule(ctx, mysqlgen.UpdateNotificationRuleParams{
+		EventType:  rule.EventType,
+		Channel:    rule.Channel,
+		Audience:   rule.Audience,
+		Template:   rule.Template,
+		WebhookUrl: rule.WebhookUrl,
+		Enabled:    rule.Enabled,
+		ID:         rule.ID,
+	})
+	if err != nil {
+		return false, mysqlError(err)
+	}
+	if n > 0 {
+		return true, nil
+	}
+	// MySQL counts changed rows only, and a rule updated twice within a
+	// second with the same values doesn't change.
+	if _, err := r.queries.GetNotificationRule(ctx, rule.ID); err != nil {
+		if err := mysqlError(err); !errors.Is(err, pgx.ErrNoRows) {
+			return false, err
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+func (r *MySQLNotificationRepository) DeleteRule(ctx context.Context, id int64) (bool, error) {
+	n, err := r.queries.DeleteNotificationRule(ctx, id)
+	return n > 0, mysqlError(err)
+}
+
+func (r *MySQLNotificationRepository) ListPreferences(ctx context.Context, userID int64) ([]generated.NotificationPreference, error) {
+	rows, err := r.queries.ListNotificationPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	prefs := make([]generated.NotificationPreference, 0, len(rows))
+	for _, row := range rows {
+		prefs = append(prefs, generated.NotificationPreference(row))
+	}
+	return prefs, nil
+}
+
+func (r *MySQLNotificationRepository) SetPreference(ctx context.Context, pref generated.NotificationPreference) error {
+	return mysqlError(r.queries.SetNotificationPreference(ctx, mysqlgen.SetNotificationPreferenceParams(pref)))
+}
+
+func (r *MySQLNotificationRepository) Create(ctx context.Context, userID int64, eventType, title, details string) error {
+	return mysqlError(r.queries.CreateNotification(ctx, mysqlgen.CreateNotificationParams{
+		UserID:    userID,
+		EventType: eventType,
+		Title:     title,
+		Details:   details,
+	}))
+}
+
+func (r *MySQLNotificationRepository) List(ctx context.Context, userID int64, limit int32) ([]generated.Notification, error) {
+	rows, err := r.queries.ListNotifications(ctx, mysqlgen.ListNotificationsParams{
+		UserID: userID,
+		Limit:  limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	notifications := make([]generated.Notification, 0, len(rows))
+	for _, row := range rows {
+		notifications = append(notifications, generated.Notification{
+			ID:        row.ID,
+			UserID:    row.UserID,
+			EventType: row.EventType,
+			Title:     row.Title,
+			Details:   row.Details,
+			CreatedAt: pgTimestamp(row.CreatedAt),
+			ReadAt:    pgNullTimestamp(row.ReadAt),
+		})
+	}
+	return notifications, nil
+}
+
+func (r *MySQLNotificationRepository) MarkRead(ctx context.Context, id, userID int64) (bool, error) {
+	n, err := r.queries.MarkNotificationRead(ctx, mysqlgen.MarkNotificationReadParams{ID: id, UserID: userID})
+	return n > 0, mysqlError(err)
+}
+
+func (r *MySQLNotificationRepository) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	row, err := r.queries.NotificationStats(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !row.Oldest.Valid {
+		return row.Total, nil, nil
+	}
+	return row.Total, &row.Oldest.Time, nil
+}
+
+func (r *MySQLNotificationRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.PruneNotifications(ctx, before.UTC())
+}
+
+func notificationRule(row mysqlgen.NotificationRule) generated.NotificationRule {
+	return generated.NotificationRule{
+		ID:         row.ID,
+		EventType:  row.EventType,
+		Channel:    row.Channel,
+		Audience:   row.Audience,
+		Template:   row.Template,
+		WebhookUrl: row.WebhookUrl,
+		Enabled:    row.Enabled,
+		CreatedAt:  pgTimestamp(row.CreatedAt),
+		UpdatedAt:  pgTimestamp(row.UpdatedAt),
+	}
+}
+
+func notificationRules(rows []mysqlgen.NotificationRule) []generated.NotificationRule {
+	rules := make([]generated.NotificationRule, 0, len(rows))
+	for _, row := range rows {
+		rules = append(rules, notificationRule(row))
+	}
+	return rules
+}
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLOrganizationRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLOrganizationRepository(q *mysqlgen.Queries) *MySQLOrganizationRepository {
+	return &MySQLOrganizationRepository{queries: q}
+}
+
+func (r *MySQLOrganizationRepository) Create(ctx context.Context, name string) (generated.Organization, error) {
+	id, err := r.queries.CreateOrganization(ctx, name)
+	if err != nil {
+		return generated.Organization{}, mysqlError(err)
+	}
+	return r.Get(ctx, id)
+}
+
+func (r *MySQLOrganizationRepository) Get(ctx context.Context, id int64) (generated.Organization, error) {
+	row, err := r.queries.GetOrganization(ctx, id)
+	if err != nil {
+		return generated.Organization{}, mysqlError(err)
+	}
+	return organization(row), nil
+}
+
+func (r *MySQLOrganizationRepository) List(ctx context.Context) ([]generated.Organization, error) {
+	rows, err := r.queries.ListOrganizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	orgs := make([]generated.Organization, 0, len(rows))
+	for _, row := range rows {
+		orgs = append(orgs, organization(row))
+	}
+	return orgs, nil
+}
+
+func (r *MySQLOrganizationRepository) SetMember(ctx context.Context, userID, orgID int64) error {
+	return mysqlError(r.queries.SetOrganizationMember(ctx, mysqlgen.SetOrganizationMemberParams{
+		UserID: userID,
+		OrgID:  orgID,
+	}))
+}
+
+func (r *MySQLOrganizationRepository) RemoveMember(ctx context.Context, userID int64) (bool, error) {
+	n, err := r.queries.DeleteOrganizationMember(ctx, userID)
+	return n > 0, err
+}
+
+func (r *MySQLOrganizationRepository) MemberOf(ctx context.Context, userID int64) (int64, error) {
+	orgID, err := r.queries.GetOrganizationMembership(ctx, userID)
+	return orgID, mysqlError(err)
+}
+
+func (r *MySQLOrganizationRepository) CountSeats(ctx context.Context, orgID, exceptUserID int64) (int64, error) {
+	return r.queries.CountOrganizationSeats(ctx, mysqlgen.CountOrganizationSeatsParams{
+		OrgID:        orgID,
+		ExceptUserID: exceptUserID,
+	})
+}
+
+func (r *MySQLOrganizationRepository) AggregateUsage(ctx context.Context, day time.Time) error {
+	return r.queries.AggregateOrganizationUsage(ctx, day)
+}
+
+func (r *MySQLOrganizationRepository) AddAPICalls(ctx context.Context, userID int64, day time.Time, calls int64) error {
+	return r.queries.AddOrganizationAPICalls(ctx, mysqlgen.AddOrganizationAPICallsParams{
+		Day:    day,
+		Calls:  calls,
+		UserID: userID,
+	})
+}
+
+func (r *MySQLOrganizationRepository) Usage(ctx context.Context, orgID int64, from, to time.Time) ([]generated.OrganizationUsage, error) {
+	rows, err := r.queries.ListOrganizationUsage(ctx, mysqlgen.ListOrganizationUsageParams{
+		OrgID:   orgID,
+		FromDay: from,
+		ToDay:   to,
+	})
+	if err != nil {
+		return nil, err
+	}
+	usage := make([]generated.OrganizationUsage, 0, len(rows))
+	for _, row := range rows {
+		usage = append(usage, generated.OrganizationUsage{
+			OrgID:        row.OrgID,
+			Day:          pgDate(row.Day),
+			Seats:        row.Seats,
+			ApiCalls:     row.ApiCalls,
+			StorageBytes: row.StorageBytes,
+		})
+	}
+	return usage, nil
+}
+
+func organization(row mysqlgen.Organization) generated.Organization {
+	return generated.Organization{
+		ID:        row.ID,
+		Name:      row.Name,
+		CreatedAt: pgTimestamp(row.CreatedAt),
+	}
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLProfileRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLProfileRepository(q *mysqlgen.Queries) *MySQLProfileRepository {
+	return &MySQLProfileRepository{queries: q}
+}
+
+func (r *MySQLProfileRepository) Get(ctx context.Context, userID int64) (generated.UserProfile, error) {
+	row, err := r.queries.GetUserProfile(ctx, userID)
+	if err != nil {
+		return generated.UserProfile{}, mysqlError(err)
+	}
+	return generated.UserProfile{
+		UserID:    row.UserID,
+		Bio:       row.Bio,
+		Phone:     row.Phone,
+		AvatarUrl: row.AvatarUrl,
+		Locale:    row.Locale,
+		Timezone:  row.Timezone,
+		UpdatedAt: pgTimestamp(row.UpdatedAt),
+		AvatarKey: row.AvatarKey,
+	}, nil
+}
+
+func (r *MySQLProfileRepository) Save(ctx context.Context, profile generated.UserProfile) (generated.UserProfile, error) {
+	err := r.queries.UpsertUserProfile(ctx, mysqlgen.UpsertUserProfileParams{
+		UserID:    profile.UserID,
+		Bio:       profile.Bio,
+		Phone:     profile.Phone,
+		AvatarUrl: profile.AvatarUrl,
+		Locale:    profile.Locale,
+		Timezone:  profile.Timezone,
+	})
+	if err != nil {
+		return generated.UserProfile{}, mysqlError(err)
+	}
+	return r.Get(ctx, profile.UserID)
+}
+
+func (r *MySQLProfileRepository) SetAvatarKey(ctx context.Context, userID int64, key string) error {
+	return mysqlError(r.queries.SetUserProfileAvatarKey(ctx, mysqlgen.SetUserProfileAvatarKeyParams{UserID: userID, AvatarKey: key}))
+}
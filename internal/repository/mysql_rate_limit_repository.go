@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLRateLimitRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLRateLimitRepository(q *mysqlgen.Queries) *MySQLRateLimitRepository {
+	return &MySQLRateLimitRepository{queries: q}
+}
+
+// Take reads the counter back after the upsert, as MySQL can't return it.
+// Requests from the same client in between may be counted in what it
+// reads.
+func (r *MySQLRateLimitRepository) Take(ctx context.Context, client string, now time.Time, window time.Duration, max int) (int, time.Time, bool, error) {
+	now = now.UTC()
+	if err := r.queries.TakeRateLimit(ctx, mysqlgen.TakeRateLimitParams{
+		Client:        client,
+		Now:           now,
+		ExpiredBefore: now.Add(-window),
+		MaxHits:       int32(max),
+	}); err != nil {
+		return 0, time.Time{}, false, mysqlError(err)
+	}
+	row, err := r.queries.GetRateLimit(ctx, client)
+	if err != nil {
+		return 0, time.Time{}, false, err
+	}
+	return takenHits(int(row.Hits), max), row.WindowStart, int(row.Hits) <= max, nil
+}
+
+func (r *MySQLRateLimitRepository) Count(ctx context.Context, client string, now time.Time, window time.Duration) (int, time.Time, error) {
+	now = now.UTC()
+	row, err := r.queries.GetRateLimit(ctx, client)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && !row.WindowStart.After(now.Add(-window))) {
+		return 0, now, nil
+	}
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return int(row.Hits), row.WindowStart, nil
+}
+
+func (r *MySQLRateLimitRepository) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	row, err := r.queries.RateLimitStats(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !row.Oldest.Valid {
+		return row.Total, nil, nil
+	}
+	return row.Total, &row.Oldest.Time, nil
+}
+
+func (r *MySQLRateLimitRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.DeleteExpiredRateLimits(ctx, before.UTC())
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLReferralRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLReferralRepository(q *mysqlgen.Queries) *MySQLReferralRepository {
+	return &MySQLReferralRepository{queries: q}
+}
+
+func (r *MySQLReferralRepository) Code(ctx context.Context, userID int64) (string, error) {
+	code, err := r.queries.GetReferralCode(ctx, userID)
+	if err != nil {
+		return "", mysqlError(err)
+	}
+	return code, nil
+}
+
+func (r *MySQLReferralRepository) CreateCode(ctx context.Context, userID int64, code string) (bool, error) {
+	n, err := r.queries.CreateReferralCode(ctx, mysqlgen.CreateReferralCodeParams{
+		UserID: userID,
+		Code:   code,
+	})
+	return n > 0, err
+}
+
+func (r *MySQLReferralRepository) Owner(ctx context.Context, code string) (int64, error) {
+	userID, err := r.queries.GetReferralCodeOwner(ctx, code)
+	if err != nil {
+		return 0, mysqlError(err)
+	}
+	return userID, nil
+}
+
+func (r *MySQLReferralRepository) Record(ctx context.Context, referrerID, referredID int64) (bool, error) {
+	n, err := r.queries.CreateReferral(ctx, mysqlgen.CreateReferralParams{
+		ReferrerID: referrerID,
+		ReferredID: referredID,
+	})
+	return n > 0, err
+}
+
+func (r *MySQLReferralRepository) Stats(ctx context.Context, referrerID int64, since time.Time) (generated.ReferralStatsRow, error) {
+	row, err := r.queries.ReferralStats(ctx, mysqlgen.ReferralStatsParams{
+		Since:      since,
+		ReferrerID: referrerID,
+	})
+	if err != nil {
+		return generated.ReferralStatsRow{}, err
+	}
+	return generated.ReferralStatsRow{Total: row.Total, Recent: row.Recent}, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLRefreshTokenRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLRefreshTokenRepository(q *mysqlgen.Queries) *MySQLRefreshTokenRepository {
+	return &MySQLRefreshTokenRepository{queries: q}
+}
+
+func (r *MySQLRefreshTokenRepository) Create(ctx context.Context, userID int64, tokenHash, family string, createdAt, expiresAt time.Time) error {
+	return mysqlError(r.queries.CreateRefreshToken(ctx, mysqlgen.CreateRefreshTokenParams{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		Family:    family,
+		CreatedAt: createdAt.UTC(),
+		ExpiresAt: expiresAt.UTC(),
+	}))
+}
+
+func (r *MySQLRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (generated.GetRefreshTokenByHashRow, error) {
+	row, err := r.queries.GetRefreshTokenByHash(ctx, tokenHash)
+	if err != nil {
+		return generated.GetRefreshTokenByHashRow{}, mysqlError(err)
+	}
+	return generated.GetRefreshTokenByHashRow{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		Family:    row.Family,
+		CreatedAt: pgTimestamp(row.CreatedAt),
+		ExpiresAt: pgTimestamp(row.ExpiresAt),
+		RevokedAt: pgNullTimestamp(row.RevokedAt),
+	}, nil
+}
+
+func (r *MySQLRefreshTokenRepository) Revoke(ctx context.Context, id int64) (bool, error) {
+	n, err := r.queries.RevokeRefreshToken(ctx, id)
+	return n > 0, mysqlError(err)
+}
+
+func (r *MySQLRefreshTokenRepository) RevokeFamily(ctx context.Context, family string) error {
+	return mysqlError(r.queries.RevokeRefreshTokenFamily(ctx, family))
+}
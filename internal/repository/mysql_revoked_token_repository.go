@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLRevokedTokenRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLRevokedTokenRepository(q *mysqlgen.Queries) *MySQLRevokedTokenRepository {
+	return &MySQLRevokedTokenRepository{queries: q}
+}
+
+func (r *MySQLRevokedTokenRepository) Revoke(ctx context.Context, jti string, userID int64, expiresAt time.Time) error {
+	return mysqlError(r.queries.RevokeToken(ctx, mysqlgen.RevokeTokenParams{
+		Jti:       jti,
+		UserID:    userID,
+		ExpiresAt: expiresAt.UTC(),
+	}))
+}
+
+func (r *MySQLRevokedTokenRepository) List(ctx context.Context, now time.Time) (map[string]time.Time, error) {
+	rows, err := r.queries.ListRevokedTokens(ctx, now.UTC())
+	if err != nil {
+		return nil, err
+	}
+	revoked := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		revoked[row.Jti] = row.ExpiresAt
+	}
+	return revoked, nil
+}
+
+func (r *MySQLRevokedTokenRepository) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	row, err := r.queries.RevokedTokenStats(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !row.Oldest.Valid {
+		return row.Total, nil, nil
+	}
+	return row.Total, &row.Oldest.Time, nil
+}
+
+func (r *MySQLRevokedTokenRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.PruneRevokedTokens(ctx, before.UTC())
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLSecurityEventRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLSecurityEventRepository(q *mysqlgen.Queries) *MySQLSecurityEventRepository {
+	return &MySQLSecurityEventRepository{queries: q}
+}
+
+func (r *MySQLSecurityEventRepository) Append(ctx context.Context, e generated.SecurityEvent) (int64, error) {
+	id, err := r.queries.AppendSecurityEvent(ctx, mysqlgen.AppendSecurityEventParams{
+		EventType: e.EventType,
+		ActorID:   sql.NullInt64{Int64: e.ActorID.Int64, Valid: e.ActorID.Valid},
+		TargetID:  sql.NullInt64{Int64: e.TargetID.Int64, Valid: e.TargetID.Valid},
+		Details:   e.Details,
+		IpAddress: e.IpAddress,
+		CreatedAt: e.CreatedAt.Time,
+		PrevHash:  e.PrevHash,
+		Hash:      e.Hash,
+	})
+	return id, mysqlError(err)
+}
+
+func (r *MySQLSecurityEventRepository) LastHash(ctx context.Context) (string, error) {
+	hash, err := r.queries.GetLastSecurityEventHash(ctx)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return hash, err
+}
+
+func (r *MySQLSecurityEventRepository) ListAfter(ctx context.Context, afterID int64, limit int32) ([]generated.SecurityEvent, error) {
+	rows, err := r.queries.ListSecurityEventsAfter(ctx, mysqlgen.ListSecurityEventsAfterParams{
+		ID:    afterID,
+		Limit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	events := make([]generated.SecurityEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, generated.SecurityEvent{
+			ID:        row.ID,
+			EventType: row.EventType,
+			ActorID:   pgtype.Int8{Int64: row.ActorID.Int64, Valid: row.ActorID.Valid},
+			TargetID:  pgtype.Int8{Int64: row.TargetID.Int64, Valid: row.TargetID.Valid},
+			Details:   row.Details,
+			IpAddress: row.IpAddress,
+			CreatedAt: pgTimestamp(row.CreatedAt),
+			PrevHash:  row.PrevHash,
+			Hash:      row.Hash,
+		})
+	}
+	return events, nil
+}
+
+func (r *MySQLSecurityEventRepository) List(ctx context.Context, beforeID int64, limit int32) ([]generated.ListSecurityEventsRow, error) {
+	rows, err := r.queries.ListSecurityEvents(ctx, mysqlgen.ListSecurityEventsParams{
+		ID:    beforeID,
+		Limit: limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	events := make([]generated.ListSecurityEventsRow, 0, len(rows))
+	for _, row := range rows {
+		event := generated.ListSecurityEventsRow{
+			ID:        row.ID,
+			EventType: row.EventType,
+			Details:   row.Details,
+			IpAddress: row.IpAddress,
+			CreatedAt: pgTimestamp(row.CreatedAt),
+			Hash:      row.Hash,
+		}
+		if row.ActorPublicID.Valid {
+			event.ActorPublicID = pgUUID(row.ActorPublicID.String)
+		}
+		if row.TargetPublicID.Valid {
+			event.TargetPublicID = pgUUID(row.TargetPublicID.String)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
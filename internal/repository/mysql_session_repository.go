@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLSessionRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLSessionRepository(q *mysqlgen.Queries) *MySQLSessionRepository {
+	return &MySQLSessionRepository{queries: q}
+}
+
+func (r *MySQLSessionRepository) Create(ctx context.Context, id string, userID int64, ipAddress, userAgent string, createdAt, expiresAt time.Time) error {
+	return mysqlError(r.queries.CreateSession(ctx, mysqlgen.CreateSessionParams{
+		ID:        id,
+		UserID:    userID,
+		IpAddress: ipAddress,
+		UserAgent: userAgent,
+		CreatedAt: createdAt.UTC(),
+		ExpiresAt: expiresAt.UTC(),
+	}))
+}
+
+func (r *MySQLSessionRepository) ListActive(ctx context.Context, userID int64, now time.Time) ([]generated.ListActiveSessionsRow, error) {
+	rows, err := r.queries.ListActiveSessions(ctx, mysqlgen.ListActiveSessionsParams{
+		UserID:    userID,
+		ExpiresAt: now.UTC(),
+	})
+	if err != nil {
+		return nil, mysqlError(err)
+	}
+	sessions := make([]generated.ListActiveSessionsRow, len(rows))
+	for i, row := range rows {
+		sessions[i] = generated.ListActiveSessionsRow{
+			ID:        row.ID,
+			IpAddress: row.IpAddress,
+			UserAgent: row.UserAgent,
+			CreatedAt: pgTimestamp(row.CreatedAt),
+			ExpiresAt: pgTimestamp(row.ExpiresAt),
+		}
+	}
+	return sessions, nil
+}
+
+func (r *MySQLSessionRepository) Extend(ctx context.Context, id string, expiresAt time.Time) error {
+	return mysqlError(r.queries.ExtendSession(ctx, mysqlgen.ExtendSessionParams{
+		ExpiresAt: expiresAt.UTC(),
+		ID:        id,
+	}))
+}
+
+func (r *MySQLSessionRepository) Revoke(ctx context.Context, id string, userID int64) (bool, error) {
+	n, err := r.queries.RevokeSession(ctx, mysqlgen.RevokeSessionParams{ID: id, UserID: userID})
+	return n > 0, mysqlError(err)
+}
+
+func (r *MySQLSessionRepository) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	row, err := r.queries.SessionStats(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !row.Oldest.Valid {
+		return row.Total, nil, nil
+	}
+	return row.Total, &row.Oldest.Time, nil
+}
+
+func (r *MySQLSessionRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.PruneSessions(ctx, before.UTC())
+}
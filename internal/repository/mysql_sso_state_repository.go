@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLSSOStateRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLSSOStateRepository(q *mysqlgen.Queries) *MySQLSSOStateRepository {
+	return &MySQLSSOStateRepository{queries: q}
+}
+
+func (r *MySQLSSOStateRepository) Save(ctx context.Context, stateHash string, login SSOPendingLogin) error {
+	err := r.queries.CreateSSOPendingLogin(ctx, mysqlgen.CreateSSOPendingLoginParams{
+		StateHash:    stateHash,
+		Nonce:        login.Nonce,
+		CodeVerifier: login.CodeVerifier,
+		ExpiresAt:    login.ExpiresAt.UTC(),
+	})
+	return mysqlError(err)
+}
+
+// Take reads the login and then deletes it; only the caller whose delete
+// removes the row gets it back.
+func (r *MySQLSSOStateRepository) Take(ctx context.Context, stateHash string) (SSOPendingLogin, error) {
+	row, err := r.queries.GetSSOPendingLogin(ctx, stateHash)
+	if err != nil {
+		return SSOPendingLogin{}, mysqlError(err)
+	}
+	n, err := r.queries.DeleteSSOPendingLogin(ctx, stateHash)
+	if err != nil {
+		return SSOPendingLogin{}, mysqlError(err)
+	}
+	if n == 0 {
+		return SSOPendingLogin{}, pgx.ErrNoRows
+	}
+	return SSOPendingLogin{
+		Nonce:        row.Nonce,
+		CodeVerifier: row.CodeVerifier,
+		ExpiresAt:    row.ExpiresAt,
+	}, nil
+}
+
+func (r *MySQLSSOStateRepository) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	row, err := r.queries.SSOPendingLoginStats(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !row.Oldest.Valid {
+		return row.Total, nil, nil
+	}
+	return row.Total, &row.Oldest.Time, nil
+}
+
+func (r *MySQLSSOStateRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.DeleteExpiredSSOPendingLogins(ctx, before.UTC())
+}
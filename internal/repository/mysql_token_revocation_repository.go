@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLTokenRevocationRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLTokenRevocationRepository(q *mysqlgen.Queries) *MySQLTokenRevocationRepository {
+	return &MySQLTokenRevocationRepository{queries: q}
+}
+
+func (r *MySQLTokenRevocationRepository) Revoke(ctx context.Context, userID int64, at time.Time) error {
+	if err := r.queries.RevokeUserTokens(ctx, mysqlgen.RevokeUserTokensParams{
+		UserID:    userID,
+		RevokedAt: at.UTC(),
+	}); err != nil {
+		return mysqlError(err)
+	}
+	return nil
+}
+
+func (r *MySQLTokenRevocationRepository) List(ctx context.Context) (map[int64]time.Time, error) {
+	rows, err := r.queries.ListTokenRevocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	revoked := make(map[int64]time.Time, len(rows))
+	for _, row := range rows {
+		revoked[row.UserID] = row.RevokedAt
+	}
+	return revoked, nil
+}
@@ -0,0 +1,552 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+// MySQLUserRepository implements UserStore on MySQL 8. MySQL has no
+// RETURNING clause, so writes are followed by a read of the affected row.
+type MySQLUserRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLUserRepository(q *mysqlgen.Queries) *MySQLUserRepository {
+	return &MySQLUserRepository{queries: q}
+}
+
+func (r *MySQLUserRepository) Create(ctx context.Context, name, source string, dob time.Time) (generated.CreateUserRow, error) {
+	return r.CreateWithAuth(ctx, name, "", "", "", source, dob)
+}
+
+func (r *MySQLUserRepository) CreateWithAuth(ctx context.Context, name, email, passwordHash, role, source string, dob time.Time) (generated.CreateUserRow, error) {
+	id, err := r.queries.CreateUser(ctx, mysqlgen.CreateUserParams{
+		Name:         name,
+		Dob:          dob,
+		Email:        email,
+		PasswordHash: passwordHash,
+		Role:         role,
+		SignupSource: source,
+	})
+	if err != nil {
+		return generated.CreateUserRow{}, mysqlError(err)
+	}
+	user, err := r.GetByID(ctx, id)
+	return generated.CreateUserRow(user), err
+}
+
+func (r *MySQLUserRepository) GetByID(ctx context.Context, id int64) (generated.GetUserByIDRow, error) {
+	row, err := r.queries.GetUserByID(ctx, id)
+	if err != nil {
+		return generated.GetUserByIDRow{}, mysqlError(err)
+	}
+	return generated.GetUserByIDRow{
+		ID:           row.ID,
+		Name:         row.Name,
+		Dob:          pgDate(row.Dob),
+		Email:        row.Email,
+		Role:         row.Role,
+		Active:       row.Active,
+		CreatedAt:    pgTimestamp(row.CreatedAt),
+		UpdatedAt:    pgTimestamp(row.UpdatedAt),
+		AccountType:  row.AccountType,
+		SignupSource: row.SignupSource,
+		PublicID:     pgUUID(row.PublicID),
+	}, nil
+}
+
+func (r *MySQLUserRepository) GetIDByPublicID(ctx context.Context, publicID string) (int64, error) {
+	id, err := r.queries.GetUserIDByPublicID(ctx, strings.ToLower(publicID))
+	if err != nil {
+		return 0, mysqlError(err)
+	}
+	return id, nil
+}
+
+func (r *MySQLUserRepository) GetByEmail(ctx context.Context, email string) (generated.User, error) {
+	row, err := r.queries.GetUserByEmail(ctx, email)
+	if err != nil {
+		return generated.User{}, mysqlError(err)
+	}
+	return generated.User{
+		ID:           row.ID,
+		Name:         row.Name,
+		Dob:          pgDate(row.Dob),
+		Email:        row.Email,
+		PasswordHash: row.PasswordHash,
+		Role:         row.Role,
+		CreatedAt:    pgTimestamp(row.CreatedAt),
+		UpdatedAt:    pgTimestamp(row.UpdatedAt),
+		Active:       row.Active,
+		AccountType:  row.AccountType,
+		SignupSource: row.SignupSource,
+		PublicID:     pgUUID(row.PublicID),
+	}, nil
+}
+
+func (r *MySQLUserRepository) LookupByEmail(ctx context.Context, prefix string, limit int32) ([]generated.LookupUsersByEmailRow, error) {
+	rows, err := r.queries.LookupUsersByEmail(ctx, mysqlgen.LookupUsersByEmailParams{
+		Pattern:  escapeLike(prefix) + "%",
+		RowLimit: limit,
+	})
+	if err != nil {
+		return nil, mysqlError(err)
+	}
+	users := make([]generated.LookupUsersByEmailRow, 0, len(rows))
+	for _, row := range rows {
+		users = append(users, generated.LookupUsersByEmailRow{
+			ID:          row.ID,
+			Name:        row.Name,
+			Email:       row.Email,
+			Role:        row.Role,
+			Active:      row.Active,
+			AccountType: row.AccountType,
+			PublicID:    pgUUID(row.PublicID),
+		})
+	}
+	return users, nil
+}
+
+func (r *MySQLUserRepository) List(ctx context.Context) ([]generated.ListUsersRow, error) {
+	rows, err := r.queries.ListUsers(ctx)
+	if err != nil {
+		return nil, mysqlError(err)
+	}
+	users := make([]generated.ListUsersRow, 0, len(rows))
+	for _, row := range rows {
+		users = append(users, generated.ListUsersRow{
+			ID:           row.ID,
+			Name:         row.Name,
+			Dob:          pgDate(row.Dob),
+			Email:        row.Email,
+			Role:         row.Role,
+			Active:       row.Active,
+			CreatedAt:    pgTimestamp(row.CreatedAt),
+			UpdatedAt:    pgTimestamp(row.UpdatedAt),
+			AccountType:  row.AccountType,
+			SignupSource: row.SignupSource,
+			PublicID:     pgUUID(row.PublicID),
+		})
+	}
+	return users, nil
+}
+
+func (r *MySQLUserRepository) ListPaginated(ctx context.Context, limit, offset int32) ([]generated.ListUsersPaginatedRow, error) {
+	rows, err := r.queries.ListUsersPaginated(ctx, mysqlgen.ListUsersPaginatedParams{
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		return nil, mysqlError(err)
+	}
+	users := make([]generated.ListUsersPaginatedRow, 0, len(rows))
+	for _, row := range rows {
+		users = append(users, generated.ListUsersPaginatedRow{
+			ID:           row.ID,
+			Name:         row.Name,
+			Dob:          pgDate(row.Dob),
+			Email:        row.Email,
+			Role:         row.Role,
+			Active:       row.Active,
+			CreatedAt:    pgTimestamp(row.CreatedAt),
+			UpdatedAt:    pgTimestamp(row.UpdatedAt),
+			AccountType:  row.AccountType,
+			SignupSource: row.SignupSource,
+			PublicID:     pgUUID(row.PublicID),
+		})
+	}
+	return users, nil
+}
+
+func (r *MySQLUserRepository) ListBySignupSource(ctx context.Context, source string) ([]generated.ListUsersBySignupSourceRow, error) {
+	rows, err := r.queries.ListUsersBySignupSource(ctx, source)
+	if err != nil {
+		return nil, mysqlError(err)
+	}
+	users := make([]generated.ListUsersBySignupSourceRow, 0, len(rows))
+	for _, row := range rows {
+		users = append(users, generated.ListUsersBySignupSourceRow{
+			ID:           row.ID,
+			Name:         row.Name,
+			Dob:          pgDate(row.Dob),
+			Email:        row.Email,
+			Role:         row.Role,
+			Active:       row.Active,
+			CreatedAt:    pgTimestamp(row.CreatedAt),
+			UpdatedAt:    pgTimestamp(row.UpdatedAt),
+			AccountType:  row.AccountType,
+			SignupSource: row.SignupSource,
+			PublicID:     pgUUID(row.PublicID),
+		})
+	}
+	return users, nil
+}
+
+func (r *MySQLUserRepository) Count(ctx context.Context) (int64, error) {
+	return r.queries.CountUsers(ctx)
+}
+
+func (r *MySQLUserRepository) Search(ctx context.Context, filter UserFilter, sort []UserSortKey, limit, offset int32) ([]generated.SearchUsersRow, error) {
+	p := mysqlSearchUsersParams(filter)
+	sorts := userSortArgs(sort)
+	rows, err := r.queries.SearchUsers(ctx, mysqlgen.SearchUsersParams{
+		NamePattern:   p.NamePattern,
+		Email:         p.Email,
+		Role:          p.Role,
+		SignupSource:  p.SignupSource,
+		DobAfter:      p.DobAfter,
+		DobUntil:      p.DobUntil,
+		CreatedFrom:   p.CreatedFrom,
+		CreatedBefore: p.CreatedBefore,
+		OrgID:         p.OrgID,
+		Sort1:         sorts[0],
+		Sort2:         sorts[1],
+		Sort3:         sorts[2],
+		Limit:         limit,
+		Offset:        offset,
+	})
+	if err != nil {
+		return nil, err
+	}
+	users := make([]generated.SearchUsersRow, 0, len(rows))
+	for _, row := range rows {
+		users = append(users, generated.SearchUsersRow{
+			ID:           row.ID,
+			Name:         row.Name,
+			Dob:          pgDate(row.Dob),
+			Email:        row.Email,
+			Role:         row.Role,
+			Active:       row.Active,
+			CreatedAt:    pgTimestamp(row.CreatedAt),
+			UpdatedAt:    pgTimestamp(row.UpdatedAt),
+			AccountType:  row.AccountType,
+			SignupSource: row.SignupSource,
+			PublicID:     pgUUID(row.PublicID),
+		})
+	}
+	return users, nil
+}
+
+func (r *MySQLUserRepository) CountSearch(ctx context.Context, filter UserFilter) (int64, error) {
+	return r.queries.CountSearchUsers(ctx, mysqlSearchUsersParams(filter))
+}
+
+func mysqlSearchUsersParams(filter UserFilter) mysqlgen.CountSearchUsersParams {
+	p := mysqlgen.CountSearchUsersParams{
+		Email:         sql.NullString{String: filter.Email, Valid: filter.Email != ""},
+		Role:          sql.NullString{String: filter.Role, Valid: filter.Role != ""},
+		SignupSource:  sql.NullString{String: filter.SignupSource, Valid: filter.SignupSource != ""},
+		DobAfter:      sql.NullTime{Time: filter.DobAfter, Valid: !filter.DobAfter.IsZero()},
+		DobUntil:      sql.NullTime{Time: filter.DobUntil, Valid: !filter.DobUntil.IsZero()},
+		CreatedFrom:   sql.NullTime{Time: filter.CreatedFrom, Valid: !filter.CreatedFrom.IsZero()},
+		CreatedBefore: sql.NullTime{Time: filter.CreatedBefore, Valid: !filter.CreatedBefore.IsZero()},
+		OrgID:         sql.NullInt64{Int64: filter.OrgID, Valid: filter.OrgID != 0},
+	}
+	if filter.Name != "" {
+		p.NamePattern = sql.NullString{String: filter.namePattern(), Valid: true}
+	}
+	return p
+}
+
+func (r *MySQLUserRepository) Update(ctx context.Context, id int64, name string, dob time.Time) (generated.UpdateUserRow, error) {
+	if _, err := r.queries.UpdateUser(ctx, mysqlgen.UpdateUserParams{
+		Name: name,
+		Dob:  dob,
+		ID:   id,
+	}); err != nil {
+		return generated.UpdateUserRow{}, mysqlError(err)
+	}
+	user, err := r.GetByID(ctx, id)
+	return generated.UpdateUserRow(user), err
+}
+
+func (r *MySQLUserRepository) SoftDelete(ctx context.Context, id int64) error {
+	n, err := r.queries.SoftDeleteUser(ctx, id)
+	if err != nil {
+		return mysqlError(err)
+	}
+	if n == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *MySQLUserRepository) Restore(ctx context.Context, id int64) (generated.RestoreUserRow, error) {
+	n, err := r.queries.RestoreUser(ctx, id)
+	if err != nil {
+		return generated.RestoreUserRow{}, mysqlError(err)
+	}
+	if n == 0 {
+		return generated.RestoreUserRow{}, pgx.ErrNoRows
+	}
+	user, err := r.GetByID(ctx, id)
+	return generated.RestoreUserRow(user), err
+}
+
+func (r *MySQLUserRepository) HardDelete(ctx context.Context, id int64) error {
+	return mysqlError(r.queries.DeleteUser(ctx, id))
+}
+
+func (r *MySQLUserRepository) GetDeletedByID(ctx context.Context, id int64) (generated.GetDeletedUserByIDRow, error) {
+	row, err := r.queries.GetDeletedUserByID(ctx, id)
+	if err != nil {
+		return generated.GetDeletedUserByIDRow{}, mysqlError(err)
+	}
+	return generated.GetDeletedUserByIDRow{
+		ID:           row.ID,
+		Name:         row.Name,
+		Dob:          pgDate(row.Dob),
+		Email:        row.Email,
+		Role:         row.Role,
+		Active:       row.Active,
+		CreatedAt:    pgTimestamp(row.CreatedAt),
+		UpdatedAt:    pgTimestamp(row.UpdatedAt),
+		AccountType:  row.AccountType,
+		SignupSource: row.SignupSource,
+		PublicID:     pgUUID(row.PublicID),
+	}, nil
+}
+
+func (r *MySQLUserRepository) SetActive(ctx context.Context, id int64, active bool) (generated.SetUserActiveRow, error) {
+	if _, err := r.queries.SetUserActive(ctx, mysqlgen.SetUserActiveParams{
+		Active: active,
+		ID:     id,
+	}); err != nil {
+		return generated.SetUserActiveRow{}, mysqlError(err)
+	}
+	user, err := r.GetByID(ctx, id)
+	return generated.SetUserActiveRow(user), err
+}
+
+func (r *MySQLUserRepository) UpdateRole(ctx context.Context, id int64, role string) (generated.UpdateUserRoleRow, error) {
+	if _, err := r.queries.UpdateUserRole(ctx, mysqlgen.UpdateUserRoleParams{
+		Role: role,
+		ID:   id,
+	}); err != nil {
+		return generated.UpdateUserRoleRow{}, mysqlError(err)
+	}
+	user, err := r.GetByID(ctx, id)
+	return generated.UpdateUserRoleRow(user), err
+}
+
+func (r *MySQLUserRepository) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	n, err := r.queries.UpdateUserPassword(ctx, mysqlgen.UpdateUserPasswordParams{
+		PasswordHash: passwordHash,
+		ID:           id,
+	})
+	if err != nil {
+		return mysqlError(err)
+	}
+	if n == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *MySQLUserRepository) CreateServiceAccount(ctx context.Context, name, email, passwordHash, role string) (generated.CreateServiceAccountRow, error) {
+	id, err := r.queries.CreateServiceAccount(ctx, mysqlgen.CreateServiceAccountParams{
+		Name:         name,
+		Email:        email,
+		PasswordHash: passwordHash,
+		Role:         role,
+	})
+	if err != nil {
+		return generated.CreateServiceAccountRow{}, mysqlError(err)
+	}
+	user, err := r.GetByID(ctx, id)
+	return generated.CreateServiceAccountRow(user), err
+}
+
+func (r *MySQLUserRepository) ListServiceAccounts(ctx context.Context) ([]generated.ListServiceAccountsRow, error) {
+	rows, err := r.queries.ListServiceAccounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	accounts := make([]generated.ListServiceAccountsRow, 0, len(rows))
+	for _, row := range rows {
+		accounts = append(accounts, generated.ListServiceAccountsRow{
+			ID:           row.ID,
+			Name:         row.Name,
+			Dob:          pgDate(row.Dob),
+			Email:        row.Email,
+			Role:         row.Role,
+			Active:       row.Active,
+			CreatedAt:    pgTimestamp(row.CreatedAt),
+			UpdatedAt:    pgTimestamp(row.UpdatedAt),
+			AccountType:  row.AccountType,
+			SignupSource: row.SignupSource,
+			PublicID:     pgUUID(row.PublicID),
+		})
+	}
+	return accounts, nil
+}
+
+func (r *MySQLUserRepository) UsersByAgeBracket(ctx context.Context) ([]generated.UsersByAgeBracketRow, error) {
+	rows, err := r.queries.UsersByAgeBracket(ctx)
+	if err != nil {
+		return nil, err
+	}
+	brackets := make([]generated.UsersByAgeBracketRow, 0, len(rows))
+	for _, row := range rows {
+		brackets = append(brackets, generated.UsersByAgeBracketRow(row))
+	}
+	return brackets, nil
+}
+
+func (r *MySQLUserRepository) AgeDistribution(ctx context.Context, groupBy string, bucketSize int32) ([]generated.AgeDistributionRow, error) {
+	rows, err := r.queries.AgeDistribution(ctx, mysqlgen.AgeDistributionParams{GroupBy: groupBy, BucketSize: bucketSize})
+	if err != nil {
+		return nil, err
+	}
+	buckets := make([]generated.AgeDistributionRow, 0, len(rows))
+	for _, row := range rows {
+		buckets = append(buckets, generated.AgeDistributionRow{
+			Grp:         row.Grp,
+			BucketStart: int32(row.BucketStart),
+			UserCount:   row.UserCount,
+		})
+	}
+	return buckets, nil
+}
+
+func (r *MySQLUserRepository) SignupsByDay(ctx context.Context, since time.Time) ([]generated.SignupsByDayRow, error) {
+	rows, err := r.queries.SignupsByDay(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	days := make([]generated.SignupsByDayRow, 0, len(rows))
+	for _, row := range rows {
+		days = append(days, generated.SignupsByDayRow(row))
+	}
+	return days, nil
+}
+
+func (r *MySQLUserRepository) SignupsByMonth(ctx context.Context, since time.Time) ([]generated.SignupsByMonthRow, error) {
+	rows, err := r.queries.SignupsByMonth(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	months := make([]generated.SignupsByMonthRow, 0, len(rows))
+	for _, row := range rows {
+		months = append(months, generated.SignupsByMonthRow(row))
+	}
+	return months, nil
+}
+
+func (r *MySQLUserRepository) SignupsBySource(ctx context.Context, since time.Time) ([]generated.SignupsBySourceRow, error) {
+	rows, err := r.queries.SignupsBySource(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	sources := make([]generated.SignupsBySourceRow, 0, len(rows))
+	for _, row := range rows {
+		sources = append(sources, generated.SignupsBySourceRow(row))
+	}
+	return sources, nil
+}
+
+func (r *MySQLUserRepository) ListDeactivatedSince(ctx context.Context, since time.Time) ([]generated.ListDeactivatedUsersRow, error) {
+	rows, err := r.queries.ListDeactivatedUsers(ctx, since)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]generated.ListDeactivatedUsersRow, 0, len(rows))
+	for _, row := range rows {
+		users = append(users, generated.ListDeactivatedUsersRow{
+			ID:        row.ID,
+			Name:      row.Name,
+			Email:     row.Email,
+			UpdatedAt: pgTimestamp(row.UpdatedAt),
+		})
+	}
+	return users, nil
+}
+
+func (r *MySQLUserRepository) UpcomingBirthdays(ctx context.Context, from, to time.Time, limit int32) ([]generated.ListUpcomingBirthdaysRow, error) {
+	w := newBirthdayWindow(from, to)
+	rows, err := r.queries.ListUpcomingBirthdays(ctx, mysqlgen.ListUpcomingBirthdaysParams{
+		FromDay:  w.from,
+		ToDay:    w.to,
+		WrapFrom: w.wrapFrom,
+		WrapTo:   w.wrapTo,
+		Limit:    limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	users := make([]generated.ListUpcomingBirthdaysRow, 0, len(rows))
+	for _, row := range rows {
+		users = append(users, generated.ListUpcomingBirthdaysRow{
+			ID:       row.ID,
+			Name:     row.Name,
+			Dob:      pgDate(row.Dob),
+			PublicID: pgUUID(row.PublicID),
+		})
+	}
+	return users, nil
+}
+
+func (r *MySQLUserRepository) ListActiveAdmins(ctx context.Context) ([]generated.ListActiveAdminsRow, error) {
+	rows, err := r.queries.ListActiveAdmins(ctx)
+	if err != nil {
+		return nil, err
+	}
+	admins := make([]generated.ListActiveAdminsRow, 0, len(rows))
+	for _, row := range rows {
+		admins = append(admins, generated.ListActiveAdminsRow(row))
+	}
+	return admins, nil
+}
+
+// GetStats computes the aggregates live; MySQL has no materialized views.
+func (r *MySQLUserRepository) GetStats(ctx context.Context) (generated.GetUserStatsRow, error) {
+	row, err := r.queries.GetUserStats(ctx)
+	if err != nil {
+		return generated.GetUserStatsRow{}, err
+	}
+	return generated.GetUserStatsRow{
+		TotalUsers:        row.TotalUsers,
+		AdminUsers:        row.AdminUsers,
+		SignupsLast7Days:  row.SignupsLast7Days,
+		SignupsLast30Days: row.SignupsLast30Days,
+		AverageAge:        row.AverageAge,
+		RefreshedAt:       pgTimestamp(row.RefreshedAt),
+	}, nil
+}
+
+func (r *MySQLUserRepository) RefreshStats(ctx context.Context) error {
+	return nil
+}
+
+// mysqlError translates driver errors into the forms the Postgres
+// repositories return: pgx.ErrNoRows and *ConstraintError.
+func mysqlError(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return pgx.ErrNoRows
+	}
+	return mysqlConstraintError(err)
+}
+
+func pgDate(t time.Time) pgtype.Date {
+	return pgtype.Date{Time: t, Valid: !t.IsZero()}
+}
+
+// pgUUID parses the CHAR(36) MySQL stores public IDs in.
+func pgUUID(s string) pgtype.UUID {
+	var u pgtype.UUID
+	_ = u.Scan(s)
+	return u
+}
+
+func pgTimestamp(t time.Time) pgtype.Timestamp {
+	return pgtype.Timestamp{Time: t, Valid: !t.IsZero()}
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+)
+
+type MySQLWebAuthnCredentialRepository struct {
+	queries *mysqlgen.Queries
+}
+
+func NewMySQLWebAuthnCredentialRepository(q *mysqlgen.Queries) *MySQLWebAuthnCredentialRepository {
+	return &MySQLWebAuthnCredentialRepository{queries: q}
+}
+
+func (r *MySQLWebAuthnCredentialRepository) Create(ctx context.Context, userID int64, credentialID, publicKey []byte, signCount int64, name string) (generated.WebauthnCredential, error) {
+	err := r.queries.CreateWebAuthnCredential(ctx, mysqlgen.CreateWebAuthnCredentialParams{
+		UserID:       userID,
+		CredentialID: credentialID,
+		PublicKey:    publicKey,
+		SignCount:    signCount,
+		Name:         name,
+	})
+	if err != nil {
+		return generated.WebauthnCredential{}, mysqlError(err)
+	}
+	return r.Get(ctx, credentialID)
+}
+
+func (r *MySQLWebAuthnCredentialRepository) Get(ctx context.Context, credentialID []byte) (generated.WebauthnCredential, error) {
+	row, err := r.queries.GetWebAuthnCredential(ctx, credentialID)
+	if err != nil {
+		return generated.WebauthnCredential{}, mysqlError(err)
+	}
+	return webAuthnCredential(row), nil
+}
+
+func (r *MySQLWebAuthnCredentialRepository) ListByUser(ctx context.Context, userID int64) ([]generated.WebauthnCredential, error) {
+	rows, err := r.queries.ListWebAuthnCredentialsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	creds := make([]generated.WebauthnCredential, 0, len(rows))
+	for _, row := range rows {
+		creds = append(creds, webAuthnCredential(row))
+	}
+	return creds, nil
+}
+
+func (r *MySQLWebAuthnCredentialRepository) UpdateSignCount(ctx context.Context, id int64, signCount int64) error {
+	return r.queries.UpdateWebAuthnCredentialUse(ctx, mysqlgen.UpdateWebAuthnCredentialUseParams{
+		SignCount: signCount,
+		ID:        id,
+	})
+}
+
+func (r *MySQLWebAuthnCredentialRepository) Delete(ctx context.Context, id, userID int64) (bool, error) {
+	n, err := r.queries.DeleteWebAuthnCredential(ctx, mysqlgen.DeleteWebAuthnCredentialParams{
+		ID:     id,
+		UserID: userID,
+	})
+	return n > 0, err
+}
+
+func webAuthnCredential(row mysqlgen.WebauthnCredential) generated.WebauthnCredential {
+	return generated.WebauthnCredential{
+		ID:           row.ID,
+		UserID:       row.UserID,
+		CredentialID: row.CredentialID,
+		PublicKey:    row.PublicKey,
+		SignCount:    row.SignCount,
+		Name:         row.Name,
+		CreatedAt:    pgTimestamp(row.CreatedAt),
+		LastUsedAt:   pgNullTimestamp(row.LastUsedAt),
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// NameReviewStore holds user names the moderation filter flagged for a
+// human to look at.
+type NameReviewStore interface {
+	Create(ctx context.Context, userID int64, name, matched string) (generated.NameReview, error)
+	Get(ctx context.Context, id int64) (generated.NameReview, error)
+	List(ctx context.Context, status string, limit int32) ([]generated.NameReview, error)
+	// Resolve records a decision on a pending review. It reports false when
+	// the review doesn't exist or was already decided.
+	Resolve(ctx context.Context, id int64, status string, reviewerID int64) (bool, error)
+}
+
+var (
+	_ NameReviewStore = (*NameReviewRepository)(nil)
+	_ NameReviewStore = (*MySQLNameReviewRepository)(nil)
+	_ NameReviewStore = (*MemoryNameReviewStore)(nil)
+)
+
+type NameReviewRepository struct {
+	queries *generated.Queries
+}
+
+func NewNameReviewRepository(q *generated.Queries) *NameReviewRepository {
+	return &NameReviewRepository{queries: q}
+}
+
+func (r *NameReviewRepository) Create(ctx context.Context, userID int64, name, matched string) (generated.NameReview, error) {
+	row, err := r.queries.CreateNameReview(ctx, generated.CreateNameReviewParams{
+		UserID:  userID,
+		Name:    name,
+		Matched: matched,
+	})
+	return row, pgError(err)
+}
+
+func (r *NameReviewRepository) Get(ctx context.Context, id int64) (generated.NameReview, error) {
+	return r.queries.GetNameReview(ctx, id)
+}
+
+func (r *NameReviewRepository) List(ctx context.Context, status string, limit int32) ([]generated.NameReview, error) {
+	return r.queries.ListNameReviews(ctx, generated.ListNameReviewsParams{
+		Status: status,
+		Limit:  limit,
+	})
+}
+
+func (r *NameReviewRepository) Resolve(ctx context.Context, id int64, status string, reviewerID int64) (bool, error) {
+	n, err := r.queries.ResolveNameReview(ctx, generated.ResolveNameReviewParams{
+		ID:         id,
+		Status:     status,
+		ReviewedBy: pgtype.Int8{Int64: reviewerID, Valid: true},
+	})
+	return n > 0, pgError(err)
+}
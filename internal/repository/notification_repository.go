@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// NotificationStore keeps the rules that route events to notification
+// channels, the channels users have opted out of, and users' in-app
+// notifications.
+type NotificationStore interface {
+	CreateRule(ctx context.Context, rule generated.NotificationRule) (generated.NotificationRule, error)
+	GetRule(ctx context.Context, id int64) (generated.NotificationRule, error)
+	ListRules(ctx context.Context) ([]generated.NotificationRule, error)
+	// ListRulesForEvent returns the enabled rules for eventType.
+	ListRulesForEvent(ctx context.Context, eventType string) ([]generated.NotificationRule, error)
+	// UpdateRule replaces the rule with rule.ID. It reports false when
+	// there is no such rule.
+	UpdateRule(ctx context.Context, rule generated.NotificationRule) (bool, error)
+	DeleteRule(ctx context.Context, id int64) (bool, error)
+
+	ListPreferences(ctx context.Context, userID int64) ([]generated.NotificationPreference, error)
+	SetPreference(ctx context.Context, pref generated.NotificationPreference) error
+
+	Create(ctx context.Context, userID int64, eventType, title, details string) error
+	// List returns the user's notifications, newest first.
+	List(ctx context.Context, userID int64, limit int32) ([]generated.Notification, error)
+	// MarkRead reports whether the notification was the user's and unread.
+	MarkRead(ctx context.Context, id, userID int64) (bool, error)
+	RetentionStats(ctx context.Context) (int64, *time.Time, error)
+	PruneBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+var (
+	_ NotificationStore = (*NotificationRepository)(nil)
+	_ NotificationStore = (*MySQLNotificationRepository)(nil)
+	_ NotificationStore = (*MemoryNotificationStore)(nil)
+)
+
+type NotificationRepository struct {
+	queries *generated.Queries
+}
+
+func NewNotificationRepository(q *generated.Queries) *NotificationRepository {
+	return &NotificationRepository{queries: q}
+}
+
+func (r *NotificationRepository) CreateRule(ctx context.Context, rule generated.NotificationRule) (generated.NotificationRule, error) {
+	row, err := r.queries.CreateNotificationRule(ctx, generated.CreateNotificationRuleParams{
+		EventType:  rule.EventType,
+		Channel:    rule.Channel,
+		Audience:   rule.Audience,
+		Template:   rule.Template,
+		WebhookUrl: rule.WebhookUrl,
+		Enabled:    rule.Enabled,
+	})
+	return row, pgError(err)
+}
+
+func (r *NotificationRepository) GetRule(ctx context.Context, id int64) (generated.NotificationRule, error) {
+	return r.queries.GetNotificationRule(ctx, id)
+}
+
+func (r *NotificationRepository) ListRules(ctx context.Context) ([]generated.NotificationRule, error) {
+	return r.queries.ListNotificationRules(ctx)
+}
+
+func (r *NotificationRepository) ListRulesForEvent(ctx context.Context, eventType string) ([]generated.NotificationRule, error) {
+	return r.queries.ListNotificationRulesForEvent(ctx, eventType)
+}
+
+func (r *NotificationRepository) UpdateRule(ctx context.Context, rule generated.NotificationRule) (bool, error) {
+	n, err := r.queries.UpdateNotificationRule(ctx, generated.UpdateNotificationRuleParams{
+		ID:         rule.ID,
+		EventType:  rule.EventType,
+		Channel:    rule.Channel,
+		Audience:   rule.Audience,
+		Template:   rule.Template,
+		WebhookUrl: rule.WebhookUrl,
+		Enabled:    rule.Enabled,
+	})
+	return n > 0, pgError(err)
+}
+
+func (r *NotificationRepository) DeleteRule(ctx context.Context, id int64) (bool, error) {
+	n, err := r.queries.DeleteNotificationRule(ctx, id)
+	return n > 0, pgError(err)
+}
+
+func (r *NotificationRepository) ListPreferences(ctx context.Context, userID int64) ([]generated.NotificationPreference, error) {
+	return r.queries.ListNotificationPreferences(ctx, userID)
+}
+
+func (r *NotificationRepository) SetPreference(ctx context.Context, pref generated.NotificationPreference) error {
+	return pgError(r.queries.SetNotificationPreference(ctx, generated.SetNotificationPreferenceParams(pref)))
+}
+
+func (r *NotificationRepository) Create(ctx context.Context, userID int64, eventType, title, details string) error {
+	return pgError(r.queries.CreateNotification(ctx, generated.CreateNotificationParams{
+		UserID:    userID,
+		EventType: eventType,
+		Title:     title,
+		Details:   details,
+	}))
+}
+
+func (r *NotificationRepository) List(ctx context.Context, userID int64, limit int32) ([]generated.Notification, error) {
+	return r.queries.ListNotifications(ctx, generated.ListNotificationsParams{
+		UserID: userID,
+		Limit:  limit,
+	})
+}
+
+func (r *NotificationRepository) MarkRead(ctx context.Context, id, userID int64) (bool, error) {
+	n, err := r.queries.MarkNotificationRead(ctx, generated.MarkNotificationReadParams{ID: id, UserID: userID})
+	return n > 0, pgError(err)
+}
+
+// RetentionStats reports the notifications held and the oldest of them.
+func (r *NotificationRepository) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	row, err := r.queries.NotificationStats(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !row.Oldest.Valid {
+		return row.Total, nil, nil
+	}
+	return row.Total, &row.Oldest.Time, nil
+}
+
+// PruneBefore deletes notifications created before before.
+func (r *NotificationRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.PruneNotifications(ctx, pgtype.Timestamp{Time: before.UTC(), Valid: true})
+}
@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// OrganizationStore holds organizations, which organization each user
+// belongs to, and each organization's daily usage.
+type OrganizationStore interface {
+	Create(ctx context.Context, name string) (generated.Organization, error)
+	Get(ctx context.Context, id int64) (generated.Organization, error)
+	List(ctx context.Context) ([]generated.Organization, error)
+	// SetMember moves the user into the organization, out of any other.
+	SetMember(ctx context.Context, userID, orgID int64) error
+	// RemoveMember reports false when the user was in no organization.
+	RemoveMember(ctx context.Context, userID int64) (bool, error)
+	// MemberOf returns the user's organization, or pgx.ErrNoRows when they
+	// are in none.
+	MemberOf(ctx context.Context, userID int64) (int64, error)
+	// CountSeats counts the organization's active, non-service members
+	// other than exceptUserID.
+	CountSeats(ctx context.Context, orgID, exceptUserID int64) (int64, error)
+	// AggregateUsage recomputes every organization's seats and storage for
+	// the day.
+	AggregateUsage(ctx context.Context, day time.Time) error
+	// AddAPICalls adds calls to the day's count for the user's
+	// organization, if they have one.
+	AddAPICalls(ctx context.Context, userID int64, day time.Time, calls int64) error
+	Usage(ctx context.Context, orgID int64, from, to time.Time) ([]generated.OrganizationUsage, error)
+}
+
+var (
+	_ OrganizationStore = (*OrganizationRepository)(nil)
+	_ OrganizationStore = (*MySQLOrganizationRepository)(nil)
+	_ OrganizationStore = (*MemoryOrganizationStore)(nil)
+)
+
+type OrganizationRepository struct {
+	queries *generated.Queries
+}
+
+func NewOrganizationRepository(q *generated.Queries) *OrganizationRepository {
+	return &OrganizationRepository{queries: q}
+}
+
+func (r *OrganizationRepository) Create(ctx context.Context, name string) (generated.Organization, error) {
+	org, err := r.queries.CreateOrganization(ctx, name)
+	return org, pgError(err)
+}
+
+func (r *OrganizationRepository) Get(ctx context.Context, id int64) (generated.Organization, error) {
+	return r.queries.GetOrganization(ctx, id)
+}
+
+func (r *OrganizationRepository) List(ctx context.Context) ([]generated.Organization, error) {
+	return r.queries.ListOrganizations(ctx)
+}
+
+func (r *OrganizationRepository) SetMember(ctx context.Context, userID, orgID int64) error {
+	return pgError(r.queries.SetOrganizationMember(ctx, generated.SetOrganizationMemberParams{
+		UserID: userID,
+		OrgID:  orgID,
+	}))
+}
+
+func (r *OrganizationRepository) RemoveMember(ctx context.Context, userID int64) (bool, error) {
+	n, err := r.queries.DeleteOrganizationMember(ctx, userID)
+	return n > 0, err
+}
+
+func (r *OrganizationRepository) MemberOf(ctx context.Context, userID int64) (int64, error) {
+	return r.queries.GetOrganizationMembership(ctx, userID)
+}
+
+func (r *OrganizationRepository) CountSeats(ctx context.Context, orgID, exceptUserID int64) (int64, error) {
+	return r.queries.CountOrganizationSeats(ctx, generated.CountOrganizationSeatsParams{
+		OrgID:        orgID,
+		ExceptUserID: exceptUserID,
+	})
+}
+
+func (r *OrganizationRepository) AggregateUsage(ctx context.Context, day time.Time) error {
+	return r.queries.AggregateOrganizationUsage(ctx, pgtype.Date{Time: day, Valid: true})
+}
+
+func (r *OrganizationRepository) AddAPICalls(ctx context.Context, userID int64, day time.Time, calls int64) error {
+	return r.queries.AddOrganizationAPICalls(ctx, generated.AddOrganizationAPICallsParams{
+		Day:    pgtype.Date{Time: day, Valid: true},
+		Calls:  calls,
+		UserID: userID,
+	})
+}
+
+func (r *OrganizationRepository) Usage(ctx context.Context, orgID int64, from, to time.Time) ([]generated.OrganizationUsage, error) {
+	return r.queries.ListOrganizationUsage(ctx, generated.ListOrganizationUsageParams{
+		OrgID:   orgID,
+		FromDay: pgtype.Date{Time: from, Valid: true},
+		ToDay:   pgtype.Date{Time: to, Valid: true},
+	})
+}
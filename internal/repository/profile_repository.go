@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// ProfileStore keeps the profile fields users set for themselves. A user
+// who never set any has no profile, and Get returns pgx.ErrNoRows.
+type ProfileStore interface {
+	Get(ctx context.Context, userID int64) (generated.UserProfile, error)
+	// Save replaces the user's profile, creating it if need be. It leaves
+	// AvatarKey alone.
+	Save(ctx context.Context, profile generated.UserProfile) (generated.UserProfile, error)
+	// SetAvatarKey records where the user's uploaded avatar is stored, ""
+	// for none.
+	SetAvatarKey(ctx context.Context, userID int64, key string) error
+}
+
+var (
+	_ ProfileStore = (*ProfileRepository)(nil)
+	_ ProfileStore = (*MySQLProfileRepository)(nil)
+	_ ProfileStore = (*MemoryProfileStore)(nil)
+)
+
+type ProfileRepository struct {
+	queries *generated.Queries
+}
+
+func NewProfileRepository(q *generated.Queries) *ProfileRepository {
+	return &ProfileRepository{queries: q}
+}
+
+func (r *ProfileRepository) Get(ctx context.Context, userID int64) (generated.UserProfile, error) {
+	return r.queries.GetUserProfile(ctx, userID)
+}
+
+func (r *ProfileRepository) Save(ctx context.Context, profile generated.UserProfile) (generated.UserProfile, error) {
+	row, err := r.queries.UpsertUserProfile(ctx, generated.UpsertUserProfileParams{
+		UserID:    profile.UserID,
+		Bio:       profile.Bio,
+		Phone:     profile.Phone,
+		AvatarUrl: profile.AvatarUrl,
+		Locale:    profile.Locale,
+		Timezone:  profile.Timezone,
+	})
+	return row, pgError(err)
+}
+
+func (r *ProfileRepository) SetAvatarKey(ctx context.Context, userID int64, key string) error {
+	return pgError(r.queries.SetUserProfileAvatarKey(ctx, generated.SetUserProfileAvatarKeyParams{UserID: userID, AvatarKey: key}))
+}
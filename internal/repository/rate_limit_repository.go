@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// RateLimitCounterStore counts requests per client in fixed windows kept in
+// the database, so counts survive restarts. It has the shape of
+// middleware.RateLimitStore; the oldest request is reported as the start of
+// the window.
+type RateLimitCounterStore interface {
+	Take(ctx context.Context, client string, now time.Time, window time.Duration, max int) (int, time.Time, bool, error)
+	Count(ctx context.Context, client string, now time.Time, window time.Duration) (int, time.Time, error)
+	RetentionStats(ctx context.Context) (int64, *time.Time, error)
+	PruneBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+var (
+	_ RateLimitCounterStore = (*RateLimitRepository)(nil)
+	_ RateLimitCounterStore = (*MySQLRateLimitRepository)(nil)
+	_ RateLimitCounterStore = (*MemoryRateLimitStore)(nil)
+)
+
+type RateLimitRepository struct {
+	queries *generated.Queries
+}
+
+func NewRateLimitRepository(q *generated.Queries) *RateLimitRepository {
+	return &RateLimitRepository{queries: q}
+}
+
+func (r *RateLimitRepository) Take(ctx context.Context, client string, now time.Time, window time.Duration, max int) (int, time.Time, bool, error) {
+	now = now.UTC()
+	row, err := r.queries.TakeRateLimit(ctx, generated.TakeRateLimitParams{
+		Client:        client,
+		Now:           pgtype.Timestamp{Time: now, Valid: true},
+		ExpiredBefore: pgtype.Timestamp{Time: now.Add(-window), Valid: true},
+		MaxHits:       int32(max),
+	})
+	if err != nil {
+		return 0, time.Time{}, false, pgError(err)
+	}
+	return takenHits(int(row.Hits), max), row.WindowStart.Time, int(row.Hits) <= max, nil
+}
+
+func (r *RateLimitRepository) Count(ctx context.Context, client string, now time.Time, window time.Duration) (int, time.Time, error) {
+	now = now.UTC()
+	row, err := r.queries.GetRateLimit(ctx, client)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !row.WindowStart.Time.After(now.Add(-window))) {
+		return 0, now, nil
+	}
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return int(row.Hits), row.WindowStart.Time, nil
+}
+
+// RetentionStats reports the counters held and the earliest window start
+// among them.
+func (r *RateLimitRepository) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	row, err := r.queries.RateLimitStats(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !row.Oldest.Valid {
+		return row.Total, nil, nil
+	}
+	return row.Total, &row.Oldest.Time, nil
+}
+
+// PruneBefore deletes counters whose window started by before.
+func (r *RateLimitRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.DeleteExpiredRateLimits(ctx, pgtype.Timestamp{Time: before.UTC(), Valid: true})
+}
+
+// takenHits caps hits at max: a request past it is stored as max+1 so
+// later ones in the window stay refused, but was not served.
+func takenHits(hits, max int) int {
+	if hits > max {
+		return max
+	}
+	return hits
+}
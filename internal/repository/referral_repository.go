@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// ReferralStore holds each user's referral code and the signups made with it.
+type ReferralStore interface {
+	Code(ctx context.Context, userID int64) (string, error)
+	// CreateCode reports false when the user already has a code or the code
+	// is taken.
+	CreateCode(ctx context.Context, userID int64, code string) (bool, error)
+	Owner(ctx context.Context, code string) (int64, error)
+	// Record reports false when the referred user was already credited to
+	// someone.
+	Record(ctx context.Context, referrerID, referredID int64) (bool, error)
+	Stats(ctx context.Context, referrerID int64, since time.Time) (generated.ReferralStatsRow, error)
+}
+
+var (
+	_ ReferralStore = (*ReferralRepository)(nil)
+	_ ReferralStore = (*MySQLReferralRepository)(nil)
+	_ ReferralStore = (*MemoryReferralStore)(nil)
+)
+
+type ReferralRepository struct {
+	queries *generated.Queries
+}
+
+func NewReferralRepository(q *generated.Queries) *ReferralRepository {
+	return &ReferralRepository{queries: q}
+}
+
+func (r *ReferralRepository) Code(ctx context.Context, userID int64) (string, error) {
+	return r.queries.GetReferralCode(ctx, userID)
+}
+
+func (r *ReferralRepository) CreateCode(ctx context.Context, userID int64, code string) (bool, error) {
+	n, err := r.queries.CreateReferralCode(ctx, generated.CreateReferralCodeParams{
+		UserID: userID,
+		Code:   code,
+	})
+	return n > 0, pgError(err)
+}
+
+func (r *ReferralRepository) Owner(ctx context.Context, code string) (int64, error) {
+	return r.queries.GetReferralCodeOwner(ctx, code)
+}
+
+func (r *ReferralRepository) Record(ctx context.Context, referrerID, referredID int64) (bool, error) {
+	n, err := r.queries.CreateReferral(ctx, generated.CreateReferralParams{
+		ReferrerID: referrerID,
+		ReferredID: referredID,
+	})
+	return n > 0, pgError(err)
+}
+
+func (r *ReferralRepository) Stats(ctx context.Context, referrerID int64, since time.Time) (generated.ReferralStatsRow, error) {
+	return r.queries.ReferralStats(ctx, generated.ReferralStatsParams{
+		Since:      pgtype.Timestamp{Time: since, Valid: true},
+		ReferrerID: referrerID,
+	})
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// RefreshTokenStore keeps the hashes of issued refresh tokens. Tokens that
+// replace one another share a family, so a whole chain can be revoked at
+// once.
+type RefreshTokenStore interface {
+	Create(ctx context.Context, userID int64, tokenHash, family string, createdAt, expiresAt time.Time) error
+	GetByHash(ctx context.Context, tokenHash string) (generated.GetRefreshTokenByHashRow, error)
+	// Revoke reports whether the token was still unrevoked, so of two
+	// requests using the same token only one succeeds.
+	Revoke(ctx context.Context, id int64) (bool, error)
+	RevokeFamily(ctx context.Context, family string) error
+}
+
+var (
+	_ RefreshTokenStore = (*RefreshTokenRepository)(nil)
+	_ RefreshTokenStore = (*MySQLRefreshTokenRepository)(nil)
+	_ RefreshTokenStore = (*MemoryRefreshTokenStore)(nil)
+)
+
+type RefreshTokenRepository struct {
+	queries *generated.Queries
+}
+
+func NewRefreshTokenRepository(q *generated.Queries) *RefreshTokenRepository {
+	return &RefreshTokenRepository{queries: q}
+}
+
+func (r *RefreshTokenRepository) Create(ctx context.Context, userID int64, tokenHash, family string, createdAt, expiresAt time.Time) error {
+	return pgError(r.queries.CreateRefreshToken(ctx, generated.CreateRefreshTokenParams{
+		UserID:    userID,
+		TokenHash: tokenHash,
+		Family:    family,
+		CreatedAt: pgtype.Timestamp{Time: createdAt.UTC(), Valid: true},
+		ExpiresAt: pgtype.Timestamp{Time: expiresAt.UTC(), Valid: true},
+	}))
+}
+
+// GetByHash reads from the primary: a client uses the token it was just
+// given straight away, before a replica may have it.
+func (r *RefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (generated.GetRefreshTokenByHashRow, error) {
+	return r.queries.GetRefreshTokenByHash(WithPrimary(ctx), tokenHash)
+}
+
+func (r *RefreshTokenRepository) Revoke(ctx context.Context, id int64) (bool, error) {
+	n, err := r.queries.RevokeRefreshToken(ctx, id)
+	return n > 0, pgError(err)
+}
+
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, family string) error {
+	return pgError(r.queries.RevokeRefreshTokenFamily(ctx, family))
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+type primaryKey struct{}
+
+// WithPrimary marks ctx so that every query made with it goes to the
+// primary, for reads that must see the latest writes.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// UsesPrimary reports whether ctx was marked by WithPrimary.
+func UsesPrimary(ctx context.Context) bool {
+	primary, _ := ctx.Value(primaryKey{}).(bool)
+	return primary
+}
+
+// replicatedDB sends plain SELECTs to a read replica, usually one in the
+// local region, and everything else to the primary. Statements that write,
+// including INSERT ... RETURNING run through QueryRow, always go to the
+// primary, as does every statement made with a WithPrimary context.
+type replicatedDB struct {
+	primary generated.DBTX
+	replica generated.DBTX
+}
+
+// NewReplicatedDB routes reads to replica. With a nil replica it returns
+// primary unchanged.
+func NewReplicatedDB(primary, replica generated.DBTX) generated.DBTX {
+	if replica == nil {
+		return primary
+	}
+	return &replicatedDB{primary: primary, replica: replica}
+}
+
+func (db *replicatedDB) pick(ctx context.Context, sql string) generated.DBTX {
+	if UsesPrimary(ctx) || !isRead(sql) {
+		return db.primary
+	}
+	return db.replica
+}
+
+func (db *replicatedDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return db.primary.Exec(ctx, sql, args...)
+}
+
+func (db *replicatedDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	return db.pick(ctx, sql).Query(ctx, sql, args...)
+}
+
+func (db *replicatedDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return db.pick(ctx, sql).QueryRow(ctx, sql, args...)
+}
+
+// isRead reports whether sql is a SELECT, skipping the "-- name:" comment
+// sqlc puts first. Anything else, WITH included since a CTE can write, is
+// treated as a write.
+func isRead(sql string) bool {
+	for {
+		sql = strings.TrimSpace(sql)
+		if !strings.HasPrefix(sql, "--") {
+			break
+		}
+		end := strings.IndexByte(sql, '\n')
+		if end < 0 {
+			return false
+		}
+		sql = sql[end+1:]
+	}
+	if len(sql) < len("SELECT") || !strings.EqualFold(sql[:len("SELECT")], "SELECT") {
+		return false
+	}
+	return !strings.Contains(strings.ToUpper(sql), "FOR UPDATE")
+}
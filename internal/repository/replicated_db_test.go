@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// recordingDB records which statements it was given.
+type recordingDB struct {
+	calls []string
+}
+
+func (db *recordingDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	db.calls = append(db.calls, sql)
+	return pgconn.CommandTag{}, nil
+}
+
+func (db *recordingDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	db.calls = append(db.calls, sql)
+	return nil, nil
+}
+
+func (db *recordingDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	db.calls = append(db.calls, sql)
+	return nil
+}
+
+func TestReplicatedDBRouting(t *testing.T) {
+	const (
+		selectSQL = "-- name: GetUserByID :one\nSELECT id FROM users WHERE id = $1"
+		insertSQL = "-- name: CreateUser :one\nINSERT INTO users (name) VALUES ($1) RETURNING id"
+	)
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		call        func(db *replicatedDB, ctx context.Context)
+		wantReplica bool
+	}{
+		{"select", context.Background(), func(db *replicatedDB, ctx context.Context) { db.QueryRow(ctx, selectSQL) }, true},
+		{"select list", context.Background(), func(db *replicatedDB, ctx context.Context) { db.Query(ctx, selectSQL) }, true},
+		{"strong select", WithPrimary(context.Background()), func(db *replicatedDB, ctx context.Context) { db.QueryRow(ctx, selectSQL) }, false},
+		{"insert returning", context.Background(), func(db *replicatedDB, ctx context.Context) { db.QueryRow(ctx, insertSQL) }, false},
+		{"exec", context.Background(), func(db *replicatedDB, ctx context.Context) { db.Exec(ctx, selectSQL) }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary, replica := &recordingDB{}, &recordingDB{}
+			db := NewReplicatedDB(primary, replica).(*replicatedDB)
+			tt.call(db, tt.ctx)
+			if got := len(replica.calls) == 1; got != tt.wantReplica {
+				t.Errorf("went to replica = %v; want %v (primary %d calls, replica %d)", got, tt.wantReplica, len(primary.calls), len(replica.calls))
+			}
+		})
+	}
+}
+
+func TestIsRead(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want bool
+	}{
+		{"SELECT 1", true},
+		{"  select * from users", true},
+		{"-- name: X :one\n-- more\nSELECT 1", true},
+		{"SELECT id FROM users WHERE id = $1 FOR UPDATE", false},
+		{"WITH x AS (DELETE FROM users RETURNING id) SELECT * FROM x", false},
+		{"UPDATE users SET name = $1", false},
+		{"-- name: X :one", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := isRead(tt.sql); got != tt.want {
+			t.Errorf("isRead(%q) = %v; want %v", tt.sql, got, tt.want)
+		}
+	}
+}
+
+func TestNewReplicatedDBWithoutReplica(t *testing.T) {
+	primary := &recordingDB{}
+	if db := NewReplicatedDB(primary, nil); db != primary {
+		t.Errorf("expected the primary back without a replica, got %T", db)
+	}
+}
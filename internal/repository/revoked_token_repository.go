@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// RevokedTokenStore holds the IDs (jti) of JWTs logged out before they
+// expired. Entries only matter until the token expires, after which they
+// can be pruned.
+type RevokedTokenStore interface {
+	Revoke(ctx context.Context, jti string, userID int64, expiresAt time.Time) error
+	// List returns the revoked tokens that expire after now, with their
+	// expiry.
+	List(ctx context.Context, now time.Time) (map[string]time.Time, error)
+	RetentionStats(ctx context.Context) (int64, *time.Time, error)
+	PruneBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+var (
+	_ RevokedTokenStore = (*RevokedTokenRepository)(nil)
+	_ RevokedTokenStore = (*MySQLRevokedTokenRepository)(nil)
+	_ RevokedTokenStore = (*MemoryRevokedTokenStore)(nil)
+)
+
+type RevokedTokenRepository struct {
+	queries *generated.Queries
+}
+
+func NewRevokedTokenRepository(q *generated.Queries) *RevokedTokenRepository {
+	return &RevokedTokenRepository{queries: q}
+}
+
+func (r *RevokedTokenRepository) Revoke(ctx context.Context, jti string, userID int64, expiresAt time.Time) error {
+	return pgError(r.queries.RevokeToken(ctx, generated.RevokeTokenParams{
+		Jti:       jti,
+		UserID:    userID,
+		ExpiresAt: pgtype.Timestamp{Time: expiresAt.UTC(), Valid: true},
+	}))
+}
+
+func (r *RevokedTokenRepository) List(ctx context.Context, now time.Time) (map[string]time.Time, error) {
+	rows, err := r.queries.ListRevokedTokens(ctx, pgtype.Timestamp{Time: now.UTC(), Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	revoked := make(map[string]time.Time, len(rows))
+	for _, row := range rows {
+		revoked[row.Jti] = row.ExpiresAt.Time
+	}
+	return revoked, nil
+}
+
+// RetentionStats reports the revoked tokens held and the earliest expiry
+// among them.
+func (r *RevokedTokenRepository) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	row, err := r.queries.RevokedTokenStats(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !row.Oldest.Valid {
+		return row.Total, nil, nil
+	}
+	return row.Total, &row.Oldest.Time, nil
+}
+
+// PruneBefore deletes revoked tokens that expired before before.
+func (r *RevokedTokenRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.PruneRevokedTokens(ctx, pgtype.Timestamp{Time: before.UTC(), Valid: true})
+}
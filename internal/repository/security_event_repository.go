@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// SecurityEventStore keeps the security log. The table refuses updates and
+// deletes, and each event holds the hash of the one before it; prev_hash is
+// unique, so two events can't both follow the same one.
+type SecurityEventStore interface {
+	// Append adds an event and returns its ID. It fails with
+	// ErrUniqueViolation when another event already follows e.PrevHash.
+	Append(ctx context.Context, e generated.SecurityEvent) (int64, error)
+	// LastHash returns the hash of the newest event, or "" if there is none.
+	LastHash(ctx context.Context) (string, error)
+	// ListAfter lists events oldest first, starting after afterID.
+	ListAfter(ctx context.Context, afterID int64, limit int32) ([]generated.SecurityEvent, error)
+	// List lists events newest first, starting before beforeID, with the
+	// public IDs of the users involved.
+	List(ctx context.Context, beforeID int64, limit int32) ([]generated.ListSecurityEventsRow, error)
+}
+
+var (
+	_ SecurityEventStore = (*SecurityEventRepository)(nil)
+	_ SecurityEventStore = (*MySQLSecurityEventRepository)(nil)
+	_ SecurityEventStore = (*MemorySecurityEventStore)(nil)
+)
+
+type SecurityEventRepository struct {
+	queries *generated.Queries
+}
+
+func NewSecurityEventRepository(q *generated.Queries) *SecurityEventRepository {
+	return &SecurityEventRepository{queries: q}
+}
+
+func (r *SecurityEventRepository) Append(ctx context.Context, e generated.SecurityEvent) (int64, error) {
+	id, err := r.queries.AppendSecurityEvent(ctx, generated.AppendSecurityEventParams{
+		EventType: e.EventType,
+		ActorID:   e.ActorID,
+		TargetID:  e.TargetID,
+		Details:   e.Details,
+		IpAddress: e.IpAddress,
+		CreatedAt: e.CreatedAt,
+		PrevHash:  e.PrevHash,
+		Hash:      e.Hash,
+	})
+	return id, pgError(err)
+}
+
+// LastHash reads from the primary, since the next event must follow the
+// newest one.
+func (r *SecurityEventRepository) LastHash(ctx context.Context) (string, error) {
+	hash, err := r.queries.GetLastSecurityEventHash(WithPrimary(ctx))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	return hash, err
+}
+
+func (r *SecurityEventRepository) ListAfter(ctx context.Context, afterID int64, limit int32) ([]generated.SecurityEvent, error) {
+	return r.queries.ListSecurityEventsAfter(ctx, generated.ListSecurityEventsAfterParams{
+		ID:    afterID,
+		Limit: limit,
+	})
+}
+
+func (r *SecurityEventRepository) List(ctx context.Context, beforeID int64, limit int32) ([]generated.ListSecurityEventsRow, error) {
+	return r.queries.ListSecurityEvents(ctx, generated.ListSecurityEventsParams{
+		ID:    beforeID,
+		Limit: limit,
+	})
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// SessionStore keeps the sessions users start by logging in. A session is
+// live until it expires or is revoked; refreshing its tokens extends it.
+type SessionStore interface {
+	Create(ctx context.Context, id string, userID int64, ipAddress, userAgent string, createdAt, expiresAt time.Time) error
+	// ListActive returns the user's live sessions, oldest first.
+	ListActive(ctx context.Context, userID int64, now time.Time) ([]generated.ListActiveSessionsRow, error)
+	Extend(ctx context.Context, id string, expiresAt time.Time) error
+	// Revoke reports whether the session was the user's and still live.
+	Revoke(ctx context.Context, id string, userID int64) (bool, error)
+	RetentionStats(ctx context.Context) (int64, *time.Time, error)
+	PruneBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+var (
+	_ SessionStore = (*SessionRepository)(nil)
+	_ SessionStore = (*MySQLSessionRepository)(nil)
+	_ SessionStore = (*MemorySessionStore)(nil)
+)
+
+type SessionRepository struct {
+	queries *generated.Queries
+}
+
+func NewSessionRepository(q *generated.Queries) *SessionRepository {
+	return &SessionRepository{queries: q}
+}
+
+func (r *SessionRepository) Create(ctx context.Context, id string, userID int64, ipAddress, userAgent string, createdAt, expiresAt time.Time) error {
+	return pgError(r.queries.CreateSession(ctx, generated.CreateSessionParams{
+		ID:        id,
+		UserID:    userID,
+		IpAddress: ipAddress,
+		UserAgent: userAgent,
+		CreatedAt: pgtype.Timestamp{Time: createdAt.UTC(), Valid: true},
+		ExpiresAt: pgtype.Timestamp{Time: expiresAt.UTC(), Valid: true},
+	}))
+}
+
+// ListActive reads from the primary, so a login right after another counts
+// it towards the limit.
+func (r *SessionRepository) ListActive(ctx context.Context, userID int64, now time.Time) ([]generated.ListActiveSessionsRow, error) {
+	return r.queries.ListActiveSessions(WithPrimary(ctx), generated.ListActiveSessionsParams{
+		UserID:    userID,
+		ExpiresAt: pgtype.Timestamp{Time: now.UTC(), Valid: true},
+	})
+}
+
+func (r *SessionRepository) Extend(ctx context.Context, id string, expiresAt time.Time) error {
+	return pgError(r.queries.ExtendSession(ctx, generated.ExtendSessionParams{
+		ID:        id,
+		ExpiresAt: pgtype.Timestamp{Time: expiresAt.UTC(), Valid: true},
+	}))
+}
+
+func (r *SessionRepository) Revoke(ctx context.Context, id string, userID int64) (bool, error) {
+	n, err := r.queries.RevokeSession(ctx, generated.RevokeSessionParams{ID: id, UserID: userID})
+	return n > 0, pgError(err)
+}
+
+// RetentionStats reports the sessions held and the earliest expiry among
+// them.
+func (r *SessionRepository) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	row, err := r.queries.SessionStats(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !row.Oldest.Valid {
+		return row.Total, nil, nil
+	}
+	return row.Total, &row.Oldest.Time, nil
+}
+
+// PruneBefore deletes sessions that expired before before.
+func (r *SessionRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.PruneSessions(ctx, pgtype.Timestamp{Time: before.UTC(), Valid: true})
+}
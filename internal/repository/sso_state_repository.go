@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// SSOPendingLogin is an SSO login waiting for the IdP's callback.
+type SSOPendingLogin struct {
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+}
+
+// SSOStateStore keeps pending SSO logins in the database, keyed by a hash of
+// their state, so the IdP's callback can reach any instance. Logins that are
+// never completed are pruned once they expire.
+type SSOStateStore interface {
+	Save(ctx context.Context, stateHash string, login SSOPendingLogin) error
+	// Take removes the login with stateHash and returns it, or pgx.ErrNoRows
+	// when there is none, so each state is used once.
+	Take(ctx context.Context, stateHash string) (SSOPendingLogin, error)
+	RetentionStats(ctx context.Context) (int64, *time.Time, error)
+	PruneBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+var (
+	_ SSOStateStore = (*SSOStateRepository)(nil)
+	_ SSOStateStore = (*MySQLSSOStateRepository)(nil)
+	_ SSOStateStore = (*MemorySSOStateStore)(nil)
+)
+
+type SSOStateRepository struct {
+	queries *generated.Queries
+}
+
+func NewSSOStateRepository(q *generated.Queries) *SSOStateRepository {
+	return &SSOStateRepository{queries: q}
+}
+
+func (r *SSOStateRepository) Save(ctx context.Context, stateHash string, login SSOPendingLogin) error {
+	err := r.queries.CreateSSOPendingLogin(ctx, generated.CreateSSOPendingLoginParams{
+		StateHash:    stateHash,
+		Nonce:        login.Nonce,
+		CodeVerifier: login.CodeVerifier,
+		ExpiresAt:    pgtype.Timestamp{Time: login.ExpiresAt.UTC(), Valid: true},
+	})
+	return pgError(err)
+}
+
+func (r *SSOStateRepository) Take(ctx context.Context, stateHash string) (SSOPendingLogin, error) {
+	row, err := r.queries.TakeSSOPendingLogin(ctx, stateHash)
+	if err != nil {
+		return SSOPendingLogin{}, pgError(err)
+	}
+	return SSOPendingLogin{
+		Nonce:        row.Nonce,
+		CodeVerifier: row.CodeVerifier,
+		ExpiresAt:    row.ExpiresAt.Time,
+	}, nil
+}
+
+// RetentionStats reports the pending logins held and the earliest expiry among them.
+func (r *SSOStateRepository) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	row, err := r.queries.SSOPendingLoginStats(ctx)
+	if err != nil {
+		return 0, nil, err
+	}
+	if !row.Oldest.Valid {
+		return row.Total, nil, nil
+	}
+	return row.Total, &row.Oldest.Time, nil
+}
+
+// PruneBefore deletes pending logins that expired by before.
+func (r *SSOStateRepository) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	return r.queries.DeleteExpiredSSOPendingLogins(ctx, pgtype.Timestamp{Time: before.UTC(), Valid: true})
+}
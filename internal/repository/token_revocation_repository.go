@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// TokenRevocationStore records, per user, the moment before which every
+// issued token stopped being valid.
+type TokenRevocationStore interface {
+	Revoke(ctx context.Context, userID int64, at time.Time) error
+	List(ctx context.Context) (map[int64]time.Time, error)
+}
+
+var (
+	_ TokenRevocationStore = (*TokenRevocationRepository)(nil)
+	_ TokenRevocationStore = (*MySQLTokenRevocationRepository)(nil)
+	_ TokenRevocationStore = (*MemoryTokenRevocationStore)(nil)
+)
+
+type TokenRevocationRepository struct {
+	queries *generated.Queries
+}
+
+func NewTokenRevocationRepository(q *generated.Queries) *TokenRevocationRepository {
+	return &TokenRevocationRepository{queries: q}
+}
+
+func (r *TokenRevocationRepository) Revoke(ctx context.Context, userID int64, at time.Time) error {
+	return pgError(r.queries.RevokeUserTokens(ctx, generated.RevokeUserTokensParams{
+		UserID:    userID,
+		RevokedAt: pgtype.Timestamp{Time: at.UTC(), Valid: true},
+	}))
+}
+
+func (r *TokenRevocationRepository) List(ctx context.Context) (map[int64]time.Time, error) {
+	rows, err := r.queries.ListTokenRevocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	revoked := make(map[int64]time.Time, len(rows))
+	for _, row := range rows {
+		revoked[row.UserID] = row.RevokedAt.Time
+	}
+	return revoked, nil
+}
@@ -3,8 +3,10 @@ package repository
 import (
 	"BACKEND/db/sqlc/generated"
 	"context"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -16,18 +18,20 @@ func NewUserRepository(q *generated.Queries) *UserRepository {
 	return &UserRepository{queries: q}
 }
 
-func (r *UserRepository) Create(ctx context.Context, name string, dob time.Time) (generated.CreateUserRow, error) {
-	return r.queries.CreateUser(ctx, generated.CreateUserParams{
+func (r *UserRepository) Create(ctx context.Context, name, source string, dob time.Time) (generated.CreateUserRow, error) {
+	row, err := r.queries.CreateUser(ctx, generated.CreateUserParams{
 		Name: name,
 		Dob: pgtype.Date{
 			Time:  dob,
 			Valid: true,
 		},
+		SignupSource: source,
 	})
+	return row, pgError(err)
 }
 
-func (r *UserRepository) CreateWithAuth(ctx context.Context, name, email, passwordHash, role string, dob time.Time) (generated.CreateUserRow, error) {
-	return r.queries.CreateUser(ctx, generated.CreateUserParams{
+func (r *UserRepository) CreateWithAuth(ctx context.Context, name, email, passwordHash, role, source string, dob time.Time) (generated.CreateUserRow, error) {
+	row, err := r.queries.CreateUser(ctx, generated.CreateUserParams{
 		Name: name,
 		Dob: pgtype.Date{
 			Time:  dob,
@@ -35,20 +39,30 @@ func (r *UserRepository) CreateWithAuth(ctx context.Context, name, email, passwo
 		},
 		Email:        email,
 		PasswordHash: passwordHash,
-		Column5:      role, 
+		Column5:      role,
+		SignupSource: source,
 	})
+	return row, pgError(err)
 }
 
-func (r *UserRepository) GetByID(ctx context.Context, id int32) (generated.GetUserByIDRow, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id int64) (generated.GetUserByIDRow, error) {
 	return r.queries.GetUserByID(ctx, id)
 }
 
+func (r *UserRepository) GetIDByPublicID(ctx context.Context, publicID string) (int64, error) {
+	var id pgtype.UUID
+	if err := id.Scan(publicID); err != nil {
+		return 0, pgx.ErrNoRows
+	}
+	return r.queries.GetUserIDByPublicID(ctx, id)
+}
+
 func (r *UserRepository) List(ctx context.Context) ([]generated.ListUsersRow, error) {
 	return r.queries.ListUsers(ctx)
 }
 
-func (r *UserRepository) Update(ctx context.Context, id int32, name string, dob time.Time) (generated.UpdateUserRow, error) {
-	return r.queries.UpdateUser(ctx, generated.UpdateUserParams{
+func (r *UserRepository) Update(ctx context.Context, id int64, name string, dob time.Time) (generated.UpdateUserRow, error) {
+	row, err := r.queries.UpdateUser(ctx, generated.UpdateUserParams{
 		ID:   id,
 		Name: name,
 		Dob: pgtype.Date{
@@ -56,10 +70,31 @@ func (r *UserRepository) Update(ctx context.Context, id int32, name string, dob
 			Valid: true,
 		},
 	})
+	return row, pgError(err)
+}
+
+func (r *UserRepository) SoftDelete(ctx context.Context, id int64) error {
+	n, err := r.queries.SoftDeleteUser(ctx, id)
+	if err != nil {
+		return pgError(err)
+	}
+	if n == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func (r *UserRepository) Restore(ctx context.Context, id int64) (generated.RestoreUserRow, error) {
+	row, err := r.queries.RestoreUser(ctx, id)
+	return row, pgError(err)
+}
+
+func (r *UserRepository) HardDelete(ctx context.Context, id int64) error {
+	return pgError(r.queries.DeleteUser(ctx, id))
 }
 
-func (r *UserRepository) Delete(ctx context.Context, id int32) error {
-	return r.queries.DeleteUser(ctx, id)
+func (r *UserRepository) GetDeletedByID(ctx context.Context, id int64) (generated.GetDeletedUserByIDRow, error) {
+	return r.queries.GetDeletedUserByID(ctx, id)
 }
 
 func (r *UserRepository) ListPaginated(ctx context.Context, limit, offset int32) ([]generated.ListUsersPaginatedRow, error) {
@@ -69,11 +104,162 @@ func (r *UserRepository) ListPaginated(ctx context.Context, limit, offset int32)
 	})
 }
 
+func (r *UserRepository) ListBySignupSource(ctx context.Context, source string) ([]generated.ListUsersBySignupSourceRow, error) {
+	return r.queries.ListUsersBySignupSource(ctx, source)
+}
+
 func (r *UserRepository) Count(ctx context.Context) (int64, error) {
 	return r.queries.CountUsers(ctx)
 }
 
+func (r *UserRepository) Search(ctx context.Context, filter UserFilter, sort []UserSortKey, limit, offset int32) ([]generated.SearchUsersRow, error) {
+	p := searchUsersParams(filter)
+	sorts := userSortArgs(sort)
+	return r.queries.SearchUsers(ctx, generated.SearchUsersParams{
+		NamePattern:   p.NamePattern,
+		Email:         p.Email,
+		Role:          p.Role,
+		SignupSource:  p.SignupSource,
+		DobAfter:      p.DobAfter,
+		DobUntil:      p.DobUntil,
+		CreatedFrom:   p.CreatedFrom,
+		CreatedBefore: p.CreatedBefore,
+		OrgID:         p.OrgID,
+		Sort1:         sorts[0],
+		Sort2:         sorts[1],
+		Sort3:         sorts[2],
+		RowLimit:      limit,
+		RowOffset:     offset,
+	})
+}
+
+func (r *UserRepository) CountSearch(ctx context.Context, filter UserFilter) (int64, error) {
+	return r.queries.CountSearchUsers(ctx, searchUsersParams(filter))
+}
+
+// searchUsersParams leaves the zero fields of filter NULL, which the
+// search queries skip.
+func searchUsersParams(filter UserFilter) generated.CountSearchUsersParams {
+	p := generated.CountSearchUsersParams{
+		Email:         pgtype.Text{String: filter.Email, Valid: filter.Email != ""},
+		Role:          pgtype.Text{String: filter.Role, Valid: filter.Role != ""},
+		SignupSource:  pgtype.Text{String: filter.SignupSource, Valid: filter.SignupSource != ""},
+		DobAfter:      pgtype.Date{Time: filter.DobAfter, Valid: !filter.DobAfter.IsZero()},
+		DobUntil:      pgtype.Date{Time: filter.DobUntil, Valid: !filter.DobUntil.IsZero()},
+		CreatedFrom:   pgtype.Timestamp{Time: filter.CreatedFrom, Valid: !filter.CreatedFrom.IsZero()},
+		CreatedBefore: pgtype.Timestamp{Time: filter.CreatedBefore, Valid: !filter.CreatedBefore.IsZero()},
+		OrgID:         pgtype.Int8{Int64: filter.OrgID, Valid: filter.OrgID != 0},
+	}
+	if filter.Name != "" {
+		p.NamePattern = pgtype.Text{String: filter.namePattern(), Valid: true}
+	}
+	return p
+}
 
 func (r *UserRepository) GetByEmail(ctx context.Context, email string) (generated.User, error) {
 	return r.queries.GetUserByEmail(ctx, email)
 }
+
+func (r *UserRepository) LookupByEmail(ctx context.Context, prefix string, limit int32) ([]generated.LookupUsersByEmailRow, error) {
+	return r.queries.LookupUsersByEmail(ctx, generated.LookupUsersByEmailParams{
+		Pattern:  escapeLike(strings.ToLower(prefix)) + "%",
+		RowLimit: limit,
+	})
+}
+
+func (r *UserRepository) CreateServiceAccount(ctx context.Context, name, email, passwordHash, role string) (generated.CreateServiceAccountRow, error) {
+	row, err := r.queries.CreateServiceAccount(ctx, generated.CreateServiceAccountParams{
+		Name:         name,
+		Email:        email,
+		PasswordHash: passwordHash,
+		Role:         role,
+	})
+	return row, pgError(err)
+}
+
+func (r *UserRepository) ListServiceAccounts(ctx context.Context) ([]generated.ListServiceAccountsRow, error) {
+	return r.queries.ListServiceAccounts(ctx)
+}
+
+func (r *UserRepository) UsersByAgeBracket(ctx context.Context) ([]generated.UsersByAgeBracketRow, error) {
+	return r.queries.UsersByAgeBracket(ctx)
+}
+
+func (r *UserRepository) AgeDistribution(ctx context.Context, groupBy string, bucketSize int32) ([]generated.AgeDistributionRow, error) {
+	return r.queries.AgeDistribution(ctx, generated.AgeDistributionParams{BucketSize: bucketSize, GroupBy: groupBy})
+}
+
+func (r *UserRepository) SignupsByDay(ctx context.Context, since time.Time) ([]generated.SignupsByDayRow, error) {
+	return r.queries.SignupsByDay(ctx, pgtype.Timestamp{
+		Time:  since,
+		Valid: true,
+	})
+}
+
+func (r *UserRepository) SignupsByMonth(ctx context.Context, since time.Time) ([]generated.SignupsByMonthRow, error) {
+	return r.queries.SignupsByMonth(ctx, pgtype.Timestamp{
+		Time:  since,
+		Valid: true,
+	})
+}
+
+func (r *UserRepository) SignupsBySource(ctx context.Context, since time.Time) ([]generated.SignupsBySourceRow, error) {
+	return r.queries.SignupsBySource(ctx, pgtype.Timestamp{
+		Time:  since,
+		Valid: true,
+	})
+}
+
+func (r *UserRepository) ListDeactivatedSince(ctx context.Context, since time.Time) ([]generated.ListDeactivatedUsersRow, error) {
+	return r.queries.ListDeactivatedUsers(ctx, pgtype.Timestamp{
+		Time:  since,
+		Valid: true,
+	})
+}
+
+func (r *UserRepository) UpcomingBirthdays(ctx context.Context, from, to time.Time, limit int32) ([]generated.ListUpcomingBirthdaysRow, error) {
+	w := newBirthdayWindow(from, to)
+	return r.queries.ListUpcomingBirthdays(ctx, generated.ListUpcomingBirthdaysParams{
+		FromDay:  w.from,
+		ToDay:    w.to,
+		WrapFrom: w.wrapFrom,
+		WrapTo:   w.wrapTo,
+		RowLimit: limit,
+	})
+}
+
+func (r *UserRepository) ListActiveAdmins(ctx context.Context) ([]generated.ListActiveAdminsRow, error) {
+	return r.queries.ListActiveAdmins(ctx)
+}
+
+func (r *UserRepository) GetStats(ctx context.Context) (generated.GetUserStatsRow, error) {
+	return r.queries.GetUserStats(ctx)
+}
+
+func (r *UserRepository) RefreshStats(ctx context.Context) error {
+	return r.queries.RefreshUserStats(ctx)
+}
+
+func (r *UserRepository) SetActive(ctx context.Context, id int64, active bool) (generated.SetUserActiveRow, error) {
+	row, err := r.queries.SetUserActive(ctx, generated.SetUserActiveParams{
+		ID:     id,
+		Active: active,
+	})
+	return row, pgError(err)
+}
+
+func (r *UserRepository) UpdateRole(ctx context.Context, id int64, role string) (generated.UpdateUserRoleRow, error) {
+	row, err := r.queries.UpdateUserRole(ctx, generated.UpdateUserRoleParams{
+		ID:   id,
+		Role: role,
+	})
+	return row, pgError(err)
+}
+
+func (r *UserRepository) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	_, err := r.queries.UpdateUserPassword(ctx, generated.UpdateUserPasswordParams{
+		ID:           id,
+		PasswordHash: passwordHash,
+	})
+	return pgError(err)
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+// UserStore is the driver-agnostic view of user persistence. Row types are
+// shared with the Postgres implementation, and lookups that match nothing
+// return pgx.ErrNoRows regardless of driver.
+//
+// Deleting a user only marks them deleted; apart from GetIDByPublicID and
+// GetDeletedByID, reads and updates skip them as if they were gone, until
+// Restore brings them back or HardDelete removes them for good.
+type UserStore interface {
+	Create(ctx context.Context, name, source string, dob time.Time) (generated.CreateUserRow, error)
+	CreateWithAuth(ctx context.Context, name, email, passwordHash, role, source string, dob time.Time) (generated.CreateUserRow, error)
+	GetByID(ctx context.Context, id int64) (generated.GetUserByIDRow, error)
+	// GetIDByPublicID maps the UUID the API shows for a user to their
+	// internal ID, deleted users included.
+	GetIDByPublicID(ctx context.Context, publicID string) (int64, error)
+	GetByEmail(ctx context.Context, email string) (generated.User, error)
+	// LookupByEmail returns up to limit users whose email starts with
+	// prefix, in any case, in email order, which puts an exact match first.
+	LookupByEmail(ctx context.Context, prefix string, limit int32) ([]generated.LookupUsersByEmailRow, error)
+	List(ctx context.Context) ([]generated.ListUsersRow, error)
+	ListPaginated(ctx context.Context, limit, offset int32) ([]generated.ListUsersPaginatedRow, error)
+	ListBySignupSource(ctx context.Context, source string) ([]generated.ListUsersBySignupSourceRow, error)
+	Count(ctx context.Context) (int64, error)
+	// Search returns a page of the users matching filter, ordered by sort
+	// and then ID, and CountSearch counts all of them.
+	Search(ctx context.Context, filter UserFilter, sort []UserSortKey, limit, offset int32) ([]generated.SearchUsersRow, error)
+	CountSearch(ctx context.Context, filter UserFilter) (int64, error)
+	Update(ctx context.Context, id int64, name string, dob time.Time) (generated.UpdateUserRow, error)
+	// SoftDelete marks the user deleted, or returns pgx.ErrNoRows if there
+	// is no such user or they are already deleted.
+	SoftDelete(ctx context.Context, id int64) error
+	// Restore undoes SoftDelete. It returns pgx.ErrNoRows if the user isn't
+	// deleted, and ErrEmailAlreadyExists if their email has since been
+	// signed up with again.
+	Restore(ctx context.Context, id int64) (generated.RestoreUserRow, error)
+	// HardDelete removes the user, deleted or not, with everything that
+	// references them.
+	HardDelete(ctx context.Context, id int64) error
+	GetDeletedByID(ctx context.Context, id int64) (generated.GetDeletedUserByIDRow, error)
+	SetActive(ctx context.Context, id int64, active bool) (generated.SetUserActiveRow, error)
+	UpdateRole(ctx context.Context, id int64, role string) (generated.UpdateUserRoleRow, error)
+	UpdatePassword(ctx context.Context, id int64, passwordHash string) error
+	CreateServiceAccount(ctx context.Context, name, email, passwordHash, role string) (generated.CreateServiceAccountRow, error)
+	ListServiceAccounts(ctx context.Context) ([]generated.ListServiceAccountsRow, error)
+	UsersByAgeBracket(ctx context.Context) ([]generated.UsersByAgeBracketRow, error)
+	// AgeDistribution counts users by age in buckets of bucketSize years,
+	// within groups by "role", "signup_cohort" (signup month) or, for "",
+	// one group named "".
+	AgeDistribution(ctx context.Context, groupBy string, bucketSize int32) ([]generated.AgeDistributionRow, error)
+	SignupsByDay(ctx context.Context, since time.Time) ([]generated.SignupsByDayRow, error)
+	SignupsByMonth(ctx context.Context, since time.Time) ([]generated.SignupsByMonthRow, error)
+	SignupsBySource(ctx context.Context, since time.Time) ([]generated.SignupsBySourceRow, error)
+	// ListDeactivatedSince lists inactive users last updated at or after
+	// since, which for most of them is when they were deactivated.
+	ListDeactivatedSince(ctx context.Context, since time.Time) ([]generated.ListDeactivatedUsersRow, error)
+	// UpcomingBirthdays lists up to limit users whose birthday falls between
+	// from and to inclusive, in the order the birthdays come, with those
+	// born on 29 February on the 28th in common years.
+	UpcomingBirthdays(ctx context.Context, from, to time.Time, limit int32) ([]generated.ListUpcomingBirthdaysRow, error)
+	ListActiveAdmins(ctx context.Context) ([]generated.ListActiveAdminsRow, error)
+	GetStats(ctx context.Context) (generated.GetUserStatsRow, error)
+	RefreshStats(ctx context.Context) error
+}
+
+// UserFilter narrows Search to the users matching all of its non-zero
+// fields.
+type UserFilter struct {
+	// Name is part of the name, in any case.
+	Name string
+	// Email is the whole email, in any case.
+	Email        string
+	Role         string
+	SignupSource string
+	// DobAfter and DobUntil bound the date of birth, the first exclusively
+	// and the second inclusively.
+	DobAfter time.Time
+	DobUntil time.Time
+	// CreatedFrom and CreatedBefore bound the signup time, the first
+	// inclusively and the second exclusively.
+	CreatedFrom   time.Time
+	CreatedBefore time.Time
+	// OrgID keeps the members of the organization.
+	OrgID int64
+}
+
+// namePattern is the LIKE pattern for f.Name, with LIKE's wildcards in it
+// matched literally.
+func (f UserFilter) namePattern() string {
+	return "%" + escapeLike(f.Name) + "%"
+}
+
+// escapeLike makes LIKE match s literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// Columns Search can order by.
+const (
+	SortName      = "name"
+	SortEmail     = "email"
+	SortRole      = "role"
+	SortDob       = "dob"
+	SortCreatedAt = "created_at"
+	SortUpdatedAt = "updated_at"
+)
+
+// MaxUserSortKeys is how many keys Search orders by; the search queries
+// have a slot for each.
+const MaxUserSortKeys = 3
+
+// UserSortKey orders Search by one of the Sort columns.
+type UserSortKey struct {
+	Column string
+	Desc   bool
+}
+
+// userSortArgs are the search queries' sort slots for keys: the column,
+// prefixed with "-" to descend, and "" for unused slots. Keys past
+// MaxUserSortKeys are dropped.
+func userSortArgs(keys []UserSortKey) [MaxUserSortKeys]string {
+	var args [MaxUserSortKeys]string
+	for i, k := range keys[:min(len(keys), MaxUserSortKeys)] {
+		args[i] = k.Column
+		if k.Desc {
+			args[i] = "-" + k.Column
+		}
+	}
+	return args
+}
+
+var (
+	_ UserStore = (*UserRepository)(nil)
+	_ UserStore = (*MySQLUserRepository)(nil)
+	_ UserStore = (*MemoryUserStore)(nil)
+)
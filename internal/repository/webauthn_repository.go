@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+type WebAuthnCredentialStore interface {
+	Create(ctx context.Context, userID int64, credentialID, publicKey []byte, signCount int64, name string) (generated.WebauthnCredential, error)
+	Get(ctx context.Context, credentialID []byte) (generated.WebauthnCredential, error)
+	ListByUser(ctx context.Context, userID int64) ([]generated.WebauthnCredential, error)
+	UpdateSignCount(ctx context.Context, id int64, signCount int64) error
+	Delete(ctx context.Context, id, userID int64) (bool, error)
+}
+
+var (
+	_ WebAuthnCredentialStore = (*WebAuthnCredentialRepository)(nil)
+	_ WebAuthnCredentialStore = (*MySQLWebAuthnCredentialRepository)(nil)
+	_ WebAuthnCredentialStore = (*MemoryWebAuthnCredentialStore)(nil)
+)
+
+type WebAuthnCredentialRepository struct {
+	queries *generated.Queries
+}
+
+func NewWebAuthnCredentialRepository(q *generated.Queries) *WebAuthnCredentialRepository {
+	return &WebAuthnCredentialRepository{queries: q}
+}
+
+func (r *WebAuthnCredentialRepository) Create(ctx context.Context, userID int64, credentialID, publicKey []byte, signCount int64, name string) (generated.WebauthnCredential, error) {
+	row, err := r.queries.CreateWebAuthnCredential(ctx, generated.CreateWebAuthnCredentialParams{
+		UserID:       userID,
+		CredentialID: credentialID,
+		PublicKey:    publicKey,
+		SignCount:    signCount,
+		Name:         name,
+	})
+	return row, pgError(err)
+}
+
+func (r *WebAuthnCredentialRepository) Get(ctx context.Context, credentialID []byte) (generated.WebauthnCredential, error) {
+	return r.queries.GetWebAuthnCredential(ctx, credentialID)
+}
+
+func (r *WebAuthnCredentialRepository) ListByUser(ctx context.Context, userID int64) ([]generated.WebauthnCredential, error) {
+	return r.queries.ListWebAuthnCredentialsByUser(ctx, userID)
+}
+
+func (r *WebAuthnCredentialRepository) UpdateSignCount(ctx context.Context, id int64, signCount int64) error {
+	return r.queries.UpdateWebAuthnCredentialUse(ctx, generated.UpdateWebAuthnCredentialUseParams{
+		ID:        id,
+		SignCount: signCount,
+	})
+}
+
+func (r *WebAuthnCredentialRepository) Delete(ctx context.Context, id, userID int64) (bool, error) {
+	n, err := r.queries.DeleteWebAuthnCredential(ctx, generated.DeleteWebAuthnCredentialParams{
+		ID:     id,
+		UserID: userID,
+	})
+	return n > 0, err
+}
@@ -0,0 +1,186 @@
+package routes
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RouteInfo is one endpoint and the handlers that run for it, middleware
+// first, in order.
+type RouteInfo struct {
+	Method string   `json:"method"`
+	Path   string   `json:"path"`
+	Chain  []string `json:"chain"`
+}
+
+// protectedGroups lists path prefixes whose every route must pass through
+// the named middleware. A route added to one of these groups with the
+// middleware missing, or registered before the group's Use, would be public.
+var protectedGroups = []struct {
+	prefix   string
+	required []string
+}{
+	{"/users", []string{"middleware.Auth"}},
+	{"/admin", []string{"middleware.Auth", "middleware.RequireRole"}},
+	{"/orgs", []string{"middleware.Auth", "middleware.RequireRole"}},
+	{"/scim/v2", []string{"middleware.SCIMAuth"}},
+}
+
+// Table returns app's routes, excluding middleware-only (Use) entries and
+// the HEAD routes Fiber adds for every GET.
+func Table(app *fiber.App) []RouteInfo {
+	var table []RouteInfo
+	for _, r := range resolve(app) {
+		if r.Method == fiber.MethodHead {
+			continue
+		}
+		table = append(table, r)
+	}
+	return table
+}
+
+// Check fails when two routes conflict, i.e. one can never be reached
+// because an earlier one with the same method matches all of its paths, or
+// when a route under a protected group (relative to prefix) is missing the
+// group's auth middleware.
+func Check(app *fiber.App, prefix string) error {
+	routes := Table(app)
+
+	var problems []string
+	for i, r := range routes {
+		for _, earlier := range routes[:i] {
+			if earlier.Method == r.Method && shadows(earlier.Path, r.Path) {
+				problems = append(problems, fmt.Sprintf("%s %s is unreachable: %s was registered first", r.Method, r.Path, earlier.Path))
+				break
+			}
+		}
+
+		for _, g := range protectedGroups {
+			group := prefix + g.prefix
+			if r.Path != group && !strings.HasPrefix(r.Path, group+"/") {
+				continue
+			}
+			for _, mw := range g.required {
+				if !contains(r.Chain, mw) {
+					problems = append(problems, fmt.Sprintf("%s %s is missing %s", r.Method, r.Path, mw))
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("route check failed:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// resolve pairs each route with the Use middleware registered before it on
+// a matching prefix. Fiber keeps middleware as separate entries in its
+// per-method stacks, and does not export which entries are Use ones, so
+// they are told apart by diffing against the list without them.
+func resolve(app *fiber.App) []RouteInfo {
+	all := app.GetRoutes()
+	endpoints := app.GetRoutes(true)
+
+	var table []RouteInfo
+	var uses []fiber.Route
+	method, next := "", 0
+	for _, r := range all {
+		if r.Method != method {
+			method, uses = r.Method, nil
+		}
+
+		if next < len(endpoints) && sameRoute(r, endpoints[next]) {
+			next++
+			var chain []string
+			for _, u := range uses {
+				if usePrefixMatches(u.Path, r.Path) {
+					chain = append(chain, handlerNames(u.Handlers)...)
+				}
+			}
+			table = append(table, RouteInfo{
+				Method: r.Method,
+				Path:   r.Path,
+				Chain:  append(chain, handlerNames(r.Handlers)...),
+			})
+			continue
+		}
+		uses = append(uses, r)
+	}
+	return table
+}
+
+func sameRoute(a, b fiber.Route) bool {
+	if a.Method != b.Method || a.Path != b.Path || len(a.Handlers) != len(b.Handlers) {
+		return false
+	}
+	for i := range a.Handlers {
+		if reflect.ValueOf(a.Handlers[i]).Pointer() != reflect.ValueOf(b.Handlers[i]).Pointer() {
+			return false
+		}
+	}
+	return true
+}
+
+func usePrefixMatches(prefix, path string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// shadows reports whether every request matching later also matches
+// earlier. Only plain segments and parameters are compared; wildcard and
+// optional segments are never considered shadowing.
+func shadows(earlier, later string) bool {
+	if earlier == later {
+		return true
+	}
+	a, b := strings.Split(strings.Trim(earlier, "/"), "/"), strings.Split(strings.Trim(later, "/"), "/")
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		switch {
+		case strings.ContainsAny(a[i], "*+?") || strings.ContainsAny(b[i], "*+?"):
+			return false
+		case strings.HasPrefix(a[i], ":"):
+			continue
+		case a[i] != b[i]:
+			return false
+		}
+	}
+	return true
+}
+
+var closureSuffix = regexp.MustCompile(`(\.func\d+)+$|-fm$`)
+
+// handlerNames turns handler funcs into short names such as
+// "middleware.Auth" or "handler.(*UserHandler).Create".
+func handlerNames(handlers []fiber.Handler) []string {
+	names := make([]string, len(handlers))
+	for i, h := range handlers {
+		name := "unknown"
+		if fn := runtime.FuncForPC(reflect.ValueOf(h).Pointer()); fn != nil {
+			name = fn.Name()
+		}
+		name = closureSuffix.ReplaceAllString(name, "")
+		if slash := strings.LastIndex(name, "/"); slash >= 0 {
+			name = name[slash+1:]
+		}
+		names[i] = name
+	}
+	return names
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
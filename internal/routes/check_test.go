@@ -0,0 +1,96 @@
+package routes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/policy"
+)
+
+func ok(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) }
+
+func TestCheckPassesProtectedGroup(t *testing.T) {
+	app := fiber.New()
+	users := app.Group("/users")
+	users.Use(middleware.Auth(middleware.AuthConfig{Secret: "secret"}))
+	users.Get("/me", ok)
+	users.Get("/:id", ok)
+
+	if err := Check(app, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	table := Table(app)
+	if len(table) != 2 {
+		t.Fatalf("expected 2 routes without HEAD duplicates, got %d", len(table))
+	}
+	chain := strings.Join(table[0].Chain, " ")
+	if table[0].Path != "/users/me" || chain != "middleware.Auth routes.ok" {
+		t.Errorf("unexpected first route %s %v", table[0].Path, table[0].Chain)
+	}
+}
+
+func TestCheckMissingAuth(t *testing.T) {
+	app := fiber.New()
+	// Registered before the group's Use, so the middleware never runs.
+	app.Get("/api/admin/debug", ok)
+	admin := app.Group("/api/admin")
+	admin.Use(middleware.Auth(middleware.AuthConfig{Secret: "secret"}), middleware.RequireRole(policy.NewEngine(), "admin"))
+	admin.Get("/users", ok)
+
+	err := Check(app, "/api")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{"GET /api/admin/debug is missing middleware.Auth", "GET /api/admin/debug is missing middleware.RequireRole"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "/api/admin/users") {
+		t.Errorf("did not expect /api/admin/users to be reported: %v", err)
+	}
+}
+
+func TestCheckUnreachableRoutes(t *testing.T) {
+	app := fiber.New()
+	app.Get("/things/:id", ok)
+	app.Get("/things/mine", ok)
+	app.Post("/things", ok)
+	app.Post("/other", ok)
+	app.Post("/things", ok)
+
+	err := Check(app, "")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, want := range []string{"GET /things/mine is unreachable", "POST /things is unreachable"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+	if strings.Contains(err.Error(), "HEAD") {
+		t.Errorf("expected HEAD mirrors of GET routes not to be reported twice: %v", err)
+	}
+}
+
+func TestShadows(t *testing.T) {
+	tests := []struct {
+		earlier, later string
+		expected       bool
+	}{
+		{"/users/:id", "/users/me", true},
+		{"/users/me", "/users/:id", false},
+		{"/users/:id", "/users/:id/keys", false},
+		{"/files/*", "/files/a", false},
+		{"/a/:x/c", "/a/b/c", true},
+	}
+	for _, tt := range tests {
+		if got := shadows(tt.earlier, tt.later); got != tt.expected {
+			t.Errorf("shadows(%q, %q) = %v, expected %v", tt.earlier, tt.later, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,239 @@
+package routes
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/models"
+)
+
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// Collection is a Postman collection (format v2.1) of the API. Requests go
+// to the baseUrl variable. Signed-in routes send the token variable as their
+// bearer token, which the login request fills in; SCIM routes send
+// scimToken.
+type Collection struct {
+	Info     CollectionInfo       `json:"info"`
+	Variable []CollectionVariable `json:"variable"`
+	Item     []CollectionFolder   `json:"item"`
+}
+
+type CollectionInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+type CollectionAuth struct {
+	Type   string               `json:"type"`
+	Bearer []CollectionVariable `json:"bearer,omitempty"`
+}
+
+type CollectionVariable struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Type  string `json:"type,omitempty"`
+}
+
+type CollectionFolder struct {
+	Name string           `json:"name"`
+	Item []CollectionItem `json:"item"`
+}
+
+type CollectionItem struct {
+	Name    string            `json:"name"`
+	Request CollectionRequest `json:"request"`
+	Event   []CollectionEvent `json:"event,omitempty"`
+}
+
+type CollectionRequest struct {
+	Method string             `json:"method"`
+	Auth   *CollectionAuth    `json:"auth,omitempty"`
+	Header []CollectionHeader `json:"header"`
+	Body   *CollectionBody    `json:"body,omitempty"`
+	URL    CollectionURL      `json:"url"`
+}
+
+type CollectionHeader struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+type CollectionBody struct {
+	Mode    string          `json:"mode"`
+	Raw     string          `json:"raw"`
+	Options json.RawMessage `json:"options"`
+}
+
+type CollectionURL struct {
+	Raw      string               `json:"raw"`
+	Host     []string             `json:"host"`
+	Path     []string             `json:"path"`
+	Variable []CollectionVariable `json:"variable,omitempty"`
+}
+
+type CollectionEvent struct {
+	Listen string           `json:"listen"`
+	Script CollectionScript `json:"script"`
+}
+
+type CollectionScript struct {
+	Type string   `json:"type"`
+	Exec []string `json:"exec"`
+}
+
+// requestExamples are the bodies the collection sends, by the handler that
+// reads them. Routes whose handler isn't listed are sent without a body.
+var requestExamples = map[string]interface{}{
+	"handler.(*AuthHandler).Signup":                models.SignupRequest{Name: "Jane Doe", Email: "jane@example.com", Password: "SecurePass123!", Dob: "1990-01-01"},
+	"handler.(*AuthHandler).Login":                 models.LoginRequest{Email: "jane@example.com", Password: "SecurePass123!"},
+	"handler.(*AuthHandler).ConfirmLoginChallenge": models.LoginChallengeRequest{ChallengeID: "<challenge_id from the login>", Code: "<code from the email>"},
+	"handler.(*AuthHandler).RequestMagicLink":      models.MagicLinkRequest{Email: "jane@example.com", Locale: "en"},
+	"handler.(*AuthHandler).ResetPassword":         models.PasswordResetRequest{Token: "<token from the reset link>", Password: "NewSecurePass123!"},
+	"handler.(*AuthHandler).ChangePassword":        models.ChangePasswordRequest{CurrentPassword: "SecurePass123!", NewPassword: "NewSecurePass123!"},
+	"handler.(*AuthHandler).Refresh":               models.RefreshRequest{RefreshToken: "{{refreshToken}}"},
+	"handler.(*AuthHandler).Logout":                models.RefreshRequest{RefreshToken: "{{refreshToken}}"},
+	"handler.(*SSOHandler).Discover":               models.SSODiscoverRequest{Email: "jane@example.com"},
+	"handler.(*DeviceHandler).Token":               models.DeviceTokenRequest{GrantType: models.DeviceGrantType, DeviceCode: "<device code>"},
+	"handler.(*DeviceHandler).Approve":             models.DeviceApproveRequest{UserCode: "<user code>"},
+	"handler.(*DeviceHandler).Deny":                models.DeviceApproveRequest{UserCode: "<user code>"},
+	"handler.(*UserHandler).Create":                models.UserRequest{Name: "John Doe", Dob: "1985-06-15"},
+	"handler.(*UserHandler).Update":                models.UserRequest{Name: "John Doe", Dob: "1985-06-15"},
+	"handler.(*UserHandler).UpdateProfile":         models.ProfileUpdateRequest{Bio: stringExample("Backend developer"), Timezone: stringExample("Europe/Madrid")},
+	"handler.(*AdminHandler).UpdateRole":           models.RoleUpdateRequest{Role: "moderator"},
+	"handler.(*ServiceAccountHandler).Create":      models.ServiceAccountRequest{Name: "Reporting job", Role: "user"},
+	"handler.(*ServiceAccountHandler).CreateKey":   models.APIKeyRequest{Name: "ci", Scopes: []string{"users:read"}, ExpiresInDays: 90},
+	"handler.(*OrganizationHandler).Create":        models.OrganizationRequest{Name: "Acme"},
+	"handler.(*OrganizationHandler).SetMember":     models.OrganizationMemberRequest{OrgID: 1},
+	"handler.(*NotificationHandler).CreateRule":    models.NotificationRuleRequest{EventType: "role_changed", Channel: "email", Audience: "user"},
+	"handler.(*NotificationHandler).UpdateRule":    models.NotificationRuleRequest{EventType: "role_changed", Channel: "in_app", Audience: "admins"},
+	"handler.(*NotificationHandler).SetPreference": models.NotificationPreference{EventType: "role_changed", Channel: "email", Enabled: new(bool)},
+}
+
+// stringExample is for the optional fields of request examples.
+func stringExample(s string) *string {
+	return &s
+}
+
+// saveTokens stores the access token cookie and the refresh token of a
+// login, so the requests after it are signed in.
+var saveTokens = []string{
+	`const token = pm.cookies.get("token");`,
+	`if (token) { pm.collectionVariables.set("token", token); }`,
+	`const body = pm.response.json();`,
+	`if (body.refresh_token) { pm.collectionVariables.set("refreshToken", body.refresh_token); }`,
+}
+
+var tokenScripts = map[string][]string{
+	"handler.(*AuthHandler).Login":                 saveTokens,
+	"handler.(*AuthHandler).ConfirmLoginChallenge": saveTokens,
+	"handler.(*AuthHandler).Refresh":               saveTokens,
+}
+
+// bearerVariables names the variable holding the bearer token for each
+// middleware that takes one. Routes without any are sent without auth.
+var bearerVariables = map[string]string{
+	"middleware.Auth":     "token",
+	"middleware.SCIMAuth": "scimToken",
+}
+
+// collectionPath is where CollectionHandler is registered, relative to the
+// API's prefix.
+const collectionPath = "/docs/collection.json"
+
+// BuildCollection returns a Postman collection of app's routes under
+// prefix, with one folder per path segment after it, sorted by path.
+// baseUrl is set to baseURL plus prefix.
+func BuildCollection(app *fiber.App, prefix, name, baseURL string) Collection {
+	coll := Collection{
+		Info: CollectionInfo{Name: name, Schema: postmanSchema},
+		Variable: []CollectionVariable{
+			{Key: "baseUrl", Value: strings.TrimSuffix(baseURL, "/") + prefix},
+			{Key: "token", Value: ""},
+			{Key: "refreshToken", Value: ""},
+			{Key: "scimToken", Value: ""},
+		},
+		Item: []CollectionFolder{},
+	}
+
+	var table []RouteInfo
+	for _, r := range Table(app) {
+		if strings.HasPrefix(r.Path, prefix+"/") {
+			r.Path = strings.TrimPrefix(r.Path, prefix)
+			table = append(table, r)
+		}
+	}
+	sort.SliceStable(table, func(i, j int) bool { return table[i].Path < table[j].Path })
+	folders := make(map[string]int)
+	for _, r := range table {
+		path := strings.Split(strings.Trim(r.Path, "/"), "/")
+		folder, ok := folders[path[0]]
+		if !ok {
+			folder = len(coll.Item)
+			folders[path[0]] = folder
+			coll.Item = append(coll.Item, CollectionFolder{Name: path[0]})
+		}
+		coll.Item[folder].Item = append(coll.Item[folder].Item, collectionItem(r, path))
+	}
+	return coll
+}
+
+func collectionItem(r RouteInfo, path []string) CollectionItem {
+	item := CollectionItem{
+		Name: r.Method + " " + r.Path,
+		Request: CollectionRequest{
+			Method: r.Method,
+			Header: []CollectionHeader{},
+			URL: CollectionURL{
+				Raw:  "{{baseUrl}}" + r.Path,
+				Host: []string{"{{baseUrl}}"},
+				Path: path,
+			},
+		},
+	}
+	for _, segment := range path {
+		if strings.HasPrefix(segment, ":") {
+			item.Request.URL.Variable = append(item.Request.URL.Variable, CollectionVariable{Key: strings.TrimPrefix(segment, ":")})
+		}
+	}
+
+	item.Request.Auth = &CollectionAuth{Type: "noauth"}
+	for _, mw := range r.Chain {
+		if variable, ok := bearerVariables[mw]; ok {
+			item.Request.Auth = &CollectionAuth{Type: "bearer", Bearer: []CollectionVariable{{Key: "token", Value: "{{" + variable + "}}", Type: "string"}}}
+			break
+		}
+	}
+
+	handler := ""
+	if len(r.Chain) > 0 {
+		handler = r.Chain[len(r.Chain)-1]
+	}
+	if example, ok := requestExamples[handler]; ok {
+		// The examples are plain structs, so marshalling can't fail.
+		raw, _ := json.MarshalIndent(example, "", "  ")
+		item.Request.Header = append(item.Request.Header, CollectionHeader{Key: fiber.HeaderContentType, Value: fiber.MIMEApplicationJSON})
+		item.Request.Body = &CollectionBody{
+			Mode:    "raw",
+			Raw:     string(raw),
+			Options: json.RawMessage(`{"raw":{"language":"json"}}`),
+		}
+	}
+	if script, ok := tokenScripts[handler]; ok {
+		item.Event = []CollectionEvent{{Listen: "test", Script: CollectionScript{Type: "text/javascript", Exec: script}}}
+	}
+	return item
+}
+
+// CollectionHandler serves the Postman collection of the API it is
+// registered in, at docs/collection.json under the API's prefix. It is
+// built on each request, so routes added after Register are listed too.
+func CollectionHandler(name, baseURL string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		prefix := strings.TrimSuffix(c.Route().Path, collectionPath)
+		return c.JSON(BuildCollection(c.App(), prefix, name, baseURL))
+	}
+}
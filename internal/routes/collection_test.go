@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/handler"
+	"BACKEND/internal/middleware"
+)
+
+func TestBuildCollection(t *testing.T) {
+	authHandler := handler.NewAuthHandler(nil, zap.NewNop(), false)
+	userHandler := handler.NewUserHandler(nil, nil, zap.NewNop())
+
+	app := fiber.New()
+	app.Post("/auth/login", authHandler.Login)
+	users := app.Group("/users")
+	users.Use(middleware.Auth(middleware.AuthConfig{Secret: "secret"}))
+	users.Put("/:id", userHandler.Update)
+	users.Get("/me", userHandler.GetCurrentUser)
+
+	coll := BuildCollection(app, "", "Test API", "http://localhost:8080")
+	if len(coll.Item) != 2 || coll.Item[0].Name != "auth" || coll.Item[1].Name != "users" {
+		t.Fatalf("folders = %+v", coll.Item)
+	}
+
+	login := coll.Item[0].Item[0]
+	if login.Request.Auth.Type != "noauth" || login.Request.Body == nil || !strings.Contains(login.Request.Body.Raw, `"password"`) {
+		t.Errorf("login request = %+v", login.Request)
+	}
+	if len(login.Event) != 1 || !strings.Contains(strings.Join(login.Event[0].Script.Exec, "\n"), `collectionVariables.set("token"`) {
+		t.Errorf("login doesn't save the token: %+v", login.Event)
+	}
+
+	update := coll.Item[1].Item[0]
+	if update.Name != "PUT /users/:id" || update.Request.URL.Raw != "{{baseUrl}}/users/:id" {
+		t.Errorf("update item = %+v", update)
+	}
+	if len(update.Request.URL.Variable) != 1 || update.Request.URL.Variable[0].Key != "id" {
+		t.Errorf("path variables = %+v", update.Request.URL.Variable)
+	}
+	if update.Request.Auth.Type != "bearer" || update.Request.Auth.Bearer[0].Value != "{{token}}" {
+		t.Errorf("update auth = %+v", update.Request.Auth)
+	}
+	if update.Request.Body == nil || !strings.Contains(update.Request.Body.Raw, `"dob"`) {
+		t.Errorf("update body = %+v", update.Request.Body)
+	}
+	if me := coll.Item[1].Item[1]; me.Request.Body != nil {
+		t.Errorf("GET /users/me has a body: %+v", me.Request.Body)
+	}
+}
@@ -0,0 +1,165 @@
+package routes
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// CheckResponse reports where a response departs from doc: a status the
+// operation does not list, a body that does not match its schema, required
+// properties that are missing, properties the schema does not know, and
+// strings that break their format or enum. route is the route's path from
+// the route table, e.g. "/users/:id" under prefix. Error statuses are
+// checked against the operation's default response, or against
+// ErrorResponse when middleware answered before any operation matched.
+func (doc OpenAPI) CheckResponse(prefix, method, route string, status int, body []byte) []string {
+	key := specPath(strings.TrimPrefix(route, prefix))
+	op, ok := doc.Paths[key][strings.ToLower(method)]
+	if !ok && status >= 400 {
+		op.Responses = map[string]OpenAPIResponse{"default": {Content: map[string]OpenAPIMediaType{
+			fiber.MIMEApplicationJSON: {Schema: &Schema{Ref: "#/components/schemas/ErrorResponse"}},
+		}}}
+	} else if !ok {
+		return []string{fmt.Sprintf("%s %s is not in the spec", method, key)}
+	}
+	resp, ok := op.Responses[strconv.Itoa(status)]
+	if !ok && status >= 400 {
+		resp, ok = op.Responses["default"]
+	}
+	if !ok {
+		return []string{fmt.Sprintf("%s %s answered %d, which the spec does not list", method, key, status)}
+	}
+
+	for _, media := range resp.Content {
+		var v interface{}
+		if err := json.Unmarshal(body, &v); err != nil {
+			return []string{fmt.Sprintf("%s %s %d: body is not JSON: %v", method, key, status, err)}
+		}
+		c := contractChecker{schemas: doc.Components.Schemas}
+		c.check("$", media.Schema, v)
+		for i, problem := range c.problems {
+			c.problems[i] = fmt.Sprintf("%s %s %d: %s", method, key, status, problem)
+		}
+		return c.problems
+	}
+	return nil
+}
+
+// specPath turns a route path such as "/users/:id" into the spec's
+// "/users/{id}".
+func specPath(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + strings.TrimPrefix(segment, ":") + "}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
+
+type contractChecker struct {
+	schemas  map[string]*Schema
+	problems []string
+}
+
+func (c *contractChecker) fail(at, format string, args ...interface{}) {
+	c.problems = append(c.problems, at+": "+fmt.Sprintf(format, args...))
+}
+
+func (c *contractChecker) check(at string, s *Schema, v interface{}) {
+	if s == nil {
+		return
+	}
+	if s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+		if s = c.schemas[name]; s == nil {
+			c.fail(at, "schema %s is not defined", name)
+			return
+		}
+	}
+	// Nil slices, maps and pointers are encoded as null.
+	if v == nil {
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			c.fail(at, "expected an object, got %T", v)
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				c.fail(at, "required property %q is missing", name)
+			}
+		}
+		for name, value := range obj {
+			switch prop, ok := s.Properties[name]; {
+			case ok:
+				c.check(at+"."+name, prop, value)
+			case s.AdditionalProperties != nil:
+				c.check(at+"."+name, s.AdditionalProperties, value)
+			case len(s.Properties) > 0:
+				c.fail(at, "property %q is not in the schema", name)
+			}
+		}
+	case "array":
+		items, ok := v.([]interface{})
+		if !ok {
+			c.fail(at, "expected an array, got %T", v)
+			return
+		}
+		for i, item := range items {
+			c.check(at+"["+strconv.Itoa(i)+"]", s.Items, item)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			c.fail(at, "expected a string, got %T", v)
+			return
+		}
+		if len(s.Enum) > 0 && !contains(s.Enum, str) {
+			c.fail(at, "%q is not one of %v", str, s.Enum)
+		}
+		if !validFormat(s.Format, str) {
+			c.fail(at, "%q is not a valid %s", str, s.Format)
+		}
+	case "integer":
+		n, ok := v.(float64)
+		if !ok || n != math.Trunc(n) {
+			c.fail(at, "expected an integer, got %v", v)
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			c.fail(at, "expected a number, got %T", v)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			c.fail(at, "expected a boolean, got %T", v)
+		}
+	}
+}
+
+func validFormat(format, s string) bool {
+	var err error
+	switch format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339Nano, s)
+	case "date":
+		_, err = time.Parse("2006-01-02", s)
+	case "email":
+		_, err = mail.ParseAddress(s)
+	case "uuid":
+		_, err = uuid.Parse(s)
+	}
+	return err == nil
+}
@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/handler"
+)
+
+func TestCheckResponse(t *testing.T) {
+	authHandler := handler.NewAuthHandler(nil, zap.NewNop(), false)
+	app := fiber.New()
+	app.Post("/api/auth/signup", authHandler.Signup)
+	doc := BuildOpenAPI(app, "/api", "Test API", "http://localhost:8080")
+
+	const created = `{"id":"3f1c6b0e-8d4a-4c55-9b0e-2f7a1d9c6e21","name":"Jane","email":"jane@example.com","role":"user","created_at":"2024-01-01T00:00:00Z"}`
+	tests := []struct {
+		name     string
+		route    string
+		status   int
+		body     string
+		expected string
+	}{
+		{"matches", "/api/auth/signup", 201, created, ""},
+		{"error", "/api/auth/signup", 400, `{"error":{"code":"VALIDATION_FAILED","message":"bad"}}`, ""},
+		{"middleware error", "/api/users", 401, `{"error":{"code":"UNAUTHORIZED","message":"no"}}`, ""},
+		{"unknown route", "/api/other", 200, `{}`, "not in the spec"},
+		{"unlisted status", "/api/auth/signup", 200, created, "does not list"},
+		{"bad format", "/api/auth/signup", 201, strings.Replace(created, "3f1c6b0e-8d4a-4c55-9b0e-2f7a1d9c6e21", "7", 1), `"7" is not a valid uuid`},
+		{"wrong type", "/api/auth/signup", 201, strings.Replace(created, `"Jane"`, `42`, 1), "$.name: expected a string"},
+		{"extra property", "/api/auth/signup", 201, strings.Replace(created, `"role"`, `"admin":true,"role"`, 1), `property "admin" is not in the schema`},
+		{"error shape", "/api/auth/signup", 500, `{"error":"boom"}`, "$.error: expected an object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := doc.CheckResponse("/api", "POST", tt.route, tt.status, []byte(tt.body))
+			if tt.expected == "" {
+				if len(problems) > 0 {
+					t.Errorf("CheckResponse() = %v, expected no problems", problems)
+				}
+				return
+			}
+			if len(problems) != 1 || !strings.Contains(problems[0], tt.expected) {
+				t.Errorf("CheckResponse() = %v, expected one containing %q", problems, tt.expected)
+			}
+		})
+	}
+}
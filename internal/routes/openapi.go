@@ -0,0 +1,453 @@
+package routes
+
+import (
+	"html/template"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"BACKEND/internal/models"
+	"BACKEND/internal/version"
+)
+
+// OpenAPI is an OpenAPI 3.0 document of the API. Operations come from the
+// route table and schemas from the models, so neither can fall behind the
+// code; see BuildOpenAPI.
+type OpenAPI struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       OpenAPIInfo                            `json:"info"`
+	Servers    []OpenAPIServer                        `json:"servers"`
+	Paths      map[string]map[string]OpenAPIOperation `json:"paths"`
+	Components OpenAPIComponents                      `json:"components"`
+}
+
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type OpenAPIServer struct {
+	URL string `json:"url"`
+}
+
+type OpenAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Tags        []string                   `json:"tags"`
+	Parameters  []OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]OpenAPIResponse `json:"responses"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+}
+
+type OpenAPIParameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+type OpenAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]OpenAPIMediaType `json:"content"`
+}
+
+type OpenAPIMediaType struct {
+	Schema  *Schema     `json:"schema"`
+	Example interface{} `json:"example,omitempty"`
+}
+
+type OpenAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]OpenAPIMediaType `json:"content,omitempty"`
+}
+
+type OpenAPIComponents struct {
+	Schemas         map[string]*Schema               `json:"schemas"`
+	SecuritySchemes map[string]OpenAPISecurityScheme `json:"securitySchemes"`
+}
+
+type OpenAPISecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// Schema is the subset of the OpenAPI schema object that the models need.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     bool               `json:"exclusiveMinimum,omitempty"`
+}
+
+// requestModels are the bodies read by handlers that the Postman collection
+// sends without one. Together with requestExamples they give every
+// operation that takes a body its schema.
+var requestModels = map[string]interface{}{
+	"handler.(*WebAuthnHandler).RegisterFinish": models.PasskeyRegisterRequest{},
+	"handler.(*SCIMHandler).CreateUser":         models.SCIMUser{},
+	"handler.(*SCIMHandler).ReplaceUser":        models.SCIMUser{},
+	"handler.(*SCIMHandler).PatchUser":          models.SCIMPatchRequest{},
+}
+
+type responseModel struct {
+	status int
+	model  interface{}
+}
+
+// responseModels are the success responses of the handlers that answer
+// with a model or with a status other than 200. Other operations are
+// documented with a bare 200.
+var responseModels = map[string]responseModel{
+	"handler.(*HealthHandler).Live":                   {fiber.StatusOK, models.HealthResponse{}},
+	"handler.(*HealthHandler).Ready":                  {fiber.StatusOK, models.ReadinessResponse{}},
+	"handler.(*AuthHandler).Signup":                   {fiber.StatusCreated, models.SignupResponse{}},
+	"handler.(*AuthHandler).Login":                    {fiber.StatusOK, models.LoginResponse{}},
+	"handler.(*AuthHandler).VerifyMagicLink":          {fiber.StatusOK, models.LoginResponse{}},
+	"handler.(*AuthHandler).ConfirmLoginChallenge":    {fiber.StatusOK, models.LoginResponse{}},
+	"handler.(*AuthHandler).Refresh":                  {fiber.StatusOK, models.LoginResponse{}},
+	"handler.(*SSOHandler).Discover":                  {fiber.StatusOK, models.SSODiscoverResponse{}},
+	"handler.(*SSOHandler).Callback":                  {fiber.StatusOK, models.LoginResponse{}},
+	"handler.(*IdentityHandler).Callback":             {fiber.StatusOK, models.LoginResponse{}},
+	"handler.(*IdentityHandler).List":                 {fiber.StatusOK, models.IdentityListResponse{}},
+	"handler.(*DeviceHandler).Token":                  {fiber.StatusOK, models.DeviceTokenResponse{}},
+	"handler.(*WebAuthnHandler).LoginFinish":          {fiber.StatusOK, models.LoginResponse{}},
+	"handler.(*WebAuthnHandler).List":                 {fiber.StatusOK, models.PasskeyListResponse{}},
+	"handler.(*AuthHandler).ListSessions":             {fiber.StatusOK, models.SessionListResponse{}},
+	"handler.(*EmailDeliveryHandler).ListDeadLetters": {fiber.StatusOK, models.EmailDeadLetterListResponse{}},
+	"handler.(*AdminHandler).LookupUsers":             {fiber.StatusOK, models.UserLookupListResponse{}},
+	"handler.(*NotificationHandler).ListRules":        {fiber.StatusOK, models.NotificationRuleListResponse{}},
+	"handler.(*NotificationHandler).CreateRule":       {fiber.StatusCreated, models.NotificationRuleResponse{}},
+	"handler.(*NotificationHandler).UpdateRule":       {fiber.StatusOK, models.NotificationRuleResponse{}},
+	"handler.(*NotificationHandler).Mine":             {fiber.StatusOK, models.NotificationListResponse{}},
+	"handler.(*NotificationHandler).Preferences":      {fiber.StatusOK, models.NotificationPreferenceListResponse{}},
+	"handler.(*NotificationHandler).SetPreference":    {fiber.StatusOK, models.NotificationPreference{}},
+	"handler.(*UserHandler).Create":                   {fiber.StatusCreated, models.UserResponse{}},
+	"handler.(*UserHandler).Update":                   {fiber.StatusOK, models.UserResponse{}},
+	"handler.(*UserHandler).GetByID":                  {fiber.StatusOK, models.UserWithAgeResponse{}},
+	"handler.(*UserHandler).GetCurrentUser":           {fiber.StatusOK, models.CurrentUserResponse{}},
+	"handler.(*UserHandler).UpdateProfile":            {fiber.StatusOK, models.CurrentUserResponse{}},
+	"handler.(*UserHandler).UploadAvatar":             {fiber.StatusOK, models.CurrentUserResponse{}},
+	"handler.(*UserHandler).GetCurrentClaims":         {fiber.StatusOK, models.ClaimsResponse{}},
+	"handler.(*UserHandler).GetAge":                   {fiber.StatusOK, models.AgeResponse{}},
+	"handler.(*UserHandler).List":                     {fiber.StatusOK, models.PaginatedUsersResponse{}},
+	"handler.(*MeteringHandler).Mine":                 {fiber.StatusOK, models.APIUsageResponse{}},
+	"handler.(*MeteringHandler).ForUser":              {fiber.StatusOK, models.APIUsageResponse{}},
+	"handler.(*MeteringHandler).Top":                  {fiber.StatusOK, models.TopAPIUsageResponse{}},
+	"handler.(*SecurityHandler).Verify":               {fiber.StatusOK, models.SecurityLogVerification{}},
+	"handler.(*OrganizationHandler).Create":           {fiber.StatusCreated, models.OrganizationResponse{}},
+	"handler.(*OrganizationHandler).List":             {fiber.StatusOK, models.OrganizationListResponse{}},
+	"handler.(*OrganizationHandler).Usage":            {fiber.StatusOK, models.OrganizationUsageResponse{}},
+	"handler.(*BillingHandler).Status":                {fiber.StatusOK, models.BillingResponse{}},
+	"handler.(*SCIMHandler).ListUsers":                {fiber.StatusOK, models.SCIMListResponse{}},
+	"handler.(*SCIMHandler).GetUser":                  {fiber.StatusOK, models.SCIMUser{}},
+	"handler.(*SCIMHandler).CreateUser":               {fiber.StatusCreated, models.SCIMUser{}},
+	"handler.(*SCIMHandler).ReplaceUser":              {fiber.StatusOK, models.SCIMUser{}},
+	"handler.(*SCIMHandler).PatchUser":                {fiber.StatusOK, models.SCIMUser{}},
+	"handler.(*WebAuthnHandler).RegisterFinish":       {fiber.StatusCreated, models.PasskeyResponse{}},
+	"handler.(*AuthHandler).RequestMagicLink":         {fiber.StatusAccepted, nil},
+	"handler.(*AdminHandler).ForcePasswordReset":      {fiber.StatusAccepted, nil},
+	"handler.(*AdminHandler).DeleteUser":              {fiber.StatusNoContent, nil},
+	"handler.(*BackupHandler).Create":                 {fiber.StatusAccepted, nil},
+	"handler.(*ExportHandler).Create":                 {fiber.StatusAccepted, nil},
+	"handler.(*DeviceHandler).Approve":                {fiber.StatusNoContent, nil},
+	"handler.(*DeviceHandler).Deny":                   {fiber.StatusNoContent, nil},
+	"handler.(*IdentityHandler).Unlink":               {fiber.StatusNoContent, nil},
+	"handler.(*OrganizationHandler).SetMember":        {fiber.StatusNoContent, nil},
+	"handler.(*OrganizationHandler).RemoveMember":     {fiber.StatusNoContent, nil},
+	"handler.(*SCIMHandler).DeleteUser":               {fiber.StatusNoContent, nil},
+	"handler.(*ServiceAccountHandler).Create":         {fiber.StatusCreated, nil},
+	"handler.(*ServiceAccountHandler).CreateKey":      {fiber.StatusCreated, nil},
+	"handler.(*ServiceAccountHandler).RevokeKey":      {fiber.StatusNoContent, nil},
+	"handler.(*WebAuthnHandler).Delete":               {fiber.StatusNoContent, nil},
+	"handler.(*EmailDeliveryHandler).RetryDeadLetter": {fiber.StatusNoContent, nil},
+	"handler.(*NotificationHandler).DeleteRule":       {fiber.StatusNoContent, nil},
+	"handler.(*NotificationHandler).MarkRead":         {fiber.StatusNoContent, nil},
+	"handler.(*UserHandler).DeleteAvatar":             {fiber.StatusNoContent, nil},
+}
+
+// securitySchemes names the security scheme for each middleware that takes
+// a bearer token, like bearerVariables does for the collection.
+var securitySchemes = map[string]string{
+	"middleware.Auth":      "bearerAuth",
+	"middleware.SCIMAuth":  "scimToken",
+	"middleware.BasicAuth": "basicAuth",
+}
+
+// openAPIPath and swaggerUIPath are where OpenAPIHandler and
+// SwaggerUIHandler are registered, relative to the API's prefix.
+const (
+	openAPIPath   = "/docs/openapi.json"
+	swaggerUIPath = "/docs"
+)
+
+// BuildOpenAPI returns an OpenAPI document of app's routes under prefix,
+// tagged by the path segment after it. Request and response schemas are
+// derived from the models' json and validate tags, plus a format tag for
+// strings with a fixed layout such as dates and public IDs; errors use
+// ErrorResponse, or SCIMError under /scim.
+func BuildOpenAPI(app *fiber.App, prefix, name, baseURL string) OpenAPI {
+	doc := OpenAPI{
+		OpenAPI: "3.0.3",
+		Info:    OpenAPIInfo{Title: name, Version: version.Version},
+		Servers: []OpenAPIServer{{URL: strings.TrimSuffix(baseURL, "/") + prefix}},
+		Paths:   make(map[string]map[string]OpenAPIOperation),
+		Components: OpenAPIComponents{
+			Schemas: make(map[string]*Schema),
+			SecuritySchemes: map[string]OpenAPISecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+				"scimToken":  {Type: "http", Scheme: "bearer"},
+				"basicAuth":  {Type: "http", Scheme: "basic"},
+			},
+		},
+	}
+	schemas := schemaBuilder(doc.Components.Schemas)
+
+	for _, r := range Table(app) {
+		if !strings.HasPrefix(r.Path, prefix+"/") {
+			continue
+		}
+		path := strings.Split(strings.Trim(strings.TrimPrefix(r.Path, prefix), "/"), "/")
+		op := OpenAPIOperation{
+			Tags:      []string{path[0]},
+			Responses: make(map[string]OpenAPIResponse),
+		}
+		for i, segment := range path {
+			if strings.HasPrefix(segment, ":") {
+				param := strings.TrimPrefix(segment, ":")
+				path[i] = "{" + param + "}"
+				op.Parameters = append(op.Parameters, OpenAPIParameter{Name: param, In: "path", Required: true, Schema: &Schema{Type: "string"}})
+			}
+		}
+		key := "/" + strings.Join(path, "/")
+
+		for _, mw := range r.Chain {
+			if scheme, ok := securitySchemes[mw]; ok {
+				op.Security = []map[string][]string{{scheme: {}}}
+				break
+			}
+		}
+
+		handler := ""
+		if len(r.Chain) > 0 {
+			handler = r.Chain[len(r.Chain)-1]
+		}
+		op.OperationID = operationID(r.Method, handler)
+
+		contentType := fiber.MIMEApplicationJSON
+		errorModel := interface{}(models.ErrorResponse{})
+		if path[0] == "scim" {
+			contentType = models.SCIMContentType
+			errorModel = models.SCIMError{}
+		}
+
+		body, example := requestModels[handler], interface{}(nil)
+		if ex, ok := requestExamples[handler]; ok {
+			body, example = ex, ex
+		}
+		if body != nil {
+			op.RequestBody = &OpenAPIRequestBody{
+				Required: true,
+				Content:  map[string]OpenAPIMediaType{contentType: {Schema: schemas(reflect.TypeOf(body)), Example: example}},
+			}
+		}
+
+		resp, ok := responseModels[handler]
+		if !ok {
+			resp.status = fiber.StatusOK
+		}
+		success := OpenAPIResponse{Description: http.StatusText(resp.status)}
+		if resp.model != nil {
+			success.Content = map[string]OpenAPIMediaType{contentType: {Schema: schemas(reflect.TypeOf(resp.model))}}
+		}
+		op.Responses[strconv.Itoa(resp.status)] = success
+		op.Responses["default"] = OpenAPIResponse{
+			Description: "Error",
+			Content:     map[string]OpenAPIMediaType{contentType: {Schema: schemas(reflect.TypeOf(errorModel))}},
+		}
+
+		if doc.Paths[key] == nil {
+			doc.Paths[key] = make(map[string]OpenAPIOperation)
+		}
+		doc.Paths[key][strings.ToLower(r.Method)] = op
+	}
+	return doc
+}
+
+// operationID turns "handler.(*UserHandler).GetByID" into
+// "UserHandler.GetByID". Handlers registered for more than one method, or
+// closures, get the method in front to keep IDs unique.
+func operationID(method, handler string) string {
+	id := strings.NewReplacer("handler.", "", "(*", "", ")", "").Replace(handler)
+	if !strings.HasPrefix(handler, "handler.(*") {
+		id = strings.ToLower(method) + "." + id
+	}
+	return id
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// schemaBuilder returns a function that describes a Go type as a schema.
+// Named structs are added to components once and referenced from then on.
+func schemaBuilder(components map[string]*Schema) func(reflect.Type) *Schema {
+	var build func(reflect.Type) *Schema
+	build = func(t reflect.Type) *Schema {
+		for t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		switch {
+		case t == timeType:
+			return &Schema{Type: "string", Format: "date-time"}
+		case t.Kind() == reflect.Struct && t.Name() != "":
+			if _, ok := components[t.Name()]; !ok {
+				// Reserve the name first so self-referencing types end.
+				components[t.Name()] = &Schema{}
+				*components[t.Name()] = *structSchema(t, build)
+			}
+			return &Schema{Ref: "#/components/schemas/" + t.Name()}
+		}
+
+		switch t.Kind() {
+		case reflect.Struct:
+			return structSchema(t, build)
+		case reflect.String:
+			return &Schema{Type: "string"}
+		case reflect.Bool:
+			return &Schema{Type: "boolean"}
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+			return &Schema{Type: "integer", Format: "int32"}
+		case reflect.Int64, reflect.Uint64:
+			return &Schema{Type: "integer", Format: "int64"}
+		case reflect.Float32, reflect.Float64:
+			return &Schema{Type: "number"}
+		case reflect.Slice, reflect.Array:
+			if t.Elem().Kind() == reflect.Uint8 {
+				return &Schema{Type: "string", Format: "byte"}
+			}
+			return &Schema{Type: "array", Items: build(t.Elem())}
+		case reflect.Map:
+			return &Schema{Type: "object", AdditionalProperties: build(t.Elem())}
+		}
+		// interface{} and anything else can hold any value.
+		return &Schema{}
+	}
+	return build
+}
+
+func structSchema(t reflect.Type, build func(reflect.Type) *Schema) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		prop := build(f.Type)
+		if format := f.Tag.Get("format"); format != "" {
+			prop.Format = format
+		}
+		if required := applyValidation(prop, f.Type, f.Tag.Get("validate")); required {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = prop
+	}
+	sort.Strings(s.Required)
+	return s
+}
+
+// applyValidation copies the rules of a validate tag that OpenAPI can
+// express onto prop, and reports whether the field is required.
+func applyValidation(prop *Schema, t reflect.Type, tag string) bool {
+	required := false
+	for _, rule := range strings.Split(tag, ",") {
+		name, arg, _ := strings.Cut(rule, "=")
+		n, numErr := strconv.Atoi(arg)
+		switch {
+		case name == "required":
+			required = true
+		case name == "email":
+			prop.Format = "email"
+		case name == "datetime" && arg == "2006-01-02":
+			prop.Format = "date"
+		case name == "oneof":
+			prop.Enum = strings.Fields(arg)
+		case numErr != nil:
+			continue
+		case name == "min" && t.Kind() == reflect.String:
+			prop.MinLength = &n
+		case name == "max" && t.Kind() == reflect.String:
+			prop.MaxLength = &n
+		case name == "min" && t.Kind() == reflect.Slice:
+			prop.MinItems = &n
+		case name == "min" || name == "gte":
+			v := float64(n)
+			prop.Minimum = &v
+		case name == "max" || name == "lte":
+			v := float64(n)
+			prop.Maximum = &v
+		case name == "gt":
+			v := float64(n)
+			prop.Minimum, prop.ExclusiveMinimum = &v, true
+		}
+	}
+	return required
+}
+
+// OpenAPIHandler serves the OpenAPI document of the API it is registered
+// in, at docs/openapi.json under the API's prefix. Like the collection it
+// is built on each request.
+func OpenAPIHandler(name, baseURL string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		prefix := strings.TrimSuffix(c.Route().Path, openAPIPath)
+		return c.JSON(BuildOpenAPI(c.App(), prefix, name, baseURL))
+	}
+}
+
+var swaggerUIPage = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+<script>
+window.ui = SwaggerUIBundle({ url: {{.Spec}}, dom_id: "#swagger-ui" });
+</script>
+</body>
+</html>
+`))
+
+// SwaggerUIHandler serves Swagger UI for the OpenAPI document, at docs
+// under the API's prefix. The UI's assets are loaded from unpkg.
+func SwaggerUIHandler(name string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		prefix := strings.TrimSuffix(c.Route().Path, swaggerUIPath)
+		c.Type("html", "utf-8")
+		return swaggerUIPage.Execute(c, struct{ Title, Spec string }{name, prefix + openAPIPath})
+	}
+}
@@ -0,0 +1,106 @@
+package routes
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/config"
+	"BACKEND/internal/handler"
+	"BACKEND/internal/middleware"
+)
+
+func TestBuildOpenAPI(t *testing.T) {
+	authHandler := handler.NewAuthHandler(nil, zap.NewNop(), false)
+	userHandler := handler.NewUserHandler(nil, nil, zap.NewNop())
+
+	app := fiber.New()
+	app.Post("/api/auth/signup", authHandler.Signup)
+	users := app.Group("/api/users")
+	users.Use(middleware.Auth(middleware.AuthConfig{Secret: "secret"}))
+	users.Put("/:id", userHandler.Update)
+	app.Get("/other", func(c *fiber.Ctx) error { return nil })
+
+	doc := BuildOpenAPI(app, "/api", "Test API", "http://localhost:8080/")
+	if doc.Servers[0].URL != "http://localhost:8080/api" || len(doc.Paths) != 2 {
+		t.Fatalf("servers %+v, paths %+v", doc.Servers, doc.Paths)
+	}
+
+	signup := doc.Paths["/auth/signup"]["post"]
+	if signup.Security != nil || signup.Tags[0] != "auth" || signup.OperationID != "AuthHandler.Signup" {
+		t.Errorf("signup operation = %+v", signup)
+	}
+	if body := signup.RequestBody.Content[fiber.MIMEApplicationJSON]; body.Schema.Ref != "#/components/schemas/SignupRequest" || body.Example == nil {
+		t.Errorf("signup body = %+v", body)
+	}
+	if created, ok := signup.Responses["201"]; !ok || created.Content[fiber.MIMEApplicationJSON].Schema.Ref != "#/components/schemas/SignupResponse" {
+		t.Errorf("signup responses = %+v", signup.Responses)
+	}
+	if errs := signup.Responses["default"]; errs.Content[fiber.MIMEApplicationJSON].Schema.Ref != "#/components/schemas/ErrorResponse" {
+		t.Errorf("signup error response = %+v", errs)
+	}
+
+	req := doc.Components.Schemas["SignupRequest"]
+	if strings.Join(req.Required, ",") != "dob,email,name,password" {
+		t.Errorf("SignupRequest required = %v", req.Required)
+	}
+	if req.Properties["email"].Format != "email" || req.Properties["dob"].Format != "date" || *req.Properties["name"].MinLength != 2 {
+		t.Errorf("SignupRequest properties = %+v", req.Properties)
+	}
+	if doc.Components.Schemas["SignupResponse"].Properties["id"].Format != "uuid" {
+		t.Error("SignupResponse id isn't a uuid")
+	}
+	if detail := doc.Components.Schemas["ErrorDetail"]; detail == nil || detail.Properties["code"].Type != "string" {
+		t.Errorf("ErrorDetail = %+v", detail)
+	}
+
+	update := doc.Paths["/users/{id}"]["put"]
+	if len(update.Parameters) != 1 || update.Parameters[0].Name != "id" || update.Parameters[0].In != "path" {
+		t.Errorf("update parameters = %+v", update.Parameters)
+	}
+	if len(update.Security) != 1 || update.Security[0]["bearerAuth"] == nil {
+		t.Errorf("update security = %+v", update.Security)
+	}
+}
+
+func TestSwaggerUIHandler(t *testing.T) {
+	app := fiber.New()
+	app.Get("/identity"+swaggerUIPath, SwaggerUIHandler("<Acme>"))
+
+	resp, err := app.Test(httptest.NewRequest("GET", "/identity/docs", nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	page, _ := io.ReadAll(resp.Body)
+	if !strings.HasPrefix(resp.Header.Get(fiber.HeaderContentType), fiber.MIMETextHTML) {
+		t.Errorf("Content-Type = %s", resp.Header.Get(fiber.HeaderContentType))
+	}
+	if !strings.Contains(string(page), `url: "/identity/docs/openapi.json"`) || !strings.Contains(string(page), "&lt;Acme&gt;") {
+		t.Errorf("page = %s", page)
+	}
+}
+
+func TestRegisterDocs(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		app := fiber.New()
+		registerDocs(app, &config.Config{DocsEnabled: enabled})
+
+		expected := fiber.StatusNotFound
+		if enabled {
+			expected = fiber.StatusOK
+		}
+		for _, path := range []string{collectionPath, openAPIPath, swaggerUIPath} {
+			resp, err := app.Test(httptest.NewRequest("GET", path, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != expected {
+				t.Errorf("DocsEnabled=%v: GET %s = %d, expected %d", enabled, path, resp.StatusCode, expected)
+			}
+		}
+	}
+}
@@ -3,37 +3,323 @@ package routes
 import (
 	"github.com/gofiber/fiber/v2"
 
+	"BACKEND/config"
 	"BACKEND/internal/handler"
 	"BACKEND/internal/middleware"
+	"BACKEND/internal/policy"
+	"BACKEND/internal/service"
+	"BACKEND/internal/storage"
 )
 
-func Register(app *fiber.App, h *handler.UserHandler, authHandler *handler.AuthHandler, adminHandler *handler.AdminHandler, jwtSecret string) {
-	
+// Deps holds what Register wires into the routes. Metrics, Analytics and
+// BackupHandler are nil when their feature is off.
+type Deps struct {
+	HealthHandler         *handler.HealthHandler
+	Metrics               *middleware.Metrics
+	UserHandler           *handler.UserHandler
+	AuthHandler           *handler.AuthHandler
+	AdminHandler          *handler.AdminHandler
+	ReportHandler         *handler.ReportHandler
+	ExportHandler         *handler.ExportHandler
+	EmailTemplateHandler  *handler.EmailTemplateHandler
+	EmailDeliveryHandler  *handler.EmailDeliveryHandler
+	NotificationHandler   *handler.NotificationHandler
+	FileHandler           *handler.FileHandler
+	SCIMHandler           *handler.SCIMHandler
+	SSOHandler            *handler.SSOHandler
+	ServiceAccountHandler *handler.ServiceAccountHandler
+	DeviceHandler         *handler.DeviceHandler
+	WebAuthnHandler       *handler.WebAuthnHandler
+	SystemHandler         *handler.SystemHandler
+	RetentionHandler      *handler.RetentionHandler
+	SecurityHandler       *handler.SecurityHandler
+	LoginHistoryHandler   *handler.LoginHistoryHandler
+	ModerationHandler     *handler.ModerationHandler
+	ReferralHandler       *handler.ReferralHandler
+	IdentityHandler       *handler.IdentityHandler
+	ConfigHandler         *handler.ConfigHandler
+	BackupHandler         *handler.BackupHandler
+	OrgHandler            *handler.OrganizationHandler
+	BillingHandler        *handler.BillingHandler
+	MeteringHandler       *handler.MeteringHandler
+
+	// Lookups the auth middleware needs.
+	APIKeys      middleware.APIKeyAuthenticator
+	Revocations  middleware.TokenRevocationChecker
+	IdleSessions *middleware.IdleSessions
+	UserIDs      middleware.UserIDResolver
+	OrgMembers   middleware.OrgMembershipResolver
+	Policies     *policy.Engine
+
+	// Middleware built from config; each may be a no-op.
+	GeoBlock         fiber.Handler
+	Chaos            fiber.Handler
+	Consistency      fiber.Handler
+	Limiter          *middleware.AdaptiveLimiter
+	Connections      *middleware.ConnectionStats
+	Deprecations     *middleware.Deprecations
+	Analytics        *middleware.EndpointAnalytics
+	RateLimiter      *middleware.RateLimiter
+	SensitiveLimiter *middleware.RateLimiter
+	Usage            []middleware.UsageRecorder
+}
+
+func Register(app fiber.Router, deps Deps, cfg *config.Config) {
+
+	// Probes and the metrics scrape come before any middleware: they must
+	// not be shed under load, would only clutter the request log and would
+	// skew the request metrics. Probes need no credentials.
+	app.Get("/healthz", deps.HealthHandler.Live)
+	app.Get("/readyz", deps.HealthHandler.Ready)
+	if deps.Metrics != nil {
+		if cfg.Metrics.Username != "" {
+			app.Get("/metrics", middleware.BasicAuth("metrics", cfg.Metrics.Username, cfg.Metrics.Password), deps.Metrics.Handler())
+		} else {
+			app.Get("/metrics", deps.Metrics.Handler())
+		}
+	}
+
+	app.Use(middleware.CollectMetrics(deps.Metrics))
+	app.Use(middleware.CountConnections(deps.Connections))
 	app.Use(middleware.RequestID())
+	app.Use(middleware.Tracing())
+	app.Use(middleware.TrackEndpoints(deps.Analytics))
 	app.Use(middleware.Logger())
+	app.Use(middleware.CORS(middleware.CORSConfig{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedMethods:   cfg.AllowedMethods,
+		AllowCredentials: cfg.AllowCredentials,
+	}))
+	app.Use(middleware.APIVersion())
+	app.Use(middleware.TrackDeprecations(deps.Deprecations))
+	app.Use(middleware.StrictJSON(cfg.StrictJSON))
+	app.Use(middleware.LoadShedding(deps.Limiter))
+	app.Use(deps.Chaos)
+	app.Use(deps.Consistency)
+	app.Use(middleware.TokenRevocation(deps.Revocations))
+	app.Use(middleware.SessionIdleTimeout(deps.IdleSessions))
 
-	
-	app.Post("/auth/signup", authHandler.Signup)
-	app.Post("/auth/login", authHandler.Login)
+	// Routes with a user :id take the user's public ID.
+	userParam := middleware.UserParam(deps.UserIDs)
+	authConfig := middleware.AuthConfig{
+		Secret:     cfg.JWTSecret,
+		Precedence: cfg.AuthTokenPrecedence,
+	}
+
+	app.Get("/version", deps.SystemHandler.Version)
+	app.Get("/avatars/:hash", deps.UserHandler.Avatar)
+	app.Get(storage.LocalPath+"*", deps.FileHandler.Serve)
+	registerDocs(app, cfg)
+	app.Get("/exports/:id/download", deps.ExportHandler.Download)
+	app.Post("/billing/webhook", deps.BillingHandler.Webhook)
+
+	// Password resets, magic links and passkey logins get their own, stricter
+	// limit on top of the general one.
+	sensitive := func(route string) fiber.Handler {
+		return middleware.RateLimitBy(deps.SensitiveLimiter, route, middleware.RateLimitClient)
+	}
+	magicLinkLimit := middleware.RateLimitBy(deps.SensitiveLimiter, "magic-link", middleware.RateLimitEmail)
+
+	auth := app.Group("/auth")
+	auth.Use(middleware.ConcurrencyLimit("auth", cfg.AuthRoutes.MaxConcurrent))
+	auth.Use(middleware.Timeout(cfg.AuthRoutes.Timeout))
+	auth.Use(middleware.RateLimit(deps.RateLimiter))
+	{
+		auth.Post("/signup", deps.AuthHandler.Signup)
+		if cfg.OIDC.Enabled() {
+			auth.Post("/login", deps.GeoBlock, deps.SSOHandler.RequirePasswordLogin, deps.AuthHandler.Login)
+			auth.Post("/magic-link", deps.GeoBlock, magicLinkLimit, deps.SSOHandler.RequirePasswordLogin, deps.AuthHandler.RequestMagicLink)
+			auth.Post("/sso/discover", deps.SSOHandler.Discover)
+			auth.Get("/sso/login", deps.GeoBlock, deps.SSOHandler.Login)
+			auth.Get("/sso/callback", deps.GeoBlock, deps.SSOHandler.Callback)
+		} else {
+			auth.Post("/login", deps.GeoBlock, deps.AuthHandler.Login)
+			auth.Post("/magic-link", deps.GeoBlock, magicLinkLimit, deps.AuthHandler.RequestMagicLink)
+		}
+		if cfg.LoginRisk.Threshold > 0 {
+			auth.Post("/login/challenge", deps.GeoBlock, sensitive("login-challenge"), deps.AuthHandler.ConfirmLoginChallenge)
+		}
+		auth.Get("/magic-link/verify", deps.GeoBlock, sensitive("magic-link-verify"), deps.AuthHandler.VerifyMagicLink)
+		auth.Post("/password-reset", sensitive("password-reset"), deps.AuthHandler.ResetPassword)
+		if cfg.RefreshTokenTTL > 0 {
+			auth.Post("/refresh", deps.AuthHandler.Refresh)
+		}
+		auth.Post("/logout", deps.AuthHandler.Logout)
+		auth.Post("/device/code", deps.GeoBlock, deps.DeviceHandler.Code)
+		auth.Post("/device/token", deps.GeoBlock, deps.DeviceHandler.Token)
+		auth.Post("/device/approve", middleware.Auth(authConfig), deps.DeviceHandler.Approve)
+		auth.Post("/device/deny", middleware.Auth(authConfig), deps.DeviceHandler.Deny)
+		auth.Post("/webauthn/register/begin", middleware.Auth(authConfig), deps.WebAuthnHandler.RegisterBegin)
+		auth.Post("/webauthn/register/finish", middleware.Auth(authConfig), deps.WebAuthnHandler.RegisterFinish)
+		auth.Post("/webauthn/login/begin", deps.GeoBlock, deps.WebAuthnHandler.LoginBegin)
+		auth.Post("/webauthn/login/finish", deps.GeoBlock, sensitive("webauthn-login"), deps.WebAuthnHandler.LoginFinish)
+		auth.Get("/identities/:provider/login", deps.GeoBlock, deps.IdentityHandler.Login)
+		auth.Get("/identities/:provider/callback", deps.GeoBlock, deps.IdentityHandler.Callback)
+	}
 
-	
 	protected := app.Group("/users")
-	protected.Use(middleware.Auth(jwtSecret))
+	protected.Use(middleware.ConcurrencyLimit("users", cfg.UserRoutes.MaxConcurrent))
+	protected.Use(middleware.Timeout(cfg.UserRoutes.Timeout))
+	protected.Use(middleware.APIKey(deps.APIKeys))
+	protected.Use(middleware.Auth(authConfig))
+	protected.Use(middleware.RateLimit(deps.RateLimiter))
+	protected.Use(middleware.CountCalls(deps.Usage...))
+	protected.Use(middleware.RequireMethodScope(service.ScopeUsersRead, service.ScopeUsersWrite))
 	{
-		protected.Get("/me", h.GetCurrentUser)
-		protected.Post("/", h.Create)
-		protected.Get("/:id", h.GetByID)
-		protected.Get("/", h.List)
-		protected.Put("/:id", h.Update)
-		protected.Delete("/:id", h.Delete)
+		protected.Head("/me", deps.UserHandler.HeadCurrentUser)
+		protected.Get("/me", deps.UserHandler.GetCurrentUser)
+		protected.Patch("/me", deps.UserHandler.UpdateProfile)
+		protected.Post("/me/avatar", deps.UserHandler.UploadAvatar)
+		protected.Delete("/me/avatar", deps.UserHandler.DeleteAvatar)
+		protected.Get("/me/claims", deps.UserHandler.GetCurrentClaims)
+		protected.Put("/me/password", sensitive("change-password"), deps.AuthHandler.ChangePassword)
+		protected.Get("/me/logins", deps.LoginHistoryHandler.Mine)
+		protected.Get("/me/referrals", deps.ReferralHandler.Mine)
+		protected.Get("/me/rate-limit", deps.SystemHandler.MyRateLimit)
+		protected.Get("/me/usage", deps.MeteringHandler.Mine)
+		protected.Get("/me/passkeys", deps.WebAuthnHandler.List)
+		protected.Delete("/me/passkeys/:id", deps.WebAuthnHandler.Delete)
+		protected.Get("/me/sessions", deps.AuthHandler.ListSessions)
+		protected.Delete("/me/sessions/:id", deps.AuthHandler.RevokeSession)
+		protected.Get("/me/identities", deps.IdentityHandler.List)
+		protected.Get("/me/notifications", deps.NotificationHandler.Mine)
+		protected.Post("/me/notifications/:id/read", deps.NotificationHandler.MarkRead)
+		protected.Get("/me/notification-preferences", deps.NotificationHandler.Preferences)
+		protected.Put("/me/notification-preferences", deps.NotificationHandler.SetPreference)
+		protected.Post("/me/identities/:provider/link", deps.IdentityHandler.Link)
+		protected.Delete("/me/identities/:provider/unlink", deps.IdentityHandler.Unlink)
+		protected.Post("/", middleware.Authorize(deps.Policies, policy.ActionUsersCreate, nil), deps.UserHandler.Create)
+		protected.Get("/:id", userParam, middleware.Authorize(deps.Policies, policy.ActionUsersRead, middleware.UserResource), deps.UserHandler.GetByID)
+		protected.Get("/:id/age", userParam, middleware.Authorize(deps.Policies, policy.ActionUsersRead, middleware.UserResource), deps.UserHandler.GetAge)
+		protected.Get("/", middleware.Authorize(deps.Policies, policy.ActionUsersList, nil), deps.UserHandler.List)
+		protected.Put("/:id", userParam, middleware.Authorize(deps.Policies, policy.ActionUsersUpdate, middleware.UserResource), deps.UserHandler.Update)
+		protected.Delete("/:id", userParam, middleware.Authorize(deps.Policies, policy.ActionUsersDelete, middleware.UserResource), deps.UserHandler.Delete)
 	}
 
-	
 	admin := app.Group("/admin")
-	admin.Use(middleware.Auth(jwtSecret))
-	admin.Use(middleware.RequireRole("admin"))
+	admin.Use(middleware.ConcurrencyLimit("admin", cfg.AdminRoutes.MaxConcurrent))
+	admin.Use(middleware.Timeout(cfg.AdminRoutes.Timeout))
+	admin.Use(middleware.APIKey(deps.APIKeys))
+	admin.Use(middleware.Auth(authConfig))
+	admin.Use(middleware.RateLimit(deps.RateLimiter))
+	admin.Use(middleware.CountCalls(deps.Usage...))
+	admin.Use(middleware.RequireScope(service.ScopeAdmin))
+	{
+		// Org admins share these with moderators, but only on their
+		// organization's users. They come before the group's moderator
+		// check, which would turn org admins away.
+		orgScope := middleware.OrgScope(deps.OrgMembers)
+		requireOrgAdmin := middleware.RequireRole(deps.Policies, service.RoleModerator, service.RoleOrgAdmin)
+
+		admin.Get("/users", requireOrgAdmin, orgScope, deps.AdminHandler.GetAllUsers)
+		admin.Get("/users/:id/logins", requireOrgAdmin, userParam, orgScope, deps.LoginHistoryHandler.ForUser)
+		admin.Post("/users/:id/deactivate", requireOrgAdmin, userParam, orgScope, deps.AdminHandler.Deactivate)
+		admin.Post("/users/:id/activate", requireOrgAdmin, userParam, orgScope, deps.AdminHandler.Activate)
+		admin.Post("/users/:id/unlock", requireOrgAdmin, userParam, orgScope, deps.AdminHandler.Unlock)
+	}
+	admin.Use(middleware.RequireRole(deps.Policies, service.RoleModerator))
 	{
-		admin.Get("/users", adminHandler.GetAllUsers)
-		admin.Get("/stats", adminHandler.GetStats)
+		// Moderators get the read-only endpoints too; the rest is for
+		// admins.
+		requireAdmin := middleware.RequireRole(deps.Policies, service.RoleAdmin)
+
+		admin.Get("/users/birthdays", deps.AdminHandler.Birthdays)
+		admin.Get("/users/lookup", deps.AdminHandler.LookupUsers)
+		admin.Get("/users/:id/usage", userParam, deps.MeteringHandler.ForUser)
+		admin.Put("/users/:id/role", requireAdmin, userParam, deps.AdminHandler.UpdateRole)
+		admin.Delete("/users/:id", requireAdmin, userParam, deps.AdminHandler.DeleteUser)
+		admin.Post("/users/:id/restore", requireAdmin, userParam, deps.AdminHandler.RestoreUser)
+		admin.Post("/users/:id/force-logout", requireAdmin, userParam, deps.AdminHandler.ForceLogout)
+		admin.Post("/users/:id/force-password-reset", requireAdmin, userParam, deps.AdminHandler.ForcePasswordReset)
+		admin.Get("/lockouts", deps.AdminHandler.Lockouts)
+		admin.Get("/name-reviews", deps.ModerationHandler.ListReviews)
+		admin.Post("/name-reviews/:id/approve", deps.ModerationHandler.Approve)
+		admin.Post("/name-reviews/:id/reject", deps.ModerationHandler.Reject)
+		admin.Get("/stats", deps.AdminHandler.GetStats)
+		admin.Post("/stats/refresh", requireAdmin, deps.AdminHandler.RefreshStats)
+		admin.Get("/load-shedding", deps.SystemHandler.LoadShedding)
+		admin.Get("/connections", deps.SystemHandler.Connections)
+		admin.Get("/deprecations", deps.SystemHandler.Deprecations)
+		if deps.Analytics != nil {
+			admin.Get("/analytics/endpoints", deps.SystemHandler.EndpointAnalytics)
+		}
+		admin.Get("/repository-stats", deps.SystemHandler.RepositoryStats)
+		admin.Get("/outcomes", deps.SystemHandler.Outcomes)
+		admin.Get("/usage", deps.MeteringHandler.Top)
+		admin.Get("/config", requireAdmin, deps.ConfigHandler.Export)
+		admin.Post("/config/import", requireAdmin, deps.ConfigHandler.Import)
+		admin.Get("/retention", deps.RetentionHandler.List)
+		admin.Get("/retention/:category", deps.RetentionHandler.Get)
+		admin.Get("/security/alerts", deps.SecurityHandler.Alerts)
+		admin.Get("/security/events", requireAdmin, deps.SecurityHandler.Events)
+		admin.Get("/security/events/verify", requireAdmin, deps.SecurityHandler.Verify)
+		admin.Get("/reports", deps.ReportHandler.List)
+		admin.Get("/reports/:name", deps.ReportHandler.Run)
+		admin.Post("/reports/:name/exports", requireAdmin, deps.ExportHandler.Create)
+		admin.Get("/exports/:id", deps.ExportHandler.Get)
+		if deps.BackupHandler != nil {
+			admin.Post("/backups", requireAdmin, deps.BackupHandler.Create)
+			admin.Get("/backups", requireAdmin, deps.BackupHandler.List)
+		}
+		admin.Get("/orgs", deps.OrgHandler.List)
+		admin.Post("/orgs", requireAdmin, deps.OrgHandler.Create)
+		admin.Put("/users/:id/org", requireAdmin, userParam, deps.OrgHandler.SetMember)
+		admin.Delete("/users/:id/org", requireAdmin, userParam, deps.OrgHandler.RemoveMember)
+		admin.Get("/email-templates", deps.EmailTemplateHandler.List)
+		admin.Get("/email-templates/:name/preview", deps.EmailTemplateHandler.Preview)
+		admin.Get("/emails/dead-letters", deps.EmailDeliveryHandler.ListDeadLetters)
+		admin.Post("/emails/dead-letters/:id/retry", requireAdmin, deps.EmailDeliveryHandler.RetryDeadLetter)
+		admin.Get("/notification-rules", deps.NotificationHandler.ListRules)
+		admin.Post("/notification-rules", requireAdmin, deps.NotificationHandler.CreateRule)
+		admin.Put("/notification-rules/:id", requireAdmin, deps.NotificationHandler.UpdateRule)
+		admin.Delete("/notification-rules/:id", requireAdmin, deps.NotificationHandler.DeleteRule)
+		admin.Get("/service-accounts", deps.ServiceAccountHandler.List)
+		admin.Post("/service-accounts", requireAdmin, deps.ServiceAccountHandler.Create)
+		admin.Get("/service-accounts/:id/keys", userParam, deps.ServiceAccountHandler.ListKeys)
+		admin.Post("/service-accounts/:id/keys", requireAdmin, userParam, deps.ServiceAccountHandler.CreateKey)
+		admin.Delete("/service-accounts/:id/keys/:keyId", requireAdmin, userParam, deps.ServiceAccountHandler.RevokeKey)
+	}
+
+	// Organization usage feeds billing integrations, so it is admin-only
+	// and shares the admin limits.
+	orgs := app.Group("/orgs")
+	orgs.Use(middleware.ConcurrencyLimit("orgs", cfg.AdminRoutes.MaxConcurrent))
+	orgs.Use(middleware.Timeout(cfg.AdminRoutes.Timeout))
+	orgs.Use(middleware.APIKey(deps.APIKeys))
+	orgs.Use(middleware.Auth(authConfig))
+	orgs.Use(middleware.RateLimit(deps.RateLimiter))
+	orgs.Use(middleware.CountCalls(deps.Usage...))
+	orgs.Use(middleware.RequireRole(deps.Policies, service.RoleAdmin))
+	orgs.Use(middleware.RequireScope(service.ScopeAdmin))
+	{
+		orgs.Get("/:id/usage", deps.OrgHandler.Usage)
+		orgs.Get("/:id/billing", deps.BillingHandler.Status)
+	}
+
+	if cfg.SCIMToken != "" {
+		scim := app.Group("/scim/v2")
+		scim.Use(middleware.ConcurrencyLimit("scim", cfg.AdminRoutes.MaxConcurrent))
+		scim.Use(middleware.Timeout(cfg.AdminRoutes.Timeout))
+		scim.Use(middleware.SCIMAuth(cfg.SCIMToken))
+		{
+			scim.Get("/ServiceProviderConfig", deps.SCIMHandler.ServiceProviderConfig)
+			scim.Get("/Users", deps.SCIMHandler.ListUsers)
+			scim.Post("/Users", deps.SCIMHandler.CreateUser)
+			scim.Get("/Users/:id", deps.SCIMHandler.GetUser)
+			scim.Put("/Users/:id", deps.SCIMHandler.ReplaceUser)
+			scim.Patch("/Users/:id", deps.SCIMHandler.PatchUser)
+			scim.Delete("/Users/:id", deps.SCIMHandler.DeleteUser)
+		}
+	}
+}
+
+// registerDocs serves the API description, its browsable UI and the Postman
+// collection, unless DOCS_ENABLED turned them off.
+func registerDocs(app fiber.Router, cfg *config.Config) {
+	if !cfg.DocsEnabled {
+		return
 	}
+	app.Get(collectionPath, CollectionHandler(cfg.Branding.ProductName, cfg.Branding.BaseURL))
+	app.Get(openAPIPath, OpenAPIHandler(cfg.Branding.ProductName, cfg.Branding.BaseURL))
+	app.Get(swaggerUIPath, SwaggerUIHandler(cfg.Branding.ProductName))
 }
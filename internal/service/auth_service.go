@@ -8,63 +8,127 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"go.opentelemetry.io/otel/attribute"
 	"golang.org/x/crypto/bcrypt"
 
 	"BACKEND/db/sqlc/generated"
+	"BACKEND/hooks"
+	"BACKEND/internal/clock"
 	"BACKEND/internal/repository"
+	"BACKEND/internal/tracing"
 )
 
-
 type AuthServiceInterface interface {
 	ValidatePasswordStrength(password string) error
 	CreateUser(ctx context.Context, name, email, password, dobStr, role string) (generated.CreateUserRow, error)
 	Login(ctx context.Context, email, password string) (generated.User, string, error)
 	GetJWTExpiry() time.Duration
 	SetJWTConfig(secret string, expiry time.Duration)
+	ParseJWT(tokenString string) (*JWTClaims, error)
+	ChangePassword(ctx context.Context, userID int64, currentPassword, newPassword string) error
 }
 
-
 type AuthService struct {
-	repo       *repository.UserRepository
-	jwtSecret  string
-	jwtExpiry  time.Duration
+	repo        repository.UserStore
+	jwtSecret   string
+	jwtExpiry   time.Duration
+	hooks       *hooks.Registry
+	enricher    ClaimsEnricher
+	clock       clock.Clock
+	securityLog *SecurityLogService
+	lockout     *LoginLockout
+	sessions    *SessionService
+	risk        *LoginRisk
 }
 
-
-func NewAuthService(repo *repository.UserRepository) *AuthService {
-	return &AuthService{repo: repo}
+func NewAuthService(repo repository.UserStore) *AuthService {
+	return &AuthService{repo: repo, clock: clock.System}
 }
 
-
 func (s *AuthService) SetJWTConfig(secret string, expiry time.Duration) {
 	s.jwtSecret = secret
 	s.jwtExpiry = expiry
 }
 
+// SetClock sets the clock tokens are issued against.
+func (s *AuthService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// now reads the configured clock, falling back to the wall clock for an
+// AuthService built without NewAuthService.
+func (s *AuthService) now() time.Time {
+	if s.clock == nil {
+		return clock.System.Now()
+	}
+	return s.clock.Now()
+}
+
+func (s *AuthService) SetHooks(registry *hooks.Registry) {
+	s.hooks = registry
+}
+
+func (s *AuthService) SetClaimsEnricher(enricher ClaimsEnricher) {
+	s.enricher = enricher
+}
+
+// SetSecurityLog records each token issued to an admin, whichever way they
+// logged in, in log. A login fails if it can't be recorded.
+func (s *AuthService) SetSecurityLog(log *SecurityLogService) {
+	s.securityLog = log
+}
+
+// SetLockout locks out emails and IPs after repeated failed logins.
+func (s *AuthService) SetLockout(lockout *LoginLockout) {
+	s.lockout = lockout
+}
+
+// SetSessions starts a session in sessions for each login, named in the
+// tokens' sid claim.
+func (s *AuthService) SetSessions(sessions *SessionService) {
+	s.sessions = sessions
+}
+
+// SetLoginRisk has risky password logins confirmed with an emailed code
+// before they complete.
+func (s *AuthService) SetLoginRisk(risk *LoginRisk) {
+	s.risk = risk
+}
 
 func (s *AuthService) GetJWTExpiry() time.Duration {
 	return s.jwtExpiry
 }
 
-
 var (
 	ErrPasswordTooShort    = errors.New("password must be at least 8 characters long")
 	ErrPasswordNoUppercase = errors.New("password must contain at least one uppercase letter")
 	ErrPasswordNoLowercase = errors.New("password must contain at least one lowercase letter")
 	ErrPasswordNoDigit     = errors.New("password must contain at least one digit")
 	ErrPasswordNoSpecial   = errors.New("password must contain at least one special character")
-	ErrEmailAlreadyExists  = errors.New("email already exists")
+	ErrEmailAlreadyExists  = repository.ErrEmailAlreadyExists
 	ErrInvalidCredentials  = errors.New("invalid email or password")
+	ErrAccountDisabled     = errors.New("account is disabled")
+	ErrAccountLocked       = errors.New("account is locked after too many failed logins")
+	ErrWrongPassword       = errors.New("current password is incorrect")
 )
 
+// Token issue times carry milliseconds rather than whole seconds, so a
+// token issued right after its user's tokens were revoked is told apart
+// from the ones issued before.
+func init() {
+	jwt.TimePrecision = time.Millisecond
+}
 
 type JWTClaims struct {
-	UserID int32  `json:"user_id"`
-	Role   string `json:"role"`
+	UserID int64                  `json:"user_id"`
+	Role   string                 `json:"role"`
+	Extra  map[string]interface{} `json:"-"`
+	// SessionID names the login the token belongs to; tokens issued by
+	// refreshing it carry the same one.
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
-
 func (s *AuthService) ValidatePasswordStrength(password string) error {
 	if len(password) < 8 {
 		return ErrPasswordTooShort
@@ -93,7 +157,6 @@ func (s *AuthService) ValidatePasswordStrength(password string) error {
 	return nil
 }
 
-
 func (s *AuthService) HashPassword(password string) (string, error) {
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), 12)
 	if err != nil {
@@ -102,12 +165,14 @@ func (s *AuthService) HashPassword(password string) (string, error) {
 	return string(hash), nil
 }
 
-
 func (s *AuthService) ComparePassword(hashedPassword, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
 }
 
-func (s *AuthService) CreateUser(ctx context.Context, name, email, password, dobStr, role string) (generated.CreateUserRow, error) {
+func (s *AuthService) CreateUser(ctx context.Context, name, email, password, dobStr, role string) (_ generated.CreateUserRow, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.CreateUser")
+	defer func() { tracing.End(span, err) }()
+
 	if err := s.ValidatePasswordStrength(password); err != nil {
 		return generated.CreateUserRow{}, err
 	}
@@ -117,8 +182,7 @@ func (s *AuthService) CreateUser(ctx context.Context, name, email, password, dob
 		return generated.CreateUserRow{}, err
 	}
 
-	
-	dob, err := time.Parse("2006-01-02", dobStr)
+	dob, err := ParseDob(dobStr)
 	if err != nil {
 		return generated.CreateUserRow{}, fmt.Errorf("invalid date format: %w", err)
 	}
@@ -127,11 +191,11 @@ func (s *AuthService) CreateUser(ctx context.Context, name, email, password, dob
 		role = "user"
 	}
 
-	user, err := s.repo.CreateWithAuth(ctx, name, email, hashedPassword, role, dob)
+	user, err := s.repo.CreateWithAuth(ctx, name, email, hashedPassword, role, SignupSourceWeb, dob)
 	if err != nil {
-		
-		if err.Error() == "duplicate key value violates unique constraint" ||
-			err.Error() == "pq: duplicate key value violates unique constraint \"users_email_key\"" {
+		// The unique index on email decides concurrent signups for the
+		// same address: exactly one insert wins.
+		if errors.Is(err, ErrEmailAlreadyExists) {
 			return generated.CreateUserRow{}, ErrEmailAlreadyExists
 		}
 		return generated.CreateUserRow{}, fmt.Errorf("failed to create user: %w", err)
@@ -140,18 +204,45 @@ func (s *AuthService) CreateUser(ctx context.Context, name, email, password, dob
 	return user, nil
 }
 
-func (s *AuthService) GenerateJWT(userID int32, role string) (string, error) {
+func (s *AuthService) GenerateJWT(ctx context.Context, userID int64, role string) (string, error) {
 	if s.jwtSecret == "" {
 		return "", fmt.Errorf("JWT secret not configured")
 	}
 
-	expiryTime := time.Now().Add(s.jwtExpiry)
+	var extra map[string]interface{}
+	if s.enricher != nil {
+		var err error
+		extra, err = s.enricher.Enrich(ctx, userID, role)
+		if err != nil {
+			return "", fmt.Errorf("failed to enrich claims: %w", err)
+		}
+	}
+
+	// The ID lets a single token be revoked on logout.
+	jti, err := randomHex(16)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token ID: %w", err)
+	}
+
+	sessionID := sessionFromContext(ctx)
+	if sessionID == "" && s.sessions != nil {
+		sessionID, err = s.sessions.Start(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	now := s.now()
+	expiryTime := now.Add(s.jwtExpiry)
 	claims := JWTClaims{
-		UserID: userID,
-		Role:   role,
+		UserID:    userID,
+		Role:      role,
+		Extra:     extra,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        jti,
 			ExpiresAt: jwt.NewNumericDate(expiryTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
@@ -161,24 +252,127 @@ func (s *AuthService) GenerateJWT(userID int32, role string) (string, error) {
 		return "", fmt.Errorf("failed to generate token: %w", err)
 	}
 
+	if role == RoleAdmin && s.securityLog != nil {
+		if err := s.securityLog.Record(ctx, SecurityEvent{Type: SecurityEventAdminLogin, ActorID: userID}); err != nil {
+			return "", fmt.Errorf("failed to record admin login: %w", err)
+		}
+	}
+
 	return tokenString, nil
 }
 
+// ParseJWT verifies a token this service issued and returns its claims.
+// Expired tokens are rejected.
+func (s *AuthService) ParseJWT(tokenString string) (*JWTClaims, error) {
+	if s.jwtSecret == "" {
+		return nil, fmt.Errorf("JWT secret not configured")
+	}
+	claims := &JWTClaims{}
+	if _, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return []byte(s.jwtSecret), nil
+	}, jwt.WithTimeFunc(s.now)); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func (s *AuthService) Login(ctx context.Context, email, password string) (_ generated.User, _ string, err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.Login")
+	defer func() { tracing.End(span, err) }()
+
+	if err := s.lockout.Check(ctx, email); err != nil {
+		return generated.User{}, "", err
+	}
 
-func (s *AuthService) Login(ctx context.Context, email, password string) (generated.User, string, error) {
 	user, err := s.repo.GetByEmail(ctx, email)
 	if err != nil {
-		return generated.User{}, "", ErrInvalidCredentials
+		return generated.User{}, "", s.loginFailed(ctx, email)
 	}
 
 	if err := s.ComparePassword(user.PasswordHash, password); err != nil {
-		return generated.User{}, "", ErrInvalidCredentials
+		return generated.User{}, "", s.loginFailed(ctx, email)
+	}
+	if err := s.lockout.Success(ctx, email); err != nil {
+		return generated.User{}, "", fmt.Errorf("failed to reset login failures: %w", err)
 	}
 
-	token, err := s.GenerateJWT(user.ID, user.Role)
+	if user.AccountType == AccountTypeService {
+		return generated.User{}, "", ErrInteractiveLoginDenied
+	}
+
+	if !user.Active {
+		return generated.User{}, "", ErrAccountDisabled
+	}
+	// The user comes back with a challenge, so the attempt can be recorded
+	// against them.
+	if err := s.risk.Check(ctx, user); err != nil {
+		return user, "", err
+	}
+
+	token, err := s.GenerateJWT(ctx, user.ID, user.Role)
 	if err != nil {
 		return generated.User{}, "", fmt.Errorf("failed to generate token: %w", err)
 	}
 
+	s.afterLogin(ctx, user)
 	return user, token, nil
 }
+
+// loginFailed counts a wrong email or password towards the lockout and
+// returns ErrInvalidCredentials. The attempt that reaches the threshold
+// still gets that error; the lock applies from the next one.
+func (s *AuthService) loginFailed(ctx context.Context, email string) error {
+	s.risk.Failure(email)
+	if err := s.lockout.Failure(ctx, email); err != nil {
+		return err
+	}
+	return ErrInvalidCredentials
+}
+
+// ChangePassword replaces the user's password once they have confirmed the
+// current one. Logging out their sessions is left to the caller.
+func (s *AuthService) ChangePassword(ctx context.Context, userID int64, currentPassword, newPassword string) (err error) {
+	ctx, span := tracing.Start(ctx, "AuthService.ChangePassword", attribute.Int64("user.id", userID))
+	defer func() { tracing.End(span, err) }()
+
+	row, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if row.AccountType == AccountTypeService {
+		return ErrInteractiveLoginDenied
+	}
+	user, err := s.repo.GetByEmail(repository.WithPrimary(ctx), row.Email)
+	if err != nil {
+		return err
+	}
+	if err := s.ComparePassword(user.PasswordHash, currentPassword); err != nil {
+		return ErrWrongPassword
+	}
+	if err := s.ValidatePasswordStrength(newPassword); err != nil {
+		return err
+	}
+
+	hash, err := s.HashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.UpdatePassword(ctx, user.ID, hash); err != nil {
+		return fmt.Errorf("failed to update password: %w", err)
+	}
+	return nil
+}
+
+func (s *AuthService) afterLogin(ctx context.Context, user generated.User) {
+	_ = s.hooks.Run(ctx, hooks.AfterLogin, &hooks.User{
+		ID:          user.ID,
+		Name:        user.Name,
+		Email:       user.Email,
+		Role:        user.Role,
+		AccountType: user.AccountType,
+		Dob:         user.Dob.Time,
+	})
+}
@@ -1,9 +1,17 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgconn"
 	"golang.org/x/crypto/bcrypt"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/repository"
 )
 
 func TestValidatePasswordStrength(t *testing.T) {
@@ -185,3 +193,81 @@ func TestPasswordHashCost(t *testing.T) {
 		t.Errorf("Bcrypt cost = %d; want %d", cost, expectedCost)
 	}
 }
+
+type failingCreateStore struct {
+	repository.UserStore
+	err error
+}
+
+func (s *failingCreateStore) CreateWithAuth(ctx context.Context, name, email, passwordHash, role, source string, dob time.Time) (generated.CreateUserRow, error) {
+	return generated.CreateUserRow{}, s.err
+}
+
+func TestCreateUser_UniqueViolation(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"email", &repository.ConstraintError{Kind: repository.ErrUniqueViolation, Constraint: "users_email_key", Err: &pgconn.PgError{Code: "23505"}}, ErrEmailAlreadyExists},
+		{"email wrapped", fmt.Errorf("hook: %w", &repository.ConstraintError{Kind: repository.ErrUniqueViolation, Constraint: "users.email"}), ErrEmailAlreadyExists},
+		{"other unique index", &repository.ConstraintError{Kind: repository.ErrUniqueViolation, Constraint: "users_public_id_idx"}, nil},
+		{"check violation", &repository.ConstraintError{Kind: repository.ErrCheckViolation, Constraint: "users_role_check"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewAuthService(&failingCreateStore{err: tt.err})
+			_, err := svc.CreateUser(context.Background(), "Jane Doe", "jane@example.com", "SecurePass123!", "1990-01-01", "")
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("CreateUser() error = %v; want %v", err, tt.want)
+			}
+			if tt.want == nil && (err == nil || errors.Is(err, ErrEmailAlreadyExists)) {
+				t.Errorf("CreateUser() error = %v; want a plain failure", err)
+			}
+		})
+	}
+}
+
+type passwordChangeStore struct {
+	repository.UserStore
+	user generated.User
+}
+
+func (s *passwordChangeStore) GetByID(ctx context.Context, id int64) (generated.GetUserByIDRow, error) {
+	return generated.GetUserByIDRow{ID: s.user.ID, Email: s.user.Email, AccountType: s.user.AccountType}, nil
+}
+
+func (s *passwordChangeStore) GetByEmail(ctx context.Context, email string) (generated.User, error) {
+	return s.user, nil
+}
+
+func (s *passwordChangeStore) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	s.user.PasswordHash = passwordHash
+	return nil
+}
+
+func TestChangePassword(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("OldPass123!"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &passwordChangeStore{user: generated.User{ID: 1, Email: "jane@example.com", PasswordHash: string(hash), AccountType: "user"}}
+	svc := NewAuthService(store)
+
+	if err := svc.ChangePassword(context.Background(), 1, "WrongPass123!", "NewPass123!"); !errors.Is(err, ErrWrongPassword) {
+		t.Errorf("wrong current password: got %v", err)
+	}
+	if err := svc.ChangePassword(context.Background(), 1, "OldPass123!", "weak"); !IsWeakPassword(err) {
+		t.Errorf("weak new password: got %v", err)
+	}
+	if store.user.PasswordHash != string(hash) {
+		t.Fatal("password changed by a rejected request")
+	}
+
+	if err := svc.ChangePassword(context.Background(), 1, "OldPass123!", "NewPass123!"); err != nil {
+		t.Fatalf("ChangePassword() = %v", err)
+	}
+	if svc.ComparePassword(store.user.PasswordHash, "NewPass123!") != nil {
+		t.Error("new password doesn't match the stored hash")
+	}
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"BACKEND/internal/storage"
+)
+
+var (
+	ErrInvalidAvatarHash = errors.New("avatar hash must be 64 hex characters")
+	ErrAvatarUploadsOff  = errors.New("avatar uploads are not configured")
+	ErrAvatarTooLarge    = errors.New("avatar is too large")
+	ErrAvatarType        = errors.New("avatar must be a PNG, JPEG or GIF image")
+)
+
+// avatarTypes maps the image types accepted as avatars to the extension
+// they are stored with.
+var avatarTypes = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+}
+
+// DefaultAvatarStyle is the image Gravatar shows for emails without a
+// picture of their own.
+const DefaultAvatarStyle = "identicon"
+
+// AvatarService picks the picture shown for users who haven't uploaded one.
+// It is derived from the SHA-256 of the user's normalized email (users
+// without an email, such as service accounts, use their public ID), so the
+// same person gets the same picture everywhere.
+//
+// With Gravatar on, the URL points at Gravatar, which serves the picture
+// registered for that email or falls back to its default style. Otherwise
+// no lookup leaves the API: the URL points at an identicon drawn by
+// Identicon and served under baseURL.
+type AvatarService struct {
+	baseURL  string
+	gravatar bool
+	style    string
+	size     int
+
+	uploads      storage.Storage
+	maxBytes     int64
+	maxDimension int
+	urlTTL       time.Duration
+}
+
+func NewAvatarService(baseURL string) *AvatarService {
+	return &AvatarService{baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+// SetGravatar sends avatar URLs to Gravatar, asking for pictures size
+// pixels square and for style when the email has none. An empty style is
+// DefaultAvatarStyle and size 0 is Gravatar's own default.
+func (s *AvatarService) SetGravatar(style string, size int) {
+	if style == "" {
+		style = DefaultAvatarStyle
+	}
+	s.gravatar = true
+	s.style = style
+	s.size = size
+}
+
+// SetUploads lets users upload their own avatar, kept in store. Uploads are
+// at most maxBytes and maxDimension pixels wide and high, and the URLs
+// handed out for them last urlTTL.
+func (s *AvatarService) SetUploads(store storage.Storage, maxBytes int64, maxDimension int, urlTTL time.Duration) {
+	s.uploads = store
+	s.maxBytes = maxBytes
+	s.maxDimension = maxDimension
+	s.urlTTL = urlTTL
+}
+
+// MaxUploadBytes is the size of the largest avatar Upload takes, or 0 when
+// uploads are off.
+func (s *AvatarService) MaxUploadBytes() int64 {
+	if s == nil || s.uploads == nil {
+		return 0
+	}
+	return s.maxBytes
+}
+
+// Upload checks that data is an image small enough to be an avatar and
+// stores it under a new key for the user with publicID, which it returns.
+func (s *AvatarService) Upload(ctx context.Context, publicID string, data []byte) (string, error) {
+	if s == nil || s.uploads == nil {
+		return "", ErrAvatarUploadsOff
+	}
+	if int64(len(data)) > s.maxBytes {
+		return "", ErrAvatarTooLarge
+	}
+	// Sniff the type rather than trust the one the client sent, and decode
+	// the header to be sure it really is an image.
+	contentType := http.DetectContentType(data)
+	ext, ok := avatarTypes[contentType]
+	if !ok {
+		return "", ErrAvatarType
+	}
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return "", ErrAvatarType
+	}
+	if cfg.Width > s.maxDimension || cfg.Height > s.maxDimension {
+		return "", fmt.Errorf("%w: %dx%d pixels, at most %d wide and high", ErrAvatarTooLarge, cfg.Width, cfg.Height, s.maxDimension)
+	}
+
+	name, err := randomHex(8)
+	if err != nil {
+		return "", err
+	}
+	key := "avatars/" + publicID + "/" + name + ext
+	if err := s.uploads.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+// UploadURL returns a URL the uploaded avatar at key can be fetched from
+// for a while.
+func (s *AvatarService) UploadURL(key string) (string, error) {
+	if s == nil || s.uploads == nil {
+		return "", ErrAvatarUploadsOff
+	}
+	return s.uploads.URL(key, time.Now().Add(s.urlTTL))
+}
+
+// RemoveUpload deletes the uploaded avatar at key.
+func (s *AvatarService) RemoveUpload(ctx context.Context, key string) error {
+	if s == nil || s.uploads == nil {
+		return ErrAvatarUploadsOff
+	}
+	return s.uploads.Delete(ctx, key)
+}
+
+// AvatarHash is the hex SHA-256 of email trimmed and lowercased, as
+// Gravatar expects it.
+func AvatarHash(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
+
+// URL returns the avatar URL for a user, or "" on a nil service.
+func (s *AvatarService) URL(email, publicID string) string {
+	if s == nil {
+		return ""
+	}
+	if strings.TrimSpace(email) == "" {
+		email = publicID
+	}
+	hash := AvatarHash(email)
+	if !s.gravatar {
+		return s.baseURL + "/avatars/" + hash
+	}
+	query := url.Values{"d": {s.style}}
+	if s.size > 0 {
+		query.Set("s", strconv.Itoa(s.size))
+	}
+	return "https://gravatar.com/avatar/" + hash + "?" + query.Encode()
+}
+
+// Identicon draws the SVG identicon for an AvatarHash: a 5x5 grid,
+// mirrored left to right, in a colour taken from the hash.
+func (s *AvatarService) Identicon(hash string) ([]byte, error) {
+	sum, err := hex.DecodeString(hash)
+	if err != nil || len(sum) != sha256.Size {
+		return nil, ErrInvalidAvatarHash
+	}
+
+	var b bytes.Buffer
+	b.WriteString(`<svg xmlns="http://www.w3.org/2000/svg" viewBox="-0.5 -0.5 6 6" width="240" height="240" shape-rendering="crispEdges">`)
+	b.WriteString(`<rect x="-0.5" y="-0.5" width="6" height="6" fill="#f0f0f0"/>`)
+	fill := fmt.Sprintf("hsl(%d,55%%,50%%)", (int(sum[0])<<8|int(sum[1]))%360)
+	for i := 0; i < 15; i++ {
+		// Cells come from the bits after the colour, column by column over
+		// the left half.
+		if sum[2+i/8]>>(i%8)&1 == 0 {
+			continue
+		}
+		x, y := i/5, i%5
+		fmt.Fprintf(&b, `<rect x="%d" y="%d" width="1" height="1" fill="%s"/>`, x, y, fill)
+		if x != 2 {
+			fmt.Fprintf(&b, `<rect x="%d" y="%d" width="1" height="1" fill="%s"/>`, 4-x, y, fill)
+		}
+	}
+	b.WriteString(`</svg>`)
+	return b.Bytes(), nil
+}
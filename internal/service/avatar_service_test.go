@@ -0,0 +1,124 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"BACKEND/internal/repository"
+	"BACKEND/internal/storage"
+)
+
+func TestAvatarURL(t *testing.T) {
+	// sha256("myemailaddress@example.com")
+	const hash = "84059b07d4be67b806386c0aad8070a23f18836bbaae342275dc0a83414c32ee"
+	if got := AvatarHash(" MyEmailAddress@example.com "); got != hash {
+		t.Fatalf("AvatarHash() = %s, want %s", got, hash)
+	}
+
+	local := NewAvatarService("https://api.example.com/")
+	if got, want := local.URL("myemailaddress@example.com", "id"), "https://api.example.com/avatars/"+hash; got != want {
+		t.Errorf("URL() without Gravatar = %s, want %s", got, want)
+	}
+	if local.URL("", "id-1") == local.URL("", "id-2") {
+		t.Error("users without an email share an avatar")
+	}
+
+	gravatar := NewAvatarService("https://api.example.com")
+	gravatar.SetGravatar("", 200)
+	if got, want := gravatar.URL("myemailaddress@example.com", "id"), "https://gravatar.com/avatar/"+hash+"?d=identicon&s=200"; got != want {
+		t.Errorf("URL() with Gravatar = %s, want %s", got, want)
+	}
+
+	var none *AvatarService
+	if got := none.URL("myemailaddress@example.com", "id"); got != "" {
+		t.Errorf("nil service URL() = %q, want empty", got)
+	}
+}
+
+func TestAvatarIdenticon(t *testing.T) {
+	s := NewAvatarService("")
+	a, err := s.Identicon(AvatarHash("a@example.com"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(a, []byte("<svg")) || !strings.Contains(string(a), "hsl(") {
+		t.Errorf("Identicon() = %s", a)
+	}
+	again, _ := s.Identicon(AvatarHash("a@example.com"))
+	b, _ := s.Identicon(AvatarHash("b@example.com"))
+	if !bytes.Equal(a, again) || bytes.Equal(a, b) {
+		t.Error("identicons should be the same for a hash and differ between hashes")
+	}
+
+	for _, hash := range []string{"", "abc", strings.Repeat("zz", 32)} {
+		if _, err := s.Identicon(hash); !errors.Is(err, ErrInvalidAvatarHash) {
+			t.Errorf("Identicon(%q) = %v, want ErrInvalidAvatarHash", hash, err)
+		}
+	}
+}
+
+func TestUploadAvatar(t *testing.T) {
+	ctx := context.Background()
+	users := repository.NewMemoryUserStore()
+	dob, _ := ParseDob("1990-01-01")
+	user, err := users.CreateWithAuth(ctx, "Jane", "jane@example.com", "hash", "", "api", dob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := &storage.Local{Dir: t.TempDir(), BaseURL: "https://api.example.com", Secret: "secret"}
+	avatars := NewAvatarService("https://api.example.com")
+	avatars.SetUploads(files, 1<<20, 64, time.Hour)
+	svc := NewUserService(users)
+	svc.SetAvatars(avatars)
+	svc.SetProfiles(repository.NewMemoryProfileStore())
+
+	picture := func(size int) []byte {
+		var b bytes.Buffer
+		png.Encode(&b, image.NewGray(image.Rect(0, 0, size, size)))
+		return b.Bytes()
+	}
+	for _, tc := range []struct {
+		name string
+		data []byte
+		want error
+	}{
+		{"text", []byte("hello, not an image"), ErrAvatarType},
+		{"SVG", []byte(`<svg xmlns="http://www.w3.org/2000/svg"></svg>`), ErrAvatarType},
+		{"truncated PNG", picture(8)[:20], ErrAvatarType},
+		{"too many pixels", picture(65), ErrAvatarTooLarge},
+		{"too many bytes", append(picture(8), make([]byte, 1<<20)...), ErrAvatarTooLarge},
+	} {
+		if err := svc.UploadAvatar(ctx, user.ID, tc.data); !errors.Is(err, tc.want) {
+			t.Errorf("%s: UploadAvatar = %v; want %v", tc.name, err, tc.want)
+		}
+	}
+
+	if err := svc.UploadAvatar(ctx, user.ID, picture(8)); err != nil {
+		t.Fatal(err)
+	}
+	first, err := svc.CurrentUser(ctx, user.ID)
+	if err != nil || !strings.HasPrefix(first.AvatarURL, "https://api.example.com"+storage.LocalPath+"avatars/"+user.PublicID.String()+"/") {
+		t.Fatalf("CurrentUser = %+v, %v; want the uploaded avatar", first, err)
+	}
+	if err := svc.UploadAvatar(ctx, user.ID, picture(16)); err != nil {
+		t.Fatal(err)
+	}
+	firstKey := strings.TrimPrefix(strings.SplitN(first.AvatarURL, "?", 2)[0], "https://api.example.com"+storage.LocalPath)
+	if _, err := files.Get(ctx, firstKey); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("the replaced avatar is still stored: %v", err)
+	}
+
+	if err := svc.RemoveAvatar(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	me, err := svc.CurrentUser(ctx, user.ID)
+	if err != nil || me.AvatarURL != avatars.URL(user.Email, user.PublicID.String()) {
+		t.Errorf("after RemoveAvatar the avatar is %q, %v; want the derived one", me.AvatarURL, err)
+	}
+}
@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/internal/backup"
+)
+
+type BackupStatus string
+
+const (
+	BackupRunning   BackupStatus = "running"
+	BackupCompleted BackupStatus = "completed"
+	BackupFailed    BackupStatus = "failed"
+)
+
+var ErrBackupRunning = errors.New("a backup is already running")
+
+// Backup is an archive in storage, or one being written by this instance.
+// Backups taken by other instances or before a restart show up with the
+// completed status and without their table counts.
+type Backup struct {
+	Name        string           `json:"name"`
+	Status      BackupStatus     `json:"status"`
+	Error       string           `json:"error,omitempty"`
+	Size        int64            `json:"size,omitempty"`
+	Tables      map[string]int64 `json:"tables,omitempty"`
+	CreatedBy   int64            `json:"created_by,omitempty"`
+	CreatedAt   time.Time        `json:"created_at"`
+	CompletedAt *time.Time       `json:"completed_at,omitempty"`
+}
+
+// BackupService snapshots the user tables into storage in the background,
+// one backup at a time.
+type BackupService struct {
+	copier  backup.Copier
+	storage backup.Storage
+	logger  *zap.Logger
+	now     func() time.Time
+
+	mu   sync.Mutex
+	runs map[string]*Backup
+}
+
+func NewBackupService(copier backup.Copier, storage backup.Storage, logger *zap.Logger) *BackupService {
+	return &BackupService{
+		copier:  copier,
+		storage: storage,
+		logger:  logger,
+		now:     time.Now,
+		runs:    make(map[string]*Backup),
+	}
+}
+
+// Start begins a backup and returns immediately.
+func (s *BackupService) Start(ctx context.Context, userID int64) (Backup, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, run := range s.runs {
+		if run.Status == BackupRunning {
+			return Backup{}, ErrBackupRunning
+		}
+	}
+
+	now := s.now().UTC()
+	run := &Backup{
+		Name:      "users-" + now.Format("20060102T150405Z") + ".tar.gz",
+		Status:    BackupRunning,
+		CreatedBy: userID,
+		CreatedAt: now,
+	}
+	s.runs[run.Name] = run
+
+	go s.run(context.WithoutCancel(ctx), run)
+
+	return *run, nil
+}
+
+func (s *BackupService) run(ctx context.Context, run *Backup) {
+	pr, pw := io.Pipe()
+	written := make(chan backup.Manifest, 1)
+	go func() {
+		manifest, err := backup.Write(ctx, pw, s.copier, backup.Tables, run.CreatedAt)
+		pw.CloseWithError(err)
+		written <- manifest
+	}()
+	err := s.storage.Put(ctx, run.Name, pr)
+	// Unblock the writer if storage gave up early.
+	pr.CloseWithError(err)
+	manifest := <-written
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now().UTC()
+	run.CompletedAt = &now
+	if err != nil {
+		run.Status = BackupFailed
+		run.Error = "backup failed"
+		s.logger.Error("backup failed", zap.String("backup", run.Name), zap.Error(err))
+		return
+	}
+	run.Status = BackupCompleted
+	run.Tables = manifest.Tables
+	s.logger.Info("backup completed", zap.String("backup", run.Name), zap.Any("tables", manifest.Tables))
+}
+
+// List returns the backups in storage and any this instance is still
+// writing or failed to write, newest first.
+func (s *BackupService) List(ctx context.Context) ([]Backup, error) {
+	objects, err := s.storage.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	backups := make([]Backup, 0, len(objects)+len(s.runs))
+	stored := make(map[string]bool, len(objects))
+	for _, obj := range objects {
+		stored[obj.Name] = true
+		b := Backup{Name: obj.Name, Status: BackupCompleted, Size: obj.Size, CreatedAt: obj.CreatedAt}
+		if run, ok := s.runs[obj.Name]; ok && run.Status == BackupCompleted {
+			b.Tables = run.Tables
+			b.CreatedBy = run.CreatedBy
+			b.CreatedAt = run.CreatedAt
+			b.CompletedAt = run.CompletedAt
+		}
+		backups = append(backups, b)
+	}
+	for _, run := range s.runs {
+		if !stored[run.Name] {
+			backups = append(backups, *run)
+		}
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].CreatedAt.After(backups[j].CreatedAt) })
+	return backups, nil
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/internal/backup"
+)
+
+type stubCopier struct{}
+
+func (stubCopier) CopyOut(ctx context.Context, table string, w io.Writer) (int64, error) {
+	_, err := io.WriteString(w, "id\n1\n")
+	return 1, err
+}
+
+// blockingStorage holds Put open until release is closed.
+type blockingStorage struct {
+	backup.LocalStorage
+	release chan struct{}
+}
+
+func (s blockingStorage) Put(ctx context.Context, name string, r io.Reader) error {
+	<-s.release
+	return s.LocalStorage.Put(ctx, name, r)
+}
+
+func waitForBackup(t *testing.T, s *BackupService, name string) Backup {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		backups, err := s.List(context.Background())
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		for _, b := range backups {
+			if b.Name == name && b.Status != BackupRunning {
+				return b
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("backup %s did not finish", name)
+	return Backup{}
+}
+
+func TestBackupServiceStartAndList(t *testing.T) {
+	storage := blockingStorage{LocalStorage: backup.LocalStorage{Dir: t.TempDir()}, release: make(chan struct{})}
+	s := NewBackupService(stubCopier{}, storage, zap.NewNop())
+
+	started, err := s.Start(context.Background(), 7)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if started.Status != BackupRunning || started.CreatedBy != 7 {
+		t.Errorf("unexpected backup %+v", started)
+	}
+	if _, err := s.Start(context.Background(), 7); !errors.Is(err, ErrBackupRunning) {
+		t.Errorf("expected ErrBackupRunning while a backup runs, got %v", err)
+	}
+
+	close(storage.release)
+	done := waitForBackup(t, s, started.Name)
+	if done.Status != BackupCompleted || done.Size == 0 || done.CompletedAt == nil {
+		t.Errorf("unexpected finished backup %+v", done)
+	}
+	if done.Tables["users"] != 1 || len(done.Tables) != len(backup.Tables) {
+		t.Errorf("unexpected table counts %v", done.Tables)
+	}
+}
+
+type failingCopier struct{}
+
+func (failingCopier) CopyOut(ctx context.Context, table string, w io.Writer) (int64, error) {
+	return 0, errors.New("connection refused")
+}
+
+func TestBackupServiceFailure(t *testing.T) {
+	s := NewBackupService(failingCopier{}, backup.LocalStorage{Dir: t.TempDir()}, zap.NewNop())
+
+	started, err := s.Start(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	done := waitForBackup(t, s, started.Name)
+	if done.Status != BackupFailed || done.Error == "" {
+		t.Errorf("expected a failed backup, got %+v", done)
+	}
+	if _, err := s.Start(context.Background(), 1); err != nil {
+		t.Errorf("expected a new backup to start after a failure, got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/billing"
+	"BACKEND/internal/repository"
+)
+
+var (
+	ErrBillingDisabled      = errors.New("billing is not configured")
+	ErrSeatLimitReached     = errors.New("organization has no free seats")
+	ErrSubscriptionInactive = errors.New("organization has no subscription that allows new members")
+	// ErrBillingOrgUnknown and ErrBillingCustomerInUse are returned for
+	// webhook events that can't be applied however often they are retried.
+	ErrBillingOrgUnknown    = errors.New("billing event is for an unknown organization")
+	ErrBillingCustomerInUse = errors.New("billing customer belongs to another organization")
+)
+
+// SeatChecker decides whether a user can join an organization.
+type SeatChecker interface {
+	CheckSeat(ctx context.Context, orgID, userID int64) error
+}
+
+// BillingStatus is an organization's subscription, as last reported by the
+// billing provider, and the seats it uses.
+type BillingStatus struct {
+	Organization generated.Organization
+	// Subscription is nil when the organization has none.
+	Subscription *generated.OrganizationBilling
+	SeatsUsed    int64
+}
+
+// BillingService keeps each organization's subscription up to date from
+// the billing provider's webhooks and enforces its seat count. With no
+// provider configured billing is off and seats are unlimited.
+type BillingService struct {
+	provider billing.Provider
+	store    repository.BillingStore
+	orgs     repository.OrganizationStore
+}
+
+func NewBillingService(provider billing.Provider, store repository.BillingStore, orgs repository.OrganizationStore) *BillingService {
+	return &BillingService{
+		provider: provider,
+		store:    store,
+		orgs:     orgs,
+	}
+}
+
+func (s *BillingService) Enabled() bool {
+	return s.provider != nil
+}
+
+// HandleWebhook verifies a webhook delivery and stores the subscription
+// change it carries. The event is returned even on error when it could be
+// read, for logging.
+func (s *BillingService) HandleWebhook(ctx context.Context, payload []byte, header func(string) string) (billing.Event, error) {
+	if s.provider == nil {
+		return billing.Event{}, ErrBillingDisabled
+	}
+	evt, err := s.provider.ParseWebhook(payload, header)
+	if err != nil {
+		return evt, err
+	}
+
+	sub := evt.Subscription
+	orgID := sub.OrgID
+	if orgID == 0 {
+		existing, err := s.store.GetByCustomer(ctx, s.provider.Name(), sub.CustomerID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return evt, ErrBillingOrgUnknown
+		}
+		if err != nil {
+			return evt, fmt.Errorf("failed to look up billing customer: %w", err)
+		}
+		orgID = existing.OrgID
+	}
+
+	update := repository.BillingUpdate{
+		OrgID:          orgID,
+		Provider:       s.provider.Name(),
+		CustomerID:     sub.CustomerID,
+		SubscriptionID: sub.SubscriptionID,
+		Plan:           sub.Plan,
+		Status:         string(sub.Status),
+		Seats:          sub.Seats,
+		EventAt:        evt.CreatedAt,
+	}
+	if !sub.CurrentPeriodEnd.IsZero() {
+		update.CurrentPeriodEnd = &sub.CurrentPeriodEnd
+	}
+	if err := s.store.Apply(ctx, update); err != nil {
+		switch {
+		case errors.Is(err, repository.ErrForeignKeyViolation):
+			return evt, ErrBillingOrgUnknown
+		case errors.Is(err, repository.ErrUniqueViolation):
+			return evt, ErrBillingCustomerInUse
+		}
+		return evt, fmt.Errorf("failed to store subscription: %w", err)
+	}
+	return evt, nil
+}
+
+// CheckSeat fails unless the organization's subscription allows members
+// and has a seat free for the user. Members already counted don't need a
+// new one.
+func (s *BillingService) CheckSeat(ctx context.Context, orgID, userID int64) error {
+	if s.provider == nil {
+		return nil
+	}
+
+	sub, err := s.store.Get(ctx, orgID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if _, err := s.orgs.Get(ctx, orgID); errors.Is(err, pgx.ErrNoRows) {
+			return ErrOrganizationNotFound
+		}
+		return ErrSubscriptionInactive
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load subscription: %w", err)
+	}
+	if !billing.Status(sub.Status).AllowsSeats() {
+		return ErrSubscriptionInactive
+	}
+
+	used, err := s.orgs.CountSeats(ctx, orgID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to count seats: %w", err)
+	}
+	if used >= sub.Seats {
+		return ErrSeatLimitReached
+	}
+	return nil
+}
+
+func (s *BillingService) Status(ctx context.Context, orgID int64) (BillingStatus, error) {
+	org, err := s.orgs.Get(ctx, orgID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return BillingStatus{}, ErrOrganizationNotFound
+	}
+	if err != nil {
+		return BillingStatus{}, err
+	}
+
+	status := BillingStatus{Organization: org}
+	sub, err := s.store.Get(ctx, orgID)
+	switch {
+	case err == nil:
+		status.Subscription = &sub
+	case !errors.Is(err, pgx.ErrNoRows):
+		return BillingStatus{}, err
+	}
+
+	if status.SeatsUsed, err = s.orgs.CountSeats(ctx, orgID, 0); err != nil {
+		return BillingStatus{}, err
+	}
+	return status, nil
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/billing"
+	"BACKEND/internal/repository"
+)
+
+type fakeBillingStore struct {
+	subs map[int64]generated.OrganizationBilling
+}
+
+func (f *fakeBillingStore) Get(ctx context.Context, orgID int64) (generated.OrganizationBilling, error) {
+	sub, ok := f.subs[orgID]
+	if !ok {
+		return generated.OrganizationBilling{}, pgx.ErrNoRows
+	}
+	return sub, nil
+}
+
+func (f *fakeBillingStore) GetByCustomer(ctx context.Context, provider, customerID string) (generated.OrganizationBilling, error) {
+	for _, sub := range f.subs {
+		if sub.Provider == provider && sub.CustomerID == customerID {
+			return sub, nil
+		}
+	}
+	return generated.OrganizationBilling{}, pgx.ErrNoRows
+}
+
+func (f *fakeBillingStore) Apply(ctx context.Context, u repository.BillingUpdate) error {
+	if existing, ok := f.subs[u.OrgID]; ok && existing.EventAt.Time.After(u.EventAt) {
+		return nil
+	}
+	f.subs[u.OrgID] = generated.OrganizationBilling{
+		OrgID:          u.OrgID,
+		Provider:       u.Provider,
+		CustomerID:     u.CustomerID,
+		SubscriptionID: u.SubscriptionID,
+		Plan:           u.Plan,
+		Status:         u.Status,
+		Seats:          u.Seats,
+		EventAt:        pgtype.Timestamp{Time: u.EventAt, Valid: true},
+	}
+	return nil
+}
+
+type fakeBillingProvider struct {
+	event billing.Event
+	err   error
+}
+
+func (p *fakeBillingProvider) Name() string { return "fake" }
+
+func (p *fakeBillingProvider) ParseWebhook(payload []byte, header func(string) string) (billing.Event, error) {
+	return p.event, p.err
+}
+
+func newTestBillingService() (*BillingService, *OrganizationService, *fakeBillingProvider, *fakeBillingStore) {
+	orgSvc, orgs := newTestOrganizationService()
+	provider := &fakeBillingProvider{}
+	store := &fakeBillingStore{subs: make(map[int64]generated.OrganizationBilling)}
+	svc := NewBillingService(provider, store, orgs)
+	orgSvc.SetSeatChecker(svc)
+	return svc, orgSvc, provider, store
+}
+
+func subscriptionEvent(at time.Time, orgID int64, status billing.Status, seats int64) billing.Event {
+	return billing.Event{
+		ID:        "evt",
+		CreatedAt: at,
+		Subscription: billing.Subscription{
+			OrgID:          orgID,
+			CustomerID:     "cus_1",
+			SubscriptionID: "sub_1",
+			Status:         status,
+			Seats:          seats,
+		},
+	}
+}
+
+func TestBillingWebhook(t *testing.T) {
+	ctx := context.Background()
+	svc, _, provider, store := newTestBillingService()
+	t0 := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	provider.event = subscriptionEvent(t0, 0, billing.StatusActive, 2)
+	if _, err := svc.HandleWebhook(ctx, nil, nil); !errors.Is(err, ErrBillingOrgUnknown) {
+		t.Errorf("unknown customer without metadata: expected ErrBillingOrgUnknown, got %v", err)
+	}
+
+	provider.event = subscriptionEvent(t0, 1, billing.StatusActive, 2)
+	if _, err := svc.HandleWebhook(ctx, nil, nil); err != nil {
+		t.Fatalf("webhook: %v", err)
+	}
+
+	// Later events find the organization by customer.
+	provider.event = subscriptionEvent(t0.Add(time.Hour), 0, billing.StatusActive, 3)
+	if _, err := svc.HandleWebhook(ctx, nil, nil); err != nil {
+		t.Fatalf("webhook: %v", err)
+	}
+	if store.subs[1].Seats != 3 || store.subs[1].Provider != "fake" {
+		t.Errorf("unexpected subscription %+v", store.subs[1])
+	}
+
+	provider.err = billing.ErrInvalidSignature
+	if _, err := svc.HandleWebhook(ctx, nil, nil); !errors.Is(err, billing.ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestBillingSeatEnforcement(t *testing.T) {
+	ctx := context.Background()
+	_, orgSvc, _, store := newTestBillingService()
+
+	if err := orgSvc.SetMember(ctx, 1, 1); !errors.Is(err, ErrSubscriptionInactive) {
+		t.Fatalf("no subscription: expected ErrSubscriptionInactive, got %v", err)
+	}
+	if err := orgSvc.SetMember(ctx, 1, 9); !errors.Is(err, ErrOrganizationNotFound) {
+		t.Fatalf("expected ErrOrganizationNotFound, got %v", err)
+	}
+
+	store.subs[1] = generated.OrganizationBilling{OrgID: 1, Status: string(billing.StatusActive), Seats: 2}
+	for _, userID := range []int64{1, 2} {
+		if err := orgSvc.SetMember(ctx, userID, 1); err != nil {
+			t.Fatalf("set member %d: %v", userID, err)
+		}
+	}
+	if err := orgSvc.SetMember(ctx, 3, 1); !errors.Is(err, ErrSeatLimitReached) {
+		t.Errorf("expected ErrSeatLimitReached, got %v", err)
+	}
+	if err := orgSvc.SetMember(ctx, 2, 1); err != nil {
+		t.Errorf("a member already holding a seat was refused: %v", err)
+	}
+
+	store.subs[1] = generated.OrganizationBilling{OrgID: 1, Status: string(billing.StatusCanceled), Seats: 10}
+	if err := orgSvc.SetMember(ctx, 3, 1); !errors.Is(err, ErrSubscriptionInactive) {
+		t.Errorf("canceled subscription: expected ErrSubscriptionInactive, got %v", err)
+	}
+}
+
+func TestBillingDisabledAllowsMembers(t *testing.T) {
+	orgSvc, orgs := newTestOrganizationService()
+	orgSvc.SetSeatChecker(NewBillingService(nil, &fakeBillingStore{subs: make(map[int64]generated.OrganizationBilling)}, orgs))
+	if err := orgSvc.SetMember(context.Background(), 1, 1); err != nil {
+		t.Errorf("set member: %v", err)
+	}
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/internal/clock"
+)
+
+// Rules a SecurityAlert can be raised for.
+const (
+	RuleIPFailures         = "ip_failures"
+	RuleCredentialStuffing = "credential_stuffing"
+	RuleDistributedAttack  = "distributed_attack"
+	RuleStuffingSuccess    = "credential_stuffing_success"
+)
+
+const (
+	maxRecentAlerts = 100
+	// maxAlertSample caps the IPs or accounts listed in one alert.
+	maxAlertSample = 20
+)
+
+type BruteForceConfig struct {
+	Window        time.Duration
+	FailuresPerIP int
+	AccountsPerIP int
+	IPsPerAccount int
+	AlertCooldown time.Duration
+}
+
+// SecurityAlert is raised when login failures match an attack pattern. Key
+// is the IP address or, for attacks on one account, the email.
+type SecurityAlert struct {
+	Rule        string    `json:"rule"`
+	Key         string    `json:"key"`
+	Description string    `json:"description"`
+	Failures    int       `json:"failures"`
+	IPs         []string  `json:"ips,omitempty"`
+	Accounts    []string  `json:"accounts,omitempty"`
+	Window      string    `json:"window"`
+	DetectedAt  time.Time `json:"detected_at"`
+}
+
+// SecurityAlerter delivers alerts, e.g. to a webhook or by email.
+type SecurityAlerter interface {
+	Alert(ctx context.Context, alert SecurityAlert) error
+}
+
+type loginFailure struct {
+	at    time.Time
+	ip    string
+	email string
+}
+
+// BruteForceDetector watches login attempts for password guessing: many
+// failures from one IP, one IP trying many accounts (credential stuffing),
+// many IPs trying one account, and a success from an IP that has been
+// stuffing credentials. It only raises alerts; it never blocks a login.
+type BruteForceDetector struct {
+	cfg      BruteForceConfig
+	alerters []SecurityAlerter
+	logger   *zap.Logger
+	clock    clock.Clock
+
+	mu         sync.Mutex
+	byIP       map[string][]loginFailure
+	byAccount  map[string][]loginFailure
+	lastAlert  map[string]time.Time
+	recent     []SecurityAlert
+	lastPruned time.Time
+}
+
+func NewBruteForceDetector(cfg BruteForceConfig, logger *zap.Logger, alerters ...SecurityAlerter) *BruteForceDetector {
+	return &BruteForceDetector{
+		cfg:       cfg,
+		alerters:  alerters,
+		logger:    logger,
+		clock:     clock.System,
+		byIP:      make(map[string][]loginFailure),
+		byAccount: make(map[string][]loginFailure),
+		lastAlert: make(map[string]time.Time),
+	}
+}
+
+// SetClock sets the clock failures and alert cooldowns are timed with.
+func (d *BruteForceDetector) SetClock(c clock.Clock) {
+	d.clock = c
+}
+
+// Observe records the outcome of a password login and raises any alerts it
+// triggers. Alerts are delivered in the background.
+func (d *BruteForceDetector) Observe(ip, email string, succeeded bool) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	now := d.clock.Now()
+
+	d.mu.Lock()
+	d.pruneLocked(now)
+	alerts := d.evaluateLocked(now, ip, email, succeeded)
+	d.mu.Unlock()
+
+	for _, alert := range alerts {
+		d.logger.Warn("security alert",
+			zap.String("rule", alert.Rule),
+			zap.String("key", alert.Key),
+			zap.Int("failures", alert.Failures),
+		)
+		go d.dispatch(alert)
+	}
+}
+
+func (d *BruteForceDetector) evaluateLocked(now time.Time, ip, email string, succeeded bool) []SecurityAlert {
+	var alerts []SecurityAlert
+	ipFailures := d.trimLocked(d.byIP, ip, now)
+	accounts := distinct(ipFailures, func(f loginFailure) string { return f.email })
+
+	if succeeded {
+		if d.cfg.AccountsPerIP > 0 && len(accounts) >= d.cfg.AccountsPerIP {
+			if alert, ok := d.raiseLocked(now, RuleStuffingSuccess, email, "successful login from an IP that failed against many accounts", len(ipFailures), []string{ip}, accounts); ok {
+				alerts = append(alerts, alert)
+			}
+		}
+		return alerts
+	}
+
+	failure := loginFailure{at: now, ip: ip, email: email}
+	d.byIP[ip] = append(ipFailures, failure)
+	d.byAccount[email] = append(d.trimLocked(d.byAccount, email, now), failure)
+	ipFailures = d.byIP[ip]
+	accounts = distinct(ipFailures, func(f loginFailure) string { return f.email })
+	ips := distinct(d.byAccount[email], func(f loginFailure) string { return f.ip })
+
+	if d.cfg.FailuresPerIP > 0 && len(ipFailures) >= d.cfg.FailuresPerIP {
+		if alert, ok := d.raiseLocked(now, RuleIPFailures, ip, "many failed logins from one IP", len(ipFailures), nil, accounts); ok {
+			alerts = append(alerts, alert)
+		}
+	}
+	if d.cfg.AccountsPerIP > 0 && len(accounts) >= d.cfg.AccountsPerIP {
+		if alert, ok := d.raiseLocked(now, RuleCredentialStuffing, ip, "failed logins against many accounts from one IP", len(ipFailures), nil, accounts); ok {
+			alerts = append(alerts, alert)
+		}
+	}
+	if d.cfg.IPsPerAccount > 0 && len(ips) >= d.cfg.IPsPerAccount {
+		if alert, ok := d.raiseLocked(now, RuleDistributedAttack, email, "failed logins against one account from many IPs", len(d.byAccount[email]), ips, nil); ok {
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts
+}
+
+func (d *BruteForceDetector) raiseLocked(now time.Time, rule, key, description string, failures int, ips, accounts []string) (SecurityAlert, bool) {
+	cooldownKey := rule + "|" + key
+	if last, ok := d.lastAlert[cooldownKey]; ok && now.Sub(last) < d.cfg.AlertCooldown {
+		return SecurityAlert{}, false
+	}
+	d.lastAlert[cooldownKey] = now
+
+	alert := SecurityAlert{
+		Rule:        rule,
+		Key:         key,
+		Description: description,
+		Failures:    failures,
+		IPs:         sample(ips),
+		Accounts:    sample(accounts),
+		Window:      d.cfg.Window.String(),
+		DetectedAt:  now,
+	}
+	d.recent = append(d.recent, alert)
+	if len(d.recent) > maxRecentAlerts {
+		d.recent = d.recent[len(d.recent)-maxRecentAlerts:]
+	}
+	return alert, true
+}
+
+func (d *BruteForceDetector) dispatch(alert SecurityAlert) {
+	for _, alerter := range d.alerters {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		if err := alerter.Alert(ctx, alert); err != nil {
+			d.logger.Error("failed to deliver security alert", zap.String("rule", alert.Rule), zap.Error(err))
+		}
+		cancel()
+	}
+}
+
+// Alerts returns recent alerts, newest first.
+func (d *BruteForceDetector) Alerts() []SecurityAlert {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	alerts := make([]SecurityAlert, len(d.recent))
+	for i, alert := range d.recent {
+		alerts[len(d.recent)-1-i] = alert
+	}
+	return alerts
+}
+
+// trimLocked drops failures that have left the window.
+func (d *BruteForceDetector) trimLocked(m map[string][]loginFailure, key string, now time.Time) []loginFailure {
+	failures := m[key]
+	i := 0
+	for i < len(failures) && now.Sub(failures[i].at) > d.cfg.Window {
+		i++
+	}
+	failures = failures[i:]
+	if len(failures) == 0 {
+		delete(m, key)
+		return nil
+	}
+	m[key] = failures
+	return failures
+}
+
+// pruneLocked sweeps keys that have not been seen for a window, at most
+// once a minute.
+func (d *BruteForceDetector) pruneLocked(now time.Time) {
+	if now.Sub(d.lastPruned) < time.Minute {
+		return
+	}
+	d.lastPruned = now
+	for key := range d.byIP {
+		d.trimLocked(d.byIP, key, now)
+	}
+	for key := range d.byAccount {
+		d.trimLocked(d.byAccount, key, now)
+	}
+	for key, at := range d.lastAlert {
+		if now.Sub(at) > d.cfg.AlertCooldown {
+			delete(d.lastAlert, key)
+		}
+	}
+}
+
+func distinct(failures []loginFailure, field func(loginFailure) string) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for _, f := range failures {
+		if v := field(f); !seen[v] {
+			seen[v] = true
+			values = append(values, v)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+func sample(values []string) []string {
+	if len(values) > maxAlertSample {
+		return values[:maxAlertSample]
+	}
+	return values
+}
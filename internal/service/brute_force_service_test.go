@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/internal/clock"
+)
+
+type recordingAlerter struct {
+	mu     sync.Mutex
+	alerts []SecurityAlert
+	done   chan struct{}
+}
+
+func (a *recordingAlerter) Alert(ctx context.Context, alert SecurityAlert) error {
+	a.mu.Lock()
+	a.alerts = append(a.alerts, alert)
+	a.mu.Unlock()
+	a.done <- struct{}{}
+	return nil
+}
+
+func newTestDetector(alerter SecurityAlerter) *BruteForceDetector {
+	return NewBruteForceDetector(BruteForceConfig{
+		Window:        time.Minute,
+		FailuresPerIP: 10,
+		AccountsPerIP: 3,
+		IPsPerAccount: 3,
+		AlertCooldown: time.Hour,
+	}, zap.NewNop(), alerter)
+}
+
+func rules(alerts []SecurityAlert) []string {
+	var out []string
+	for _, a := range alerts {
+		out = append(out, a.Rule+" "+a.Key)
+	}
+	return out
+}
+
+func TestBruteForceIPFailures(t *testing.T) {
+	alerter := &recordingAlerter{done: make(chan struct{}, 10)}
+	d := newTestDetector(alerter)
+
+	for i := 0; i < 9; i++ {
+		d.Observe("198.51.100.1", "alice@example.com", false)
+	}
+	if len(d.Alerts()) != 0 {
+		t.Fatalf("expected no alert below the threshold, got %v", rules(d.Alerts()))
+	}
+
+	d.Observe("198.51.100.1", "Alice@example.com", false)
+	<-alerter.done
+
+	alerts := d.Alerts()
+	if len(alerts) != 1 || alerts[0].Rule != RuleIPFailures || alerts[0].Key != "198.51.100.1" || alerts[0].Failures != 10 {
+		t.Fatalf("unexpected alerts %+v", alerts)
+	}
+
+	for i := 0; i < 5; i++ {
+		d.Observe("198.51.100.1", "alice@example.com", false)
+	}
+	if len(d.Alerts()) != 1 {
+		t.Errorf("expected cooldown to suppress repeat alerts, got %v", rules(d.Alerts()))
+	}
+}
+
+func TestBruteForceCredentialStuffing(t *testing.T) {
+	alerter := &recordingAlerter{done: make(chan struct{}, 10)}
+	d := newTestDetector(alerter)
+
+	for i := 0; i < 3; i++ {
+		d.Observe("203.0.113.7", fmt.Sprintf("user%d@example.com", i), false)
+	}
+	<-alerter.done
+	if got := rules(d.Alerts()); len(got) != 1 || got[0] != RuleCredentialStuffing+" 203.0.113.7" {
+		t.Fatalf("alerts = %v; want credential stuffing from 203.0.113.7", got)
+	}
+
+	d.Observe("203.0.113.7", "victim@example.com", true)
+	<-alerter.done
+	if got := d.Alerts()[0]; got.Rule != RuleStuffingSuccess || got.Key != "victim@example.com" {
+		t.Errorf("newest alert = %+v; want a stuffing success for victim@example.com", got)
+	}
+}
+
+func TestBruteForceDistributedAttack(t *testing.T) {
+	alerter := &recordingAlerter{done: make(chan struct{}, 10)}
+	d := newTestDetector(alerter)
+
+	for i := 0; i < 3; i++ {
+		d.Observe(fmt.Sprintf("192.0.2.%d", i), "admin@example.com", false)
+	}
+	<-alerter.done
+
+	alerts := d.Alerts()
+	if len(alerts) != 1 || alerts[0].Rule != RuleDistributedAttack || len(alerts[0].IPs) != 3 {
+		t.Fatalf("unexpected alerts %+v", alerts)
+	}
+}
+
+func TestBruteForceSuccessWithoutStuffing(t *testing.T) {
+	d := newTestDetector(&recordingAlerter{done: make(chan struct{}, 10)})
+
+	d.Observe("198.51.100.1", "alice@example.com", false)
+	d.Observe("198.51.100.1", "alice@example.com", true)
+	if len(d.Alerts()) != 0 {
+		t.Errorf("expected a normal typo-then-login to raise nothing, got %v", rules(d.Alerts()))
+	}
+}
+
+func TestBruteForceWindowExpiry(t *testing.T) {
+	alerter := &recordingAlerter{done: make(chan struct{}, 10)}
+	d := newTestDetector(alerter)
+	clk := clock.NewFake(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+	d.SetClock(clk)
+
+	for i := 0; i < 9; i++ {
+		d.Observe("198.51.100.1", "alice@example.com", false)
+	}
+	clk.Advance(2 * time.Minute)
+	d.Observe("198.51.100.1", "alice@example.com", false)
+	if len(d.Alerts()) != 0 {
+		t.Fatalf("expected failures outside the window to be forgotten, got %v", rules(d.Alerts()))
+	}
+
+	for i := 0; i < 9; i++ {
+		d.Observe("198.51.100.1", "alice@example.com", false)
+	}
+	<-alerter.done
+	if got := rules(d.Alerts()); len(got) != 1 || got[0] != RuleIPFailures+" 198.51.100.1" {
+		t.Fatalf("alerts = %v; want one IP failures alert", got)
+	}
+
+	clk.Advance(2 * time.Hour)
+	for i := 0; i < 10; i++ {
+		d.Observe("198.51.100.1", "alice@example.com", false)
+	}
+	<-alerter.done
+	if len(d.Alerts()) != 2 {
+		t.Errorf("expected a new alert once the cooldown passed, got %v", rules(d.Alerts()))
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ClaimsEnricher adds claims (org ID, permissions, custom attributes) to
+// every token AuthService issues. Returning an error fails the login.
+type ClaimsEnricher interface {
+	Enrich(ctx context.Context, userID int64, role string) (map[string]interface{}, error)
+}
+
+type ClaimsEnricherFunc func(ctx context.Context, userID int64, role string) (map[string]interface{}, error)
+
+func (f ClaimsEnricherFunc) Enrich(ctx context.Context, userID int64, role string) (map[string]interface{}, error) {
+	return f(ctx, userID, role)
+}
+
+// StaticClaims adds the same claims to every token.
+func StaticClaims(claims map[string]interface{}) ClaimsEnricher {
+	return ClaimsEnricherFunc(func(context.Context, int64, string) (map[string]interface{}, error) {
+		return claims, nil
+	})
+}
+
+// ChainEnrichers merges the claims of several enrichers; later ones win on
+// conflicting keys. Nil enrichers are skipped.
+func ChainEnrichers(enrichers ...ClaimsEnricher) ClaimsEnricher {
+	return ClaimsEnricherFunc(func(ctx context.Context, userID int64, role string) (map[string]interface{}, error) {
+		merged := map[string]interface{}{}
+		for _, e := range enrichers {
+			if e == nil {
+				continue
+			}
+			claims, err := e.Enrich(ctx, userID, role)
+			if err != nil {
+				return nil, err
+			}
+			for k, v := range claims {
+				merged[k] = v
+			}
+		}
+		return merged, nil
+	})
+}
+
+// reservedClaims can't be set by an enricher: they carry identity and
+// validity, and the auth middleware relies on them.
+var reservedClaims = map[string]bool{
+	"user_id": true,
+	"role":    true,
+	"iss":     true,
+	"sub":     true,
+	"aud":     true,
+	"exp":     true,
+	"nbf":     true,
+	"iat":     true,
+	"jti":     true,
+	"sid":     true,
+}
+
+type jwtClaimsFields JWTClaims
+
+// MarshalJSON flattens Extra into the top level of the token payload.
+func (c JWTClaims) MarshalJSON() ([]byte, error) {
+	base, err := json.Marshal(jwtClaimsFields(c))
+	if err != nil || len(c.Extra) == 0 {
+		return base, err
+	}
+
+	merged := map[string]json.RawMessage{}
+	if err := json.Unmarshal(base, &merged); err != nil {
+		return nil, err
+	}
+	for k, v := range c.Extra {
+		if reservedClaims[k] {
+			continue
+		}
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("claim %q: %w", k, err)
+		}
+		merged[k] = raw
+	}
+	return json.Marshal(merged)
+}
+
+// UnmarshalJSON collects non-reserved claims into Extra.
+func (c *JWTClaims) UnmarshalJSON(data []byte) error {
+	var fields jwtClaimsFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	for k, v := range all {
+		if reservedClaims[k] {
+			continue
+		}
+		if fields.Extra == nil {
+			fields.Extra = map[string]interface{}{}
+		}
+		fields.Extra[k] = v
+	}
+
+	*c = JWTClaims(fields)
+	return nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"BACKEND/internal/clock"
+)
+
+func TestGenerateJWTWithEnrichedClaims(t *testing.T) {
+	svc := &AuthService{}
+	svc.SetJWTConfig("test-secret", time.Hour)
+	svc.SetClaimsEnricher(ChainEnrichers(
+		StaticClaims(map[string]interface{}{"org_id": "acme", "tier": "free"}),
+		ClaimsEnricherFunc(func(ctx context.Context, userID int64, role string) (map[string]interface{}, error) {
+			return map[string]interface{}{
+				"tier":        "enterprise",
+				"permissions": []string{"users:read"},
+				"role":        "admin",
+			}, nil
+		}),
+	))
+
+	tokenString, err := svc.GenerateJWT(context.Background(), 42, "user")
+	if err != nil {
+		t.Fatalf("GenerateJWT() error = %v", err)
+	}
+
+	claims := &JWTClaims{}
+	if _, err := jwt.ParseWithClaims(tokenString, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte("test-secret"), nil
+	}); err != nil {
+		t.Fatalf("failed to parse token: %v", err)
+	}
+
+	if claims.UserID != 42 || claims.Role != "user" {
+		t.Errorf("expected user 42 with role user, got %d/%s", claims.UserID, claims.Role)
+	}
+	if claims.Extra["org_id"] != "acme" {
+		t.Errorf("expected org_id acme, got %v", claims.Extra["org_id"])
+	}
+	if claims.Extra["tier"] != "enterprise" {
+		t.Errorf("expected later enricher to win, got tier %v", claims.Extra["tier"])
+	}
+	if perms, ok := claims.Extra["permissions"].([]interface{}); !ok || len(perms) != 1 || perms[0] != "users:read" {
+		t.Errorf("unexpected permissions claim %v", claims.Extra["permissions"])
+	}
+	if _, ok := claims.Extra["role"]; ok {
+		t.Error("reserved claims must not appear in Extra")
+	}
+}
+
+func TestGenerateJWTEnricherError(t *testing.T) {
+	svc := &AuthService{}
+	svc.SetJWTConfig("test-secret", time.Hour)
+	svc.SetClaimsEnricher(ClaimsEnricherFunc(func(context.Context, int64, string) (map[string]interface{}, error) {
+		return nil, errors.New("directory unavailable")
+	}))
+
+	if _, err := svc.GenerateJWT(context.Background(), 1, "user"); err == nil {
+		t.Error("expected enricher error to fail token generation")
+	}
+}
+
+func TestGenerateJWTUsesClock(t *testing.T) {
+	issued := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	svc := &AuthService{}
+	svc.SetJWTConfig("test-secret", time.Hour)
+	svc.SetClock(clock.NewFake(issued))
+
+	tokenString, err := svc.GenerateJWT(context.Background(), 42, "user")
+	if err != nil {
+		t.Fatalf("GenerateJWT() error = %v", err)
+	}
+
+	claims := &JWTClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(tokenString, claims); err != nil {
+		t.Fatalf("failed to parse token: %v", err)
+	}
+	if !claims.IssuedAt.Time.Equal(issued) {
+		t.Errorf("iat = %v; want %v", claims.IssuedAt.Time, issued)
+	}
+	if !claims.ExpiresAt.Time.Equal(issued.Add(time.Hour)) {
+		t.Errorf("exp = %v; want %v", claims.ExpiresAt.Time, issued.Add(time.Hour))
+	}
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/sso"
+)
+
+// Errors returned while polling for a token. Their messages are the RFC 8628
+// error codes, so handlers can send them to the client as is.
+var (
+	ErrDeviceAuthorizationPending = errors.New("authorization_pending")
+	ErrDeviceSlowDown             = errors.New("slow_down")
+	ErrDeviceAccessDenied         = errors.New("access_denied")
+	ErrDeviceExpiredToken         = errors.New("expired_token")
+	ErrDeviceInvalidCode          = errors.New("invalid_grant")
+)
+
+// User codes avoid vowels and look-alike characters so they are easy to
+// read off one screen and type into another.
+const userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+
+type DeviceConfig struct {
+	CodeTTL         time.Duration
+	PollInterval    time.Duration
+	VerificationURL string
+}
+
+type DeviceCode struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+type deviceAuthorization struct {
+	userCode   string
+	expiresAt  time.Time
+	interval   time.Duration
+	lastPolled time.Time
+	userID     int64
+	approved   bool
+	denied     bool
+}
+
+type DeviceService struct {
+	repo repository.UserStore
+	auth *AuthService
+	cfg  DeviceConfig
+
+	mu        sync.Mutex
+	pending   map[string]*deviceAuthorization
+	userCodes map[string]string
+}
+
+func NewDeviceService(repo repository.UserStore, auth *AuthService, cfg DeviceConfig) *DeviceService {
+	return &DeviceService{
+		repo:      repo,
+		auth:      auth,
+		cfg:       cfg,
+		pending:   make(map[string]*deviceAuthorization),
+		userCodes: make(map[string]string),
+	}
+}
+
+// Start begins a device authorization for a CLI client.
+func (s *DeviceService) Start() (*DeviceCode, error) {
+	deviceCode, err := sso.RandomString()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pruneLocked()
+
+	var userCode string
+	for {
+		userCode, err = randomUserCode()
+		if err != nil {
+			return nil, err
+		}
+		if _, taken := s.userCodes[userCode]; !taken {
+			break
+		}
+	}
+
+	s.pending[deviceCode] = &deviceAuthorization{
+		userCode:  userCode,
+		expiresAt: time.Now().Add(s.cfg.CodeTTL),
+		interval:  s.cfg.PollInterval,
+	}
+	s.userCodes[userCode] = deviceCode
+
+	return &DeviceCode{
+		DeviceCode:              deviceCode,
+		UserCode:                userCode,
+		VerificationURI:         s.cfg.VerificationURL,
+		VerificationURIComplete: s.cfg.VerificationURL + "?user_code=" + userCode,
+		ExpiresIn:               int(s.cfg.CodeTTL.Seconds()),
+		Interval:                int(s.cfg.PollInterval.Seconds()),
+	}, nil
+}
+
+// Approve grants the device behind userCode a token for userID. Denying
+// makes the device's next poll fail with access_denied.
+func (s *DeviceService) Approve(userCode string, userID int64, approve bool) error {
+	userCode = normalizeUserCode(userCode)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	auth, ok := s.pending[s.userCodes[userCode]]
+	if !ok || auth.approved || auth.denied {
+		return ErrDeviceInvalidCode
+	}
+	if time.Now().After(auth.expiresAt) {
+		return ErrDeviceExpiredToken
+	}
+
+	if approve {
+		auth.approved = true
+		auth.userID = userID
+	} else {
+		auth.denied = true
+	}
+	return nil
+}
+
+// Token is polled by the device until the user has approved or denied it.
+func (s *DeviceService) Token(ctx context.Context, deviceCode string) (string, error) {
+	s.mu.Lock()
+	auth, ok := s.pending[deviceCode]
+	if !ok {
+		s.mu.Unlock()
+		return "", ErrDeviceInvalidCode
+	}
+
+	now := time.Now()
+	switch {
+	case now.After(auth.expiresAt):
+		s.removeLocked(deviceCode)
+		s.mu.Unlock()
+		return "", ErrDeviceExpiredToken
+	case auth.denied:
+		s.removeLocked(deviceCode)
+		s.mu.Unlock()
+		return "", ErrDeviceAccessDenied
+	case !auth.approved:
+		tooSoon := now.Sub(auth.lastPolled) < auth.interval
+		auth.lastPolled = now
+		if tooSoon {
+			auth.interval += 5 * time.Second
+			s.mu.Unlock()
+			return "", ErrDeviceSlowDown
+		}
+		s.mu.Unlock()
+		return "", ErrDeviceAuthorizationPending
+	}
+
+	userID := auth.userID
+	s.removeLocked(deviceCode)
+	s.mu.Unlock()
+
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("failed to load approving user: %w", err)
+	}
+	if !user.Active {
+		return "", ErrAccountDisabled
+	}
+
+	token, err := s.auth.GenerateJWT(ctx, user.ID, user.Role)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	s.auth.afterLogin(ctx, generated.User{
+		ID:          user.ID,
+		Name:        user.Name,
+		Dob:         user.Dob,
+		Email:       user.Email,
+		Role:        user.Role,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+		Active:      user.Active,
+		AccountType: user.AccountType,
+	})
+	return token, nil
+}
+
+func (s *DeviceService) removeLocked(deviceCode string) {
+	if auth, ok := s.pending[deviceCode]; ok {
+		delete(s.userCodes, auth.userCode)
+		delete(s.pending, deviceCode)
+	}
+}
+
+func (s *DeviceService) pruneLocked() {
+	now := time.Now()
+	for code, auth := range s.pending {
+		if now.After(auth.expiresAt) {
+			s.removeLocked(code)
+		}
+	}
+}
+
+func randomUserCode() (string, error) {
+	var b strings.Builder
+	for i := 0; i < 8; i++ {
+		if i == 4 {
+			b.WriteByte('-')
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(userCodeAlphabet))))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate user code: %w", err)
+		}
+		b.WriteByte(userCodeAlphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// normalizeUserCode accepts codes typed in lower case or without the dash.
+func normalizeUserCode(code string) string {
+	code = strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	if len(code) != 8 {
+		return code
+	}
+	return code[:4] + "-" + code[4:]
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/repository"
+)
+
+type fakeDeviceUserStore struct {
+	repository.UserStore
+	user generated.GetUserByIDRow
+}
+
+func (f *fakeDeviceUserStore) GetByID(ctx context.Context, id int64) (generated.GetUserByIDRow, error) {
+	return f.user, nil
+}
+
+func newTestDeviceService(user generated.GetUserByIDRow) *DeviceService {
+	store := &fakeDeviceUserStore{user: user}
+	auth := NewAuthService(store)
+	auth.SetJWTConfig("test-secret", time.Hour)
+	return NewDeviceService(store, auth, DeviceConfig{
+		CodeTTL:         time.Minute,
+		PollInterval:    0,
+		VerificationURL: "https://example.com/device",
+	})
+}
+
+func TestDeviceFlowApprove(t *testing.T) {
+	svc := newTestDeviceService(generated.GetUserByIDRow{ID: 7, Role: "user", Active: true})
+	ctx := context.Background()
+
+	code, err := svc.Start()
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if code.VerificationURIComplete != "https://example.com/device?user_code="+code.UserCode {
+		t.Errorf("unexpected verification_uri_complete %q", code.VerificationURIComplete)
+	}
+
+	if _, err := svc.Token(ctx, code.DeviceCode); !errors.Is(err, ErrDeviceAuthorizationPending) {
+		t.Fatalf("expected authorization_pending, got %v", err)
+	}
+
+	// Users may type the code in lower case without the dash.
+	typed := code.UserCode[:4] + code.UserCode[5:]
+	if err := svc.Approve(typed, 7, true); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+
+	token, err := svc.Token(ctx, code.DeviceCode)
+	if err != nil || token == "" {
+		t.Fatalf("expected token, got %q, %v", token, err)
+	}
+
+	if _, err := svc.Token(ctx, code.DeviceCode); !errors.Is(err, ErrDeviceInvalidCode) {
+		t.Errorf("expected device code to be single use, got %v", err)
+	}
+}
+
+func TestDeviceFlowDeny(t *testing.T) {
+	svc := newTestDeviceService(generated.GetUserByIDRow{ID: 7, Active: true})
+
+	code, err := svc.Start()
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := svc.Approve(code.UserCode, 7, false); err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if err := svc.Approve(code.UserCode, 7, true); !errors.Is(err, ErrDeviceInvalidCode) {
+		t.Errorf("expected a decided code to be rejected, got %v", err)
+	}
+	if _, err := svc.Token(context.Background(), code.DeviceCode); !errors.Is(err, ErrDeviceAccessDenied) {
+		t.Errorf("expected access_denied, got %v", err)
+	}
+}
+
+func TestDeviceFlowSlowDown(t *testing.T) {
+	svc := newTestDeviceService(generated.GetUserByIDRow{})
+	svc.cfg.PollInterval = time.Hour
+
+	code, err := svc.Start()
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	if _, err := svc.Token(context.Background(), code.DeviceCode); !errors.Is(err, ErrDeviceAuthorizationPending) {
+		t.Fatalf("expected authorization_pending on first poll, got %v", err)
+	}
+	if _, err := svc.Token(context.Background(), code.DeviceCode); !errors.Is(err, ErrDeviceSlowDown) {
+		t.Errorf("expected slow_down, got %v", err)
+	}
+}
+
+func TestNormalizeUserCode(t *testing.T) {
+	tests := map[string]string{
+		"BCDF-GHJK":   "BCDF-GHJK",
+		"bcdfghjk":    "BCDF-GHJK",
+		" bcdf-ghjk ": "BCDF-GHJK",
+		"short":       "SHORT",
+	}
+	for in, expected := range tests {
+		if got := normalizeUserCode(in); got != expected {
+			t.Errorf("normalizeUserCode(%q) = %q, expected %q", in, got, expected)
+		}
+	}
+}
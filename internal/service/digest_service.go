@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/internal/mailer"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/templates"
+)
+
+const (
+	// A day is a failed-login spike when it has at least spikeFactor times
+	// the period's daily average and at least spikeMinFailures failures.
+	spikeFactor      = 3
+	spikeMinFailures = 10
+
+	maxDigestDeactivated = 20
+)
+
+// DigestConfig configures the admin digest. Period is how far back each
+// digest looks; Recipients replaces the default of every active admin.
+type DigestConfig struct {
+	Period     time.Duration
+	Recipients []string
+}
+
+type DigestCount struct {
+	Day   string `json:"day"`
+	Count int64  `json:"count"`
+}
+
+type DigestUser struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type AdminDigest struct {
+	From         time.Time     `json:"from"`
+	To           time.Time     `json:"to"`
+	Signups      int64         `json:"signups"`
+	SignupsByDay []DigestCount `json:"signups_by_day"`
+	FailedLogins int64         `json:"failed_logins"`
+	Spikes       []DigestCount `json:"failed_login_spikes"`
+	Deactivated  []DigestUser  `json:"deactivated"`
+}
+
+// DigestService summarises recent activity from the admin reports and emails
+// it to admins.
+type DigestService struct {
+	reports  *ReportService
+	users    repository.UserStore
+	mailer   mailer.Mailer
+	renderer *templates.Renderer
+	cfg      DigestConfig
+	logger   *zap.Logger
+}
+
+func NewDigestService(reports *ReportService, users repository.UserStore, m mailer.Mailer, renderer *templates.Renderer, cfg DigestConfig, logger *zap.Logger) *DigestService {
+	return &DigestService{
+		reports:  reports,
+		users:    users,
+		mailer:   m,
+		renderer: renderer,
+		cfg:      cfg,
+		logger:   logger,
+	}
+}
+
+func (s *DigestService) days() int {
+	days := int(s.cfg.Period / (24 * time.Hour))
+	if days < 1 {
+		days = 1
+	}
+	return days
+}
+
+// Build runs the reports behind the digest over the configured period.
+func (s *DigestService) Build(ctx context.Context) (*AdminDigest, error) {
+	days := s.days()
+	params := map[string]string{"days": strconv.Itoa(days)}
+	digest := &AdminDigest{From: daysAgo(days), To: time.Now().UTC()}
+
+	signups, err := s.reports.Run(ctx, "signups-by-day", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run signups report: %w", err)
+	}
+	digest.SignupsByDay, digest.Signups = reportCounts(signups)
+
+	failed, err := s.reports.Run(ctx, "failed-logins-by-day", params)
+	switch {
+	case errors.Is(err, ErrReportNotFound):
+		// No login history configured.
+	case err != nil:
+		return nil, fmt.Errorf("failed to run failed logins report: %w", err)
+	default:
+		var byDay []DigestCount
+		byDay, digest.FailedLogins = reportCounts(failed)
+		digest.Spikes = failedLoginSpikes(byDay, digest.FailedLogins, days)
+	}
+
+	deactivated, err := s.reports.Run(ctx, "deactivated-users", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run deactivated users report: %w", err)
+	}
+	for _, row := range deactivated.Rows {
+		id, _ := row[0].(int64)
+		name, _ := row[1].(string)
+		email, _ := row[2].(string)
+		digest.Deactivated = append(digest.Deactivated, DigestUser{ID: id, Name: name, Email: email})
+	}
+
+	return digest, nil
+}
+
+// Send builds the digest and emails it to the configured recipients or, by
+// default, every active admin.
+func (s *DigestService) Send(ctx context.Context) error {
+	to, err := s.recipients(ctx)
+	if err != nil {
+		return err
+	}
+	if len(to) == 0 {
+		s.logger.Warn("admin digest not sent: no recipients")
+		return nil
+	}
+
+	digest, err := s.Build(ctx)
+	if err != nil {
+		return err
+	}
+
+	shown := digest.Deactivated
+	if len(shown) > maxDigestDeactivated {
+		shown = shown[:maxDigestDeactivated]
+	}
+	email, err := s.renderer.Render("admin_digest", "", map[string]interface{}{
+		"Name":             "admin",
+		"From":             digest.From.Format("Jan 2, 2006"),
+		"To":               digest.To.Format("Jan 2, 2006"),
+		"Signups":          digest.Signups,
+		"SignupsByDay":     digest.SignupsByDay,
+		"FailedLogins":     digest.FailedLogins,
+		"Spikes":           digest.Spikes,
+		"DeactivatedCount": len(digest.Deactivated),
+		"Deactivated":      shown,
+		"MoreDeactivated":  len(digest.Deactivated) - len(shown),
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.mailer.Send(ctx, to, email); err != nil {
+		return fmt.Errorf("failed to send admin digest: %w", err)
+	}
+
+	s.logger.Info("admin digest sent",
+		zap.Int("recipients", len(to)),
+		zap.Int64("signups", digest.Signups),
+		zap.Int64("failed_logins", digest.FailedLogins),
+		zap.Int("deactivated", len(digest.Deactivated)),
+	)
+	return nil
+}
+
+func (s *DigestService) recipients(ctx context.Context) ([]string, error) {
+	if len(s.cfg.Recipients) > 0 {
+		return s.cfg.Recipients, nil
+	}
+	admins, err := s.users.ListActiveAdmins(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list admins: %w", err)
+	}
+	to := make([]string, 0, len(admins))
+	for _, admin := range admins {
+		if admin.Email != "" {
+			to = append(to, admin.Email)
+		}
+	}
+	return to, nil
+}
+
+// reportCounts reads a (day, count) report into counts and their total.
+func reportCounts(result *ReportResult) ([]DigestCount, int64) {
+	counts := make([]DigestCount, 0, len(result.Rows))
+	var total int64
+	for _, row := range result.Rows {
+		day, _ := row[0].(string)
+		count, _ := row[1].(int64)
+		counts = append(counts, DigestCount{Day: day, Count: count})
+		total += count
+	}
+	return counts, total
+}
+
+func failedLoginSpikes(byDay []DigestCount, total int64, days int) []DigestCount {
+	average := float64(total) / float64(days)
+	var spikes []DigestCount
+	for _, day := range byDay {
+		if day.Count >= spikeMinFailures && float64(day.Count) >= spikeFactor*average {
+			spikes = append(spikes, day)
+		}
+	}
+	return spikes
+}
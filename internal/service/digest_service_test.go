@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/templates"
+)
+
+type fakeDigestUserStore struct {
+	repository.UserStore
+	since time.Time
+}
+
+func (f *fakeDigestUserStore) SignupsByDay(ctx context.Context, since time.Time) ([]generated.SignupsByDayRow, error) {
+	f.since = since
+	return []generated.SignupsByDayRow{
+		{Day: "2026-10-10", Signups: 4},
+		{Day: "2026-10-12", Signups: 6},
+	}, nil
+}
+
+func (f *fakeDigestUserStore) ListDeactivatedSince(ctx context.Context, since time.Time) ([]generated.ListDeactivatedUsersRow, error) {
+	return []generated.ListDeactivatedUsersRow{
+		{ID: 7, Name: "Jane Doe", Email: "jane@example.com", UpdatedAt: pgtype.Timestamp{Time: time.Now(), Valid: true}},
+	}, nil
+}
+
+func (f *fakeDigestUserStore) ListActiveAdmins(ctx context.Context) ([]generated.ListActiveAdminsRow, error) {
+	return []generated.ListActiveAdminsRow{
+		{ID: 1, Name: "Admin", Email: "admin@example.com"},
+		{ID: 2, Name: "No Email"},
+	}, nil
+}
+
+type fakeDigestLoginHistory struct {
+	repository.LoginHistoryStore
+}
+
+func (f *fakeDigestLoginHistory) FailedByDay(ctx context.Context, since time.Time) ([]generated.FailedLoginsByDayRow, error) {
+	return []generated.FailedLoginsByDayRow{
+		{Day: "2026-10-10", Failures: 3},
+		{Day: "2026-10-11", Failures: 40},
+		{Day: "2026-10-12", Failures: 5},
+	}, nil
+}
+
+type fakeDigestMailer struct {
+	to    []string
+	email *templates.Email
+}
+
+func (m *fakeDigestMailer) Send(ctx context.Context, to []string, email *templates.Email) error {
+	m.to, m.email = to, email
+	return nil
+}
+
+func (m *fakeDigestMailer) Driver() string { return "fake" }
+
+func newTestDigestService(t *testing.T, cfg DigestConfig) (*DigestService, *fakeDigestUserStore, *fakeDigestMailer) {
+	t.Helper()
+	users := &fakeDigestUserStore{}
+	reports := NewReportService(users)
+	reports.SetLoginHistory(&fakeDigestLoginHistory{})
+	renderer, err := templates.NewRenderer(templates.Branding{ProductName: "Test", SupportEmail: "support@example.com"}, "en")
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+	m := &fakeDigestMailer{}
+	return NewDigestService(reports, users, m, renderer, cfg, zap.NewNop()), users, m
+}
+
+func TestDigestService_Build(t *testing.T) {
+	svc, users, _ := newTestDigestService(t, DigestConfig{Period: 7 * 24 * time.Hour})
+
+	digest, err := svc.Build(context.Background())
+	if err != nil {
+		t.Fatalf("Build: %v", err)
+	}
+	if digest.Signups != 10 || len(digest.SignupsByDay) != 2 {
+		t.Errorf("signups = %d over %v; want 10 over 2 days", digest.Signups, digest.SignupsByDay)
+	}
+	if digest.FailedLogins != 48 {
+		t.Errorf("failed logins = %d; want 48", digest.FailedLogins)
+	}
+	if len(digest.Spikes) != 1 || digest.Spikes[0].Day != "2026-10-11" {
+		t.Errorf("spikes = %v; want only 2026-10-11", digest.Spikes)
+	}
+	if len(digest.Deactivated) != 1 || digest.Deactivated[0].ID != 7 {
+		t.Errorf("deactivated = %v; want user 7", digest.Deactivated)
+	}
+	if want := daysAgo(7); !users.since.Equal(want) {
+		t.Errorf("since = %v; want %v", users.since, want)
+	}
+}
+
+func TestDigestService_Send(t *testing.T) {
+	svc, _, m := newTestDigestService(t, DigestConfig{Period: 7 * 24 * time.Hour})
+
+	if err := svc.Send(context.Background()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(m.to) != 1 || m.to[0] != "admin@example.com" {
+		t.Errorf("to = %v; want the admins with an email", m.to)
+	}
+	if m.email == nil || !strings.Contains(m.email.HTML, "jane@example.com") || !strings.Contains(m.email.HTML, "2026-10-11 (40 failures)") {
+		t.Errorf("digest email is missing report data: %+v", m.email)
+	}
+
+	svc, _, m = newTestDigestService(t, DigestConfig{Period: 24 * time.Hour, Recipients: []string{"ops@example.com"}})
+	if err := svc.Send(context.Background()); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if len(m.to) != 1 || m.to[0] != "ops@example.com" {
+		t.Errorf("to = %v; want the configured recipients", m.to)
+	}
+}
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"BACKEND/internal/dates"
+)
+
+func FuzzParseDob(f *testing.F) {
+	for _, seed := range []string{
+		"1990-05-10",
+		"2000-02-29",
+		"2023-02-29",
+		"0000-01-01",
+		"9999-12-31",
+		"1990-5-10",
+		"1990-05-10T00:00:00Z",
+		" 1990-05-10",
+		"",
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, s string) {
+		dob, err := ParseDob(s)
+		if err != nil {
+			return
+		}
+		if got := dob.Format(DobLayout); got != s {
+			t.Fatalf("ParseDob(%q) = %s; want the same date back", s, got)
+		}
+		if dob.Location() != time.UTC || dob.Hour() != 0 || dob.Minute() != 0 {
+			t.Fatalf("ParseDob(%q) = %v; want midnight UTC", s, dob)
+		}
+		_ = dates.Age(dob, time.Now())
+	})
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type ExportStatus string
+
+const (
+	ExportPending   ExportStatus = "pending"
+	ExportCompleted ExportStatus = "completed"
+	ExportFailed    ExportStatus = "failed"
+)
+
+var (
+	ErrExportNotFound    = errors.New("export not found")
+	ErrExportNotReady    = errors.New("export is not ready")
+	ErrExportURLInvalid  = errors.New("download url is invalid")
+	ErrExportURLExpired  = errors.New("download url has expired")
+	ErrExportURLUsed     = errors.New("download url has already been used")
+	ErrExportFileMissing = errors.New("export file is no longer available")
+)
+
+type ExportConfig struct {
+	Dir       string
+	Secret    string
+	URLTTL    time.Duration
+	Retention time.Duration
+}
+
+type Export struct {
+	ID           string            `json:"id"`
+	Report       string            `json:"report"`
+	Params       map[string]string `json:"params"`
+	Status       ExportStatus      `json:"status"`
+	Error        string            `json:"error,omitempty"`
+	CreatedBy    int64             `json:"created_by"`
+	CreatedAt    time.Time         `json:"created_at"`
+	CompletedAt  *time.Time        `json:"completed_at,omitempty"`
+	Suppressed   int               `json:"suppressed_rows,omitempty"`
+	Downloads    int               `json:"downloads"`
+	LastDownload *time.Time        `json:"last_downloaded_at,omitempty"`
+	path         string
+}
+
+// ExportService runs reports in the background and writes them to disk as
+// CSV. Finished exports are fetched through signed, single-use URLs so the
+// download can happen outside the authenticated API, e.g. from a browser.
+type ExportService struct {
+	reports *ReportService
+	cfg     ExportConfig
+	logger  *zap.Logger
+
+	mu      sync.Mutex
+	exports map[string]*Export
+	used    map[string]time.Time
+}
+
+func NewExportService(reports *ReportService, cfg ExportConfig, logger *zap.Logger) *ExportService {
+	return &ExportService{
+		reports: reports,
+		cfg:     cfg,
+		logger:  logger,
+		exports: make(map[string]*Export),
+		used:    make(map[string]time.Time),
+	}
+}
+
+// Start queues an export and returns immediately.
+func (s *ExportService) Start(ctx context.Context, report string, params map[string]string, userID int64) (Export, error) {
+	if err := s.reports.Validate(report, params); err != nil {
+		return Export{}, err
+	}
+	if err := os.MkdirAll(s.cfg.Dir, 0o700); err != nil {
+		return Export{}, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		return Export{}, err
+	}
+
+	// The report runs after the request is done, so it must not hold on to
+	// request-scoped strings.
+	owned := make(map[string]string, len(params))
+	for k, v := range params {
+		owned[strings.Clone(k)] = strings.Clone(v)
+	}
+
+	export := &Export{
+		ID:        id,
+		Report:    strings.Clone(report),
+		Params:    owned,
+		Status:    ExportPending,
+		CreatedBy: userID,
+		CreatedAt: time.Now(),
+		path:      filepath.Join(s.cfg.Dir, id+".csv"),
+	}
+
+	s.mu.Lock()
+	s.pruneLocked()
+	s.exports[id] = export
+	s.mu.Unlock()
+
+	go s.run(context.WithoutCancel(ctx), export)
+
+	return s.Get(id)
+}
+
+func (s *ExportService) run(ctx context.Context, export *Export) {
+	suppressed, err := s.write(ctx, export)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	export.CompletedAt = &now
+	if err != nil {
+		export.Status = ExportFailed
+		export.Error = "export failed"
+		s.logger.Error("export failed", zap.String("export_id", export.ID), zap.Error(err))
+		return
+	}
+	export.Status = ExportCompleted
+	export.Suppressed = suppressed
+	s.logger.Info("export completed", zap.String("export_id", export.ID), zap.String("report", export.Report))
+}
+
+// write runs the export's report into its file and returns how many rows
+// the report suppressed.
+func (s *ExportService) write(ctx context.Context, export *Export) (int, error) {
+	result, err := s.reports.Run(ctx, export.Report, export.Params)
+	if err != nil {
+		return 0, err
+	}
+
+	f, err := os.OpenFile(export.path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return 0, err
+	}
+	if err := result.WriteCSV(f); err != nil {
+		f.Close()
+		return 0, err
+	}
+	return result.Suppressed, f.Close()
+}
+
+func (s *ExportService) Get(id string) (Export, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	export, ok := s.exports[id]
+	if !ok {
+		return Export{}, ErrExportNotFound
+	}
+	return *export, nil
+}
+
+// SignedURL returns a download URL for a completed export, relative to
+// basePath, and when it expires. Each call mints a URL that works once.
+func (s *ExportService) SignedURL(id, basePath string) (string, time.Time, error) {
+	export, err := s.Get(id)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if export.Status != ExportCompleted {
+		return "", time.Time{}, ErrExportNotReady
+	}
+
+	nonce, err := randomHex(8)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := time.Now().Add(s.cfg.URLTTL)
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("nonce", nonce)
+	q.Set("signature", s.sign(id, expires, nonce))
+	return basePath + "/exports/" + id + "/download?" + q.Encode(), expiresAt, nil
+}
+
+// Open verifies a signed URL, marks it used and returns the export and the
+// path of its file.
+func (s *ExportService) Open(id, expires, nonce, signature string) (Export, string, error) {
+	if !hmac.Equal([]byte(signature), []byte(s.sign(id, expires, nonce))) {
+		return Export{}, "", ErrExportURLInvalid
+	}
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return Export{}, "", ErrExportURLInvalid
+	}
+	expiresAt := time.Unix(expiresUnix, 0)
+	if time.Now().After(expiresAt) {
+		return Export{}, "", ErrExportURLExpired
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, used := s.used[signature]; used {
+		return Export{}, "", ErrExportURLUsed
+	}
+	export, ok := s.exports[id]
+	if !ok {
+		return Export{}, "", ErrExportFileMissing
+	}
+
+	s.used[signature] = expiresAt
+	now := time.Now()
+	export.Downloads++
+	export.LastDownload = &now
+	return *export, export.path, nil
+}
+
+func (s *ExportService) sign(id, expires, nonce string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.Secret))
+	mac.Write([]byte(id + "." + expires + "." + nonce))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// pruneLocked forgets used signatures once they have expired anyway, and
+// deletes exports older than the retention period.
+func (s *ExportService) pruneLocked() {
+	now := time.Now()
+	for sig, expiresAt := range s.used {
+		if now.After(expiresAt) {
+			delete(s.used, sig)
+		}
+	}
+	s.pruneBeforeLocked(now.Add(-s.cfg.Retention))
+}
+
+func (s *ExportService) pruneBeforeLocked(before time.Time) int64 {
+	var n int64
+	for id, export := range s.exports {
+		if export.Status != ExportPending && export.CreatedAt.Before(before) {
+			os.Remove(export.path)
+			delete(s.exports, id)
+			n++
+		}
+	}
+	return n
+}
+
+// RetentionStats and PruneBefore make exports a RetentionTarget.
+func (s *ExportService) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var oldest *time.Time
+	for _, export := range s.exports {
+		if oldest == nil || export.CreatedAt.Before(*oldest) {
+			createdAt := export.CreatedAt
+			oldest = &createdAt
+		}
+	}
+	return int64(len(s.exports)), oldest, nil
+}
+
+func (s *ExportService) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pruneBeforeLocked(before), nil
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+func newTestExportService(ttl time.Duration) *ExportService {
+	s := NewExportService(nil, ExportConfig{Secret: "test-secret", URLTTL: ttl, Retention: time.Hour}, zap.NewNop())
+	s.exports["abc"] = &Export{ID: "abc", Report: "signups-by-month", Status: ExportCompleted, CreatedAt: time.Now(), path: "/tmp/abc.csv"}
+	s.exports["pending"] = &Export{ID: "pending", Status: ExportPending, CreatedAt: time.Now()}
+	return s
+}
+
+func signedQuery(t *testing.T, s *ExportService, id string) url.Values {
+	t.Helper()
+	raw, _, err := s.SignedURL(id, "https://api.example.com/identity")
+	if err != nil {
+		t.Fatalf("SignedURL: %v", err)
+	}
+	if !strings.HasPrefix(raw, "https://api.example.com/identity/exports/"+id+"/download?") {
+		t.Fatalf("unexpected url %q", raw)
+	}
+	u, err := url.Parse(raw)
+	if err != nil {
+		t.Fatalf("parse url: %v", err)
+	}
+	return u.Query()
+}
+
+func TestExportSignedURLSingleUse(t *testing.T) {
+	s := newTestExportService(time.Minute)
+	q := signedQuery(t, s, "abc")
+
+	export, path, err := s.Open("abc", q.Get("expires"), q.Get("nonce"), q.Get("signature"))
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	if path != "/tmp/abc.csv" || export.Downloads != 1 || export.LastDownload == nil {
+		t.Errorf("unexpected export after download: %+v, %q", export, path)
+	}
+
+	if _, _, err := s.Open("abc", q.Get("expires"), q.Get("nonce"), q.Get("signature")); !errors.Is(err, ErrExportURLUsed) {
+		t.Errorf("expected second use to fail with ErrExportURLUsed, got %v", err)
+	}
+
+	q = signedQuery(t, s, "abc")
+	if _, _, err := s.Open("abc", q.Get("expires"), q.Get("nonce"), q.Get("signature")); err != nil {
+		t.Errorf("expected a freshly signed url to work, got %v", err)
+	}
+}
+
+func TestExportSignedURLRejectsTampering(t *testing.T) {
+	s := newTestExportService(time.Minute)
+	q := signedQuery(t, s, "abc")
+
+	tests := []struct {
+		name                          string
+		id, expires, nonce, signature string
+	}{
+		{"other export", "pending", q.Get("expires"), q.Get("nonce"), q.Get("signature")},
+		{"extended expiry", "abc", "9999999999", q.Get("nonce"), q.Get("signature")},
+		{"bad signature", "abc", q.Get("expires"), q.Get("nonce"), strings.Repeat("0", 64)},
+		{"missing signature", "abc", q.Get("expires"), q.Get("nonce"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := s.Open(tt.id, tt.expires, tt.nonce, tt.signature); !errors.Is(err, ErrExportURLInvalid) {
+				t.Errorf("expected ErrExportURLInvalid, got %v", err)
+			}
+		})
+	}
+}
+
+func TestExportSignedURLExpired(t *testing.T) {
+	s := newTestExportService(-time.Minute)
+	q := signedQuery(t, s, "abc")
+
+	if _, _, err := s.Open("abc", q.Get("expires"), q.Get("nonce"), q.Get("signature")); !errors.Is(err, ErrExportURLExpired) {
+		t.Errorf("expected ErrExportURLExpired, got %v", err)
+	}
+}
+
+func TestExportSignedURLNotReady(t *testing.T) {
+	s := newTestExportService(time.Minute)
+	if _, _, err := s.SignedURL("pending", ""); !errors.Is(err, ErrExportNotReady) {
+		t.Errorf("expected ErrExportNotReady, got %v", err)
+	}
+}
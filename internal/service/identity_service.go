@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/sso"
+)
+
+const identityStateTTL = 10 * time.Minute
+
+var (
+	ErrIdentityProviderUnknown = errors.New("identity provider is not configured")
+	ErrIdentityInvalidState    = errors.New("identity state is invalid or expired")
+	ErrIdentityMissingSubject  = errors.New("identity provider did not return a subject")
+	ErrIdentityLinkedElsewhere = errors.New("identity is linked to another account")
+	ErrIdentityProviderLinked  = errors.New("an identity from this provider is already linked")
+	ErrIdentityNotLinked       = errors.New("no identity from this provider is linked")
+	ErrIdentityUnknown         = errors.New("no account is linked to this identity")
+	ErrIdentitySSORequired     = errors.New("account must sign in with sso")
+	ErrLastCredential          = errors.New("the account has no other way to sign in")
+)
+
+type identityPending struct {
+	provider     string
+	userID       int64
+	nonce        string
+	codeVerifier string
+	expiresAt    time.Time
+}
+
+// IdentityOutcome is the result of a provider callback: the identity that
+// was linked, or, for a login, the user and their token.
+type IdentityOutcome struct {
+	Identity generated.UserIdentity
+	User     generated.User
+	Token    string
+}
+
+// IdentityService links external OIDC identities to accounts and signs users
+// in with them. A user has at most one identity per provider, and an
+// identity belongs to one user. Identities are never used to create
+// accounts.
+type IdentityService struct {
+	providers  map[string]*sso.OIDCProvider
+	identities repository.IdentityStore
+	passkeys   repository.WebAuthnCredentialStore
+	repo       repository.UserStore
+	auth       *AuthService
+	sso        *SSOService
+
+	mu      sync.Mutex
+	pending map[string]identityPending
+
+	// Unlinks run one at a time, so two at once can't each count the
+	// other's identity as the one that remains.
+	unlinkMu sync.Mutex
+}
+
+func NewIdentityService(providers map[string]*sso.OIDCProvider, identities repository.IdentityStore, passkeys repository.WebAuthnCredentialStore, repo repository.UserStore, auth *AuthService) *IdentityService {
+	return &IdentityService{
+		providers:  providers,
+		identities: identities,
+		passkeys:   passkeys,
+		repo:       repo,
+		auth:       auth,
+		pending:    make(map[string]identityPending),
+	}
+}
+
+// SetSSO keeps domains that must use SSO from linking identities or signing
+// in with them, and lets accounts provisioned by SSO count it as a way to
+// sign in.
+func (s *IdentityService) SetSSO(svc *SSOService) {
+	s.sso = svc
+}
+
+// Providers returns the names of the configured providers, sorted.
+func (s *IdentityService) Providers() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *IdentityService) List(ctx context.Context, userID int64) ([]generated.UserIdentity, error) {
+	return s.identities.ListByUser(ctx, userID)
+}
+
+// BeginLink returns the provider's authorization URL for linking an
+// identity to the user.
+func (s *IdentityService) BeginLink(ctx context.Context, userID int64, provider string) (string, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	if err := s.checkUser(user.Email, user.AccountType, true); err != nil {
+		return "", err
+	}
+	return s.begin(ctx, provider, userID)
+}
+
+// BeginLogin returns the provider's authorization URL for signing in with a
+// linked identity.
+func (s *IdentityService) BeginLogin(ctx context.Context, provider string) (string, error) {
+	return s.begin(ctx, provider, 0)
+}
+
+func (s *IdentityService) begin(ctx context.Context, provider string, userID int64) (string, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return "", ErrIdentityProviderUnknown
+	}
+
+	state, err := sso.RandomString()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := sso.RandomString()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := sso.RandomString()
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := p.AuthCodeURL(ctx, state, nonce, verifier, "")
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	s.pruneLocked()
+	s.pending[state] = identityPending{
+		provider:     provider,
+		userID:       userID,
+		nonce:        nonce,
+		codeVerifier: verifier,
+		expiresAt:    time.Now().Add(identityStateTTL),
+	}
+	s.mu.Unlock()
+
+	return authURL, nil
+}
+
+// Complete handles the provider's callback, finishing whichever of a link
+// or a login the state was issued for.
+func (s *IdentityService) Complete(ctx context.Context, provider, state, code string) (IdentityOutcome, error) {
+	p, ok := s.providers[provider]
+	if !ok {
+		return IdentityOutcome{}, ErrIdentityProviderUnknown
+	}
+
+	s.mu.Lock()
+	pending, ok := s.pending[state]
+	delete(s.pending, state)
+	s.mu.Unlock()
+
+	if !ok || pending.provider != provider || time.Now().After(pending.expiresAt) {
+		return IdentityOutcome{}, ErrIdentityInvalidState
+	}
+
+	claims, err := p.Exchange(ctx, code, pending.codeVerifier, pending.nonce)
+	if err != nil {
+		return IdentityOutcome{}, err
+	}
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return IdentityOutcome{}, ErrIdentityMissingSubject
+	}
+	email, _ := claims["email"].(string)
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	if pending.userID != 0 {
+		identity, err := s.link(ctx, pending.userID, provider, subject, email)
+		return IdentityOutcome{Identity: identity}, err
+	}
+	return s.login(ctx, provider, subject)
+}
+
+func (s *IdentityService) link(ctx context.Context, userID int64, provider, subject, email string) (generated.UserIdentity, error) {
+	identity, err := s.identities.Create(ctx, userID, provider, subject, email)
+	if err == nil {
+		return identity, nil
+	}
+	if !errors.Is(err, repository.ErrUniqueViolation) {
+		return generated.UserIdentity{}, fmt.Errorf("failed to link identity: %w", err)
+	}
+
+	existing, getErr := s.identities.GetBySubject(ctx, provider, subject)
+	switch {
+	case getErr == nil && existing.UserID == userID:
+		// Linking the same identity again changes nothing.
+		return existing, nil
+	case getErr == nil:
+		return generated.UserIdentity{}, ErrIdentityLinkedElsewhere
+	case errors.Is(getErr, pgx.ErrNoRows):
+		return generated.UserIdentity{}, ErrIdentityProviderLinked
+	}
+	return generated.UserIdentity{}, fmt.Errorf("failed to look up identity: %w", getErr)
+}
+
+func (s *IdentityService) login(ctx context.Context, provider, subject string) (IdentityOutcome, error) {
+	identity, err := s.identities.GetBySubject(ctx, provider, subject)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return IdentityOutcome{}, ErrIdentityUnknown
+		}
+		return IdentityOutcome{}, fmt.Errorf("failed to look up identity: %w", err)
+	}
+
+	user, err := s.repo.GetByID(ctx, identity.UserID)
+	if err != nil {
+		return IdentityOutcome{}, fmt.Errorf("failed to load identity owner: %w", err)
+	}
+	if err := s.checkUser(user.Email, user.AccountType, user.Active); err != nil {
+		return IdentityOutcome{}, err
+	}
+
+	token, err := s.auth.GenerateJWT(ctx, user.ID, user.Role)
+	if err != nil {
+		return IdentityOutcome{}, fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	loggedIn := generated.User{
+		ID:          user.ID,
+		Name:        user.Name,
+		Dob:         user.Dob,
+		Email:       user.Email,
+		Role:        user.Role,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+		Active:      user.Active,
+		AccountType: user.AccountType,
+		PublicID:    user.PublicID,
+	}
+	s.auth.afterLogin(ctx, loggedIn)
+	return IdentityOutcome{Identity: identity, User: loggedIn, Token: token}, nil
+}
+
+// Unlink removes the user's identity from the provider, unless it is the
+// only way left to sign in to the account.
+func (s *IdentityService) Unlink(ctx context.Context, userID int64, provider string) error {
+	s.unlinkMu.Lock()
+	defer s.unlinkMu.Unlock()
+
+	identities, err := s.identities.ListByUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list identities: %w", err)
+	}
+	linked := false
+	for _, identity := range identities {
+		if identity.Provider == provider {
+			linked = true
+		}
+	}
+	if !linked {
+		return ErrIdentityNotLinked
+	}
+
+	if len(identities) == 1 {
+		ok, err := s.canSignInWithoutIdentities(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrLastCredential
+		}
+	}
+
+	deleted, err := s.identities.Delete(ctx, userID, provider)
+	if err != nil {
+		return fmt.Errorf("failed to unlink identity: %w", err)
+	}
+	if !deleted {
+		return ErrIdentityNotLinked
+	}
+	return nil
+}
+
+// canSignInWithoutIdentities reports whether the user has a passkey, a
+// password or enterprise SSO to fall back on. Accounts SSO provisioned were
+// given a random password nobody knows, so only SSO itself counts for them.
+func (s *IdentityService) canSignInWithoutIdentities(ctx context.Context, userID int64) (bool, error) {
+	passkeys, err := s.passkeys.ListByUser(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+	if len(passkeys) > 0 {
+		return true, nil
+	}
+
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	if strings.HasPrefix(user.SignupSource, SignupSourceSSO) {
+		return s.sso.Enabled(), nil
+	}
+	return true, nil
+}
+
+func (s *IdentityService) checkUser(email, accountType string, active bool) error {
+	if accountType == AccountTypeService {
+		return ErrInteractiveLoginDenied
+	}
+	if !active {
+		return ErrAccountDisabled
+	}
+	if s.sso != nil && s.sso.RequiresSSO(email) {
+		return ErrIdentitySSORequired
+	}
+	return nil
+}
+
+func (s *IdentityService) pruneLocked() {
+	now := time.Now()
+	for state, p := range s.pending {
+		if now.After(p.expiresAt) {
+			delete(s.pending, state)
+		}
+	}
+}
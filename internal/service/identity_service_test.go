@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/sso"
+)
+
+type fakeIdentityStore struct {
+	identities []generated.UserIdentity
+}
+
+func (f *fakeIdentityStore) Create(ctx context.Context, userID int64, provider, subject, email string) (generated.UserIdentity, error) {
+	for _, identity := range f.identities {
+		if identity.Provider == provider && (identity.Subject == subject || identity.UserID == userID) {
+			return generated.UserIdentity{}, &repository.ConstraintError{Kind: repository.ErrUniqueViolation, Constraint: "user_identities", Err: errors.New("duplicate")}
+		}
+	}
+	identity := generated.UserIdentity{
+		ID:       int64(len(f.identities) + 1),
+		UserID:   userID,
+		Provider: provider,
+		Subject:  subject,
+		Email:    email,
+	}
+	f.identities = append(f.identities, identity)
+	return identity, nil
+}
+
+func (f *fakeIdentityStore) GetBySubject(ctx context.Context, provider, subject string) (generated.UserIdentity, error) {
+	for _, identity := range f.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return generated.UserIdentity{}, pgx.ErrNoRows
+}
+
+func (f *fakeIdentityStore) ListByUser(ctx context.Context, userID int64) ([]generated.UserIdentity, error) {
+	var identities []generated.UserIdentity
+	for _, identity := range f.identities {
+		if identity.UserID == userID {
+			identities = append(identities, identity)
+		}
+	}
+	return identities, nil
+}
+
+func (f *fakeIdentityStore) Delete(ctx context.Context, userID int64, provider string) (bool, error) {
+	for i, identity := range f.identities {
+		if identity.UserID == userID && identity.Provider == provider {
+			f.identities = append(f.identities[:i], f.identities[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func newTestIdentityService(user generated.GetUserByIDRow, identities ...generated.UserIdentity) (*IdentityService, *fakeIdentityStore, *fakeCredentialStore) {
+	store := &fakeIdentityStore{identities: identities}
+	passkeys := &fakeCredentialStore{}
+	providers := map[string]*sso.OIDCProvider{
+		"google":    sso.NewOIDCProvider(sso.OIDCConfig{Issuer: "https://accounts.google.com", ClientID: "client"}),
+		"microsoft": sso.NewOIDCProvider(sso.OIDCConfig{Issuer: "https://login.microsoftonline.com/common/v2.0", ClientID: "client"}),
+	}
+	return NewIdentityService(providers, store, passkeys, &fakeWebAuthnUserStore{user: user}, nil), store, passkeys
+}
+
+func TestIdentityLink(t *testing.T) {
+	ctx := context.Background()
+	svc, store, _ := newTestIdentityService(generated.GetUserByIDRow{ID: 1},
+		generated.UserIdentity{ID: 1, UserID: 2, Provider: "google", Subject: "theirs"},
+	)
+
+	if _, err := svc.link(ctx, 1, "google", "theirs", ""); !errors.Is(err, ErrIdentityLinkedElsewhere) {
+		t.Fatalf("expected ErrIdentityLinkedElsewhere, got %v", err)
+	}
+
+	linked, err := svc.link(ctx, 1, "google", "mine", "jane@gmail.com")
+	if err != nil {
+		t.Fatalf("link: %v", err)
+	}
+	if linked.UserID != 1 || linked.Email != "jane@gmail.com" {
+		t.Errorf("unexpected identity %+v", linked)
+	}
+
+	again, err := svc.link(ctx, 1, "google", "mine", "jane@gmail.com")
+	if err != nil || again.ID != linked.ID {
+		t.Errorf("relinking the same identity: got %+v, %v", again, err)
+	}
+
+	if _, err := svc.link(ctx, 1, "google", "another", ""); !errors.Is(err, ErrIdentityProviderLinked) {
+		t.Errorf("expected ErrIdentityProviderLinked, got %v", err)
+	}
+	if len(store.identities) != 2 {
+		t.Errorf("expected 2 identities, got %d", len(store.identities))
+	}
+}
+
+func TestIdentityUnlink(t *testing.T) {
+	ctx := context.Background()
+	google := generated.UserIdentity{ID: 1, UserID: 1, Provider: "google", Subject: "g"}
+	microsoft := generated.UserIdentity{ID: 2, UserID: 1, Provider: "microsoft", Subject: "m"}
+	ssoUser := generated.GetUserByIDRow{ID: 1, SignupSource: "sso:idp.example.com"}
+
+	t.Run("not linked", func(t *testing.T) {
+		svc, _, _ := newTestIdentityService(generated.GetUserByIDRow{ID: 1, SignupSource: SignupSourceWeb})
+		if err := svc.Unlink(ctx, 1, "google"); !errors.Is(err, ErrIdentityNotLinked) {
+			t.Errorf("expected ErrIdentityNotLinked, got %v", err)
+		}
+	})
+
+	t.Run("password remains", func(t *testing.T) {
+		svc, store, _ := newTestIdentityService(generated.GetUserByIDRow{ID: 1, SignupSource: SignupSourceWeb}, google)
+		if err := svc.Unlink(ctx, 1, "google"); err != nil {
+			t.Fatalf("unlink: %v", err)
+		}
+		if len(store.identities) != 0 {
+			t.Errorf("identity was not removed")
+		}
+	})
+
+	t.Run("last credential", func(t *testing.T) {
+		svc, store, _ := newTestIdentityService(ssoUser, google)
+		if err := svc.Unlink(ctx, 1, "google"); !errors.Is(err, ErrLastCredential) {
+			t.Fatalf("expected ErrLastCredential, got %v", err)
+		}
+		if len(store.identities) != 1 {
+			t.Errorf("identity was removed")
+		}
+	})
+
+	t.Run("another identity remains", func(t *testing.T) {
+		svc, store, _ := newTestIdentityService(ssoUser, google, microsoft)
+		if err := svc.Unlink(ctx, 1, "google"); err != nil {
+			t.Fatalf("unlink: %v", err)
+		}
+		if err := svc.Unlink(ctx, 1, "microsoft"); !errors.Is(err, ErrLastCredential) {
+			t.Errorf("expected ErrLastCredential for the last identity, got %v", err)
+		}
+		if len(store.identities) != 1 || store.identities[0].Provider != "microsoft" {
+			t.Errorf("unexpected identities %+v", store.identities)
+		}
+	})
+
+	t.Run("passkey remains", func(t *testing.T) {
+		svc, _, passkeys := newTestIdentityService(ssoUser, google)
+		passkeys.creds = []generated.WebauthnCredential{{ID: 1, UserID: 1}}
+		if err := svc.Unlink(ctx, 1, "google"); err != nil {
+			t.Errorf("unlink: %v", err)
+		}
+	})
+}
+
+func TestIdentityCompleteRejectsBadState(t *testing.T) {
+	ctx := context.Background()
+	svc, _, _ := newTestIdentityService(generated.GetUserByIDRow{ID: 1})
+	svc.pending["state"] = identityPending{provider: "google", userID: 1, expiresAt: time.Now().Add(time.Minute)}
+	svc.pending["expired"] = identityPending{provider: "google", userID: 1, expiresAt: time.Now().Add(-time.Minute)}
+
+	if _, err := svc.Complete(ctx, "microsoft", "state", "code"); !errors.Is(err, ErrIdentityInvalidState) {
+		t.Errorf("state for another provider: expected ErrIdentityInvalidState, got %v", err)
+	}
+	if _, ok := svc.pending["state"]; ok {
+		t.Errorf("state was not consumed")
+	}
+	if _, err := svc.Complete(ctx, "google", "expired", "code"); !errors.Is(err, ErrIdentityInvalidState) {
+		t.Errorf("expired state: expected ErrIdentityInvalidState, got %v", err)
+	}
+	if _, err := svc.Complete(ctx, "github", "state", "code"); !errors.Is(err, ErrIdentityProviderUnknown) {
+		t.Errorf("expected ErrIdentityProviderUnknown, got %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"BACKEND/internal/clock"
+	"BACKEND/internal/repository"
+)
+
+// LockoutConfig sets how many failed logins within Window lock out an
+// email or an IP, and for how long. A zero threshold turns that kind of
+// lockout off.
+type LockoutConfig struct {
+	Threshold   int
+	IPThreshold int
+	Window      time.Duration
+	Duration    time.Duration
+}
+
+// AccountLockedError is returned for a login to a locked out email or from
+// a locked out IP. It matches ErrAccountLocked.
+type AccountLockedError struct {
+	Until time.Time
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("%s until %s", ErrAccountLocked, e.Until.Format(time.RFC3339))
+}
+
+func (e *AccountLockedError) Unwrap() error {
+	return ErrAccountLocked
+}
+
+// LockedLogin is an email or IP that is locked out.
+type LockedLogin struct {
+	Key         string    `json:"key"`
+	Failures    int       `json:"failures"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+// LoginLockout locks out emails and IPs after repeated failed logins. The
+// counts live in the database, so every instance enforces the same locks.
+// A nil LoginLockout never locks anything out.
+type LoginLockout struct {
+	store repository.LoginLockoutStore
+	cfg   LockoutConfig
+	clock clock.Clock
+}
+
+func NewLoginLockout(store repository.LoginLockoutStore, cfg LockoutConfig) *LoginLockout {
+	return &LoginLockout{store: store, cfg: cfg, clock: clock.System}
+}
+
+func (l *LoginLockout) SetClock(c clock.Clock) {
+	l.clock = c
+}
+
+type clientIPKey struct{}
+
+// WithClientIP attaches the IP a login comes from, so it can be counted
+// towards the IP's lockout.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey{}, ip)
+}
+
+func clientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey{}).(string)
+	return ip
+}
+
+func emailLockKey(email string) string {
+	return "email:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+// keys returns the lock keys a login counts towards, with the threshold of
+// each.
+func (l *LoginLockout) keys(ctx context.Context, email string) map[string]int {
+	keys := make(map[string]int, 2)
+	if l.cfg.Threshold > 0 {
+		keys[emailLockKey(email)] = l.cfg.Threshold
+	}
+	if ip := clientIP(ctx); ip != "" && l.cfg.IPThreshold > 0 {
+		keys["ip:"+ip] = l.cfg.IPThreshold
+	}
+	return keys
+}
+
+// Check returns an *AccountLockedError if the email, or the IP in ctx, is
+// locked out.
+func (l *LoginLockout) Check(ctx context.Context, email string) error {
+	if l == nil {
+		return nil
+	}
+	now := l.clock.Now()
+	for key := range l.keys(ctx, email) {
+		until, locked, err := l.store.LockedUntil(ctx, key, now)
+		if err != nil {
+			return fmt.Errorf("failed to check login lockout: %w", err)
+		}
+		if locked {
+			return &AccountLockedError{Until: until}
+		}
+	}
+	return nil
+}
+
+// Failure counts a failed login against the email and IP, locking out
+// whichever reached its threshold.
+func (l *LoginLockout) Failure(ctx context.Context, email string) error {
+	if l == nil {
+		return nil
+	}
+	now := l.clock.Now()
+	for key, threshold := range l.keys(ctx, email) {
+		failures, err := l.store.RecordFailure(ctx, key, now, l.cfg.Window)
+		if err != nil {
+			return fmt.Errorf("failed to record login failure: %w", err)
+		}
+		if failures >= threshold {
+			if err := l.store.Lock(ctx, key, now.Add(l.cfg.Duration)); err != nil {
+				return fmt.Errorf("failed to lock login: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Success resets the email's failure count, so only consecutive failures
+// lock it. The IP's count is kept: one good password doesn't vouch for
+// everything else tried from there.
+func (l *LoginLockout) Success(ctx context.Context, email string) error {
+	if l == nil || l.cfg.Threshold <= 0 {
+		return nil
+	}
+	_, err := l.store.Clear(ctx, emailLockKey(email))
+	return err
+}
+
+// Unlock lifts the email's lockout, reporting whether it had failures or a
+// lock to clear.
+func (l *LoginLockout) Unlock(ctx context.Context, email string) (bool, error) {
+	if l == nil {
+		return false, nil
+	}
+	return l.store.Clear(ctx, emailLockKey(email))
+}
+
+// Locked lists the emails and IPs locked out now.
+func (l *LoginLockout) Locked(ctx context.Context) ([]LockedLogin, error) {
+	if l == nil {
+		return []LockedLogin{}, nil
+	}
+	rows, err := l.store.ListLocked(ctx, l.clock.Now())
+	if err != nil {
+		return nil, err
+	}
+	locked := make([]LockedLogin, 0, len(rows))
+	for _, row := range rows {
+		locked = append(locked, LockedLogin{
+			Key:         row.LockKey,
+			Failures:    int(row.Failures),
+			LockedUntil: row.LockedUntil.Time,
+		})
+	}
+	return locked, nil
+}
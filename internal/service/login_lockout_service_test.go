@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"golang.org/x/crypto/bcrypt"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/clock"
+	"BACKEND/internal/repository"
+)
+
+type fakeLoginLockoutStore struct {
+	rows map[string]*generated.LoginLockout
+}
+
+func newFakeLoginLockoutStore() *fakeLoginLockoutStore {
+	return &fakeLoginLockoutStore{rows: make(map[string]*generated.LoginLockout)}
+}
+
+func (f *fakeLoginLockoutStore) RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (int, error) {
+	row, ok := f.rows[key]
+	if !ok || !row.WindowStart.Time.After(now.Add(-window)) {
+		row = &generated.LoginLockout{LockKey: key, WindowStart: pgtype.Timestamp{Time: now, Valid: true}}
+		f.rows[key] = row
+	}
+	row.Failures++
+	return int(row.Failures), nil
+}
+
+func (f *fakeLoginLockoutStore) Lock(ctx context.Context, key string, until time.Time) error {
+	f.rows[key].LockedUntil = pgtype.Timestamp{Time: until, Valid: true}
+	return nil
+}
+
+func (f *fakeLoginLockoutStore) LockedUntil(ctx context.Context, key string, now time.Time) (time.Time, bool, error) {
+	row, ok := f.rows[key]
+	if !ok || !row.LockedUntil.Valid || !row.LockedUntil.Time.After(now) {
+		return time.Time{}, false, nil
+	}
+	return row.LockedUntil.Time, true, nil
+}
+
+func (f *fakeLoginLockoutStore) Clear(ctx context.Context, key string) (bool, error) {
+	_, ok := f.rows[key]
+	delete(f.rows, key)
+	return ok, nil
+}
+
+func (f *fakeLoginLockoutStore) ListLocked(ctx context.Context, now time.Time) ([]generated.ListLoginLockoutsRow, error) {
+	var rows []generated.ListLoginLockoutsRow
+	for _, row := range f.rows {
+		if row.LockedUntil.Valid && row.LockedUntil.Time.After(now) {
+			rows = append(rows, generated.ListLoginLockoutsRow{LockKey: row.LockKey, Failures: row.Failures, LockedUntil: row.LockedUntil})
+		}
+	}
+	return rows, nil
+}
+
+func (f *fakeLoginLockoutStore) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	return int64(len(f.rows)), nil, nil
+}
+
+func (f *fakeLoginLockoutStore) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+type fakeLockoutUserStore struct {
+	repository.UserStore
+	user generated.User
+}
+
+func (f *fakeLockoutUserStore) GetByEmail(ctx context.Context, email string) (generated.User, error) {
+	if email != f.user.Email {
+		return generated.User{}, errors.New("not found")
+	}
+	return f.user, nil
+}
+
+func TestLoginLockout(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("SecurePass123!"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clk := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	store := newFakeLoginLockoutStore()
+	lockout := NewLoginLockout(store, LockoutConfig{Threshold: 3, IPThreshold: 5, Window: 15 * time.Minute, Duration: 10 * time.Minute})
+	lockout.SetClock(clk)
+	auth := NewAuthService(&fakeLockoutUserStore{user: generated.User{ID: 1, Email: "jane@example.com", PasswordHash: string(hash), Role: "user", Active: true}})
+	auth.SetJWTConfig("test-secret", time.Hour)
+	auth.SetClock(clk)
+	auth.SetLockout(lockout)
+
+	ctx := WithClientIP(context.Background(), "203.0.113.7")
+	login := func(email, password string) error {
+		_, _, err := auth.Login(ctx, email, password)
+		return err
+	}
+
+	// A success in between resets the email's count.
+	login("jane@example.com", "wrong")
+	login("jane@example.com", "wrong")
+	if err := login("jane@example.com", "SecurePass123!"); err != nil {
+		t.Fatalf("login before the threshold: %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := login("Jane@example.com", "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("failure %d: got %v, want invalid credentials", i+1, err)
+		}
+	}
+
+	err = login("jane@example.com", "SecurePass123!")
+	var locked *AccountLockedError
+	if !errors.As(err, &locked) || !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("login after the threshold: got %v, want locked", err)
+	}
+	if want := clk.Now().Add(10 * time.Minute); !locked.Until.Equal(want) {
+		t.Errorf("locked until %v, want %v", locked.Until, want)
+	}
+
+	// The IP reached its 5 failures too, so other emails are locked out
+	// from it.
+	if err := login("bob@example.com", "wrong"); !errors.Is(err, ErrAccountLocked) {
+		t.Errorf("from the locked IP: got %v, want locked", err)
+	}
+
+	list, err := lockout.Locked(context.Background())
+	if err != nil || len(list) != 2 {
+		t.Errorf("Locked() = %+v, %v; want the email and the IP", list, err)
+	}
+
+	if cleared, err := lockout.Unlock(context.Background(), "JANE@example.com"); !cleared || err != nil {
+		t.Fatalf("Unlock() = %v, %v", cleared, err)
+	}
+	if _, _, err := auth.Login(WithClientIP(context.Background(), "198.51.100.1"), "jane@example.com", "SecurePass123!"); err != nil {
+		t.Errorf("login after unlock: %v", err)
+	}
+
+	// The IP's lock runs out.
+	clk.Advance(10 * time.Minute)
+	if err := login("jane@example.com", "SecurePass123!"); err != nil {
+		t.Errorf("login after the lock expired: %v", err)
+	}
+}
+
+func TestLoginLockoutDisabled(t *testing.T) {
+	lockout := NewLoginLockout(newFakeLoginLockoutStore(), LockoutConfig{Window: time.Minute, Duration: time.Minute})
+	ctx := WithClientIP(context.Background(), "203.0.113.7")
+	for i := 0; i < 10; i++ {
+		if err := lockout.Failure(ctx, "jane@example.com"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := lockout.Check(ctx, "jane@example.com"); err != nil {
+		t.Errorf("Check() with zero thresholds = %v", err)
+	}
+}
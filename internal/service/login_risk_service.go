@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/clock"
+	"BACKEND/internal/geoip"
+	"BACKEND/internal/mailer"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/templates"
+)
+
+var (
+	ErrLoginChallenged       = errors.New("login requires confirmation")
+	ErrLoginChallengeInvalid = errors.New("login challenge is invalid or has expired")
+	ErrLoginChallengeCode    = errors.New("login challenge code is incorrect")
+)
+
+// Signals that make a login risky.
+const (
+	RiskNewDevice      = "new_device"
+	RiskNewCountry     = "new_country"
+	RiskRapidAttempts  = "rapid_attempts"
+	loginHistoryWindow = 50
+)
+
+// riskWeights is what each signal adds to a login's score. A new country
+// weighs most: one alone is still likely to be a trip, but together with
+// either other signal it reads as someone else.
+var riskWeights = map[string]int{
+	RiskNewDevice:     30,
+	RiskNewCountry:    40,
+	RiskRapidAttempts: 30,
+}
+
+// Wrong codes a challenge takes before it is thrown away.
+const maxChallengeAttempts = 5
+
+// LoginRiskConfig configures the risk check on password logins. A login
+// scoring Threshold or more has to be confirmed with a code emailed to the
+// user; zero Threshold turns the check off. RapidAttempts failed logins to
+// the account within RapidWindow count as rapid attempts.
+type LoginRiskConfig struct {
+	Threshold     int
+	ChallengeTTL  time.Duration
+	RapidAttempts int
+	RapidWindow   time.Duration
+}
+
+// LoginChallengeError is returned for a correct password when the login
+// looks too risky to complete at once. A code has been emailed to the user,
+// and the login completes when it is confirmed with ChallengeID. It matches
+// ErrLoginChallenged.
+type LoginChallengeError struct {
+	ChallengeID string
+	Signals     []string
+	ExpiresAt   time.Time
+}
+
+func (e *LoginChallengeError) Error() string {
+	return fmt.Sprintf("%s (%s)", ErrLoginChallenged, strings.Join(e.Signals, ", "))
+}
+
+func (e *LoginChallengeError) Unwrap() error {
+	return ErrLoginChallenged
+}
+
+type loginChallenge struct {
+	userID    int64
+	email     string
+	code      string
+	expiresAt time.Time
+	attempts  int
+}
+
+// LoginRisk scores password logins on a new device (an unseen user agent),
+// from a new country and after a burst of failed attempts, comparing them
+// with the user's successful logins in the login history. Users without
+// any are never considered on a new device or in a new country.
+//
+// Failed attempts and open challenges are kept in memory and swept once
+// they expire. Each instance only counts the failures it sees, and a
+// challenge has to be confirmed on the instance that issued it, so this is
+// meant for a single instance.
+type LoginRisk struct {
+	history  repository.LoginHistoryStore
+	users    repository.UserStore
+	locator  geoip.Locator
+	auth     *AuthService
+	mailer   mailer.Mailer
+	renderer *templates.Renderer
+	cfg      LoginRiskConfig
+	logger   *zap.Logger
+	clock    clock.Clock
+
+	mu         sync.Mutex
+	failures   map[string][]time.Time
+	challenges map[string]*loginChallenge
+	lastPruned time.Time
+}
+
+func NewLoginRisk(history repository.LoginHistoryStore, users repository.UserStore, auth *AuthService, m mailer.Mailer, renderer *templates.Renderer, cfg LoginRiskConfig, logger *zap.Logger) *LoginRisk {
+	return &LoginRisk{
+		history:    history,
+		users:      users,
+		auth:       auth,
+		mailer:     m,
+		renderer:   renderer,
+		cfg:        cfg,
+		logger:     logger,
+		clock:      clock.System,
+		failures:   make(map[string][]time.Time),
+		challenges: make(map[string]*loginChallenge),
+	}
+}
+
+// SetGeoIP enables the new country signal.
+func (r *LoginRisk) SetGeoIP(locator geoip.Locator) {
+	r.locator = locator
+}
+
+func (r *LoginRisk) SetClock(c clock.Clock) {
+	r.clock = c
+}
+
+// Failure counts a failed login to email towards the rapid attempts signal.
+func (r *LoginRisk) Failure(email string) {
+	if r == nil {
+		return
+	}
+	key := strings.ToLower(strings.TrimSpace(email))
+	now := r.clock.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pruneLocked(now)
+	r.failures[key] = append(r.recentFailuresLocked(key, now), now)
+}
+
+func (r *LoginRisk) recentFailuresLocked(key string, now time.Time) []time.Time {
+	recent := r.failures[key][:0]
+	for _, at := range r.failures[key] {
+		if now.Sub(at) < r.cfg.RapidWindow {
+			recent = append(recent, at)
+		}
+	}
+	if len(recent) == 0 {
+		delete(r.failures, key)
+		return nil
+	}
+	r.failures[key] = recent
+	return recent
+}
+
+// pruneLocked sweeps accounts whose failures have all left the rapid
+// window and challenges that have expired, at most once a minute.
+func (r *LoginRisk) pruneLocked(now time.Time) {
+	if now.Sub(r.lastPruned) < time.Minute {
+		return
+	}
+	r.lastPruned = now
+	for key := range r.failures {
+		r.recentFailuresLocked(key, now)
+	}
+	for id, c := range r.challenges {
+		if !now.Before(c.expiresAt) {
+			delete(r.challenges, id)
+		}
+	}
+}
+
+// Assess returns the signals a login by user, from the client in ctx,
+// raises and its score.
+func (r *LoginRisk) Assess(ctx context.Context, user generated.User) ([]string, int, error) {
+	rows, err := r.history.ListByUser(ctx, user.ID, loginHistoryWindow)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to load login history: %w", err)
+	}
+	devices := make(map[string]bool)
+	countries := make(map[string]bool)
+	for _, row := range rows {
+		if !row.Succeeded {
+			continue
+		}
+		devices[row.UserAgent] = true
+		if row.Country != "" {
+			countries[row.Country] = true
+		}
+	}
+
+	var signals []string
+	if len(devices) > 0 && !devices[userAgent(ctx)] {
+		signals = append(signals, RiskNewDevice)
+	}
+	if r.locator != nil && len(countries) > 0 {
+		if loc, ok := r.locator.Lookup(clientIP(ctx)); ok && !countries[loc.Country] {
+			signals = append(signals, RiskNewCountry)
+		}
+	}
+	r.mu.Lock()
+	failures := len(r.recentFailuresLocked(strings.ToLower(user.Email), r.clock.Now()))
+	r.mu.Unlock()
+	if r.cfg.RapidAttempts > 0 && failures >= r.cfg.RapidAttempts {
+		signals = append(signals, RiskRapidAttempts)
+	}
+
+	score := 0
+	for _, signal := range signals {
+		score += riskWeights[signal]
+	}
+	return signals, score, nil
+}
+
+// Check lets a login by user through, or, when it scores at or over the
+// threshold, emails a code and returns a *LoginChallengeError.
+func (r *LoginRisk) Check(ctx context.Context, user generated.User) error {
+	if r == nil || r.cfg.Threshold <= 0 {
+		return nil
+	}
+	signals, score, err := r.Assess(ctx, user)
+	if err != nil {
+		return err
+	}
+	if score < r.cfg.Threshold {
+		return nil
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		return err
+	}
+	code, err := randomDigits(6)
+	if err != nil {
+		return err
+	}
+	msg, err := r.renderer.Render("login_challenge", "", map[string]interface{}{
+		"Name":      user.Name,
+		"Code":      code,
+		"IPAddress": clientIP(ctx),
+		"ExpiresIn": r.cfg.ChallengeTTL.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render login challenge email: %w", err)
+	}
+
+	now := r.clock.Now()
+	expiresAt := now.Add(r.cfg.ChallengeTTL)
+	r.mu.Lock()
+	r.pruneLocked(now)
+	r.challenges[id] = &loginChallenge{
+		userID:    user.ID,
+		email:     user.Email,
+		code:      HashAPIKey(code),
+		expiresAt: expiresAt,
+	}
+	r.mu.Unlock()
+
+	to := []string{user.Email}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := r.mailer.Send(ctx, to, msg); err != nil {
+			r.logger.Error("failed to send login challenge", zap.Int64("user_id", user.ID), zap.Error(err))
+		}
+	}()
+	return &LoginChallengeError{ChallengeID: id, Signals: signals, ExpiresAt: expiresAt}
+}
+
+// Confirm completes the login challenge id with the emailed code and
+// returns its user with a JWT.
+func (r *LoginRisk) Confirm(ctx context.Context, id, code string) (generated.User, string, error) {
+	now := r.clock.Now()
+	r.mu.Lock()
+	c, ok := r.challenges[id]
+	if !ok || !now.Before(c.expiresAt) {
+		delete(r.challenges, id)
+		r.mu.Unlock()
+		return generated.User{}, "", ErrLoginChallengeInvalid
+	}
+	if !hmac.Equal([]byte(HashAPIKey(strings.TrimSpace(code))), []byte(c.code)) {
+		c.attempts++
+		if c.attempts >= maxChallengeAttempts {
+			delete(r.challenges, id)
+		}
+		r.mu.Unlock()
+		return generated.User{}, "", ErrLoginChallengeCode
+	}
+	delete(r.challenges, id)
+	delete(r.failures, strings.ToLower(c.email))
+	r.mu.Unlock()
+
+	// The account may have changed while the code was on its way.
+	user, err := r.users.GetByID(ctx, c.userID)
+	if err != nil {
+		return generated.User{}, "", ErrLoginChallengeInvalid
+	}
+	if !user.Active {
+		return generated.User{}, "", ErrAccountDisabled
+	}
+	token, err := r.auth.GenerateJWT(ctx, user.ID, user.Role)
+	if err != nil {
+		return generated.User{}, "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	loggedIn := generated.User{
+		ID:          user.ID,
+		Name:        user.Name,
+		Dob:         user.Dob,
+		Email:       user.Email,
+		Role:        user.Role,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+		Active:      user.Active,
+		AccountType: user.AccountType,
+		PublicID:    user.PublicID,
+	}
+	r.auth.afterLogin(ctx, loggedIn)
+	return loggedIn, token, nil
+}
+
+// randomDigits returns n random decimal digits.
+func randomDigits(n int) (string, error) {
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		d, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(byte('0' + d.Int64()))
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/clock"
+	"BACKEND/internal/geoip"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/templates"
+)
+
+type countryLocator map[string]string
+
+func (l countryLocator) Lookup(ip string) (geoip.Location, bool) {
+	country, ok := l[ip]
+	return geoip.Location{Country: country}, ok
+}
+
+func TestLoginRiskChallenge(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("SecurePass123!"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := generated.User{ID: 7, Name: "Jane", Email: "jane@example.com", PasswordHash: string(hash), Role: "user", Active: true, AccountType: AccountTypeHuman}
+	store := &fakeMagicLinkUserStore{user: user}
+	auth := NewAuthService(store)
+	auth.SetJWTConfig("test-secret", time.Hour)
+	renderer, err := templates.NewRenderer(templates.Branding{ProductName: "Test"}, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	history := repository.NewMemoryLoginHistoryStore()
+	m := &fakeMailer{sent: make(chan *templates.Email, 1)}
+	risk := NewLoginRisk(history, store, auth, m, renderer, LoginRiskConfig{
+		Threshold:     50,
+		ChallengeTTL:  10 * time.Minute,
+		RapidAttempts: 3,
+		RapidWindow:   15 * time.Minute,
+	}, zap.NewNop())
+	risk.SetGeoIP(countryLocator{"198.51.100.1": "ES", "203.0.113.7": "KP"})
+	auth.SetLoginRisk(risk)
+
+	client := func(ip, ua string) context.Context {
+		return WithUserAgent(WithClientIP(context.Background(), ip), ua)
+	}
+	login := func(ctx context.Context, password string) (string, error) {
+		_, token, err := auth.Login(ctx, user.Email, password)
+		return token, err
+	}
+
+	// Nothing to compare the first login with.
+	home := client("198.51.100.1", "Firefox")
+	if _, err := login(home, "SecurePass123!"); err != nil {
+		t.Fatalf("first login: %v", err)
+	}
+	id := user.ID
+	history.Record(context.Background(), repository.LoginAttempt{UserID: &id, Email: user.Email, Succeeded: true, IPAddress: "198.51.100.1", UserAgent: "Firefox", Country: "ES"})
+
+	// A new device alone scores under the threshold.
+	if _, err := login(client("198.51.100.1", "Safari"), "SecurePass123!"); err != nil {
+		t.Errorf("login from a new device at home: %v", err)
+	}
+
+	// A new device after failed attempts doesn't.
+	for i := 0; i < 3; i++ {
+		if _, err := login(home, "wrong"); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("wrong password: %v", err)
+		}
+	}
+	_, err = login(client("203.0.113.7", "Safari"), "SecurePass123!")
+	var challenge *LoginChallengeError
+	if !errors.As(err, &challenge) {
+		t.Fatalf("risky login = %v; want a challenge", err)
+	}
+	if len(challenge.Signals) != 3 {
+		t.Errorf("signals = %v; want a new device, a new country and rapid attempts", challenge.Signals)
+	}
+
+	var code string
+	select {
+	case email := <-m.sent:
+		if match := regexp.MustCompile(`<strong>(\d{6})</strong>`).FindStringSubmatch(email.HTML); match != nil {
+			code = match[1]
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no code was emailed")
+	}
+
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	if _, _, err := risk.Confirm(context.Background(), challenge.ChallengeID, wrong); !errors.Is(err, ErrLoginChallengeCode) {
+		t.Errorf("wrong code = %v; want ErrLoginChallengeCode", err)
+	}
+	confirmed, token, err := risk.Confirm(context.Background(), challenge.ChallengeID, code)
+	if err != nil || token == "" || confirmed.ID != user.ID {
+		t.Fatalf("Confirm = %d, %q, %v", confirmed.ID, token, err)
+	}
+	if _, _, err := risk.Confirm(context.Background(), challenge.ChallengeID, code); !errors.Is(err, ErrLoginChallengeInvalid) {
+		t.Errorf("confirming twice = %v; want ErrLoginChallengeInvalid", err)
+	}
+}
+
+func TestLoginRiskPrunesFailures(t *testing.T) {
+	fake := clock.NewFake(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	risk := NewLoginRisk(nil, nil, nil, nil, nil, LoginRiskConfig{RapidAttempts: 3, RapidWindow: 15 * time.Minute}, zap.NewNop())
+	risk.SetClock(fake)
+
+	for _, email := range []string{"a@example.com", "b@example.com", "c@example.com"} {
+		risk.Failure(email)
+	}
+	risk.challenges["old"] = &loginChallenge{expiresAt: fake.Now().Add(10 * time.Minute)}
+
+	fake.Advance(16 * time.Minute)
+	risk.Failure("d@example.com")
+
+	if len(risk.failures) != 1 || risk.failures["d@example.com"] == nil {
+		t.Errorf("failures after the window = %v, expected only d@example.com", risk.failures)
+	}
+	if len(risk.challenges) != 0 {
+		t.Errorf("expected the expired challenge to be swept, got %d", len(risk.challenges))
+	}
+}
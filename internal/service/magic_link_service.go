@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/clock"
+	"BACKEND/internal/mailer"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/templates"
+)
+
+var (
+	ErrMagicLinkInvalid = errors.New("magic link is invalid")
+	ErrMagicLinkExpired = errors.New("magic link has expired")
+	ErrMagicLinkUsed    = errors.New("magic link has already been used")
+)
+
+// A new link for the same address is not sent more often than this, so the
+// endpoint cannot be used to flood someone's inbox.
+const magicLinkResendInterval = time.Minute
+
+// MagicLinkConfig configures password-less login. URL is where the link
+// points; the token is added as the "token" query parameter.
+type MagicLinkConfig struct {
+	Secret string
+	TTL    time.Duration
+	URL    string
+}
+
+// MagicLinkService emails signed, single-use login links. A link carries the
+// user's ID and email, so it stops working if the email changes. Used links
+// are recorded in redemptions, so they stay used on every instance.
+type MagicLinkService struct {
+	repo        repository.UserStore
+	redemptions repository.MagicLinkRedemptionStore
+	auth        *AuthService
+	mailer      mailer.Mailer
+	renderer    *templates.Renderer
+	cfg         MagicLinkConfig
+	logger      *zap.Logger
+	clock       clock.Clock
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time
+}
+
+func NewMagicLinkService(repo repository.UserStore, redemptions repository.MagicLinkRedemptionStore, auth *AuthService, m mailer.Mailer, renderer *templates.Renderer, cfg MagicLinkConfig, logger *zap.Logger) *MagicLinkService {
+	return &MagicLinkService{
+		repo:        repo,
+		redemptions: redemptions,
+		auth:        auth,
+		mailer:      m,
+		renderer:    renderer,
+		cfg:         cfg,
+		logger:      logger,
+		lastSent:    make(map[string]time.Time),
+		clock:       clock.System,
+	}
+}
+
+// SetClock sets the clock links and resends are timed with.
+func (s *MagicLinkService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// Send emails a login link to email. Unknown, disabled and service accounts
+// are skipped without an error so callers cannot tell which addresses exist.
+// The email is sent in the background.
+func (s *MagicLinkService) Send(ctx context.Context, email, locale string) error {
+	user, err := s.repo.GetByEmail(ctx, email)
+	if err != nil || !user.Active || user.AccountType == AccountTypeService {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.pruneLocked()
+	now := s.clock.Now()
+	if last, ok := s.lastSent[user.Email]; ok && now.Sub(last) < magicLinkResendInterval {
+		s.mu.Unlock()
+		return nil
+	}
+	s.lastSent[user.Email] = now
+	s.mu.Unlock()
+
+	link, err := s.link(user.ID, user.Email)
+	if err != nil {
+		return err
+	}
+	msg, err := s.renderer.Render("magic_link", locale, map[string]interface{}{
+		"Name":      user.Name,
+		"LoginURL":  link,
+		"ExpiresIn": s.cfg.TTL.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render magic link email: %w", err)
+	}
+
+	to := []string{user.Email}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := s.mailer.Send(ctx, to, msg); err != nil {
+			s.logger.Error("failed to send magic link", zap.Int64("user_id", user.ID), zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// Verify checks a link's token, marks it used and logs the user in.
+func (s *MagicLinkService) Verify(ctx context.Context, token string) (generated.User, string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 {
+		return generated.User{}, "", ErrMagicLinkInvalid
+	}
+	id, expires, nonce, signature := parts[0], parts[1], parts[2], parts[3]
+
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return generated.User{}, "", ErrMagicLinkInvalid
+	}
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return generated.User{}, "", ErrMagicLinkInvalid
+	}
+
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return generated.User{}, "", ErrMagicLinkInvalid
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(id, user.Email, expires, nonce))) {
+		return generated.User{}, "", ErrMagicLinkInvalid
+	}
+	now := s.clock.Now()
+	expiresAt := time.Unix(expiresUnix, 0)
+	if now.After(expiresAt) {
+		return generated.User{}, "", ErrMagicLinkExpired
+	}
+
+	fresh, err := s.redemptions.Redeem(ctx, HashAPIKey(signature), expiresAt)
+	if err != nil {
+		return generated.User{}, "", fmt.Errorf("failed to redeem magic link: %w", err)
+	}
+	if !fresh {
+		return generated.User{}, "", ErrMagicLinkUsed
+	}
+
+	if user.AccountType == AccountTypeService {
+		return generated.User{}, "", ErrInteractiveLoginDenied
+	}
+	if !user.Active {
+		return generated.User{}, "", ErrAccountDisabled
+	}
+
+	jwtToken, err := s.auth.GenerateJWT(ctx, user.ID, user.Role)
+	if err != nil {
+		return generated.User{}, "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	loggedIn := generated.User{
+		ID:          user.ID,
+		Name:        user.Name,
+		Dob:         user.Dob,
+		Email:       user.Email,
+		Role:        user.Role,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+		Active:      user.Active,
+		AccountType: user.AccountType,
+		PublicID:    user.PublicID,
+	}
+	s.auth.afterLogin(ctx, loggedIn)
+	return loggedIn, jwtToken, nil
+}
+
+func (s *MagicLinkService) link(userID int64, email string) (string, error) {
+	nonce, err := randomHex(8)
+	if err != nil {
+		return "", err
+	}
+	id := strconv.FormatInt(userID, 10)
+	expires := strconv.FormatInt(s.clock.Now().Add(s.cfg.TTL).Unix(), 10)
+	token := strings.Join([]string{id, expires, nonce, s.sign(id, email, expires, nonce)}, ".")
+
+	q := url.Values{}
+	q.Set("token", token)
+	sep := "?"
+	if strings.Contains(s.cfg.URL, "?") {
+		sep = "&"
+	}
+	return s.cfg.URL + sep + q.Encode(), nil
+}
+
+func (s *MagicLinkService) sign(id, email, expires, nonce string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.Secret))
+	mac.Write([]byte("magic-link." + id + "." + strings.ToLower(email) + "." + expires + "." + nonce))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// pruneLocked forgets resend timestamps once they no longer hold anything
+// back.
+func (s *MagicLinkService) pruneLocked() {
+	now := s.clock.Now()
+	for email, sent := range s.lastSent {
+		if now.Sub(sent) >= magicLinkResendInterval {
+			delete(s.lastSent, email)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/clock"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/templates"
+)
+
+type fakeMagicLinkUserStore struct {
+	repository.UserStore
+	user generated.User
+}
+
+func (f *fakeMagicLinkUserStore) GetByEmail(ctx context.Context, email string) (generated.User, error) {
+	if email != f.user.Email {
+		return generated.User{}, errors.New("not found")
+	}
+	return f.user, nil
+}
+
+func (f *fakeMagicLinkUserStore) GetByID(ctx context.Context, id int64) (generated.GetUserByIDRow, error) {
+	if id != f.user.ID {
+		return generated.GetUserByIDRow{}, errors.New("not found")
+	}
+	return generated.GetUserByIDRow{
+		ID:          f.user.ID,
+		Name:        f.user.Name,
+		Email:       f.user.Email,
+		Role:        f.user.Role,
+		Active:      f.user.Active,
+		AccountType: f.user.AccountType,
+		PublicID:    f.user.PublicID,
+	}, nil
+}
+
+type fakeMailer struct {
+	sent chan *templates.Email
+}
+
+func (m *fakeMailer) Send(ctx context.Context, to []string, email *templates.Email) error {
+	m.sent <- email
+	return nil
+}
+
+func (m *fakeMailer) Driver() string { return "fake" }
+
+func newTestMagicLinkService(t *testing.T, user generated.User, ttl time.Duration) (*MagicLinkService, *fakeMagicLinkUserStore, *fakeMailer) {
+	t.Helper()
+	store := &fakeMagicLinkUserStore{user: user}
+	auth := NewAuthService(store)
+	auth.SetJWTConfig("test-secret", time.Hour)
+	renderer, err := templates.NewRenderer(templates.Branding{ProductName: "Test", SupportEmail: "support@example.com"}, "en")
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+	m := &fakeMailer{sent: make(chan *templates.Email, 1)}
+	svc := NewMagicLinkService(store, repository.NewMemoryMagicLinkStore(), auth, m, renderer, MagicLinkConfig{
+		Secret: "link-secret",
+		TTL:    ttl,
+		URL:    "https://example.com/auth/magic-link/verify",
+	}, zap.NewNop())
+	return svc, store, m
+}
+
+func sentToken(t *testing.T, m *fakeMailer) string {
+	t.Helper()
+	select {
+	case email := <-m.sent:
+		start := strings.Index(email.HTML, "https://example.com/")
+		if start < 0 {
+			t.Fatalf("email has no link: %s", email.HTML)
+		}
+		link := email.HTML[start:]
+		link = link[:strings.IndexByte(link, '"')]
+		u, err := url.Parse(strings.ReplaceAll(link, "&amp;", "&"))
+		if err != nil {
+			t.Fatalf("parse link: %v", err)
+		}
+		return u.Query().Get("token")
+	case <-time.After(time.Second):
+		t.Fatal("no email sent")
+		return ""
+	}
+}
+
+func TestMagicLinkLogin(t *testing.T) {
+	user := generated.User{ID: 7, Name: "Jane", Email: "jane@example.com", Role: "user", Active: true, AccountType: AccountTypeHuman,
+		PublicID: pgtype.UUID{Bytes: uuid.New(), Valid: true}}
+	svc, _, m := newTestMagicLinkService(t, user, time.Minute)
+	ctx := context.Background()
+
+	if err := svc.Send(ctx, user.Email, ""); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	token := sentToken(t, m)
+
+	got, jwtToken, err := svc.Verify(ctx, token)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if got.ID != user.ID || jwtToken == "" {
+		t.Fatalf("Verify = %d, %q", got.ID, jwtToken)
+	}
+	if got.PublicID != user.PublicID {
+		t.Errorf("Verify public ID = %v, want %v", got.PublicID, user.PublicID)
+	}
+
+	if _, _, err := svc.Verify(ctx, token); !errors.Is(err, ErrMagicLinkUsed) {
+		t.Fatalf("second Verify err = %v, want %v", err, ErrMagicLinkUsed)
+	}
+}
+
+func TestMagicLinkUsedOnAnotherInstance(t *testing.T) {
+	user := generated.User{ID: 7, Name: "Jane", Email: "jane@example.com", Role: "user", Active: true, AccountType: AccountTypeHuman}
+	svc, _, m := newTestMagicLinkService(t, user, time.Minute)
+	other, _, _ := newTestMagicLinkService(t, user, time.Minute)
+	other.redemptions = svc.redemptions
+	ctx := context.Background()
+
+	if err := svc.Send(ctx, user.Email, ""); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	token := sentToken(t, m)
+
+	if _, _, err := other.Verify(ctx, token); err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if _, _, err := svc.Verify(ctx, token); !errors.Is(err, ErrMagicLinkUsed) {
+		t.Fatalf("Verify on the sending instance err = %v, want %v", err, ErrMagicLinkUsed)
+	}
+}
+
+func TestMagicLinkRejectsTamperedAndExpired(t *testing.T) {
+	user := generated.User{ID: 7, Name: "Jane", Email: "jane@example.com", Role: "user", Active: true, AccountType: AccountTypeHuman}
+	ctx := context.Background()
+
+	svc, _, m := newTestMagicLinkService(t, user, time.Minute)
+	if err := svc.Send(ctx, user.Email, ""); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	token := sentToken(t, m)
+	parts := strings.Split(token, ".")
+	parts[1] = "9999999999"
+	if _, _, err := svc.Verify(ctx, strings.Join(parts, ".")); !errors.Is(err, ErrMagicLinkInvalid) {
+		t.Fatalf("tampered Verify err = %v, want %v", err, ErrMagicLinkInvalid)
+	}
+
+	expired, _, m := newTestMagicLinkService(t, user, time.Minute)
+	clk := clock.NewFake(time.Now())
+	expired.SetClock(clk)
+	if err := expired.Send(ctx, user.Email, ""); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	clk.Advance(2 * time.Minute)
+	if _, _, err := expired.Verify(ctx, sentToken(t, m)); !errors.Is(err, ErrMagicLinkExpired) {
+		t.Fatalf("expired Verify err = %v, want %v", err, ErrMagicLinkExpired)
+	}
+}
+
+func TestMagicLinkInvalidAfterEmailChange(t *testing.T) {
+	user := generated.User{ID: 7, Name: "Jane", Email: "jane@example.com", Role: "user", Active: true, AccountType: AccountTypeHuman}
+	svc, store, m := newTestMagicLinkService(t, user, time.Minute)
+	ctx := context.Background()
+
+	if err := svc.Send(ctx, user.Email, ""); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	token := sentToken(t, m)
+
+	store.user.Email = "new@example.com"
+	if _, _, err := svc.Verify(ctx, token); !errors.Is(err, ErrMagicLinkInvalid) {
+		t.Fatalf("Verify err = %v, want %v", err, ErrMagicLinkInvalid)
+	}
+}
+
+func TestMagicLinkSkipsUnknownAndServiceAccounts(t *testing.T) {
+	user := generated.User{ID: 7, Name: "Bot", Email: "bot@example.com", Role: "user", Active: true, AccountType: AccountTypeService}
+	svc, _, m := newTestMagicLinkService(t, user, time.Minute)
+	ctx := context.Background()
+
+	for _, email := range []string{"nobody@example.com", user.Email} {
+		if err := svc.Send(ctx, email, ""); err != nil {
+			t.Fatalf("Send(%q): %v", email, err)
+		}
+	}
+	select {
+	case <-m.sent:
+		t.Fatal("email sent for an account that cannot use magic links")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestMagicLinkResendInterval(t *testing.T) {
+	user := generated.User{ID: 7, Name: "Jane", Email: "jane@example.com", Role: "user", Active: true, AccountType: AccountTypeHuman}
+	svc, _, m := newTestMagicLinkService(t, user, time.Minute)
+	ctx := context.Background()
+
+	if err := svc.Send(ctx, user.Email, ""); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	sentToken(t, m)
+	if err := svc.Send(ctx, user.Email, ""); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	select {
+	case <-m.sent:
+		t.Fatal("second link sent within the resend interval")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/clock"
+	"BACKEND/internal/mailer"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/templates"
+)
+
+var (
+	ErrMailThrottled         = errors.New("recipients have reached their email limit")
+	ErrDeadLetterNotFound    = errors.New("dead letter not found")
+	ErrDeadLetterRetryFailed = errors.New("dead letter could not be sent")
+)
+
+// MailDeliveryConfig limits and retries outgoing email. RecipientLimit
+// emails per RecipientWindow go to each address, and an email identical to
+// one sent to the same address within DedupWindow is dropped; zero turns
+// either off. A failed send is tried MaxAttempts times in all, waiting
+// RetryBackoff before the second try and twice as long before each one
+// after.
+type MailDeliveryConfig struct {
+	RecipientLimit  int
+	RecipientWindow time.Duration
+	DedupWindow     time.Duration
+	MaxAttempts     int
+	RetryBackoff    time.Duration
+}
+
+// MailDelivery is the Mailer the services send through. It wraps the
+// configured driver with per-recipient limits, drops duplicates of recent
+// emails and retries failed sends; emails the relay rejects outright, or
+// that still fail after the last try, are kept as dead letters for an admin
+// to retry.
+//
+// Limits and recent emails are counted in memory, per instance.
+type MailDelivery struct {
+	mailer      mailer.Mailer
+	deadLetters repository.EmailDeadLetterStore
+	cfg         MailDeliveryConfig
+	logger      *zap.Logger
+	clock       clock.Clock
+
+	mu     sync.Mutex
+	sends  map[string][]time.Time
+	recent map[string]time.Time
+}
+
+var _ mailer.Mailer = (*MailDelivery)(nil)
+
+func NewMailDelivery(m mailer.Mailer, deadLetters repository.EmailDeadLetterStore, cfg MailDeliveryConfig, logger *zap.Logger) *MailDelivery {
+	return &MailDelivery{
+		mailer:      m,
+		deadLetters: deadLetters,
+		cfg:         cfg,
+		logger:      logger,
+		clock:       clock.System,
+		sends:       make(map[string][]time.Time),
+		recent:      make(map[string]time.Time),
+	}
+}
+
+func (d *MailDelivery) SetClock(c clock.Clock) {
+	d.clock = c
+}
+
+func (d *MailDelivery) Driver() string {
+	return d.mailer.Driver()
+}
+
+// Send delivers email to the recipients that are under their limit and
+// haven't just been sent the same email. It returns ErrMailThrottled when
+// every recipient is over the limit, and the driver's error when the send
+// failed and was dead-lettered.
+func (d *MailDelivery) Send(ctx context.Context, to []string, email *templates.Email) error {
+	admitted, throttled := d.admit(to, email)
+	if len(throttled) > 0 {
+		d.logger.Warn("email throttled",
+			zap.Strings("to", throttled),
+			zap.String("subject", email.Subject),
+		)
+	}
+	if len(admitted) == 0 {
+		if len(throttled) > 0 {
+			return ErrMailThrottled
+		}
+		return nil
+	}
+
+	maxAttempts := max(d.cfg.MaxAttempts, 1)
+	attempts := 0
+	var err error
+	for {
+		attempts++
+		if err = d.mailer.Send(ctx, admitted, email); err == nil {
+			return nil
+		}
+		if mailer.Permanent(err) || attempts >= maxAttempts || !d.wait(ctx, d.cfg.RetryBackoff<<(attempts-1)) {
+			break
+		}
+	}
+
+	// A later send of the same email is a retry, not a duplicate.
+	d.forget(admitted, email)
+	d.deadLetter(ctx, admitted, email, err, attempts)
+	return err
+}
+
+// admit splits to into the recipients the email may go to now and those
+// over their limit, dropping those it was just sent to, and counts the
+// send against the admitted ones.
+func (d *MailDelivery) admit(to []string, email *templates.Email) (admitted, throttled []string) {
+	now := d.clock.Now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.pruneLocked(now)
+
+	for _, recipient := range to {
+		address := strings.ToLower(strings.TrimSpace(recipient))
+		key := dedupKey(address, email)
+		if d.cfg.DedupWindow > 0 {
+			if _, ok := d.recent[key]; ok {
+				continue
+			}
+		}
+		if d.cfg.RecipientLimit > 0 && len(d.sends[address]) >= d.cfg.RecipientLimit {
+			throttled = append(throttled, recipient)
+			continue
+		}
+		if d.cfg.RecipientLimit > 0 {
+			d.sends[address] = append(d.sends[address], now)
+		}
+		if d.cfg.DedupWindow > 0 {
+			d.recent[key] = now
+		}
+		admitted = append(admitted, recipient)
+	}
+	return admitted, throttled
+}
+
+func (d *MailDelivery) pruneLocked(now time.Time) {
+	for address, times := range d.sends {
+		live := times[:0]
+		for _, at := range times {
+			if now.Sub(at) < d.cfg.RecipientWindow {
+				live = append(live, at)
+			}
+		}
+		if len(live) == 0 {
+			delete(d.sends, address)
+		} else {
+			d.sends[address] = live
+		}
+	}
+	for key, at := range d.recent {
+		if now.Sub(at) >= d.cfg.DedupWindow {
+			delete(d.recent, key)
+		}
+	}
+}
+
+func (d *MailDelivery) forget(to []string, email *templates.Email) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, recipient := range to {
+		delete(d.recent, dedupKey(strings.ToLower(strings.TrimSpace(recipient)), email))
+	}
+}
+
+func dedupKey(address string, email *templates.Email) string {
+	sum := sha256.Sum256([]byte(address + "\x00" + email.Subject + "\x00" + email.HTML))
+	return hex.EncodeToString(sum[:])
+}
+
+// wait sleeps for backoff and reports false if ctx ended first.
+func (d *MailDelivery) wait(ctx context.Context, backoff time.Duration) bool {
+	if backoff <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(backoff)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (d *MailDelivery) deadLetter(ctx context.Context, to []string, email *templates.Email, sendErr error, attempts int) {
+	// The caller may have given up on the request; the email is still
+	// worth keeping.
+	letter, err := d.deadLetters.Create(context.WithoutCancel(ctx), strings.Join(to, ","), email.Subject, email.HTML, sendErr.Error(), int32(attempts))
+	if err != nil {
+		d.logger.Error("failed to store dead letter",
+			zap.Strings("to", to),
+			zap.String("subject", email.Subject),
+			zap.NamedError("send_error", sendErr),
+			zap.Error(err),
+		)
+		return
+	}
+	d.logger.Error("email dead-lettered",
+		zap.Int64("dead_letter_id", letter.ID),
+		zap.Strings("to", to),
+		zap.String("subject", email.Subject),
+		zap.Int("attempts", attempts),
+		zap.Error(sendErr),
+	)
+}
+
+// ListDeadLetters returns up to limit dead letters, newest first.
+func (d *MailDelivery) ListDeadLetters(ctx context.Context, limit int32) ([]generated.EmailDeadLetter, error) {
+	return d.deadLetters.List(ctx, limit)
+}
+
+// RetryDeadLetter sends dead letter id once more, skipping the limits, and
+// deletes it once it goes through. A failure is recorded on it and returned
+// wrapped in ErrDeadLetterRetryFailed.
+func (d *MailDelivery) RetryDeadLetter(ctx context.Context, id int64) error {
+	letter, err := d.deadLetters.Get(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get dead letter: %w", err)
+	}
+
+	to := strings.Split(letter.Recipients, ",")
+	if sendErr := d.mailer.Send(ctx, to, &templates.Email{Subject: letter.Subject, HTML: letter.Html}); sendErr != nil {
+		if err := d.deadLetters.RecordAttempt(context.WithoutCancel(ctx), id, sendErr.Error()); err != nil {
+			return fmt.Errorf("failed to record dead letter attempt: %w", err)
+		}
+		return fmt.Errorf("%w: %w", ErrDeadLetterRetryFailed, sendErr)
+	}
+	if _, err := d.deadLetters.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete dead letter: %w", err)
+	}
+	return nil
+}
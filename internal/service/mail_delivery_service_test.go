@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/internal/clock"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/templates"
+)
+
+// scriptedMailer fails sends with errs in turn, then succeeds.
+type scriptedMailer struct {
+	errs  []error
+	sends [][]string
+}
+
+func (m *scriptedMailer) Send(ctx context.Context, to []string, email *templates.Email) error {
+	m.sends = append(m.sends, to)
+	if len(m.errs) == 0 {
+		return nil
+	}
+	err := m.errs[0]
+	m.errs = m.errs[1:]
+	return err
+}
+
+func (m *scriptedMailer) Driver() string {
+	return "scripted"
+}
+
+func TestMailDelivery(t *testing.T) {
+	ctx := context.Background()
+	newDelivery := func(m *scriptedMailer) (*MailDelivery, *repository.MemoryEmailDeadLetterStore, *clock.Fake) {
+		deadLetters := repository.NewMemoryEmailDeadLetterStore()
+		d := NewMailDelivery(m, deadLetters, MailDeliveryConfig{
+			RecipientLimit:  2,
+			RecipientWindow: time.Hour,
+			DedupWindow:     10 * time.Minute,
+			MaxAttempts:     3,
+		}, zap.NewNop())
+		c := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC))
+		d.SetClock(c)
+		return d, deadLetters, c
+	}
+	welcome := &templates.Email{Subject: "Welcome", HTML: "<p>hi</p>"}
+	reset := &templates.Email{Subject: "Reset", HTML: "<p>code 1</p>"}
+
+	t.Run("dedup and limits", func(t *testing.T) {
+		m := &scriptedMailer{}
+		d, _, c := newDelivery(m)
+
+		d.Send(ctx, []string{"jane@example.com"}, welcome)
+		if err := d.Send(ctx, []string{"Jane@Example.com"}, welcome); err != nil {
+			t.Fatalf("duplicate = %v; want it dropped quietly", err)
+		}
+		if len(m.sends) != 1 {
+			t.Fatalf("sends = %d; want the duplicate dropped", len(m.sends))
+		}
+
+		d.Send(ctx, []string{"jane@example.com"}, reset)
+		err := d.Send(ctx, []string{"jane@example.com"}, &templates.Email{Subject: "Reset", HTML: "<p>code 2</p>"})
+		if !errors.Is(err, ErrMailThrottled) {
+			t.Fatalf("third email within the window = %v; want ErrMailThrottled", err)
+		}
+		// Other recipients of the same email still get it.
+		if err := d.Send(ctx, []string{"jane@example.com", "john@example.com"}, &templates.Email{Subject: "Digest"}); err != nil {
+			t.Fatal(err)
+		}
+		if last := m.sends[len(m.sends)-1]; len(last) != 1 || last[0] != "john@example.com" {
+			t.Errorf("sent to %v; want only the recipient under the limit", last)
+		}
+
+		c.Advance(time.Hour)
+		if err := d.Send(ctx, []string{"jane@example.com"}, welcome); err != nil || len(m.sends) != 4 {
+			t.Errorf("after the windows = %v, %d sends; want the email sent", err, len(m.sends))
+		}
+	})
+
+	t.Run("retries then dead letter", func(t *testing.T) {
+		transient := errors.New("connection reset")
+		m := &scriptedMailer{errs: []error{transient, transient, transient}}
+		d, deadLetters, _ := newDelivery(m)
+
+		if err := d.Send(ctx, []string{"jane@example.com"}, welcome); !errors.Is(err, transient) {
+			t.Fatalf("Send = %v; want the last error", err)
+		}
+		letters, _ := deadLetters.List(ctx, 10)
+		if len(m.sends) != 3 || len(letters) != 1 || letters[0].Attempts != 3 {
+			t.Fatalf("%d sends, dead letters %+v; want 3 tries and one dead letter", len(m.sends), letters)
+		}
+
+		// The failed email isn't a duplicate, and a retry that goes
+		// through removes the dead letter.
+		if err := d.RetryDeadLetter(ctx, letters[0].ID); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := deadLetters.Get(ctx, letters[0].ID); err == nil {
+			t.Error("dead letter kept after a successful retry")
+		}
+		if err := d.RetryDeadLetter(ctx, letters[0].ID); !errors.Is(err, ErrDeadLetterNotFound) {
+			t.Errorf("retrying a removed dead letter = %v; want ErrDeadLetterNotFound", err)
+		}
+	})
+
+	t.Run("permanent failure", func(t *testing.T) {
+		rejected := &textproto.Error{Code: 550, Msg: "no such user"}
+		m := &scriptedMailer{errs: []error{rejected, rejected}}
+		d, deadLetters, _ := newDelivery(m)
+
+		d.Send(ctx, []string{"nobody@example.com"}, welcome)
+		letters, _ := deadLetters.List(ctx, 10)
+		if len(m.sends) != 1 || len(letters) != 1 || letters[0].Error != rejected.Error() {
+			t.Fatalf("%d sends, dead letters %+v; want one try and a dead letter", len(m.sends), letters)
+		}
+
+		if err := d.RetryDeadLetter(ctx, letters[0].ID); !errors.Is(err, ErrDeadLetterRetryFailed) {
+			t.Fatalf("retry = %v; want ErrDeadLetterRetryFailed", err)
+		}
+		letter, _ := deadLetters.Get(ctx, letters[0].ID)
+		if letter.Attempts != 2 {
+			t.Errorf("attempts = %d; want the failed retry counted", letter.Attempts)
+		}
+	})
+}
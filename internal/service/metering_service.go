@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/models"
+	"BACKEND/internal/repository"
+)
+
+type meterKey struct {
+	userID   int64
+	apiKeyID int64
+}
+
+// MeteringService meters API requests per user and API key per day, for
+// chargeback and for spotting abuse.
+//
+// Requests are counted in memory by RecordCall and written out in one
+// batch by Flush. Requests counted since the last Flush are lost if the
+// instance stops.
+type MeteringService struct {
+	store repository.APIUsageStore
+	now   func() time.Time
+
+	mu     sync.Mutex
+	counts map[meterKey]int64
+}
+
+func NewMeteringService(store repository.APIUsageStore) *MeteringService {
+	return &MeteringService{
+		store:  store,
+		now:    time.Now,
+		counts: make(map[meterKey]int64),
+	}
+}
+
+// RecordCall counts a request by the user, under the API key it was made
+// with if any.
+func (s *MeteringService) RecordCall(user models.AuthUser) {
+	s.mu.Lock()
+	s.counts[meterKey{userID: user.ID, apiKeyID: user.APIKeyID}]++
+	s.mu.Unlock()
+}
+
+// Flush writes out the requests counted so far into today's (UTC) usage.
+// Counts that fail to be written are kept for the next run.
+func (s *MeteringService) Flush(ctx context.Context) error {
+	day := utcDay(s.now())
+
+	s.mu.Lock()
+	counts := s.counts
+	s.counts = make(map[meterKey]int64)
+	s.mu.Unlock()
+
+	var failed error
+	for key, n := range counts {
+		if failed == nil {
+			failed = s.store.Add(ctx, key.userID, key.apiKeyID, day, n)
+			if failed == nil {
+				delete(counts, key)
+			}
+		}
+	}
+	if failed != nil {
+		s.mu.Lock()
+		for key, n := range counts {
+			s.counts[key] += n
+		}
+		s.mu.Unlock()
+		return fmt.Errorf("failed to write api usage: %w", failed)
+	}
+	return nil
+}
+
+// ForUser returns the user's requests per key and day for the days from and
+// to, inclusive. Requests not flushed yet are not included.
+func (s *MeteringService) ForUser(ctx context.Context, userID int64, from, to time.Time) ([]generated.ListAPIUsageByUserRow, error) {
+	from, to, err := usageRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.ListByUser(ctx, userID, from, to)
+}
+
+// Top returns the limit users and keys that made the most requests over the
+// days from and to, inclusive.
+func (s *MeteringService) Top(ctx context.Context, from, to time.Time, limit int32) ([]generated.TopAPIUsageRow, error) {
+	from, to, err := usageRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return s.store.Top(ctx, from, to, limit)
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/models"
+)
+
+type fakeAPIUsageStore struct {
+	counts map[meterKey]int64
+	days   []time.Time
+	err    error
+}
+
+func (f *fakeAPIUsageStore) Add(ctx context.Context, userID, apiKeyID int64, day time.Time, requests int64) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.counts[meterKey{userID: userID, apiKeyID: apiKeyID}] += requests
+	f.days = append(f.days, day)
+	return nil
+}
+
+func (f *fakeAPIUsageStore) ListByUser(ctx context.Context, userID int64, from, to time.Time) ([]generated.ListAPIUsageByUserRow, error) {
+	return nil, nil
+}
+
+func (f *fakeAPIUsageStore) Top(ctx context.Context, from, to time.Time, limit int32) ([]generated.TopAPIUsageRow, error) {
+	return nil, nil
+}
+
+func TestMeteringFlush(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeAPIUsageStore{counts: make(map[meterKey]int64)}
+	svc := NewMeteringService(store)
+	svc.now = func() time.Time { return time.Date(2026, 3, 4, 23, 30, 0, 0, time.FixedZone("", -2*60*60)) }
+
+	svc.RecordCall(models.AuthUser{ID: 1})
+	svc.RecordCall(models.AuthUser{ID: 1, APIKeyID: 7})
+	svc.RecordCall(models.AuthUser{ID: 1, APIKeyID: 7})
+
+	store.err = errors.New("db down")
+	if err := svc.Flush(ctx); err == nil {
+		t.Fatal("expected an error when usage can't be written")
+	}
+
+	store.err = nil
+	svc.RecordCall(models.AuthUser{ID: 1})
+	if err := svc.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if store.counts[meterKey{1, 0}] != 2 || store.counts[meterKey{1, 7}] != 2 {
+		t.Errorf("requests were lost or double counted: %v", store.counts)
+	}
+	want := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	for _, day := range store.days {
+		if !day.Equal(want) {
+			t.Errorf("expected usage written for %v, got %v", want, day)
+		}
+	}
+
+	if err := svc.Flush(ctx); err != nil {
+		t.Fatalf("flush: %v", err)
+	}
+	if len(store.days) != 2 {
+		t.Errorf("expected 2 writes, got %d", len(store.days))
+	}
+}
+
+func TestMeteringRange(t *testing.T) {
+	svc := NewMeteringService(&fakeAPIUsageStore{})
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	if _, err := svc.ForUser(context.Background(), 1, from, from.AddDate(0, 0, -1)); !errors.Is(err, ErrUsageRange) {
+		t.Errorf("reversed range: expected ErrUsageRange, got %v", err)
+	}
+	if _, err := svc.Top(context.Background(), from, from.AddDate(1, 0, 1), 10); !errors.Is(err, ErrUsageRange) {
+		t.Errorf("range over a year: expected ErrUsageRange, got %v", err)
+	}
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/hooks"
+	"BACKEND/internal/repository"
+)
+
+var (
+	ErrNameReviewNotFound = errors.New("name review not found")
+	ErrNameReviewResolved = errors.New("name review was already decided")
+)
+
+// Name review statuses.
+const (
+	NameReviewPending  = "pending"
+	NameReviewApproved = "approved"
+	NameReviewRejected = "rejected"
+)
+
+type NameVerdict int
+
+const (
+	NameAllowed NameVerdict = iota
+	// NameNeedsReview lets the name through but queues it for a moderator.
+	NameNeedsReview
+	NameBlocked
+)
+
+// NameFilter judges user-supplied names. It returns the term that caused a
+// verdict other than NameAllowed. WordListFilter is the built-in filter; a
+// hosted moderation API can be plugged in through useapi.Options.
+type NameFilter interface {
+	CheckName(ctx context.Context, name string) (NameVerdict, string, error)
+}
+
+// WordListFilter blocks names containing a blocked word on its own and
+// queues names where a blocked word only appears inside a longer word (so
+// "Scunthorpe" reaches a human instead of being refused) or where a review
+// word appears anywhere. Matching ignores case, punctuation, spacing and
+// common digit-for-letter swaps.
+type WordListFilter struct {
+	blocked []string
+	review  []string
+}
+
+func NewWordListFilter(blocked, review []string) *WordListFilter {
+	return &WordListFilter{
+		blocked: normalizeTerms(blocked),
+		review:  normalizeTerms(review),
+	}
+}
+
+func (f *WordListFilter) CheckName(_ context.Context, name string) (NameVerdict, string, error) {
+	words := nameWords(name)
+	joined := strings.Join(words, "")
+
+	for _, term := range f.blocked {
+		for _, word := range words {
+			if word == term {
+				return NameBlocked, term, nil
+			}
+		}
+	}
+	for _, term := range f.blocked {
+		if strings.Contains(joined, term) {
+			return NameNeedsReview, term, nil
+		}
+	}
+	for _, term := range f.review {
+		if strings.Contains(joined, term) {
+			return NameNeedsReview, term, nil
+		}
+	}
+	return NameAllowed, "", nil
+}
+
+// LoadWordList reads one term per line, skipping blank lines and lines
+// starting with #.
+func LoadWordList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var terms []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		terms = append(terms, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read word list %s: %w", path, err)
+	}
+	return terms, nil
+}
+
+var leetReplacer = strings.NewReplacer(
+	"0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s",
+)
+
+// nameWords lowercases s, undoes digit-for-letter swaps and splits it into
+// runs of letters.
+func nameWords(s string) []string {
+	s = leetReplacer.Replace(strings.ToLower(s))
+	return strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+}
+
+func normalizeTerms(terms []string) []string {
+	normalized := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if t := strings.Join(nameWords(term), ""); t != "" {
+			normalized = append(normalized, t)
+		}
+	}
+	return normalized
+}
+
+// ModerationService checks names at signup and update and keeps the queue
+// of names a moderator has to look at.
+type ModerationService struct {
+	filter      NameFilter
+	reviews     repository.NameReviewStore
+	users       repository.UserStore
+	revocations *TokenRevocationService
+	logger      *zap.Logger
+}
+
+func NewModerationService(filter NameFilter, reviews repository.NameReviewStore, users repository.UserStore, revocations *TokenRevocationService, logger *zap.Logger) *ModerationService {
+	return &ModerationService{
+		filter:      filter,
+		reviews:     reviews,
+		users:       users,
+		revocations: revocations,
+		logger:      logger,
+	}
+}
+
+// CheckName runs the filter. A filter error queues the name for review
+// rather than failing the signup.
+func (s *ModerationService) CheckName(ctx context.Context, name string) (NameVerdict, string) {
+	verdict, term, err := s.filter.CheckName(ctx, name)
+	if err != nil {
+		s.logger.Warn("name filter failed, queueing name for review", zap.Error(err))
+		return NameNeedsReview, "filter error"
+	}
+	return verdict, term
+}
+
+func (s *ModerationService) queue(ctx context.Context, userID int64, name, term string) {
+	if _, err := s.reviews.Create(ctx, userID, name, term); err != nil {
+		s.logger.Error("failed to queue name for review", zap.Int64("user_id", userID), zap.Error(err))
+		return
+	}
+	s.logger.Info("name queued for review", zap.Int64("user_id", userID), zap.String("matched", term))
+}
+
+func (s *ModerationService) ListReviews(ctx context.Context, status string, limit int32) ([]generated.NameReview, error) {
+	return s.reviews.List(ctx, status, limit)
+}
+
+func (s *ModerationService) GetReview(ctx context.Context, id int64) (generated.NameReview, error) {
+	review, err := s.reviews.Get(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return generated.NameReview{}, ErrNameReviewNotFound
+	}
+	return review, err
+}
+
+// Approve keeps the name.
+func (s *ModerationService) Approve(ctx context.Context, id, reviewerID int64) (generated.NameReview, error) {
+	return s.resolve(ctx, id, NameReviewApproved, reviewerID)
+}
+
+// Reject deactivates the user and logs them out; they can be reactivated
+// once they have picked another name.
+func (s *ModerationService) Reject(ctx context.Context, id, reviewerID int64) (generated.NameReview, error) {
+	review, err := s.resolve(ctx, id, NameReviewRejected, reviewerID)
+	if err != nil {
+		return review, err
+	}
+	if _, err := s.users.SetActive(ctx, review.UserID, false); err != nil {
+		return review, fmt.Errorf("failed to deactivate user: %w", err)
+	}
+	if s.revocations != nil {
+		if err := s.revocations.RevokeUser(ctx, review.UserID); err != nil {
+			return review, err
+		}
+	}
+	return review, nil
+}
+
+func (s *ModerationService) resolve(ctx context.Context, id int64, status string, reviewerID int64) (generated.NameReview, error) {
+	ok, err := s.reviews.Resolve(ctx, id, status, reviewerID)
+	if err != nil {
+		return generated.NameReview{}, fmt.Errorf("failed to resolve name review: %w", err)
+	}
+	if !ok {
+		if _, err := s.GetReview(ctx, id); err != nil {
+			return generated.NameReview{}, err
+		}
+		return generated.NameReview{}, ErrNameReviewResolved
+	}
+	return s.GetReview(ctx, id)
+}
+
+// moderatedUserStore checks names on every write to the wrapped store. Blocked
+// names are refused the same way a hook veto is; borderline names are saved
+// and queued.
+type moderatedUserStore struct {
+	repository.UserStore
+	moderation *ModerationService
+}
+
+// WithModeration wraps store so names are checked on create and update. Wrap
+// it before repository.WithHooks so the check sees names edited by hooks.
+func WithModeration(store repository.UserStore, moderation *ModerationService) repository.UserStore {
+	if moderation == nil {
+		return store
+	}
+	return &moderatedUserStore{UserStore: store, moderation: moderation}
+}
+
+func (s *moderatedUserStore) check(ctx context.Context, name string) (string, error) {
+	verdict, term := s.moderation.CheckName(ctx, name)
+	switch verdict {
+	case NameBlocked:
+		return "", hooks.Reject("name is not allowed")
+	case NameNeedsReview:
+		return term, nil
+	}
+	return "", nil
+}
+
+func (s *moderatedUserStore) Create(ctx context.Context, name, source string, dob time.Time) (generated.CreateUserRow, error) {
+	term, err := s.check(ctx, name)
+	if err != nil {
+		return generated.CreateUserRow{}, err
+	}
+	user, err := s.UserStore.Create(ctx, name, source, dob)
+	if err == nil && term != "" {
+		s.moderation.queue(ctx, user.ID, user.Name, term)
+	}
+	return user, err
+}
+
+func (s *moderatedUserStore) CreateWithAuth(ctx context.Context, name, email, passwordHash, role, source string, dob time.Time) (generated.CreateUserRow, error) {
+	term, err := s.check(ctx, name)
+	if err != nil {
+		return generated.CreateUserRow{}, err
+	}
+	user, err := s.UserStore.CreateWithAuth(ctx, name, email, passwordHash, role, source, dob)
+	if err == nil && term != "" {
+		s.moderation.queue(ctx, user.ID, user.Name, term)
+	}
+	return user, err
+}
+
+func (s *moderatedUserStore) CreateServiceAccount(ctx context.Context, name, email, passwordHash, role string) (generated.CreateServiceAccountRow, error) {
+	term, err := s.check(ctx, name)
+	if err != nil {
+		return generated.CreateServiceAccountRow{}, err
+	}
+	user, err := s.UserStore.CreateServiceAccount(ctx, name, email, passwordHash, role)
+	if err == nil && term != "" {
+		s.moderation.queue(ctx, user.ID, user.Name, term)
+	}
+	return user, err
+}
+
+func (s *moderatedUserStore) Update(ctx context.Context, id int64, name string, dob time.Time) (generated.UpdateUserRow, error) {
+	term, err := s.check(ctx, name)
+	if err != nil {
+		return generated.UpdateUserRow{}, err
+	}
+	user, err := s.UserStore.Update(ctx, id, name, dob)
+	if err == nil && term != "" {
+		s.moderation.queue(ctx, user.ID, user.Name, term)
+	}
+	return user, err
+}
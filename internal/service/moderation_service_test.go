@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/hooks"
+	"BACKEND/internal/repository"
+)
+
+type fakeModerationUserStore struct {
+	repository.UserStore
+	nextID      int64
+	deactivated []int64
+}
+
+func (f *fakeModerationUserStore) Create(ctx context.Context, name, source string, dob time.Time) (generated.CreateUserRow, error) {
+	f.nextID++
+	return generated.CreateUserRow{ID: f.nextID, Name: name}, nil
+}
+
+func (f *fakeModerationUserStore) Update(ctx context.Context, id int64, name string, dob time.Time) (generated.UpdateUserRow, error) {
+	return generated.UpdateUserRow{ID: id, Name: name}, nil
+}
+
+func (f *fakeModerationUserStore) SetActive(ctx context.Context, id int64, active bool) (generated.SetUserActiveRow, error) {
+	if !active {
+		f.deactivated = append(f.deactivated, id)
+	}
+	return generated.SetUserActiveRow{ID: id, Active: active}, nil
+}
+
+type fakeNameReviewStore struct {
+	reviews []generated.NameReview
+}
+
+func (f *fakeNameReviewStore) Create(ctx context.Context, userID int64, name, matched string) (generated.NameReview, error) {
+	review := generated.NameReview{ID: int64(len(f.reviews) + 1), UserID: userID, Name: name, Matched: matched, Status: NameReviewPending}
+	f.reviews = append(f.reviews, review)
+	return review, nil
+}
+
+func (f *fakeNameReviewStore) Get(ctx context.Context, id int64) (generated.NameReview, error) {
+	if id < 1 || int(id) > len(f.reviews) {
+		return generated.NameReview{}, pgx.ErrNoRows
+	}
+	return f.reviews[id-1], nil
+}
+
+func (f *fakeNameReviewStore) List(ctx context.Context, status string, limit int32) ([]generated.NameReview, error) {
+	var out []generated.NameReview
+	for _, r := range f.reviews {
+		if r.Status == status && int32(len(out)) < limit {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeNameReviewStore) Resolve(ctx context.Context, id int64, status string, reviewerID int64) (bool, error) {
+	if id < 1 || int(id) > len(f.reviews) || f.reviews[id-1].Status != NameReviewPending {
+		return false, nil
+	}
+	f.reviews[id-1].Status = status
+	f.reviews[id-1].ReviewedBy.Int64, f.reviews[id-1].ReviewedBy.Valid = reviewerID, true
+	return true, nil
+}
+
+func TestWordListFilter(t *testing.T) {
+	filter := NewWordListFilter([]string{"cunt", "Bad Word"}, []string{"admin"})
+
+	tests := []struct {
+		name string
+		want NameVerdict
+	}{
+		{"Jane Doe", NameAllowed},
+		{"cunt", NameBlocked},
+		{"Mr CUNT", NameBlocked},
+		{"Mr C-U-N-T", NameNeedsReview},
+		{"Scunthorpe United", NameNeedsReview},
+		{"badword", NameBlocked},
+		{"b4dw0rd", NameBlocked},
+		{"Site Admin", NameNeedsReview},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, term, err := filter.CheckName(context.Background(), tt.name)
+			if err != nil {
+				t.Fatalf("CheckName: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CheckName(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+			if (got == NameAllowed) != (term == "") {
+				t.Errorf("CheckName(%q) matched %q with verdict %v", tt.name, term, got)
+			}
+		})
+	}
+}
+
+func TestWithModeration(t *testing.T) {
+	users := &fakeModerationUserStore{}
+	reviews := &fakeNameReviewStore{}
+	svc := NewModerationService(NewWordListFilter([]string{"badword"}, []string{"admin"}), reviews, users, nil, zap.NewNop())
+	store := WithModeration(users, svc)
+	ctx := context.Background()
+
+	_, err := store.Create(ctx, "BadWord", SignupSourceAPI, time.Now())
+	var rejected *hooks.RejectedError
+	if !errors.As(err, &rejected) {
+		t.Fatalf("Create with blocked name: err = %v, want a rejection", err)
+	}
+	if users.nextID != 0 {
+		t.Error("blocked name was saved")
+	}
+
+	user, err := store.Create(ctx, "Jane Doe", SignupSourceAPI, time.Now())
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if len(reviews.reviews) != 0 {
+		t.Error("allowed name was queued")
+	}
+
+	if _, err := store.Update(ctx, user.ID, "Jane the Admin", time.Now()); err != nil {
+		t.Fatalf("Update with borderline name: %v", err)
+	}
+	if len(reviews.reviews) != 1 || reviews.reviews[0].UserID != user.ID || reviews.reviews[0].Matched != "admin" {
+		t.Fatalf("reviews = %+v, want one for user %d matching admin", reviews.reviews, user.ID)
+	}
+}
+
+func TestModerationReview(t *testing.T) {
+	users := &fakeModerationUserStore{}
+	reviews := &fakeNameReviewStore{}
+	revocations := NewTokenRevocationService(&fakeRevocationStore{revoked: map[int64]time.Time{}})
+	svc := NewModerationService(NewWordListFilter(nil, []string{"admin"}), reviews, users, revocations, zap.NewNop())
+	ctx := context.Background()
+
+	reviews.Create(ctx, 4, "Admin Alice", "admin")
+	reviews.Create(ctx, 5, "Admin Bob", "admin")
+
+	approved, err := svc.Approve(ctx, 1, 9)
+	if err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if approved.Status != NameReviewApproved || approved.ReviewedBy.Int64 != 9 {
+		t.Errorf("approved review = %+v", approved)
+	}
+	if len(users.deactivated) != 0 {
+		t.Error("approving deactivated the user")
+	}
+	if _, err := svc.Reject(ctx, 1, 9); !errors.Is(err, ErrNameReviewResolved) {
+		t.Errorf("Reject after Approve: err = %v, want ErrNameReviewResolved", err)
+	}
+
+	if _, err := svc.Reject(ctx, 2, 9); err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+	if len(users.deactivated) != 1 || users.deactivated[0] != 5 {
+		t.Errorf("deactivated = %v, want [5]", users.deactivated)
+	}
+	if !revocations.IsRevoked(5, time.Now().Add(-time.Minute)) {
+		t.Error("rejected user's tokens are still valid")
+	}
+
+	if _, err := svc.Approve(ctx, 3, 9); !errors.Is(err, ErrNameReviewNotFound) {
+		t.Errorf("Approve missing review: err = %v, want ErrNameReviewNotFound", err)
+	}
+
+	pending, err := svc.ListReviews(ctx, NameReviewPending, 10)
+	if err != nil || len(pending) != 0 {
+		t.Errorf("pending reviews = %v, %v; want none", pending, err)
+	}
+}
@@ -0,0 +1,481 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/hooks"
+	"BACKEND/internal/mailer"
+	"BACKEND/internal/models"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/templates"
+)
+
+var (
+	ErrNotificationRuleNotFound = errors.New("notification rule not found")
+	ErrNotificationNotFound     = errors.New("notification not found")
+	ErrInvalidNotificationRule  = errors.New("invalid notification rule")
+	ErrUnknownNotificationEvent = errors.New("unknown notification event type")
+)
+
+// Notification channels.
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelInApp   = "in_app"
+)
+
+// Who email and in-app rules notify: the user the event is about, or every
+// active admin.
+const (
+	AudienceUser   = "user"
+	AudienceAdmins = "admins"
+)
+
+// NotificationEventSecurityAlert is the event a SecurityAlert is routed as.
+const NotificationEventSecurityAlert = "security_alert"
+
+const defaultNotificationTemplate = "notification"
+
+// NotificationEvents are the event types rules can be written for.
+var NotificationEvents = []string{
+	SecurityEventAdminLogin,
+	SecurityEventRoleChanged,
+	SecurityEventUserDeactivated,
+	SecurityEventUserActivated,
+	SecurityEventUserDeleted,
+	SecurityEventUserRestored,
+	SecurityEventForceLogout,
+	SecurityEventForcePasswordReset,
+	SecurityEventAccountUnlocked,
+	NotificationEventSecurityAlert,
+}
+
+// NotificationEvent is something that happened that rules may notify
+// about. UserID is the user it is about, or 0.
+type NotificationEvent struct {
+	Type    string
+	UserID  int64
+	Details map[string]string
+}
+
+type notificationRecipient struct {
+	id    int64
+	name  string
+	email string
+}
+
+// NotificationService routes events to channels by the rules admins keep
+// in the database, skipping users who opted out of an event on a channel.
+// Email and in-app messages are rendered from an email template with the
+// recipient's Name, the Event type, the UserName it is about and its
+// Details; in-app notifications keep the subject as their title.
+type NotificationService struct {
+	store    repository.NotificationStore
+	users    repository.UserStore
+	mailer   mailer.Mailer
+	renderer *templates.Renderer
+	secret   string
+	client   *http.Client
+	logger   *zap.Logger
+}
+
+var _ SecurityAlerter = (*NotificationService)(nil)
+
+// NewNotificationService signs webhook notifications with secret, like
+// hooks, when it is set.
+func NewNotificationService(store repository.NotificationStore, users repository.UserStore, m mailer.Mailer, renderer *templates.Renderer, secret string, timeout time.Duration, logger *zap.Logger) *NotificationService {
+	return &NotificationService{
+		store:    store,
+		users:    users,
+		mailer:   m,
+		renderer: renderer,
+		secret:   secret,
+		client:   &http.Client{Timeout: timeout},
+		logger:   logger,
+	}
+}
+
+// Notify sends e on every channel its rules route it to. A failed delivery
+// doesn't stop the others; their errors are returned together.
+func (s *NotificationService) Notify(ctx context.Context, e NotificationEvent) error {
+	rules, err := s.store.ListRulesForEvent(ctx, e.Type)
+	if err != nil {
+		return fmt.Errorf("failed to load notification rules: %w", err)
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	var subject *generated.GetUserByIDRow
+	if e.UserID != 0 {
+		if user, err := s.users.GetByID(ctx, e.UserID); err == nil {
+			subject = &user
+		}
+	}
+
+	var errs []error
+	for _, rule := range rules {
+		if rule.Channel == ChannelWebhook {
+			if err := s.postWebhook(ctx, rule, e, subject); err != nil {
+				errs = append(errs, fmt.Errorf("rule %d: %w", rule.ID, err))
+			}
+			continue
+		}
+		recipients, err := s.recipients(ctx, rule.Audience, subject)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule %d: %w", rule.ID, err))
+			continue
+		}
+		for _, r := range recipients {
+			if err := s.deliver(ctx, rule, e, subject, r); err != nil {
+				errs = append(errs, fmt.Errorf("rule %d, user %d: %w", rule.ID, r.id, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Publish notifies about e in the background, outliving the request that
+// raised it.
+func (s *NotificationService) Publish(ctx context.Context, e NotificationEvent) {
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+		if err := s.Notify(ctx, e); err != nil {
+			s.logger.Error("failed to send notifications", zap.String("event", e.Type), zap.Error(err))
+		}
+	}()
+}
+
+// Alert routes alert as a security_alert event.
+func (s *NotificationService) Alert(ctx context.Context, alert SecurityAlert) error {
+	return s.Notify(ctx, NotificationEvent{
+		Type: NotificationEventSecurityAlert,
+		Details: map[string]string{
+			"rule":        alert.Rule,
+			"key":         alert.Key,
+			"description": alert.Description,
+			"failures":    strconv.Itoa(alert.Failures),
+			"window":      alert.Window,
+		},
+	})
+}
+
+func (s *NotificationService) recipients(ctx context.Context, audience string, subject *generated.GetUserByIDRow) ([]notificationRecipient, error) {
+	switch audience {
+	case AudienceUser:
+		// A deleted or deactivated user isn't told about it here.
+		if subject == nil || !subject.Active {
+			return nil, nil
+		}
+		return []notificationRecipient{{id: subject.ID, name: subject.Name, email: subject.Email}}, nil
+	case AudienceAdmins:
+		admins, err := s.users.ListActiveAdmins(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list admins: %w", err)
+		}
+		recipients := make([]notificationRecipient, len(admins))
+		for i, a := range admins {
+			recipients[i] = notificationRecipient{id: a.ID, name: a.Name, email: a.Email}
+		}
+		return recipients, nil
+	default:
+		return nil, fmt.Errorf("unknown audience %q", audience)
+	}
+}
+
+func (s *NotificationService) deliver(ctx context.Context, rule generated.NotificationRule, e NotificationEvent, subject *generated.GetUserByIDRow, r notificationRecipient) error {
+	wanted, err := s.wants(ctx, r.id, e.Type, rule.Channel)
+	if err != nil || !wanted {
+		return err
+	}
+
+	data := map[string]interface{}{
+		"Name":    r.name,
+		"Event":   e.Type,
+		"Details": e.Details,
+	}
+	if subject != nil {
+		data["UserName"] = subject.Name
+	}
+	msg, err := s.renderer.Render(ruleTemplate(rule), "", data)
+	if err != nil {
+		return fmt.Errorf("failed to render notification: %w", err)
+	}
+
+	if rule.Channel == ChannelEmail {
+		return s.mailer.Send(ctx, []string{r.email}, msg)
+	}
+	details := ""
+	if len(e.Details) > 0 {
+		b, err := json.Marshal(e.Details)
+		if err != nil {
+			return err
+		}
+		details = string(b)
+	}
+	return s.store.Create(ctx, r.id, e.Type, msg.Subject, details)
+}
+
+func (s *NotificationService) wants(ctx context.Context, userID int64, eventType, channel string) (bool, error) {
+	prefs, err := s.store.ListPreferences(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to load notification preferences: %w", err)
+	}
+	for _, p := range prefs {
+		if p.EventType == eventType && p.Channel == channel {
+			return p.Enabled, nil
+		}
+	}
+	return true, nil
+}
+
+func (s *NotificationService) postWebhook(ctx context.Context, rule generated.NotificationRule, e NotificationEvent, subject *generated.GetUserByIDRow) error {
+	payload := struct {
+		Event      string            `json:"event"`
+		Type       string            `json:"type"`
+		UserID     string            `json:"user_id,omitempty"`
+		Details    map[string]string `json:"details,omitempty"`
+		OccurredAt time.Time         `json:"occurred_at"`
+	}{"notification", e.Type, "", e.Details, time.Now().UTC()}
+	if subject != nil && subject.PublicID.Valid {
+		payload.UserID = subject.PublicID.String()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rule.WebhookUrl, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.secret != "" {
+		req.Header.Set(hooks.SignatureHeader, hooks.Sign(s.secret, body))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("notification webhook: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("notification webhook: endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func ruleTemplate(rule generated.NotificationRule) string {
+	if rule.Template == "" {
+		return defaultNotificationTemplate
+	}
+	return rule.Template
+}
+
+// ListRules returns every rule, enabled or not.
+func (s *NotificationService) ListRules(ctx context.Context) ([]models.NotificationRuleResponse, error) {
+	rows, err := s.store.ListRules(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]models.NotificationRuleResponse, len(rows))
+	for i, row := range rows {
+		rules[i] = toNotificationRuleResponse(row)
+	}
+	return rules, nil
+}
+
+func (s *NotificationService) CreateRule(ctx context.Context, req models.NotificationRuleRequest) (models.NotificationRuleResponse, error) {
+	rule, err := s.ruleFromRequest(req)
+	if err != nil {
+		return models.NotificationRuleResponse{}, err
+	}
+	created, err := s.store.CreateRule(ctx, rule)
+	if err != nil {
+		return models.NotificationRuleResponse{}, err
+	}
+	return toNotificationRuleResponse(created), nil
+}
+
+func (s *NotificationService) UpdateRule(ctx context.Context, id int64, req models.NotificationRuleRequest) (models.NotificationRuleResponse, error) {
+	rule, err := s.ruleFromRequest(req)
+	if err != nil {
+		return models.NotificationRuleResponse{}, err
+	}
+	rule.ID = id
+	updated, err := s.store.UpdateRule(ctx, rule)
+	if err != nil {
+		return models.NotificationRuleResponse{}, err
+	}
+	if !updated {
+		return models.NotificationRuleResponse{}, ErrNotificationRuleNotFound
+	}
+	row, err := s.store.GetRule(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.NotificationRuleResponse{}, ErrNotificationRuleNotFound
+	}
+	if err != nil {
+		return models.NotificationRuleResponse{}, err
+	}
+	return toNotificationRuleResponse(row), nil
+}
+
+func (s *NotificationService) DeleteRule(ctx context.Context, id int64) error {
+	deleted, err := s.store.DeleteRule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrNotificationRuleNotFound
+	}
+	return nil
+}
+
+// ruleFromRequest checks what the request's validation tags can't: that
+// the event is known, the channel has what it needs to deliver and the
+// template exists. Its errors wrap ErrInvalidNotificationRule.
+func (s *NotificationService) ruleFromRequest(req models.NotificationRuleRequest) (generated.NotificationRule, error) {
+	invalid := func(msg string) error {
+		return fmt.Errorf("%w: %s", ErrInvalidNotificationRule, msg)
+	}
+	if !slices.Contains(NotificationEvents, req.EventType) {
+		return generated.NotificationRule{}, invalid(fmt.Sprintf("unknown event type %q", req.EventType))
+	}
+
+	rule := generated.NotificationRule{
+		EventType: req.EventType,
+		Channel:   req.Channel,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+	}
+	if req.Channel == ChannelWebhook {
+		if req.WebhookURL == "" {
+			return generated.NotificationRule{}, invalid("webhook rules need a webhook_url")
+		}
+		rule.WebhookUrl = req.WebhookURL
+		return rule, nil
+	}
+
+	switch {
+	case req.Audience == "":
+		return generated.NotificationRule{}, invalid(req.Channel + " rules need an audience")
+	case req.Audience == AudienceUser && req.EventType == NotificationEventSecurityAlert:
+		return generated.NotificationRule{}, invalid("security alerts aren't about a user; use the admins audience")
+	}
+	rule.Audience = req.Audience
+	rule.Template = req.Template
+	if rule.Template == "" {
+		rule.Template = defaultNotificationTemplate
+	}
+	known := false
+	for _, t := range s.renderer.List() {
+		known = known || t.Name == rule.Template
+	}
+	if !known {
+		return generated.NotificationRule{}, invalid(fmt.Sprintf("unknown template %q", rule.Template))
+	}
+	return rule, nil
+}
+
+func toNotificationRuleResponse(rule generated.NotificationRule) models.NotificationRuleResponse {
+	return models.NotificationRuleResponse{
+		ID:         rule.ID,
+		EventType:  rule.EventType,
+		Channel:    rule.Channel,
+		Audience:   rule.Audience,
+		Template:   rule.Template,
+		WebhookURL: rule.WebhookUrl,
+		Enabled:    rule.Enabled,
+		CreatedAt:  rule.CreatedAt.Time,
+		UpdatedAt:  rule.UpdatedAt.Time,
+	}
+}
+
+// Preferences returns whether userID receives each event type by email and
+// in-app.
+func (s *NotificationService) Preferences(ctx context.Context, userID int64) ([]models.NotificationPreference, error) {
+	rows, err := s.store.ListPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	optedOut := make(map[[2]string]bool)
+	for _, row := range rows {
+		if !row.Enabled {
+			optedOut[[2]string{row.EventType, row.Channel}] = true
+		}
+	}
+
+	prefs := make([]models.NotificationPreference, 0, 2*len(NotificationEvents))
+	for _, event := range NotificationEvents {
+		for _, channel := range []string{ChannelEmail, ChannelInApp} {
+			enabled := !optedOut[[2]string{event, channel}]
+			prefs = append(prefs, models.NotificationPreference{EventType: event, Channel: channel, Enabled: &enabled})
+		}
+	}
+	return prefs, nil
+}
+
+func (s *NotificationService) SetPreference(ctx context.Context, userID int64, pref models.NotificationPreference) error {
+	if !slices.Contains(NotificationEvents, pref.EventType) {
+		return fmt.Errorf("%w %q", ErrUnknownNotificationEvent, pref.EventType)
+	}
+	return s.store.SetPreference(ctx, generated.NotificationPreference{
+		UserID:    userID,
+		EventType: pref.EventType,
+		Channel:   pref.Channel,
+		Enabled:   *pref.Enabled,
+	})
+}
+
+// Inbox returns up to limit of userID's in-app notifications, newest
+// first.
+func (s *NotificationService) Inbox(ctx context.Context, userID int64, limit int) ([]models.NotificationResponse, error) {
+	rows, err := s.store.List(ctx, userID, int32(limit))
+	if err != nil {
+		return nil, err
+	}
+	notifications := make([]models.NotificationResponse, len(rows))
+	for i, row := range rows {
+		notifications[i] = models.NotificationResponse{
+			ID:        row.ID,
+			EventType: row.EventType,
+			Title:     row.Title,
+			CreatedAt: row.CreatedAt.Time,
+		}
+		if row.ReadAt.Valid {
+			readAt := row.ReadAt.Time
+			notifications[i].ReadAt = &readAt
+		}
+		if row.Details != "" {
+			// Details were written by deliver, so they are a JSON object.
+			_ = json.Unmarshal([]byte(row.Details), &notifications[i].Details)
+		}
+	}
+	return notifications, nil
+}
+
+// MarkRead marks notification id read, returning ErrNotificationNotFound
+// unless it is userID's and unread.
+func (s *NotificationService) MarkRead(ctx context.Context, userID, id int64) error {
+	marked, err := s.store.MarkRead(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+	if !marked {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
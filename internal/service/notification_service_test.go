@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/hooks"
+	"BACKEND/internal/models"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/templates"
+)
+
+func TestNotificationRules(t *testing.T) {
+	ctx := context.Background()
+	users := repository.NewMemoryUserStore()
+	dob, _ := ParseDob("1990-01-01")
+	jane, err := users.CreateWithAuth(ctx, "Jane", "jane@example.com", "hash", "user", "api", dob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	admin, err := users.CreateWithAuth(ctx, "Ada", "ada@example.com", "hash", "admin", "api", dob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	renderer, err := templates.NewRenderer(templates.Branding{ProductName: "Test"}, "en")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hooked := make(chan map[string]interface{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get(hooks.SignatureHeader) != hooks.Sign("secret", body) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var payload map[string]interface{}
+		json.Unmarshal(body, &payload)
+		hooked <- payload
+	}))
+	defer server.Close()
+
+	store := repository.NewMemoryNotificationStore()
+	m := &scriptedMailer{}
+	svc := NewNotificationService(store, users, m, renderer, "secret", time.Second, zap.NewNop())
+
+	for _, tc := range []struct {
+		name string
+		req  models.NotificationRuleRequest
+	}{
+		{"unknown event", models.NotificationRuleRequest{EventType: "user_renamed", Channel: ChannelEmail, Audience: AudienceUser}},
+		{"webhook without a URL", models.NotificationRuleRequest{EventType: SecurityEventRoleChanged, Channel: ChannelWebhook}},
+		{"email without an audience", models.NotificationRuleRequest{EventType: SecurityEventRoleChanged, Channel: ChannelEmail}},
+		{"alert to a user", models.NotificationRuleRequest{EventType: NotificationEventSecurityAlert, Channel: ChannelInApp, Audience: AudienceUser}},
+		{"unknown template", models.NotificationRuleRequest{EventType: SecurityEventRoleChanged, Channel: ChannelEmail, Audience: AudienceUser, Template: "missing"}},
+	} {
+		if _, err := svc.CreateRule(ctx, tc.req); !errors.Is(err, ErrInvalidNotificationRule) {
+			t.Errorf("%s: CreateRule = %v; want ErrInvalidNotificationRule", tc.name, err)
+		}
+	}
+
+	for _, req := range []models.NotificationRuleRequest{
+		{EventType: SecurityEventRoleChanged, Channel: ChannelEmail, Audience: AudienceUser},
+		{EventType: SecurityEventRoleChanged, Channel: ChannelInApp, Audience: AudienceAdmins},
+		{EventType: SecurityEventRoleChanged, Channel: ChannelWebhook, WebhookURL: server.URL},
+	} {
+		if _, err := svc.CreateRule(ctx, req); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	changed := NotificationEvent{Type: SecurityEventRoleChanged, UserID: jane.ID, Details: map[string]string{"from": "user", "to": "moderator"}}
+	if err := svc.Notify(ctx, changed); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.sends) != 1 || m.sends[0][0] != jane.Email {
+		t.Errorf("emailed %v; want only Jane", m.sends)
+	}
+	select {
+	case payload := <-hooked:
+		if payload["type"] != SecurityEventRoleChanged {
+			t.Errorf("webhook payload = %v", payload)
+		}
+	default:
+		t.Error("webhook not called")
+	}
+	inbox, err := svc.Inbox(ctx, admin.ID, 10)
+	if err != nil || len(inbox) != 1 || inbox[0].Title != "[Test] Role changed for Jane" || inbox[0].Details["to"] != "moderator" {
+		t.Fatalf("admin inbox = %+v, %v", inbox, err)
+	}
+
+	// Opting out of email keeps the other channels going.
+	off := false
+	if err := svc.SetPreference(ctx, jane.ID, models.NotificationPreference{EventType: SecurityEventRoleChanged, Channel: ChannelEmail, Enabled: &off}); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.Notify(ctx, changed); err != nil {
+		t.Fatal(err)
+	}
+	<-hooked
+	if len(m.sends) != 1 {
+		t.Errorf("emailed %d times; want Jane's opt-out respected", len(m.sends))
+	}
+
+	if err := svc.MarkRead(ctx, jane.ID, inbox[0].ID); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("marking someone else's notification = %v; want ErrNotificationNotFound", err)
+	}
+	if err := svc.MarkRead(ctx, admin.ID, inbox[0].ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.MarkRead(ctx, admin.ID, inbox[0].ID); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("marking twice = %v; want ErrNotificationNotFound", err)
+	}
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/models"
+	"BACKEND/internal/repository"
+)
+
+// maxUsageDays bounds the ranges usage is returned for, about a year of
+// daily rows.
+const maxUsageDays = 366
+
+var (
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrUsageRange           = errors.New("usage range is invalid")
+)
+
+// OrganizationUsage is an organization's usage over a range of days.
+// APICalls is the total over the range; Seats and StorageBytes are levels
+// rather than flows, so they are the latest day's figures.
+type OrganizationUsage struct {
+	Organization generated.Organization
+	Days         []generated.OrganizationUsage
+	Seats        int64
+	APICalls     int64
+	StorageBytes int64
+}
+
+// OrganizationService groups users into organizations and meters each
+// organization's usage per day: seats (its active, non-service users),
+// API calls made by its members, and the storage their rows take up.
+//
+// API calls are counted in memory by RecordCall and written out by
+// Aggregate, which also recomputes seats and storage. Calls counted since
+// the last Aggregate are lost if the instance stops.
+type OrganizationService struct {
+	store repository.OrganizationStore
+	seats SeatChecker
+	now   func() time.Time
+
+	mu    sync.Mutex
+	calls map[int64]int64
+
+	// Members are added one at a time when seats are checked, so two
+	// additions can't both take the last free seat.
+	memberMu sync.Mutex
+}
+
+func NewOrganizationService(store repository.OrganizationStore) *OrganizationService {
+	return &OrganizationService{
+		store: store,
+		now:   time.Now,
+		calls: make(map[int64]int64),
+	}
+}
+
+// SetSeatChecker makes SetMember check there is a seat for the user first.
+func (s *OrganizationService) SetSeatChecker(seats SeatChecker) {
+	s.seats = seats
+}
+
+func (s *OrganizationService) Create(ctx context.Context, name string) (generated.Organization, error) {
+	return s.store.Create(ctx, name)
+}
+
+func (s *OrganizationService) Get(ctx context.Context, id int64) (generated.Organization, error) {
+	org, err := s.store.Get(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return generated.Organization{}, ErrOrganizationNotFound
+	}
+	return org, err
+}
+
+func (s *OrganizationService) List(ctx context.Context) ([]generated.Organization, error) {
+	return s.store.List(ctx)
+}
+
+// SetMember moves the user into the organization, out of any they were in.
+func (s *OrganizationService) SetMember(ctx context.Context, userID, orgID int64) error {
+	if s.seats != nil {
+		s.memberMu.Lock()
+		defer s.memberMu.Unlock()
+		if err := s.seats.CheckSeat(ctx, orgID, userID); err != nil {
+			return err
+		}
+	}
+
+	err := s.store.SetMember(ctx, userID, orgID)
+	if errors.Is(err, repository.ErrForeignKeyViolation) {
+		return ErrOrganizationNotFound
+	}
+	return err
+}
+
+// RemoveMember reports false when the user was in no organization.
+func (s *OrganizationService) RemoveMember(ctx context.Context, userID int64) (bool, error) {
+	return s.store.RemoveMember(ctx, userID)
+}
+
+// RecordCall counts an API call by the user towards their organization's
+// usage. Users in no organization are dropped when the counts are written.
+func (s *OrganizationService) RecordCall(user models.AuthUser) {
+	s.mu.Lock()
+	s.calls[user.ID]++
+	s.mu.Unlock()
+}
+
+// Aggregate writes out the API calls counted so far into today's (UTC)
+// usage, then recomputes today's seats and storage. Counts that fail to be
+// written are kept for the next run.
+func (s *OrganizationService) Aggregate(ctx context.Context) error {
+	day := utcDay(s.now())
+
+	s.mu.Lock()
+	calls := s.calls
+	s.calls = make(map[int64]int64)
+	s.mu.Unlock()
+
+	var failed error
+	for userID, n := range calls {
+		if failed == nil {
+			failed = s.store.AddAPICalls(ctx, userID, day, n)
+			if failed == nil {
+				delete(calls, userID)
+			}
+		}
+	}
+	if failed != nil {
+		s.mu.Lock()
+		for userID, n := range calls {
+			s.calls[userID] += n
+		}
+		s.mu.Unlock()
+		return fmt.Errorf("failed to add api calls: %w", failed)
+	}
+
+	if err := s.store.AggregateUsage(ctx, day); err != nil {
+		return fmt.Errorf("failed to aggregate usage: %w", err)
+	}
+	return nil
+}
+
+// Usage returns the organization's usage for the days from and to,
+// inclusive.
+func (s *OrganizationService) Usage(ctx context.Context, orgID int64, from, to time.Time) (OrganizationUsage, error) {
+	from, to, err := usageRange(from, to)
+	if err != nil {
+		return OrganizationUsage{}, err
+	}
+
+	org, err := s.Get(ctx, orgID)
+	if err != nil {
+		return OrganizationUsage{}, err
+	}
+
+	days, err := s.store.Usage(ctx, orgID, from, to)
+	if err != nil {
+		return OrganizationUsage{}, err
+	}
+
+	usage := OrganizationUsage{Organization: org, Days: days}
+	for _, day := range days {
+		usage.APICalls += day.ApiCalls
+	}
+	if len(days) > 0 {
+		usage.Seats = days[len(days)-1].Seats
+		usage.StorageBytes = days[len(days)-1].StorageBytes
+	}
+	return usage, nil
+}
+
+// usageRange truncates from and to to UTC days and checks they make a
+// range usage can be returned for.
+func usageRange(from, to time.Time) (time.Time, time.Time, error) {
+	from, to = utcDay(from), utcDay(to)
+	if to.Before(from) || to.Sub(from) >= maxUsageDays*24*time.Hour {
+		return time.Time{}, time.Time{}, ErrUsageRange
+	}
+	return from, to, nil
+}
+
+func utcDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/models"
+	"BACKEND/internal/repository"
+)
+
+type fakeOrganizationStore struct {
+	orgs       map[int64]generated.Organization
+	members    map[int64]int64
+	calls      map[int64]int64
+	aggregated []time.Time
+	usage      []generated.OrganizationUsage
+	callsErr   error
+}
+
+func (f *fakeOrganizationStore) Create(ctx context.Context, name string) (generated.Organization, error) {
+	org := generated.Organization{ID: int64(len(f.orgs) + 1), Name: name}
+	f.orgs[org.ID] = org
+	return org, nil
+}
+
+func (f *fakeOrganizationStore) Get(ctx context.Context, id int64) (generated.Organization, error) {
+	org, ok := f.orgs[id]
+	if !ok {
+		return generated.Organization{}, pgx.ErrNoRows
+	}
+	return org, nil
+}
+
+func (f *fakeOrganizationStore) List(ctx context.Context) ([]generated.Organization, error) {
+	return nil, nil
+}
+
+func (f *fakeOrganizationStore) SetMember(ctx context.Context, userID, orgID int64) error {
+	if _, ok := f.orgs[orgID]; !ok {
+		return &repository.ConstraintError{Kind: repository.ErrForeignKeyViolation, Constraint: "organization_members_org_id_fkey", Err: errors.New("fk")}
+	}
+	f.members[userID] = orgID
+	return nil
+}
+
+func (f *fakeOrganizationStore) RemoveMember(ctx context.Context, userID int64) (bool, error) {
+	_, ok := f.members[userID]
+	delete(f.members, userID)
+	return ok, nil
+}
+
+func (f *fakeOrganizationStore) MemberOf(ctx context.Context, userID int64) (int64, error) {
+	orgID, ok := f.members[userID]
+	if !ok {
+		return 0, pgx.ErrNoRows
+	}
+	return orgID, nil
+}
+
+func (f *fakeOrganizationStore) CountSeats(ctx context.Context, orgID, exceptUserID int64) (int64, error) {
+	var n int64
+	for userID, org := range f.members {
+		if org == orgID && userID != exceptUserID {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeOrganizationStore) AggregateUsage(ctx context.Context, day time.Time) error {
+	f.aggregated = append(f.aggregated, day)
+	return nil
+}
+
+func (f *fakeOrganizationStore) AddAPICalls(ctx context.Context, userID int64, day time.Time, calls int64) error {
+	if f.callsErr != nil {
+		return f.callsErr
+	}
+	f.calls[userID] += calls
+	return nil
+}
+
+func (f *fakeOrganizationStore) Usage(ctx context.Context, orgID int64, from, to time.Time) ([]generated.OrganizationUsage, error) {
+	return f.usage, nil
+}
+
+func newTestOrganizationService() (*OrganizationService, *fakeOrganizationStore) {
+	store := &fakeOrganizationStore{
+		orgs:    map[int64]generated.Organization{1: {ID: 1, Name: "Acme"}},
+		members: make(map[int64]int64),
+		calls:   make(map[int64]int64),
+	}
+	svc := NewOrganizationService(store)
+	svc.now = func() time.Time { return time.Date(2026, 3, 4, 23, 30, 0, 0, time.FixedZone("", -2*60*60)) }
+	return svc, store
+}
+
+func TestOrganizationAggregate(t *testing.T) {
+	ctx := context.Background()
+	svc, store := newTestOrganizationService()
+
+	svc.RecordCall(models.AuthUser{ID: 1})
+	svc.RecordCall(models.AuthUser{ID: 1})
+	svc.RecordCall(models.AuthUser{ID: 2})
+
+	store.callsErr = errors.New("db down")
+	if err := svc.Aggregate(ctx); err == nil {
+		t.Fatal("expected an error when calls can't be written")
+	}
+	if len(store.aggregated) != 0 {
+		t.Errorf("usage was aggregated after a failure")
+	}
+
+	store.callsErr = nil
+	svc.RecordCall(models.AuthUser{ID: 2})
+	if err := svc.Aggregate(ctx); err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	if store.calls[1] != 2 || store.calls[2] != 2 {
+		t.Errorf("calls were lost or double counted: %v", store.calls)
+	}
+	want := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	if len(store.aggregated) != 1 || !store.aggregated[0].Equal(want) {
+		t.Errorf("expected usage aggregated for %v, got %v", want, store.aggregated)
+	}
+
+	if err := svc.Aggregate(ctx); err != nil {
+		t.Fatalf("aggregate: %v", err)
+	}
+	if store.calls[1] != 2 || store.calls[2] != 2 {
+		t.Errorf("calls were written twice: %v", store.calls)
+	}
+}
+
+func TestOrganizationUsage(t *testing.T) {
+	ctx := context.Background()
+	svc, store := newTestOrganizationService()
+	day := func(d int) pgtype.Date {
+		return pgtype.Date{Time: time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC), Valid: true}
+	}
+	store.usage = []generated.OrganizationUsage{
+		{OrgID: 1, Day: day(1), Seats: 3, ApiCalls: 10, StorageBytes: 100},
+		{OrgID: 1, Day: day(2), Seats: 5, ApiCalls: 20, StorageBytes: 150},
+	}
+	from, to := day(1).Time, day(2).Time
+
+	usage, err := svc.Usage(ctx, 1, from, to)
+	if err != nil {
+		t.Fatalf("usage: %v", err)
+	}
+	if usage.Seats != 5 || usage.APICalls != 30 || usage.StorageBytes != 150 || len(usage.Days) != 2 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+
+	if _, err := svc.Usage(ctx, 2, from, to); !errors.Is(err, ErrOrganizationNotFound) {
+		t.Errorf("expected ErrOrganizationNotFound, got %v", err)
+	}
+	if _, err := svc.Usage(ctx, 1, to, from); !errors.Is(err, ErrUsageRange) {
+		t.Errorf("reversed range: expected ErrUsageRange, got %v", err)
+	}
+	if _, err := svc.Usage(ctx, 1, from, from.AddDate(1, 0, 1)); !errors.Is(err, ErrUsageRange) {
+		t.Errorf("range over a year: expected ErrUsageRange, got %v", err)
+	}
+}
+
+func TestOrganizationSetMemberUnknownOrg(t *testing.T) {
+	svc, _ := newTestOrganizationService()
+	if err := svc.SetMember(context.Background(), 1, 9); !errors.Is(err, ErrOrganizationNotFound) {
+		t.Errorf("expected ErrOrganizationNotFound, got %v", err)
+	}
+	if err := svc.SetMember(context.Background(), 1, 1); err != nil {
+		t.Errorf("set member: %v", err)
+	}
+}
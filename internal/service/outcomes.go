@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/hooks"
+)
+
+// Outcome is the business result of a service call, such as a signup
+// turned away for a duplicate email. Outcomes are counted apart from HTTP
+// statuses, which lump very different results together: a 400 may be a weak
+// password or a malformed body.
+type Outcome string
+
+const (
+	OutcomeSignupSuccess        Outcome = "signup_success"
+	OutcomeSignupDuplicateEmail Outcome = "signup_duplicate_email"
+	OutcomeSignupWeakPassword   Outcome = "signup_weak_password"
+	OutcomeSignupRejected       Outcome = "signup_rejected"
+	OutcomeSignupError          Outcome = "signup_error"
+
+	OutcomeLoginSuccess            Outcome = "login_success"
+	OutcomeLoginInvalidCredentials Outcome = "login_invalid_credentials"
+	OutcomeLoginAccountDisabled    Outcome = "login_account_disabled"
+	OutcomeLoginServiceAccount     Outcome = "login_service_account"
+	OutcomeLoginChallenged         Outcome = "login_challenged"
+	OutcomeLoginError              Outcome = "login_error"
+)
+
+// Outcomes counts outcomes since startup.
+type Outcomes struct {
+	mu     sync.Mutex
+	counts map[Outcome]int64
+}
+
+func NewOutcomes() *Outcomes {
+	return &Outcomes{counts: make(map[Outcome]int64)}
+}
+
+func (o *Outcomes) Record(outcome Outcome) {
+	o.mu.Lock()
+	o.counts[outcome]++
+	o.mu.Unlock()
+}
+
+// Counts returns a copy of the counts. Outcomes that never happened are
+// left out.
+func (o *Outcomes) Counts() map[Outcome]int64 {
+	if o == nil {
+		return map[Outcome]int64{}
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	counts := make(map[Outcome]int64, len(o.counts))
+	for outcome, n := range o.counts {
+		counts[outcome] = n
+	}
+	return counts
+}
+
+// IsWeakPassword reports whether err is one of the password strength
+// errors.
+func IsWeakPassword(err error) bool {
+	for _, weak := range []error{ErrPasswordTooShort, ErrPasswordNoUppercase, ErrPasswordNoLowercase, ErrPasswordNoDigit, ErrPasswordNoSpecial} {
+		if errors.Is(err, weak) {
+			return true
+		}
+	}
+	return false
+}
+
+func signupOutcome(err error) Outcome {
+	var rejected *hooks.RejectedError
+	switch {
+	case err == nil:
+		return OutcomeSignupSuccess
+	case errors.Is(err, ErrEmailAlreadyExists):
+		return OutcomeSignupDuplicateEmail
+	case IsWeakPassword(err):
+		return OutcomeSignupWeakPassword
+	case errors.As(err, &rejected):
+		return OutcomeSignupRejected
+	}
+	return OutcomeSignupError
+}
+
+func loginOutcome(err error) Outcome {
+	switch {
+	case err == nil:
+		return OutcomeLoginSuccess
+	case errors.Is(err, ErrInvalidCredentials):
+		return OutcomeLoginInvalidCredentials
+	case errors.Is(err, ErrAccountDisabled):
+		return OutcomeLoginAccountDisabled
+	case errors.Is(err, ErrInteractiveLoginDenied):
+		return OutcomeLoginServiceAccount
+	case errors.Is(err, ErrLoginChallenged):
+		return OutcomeLoginChallenged
+	}
+	return OutcomeLoginError
+}
+
+// outcomeAuthService records the outcome of every signup and login through
+// the wrapped service.
+type outcomeAuthService struct {
+	AuthServiceInterface
+	outcomes *Outcomes
+}
+
+func WithOutcomes(svc AuthServiceInterface, outcomes *Outcomes) AuthServiceInterface {
+	if outcomes == nil {
+		return svc
+	}
+	return &outcomeAuthService{AuthServiceInterface: svc, outcomes: outcomes}
+}
+
+func (s *outcomeAuthService) CreateUser(ctx context.Context, name, email, password, dobStr, role string) (generated.CreateUserRow, error) {
+	user, err := s.AuthServiceInterface.CreateUser(ctx, name, email, password, dobStr, role)
+	s.outcomes.Record(signupOutcome(err))
+	return user, err
+}
+
+func (s *outcomeAuthService) Login(ctx context.Context, email, password string) (generated.User, string, error) {
+	user, token, err := s.AuthServiceInterface.Login(ctx, email, password)
+	s.outcomes.Record(loginOutcome(err))
+	return user, token, err
+}
@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/hooks"
+)
+
+type stubAuthService struct {
+	AuthServiceInterface
+	err error
+}
+
+func (s *stubAuthService) CreateUser(ctx context.Context, name, email, password, dobStr, role string) (generated.CreateUserRow, error) {
+	return generated.CreateUserRow{}, s.err
+}
+
+func (s *stubAuthService) Login(ctx context.Context, email, password string) (generated.User, string, error) {
+	return generated.User{}, "", s.err
+}
+
+func TestWithOutcomes(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantSignup Outcome
+		wantLogin  Outcome
+	}{
+		{nil, OutcomeSignupSuccess, OutcomeLoginSuccess},
+		{ErrEmailAlreadyExists, OutcomeSignupDuplicateEmail, OutcomeLoginError},
+		{ErrPasswordNoDigit, OutcomeSignupWeakPassword, OutcomeLoginError},
+		{fmt.Errorf("hook: %w", &hooks.RejectedError{Reason: "no"}), OutcomeSignupRejected, OutcomeLoginError},
+		{ErrInvalidCredentials, OutcomeSignupError, OutcomeLoginInvalidCredentials},
+		{ErrAccountDisabled, OutcomeSignupError, OutcomeLoginAccountDisabled},
+		{ErrInteractiveLoginDenied, OutcomeSignupError, OutcomeLoginServiceAccount},
+		{errors.New("connection reset"), OutcomeSignupError, OutcomeLoginError},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.err), func(t *testing.T) {
+			outcomes := NewOutcomes()
+			svc := WithOutcomes(&stubAuthService{err: tt.err}, outcomes)
+
+			svc.CreateUser(context.Background(), "Jane", "jane@example.com", "pw", "1990-01-01", "user")
+			svc.Login(context.Background(), "jane@example.com", "pw")
+			svc.Login(context.Background(), "jane@example.com", "pw")
+
+			counts := outcomes.Counts()
+			if counts[tt.wantSignup] != 1 {
+				t.Errorf("counts = %v; want one %s", counts, tt.wantSignup)
+			}
+			if counts[tt.wantLogin] != 2 {
+				t.Errorf("counts = %v; want two %s", counts, tt.wantLogin)
+			}
+		})
+	}
+}
+
+func TestWithOutcomes_NilOutcomes(t *testing.T) {
+	svc := &stubAuthService{}
+	if got := WithOutcomes(svc, nil); got != svc {
+		t.Error("WithOutcomes(svc, nil) should return the service itself")
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/internal/clock"
+	"BACKEND/internal/mailer"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/templates"
+)
+
+var (
+	ErrPasswordResetInvalid = errors.New("password reset link is invalid")
+	ErrPasswordResetExpired = errors.New("password reset link has expired")
+)
+
+// PasswordResetConfig configures reset links. URL is the page that asks for
+// the new password; the token is added as the "token" query parameter.
+type PasswordResetConfig struct {
+	Secret string
+	TTL    time.Duration
+	URL    string
+}
+
+// PasswordResetService lets admins lock a user out of password login and
+// emails the user a link to choose a new password. Links are signed over the
+// current password hash, so a link stops working once it has been used.
+type PasswordResetService struct {
+	repo        repository.UserStore
+	auth        *AuthService
+	revocations *TokenRevocationService
+	mailer      mailer.Mailer
+	renderer    *templates.Renderer
+	cfg         PasswordResetConfig
+	logger      *zap.Logger
+	clock       clock.Clock
+}
+
+func NewPasswordResetService(repo repository.UserStore, auth *AuthService, revocations *TokenRevocationService, m mailer.Mailer, renderer *templates.Renderer, cfg PasswordResetConfig, logger *zap.Logger) *PasswordResetService {
+	return &PasswordResetService{
+		repo:        repo,
+		auth:        auth,
+		revocations: revocations,
+		mailer:      m,
+		renderer:    renderer,
+		cfg:         cfg,
+		logger:      logger,
+		clock:       clock.System,
+	}
+}
+
+// SetClock sets the clock reset links expire by.
+func (s *PasswordResetService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// ForceReset replaces the user's password with one that matches nothing,
+// revokes their tokens and emails them a reset link. The email is sent in
+// the background.
+func (s *PasswordResetService) ForceReset(ctx context.Context, userID int64, locale string) error {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.AccountType == AccountTypeService {
+		return ErrInteractiveLoginDenied
+	}
+
+	random, err := randomHex(16)
+	if err != nil {
+		return err
+	}
+	// Not a bcrypt hash, so no password compares equal to it.
+	unusable := "!reset:" + random
+	if err := s.repo.UpdatePassword(ctx, user.ID, unusable); err != nil {
+		return fmt.Errorf("failed to invalidate password: %w", err)
+	}
+	if err := s.revocations.RevokeUser(ctx, user.ID); err != nil {
+		return err
+	}
+
+	link, err := s.link(user.ID, unusable)
+	if err != nil {
+		return err
+	}
+	msg, err := s.renderer.Render("password_reset_required", locale, map[string]interface{}{
+		"Name":      user.Name,
+		"ResetURL":  link,
+		"ExpiresIn": s.cfg.TTL.String(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render password reset email: %w", err)
+	}
+
+	to := []string{user.Email}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := s.mailer.Send(ctx, to, msg); err != nil {
+			s.logger.Error("failed to send password reset link", zap.Int64("user_id", user.ID), zap.Error(err))
+		}
+	}()
+	return nil
+}
+
+// Reset sets a new password for the user a reset link was sent to and
+// returns their ID. The caller checks the password's strength.
+func (s *PasswordResetService) Reset(ctx context.Context, token, password string) (int64, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 {
+		return 0, ErrPasswordResetInvalid
+	}
+	id, expires, nonce, signature := parts[0], parts[1], parts[2], parts[3]
+
+	userID, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, ErrPasswordResetInvalid
+	}
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return 0, ErrPasswordResetInvalid
+	}
+
+	row, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return 0, ErrPasswordResetInvalid
+	}
+	user, err := s.repo.GetByEmail(ctx, row.Email)
+	if err != nil {
+		return 0, ErrPasswordResetInvalid
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.sign(id, user.PasswordHash, expires, nonce))) {
+		return 0, ErrPasswordResetInvalid
+	}
+	if s.clock.Now().After(time.Unix(expiresUnix, 0)) {
+		return 0, ErrPasswordResetExpired
+	}
+	if !user.Active {
+		return 0, ErrAccountDisabled
+	}
+
+	hash, err := s.auth.HashPassword(password)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.repo.UpdatePassword(ctx, user.ID, hash); err != nil {
+		return 0, fmt.Errorf("failed to update password: %w", err)
+	}
+	return user.ID, nil
+}
+
+func (s *PasswordResetService) link(userID int64, passwordHash string) (string, error) {
+	nonce, err := randomHex(8)
+	if err != nil {
+		return "", err
+	}
+	id := strconv.FormatInt(userID, 10)
+	expires := strconv.FormatInt(s.clock.Now().Add(s.cfg.TTL).Unix(), 10)
+	token := strings.Join([]string{id, expires, nonce, s.sign(id, passwordHash, expires, nonce)}, ".")
+
+	q := url.Values{}
+	q.Set("token", token)
+	sep := "?"
+	if strings.Contains(s.cfg.URL, "?") {
+		sep = "&"
+	}
+	return s.cfg.URL + sep + q.Encode(), nil
+}
+
+func (s *PasswordResetService) sign(id, passwordHash, expires, nonce string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.Secret))
+	mac.Write([]byte("password-reset." + id + "." + passwordHash + "." + expires + "." + nonce))
+	return hex.EncodeToString(mac.Sum(nil))
+}
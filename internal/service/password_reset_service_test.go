@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/clock"
+	"BACKEND/internal/templates"
+)
+
+type fakePasswordResetUserStore struct {
+	fakeMagicLinkUserStore
+}
+
+func (f *fakePasswordResetUserStore) UpdatePassword(ctx context.Context, id int64, passwordHash string) error {
+	f.user.PasswordHash = passwordHash
+	return nil
+}
+
+func newTestPasswordResetService(t *testing.T, user generated.User, ttl time.Duration) (*PasswordResetService, *fakePasswordResetUserStore, *TokenRevocationService, *fakeMailer) {
+	t.Helper()
+	store := &fakePasswordResetUserStore{fakeMagicLinkUserStore{user: user}}
+	auth := NewAuthService(store)
+	revocations := NewTokenRevocationService(&fakeRevocationStore{revoked: map[int64]time.Time{}})
+	renderer, err := templates.NewRenderer(templates.Branding{ProductName: "Test", SupportEmail: "support@example.com"}, "en")
+	if err != nil {
+		t.Fatalf("NewRenderer: %v", err)
+	}
+	m := &fakeMailer{sent: make(chan *templates.Email, 1)}
+	svc := NewPasswordResetService(store, auth, revocations, m, renderer, PasswordResetConfig{
+		Secret: "reset-secret",
+		TTL:    ttl,
+		URL:    "https://example.com/reset-password",
+	}, zap.NewNop())
+	return svc, store, revocations, m
+}
+
+func TestForcePasswordReset(t *testing.T) {
+	auth := NewAuthService(nil)
+	oldHash, err := auth.HashPassword("OldPassword1!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := generated.User{ID: 7, Name: "Jane", Email: "jane@example.com", PasswordHash: oldHash, Role: "user", Active: true, AccountType: AccountTypeHuman}
+	svc, store, revocations, m := newTestPasswordResetService(t, user, time.Hour)
+	ctx := context.Background()
+
+	if err := svc.ForceReset(ctx, user.ID, ""); err != nil {
+		t.Fatalf("ForceReset: %v", err)
+	}
+	if auth.ComparePassword(store.user.PasswordHash, "OldPassword1!") == nil {
+		t.Error("old password still works")
+	}
+	if !revocations.IsRevoked(user.ID, time.Now().Add(-time.Minute)) {
+		t.Error("existing tokens were not revoked")
+	}
+
+	token := sentToken(t, m)
+	if _, err := svc.Reset(ctx, token, "NewPassword1!"); err != nil {
+		t.Fatalf("Reset: %v", err)
+	}
+	if err := auth.ComparePassword(store.user.PasswordHash, "NewPassword1!"); err != nil {
+		t.Errorf("new password does not work: %v", err)
+	}
+
+	if _, err := svc.Reset(ctx, token, "OtherPassword1!"); !errors.Is(err, ErrPasswordResetInvalid) {
+		t.Fatalf("reused link err = %v, want %v", err, ErrPasswordResetInvalid)
+	}
+}
+
+func TestPasswordResetExpired(t *testing.T) {
+	user := generated.User{ID: 7, Name: "Jane", Email: "jane@example.com", Role: "user", Active: true, AccountType: AccountTypeHuman}
+	svc, _, _, m := newTestPasswordResetService(t, user, time.Minute)
+	clk := clock.NewFake(time.Now())
+	svc.SetClock(clk)
+	ctx := context.Background()
+
+	if err := svc.ForceReset(ctx, user.ID, ""); err != nil {
+		t.Fatalf("ForceReset: %v", err)
+	}
+	clk.Advance(2 * time.Minute)
+	if _, err := svc.Reset(ctx, sentToken(t, m), "NewPassword1!"); !errors.Is(err, ErrPasswordResetExpired) {
+		t.Fatalf("err = %v, want %v", err, ErrPasswordResetExpired)
+	}
+}
+
+func TestPasswordResetRejectsTamperedToken(t *testing.T) {
+	user := generated.User{ID: 7, Name: "Jane", Email: "jane@example.com", Role: "user", Active: true, AccountType: AccountTypeHuman}
+	svc, _, _, m := newTestPasswordResetService(t, user, time.Hour)
+	ctx := context.Background()
+
+	if err := svc.ForceReset(ctx, user.ID, ""); err != nil {
+		t.Fatalf("ForceReset: %v", err)
+	}
+	token := sentToken(t, m)
+	for _, bad := range []string{"", "7.1.2", token + "0", "8" + token[1:]} {
+		if _, err := svc.Reset(ctx, bad, "NewPassword1!"); !errors.Is(err, ErrPasswordResetInvalid) {
+			t.Errorf("Reset(%q) err = %v, want %v", bad, err, ErrPasswordResetInvalid)
+		}
+	}
+}
+
+func TestForcePasswordResetServiceAccount(t *testing.T) {
+	user := generated.User{ID: 7, Name: "ci", Email: "ci@service.local", Role: "user", Active: true, AccountType: AccountTypeService}
+	svc, _, _, _ := newTestPasswordResetService(t, user, time.Hour)
+
+	if err := svc.ForceReset(context.Background(), user.ID, ""); !errors.Is(err, ErrInteractiveLoginDenied) {
+		t.Fatalf("err = %v, want %v", err, ErrInteractiveLoginDenied)
+	}
+}
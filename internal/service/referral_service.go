@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"BACKEND/internal/repository"
+)
+
+var ErrReferralCodeInvalid = errors.New("referral code is invalid")
+
+const (
+	referralCodeLength   = 8
+	referralCodeAttempts = 5
+)
+
+// ReferralConfig configures referral links. URL is the signup page shared
+// links point to; the code is added as the "ref" query parameter.
+type ReferralConfig struct {
+	URL        string
+	RecentDays int
+}
+
+// ReferralStats is what a user sees about their own referrals.
+type ReferralStats struct {
+	Code       string `json:"code"`
+	URL        string `json:"url"`
+	Total      int64  `json:"total"`
+	Recent     int64  `json:"recent"`
+	RecentDays int    `json:"recent_days"`
+}
+
+// ReferralService hands out one referral code per user and credits signups
+// made with it to the code's owner.
+type ReferralService struct {
+	store  repository.ReferralStore
+	cfg    ReferralConfig
+	logger *zap.Logger
+}
+
+func NewReferralService(store repository.ReferralStore, cfg ReferralConfig, logger *zap.Logger) *ReferralService {
+	if cfg.RecentDays <= 0 {
+		cfg.RecentDays = 30
+	}
+	return &ReferralService{
+		store:  store,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+// Code returns the user's referral code, creating it the first time.
+func (s *ReferralService) Code(ctx context.Context, userID int64) (string, error) {
+	code, err := s.store.Code(ctx, userID)
+	if err == nil {
+		return code, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return "", fmt.Errorf("failed to get referral code: %w", err)
+	}
+
+	for i := 0; i < referralCodeAttempts; i++ {
+		code, err := randomReferralCode()
+		if err != nil {
+			return "", err
+		}
+		created, err := s.store.CreateCode(ctx, userID, code)
+		if err != nil {
+			return "", fmt.Errorf("failed to create referral code: %w", err)
+		}
+		if created {
+			return code, nil
+		}
+		// Either a concurrent request gave the user a code first or the
+		// random code is taken; only the latter needs another try.
+		if existing, err := s.store.Code(ctx, userID); err == nil {
+			return existing, nil
+		}
+	}
+	return "", errors.New("failed to create referral code: no free code found")
+}
+
+// RecordSignup credits a new user to the owner of code. A user is credited
+// at most once.
+func (s *ReferralService) RecordSignup(ctx context.Context, code string, userID int64) error {
+	code = normalizeReferralCode(code)
+	if code == "" {
+		return ErrReferralCodeInvalid
+	}
+	referrerID, err := s.store.Owner(ctx, code)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrReferralCodeInvalid
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up referral code: %w", err)
+	}
+	if referrerID == userID {
+		return ErrReferralCodeInvalid
+	}
+
+	recorded, err := s.store.Record(ctx, referrerID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to record referral: %w", err)
+	}
+	if recorded {
+		s.logger.Info("referral recorded", zap.Int64("referrer_id", referrerID), zap.Int64("user_id", userID))
+	}
+	return nil
+}
+
+func (s *ReferralService) Stats(ctx context.Context, userID int64) (ReferralStats, error) {
+	code, err := s.Code(ctx, userID)
+	if err != nil {
+		return ReferralStats{}, err
+	}
+	since := time.Now().AddDate(0, 0, -s.cfg.RecentDays)
+	row, err := s.store.Stats(ctx, userID, since)
+	if err != nil {
+		return ReferralStats{}, fmt.Errorf("failed to count referrals: %w", err)
+	}
+	return ReferralStats{
+		Code:       code,
+		URL:        s.link(code),
+		Total:      row.Total,
+		Recent:     row.Recent,
+		RecentDays: s.cfg.RecentDays,
+	}, nil
+}
+
+func (s *ReferralService) link(code string) string {
+	u, err := url.Parse(s.cfg.URL)
+	if err != nil {
+		return ""
+	}
+	q := u.Query()
+	q.Set("ref", code)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// Referral codes share the device user-code alphabet, which also keeps
+// them from spelling words.
+func randomReferralCode() (string, error) {
+	b := make([]byte, referralCodeLength)
+	for i := range b {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(userCodeAlphabet))))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate referral code: %w", err)
+		}
+		b[i] = userCodeAlphabet[n.Int64()]
+	}
+	return string(b), nil
+}
+
+// normalizeReferralCode accepts codes typed in lower case or with stray
+// spaces or dashes.
+func normalizeReferralCode(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+
+	"BACKEND/db/sqlc/generated"
+)
+
+type fakeReferralStore struct {
+	codes     map[int64]string
+	referrals map[int64]int64
+	since     time.Time
+}
+
+func newFakeReferralStore() *fakeReferralStore {
+	return &fakeReferralStore{codes: map[int64]string{}, referrals: map[int64]int64{}}
+}
+
+func (f *fakeReferralStore) Code(ctx context.Context, userID int64) (string, error) {
+	code, ok := f.codes[userID]
+	if !ok {
+		return "", pgx.ErrNoRows
+	}
+	return code, nil
+}
+
+func (f *fakeReferralStore) CreateCode(ctx context.Context, userID int64, code string) (bool, error) {
+	if _, ok := f.codes[userID]; ok {
+		return false, nil
+	}
+	if _, err := f.Owner(ctx, code); err == nil {
+		return false, nil
+	}
+	f.codes[userID] = code
+	return true, nil
+}
+
+func (f *fakeReferralStore) Owner(ctx context.Context, code string) (int64, error) {
+	for userID, c := range f.codes {
+		if c == code {
+			return userID, nil
+		}
+	}
+	return 0, pgx.ErrNoRows
+}
+
+func (f *fakeReferralStore) Record(ctx context.Context, referrerID, referredID int64) (bool, error) {
+	if _, ok := f.referrals[referredID]; ok {
+		return false, nil
+	}
+	f.referrals[referredID] = referrerID
+	return true, nil
+}
+
+func (f *fakeReferralStore) Stats(ctx context.Context, referrerID int64, since time.Time) (generated.ReferralStatsRow, error) {
+	f.since = since
+	var row generated.ReferralStatsRow
+	for _, r := range f.referrals {
+		if r == referrerID {
+			row.Total++
+			row.Recent++
+		}
+	}
+	return row, nil
+}
+
+func TestReferralService(t *testing.T) {
+	store := newFakeReferralStore()
+	svc := NewReferralService(store, ReferralConfig{URL: "https://app.example.com/signup?plan=free"}, zap.NewNop())
+	ctx := context.Background()
+
+	code, err := svc.Code(ctx, 1)
+	if err != nil {
+		t.Fatalf("Code: %v", err)
+	}
+	if len(code) != referralCodeLength {
+		t.Errorf("code = %q, want %d characters", code, referralCodeLength)
+	}
+	if again, _ := svc.Code(ctx, 1); again != code {
+		t.Errorf("second Code = %q, want %q", again, code)
+	}
+
+	lower := []byte(code)
+	for i := range lower {
+		lower[i] += 'a' - 'A'
+	}
+	if err := svc.RecordSignup(ctx, " "+string(lower)+" ", 2); err != nil {
+		t.Fatalf("RecordSignup: %v", err)
+	}
+	if err := svc.RecordSignup(ctx, code, 2); err != nil {
+		t.Fatalf("RecordSignup twice: %v", err)
+	}
+	if err := svc.RecordSignup(ctx, code, 3); err != nil {
+		t.Fatalf("RecordSignup: %v", err)
+	}
+	if err := svc.RecordSignup(ctx, "NOPE", 4); !errors.Is(err, ErrReferralCodeInvalid) {
+		t.Errorf("RecordSignup with unknown code: err = %v, want ErrReferralCodeInvalid", err)
+	}
+	if err := svc.RecordSignup(ctx, code, 1); !errors.Is(err, ErrReferralCodeInvalid) {
+		t.Errorf("RecordSignup with own code: err = %v, want ErrReferralCodeInvalid", err)
+	}
+
+	stats, err := svc.Stats(ctx, 1)
+	if err != nil {
+		t.Fatalf("Stats: %v", err)
+	}
+	if stats.Code != code || stats.Total != 2 || stats.RecentDays != 30 {
+		t.Errorf("stats = %+v, want code %s with 2 referrals over 30 days", stats, code)
+	}
+	if want := "https://app.example.com/signup?plan=free&ref=" + code; stats.URL != want {
+		t.Errorf("URL = %q, want %q", stats.URL, want)
+	}
+	if since := time.Since(store.since); since < 29*24*time.Hour || since > 31*24*time.Hour {
+		t.Errorf("recent window starts %v ago, want 30 days", since)
+	}
+}
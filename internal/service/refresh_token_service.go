@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/clock"
+	"BACKEND/internal/repository"
+)
+
+var (
+	ErrRefreshTokenInvalid = errors.New("refresh token is invalid")
+	ErrRefreshTokenExpired = errors.New("refresh token has expired")
+	ErrRefreshTokenReused  = errors.New("refresh token has already been used")
+)
+
+// RefreshTokenService issues long-lived refresh tokens alongside JWTs and
+// trades them for new JWTs. Each refresh token works once: refreshing
+// revokes it and issues its replacement in the same family. A replaced
+// token coming back means it was copied, so its whole family is revoked and
+// whoever holds the newest token has to log in again too.
+//
+// Only SHA-256 hashes of the tokens are stored.
+type RefreshTokenService struct {
+	store       repository.RefreshTokenStore
+	users       repository.UserStore
+	auth        *AuthService
+	revocations *TokenRevocationService
+	sessions    *SessionService
+	ttl         time.Duration
+	clock       clock.Clock
+}
+
+func NewRefreshTokenService(store repository.RefreshTokenStore, users repository.UserStore, auth *AuthService, revocations *TokenRevocationService, ttl time.Duration) *RefreshTokenService {
+	return &RefreshTokenService{
+		store:       store,
+		users:       users,
+		auth:        auth,
+		revocations: revocations,
+		ttl:         ttl,
+		clock:       clock.System,
+	}
+}
+
+// SetClock sets the clock refresh tokens expire by.
+func (s *RefreshTokenService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetSessions keeps the session of each refreshed token live.
+func (s *RefreshTokenService) SetSessions(sessions *SessionService) {
+	s.sessions = sessions
+}
+
+func (s *RefreshTokenService) TTL() time.Duration {
+	return s.ttl
+}
+
+// Issue returns a refresh token for a user who has just logged in, starting
+// a new family. The family is the login's session, if it has one.
+func (s *RefreshTokenService) Issue(ctx context.Context, userID int64, sessionID string) (string, error) {
+	family := sessionID
+	if family == "" {
+		var err error
+		if family, err = randomHex(16); err != nil {
+			return "", err
+		}
+	}
+	return s.create(ctx, userID, family)
+}
+
+// Refresh revokes token and returns its user with a new JWT and the refresh
+// token that replaces it.
+func (s *RefreshTokenService) Refresh(ctx context.Context, token string) (generated.GetUserByIDRow, string, string, error) {
+	row, err := s.store.GetByHash(ctx, HashAPIKey(token))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return generated.GetUserByIDRow{}, "", "", ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return generated.GetUserByIDRow{}, "", "", fmt.Errorf("failed to look up refresh token: %w", err)
+	}
+	if row.RevokedAt.Valid {
+		return generated.GetUserByIDRow{}, "", "", s.reused(ctx, row.Family)
+	}
+	if !s.clock.Now().Before(row.ExpiresAt.Time) {
+		return generated.GetUserByIDRow{}, "", "", ErrRefreshTokenExpired
+	}
+	// Of two requests racing with the same token only one revokes it; the
+	// other is treated as a reuse.
+	revoked, err := s.store.Revoke(ctx, row.ID)
+	if err != nil {
+		return generated.GetUserByIDRow{}, "", "", fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	if !revoked {
+		return generated.GetUserByIDRow{}, "", "", s.reused(ctx, row.Family)
+	}
+
+	user, err := s.users.GetByID(ctx, row.UserID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return generated.GetUserByIDRow{}, "", "", ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return generated.GetUserByIDRow{}, "", "", fmt.Errorf("failed to load user: %w", err)
+	}
+	// Forcing a logout revokes the user's JWTs; the refresh tokens issued
+	// before it go with them. Revocations have millisecond precision, like
+	// JWT issue times.
+	if s.revocations != nil && s.revocations.IsRevoked(user.ID, row.CreatedAt.Time.Truncate(time.Millisecond)) {
+		return generated.GetUserByIDRow{}, "", "", ErrRefreshTokenInvalid
+	}
+	if user.AccountType == AccountTypeService {
+		return generated.GetUserByIDRow{}, "", "", ErrInteractiveLoginDenied
+	}
+	if !user.Active {
+		return generated.GetUserByIDRow{}, "", "", ErrAccountDisabled
+	}
+
+	jwtToken, err := s.auth.GenerateJWT(WithSession(ctx, row.Family), user.ID, user.Role)
+	if err != nil {
+		return generated.GetUserByIDRow{}, "", "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	if s.sessions != nil {
+		if err := s.sessions.Extend(ctx, row.Family); err != nil {
+			return generated.GetUserByIDRow{}, "", "", err
+		}
+	}
+	next, err := s.create(ctx, user.ID, row.Family)
+	if err != nil {
+		return generated.GetUserByIDRow{}, "", "", err
+	}
+	return user, jwtToken, next, nil
+}
+
+// Revoke logs out the session token belongs to by revoking its family.
+// Unknown tokens are ignored.
+func (s *RefreshTokenService) Revoke(ctx context.Context, token string) error {
+	row, err := s.store.GetByHash(ctx, HashAPIKey(token))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to look up refresh token: %w", err)
+	}
+	if err := s.store.RevokeFamily(ctx, row.Family); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
+
+func (s *RefreshTokenService) reused(ctx context.Context, family string) error {
+	if err := s.store.RevokeFamily(ctx, family); err != nil {
+		return fmt.Errorf("failed to revoke reused refresh token family: %w", err)
+	}
+	return ErrRefreshTokenReused
+}
+
+func (s *RefreshTokenService) create(ctx context.Context, userID int64, family string) (string, error) {
+	token, err := randomHex(32)
+	if err != nil {
+		return "", err
+	}
+	now := s.clock.Now()
+	if err := s.store.Create(ctx, userID, HashAPIKey(token), family, now, now.Add(s.ttl)); err != nil {
+		return "", fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return token, nil
+}
@@ -0,0 +1,137 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/clock"
+)
+
+type fakeRefreshTokenStore struct {
+	tokens map[string]*generated.RefreshToken
+	nextID int64
+}
+
+func (f *fakeRefreshTokenStore) Create(ctx context.Context, userID int64, tokenHash, family string, createdAt, expiresAt time.Time) error {
+	f.nextID++
+	f.tokens[tokenHash] = &generated.RefreshToken{
+		ID:        f.nextID,
+		UserID:    userID,
+		TokenHash: tokenHash,
+		Family:    family,
+		CreatedAt: pgtype.Timestamp{Time: createdAt, Valid: true},
+		ExpiresAt: pgtype.Timestamp{Time: expiresAt, Valid: true},
+	}
+	return nil
+}
+
+func (f *fakeRefreshTokenStore) GetByHash(ctx context.Context, tokenHash string) (generated.GetRefreshTokenByHashRow, error) {
+	t, ok := f.tokens[tokenHash]
+	if !ok {
+		return generated.GetRefreshTokenByHashRow{}, pgx.ErrNoRows
+	}
+	return generated.GetRefreshTokenByHashRow{
+		ID:        t.ID,
+		UserID:    t.UserID,
+		Family:    t.Family,
+		CreatedAt: t.CreatedAt,
+		ExpiresAt: t.ExpiresAt,
+		RevokedAt: t.RevokedAt,
+	}, nil
+}
+
+func (f *fakeRefreshTokenStore) Revoke(ctx context.Context, id int64) (bool, error) {
+	for _, t := range f.tokens {
+		if t.ID == id && !t.RevokedAt.Valid {
+			t.RevokedAt = pgtype.Timestamp{Time: time.Now(), Valid: true}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeRefreshTokenStore) RevokeFamily(ctx context.Context, family string) error {
+	for _, t := range f.tokens {
+		if t.Family == family && !t.RevokedAt.Valid {
+			t.RevokedAt = pgtype.Timestamp{Time: time.Now(), Valid: true}
+		}
+	}
+	return nil
+}
+
+func newTestRefreshTokenService(user generated.User) (*RefreshTokenService, *TokenRevocationService) {
+	users := &fakeMagicLinkUserStore{user: user}
+	auth := NewAuthService(users)
+	auth.SetJWTConfig("secret", time.Hour)
+	revocations := NewTokenRevocationService(&fakeRevocationStore{revoked: map[int64]time.Time{}})
+	store := &fakeRefreshTokenStore{tokens: map[string]*generated.RefreshToken{}}
+	return NewRefreshTokenService(store, users, auth, revocations, time.Hour), revocations
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	user := generated.User{ID: 7, Email: "jane@example.com", Role: "user", Active: true, AccountType: AccountTypeHuman}
+	svc, _ := newTestRefreshTokenService(user)
+	ctx := context.Background()
+
+	first, err := svc.Issue(ctx, user.ID, "")
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	got, jwtToken, second, err := svc.Refresh(ctx, first)
+	if err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if got.ID != user.ID || jwtToken == "" || second == "" || second == first {
+		t.Fatalf("unexpected refresh result: user %d, jwt %q, token %q", got.ID, jwtToken, second)
+	}
+
+	// The first token was stolen and is used after the real client
+	// refreshed: the whole family goes, including the second token.
+	if _, _, _, err := svc.Refresh(ctx, first); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("reused token err = %v, want %v", err, ErrRefreshTokenReused)
+	}
+	if _, _, _, err := svc.Refresh(ctx, second); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Errorf("token from a revoked family err = %v, want %v", err, ErrRefreshTokenReused)
+	}
+
+	if _, _, _, err := svc.Refresh(ctx, "unknown"); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Errorf("unknown token err = %v, want %v", err, ErrRefreshTokenInvalid)
+	}
+}
+
+func TestRefreshTokenLogoutAndExpiry(t *testing.T) {
+	user := generated.User{ID: 7, Email: "jane@example.com", Role: "user", Active: true, AccountType: AccountTypeHuman}
+	svc, revocations := newTestRefreshTokenService(user)
+	ctx := context.Background()
+
+	token, _ := svc.Issue(ctx, user.ID, "")
+	if err := svc.Revoke(ctx, token); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, _, _, err := svc.Refresh(ctx, token); err == nil {
+		t.Error("token still works after logout")
+	}
+
+	clk := clock.NewFake(time.Now())
+	svc.SetClock(clk)
+	token, _ = svc.Issue(ctx, user.ID, "")
+	clk.Advance(2 * time.Hour)
+	if _, _, _, err := svc.Refresh(ctx, token); !errors.Is(err, ErrRefreshTokenExpired) {
+		t.Errorf("expired token err = %v, want %v", err, ErrRefreshTokenExpired)
+	}
+	svc.SetClock(clock.System)
+
+	token, _ = svc.Issue(ctx, user.ID, "")
+	if err := revocations.RevokeUser(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := svc.Refresh(ctx, token); !errors.Is(err, ErrRefreshTokenInvalid) {
+		t.Errorf("token issued before a forced logout err = %v, want %v", err, ErrRefreshTokenInvalid)
+	}
+}
@@ -0,0 +1,463 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"BACKEND/internal/queryparams"
+	"BACKEND/internal/repository"
+)
+
+var (
+	ErrReportNotFound     = errors.New("report not found")
+	ErrInvalidReportParam = errors.New("invalid report parameter")
+)
+
+type ReportParam struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Default     int    `json:"default"`
+	Min         int    `json:"min"`
+	Max         int    `json:"max"`
+}
+
+// ReportOption is a parameter that takes one of a fixed set of values
+// rather than a number.
+type ReportOption struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Default     string   `json:"default"`
+	Choices     []string `json:"choices"`
+}
+
+type ReportDefinition struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	Params      []ReportParam  `json:"params"`
+	Options     []ReportOption `json:"options,omitempty"`
+}
+
+type ReportResult struct {
+	Report  string            `json:"report"`
+	Params  map[string]int    `json:"params"`
+	Options map[string]string `json:"options,omitempty"`
+	Columns []string          `json:"columns"`
+	Rows    [][]interface{}   `json:"rows"`
+	// Suppressed counts rows left out to keep the result anonymous.
+	Suppressed int `json:"suppressed,omitempty"`
+}
+
+type report struct {
+	ReportDefinition
+	run func(ctx context.Context, params map[string]int, options map[string]string) (*ReportResult, error)
+}
+
+type ReportService struct {
+	repo    repository.UserStore
+	logins  repository.LoginHistoryStore
+	reports map[string]report
+}
+
+func NewReportService(repo repository.UserStore) *ReportService {
+	s := &ReportService{
+		repo:    repo,
+		reports: make(map[string]report),
+	}
+
+	s.register(report{
+		ReportDefinition: ReportDefinition{
+			Name:        "users-by-age-bracket",
+			Description: "Number of users in each age bracket",
+		},
+		run: s.usersByAgeBracket,
+	})
+	s.register(report{
+		ReportDefinition: ReportDefinition{
+			Name:        "age-distribution",
+			Description: "Number of users in each age bucket, optionally per role or per signup month",
+			Params: []ReportParam{
+				{Name: "bucket_size", Description: "Width of each age bucket in years", Default: 10, Min: 1, Max: 100},
+			},
+			Options: []ReportOption{
+				{Name: "group_by", Description: "Split the buckets by role or by signup month", Default: "none", Choices: []string{"none", "role", "signup_cohort"}},
+			},
+		},
+		run: s.ageDistribution,
+	})
+	s.register(report{
+		ReportDefinition: ReportDefinition{
+			Name:        "signups-by-day",
+			Description: "Number of signups per day",
+			Params: []ReportParam{
+				{Name: "days", Description: "How many days back to include", Default: 30, Min: 1, Max: 366},
+			},
+		},
+		run: s.signupsByDay,
+	})
+	s.register(report{
+		ReportDefinition: ReportDefinition{
+			Name:        "signups-by-month",
+			Description: "Number of signups per calendar month",
+			Params: []ReportParam{
+				{Name: "months", Description: "How many months back to include", Default: 12, Min: 1, Max: 120},
+			},
+		},
+		run: s.signupsByMonth,
+	})
+	s.register(report{
+		ReportDefinition: ReportDefinition{
+			Name:        "signups-by-source",
+			Description: "Number of signups from each signup source (web, api, scim, admin, sso:<provider>)",
+			Params: []ReportParam{
+				{Name: "months", Description: "How many months back to include", Default: 12, Min: 1, Max: 120},
+			},
+		},
+		run: s.signupsBySource,
+	})
+	s.register(report{
+		ReportDefinition: ReportDefinition{
+			Name:        "deactivated-users",
+			Description: "Inactive users last updated in the period, which for most is when they were deactivated",
+			Params: []ReportParam{
+				{Name: "days", Description: "How many days back to include", Default: 30, Min: 1, Max: 366},
+			},
+		},
+		run: s.deactivatedUsers,
+	})
+	s.register(report{
+		ReportDefinition: ReportDefinition{
+			Name:        "anonymized-users",
+			Description: "One row per user with no name, email or ID and the date of birth cut to the year; rows shared by fewer than k users are left out",
+			Params: []ReportParam{
+				{Name: "k", Description: "Smallest number of users that may share a row", Default: 5, Min: 2, Max: 1000},
+			},
+			Options: []ReportOption{
+				{Name: "cohort", Description: "Generalize the signup date to its month or its year", Default: "year", Choices: []string{"year", "month"}},
+			},
+		},
+		run: s.anonymizedUsers,
+	})
+
+	return s
+}
+
+// SetLoginHistory adds the reports built from login history.
+func (s *ReportService) SetLoginHistory(store repository.LoginHistoryStore) {
+	s.logins = store
+	s.register(report{
+		ReportDefinition: ReportDefinition{
+			Name:        "failed-logins-by-day",
+			Description: "Number of failed password logins per day",
+			Params: []ReportParam{
+				{Name: "days", Description: "How many days back to include", Default: 30, Min: 1, Max: 366},
+			},
+		},
+		run: s.failedLoginsByDay,
+	})
+}
+
+func (s *ReportService) register(r report) {
+	s.reports[r.Name] = r
+}
+
+func (s *ReportService) List() []ReportDefinition {
+	defs := make([]ReportDefinition, 0, len(s.reports))
+	for _, r := range s.reports {
+		defs = append(defs, r.ReportDefinition)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+func (s *ReportService) Run(ctx context.Context, name string, rawParams map[string]string) (*ReportResult, error) {
+	r, params, options, err := s.resolve(name, rawParams)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := r.run(ctx, params, options)
+	if err != nil {
+		return nil, err
+	}
+	result.Report = name
+	result.Params = params
+	if len(options) > 0 {
+		result.Options = options
+	}
+	return result, nil
+}
+
+// Validate checks the report name and parameters without running it.
+func (s *ReportService) Validate(name string, rawParams map[string]string) error {
+	_, _, _, err := s.resolve(name, rawParams)
+	return err
+}
+
+func (s *ReportService) resolve(name string, rawParams map[string]string) (report, map[string]int, map[string]string, error) {
+	r, ok := s.reports[name]
+	if !ok {
+		return report{}, nil, nil, ErrReportNotFound
+	}
+
+	params := make(map[string]int, len(r.Params))
+	for _, p := range r.Params {
+		raw, ok := rawParams[p.Name]
+		if !ok || raw == "" {
+			params[p.Name] = p.Default
+			continue
+		}
+
+		value, fe := queryparams.Int(p.Name, raw, int64(p.Min), int64(p.Max))
+		if fe != nil {
+			return report{}, nil, nil, fmt.Errorf("%w: %s", ErrInvalidReportParam, fe)
+		}
+		params[p.Name] = int(value)
+	}
+
+	options := make(map[string]string, len(r.Options))
+	for _, o := range r.Options {
+		raw, ok := rawParams[o.Name]
+		if !ok || raw == "" {
+			options[o.Name] = o.Default
+			continue
+		}
+		if fe := queryparams.OneOf(o.Name, raw, o.Choices); fe != nil {
+			return report{}, nil, nil, fmt.Errorf("%w: %s", ErrInvalidReportParam, fe)
+		}
+		options[o.Name] = raw
+	}
+	return r, params, options, nil
+}
+
+// WriteCSV writes the result as CSV with a header row.
+func (r *ReportResult) WriteCSV(out io.Writer) error {
+	w := csv.NewWriter(out)
+
+	if err := w.Write(r.Columns); err != nil {
+		return err
+	}
+	record := make([]string, len(r.Columns))
+	for _, row := range r.Rows {
+		for i, v := range row {
+			record[i] = fmt.Sprint(v)
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func (s *ReportService) usersByAgeBracket(ctx context.Context, _ map[string]int, _ map[string]string) (*ReportResult, error) {
+	rows, err := s.repo.UsersByAgeBracket(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReportResult{
+		Columns: []string{"age_bracket", "user_count"},
+		Rows:    make([][]interface{}, len(rows)),
+	}
+	for i, row := range rows {
+		result.Rows[i] = []interface{}{row.Bracket, row.UserCount}
+	}
+	return result, nil
+}
+
+// ageDistribution labels buckets by the ages they cover, "30-39" for a
+// bucket size of 10 or "30" for 1.
+func (s *ReportService) ageDistribution(ctx context.Context, params map[string]int, options map[string]string) (*ReportResult, error) {
+	size := params["bucket_size"]
+	groupBy := options["group_by"]
+	if groupBy == "none" {
+		groupBy = ""
+	}
+	rows, err := s.repo.AgeDistribution(ctx, groupBy, int32(size))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReportResult{
+		Columns: []string{"age_bucket", "user_count"},
+		Rows:    make([][]interface{}, len(rows)),
+	}
+	if groupBy != "" {
+		result.Columns = append([]string{groupBy}, result.Columns...)
+	}
+	for i, row := range rows {
+		bucket := strconv.Itoa(int(row.BucketStart))
+		if size > 1 {
+			bucket += "-" + strconv.Itoa(int(row.BucketStart)+size-1)
+		}
+		result.Rows[i] = []interface{}{bucket, row.UserCount}
+		if groupBy != "" {
+			result.Rows[i] = append([]interface{}{row.Grp}, result.Rows[i]...)
+		}
+	}
+	return result, nil
+}
+
+func (s *ReportService) signupsByDay(ctx context.Context, params map[string]int, _ map[string]string) (*ReportResult, error) {
+	rows, err := s.repo.SignupsByDay(ctx, daysAgo(params["days"]))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReportResult{
+		Columns: []string{"day", "signups"},
+		Rows:    make([][]interface{}, len(rows)),
+	}
+	for i, row := range rows {
+		result.Rows[i] = []interface{}{row.Day, row.Signups}
+	}
+	return result, nil
+}
+
+func (s *ReportService) signupsByMonth(ctx context.Context, params map[string]int, _ map[string]string) (*ReportResult, error) {
+	rows, err := s.repo.SignupsByMonth(ctx, monthsAgo(params["months"]))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReportResult{
+		Columns: []string{"month", "signups"},
+		Rows:    make([][]interface{}, len(rows)),
+	}
+	for i, row := range rows {
+		result.Rows[i] = []interface{}{row.Month, row.Signups}
+	}
+	return result, nil
+}
+
+func (s *ReportService) signupsBySource(ctx context.Context, params map[string]int, _ map[string]string) (*ReportResult, error) {
+	rows, err := s.repo.SignupsBySource(ctx, monthsAgo(params["months"]))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReportResult{
+		Columns: []string{"signup_source", "signups"},
+		Rows:    make([][]interface{}, len(rows)),
+	}
+	for i, row := range rows {
+		result.Rows[i] = []interface{}{row.SignupSource, row.Signups}
+	}
+	return result, nil
+}
+
+func (s *ReportService) deactivatedUsers(ctx context.Context, params map[string]int, _ map[string]string) (*ReportResult, error) {
+	rows, err := s.repo.ListDeactivatedSince(ctx, daysAgo(params["days"]))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReportResult{
+		Columns: []string{"id", "name", "email", "updated_at"},
+		Rows:    make([][]interface{}, len(rows)),
+	}
+	for i, row := range rows {
+		result.Rows[i] = []interface{}{row.ID, row.Name, row.Email, row.UpdatedAt.Time.Format(time.RFC3339)}
+	}
+	return result, nil
+}
+
+// anonymizedUsers releases user records k-anonymously: every column is a
+// quasi-identifier, so rows are grouped on all of them and any group with
+// fewer than k users is suppressed. Service accounts aren't people and are
+// left out.
+func (s *ReportService) anonymizedUsers(ctx context.Context, params map[string]int, options map[string]string) (*ReportResult, error) {
+	users, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	cohortLayout := "2006"
+	if options["cohort"] == "month" {
+		cohortLayout = "2006-01"
+	}
+
+	type group struct {
+		row   []interface{}
+		count int
+	}
+	groups := make(map[string]*group)
+	for _, user := range users {
+		if user.AccountType != "human" {
+			continue
+		}
+		row := []interface{}{
+			user.Dob.Time.Year(),
+			user.Role,
+			user.SignupSource,
+			user.CreatedAt.Time.Format(cohortLayout),
+			user.Active,
+		}
+		key := fmt.Sprint(row...)
+		if g, ok := groups[key]; ok {
+			g.count++
+			continue
+		}
+		groups[key] = &group{row: row, count: 1}
+	}
+
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	result := &ReportResult{
+		Columns: []string{"birth_year", "role", "signup_source", "signup_cohort", "active"},
+		Rows:    [][]interface{}{},
+	}
+	for _, key := range keys {
+		g := groups[key]
+		if g.count < params["k"] {
+			result.Suppressed += g.count
+			continue
+		}
+		for i := 0; i < g.count; i++ {
+			result.Rows = append(result.Rows, g.row)
+		}
+	}
+	return result, nil
+}
+
+func (s *ReportService) failedLoginsByDay(ctx context.Context, params map[string]int, _ map[string]string) (*ReportResult, error) {
+	rows, err := s.logins.FailedByDay(ctx, daysAgo(params["days"]))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReportResult{
+		Columns: []string{"day", "failures"},
+		Rows:    make([][]interface{}, len(rows)),
+	}
+	for i, row := range rows {
+		result.Rows[i] = []interface{}{row.Day, row.Failures}
+	}
+	return result, nil
+}
+
+// daysAgo is the start of the day days-1 days before today, so days=1
+// covers today.
+func daysAgo(days int) time.Time {
+	now := time.Now().UTC()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return startOfDay.AddDate(0, 0, -(days - 1))
+}
+
+// monthsAgo is the start of the calendar month months-1 months before the
+// current one, so months=1 covers the current month.
+func monthsAgo(months int) time.Time {
+	now := time.Now().UTC()
+	startOfMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return startOfMonth.AddDate(0, -(months - 1), 0)
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/repository"
+)
+
+type fakeReportUserStore struct {
+	repository.UserStore
+	since   time.Time
+	groupBy string
+	users   []generated.ListUsersRow
+}
+
+func (f *fakeReportUserStore) List(ctx context.Context) ([]generated.ListUsersRow, error) {
+	return f.users, nil
+}
+
+func (f *fakeReportUserStore) AgeDistribution(ctx context.Context, groupBy string, bucketSize int32) ([]generated.AgeDistributionRow, error) {
+	f.groupBy = groupBy
+	return []generated.AgeDistributionRow{
+		{Grp: groupBy, BucketStart: 2 * bucketSize, UserCount: 4},
+	}, nil
+}
+
+func (f *fakeReportUserStore) SignupsBySource(ctx context.Context, since time.Time) ([]generated.SignupsBySourceRow, error) {
+	f.since = since
+	return []generated.SignupsBySourceRow{
+		{SignupSource: "web", Signups: 12},
+		{SignupSource: "sso:accounts.google.com", Signups: 3},
+	}, nil
+}
+
+func TestReportService_Run_UnknownReport(t *testing.T) {
+	svc := NewReportService(nil)
+
+	_, err := svc.Run(context.Background(), "drop-tables", nil)
+	if !errors.Is(err, ErrReportNotFound) {
+		t.Errorf("Run() error = %v; want ErrReportNotFound", err)
+	}
+}
+
+func TestReportService_Run_InvalidParams(t *testing.T) {
+	svc := NewReportService(nil)
+
+	tests := []struct {
+		name   string
+		params map[string]string
+	}{
+		{name: "Non-numeric", params: map[string]string{"months": "abc"}},
+		{name: "Below minimum", params: map[string]string{"months": "0"}},
+		{name: "Above maximum", params: map[string]string{"months": "121"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Run(context.Background(), "signups-by-month", tt.params)
+			if !errors.Is(err, ErrInvalidReportParam) {
+				t.Errorf("Run() error = %v; want ErrInvalidReportParam", err)
+			}
+		})
+	}
+}
+
+func TestReportService_List(t *testing.T) {
+	svc := NewReportService(nil)
+
+	defs := svc.List()
+	if len(defs) != 7 {
+		t.Fatalf("List() returned %d reports; want 7", len(defs))
+	}
+	if defs[0].Name != "age-distribution" || defs[2].Name != "deactivated-users" || defs[6].Name != "users-by-age-bracket" {
+		t.Errorf("List() = %v; want reports sorted by name", defs)
+	}
+
+	svc.SetLoginHistory(nil)
+	if defs := svc.List(); len(defs) != 8 || defs[3].Name != "failed-logins-by-day" {
+		t.Errorf("List() with login history = %v; want failed-logins-by-day added", defs)
+	}
+}
+
+func TestReportService_SignupsBySource(t *testing.T) {
+	store := &fakeReportUserStore{}
+	svc := NewReportService(store)
+
+	result, err := svc.Run(context.Background(), "signups-by-source", map[string]string{"months": "1"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(result.Columns) != 2 || result.Columns[0] != "signup_source" {
+		t.Errorf("Columns = %v; want signup_source first", result.Columns)
+	}
+	if len(result.Rows) != 2 || result.Rows[1][0] != "sso:accounts.google.com" || result.Rows[1][1] != int64(3) {
+		t.Errorf("Rows = %v", result.Rows)
+	}
+
+	now := time.Now().UTC()
+	if want := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC); !store.since.Equal(want) {
+		t.Errorf("since = %v; want start of the current month %v", store.since, want)
+	}
+}
+
+func TestReportService_AgeDistribution(t *testing.T) {
+	store := &fakeReportUserStore{}
+	svc := NewReportService(store)
+
+	result, err := svc.Run(context.Background(), "age-distribution", nil)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if store.groupBy != "" || len(result.Columns) != 2 || len(result.Rows) != 1 || result.Rows[0][0] != "20-29" {
+		t.Errorf("ungrouped = %v %v (group %q); want one 20-29 bucket", result.Columns, result.Rows, store.groupBy)
+	}
+
+	result, err = svc.Run(context.Background(), "age-distribution", map[string]string{"group_by": "role", "bucket_size": "1"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Columns[0] != "role" || result.Rows[0][0] != "role" || result.Rows[0][1] != "2" || result.Options["group_by"] != "role" {
+		t.Errorf("by role = %v %v %v; want a role column and single-year buckets", result.Columns, result.Rows, result.Options)
+	}
+
+	if _, err := svc.Run(context.Background(), "age-distribution", map[string]string{"group_by": "country"}); !errors.Is(err, ErrInvalidReportParam) {
+		t.Errorf("Run() with unknown group_by error = %v; want ErrInvalidReportParam", err)
+	}
+}
+
+func TestReportService_AnonymizedUsers(t *testing.T) {
+	user := func(dob, created time.Time, role, accountType string) generated.ListUsersRow {
+		return generated.ListUsersRow{
+			Name:         "Jane",
+			Email:        "jane@example.com",
+			Dob:          pgtype.Date{Time: dob, Valid: true},
+			Role:         role,
+			Active:       true,
+			CreatedAt:    pgtype.Timestamp{Time: created, Valid: true},
+			AccountType:  accountType,
+			SignupSource: "web",
+		}
+	}
+	jan := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	store := &fakeReportUserStore{users: []generated.ListUsersRow{
+		user(time.Date(1990, 2, 1, 0, 0, 0, 0, time.UTC), jan, "user", "human"),
+		user(time.Date(1990, 11, 30, 0, 0, 0, 0, time.UTC), mar, "user", "human"),
+		user(time.Date(1990, 6, 15, 0, 0, 0, 0, time.UTC), mar, "user", "human"),
+		user(time.Date(1990, 6, 15, 0, 0, 0, 0, time.UTC), mar, "admin", "human"),
+		user(time.Date(1990, 6, 15, 0, 0, 0, 0, time.UTC), mar, "user", "service"),
+	}}
+	svc := NewReportService(store)
+
+	result, err := svc.Run(context.Background(), "anonymized-users", map[string]string{"k": "4"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	for _, column := range result.Columns {
+		if column == "name" || column == "email" || column == "id" || column == "dob" {
+			t.Errorf("Columns = %v; want no direct identifiers", result.Columns)
+		}
+	}
+	if len(result.Rows) != 0 || result.Suppressed != 4 {
+		t.Errorf("k=4 gave %v with %d suppressed; want every human user suppressed", result.Rows, result.Suppressed)
+	}
+
+	result, err = svc.Run(context.Background(), "anonymized-users", map[string]string{"k": "2"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(result.Rows) != 3 || result.Suppressed != 1 {
+		t.Fatalf("k=2 gave %v with %d suppressed; want the three 1990 users by year", result.Rows, result.Suppressed)
+	}
+	if row := result.Rows[0]; row[0] != 1990 || row[1] != "user" || row[3] != "2026" {
+		t.Errorf("Rows[0] = %v; want birth year 1990 and signup year 2026", row)
+	}
+
+	result, err = svc.Run(context.Background(), "anonymized-users", map[string]string{"k": "2", "cohort": "month"})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(result.Rows) != 2 || result.Suppressed != 2 || result.Rows[0][3] != "2026-03" {
+		t.Errorf("by month gave %v with %d suppressed; want the two March signups", result.Rows, result.Suppressed)
+	}
+
+	if _, err := svc.Run(context.Background(), "anonymized-users", map[string]string{"k": "1"}); !errors.Is(err, ErrInvalidReportParam) {
+		t.Errorf("Run() with k=1 error = %v; want ErrInvalidReportParam", err)
+	}
+}
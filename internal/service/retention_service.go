@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+var ErrRetentionCategoryNotFound = errors.New("retention category not found")
+
+// Data categories with a retention period.
+const (
+	RetentionLoginHistory  = "login_history"
+	RetentionExports       = "exports"
+	RetentionRevokedTokens = "revoked_tokens"
+	RetentionSessions      = "sessions"
+	RetentionNotifications = "notifications"
+	RetentionRateLimits    = "rate_limit_counters"
+	RetentionLoginLockouts = "login_lockouts"
+	RetentionMagicLinks    = "magic_link_redemptions"
+	RetentionSSOLogins     = "sso_pending_logins"
+)
+
+// RetentionTarget is a store whose records expire. Both the login history
+// repositories and ExportService implement it.
+type RetentionTarget interface {
+	RetentionStats(ctx context.Context) (int64, *time.Time, error)
+	PruneBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type RetentionStatus struct {
+	Category         string     `json:"category"`
+	RetentionSeconds int64      `json:"retention_seconds"`
+	Records          int64      `json:"records"`
+	OldestRecordAt   *time.Time `json:"oldest_record_at,omitempty"`
+	PruneBefore      *time.Time `json:"prune_before,omitempty"`
+	LastPrunedAt     *time.Time `json:"last_pruned_at,omitempty"`
+	LastPrunedCount  int64      `json:"last_pruned_count"`
+	LastError        string     `json:"last_error,omitempty"`
+}
+
+type retentionCategory struct {
+	name      string
+	retention time.Duration
+	target    RetentionTarget
+
+	lastPrunedAt *time.Time
+	lastPruned   int64
+	lastError    string
+}
+
+// RetentionService deletes records older than their category's retention
+// period and reports what each category currently holds.
+type RetentionService struct {
+	logger *zap.Logger
+
+	mu         sync.Mutex
+	categories []*retentionCategory
+}
+
+func NewRetentionService(logger *zap.Logger) *RetentionService {
+	return &RetentionService{logger: logger}
+}
+
+// Register adds a category. A retention of zero or less keeps its records
+// indefinitely.
+func (s *RetentionService) Register(name string, retention time.Duration, target RetentionTarget) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.categories = append(s.categories, &retentionCategory{
+		name:      name,
+		retention: retention,
+		target:    target,
+	})
+}
+
+// Prune deletes expired records in every category. A failing category is
+// logged and does not stop the others.
+func (s *RetentionService) Prune(ctx context.Context) {
+	s.mu.Lock()
+	categories := append([]*retentionCategory(nil), s.categories...)
+	s.mu.Unlock()
+
+	for _, cat := range categories {
+		if cat.retention <= 0 {
+			continue
+		}
+
+		now := time.Now()
+		n, err := cat.target.PruneBefore(ctx, now.Add(-cat.retention))
+
+		s.mu.Lock()
+		cat.lastPrunedAt = &now
+		cat.lastPruned = n
+		cat.lastError = ""
+		if err != nil {
+			cat.lastError = err.Error()
+		}
+		s.mu.Unlock()
+
+		if err != nil {
+			if ctx.Err() == nil {
+				s.logger.Error("failed to prune expired records", zap.String("category", cat.name), zap.Error(err))
+			}
+			continue
+		}
+		if n > 0 {
+			s.logger.Info("pruned expired records", zap.String("category", cat.name), zap.Int64("deleted", n))
+		}
+	}
+}
+
+func (s *RetentionService) Status(ctx context.Context) ([]RetentionStatus, error) {
+	s.mu.Lock()
+	categories := append([]*retentionCategory(nil), s.categories...)
+	s.mu.Unlock()
+
+	statuses := make([]RetentionStatus, 0, len(categories))
+	for _, cat := range categories {
+		status, err := s.status(ctx, cat)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (s *RetentionService) CategoryStatus(ctx context.Context, name string) (RetentionStatus, error) {
+	s.mu.Lock()
+	var found *retentionCategory
+	for _, cat := range s.categories {
+		if cat.name == name {
+			found = cat
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if found == nil {
+		return RetentionStatus{}, ErrRetentionCategoryNotFound
+	}
+	return s.status(ctx, found)
+}
+
+func (s *RetentionService) status(ctx context.Context, cat *retentionCategory) (RetentionStatus, error) {
+	records, oldest, err := cat.target.RetentionStats(ctx)
+	if err != nil {
+		return RetentionStatus{}, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := RetentionStatus{
+		Category:         cat.name,
+		RetentionSeconds: int64(cat.retention.Seconds()),
+		Records:          records,
+		OldestRecordAt:   oldest,
+		LastPrunedAt:     cat.lastPrunedAt,
+		LastPrunedCount:  cat.lastPruned,
+		LastError:        cat.lastError,
+	}
+	if cat.retention > 0 {
+		cutoff := time.Now().Add(-cat.retention)
+		status.PruneBefore = &cutoff
+	}
+	return status, nil
+}
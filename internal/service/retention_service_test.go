@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type fakeRetentionTarget struct {
+	records []time.Time
+	err     error
+}
+
+func (f *fakeRetentionTarget) RetentionStats(ctx context.Context) (int64, *time.Time, error) {
+	var oldest *time.Time
+	for i := range f.records {
+		if oldest == nil || f.records[i].Before(*oldest) {
+			oldest = &f.records[i]
+		}
+	}
+	return int64(len(f.records)), oldest, nil
+}
+
+func (f *fakeRetentionTarget) PruneBefore(ctx context.Context, before time.Time) (int64, error) {
+	if f.err != nil {
+		return 0, f.err
+	}
+	var kept []time.Time
+	for _, t := range f.records {
+		if !t.Before(before) {
+			kept = append(kept, t)
+		}
+	}
+	n := int64(len(f.records) - len(kept))
+	f.records = kept
+	return n, nil
+}
+
+func TestRetentionPrune(t *testing.T) {
+	now := time.Now()
+	history := &fakeRetentionTarget{records: []time.Time{now.Add(-48 * time.Hour), now.Add(-time.Hour), now}}
+	forever := &fakeRetentionTarget{records: []time.Time{now.Add(-1000 * time.Hour)}}
+	broken := &fakeRetentionTarget{err: errors.New("connection refused")}
+
+	s := NewRetentionService(zap.NewNop())
+	s.Register(RetentionLoginHistory, 24*time.Hour, history)
+	s.Register("forever", 0, forever)
+	s.Register("broken", time.Hour, broken)
+	s.Prune(context.Background())
+
+	if len(history.records) != 2 {
+		t.Errorf("login history has %d records after pruning; want 2", len(history.records))
+	}
+	if len(forever.records) != 1 {
+		t.Error("a category without a retention period must not be pruned")
+	}
+
+	statuses, err := s.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	if len(statuses) != 3 {
+		t.Fatalf("got %d statuses; want 3", len(statuses))
+	}
+
+	got := statuses[0]
+	if got.Category != RetentionLoginHistory || got.Records != 2 || got.LastPrunedCount != 1 || got.RetentionSeconds != 86400 {
+		t.Errorf("unexpected login history status %+v", got)
+	}
+	if got.LastPrunedAt == nil || got.PruneBefore == nil || got.OldestRecordAt == nil {
+		t.Errorf("expected prune times and oldest record to be set: %+v", got)
+	}
+	if statuses[1].PruneBefore != nil || statuses[1].LastPrunedAt != nil {
+		t.Errorf("unexpected status for kept category %+v", statuses[1])
+	}
+	if statuses[2].LastError != "connection refused" {
+		t.Errorf("last error = %q; want the prune error", statuses[2].LastError)
+	}
+}
+
+func TestRetentionCategoryStatus(t *testing.T) {
+	s := NewRetentionService(zap.NewNop())
+	s.Register(RetentionExports, time.Hour, &fakeRetentionTarget{})
+
+	if _, err := s.CategoryStatus(context.Background(), RetentionExports); err != nil {
+		t.Errorf("CategoryStatus: %v", err)
+	}
+	if _, err := s.CategoryStatus(context.Background(), "sessions"); !errors.Is(err, ErrRetentionCategoryNotFound) {
+		t.Errorf("expected ErrRetentionCategoryNotFound, got %v", err)
+	}
+}
+
+func TestExportPruneBefore(t *testing.T) {
+	s := newTestExportService(time.Minute)
+	s.exports["old"] = &Export{ID: "old", Status: ExportFailed, CreatedAt: time.Now().Add(-48 * time.Hour)}
+
+	n, err := s.PruneBefore(context.Background(), time.Now().Add(-24*time.Hour))
+	if err != nil || n != 1 {
+		t.Fatalf("PruneBefore = %d, %v; want 1, nil", n, err)
+	}
+	records, _, _ := s.RetentionStats(context.Background())
+	if records != 2 {
+		t.Errorf("got %d exports after pruning; want 2", records)
+	}
+}
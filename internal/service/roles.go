@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/repository"
+)
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+	// RoleOrgAdmin may use some of the /admin user endpoints, but only on
+	// members of their own organization.
+	RoleOrgAdmin = "org_admin"
+)
+
+// ValidRole reports whether role is one the users table accepts.
+func ValidRole(role string) bool {
+	switch role {
+	case RoleUser, RoleOrgAdmin, RoleModerator, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// RoleChange is a change of a user's role. ActorID is who made it, 0 when
+// the system did, e.g. syncing the role from an identity provider.
+type RoleChange struct {
+	UserID    int64
+	From      string
+	To        string
+	ActorID   int64
+	IPAddress string
+	// Source, if set, is recorded with the security event.
+	Source string
+}
+
+// RoleChanger changes users' roles the audited way: the new role is saved,
+// the user's tokens are revoked so the old role stops working at once, and
+// a role_changed security event is recorded. Without revocations or a
+// security log those steps are skipped.
+type RoleChanger struct {
+	repo        repository.UserStore
+	revocations *TokenRevocationService
+	securityLog *SecurityLogService
+	logger      *zap.Logger
+}
+
+func NewRoleChanger(repo repository.UserStore, revocations *TokenRevocationService, securityLog *SecurityLogService, logger *zap.Logger) *RoleChanger {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &RoleChanger{repo: repo, revocations: revocations, securityLog: securityLog, logger: logger}
+}
+
+// Change saves and audits change. The role is saved even if revoking the
+// user's tokens then fails, which is returned as an error.
+func (r *RoleChanger) Change(ctx context.Context, change RoleChange) (generated.UpdateUserRoleRow, error) {
+	user, err := r.repo.UpdateRole(ctx, change.UserID, change.To)
+	if err != nil {
+		return generated.UpdateUserRoleRow{}, err
+	}
+	if r.revocations != nil {
+		if err := r.revocations.RevokeUser(ctx, user.ID); err != nil {
+			return user, err
+		}
+	}
+
+	if r.securityLog != nil {
+		details := map[string]string{"from": change.From, "to": user.Role}
+		if change.Source != "" {
+			details["source"] = change.Source
+		}
+		err := r.securityLog.Record(ctx, SecurityEvent{
+			Type:      SecurityEventRoleChanged,
+			ActorID:   change.ActorID,
+			TargetID:  user.ID,
+			Details:   details,
+			IPAddress: change.IPAddress,
+		})
+		if err != nil {
+			r.logger.Error("failed to record security event", zap.String("event", SecurityEventRoleChanged), zap.Error(err))
+		}
+	}
+	return user, nil
+}
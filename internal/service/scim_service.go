@@ -0,0 +1,425 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/models"
+	"BACKEND/internal/repository"
+)
+
+const (
+	scimDefaultCount = 100
+	scimMaxCount     = 200
+)
+
+var (
+	ErrSCIMInvalidFilter = errors.New("invalid filter")
+	ErrSCIMInvalidValue  = errors.New("invalid value")
+	ErrSCIMMutability    = errors.New("attribute is immutable")
+)
+
+var scimUserNameFilter = regexp.MustCompile(`(?i)^userName\s+eq\s+"([^"]+)"$`)
+
+type SCIMService struct {
+	repo    repository.UserStore
+	auth    *AuthService
+	baseURL string
+}
+
+func NewSCIMService(repo repository.UserStore, auth *AuthService, baseURL string) *SCIMService {
+	return &SCIMService{
+		repo:    repo,
+		auth:    auth,
+		baseURL: strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// Get, Replace, Patch and Delete take the SCIM resource id, which is the
+// user's public ID.
+func (s *SCIMService) Get(ctx context.Context, publicID string) (*models.SCIMUser, error) {
+	id, err := s.userID(ctx, publicID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err)
+	}
+	return s.toSCIMUser(user), nil
+}
+
+func (s *SCIMService) List(ctx context.Context, filter string, startIndex, count int) (*models.SCIMListResponse, error) {
+	if startIndex < 1 {
+		startIndex = 1
+	}
+	if count < 0 {
+		count = 0
+	}
+	if count == 0 && filter == "" {
+		count = scimDefaultCount
+	}
+	if count > scimMaxCount {
+		count = scimMaxCount
+	}
+
+	resp := &models.SCIMListResponse{
+		Schemas:    []string{models.SCIMSchemaListResponse},
+		StartIndex: startIndex,
+		Resources:  []models.SCIMUser{},
+	}
+
+	if filter != "" {
+		match := scimUserNameFilter.FindStringSubmatch(strings.TrimSpace(filter))
+		if match == nil {
+			return nil, fmt.Errorf("%w: only 'userName eq \"value\"' filters are supported", ErrSCIMInvalidFilter)
+		}
+
+		user, err := s.repo.GetByEmail(ctx, match[1])
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return resp, nil
+			}
+			return nil, err
+		}
+
+		resp.TotalResults = 1
+		resp.ItemsPerPage = 1
+		resp.Resources = append(resp.Resources, *s.toSCIMUser(generated.GetUserByIDRow{
+			ID:        user.ID,
+			Name:      user.Name,
+			Dob:       user.Dob,
+			Email:     user.Email,
+			Role:      user.Role,
+			Active:    user.Active,
+			CreatedAt: user.CreatedAt,
+			UpdatedAt: user.UpdatedAt,
+			PublicID:  user.PublicID,
+		}))
+		return resp, nil
+	}
+
+	total, err := s.repo.Count(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp.TotalResults = total
+
+	users, err := s.repo.ListPaginated(ctx, int32(count), int32(startIndex-1))
+	if err != nil {
+		return nil, err
+	}
+	for _, user := range users {
+		resp.Resources = append(resp.Resources, *s.toSCIMUser(generated.GetUserByIDRow(user)))
+	}
+	resp.ItemsPerPage = len(resp.Resources)
+
+	return resp, nil
+}
+
+func (s *SCIMService) Create(ctx context.Context, in models.SCIMUser) (*models.SCIMUser, error) {
+	email, err := scimEmail(in)
+	if err != nil {
+		return nil, err
+	}
+	name, err := scimDisplayName(in)
+	if err != nil {
+		return nil, err
+	}
+	dob, err := scimDob(in)
+	if err != nil {
+		return nil, err
+	}
+
+	role := "user"
+	if in.Extension != nil && in.Extension.Role != "" {
+		role = in.Extension.Role
+	}
+	if !ValidRole(role) {
+		return nil, fmt.Errorf("%w: role must be user, moderator or admin", ErrSCIMInvalidValue)
+	}
+
+	password := in.Password
+	if password == "" {
+		// Provisioned users authenticate through the IdP; give them a
+		// random password nobody knows so the column constraint holds.
+		password, err = randomPassword()
+		if err != nil {
+			return nil, err
+		}
+	} else if err := s.auth.ValidatePasswordStrength(password); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSCIMInvalidValue, err.Error())
+	}
+
+	hash, err := s.auth.HashPassword(password)
+	if err != nil {
+		return nil, err
+	}
+
+	created, err := s.repo.CreateWithAuth(ctx, name, email, hash, role, SignupSourceSCIM, dob)
+	if err != nil {
+		if errors.Is(err, ErrEmailAlreadyExists) {
+			return nil, ErrEmailAlreadyExists
+		}
+		return nil, err
+	}
+
+	user := generated.GetUserByIDRow(created)
+	if in.Active != nil && !*in.Active {
+		updated, err := s.repo.SetActive(ctx, user.ID, false)
+		if err != nil {
+			return nil, err
+		}
+		user = generated.GetUserByIDRow(updated)
+	}
+
+	return s.toSCIMUser(user), nil
+}
+
+func (s *SCIMService) Replace(ctx context.Context, publicID string, in models.SCIMUser) (*models.SCIMUser, error) {
+	id, err := s.userID(ctx, publicID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err)
+	}
+
+	email, err := scimEmail(in)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(email, current.Email) {
+		return nil, fmt.Errorf("%w: userName cannot be changed", ErrSCIMMutability)
+	}
+	if in.Extension != nil && in.Extension.Role != "" && in.Extension.Role != current.Role {
+		return nil, fmt.Errorf("%w: role cannot be changed via SCIM", ErrSCIMMutability)
+	}
+
+	name, err := scimDisplayName(in)
+	if err != nil {
+		return nil, err
+	}
+
+	dob := current.Dob.Time
+	if in.Extension != nil && in.Extension.Dob != "" {
+		if dob, err = scimDob(in); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := s.repo.Update(ctx, id, name, dob)
+	if err != nil {
+		return nil, mapNotFound(err)
+	}
+	user := generated.GetUserByIDRow(updated)
+
+	if in.Active != nil && *in.Active != user.Active {
+		row, err := s.repo.SetActive(ctx, id, *in.Active)
+		if err != nil {
+			return nil, mapNotFound(err)
+		}
+		user = generated.GetUserByIDRow(row)
+	}
+
+	return s.toSCIMUser(user), nil
+}
+
+// Patch supports the operations identity providers send in practice:
+// toggling "active" and renaming the user.
+func (s *SCIMService) Patch(ctx context.Context, publicID string, req models.SCIMPatchRequest) (*models.SCIMUser, error) {
+	id, err := s.userID(ctx, publicID)
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, mapNotFound(err)
+	}
+
+	active := current.Active
+	name := current.Name
+
+	for _, op := range req.Operations {
+		switch strings.ToLower(op.Op) {
+		case "replace", "add":
+		default:
+			return nil, fmt.Errorf("%w: unsupported patch op %q", ErrSCIMInvalidValue, op.Op)
+		}
+
+		values := map[string]interface{}{}
+		if op.Path == "" {
+			m, ok := op.Value.(map[string]interface{})
+			if !ok {
+				return nil, fmt.Errorf("%w: patch value must be an object when no path is given", ErrSCIMInvalidValue)
+			}
+			values = m
+		} else {
+			values[op.Path] = op.Value
+		}
+
+		for path, value := range values {
+			switch strings.ToLower(path) {
+			case "active":
+				b, err := scimBool(value)
+				if err != nil {
+					return nil, err
+				}
+				active = b
+			case "displayname", "name.formatted":
+				str, ok := value.(string)
+				if !ok || len(strings.TrimSpace(str)) < 2 {
+					return nil, fmt.Errorf("%w: %s must be at least 2 characters", ErrSCIMInvalidValue, path)
+				}
+				name = strings.TrimSpace(str)
+			default:
+				return nil, fmt.Errorf("%w: unsupported patch path %q", ErrSCIMInvalidValue, path)
+			}
+		}
+	}
+
+	user := current
+	if name != current.Name {
+		updated, err := s.repo.Update(ctx, id, name, current.Dob.Time)
+		if err != nil {
+			return nil, mapNotFound(err)
+		}
+		user = generated.GetUserByIDRow(updated)
+	}
+	if active != current.Active {
+		updated, err := s.repo.SetActive(ctx, id, active)
+		if err != nil {
+			return nil, mapNotFound(err)
+		}
+		user = generated.GetUserByIDRow(updated)
+	}
+
+	return s.toSCIMUser(user), nil
+}
+
+func (s *SCIMService) Delete(ctx context.Context, publicID string) error {
+	id, err := s.userID(ctx, publicID)
+	if err != nil {
+		return err
+	}
+	return mapNotFound(s.repo.SoftDelete(ctx, id))
+}
+
+func (s *SCIMService) userID(ctx context.Context, publicID string) (int64, error) {
+	id, err := s.repo.GetIDByPublicID(ctx, publicID)
+	if err != nil {
+		return 0, mapNotFound(err)
+	}
+	return id, nil
+}
+
+func (s *SCIMService) toSCIMUser(u generated.GetUserByIDRow) *models.SCIMUser {
+	id := u.PublicID.String()
+	active := u.Active
+
+	return &models.SCIMUser{
+		Schemas:     []string{models.SCIMSchemaUser, models.SCIMSchemaUserExt},
+		ID:          id,
+		UserName:    u.Email,
+		Name:        &models.SCIMName{Formatted: u.Name},
+		DisplayName: u.Name,
+		Emails:      []models.SCIMEmail{{Value: u.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Extension: &models.SCIMUserExtension{
+			Dob:  u.Dob.Time.Format("2006-01-02"),
+			Role: u.Role,
+		},
+		Meta: &models.SCIMMeta{
+			ResourceType: "User",
+			Created:      u.CreatedAt.Time.Format(time.RFC3339),
+			LastModified: u.UpdatedAt.Time.Format(time.RFC3339),
+			Location:     s.baseURL + "/scim/v2/Users/" + id,
+		},
+	}
+}
+
+func scimEmail(in models.SCIMUser) (string, error) {
+	email := in.UserName
+	for _, e := range in.Emails {
+		if e.Primary {
+			email = e.Value
+			break
+		}
+	}
+	if email == "" && len(in.Emails) > 0 {
+		email = in.Emails[0].Value
+	}
+
+	if _, err := mail.ParseAddress(email); err != nil || email == "" {
+		return "", fmt.Errorf("%w: userName or primary email must be a valid email address", ErrSCIMInvalidValue)
+	}
+	return strings.ToLower(email), nil
+}
+
+func scimDisplayName(in models.SCIMUser) (string, error) {
+	name := in.DisplayName
+	if name == "" && in.Name != nil {
+		name = in.Name.Formatted
+		if name == "" {
+			name = strings.TrimSpace(in.Name.GivenName + " " + in.Name.FamilyName)
+		}
+	}
+	name = strings.TrimSpace(name)
+	if len(name) < 2 {
+		return "", fmt.Errorf("%w: displayName or name must be at least 2 characters", ErrSCIMInvalidValue)
+	}
+	return name, nil
+}
+
+func scimDob(in models.SCIMUser) (time.Time, error) {
+	if in.Extension == nil || in.Extension.Dob == "" {
+		return time.Time{}, fmt.Errorf("%w: %s:dob is required", ErrSCIMInvalidValue, models.SCIMSchemaUserExt)
+	}
+	dob, err := ParseDob(in.Extension.Dob)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: dob must use YYYY-MM-DD", ErrSCIMInvalidValue)
+	}
+	return dob, nil
+}
+
+// scimBool accepts both JSON booleans and the "True"/"False" strings some
+// identity providers (notably Azure AD) send.
+func scimBool(v interface{}) (bool, error) {
+	switch b := v.(type) {
+	case bool:
+		return b, nil
+	case string:
+		parsed, err := strconv.ParseBool(b)
+		if err == nil {
+			return parsed, nil
+		}
+	}
+	return false, fmt.Errorf("%w: active must be a boolean", ErrSCIMInvalidValue)
+}
+
+func randomPassword() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func mapNotFound(err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	return err
+}
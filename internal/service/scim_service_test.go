@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"BACKEND/internal/models"
+)
+
+func TestSCIMEmail(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       models.SCIMUser
+		expected string
+		wantErr  bool
+	}{
+		{
+			name:     "userName is an email",
+			in:       models.SCIMUser{UserName: "Jane@Example.com"},
+			expected: "jane@example.com",
+		},
+		{
+			name: "primary email wins over userName",
+			in: models.SCIMUser{
+				UserName: "jdoe",
+				Emails: []models.SCIMEmail{
+					{Value: "other@example.com"},
+					{Value: "jane@example.com", Primary: true},
+				},
+			},
+			expected: "jane@example.com",
+		},
+		{
+			name:    "no usable email",
+			in:      models.SCIMUser{UserName: "jdoe"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := scimEmail(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrSCIMInvalidValue) {
+					t.Errorf("scimEmail() error = %v; want ErrSCIMInvalidValue", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("scimEmail() unexpected error: %v", err)
+			}
+			if got != tt.expected {
+				t.Errorf("scimEmail() = %q; want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSCIMDisplayName(t *testing.T) {
+	got, err := scimDisplayName(models.SCIMUser{Name: &models.SCIMName{GivenName: "Jane", FamilyName: "Doe"}})
+	if err != nil || got != "Jane Doe" {
+		t.Errorf("scimDisplayName() = %q, %v; want %q", got, err, "Jane Doe")
+	}
+
+	if _, err := scimDisplayName(models.SCIMUser{DisplayName: "J"}); !errors.Is(err, ErrSCIMInvalidValue) {
+		t.Errorf("scimDisplayName() error = %v; want ErrSCIMInvalidValue", err)
+	}
+}
+
+func TestSCIMBool(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected bool
+		wantErr  bool
+	}{
+		{value: false, expected: false},
+		{value: true, expected: true},
+		{value: "False", expected: false},
+		{value: "True", expected: true},
+		{value: "nope", wantErr: true},
+		{value: 1.0, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := scimBool(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("scimBool(%v) expected error", tt.value)
+			}
+			continue
+		}
+		if err != nil || got != tt.expected {
+			t.Errorf("scimBool(%v) = %v, %v; want %v", tt.value, got, err, tt.expected)
+		}
+	}
+}
+
+func TestSCIMUserNameFilter(t *testing.T) {
+	match := scimUserNameFilter.FindStringSubmatch(`userName eq "jane@example.com"`)
+	if match == nil || match[1] != "jane@example.com" {
+		t.Errorf("expected filter to match and capture the email, got %v", match)
+	}
+
+	if scimUserNameFilter.MatchString(`name.givenName sw "J"`) {
+		t.Error("expected unsupported filter not to match")
+	}
+}
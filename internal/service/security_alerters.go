@@ -0,0 +1,92 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"BACKEND/hooks"
+	"BACKEND/internal/mailer"
+	"BACKEND/internal/templates"
+)
+
+// WebhookAlerter POSTs {"event": "security.alert", "alert": ...} to url,
+// signed like hooks when secret is set.
+type WebhookAlerter struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func NewWebhookAlerter(url, secret string, timeout time.Duration) *WebhookAlerter {
+	return &WebhookAlerter{
+		url:    url,
+		secret: secret,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (a *WebhookAlerter) Alert(ctx context.Context, alert SecurityAlert) error {
+	body, err := json.Marshal(struct {
+		Event string        `json:"event"`
+		Alert SecurityAlert `json:"alert"`
+	}{"security.alert", alert})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if a.secret != "" {
+		req.Header.Set(hooks.SignatureHeader, hooks.Sign(a.secret, body))
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("security alert webhook: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("security alert webhook: endpoint returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// EmailAlerter emails alerts to a fixed list of admin addresses.
+type EmailAlerter struct {
+	mailer   mailer.Mailer
+	renderer *templates.Renderer
+	to       []string
+}
+
+func NewEmailAlerter(m mailer.Mailer, renderer *templates.Renderer, to []string) *EmailAlerter {
+	return &EmailAlerter{
+		mailer:   m,
+		renderer: renderer,
+		to:       to,
+	}
+}
+
+func (a *EmailAlerter) Alert(ctx context.Context, alert SecurityAlert) error {
+	email, err := a.renderer.Render("security_alert", "", map[string]interface{}{
+		"Name":        "admin",
+		"Rule":        alert.Rule,
+		"Key":         alert.Key,
+		"Description": alert.Description,
+		"Failures":    alert.Failures,
+		"Window":      alert.Window,
+		"IPs":         alert.IPs,
+		"Accounts":    alert.Accounts,
+		"DetectedAt":  alert.DetectedAt.UTC().Format(time.RFC1123),
+	})
+	if err != nil {
+		return err
+	}
+	return a.mailer.Send(ctx, a.to, email)
+}
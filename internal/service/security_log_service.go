@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/models"
+	"BACKEND/internal/repository"
+)
+
+// Security event types.
+const (
+	SecurityEventAdminLogin         = "admin_login"
+	SecurityEventRoleChanged        = "role_changed"
+	SecurityEventUserDeactivated    = "user_deactivated"
+	SecurityEventUserActivated      = "user_activated"
+	SecurityEventUserDeleted        = "user_deleted"
+	SecurityEventUserRestored       = "user_restored"
+	SecurityEventForceLogout        = "force_logout"
+	SecurityEventForcePasswordReset = "force_password_reset"
+	SecurityEventAccountUnlocked    = "account_unlocked"
+)
+
+// appendAttempts is how often Record retries when another instance
+// appended first.
+const appendAttempts = 5
+
+// verifyBatch is how many events Verify reads at a time.
+const verifyBatch = 500
+
+// SecurityEvent is an event to record. ActorID is the user who acted and
+// TargetID the user acted on; either is 0 when there is none.
+type SecurityEvent struct {
+	Type      string
+	ActorID   int64
+	TargetID  int64
+	Details   map[string]string
+	IPAddress string
+}
+
+// SecurityLogService keeps an append-only log of security events, such as
+// role changes and admin logins. Each event is hashed together with the
+// hash of the event before it, so changing, removing or reordering events
+// breaks the chain, which Verify detects.
+type SecurityLogService struct {
+	store         repository.SecurityEventStore
+	notifications *NotificationService
+	now           func() time.Time
+
+	// mu orders appends from this instance; the unique prev_hash orders
+	// them across instances.
+	mu sync.Mutex
+}
+
+func NewSecurityLogService(store repository.SecurityEventStore) *SecurityLogService {
+	return &SecurityLogService{store: store, now: time.Now}
+}
+
+// SetNotifications routes recorded events through the notification rules.
+func (s *SecurityLogService) SetNotifications(n *NotificationService) {
+	s.notifications = n
+}
+
+// Record appends e to the log and publishes it to the notification rules,
+// as about its target or, without one, its actor.
+func (s *SecurityLogService) Record(ctx context.Context, e SecurityEvent) error {
+	details := ""
+	if len(e.Details) > 0 {
+		b, err := json.Marshal(e.Details)
+		if err != nil {
+			return err
+		}
+		details = string(b)
+	}
+	event := generated.SecurityEvent{
+		EventType: e.Type,
+		ActorID:   pgtype.Int8{Int64: e.ActorID, Valid: e.ActorID != 0},
+		TargetID:  pgtype.Int8{Int64: e.TargetID, Valid: e.TargetID != 0},
+		Details:   details,
+		IpAddress: e.IPAddress,
+		// Both databases keep microseconds, and the hash must match what
+		// is read back.
+		CreatedAt: pgtype.Timestamp{Time: s.now().UTC().Truncate(time.Microsecond), Valid: true},
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for attempt := 1; ; attempt++ {
+		prev, err := s.store.LastHash(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to read security log head: %w", err)
+		}
+		event.PrevHash = prev
+		event.Hash = securityEventHash(event)
+
+		_, err = s.store.Append(ctx, event)
+		if errors.Is(err, repository.ErrUniqueViolation) && attempt < appendAttempts {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to append security event: %w", err)
+		}
+		if s.notifications != nil {
+			about := e.TargetID
+			if about == 0 {
+				about = e.ActorID
+			}
+			s.notifications.Publish(ctx, NotificationEvent{Type: e.Type, UserID: about, Details: e.Details})
+		}
+		return nil
+	}
+}
+
+// List returns up to limit events before beforeID, newest first; 0 starts
+// from the newest.
+func (s *SecurityLogService) List(ctx context.Context, beforeID int64, limit int) ([]models.SecurityEventResponse, error) {
+	if beforeID <= 0 {
+		beforeID = math.MaxInt64
+	}
+	rows, err := s.store.List(ctx, beforeID, int32(limit))
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]models.SecurityEventResponse, len(rows))
+	for i, row := range rows {
+		events[i] = models.SecurityEventResponse{
+			ID:        row.ID,
+			Type:      row.EventType,
+			IPAddress: row.IpAddress,
+			CreatedAt: row.CreatedAt.Time,
+			Hash:      row.Hash,
+		}
+		if row.ActorPublicID.Valid {
+			events[i].ActorID = row.ActorPublicID.String()
+		}
+		if row.TargetPublicID.Valid {
+			events[i].TargetID = row.TargetPublicID.String()
+		}
+		if row.Details != "" {
+			// Details were written by Record, so they are a JSON object.
+			_ = json.Unmarshal([]byte(row.Details), &events[i].Details)
+		}
+	}
+	return events, nil
+}
+
+// Verify walks the whole log, oldest first, checking that each event
+// follows the one before it and that its hash matches its contents.
+func (s *SecurityLogService) Verify(ctx context.Context) (models.SecurityLogVerification, error) {
+	result := models.SecurityLogVerification{Valid: true}
+	var afterID int64
+	for {
+		events, err := s.store.ListAfter(ctx, afterID, verifyBatch)
+		if err != nil {
+			return models.SecurityLogVerification{}, err
+		}
+		for _, e := range events {
+			switch {
+			case e.PrevHash != result.HeadHash:
+				result.Problem = "does not follow the event before it"
+			case e.Hash != securityEventHash(e):
+				result.Problem = "does not match its hash"
+			}
+			if result.Problem != "" {
+				result.Valid = false
+				result.BrokenAt = e.ID
+				return result, nil
+			}
+			result.Events++
+			result.HeadHash = e.Hash
+			afterID = e.ID
+		}
+		if len(events) < verifyBatch {
+			return result, nil
+		}
+	}
+}
+
+// securityEventHash hashes an event's contents and the hash of the event
+// before it. Fields are JSON-encoded so none can run into the next.
+func securityEventHash(e generated.SecurityEvent) string {
+	var actorID, targetID *int64
+	if e.ActorID.Valid {
+		actorID = &e.ActorID.Int64
+	}
+	if e.TargetID.Valid {
+		targetID = &e.TargetID.Int64
+	}
+	b, _ := json.Marshal([]interface{}{
+		e.PrevHash,
+		e.EventType,
+		actorID,
+		targetID,
+		e.Details,
+		e.IpAddress,
+		e.CreatedAt.Time.UTC().Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/repository"
+)
+
+// fakeSecurityEventStore keeps events in order. Before an append it lets
+// race add an event, as another instance would.
+type fakeSecurityEventStore struct {
+	events []generated.SecurityEvent
+	race   func()
+}
+
+func (f *fakeSecurityEventStore) Append(ctx context.Context, e generated.SecurityEvent) (int64, error) {
+	if f.race != nil {
+		race := f.race
+		f.race = nil
+		race()
+	}
+	for _, existing := range f.events {
+		if existing.PrevHash == e.PrevHash {
+			return 0, &repository.ConstraintError{Kind: repository.ErrUniqueViolation, Constraint: "security_events_prev_hash_key"}
+		}
+	}
+	e.ID = int64(len(f.events) + 1)
+	f.events = append(f.events, e)
+	return e.ID, nil
+}
+
+func (f *fakeSecurityEventStore) LastHash(ctx context.Context) (string, error) {
+	if len(f.events) == 0 {
+		return "", nil
+	}
+	return f.events[len(f.events)-1].Hash, nil
+}
+
+func (f *fakeSecurityEventStore) ListAfter(ctx context.Context, afterID int64, limit int32) ([]generated.SecurityEvent, error) {
+	var events []generated.SecurityEvent
+	for _, e := range f.events {
+		if e.ID > afterID && len(events) < int(limit) {
+			events = append(events, e)
+		}
+	}
+	return events, nil
+}
+
+func (f *fakeSecurityEventStore) List(ctx context.Context, beforeID int64, limit int32) ([]generated.ListSecurityEventsRow, error) {
+	return nil, nil
+}
+
+func TestSecurityLogService_RecordAndVerify(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeSecurityEventStore{}
+	svc := NewSecurityLogService(store)
+	svc.now = func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 123456789, time.UTC) }
+
+	if err := svc.Record(ctx, SecurityEvent{Type: SecurityEventAdminLogin, ActorID: 1}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	// Another instance appends first; Record retries after the new head.
+	store.race = func() {
+		_ = NewSecurityLogService(store).Record(ctx, SecurityEvent{Type: SecurityEventForceLogout, ActorID: 1, TargetID: 3})
+	}
+	if err := svc.Record(ctx, SecurityEvent{Type: SecurityEventRoleChanged, ActorID: 1, TargetID: 2, Details: map[string]string{"from": "user", "to": "admin"}, IPAddress: "10.0.0.1"}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	result, err := svc.Verify(ctx)
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if !result.Valid || result.Events != 3 || result.HeadHash != store.events[2].Hash {
+		t.Fatalf("Verify() = %+v; want 3 valid events", result)
+	}
+
+	store.events[2].Details = `{"from":"user","to":"moderator"}`
+	if result, _ := svc.Verify(ctx); result.Valid || result.BrokenAt != 3 {
+		t.Errorf("Verify() after editing an event = %+v; want broken at 3", result)
+	}
+
+	store.events = append(store.events[:1], store.events[2:]...)
+	if result, _ := svc.Verify(ctx); result.Valid || result.BrokenAt != 3 || result.Problem != "does not follow the event before it" {
+		t.Errorf("Verify() after removing an event = %+v; want broken at 3", result)
+	}
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/models"
+	"BACKEND/internal/repository"
+)
+
+const (
+	AccountTypeHuman   = "human"
+	AccountTypeService = "service"
+
+	apiKeyPrefix        = "uak_"
+	apiKeyDisplayLength = 12
+	serviceAccountEmail = "service-accounts.invalid"
+)
+
+// Scopes an API key may carry. "admin" is required on /admin routes in
+// addition to the account's role.
+const (
+	ScopeUsersRead  = "users:read"
+	ScopeUsersWrite = "users:write"
+	ScopeAdmin      = "admin"
+)
+
+var knownScopes = map[string]bool{
+	ScopeUsersRead:  true,
+	ScopeUsersWrite: true,
+	ScopeAdmin:      true,
+}
+
+var (
+	ErrNotServiceAccount      = errors.New("user is not a service account")
+	ErrInvalidScope           = errors.New("invalid scope")
+	ErrInvalidAPIKey          = errors.New("invalid or expired api key")
+	ErrAPIKeyNotFound         = errors.New("api key not found")
+	ErrInteractiveLoginDenied = errors.New("service accounts cannot log in interactively")
+)
+
+var slugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+type ServiceAccountService struct {
+	users repository.UserStore
+	keys  repository.APIKeyStore
+	auth  *AuthService
+}
+
+func NewServiceAccountService(users repository.UserStore, keys repository.APIKeyStore, auth *AuthService) *ServiceAccountService {
+	return &ServiceAccountService{
+		users: users,
+		keys:  keys,
+		auth:  auth,
+	}
+}
+
+// Create adds a service account. It gets a synthetic, undeliverable email
+// and a random password nobody knows, so it can only authenticate with keys.
+func (s *ServiceAccountService) Create(ctx context.Context, name, role string) (generated.CreateServiceAccountRow, error) {
+	if role == "" {
+		role = "user"
+	}
+
+	suffix, err := randomHex(4)
+	if err != nil {
+		return generated.CreateServiceAccountRow{}, err
+	}
+	slug := strings.Trim(slugPattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		slug = "account"
+	}
+	email := fmt.Sprintf("svc-%s-%s@%s", slug, suffix, serviceAccountEmail)
+
+	password, err := randomPassword()
+	if err != nil {
+		return generated.CreateServiceAccountRow{}, err
+	}
+	hash, err := s.auth.HashPassword(password)
+	if err != nil {
+		return generated.CreateServiceAccountRow{}, err
+	}
+
+	return s.users.CreateServiceAccount(ctx, name, email, hash, role)
+}
+
+func (s *ServiceAccountService) List(ctx context.Context) ([]generated.ListServiceAccountsRow, error) {
+	return s.users.ListServiceAccounts(ctx)
+}
+
+// CreateKey issues a new key for a service account. The plaintext key is
+// returned once; only its SHA-256 hash is stored.
+func (s *ServiceAccountService) CreateKey(ctx context.Context, accountID int64, name string, scopes []string, expiresIn time.Duration) (generated.CreateAPIKeyRow, string, error) {
+	if err := s.requireServiceAccount(ctx, accountID); err != nil {
+		return generated.CreateAPIKeyRow{}, "", err
+	}
+	if err := ValidateScopes(scopes); err != nil {
+		return generated.CreateAPIKeyRow{}, "", err
+	}
+
+	secret, err := randomHex(24)
+	if err != nil {
+		return generated.CreateAPIKeyRow{}, "", err
+	}
+	rawKey := apiKeyPrefix + secret
+
+	var expiresAt *time.Time
+	if expiresIn > 0 {
+		t := time.Now().Add(expiresIn)
+		expiresAt = &t
+	}
+
+	key, err := s.keys.Create(ctx, accountID, name, rawKey[:apiKeyDisplayLength], HashAPIKey(rawKey), strings.Join(scopes, " "), expiresAt)
+	if err != nil {
+		return generated.CreateAPIKeyRow{}, "", fmt.Errorf("failed to create api key: %w", err)
+	}
+	return key, rawKey, nil
+}
+
+func (s *ServiceAccountService) ListKeys(ctx context.Context, accountID int64) ([]generated.ListAPIKeysByUserRow, error) {
+	if err := s.requireServiceAccount(ctx, accountID); err != nil {
+		return nil, err
+	}
+	return s.keys.ListByUser(ctx, accountID)
+}
+
+func (s *ServiceAccountService) RevokeKey(ctx context.Context, accountID, keyID int64) error {
+	revoked, err := s.keys.Revoke(ctx, keyID, accountID)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+	return nil
+}
+
+// Authenticate resolves a raw key to the service account that owns it.
+func (s *ServiceAccountService) Authenticate(ctx context.Context, rawKey string) (*models.AuthUser, error) {
+	if !strings.HasPrefix(rawKey, apiKeyPrefix) {
+		return nil, ErrInvalidAPIKey
+	}
+
+	key, err := s.keys.GetByHash(ctx, HashAPIKey(rawKey))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if key.RevokedAt.Valid || (key.ExpiresAt.Valid && time.Now().After(key.ExpiresAt.Time)) {
+		return nil, ErrInvalidAPIKey
+	}
+	if key.AccountType != AccountTypeService {
+		return nil, ErrNotServiceAccount
+	}
+	if !key.Active {
+		return nil, ErrAccountDisabled
+	}
+
+	if err := s.keys.Touch(ctx, key.ID); err != nil {
+		return nil, fmt.Errorf("failed to record api key use: %w", err)
+	}
+
+	return &models.AuthUser{
+		ID:          key.UserID,
+		Role:        key.Role,
+		AccountType: AccountTypeService,
+		Scopes:      strings.Fields(key.Scopes),
+		APIKeyID:    key.ID,
+	}, nil
+}
+
+func (s *ServiceAccountService) requireServiceAccount(ctx context.Context, id int64) error {
+	user, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return err
+	}
+	if user.AccountType != AccountTypeService {
+		return ErrNotServiceAccount
+	}
+	return nil
+}
+
+func ValidateScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return fmt.Errorf("%w: at least one scope is required", ErrInvalidScope)
+	}
+	for _, scope := range scopes {
+		if !knownScopes[scope] {
+			return fmt.Errorf("%w: %q", ErrInvalidScope, scope)
+		}
+	}
+	return nil
+}
+
+func HashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate random value: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/repository"
+)
+
+type fakeAPIKeyStore struct {
+	repository.APIKeyStore
+	keys    map[string]generated.GetAPIKeyByHashRow
+	touched []int64
+}
+
+func (f *fakeAPIKeyStore) GetByHash(ctx context.Context, keyHash string) (generated.GetAPIKeyByHashRow, error) {
+	key, ok := f.keys[keyHash]
+	if !ok {
+		return generated.GetAPIKeyByHashRow{}, pgx.ErrNoRows
+	}
+	return key, nil
+}
+
+func (f *fakeAPIKeyStore) Touch(ctx context.Context, id int64) error {
+	f.touched = append(f.touched, id)
+	return nil
+}
+
+func TestValidateScopes(t *testing.T) {
+	tests := []struct {
+		name    string
+		scopes  []string
+		wantErr bool
+	}{
+		{"read only", []string{"users:read"}, false},
+		{"all", []string{"users:read", "users:write", "admin"}, false},
+		{"empty", nil, true},
+		{"unknown", []string{"users:read", "billing"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateScopes(tt.scopes)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateScopes(%v) error = %v, wantErr %v", tt.scopes, err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidScope) {
+				t.Errorf("expected ErrInvalidScope, got %v", err)
+			}
+		})
+	}
+}
+
+func TestServiceAccountAuthenticate(t *testing.T) {
+	past := pgtype.Timestamp{Time: time.Now().Add(-time.Hour), Valid: true}
+	store := &fakeAPIKeyStore{keys: map[string]generated.GetAPIKeyByHashRow{
+		HashAPIKey("uak_valid"):    {ID: 1, UserID: 10, Scopes: "users:read admin", Role: "admin", Active: true, AccountType: AccountTypeService},
+		HashAPIKey("uak_revoked"):  {ID: 2, UserID: 10, Scopes: "users:read", Active: true, AccountType: AccountTypeService, RevokedAt: past},
+		HashAPIKey("uak_expired"):  {ID: 3, UserID: 10, Scopes: "users:read", Active: true, AccountType: AccountTypeService, ExpiresAt: past},
+		HashAPIKey("uak_human"):    {ID: 4, UserID: 11, Scopes: "users:read", Active: true, AccountType: AccountTypeHuman},
+		HashAPIKey("uak_disabled"): {ID: 5, UserID: 12, Scopes: "users:read", Active: false, AccountType: AccountTypeService},
+	}}
+	svc := NewServiceAccountService(nil, store, nil)
+
+	user, err := svc.Authenticate(context.Background(), "uak_valid")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.ID != 10 || user.Role != "admin" || user.AccountType != AccountTypeService {
+		t.Errorf("unexpected user: %+v", user)
+	}
+	if len(user.Scopes) != 2 || user.Scopes[0] != "users:read" || user.Scopes[1] != "admin" {
+		t.Errorf("unexpected scopes: %v", user.Scopes)
+	}
+	if len(store.touched) != 1 || store.touched[0] != 1 {
+		t.Errorf("expected key 1 to be touched, got %v", store.touched)
+	}
+
+	tests := []struct {
+		key      string
+		expected error
+	}{
+		{"not-a-key", ErrInvalidAPIKey},
+		{"uak_unknown", ErrInvalidAPIKey},
+		{"uak_revoked", ErrInvalidAPIKey},
+		{"uak_expired", ErrInvalidAPIKey},
+		{"uak_human", ErrNotServiceAccount},
+		{"uak_disabled", ErrAccountDisabled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if _, err := svc.Authenticate(context.Background(), tt.key); !errors.Is(err, tt.expected) {
+				t.Errorf("Authenticate(%q) error = %v, expected %v", tt.key, err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/repository"
+)
+
+var (
+	ErrSessionLimitReached = errors.New("too many active sessions")
+	ErrSessionNotFound     = errors.New("session not found")
+)
+
+// What a login past the session limit does.
+const (
+	SessionLimitRevokeOldest = "revoke_oldest"
+	SessionLimitReject       = "reject"
+)
+
+// SessionConfig configures sessions. MaxPerUser caps the live sessions of
+// a user, zero meaning no cap; OnLimit is SessionLimitRevokeOldest or
+// SessionLimitReject. A session lasts Lifetime from its login or latest
+// refresh, and its tokens at most TokenTTL from issue.
+type SessionConfig struct {
+	MaxPerUser int
+	OnLimit    string
+	Lifetime   time.Duration
+	TokenTTL   time.Duration
+}
+
+// SessionService keeps a record of each login, so users can see where they
+// are signed in and sign out of a single device. A session covers the JWT
+// issued at login and every JWT and refresh token descending from it:
+// the JWTs name it in their sid claim and the refresh tokens use it as
+// their family.
+type SessionService struct {
+	store       repository.SessionStore
+	refresh     repository.RefreshTokenStore
+	revocations *TokenRevocationService
+	cfg         SessionConfig
+	now         func() time.Time
+}
+
+func NewSessionService(store repository.SessionStore, refresh repository.RefreshTokenStore, revocations *TokenRevocationService, cfg SessionConfig) *SessionService {
+	return &SessionService{
+		store:       store,
+		refresh:     refresh,
+		revocations: revocations,
+		cfg:         cfg,
+		now:         time.Now,
+	}
+}
+
+type sessionKey struct{}
+
+// WithSession makes the tokens issued with ctx continue session id rather
+// than start a new one.
+func WithSession(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, sessionKey{}, id)
+}
+
+func sessionFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(sessionKey{}).(string)
+	return id
+}
+
+type userAgentKey struct{}
+
+// WithUserAgent attaches the User-Agent of the client logging in, to tell
+// its session apart from the user's others.
+func WithUserAgent(ctx context.Context, userAgent string) context.Context {
+	return context.WithValue(ctx, userAgentKey{}, userAgent)
+}
+
+func userAgent(ctx context.Context) string {
+	ua, _ := ctx.Value(userAgentKey{}).(string)
+	return ua
+}
+
+// Start records a login by the user and returns the new session's ID. At
+// the limit, the oldest sessions are revoked to make room or the login is
+// refused with ErrSessionLimitReached, as configured. Logins racing each
+// other can briefly leave the user one over the limit.
+func (s *SessionService) Start(ctx context.Context, userID int64) (string, error) {
+	now := s.now()
+	if s.cfg.MaxPerUser > 0 {
+		active, err := s.store.ListActive(ctx, userID, now)
+		if err != nil {
+			return "", fmt.Errorf("failed to list sessions: %w", err)
+		}
+		if excess := len(active) - s.cfg.MaxPerUser + 1; excess > 0 {
+			if s.cfg.OnLimit == SessionLimitReject {
+				return "", ErrSessionLimitReached
+			}
+			for _, session := range active[:excess] {
+				if err := s.Revoke(ctx, userID, session.ID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+					return "", err
+				}
+			}
+		}
+	}
+
+	id, err := randomHex(16)
+	if err != nil {
+		return "", err
+	}
+	if err := s.store.Create(ctx, id, userID, clientIP(ctx), userAgent(ctx), now, now.Add(s.cfg.Lifetime)); err != nil {
+		return "", fmt.Errorf("failed to store session: %w", err)
+	}
+	return id, nil
+}
+
+// Extend keeps session id live for another Lifetime, when its tokens are
+// refreshed.
+func (s *SessionService) Extend(ctx context.Context, id string) error {
+	if err := s.store.Extend(ctx, id, s.now().Add(s.cfg.Lifetime)); err != nil {
+		return fmt.Errorf("failed to extend session: %w", err)
+	}
+	return nil
+}
+
+// List returns the user's live sessions, oldest first.
+func (s *SessionService) List(ctx context.Context, userID int64) ([]generated.ListActiveSessionsRow, error) {
+	return s.store.ListActive(ctx, userID, s.now())
+}
+
+// Revoke ends one of the user's sessions: its refresh tokens stop working
+// at once and its JWTs as soon as every instance has picked up the
+// revocation. Sessions that aren't the user's, or already ended, are
+// ErrSessionNotFound.
+func (s *SessionService) Revoke(ctx context.Context, userID int64, id string) error {
+	revoked, err := s.store.Revoke(ctx, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+	if s.refresh != nil {
+		if err := s.refresh.RevokeFamily(ctx, id); err != nil {
+			return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+		}
+	}
+	// No token of the session outlives one issued right now.
+	if err := s.revocations.RevokeSession(ctx, id, userID, s.now().Add(s.cfg.TokenTTL)); err != nil {
+		return err
+	}
+	return nil
+}
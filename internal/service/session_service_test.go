@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"BACKEND/internal/repository"
+)
+
+func TestSessionLimit(t *testing.T) {
+	ctx := WithUserAgent(WithClientIP(context.Background(), "203.0.113.7"), "curl/8.0")
+	newService := func(onLimit string) (*SessionService, *TokenRevocationService) {
+		revocations := NewTokenRevocationService(&fakeRevocationStore{revoked: map[int64]time.Time{}})
+		revocations.SetTokenStore(repository.NewMemoryRevokedTokenStore())
+		return NewSessionService(repository.NewMemorySessionStore(), nil, revocations, SessionConfig{
+			MaxPerUser: 2,
+			OnLimit:    onLimit,
+			Lifetime:   time.Hour,
+			TokenTTL:   time.Hour,
+		}), revocations
+	}
+
+	t.Run("revoke oldest", func(t *testing.T) {
+		svc, revocations := newService(SessionLimitRevokeOldest)
+		var ids []string
+		for i := 0; i < 3; i++ {
+			id, err := svc.Start(ctx, 7)
+			if err != nil {
+				t.Fatalf("login %d: %v", i+1, err)
+			}
+			ids = append(ids, id)
+		}
+		if !revocations.IsTokenRevoked(ids[0]) || revocations.IsTokenRevoked(ids[1]) {
+			t.Error("the third login should revoke the first session only")
+		}
+		active, err := svc.List(ctx, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(active) != 2 || active[0].ID != ids[1] || active[1].ID != ids[2] {
+			t.Fatalf("active sessions = %+v; want the last two", active)
+		}
+		if active[1].IpAddress != "203.0.113.7" || active[1].UserAgent != "curl/8.0" {
+			t.Errorf("session recorded %q %q; want the client's address and user agent", active[1].IpAddress, active[1].UserAgent)
+		}
+		// Other users have their own limit.
+		if _, err := svc.Start(ctx, 8); err != nil {
+			t.Errorf("another user's login: %v", err)
+		}
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		svc, _ := newService(SessionLimitReject)
+		first, _ := svc.Start(ctx, 7)
+		svc.Start(ctx, 7)
+		if _, err := svc.Start(ctx, 7); !errors.Is(err, ErrSessionLimitReached) {
+			t.Fatalf("third login = %v; want ErrSessionLimitReached", err)
+		}
+		if err := svc.Revoke(ctx, 8, first); !errors.Is(err, ErrSessionNotFound) {
+			t.Errorf("revoking another user's session = %v; want ErrSessionNotFound", err)
+		}
+		if err := svc.Revoke(ctx, 7, first); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := svc.Start(ctx, 7); err != nil {
+			t.Errorf("login after signing out elsewhere: %v", err)
+		}
+	})
+}
@@ -0,0 +1,22 @@
+package service
+
+import "net/url"
+
+// Signup sources recorded on each user. Users created before sources were
+// tracked have "unknown".
+const (
+	SignupSourceWeb   = "web"
+	SignupSourceAPI   = "api"
+	SignupSourceSCIM  = "scim"
+	SignupSourceAdmin = "admin"
+	SignupSourceSSO   = "sso"
+)
+
+// ssoSignupSource names the identity provider a user was provisioned from,
+// e.g. "sso:accounts.google.com".
+func ssoSignupSource(issuer string) string {
+	if u, err := url.Parse(issuer); err == nil && u.Host != "" {
+		return SignupSourceSSO + ":" + u.Host
+	}
+	return SignupSourceSSO
+}
@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/clock"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/sso"
+)
+
+const ssoStateTTL = 10 * time.Minute
+
+var (
+	ErrSSONotConfigured    = errors.New("sso is not configured")
+	ErrSSOInvalidState     = errors.New("sso state is invalid or expired")
+	ErrSSOEmailNotVerified = errors.New("identity provider did not return a verified email")
+	ErrSSOUserNotFound     = errors.New("no account exists for this identity and provisioning is disabled")
+	ErrSSOMissingBirthdate = errors.New("identity provider did not supply a birthdate required to provision the account")
+	ErrSSODomainNotLinked  = errors.New("an account with this email exists and its domain is not routed to sso")
+)
+
+type SSOConfig struct {
+	Domains         []string
+	JITProvisioning bool
+	RoleClaim       string
+	AdminValues     []string
+}
+
+// SSOService logs users in through the OIDC provider. Logins waiting for
+// the IdP's callback are kept in states, so the callback can reach any
+// instance.
+type SSOService struct {
+	provider *sso.OIDCProvider
+	repo     repository.UserStore
+	states   repository.SSOStateStore
+	auth     *AuthService
+	cfg      SSOConfig
+	roles    *RoleChanger
+	clock    clock.Clock
+}
+
+func NewSSOService(provider *sso.OIDCProvider, repo repository.UserStore, states repository.SSOStateStore, auth *AuthService, cfg SSOConfig) *SSOService {
+	for i, d := range cfg.Domains {
+		cfg.Domains[i] = strings.ToLower(strings.TrimSpace(d))
+	}
+	return &SSOService{
+		provider: provider,
+		repo:     repo,
+		states:   states,
+		auth:     auth,
+		cfg:      cfg,
+		clock:    clock.System,
+	}
+}
+
+// SetClock sets the clock pending logins expire by.
+func (s *SSOService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetRoles sets how roles synced from the IdP are changed, so the changes
+// revoke tokens and are logged like an admin's. Without it they are only
+// saved.
+func (s *SSOService) SetRoles(roles *RoleChanger) {
+	s.roles = roles
+}
+
+func (s *SSOService) Enabled() bool {
+	return s != nil && s.provider != nil
+}
+
+// RequiresSSO reports whether the email's domain is routed to the IdP.
+func (s *SSOService) RequiresSSO(email string) bool {
+	return s.Enabled() && s.ownsDomain(email)
+}
+
+func (s *SSOService) ownsDomain(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, d := range s.cfg.Domains {
+		if d == domain {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *SSOService) Begin(ctx context.Context, loginHint string) (string, error) {
+	if !s.Enabled() {
+		return "", ErrSSONotConfigured
+	}
+
+	state, err := sso.RandomString()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := sso.RandomString()
+	if err != nil {
+		return "", err
+	}
+	verifier, err := sso.RandomString()
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := s.provider.AuthCodeURL(ctx, state, nonce, verifier, loginHint)
+	if err != nil {
+		return "", err
+	}
+
+	err = s.states.Save(ctx, HashAPIKey(state), repository.SSOPendingLogin{
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		ExpiresAt:    s.clock.Now().Add(ssoStateTTL),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to store sso state: %w", err)
+	}
+
+	return authURL, nil
+}
+
+func (s *SSOService) Complete(ctx context.Context, state, code string) (generated.User, string, error) {
+	if !s.Enabled() {
+		return generated.User{}, "", ErrSSONotConfigured
+	}
+
+	pending, err := s.states.Take(ctx, HashAPIKey(state))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return generated.User{}, "", ErrSSOInvalidState
+		}
+		return generated.User{}, "", fmt.Errorf("failed to load sso state: %w", err)
+	}
+	if s.clock.Now().After(pending.ExpiresAt) {
+		return generated.User{}, "", ErrSSOInvalidState
+	}
+
+	claims, err := s.provider.Exchange(ctx, code, pending.CodeVerifier, pending.Nonce)
+	if err != nil {
+		return generated.User{}, "", err
+	}
+
+	email, _ := claims["email"].(string)
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" || !claimBool(claims["email_verified"]) {
+		return generated.User{}, "", ErrSSOEmailNotVerified
+	}
+
+	user, err := s.repo.GetByEmail(ctx, email)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return generated.User{}, "", err
+		}
+		user, err = s.provision(ctx, email, s.mapRole(claims), claims)
+		if err != nil {
+			return generated.User{}, "", err
+		}
+	} else if !s.ownsDomain(email) {
+		// The IdP can vouch for addresses in any domain, so it only logs
+		// in to existing accounts in the domains routed to it.
+		return generated.User{}, "", ErrSSODomainNotLinked
+	}
+
+	if user.AccountType == AccountTypeService {
+		return generated.User{}, "", ErrInteractiveLoginDenied
+	}
+
+	if !user.Active {
+		return generated.User{}, "", ErrAccountDisabled
+	}
+
+	if role, ok := s.syncedRole(user.Role, claims); ok {
+		roles := s.roles
+		if roles == nil {
+			roles = NewRoleChanger(s.repo, nil, nil, nil)
+		}
+		updated, err := roles.Change(ctx, RoleChange{UserID: user.ID, From: user.Role, To: role, Source: "sso"})
+		if err != nil {
+			return generated.User{}, "", err
+		}
+		user.Role = updated.Role
+	}
+
+	token, err := s.auth.GenerateJWT(ctx, user.ID, user.Role)
+	if err != nil {
+		return generated.User{}, "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	s.auth.afterLogin(ctx, user)
+	return user, token, nil
+}
+
+func (s *SSOService) provision(ctx context.Context, email, role string, claims jwt.MapClaims) (generated.User, error) {
+	if !s.cfg.JITProvisioning {
+		return generated.User{}, ErrSSOUserNotFound
+	}
+
+	birthdate, _ := claims["birthdate"].(string)
+	dob, err := ParseDob(birthdate)
+	if err != nil {
+		return generated.User{}, ErrSSOMissingBirthdate
+	}
+
+	name, _ := claims["name"].(string)
+	if strings.TrimSpace(name) == "" {
+		name = email[:strings.Index(email, "@")]
+	}
+
+	password, err := randomPassword()
+	if err != nil {
+		return generated.User{}, err
+	}
+	hash, err := s.auth.HashPassword(password)
+	if err != nil {
+		return generated.User{}, err
+	}
+
+	issuer, _ := claims["iss"].(string)
+	created, err := s.repo.CreateWithAuth(ctx, name, email, hash, role, ssoSignupSource(issuer), dob)
+	if err != nil {
+		return generated.User{}, fmt.Errorf("failed to provision sso user: %w", err)
+	}
+
+	return generated.User{
+		ID:           created.ID,
+		Name:         created.Name,
+		Dob:          created.Dob,
+		Email:        created.Email,
+		Role:         created.Role,
+		CreatedAt:    created.CreatedAt,
+		UpdatedAt:    created.UpdatedAt,
+		Active:       created.Active,
+		AccountType:  created.AccountType,
+		SignupSource: created.SignupSource,
+		PublicID:     created.PublicID,
+	}, nil
+}
+
+// syncedRole returns the role the IdP says a user with role current should
+// have, and whether it differs. Roles are only synced when admin values are
+// configured, and only between the two the mapping covers, admin and user:
+// moderators and org admins are managed in the API alone.
+func (s *SSOService) syncedRole(current string, claims jwt.MapClaims) (string, bool) {
+	if s.cfg.RoleClaim == "" || len(s.cfg.AdminValues) == 0 {
+		return "", false
+	}
+	if current != RoleAdmin && current != RoleUser {
+		return "", false
+	}
+	role := s.mapRole(claims)
+	return role, role != current
+}
+
+// mapRole grants "admin" when the configured claim (a string or list of
+// strings, e.g. "groups") contains one of the admin values.
+func (s *SSOService) mapRole(claims jwt.MapClaims) string {
+	if s.cfg.RoleClaim == "" || len(s.cfg.AdminValues) == 0 {
+		return "user"
+	}
+
+	var values []string
+	switch v := claims[s.cfg.RoleClaim].(type) {
+	case string:
+		values = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				values = append(values, str)
+			}
+		}
+	}
+
+	for _, v := range values {
+		for _, admin := range s.cfg.AdminValues {
+			if strings.EqualFold(v, admin) {
+				return "admin"
+			}
+		}
+	}
+	return "user"
+}
+
+func claimBool(v interface{}) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return strings.EqualFold(b, "true")
+	}
+	return false
+}
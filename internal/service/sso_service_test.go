@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"BACKEND/internal/clock"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/sso"
+)
+
+func TestSSORequiresSSO(t *testing.T) {
+	svc := NewSSOService(sso.NewOIDCProvider(sso.OIDCConfig{}), nil, nil, nil, SSOConfig{
+		Domains: []string{" Example.com "},
+	})
+
+	tests := []struct {
+		email    string
+		expected bool
+	}{
+		{"jane@example.com", true},
+		{"Jane@EXAMPLE.COM", true},
+		{"jane@other.com", false},
+		{"jane@sub.example.com", false},
+		{"not-an-email", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			if got := svc.RequiresSSO(tt.email); got != tt.expected {
+				t.Errorf("RequiresSSO(%q) = %v, expected %v", tt.email, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSSORequiresSSODisabled(t *testing.T) {
+	var svc *SSOService
+	if svc.RequiresSSO("jane@example.com") {
+		t.Error("expected nil service to never require SSO")
+	}
+}
+
+func TestSSOMapRole(t *testing.T) {
+	svc := NewSSOService(nil, nil, nil, nil, SSOConfig{
+		RoleClaim:   "groups",
+		AdminValues: []string{"platform-admins"},
+	})
+
+	tests := []struct {
+		name     string
+		claims   jwt.MapClaims
+		expected string
+	}{
+		{"no claim", jwt.MapClaims{}, "user"},
+		{"list contains admin", jwt.MapClaims{"groups": []interface{}{"staff", "Platform-Admins"}}, "admin"},
+		{"list without admin", jwt.MapClaims{"groups": []interface{}{"staff"}}, "user"},
+		{"string claim", jwt.MapClaims{"groups": "platform-admins"}, "admin"},
+		{"wrong type", jwt.MapClaims{"groups": 42}, "user"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := svc.mapRole(tt.claims); got != tt.expected {
+				t.Errorf("mapRole() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSSOSyncedRole(t *testing.T) {
+	svc := NewSSOService(nil, nil, nil, nil, SSOConfig{
+		RoleClaim:   "groups",
+		AdminValues: []string{"platform-admins"},
+	})
+	admins := jwt.MapClaims{"groups": []interface{}{"platform-admins"}}
+	staff := jwt.MapClaims{"groups": []interface{}{"staff"}}
+
+	tests := []struct {
+		name     string
+		current  string
+		claims   jwt.MapClaims
+		expected string
+		changed  bool
+	}{
+		{"user promoted", RoleUser, admins, RoleAdmin, true},
+		{"admin demoted", RoleAdmin, staff, RoleUser, true},
+		{"admin unchanged", RoleAdmin, admins, RoleAdmin, false},
+		{"moderator untouched", RoleModerator, admins, "", false},
+		{"org admin untouched", RoleOrgAdmin, staff, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			role, changed := svc.syncedRole(tt.current, tt.claims)
+			if changed != tt.changed || (changed && role != tt.expected) {
+				t.Errorf("syncedRole(%q) = %q, %v, expected %q, %v", tt.current, role, changed, tt.expected, tt.changed)
+			}
+		})
+	}
+
+	unconfigured := NewSSOService(nil, nil, nil, nil, SSOConfig{RoleClaim: "groups"})
+	if _, changed := unconfigured.syncedRole(RoleAdmin, staff); changed {
+		t.Error("expected no role sync without admin values")
+	}
+}
+
+func TestSSOProvisionPublicID(t *testing.T) {
+	store := repository.NewMemoryUserStore()
+	svc := NewSSOService(nil, store, nil, NewAuthService(store), SSOConfig{JITProvisioning: true})
+
+	user, err := svc.provision(context.Background(), "jane@example.com", RoleUser, jwt.MapClaims{
+		"iss":       "https://idp.example.com",
+		"birthdate": "1990-01-01",
+	})
+	if err != nil {
+		t.Fatalf("provision() error = %v", err)
+	}
+	if !user.PublicID.Valid {
+		t.Error("expected provisioned user to have a public ID")
+	}
+}
+
+func TestSSOSignupSource(t *testing.T) {
+	tests := []struct {
+		issuer   string
+		expected string
+	}{
+		{"https://accounts.google.com", "sso:accounts.google.com"},
+		{"https://login.example.com/realms/staff", "sso:login.example.com"},
+		{"", "sso"},
+		{"not a url", "sso"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.issuer, func(t *testing.T) {
+			if got := ssoSignupSource(tt.issuer); got != tt.expected {
+				t.Errorf("ssoSignupSource(%q) = %q, expected %q", tt.issuer, got, tt.expected)
+			}
+		})
+	}
+}
+
+// newTestSSOProvider starts an IdP whose token endpoint returns an ID token
+// for email, and returns a provider for it. The nonce the ID token carries
+// is read from the authorization URL through the returned setter.
+func newTestSSOProvider(t *testing.T, email string) (*sso.OIDCProvider, func(authURL string)) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var nonce string
+	var server *httptest.Server
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 server.URL,
+			"authorization_endpoint": server.URL + "/authorize",
+			"token_endpoint":         server.URL + "/token",
+			"jwks_uri":               server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kid": "key-1",
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+			"iss":            server.URL,
+			"aud":            "client-1",
+			"sub":            "idp-user",
+			"nonce":          nonce,
+			"email":          email,
+			"email_verified": true,
+			"birthdate":      "1990-01-01",
+			"exp":            time.Now().Add(time.Minute).Unix(),
+		})
+		token.Header["kid"] = "key-1"
+		signed, err := token.SignedString(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"id_token": signed})
+	})
+	server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	provider := sso.NewOIDCProvider(sso.OIDCConfig{Issuer: server.URL, ClientID: "client-1", RedirectURL: "https://example.com/callback"})
+	return provider, func(authURL string) {
+		u, err := url.Parse(authURL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		nonce = u.Query().Get("nonce")
+	}
+}
+
+// beginTestSSOLogin starts a login and returns its state.
+func beginTestSSOLogin(t *testing.T, svc *SSOService, authorize func(string)) string {
+	t.Helper()
+	authURL, err := svc.Begin(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Begin() error = %v", err)
+	}
+	authorize(authURL)
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Query().Get("state")
+}
+
+func TestSSOCompleteStateUsedOnce(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryUserStore()
+	if _, err := store.CreateWithAuth(ctx, "Jane", "jane@example.com", "hash", RoleUser, "web", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	auth := NewAuthService(store)
+	auth.SetJWTConfig("test-secret", time.Hour)
+	provider, authorize := newTestSSOProvider(t, "jane@example.com")
+	states := repository.NewMemorySSOStateStore()
+	svc := NewSSOService(provider, store, states, auth, SSOConfig{Domains: []string{"example.com"}})
+
+	state := beginTestSSOLogin(t, svc, authorize)
+	// A second service sharing the store stands in for another instance.
+	other := NewSSOService(provider, store, states, auth, SSOConfig{Domains: []string{"example.com"}})
+	user, token, err := other.Complete(ctx, state, "code")
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if user.Email != "jane@example.com" || token == "" {
+		t.Errorf("Complete() = %q, %q", user.Email, token)
+	}
+
+	if _, _, err := svc.Complete(ctx, state, "code"); !errors.Is(err, ErrSSOInvalidState) {
+		t.Errorf("reused state error = %v, expected %v", err, ErrSSOInvalidState)
+	}
+}
+
+func TestSSOCompleteLinksOnlySSODomains(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name     string
+		email    string
+		expected error
+	}{
+		{"sso domain", "jane@example.com", nil},
+		{"other domain", "jane@other.com", ErrSSODomainNotLinked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := repository.NewMemoryUserStore()
+			if _, err := store.CreateWithAuth(ctx, "Jane", tt.email, "hash", RoleUser, "web", time.Now()); err != nil {
+				t.Fatal(err)
+			}
+			auth := NewAuthService(store)
+			auth.SetJWTConfig("test-secret", time.Hour)
+			provider, authorize := newTestSSOProvider(t, tt.email)
+			svc := NewSSOService(provider, store, repository.NewMemorySSOStateStore(), auth, SSOConfig{Domains: []string{"example.com"}})
+
+			state := beginTestSSOLogin(t, svc, authorize)
+			if _, _, err := svc.Complete(ctx, state, "code"); !errors.Is(err, tt.expected) {
+				t.Errorf("Complete() error = %v, expected %v", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSSOCompleteExpiredState(t *testing.T) {
+	store := repository.NewMemoryUserStore()
+	provider, authorize := newTestSSOProvider(t, "jane@example.com")
+	svc := NewSSOService(provider, store, repository.NewMemorySSOStateStore(), NewAuthService(store), SSOConfig{Domains: []string{"example.com"}})
+	clk := clock.NewFake(time.Now())
+	svc.SetClock(clk)
+
+	state := beginTestSSOLogin(t, svc, authorize)
+	clk.Advance(ssoStateTTL + time.Second)
+	if _, _, err := svc.Complete(context.Background(), state, "code"); !errors.Is(err, ErrSSOInvalidState) {
+		t.Errorf("Complete() error = %v, expected %v", err, ErrSSOInvalidState)
+	}
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"BACKEND/internal/clock"
+	"BACKEND/internal/repository"
+)
+
+// TokenRevocationService invalidates every token issued to a user before a
+// point in time. Revocations are cached in memory so the auth middleware
+// doesn't hit the database; Refresh picks up revocations made by other
+// instances.
+//
+// With a token store it also revokes single tokens by ID, for logout,
+// cached and refreshed the same way.
+type TokenRevocationService struct {
+	store  repository.TokenRevocationStore
+	tokens repository.RevokedTokenStore
+	clock  clock.Clock
+
+	mu            sync.RWMutex
+	revoked       map[int64]time.Time
+	revokedTokens map[string]time.Time
+}
+
+func NewTokenRevocationService(store repository.TokenRevocationStore) *TokenRevocationService {
+	return &TokenRevocationService{
+		store:         store,
+		clock:         clock.System,
+		revoked:       make(map[int64]time.Time),
+		revokedTokens: make(map[string]time.Time),
+	}
+}
+
+// RevokeUser invalidates all of the user's current tokens. Token issue times
+// have millisecond precision, so only a token issued later in the same
+// millisecond is rejected too.
+func (s *TokenRevocationService) RevokeUser(ctx context.Context, userID int64) error {
+	at := s.clock.Now().Truncate(time.Millisecond)
+	if err := s.store.Revoke(ctx, userID, at); err != nil {
+		return fmt.Errorf("failed to revoke tokens: %w", err)
+	}
+
+	s.mu.Lock()
+	s.revoked[userID] = at
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *TokenRevocationService) SetTokenStore(store repository.RevokedTokenStore) {
+	s.tokens = store
+}
+
+// SetClock sets the clock revocations are timed with.
+func (s *TokenRevocationService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// RevokeToken invalidates one token until it expires. Tokens without an ID
+// were issued before tokens had one and can only be revoked with the rest
+// of the user's tokens.
+func (s *TokenRevocationService) RevokeToken(ctx context.Context, claims *JWTClaims) error {
+	if s.tokens == nil || claims.ID == "" || claims.ExpiresAt == nil {
+		return nil
+	}
+	if err := s.tokens.Revoke(ctx, claims.ID, claims.UserID, claims.ExpiresAt.Time); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	s.mu.Lock()
+	s.revokedTokens[claims.ID] = claims.ExpiresAt.Time
+	s.mu.Unlock()
+	return nil
+}
+
+// RevokeSession invalidates every token of a session, by the sid claim
+// they share, until until, when the last of them expires. Session IDs are
+// kept with the token IDs revoked by RevokeToken.
+func (s *TokenRevocationService) RevokeSession(ctx context.Context, sessionID string, userID int64, until time.Time) error {
+	if s.tokens == nil {
+		return nil
+	}
+	if err := s.tokens.Revoke(ctx, sessionID, userID, until); err != nil {
+		return fmt.Errorf("failed to revoke session tokens: %w", err)
+	}
+
+	s.mu.Lock()
+	s.revokedTokens[sessionID] = until
+	s.mu.Unlock()
+	return nil
+}
+
+// IsTokenRevoked reports whether the token with ID jti was revoked on its
+// own by RevokeToken, or the session with ID jti by RevokeSession.
+func (s *TokenRevocationService) IsTokenRevoked(jti string) bool {
+	s.mu.RLock()
+	_, ok := s.revokedTokens[jti]
+	s.mu.RUnlock()
+	return ok
+}
+
+func (s *TokenRevocationService) Refresh(ctx context.Context) error {
+	revoked, err := s.store.List(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load token revocations: %w", err)
+	}
+	// Expired tokens are rejected anyway, so only the rest are kept.
+	var revokedTokens map[string]time.Time
+	if s.tokens != nil {
+		revokedTokens, err = s.tokens.List(ctx, s.clock.Now())
+		if err != nil {
+			return fmt.Errorf("failed to load revoked tokens: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	s.revoked = revoked
+	if revokedTokens != nil {
+		s.revokedTokens = revokedTokens
+	}
+	s.mu.Unlock()
+	return nil
+}
+
+// IsRevoked reports whether a token for userID issued at issuedAt has been
+// revoked.
+func (s *TokenRevocationService) IsRevoked(userID int64, issuedAt time.Time) bool {
+	s.mu.RLock()
+	at, ok := s.revoked[userID]
+	s.mu.RUnlock()
+	return ok && !issuedAt.After(at)
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"BACKEND/internal/clock"
+	"BACKEND/internal/repository"
+)
+
+type fakeRevocationStore struct {
+	revoked map[int64]time.Time
+}
+
+func (f *fakeRevocationStore) Revoke(ctx context.Context, userID int64, at time.Time) error {
+	f.revoked[userID] = at
+	return nil
+}
+
+func (f *fakeRevocationStore) List(ctx context.Context) (map[int64]time.Time, error) {
+	revoked := make(map[int64]time.Time, len(f.revoked))
+	for id, at := range f.revoked {
+		revoked[id] = at
+	}
+	return revoked, nil
+}
+
+func TestTokenRevocation(t *testing.T) {
+	store := &fakeRevocationStore{revoked: map[int64]time.Time{}}
+	svc := NewTokenRevocationService(store)
+	ctx := context.Background()
+
+	issued := time.Now().Add(-time.Minute)
+	if svc.IsRevoked(7, issued) {
+		t.Fatal("token revoked before RevokeUser")
+	}
+	if err := svc.RevokeUser(ctx, 7); err != nil {
+		t.Fatalf("RevokeUser: %v", err)
+	}
+	if !svc.IsRevoked(7, issued) {
+		t.Error("token issued before the revocation is still valid")
+	}
+	if !svc.IsRevoked(7, time.Now().Truncate(time.Second)) {
+		t.Error("token issued in the same second as the revocation is still valid")
+	}
+	if svc.IsRevoked(7, time.Now().Add(2*time.Second)) {
+		t.Error("token issued after the revocation is revoked")
+	}
+	if svc.IsRevoked(8, issued) {
+		t.Error("other user's token is revoked")
+	}
+}
+
+func TestTokenRevocationSameSecond(t *testing.T) {
+	fake := clock.NewFake(time.Date(2026, 3, 1, 12, 0, 0, 300*int(time.Millisecond), time.UTC))
+	auth := NewAuthService(&fakeMagicLinkUserStore{})
+	auth.SetJWTConfig("secret", time.Hour)
+	auth.SetClock(fake)
+	svc := NewTokenRevocationService(&fakeRevocationStore{revoked: map[int64]time.Time{}})
+	svc.SetClock(fake)
+	ctx := context.Background()
+
+	issuedAt := func() time.Time {
+		token, err := auth.GenerateJWT(ctx, 7, "user")
+		if err != nil {
+			t.Fatalf("GenerateJWT: %v", err)
+		}
+		claims, err := auth.ParseJWT(token)
+		if err != nil {
+			t.Fatalf("ParseJWT: %v", err)
+		}
+		return claims.IssuedAt.Time
+	}
+	before := issuedAt()
+	fake.Advance(200 * time.Millisecond)
+	if err := svc.RevokeUser(ctx, 7); err != nil {
+		t.Fatalf("RevokeUser: %v", err)
+	}
+	fake.Advance(200 * time.Millisecond)
+	after := issuedAt()
+
+	if !svc.IsRevoked(7, before) {
+		t.Error("token issued before the revocation is still valid")
+	}
+	if svc.IsRevoked(7, after) {
+		t.Error("token issued after the revocation, in the same second, is revoked")
+	}
+}
+
+func TestTokenRevocationRefreshSeesOtherInstances(t *testing.T) {
+	store := &fakeRevocationStore{revoked: map[int64]time.Time{}}
+	svc := NewTokenRevocationService(store)
+	other := NewTokenRevocationService(store)
+	ctx := context.Background()
+
+	if err := other.RevokeUser(ctx, 7); err != nil {
+		t.Fatalf("RevokeUser: %v", err)
+	}
+	issued := time.Now().Add(-time.Minute)
+	if svc.IsRevoked(7, issued) {
+		t.Fatal("revocation seen before Refresh")
+	}
+	if err := svc.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if !svc.IsRevoked(7, issued) {
+		t.Error("revocation not seen after Refresh")
+	}
+}
+
+func TestRevokeSingleToken(t *testing.T) {
+	auth := NewAuthService(&fakeMagicLinkUserStore{})
+	auth.SetJWTConfig("secret", time.Hour)
+	tokens := repository.NewMemoryRevokedTokenStore()
+	svc := NewTokenRevocationService(&fakeRevocationStore{revoked: map[int64]time.Time{}})
+	svc.SetTokenStore(tokens)
+	ctx := context.Background()
+
+	parse := func() *JWTClaims {
+		token, err := auth.GenerateJWT(ctx, 7, "user")
+		if err != nil {
+			t.Fatalf("GenerateJWT: %v", err)
+		}
+		claims, err := auth.ParseJWT(token)
+		if err != nil {
+			t.Fatalf("ParseJWT: %v", err)
+		}
+		return claims
+	}
+	loggedOut, other := parse(), parse()
+	if loggedOut.ID == "" || loggedOut.ID == other.ID {
+		t.Fatalf("token IDs %q and %q should be set and distinct", loggedOut.ID, other.ID)
+	}
+
+	if err := svc.RevokeToken(ctx, loggedOut); err != nil {
+		t.Fatalf("RevokeToken: %v", err)
+	}
+	if !svc.IsTokenRevoked(loggedOut.ID) {
+		t.Error("logged out token is still valid")
+	}
+	if svc.IsTokenRevoked(other.ID) {
+		t.Error("another token of the same user was revoked")
+	}
+	// Logging out leaves the user's other sessions alone.
+	if svc.IsRevoked(7, other.IssuedAt.Time) {
+		t.Error("logout revoked every token of the user")
+	}
+
+	// Another instance sharing the store sees the logout once it refreshes.
+	instance := NewTokenRevocationService(&fakeRevocationStore{revoked: map[int64]time.Time{}})
+	instance.SetTokenStore(tokens)
+	if err := instance.Refresh(ctx); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if !instance.IsTokenRevoked(loggedOut.ID) {
+		t.Error("logout not seen after Refresh")
+	}
+}
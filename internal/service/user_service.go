@@ -2,70 +2,563 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel/attribute"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/clock"
+	"BACKEND/internal/dates"
 	"BACKEND/internal/models"
 	"BACKEND/internal/repository"
+	"BACKEND/internal/tracing"
+)
+
+var (
+	ErrUserNotFound    = errors.New("user not found")
+	ErrDateBeforeBirth = errors.New("date is before the user was born")
+	ErrInvalidSort     = errors.New("invalid sort")
+	ErrLookupTooShort  = fmt.Errorf("email lookups need at least %d characters", MinEmailLookup)
+	ErrNoProfiles      = errors.New("profiles are not configured")
 )
 
+// MinEmailLookup is the shortest email prefix LookupByEmail accepts, so a
+// lookup can't walk the user base a letter at a time.
+const MinEmailLookup = 3
+
 type UserService struct {
-	repo *repository.UserRepository
+	repo            repository.UserStore
+	clock           clock.Clock
+	defaultPageSize int
+	maxPageSize     int
+	listCap         int
+	alwaysPaginate  bool
+	avatars         *AvatarService
+	profiles        repository.ProfileStore
 }
 
-func NewUserService(r *repository.UserRepository) *UserService {
-	return &UserService{repo: r}
+func NewUserService(r repository.UserStore) *UserService {
+	return &UserService{
+		repo:            r,
+		clock:           clock.System,
+		defaultPageSize: DefaultPageSize,
+		maxPageSize:     DefaultMaxPageSize,
+		listCap:         DefaultListCap,
+	}
 }
 
-func calculateAge(dob time.Time) int {
-	now := time.Now()
-	age := now.Year() - dob.Year()
-	if now.YearDay() < dob.YearDay() {
-		age--
-	}
+// SetClock sets the clock ages are computed against.
+func (s *UserService) SetClock(c clock.Clock) {
+	s.clock = c
+}
+
+// SetAvatars adds avatar_url to the users this service returns.
+func (s *UserService) SetAvatars(a *AvatarService) {
+	s.avatars = a
+}
+
+// Avatars returns the service set by SetAvatars, or nil.
+func (s *UserService) Avatars() *AvatarService {
+	return s.avatars
+}
+
+// SetProfiles sets where user profiles are kept. Without it users have
+// empty profiles they can't change.
+func (s *UserService) SetProfiles(p repository.ProfileStore) {
+	s.profiles = p
+}
+
+// Page sizes used unless SetPageSizes and SetListCap say otherwise.
+const (
+	DefaultPageSize    = 10
+	DefaultMaxPageSize = 100
+	// DefaultListCap bounds the unpaginated list, so it can't load the
+	// whole table into memory.
+	DefaultListCap = 1000
+)
+
+// SetPageSizes sets the page size lists use when none is asked for, and
+// the largest they return.
+func (s *UserService) SetPageSizes(defaultSize, maxSize int) {
+	s.defaultPageSize = defaultSize
+	s.maxPageSize = maxSize
+}
+
+// SetListCap sets the most users the unpaginated list returns; 0 removes
+// the cap.
+func (s *UserService) SetListCap(n int) {
+	s.listCap = n
+}
+
+// SetAlwaysPaginate makes lists requested without a page return the first
+// page rather than every user.
+func (s *UserService) SetAlwaysPaginate(always bool) {
+	s.alwaysPaginate = always
+}
 
-	return age
+func (s *UserService) AlwaysPaginate() bool {
+	return s.alwaysPaginate
 }
 
-func (s *UserService) GetUserWithAge(ctx context.Context, id int32) (*models.UserWithAgeResponse, error) {
+func (s *UserService) ListCap() int {
+	return s.listCap
+}
+
+func (s *UserService) DefaultPageSize() int {
+	return s.defaultPageSize
+}
+
+// MaxPageSize returns the largest page of users a list returns.
+func (s *UserService) MaxPageSize() int {
+	return s.maxPageSize
+}
+
+// DobLayout is the format of dates of birth in requests and responses.
+const DobLayout = "2006-01-02"
+
+// ParseDob parses a date of birth in DobLayout.
+func ParseDob(s string) (time.Time, error) {
+	return time.Parse(DobLayout, s)
+}
+
+func (s *UserService) GetUserWithAge(ctx context.Context, id int64) (_ *models.UserWithAgeResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.GetUserWithAge", attribute.Int64("user.id", id))
+	defer func() { tracing.End(span, err) }()
+
 	user, err := s.repo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	return &models.UserWithAgeResponse{
-		ID:   user.ID,
-		Name: user.Name,
-		Dob:  user.Dob.Time.Format("2006-01-02"),
-		Age:  calculateAge(user.Dob.Time),
+		ID:          user.PublicID.String(),
+		Name:        user.Name,
+		Dob:         user.Dob.Time.Format("2006-01-02"),
+		Age:         dates.Age(user.Dob.Time, s.clock.Now()),
+		AccountType: user.AccountType,
+		AvatarURL:   s.avatars.URL(user.Email, user.PublicID.String()),
 	}, nil
 }
 
-func (s *UserService) ListUsersWithAge(ctx context.Context) ([]models.UserWithAgeResponse, error) {
-	users, err := s.repo.List(ctx)
+// CurrentUser returns the user with their profile, for the user themselves.
+// Their avatar is the one they uploaded, else the profile's avatar_url,
+// else the derived one.
+func (s *UserService) CurrentUser(ctx context.Context, id int64) (_ *models.CurrentUserResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.CurrentUser", attribute.Int64("user.id", id))
+	defer func() { tracing.End(span, err) }()
+
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	row, err := s.profile(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
+	profile := profileModel(row)
+	avatar := profile.AvatarURL
+	if row.AvatarKey != "" {
+		if avatar, err = s.avatars.UploadURL(row.AvatarKey); err != nil {
+			return nil, err
+		}
+	}
+	if avatar == "" {
+		avatar = s.avatars.URL(user.Email, user.PublicID.String())
+	}
+	return &models.CurrentUserResponse{
+		ID:          user.PublicID.String(),
+		Name:        user.Name,
+		Dob:         user.Dob.Time.Format("2006-01-02"),
+		Age:         dates.Age(user.Dob.Time, s.clock.Now()),
+		AccountType: user.AccountType,
+		AvatarURL:   avatar,
+		Profile:     profile,
+	}, nil
+}
+
+// Profile returns the user's profile, empty if they never set one.
+func (s *UserService) Profile(ctx context.Context, id int64) (models.Profile, error) {
+	row, err := s.profile(ctx, id)
+	if err != nil {
+		return models.Profile{}, err
+	}
+	return profileModel(row), nil
+}
+
+func (s *UserService) profile(ctx context.Context, id int64) (generated.UserProfile, error) {
+	if s.profiles == nil {
+		return generated.UserProfile{}, nil
+	}
+	row, err := s.profiles.Get(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return generated.UserProfile{}, nil
+	}
+	return row, err
+}
+
+func profileModel(row generated.UserProfile) models.Profile {
+	return models.Profile{
+		Bio:       row.Bio,
+		Phone:     row.Phone,
+		AvatarURL: row.AvatarUrl,
+		Locale:    row.Locale,
+		Timezone:  row.Timezone,
+	}
+}
+
+// SaveProfile replaces the user's profile. The caller validates it.
+func (s *UserService) SaveProfile(ctx context.Context, id int64, profile models.Profile) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.SaveProfile", attribute.Int64("user.id", id))
+	defer func() { tracing.End(span, err) }()
+
+	if s.profiles == nil {
+		return ErrNoProfiles
+	}
+	_, err = s.profiles.Save(ctx, generated.UserProfile{
+		UserID:    id,
+		Bio:       profile.Bio,
+		Phone:     profile.Phone,
+		AvatarUrl: profile.AvatarURL,
+		Locale:    profile.Locale,
+		Timezone:  profile.Timezone,
+	})
+	return err
+}
+
+// UploadAvatar makes the image in data the user's avatar, replacing any
+// they uploaded before.
+func (s *UserService) UploadAvatar(ctx context.Context, id int64, data []byte) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.UploadAvatar", attribute.Int64("user.id", id))
+	defer func() { tracing.End(span, err) }()
+
+	if s.profiles == nil {
+		return ErrNoProfiles
+	}
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	row, err := s.profile(ctx, id)
+	if err != nil {
+		return err
+	}
+	key, err := s.avatars.Upload(ctx, user.PublicID.String(), data)
+	if err != nil {
+		return err
+	}
+	if err := s.profiles.SetAvatarKey(ctx, id, key); err != nil {
+		s.avatars.RemoveUpload(ctx, key)
+		return err
+	}
+	if row.AvatarKey != "" {
+		// Failing to delete the old picture only leaves an unreferenced
+		// object behind.
+		s.avatars.RemoveUpload(ctx, row.AvatarKey)
+	}
+	return nil
+}
+
+// RemoveAvatar deletes the user's uploaded avatar, if they have one.
+func (s *UserService) RemoveAvatar(ctx context.Context, id int64) (err error) {
+	ctx, span := tracing.Start(ctx, "UserService.RemoveAvatar", attribute.Int64("user.id", id))
+	defer func() { tracing.End(span, err) }()
+
+	row, err := s.profile(ctx, id)
+	if err != nil || row.AvatarKey == "" {
+		return err
+	}
+	if err := s.profiles.SetAvatarKey(ctx, id, ""); err != nil {
+		return err
+	}
+	return s.avatars.RemoveUpload(ctx, row.AvatarKey)
+}
+
+// AgeAt returns the user's age on the day at, or today for a zero at.
+func (s *UserService) AgeAt(ctx context.Context, id int64, at time.Time) (_ *models.AgeResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.AgeAt", attribute.Int64("user.id", id))
+	defer func() { tracing.End(span, err) }()
+
+	if at.IsZero() {
+		at = s.clock.Now()
+	}
+	user, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	age := dates.Age(user.Dob.Time, at)
+	if age < 0 {
+		return nil, ErrDateBeforeBirth
+	}
+
+	return &models.AgeResponse{
+		ID:  user.PublicID.String(),
+		Dob: user.Dob.Time.Format(DobLayout),
+		At:  dates.Day(at).Format(DobLayout),
+		Age: age,
+	}, nil
+}
+
+// ListUsersWithAge returns every user, or the first of them up to the list
+// cap; truncated reports whether users were left out.
+func (s *UserService) ListUsersWithAge(ctx context.Context) (result []models.UserWithAgeResponse, truncated bool, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.ListUsersWithAge")
+	defer func() { tracing.End(span, err) }()
+
+	limit := int32(math.MaxInt32)
+	if s.listCap > 0 {
+		// One more than the cap tells whether any were left out.
+		limit = int32(s.listCap + 1)
+	}
+	users, err := s.repo.ListPaginated(ctx, limit, 0)
+	if err != nil {
+		return nil, false, err
+	}
+	if s.listCap > 0 && len(users) > s.listCap {
+		users, truncated = users[:s.listCap], true
+	}
+
 	// Convert DB models to response models with ages
-	result := make([]models.UserWithAgeResponse, len(users))
+	result = make([]models.UserWithAgeResponse, len(users))
 	for i, user := range users {
 		result[i] = models.UserWithAgeResponse{
-			ID:   user.ID,
-			Name: user.Name,
-			Dob:  user.Dob.Time.Format("2006-01-02"),
-			Age:  calculateAge(user.Dob.Time),
+			ID:          user.PublicID.String(),
+			Name:        user.Name,
+			Dob:         user.Dob.Time.Format("2006-01-02"),
+			Age:         dates.Age(user.Dob.Time, s.clock.Now()),
+			AccountType: user.AccountType,
+			AvatarURL:   s.avatars.URL(user.Email, user.PublicID.String()),
+		}
+	}
+
+	return result, truncated, nil
+}
+
+// sortableUserFields maps the fields user lists can be sorted by to their
+// columns. Sorting by age is sorting by date of birth the other way round.
+var sortableUserFields = map[string]repository.UserSortKey{
+	"name":       {Column: repository.SortName},
+	"email":      {Column: repository.SortEmail},
+	"role":       {Column: repository.SortRole},
+	"dob":        {Column: repository.SortDob},
+	"age":        {Column: repository.SortDob, Desc: true},
+	"created_at": {Column: repository.SortCreatedAt},
+	"updated_at": {Column: repository.SortUpdatedAt},
+}
+
+// SortableUserFields lists the fields ParseUserSort accepts, sorted.
+func SortableUserFields() []string {
+	fields := make([]string, 0, len(sortableUserFields))
+	for field := range sortableUserFields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// ParseUserSort parses a sort parameter such as "name,-created_at": up to
+// repository.MaxUserSortKeys fields to order by, most significant first,
+// each descending when prefixed with "-". The error wraps ErrInvalidSort
+// and is fit to show the client.
+func ParseUserSort(raw string) ([]repository.UserSortKey, error) {
+	if raw == "" {
+		return nil, nil
+	}
+	fields := strings.Split(raw, ",")
+	if len(fields) > repository.MaxUserSortKeys {
+		return nil, fmt.Errorf("%w: at most %d fields", ErrInvalidSort, repository.MaxUserSortKeys)
+	}
+	keys := make([]repository.UserSortKey, 0, len(fields))
+	seen := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		name, desc := strings.CutPrefix(strings.TrimSpace(field), "-")
+		key, ok := sortableUserFields[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: cannot sort by %q; sortable fields are %s", ErrInvalidSort, name, strings.Join(SortableUserFields(), ", "))
+		}
+		if seen[key.Column] {
+			return nil, fmt.Errorf("%w: %q is sorted by twice", ErrInvalidSort, name)
+		}
+		seen[key.Column] = true
+		key.Desc = key.Desc != desc
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// UserSearch filters and orders the user list. Zero fields, and nil ages,
+// don't filter.
+type UserSearch struct {
+	// Name is matched anywhere in the name, in any case.
+	Name string
+	// Email is matched whole, in any case.
+	Email string
+	Role  string
+	// MinAge and MaxAge are inclusive ages in whole years.
+	MinAge *int
+	MaxAge *int
+	// CreatedAfter and CreatedBefore bound when the user signed up, the
+	// first inclusively and the second exclusively.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// Sort orders the list, which is by ID after it and without it.
+	Sort []repository.UserSortKey
+}
+
+// IsZero reports whether q neither filters nor sorts.
+func (q UserSearch) IsZero() bool {
+	return q.Name == "" && q.Email == "" && q.Role == "" && q.MinAge == nil && q.MaxAge == nil &&
+		q.CreatedAfter.IsZero() && q.CreatedBefore.IsZero() && len(q.Sort) == 0
+}
+
+// filter turns the ages in q into dates of birth as of today.
+func (q UserSearch) filter(today time.Time) repository.UserFilter {
+	f := repository.UserFilter{
+		Name:          q.Name,
+		Email:         q.Email,
+		Role:          q.Role,
+		CreatedFrom:   q.CreatedAfter,
+		CreatedBefore: q.CreatedBefore,
+	}
+	if q.MinAge != nil {
+		f.DobUntil = dates.BornBy(today, *q.MinAge)
+	}
+	if q.MaxAge != nil {
+		f.DobAfter = dates.BornBy(today, *q.MaxAge+1)
+	}
+	return f
+}
+
+// SearchUsersWithAgePaginated is ListUsersWithAgePaginated for the users
+// matching q.
+func (s *UserService) SearchUsersWithAgePaginated(ctx context.Context, q UserSearch, page, limit int) (_ *models.PaginatedUsersResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.SearchUsersWithAgePaginated")
+	defer func() { tracing.End(span, err) }()
+
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = s.defaultPageSize
+	}
+	if limit > s.maxPageSize {
+		limit = s.maxPageSize
+	}
+	now := s.clock.Now()
+	filter := q.filter(dates.Day(now))
+	total, err := s.repo.CountSearch(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.repo.Search(ctx, filter, q.Sort, int32(limit), int32((page-1)*limit))
+	if err != nil {
+		return nil, err
+	}
+	data := make([]models.UserWithAgeResponse, len(users))
+	for i, user := range users {
+		data[i] = models.UserWithAgeResponse{
+			ID:          user.PublicID.String(),
+			Name:        user.Name,
+			Dob:         user.Dob.Time.Format("2006-01-02"),
+			Age:         dates.Age(user.Dob.Time, now),
+			AccountType: user.AccountType,
+			AvatarURL:   s.avatars.URL(user.Email, user.PublicID.String()),
+		}
+	}
+
+	return &models.PaginatedUsersResponse{
+		Data:       data,
+		Pagination: models.NewPaginationMeta(total, page, limit),
+	}, nil
+}
+
+// UpcomingBirthdays returns the users whose birthday falls in the next days
+// days, today included, soonest first and up to the list cap; truncated
+// reports whether users were left out. Those born on 29 February have
+// their birthday on the 28th in common years.
+func (s *UserService) UpcomingBirthdays(ctx context.Context, days int) (result []models.BirthdayResponse, truncated bool, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.UpcomingBirthdays")
+	defer func() { tracing.End(span, err) }()
+
+	today := dates.Day(s.clock.Now())
+	limit := int32(math.MaxInt32)
+	if s.listCap > 0 {
+		limit = int32(s.listCap + 1)
+	}
+	users, err := s.repo.UpcomingBirthdays(ctx, today, today.AddDate(0, 0, days-1), limit)
+	if err != nil {
+		return nil, false, err
+	}
+	if s.listCap > 0 && len(users) > s.listCap {
+		users, truncated = users[:s.listCap], true
+	}
+
+	result = make([]models.BirthdayResponse, len(users))
+	for i, user := range users {
+		birthday := dates.BirthdayIn(user.Dob.Time, today.Year())
+		if birthday.Before(today) {
+			birthday = dates.BirthdayIn(user.Dob.Time, today.Year()+1)
+		}
+		result[i] = models.BirthdayResponse{
+			ID:       user.PublicID.String(),
+			Name:     user.Name,
+			Dob:      user.Dob.Time.Format(DobLayout),
+			Birthday: birthday.Format(DobLayout),
+			Turns:    birthday.Year() - user.Dob.Time.Year(),
 		}
 	}
+	return result, truncated, nil
+}
+
+// LookupByEmail returns up to limit users whose email is or starts with
+// email, in any case, in email order. Emails shorter than
+// MinEmailLookup return ErrLookupTooShort.
+func (s *UserService) LookupByEmail(ctx context.Context, email string, limit int) (_ []models.UserLookupResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.LookupByEmail")
+	defer func() { tracing.End(span, err) }()
+
+	email = strings.ToLower(strings.TrimSpace(email))
+	if utf8.RuneCountInString(email) < MinEmailLookup {
+		return nil, ErrLookupTooShort
+	}
+	users, err := s.repo.LookupByEmail(ctx, email, int32(limit))
+	if err != nil {
+		return nil, err
+	}
 
+	result := make([]models.UserLookupResponse, len(users))
+	for i, user := range users {
+		result[i] = models.UserLookupResponse{
+			ID:          user.PublicID.String(),
+			Name:        user.Name,
+			Email:       user.Email,
+			Role:        user.Role,
+			Active:      user.Active,
+			AccountType: user.AccountType,
+		}
+	}
 	return result, nil
 }
 
-func (s *UserService) ListUsersWithAgePaginated(ctx context.Context, page, limit int) (*models.PaginatedUsersResponse, error) {
+func (s *UserService) ListUsersWithAgePaginated(ctx context.Context, page, limit int) (_ *models.PaginatedUsersResponse, err error) {
+	ctx, span := tracing.Start(ctx, "UserService.ListUsersWithAgePaginated")
+	defer func() { tracing.End(span, err) }()
+
 	if page < 1 {
 		page = 1
 	}
-	if limit < 1 || limit > 100 {
-		limit = 10
+	if limit < 1 {
+		limit = s.defaultPageSize
+	}
+	if limit > s.maxPageSize {
+		limit = s.maxPageSize
 	}
 	offset := (page - 1) * limit
 	total, err := s.repo.Count(ctx)
@@ -79,26 +572,17 @@ func (s *UserService) ListUsersWithAgePaginated(ctx context.Context, page, limit
 	data := make([]models.UserWithAgeResponse, len(users))
 	for i, user := range users {
 		data[i] = models.UserWithAgeResponse{
-			ID:   user.ID,
-			Name: user.Name,
-			Dob:  user.Dob.Time.Format("2006-01-02"),
-			Age:  calculateAge(user.Dob.Time),
+			ID:          user.PublicID.String(),
+			Name:        user.Name,
+			Dob:         user.Dob.Time.Format("2006-01-02"),
+			Age:         dates.Age(user.Dob.Time, s.clock.Now()),
+			AccountType: user.AccountType,
+			AvatarURL:   s.avatars.URL(user.Email, user.PublicID.String()),
 		}
 	}
-	totalPages := int(total) / limit
-	if int(total)%limit != 0 {
-		totalPages++
-	}
 
 	return &models.PaginatedUsersResponse{
-		Data: data,
-		Pagination: models.PaginationMeta{
-			Total:       total,
-			Page:        page,
-			Limit:       limit,
-			TotalPages:  totalPages,
-			HasNext:     page < totalPages,
-			HasPrevious: page > 1,
-		},
+		Data:       data,
+		Pagination: models.NewPaginationMeta(total, page, limit),
 	}, nil
 }
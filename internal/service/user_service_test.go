@@ -1,78 +1,354 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"slices"
 	"testing"
 	"time"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/clock"
+	"BACKEND/internal/models"
+	"BACKEND/internal/repository"
 )
 
-func TestCalculateAge(t *testing.T) {
-	tests := []struct {
-		name     string
-		dob      time.Time
-		expected int
+type fakeListUserStore struct {
+	repository.UserStore
+	users int
+}
+
+func (f *fakeListUserStore) ListPaginated(ctx context.Context, limit, offset int32) ([]generated.ListUsersPaginatedRow, error) {
+	var rows []generated.ListUsersPaginatedRow
+	for i := int(offset); i < f.users && len(rows) < int(limit); i++ {
+		rows = append(rows, generated.ListUsersPaginatedRow{ID: int64(i + 1)})
+	}
+	return rows, nil
+}
+
+func (f *fakeListUserStore) Count(ctx context.Context) (int64, error) {
+	return int64(f.users), nil
+}
+
+func TestListUsersWithAgeCap(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeListUserStore{users: 5}
+	svc := NewUserService(store)
+
+	svc.SetListCap(3)
+	users, truncated, err := svc.ListUsersWithAge(ctx)
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(users) != 3 || !truncated {
+		t.Errorf("got %d users, truncated %v; want 3, true", len(users), truncated)
+	}
+
+	svc.SetListCap(5)
+	if users, truncated, _ := svc.ListUsersWithAge(ctx); len(users) != 5 || truncated {
+		t.Errorf("at the cap: got %d users, truncated %v; want 5, false", len(users), truncated)
+	}
+
+	svc.SetListCap(0)
+	if users, truncated, _ := svc.ListUsersWithAge(ctx); len(users) != 5 || truncated {
+		t.Errorf("uncapped: got %d users, truncated %v; want 5, false", len(users), truncated)
+	}
+}
+
+func TestListUsersWithAgePaginatedPageSizes(t *testing.T) {
+	ctx := context.Background()
+	store := &fakeListUserStore{users: 50}
+	svc := NewUserService(store)
+	svc.SetPageSizes(20, 30)
+
+	for _, tt := range []struct {
+		limit, want int
 	}{
-		{
-			name:     "Age 34 - birthday already passed this year",
-			dob:      time.Date(1990, 5, 10, 0, 0, 0, 0, time.UTC),
-			expected: 34,
-		},
-		{
-			name:     "Age 33 - birthday not yet this year",
-			dob:      time.Date(1991, 12, 31, 0, 0, 0, 0, time.UTC),
-			expected: 33,
-		},
-		{
-			name:     "Age 0 - born this year",
-			dob:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
-			expected: 0,
-		},
-		{
-			name:     "Age 25 - leap year birth",
-			dob:      time.Date(2000, 2, 29, 0, 0, 0, 0, time.UTC),
-			expected: 24,
-		},
-		{
-			name:     "Age 100 - very old person",
-			dob:      time.Date(1924, 6, 15, 0, 0, 0, 0, time.UTC),
-			expected: 100,
-		},
+		{limit: 0, want: 20},
+		{limit: 25, want: 25},
+		{limit: 40, want: 30},
+	} {
+		resp, err := svc.ListUsersWithAgePaginated(ctx, 1, tt.limit)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+		if resp.Pagination.Limit != tt.want || len(resp.Data) != tt.want {
+			t.Errorf("limit %d: got page size %d with %d users; want %d", tt.limit, resp.Pagination.Limit, len(resp.Data), tt.want)
+		}
 	}
+}
 
+func TestUpcomingBirthdays(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryUserStore()
+	for name, dob := range map[string]string{
+		"Leap":     "2000-02-29",
+		"March":    "1990-03-01",
+		"NewYear":  "1985-01-02",
+		"December": "1970-12-30",
+		"Later":    "1999-06-01",
+	} {
+		d, _ := ParseDob(dob)
+		if _, err := store.CreateWithAuth(ctx, name, name+"@example.com", "hash", "", "api", d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc := NewUserService(store)
+	names := func(users []models.BirthdayResponse) []string {
+		var out []string
+		for _, u := range users {
+			out = append(out, u.Name+" "+u.Birthday)
+		}
+		return out
+	}
+
+	// 2027 is a common year, so the leap day birthday is on the 28th.
+	svc.SetClock(clock.NewFake(time.Date(2027, 2, 22, 15, 0, 0, 0, time.UTC)))
+	users, _, err := svc.UpcomingBirthdays(ctx, 7)
+	if got, want := names(users), []string{"Leap 2027-02-28"}; err != nil || !slices.Equal(got, want) {
+		t.Errorf("7 days from 22 Feb 2027 = %v, %v; want %v", got, err, want)
+	}
+	if users[0].Turns != 27 {
+		t.Errorf("Turns = %d, want 27", users[0].Turns)
+	}
+
+	// In 2028 it is on the 29th, after the window.
+	svc.SetClock(clock.NewFake(time.Date(2028, 2, 22, 15, 0, 0, 0, time.UTC)))
+	if users, _, _ := svc.UpcomingBirthdays(ctx, 7); len(users) != 0 {
+		t.Errorf("7 days from 22 Feb 2028 = %v, want none", names(users))
+	}
+	if users, _, _ := svc.UpcomingBirthdays(ctx, 9); !slices.Equal(names(users), []string{"Leap 2028-02-29", "March 2028-03-01"}) {
+		t.Errorf("9 days from 22 Feb 2028 = %v", names(users))
+	}
+
+	svc.SetClock(clock.NewFake(time.Date(2026, 12, 28, 9, 0, 0, 0, time.UTC)))
+	users, _, _ = svc.UpcomingBirthdays(ctx, 7)
+	if got, want := names(users), []string{"December 2026-12-30", "NewYear 2027-01-02"}; !slices.Equal(got, want) {
+		t.Errorf("7 days from 28 Dec 2026 = %v, want %v", got, want)
+	}
+
+	svc.SetListCap(1)
+	if users, truncated, _ := svc.UpcomingBirthdays(ctx, 366); len(users) != 1 || !truncated {
+		t.Errorf("capped: got %d users, truncated %v; want 1, true", len(users), truncated)
+	}
+}
+
+func TestSearchUsersWithAgePaginated(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryUserStore()
+	for _, u := range []struct{ name, email, role, dob string }{
+		{"Ann Lee", "ann@example.com", "admin", "2000-02-29"},
+		{"Joanna 100%", "jo@example.com", "", "1990-06-15"},
+		{"Bob", "Bob@Example.com", "", "1980-01-01"},
+	} {
+		dob, _ := ParseDob(u.dob)
+		if _, err := store.CreateWithAuth(ctx, u.name, u.email, "hash", u.role, "api", dob); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc := NewUserService(store)
+	svc.SetClock(clock.NewFake(time.Date(2025, 2, 27, 12, 0, 0, 0, time.UTC)))
+	age := func(n int) *int { return &n }
+
+	tests := []struct {
+		name   string
+		search UserSearch
+		want   []string
+	}{
+		{"name in any case", UserSearch{Name: "AN"}, []string{"Ann Lee", "Joanna 100%"}},
+		{"percent sign is literal", UserSearch{Name: "0%"}, []string{"Joanna 100%"}},
+		{"underscore is literal", UserSearch{Name: "_"}, nil},
+		{"whole email in any case", UserSearch{Email: "bob@example.COM"}, []string{"Bob"}},
+		{"partial email", UserSearch{Email: "bob"}, nil},
+		{"role", UserSearch{Role: "admin"}, []string{"Ann Lee"}},
+		// Born on 29 February, Ann turns 25 tomorrow, the 28th.
+		{"min age", UserSearch{MinAge: age(25)}, []string{"Joanna 100%", "Bob"}},
+		{"max age", UserSearch{MaxAge: age(24)}, []string{"Ann Lee"}},
+		{"age range", UserSearch{MinAge: age(30), MaxAge: age(45)}, []string{"Joanna 100%", "Bob"}},
+		{"created before", UserSearch{Name: "a", CreatedBefore: time.Now().Add(-time.Hour)}, nil},
+	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := calculateAge(tt.dob)
+			resp, err := svc.SearchUsersWithAgePaginated(ctx, tt.search, 1, 10)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, u := range resp.Data {
+				got = append(got, u.Name)
+			}
+			if !slices.Equal(got, tt.want) || resp.Pagination.Total != int64(len(tt.want)) {
+				t.Errorf("got %v (total %d), want %v", got, resp.Pagination.Total, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupByEmail(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryUserStore()
+	dob, _ := ParseDob("1990-01-01")
+	for _, email := range []string{"jane.doe@example.com", "Jane@Example.com", "janet@example.org", "john@example.com"} {
+		if _, err := store.CreateWithAuth(ctx, email, email, "hash", "", "api", dob); err != nil {
+			t.Fatal(err)
+		}
+	}
+	svc := NewUserService(store)
 
-			if result < tt.expected-1 || result > tt.expected+1 {
-				t.Errorf("calculateAge(%v) = %d; want approximately %d", tt.dob, result, tt.expected)
+	tests := []struct {
+		name  string
+		email string
+		limit int
+		want  []string
+	}{
+		{"prefix in any case", "JAN", 10, []string{"jane.doe@example.com", "Jane@Example.com", "janet@example.org"}},
+		{"whole email", " jane@example.com ", 10, []string{"Jane@Example.com"}},
+		{"wildcards are literal", "jan_", 10, nil},
+		{"limit", "jan", 2, []string{"jane.doe@example.com", "Jane@Example.com"}},
+		{"no match", "zed", 10, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users, err := svc.LookupByEmail(ctx, tt.email, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, u := range users {
+				got = append(got, u.Email)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
 			}
 		})
 	}
+
+	if _, err := svc.LookupByEmail(ctx, "ja", 10); !errors.Is(err, ErrLookupTooShort) {
+		t.Errorf("two characters = %v; want ErrLookupTooShort", err)
+	}
 }
 
-func TestCalculateAgeEdgeCases(t *testing.T) {
-	now := time.Now()
+func TestCurrentUserProfile(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryUserStore()
+	dob, _ := ParseDob("1990-01-01")
+	user, err := store.CreateWithAuth(ctx, "Jane", "jane@example.com", "hash", "", "api", dob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewUserService(store)
+	if err := svc.SaveProfile(ctx, user.ID, models.Profile{Bio: "hi"}); !errors.Is(err, ErrNoProfiles) {
+		t.Errorf("SaveProfile without a store = %v; want ErrNoProfiles", err)
+	}
+	svc.SetProfiles(repository.NewMemoryProfileStore())
+
+	me, err := svc.CurrentUser(ctx, user.ID)
+	if err != nil || me.Profile != (models.Profile{}) {
+		t.Fatalf("CurrentUser before any profile = %+v, %v; want an empty profile", me, err)
+	}
 
-	t.Run("Birthday today", func(t *testing.T) {
-		dob := now.AddDate(-30, 0, 0)
-		age := calculateAge(dob)
-		if age != 30 {
-			t.Errorf("calculateAge for birthday today = %d; want 30", age)
+	profile, _ := svc.Profile(ctx, user.ID)
+	bio, avatar := "Backend developer", "https://example.com/jane.png"
+	models.ProfileUpdateRequest{Bio: &bio, AvatarURL: &avatar}.Apply(&profile)
+	if err := svc.SaveProfile(ctx, user.ID, profile); err != nil {
+		t.Fatal(err)
+	}
+	profile, _ = svc.Profile(ctx, user.ID)
+	cleared := ""
+	models.ProfileUpdateRequest{AvatarURL: &cleared}.Apply(&profile)
+	if profile.Bio != bio || profile.AvatarURL != "" {
+		t.Errorf("after clearing the avatar the profile is %+v; want the bio kept", profile)
+	}
+
+	me, err = svc.CurrentUser(ctx, user.ID)
+	if err != nil || me.Profile.Bio != bio || me.AvatarURL != avatar {
+		t.Errorf("CurrentUser = %+v, %v; want the saved profile and its avatar", me, err)
+	}
+	public, err := svc.GetUserWithAge(ctx, user.ID)
+	if err != nil || public.AvatarURL == avatar {
+		t.Errorf("GetUserWithAge = %+v, %v; want the profile kept out", public, err)
+	}
+}
+
+func TestAgeAt(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryUserStore()
+	dob, _ := ParseDob("2000-02-29")
+	user, err := store.CreateWithAuth(ctx, "Leap", "leap@example.com", "hash", "", "api", dob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewUserService(store)
+	svc.SetClock(clock.NewFake(time.Date(2027, 2, 28, 23, 0, 0, 0, time.UTC)))
+
+	if resp, err := svc.AgeAt(ctx, user.ID, time.Time{}); err != nil || resp.Age != 27 || resp.At != "2027-02-28" {
+		t.Errorf("AgeAt(today) = %+v, %v; want 27 on 2027-02-28", resp, err)
+	}
+	at, _ := ParseDob("2030-01-01")
+	if resp, err := svc.AgeAt(ctx, user.ID, at); err != nil || resp.Age != 29 {
+		t.Errorf("AgeAt(2030-01-01) = %+v, %v; want 29", resp, err)
+	}
+	at, _ = ParseDob("1999-12-31")
+	if _, err := svc.AgeAt(ctx, user.ID, at); !errors.Is(err, ErrDateBeforeBirth) {
+		t.Errorf("AgeAt(before birth) = %v; want ErrDateBeforeBirth", err)
+	}
+}
+
+func TestParseUserSort(t *testing.T) {
+	keys, err := ParseUserSort("name,-created_at,age")
+	want := []repository.UserSortKey{
+		{Column: repository.SortName},
+		{Column: repository.SortCreatedAt, Desc: true},
+		{Column: repository.SortDob, Desc: true},
+	}
+	if err != nil || !slices.Equal(keys, want) {
+		t.Errorf("ParseUserSort() = %+v, %v; want %+v", keys, err, want)
+	}
+	if keys, err := ParseUserSort("-age"); err != nil || len(keys) != 1 || keys[0].Desc {
+		t.Errorf("ParseUserSort(-age) = %+v, %v; want dob ascending", keys, err)
+	}
+	for _, raw := range []string{"password_hash", "name,", "name,-name", "dob,age", "name,email,role,dob"} {
+		if _, err := ParseUserSort(raw); !errors.Is(err, ErrInvalidSort) {
+			t.Errorf("ParseUserSort(%q) = %v; want ErrInvalidSort", raw, err)
 		}
-	})
+	}
+}
 
-	t.Run("Birthday tomorrow", func(t *testing.T) {
-		dob := now.AddDate(-30, 0, 1)
-		age := calculateAge(dob)
-		if age != 29 {
-			t.Errorf("calculateAge for birthday tomorrow = %d; want 29", age)
+func TestSearchUsersSorted(t *testing.T) {
+	ctx := context.Background()
+	store := repository.NewMemoryUserStore()
+	for _, u := range []struct{ name, role, dob string }{
+		{"Cy", "user", "1990-01-01"},
+		{"Al", "admin", "1980-01-01"},
+		{"Bo", "user", "2000-01-01"},
+	} {
+		dob, _ := ParseDob(u.dob)
+		if _, err := store.CreateWithAuth(ctx, u.name, u.name+"@example.com", "hash", u.role, "api", dob); err != nil {
+			t.Fatal(err)
 		}
-	})
+	}
+	svc := NewUserService(store)
 
-	t.Run("Birthday yesterday", func(t *testing.T) {
-		dob := now.AddDate(-30, 0, -1)
-		age := calculateAge(dob)
-		if age != 30 {
-			t.Errorf("calculateAge for birthday yesterday = %d; want 30", age)
+	for raw, want := range map[string][]string{
+		"name":       {"Al", "Bo", "Cy"},
+		"-name":      {"Cy", "Bo", "Al"},
+		"age":        {"Bo", "Cy", "Al"},
+		"-role,name": {"Bo", "Cy", "Al"},
+	} {
+		sort, err := ParseUserSort(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp, err := svc.SearchUsersWithAgePaginated(ctx, UserSearch{Sort: sort}, 1, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, u := range resp.Data {
+			got = append(got, u.Name)
 		}
-	})
+		if !slices.Equal(got, want) {
+			t.Errorf("sort=%s: got %v, want %v", raw, got, want)
+		}
+	}
 }
@@ -0,0 +1,313 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/sso"
+	"BACKEND/internal/webauthn"
+)
+
+var (
+	ErrWebAuthnInvalidChallenge  = errors.New("passkey challenge is invalid or expired")
+	ErrWebAuthnUnknownCredential = errors.New("passkey is not registered")
+	ErrWebAuthnCredentialExists  = errors.New("passkey is already registered")
+	ErrWebAuthnCloned            = errors.New("passkey signature counter went backwards")
+	ErrWebAuthnSSORequired       = errors.New("account must sign in with sso")
+	ErrWebAuthnNotFound          = errors.New("passkey not found")
+)
+
+type WebAuthnConfig struct {
+	ChallengeTTL time.Duration
+}
+
+type webAuthnChallenge struct {
+	userID       int64
+	registration bool
+	expiresAt    time.Time
+}
+
+// WebAuthnService runs passkey registration and login. Issued challenges are
+// kept in memory until they are answered or expire; the challenge inside the
+// browser's client data identifies which ceremony a response belongs to.
+type WebAuthnService struct {
+	rp    *webauthn.RelyingParty
+	creds repository.WebAuthnCredentialStore
+	repo  repository.UserStore
+	auth  *AuthService
+	sso   *SSOService
+	cfg   WebAuthnConfig
+
+	mu         sync.Mutex
+	challenges map[string]*webAuthnChallenge
+}
+
+func NewWebAuthnService(rp *webauthn.RelyingParty, creds repository.WebAuthnCredentialStore, repo repository.UserStore, auth *AuthService, cfg WebAuthnConfig) *WebAuthnService {
+	return &WebAuthnService{
+		rp:         rp,
+		creds:      creds,
+		repo:       repo,
+		auth:       auth,
+		cfg:        cfg,
+		challenges: make(map[string]*webAuthnChallenge),
+	}
+}
+
+// SetSSO makes domains that must use SSO unable to register or log in with
+// passkeys.
+func (s *WebAuthnService) SetSSO(svc *SSOService) {
+	s.sso = svc
+}
+
+// BeginRegistration returns the options for navigator.credentials.create().
+func (s *WebAuthnService) BeginRegistration(ctx context.Context, userID int64) (*webauthn.CreationOptions, error) {
+	user, err := s.repo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load user: %w", err)
+	}
+	if err := s.checkUser(user.Email, user.AccountType, user.Active); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.creds.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list passkeys: %w", err)
+	}
+	exclude := make([]webauthn.CredentialDescriptor, 0, len(existing))
+	for _, cred := range existing {
+		exclude = append(exclude, webauthn.CredentialDescriptor{Type: "public-key", ID: cred.CredentialID})
+	}
+
+	challenge, err := s.newChallenge(userID, true)
+	if err != nil {
+		return nil, err
+	}
+	return &webauthn.CreationOptions{
+		Challenge: challenge,
+		RP:        webauthn.RelyingPartyEntity{ID: s.rp.ID(), Name: s.rp.Name()},
+		User: webauthn.UserEntity{
+			ID:          userHandle(user.ID),
+			Name:        user.Email,
+			DisplayName: user.Name,
+		},
+		PubKeyCredParams:   webauthn.SupportedAlgorithms(),
+		Timeout:            s.cfg.ChallengeTTL.Milliseconds(),
+		ExcludeCredentials: exclude,
+		AuthenticatorSelection: webauthn.AuthenticatorSelection{
+			ResidentKey:      "preferred",
+			UserVerification: "required",
+		},
+		Attestation: "none",
+	}, nil
+}
+
+// FinishRegistration verifies the browser's response and stores the new
+// passkey under name.
+func (s *WebAuthnService) FinishRegistration(ctx context.Context, userID int64, name string, resp webauthn.RegistrationResponse) (generated.WebauthnCredential, error) {
+	challenge, err := s.takeChallenge(resp.Response.ClientDataJSON, true)
+	if err != nil {
+		return generated.WebauthnCredential{}, err
+	}
+	if challenge.userID != userID {
+		return generated.WebauthnCredential{}, ErrWebAuthnInvalidChallenge
+	}
+
+	cred, err := s.rp.VerifyRegistration(challengeOf(resp.Response.ClientDataJSON), resp.Response.ClientDataJSON, resp.Response.AttestationObject)
+	if err != nil {
+		return generated.WebauthnCredential{}, err
+	}
+
+	if _, err := s.creds.Get(ctx, cred.ID); err == nil {
+		return generated.WebauthnCredential{}, ErrWebAuthnCredentialExists
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return generated.WebauthnCredential{}, fmt.Errorf("failed to look up passkey: %w", err)
+	}
+
+	created, err := s.creds.Create(ctx, userID, cred.ID, cred.PublicKey, int64(cred.SignCount), name)
+	if err != nil {
+		return generated.WebauthnCredential{}, fmt.Errorf("failed to store passkey: %w", err)
+	}
+	return created, nil
+}
+
+// BeginLogin returns the options for navigator.credentials.get(). They
+// allow any discoverable passkey: listing an account's passkeys would tell
+// the caller that the account exists.
+func (s *WebAuthnService) BeginLogin(ctx context.Context) (*webauthn.RequestOptions, error) {
+	challenge, err := s.newChallenge(0, false)
+	if err != nil {
+		return nil, err
+	}
+	return &webauthn.RequestOptions{
+		Challenge:        challenge,
+		Timeout:          s.cfg.ChallengeTTL.Milliseconds(),
+		RPID:             s.rp.ID(),
+		AllowCredentials: []webauthn.CredentialDescriptor{},
+		UserVerification: "required",
+	}, nil
+}
+
+// FinishLogin verifies a passkey assertion and logs its owner in.
+func (s *WebAuthnService) FinishLogin(ctx context.Context, resp webauthn.AssertionResponse) (generated.User, string, error) {
+	if _, err := s.takeChallenge(resp.Response.ClientDataJSON, false); err != nil {
+		return generated.User{}, "", err
+	}
+
+	cred, err := s.creds.Get(ctx, resp.RawID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return generated.User{}, "", ErrWebAuthnUnknownCredential
+		}
+		return generated.User{}, "", fmt.Errorf("failed to look up passkey: %w", err)
+	}
+	if len(resp.Response.UserHandle) > 0 && !bytes.Equal(resp.Response.UserHandle, userHandle(cred.UserID)) {
+		return generated.User{}, "", ErrWebAuthnUnknownCredential
+	}
+
+	signCount, err := s.rp.VerifyAssertion(challengeOf(resp.Response.ClientDataJSON), cred.PublicKey,
+		resp.Response.ClientDataJSON, resp.Response.AuthenticatorData, resp.Response.Signature)
+	if err != nil {
+		return generated.User{}, "", err
+	}
+	// A passkey stands in for the password, so the authenticator must have
+	// checked a PIN or biometric, not just a touch.
+	authData, err := webauthn.ParseAuthenticatorData(resp.Response.AuthenticatorData)
+	if err != nil {
+		return generated.User{}, "", err
+	}
+	if !authData.UserVerified() {
+		return generated.User{}, "", webauthn.ErrUserNotVerified
+	}
+	// Authenticators that keep a counter must increase it on every use; a
+	// counter that does not move forward suggests a cloned key.
+	if (signCount != 0 || cred.SignCount != 0) && int64(signCount) <= cred.SignCount {
+		return generated.User{}, "", ErrWebAuthnCloned
+	}
+	if err := s.creds.UpdateSignCount(ctx, cred.ID, int64(signCount)); err != nil {
+		return generated.User{}, "", fmt.Errorf("failed to update passkey: %w", err)
+	}
+
+	user, err := s.repo.GetByID(ctx, cred.UserID)
+	if err != nil {
+		return generated.User{}, "", fmt.Errorf("failed to load passkey owner: %w", err)
+	}
+	if err := s.checkUser(user.Email, user.AccountType, user.Active); err != nil {
+		return generated.User{}, "", err
+	}
+
+	token, err := s.auth.GenerateJWT(ctx, user.ID, user.Role)
+	if err != nil {
+		return generated.User{}, "", fmt.Errorf("failed to generate token: %w", err)
+	}
+
+	loggedIn := generated.User{
+		ID:          user.ID,
+		Name:        user.Name,
+		Dob:         user.Dob,
+		Email:       user.Email,
+		Role:        user.Role,
+		CreatedAt:   user.CreatedAt,
+		UpdatedAt:   user.UpdatedAt,
+		Active:      user.Active,
+		AccountType: user.AccountType,
+		PublicID:    user.PublicID,
+	}
+	s.auth.afterLogin(ctx, loggedIn)
+	return loggedIn, token, nil
+}
+
+func (s *WebAuthnService) ListCredentials(ctx context.Context, userID int64) ([]generated.WebauthnCredential, error) {
+	return s.creds.ListByUser(ctx, userID)
+}
+
+func (s *WebAuthnService) DeleteCredential(ctx context.Context, id, userID int64) error {
+	deleted, err := s.creds.Delete(ctx, id, userID)
+	if err != nil {
+		return fmt.Errorf("failed to delete passkey: %w", err)
+	}
+	if !deleted {
+		return ErrWebAuthnNotFound
+	}
+	return nil
+}
+
+func (s *WebAuthnService) checkUser(email, accountType string, active bool) error {
+	if accountType == AccountTypeService {
+		return ErrInteractiveLoginDenied
+	}
+	if !active {
+		return ErrAccountDisabled
+	}
+	if s.sso != nil && s.sso.RequiresSSO(email) {
+		return ErrWebAuthnSSORequired
+	}
+	return nil
+}
+
+func (s *WebAuthnService) newChallenge(userID int64, registration bool) (string, error) {
+	challenge, err := sso.RandomString()
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pruneLocked()
+	s.challenges[challenge] = &webAuthnChallenge{
+		userID:       userID,
+		registration: registration,
+		expiresAt:    time.Now().Add(s.cfg.ChallengeTTL),
+	}
+	return challenge, nil
+}
+
+// takeChallenge finds the ceremony a response answers and removes it, so
+// each challenge can be answered once.
+func (s *WebAuthnService) takeChallenge(clientDataJSON []byte, registration bool) (*webAuthnChallenge, error) {
+	challenge := challengeOf(clientDataJSON)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.challenges[challenge]
+	if !ok || c.registration != registration {
+		return nil, ErrWebAuthnInvalidChallenge
+	}
+	delete(s.challenges, challenge)
+	if time.Now().After(c.expiresAt) {
+		return nil, ErrWebAuthnInvalidChallenge
+	}
+	return c, nil
+}
+
+func (s *WebAuthnService) pruneLocked() {
+	now := time.Now()
+	for challenge, c := range s.challenges {
+		if now.After(c.expiresAt) {
+			delete(s.challenges, challenge)
+		}
+	}
+}
+
+func challengeOf(clientDataJSON []byte) string {
+	cd, err := webauthn.ParseClientData(clientDataJSON)
+	if err != nil {
+		return ""
+	}
+	return cd.Challenge
+}
+
+// userHandle is the WebAuthn user.id for an account. Authenticators return
+// it with discoverable credentials.
+func userHandle(userID int64) []byte {
+	return []byte(strconv.FormatInt(userID, 10))
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/webauthn"
+)
+
+type fakeWebAuthnUserStore struct {
+	repository.UserStore
+	user generated.GetUserByIDRow
+}
+
+func (f *fakeWebAuthnUserStore) GetByID(ctx context.Context, id int64) (generated.GetUserByIDRow, error) {
+	return f.user, nil
+}
+
+type fakeCredentialStore struct {
+	creds []generated.WebauthnCredential
+}
+
+func (f *fakeCredentialStore) Create(ctx context.Context, userID int64, credentialID, publicKey []byte, signCount int64, name string) (generated.WebauthnCredential, error) {
+	cred := generated.WebauthnCredential{
+		ID:           int64(len(f.creds) + 1),
+		UserID:       userID,
+		CredentialID: credentialID,
+		PublicKey:    publicKey,
+		SignCount:    signCount,
+		Name:         name,
+	}
+	f.creds = append(f.creds, cred)
+	return cred, nil
+}
+
+func (f *fakeCredentialStore) Get(ctx context.Context, credentialID []byte) (generated.WebauthnCredential, error) {
+	for _, cred := range f.creds {
+		if bytes.Equal(cred.CredentialID, credentialID) {
+			return cred, nil
+		}
+	}
+	return generated.WebauthnCredential{}, pgx.ErrNoRows
+}
+
+func (f *fakeCredentialStore) ListByUser(ctx context.Context, userID int64) ([]generated.WebauthnCredential, error) {
+	return f.creds, nil
+}
+
+func (f *fakeCredentialStore) UpdateSignCount(ctx context.Context, id int64, signCount int64) error {
+	f.creds[id-1].SignCount = signCount
+	return nil
+}
+
+func (f *fakeCredentialStore) Delete(ctx context.Context, id, userID int64) (bool, error) {
+	return false, nil
+}
+
+// testPasskey is a software ES256 authenticator.
+type testPasskey struct {
+	key       *ecdsa.PrivateKey
+	id        []byte
+	signCount uint32
+}
+
+func newTestPasskey(t *testing.T) *testPasskey {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testPasskey{key: key, id: []byte("passkey-1")}
+}
+
+// coseKey is the CBOR map {1: 2, 3: -7, -1: 1, -2: x, -3: y}.
+func (p *testPasskey) coseKey() []byte {
+	x, y := make([]byte, 32), make([]byte, 32)
+	p.key.X.FillBytes(x)
+	p.key.Y.FillBytes(y)
+	out := []byte{0xa5, 0x01, 0x02, 0x03, 0x26, 0x20, 0x01, 0x21, 0x58, 0x20}
+	out = append(out, x...)
+	out = append(out, 0x22, 0x58, 0x20)
+	return append(out, y...)
+}
+
+func (p *testPasskey) authData(flags byte, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte("example.com"))
+	out := append(rpIDHash[:], flags)
+	out = binary.BigEndian.AppendUint32(out, p.signCount)
+	if attested {
+		out = append(out, make([]byte, 16)...)
+		out = binary.BigEndian.AppendUint16(out, uint16(len(p.id)))
+		out = append(out, p.id...)
+		out = append(out, p.coseKey()...)
+	}
+	return out
+}
+
+func testClientData(t *testing.T, typ, challenge string) []byte {
+	t.Helper()
+	b, err := json.Marshal(map[string]string{"type": typ, "challenge": challenge, "origin": "https://example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func (p *testPasskey) register(t *testing.T, challenge string) webauthn.RegistrationResponse {
+	t.Helper()
+	authData := p.authData(0x01|0x40, true)
+	// {"fmt": "none", "attStmt": {}, "authData": authData}
+	att := []byte{0xa3, 0x63, 'f', 'm', 't', 0x64, 'n', 'o', 'n', 'e', 0x67, 'a', 't', 't', 'S', 't', 'm', 't', 0xa0, 0x68, 'a', 'u', 't', 'h', 'D', 'a', 't', 'a', 0x59}
+	att = binary.BigEndian.AppendUint16(att, uint16(len(authData)))
+	att = append(att, authData...)
+
+	var resp webauthn.RegistrationResponse
+	resp.RawID = p.id
+	resp.Response.ClientDataJSON = testClientData(t, "webauthn.create", challenge)
+	resp.Response.AttestationObject = att
+	return resp
+}
+
+// assert signs an assertion with the user present and verified.
+func (p *testPasskey) assert(t *testing.T, challenge string) webauthn.AssertionResponse {
+	t.Helper()
+	return p.assertFlags(t, challenge, 0x01|0x04)
+}
+
+func (p *testPasskey) assertFlags(t *testing.T, challenge string, flags byte) webauthn.AssertionResponse {
+	t.Helper()
+	clientData := testClientData(t, "webauthn.get", challenge)
+	authData := p.authData(flags, false)
+	hash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte(nil), authData...), hash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, p.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var resp webauthn.AssertionResponse
+	resp.RawID = p.id
+	resp.Response.ClientDataJSON = clientData
+	resp.Response.AuthenticatorData = authData
+	resp.Response.Signature = sig
+	return resp
+}
+
+func newTestWebAuthnService(user generated.GetUserByIDRow) (*WebAuthnService, *fakeWebAuthnUserStore) {
+	users := &fakeWebAuthnUserStore{user: user}
+	auth := NewAuthService(users)
+	auth.SetJWTConfig("test-secret", time.Hour)
+	rp := webauthn.NewRelyingParty(webauthn.Config{RPID: "example.com", RPName: "Example", Origins: []string{"https://example.com"}})
+	return NewWebAuthnService(rp, &fakeCredentialStore{}, users, auth, WebAuthnConfig{ChallengeTTL: time.Minute}), users
+}
+
+func registerTestPasskey(t *testing.T, svc *WebAuthnService, passkey *testPasskey) {
+	t.Helper()
+	ctx := context.Background()
+	opts, err := svc.BeginRegistration(ctx, 7)
+	if err != nil {
+		t.Fatalf("BeginRegistration: %v", err)
+	}
+	if _, err := svc.FinishRegistration(ctx, 7, "laptop", passkey.register(t, opts.Challenge)); err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+}
+
+func TestWebAuthnRegisterAndLogin(t *testing.T) {
+	svc, _ := newTestWebAuthnService(generated.GetUserByIDRow{ID: 7, Email: "jane@example.com", Role: "user", Active: true, AccountType: AccountTypeHuman})
+	passkey := newTestPasskey(t)
+	ctx := context.Background()
+	registerTestPasskey(t, svc, passkey)
+
+	opts, err := svc.BeginLogin(ctx)
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+	passkey.signCount = 1
+	assertion := passkey.assert(t, opts.Challenge)
+	user, token, err := svc.FinishLogin(ctx, assertion)
+	if err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+	if user.ID != 7 || token == "" {
+		t.Fatalf("FinishLogin = %d, %q", user.ID, token)
+	}
+
+	if _, _, err := svc.FinishLogin(ctx, assertion); !errors.Is(err, ErrWebAuthnInvalidChallenge) {
+		t.Fatalf("replayed FinishLogin err = %v, want %v", err, ErrWebAuthnInvalidChallenge)
+	}
+}
+
+func TestWebAuthnRejectsCounterRegression(t *testing.T) {
+	svc, _ := newTestWebAuthnService(generated.GetUserByIDRow{ID: 7, Email: "jane@example.com", Role: "user", Active: true, AccountType: AccountTypeHuman})
+	passkey := newTestPasskey(t)
+	ctx := context.Background()
+	registerTestPasskey(t, svc, passkey)
+
+	for i, count := range []uint32{5, 5} {
+		opts, err := svc.BeginLogin(ctx)
+		if err != nil {
+			t.Fatalf("BeginLogin: %v", err)
+		}
+		passkey.signCount = count
+		_, _, err = svc.FinishLogin(ctx, passkey.assert(t, opts.Challenge))
+		if i == 0 && err != nil {
+			t.Fatalf("FinishLogin: %v", err)
+		}
+		if i == 1 && !errors.Is(err, ErrWebAuthnCloned) {
+			t.Fatalf("repeated counter err = %v, want %v", err, ErrWebAuthnCloned)
+		}
+	}
+}
+
+func TestWebAuthnLoginDisabledAccount(t *testing.T) {
+	svc, users := newTestWebAuthnService(generated.GetUserByIDRow{ID: 7, Email: "jane@example.com", Role: "user", Active: true, AccountType: AccountTypeHuman})
+	passkey := newTestPasskey(t)
+	ctx := context.Background()
+	registerTestPasskey(t, svc, passkey)
+
+	users.user.Active = false
+	opts, err := svc.BeginLogin(ctx)
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+	passkey.signCount = 1
+	if _, _, err := svc.FinishLogin(ctx, passkey.assert(t, opts.Challenge)); !errors.Is(err, ErrAccountDisabled) {
+		t.Fatalf("err = %v, want %v", err, ErrAccountDisabled)
+	}
+}
+
+func TestWebAuthnRegistrationChallengeBoundToUser(t *testing.T) {
+	svc, _ := newTestWebAuthnService(generated.GetUserByIDRow{ID: 7, Email: "jane@example.com", Role: "user", Active: true, AccountType: AccountTypeHuman})
+	passkey := newTestPasskey(t)
+	ctx := context.Background()
+
+	opts, err := svc.BeginRegistration(ctx, 7)
+	if err != nil {
+		t.Fatalf("BeginRegistration: %v", err)
+	}
+	if _, err := svc.FinishRegistration(ctx, 8, "laptop", passkey.register(t, opts.Challenge)); !errors.Is(err, ErrWebAuthnInvalidChallenge) {
+		t.Fatalf("err = %v, want %v", err, ErrWebAuthnInvalidChallenge)
+	}
+}
+
+func TestWebAuthnLoginRequiresUserVerification(t *testing.T) {
+	svc, _ := newTestWebAuthnService(generated.GetUserByIDRow{ID: 7, Email: "jane@example.com", Role: "user", Active: true, AccountType: AccountTypeHuman})
+	passkey := newTestPasskey(t)
+	ctx := context.Background()
+	registerTestPasskey(t, svc, passkey)
+
+	opts, err := svc.BeginLogin(ctx)
+	if err != nil {
+		t.Fatalf("BeginLogin: %v", err)
+	}
+	if opts.UserVerification != "required" {
+		t.Fatalf("UserVerification = %q, want %q", opts.UserVerification, "required")
+	}
+	if len(opts.AllowCredentials) != 0 {
+		t.Fatalf("AllowCredentials = %v, want none", opts.AllowCredentials)
+	}
+	passkey.signCount = 1
+	if _, _, err := svc.FinishLogin(ctx, passkey.assertFlags(t, opts.Challenge, 0x01)); !errors.Is(err, webauthn.ErrUserNotVerified) {
+		t.Fatalf("err = %v, want %v", err, webauthn.ErrUserNotVerified)
+	}
+}
@@ -0,0 +1,206 @@
+package sso
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestCodeChallenge(t *testing.T) {
+	// Test vector from RFC 7636 appendix B.
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	expected := "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM"
+
+	if got := CodeChallenge(verifier); got != expected {
+		t.Errorf("CodeChallenge() = %q, expected %q", got, expected)
+	}
+}
+
+// testIdP serves discovery and a JWKS holding the keys it currently signs
+// with.
+type testIdP struct {
+	*httptest.Server
+
+	mu   sync.Mutex
+	keys map[string]*rsa.PrivateKey
+}
+
+func newTestIdP(t *testing.T) *testIdP {
+	t.Helper()
+	idp := &testIdP{keys: make(map[string]*rsa.PrivateKey)}
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(discoveryDocument{
+			Issuer:                idp.URL,
+			AuthorizationEndpoint: idp.URL + "/authorize",
+			TokenEndpoint:         idp.URL + "/token",
+			JWKSURI:               idp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		idp.mu.Lock()
+		defer idp.mu.Unlock()
+		var keys []jsonWebKey
+		for kid, key := range idp.keys {
+			keys = append(keys, jsonWebKey{
+				Kid: kid,
+				Kty: "RSA",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+	})
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+// rotate replaces the IdP's keys with a new one under kid.
+func (idp *testIdP) rotate(t *testing.T, kid string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.keys = map[string]*rsa.PrivateKey{kid: key}
+}
+
+func (idp *testIdP) sign(t *testing.T, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	idp.mu.Lock()
+	key := idp.keys[kid]
+	idp.mu.Unlock()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func (idp *testIdP) claims() jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":   idp.URL,
+		"aud":   "client-1",
+		"sub":   "user-1",
+		"nonce": "nonce-1",
+		"exp":   time.Now().Add(time.Minute).Unix(),
+	}
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	idp := newTestIdP(t)
+	idp.rotate(t, "key-1")
+	provider := NewOIDCProvider(OIDCConfig{Issuer: idp.URL, ClientID: "client-1"})
+	ctx := context.Background()
+
+	claims, err := provider.verifyIDToken(ctx, idp.sign(t, "key-1", idp.claims()), "nonce-1")
+	if err != nil {
+		t.Fatalf("verifyIDToken() error = %v", err)
+	}
+	if claims["sub"] != "user-1" {
+		t.Errorf("sub = %v, expected user-1", claims["sub"])
+	}
+
+	tests := []struct {
+		name   string
+		change func(jwt.MapClaims)
+	}{
+		{"wrong issuer", func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{"wrong audience", func(c jwt.MapClaims) { c["aud"] = "client-2" }},
+		{"wrong nonce", func(c jwt.MapClaims) { c["nonce"] = "nonce-2" }},
+		{"no nonce", func(c jwt.MapClaims) { delete(c, "nonce") }},
+		{"expired", func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Minute).Unix() }},
+		{"no expiry", func(c jwt.MapClaims) { delete(c, "exp") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := idp.claims()
+			tt.change(claims)
+			if _, err := provider.verifyIDToken(ctx, idp.sign(t, "key-1", claims), "nonce-1"); !errors.Is(err, ErrInvalidIDToken) {
+				t.Errorf("verifyIDToken() error = %v, expected %v", err, ErrInvalidIDToken)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenRejectsAlgorithms(t *testing.T) {
+	idp := newTestIdP(t)
+	idp.rotate(t, "key-1")
+	provider := NewOIDCProvider(OIDCConfig{Issuer: idp.URL, ClientID: "client-1"})
+
+	tests := []struct {
+		name  string
+		token func() string
+	}{
+		{"none", func() string {
+			token := jwt.NewWithClaims(jwt.SigningMethodNone, idp.claims())
+			token.Header["kid"] = "key-1"
+			signed, _ := token.SignedString(jwt.UnsafeAllowNoneSignatureType)
+			return signed
+		}},
+		{"HS256 with the public key", func() string {
+			idp.mu.Lock()
+			public := idp.keys["key-1"].PublicKey
+			idp.mu.Unlock()
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, idp.claims())
+			token.Header["kid"] = "key-1"
+			signed, _ := token.SignedString(public.N.Bytes())
+			return signed
+		}},
+		{"PS256", func() string {
+			idp.mu.Lock()
+			key := idp.keys["key-1"]
+			idp.mu.Unlock()
+			token := jwt.NewWithClaims(jwt.SigningMethodPS256, idp.claims())
+			token.Header["kid"] = "key-1"
+			signed, _ := token.SignedString(key)
+			return signed
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := provider.verifyIDToken(context.Background(), tt.token(), "nonce-1"); !errors.Is(err, ErrInvalidIDToken) {
+				t.Errorf("verifyIDToken() error = %v, expected %v", err, ErrInvalidIDToken)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenKeyRotation(t *testing.T) {
+	idp := newTestIdP(t)
+	idp.rotate(t, "key-1")
+	provider := NewOIDCProvider(OIDCConfig{Issuer: idp.URL, ClientID: "client-1"})
+	ctx := context.Background()
+
+	old := idp.sign(t, "key-1", idp.claims())
+	if _, err := provider.verifyIDToken(ctx, old, "nonce-1"); err != nil {
+		t.Fatalf("verifyIDToken() before rotation error = %v", err)
+	}
+
+	// A token signed with a key the cache doesn't have makes the provider
+	// fetch the JWKS again.
+	idp.rotate(t, "key-2")
+	if _, err := provider.verifyIDToken(ctx, idp.sign(t, "key-2", idp.claims()), "nonce-1"); err != nil {
+		t.Fatalf("verifyIDToken() after rotation error = %v", err)
+	}
+	// The refetch drops the retired key.
+	if _, err := provider.verifyIDToken(ctx, old, "nonce-1"); !errors.Is(err, ErrInvalidIDToken) {
+		t.Errorf("verifyIDToken() with retired key error = %v, expected %v", err, ErrInvalidIDToken)
+	}
+}
@@ -0,0 +1,144 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxPresignExpiry is the longest S3 honours a presigned URL for.
+const maxPresignExpiry = 7 * 24 * time.Hour
+
+// S3 keeps objects in an S3 bucket, or any service with the S3 API such as
+// MinIO, under Prefix. Requests use path-style URLs, and URL returns
+// presigned ones, so the bucket can stay private.
+type S3 struct {
+	// Endpoint defaults to https://s3.<Region>.amazonaws.com.
+	Endpoint    string
+	Bucket      string
+	Prefix      string
+	Credentials Credentials
+	Client      *http.Client
+	now         func() time.Time
+}
+
+func (s *S3) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+	req, err := s.request(ctx, http.MethodPut, key, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	s.Credentials.Sign(req, s.time())
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	if !validKey(key) {
+		return nil, ErrNotFound
+	}
+	req, err := s.request(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	s.Credentials.Sign(req, s.time())
+	resp, err := s.do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+func (s *S3) Delete(ctx context.Context, key string) error {
+	if !validKey(key) {
+		return ErrInvalidKey
+	}
+	req, err := s.request(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	s.Credentials.Sign(req, s.time())
+	resp, err := s.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3) URL(key string, expires time.Time) (string, error) {
+	if !validKey(key) {
+		return "", ErrInvalidKey
+	}
+	now := s.time()
+	ttl := expires.Sub(now).Truncate(time.Second)
+	if ttl <= 0 {
+		return "", fmt.Errorf("url would expire at %s, before it is made", expires.Format(time.RFC3339))
+	}
+	if ttl > maxPresignExpiry {
+		ttl = maxPresignExpiry
+	}
+	req, err := s.request(context.Background(), http.MethodGet, key, nil)
+	if err != nil {
+		return "", err
+	}
+	s.Credentials.Presign(req, now, ttl)
+	return req.URL.String(), nil
+}
+
+func (s *S3) endpoint() string {
+	if s.Endpoint != "" {
+		return strings.TrimSuffix(s.Endpoint, "/")
+	}
+	return "https://s3." + s.Credentials.Region + ".amazonaws.com"
+}
+
+func (s *S3) time() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+func (s *S3) request(ctx context.Context, method, key string, body io.Reader) (*http.Request, error) {
+	u, err := url.Parse(s.endpoint() + EscapePath("/"+s.Bucket+"/"+s.Prefix+key))
+	if err != nil {
+		return nil, err
+	}
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+func (s *S3) do(req *http.Request) (*http.Response, error) {
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusNotFound && req.Method == http.MethodGet {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		return nil, fmt.Errorf("s3 %s %s: %s: %s", req.Method, req.URL.Path, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return resp, nil
+}
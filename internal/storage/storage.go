@@ -0,0 +1,69 @@
+// Package storage keeps uploaded files, such as avatars, as objects on
+// local disk or in an S3 bucket, and hands out URLs they can be downloaded
+// from for a while without credentials.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"BACKEND/config"
+)
+
+var (
+	// ErrNotFound is returned by Storage.Get for an object that doesn't
+	// exist.
+	ErrNotFound   = errors.New("object not found")
+	ErrInvalidKey = errors.New("invalid object key")
+	ErrURLInvalid = errors.New("file url is invalid")
+	ErrURLExpired = errors.New("file url has expired")
+)
+
+// Storage keeps objects by key. Keys are slash-separated paths such as
+// "avatars/<id>/<name>.png".
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes an object. Deleting one that doesn't exist is not an
+	// error.
+	Delete(ctx context.Context, key string) error
+	// URL returns a URL anyone holding it can download the object from
+	// until expires.
+	URL(key string, expires time.Time) (string, error)
+}
+
+// New returns the S3 bucket in cfg if one is set, and the local directory
+// otherwise. Local files are served by the API under baseURL.
+func New(cfg config.Uploads, baseURL string) Storage {
+	if cfg.S3Bucket != "" {
+		return &S3{
+			Endpoint: cfg.S3Endpoint,
+			Bucket:   cfg.S3Bucket,
+			Prefix:   cfg.S3Prefix,
+			Credentials: Credentials{
+				Region:       cfg.S3Region,
+				AccessKey:    cfg.AccessKey,
+				SecretKey:    cfg.SecretKey,
+				SessionToken: cfg.SessionToken,
+			},
+		}
+	}
+	return &Local{Dir: cfg.Dir, BaseURL: baseURL, Secret: cfg.URLSecret}
+}
+
+// validKey rejects keys that are empty, absolute or could leave the
+// storage, and hidden names, which Local uses for partial uploads.
+func validKey(key string) bool {
+	if key == "" || len(key) > 1024 {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || strings.HasPrefix(part, ".") || strings.ContainsAny(part, "\\\x00") {
+			return false
+		}
+	}
+	return true
+}
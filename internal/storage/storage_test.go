@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLocalSignedURLs(t *testing.T) {
+	ctx := context.Background()
+	s := &Local{Dir: t.TempDir(), BaseURL: "https://api.example.com/", Secret: "secret"}
+	if err := s.Put(ctx, "avatars/u1/a.png", strings.NewReader("png"), 3, "image/png"); err != nil {
+		t.Fatal(err)
+	}
+	f, err := s.Get(ctx, "avatars/u1/a.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(f)
+	f.Close()
+	if string(data) != "png" {
+		t.Errorf("Get returned %q", data)
+	}
+
+	raw, err := s.URL("avatars/u1/a.png", time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(raw)
+	if u.Host != "api.example.com" || u.Path != LocalPath+"avatars/u1/a.png" {
+		t.Fatalf("URL = %s", raw)
+	}
+	expires, signature := u.Query().Get("expires"), u.Query().Get("signature")
+	if err := s.Verify("avatars/u1/a.png", expires, signature); err != nil {
+		t.Errorf("Verify = %v", err)
+	}
+	if err := s.Verify("avatars/u2/a.png", expires, signature); !errors.Is(err, ErrURLInvalid) {
+		t.Errorf("Verify for another key = %v; want ErrURLInvalid", err)
+	}
+	if err := s.Verify("avatars/u1/a.png", expires+"0", signature); !errors.Is(err, ErrURLInvalid) {
+		t.Errorf("Verify with a later expiry = %v; want ErrURLInvalid", err)
+	}
+	old, _ := s.URL("avatars/u1/a.png", time.Now().Add(-time.Minute))
+	u, _ = url.Parse(old)
+	if err := s.Verify("avatars/u1/a.png", u.Query().Get("expires"), u.Query().Get("signature")); !errors.Is(err, ErrURLExpired) {
+		t.Errorf("Verify after expiry = %v; want ErrURLExpired", err)
+	}
+
+	if err := s.Delete(ctx, "avatars/u1/a.png"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(ctx, "avatars/u1/a.png"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete = %v; want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, "avatars/u1/a.png"); err != nil {
+		t.Errorf("deleting twice = %v", err)
+	}
+
+	for _, key := range []string{"", "/etc/passwd", "avatars/../../secret", "avatars/.tmp-1", "avatars//a.png"} {
+		if err := s.Put(ctx, key, strings.NewReader("x"), 1, ""); !errors.Is(err, ErrInvalidKey) {
+			t.Errorf("Put(%q) = %v; want ErrInvalidKey", key, err)
+		}
+	}
+}
+
+func TestS3PutAndPresign(t *testing.T) {
+	var method, path, contentType, auth, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		contentType = r.Header.Get("Content-Type")
+		auth = r.Header.Get("Authorization")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	s := &S3{
+		Endpoint:    srv.URL,
+		Bucket:      "bucket",
+		Prefix:      "uploads/",
+		Credentials: Credentials{Region: "eu-west-1", AccessKey: "AKID", SecretKey: "secret", SessionToken: "token"},
+		now:         func() time.Time { return now },
+	}
+	if err := s.Put(context.Background(), "avatars/u1/a.png", strings.NewReader("png"), 3, "image/png"); err != nil {
+		t.Fatal(err)
+	}
+	if method != http.MethodPut || path != "/bucket/uploads/avatars/u1/a.png" || contentType != "image/png" || body != "png" {
+		t.Errorf("unexpected upload %s %s (%s): %q", method, path, contentType, body)
+	}
+	if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/20260102/eu-west-1/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date;x-amz-security-token, Signature=") {
+		t.Errorf("unexpected Authorization header %q", auth)
+	}
+
+	raw, err := s.URL("avatars/u1/a.png", now.Add(30*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(raw)
+	q := u.Query()
+	if u.Path != "/bucket/uploads/avatars/u1/a.png" || q.Get("X-Amz-Expires") != "604800" || q.Get("X-Amz-Security-Token") != "token" || len(q.Get("X-Amz-Signature")) != 64 {
+		t.Errorf("unexpected presigned URL %s", raw)
+	}
+	if !strings.HasSuffix(u.RawQuery, "&X-Amz-Signature="+q.Get("X-Amz-Signature")) {
+		t.Errorf("signature should come last in %s", u.RawQuery)
+	}
+	if _, err := s.URL("avatars/u1/a.png", now.Add(-time.Second)); err == nil {
+		t.Error("URL in the past should fail")
+	}
+}
@@ -0,0 +1,217 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed emails
+var emailFS embed.FS
+
+var ErrTemplateNotFound = errors.New("email template not found")
+
+type Branding struct {
+	ProductName  string `json:"product_name"`
+	LogoURL      string `json:"logo_url"`
+	SupportEmail string `json:"support_email"`
+	BaseURL      string `json:"base_url"`
+}
+
+type Email struct {
+	Subject string `json:"subject"`
+	HTML    string `json:"html"`
+}
+
+type TemplateInfo struct {
+	Name    string   `json:"name"`
+	Locales []string `json:"locales"`
+}
+
+type templateData struct {
+	Locale string
+	Brand  Branding
+	Data   map[string]interface{}
+}
+
+type Renderer struct {
+	branding      Branding
+	defaultLocale string
+	templates     map[string]map[string]*template.Template
+}
+
+func NewRenderer(branding Branding, defaultLocale string) (*Renderer, error) {
+	r := &Renderer{
+		branding:      branding,
+		defaultLocale: defaultLocale,
+		templates:     make(map[string]map[string]*template.Template),
+	}
+
+	layout, err := template.ParseFS(emailFS, "emails/layout.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse email layout: %w", err)
+	}
+
+	files, err := fs.Glob(emailFS, "emails/*/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		locale := path.Base(path.Dir(file))
+		name := strings.TrimSuffix(path.Base(file), ".html")
+
+		base, err := layout.Clone()
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err := base.ParseFS(emailFS, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse email template %s: %w", file, err)
+		}
+
+		if r.templates[name] == nil {
+			r.templates[name] = make(map[string]*template.Template)
+		}
+		r.templates[name][locale] = tmpl
+	}
+
+	return r, nil
+}
+
+func (r *Renderer) Branding() Branding {
+	return r.branding
+}
+
+// Render executes the named email template for the requested locale, falling
+// back to the default locale when no translation exists.
+func (r *Renderer) Render(name, locale string, data map[string]interface{}) (*Email, error) {
+	byLocale, ok := r.templates[name]
+	if !ok {
+		return nil, ErrTemplateNotFound
+	}
+
+	tmpl, ok := byLocale[locale]
+	if !ok {
+		locale = r.defaultLocale
+		tmpl, ok = byLocale[locale]
+		if !ok {
+			return nil, ErrTemplateNotFound
+		}
+	}
+
+	td := templateData{
+		Locale: locale,
+		Brand:  r.branding,
+		Data:   data,
+	}
+
+	var subject bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", td); err != nil {
+		return nil, fmt.Errorf("failed to render subject for %s: %w", name, err)
+	}
+
+	var body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&body, "layout", td); err != nil {
+		return nil, fmt.Errorf("failed to render body for %s: %w", name, err)
+	}
+
+	return &Email{
+		Subject: strings.TrimSpace(html.UnescapeString(subject.String())),
+		HTML:    body.String(),
+	}, nil
+}
+
+func (r *Renderer) List() []TemplateInfo {
+	infos := make([]TemplateInfo, 0, len(r.templates))
+	for name, byLocale := range r.templates {
+		locales := make([]string, 0, len(byLocale))
+		for locale := range byLocale {
+			locales = append(locales, locale)
+		}
+		sort.Strings(locales)
+		infos = append(infos, TemplateInfo{Name: name, Locales: locales})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// SampleData returns placeholder values used when previewing a template.
+func SampleData(name string) map[string]interface{} {
+	switch name {
+	case "password_reset":
+		return map[string]interface{}{
+			"Name":      "Jane Doe",
+			"ResetURL":  "https://example.com/reset-password?token=sample",
+			"ExpiresIn": "1 hour",
+		}
+	case "password_reset_required":
+		return map[string]interface{}{
+			"Name":      "Jane Doe",
+			"ResetURL":  "https://example.com/reset-password?token=sample",
+			"ExpiresIn": "1h0m0s",
+		}
+	case "magic_link":
+		return map[string]interface{}{
+			"Name":      "Jane Doe",
+			"LoginURL":  "https://example.com/auth/magic-link/verify?token=sample",
+			"ExpiresIn": "15m0s",
+		}
+	case "login_challenge":
+		return map[string]interface{}{
+			"Name":      "Jane Doe",
+			"Code":      "482913",
+			"IPAddress": "203.0.113.7",
+			"ExpiresIn": "10m0s",
+		}
+	case "admin_digest":
+		return map[string]interface{}{
+			"Name":    "Jane Doe",
+			"From":    "Jan 2, 2006",
+			"To":      "Jan 8, 2006",
+			"Signups": 14,
+			"SignupsByDay": []map[string]interface{}{
+				{"Day": "2006-01-02", "Count": 5},
+				{"Day": "2006-01-05", "Count": 9},
+			},
+			"FailedLogins": 63,
+			"Spikes": []map[string]interface{}{
+				{"Day": "2006-01-04", "Count": 48},
+			},
+			"DeactivatedCount": 1,
+			"Deactivated": []map[string]interface{}{
+				{"Name": "John Roe", "Email": "john@example.com"},
+			},
+			"MoreDeactivated": 0,
+		}
+	case "security_alert":
+		return map[string]interface{}{
+			"Name":        "Jane Doe",
+			"Rule":        "credential_stuffing",
+			"Key":         "203.0.113.7",
+			"Description": "failed logins against many accounts from one IP",
+			"Failures":    42,
+			"Window":      "10m0s",
+			"Accounts":    []string{"alice@example.com", "bob@example.com"},
+			"DetectedAt":  "Mon, 02 Jan 2006 15:04:05 UTC",
+		}
+	case "notification":
+		return map[string]interface{}{
+			"Name":     "Jane Doe",
+			"Event":    "role_changed",
+			"UserName": "John Roe",
+			"Details":  map[string]string{"from": "user", "to": "moderator"},
+		}
+	default:
+		return map[string]interface{}{
+			"Name": "Jane Doe",
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package templates
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func newTestRenderer(t *testing.T) *Renderer {
+	t.Helper()
+	r, err := NewRenderer(Branding{
+		ProductName:  "Acme & Co",
+		SupportEmail: "help@acme.test",
+		BaseURL:      "https://acme.test",
+	}, "en")
+	if err != nil {
+		t.Fatalf("NewRenderer() error = %v", err)
+	}
+	return r
+}
+
+func TestRender_AllTemplatesAndLocales(t *testing.T) {
+	r := newTestRenderer(t)
+
+	for _, info := range r.List() {
+		for _, locale := range info.Locales {
+			t.Run(info.Name+"/"+locale, func(t *testing.T) {
+				email, err := r.Render(info.Name, locale, SampleData(info.Name))
+				if err != nil {
+					t.Fatalf("Render() error = %v", err)
+				}
+				if email.Subject == "" {
+					t.Error("expected a non-empty subject")
+				}
+				if !strings.Contains(email.HTML, "Jane Doe") {
+					t.Error("expected sample data in rendered HTML")
+				}
+				if !strings.Contains(email.HTML, "help@acme.test") {
+					t.Error("expected branding in rendered HTML")
+				}
+			})
+		}
+	}
+}
+
+func TestRender_SubjectIsNotHTMLEscaped(t *testing.T) {
+	r := newTestRenderer(t)
+
+	email, err := r.Render("welcome", "en", SampleData("welcome"))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if email.Subject != "Welcome to Acme & Co" {
+		t.Errorf("Subject = %q; want %q", email.Subject, "Welcome to Acme & Co")
+	}
+}
+
+func TestRender_FallsBackToDefaultLocale(t *testing.T) {
+	r := newTestRenderer(t)
+
+	email, err := r.Render("welcome", "fr", SampleData("welcome"))
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if !strings.HasPrefix(email.Subject, "Welcome") {
+		t.Errorf("Subject = %q; want the English fallback", email.Subject)
+	}
+}
+
+func TestRender_UnknownTemplate(t *testing.T) {
+	r := newTestRenderer(t)
+
+	if _, err := r.Render("does_not_exist", "en", nil); !errors.Is(err, ErrTemplateNotFound) {
+		t.Errorf("Render() error = %v; want ErrTemplateNotFound", err)
+	}
+}
@@ -0,0 +1,19 @@
+package tracing
+
+import "testing"
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		sql  string
+		want string
+	}{
+		{"-- name: GetUserByID :one\nSELECT id FROM users WHERE id = $1", "GetUserByID"},
+		{"select 1", "SELECT"},
+		{"", "query"},
+	}
+	for _, tt := range tests {
+		if got := queryName(tt.sql); got != tt.want {
+			t.Errorf("queryName(%q) = %q; want %q", tt.sql, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package version
+
+import "runtime"
+
+// Set at build time via:
+//
+//	-ldflags "-X BACKEND/internal/version.Version=v1.2.3 -X BACKEND/internal/version.GitCommit=abc123 -X BACKEND/internal/version.BuildTime=2024-01-01T00:00:00Z"
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildTime = "unknown"
+)
+
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+func Get() Info {
+	return Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
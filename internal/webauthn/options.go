@@ -0,0 +1,98 @@
+package webauthn
+
+// The types below follow the JSON serialization of WebAuthn options and
+// responses (PublicKeyCredential.parseCreationOptionsFromJSON and toJSON),
+// with binary fields base64url encoded.
+
+type RelyingPartyEntity struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type UserEntity struct {
+	ID          Bytes  `json:"id"`
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+}
+
+type CredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int64  `json:"alg"`
+}
+
+type CredentialDescriptor struct {
+	Type string `json:"type"`
+	ID   Bytes  `json:"id"`
+}
+
+type AuthenticatorSelection struct {
+	ResidentKey      string `json:"residentKey"`
+	UserVerification string `json:"userVerification"`
+}
+
+// CreationOptions is passed to navigator.credentials.create().
+type CreationOptions struct {
+	Challenge              string                 `json:"challenge"`
+	RP                     RelyingPartyEntity     `json:"rp"`
+	User                   UserEntity             `json:"user"`
+	PubKeyCredParams       []CredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                  `json:"timeout"`
+	ExcludeCredentials     []CredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection AuthenticatorSelection `json:"authenticatorSelection"`
+	Attestation            string                 `json:"attestation"`
+}
+
+// RequestOptions is passed to navigator.credentials.get(). An empty
+// AllowCredentials lets the user pick any passkey for the relying party.
+type RequestOptions struct {
+	Challenge        string                 `json:"challenge"`
+	Timeout          int64                  `json:"timeout"`
+	RPID             string                 `json:"rpId"`
+	AllowCredentials []CredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                 `json:"userVerification"`
+}
+
+// RegistrationResponse is the JSON form of the credential returned by
+// navigator.credentials.create(). Fields the relying party does not check
+// are listed so strict JSON decoding accepts what browsers send.
+type RegistrationResponse struct {
+	ID                      string                 `json:"id"`
+	RawID                   Bytes                  `json:"rawId"`
+	Type                    string                 `json:"type"`
+	AuthenticatorAttachment string                 `json:"authenticatorAttachment"`
+	ClientExtensionResults  map[string]interface{} `json:"clientExtensionResults"`
+	Response                struct {
+		ClientDataJSON     Bytes    `json:"clientDataJSON" validate:"required"`
+		AttestationObject  Bytes    `json:"attestationObject" validate:"required"`
+		AuthenticatorData  Bytes    `json:"authenticatorData"`
+		Transports         []string `json:"transports"`
+		PublicKey          Bytes    `json:"publicKey"`
+		PublicKeyAlgorithm int64    `json:"publicKeyAlgorithm"`
+	} `json:"response"`
+}
+
+// AssertionResponse is the JSON form of the credential returned by
+// navigator.credentials.get().
+type AssertionResponse struct {
+	ID                      string                 `json:"id"`
+	RawID                   Bytes                  `json:"rawId" validate:"required"`
+	Type                    string                 `json:"type"`
+	AuthenticatorAttachment string                 `json:"authenticatorAttachment"`
+	ClientExtensionResults  map[string]interface{} `json:"clientExtensionResults"`
+	Response                struct {
+		ClientDataJSON    Bytes `json:"clientDataJSON" validate:"required"`
+		AuthenticatorData Bytes `json:"authenticatorData" validate:"required"`
+		Signature         Bytes `json:"signature" validate:"required"`
+		UserHandle        Bytes `json:"userHandle"`
+	} `json:"response"`
+}
+
+// SupportedAlgorithms lists the credential algorithms the relying party
+// accepts, in order of preference.
+func SupportedAlgorithms() []CredentialParameter {
+	return []CredentialParameter{
+		{Type: "public-key", Alg: AlgES256},
+		{Type: "public-key", Alg: AlgEdDSA},
+		{Type: "public-key", Alg: AlgRS256},
+	}
+}
@@ -0,0 +1,330 @@
+// Package webauthn verifies WebAuthn registration and authentication
+// ceremonies for passkey login. It implements the parts of the Web
+// Authentication Level 2 spec a relying party needs: client data, the
+// authenticator data layout, COSE public keys (ES256, EdDSA and RS256) and
+// assertion signatures. Attestation statements are not verified; the
+// relying party asks for "none" attestation.
+package webauthn
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrInvalidResponse   = errors.New("webauthn response is malformed")
+	ErrChallengeMismatch = errors.New("webauthn challenge does not match")
+	ErrOriginMismatch    = errors.New("webauthn origin is not allowed")
+	ErrRPIDMismatch      = errors.New("webauthn relying party ID does not match")
+	ErrUserNotPresent    = errors.New("webauthn user presence flag is not set")
+	ErrUserNotVerified   = errors.New("webauthn user verification flag is not set")
+	ErrUnsupportedKey    = errors.New("webauthn public key type is not supported")
+	ErrInvalidSignature  = errors.New("webauthn signature is invalid")
+)
+
+// COSE algorithm identifiers accepted for credentials, in order of
+// preference.
+const (
+	AlgES256 int64 = -7
+	AlgEdDSA int64 = -8
+	AlgRS256 int64 = -257
+)
+
+const (
+	flagUserPresent  = 0x01
+	flagUserVerified = 0x04
+	flagAttestedData = 0x40
+)
+
+// Bytes is binary data that is base64url encoded in JSON, the encoding
+// browsers use for WebAuthn's JSON serialization.
+type Bytes []byte
+
+func (b Bytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+func (b *Bytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return fmt.Errorf("invalid base64url: %w", err)
+	}
+	*b = decoded
+	return nil
+}
+
+type Config struct {
+	RPID    string
+	RPName  string
+	Origins []string
+}
+
+// RelyingParty checks ceremony responses for one relying party ID.
+type RelyingParty struct {
+	cfg      Config
+	rpIDHash [32]byte
+}
+
+func NewRelyingParty(cfg Config) *RelyingParty {
+	return &RelyingParty{
+		cfg:      cfg,
+		rpIDHash: sha256.Sum256([]byte(cfg.RPID)),
+	}
+}
+
+func (rp *RelyingParty) ID() string   { return rp.cfg.RPID }
+func (rp *RelyingParty) Name() string { return rp.cfg.RPName }
+
+// ClientData is the part of clientDataJSON the relying party checks.
+type ClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+func ParseClientData(raw []byte) (ClientData, error) {
+	var cd ClientData
+	if err := json.Unmarshal(raw, &cd); err != nil {
+		return ClientData{}, ErrInvalidResponse
+	}
+	return cd, nil
+}
+
+// AuthenticatorData is the parsed authenticator data. CredentialID and
+// PublicKey are only set during registration.
+type AuthenticatorData struct {
+	RPIDHash     []byte
+	Flags        byte
+	SignCount    uint32
+	CredentialID []byte
+	PublicKey    []byte
+}
+
+func (d AuthenticatorData) UserPresent() bool  { return d.Flags&flagUserPresent != 0 }
+func (d AuthenticatorData) UserVerified() bool { return d.Flags&flagUserVerified != 0 }
+
+func ParseAuthenticatorData(b []byte) (AuthenticatorData, error) {
+	if len(b) < 37 {
+		return AuthenticatorData{}, ErrInvalidResponse
+	}
+	d := AuthenticatorData{
+		RPIDHash:  b[:32],
+		Flags:     b[32],
+		SignCount: binary.BigEndian.Uint32(b[33:37]),
+	}
+	if d.Flags&flagAttestedData == 0 {
+		return d, nil
+	}
+
+	// Attested credential data: 16-byte AAGUID, 2-byte length, credential
+	// ID, then the COSE key. Extensions may follow the key.
+	rest := b[37:]
+	if len(rest) < 18 {
+		return AuthenticatorData{}, ErrInvalidResponse
+	}
+	idLen := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if idLen == 0 || len(rest) < idLen {
+		return AuthenticatorData{}, ErrInvalidResponse
+	}
+	d.CredentialID = rest[:idLen]
+	rest = rest[idLen:]
+
+	_, after, err := decodeCBOR(rest)
+	if err != nil {
+		return AuthenticatorData{}, ErrInvalidResponse
+	}
+	d.PublicKey = rest[:len(rest)-len(after)]
+	return d, nil
+}
+
+// Credential is a public key credential created during registration.
+type Credential struct {
+	ID        []byte
+	PublicKey []byte
+	SignCount uint32
+}
+
+// VerifyRegistration checks a navigator.credentials.create() response
+// against the challenge the relying party issued.
+func (rp *RelyingParty) VerifyRegistration(challenge string, clientDataJSON, attestationObject []byte) (Credential, error) {
+	if err := rp.checkClientData(clientDataJSON, "webauthn.create", challenge); err != nil {
+		return Credential{}, err
+	}
+
+	obj, _, err := decodeCBOR(attestationObject)
+	if err != nil {
+		return Credential{}, ErrInvalidResponse
+	}
+	m, ok := obj.(map[interface{}]interface{})
+	if !ok {
+		return Credential{}, ErrInvalidResponse
+	}
+	rawAuthData, ok := m["authData"].([]byte)
+	if !ok {
+		return Credential{}, ErrInvalidResponse
+	}
+
+	authData, err := rp.checkAuthenticatorData(rawAuthData)
+	if err != nil {
+		return Credential{}, err
+	}
+	if authData.CredentialID == nil {
+		return Credential{}, ErrInvalidResponse
+	}
+	if _, _, err := ParsePublicKey(authData.PublicKey); err != nil {
+		return Credential{}, err
+	}
+
+	return Credential{
+		ID:        authData.CredentialID,
+		PublicKey: authData.PublicKey,
+		SignCount: authData.SignCount,
+	}, nil
+}
+
+// VerifyAssertion checks a navigator.credentials.get() response signed by
+// the credential with publicKey and returns the authenticator's signature
+// counter.
+func (rp *RelyingParty) VerifyAssertion(challenge string, publicKey, clientDataJSON, authenticatorData, signature []byte) (uint32, error) {
+	if err := rp.checkClientData(clientDataJSON, "webauthn.get", challenge); err != nil {
+		return 0, err
+	}
+	authData, err := rp.checkAuthenticatorData(authenticatorData)
+	if err != nil {
+		return 0, err
+	}
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	signed := append(append([]byte(nil), authenticatorData...), clientDataHash[:]...)
+	if err := VerifySignature(publicKey, signed, signature); err != nil {
+		return 0, err
+	}
+	return authData.SignCount, nil
+}
+
+func (rp *RelyingParty) checkClientData(raw []byte, wantType, challenge string) error {
+	cd, err := ParseClientData(raw)
+	if err != nil {
+		return err
+	}
+	if cd.Type != wantType {
+		return ErrInvalidResponse
+	}
+	if subtle.ConstantTimeCompare([]byte(cd.Challenge), []byte(challenge)) != 1 {
+		return ErrChallengeMismatch
+	}
+	for _, origin := range rp.cfg.Origins {
+		if cd.Origin == origin {
+			return nil
+		}
+	}
+	return ErrOriginMismatch
+}
+
+func (rp *RelyingParty) checkAuthenticatorData(raw []byte) (AuthenticatorData, error) {
+	authData, err := ParseAuthenticatorData(raw)
+	if err != nil {
+		return AuthenticatorData{}, err
+	}
+	if !bytes.Equal(authData.RPIDHash, rp.rpIDHash[:]) {
+		return AuthenticatorData{}, ErrRPIDMismatch
+	}
+	if !authData.UserPresent() {
+		return AuthenticatorData{}, ErrUserNotPresent
+	}
+	return authData, nil
+}
+
+// ParsePublicKey decodes a COSE_Key and returns the key and its algorithm.
+func ParsePublicKey(coseKey []byte) (interface{}, int64, error) {
+	obj, rest, err := decodeCBOR(coseKey)
+	if err != nil || len(rest) != 0 {
+		return nil, 0, ErrInvalidResponse
+	}
+	m, ok := obj.(map[interface{}]interface{})
+	if !ok {
+		return nil, 0, ErrInvalidResponse
+	}
+	kty, _ := m[int64(1)].(int64)
+	alg, _ := m[int64(3)].(int64)
+
+	switch {
+	case kty == 2 && alg == AlgES256:
+		crv, _ := m[int64(-1)].(int64)
+		x, _ := m[int64(-2)].([]byte)
+		y, _ := m[int64(-3)].([]byte)
+		if crv != 1 || len(x) != 32 || len(y) != 32 {
+			return nil, 0, ErrUnsupportedKey
+		}
+		point := append(append([]byte{4}, x...), y...)
+		if _, err := ecdh.P256().NewPublicKey(point); err != nil {
+			return nil, 0, ErrUnsupportedKey
+		}
+		return &ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}, alg, nil
+	case kty == 1 && alg == AlgEdDSA:
+		crv, _ := m[int64(-1)].(int64)
+		x, _ := m[int64(-2)].([]byte)
+		if crv != 6 || len(x) != ed25519.PublicKeySize {
+			return nil, 0, ErrUnsupportedKey
+		}
+		return ed25519.PublicKey(x), alg, nil
+	case kty == 3 && alg == AlgRS256:
+		n, _ := m[int64(-1)].([]byte)
+		e, _ := m[int64(-2)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, 0, ErrUnsupportedKey
+		}
+		return &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}, alg, nil
+	}
+	return nil, 0, ErrUnsupportedKey
+}
+
+// VerifySignature checks sig over data with a COSE_Key.
+func VerifySignature(coseKey, data, sig []byte) error {
+	key, _, err := ParsePublicKey(coseKey)
+	if err != nil {
+		return err
+	}
+
+	var ok bool
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(data)
+		ok = ecdsa.VerifyASN1(k, digest[:], sig)
+	case ed25519.PublicKey:
+		ok = ed25519.Verify(k, data, sig)
+	case *rsa.PublicKey:
+		digest := sha256.Sum256(data)
+		ok = rsa.VerifyPKCS1v15(k, crypto.SHA256, digest[:], sig) == nil
+	}
+	if !ok {
+		return ErrInvalidSignature
+	}
+	return nil
+}
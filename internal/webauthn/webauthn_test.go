@@ -0,0 +1,250 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"sort"
+	"testing"
+)
+
+// cborEncode encodes the CBOR subset decodeCBOR supports. Map keys are
+// written in sorted order so encodings are deterministic.
+func cborEncode(v interface{}) []byte {
+	head := func(major byte, n uint64) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n <= 0xff:
+			return []byte{major<<5 | 24, byte(n)}
+		case n <= 0xffff:
+			return binary.BigEndian.AppendUint16([]byte{major<<5 | 25}, uint16(n))
+		default:
+			return binary.BigEndian.AppendUint32([]byte{major<<5 | 26}, uint32(n))
+		}
+	}
+	switch x := v.(type) {
+	case int:
+		if x < 0 {
+			return head(1, uint64(-1-x))
+		}
+		return head(0, uint64(x))
+	case []byte:
+		return append(head(2, uint64(len(x))), x...)
+	case string:
+		return append(head(3, uint64(len(x))), x...)
+	case map[interface{}]interface{}:
+		keys := make([][]byte, 0, len(x))
+		encoded := make(map[string][]byte, len(x))
+		for k, val := range x {
+			ek := cborEncode(k)
+			keys = append(keys, ek)
+			encoded[string(ek)] = cborEncode(val)
+		}
+		sort.Slice(keys, func(i, j int) bool { return bytes.Compare(keys[i], keys[j]) < 0 })
+		out := head(5, uint64(len(x)))
+		for _, k := range keys {
+			out = append(append(out, k...), encoded[string(k)]...)
+		}
+		return out
+	}
+	panic("unsupported type")
+}
+
+type testAuthenticator struct {
+	credentialID []byte
+	coseKey      []byte
+	sign         func(data []byte) []byte
+	signCount    uint32
+}
+
+func newES256Authenticator(t *testing.T) *testAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, y := make([]byte, 32), make([]byte, 32)
+	key.X.FillBytes(x)
+	key.Y.FillBytes(y)
+	return &testAuthenticator{
+		credentialID: []byte("es256-credential"),
+		coseKey: cborEncode(map[interface{}]interface{}{
+			1: 2, 3: -7, -1: 1, -2: x, -3: y,
+		}),
+		sign: func(data []byte) []byte {
+			digest := sha256.Sum256(data)
+			sig, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			return sig
+		},
+	}
+}
+
+func newEd25519Authenticator(t *testing.T) *testAuthenticator {
+	t.Helper()
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testAuthenticator{
+		credentialID: []byte("ed25519-credential"),
+		coseKey: cborEncode(map[interface{}]interface{}{
+			1: 1, 3: -8, -1: 6, -2: []byte(pub),
+		}),
+		sign: func(data []byte) []byte { return ed25519.Sign(priv, data) },
+	}
+}
+
+func (a *testAuthenticator) authData(rpID string, flags byte, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte(rpID))
+	out := append([]byte(nil), rpIDHash[:]...)
+	if attested {
+		flags |= flagAttestedData
+	}
+	out = append(out, flags)
+	out = binary.BigEndian.AppendUint32(out, a.signCount)
+	if attested {
+		out = append(out, make([]byte, 16)...)
+		out = binary.BigEndian.AppendUint16(out, uint16(len(a.credentialID)))
+		out = append(out, a.credentialID...)
+		out = append(out, a.coseKey...)
+	}
+	return out
+}
+
+func clientData(t *testing.T, typ, challenge, origin string) []byte {
+	t.Helper()
+	b, err := json.Marshal(map[string]interface{}{
+		"type":        typ,
+		"challenge":   challenge,
+		"origin":      origin,
+		"crossOrigin": false,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func (a *testAuthenticator) register(t *testing.T, rpID, challenge, origin string) ([]byte, []byte) {
+	t.Helper()
+	att := cborEncode(map[interface{}]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[interface{}]interface{}{},
+		"authData": a.authData(rpID, flagUserPresent|flagUserVerified, true),
+	})
+	return clientData(t, "webauthn.create", challenge, origin), att
+}
+
+func (a *testAuthenticator) assert(t *testing.T, rpID, challenge, origin string) ([]byte, []byte, []byte) {
+	t.Helper()
+	a.signCount++
+	cd := clientData(t, "webauthn.get", challenge, origin)
+	authData := a.authData(rpID, flagUserPresent, false)
+	hash := sha256.Sum256(cd)
+	return cd, authData, a.sign(append(append([]byte(nil), authData...), hash[:]...))
+}
+
+func testRelyingParty() *RelyingParty {
+	return NewRelyingParty(Config{RPID: "example.com", RPName: "Example", Origins: []string{"https://example.com"}})
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	for name, newAuth := range map[string]func(*testing.T) *testAuthenticator{
+		"ES256":   newES256Authenticator,
+		"Ed25519": newEd25519Authenticator,
+	} {
+		t.Run(name, func(t *testing.T) {
+			rp := testRelyingParty()
+			auth := newAuth(t)
+
+			cd, att := auth.register(t, "example.com", "reg-challenge", "https://example.com")
+			cred, err := rp.VerifyRegistration("reg-challenge", cd, att)
+			if err != nil {
+				t.Fatalf("VerifyRegistration: %v", err)
+			}
+			if !bytes.Equal(cred.ID, auth.credentialID) || !bytes.Equal(cred.PublicKey, auth.coseKey) {
+				t.Fatalf("credential = %x / %x", cred.ID, cred.PublicKey)
+			}
+
+			cd, authData, sig := auth.assert(t, "example.com", "login-challenge", "https://example.com")
+			count, err := rp.VerifyAssertion("login-challenge", cred.PublicKey, cd, authData, sig)
+			if err != nil {
+				t.Fatalf("VerifyAssertion: %v", err)
+			}
+			if count != 1 {
+				t.Fatalf("sign count = %d, want 1", count)
+			}
+		})
+	}
+}
+
+func TestRegistrationRejects(t *testing.T) {
+	rp := testRelyingParty()
+	auth := newES256Authenticator(t)
+
+	tests := []struct {
+		name      string
+		rpID      string
+		challenge string
+		origin    string
+		want      error
+	}{
+		{"wrong challenge", "example.com", "other", "https://example.com", ErrChallengeMismatch},
+		{"wrong origin", "example.com", "reg-challenge", "https://evil.example", ErrOriginMismatch},
+		{"wrong rp id", "evil.example", "reg-challenge", "https://example.com", ErrRPIDMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cd, att := auth.register(t, tt.rpID, tt.challenge, tt.origin)
+			if _, err := rp.VerifyRegistration("reg-challenge", cd, att); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	cd, _ := auth.register(t, "example.com", "reg-challenge", "https://example.com")
+	if _, err := rp.VerifyRegistration("reg-challenge", cd, []byte{0xa1}); !errors.Is(err, ErrInvalidResponse) {
+		t.Fatalf("truncated attestation err = %v, want %v", err, ErrInvalidResponse)
+	}
+}
+
+func TestAssertionRejectsBadSignature(t *testing.T) {
+	rp := testRelyingParty()
+	auth := newES256Authenticator(t)
+	other := newES256Authenticator(t)
+
+	cd, authData, sig := other.assert(t, "example.com", "login-challenge", "https://example.com")
+	if _, err := rp.VerifyAssertion("login-challenge", auth.coseKey, cd, authData, sig); !errors.Is(err, ErrInvalidSignature) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidSignature)
+	}
+
+	cd, authData, sig = auth.assert(t, "example.com", "login-challenge", "https://example.com")
+	authData[32] &^= flagUserPresent
+	if _, err := rp.VerifyAssertion("login-challenge", auth.coseKey, cd, authData, sig); !errors.Is(err, ErrUserNotPresent) {
+		t.Fatalf("err = %v, want %v", err, ErrUserNotPresent)
+	}
+}
+
+func TestDecodeCBORRejectsMalformed(t *testing.T) {
+	for name, input := range map[string][]byte{
+		"empty":            {},
+		"short bytes":      {0x45, 0x01},
+		"indefinite array": {0x9f, 0x01, 0xff},
+		"huge map":         {0xbb, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		"float":            {0xf9, 0x00, 0x00},
+	} {
+		if _, _, err := decodeCBOR(input); err == nil {
+			t.Errorf("%s: decodeCBOR succeeded", name)
+		}
+	}
+}
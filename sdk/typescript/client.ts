@@ -0,0 +1,976 @@
+// Code generated by gensdk from the OpenAPI spec. DO NOT EDIT.
+
+export interface APIKeyRequest {
+  expires_in_days?: number;
+  name: string;
+  scopes: Array<string>;
+}
+
+export interface APIUsageConsumer {
+  api_key_id?: number;
+  key_name?: string;
+  name?: string;
+  requests?: number;
+  user_id?: string;
+}
+
+export interface APIUsageDay {
+  api_key_id?: number;
+  day?: string;
+  key_name?: string;
+  requests?: number;
+}
+
+export interface APIUsageResponse {
+  days?: Array<APIUsageDay>;
+  from?: string;
+  requests?: number;
+  to?: string;
+}
+
+export interface AgeResponse {
+  age?: number;
+  at?: string;
+  dob?: string;
+  id?: string;
+}
+
+export interface BillingResponse {
+  current_period_end?: string;
+  customer_id?: string;
+  organization?: OrganizationResponse;
+  plan?: string;
+  provider?: string;
+  seats?: number;
+  seats_used?: number;
+  status?: string;
+  subscription_id?: string;
+  updated_at?: string;
+}
+
+export interface ChangePasswordRequest {
+  current_password: string;
+  new_password: string;
+}
+
+export interface ClaimsResponse {
+  account_type?: string;
+  claims?: Record<string, unknown>;
+  expires_at?: string;
+  issued_at?: string;
+  role?: string;
+  scopes?: Array<string>;
+  user_id?: number;
+}
+
+export interface CurrentUserResponse {
+  account_type?: string;
+  age?: number;
+  avatar_url?: string;
+  dob?: string;
+  id?: string;
+  name?: string;
+  profile?: Profile;
+}
+
+export interface DatabaseHealth {
+  driver?: string;
+  error?: string;
+  latency_ms?: number;
+  pool?: PoolStats;
+  status?: string;
+}
+
+export interface DeviceApproveRequest {
+  user_code: string;
+}
+
+export interface DeviceTokenRequest {
+  device_code: string;
+  grant_type: string;
+}
+
+export interface DeviceTokenResponse {
+  access_token?: string;
+  expires_in?: number;
+  token_type?: string;
+}
+
+export interface EmailDeadLetterListResponse {
+  dead_letters?: Array<EmailDeadLetterResponse>;
+  total?: number;
+}
+
+export interface EmailDeadLetterResponse {
+  attempts?: number;
+  created_at?: string;
+  error?: string;
+  id?: number;
+  last_attempt_at?: string;
+  recipients?: Array<string>;
+  subject?: string;
+}
+
+export interface ErrorDetail {
+  code?: string;
+  details?: unknown;
+  message?: string;
+  request_id?: string;
+}
+
+export interface ErrorResponse {
+  error?: ErrorDetail;
+}
+
+export interface HealthResponse {
+  status?: string;
+}
+
+export interface IdentityListResponse {
+  identities?: Array<IdentityResponse>;
+  providers?: Array<string>;
+  total?: number;
+}
+
+export interface IdentityResponse {
+  email?: string;
+  linked_at?: string;
+  provider?: string;
+}
+
+export interface Info {
+  build_time?: string;
+  git_commit?: string;
+  go_version?: string;
+  version?: string;
+}
+
+export interface LoginRequest {
+  email: string;
+  password: string;
+}
+
+export interface LoginResponse {
+  message?: string;
+  refresh_token?: string;
+  user?: {
+    email?: string;
+    id?: string;
+    name?: string;
+    role?: string;
+  };
+}
+
+export interface MagicLinkRequest {
+  email: string;
+  locale?: string;
+}
+
+export interface NotificationListResponse {
+  notifications?: Array<NotificationResponse>;
+  total?: number;
+}
+
+export interface NotificationPreference {
+  channel: "email" | "in_app";
+  enabled: boolean;
+  event_type: string;
+}
+
+export interface NotificationPreferenceListResponse {
+  preferences?: Array<NotificationPreference>;
+  total?: number;
+}
+
+export interface NotificationResponse {
+  created_at?: string;
+  details?: Record<string, string>;
+  event_type?: string;
+  id?: number;
+  read_at?: string;
+  title?: string;
+}
+
+export interface NotificationRuleListResponse {
+  events?: Array<string>;
+  rules?: Array<NotificationRuleResponse>;
+  total?: number;
+}
+
+export interface NotificationRuleRequest {
+  audience?: "user" | "admins";
+  channel: "email" | "webhook" | "in_app";
+  enabled?: boolean;
+  event_type: string;
+  template?: string;
+  webhook_url?: string;
+}
+
+export interface NotificationRuleResponse {
+  audience?: string;
+  channel?: string;
+  created_at?: string;
+  enabled?: boolean;
+  event_type?: string;
+  id?: number;
+  template?: string;
+  updated_at?: string;
+  webhook_url?: string;
+}
+
+export interface OrganizationListResponse {
+  organizations?: Array<OrganizationResponse>;
+  total?: number;
+}
+
+export interface OrganizationMemberRequest {
+  org_id: number;
+}
+
+export interface OrganizationRequest {
+  name: string;
+}
+
+export interface OrganizationResponse {
+  created_at?: string;
+  id?: number;
+  name?: string;
+}
+
+export interface OrganizationUsageDay {
+  api_calls?: number;
+  day?: string;
+  seats?: number;
+  storage_bytes?: number;
+}
+
+export interface OrganizationUsageResponse {
+  api_calls?: number;
+  days?: Array<OrganizationUsageDay>;
+  from?: string;
+  organization?: OrganizationResponse;
+  seats?: number;
+  storage_bytes?: number;
+  to?: string;
+}
+
+export interface PaginatedUsersResponse {
+  data?: Array<UserWithAgeResponse>;
+  pagination?: PaginationMeta;
+}
+
+export interface PaginationMeta {
+  has_next?: boolean;
+  has_previous?: boolean;
+  limit?: number;
+  page?: number;
+  total?: number;
+  total_pages?: number;
+}
+
+export interface PasskeyListResponse {
+  passkeys?: Array<PasskeyResponse>;
+  total?: number;
+}
+
+export interface PasskeyRegisterRequest {
+  credential?: RegistrationResponse;
+  name?: string;
+}
+
+export interface PasskeyResponse {
+  created_at?: string;
+  id?: number;
+  last_used_at?: string;
+  name?: string;
+}
+
+export interface PasswordResetRequest {
+  password: string;
+  token: string;
+}
+
+export interface PoolStats {
+  idle_conns?: number;
+  in_use_conns?: number;
+  max_conns?: number;
+  total_conns?: number;
+  wait_count?: number;
+}
+
+export interface Profile {
+  avatar_url?: string;
+  bio?: string;
+  locale?: string;
+  phone?: string;
+  timezone?: string;
+}
+
+export interface ProfileUpdateRequest {
+  avatar_url?: string;
+  bio?: string;
+  locale?: string;
+  phone?: string;
+  timezone?: string;
+}
+
+export interface ReadinessResponse {
+  databases?: Record<string, DatabaseHealth>;
+  status?: string;
+  version?: Info;
+}
+
+export interface RefreshRequest {
+  refresh_token?: string;
+}
+
+export interface RegistrationResponse {
+  authenticatorAttachment?: string;
+  clientExtensionResults?: Record<string, unknown>;
+  id?: string;
+  rawId?: string;
+  response?: {
+    attestationObject: string;
+    authenticatorData?: string;
+    clientDataJSON: string;
+    publicKey?: string;
+    publicKeyAlgorithm?: number;
+    transports?: Array<string>;
+  };
+  type?: string;
+}
+
+export interface RoleUpdateRequest {
+  role: "user" | "org_admin" | "moderator" | "admin";
+}
+
+export interface SecurityLogVerification {
+  broken_at?: number;
+  events?: number;
+  head_hash?: string;
+  problem?: string;
+  valid?: boolean;
+}
+
+export interface ServiceAccountRequest {
+  name: string;
+  role?: "user" | "moderator" | "admin";
+}
+
+export interface SessionListResponse {
+  sessions?: Array<SessionResponse>;
+  total?: number;
+}
+
+export interface SessionResponse {
+  created_at?: string;
+  current?: boolean;
+  expires_at?: string;
+  id?: string;
+  ip_address?: string;
+  user_agent?: string;
+}
+
+export interface SignupRequest {
+  dob: string;
+  email: string;
+  name: string;
+  password: string;
+}
+
+export interface SignupResponse {
+  created_at?: string;
+  email?: string;
+  id?: string;
+  name?: string;
+  role?: string;
+}
+
+export interface TopAPIUsageResponse {
+  consumers?: Array<APIUsageConsumer>;
+  from?: string;
+  to?: string;
+}
+
+export interface UserLookupListResponse {
+  total?: number;
+  users?: Array<UserLookupResponse>;
+}
+
+export interface UserLookupResponse {
+  account_type?: string;
+  active?: boolean;
+  email?: string;
+  id?: string;
+  name?: string;
+  role?: string;
+}
+
+export interface UserRequest {
+  dob: string;
+  name: string;
+}
+
+export interface UserResponse {
+  avatar_url?: string;
+  dob?: string;
+  id?: string;
+  name?: string;
+}
+
+export interface UserWithAgeResponse {
+  account_type?: string;
+  age?: number;
+  avatar_url?: string;
+  dob?: string;
+  id?: string;
+  name?: string;
+}
+
+/** Thrown for responses with an error status. */
+export class APIError extends Error {
+  constructor(
+    public readonly status: number,
+    public readonly response?: ErrorResponse,
+  ) {
+    super(response ? `${status} ${response.error.code}: ${response.error.message}` : `${status}`);
+  }
+}
+
+/**
+ * Calls the API at baseURL, including the mount prefix. Requests send
+ * cookies, so the session cookie set by authLogin signs in the calls after
+ * it; set token to send a bearer token instead.
+ */
+export class Client {
+  constructor(
+    private readonly baseURL: string,
+    public token?: string,
+  ) {}
+
+  private async request<T>(method: string, path: string, query?: Record<string, string>, body?: unknown): Promise<T> {
+    let url = this.baseURL.replace(/\/$/, "") + path;
+    if (query && Object.keys(query).length > 0) {
+      url += "?" + new URLSearchParams(query).toString();
+    }
+    const headers: Record<string, string> = { Accept: "application/json" };
+    if (body !== undefined) {
+      headers["Content-Type"] = "application/json";
+    }
+    if (this.token) {
+      headers["Authorization"] = `Bearer ${this.token}`;
+    }
+    const resp = await fetch(url, {
+      method,
+      headers,
+      credentials: "include",
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+    const text = await resp.text();
+    if (!resp.ok) {
+      let payload: ErrorResponse | undefined;
+      try {
+        payload = JSON.parse(text);
+      } catch {
+        payload = undefined;
+      }
+      throw new APIError(resp.status, payload);
+    }
+    return (text ? JSON.parse(text) : undefined) as T;
+  }
+
+  /** POST /admin/users/{id}/activate */
+  adminActivate(id: string): Promise<unknown> {
+    return this.request("POST", `/admin/users/${encodeURIComponent(id)}/activate`, undefined);
+  }
+
+  /** GET /admin/users/birthdays */
+  adminBirthdays(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/users/birthdays", query);
+  }
+
+  /** POST /admin/users/{id}/deactivate */
+  adminDeactivate(id: string): Promise<unknown> {
+    return this.request("POST", `/admin/users/${encodeURIComponent(id)}/deactivate`, undefined);
+  }
+
+  /** DELETE /admin/users/{id} */
+  adminDeleteUser(id: string): Promise<void> {
+    return this.request("DELETE", `/admin/users/${encodeURIComponent(id)}`, undefined);
+  }
+
+  /** POST /admin/users/{id}/force-logout */
+  adminForceLogout(id: string): Promise<unknown> {
+    return this.request("POST", `/admin/users/${encodeURIComponent(id)}/force-logout`, undefined);
+  }
+
+  /** POST /admin/users/{id}/force-password-reset */
+  adminForcePasswordReset(id: string): Promise<unknown> {
+    return this.request("POST", `/admin/users/${encodeURIComponent(id)}/force-password-reset`, undefined);
+  }
+
+  /** GET /admin/users */
+  adminGetAllUsers(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/users", query);
+  }
+
+  /** GET /admin/stats */
+  adminGetStats(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/stats", query);
+  }
+
+  /** GET /admin/lockouts */
+  adminLockouts(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/lockouts", query);
+  }
+
+  /** GET /admin/users/lookup */
+  adminLookupUsers(query?: Record<string, string>): Promise<UserLookupListResponse> {
+    return this.request("GET", "/admin/users/lookup", query);
+  }
+
+  /** POST /admin/stats/refresh */
+  adminRefreshStats(): Promise<unknown> {
+    return this.request("POST", "/admin/stats/refresh", undefined);
+  }
+
+  /** POST /admin/users/{id}/restore */
+  adminRestoreUser(id: string): Promise<unknown> {
+    return this.request("POST", `/admin/users/${encodeURIComponent(id)}/restore`, undefined);
+  }
+
+  /** POST /admin/users/{id}/unlock */
+  adminUnlock(id: string): Promise<unknown> {
+    return this.request("POST", `/admin/users/${encodeURIComponent(id)}/unlock`, undefined);
+  }
+
+  /** PUT /admin/users/{id}/role */
+  adminUpdateRole(id: string, body: RoleUpdateRequest): Promise<unknown> {
+    return this.request("PUT", `/admin/users/${encodeURIComponent(id)}/role`, undefined, body);
+  }
+
+  /** PUT /users/me/password */
+  authChangePassword(body: ChangePasswordRequest): Promise<unknown> {
+    return this.request("PUT", "/users/me/password", undefined, body);
+  }
+
+  /** GET /users/me/sessions */
+  authListSessions(query?: Record<string, string>): Promise<SessionListResponse> {
+    return this.request("GET", "/users/me/sessions", query);
+  }
+
+  /** POST /auth/login */
+  authLogin(body: LoginRequest): Promise<LoginResponse> {
+    return this.request("POST", "/auth/login", undefined, body);
+  }
+
+  /** POST /auth/logout */
+  authLogout(body: RefreshRequest): Promise<unknown> {
+    return this.request("POST", "/auth/logout", undefined, body);
+  }
+
+  /** POST /auth/refresh */
+  authRefresh(body: RefreshRequest): Promise<LoginResponse> {
+    return this.request("POST", "/auth/refresh", undefined, body);
+  }
+
+  /** POST /auth/magic-link */
+  authRequestMagicLink(body: MagicLinkRequest): Promise<unknown> {
+    return this.request("POST", "/auth/magic-link", undefined, body);
+  }
+
+  /** POST /auth/password-reset */
+  authResetPassword(body: PasswordResetRequest): Promise<unknown> {
+    return this.request("POST", "/auth/password-reset", undefined, body);
+  }
+
+  /** DELETE /users/me/sessions/{id} */
+  authRevokeSession(id: string): Promise<unknown> {
+    return this.request("DELETE", `/users/me/sessions/${encodeURIComponent(id)}`, undefined);
+  }
+
+  /** POST /auth/signup */
+  authSignup(body: SignupRequest): Promise<SignupResponse> {
+    return this.request("POST", "/auth/signup", undefined, body);
+  }
+
+  /** GET /auth/magic-link/verify */
+  authVerifyMagicLink(query?: Record<string, string>): Promise<LoginResponse> {
+    return this.request("GET", "/auth/magic-link/verify", query);
+  }
+
+  /** GET /orgs/{id}/billing */
+  billingStatus(id: string, query?: Record<string, string>): Promise<BillingResponse> {
+    return this.request("GET", `/orgs/${encodeURIComponent(id)}/billing`, query);
+  }
+
+  /** GET /admin/config */
+  configExport(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/config", query);
+  }
+
+  /** POST /admin/config/import */
+  configImport(): Promise<unknown> {
+    return this.request("POST", "/admin/config/import", undefined);
+  }
+
+  /** POST /auth/device/approve */
+  deviceApprove(body: DeviceApproveRequest): Promise<void> {
+    return this.request("POST", "/auth/device/approve", undefined, body);
+  }
+
+  /** POST /auth/device/code */
+  deviceCode(): Promise<unknown> {
+    return this.request("POST", "/auth/device/code", undefined);
+  }
+
+  /** POST /auth/device/deny */
+  deviceDeny(body: DeviceApproveRequest): Promise<void> {
+    return this.request("POST", "/auth/device/deny", undefined, body);
+  }
+
+  /** POST /auth/device/token */
+  deviceToken(body: DeviceTokenRequest): Promise<DeviceTokenResponse> {
+    return this.request("POST", "/auth/device/token", undefined, body);
+  }
+
+  /** GET /admin/emails/dead-letters */
+  emailDeliveryListDeadLetters(query?: Record<string, string>): Promise<EmailDeadLetterListResponse> {
+    return this.request("GET", "/admin/emails/dead-letters", query);
+  }
+
+  /** POST /admin/emails/dead-letters/{id}/retry */
+  emailDeliveryRetryDeadLetter(id: string): Promise<void> {
+    return this.request("POST", `/admin/emails/dead-letters/${encodeURIComponent(id)}/retry`, undefined);
+  }
+
+  /** GET /admin/email-templates */
+  emailTemplateList(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/email-templates", query);
+  }
+
+  /** GET /admin/email-templates/{name}/preview */
+  emailTemplatePreview(name: string, query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", `/admin/email-templates/${encodeURIComponent(name)}/preview`, query);
+  }
+
+  /** POST /admin/reports/{name}/exports */
+  exportCreate(name: string): Promise<unknown> {
+    return this.request("POST", `/admin/reports/${encodeURIComponent(name)}/exports`, undefined);
+  }
+
+  /** GET /admin/exports/{id} */
+  exportGet(id: string, query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", `/admin/exports/${encodeURIComponent(id)}`, query);
+  }
+
+  /** GET /healthz */
+  healthLive(query?: Record<string, string>): Promise<HealthResponse> {
+    return this.request("GET", "/healthz", query);
+  }
+
+  /** GET /readyz */
+  healthReady(query?: Record<string, string>): Promise<ReadinessResponse> {
+    return this.request("GET", "/readyz", query);
+  }
+
+  /** POST /users/me/identities/{provider}/link */
+  identityLink(provider: string): Promise<unknown> {
+    return this.request("POST", `/users/me/identities/${encodeURIComponent(provider)}/link`, undefined);
+  }
+
+  /** GET /users/me/identities */
+  identityList(query?: Record<string, string>): Promise<IdentityListResponse> {
+    return this.request("GET", "/users/me/identities", query);
+  }
+
+  /** DELETE /users/me/identities/{provider}/unlink */
+  identityUnlink(provider: string): Promise<void> {
+    return this.request("DELETE", `/users/me/identities/${encodeURIComponent(provider)}/unlink`, undefined);
+  }
+
+  /** GET /admin/users/{id}/logins */
+  loginHistoryForUser(id: string, query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", `/admin/users/${encodeURIComponent(id)}/logins`, query);
+  }
+
+  /** GET /users/me/logins */
+  loginHistoryMine(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/users/me/logins", query);
+  }
+
+  /** GET /admin/users/{id}/usage */
+  meteringForUser(id: string, query?: Record<string, string>): Promise<APIUsageResponse> {
+    return this.request("GET", `/admin/users/${encodeURIComponent(id)}/usage`, query);
+  }
+
+  /** GET /users/me/usage */
+  meteringMine(query?: Record<string, string>): Promise<APIUsageResponse> {
+    return this.request("GET", "/users/me/usage", query);
+  }
+
+  /** GET /admin/usage */
+  meteringTop(query?: Record<string, string>): Promise<TopAPIUsageResponse> {
+    return this.request("GET", "/admin/usage", query);
+  }
+
+  /** POST /admin/name-reviews/{id}/approve */
+  moderationApprove(id: string): Promise<unknown> {
+    return this.request("POST", `/admin/name-reviews/${encodeURIComponent(id)}/approve`, undefined);
+  }
+
+  /** GET /admin/name-reviews */
+  moderationListReviews(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/name-reviews", query);
+  }
+
+  /** POST /admin/name-reviews/{id}/reject */
+  moderationReject(id: string): Promise<unknown> {
+    return this.request("POST", `/admin/name-reviews/${encodeURIComponent(id)}/reject`, undefined);
+  }
+
+  /** POST /admin/notification-rules */
+  notificationCreateRule(body: NotificationRuleRequest): Promise<NotificationRuleResponse> {
+    return this.request("POST", "/admin/notification-rules", undefined, body);
+  }
+
+  /** DELETE /admin/notification-rules/{id} */
+  notificationDeleteRule(id: string): Promise<void> {
+    return this.request("DELETE", `/admin/notification-rules/${encodeURIComponent(id)}`, undefined);
+  }
+
+  /** GET /admin/notification-rules */
+  notificationListRules(query?: Record<string, string>): Promise<NotificationRuleListResponse> {
+    return this.request("GET", "/admin/notification-rules", query);
+  }
+
+  /** POST /users/me/notifications/{id}/read */
+  notificationMarkRead(id: string): Promise<void> {
+    return this.request("POST", `/users/me/notifications/${encodeURIComponent(id)}/read`, undefined);
+  }
+
+  /** GET /users/me/notifications */
+  notificationMine(query?: Record<string, string>): Promise<NotificationListResponse> {
+    return this.request("GET", "/users/me/notifications", query);
+  }
+
+  /** GET /users/me/notification-preferences */
+  notificationPreferences(query?: Record<string, string>): Promise<NotificationPreferenceListResponse> {
+    return this.request("GET", "/users/me/notification-preferences", query);
+  }
+
+  /** PUT /users/me/notification-preferences */
+  notificationSetPreference(body: NotificationPreference): Promise<NotificationPreference> {
+    return this.request("PUT", "/users/me/notification-preferences", undefined, body);
+  }
+
+  /** PUT /admin/notification-rules/{id} */
+  notificationUpdateRule(id: string, body: NotificationRuleRequest): Promise<NotificationRuleResponse> {
+    return this.request("PUT", `/admin/notification-rules/${encodeURIComponent(id)}`, undefined, body);
+  }
+
+  /** POST /admin/orgs */
+  organizationCreate(body: OrganizationRequest): Promise<OrganizationResponse> {
+    return this.request("POST", "/admin/orgs", undefined, body);
+  }
+
+  /** GET /admin/orgs */
+  organizationList(query?: Record<string, string>): Promise<OrganizationListResponse> {
+    return this.request("GET", "/admin/orgs", query);
+  }
+
+  /** DELETE /admin/users/{id}/org */
+  organizationRemoveMember(id: string): Promise<void> {
+    return this.request("DELETE", `/admin/users/${encodeURIComponent(id)}/org`, undefined);
+  }
+
+  /** PUT /admin/users/{id}/org */
+  organizationSetMember(id: string, body: OrganizationMemberRequest): Promise<void> {
+    return this.request("PUT", `/admin/users/${encodeURIComponent(id)}/org`, undefined, body);
+  }
+
+  /** GET /orgs/{id}/usage */
+  organizationUsage(id: string, query?: Record<string, string>): Promise<OrganizationUsageResponse> {
+    return this.request("GET", `/orgs/${encodeURIComponent(id)}/usage`, query);
+  }
+
+  /** GET /users/me/referrals */
+  referralMine(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/users/me/referrals", query);
+  }
+
+  /** GET /admin/reports */
+  reportList(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/reports", query);
+  }
+
+  /** GET /admin/reports/{name} */
+  reportRun(name: string, query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", `/admin/reports/${encodeURIComponent(name)}`, query);
+  }
+
+  /** GET /admin/retention/{category} */
+  retentionGet(category: string, query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", `/admin/retention/${encodeURIComponent(category)}`, query);
+  }
+
+  /** GET /admin/retention */
+  retentionList(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/retention", query);
+  }
+
+  /** GET /admin/security/alerts */
+  securityAlerts(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/security/alerts", query);
+  }
+
+  /** GET /admin/security/events */
+  securityEvents(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/security/events", query);
+  }
+
+  /** GET /admin/security/events/verify */
+  securityVerify(query?: Record<string, string>): Promise<SecurityLogVerification> {
+    return this.request("GET", "/admin/security/events/verify", query);
+  }
+
+  /** POST /admin/service-accounts */
+  serviceAccountCreate(body: ServiceAccountRequest): Promise<unknown> {
+    return this.request("POST", "/admin/service-accounts", undefined, body);
+  }
+
+  /** POST /admin/service-accounts/{id}/keys */
+  serviceAccountCreateKey(id: string, body: APIKeyRequest): Promise<unknown> {
+    return this.request("POST", `/admin/service-accounts/${encodeURIComponent(id)}/keys`, undefined, body);
+  }
+
+  /** GET /admin/service-accounts */
+  serviceAccountList(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/service-accounts", query);
+  }
+
+  /** GET /admin/service-accounts/{id}/keys */
+  serviceAccountListKeys(id: string, query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", `/admin/service-accounts/${encodeURIComponent(id)}/keys`, query);
+  }
+
+  /** DELETE /admin/service-accounts/{id}/keys/{keyId} */
+  serviceAccountRevokeKey(id: string, keyID: string): Promise<void> {
+    return this.request("DELETE", `/admin/service-accounts/${encodeURIComponent(id)}/keys/${encodeURIComponent(keyID)}`, undefined);
+  }
+
+  /** GET /admin/connections */
+  systemConnections(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/connections", query);
+  }
+
+  /** GET /admin/deprecations */
+  systemDeprecations(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/deprecations", query);
+  }
+
+  /** GET /admin/load-shedding */
+  systemLoadShedding(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/load-shedding", query);
+  }
+
+  /** GET /users/me/rate-limit */
+  systemMyRateLimit(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/users/me/rate-limit", query);
+  }
+
+  /** GET /admin/outcomes */
+  systemOutcomes(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/outcomes", query);
+  }
+
+  /** GET /admin/repository-stats */
+  systemRepositoryStats(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/admin/repository-stats", query);
+  }
+
+  /** GET /version */
+  systemVersion(query?: Record<string, string>): Promise<unknown> {
+    return this.request("GET", "/version", query);
+  }
+
+  /** POST /users */
+  userCreate(body: UserRequest): Promise<UserResponse> {
+    return this.request("POST", "/users", undefined, body);
+  }
+
+  /** DELETE /users/{id} */
+  userDelete(id: string): Promise<unknown> {
+    return this.request("DELETE", `/users/${encodeURIComponent(id)}`, undefined);
+  }
+
+  /** DELETE /users/me/avatar */
+  userDeleteAvatar(): Promise<void> {
+    return this.request("DELETE", "/users/me/avatar", undefined);
+  }
+
+  /** GET /users/{id}/age */
+  userGetAge(id: string, query?: Record<string, string>): Promise<AgeResponse> {
+    return this.request("GET", `/users/${encodeURIComponent(id)}/age`, query);
+  }
+
+  /** GET /users/{id} */
+  userGetByID(id: string, query?: Record<string, string>): Promise<UserWithAgeResponse> {
+    return this.request("GET", `/users/${encodeURIComponent(id)}`, query);
+  }
+
+  /** GET /users/me/claims */
+  userGetCurrentClaims(query?: Record<string, string>): Promise<ClaimsResponse> {
+    return this.request("GET", "/users/me/claims", query);
+  }
+
+  /** GET /users/me */
+  userGetCurrentUser(query?: Record<string, string>): Promise<CurrentUserResponse> {
+    return this.request("GET", "/users/me", query);
+  }
+
+  /** GET /users */
+  userList(query?: Record<string, string>): Promise<PaginatedUsersResponse> {
+    return this.request("GET", "/users", query);
+  }
+
+  /** PUT /users/{id} */
+  userUpdate(id: string, body: UserRequest): Promise<UserResponse> {
+    return this.request("PUT", `/users/${encodeURIComponent(id)}`, undefined, body);
+  }
+
+  /** PATCH /users/me */
+  userUpdateProfile(body: ProfileUpdateRequest): Promise<CurrentUserResponse> {
+    return this.request("PATCH", "/users/me", undefined, body);
+  }
+
+  /** DELETE /users/me/passkeys/{id} */
+  webAuthnDelete(id: string): Promise<void> {
+    return this.request("DELETE", `/users/me/passkeys/${encodeURIComponent(id)}`, undefined);
+  }
+
+  /** GET /users/me/passkeys */
+  webAuthnList(query?: Record<string, string>): Promise<PasskeyListResponse> {
+    return this.request("GET", "/users/me/passkeys", query);
+  }
+
+  /** POST /auth/webauthn/login/begin */
+  webAuthnLoginBegin(): Promise<unknown> {
+    return this.request("POST", "/auth/webauthn/login/begin", undefined);
+  }
+
+  /** POST /auth/webauthn/login/finish */
+  webAuthnLoginFinish(): Promise<LoginResponse> {
+    return this.request("POST", "/auth/webauthn/login/finish", undefined);
+  }
+
+  /** POST /auth/webauthn/register/begin */
+  webAuthnRegisterBegin(): Promise<unknown> {
+    return this.request("POST", "/auth/webauthn/register/begin", undefined);
+  }
+
+  /** POST /auth/webauthn/register/finish */
+  webAuthnRegisterFinish(body: PasskeyRegisterRequest): Promise<PasskeyResponse> {
+    return this.request("POST", "/auth/webauthn/register/finish", undefined, body);
+  }
+}
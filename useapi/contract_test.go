@@ -0,0 +1,168 @@
+package useapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+
+	"BACKEND/config"
+	"BACKEND/internal/routes"
+	"BACKEND/internal/service"
+)
+
+type recordedResponse struct {
+	method, route string
+	status        int
+	body          []byte
+}
+
+// TestContract drives the API in memory through its main flows and checks
+// every response against the OpenAPI spec, so handlers can't drift from
+// what the spec promises.
+func TestContract(t *testing.T) {
+	cfg := testConfig()
+	cfg.Storage = config.StorageMemory
+	cfg.JWTExpiry = time.Hour
+	cfg.RefreshTokenTTL = 24 * time.Hour
+	cfg.RoleHierarchy = []string{"admin", "moderator", "org_admin", "user"}
+
+	app := fiber.New()
+	var recorded []recordedResponse
+	app.Use(func(c *fiber.Ctx) error {
+		err := c.Next()
+		if err == nil {
+			recorded = append(recorded, recordedResponse{
+				method: strings.Clone(c.Method()),
+				route:  c.Route().Path,
+				status: c.Response().StatusCode(),
+				body:   append([]byte(nil), c.Response().Body()...),
+			})
+		}
+		return err
+	})
+	api, err := Mount(app, Options{Config: cfg, Logger: zap.NewNop()})
+	if err != nil {
+		t.Fatalf("Mount() error = %v", err)
+	}
+	defer api.Close()
+
+	auth := service.NewAuthService(nil)
+	auth.SetJWTConfig(cfg.JWTSecret, time.Hour)
+	admin, err := auth.GenerateJWT(context.Background(), 99, "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	do := func(tok, method, path, reqBody string) (*http.Response, []byte) {
+		req := httptest.NewRequest(method, path, strings.NewReader(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		if tok != "" {
+			req.Header.Set("Authorization", "Bearer "+tok)
+		}
+		resp, err := app.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, body
+	}
+
+	do("", "GET", "/healthz", "")
+	do("", "POST", "/auth/signup", `{"name":"Jane Doe","email":"jane@example.com","password":"SecurePass123!","dob":"1990-01-01"}`)
+	do("", "POST", "/auth/signup", `{"name":"John Roe","email":"john@example.com","password":"SecurePass123!","dob":"1985-06-15"}`)
+	do("", "POST", "/auth/signup", `{"name":"","email":"not-an-email"}`)
+	var login struct {
+		RefreshToken string `json:"refresh_token"`
+		User         struct {
+			ID string `json:"id"`
+		} `json:"user"`
+	}
+	resp, body := do("", "POST", "/auth/login", `{"email":"jane@example.com","password":"SecurePass123!"}`)
+	if err := json.Unmarshal(body, &login); err != nil {
+		t.Fatalf("login = %d: %v", resp.StatusCode, err)
+	}
+	var user string
+	for _, cookie := range resp.Cookies() {
+		if cookie.Name == "token" {
+			user = cookie.Value
+		}
+	}
+	do("", "POST", "/auth/login", `{"email":"jane@example.com","password":"wrong"}`)
+
+	do(user, "GET", "/users/me", "")
+	do(user, "PATCH", "/users/me", `{"bio":"Hello","locale":"en"}`)
+	do(user, "GET", "/users/me/claims", "")
+	do(user, "GET", "/users/me/sessions", "")
+	do(user, "GET", "/users/me/logins", "")
+	do(user, "GET", "/users/me/usage", "")
+	do(user, "GET", "/users/me/passkeys", "")
+	do(user, "GET", "/users/me/identities", "")
+	do(user, "GET", "/users/me/notifications", "")
+	do(user, "GET", "/users/me/notification-preferences", "")
+	do(user, "GET", "/users/"+login.User.ID, "")
+	do(user, "GET", "/users/"+login.User.ID+"/age", "")
+	do(user, "GET", "/users/00000000-0000-0000-0000-000000000000", "")
+	if login.RefreshToken != "" {
+		do("", "POST", "/auth/refresh", `{"refresh_token":"`+login.RefreshToken+`"}`)
+	}
+
+	do(admin, "GET", "/users?page=1", "")
+	do(admin, "POST", "/users", `{"name":"Ann Poe","email":"ann@example.com","dob":"1970-03-02"}`)
+	do(admin, "PUT", "/users/"+login.User.ID, `{"name":"Jane Q Doe","email":"jane@example.com","dob":"1990-01-01"}`)
+	do(admin, "GET", "/admin/users?page=1", "")
+	do(admin, "GET", "/admin/users/lookup?email=jane", "")
+	do(admin, "GET", "/admin/stats", "")
+	do(admin, "GET", "/admin/usage", "")
+	do(admin, "GET", "/admin/users/"+login.User.ID+"/usage", "")
+	do(admin, "POST", "/admin/orgs", `{"name":"Acme"}`)
+	do(admin, "GET", "/admin/orgs", "")
+	do(admin, "GET", "/admin/notification-rules", "")
+	do(admin, "GET", "/admin/emails/dead-letters", "")
+	do(admin, "GET", "/admin/security/events/verify", "")
+	do(admin, "GET", "/admin/security/events", "")
+	do(admin, "GET", "/admin/security/alerts", "")
+	do(admin, "GET", "/admin/users/"+login.User.ID+"/logins", "")
+	do(admin, "GET", "/admin/users/birthdays", "")
+	do(admin, "GET", "/admin/lockouts", "")
+	do(admin, "GET", "/admin/reports", "")
+	do(admin, "GET", "/admin/retention", "")
+	do(admin, "GET", "/admin/email-templates", "")
+	do(admin, "GET", "/admin/service-accounts", "")
+	do(admin, "POST", "/admin/service-accounts", `{"name":"ci-bot"}`)
+	do(admin, "POST", "/admin/notification-rules", `{"event_type":"role_changed","channel":"in_app","audience":"user"}`)
+	do(admin, "PUT", "/admin/notification-rules/1", `{"event_type":"role_changed","channel":"in_app","audience":"admins"}`)
+	do(admin, "DELETE", "/admin/notification-rules/1", "")
+	do(admin, "PUT", "/admin/users/"+login.User.ID+"/role", `{"role":"moderator"}`)
+	do(admin, "POST", "/admin/users/"+login.User.ID+"/deactivate", "")
+	do(admin, "POST", "/admin/users/"+login.User.ID+"/activate", "")
+	do(admin, "POST", "/admin/users/"+login.User.ID+"/force-logout", "")
+	do(admin, "POST", "/admin/users/"+login.User.ID+"/force-password-reset", "")
+	do(admin, "PUT", "/admin/users/"+login.User.ID+"/org", `{"org_id":1}`)
+	do(admin, "GET", "/orgs/1/usage", "")
+	do(admin, "GET", "/orgs/1/billing", "")
+	do(admin, "DELETE", "/admin/users/"+login.User.ID, "")
+	do(admin, "POST", "/admin/users/"+login.User.ID+"/restore", "")
+	do("", "POST", "/auth/magic-link", `{"email":"john@example.com"}`)
+	do("", "POST", "/auth/device/code", "")
+	do("", "GET", "/users/me", "")
+
+	doc := routes.BuildOpenAPI(app, "", cfg.Branding.ProductName, cfg.Branding.BaseURL)
+	if len(recorded) == 0 {
+		t.Fatal("no responses were recorded")
+	}
+	for _, r := range recorded {
+		for _, problem := range doc.CheckResponse("", r.method, r.route, r.status, r.body) {
+			t.Error(problem)
+		}
+	}
+}
@@ -0,0 +1,955 @@
+// Package useapi embeds the user management API into an existing Fiber app,
+// for services that would rather not run it as a separate server.
+package useapi
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"BACKEND/config"
+	"BACKEND/db/sqlc/generated"
+	"BACKEND/db/sqlc/mysqlgen"
+	"BACKEND/hooks"
+	"BACKEND/internal/backup"
+	"BACKEND/internal/billing"
+	"BACKEND/internal/geoip"
+	"BACKEND/internal/handler"
+	"BACKEND/internal/jobs"
+	"BACKEND/internal/logger"
+	"BACKEND/internal/mailer"
+	"BACKEND/internal/middleware"
+	"BACKEND/internal/models"
+	"BACKEND/internal/policy"
+	"BACKEND/internal/redis"
+	"BACKEND/internal/repository"
+	"BACKEND/internal/routes"
+	"BACKEND/internal/service"
+	"BACKEND/internal/sso"
+	"BACKEND/internal/storage"
+	"BACKEND/internal/templates"
+	"BACKEND/internal/webauthn"
+)
+
+var (
+	ErrNoDatabase      = errors.New("useapi: exactly one of Options.DB or Options.MySQL must be set, or neither with STORAGE=memory")
+	ErrChaosOutsideDev = errors.New("useapi: CHAOS_* fault injection is only allowed with APP_ENV=dev")
+	ErrDefaultSecret   = errors.New("useapi: JWT_SECRET must be set with APP_ENV=prod")
+)
+
+type Options struct {
+	// Config defaults to config.Load(), i.e. the same environment variables
+	// the standalone server reads.
+	Config *config.Config
+
+	// DB is used when the host app runs Postgres; MySQL when it runs
+	// MySQL. The caller owns the connection and closes it.
+	DB    *pgxpool.Pool
+	MySQL *sql.DB
+
+	// ReadDB, when set alongside DB, serves reads, e.g. from a replica in
+	// the local region. See config.Replication for when the primary is
+	// used instead.
+	ReadDB *pgxpool.Pool
+
+	// Logger defaults to one built from Config.
+	Logger *zap.Logger
+
+	// Prefix mounts every route under a path, e.g. "/identity".
+	Prefix string
+
+	// Hooks carries Go hooks registered by the host app. HTTP hooks from
+	// Config are added to it.
+	Hooks *hooks.Registry
+
+	// ClaimsEnricher adds claims to every issued JWT, after the static
+	// JWT_EXTRA_CLAIMS from Config.
+	ClaimsEnricher ClaimsEnricher
+
+	// GeoIP locates login IPs, e.g. with a MaxMind reader. It defaults to
+	// the DB-IP CSV file in Config, if any.
+	GeoIP GeoIPLocator
+
+	// SecurityAlerter receives brute-force alerts in addition to the
+	// webhook and admin emails from Config.
+	SecurityAlerter SecurityAlerter
+
+	// NameFilter checks user names at signup and update, e.g. with a hosted
+	// moderation API. It defaults to the word lists in Config, if any.
+	NameFilter NameFilter
+}
+
+type (
+	ClaimsEnricher     = service.ClaimsEnricher
+	ClaimsEnricherFunc = service.ClaimsEnricherFunc
+	SecurityAlert      = service.SecurityAlert
+	SecurityAlerter    = service.SecurityAlerter
+	GeoIPLocator       = geoip.Locator
+	GeoIPLocation      = geoip.Location
+	NameFilter         = service.NameFilter
+	NameVerdict        = service.NameVerdict
+)
+
+// Instance is a mounted API. Close stops its background jobs.
+type Instance struct {
+	stopJobs context.CancelFunc
+	redis    *redis.Client
+}
+
+func (i *Instance) Close() {
+	i.stopJobs()
+	if i.redis != nil {
+		i.redis.Close()
+	}
+}
+
+func Mount(app fiber.Router, opts Options) (*Instance, error) {
+	cfg := opts.Config
+	if cfg == nil {
+		cfg = config.Load()
+	}
+
+	appLogger := opts.Logger
+	if appLogger == nil {
+		appLogger = logger.New(cfg.LogLevel, cfg.LogFormat, cfg.LogStackTraces)
+	}
+	middleware.InitLogger(appLogger)
+	logPolicy, err := parseLogPolicy(cfg.LogPolicy)
+	if err != nil {
+		return nil, err
+	}
+	middleware.SetLogPolicy(logPolicy)
+
+	var userRepo repository.UserStore
+	var apiKeyRepo repository.APIKeyStore
+	var loginHistoryRepo repository.LoginHistoryStore
+	var webauthnRepo repository.WebAuthnCredentialStore
+	var revocationRepo repository.TokenRevocationStore
+	var nameReviewRepo repository.NameReviewStore
+	var referralRepo repository.ReferralStore
+	var identityRepo repository.IdentityStore
+	var orgRepo repository.OrganizationStore
+	var billingRepo repository.BillingStore
+	var apiUsageRepo repository.APIUsageStore
+	var jobLeaseRepo repository.JobLeaseStore
+	var rateLimitRepo repository.RateLimitCounterStore
+	var securityEventRepo repository.SecurityEventStore
+	var refreshTokenRepo repository.RefreshTokenStore
+	var revokedTokenRepo repository.RevokedTokenStore
+	var sessionRepo repository.SessionStore
+	var loginLockoutRepo repository.LoginLockoutStore
+	var deadLetterRepo repository.EmailDeadLetterStore
+	var notificationRepo repository.NotificationStore
+	var profileRepo repository.ProfileStore
+	var magicLinkRepo repository.MagicLinkRedemptionStore
+	var ssoStateRepo repository.SSOStateStore
+	var memory bool
+	switch cfg.Storage {
+	case "", config.StorageDatabase:
+	case config.StorageMemory:
+		memory = true
+	default:
+		return nil, fmt.Errorf("unknown STORAGE %q", cfg.Storage)
+	}
+	switch {
+	case memory && opts.DB == nil && opts.MySQL == nil:
+		appLogger.Warn("storage is in memory: data is lost on restart and not shared between instances")
+		users := repository.NewMemoryUserStore()
+		apiKeys := repository.NewMemoryAPIKeyStore(users)
+		logins := repository.NewMemoryLoginHistoryStore()
+		userRepo = users
+		apiKeyRepo = apiKeys
+		loginHistoryRepo = logins
+		webauthnRepo = repository.NewMemoryWebAuthnCredentialStore()
+		revocationRepo = repository.NewMemoryTokenRevocationStore()
+		nameReviewRepo = repository.NewMemoryNameReviewStore()
+		referralRepo = repository.NewMemoryReferralStore()
+		identityRepo = repository.NewMemoryIdentityStore()
+		orgRepo = repository.NewMemoryOrganizationStore(users, logins)
+		billingRepo = repository.NewMemoryBillingStore()
+		apiUsageRepo = repository.NewMemoryAPIUsageStore(users, apiKeys)
+		jobLeaseRepo = repository.NewMemoryJobLeaseStore()
+		rateLimitRepo = repository.NewMemoryRateLimitStore()
+		securityEventRepo = repository.NewMemorySecurityEventStore(users)
+		refreshTokenRepo = repository.NewMemoryRefreshTokenStore()
+		revokedTokenRepo = repository.NewMemoryRevokedTokenStore()
+		sessionRepo = repository.NewMemorySessionStore()
+		loginLockoutRepo = repository.NewMemoryLoginLockoutStore()
+		deadLetterRepo = repository.NewMemoryEmailDeadLetterStore()
+		notificationRepo = repository.NewMemoryNotificationStore()
+		profileRepo = repository.NewMemoryProfileStore()
+		magicLinkRepo = repository.NewMemoryMagicLinkStore()
+		ssoStateRepo = repository.NewMemorySSOStateStore()
+	case !memory && opts.DB != nil && opts.MySQL == nil:
+		db := generated.DBTX(opts.DB)
+		if opts.ReadDB != nil {
+			db = repository.NewReplicatedDB(opts.DB, opts.ReadDB)
+		}
+		userRepo = repository.NewUserRepository(generated.New(db))
+		apiKeyRepo = repository.NewAPIKeyRepository(generated.New(db))
+		loginHistoryRepo = repository.NewLoginHistoryRepository(generated.New(db))
+		webauthnRepo = repository.NewWebAuthnCredentialRepository(generated.New(db))
+		revocationRepo = repository.NewTokenRevocationRepository(generated.New(db))
+		nameReviewRepo = repository.NewNameReviewRepository(generated.New(db))
+		referralRepo = repository.NewReferralRepository(generated.New(db))
+		identityRepo = repository.NewIdentityRepository(generated.New(db))
+		orgRepo = repository.NewOrganizationRepository(generated.New(db))
+		billingRepo = repository.NewBillingRepository(generated.New(db))
+		apiUsageRepo = repository.NewAPIUsageRepository(generated.New(db))
+		jobLeaseRepo = repository.NewJobLeaseRepository(generated.New(db))
+		rateLimitRepo = repository.NewRateLimitRepository(generated.New(db))
+		securityEventRepo = repository.NewSecurityEventRepository(generated.New(db))
+		refreshTokenRepo = repository.NewRefreshTokenRepository(generated.New(db))
+		revokedTokenRepo = repository.NewRevokedTokenRepository(generated.New(db))
+		sessionRepo = repository.NewSessionRepository(generated.New(db))
+		loginLockoutRepo = repository.NewLoginLockoutRepository(generated.New(db))
+		deadLetterRepo = repository.NewEmailDeadLetterRepository(generated.New(db))
+		notificationRepo = repository.NewNotificationRepository(generated.New(db))
+		profileRepo = repository.NewProfileRepository(generated.New(db))
+		magicLinkRepo = repository.NewMagicLinkRepository(generated.New(db))
+		ssoStateRepo = repository.NewSSOStateRepository(generated.New(db))
+	case !memory && opts.MySQL != nil && opts.DB == nil:
+		userRepo = repository.NewMySQLUserRepository(mysqlgen.New(opts.MySQL))
+		apiKeyRepo = repository.NewMySQLAPIKeyRepository(mysqlgen.New(opts.MySQL))
+		loginHistoryRepo = repository.NewMySQLLoginHistoryRepository(mysqlgen.New(opts.MySQL))
+		webauthnRepo = repository.NewMySQLWebAuthnCredentialRepository(mysqlgen.New(opts.MySQL))
+		revocationRepo = repository.NewMySQLTokenRevocationRepository(mysqlgen.New(opts.MySQL))
+		nameReviewRepo = repository.NewMySQLNameReviewRepository(mysqlgen.New(opts.MySQL))
+		referralRepo = repository.NewMySQLReferralRepository(mysqlgen.New(opts.MySQL))
+		identityRepo = repository.NewMySQLIdentityRepository(mysqlgen.New(opts.MySQL))
+		orgRepo = repository.NewMySQLOrganizationRepository(mysqlgen.New(opts.MySQL))
+		billingRepo = repository.NewMySQLBillingRepository(mysqlgen.New(opts.MySQL))
+		apiUsageRepo = repository.NewMySQLAPIUsageRepository(mysqlgen.New(opts.MySQL))
+		jobLeaseRepo = repository.NewMySQLJobLeaseRepository(mysqlgen.New(opts.MySQL))
+		rateLimitRepo = repository.NewMySQLRateLimitRepository(mysqlgen.New(opts.MySQL))
+		securityEventRepo = repository.NewMySQLSecurityEventRepository(mysqlgen.New(opts.MySQL))
+		refreshTokenRepo = repository.NewMySQLRefreshTokenRepository(mysqlgen.New(opts.MySQL))
+		revokedTokenRepo = repository.NewMySQLRevokedTokenRepository(mysqlgen.New(opts.MySQL))
+		sessionRepo = repository.NewMySQLSessionRepository(mysqlgen.New(opts.MySQL))
+		loginLockoutRepo = repository.NewMySQLLoginLockoutRepository(mysqlgen.New(opts.MySQL))
+		deadLetterRepo = repository.NewMySQLEmailDeadLetterRepository(mysqlgen.New(opts.MySQL))
+		notificationRepo = repository.NewMySQLNotificationRepository(mysqlgen.New(opts.MySQL))
+		profileRepo = repository.NewMySQLProfileRepository(mysqlgen.New(opts.MySQL))
+		magicLinkRepo = repository.NewMySQLMagicLinkRepository(mysqlgen.New(opts.MySQL))
+		ssoStateRepo = repository.NewMySQLSSOStateRepository(mysqlgen.New(opts.MySQL))
+	default:
+		return nil, ErrNoDatabase
+	}
+
+	if cfg.IsProduction() && cfg.JWTSecret == config.DefaultJWTSecret {
+		return nil, ErrDefaultSecret
+	}
+
+	if cfg.Chaos.Enabled() {
+		if cfg.AppEnv != config.EnvDev {
+			return nil, ErrChaosOutsideDev
+		}
+		appLogger.Warn("chaos fault injection enabled",
+			zap.Duration("latency", cfg.Chaos.Latency),
+			zap.Int("latency_percent", cfg.Chaos.LatencyPercent),
+			zap.Int("error_percent", cfg.Chaos.ErrorPercent),
+			zap.Int("db_error_percent", cfg.Chaos.DBErrorPercent),
+		)
+		userRepo = repository.WithFaults(userRepo, cfg.Chaos.DBErrorPercent)
+	}
+
+	repoMetrics := repository.NewMetrics(appLogger, cfg.SlowQueryThreshold)
+	userRepo = repository.WithInstrumentation(userRepo, repoMetrics)
+
+	registry := opts.Hooks
+	if registry == nil {
+		registry = hooks.NewRegistry(appLogger)
+	}
+	registerHTTPHooks(registry, cfg.Hooks)
+
+	revocationSvc := service.NewTokenRevocationService(revocationRepo)
+	switch cfg.TokenRevocation.Store {
+	case "", "database":
+	case "memory":
+		revokedTokenRepo = repository.NewMemoryRevokedTokenStore()
+	default:
+		return nil, fmt.Errorf("unknown TOKEN_REVOCATION_STORE %q", cfg.TokenRevocation.Store)
+	}
+	revocationSvc.SetTokenStore(revokedTokenRepo)
+
+	nameFilter := opts.NameFilter
+	if nameFilter == nil {
+		var err error
+		if nameFilter, err = wordListFilter(cfg.Moderation); err != nil {
+			return nil, err
+		}
+	}
+	moderationSvc := service.NewModerationService(nameFilter, nameReviewRepo, userRepo, revocationSvc, appLogger)
+	if nameFilter != nil {
+		userRepo = service.WithModeration(userRepo, moderationSvc)
+	}
+	userRepo = repository.WithHooks(userRepo, registry)
+	userRepo = repository.WithCache(userRepo, repository.CacheConfig{
+		TTL:           cfg.Cache.TTL,
+		JitterPercent: cfg.Cache.JitterPercent,
+	})
+
+	userSvc := service.NewUserService(userRepo)
+	defaultPageSize, maxPageSize := cfg.Pagination.DefaultLimit, cfg.Pagination.MaxLimit
+	if defaultPageSize <= 0 {
+		defaultPageSize = service.DefaultPageSize
+	}
+	if maxPageSize <= 0 {
+		maxPageSize = service.DefaultMaxPageSize
+	}
+	if defaultPageSize > maxPageSize {
+		return nil, fmt.Errorf("DEFAULT_PAGE_SIZE (%d) is larger than MAX_PAGE_SIZE (%d)", defaultPageSize, maxPageSize)
+	}
+	userSvc.SetPageSizes(defaultPageSize, maxPageSize)
+	userSvc.SetListCap(cfg.Pagination.ListCap)
+	userSvc.SetAlwaysPaginate(cfg.Pagination.Always)
+	avatars := service.NewAvatarService(cfg.Branding.BaseURL)
+	if cfg.Avatars.Gravatar {
+		avatars.SetGravatar(cfg.Avatars.Style, cfg.Avatars.Size)
+	}
+	// Uploads on local disk are served by the API itself, under signed URLs.
+	uploads := storage.New(cfg.Uploads, cfg.Branding.BaseURL+opts.Prefix)
+	avatars.SetUploads(uploads, cfg.Avatars.MaxUploadBytes, cfg.Avatars.MaxUploadDimension, cfg.Uploads.URLTTL)
+	localUploads, _ := uploads.(*storage.Local)
+	fileHandler := handler.NewFileHandler(localUploads, appLogger)
+	userSvc.SetAvatars(avatars)
+	userSvc.SetProfiles(profileRepo)
+	userHandler := handler.NewUserHandler(userRepo, userSvc, appLogger)
+
+	securityLog := service.NewSecurityLogService(securityEventRepo)
+
+	authSvc := service.NewAuthService(userRepo)
+	authSvc.SetJWTConfig(cfg.JWTSecret, cfg.JWTExpiry)
+	authSvc.SetHooks(registry)
+	authSvc.SetSecurityLog(securityLog)
+	lockout := service.NewLoginLockout(loginLockoutRepo, service.LockoutConfig{
+		Threshold:   cfg.Lockout.Threshold,
+		IPThreshold: cfg.Lockout.IPThreshold,
+		Window:      cfg.Lockout.Window,
+		Duration:    cfg.Lockout.Duration,
+	})
+	authSvc.SetLockout(lockout)
+	if len(cfg.JWTExtraClaims) > 0 || opts.ClaimsEnricher != nil {
+		var static ClaimsEnricher
+		if len(cfg.JWTExtraClaims) > 0 {
+			static = service.StaticClaims(cfg.JWTExtraClaims)
+		}
+		authSvc.SetClaimsEnricher(service.ChainEnrichers(static, opts.ClaimsEnricher))
+	}
+	outcomes := service.NewOutcomes()
+	authHandler := handler.NewAuthHandler(service.WithOutcomes(authSvc, outcomes), appLogger, cfg.CookieSecure)
+	authHandler.SetLoginHistory(loginHistoryRepo)
+	authHandler.SetTokenRevocation(revocationSvc)
+	switch cfg.AuthTokenPrecedence {
+	case "", middleware.TokenPrecedenceHeader, middleware.TokenPrecedenceCookie:
+	default:
+		return nil, fmt.Errorf("unknown AUTH_TOKEN_PRECEDENCE %q", cfg.AuthTokenPrecedence)
+	}
+	switch cfg.Sessions.OnLimit {
+	case "", service.SessionLimitRevokeOldest, service.SessionLimitReject:
+	default:
+		return nil, fmt.Errorf("unknown SESSION_LIMIT_POLICY %q", cfg.Sessions.OnLimit)
+	}
+	// A session lives as long as the longest-lived token it was issued.
+	sessionLifetime := cfg.JWTExpiry
+	if cfg.RefreshTokenTTL > sessionLifetime {
+		sessionLifetime = cfg.RefreshTokenTTL
+	}
+	sessionSvc := service.NewSessionService(sessionRepo, refreshTokenRepo, revocationSvc, service.SessionConfig{
+		MaxPerUser: cfg.Sessions.MaxPerUser,
+		OnLimit:    cfg.Sessions.OnLimit,
+		Lifetime:   sessionLifetime,
+		TokenTTL:   cfg.JWTExpiry,
+	})
+	authSvc.SetSessions(sessionSvc)
+	authHandler.SetSessions(sessionSvc)
+	if cfg.RefreshTokenTTL > 0 {
+		refreshSvc := service.NewRefreshTokenService(refreshTokenRepo, userRepo, authSvc, revocationSvc, cfg.RefreshTokenTTL)
+		refreshSvc.SetSessions(sessionSvc)
+		authHandler.SetRefreshTokens(refreshSvc)
+	}
+
+	locator := opts.GeoIP
+	if locator == nil && cfg.GeoIP.DatabasePath != "" {
+		db, err := geoip.Open(cfg.GeoIP.DatabasePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load geoip database: %w", err)
+		}
+		locator = db
+	}
+	if locator == nil && len(cfg.GeoIP.DenyCountries) > 0 {
+		return nil, errors.New("GEOIP_DENY_COUNTRIES requires GEOIP_DATABASE or Options.GeoIP")
+	}
+	if locator != nil {
+		authHandler.SetGeoIP(locator)
+	}
+	loginHistoryHandler := handler.NewLoginHistoryHandler(loginHistoryRepo, appLogger)
+
+	referralSvc := service.NewReferralService(referralRepo, service.ReferralConfig{
+		URL:        cfg.Referrals.URL,
+		RecentDays: cfg.Referrals.RecentDays,
+	}, appLogger)
+	authHandler.SetReferrals(referralSvc)
+	referralHandler := handler.NewReferralHandler(referralSvc, appLogger)
+
+	policies := policy.NewEngine(policy.DefaultRules()...)
+	if len(cfg.RoleHierarchy) > 0 {
+		policies.SetRoleHierarchy(cfg.RoleHierarchy)
+	}
+	adminHandler := handler.NewAdminHandler(userRepo, policies, appLogger)
+	adminHandler.SetPagination(userSvc)
+	adminHandler.SetSecurityLog(securityLog)
+	adminHandler.SetLockout(lockout)
+	moderationHandler := handler.NewModerationHandler(moderationSvc, userRepo, policies, appLogger)
+
+	deviceSvc := service.NewDeviceService(userRepo, authSvc, service.DeviceConfig{
+		CodeTTL:         cfg.DeviceFlow.CodeTTL,
+		PollInterval:    cfg.DeviceFlow.PollInterval,
+		VerificationURL: cfg.DeviceFlow.VerificationURL,
+	})
+	deviceHandler := handler.NewDeviceHandler(deviceSvc, appLogger, int(cfg.JWTExpiry.Seconds()))
+
+	webauthnSvc := service.NewWebAuthnService(webauthn.NewRelyingParty(webauthn.Config{
+		RPID:    cfg.WebAuthn.RPID,
+		RPName:  cfg.WebAuthn.RPName,
+		Origins: cfg.WebAuthn.Origins,
+	}), webauthnRepo, userRepo, authSvc, service.WebAuthnConfig{
+		ChallengeTTL: cfg.WebAuthn.ChallengeTTL,
+	})
+	webauthnHandler := handler.NewWebAuthnHandler(webauthnSvc, appLogger, cfg.CookieSecure, int(cfg.JWTExpiry.Seconds()))
+
+	identityProviders := make(map[string]*sso.OIDCProvider, len(cfg.IdentityProviders))
+	for _, p := range cfg.IdentityProviders {
+		redirectURL := p.RedirectURL
+		if redirectURL == "" {
+			redirectURL = cfg.Branding.BaseURL + opts.Prefix + "/auth/identities/" + p.Name + "/callback"
+		}
+		identityProviders[p.Name] = sso.NewOIDCProvider(sso.OIDCConfig{
+			Issuer:       p.Issuer,
+			ClientID:     p.ClientID,
+			ClientSecret: p.ClientSecret,
+			RedirectURL:  redirectURL,
+			Scopes:       p.Scopes,
+		})
+	}
+	identitySvc := service.NewIdentityService(identityProviders, identityRepo, webauthnRepo, userRepo, authSvc)
+	identityHandler := handler.NewIdentityHandler(identitySvc, appLogger, cfg.CookieSecure, int(cfg.JWTExpiry.Seconds()))
+
+	orgSvc := service.NewOrganizationService(orgRepo)
+	orgHandler := handler.NewOrganizationHandler(orgSvc, appLogger)
+	billingProvider, err := newBillingProvider(cfg.Billing)
+	if err != nil {
+		return nil, err
+	}
+	billingSvc := service.NewBillingService(billingProvider, billingRepo, orgRepo)
+	orgSvc.SetSeatChecker(billingSvc)
+	billingHandler := handler.NewBillingHandler(billingSvc, appLogger)
+
+	meteringSvc := service.NewMeteringService(apiUsageRepo)
+	meteringHandler := handler.NewMeteringHandler(meteringSvc, appLogger)
+
+	serviceAccountSvc := service.NewServiceAccountService(userRepo, apiKeyRepo, authSvc)
+	serviceAccountHandler := handler.NewServiceAccountHandler(serviceAccountSvc, appLogger)
+
+	reportSvc := service.NewReportService(userRepo)
+	reportSvc.SetLoginHistory(loginHistoryRepo)
+	reportHandler := handler.NewReportHandler(reportSvc, appLogger)
+	exportSvc := service.NewExportService(reportSvc, service.ExportConfig{
+		Dir:       cfg.Exports.Dir,
+		Secret:    cfg.Exports.URLSecret,
+		URLTTL:    cfg.Exports.URLTTL,
+		Retention: cfg.Exports.Retention,
+	}, appLogger)
+	exportHandler := handler.NewExportHandler(exportSvc, cfg.Branding.BaseURL+opts.Prefix, appLogger)
+
+	// Backups use COPY, so they are only offered on Postgres.
+	var backupHandler *handler.BackupHandler
+	if opts.DB != nil {
+		backupSvc := service.NewBackupService(backup.PostgresCopier{Pool: opts.DB}, backup.NewStorage(cfg.Backups), appLogger)
+		backupHandler = handler.NewBackupHandler(backupSvc, appLogger)
+	}
+
+	retentionSvc := service.NewRetentionService(appLogger)
+	retentionSvc.Register(service.RetentionLoginHistory, cfg.Retention.LoginHistory, loginHistoryRepo)
+	retentionSvc.Register(service.RetentionExports, cfg.Exports.Retention, exportSvc)
+	retentionSvc.Register(service.RetentionNotifications, cfg.Retention.Notifications, notificationRepo)
+	// Revoked tokens are kept by expiry, and expired tokens are rejected
+	// anyway, so they go shortly after expiring.
+	retentionSvc.Register(service.RetentionRevokedTokens, time.Minute, revokedTokenRepo)
+	retentionSvc.Register(service.RetentionSessions, time.Minute, sessionRepo)
+	retentionSvc.Register(service.RetentionMagicLinks, time.Minute, magicLinkRepo)
+	retentionSvc.Register(service.RetentionSSOLogins, time.Minute, ssoStateRepo)
+	// Counters are pruned once their window has passed. Both rate limits
+	// share the store, so the longer window decides.
+	retentionSvc.Register(service.RetentionRateLimits, max(cfg.RateLimit.Window, cfg.SensitiveRateLimit.Window), rateLimitRepo)
+	retentionSvc.Register(service.RetentionLoginLockouts, cfg.Lockout.Window, loginLockoutRepo)
+	retentionHandler := handler.NewRetentionHandler(retentionSvc, appLogger)
+
+	emailRenderer, err := templates.NewRenderer(templates.Branding{
+		ProductName:  cfg.Branding.ProductName,
+		LogoURL:      cfg.Branding.LogoURL,
+		SupportEmail: cfg.Branding.SupportEmail,
+		BaseURL:      cfg.Branding.BaseURL,
+	}, cfg.DefaultLocale)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load email templates: %w", err)
+	}
+	emailTemplateHandler := handler.NewEmailTemplateHandler(emailRenderer, appLogger)
+
+	var mail mailer.Mailer = mailer.NewLog(appLogger)
+	if cfg.Mailer.SMTPAddr != "" {
+		mail = mailer.NewSMTP(mailer.SMTPConfig{
+			Addr:     cfg.Mailer.SMTPAddr,
+			Username: cfg.Mailer.SMTPUsername,
+			Password: cfg.Mailer.SMTPPassword,
+			From:     cfg.Mailer.From,
+		})
+	}
+	mailDelivery := service.NewMailDelivery(mail, deadLetterRepo, service.MailDeliveryConfig{
+		RecipientLimit:  cfg.Mailer.RecipientLimit,
+		RecipientWindow: cfg.Mailer.RecipientWindow,
+		DedupWindow:     cfg.Mailer.DedupWindow,
+		MaxAttempts:     cfg.Mailer.MaxAttempts,
+		RetryBackoff:    cfg.Mailer.RetryBackoff,
+	}, appLogger)
+	mail = mailDelivery
+	emailDeliveryHandler := handler.NewEmailDeliveryHandler(mailDelivery, appLogger)
+
+	authHandler.SetMagicLinks(service.NewMagicLinkService(userRepo, magicLinkRepo, authSvc, mail, emailRenderer, service.MagicLinkConfig{
+		Secret: cfg.MagicLink.Secret,
+		TTL:    cfg.MagicLink.TTL,
+		URL:    cfg.MagicLink.URL,
+	}, appLogger))
+
+	if cfg.LoginRisk.Threshold > 0 {
+		risk := service.NewLoginRisk(loginHistoryRepo, userRepo, authSvc, mail, emailRenderer, service.LoginRiskConfig{
+			Threshold:     cfg.LoginRisk.Threshold,
+			ChallengeTTL:  cfg.LoginRisk.ChallengeTTL,
+			RapidAttempts: cfg.LoginRisk.RapidAttempts,
+			RapidWindow:   cfg.LoginRisk.RapidWindow,
+		}, appLogger)
+		if locator != nil {
+			risk.SetGeoIP(locator)
+		}
+		authSvc.SetLoginRisk(risk)
+		authHandler.SetLoginRisk(risk)
+	}
+
+	resetSvc := service.NewPasswordResetService(userRepo, authSvc, revocationSvc, mail, emailRenderer, service.PasswordResetConfig{
+		Secret: cfg.PasswordReset.Secret,
+		TTL:    cfg.PasswordReset.TTL,
+		URL:    cfg.PasswordReset.URL,
+	}, appLogger)
+	authHandler.SetPasswordResets(resetSvc)
+
+	digestSvc := service.NewDigestService(reportSvc, userRepo, mail, emailRenderer, service.DigestConfig{
+		Period:     cfg.Digest.Interval,
+		Recipients: cfg.Digest.Recipients,
+	}, appLogger)
+	adminHandler.SetCredentialControls(revocationSvc, resetSvc)
+
+	notificationSvc := service.NewNotificationService(notificationRepo, userRepo, mail, emailRenderer, cfg.Hooks.Secret, cfg.Hooks.Timeout, appLogger)
+	securityLog.SetNotifications(notificationSvc)
+	notificationHandler := handler.NewNotificationHandler(notificationSvc, appLogger)
+
+	// Alerts also go through the notification rules, as security_alert
+	// events.
+	alerters := []service.SecurityAlerter{notificationSvc}
+	if cfg.BruteForce.AlertWebhookURL != "" {
+		alerters = append(alerters, service.NewWebhookAlerter(cfg.BruteForce.AlertWebhookURL, cfg.Hooks.Secret, cfg.Hooks.Timeout))
+	}
+	if len(cfg.BruteForce.AlertEmails) > 0 {
+		alerters = append(alerters, service.NewEmailAlerter(mail, emailRenderer, cfg.BruteForce.AlertEmails))
+	}
+	if opts.SecurityAlerter != nil {
+		alerters = append(alerters, opts.SecurityAlerter)
+	}
+	detector := service.NewBruteForceDetector(service.BruteForceConfig{
+		Window:        cfg.BruteForce.Window,
+		FailuresPerIP: cfg.BruteForce.FailuresPerIP,
+		AccountsPerIP: cfg.BruteForce.AccountsPerIP,
+		IPsPerAccount: cfg.BruteForce.IPsPerAccount,
+		AlertCooldown: cfg.BruteForce.AlertCooldown,
+	}, appLogger, alerters...)
+	authHandler.SetBruteForceDetector(detector)
+	securityHandler := handler.NewSecurityHandler(detector, appLogger)
+	securityHandler.SetSecurityLog(securityLog)
+
+	scimSvc := service.NewSCIMService(userRepo, authSvc, cfg.Branding.BaseURL+opts.Prefix)
+	scimHandler := handler.NewSCIMHandler(scimSvc, appLogger)
+
+	var ssoHandler *handler.SSOHandler
+	if cfg.OIDC.Enabled() {
+		provider := sso.NewOIDCProvider(sso.OIDCConfig{
+			Issuer:       cfg.OIDC.Issuer,
+			ClientID:     cfg.OIDC.ClientID,
+			ClientSecret: cfg.OIDC.ClientSecret,
+			RedirectURL:  cfg.OIDC.RedirectURL,
+		})
+		ssoSvc := service.NewSSOService(provider, userRepo, ssoStateRepo, authSvc, service.SSOConfig{
+			Domains:         cfg.OIDC.Domains,
+			JITProvisioning: cfg.OIDC.JITProvisioning,
+			RoleClaim:       cfg.OIDC.RoleClaim,
+			AdminValues:     cfg.OIDC.AdminValues,
+		})
+		ssoSvc.SetRoles(service.NewRoleChanger(userRepo, revocationSvc, securityLog, appLogger))
+		ssoHandler = handler.NewSSOHandler(ssoSvc, appLogger, cfg.CookieSecure, int(cfg.JWTExpiry.Seconds()))
+		webauthnSvc.SetSSO(ssoSvc)
+		identitySvc.SetSSO(ssoSvc)
+	}
+
+	var limiter *middleware.AdaptiveLimiter
+	if cfg.LoadShedding.Enabled {
+		limiter = middleware.NewAdaptiveLimiter(
+			cfg.LoadShedding.InitialLimit,
+			cfg.LoadShedding.MinLimit,
+			cfg.LoadShedding.MaxLimit,
+			cfg.LoadShedding.TargetLatency,
+		)
+	}
+	var rateLimiter *middleware.RateLimiter
+	if cfg.RateLimit.Requests > 0 {
+		rateLimiter = middleware.NewRateLimiter(
+			cfg.RateLimit.Requests,
+			cfg.RateLimit.Window,
+			cfg.RateLimit.WarnPercent,
+			cfg.RateLimit.GracePercent,
+		)
+	}
+	var redisClient *redis.Client
+	if cfg.Redis.URL != "" {
+		redisOpts, err := redis.ParseURL(cfg.Redis.URL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid REDIS_URL: %w", err)
+		}
+		redisClient = redis.New(redisOpts)
+		pingCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		if err := redisClient.Ping(pingCtx); err != nil {
+			appLogger.Warn("redis is unreachable, shared state is unavailable until it is", zap.Error(err))
+		}
+		cancel()
+	}
+	if rateLimiter != nil {
+		store, err := newRateLimitStore("RATE_LIMIT_STORE", cfg.RateLimit.Store, redisClient, "ratelimit:", rateLimitRepo)
+		if err != nil {
+			return nil, err
+		}
+		if store != nil {
+			rateLimiter.SetStore(store)
+		}
+	}
+	var sensitiveLimiter *middleware.RateLimiter
+	if cfg.SensitiveRateLimit.Requests > 0 {
+		sensitiveLimiter = middleware.NewRateLimiter(cfg.SensitiveRateLimit.Requests, cfg.SensitiveRateLimit.Window, 0, 0)
+		sensitiveLimiter.SetGrace(0)
+		store, err := newRateLimitStore("SENSITIVE_RATE_LIMIT_STORE", cfg.SensitiveRateLimit.Store, redisClient, "ratelimit:sensitive:", rateLimitRepo)
+		if err != nil {
+			return nil, err
+		}
+		if store != nil {
+			sensitiveLimiter.SetStore(store)
+		}
+	}
+	var idleSessions *middleware.IdleSessions
+	if cfg.SessionIdle.Timeout > 0 {
+		idleSessions = middleware.NewIdleSessions(cfg.SessionIdle.Timeout)
+		switch cfg.SessionIdle.Store {
+		case "", "memory":
+		case "redis":
+			if redisClient == nil {
+				return nil, fmt.Errorf("SESSION_IDLE_STORE=redis requires REDIS_URL")
+			}
+			idleSessions.SetStore(middleware.NewRedisSessionActivityStore(redisClient, "session:"))
+		default:
+			return nil, fmt.Errorf("unknown SESSION_IDLE_STORE %q", cfg.SessionIdle.Store)
+		}
+	}
+	systemHandler := handler.NewSystemHandler(limiter, appLogger)
+	systemHandler.SetRateLimiter(rateLimiter)
+	systemHandler.SetRepositoryMetrics(repoMetrics)
+	systemHandler.SetOutcomes(outcomes)
+	connections := middleware.NewConnectionStats()
+	systemHandler.SetConnectionStats(connections)
+	deprecations := middleware.NewDeprecations(handler.DeprecatedFeatures...)
+	systemHandler.SetDeprecations(deprecations)
+	var analytics *middleware.EndpointAnalytics
+	if cfg.Analytics.Window > 0 {
+		analytics = middleware.NewEndpointAnalytics(cfg.Analytics.Window, cfg.Analytics.MaxSeries)
+		systemHandler.SetEndpointAnalytics(analytics)
+	}
+	configHandler := handler.NewConfigHandler(cfg, policies, appLogger)
+	healthHandler := handler.NewHealthHandler(cfg.ReadinessTimeout, appLogger)
+	var metrics *middleware.Metrics
+	if cfg.Metrics.Enabled {
+		metrics = middleware.NewMetrics()
+	}
+	addDatabase := func(name, driver string, ping func(context.Context) error, stats func() models.PoolStats) {
+		healthHandler.AddDatabase(name, driver, ping, stats)
+		if metrics != nil {
+			metrics.AddDatabase(name, stats)
+		}
+	}
+	switch {
+	case opts.DB != nil:
+		addDatabase("primary", config.DriverPostgres, opts.DB.Ping, pgxPoolStats(opts.DB))
+		if opts.ReadDB != nil {
+			addDatabase("replica", config.DriverPostgres, opts.ReadDB.Ping, pgxPoolStats(opts.ReadDB))
+		}
+	case opts.MySQL != nil:
+		addDatabase("primary", config.DriverMySQL, opts.MySQL.PingContext, sqlPoolStats(opts.MySQL))
+	}
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	// Jobs that write shared state run on one instance only; those that
+	// refresh or flush this instance's memory run everywhere.
+	var leader jobs.Leader
+	if cfg.JobLeaseTTL > 0 {
+		elector := jobs.NewLeaseElector(jobLeaseRepo, leaseHolder(), cfg.JobLeaseTTL, appLogger)
+		elector.Start(jobsCtx)
+		leader = elector
+	}
+	statsRefresher := jobs.NewStatsRefresher(userRepo, cfg.StatsRefreshInterval, appLogger)
+	statsRefresher.SetLeader(leader)
+	go statsRefresher.Run(jobsCtx)
+	retentionPruner := jobs.NewRetentionPruner(retentionSvc, cfg.Retention.PruneInterval, appLogger)
+	retentionPruner.SetLeader(leader)
+	go retentionPruner.Run(jobsCtx)
+	go jobs.NewTokenRevocationRefresher(revocationSvc, cfg.TokenRevocation.RefreshInterval, appLogger).Run(jobsCtx)
+	digestSender := jobs.NewDigestSender(digestSvc, cfg.Digest.Interval, appLogger)
+	digestSender.SetLeader(leader)
+	go digestSender.Run(jobsCtx)
+	go jobs.NewUsageAggregator(orgSvc, cfg.OrgUsageInterval, appLogger).Run(jobsCtx)
+	go jobs.NewUsageFlusher(meteringSvc, cfg.UsageFlushInterval, appLogger).Run(jobsCtx)
+
+	chaos := middleware.Chaos(middleware.ChaosConfig{
+		Latency:        cfg.Chaos.Latency,
+		LatencyPercent: cfg.Chaos.LatencyPercent,
+		ErrorPercent:   cfg.Chaos.ErrorPercent,
+	})
+
+	// Without a replica every query already goes to the primary.
+	consistency := func(c *fiber.Ctx) error { return c.Next() }
+	if opts.ReadDB != nil {
+		consistency = middleware.Consistency(middleware.ConsistencyConfig{
+			StickyWindow: cfg.Replication.StickyWindow,
+			CookieSecure: cfg.CookieSecure,
+		})
+	}
+
+	router := app
+	if opts.Prefix != "" {
+		router = app.Group(opts.Prefix)
+	}
+	routes.Register(router, routes.Deps{
+		HealthHandler:         healthHandler,
+		Metrics:               metrics,
+		UserHandler:           userHandler,
+		AuthHandler:           authHandler,
+		AdminHandler:          adminHandler,
+		ReportHandler:         reportHandler,
+		ExportHandler:         exportHandler,
+		EmailTemplateHandler:  emailTemplateHandler,
+		EmailDeliveryHandler:  emailDeliveryHandler,
+		NotificationHandler:   notificationHandler,
+		FileHandler:           fileHandler,
+		SCIMHandler:           scimHandler,
+		SSOHandler:            ssoHandler,
+		ServiceAccountHandler: serviceAccountHandler,
+		DeviceHandler:         deviceHandler,
+		WebAuthnHandler:       webauthnHandler,
+		SystemHandler:         systemHandler,
+		RetentionHandler:      retentionHandler,
+		SecurityHandler:       securityHandler,
+		LoginHistoryHandler:   loginHistoryHandler,
+		ModerationHandler:     moderationHandler,
+		ReferralHandler:       referralHandler,
+		IdentityHandler:       identityHandler,
+		ConfigHandler:         configHandler,
+		BackupHandler:         backupHandler,
+		OrgHandler:            orgHandler,
+		BillingHandler:        billingHandler,
+		MeteringHandler:       meteringHandler,
+		APIKeys:               serviceAccountSvc,
+		Revocations:           revocationSvc,
+		IdleSessions:          idleSessions,
+		UserIDs:               userRepo,
+		OrgMembers:            orgRepo,
+		Policies:              policies,
+		GeoBlock:              middleware.GeoBlock(locator, cfg.GeoIP.DenyCountries),
+		Chaos:                 chaos,
+		Consistency:           consistency,
+		Limiter:               limiter,
+		Connections:           connections,
+		Deprecations:          deprecations,
+		Analytics:             analytics,
+		RateLimiter:           rateLimiter,
+		SensitiveLimiter:      sensitiveLimiter,
+		Usage:                 []middleware.UsageRecorder{orgSvc, meteringSvc},
+	}, cfg)
+
+	if fiberApp, ok := app.(*fiber.App); ok {
+		if err := CheckRoutes(fiberApp, opts.Prefix, appLogger); err != nil {
+			stopJobs()
+			return nil, err
+		}
+	}
+
+	return &Instance{stopJobs: stopJobs, redis: redisClient}, nil
+}
+
+type RouteInfo = routes.RouteInfo
+
+// CheckRoutes logs app's route table at debug level and fails on
+// unreachable routes or protected routes missing their auth middleware.
+// Mount runs it when given the *fiber.App itself; hosts that mount into a
+// group should call it once all routes are registered, with the group's
+// prefix plus Options.Prefix.
+func CheckRoutes(app *fiber.App, prefix string, log *zap.Logger) error {
+	for _, r := range routes.Table(app) {
+		log.Debug("route",
+			zap.String("method", r.Method),
+			zap.String("path", r.Path),
+			zap.Strings("chain", r.Chain),
+		)
+	}
+	return routes.Check(app, prefix)
+}
+
+func pgxPoolStats(pool *pgxpool.Pool) func() models.PoolStats {
+	return func() models.PoolStats {
+		s := pool.Stat()
+		return models.PoolStats{
+			MaxConns:   int64(s.MaxConns()),
+			TotalConns: int64(s.TotalConns()),
+			IdleConns:  int64(s.IdleConns()),
+			InUseConns: int64(s.AcquiredConns()),
+			WaitCount:  s.EmptyAcquireCount(),
+		}
+	}
+}
+
+func sqlPoolStats(db *sql.DB) func() models.PoolStats {
+	return func() models.PoolStats {
+		s := db.Stats()
+		return models.PoolStats{
+			MaxConns:   int64(s.MaxOpenConnections),
+			TotalConns: int64(s.OpenConnections),
+			IdleConns:  int64(s.Idle),
+			InUseConns: int64(s.InUse),
+			WaitCount:  s.WaitCount,
+		}
+	}
+}
+
+// parseLogPolicy reads the level names in cfg. Empty names keep the
+// default level.
+func parseLogPolicy(cfg config.LogPolicy) (middleware.LogPolicy, error) {
+	p := middleware.DefaultLogPolicy
+	for _, l := range []struct {
+		env   string
+		value string
+		level *zapcore.Level
+	}{
+		{"LOG_SUCCESS_LEVEL", cfg.Success, &p.Success},
+		{"LOG_CLIENT_ERROR_LEVEL", cfg.ClientError, &p.ClientError},
+		{"LOG_ROUTINE_LEVEL", cfg.Routine, &p.Routine},
+	} {
+		if l.value == "" {
+			continue
+		}
+		level, err := zapcore.ParseLevel(l.value)
+		if err != nil {
+			return middleware.LogPolicy{}, fmt.Errorf("invalid %s: %w", l.env, err)
+		}
+		*l.level = level
+	}
+	return p, nil
+}
+
+// wordListFilter builds the built-in name filter, or returns nil when no
+// terms are configured.
+func wordListFilter(cfg config.Moderation) (service.NameFilter, error) {
+	blocked, review := cfg.BlockedWords, cfg.ReviewWords
+	if cfg.BlockedWordsFile != "" {
+		terms, err := service.LoadWordList(cfg.BlockedWordsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load blocked words: %w", err)
+		}
+		blocked = append(blocked, terms...)
+	}
+	if cfg.ReviewWordsFile != "" {
+		terms, err := service.LoadWordList(cfg.ReviewWordsFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load review words: %w", err)
+		}
+		review = append(review, terms...)
+	}
+	if len(blocked) == 0 && len(review) == 0 {
+		return nil, nil
+	}
+	return service.NewWordListFilter(blocked, review), nil
+}
+
+// newBillingProvider returns the configured billing provider, or nil when
+// billing is off.
+func newBillingProvider(cfg config.Billing) (billing.Provider, error) {
+	switch cfg.Provider {
+	case "":
+		return nil, nil
+	case "stripe":
+		if cfg.StripeWebhookSecret == "" {
+			return nil, errors.New("BILLING_PROVIDER=stripe requires STRIPE_WEBHOOK_SECRET")
+		}
+		return billing.NewStripe(cfg.StripeWebhookSecret, cfg.WebhookTolerance), nil
+	}
+	return nil, fmt.Errorf("unknown BILLING_PROVIDER %q", cfg.Provider)
+}
+
+// leaseHolder names this instance in job leases: its host name, for
+// operators, and a random suffix, since instances can share one.
+func leaseHolder() string {
+	host, _ := os.Hostname()
+	b := make([]byte, 4)
+	rand.Read(b)
+	return host + "-" + hex.EncodeToString(b)
+}
+
+// newRateLimitStore returns nil when counts are kept in memory. setting
+// names the variable store came from, for errors.
+func newRateLimitStore(setting, store string, client *redis.Client, prefix string, database repository.RateLimitCounterStore) (middleware.RateLimitStore, error) {
+	switch store {
+	case "", "memory":
+		return nil, nil
+	case "redis":
+		if client == nil {
+			return nil, fmt.Errorf("%s=redis requires REDIS_URL", setting)
+		}
+		return middleware.NewRedisRateLimitStore(client, prefix), nil
+	case "database":
+		return database, nil
+	}
+	return nil, fmt.Errorf("unknown %s %q", setting, store)
+}
+
+func registerHTTPHooks(registry *hooks.Registry, cfg config.Hooks) {
+	urls := map[hooks.Event]string{
+		hooks.BeforeUserCreate: cfg.BeforeUserCreateURL,
+		hooks.AfterLogin:       cfg.AfterLoginURL,
+		hooks.BeforeDelete:     cfg.BeforeDeleteURL,
+	}
+	for event, url := range urls {
+		if url != "" {
+			registry.Register(event, hooks.HTTPHook(event, url, cfg.Secret, cfg.Timeout))
+		}
+	}
+}